@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	emw "github.com/labstack/echo/v4/middleware"
@@ -46,6 +47,11 @@ func main() {
 
 	e.Use(middleware.Logging())
 	e.Use(middleware.CORS())
+	e.Use(middleware.WebSocketOrigin())
+
+	// Per-tenant daily feature usage counts (no PII), rolled up by analytics-service
+	featureUsage := middleware.NewFeatureUsage()
+	e.Use(featureUsage.Track())
 
 	rateLimiter := middleware.NewRateLimiter()
 
@@ -74,6 +80,9 @@ func main() {
 
 	public := e.Group("")
 
+	// Suspended tenants and tenants pending deletion are refused on every tenant-scoped route
+	activeTenant := middleware.ActiveTenant()
+
 	tenantServiceURL := utils.GetEnv("TENANT_SERVICE_URL")
 	productServiceURL := utils.GetEnv("PRODUCT_SERVICE_URL")
 	authServiceURL := utils.GetEnv("AUTH_SERVICE_URL")
@@ -82,6 +91,9 @@ func main() {
 	analyticsServiceURL := utils.GetEnv("ANALYTICS_SERVICE_URL")
 
 	public.POST("/api/tenants/register", proxyHandler(tenantServiceURL, "/register"))
+
+	// SCIM 2.0 provisioning; user-service authenticates the identity provider's SCIM token
+	public.Any("/scim/v2/*", proxyWildcard(userServiceURL))
 	public.GET("/api/public/tenants/:tenant_slug/config", func(c echo.Context) error {
 		tenantSlug := c.Param("tenant_slug")
 		return proxyHandler(tenantServiceURL, "/public/tenants/"+tenantSlug+"/config")(c)
@@ -107,7 +119,7 @@ func main() {
 		}
 		proxy.ServeHTTP(c.Response(), c.Request())
 		return nil
-	})
+	}, activeTenant)
 
 	// Public product photo endpoint
 	public.GET("/api/public/products/:tenant_id/:id/photo", func(c echo.Context) error {
@@ -123,36 +135,124 @@ func main() {
 		}
 		proxy.ServeHTTP(c.Response(), c.Request())
 		return nil
-	})
+	}, activeTenant)
 
 	public.POST("/api/auth/login", proxyHandler(authServiceURL, "/login"))
 	public.POST("/api/auth/password-reset/request", proxyHandler(authServiceURL, "/password-reset/request"))
 	public.POST("/api/auth/password-reset/reset", proxyHandler(authServiceURL, "/password-reset/reset"))
+	public.POST("/api/auth/password/change-required", proxyHandler(authServiceURL, "/password/change-required"))
+	public.POST("/api/auth/magic-link/request", proxyHandler(authServiceURL, "/magic-link/request"))
+	public.POST("/api/auth/magic-link/consume", proxyHandler(authServiceURL, "/magic-link/consume"))
 	public.POST("/api/auth/verify-account", proxyHandler(authServiceURL, "/verify-account"))
+	public.POST("/api/auth/email-change/confirm", proxyHandler(authServiceURL, "/email-change/confirm"))
+	public.POST("/api/auth/email-change/verify", proxyHandler(authServiceURL, "/email-change/verify"))
+	public.POST("/api/auth/passkeys/login/options", proxyHandler(authServiceURL, "/passkeys/login/options"))
+	public.POST("/api/auth/passkeys/login", proxyHandler(authServiceURL, "/passkeys/login"))
+	public.GET("/api/auth/sso/google/login", proxyHandler(authServiceURL, "/sso/google/login"))
+	public.GET("/api/auth/sso/google/callback", proxyHandler(authServiceURL, "/sso/google/callback"))
+	public.GET("/api/auth/captcha/config", proxyHandler(authServiceURL, "/captcha/config"))
+
+	// Cashier PIN quick-switch; the device authenticates with its enrolled terminal cookie
+	public.GET("/api/auth/pin/users", proxyHandler(authServiceURL, "/pin/users"))
+	public.POST("/api/auth/pin/switch", proxyHandler(authServiceURL, "/pin/switch"))
+
+	// Platform operators authenticate with their API key, not a session
+	public.POST("/api/auth/impersonation", proxyHandler(authServiceURL, "/impersonation"))
+	public.Any("/api/v1/operator/tenants/*", proxyWildcard(tenantServiceURL))
 
 	public.POST("/api/invitations/:token/accept", proxyHandler(userServiceURL, "/invitations/:token/accept"))
 
+	// Personal data export downloads are authorized by the signed link emailed to the user
+	public.GET("/api/v1/data-exports/:export_id/download", proxyWildcard(userServiceURL))
+
 	protected := e.Group("")
 	protected.Use(middleware.JWTAuth())
 	protected.Use(middleware.TenantScope())
+	protected.Use(activeTenant)
 
 	// Refresh endpoint - outside protected group since it may not have valid JWT
 	e.POST("/api/auth/refresh", proxyHandler(authServiceURL, "/refresh"))
 
 	protected.GET("/api/auth/session", proxyHandler(authServiceURL, "/session"))
 	protected.POST("/api/auth/logout", proxyHandler(authServiceURL, "/logout"))
+	protected.POST("/api/auth/pin/lock", proxyHandler(authServiceURL, "/pin/lock"))
+	protected.POST("/api/auth/step-up/password", proxyHandler(authServiceURL, "/step-up/password"))
+	protected.POST("/api/auth/step-up/passkey/options", proxyHandler(authServiceURL, "/step-up/passkey/options"))
+	protected.POST("/api/auth/step-up/passkey", proxyHandler(authServiceURL, "/step-up/passkey"))
+	protected.POST("/api/auth/email-change", proxyHandler(authServiceURL, "/email-change"), middleware.RequireStepUp())
+	protected.POST("/api/auth/passkeys/register/options", proxyHandler(authServiceURL, "/passkeys/register/options"))
+	protected.POST("/api/auth/passkeys/register", proxyHandler(authServiceURL, "/passkeys/register"))
+	protected.GET("/api/auth/passkeys", proxyHandler(authServiceURL, "/passkeys"))
+	protected.PATCH("/api/auth/passkeys/:passkeyId", proxyHandler(authServiceURL, "/passkeys"))
+	protected.DELETE("/api/auth/passkeys/:passkeyId", proxyHandler(authServiceURL, "/passkeys"))
+	protected.GET("/api/auth/sso/settings", proxyHandler(authServiceURL, "/sso/settings"))
+	protected.PUT("/api/auth/sso/settings", proxyHandler(authServiceURL, "/sso/settings"))
+	protected.GET("/api/auth/captcha/settings", proxyHandler(authServiceURL, "/captcha/settings"))
+	protected.PUT("/api/auth/captcha/settings", proxyHandler(authServiceURL, "/captcha/settings"))
+	protected.GET("/api/auth/password-rotation", proxyHandler(authServiceURL, "/password-rotation"))
+	protected.PUT("/api/auth/password-rotation", proxyHandler(authServiceURL, "/password-rotation"))
+	protected.POST("/api/auth/password-rotation/force-change", proxyHandler(authServiceURL, "/password-rotation/force-change"))
+	protected.GET("/api/auth/session-limits", proxyHandler(authServiceURL, "/session-limits"))
+	protected.PUT("/api/auth/session-limits", proxyHandler(authServiceURL, "/session-limits"))
+	protected.POST("/api/auth/users/:lockedUserId/unlock", proxyHandler(authServiceURL, "/users/unlock"))
+	protected.POST("/api/auth/impersonation/stop", proxyHandler(authServiceURL, "/impersonation/stop"))
 
 	protected.GET("/api/tenant", proxyHandler(tenantServiceURL, "/tenant"))
 
-	// Admin tenant configuration routes (owner only)
+	// Enrolling a device as a shared PIN terminal (settings.write)
+	pinTerminalGroup := protected.Group("/api/auth/pin")
+	pinTerminalGroup.Use(middleware.RequirePermission(middleware.PermissionSettingsWrite))
+	pinTerminalGroup.POST("/terminal", proxyHandler(authServiceURL, "/pin/terminal"))
+	pinTerminalGroup.DELETE("/terminal", proxyHandler(authServiceURL, "/pin/terminal"))
+
+	// Quick-switch PINs: everyone manages their own, managing another user's is checked by the user service
+	protected.GET("/api/v1/users/me/pin", proxyWildcard(userServiceURL))
+	protected.PUT("/api/v1/users/me/pin", proxyWildcard(userServiceURL))
+	protected.GET("/api/v1/users/:user_id/pin", proxyWildcard(userServiceURL))
+	protected.PUT("/api/v1/users/:user_id/pin", proxyWildcard(userServiceURL))
+	protected.DELETE("/api/v1/users/:user_id/pin", proxyWildcard(userServiceURL))
+
+	// Own profile and avatar, for every staff member
+	protected.GET("/api/v1/users/me", proxyWildcard(userServiceURL))
+	protected.PATCH("/api/v1/users/me", proxyWildcard(userServiceURL))
+	protected.PUT("/api/v1/users/me/avatar", proxyWildcard(userServiceURL))
+	protected.DELETE("/api/v1/users/me/avatar", proxyWildcard(userServiceURL))
+
+	// Own notification preferences (event type x channel), for every staff member
+	protected.GET("/api/v1/users/me/notification-preferences", proxyWildcard(userServiceURL))
+	protected.PATCH("/api/v1/users/me/notification-preferences", proxyWildcard(userServiceURL))
+
+	// Personal data exports, for every staff member's own data
+	protected.POST("/api/v1/users/me/data-exports", proxyWildcard(userServiceURL))
+	protected.GET("/api/v1/users/me/data-exports/:export_id", proxyWildcard(userServiceURL))
+
+	// Staff management (users.invite); which users an owner or manager may change is checked by the user service
+	userManagementGroup := protected.Group("/api/v1/users")
+	userManagementGroup.Use(middleware.RequirePermission(middleware.PermissionUsersInvite))
+	userManagementGroup.GET("", proxyWildcard(userServiceURL))
+	userManagementGroup.GET("/:user_id", proxyWildcard(userServiceURL))
+	userManagementGroup.GET("/:user_id/activity", proxyWildcard(userServiceURL))
+	userManagementGroup.PATCH("/:user_id", proxyWildcard(userServiceURL))
+	userManagementGroup.PUT("/:user_id/role", proxyWildcard(userServiceURL))
+	userManagementGroup.PUT("/:user_id/outlet", proxyWildcard(userServiceURL))
+	userManagementGroup.POST("/:user_id/deactivate", proxyWildcard(userServiceURL))
+	userManagementGroup.POST("/:user_id/reactivate", proxyWildcard(userServiceURL))
+
+	// Admin tenant configuration routes (tenant.write)
 	adminTenantConfig := protected.Group("/api/v1/admin/tenants")
-	adminTenantConfig.Use(middleware.RBACMiddleware(middleware.RoleOwner))
+	adminTenantConfig.Use(middleware.RequirePermission(middleware.PermissionTenantWrite))
+	// Payment credentials changes require recent re-authentication
+	adminTenantConfig.PATCH("/:tenant_id/midtrans-config", proxyWildcard(tenantServiceURL), middleware.RequireStepUp())
+	adminTenantConfig.PATCH("/:tenant_id/payment-gateway-config", proxyWildcard(tenantServiceURL), middleware.RequireStepUp())
 	adminTenantConfig.Any("/*", proxyWildcard(tenantServiceURL))
+	// Every staff member may list the outlets, e.g. to pick where an order is for
+	protected.GET("/api/v1/admin/tenants/:tenant_id/outlets", proxyWildcard(tenantServiceURL))
 
-	// Invitation endpoints - only owner and manager can create/resend
+	// Invitation endpoints - creating and resending requires users.invite
 	inviteGroup := protected.Group("")
-	inviteGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner, middleware.RoleManager))
+	inviteGroup.Use(middleware.RequirePermission(middleware.PermissionUsersInvite))
 	inviteGroup.POST("/api/invitations", proxyHandler(userServiceURL, "/invitations"))
+	inviteGroup.POST("/api/invitations/bulk", proxyHandler(userServiceURL, "/invitations/bulk"))
 	inviteGroup.POST("/api/invitations/:id/resend", proxyHandler(userServiceURL, "/invitations/:id/resend"))
 
 	// All authenticated users can list invitations
@@ -160,65 +260,129 @@ func main() {
 
 	// Product service routes - only owner and manager can manage products
 	productGroup := protected.Group("")
-	productGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner, middleware.RoleManager))
+	productGroup.Use(middleware.RequireReadWritePermission(middleware.PermissionProductsRead, middleware.PermissionProductsWrite))
 	productGroup.Any("/api/v1/products*", proxyWildcard(productServiceURL))
 	productGroup.Any("/api/v1/categories*", proxyWildcard(productServiceURL))
 	productGroup.Any("/api/v1/inventory*", proxyWildcard(productServiceURL))
 
+	// Catalog versions publish menu changes to guests (catalog.publish)
+	catalogVersionGroup := protected.Group("")
+	catalogVersionGroup.Use(middleware.RequirePermission(middleware.PermissionCatalogPublish))
+	catalogVersionGroup.Any("/api/v1/catalog-versions*", proxyWildcard(productServiceURL))
+
 	// Order service routes
 	orderServiceURL := utils.GetEnv("ORDER_SERVICE_URL")
 
 	// Public guest ordering routes (no auth required)
-	publicOrders := e.Group("/api/v1/public/:tenantId")
+	publicOrders := e.Group("/api/v1/public/:tenantId", activeTenant)
 	// publicOrders.Use(middleware.RateLimit()) // Rate limiting will be added later
 	publicOrders.Any("/*", proxyWildcard(orderServiceURL))
 
-	// Admin order management routes (requires auth + appropriate role)
+	// Host-addressed storefront: the tenant comes from the storefront's hostname
+	// (<slug>.<platform domain> or a verified custom domain) instead of the URL path
+	storefront := e.Group("/api/v1/storefront", middleware.StorefrontTenant(), activeTenant)
+	storefront.GET("/config", proxyStorefront(tenantServiceURL, func(c echo.Context, tenantID, tenantSlug string) string {
+		return "/public/tenants/" + tenantSlug + "/config"
+	}))
+	storefront.GET("/menu/products", proxyStorefront(productServiceURL, func(c echo.Context, tenantID, tenantSlug string) string {
+		return "/public/menu/" + tenantID + "/products"
+	}))
+	storefront.GET("/products/:id/photo", proxyStorefront(productServiceURL, func(c echo.Context, tenantID, tenantSlug string) string {
+		return "/public/products/" + tenantID + "/" + c.Param("id") + "/photo"
+	}))
+	storefront.Any("/*", proxyStorefront(orderServiceURL, func(c echo.Context, tenantID, tenantSlug string) string {
+		return "/api/v1/public/" + tenantID + "/" + c.Param("*")
+	}))
+
+	// Admin order management routes (orders.read to view, orders.write to change)
 	adminOrders := protected.Group("/api/v1/admin")
-	adminOrders.Use(middleware.RBACMiddleware(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier))
-	adminOrders.Any("/orders*", proxyWildcard(orderServiceURL))
+	adminOrders.Use(middleware.RequireReadWritePermission(middleware.PermissionOrdersRead, middleware.PermissionOrdersWrite))
+	adminOrders.Any("/orders*", proxyWildcard(orderServiceURL)) // Includes the /orders/live WebSocket feed
 	adminOrders.Any("/offline-orders*", proxyWildcard(orderServiceURL))
-
-	// Admin order settings routes (requires auth, owner/manager only)
+	adminOrders.Any("/returns*", proxyWildcard(orderServiceURL))
+	adminOrders.Any("/kitchen*", proxyWildcard(orderServiceURL))
+	adminOrders.Any("/tables*", proxyWildcard(orderServiceURL))
+	adminOrders.Any("/print-jobs*", proxyWildcard(orderServiceURL))
+	adminOrders.Any("/shifts*", proxyWildcard(orderServiceURL)) // Listing and closing others' shifts is limited to owner/manager by order-service
+
+	// Refunds additionally require orders.refund; these routes take precedence over the wildcards above
+	refundGroup := protected.Group("/api/v1/admin")
+	refundGroup.Use(middleware.RequirePermission(middleware.PermissionOrdersRefund))
+	refundGroup.POST("/orders/:id/payments/refund", proxyWildcard(orderServiceURL))
+	refundGroup.POST("/shifts/current/cash-refunds", proxyWildcard(orderServiceURL))
+
+	// Admin order settings, voucher and promotion routes (settings.write)
 	adminSettings := protected.Group("/api/v1/admin")
-	adminSettings.Use(middleware.RBACMiddleware(middleware.RoleOwner, middleware.RoleManager))
+	adminSettings.Use(middleware.RequirePermission(middleware.PermissionSettingsWrite))
 	adminSettings.Any("/settings*", proxyWildcard(orderServiceURL))
+	adminSettings.Any("/vouchers*", proxyWildcard(orderServiceURL))
+	adminSettings.Any("/promotions*", proxyWildcard(orderServiceURL))
+
+	// Settlement reports (analytics.read)
+	adminReports := protected.Group("/api/v1/admin")
+	adminReports.Use(middleware.RequirePermission(middleware.PermissionAnalyticsRead))
+	adminReports.Any("/settlement-reports*", proxyWildcard(orderServiceURL))
+
+	// Subscription plan and invoices (tenant.write); order-service additionally limits them to the owner
+	adminBilling := protected.Group("/api/v1/admin")
+	adminBilling.Use(middleware.RequirePermission(middleware.PermissionTenantWrite))
+	adminBilling.Any("/billing*", proxyWildcard(orderServiceURL))
+
+	// Refund approvals (refunds.approve)
+	// Cashiers file refund requests through /orders/:id/payments/refund but cannot approve them
+	adminRefundApprovals := protected.Group("/api/v1/admin")
+	adminRefundApprovals.Use(middleware.RequirePermission(middleware.PermissionRefundsApprove))
+	adminRefundApprovals.Any("/refund-requests*", proxyWildcard(orderServiceURL))
 
 	// Webhook routes (no auth, but signature verification in order-service)
 	e.Any("/api/v1/webhooks/*", proxyWildcard(orderServiceURL))
 
-	// Notification service routes (owner/manager only)
+	// Notification service routes (settings.write)
 	notificationServiceURL := utils.GetEnv("NOTIFICATION_SERVICE_URL")
 	notificationGroup := protected.Group("/api/v1")
-	notificationGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner, middleware.RoleManager))
+	notificationGroup.Use(middleware.RequirePermission(middleware.PermissionSettingsWrite))
 	notificationGroup.Any("/notifications*", proxyWildcard(notificationServiceURL))
 
-	// User notification preferences routes (owner/manager only)
+	// User notification preferences routes (settings.write)
 	userNotificationGroup := protected.Group("/api/v1/users")
-	userNotificationGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner, middleware.RoleManager))
+	userNotificationGroup.Use(middleware.RequirePermission(middleware.PermissionSettingsWrite))
 	userNotificationGroup.GET("/notification-preferences", proxyHandler(userServiceURL, "/api/v1/users/notification-preferences"))
+	userNotificationGroup.GET("/:user_id/notification-preferences", proxyWildcard(userServiceURL))
 	userNotificationGroup.PATCH("/:user_id/notification-preferences", func(c echo.Context) error {
 		userID := c.Param("user_id")
 		return proxyHandler(userServiceURL, "/api/v1/users/"+userID+"/notification-preferences")(c)
 	})
 
-	// Audit service routes (owner only - compliance audit trail access)
+	// Team routes (users.invite): teams group staff for notification routing and staff list filters
+	teamGroup := protected.Group("/api/v1")
+	teamGroup.Use(middleware.RequirePermission(middleware.PermissionUsersInvite))
+	teamGroup.Any("/teams*", proxyWildcard(userServiceURL))
+
+	// Custom role routes (roles.manage - owner only)
+	roleGroup := protected.Group("/api/v1")
+	roleGroup.Use(middleware.RequirePermission(middleware.PermissionRolesManage))
+	roleGroup.Any("/roles*", proxyWildcard(userServiceURL))
+	roleGroup.PUT("/users/:user_id/custom-role", proxyWildcard(userServiceURL))
+	roleGroup.Any("/scim/*", proxyWildcard(userServiceURL))
+
+	// Audit service routes (audit.read - compliance audit trail access)
 	auditGroup := protected.Group("/api/v1")
-	auditGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner))
+	auditGroup.Use(middleware.RequirePermission(middleware.PermissionAuditRead))
 	auditGroup.Any("/audit-events*", proxyWildcard(auditServiceURL))
 	auditGroup.Any("/consent-records*", proxyWildcard(auditServiceURL))
 	auditGroup.Any("/audit/tenant*", proxyWildcard(auditServiceURL))            // Tenant audit trail (T110)
 	auditGroup.Any("/admin/compliance/report*", proxyWildcard(auditServiceURL)) // Compliance report (T201)
 
-	// Tenant data rights routes (owner only - UU PDP compliance)
+	// Tenant data rights routes (data_rights.write - UU PDP compliance); PII export requires recent re-authentication
 	tenantDataGroup := protected.Group("/api/v1/tenant")
-	tenantDataGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner))
+	tenantDataGroup.Use(middleware.RequirePermission(middleware.PermissionDataRightsWrite), middleware.RequireStepUp())
 	tenantDataGroup.GET("/data", proxyHandler(tenantServiceURL, "/api/v1/tenant/data"))
 	tenantDataGroup.POST("/data/export", proxyHandler(tenantServiceURL, "/api/v1/tenant/data/export"))
+	tenantDataGroup.Any("/data/exports*", proxyWildcard(tenantServiceURL))
 
-	// User deletion routes (owner only - UU PDP compliance)
+	// User deletion routes (data_rights.write - UU PDP compliance); requires recent re-authentication
 	userDeletionGroup := protected.Group("/api/v1/tenant/users")
-	userDeletionGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner))
+	userDeletionGroup.Use(middleware.RequirePermission(middleware.PermissionDataRightsWrite), middleware.RequireStepUp())
 	userDeletionGroup.DELETE("/:user_id", func(c echo.Context) error {
 		userID := c.Param("user_id")
 		path := "/api/v1/users/" + userID
@@ -244,9 +408,9 @@ func main() {
 	protected.POST("/api/v1/consent/revoke", proxyHandler(auditServiceURL, "/api/v1/consent/revoke"))
 	protected.GET("/api/v1/consent/history", proxyHandler(auditServiceURL, "/api/v1/consent/history"))
 
-	// Analytics service routes (owner and manager only)
+	// Analytics service routes (analytics.read)
 	analyticsGroup := protected.Group("/api/v1/analytics")
-	analyticsGroup.Use(middleware.RBACMiddleware(middleware.RoleOwner, middleware.RoleManager))
+	analyticsGroup.Use(middleware.RequirePermission(middleware.PermissionAnalyticsRead))
 	analyticsGroup.Any("/*", proxyWildcard(analyticsServiceURL))
 
 	port := utils.GetEnv("PORT")
@@ -280,6 +444,12 @@ func proxyHandler(targetURL, path string) echo.HandlerFunc {
 			if c.Param("id") != "" {
 				req.URL.Path = "/invitations/" + c.Param("id") + "/resend"
 			}
+			if c.Param("passkeyId") != "" {
+				req.URL.Path = "/passkeys/" + c.Param("passkeyId")
+			}
+			if c.Param("lockedUserId") != "" {
+				req.URL.Path = "/users/" + c.Param("lockedUserId") + "/unlock"
+			}
 
 			// Forward context values as headers
 			if tenantID := c.Get("tenant_id"); tenantID != nil {
@@ -291,6 +461,22 @@ func proxyHandler(targetURL, path string) echo.HandlerFunc {
 			if role := c.Get("role"); role != nil {
 				req.Header.Set("X-User-Role", role.(string))
 			}
+			req.Header.Del("X-User-Permissions")
+			if permissions, ok := c.Get("permissions").([]string); ok {
+				req.Header.Set("X-User-Permissions", strings.Join(permissions, ","))
+			}
+			// Identify the real operator behind impersonated requests; never trust a client-sent value
+			req.Header.Del("X-Impersonator-ID")
+			req.Header.Del("X-Impersonator-Email")
+			if impersonatorID := c.Get("impersonator_id"); impersonatorID != nil {
+				req.Header.Set("X-Impersonator-ID", impersonatorID.(string))
+				req.Header.Set("X-Impersonator-Email", c.Get("impersonator_email").(string))
+			}
+			// Scope requests of staff working at one outlet to it; never trust a client-sent value
+			req.Header.Del("X-Outlet-ID")
+			if outletID, ok := c.Get("outlet_id").(string); ok {
+				req.Header.Set("X-Outlet-ID", outletID)
+			}
 		}
 
 		proxy.ServeHTTP(c.Response(), c.Request())
@@ -327,6 +513,50 @@ func proxyWildcard(targetURL string) echo.HandlerFunc {
 			if role := c.Get("role"); role != nil {
 				req.Header.Set("X-User-Role", role.(string))
 			}
+			req.Header.Del("X-User-Permissions")
+			if permissions, ok := c.Get("permissions").([]string); ok {
+				req.Header.Set("X-User-Permissions", strings.Join(permissions, ","))
+			}
+			// Identify the real operator behind impersonated requests; never trust a client-sent value
+			req.Header.Del("X-Impersonator-ID")
+			req.Header.Del("X-Impersonator-Email")
+			if impersonatorID := c.Get("impersonator_id"); impersonatorID != nil {
+				req.Header.Set("X-Impersonator-ID", impersonatorID.(string))
+				req.Header.Set("X-Impersonator-Email", c.Get("impersonator_email").(string))
+			}
+			// Scope requests of staff working at one outlet to it; never trust a client-sent value
+			req.Header.Del("X-Outlet-ID")
+			if outletID, ok := c.Get("outlet_id").(string); ok {
+				req.Header.Set("X-Outlet-ID", outletID)
+			}
+		}
+
+		proxy.ServeHTTP(c.Response(), c.Request())
+
+		return nil
+	}
+}
+
+// proxyStorefront forwards a host-addressed storefront request to the tenant's path on targetURL
+// StorefrontTenant must have resolved the tenant; the query string is kept.
+func proxyStorefront(targetURL string, path func(c echo.Context, tenantID, tenantSlug string) string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		target, err := url.Parse(targetURL)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Service configuration error",
+			})
+		}
+
+		tenantPath := path(c, c.Get("storefront_tenant_id").(string), c.Get("storefront_tenant_slug").(string))
+		proxy := httputil.NewSingleHostReverseProxy(target)
+
+		proxy.Director = func(req *http.Request) {
+			req.Host = target.Host
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.URL.Path = tenantPath
+			req.URL.RawPath = ""
 		}
 
 		proxy.ServeHTTP(c.Response(), c.Request())
@@ -15,9 +15,20 @@ type JWTClaims struct {
 	TenantID  string `json:"tenantId"`
 	Email     string `json:"email"`
 	Role      string `json:"role"`
+	// Permissions granted by the role; nil for tokens issued before permissions existed
+	Permissions []string `json:"permissions"`
+	// Outlet the user works at; empty for users working at every outlet
+	OutletID string `json:"outletId,omitempty"`
+	// Set when a platform operator is acting as the user
+	Impersonation *ImpersonationClaims `json:"impersonation,omitempty"`
 	jwt.RegisteredClaims
 }
 
+type ImpersonationClaims struct {
+	OperatorID    string `json:"operatorId"`
+	OperatorEmail string `json:"operatorEmail"`
+}
+
 func JWTAuth() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -57,10 +68,21 @@ func JWTAuth() echo.MiddlewareFunc {
 				})
 			}
 
+			c.Set("session_id", claims.SessionID)
 			c.Set("user_id", claims.UserID)
 			c.Set("tenant_id", claims.TenantID)
 			c.Set("email", claims.Email)
 			c.Set("role", claims.Role)
+			if claims.Permissions != nil {
+				c.Set("permissions", claims.Permissions)
+			}
+			if claims.OutletID != "" {
+				c.Set("outlet_id", claims.OutletID)
+			}
+			if claims.Impersonation != nil {
+				c.Set("impersonator_id", claims.Impersonation.OperatorID)
+				c.Set("impersonator_email", claims.Impersonation.OperatorEmail)
+			}
 
 			return next(c)
 		}
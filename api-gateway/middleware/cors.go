@@ -1,23 +1,165 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pos/api-gateway/utils"
+	"github.com/rs/zerolog/log"
 )
 
-func CORS() echo.MiddlewareFunc {
-	allowOrigins := utils.GetEnv("ALLOWED_ORIGINS")
+const (
+	// Preflight responses may be cached by browsers for this many seconds
+	corsMaxAge = 3600
+
+	// How long a resolved tenant domain is trusted before asking tenant-service again
+	originCacheTTL = 5 * time.Minute
+	// Unknown origins are cached for a shorter time so newly added domains work quickly
+	originNegativeCacheTTL = 30 * time.Second
+	// Any client can send any Origin header, so the number of cached answers is bounded
+	originCacheMaxEntries = 10000
+)
+
+// OriginResolver decides whether a browser origin may call the API
+// Static origins come from ALLOWED_ORIGINS, which may include http origins for local
+// development; tenant storefront domains are only trusted over https, are looked up in
+// tenant-service and cached in memory.
+type OriginResolver struct {
+	staticOrigins    map[string]bool
+	tenantServiceURL string
+	client           *http.Client
+	cache            *ttlCache[bool]
+}
+
+func NewOriginResolver(allowedOrigins string, tenantServiceURL string) *OriginResolver {
+	staticOrigins := make(map[string]bool)
+	for _, origin := range strings.Split(allowedOrigins, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			staticOrigins[strings.ToLower(origin)] = true
+		}
+	}
+
+	return &OriginResolver{
+		staticOrigins:    staticOrigins,
+		tenantServiceURL: tenantServiceURL,
+		client:           &http.Client{Timeout: 3 * time.Second},
+		cache:            newTTLCache[bool](originCacheMaxEntries),
+	}
+}
+
+// IsAllowed reports whether the origin is a static origin or an active tenant domain
+func (r *OriginResolver) IsAllowed(origin string) (bool, error) {
+	origin = strings.ToLower(strings.TrimRight(origin, "/"))
+	if origin == "" {
+		return false, nil
+	}
+
+	if r.staticOrigins[origin] {
+		return true, nil
+	}
+
+	// Credentials are sent with CORS requests, so a tenant domain served over plain http is not trusted
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" || parsed.Scheme != "https" {
+		return false, nil
+	}
+
+	if allowed, ok := r.cache.get(origin); ok {
+		return allowed, nil
+	}
+
+	allowed, err := r.lookupTenantDomain(origin)
+	if err != nil {
+		// Fail closed without caching so the next request retries the lookup
+		log.Warn().Err(err).Str("origin", origin).Msg("Failed to resolve CORS origin")
+		return false, nil
+	}
+
+	ttl := originCacheTTL
+	if !allowed {
+		ttl = originNegativeCacheTTL
+	}
+	r.cache.set(origin, allowed, ttl)
 
-	origins := strings.Split(allowOrigins, ",")
+	return allowed, nil
+}
+
+func (r *OriginResolver) lookupTenantDomain(origin string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	checkURL := fmt.Sprintf("%s/public/domains/resolve?origin=%s", r.tenantServiceURL, url.QueryEscape(origin))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("tenant domain lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusBadRequest:
+		return false, nil
+	default:
+		return false, fmt.Errorf("tenant domain lookup returned status %d", resp.StatusCode)
+	}
+}
+
+// defaultOriginResolver is shared by the CORS and WebSocket origin checks so they use one cache
+var defaultOriginResolver = sync.OnceValue(func() *OriginResolver {
+	return NewOriginResolver(utils.GetEnv("ALLOWED_ORIGINS"), utils.GetEnv("TENANT_SERVICE_URL"))
+})
+
+func CORS() echo.MiddlewareFunc {
+	resolver := defaultOriginResolver()
 
 	return middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins:     origins,
+		// Echo reflects the single matching origin back, so multiple origins work with credentials
+		AllowOriginFunc:  resolver.IsAllowed,
 		AllowMethods:     []string{echo.GET, echo.POST, echo.PUT, echo.PATCH, echo.DELETE, echo.OPTIONS},
 		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-Request-ID", "X-Tenant-ID", "X-User-ID", "X-User-Email", "X-User-Role", "X-Session-Id"},
 		AllowCredentials: true,
-		MaxAge:           3600,
+		MaxAge:           corsMaxAge,
 	})
 }
+
+// WebSocketOrigin rejects WebSocket upgrades from origins the CORS policy does not allow
+// CORS does not apply to WebSocket handshakes and browsers send the auth cookie with them,
+// so without this any site could open a staff session's live order feed.
+func WebSocketOrigin() echo.MiddlewareFunc {
+	resolver := defaultOriginResolver()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !strings.EqualFold(c.Request().Header.Get(echo.HeaderUpgrade), "websocket") {
+				return next(c)
+			}
+
+			origin := c.Request().Header.Get(echo.HeaderOrigin)
+			if allowed, _ := resolver.IsAllowed(origin); !allowed {
+				log.Warn().
+					Str("origin", origin).
+					Str("path", c.Request().URL.Path).
+					Msg("Rejected WebSocket upgrade from disallowed origin")
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Origin not allowed",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// Feature usage counters live in one Redis hash per UTC day, keyed
+// "feature_usage:YYYY-MM-DD" with fields "<tenant_id>:<feature>". analytics-service
+// rolls them up into Postgres. Only tenant IDs and feature names are stored - no users,
+// IPs, request bodies or resource IDs.
+const (
+	featureUsageKeyPrefix = "feature_usage:"
+	featureUsageKeyTTL    = 8 * 24 * time.Hour
+)
+
+// tenantInPath captures the tenant ID of public routes
+const tenantInPath = `([0-9a-fA-F-]{36})`
+
+// featureRule maps requests to a feature; methods nil matches any method
+type featureRule struct {
+	feature string
+	methods []string
+	path    *regexp.Regexp
+}
+
+var (
+	writeMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+	featureRules = []featureRule{
+		{"product_photos", writeMethods, regexp.MustCompile(`^/api/v1/products/[^/]+/photos`)},
+		{"product_photo_views", []string{http.MethodGet}, regexp.MustCompile(`^/api/public/products/` + tenantInPath + `/[^/]+/photo$`)},
+		{"catalog_versions", writeMethods, regexp.MustCompile(`^/api/v1/catalog-versions`)},
+		{"voucher_management", writeMethods, regexp.MustCompile(`^/api/v1/admin/vouchers`)},
+		{"voucher_redemption", []string{http.MethodPost}, regexp.MustCompile(`^/api/v1/public/` + tenantInPath + `/cart/voucher$`)},
+		{"promotions", writeMethods, regexp.MustCompile(`^/api/v1/admin/promotions`)},
+		{"loyalty_settings", writeMethods, regexp.MustCompile(`^/api/v1/admin/settings/loyalty`)},
+		{"loyalty_balance", []string{http.MethodPost}, regexp.MustCompile(`^/api/v1/public/` + tenantInPath + `/loyalty/balance$`)},
+		{"scheduled_slots", []string{http.MethodGet}, regexp.MustCompile(`^/api/v1/public/` + tenantInPath + `/schedule/slots$`)},
+		{"kitchen_display", nil, regexp.MustCompile(`^/api/v1/admin/kitchen`)},
+		{"offline_orders", []string{http.MethodPost}, regexp.MustCompile(`^/api/v1/admin/offline-orders$`)},
+		{"order_edits", []string{http.MethodPut}, regexp.MustCompile(`^/api/v1/admin/orders/[^/]+/items$`)},
+		{"split_payments", []string{http.MethodPost}, regexp.MustCompile(`^/api/v1/admin/orders/[^/]+/payments/split$`)},
+		{"refunds", []string{http.MethodPost}, regexp.MustCompile(`^/api/v1/admin/orders/[^/]+/payments/refund$`)},
+		{"analytics_dashboard", []string{http.MethodGet}, regexp.MustCompile(`^/api/v1/analytics`)},
+	}
+)
+
+// matchFeature returns the feature a request uses and, for public routes, the tenant in its path
+func matchFeature(method, path string) (feature, tenantID string, ok bool) {
+	for _, rule := range featureRules {
+		if rule.methods != nil && !containsMethod(rule.methods, method) {
+			continue
+		}
+		match := rule.path.FindStringSubmatch(path)
+		if match == nil {
+			continue
+		}
+		if len(match) > 1 {
+			tenantID = match[1]
+		}
+		return rule.feature, tenantID, true
+	}
+	return "", "", false
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// FeatureUsage counts successful requests to tracked features per tenant per day
+type FeatureUsage struct {
+	redis *redis.Client
+}
+
+func NewFeatureUsage() *FeatureUsage {
+	return &FeatureUsage{redis: newRedisClient()}
+}
+
+// Track records feature usage after the request has been served
+// Counting happens off the request path and failures are only logged.
+func (fu *FeatureUsage) Track() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+
+			if err != nil || c.Response().Status >= http.StatusBadRequest {
+				return err
+			}
+			feature, tenantID, ok := matchFeature(c.Request().Method, c.Request().URL.Path)
+			if !ok {
+				return err
+			}
+			if authTenantID, isSet := c.Get("tenant_id").(string); isSet && authTenantID != "" {
+				tenantID = authTenantID
+			}
+			if tenantID == "" {
+				return err
+			}
+
+			logger := c.Logger()
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				defer cancel()
+
+				key := featureUsageKeyPrefix + time.Now().UTC().Format("2006-01-02")
+				pipe := fu.redis.Pipeline()
+				pipe.HIncrBy(ctx, key, tenantID+":"+feature, 1)
+				pipe.Expire(ctx, key, featureUsageKeyTTL)
+				if _, err := pipe.Exec(ctx); err != nil {
+					logger.Errorf("Feature usage counter error: %v", err)
+				}
+			}()
+
+			return err
+		}
+	}
+}
@@ -18,6 +18,8 @@ func Logging() echo.MiddlewareFunc {
 			}
 			c.Set("request_id", requestID)
 			c.Response().Header().Set("X-Request-ID", requestID)
+			// Forwarded to backend services, which carry it into Kafka events as the correlation ID
+			c.Request().Header.Set("X-Request-ID", requestID)
 
 			err := next(c)
 
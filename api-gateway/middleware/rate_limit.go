@@ -17,17 +17,20 @@ type RateLimiter struct {
 }
 
 func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{redis: newRedisClient()}
+}
+
+// newRedisClient connects to the gateway's Redis (rate limits and usage counters)
+func newRedisClient() *redis.Client {
 	redisHost := utils.GetEnv("REDIS_HOST")
 	redisPass := utils.GetEnv("REDIS_PASSWORD")
-	client := redis.NewClient(&redis.Options{
+	return redis.NewClient(&redis.Options{
 		Addr:         redisHost,
 		Password:     redisPass,
 		DialTimeout:  5 * time.Second,
 		ReadTimeout:  3 * time.Second,
 		WriteTimeout: 3 * time.Second,
 	})
-
-	return &RateLimiter{redis: client}
 }
 
 func (rl *RateLimiter) IsRedisConnected() bool {
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/pos/api-gateway/utils"
+)
+
+// stepUpAudience must match the audience auth-service signs step-up tokens with
+const stepUpAudience = "pos-step-up"
+
+type StepUpClaims struct {
+	SessionID string `json:"sessionId"`
+	UserID    string `json:"userId"`
+	Method    string `json:"method"`
+	jwt.RegisteredClaims
+}
+
+// RequireStepUp only lets a request through when the user re-authenticated recently.
+// The step_up_token cookie is issued by auth-service and bound to the session, so a
+// token from another session or user is rejected. Must run after JWTAuth.
+func RequireStepUp() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cookie, err := c.Cookie("step_up_token")
+			if err != nil {
+				return stepUpRequired(c)
+			}
+
+			claims := &StepUpClaims{}
+			token, err := jwt.ParseWithClaims(cookie.Value, claims, func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+				}
+				return stepUpKey(utils.GetEnv("JWT_SECRET")), nil
+			})
+			if err != nil || !token.Valid || !claims.VerifyAudience(stepUpAudience, true) {
+				return stepUpRequired(c)
+			}
+
+			sessionID, _ := c.Get("session_id").(string)
+			userID, _ := c.Get("user_id").(string)
+			if sessionID == "" || claims.SessionID != sessionID || claims.UserID != userID {
+				c.Logger().Warnf("Step-up token does not belong to session of user %s", userID)
+				return stepUpRequired(c)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// stepUpKey derives the step-up signing key so a session token can never pass as a step-up token
+func stepUpKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("step-up"))
+	return mac.Sum(nil)
+}
+
+func stepUpRequired(c echo.Context) error {
+	return c.JSON(http.StatusForbidden, map[string]string{
+		"error": "Recent re-authentication required",
+		"code":  "STEP_UP_REQUIRED",
+	})
+}
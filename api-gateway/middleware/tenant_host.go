@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/api-gateway/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// HostTenant is the tenant a storefront hostname belongs to
+type HostTenant struct {
+	TenantID   string `json:"tenant_id"`
+	TenantSlug string `json:"tenant_slug"`
+}
+
+// HostResolver maps storefront hostnames (<slug>.<platform domain> or a verified
+// custom domain) to tenants through tenant-service, caching answers in memory
+type HostResolver struct {
+	tenantServiceURL string
+	client           *http.Client
+	cache            *ttlCache[*HostTenant] // nil tenants for hosts that belong to none
+}
+
+func NewHostResolver(tenantServiceURL string) *HostResolver {
+	return &HostResolver{
+		tenantServiceURL: tenantServiceURL,
+		client:           &http.Client{Timeout: 3 * time.Second},
+		cache:            newTTLCache[*HostTenant](originCacheMaxEntries),
+	}
+}
+
+// Resolve returns the tenant serving host, or nil when it serves none
+func (r *HostResolver) Resolve(host string) (*HostTenant, error) {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return nil, nil
+	}
+
+	if tenant, ok := r.cache.get(host); ok {
+		return tenant, nil
+	}
+
+	tenant, err := r.lookup(host)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := originCacheTTL
+	if tenant == nil {
+		ttl = originNegativeCacheTTL
+	}
+	r.cache.set(host, tenant, ttl)
+
+	return tenant, nil
+}
+
+func (r *HostResolver) lookup(host string) (*HostTenant, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	checkURL := fmt.Sprintf("%s/public/domains/resolve?host=%s", r.tenantServiceURL, url.QueryEscape(host))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tenant host lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var tenant HostTenant
+		if err := json.NewDecoder(resp.Body).Decode(&tenant); err != nil {
+			return nil, fmt.Errorf("invalid tenant host lookup response: %w", err)
+		}
+		return &tenant, nil
+	case http.StatusNotFound, http.StatusBadRequest:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("tenant host lookup returned status %d", resp.StatusCode)
+	}
+}
+
+// storefrontHost is the hostname the guest's storefront is served from
+// A storefront page calling the API cross-origin sends its own host as Origin; a storefront
+// proxying the API on its own domain is seen through X-Forwarded-Host or Host.
+func storefrontHost(req *http.Request) string {
+	if origin := req.Header.Get(echo.HeaderOrigin); origin != "" {
+		if parsed, err := url.Parse(origin); err == nil && parsed.Host != "" {
+			return parsed.Host
+		}
+	}
+	if forwarded := req.Header.Get("X-Forwarded-Host"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	return req.Host
+}
+
+// StorefrontTenant resolves the tenant of a host-addressed public storefront request
+// The tenant is stored as "storefront_tenant_id" and "storefront_tenant_slug" and sent
+// downstream as X-Tenant-ID; any client-sent X-Tenant-ID is replaced.
+func StorefrontTenant() echo.MiddlewareFunc {
+	resolver := NewHostResolver(utils.GetEnv("TENANT_SERVICE_URL"))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			host := storefrontHost(c.Request())
+			tenant, err := resolver.Resolve(host)
+			if err != nil {
+				log.Error().Err(err).Str("host", host).Msg("Failed to resolve storefront host")
+				return c.JSON(http.StatusBadGateway, map[string]string{
+					"error": "Failed to resolve store",
+				})
+			}
+			if tenant == nil {
+				return c.JSON(http.StatusNotFound, map[string]string{
+					"error": "No store is served at this domain",
+				})
+			}
+
+			c.Set("storefront_tenant_id", tenant.TenantID)
+			c.Set("storefront_tenant_slug", tenant.TenantSlug)
+			c.Request().Header.Set("X-Tenant-ID", tenant.TenantID)
+
+			return next(c)
+		}
+	}
+}
@@ -34,6 +34,11 @@ func TenantScope() echo.MiddlewareFunc {
 				c.Request().Header.Set("X-User-Role", role.(string))
 			}
 
+			c.Request().Header.Del("X-Outlet-ID")
+			if outletID, ok := c.Get("outlet_id").(string); ok {
+				c.Request().Header.Set("X-Outlet-ID", outletID)
+			}
+
 			return next(c)
 		}
 	}
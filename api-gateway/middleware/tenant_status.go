@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pos/api-gateway/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// How long a tenant's status is trusted before asking tenant-service again; a suspension
+	// takes effect within this time
+	tenantStatusCacheTTL = 30 * time.Second
+	// How long a suspended or pending deletion status is still used while tenant-service cannot be reached
+	tenantStatusBlockedTTL = time.Hour
+	// Tenant IDs come from public URLs, so the number of cached statuses is bounded
+	tenantStatusCacheMaxEntries = 10000
+)
+
+// tenantStatusExemptPaths stay open to blocked tenants' staff
+var tenantStatusExemptPaths = map[string]bool{
+	"/api/auth/logout": true,
+}
+
+// pendingDeletionPaths stay open to tenants pending deletion, so owners can take their data with them
+var pendingDeletionPaths = map[string]bool{
+	"/api/v1/tenant/data":          true,
+	"/api/v1/tenant/data/export":   true,
+	"/api/v1/tenant/data/exports*": true,
+}
+
+type tenantStatusCacheEntry struct {
+	status     string
+	freshUntil time.Time
+}
+
+// TenantStatusChecker looks up tenant statuses in tenant-service, caching answers in memory
+type TenantStatusChecker struct {
+	tenantServiceURL string
+	client           *http.Client
+	cache            *ttlCache[tenantStatusCacheEntry]
+}
+
+func NewTenantStatusChecker(tenantServiceURL string) *TenantStatusChecker {
+	return &TenantStatusChecker{
+		tenantServiceURL: tenantServiceURL,
+		client:           &http.Client{Timeout: 3 * time.Second},
+		cache:            newTTLCache[tenantStatusCacheEntry](tenantStatusCacheMaxEntries),
+	}
+}
+
+// Status returns the tenant's status, or "" when tenant-service does not know the tenant
+// When tenant-service cannot be reached, the error is returned along with the tenant's last
+// status if it was suspended or pending deletion, and "" otherwise.
+func (r *TenantStatusChecker) Status(tenantID string) (string, error) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return "", nil
+	}
+
+	entry, cached := r.cache.get(tenantID)
+	if cached && time.Now().Before(entry.freshUntil) {
+		return entry.status, nil
+	}
+
+	status, err := r.lookup(tenantID)
+	if err != nil {
+		if cached {
+			return entry.status, err
+		}
+		return "", err
+	}
+
+	ttl := tenantStatusCacheTTL
+	if status == "suspended" || status == "pending_deletion" {
+		ttl = tenantStatusBlockedTTL
+	}
+	r.cache.set(tenantID, tenantStatusCacheEntry{status: status, freshUntil: time.Now().Add(tenantStatusCacheTTL)}, ttl)
+
+	return status, nil
+}
+
+func (r *TenantStatusChecker) lookup(tenantID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	checkURL := fmt.Sprintf("%s/public/tenant-status/%s", r.tenantServiceURL, url.PathEscape(tenantID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("tenant status lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var body struct {
+			Status string `json:"status"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("invalid tenant status response: %w", err)
+		}
+		return body.Status, nil
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("tenant status lookup returned status %d", resp.StatusCode)
+	}
+}
+
+// requestTenantID is the tenant a request acts for: the signed-in user's, the storefront host's,
+// or the one in the public URL
+func requestTenantID(c echo.Context) string {
+	if tenantID, ok := c.Get("tenant_id").(string); ok && tenantID != "" {
+		return tenantID
+	}
+	if tenantID, ok := c.Get("storefront_tenant_id").(string); ok && tenantID != "" {
+		return tenantID
+	}
+	if tenantID := c.Param("tenantId"); tenantID != "" {
+		return tenantID
+	}
+	return c.Param("tenant_id")
+}
+
+// ActiveTenant refuses requests for suspended tenants and tenants pending deletion
+// Responds 403 with error "tenant_suspended" or "tenant_pending_deletion". When tenant-service
+// cannot be reached the request is deliberately let through, so an outage there does not close
+// every store; tenants last seen suspended or pending deletion stay refused meanwhile.
+// Owners of tenants pending deletion may still export their data.
+func ActiveTenant() echo.MiddlewareFunc {
+	checker := NewTenantStatusChecker(utils.GetEnv("TENANT_SERVICE_URL"))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tenantID := requestTenantID(c)
+			if tenantID == "" || tenantStatusExemptPaths[c.Path()] {
+				return next(c)
+			}
+			// Platform operators impersonating a user may still look into a blocked tenant
+			if c.Get("impersonator_id") != nil {
+				return next(c)
+			}
+
+			status, err := checker.Status(tenantID)
+			if err != nil && status == "" {
+				log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to check tenant status; allowing request")
+				return next(c)
+			}
+			if err != nil {
+				log.Warn().Err(err).Str("tenant_id", tenantID).Str("status", status).Msg("Failed to check tenant status; using last known status")
+			}
+
+			switch status {
+			case "suspended":
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error":   "tenant_suspended",
+					"message": "This store has been suspended",
+				})
+			case "pending_deletion":
+				if pendingDeletionPaths[c.Path()] {
+					return next(c)
+				}
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error":   "tenant_pending_deletion",
+					"message": "This store is closing and its account is scheduled for deletion",
+				})
+			}
+			return next(c)
+		}
+	}
+}
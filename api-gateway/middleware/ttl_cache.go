@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+type ttlCacheEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// ttlCache is an in-memory cache whose entries expire and whose size is bounded
+// Its keys come from request input (origins, hosts, tenant IDs), so entries are dropped
+// once expired and a full cache evicts rather than grows.
+type ttlCache[V any] struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]ttlCacheEntry[V]
+}
+
+func newTTLCache[V any](maxEntries int) *ttlCache[V] {
+	return &ttlCache[V]{
+		maxEntries: maxEntries,
+		entries:    make(map[string]ttlCacheEntry[V]),
+	}
+}
+
+// get returns the cached value for key, or false when there is none or it has expired
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if !time.Now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// set caches value for key during ttl
+// When the cache is full, expired entries are swept first; if none had expired an arbitrary
+// entry is evicted, costing at worst one more lookup for it.
+func (c *ttlCache[V]) set(key string, value V, ttl time.Duration) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = ttlCacheEntry[V]{value: value, expiresAt: now.Add(ttl)}
+}
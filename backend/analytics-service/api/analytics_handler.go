@@ -24,6 +24,22 @@ func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *Analytics
 	}
 }
 
+// serviceFor returns the analytics service for the requesting user's timezone
+// Days are bucketed in the timezone the user picked in their profile, or the server's TZ when they kept
+// the default. X-User-ID is set by the API Gateway.
+func (h *AnalyticsHandler) serviceFor(c echo.Context, tenantID string) *services.AnalyticsService {
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return h.analyticsService
+	}
+	timezone, err := h.analyticsService.UserTimezone(c.Request().Context(), tenantID, userID)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to get user timezone, using the server timezone")
+		return h.analyticsService
+	}
+	return h.analyticsService.InTimezone(timezone)
+}
+
 // GetSalesOverview handles GET /analytics/overview
 // Returns sales metrics, daily sales chart, and category breakdown
 func (h *AnalyticsHandler) GetSalesOverview(c echo.Context) error {
@@ -99,7 +115,7 @@ func (h *AnalyticsHandler) GetSalesOverview(c echo.Context) error {
 	}
 
 	// Get sales overview from service
-	response, err := h.analyticsService.GetSalesOverview(c.Request().Context(), tenantID, timeRange, startDate, endDate)
+	response, err := h.serviceFor(c, tenantID).GetSalesOverview(c.Request().Context(), tenantID, timeRange, startDate, endDate)
 	if err != nil {
 		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get sales overview")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -186,7 +202,7 @@ func (h *AnalyticsHandler) GetTopProducts(c echo.Context) error {
 	}
 
 	// Get top products from service
-	response, err := h.analyticsService.GetTopProducts(c.Request().Context(), tenantID, timeRange, startDate, endDate, limit)
+	response, err := h.serviceFor(c, tenantID).GetTopProducts(c.Request().Context(), tenantID, timeRange, startDate, endDate, limit)
 	if err != nil {
 		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get top products")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -274,7 +290,7 @@ func (h *AnalyticsHandler) GetTopCustomers(c echo.Context) error {
 	}
 
 	// Get top customers from service
-	response, err := h.analyticsService.GetTopCustomers(c.Request().Context(), tenantID, timeRange, startDate, endDate, limit)
+	response, err := h.serviceFor(c, tenantID).GetTopCustomers(c.Request().Context(), tenantID, timeRange, startDate, endDate, limit)
 	if err != nil {
 		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get top customers")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -294,6 +310,81 @@ func (h *AnalyticsHandler) GetTopCustomers(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// GetStaffPerformance handles GET /analytics/staff-performance
+// Returns per-staff orders processed, handling time, refunds issued and stock adjustments for the team view
+func (h *AnalyticsHandler) GetStaffPerformance(c echo.Context) error {
+	startTime := time.Now()
+
+	tenantID := middleware.GetTenantID(c)
+	if tenantID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Tenant ID not found in context",
+		})
+	}
+
+	// Parse query parameters
+	timeRangeStr := c.QueryParam("time_range")
+	if timeRangeStr == "" {
+		timeRangeStr = "this_month"
+	}
+
+	timeRange := models.TimeRange(timeRangeStr)
+	if !timeRange.IsValid() {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid time_range parameter",
+		})
+	}
+
+	// Parse custom date range if provided
+	var startDate, endDate *time.Time
+	if timeRange == models.TimeRangeCustom {
+		startStr := c.QueryParam("start_date")
+		endStr := c.QueryParam("end_date")
+
+		if startStr == "" || endStr == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "start_date and end_date required for custom time range",
+			})
+		}
+
+		start, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid start_date format (use YYYY-MM-DD)",
+			})
+		}
+
+		end, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid end_date format (use YYYY-MM-DD)",
+			})
+		}
+
+		startDate = &start
+		endDate = &end
+	}
+
+	response, err := h.serviceFor(c, tenantID).GetStaffPerformance(c.Request().Context(), tenantID, timeRange, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get staff performance")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve staff performance",
+		})
+	}
+
+	// Log query performance
+	queryTime := time.Since(startTime).Milliseconds()
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("time_range", string(timeRange)).
+		Int("staff_count", len(response.Staff)).
+		Int64("query_time_ms", queryTime).
+		Msg("Staff performance retrieved successfully")
+
+	return c.JSON(http.StatusOK, response)
+}
+
 // GetSalesTrend handles GET /analytics/sales-trend
 // Returns time series data for sales revenue and order count with configurable granularity
 func (h *AnalyticsHandler) GetSalesTrend(c echo.Context) error {
@@ -366,7 +457,7 @@ func (h *AnalyticsHandler) GetSalesTrend(c echo.Context) error {
 	// }
 
 	// Get sales trend from service
-	response, err := h.analyticsService.GetSalesTrend(c.Request().Context(), tenantID, startDate, endDate, granularity)
+	response, err := h.serviceFor(c, tenantID).GetSalesTrend(c.Request().Context(), tenantID, startDate, endDate, granularity)
 	if err != nil {
 		log.Error().Err(err).Str("tenant_id", tenantID).Str("granularity", granularity).Msg("Failed to get sales trend")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
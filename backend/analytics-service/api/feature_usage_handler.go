@@ -0,0 +1,124 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pos/analytics-service/src/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultFeatureUsageDays is the period reported when no dates are given
+const defaultFeatureUsageDays = 30
+
+// FeatureUsageHandler serves feature adoption data to platform operators
+type FeatureUsageHandler struct {
+	repo *repository.FeatureUsageRepository
+}
+
+// NewFeatureUsageHandler creates a new feature usage handler
+func NewFeatureUsageHandler(repo *repository.FeatureUsageRepository) *FeatureUsageHandler {
+	return &FeatureUsageHandler{repo: repo}
+}
+
+// GetSummary handles GET /operator/feature-usage
+// Returns per-feature adoption across tenants for the period
+func (h *FeatureUsageHandler) GetSummary(c echo.Context) error {
+	startDate, endDate, err := parseUsagePeriod(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	summary, err := h.repo.GetSummary(c.Request().Context(), startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get feature usage summary")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve feature usage",
+		})
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
+
+// GetDaily handles GET /operator/feature-usage/daily
+// Returns usage per day, optionally limited to one feature
+func (h *FeatureUsageHandler) GetDaily(c echo.Context) error {
+	startDate, endDate, err := parseUsagePeriod(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	days, err := h.repo.GetDaily(c.Request().Context(), c.QueryParam("feature"), startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get daily feature usage")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve feature usage",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"start_date": startDate.Format("2006-01-02"),
+		"end_date":   endDate.Format("2006-01-02"),
+		"days":       days,
+	})
+}
+
+// GetTenantUsage handles GET /operator/feature-usage/tenants/:tenant_id
+// Returns which features one tenant used in the period
+func (h *FeatureUsageHandler) GetTenantUsage(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid tenant_id"})
+	}
+
+	startDate, endDate, err := parseUsagePeriod(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	features, err := h.repo.GetTenantUsage(c.Request().Context(), tenantID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get tenant feature usage")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve feature usage",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tenant_id":  tenantID,
+		"start_date": startDate.Format("2006-01-02"),
+		"end_date":   endDate.Format("2006-01-02"),
+		"features":   features,
+	})
+}
+
+// parseUsagePeriod reads start_date and end_date (YYYY-MM-DD, UTC days)
+// Both default to the last defaultFeatureUsageDays days ending today.
+func parseUsagePeriod(c echo.Context) (time.Time, time.Time, error) {
+	endDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if endStr := c.QueryParam("end_date"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid end_date format, expected YYYY-MM-DD")
+		}
+		endDate = parsed
+	}
+
+	startDate := endDate.AddDate(0, 0, -(defaultFeatureUsageDays - 1))
+	if startStr := c.QueryParam("start_date"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid start_date format, expected YYYY-MM-DD")
+		}
+		startDate = parsed
+	}
+
+	if startDate.After(endDate) {
+		return time.Time{}, time.Time{}, errors.New("start_date must be before end_date")
+	}
+
+	return startDate, endDate, nil
+}
@@ -68,6 +68,15 @@ func main() {
 	taskRepo := repository.NewTaskRepository(config.GetDB(), encryptor, timezone)
 	tasksHandler := api.NewTasksHandler(taskRepo)
 
+	// Initialize feature usage telemetry
+	featureUsageRepo := repository.NewFeatureUsageRepository(config.GetDB())
+	featureUsageHandler := api.NewFeatureUsageHandler(featureUsageRepo)
+	featureUsageRollupJob := services.NewFeatureUsageRollupJob(config.GetRedis(), featureUsageRepo, 5*time.Minute)
+
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go featureUsageRollupJob.Start(jobCtx)
+
 	// Routes
 	e.GET("/health", healthHandler.Health)
 
@@ -80,8 +89,16 @@ func main() {
 	v1.GET("/analytics/top-products", analyticsHandler.GetTopProducts)
 	v1.GET("/analytics/top-customers", analyticsHandler.GetTopCustomers)
 	v1.GET("/analytics/sales-trend", analyticsHandler.GetSalesTrend)
+	v1.GET("/analytics/staff-performance", analyticsHandler.GetStaffPerformance)
 	v1.GET("/analytics/tasks", tasksHandler.GetOperationalTasks)
 
+	// Platform operator routes (internal only, not proxied by API Gateway)
+	operator := e.Group("/api/v1/operator")
+	operator.Use(customMiddleware.OperatorAuth())
+	operator.GET("/feature-usage", featureUsageHandler.GetSummary)
+	operator.GET("/feature-usage/daily", featureUsageHandler.GetDaily)
+	operator.GET("/feature-usage/tenants/:tenant_id", featureUsageHandler.GetTenantUsage)
+
 	// Start server
 	port := utils.GetEnv("PORT")
 	serverAddr := fmt.Sprintf(":%s", port)
@@ -110,6 +127,7 @@ func main() {
 	<-quit
 
 	log.Info().Msg("Shutting down Analytics Service...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
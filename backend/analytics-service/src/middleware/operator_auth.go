@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// OperatorAuth protects platform operator endpoints with a shared token
+// The token is read from OPERATOR_API_TOKEN and must be sent in the X-Operator-Token
+// header. These routes are not proxied by the API gateway; when no token is
+// configured they are disabled.
+func OperatorAuth() echo.MiddlewareFunc {
+	token := os.Getenv("OPERATOR_API_TOKEN")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token == "" {
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{
+					"error": "Operator API is not configured",
+				})
+			}
+
+			provided := c.Request().Header.Get("X-Operator-Token")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				log.Warn().
+					Str("path", c.Request().URL.Path).
+					Msg("Rejected operator request with invalid token")
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid operator token",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// FeatureUsageCount is one tenant's use of one feature on one UTC day
+type FeatureUsageCount struct {
+	Date     time.Time
+	TenantID string
+	Feature  string
+	Count    int64
+}
+
+// FeatureAdoption summarizes how widely a feature is used over a period
+type FeatureAdoption struct {
+	Feature       string  `json:"feature"`
+	TenantsUsing  int64   `json:"tenants_using"`
+	AdoptionRate  float64 `json:"adoption_rate"` // Percentage of active tenants that used the feature
+	TotalRequests int64   `json:"total_requests"`
+	TenantDays    int64   `json:"tenant_days"` // Days summed over tenants on which the feature was used
+}
+
+// FeatureUsageSummary is the operator overview of feature adoption
+type FeatureUsageSummary struct {
+	StartDate     time.Time         `json:"start_date"`
+	EndDate       time.Time         `json:"end_date"`
+	ActiveTenants int64             `json:"active_tenants"`
+	Features      []FeatureAdoption `json:"features"`
+}
+
+// FeatureUsageDay is the usage of one feature on one day across tenants
+type FeatureUsageDay struct {
+	Date     time.Time `json:"date"`
+	Feature  string    `json:"feature"`
+	Tenants  int64     `json:"tenants"`
+	Requests int64     `json:"requests"`
+}
+
+// TenantFeatureUsage is one tenant's use of a feature over a period
+type TenantFeatureUsage struct {
+	Feature  string    `json:"feature"`
+	Requests int64     `json:"requests"`
+	DaysUsed int64     `json:"days_used"`
+	LastUsed time.Time `json:"last_used"`
+}
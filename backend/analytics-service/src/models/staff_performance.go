@@ -0,0 +1,19 @@
+package models
+
+// StaffPerformance represents one staff member's activity over a time range
+type StaffPerformance struct {
+	UserID             string   `json:"user_id"`
+	Name               string   `json:"name"`
+	Role               string   `json:"role"`
+	OrdersProcessed    int64    `json:"orders_processed"`     // Orders recorded offline or moved to a new status
+	OrdersCompleted    int64    `json:"orders_completed"`     // Orders this staff member marked COMPLETE
+	AvgHandlingMinutes *float64 `json:"avg_handling_minutes"` // Payment to completion; null when nothing was completed
+	RefundsIssued      int64    `json:"refunds_issued"`
+	RefundAmount       float64  `json:"refund_amount"`
+	StockAdjustments   int64    `json:"stock_adjustments"`
+}
+
+// StaffPerformanceResponse contains the team view of the analytics dashboard
+type StaffPerformanceResponse struct {
+	Staff []StaffPerformance `json:"staff"`
+}
@@ -21,10 +21,14 @@ const (
 	TimeRangeCustom     TimeRange = "custom"
 )
 
-// GetDateRange returns the start and end dates for a time range
+// GetDateRange returns the start and end dates for a time range in the server's timezone
 func (tr TimeRange) GetDateRange() (start, end time.Time, err error) {
-	now := time.Now()
-	loc := now.Location()
+	return tr.GetDateRangeIn(time.Local)
+}
+
+// GetDateRangeIn returns the start and end dates for a time range, with days starting at midnight in loc
+func (tr TimeRange) GetDateRangeIn(loc *time.Location) (start, end time.Time, err error) {
+	now := time.Now().In(loc)
 
 	// Normalize to start of day
 	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
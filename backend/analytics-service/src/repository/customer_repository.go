@@ -28,6 +28,8 @@ func NewCustomerRepository(db *sql.DB, encryptor utils.Encryptor, timezone strin
 }
 
 // GetTopCustomersBySpending returns top N customers by total spending
+// Anonymized orders share placeholder PII, so they are left out of customer rankings;
+// their revenue is still counted by the sales queries.
 func (r *CustomerRepository) GetTopCustomersBySpending(ctx context.Context, tenantID string, start, end time.Time, limit int) ([]models.CustomerRanking, error) {
 	query := fmt.Sprintf(`
 		SELECT 
@@ -40,6 +42,7 @@ func (r *CustomerRepository) GetTopCustomersBySpending(ctx context.Context, tena
 		FROM guest_orders
 		WHERE tenant_id = $1 
 			AND status = 'COMPLETE'
+			AND is_anonymized = FALSE
 			AND (created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
 		GROUP BY customer_name, customer_phone, customer_email
 		ORDER BY total_spent DESC
@@ -62,6 +65,7 @@ func (r *CustomerRepository) GetTopCustomersByOrders(ctx context.Context, tenant
 		FROM guest_orders
 		WHERE tenant_id = $1 
 			AND status = 'COMPLETE'
+			AND is_anonymized = FALSE
 			AND (created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
 		GROUP BY customer_name, customer_phone, customer_email
 		ORDER BY order_count DESC
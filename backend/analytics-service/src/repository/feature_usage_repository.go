@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pos/analytics-service/src/models"
+)
+
+// FeatureUsageRepository stores and aggregates daily feature usage counts
+type FeatureUsageRepository struct {
+	db *sql.DB
+}
+
+// NewFeatureUsageRepository creates a new feature usage repository instance
+func NewFeatureUsageRepository(db *sql.DB) *FeatureUsageRepository {
+	return &FeatureUsageRepository{db: db}
+}
+
+// UpsertCounts stores the running daily totals read from Redis
+// Counts only ever grow, so a stored count is never lowered (e.g. after a Redis restart).
+// Counts for tenants that do not exist are skipped.
+func (r *FeatureUsageRepository) UpsertCounts(ctx context.Context, counts []models.FeatureUsageCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO feature_usage_daily (usage_date, tenant_id, feature, request_count)
+		SELECT $1, $2, $3, $4
+		WHERE EXISTS (SELECT 1 FROM tenants WHERE id = $2)
+		ON CONFLICT (usage_date, tenant_id, feature) DO UPDATE
+		SET request_count = GREATEST(feature_usage_daily.request_count, EXCLUDED.request_count),
+		    updated_at = NOW()
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare feature usage upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, count := range counts {
+		if _, err := stmt.ExecContext(ctx, count.Date, count.TenantID, count.Feature, count.Count); err != nil {
+			return fmt.Errorf("failed to upsert feature usage: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit feature usage: %w", err)
+	}
+	return nil
+}
+
+// GetSummary returns adoption per feature between two dates (inclusive), most adopted first
+// Active tenants are those with any tracked usage in the period.
+func (r *FeatureUsageRepository) GetSummary(ctx context.Context, startDate, endDate time.Time) (*models.FeatureUsageSummary, error) {
+	summary := &models.FeatureUsageSummary{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Features:  []models.FeatureAdoption{},
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT tenant_id)
+		FROM feature_usage_daily
+		WHERE usage_date BETWEEN $1 AND $2
+	`, startDate, endDate).Scan(&summary.ActiveTenants)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active tenants: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT feature,
+		       COUNT(DISTINCT tenant_id) AS tenants_using,
+		       SUM(request_count) AS total_requests,
+		       COUNT(*) AS tenant_days
+		FROM feature_usage_daily
+		WHERE usage_date BETWEEN $1 AND $2
+		GROUP BY feature
+		ORDER BY tenants_using DESC, total_requests DESC
+	`, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feature adoption: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var adoption models.FeatureAdoption
+		if err := rows.Scan(&adoption.Feature, &adoption.TenantsUsing, &adoption.TotalRequests, &adoption.TenantDays); err != nil {
+			return nil, fmt.Errorf("failed to scan feature adoption: %w", err)
+		}
+		if summary.ActiveTenants > 0 {
+			adoption.AdoptionRate = float64(adoption.TenantsUsing) / float64(summary.ActiveTenants) * 100
+		}
+		summary.Features = append(summary.Features, adoption)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate feature adoption: %w", err)
+	}
+
+	return summary, nil
+}
+
+// GetDaily returns usage per feature per day between two dates (inclusive)
+// An empty feature returns every feature.
+func (r *FeatureUsageRepository) GetDaily(ctx context.Context, feature string, startDate, endDate time.Time) ([]models.FeatureUsageDay, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT usage_date, feature, COUNT(DISTINCT tenant_id), SUM(request_count)
+		FROM feature_usage_daily
+		WHERE usage_date BETWEEN $1 AND $2
+		  AND ($3 = '' OR feature = $3)
+		GROUP BY usage_date, feature
+		ORDER BY usage_date, feature
+	`, startDate, endDate, feature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily feature usage: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.FeatureUsageDay{}
+	for rows.Next() {
+		var day models.FeatureUsageDay
+		if err := rows.Scan(&day.Date, &day.Feature, &day.Tenants, &day.Requests); err != nil {
+			return nil, fmt.Errorf("failed to scan daily feature usage: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate daily feature usage: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetTenantUsage returns one tenant's usage per feature between two dates (inclusive)
+func (r *FeatureUsageRepository) GetTenantUsage(ctx context.Context, tenantID string, startDate, endDate time.Time) ([]models.TenantFeatureUsage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT feature, SUM(request_count), COUNT(*), MAX(usage_date)
+		FROM feature_usage_daily
+		WHERE tenant_id = $1 AND usage_date BETWEEN $2 AND $3
+		GROUP BY feature
+		ORDER BY SUM(request_count) DESC
+	`, tenantID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenant feature usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.TenantFeatureUsage{}
+	for rows.Next() {
+		var feature models.TenantFeatureUsage
+		if err := rows.Scan(&feature.Feature, &feature.Requests, &feature.DaysUsed, &feature.LastUsed); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant feature usage: %w", err)
+		}
+		usage = append(usage, feature)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tenant feature usage: %w", err)
+	}
+
+	return usage, nil
+}
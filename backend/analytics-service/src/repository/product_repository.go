@@ -35,8 +35,8 @@ func (r *ProductRepository) GetTopProductsByRevenue(ctx context.Context, tenantI
 			COALESCE(SUM(oi.total_price), 0) as revenue,
 			c.name as category_name
 		FROM products p
-		LEFT JOIN order_items oi ON oi.product_id = p.id
-		LEFT JOIN guest_orders od ON od.id = oi.order_id 
+		LEFT JOIN reporting_order_items oi ON oi.product_id = p.id
+		LEFT JOIN reporting_guest_orders od ON od.id = oi.order_id 
 			AND od.tenant_id = $1 
 			AND od.status = 'COMPLETE'
 			AND (od.created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
@@ -62,8 +62,8 @@ func (r *ProductRepository) GetTopProductsByQuantity(ctx context.Context, tenant
 			COALESCE(SUM(oi.total_price), 0) as revenue,
 			c.name as category_name
 		FROM products p
-		LEFT JOIN order_items oi ON oi.product_id = p.id
-		LEFT JOIN guest_orders od ON od.id = oi.order_id 
+		LEFT JOIN reporting_order_items oi ON oi.product_id = p.id
+		LEFT JOIN reporting_guest_orders od ON od.id = oi.order_id 
 			AND od.tenant_id = $1 
 			AND od.status = 'COMPLETE'
 			AND (od.created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
@@ -89,8 +89,8 @@ func (r *ProductRepository) GetBottomProductsByRevenue(ctx context.Context, tena
 			COALESCE(SUM(oi.total_price), 0) as revenue,
 			c.name as category_name
 		FROM products p
-		LEFT JOIN order_items oi ON oi.product_id = p.id
-		LEFT JOIN guest_orders od ON od.id = oi.order_id 
+		LEFT JOIN reporting_order_items oi ON oi.product_id = p.id
+		LEFT JOIN reporting_guest_orders od ON od.id = oi.order_id 
 			AND od.tenant_id = $1 
 			AND od.status = 'COMPLETE'
 			AND (od.created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
@@ -116,8 +116,8 @@ func (r *ProductRepository) GetBottomProductsByQuantity(ctx context.Context, ten
 			COALESCE(SUM(oi.total_price), 0) as revenue,
 			c.name as category_name
 		FROM products p
-		LEFT JOIN order_items oi ON oi.product_id = p.id
-		LEFT JOIN guest_orders od ON od.id = oi.order_id 
+		LEFT JOIN reporting_order_items oi ON oi.product_id = p.id
+		LEFT JOIN reporting_guest_orders od ON od.id = oi.order_id 
 			AND od.tenant_id = $1 
 			AND od.status = 'COMPLETE'
 			AND (od.created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
@@ -37,7 +37,7 @@ func (r *SalesRepository) GetSalesMetrics(ctx context.Context, tenantID string,
 			COALESCE(SUM(total_amount), 0) as total_revenue,
 			COUNT(*) as total_orders,
 			COALESCE(AVG(total_amount), 0) as average_order_value
-		FROM guest_orders
+		FROM reporting_guest_orders
 		WHERE tenant_id = $1 
 			AND status = 'COMPLETE'
 			AND (created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
@@ -112,7 +112,7 @@ func (r *SalesRepository) GetSalesMetrics(ctx context.Context, tenantID string,
 			COALESCE(SUM(total_amount), 0) as offline_revenue,
 			COUNT(CASE WHEN pt.payment_type = 'installment' THEN 1 END) as installment_count,
 			COALESCE(SUM(CASE WHEN pt.payment_type = 'installment' THEN total_amount ELSE 0 END), 0) as installment_revenue
-		FROM guest_orders go
+		FROM reporting_guest_orders go
 		LEFT JOIN payment_terms pt ON pt.order_id = go.id AND pt.tenant_id = go.tenant_id
 		WHERE go.tenant_id = $1 
 			AND go.order_type = 'offline'
@@ -165,7 +165,7 @@ func (r *SalesRepository) GetDailySales(ctx context.Context, tenantID string, st
 			DATE((created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s') as date,
 			COALESCE(SUM(total_amount), 0) as revenue,
 			COUNT(*) as orders
-		FROM guest_orders
+		FROM reporting_guest_orders
 		WHERE tenant_id = $1 
 			AND status = 'COMPLETE'
 			AND (created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
@@ -203,8 +203,8 @@ func (r *SalesRepository) GetCategoryBreakdown(ctx context.Context, tenantID str
 			COUNT(DISTINCT go.id) as order_count
 		FROM categories c
 		LEFT JOIN products p ON p.category_id = c.id AND p.tenant_id = c.tenant_id
-		LEFT JOIN order_items oi ON oi.product_id = p.id
-		LEFT JOIN guest_orders go ON go.id = oi.order_id 
+		LEFT JOIN reporting_order_items oi ON oi.product_id = p.id
+		LEFT JOIN reporting_guest_orders go ON go.id = oi.order_id 
 			AND go.tenant_id = $1 
 			AND go.status = 'COMPLETE'
 			AND (go.created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s' BETWEEN $2 AND $3
@@ -285,7 +285,7 @@ func (r *SalesRepository) GetSalesTrend(ctx context.Context, tenantID string, st
 			COALESCE(SUM(go.total_amount), 0) as revenue,
 			COUNT(go.id) as orders
 		FROM date_series ds
-		LEFT JOIN guest_orders go ON 
+		LEFT JOIN reporting_guest_orders go ON 
 			date_trunc($4, (go.created_at AT TIME ZONE 'UTC') AT TIME ZONE '%s') = ds.date
 			AND go.tenant_id = $1
 			AND go.status = 'COMPLETE'
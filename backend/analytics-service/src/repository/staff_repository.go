@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pos/analytics-service/src/models"
+	"github.com/pos/analytics-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// StaffRepository handles per-staff performance queries
+// Order status changes and stock adjustments come from the audit trail, where order-service and
+// product-service record the staff member who made them; refunds come from refund_approvals.
+type StaffRepository struct {
+	db        *sql.DB
+	encryptor utils.Encryptor
+	timezone  string
+}
+
+// NewStaffRepository creates a new staff repository
+func NewStaffRepository(db *sql.DB, encryptor utils.Encryptor, timezone string) *StaffRepository {
+	return &StaffRepository{
+		db:        db,
+		encryptor: encryptor,
+		timezone:  timezone,
+	}
+}
+
+// GetStaffPerformance returns the metrics of every staff member with activity in the range
+// Handling time runs from payment (or creation, for orders never marked paid) to the staff
+// member's change to COMPLETE.
+func (r *StaffRepository) GetStaffPerformance(ctx context.Context, tenantID string, start, end time.Time) ([]models.StaffPerformance, error) {
+	query := fmt.Sprintf(`
+		WITH status_changes AS (
+			SELECT actor_id AS user_id, resource_id AS order_id, "timestamp" AS changed_at, after_value->>'status' AS status
+			FROM audit_events
+			WHERE tenant_id = $1
+				AND actor_type = 'user'
+				AND actor_id IS NOT NULL
+				AND action = 'UPDATE'
+				AND resource_type = 'guest_order'
+				AND "timestamp" AT TIME ZONE '%[1]s' BETWEEN $2 AND $3
+		),
+		handled_orders AS (
+			SELECT user_id, order_id FROM status_changes
+			UNION
+			SELECT recorded_by_user_id, id::text
+			FROM guest_orders
+			WHERE tenant_id = $1
+				AND order_type = 'offline'
+				AND recorded_by_user_id IS NOT NULL
+				AND (created_at AT TIME ZONE 'UTC') AT TIME ZONE '%[1]s' BETWEEN $2 AND $3
+		),
+		processed AS (
+			SELECT user_id, COUNT(*) AS orders_processed
+			FROM handled_orders
+			GROUP BY user_id
+		),
+		completed AS (
+			SELECT
+				sc.user_id,
+				COUNT(*) AS orders_completed,
+				AVG(EXTRACT(EPOCH FROM (sc.changed_at - (COALESCE(o.paid_at, o.created_at) AT TIME ZONE 'UTC')))) / 60 AS avg_handling_minutes
+			FROM status_changes sc
+			JOIN reporting_guest_orders o ON o.id::text = sc.order_id AND o.tenant_id = $1
+			WHERE sc.status = 'COMPLETE'
+			GROUP BY sc.user_id
+		),
+		refunds AS (
+			SELECT decided_by AS user_id, COUNT(*) AS refunds_issued, COALESCE(SUM(amount), 0) AS refund_amount
+			FROM refund_approvals
+			WHERE tenant_id = $1
+				AND status = 'approved'
+				AND decided_by IS NOT NULL
+				AND (decided_at AT TIME ZONE 'UTC') AT TIME ZONE '%[1]s' BETWEEN $2 AND $3
+			GROUP BY decided_by
+		),
+		adjustments AS (
+			SELECT actor_id AS user_id, COUNT(*) AS stock_adjustments
+			FROM audit_events
+			WHERE tenant_id = $1
+				AND actor_type = 'user'
+				AND actor_id IS NOT NULL
+				AND action = 'UPDATE'
+				AND resource_type = 'product_stock'
+				AND "timestamp" AT TIME ZONE '%[1]s' BETWEEN $2 AND $3
+			GROUP BY actor_id
+		),
+		staff AS (
+			SELECT user_id FROM processed
+			UNION SELECT user_id FROM refunds
+			UNION SELECT user_id FROM adjustments
+		)
+		SELECT
+			u.id,
+			u.first_name,
+			u.last_name,
+			u.role,
+			COALESCE(p.orders_processed, 0),
+			COALESCE(c.orders_completed, 0),
+			c.avg_handling_minutes,
+			COALESCE(rf.refunds_issued, 0),
+			COALESCE(rf.refund_amount, 0),
+			COALESCE(a.stock_adjustments, 0)
+		FROM staff s
+		JOIN users u ON u.id = s.user_id AND u.tenant_id = $1
+		LEFT JOIN processed p ON p.user_id = s.user_id
+		LEFT JOIN completed c ON c.user_id = s.user_id
+		LEFT JOIN refunds rf ON rf.user_id = s.user_id
+		LEFT JOIN adjustments a ON a.user_id = s.user_id
+		ORDER BY COALESCE(p.orders_processed, 0) DESC, u.id
+	`, r.timezone)
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, start, end)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to query staff performance")
+		return nil, err
+	}
+	defer rows.Close()
+
+	staff := []models.StaffPerformance{}
+	var encryptedFirstNames []string
+	var encryptedLastNames []string
+
+	// First pass: collect encrypted names
+	for rows.Next() {
+		var s models.StaffPerformance
+		var firstName, lastName sql.NullString
+		var avgHandling sql.NullFloat64
+
+		if err := rows.Scan(&s.UserID, &firstName, &lastName, &s.Role, &s.OrdersProcessed, &s.OrdersCompleted,
+			&avgHandling, &s.RefundsIssued, &s.RefundAmount, &s.StockAdjustments); err != nil {
+			log.Error().Err(err).Msg("Failed to scan staff performance row")
+			continue
+		}
+		if avgHandling.Valid {
+			s.AvgHandlingMinutes = &avgHandling.Float64
+		}
+
+		staff = append(staff, s)
+		encryptedFirstNames = append(encryptedFirstNames, firstName.String)
+		encryptedLastNames = append(encryptedLastNames, lastName.String)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Batch decrypt staff names, written by user-service
+	firstNames, err := r.encryptor.DecryptBatch(ctx, encryptedFirstNames, "user:first_name")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to decrypt staff first names")
+		firstNames = nil
+	}
+	lastNames, err := r.encryptor.DecryptBatch(ctx, encryptedLastNames, "user:last_name")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to decrypt staff last names")
+		lastNames = nil
+	}
+
+	// Second pass: staff are shown to their own tenant, so names are not masked
+	for i := range staff {
+		var parts []string
+		if i < len(firstNames) && firstNames[i] != "" {
+			parts = append(parts, firstNames[i])
+		}
+		if i < len(lastNames) && lastNames[i] != "" {
+			parts = append(parts, lastNames[i])
+		}
+		staff[i].Name = strings.Join(parts, " ")
+		if staff[i].Name == "" {
+			staff[i].Name = "Unknown"
+		}
+	}
+
+	return staff, nil
+}
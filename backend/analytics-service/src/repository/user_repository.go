@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// UserRepository reads the settings of the users requesting analytics
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// GetTimezone returns the IANA timezone a user picked in their profile, or "" when they kept the default
+func (r *UserRepository) GetTimezone(ctx context.Context, tenantID, userID string) (string, error) {
+	var timezone sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT timezone FROM users WHERE id = $1 AND tenant_id = $2
+	`, userID, tenantID).Scan(&timezone)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return timezone.String, nil
+}
@@ -14,9 +14,15 @@ import (
 
 // AnalyticsService orchestrates analytics operations with caching
 type AnalyticsService struct {
+	db            *sql.DB
+	encryptor     utils.Encryptor
+	timezone      string
+	location      *time.Location // nil buckets dates in the server's timezone
 	salesRepo     *repository.SalesRepository
 	productRepo   *repository.ProductRepository
 	customerRepo  *repository.CustomerRepository
+	staffRepo     *repository.StaffRepository
+	userRepo      *repository.UserRepository
 	cache         *CacheService
 	currentTTL    time.Duration
 	historicalTTL time.Duration
@@ -25,15 +31,63 @@ type AnalyticsService struct {
 // NewAnalyticsService creates a new analytics service
 func NewAnalyticsService(db *sql.DB, redisClient *redis.Client, encryptor utils.Encryptor, currentTTL, historicalTTL time.Duration, timezone string) *AnalyticsService {
 	return &AnalyticsService{
+		db:            db,
+		encryptor:     encryptor,
+		timezone:      timezone,
 		salesRepo:     repository.NewSalesRepository(db, timezone),
 		productRepo:   repository.NewProductRepository(db, timezone),
 		customerRepo:  repository.NewCustomerRepository(db, encryptor, timezone),
+		staffRepo:     repository.NewStaffRepository(db, encryptor, timezone),
+		userRepo:      repository.NewUserRepository(db),
 		cache:         NewCacheService(redisClient),
 		currentTTL:    currentTTL,
 		historicalTTL: historicalTTL,
 	}
 }
 
+// UserTimezone returns the timezone a user picked in their profile, or "" for the server's timezone
+func (s *AnalyticsService) UserTimezone(ctx context.Context, tenantID, userID string) (string, error) {
+	return s.userRepo.GetTimezone(ctx, tenantID, userID)
+}
+
+// InTimezone returns a copy of the service that buckets dates by the days of the given IANA timezone
+// It shares the cache, under keys suffixed with the timezone. An empty or unknown timezone returns s.
+func (s *AnalyticsService) InTimezone(timezone string) *AnalyticsService {
+	if timezone == "" || timezone == s.timezone {
+		return s
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		log.Warn().Str("timezone", timezone).Msg("Ignoring unknown timezone")
+		return s
+	}
+
+	scoped := *s
+	scoped.timezone = timezone
+	scoped.location = loc
+	scoped.salesRepo = repository.NewSalesRepository(s.db, timezone)
+	scoped.productRepo = repository.NewProductRepository(s.db, timezone)
+	scoped.customerRepo = repository.NewCustomerRepository(s.db, s.encryptor, timezone)
+	scoped.staffRepo = repository.NewStaffRepository(s.db, s.encryptor, timezone)
+	return &scoped
+}
+
+// dateRange resolves a time range in the service's timezone
+func (s *AnalyticsService) dateRange(timeRange models.TimeRange) (time.Time, time.Time, error) {
+	if s.location == nil {
+		return timeRange.GetDateRange()
+	}
+	return timeRange.GetDateRangeIn(s.location)
+}
+
+// cacheKey builds the cache key of a metric, keeping results bucketed in other timezones apart
+func (s *AnalyticsService) cacheKey(tenantID, timeRange, metric string) string {
+	if s.location != nil {
+		metric += "_" + s.timezone
+	}
+	return GenerateKeyWithTimeRange(tenantID, timeRange, metric)
+}
+
 // GetSalesOverview returns sales metrics, daily sales, and category breakdown with caching
 func (s *AnalyticsService) GetSalesOverview(ctx context.Context, tenantID string, timeRange models.TimeRange, startDate, endDate *time.Time) (*models.SalesOverviewResponse, error) {
 	// Determine date range
@@ -44,14 +98,14 @@ func (s *AnalyticsService) GetSalesOverview(ctx context.Context, tenantID string
 		start = *startDate
 		end = *endDate
 	} else {
-		start, end, err = timeRange.GetDateRange()
+		start, end, err = s.dateRange(timeRange)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Try to get from cache
-	cacheKey := GenerateKeyWithTimeRange(tenantID, string(timeRange), "sales_overview")
+	cacheKey := s.cacheKey(tenantID, string(timeRange), "sales_overview")
 	var response models.SalesOverviewResponse
 	if err := s.cache.Get(ctx, cacheKey, &response); err == nil {
 		log.Debug().Str("cache_key", cacheKey).Msg("Cache hit for sales overview")
@@ -104,14 +158,14 @@ func (s *AnalyticsService) GetTopProducts(ctx context.Context, tenantID string,
 		start = *startDate
 		end = *endDate
 	} else {
-		start, end, err = timeRange.GetDateRange()
+		start, end, err = s.dateRange(timeRange)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Try to get from cache
-	cacheKey := GenerateKeyWithTimeRange(tenantID, string(timeRange), "top_products")
+	cacheKey := s.cacheKey(tenantID, string(timeRange), "top_products")
 	var response models.TopProductsResponse
 	if err := s.cache.Get(ctx, cacheKey, &response); err == nil {
 		log.Debug().Str("cache_key", cacheKey).Msg("Cache hit for top products")
@@ -195,14 +249,14 @@ func (s *AnalyticsService) GetTopCustomers(ctx context.Context, tenantID string,
 		start = *startDate
 		end = *endDate
 	} else {
-		start, end, err = timeRange.GetDateRange()
+		start, end, err = s.dateRange(timeRange)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Try to get from cache
-	cacheKey := GenerateKeyWithTimeRange(tenantID, string(timeRange), "top_customers")
+	cacheKey := s.cacheKey(tenantID, string(timeRange), "top_customers")
 	var response models.TopCustomersResponse
 	if err := s.cache.Get(ctx, cacheKey, &response); err == nil {
 		log.Debug().Str("cache_key", cacheKey).Msg("Cache hit for top customers")
@@ -254,10 +308,54 @@ func (s *AnalyticsService) GetTopCustomers(ctx context.Context, tenantID string,
 	return &response, nil
 }
 
+// GetStaffPerformance returns per-staff order, refund and stock metrics with caching
+func (s *AnalyticsService) GetStaffPerformance(ctx context.Context, tenantID string, timeRange models.TimeRange, startDate, endDate *time.Time) (*models.StaffPerformanceResponse, error) {
+	// Determine date range
+	var start, end time.Time
+	var err error
+
+	cacheName := "staff_performance"
+	if timeRange == models.TimeRangeCustom && startDate != nil && endDate != nil {
+		start = *startDate
+		end = *endDate
+		cacheName += "_" + start.Format("20060102") + "_" + end.Format("20060102")
+	} else {
+		start, end, err = s.dateRange(timeRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Try to get from cache
+	cacheKey := s.cacheKey(tenantID, string(timeRange), cacheName)
+	var response models.StaffPerformanceResponse
+	if err := s.cache.Get(ctx, cacheKey, &response); err == nil {
+		log.Debug().Str("cache_key", cacheKey).Msg("Cache hit for staff performance")
+		return &response, nil
+	}
+
+	// Cache miss - query database
+	log.Debug().Str("cache_key", cacheKey).Msg("Cache miss for staff performance")
+
+	staff, err := s.staffRepo.GetStaffPerformance(ctx, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	response.Staff = staff
+
+	// Cache the response
+	ttl := timeRange.GetCacheTTL(s.currentTTL, s.historicalTTL)
+	if err := s.cache.Set(ctx, cacheKey, response, ttl); err != nil {
+		log.Warn().Err(err).Msg("Failed to cache staff performance")
+	}
+
+	return &response, nil
+}
+
 // GetSalesTrend returns time series data for sales with caching
 func (s *AnalyticsService) GetSalesTrend(ctx context.Context, tenantID string, startDate, endDate time.Time, granularity string) (*models.SalesTrendResponse, error) {
 	// Generate cache key
-	cacheKey := GenerateKeyWithTimeRange(tenantID, granularity, "sales_trend_"+startDate.Format("20060102")+"_"+endDate.Format("20060102"))
+	cacheKey := s.cacheKey(tenantID, granularity, "sales_trend_"+startDate.Format("20060102")+"_"+endDate.Format("20060102"))
 
 	// Try cache first
 	var cached models.SalesTrendResponse
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/analytics-service/src/models"
+	"github.com/pos/analytics-service/src/repository"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// featureUsageKeyPrefix matches the daily hashes written by the API gateway and order-service
+const featureUsageKeyPrefix = "feature_usage:"
+
+// FeatureUsageRollupJob copies the daily feature usage counters from Redis into Postgres
+// The Redis hashes hold running totals, so each run re-reads today and yesterday (UTC)
+// and overwrites the stored counts.
+type FeatureUsageRollupJob struct {
+	redisClient *redis.Client
+	repo        *repository.FeatureUsageRepository
+	interval    time.Duration
+}
+
+// NewFeatureUsageRollupJob creates a new feature usage rollup job
+func NewFeatureUsageRollupJob(redisClient *redis.Client, repo *repository.FeatureUsageRepository, interval time.Duration) *FeatureUsageRollupJob {
+	return &FeatureUsageRollupJob{
+		redisClient: redisClient,
+		repo:        repo,
+		interval:    interval,
+	}
+}
+
+// Start runs the rollup on every interval until ctx is cancelled
+func (j *FeatureUsageRollupJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", j.interval).Msg("Feature usage rollup job started")
+
+	j.run(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Feature usage rollup job stopped")
+			return
+		case <-ticker.C:
+			j.run(ctx)
+		}
+	}
+}
+
+func (j *FeatureUsageRollupJob) run(ctx context.Context) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if err := j.rollupDay(ctx, day); err != nil {
+			log.Error().Err(err).Str("date", day.Format("2006-01-02")).Msg("Failed to roll up feature usage")
+		}
+	}
+}
+
+func (j *FeatureUsageRollupJob) rollupDay(ctx context.Context, day time.Time) error {
+	fields, err := j.redisClient.HGetAll(ctx, featureUsageKeyPrefix+day.Format("2006-01-02")).Result()
+	if err != nil {
+		return err
+	}
+
+	counts := make([]models.FeatureUsageCount, 0, len(fields))
+	for field, value := range fields {
+		tenantID, feature, ok := strings.Cut(field, ":")
+		if !ok || feature == "" {
+			continue
+		}
+		if _, err := uuid.Parse(tenantID); err != nil {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || count <= 0 {
+			continue
+		}
+		counts = append(counts, models.FeatureUsageCount{
+			Date:     day,
+			TenantID: tenantID,
+			Feature:  feature,
+			Count:    count,
+		})
+	}
+
+	if err := j.repo.UpsertCounts(ctx, counts); err != nil {
+		return err
+	}
+
+	log.Debug().Str("date", day.Format("2006-01-02")).Int("counters", len(counts)).Msg("Feature usage rolled up")
+	return nil
+}
@@ -1,545 +1,48 @@
 package integration
-package integration
-
-import (
-	"context"
-	"testing"
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
-
 
-}	// TODO: Record installment paymentfunc recordPayment(ctx context.Context, orderID string, amount int64) {}	return "offline-order-uuid"	// TODO: Create offline order for specific tenantfunc createOfflineOrderForTenant(ctx context.Context, tenantID string, amount int64) string {}	return "offline-order-uuid"	// TODO: Create offline order with installment planfunc createOfflineOrderWithInstallments(ctx context.Context, totalAmount, downPayment int64, installmentCount int) string {}	return "offline-order-uuid"	// TODO: Create offline order with specific payment methodfunc createOfflineOrderWithMethod(ctx context.Context, method string, amount int64) string {}	return "offline-order-uuid"	// TODO: Create offline order with specific payment typefunc createOfflineOrderWithPayment(ctx context.Context, paymentType string, amount int64) string {}	return "offline-order-uuid"	// TODO: Create offline orderfunc createOfflineOrder(ctx context.Context, amount int64) string {}	return "online-order-uuid"	// TODO: Create online order (not offline)func createOnlineOrder(ctx context.Context, amount int64) string {}	return nil	// TODO: Call analytics API with tenant-specific JWTfunc callAnalyticsAPIWithTenant(ctx context.Context, tenantID string, queryParams string, result interface{}) error {}	return nil	// TODO: Call analytics-service HTTP APIfunc callAnalyticsAPI(ctx context.Context, queryParams string, result interface{}) error {}	OutstandingBalance int64	TotalAmount       int64	Revenue           int64	Count             inttype OrderTypeMetrics struct {}	TotalRevenue  int64	TotalOrders   int	OfflineOrders OrderTypeMetrics	OnlineOrders  OrderTypeMetricstype DashboardSummary struct {}	Revenue    int64	OrderCount int	Date       stringtype DailyMetrics struct {}	Revenue int64	Count   inttype PaymentMethodMetrics struct {}	OutstandingBalance int64	TotalAmount       int64	Revenue           int64	Count             inttype StatusMetrics struct {}	PaymentMethodBreakdown map[string]PaymentMethodMetrics	StatusBreakdown   map[string]StatusMetrics	OutstandingBalance int64	TotalAmount       int64	TotalRevenue      int64	TotalOrders       inttype SalesMetrics struct {// Helper functions (to be implemented)}	// This requires Kafka offset management and analytics service replay capability	// TODO: Implement event replay test	t.Log("✅ Event replay restores accurate metrics")	// assert.Equal(t, beforeMetrics, afterMetrics, "Metrics should match after replay")	// var afterMetrics SalesMetrics	t.Log("Comparing metrics after replay...")	// Step 5: Compare metrics after replay	// replayKafkaEvents("offline_order.created", startOffset, endOffset)	t.Log("Replaying events from Kafka...")	// Step 4: Replay events from Kafka	// truncateAnalyticsTables()	t.Log("Simulating data corruption...")	// Step 3: Simulate data corruption (delete analytics tables)	// var beforeMetrics SalesMetrics	t.Log("Taking snapshot of current metrics...")	// Step 2: Take snapshot of current metrics	// Create 10 offline orders	t.Log("Creating initial order set...")	// Step 1: Create initial orders	t.Log("This test validates analytics service can rebuild metrics from event log")	// or migration	// Scenario: Analytics service reprocesses events after data corruption	t.Log("=== Event Replay Test ===")func TestOfflineOrdersAnalytics_EventReplayScenario_EndToEnd(t *testing.T) {}	t.Log("✅ Dashboard integration successful")	// assert.Equal(t, int64(2250000), dashboard.TotalRevenue) // Only PAID orders	// assert.Equal(t, 15, dashboard.TotalOrders)	// Verify combined totals	// assert.Equal(t, int64(1750000), dashboard.OfflineOrders.OutstandingBalance)	// assert.Equal(t, int64(2500000), dashboard.OfflineOrders.TotalAmount) // All 5 orders	// assert.Equal(t, int64(750000), dashboard.OfflineOrders.Revenue) // 3 PAID only	// assert.Equal(t, 5, dashboard.OfflineOrders.Count)	// Verify offline metrics	// assert.Equal(t, int64(1500000), dashboard.OnlineOrders.Revenue)	// assert.Equal(t, 10, dashboard.OnlineOrders.Count)	// Verify online metrics	// assert.Contains(t, dashboard, "offline_orders")	// assert.Contains(t, dashboard, "online_orders")	// Verify structure includes offline order metrics	// callAnalyticsAPI(ctx, "dashboard", &dashboard)	// var dashboard DashboardSummary	// TODO: Parse response	t.Log("Validating dashboard response...")	// Step 3: Validate dashboard response structure	// GET /api/v1/analytics/dashboard?period=today	// TODO: Call analytics API (same endpoint used by frontend dashboard)	t.Log("Querying dashboard summary...")	// Step 2: Query dashboard summary	time.Sleep(2 * time.Second)	// createOfflineOrderWithPayment(ctx, "installment", 1500000)	// createOfflineOrderWithPayment(ctx, "installment", 1000000)	// createOfflineOrderWithPayment(ctx, "full", 300000)	// createOfflineOrderWithPayment(ctx, "full", 250000)	// createOfflineOrderWithPayment(ctx, "full", 200000)	// 5 offline orders (3 PAID, 2 PENDING)	}		// createOnlineOrder(ctx, 150000)	for i := 0; i < 10; i++ {	// 10 online orders	t.Log("Creating realistic mixed order data...")	// Step 1: Create mixed order data	ctx := context.Background()	t.Log("=== Dashboard Integration Test ===")func TestOfflineOrdersAnalytics_DashboardIntegration_EndToEnd(t *testing.T) {}	t.Log("✅ Tenant isolation enforced in analytics")	// This is enforced by Row-Level Security (RLS) in PostgreSQL	// Tenant A should not see Tenant B's order IDs, amounts, etc.	t.Log("Verifying no data leakage...")	// Step 4: Verify cross-tenant data not leaked	// assert.Equal(t, int64(900000), tenantBMetrics.TotalRevenue)	// assert.Equal(t, 2, tenantBMetrics.TotalOrders)	// Verify Tenant B sees only their orders	// callAnalyticsAPIWithTenant(ctx, "tenant-b", "order_type=offline", &tenantBMetrics)	// var tenantBMetrics SalesMetrics	// TODO: Call analytics API with tenant B JWT	t.Log("Querying metrics for Tenant B...")	// Step 3: Query metrics for Tenant B	// assert.Equal(t, int64(600000), tenantAMetrics.TotalRevenue)	// assert.Equal(t, 3, tenantAMetrics.TotalOrders)	// Verify Tenant A sees only their orders	// callAnalyticsAPIWithTenant(ctx, "tenant-a", "order_type=offline", &tenantAMetrics)	// var tenantAMetrics SalesMetrics	// TODO: Call analytics API with tenant A JWT	t.Log("Querying metrics for Tenant A...")	// Step 2: Query metrics for Tenant A	time.Sleep(2 * time.Second)	// createOfflineOrderForTenant(ctx, "tenant-b", 500000)	// createOfflineOrderForTenant(ctx, "tenant-b", 400000)	// Tenant B: 2 orders	// createOfflineOrderForTenant(ctx, "tenant-a", 300000)	// createOfflineOrderForTenant(ctx, "tenant-a", 200000)	// createOfflineOrderForTenant(ctx, "tenant-a", 100000)	// Tenant A: 3 orders	t.Log("Creating orders for 2 different tenants...")	// Step 1: Create orders for different tenants	ctx := context.Background()	t.Log("=== Analytics Test: Tenant Isolation ===")func TestOfflineOrdersAnalytics_TenantIsolation_EndToEnd(t *testing.T) {}	t.Log("✅ Installment tracking accurate across payment lifecycle")	// assert.Equal(t, 1, metrics.StatusBreakdown["PAID"].Count)	// assert.Equal(t, 0, metrics.StatusBreakdown["PENDING"].Count)	// Verify status changed to PAID in breakdown	// assert.Equal(t, int64(0), metrics.OutstandingBalance)	// assert.Equal(t, int64(1000000), metrics.TotalRevenue)	// callAnalyticsAPI(ctx, "order_type=offline", &metrics)	// Verify fully paid	t.Log("Checking final analytics (fully paid)...")	time.Sleep(2 * time.Second)	// recordPayment(ctx, orderID, 233334)	t.Log("Recording final installment: 233,334...")	// Step 5: Record final installment	// assert.Equal(t, int64(233334), metrics.OutstandingBalance)	// assert.Equal(t, int64(766666), metrics.TotalRevenue)	// Verify after second payment	time.Sleep(2 * time.Second)	// recordPayment(ctx, orderID, 233333)	t.Log("Recording second installment: 233,333...")	// Step 4: Record second installment	// assert.Equal(t, int64(466667), metrics.OutstandingBalance)	// assert.Equal(t, int64(533333), metrics.TotalRevenue) // Down + 1st installment	// Verify after first payment	// callAnalyticsAPI(ctx, "order_type=offline", &metrics)	// TODO: Query updated metrics	t.Log("Checking analytics after first payment...")	time.Sleep(2 * time.Second)	// recordPayment(ctx, orderID, 233333)	t.Log("Recording first installment: 233,333...")	// Step 3: Record first installment	// assert.Equal(t, int64(700000), metrics.OutstandingBalance)	// assert.Equal(t, int64(300000), metrics.TotalRevenue) // Only down payment counted	// assert.Equal(t, int64(1000000), metrics.TotalAmount)	// assert.Equal(t, 1, metrics.TotalOrders)	// Verify initial state	// callAnalyticsAPI(ctx, "order_type=offline", &metrics)	// var metrics SalesMetrics	// TODO: Query metrics	t.Log("Checking initial analytics (down payment only)...")	time.Sleep(2 * time.Second)	// Step 2: Check initial metrics	// orderID := createOfflineOrderWithInstallments(ctx, 1000000, 300000, 3)	// Installments: 3x 233,333	// Down: 300,000	// Total: 1,000,000	t.Log("Creating installment order with 3 payments...")	// Step 1: Create installment order	ctx := context.Background()	t.Log("=== Analytics Test: Installment Tracking ===")func TestOfflineOrdersAnalytics_InstallmentTracking_EndToEnd(t *testing.T) {}	t.Log("✅ Payment method breakdown accurate")	// assert.Equal(t, int64(250000), metrics.PaymentMethodBreakdown["qris"].Revenue)	// assert.Equal(t, 1, metrics.PaymentMethodBreakdown["qris"].Count)	// Verify QRIS	// assert.Equal(t, int64(700000), metrics.PaymentMethodBreakdown["bank_transfer"].Revenue)	// assert.Equal(t, 2, metrics.PaymentMethodBreakdown["bank_transfer"].Count)	// Verify bank_transfer	// assert.Equal(t, int64(450000), metrics.PaymentMethodBreakdown["cash"].Revenue)	// assert.Equal(t, 3, metrics.PaymentMethodBreakdown["cash"].Count)	// Verify cash	// callAnalyticsAPI(ctx, "...", &metrics)	// var metrics SalesMetrics	// TODO: Parse response	t.Log("Validating payment method breakdown...")	// Step 4: Validate payment methods	// GET /api/v1/analytics/sales?order_type=offline&include_payment_method_breakdown=true	// TODO: Call analytics API	t.Log("Querying payment method breakdown...")	// Step 3: Query payment method breakdown	time.Sleep(2 * time.Second)	// Step 2: Wait for processing	// createOfflineOrderWithMethod(ctx, "qris", 250000)	// 1 QRIS payment	// createOfflineOrderWithMethod(ctx, "bank_transfer", 400000)	// createOfflineOrderWithMethod(ctx, "bank_transfer", 300000)	// 2 bank transfers	// createOfflineOrderWithMethod(ctx, "cash", 200000)	// createOfflineOrderWithMethod(ctx, "cash", 150000)	// createOfflineOrderWithMethod(ctx, "cash", 100000)	// 3 cash payments	t.Log("Creating orders with various payment methods...")	// Step 1: Create orders with different payment methods	ctx := context.Background()	t.Log("=== Analytics Test: Payment Method Breakdown ===")func TestOfflineOrdersAnalytics_PaymentMethodBreakdown_EndToEnd(t *testing.T) {}	t.Log("✅ Time series data accurate")	// assert.Equal(t, 7, timeSeriesData[2].OrderCount)	// Verify day 3	// assert.Equal(t, 3, timeSeriesData[1].OrderCount)	// Verify day 2	// assert.Equal(t, 5, timeSeriesData[0].OrderCount)	// Verify day 1	// assert.Len(t, timeSeriesData, 3)	// Verify 3 days of data	// callAnalyticsAPI(ctx, "...", &timeSeriesData)	// var timeSeriesData []DailyMetrics	// TODO: Parse response	t.Log("Validating daily breakdown...")	// Step 4: Validate daily breakdown	// GET /api/v1/analytics/sales?order_type=offline&start_date=3_days_ago&end_date=today&group_by=day	// TODO: Call analytics API	t.Log("Querying daily time series data...")	// Step 3: Query time series data	time.Sleep(2 * time.Second)	// Step 2: Wait for processing	// - Analytics service accepting backdated events	// - Using test database with historical data	// - Mocking time in tests	// This requires either:	// TODO: Create orders with specific created_at timestamps	// Day 3: 7 offline orders	// Day 2: 3 offline orders	// Day 1: 5 offline orders	t.Log("Creating orders across 3 days...")	// Step 1: Create orders across multiple days	ctx := context.Background()	t.Log("=== Analytics Test: Time Series Data ===")func TestOfflineOrdersAnalytics_TimeSeriesData_EndToEnd(t *testing.T) {}	t.Log("✅ Payment status breakdown accurate")	// assert.Equal(t, expectedOutstanding, metrics.StatusBreakdown["PENDING"].OutstandingBalance)	t.Logf("Expected outstanding balance: %d", expectedOutstanding)	expectedOutstanding := int64(4500000 - (300000 + 450000 + 600000)) // Assuming down payments	// Verify outstanding balance	// assert.Equal(t, int64(4500000), metrics.StatusBreakdown["PENDING"].TotalAmount)	// assert.Equal(t, 3, metrics.StatusBreakdown["PENDING"].Count)	// Verify PENDING orders	// assert.Equal(t, int64(700000), metrics.StatusBreakdown["PAID"].Revenue)	// assert.Equal(t, 2, metrics.StatusBreakdown["PAID"].Count)	// Verify PAID orders	// callAnalyticsAPI(ctx, "order_type=offline&include_status_breakdown=true", &metrics)	// var metrics SalesMetrics	// TODO: Parse response	t.Log("Validating payment status breakdown...")	// Step 4: Validate status breakdown	// GET /api/v1/analytics/sales?order_type=offline&include_status_breakdown=true	// TODO: Call analytics API	t.Log("Querying sales metrics with status breakdown...")	// Step 3: Query metrics with status breakdown	time.Sleep(2 * time.Second)	// Step 2: Wait for processing	// createOfflineOrderWithPayment(ctx, "installment", 2000000)	// createOfflineOrderWithPayment(ctx, "installment", 1500000)	// createOfflineOrderWithPayment(ctx, "installment", 1000000)	t.Log("Creating 3 PENDING offline orders (installments)...")	// 3 offline orders with installments (PENDING)	// createOfflineOrderWithPayment(ctx, "full", 400000)	// createOfflineOrderWithPayment(ctx, "full", 300000)	t.Log("Creating 2 PAID offline orders...")	// 2 offline orders with full payment (PAID)	t.Log("Creating orders with different payment statuses...")	// Step 1: Create orders with different payment statuses	ctx := context.Background()	t.Log("=== Analytics Test: Payment Status Breakdown ===")func TestOfflineOrdersAnalytics_PaymentStatusBreakdown_EndToEnd(t *testing.T) {}	t.Log("✅ Order type filtering works correctly")	// assert.Equal(t, expectedTotalRevenue, allMetrics.TotalRevenue)	t.Logf("Expected total revenue: %d", expectedTotalRevenue)	expectedTotalRevenue := expectedOfflineRevenue + expectedOnlineRevenue // 1,230,000	// Verify total revenue	// assert.Equal(t, 8, allMetrics.TotalOrders, "Should have 8 total orders")	// Verify total count	// require.NoError(t, err)	// err = callAnalyticsAPI(ctx, "", &allMetrics)	// var allMetrics SalesMetrics	// TODO: Call without order_type filter	t.Log("Querying combined metrics (all order types)...")	// Step 6: Validate combined metrics (no filter)	t.Logf("Expected online revenue: %d", expectedOnlineRevenue)	expectedOnlineRevenue := int64(100000 + 110000 + 120000 + 130000 + 140000) // 600,000	// assert.Equal(t, 5, onlineMetrics.TotalOrders, "Should have 5 online orders")	// Verify online order count	// require.NoError(t, err)	// err = callAnalyticsAPI(ctx, "order_type=online", &onlineMetrics)	// var onlineMetrics SalesMetrics	// TODO: Call with order_type=online	t.Log("Querying online order metrics...")	// Step 5: Validate online order metrics	// assert.Equal(t, expectedOfflineRevenue, metrics.TotalRevenue)	t.Logf("Expected offline revenue: %d", expectedOfflineRevenue)	expectedOfflineRevenue := int64(200000 + 210000 + 220000) // 630,000	// Verify offline revenue	// assert.Equal(t, 3, metrics.TotalOrders, "Should have 3 offline orders")	// Verify offline order count	// require.NoError(t, err)	// err := callAnalyticsAPI(ctx, "order_type=offline", &metrics)	// var metrics SalesMetrics	// TODO: Parse response	t.Log("Validating offline order metrics...")	// Step 4: Validate offline order metrics	// GET /api/v1/analytics/sales?order_type=offline&start_date=today&end_date=today	// TODO: Call analytics-service API	t.Log("Querying sales metrics...")	// Step 3: Query sales metrics with order_type filter	time.Sleep(2 * time.Second) // Allow time for Kafka consumption and metric updates	t.Log("Waiting for analytics service to process events...")	// Step 2: Wait for analytics processing	}		// createOfflineOrder(ctx, 200000+int64(i*10000))		// TODO: Create offline order via order-service	for i := 0; i < 3; i++ {	t.Log("Creating 3 offline orders...")	// Create 3 offline orders	}		// createOnlineOrder(ctx, 100000+int64(i*10000))		// TODO: Create online order via order-service	for i := 0; i < 5; i++ {	t.Log("Creating 5 online orders...")	// Create 5 online orders	t.Log("Creating test orders...")	// Step 1: Create test data	ctx := context.Background()	t.Log("=== Analytics Test: Order Type Filtering ===")func TestOfflineOrdersAnalytics_OrderTypeFiltering_EndToEnd(t *testing.T) {// Verifies analytics service correctly processes offline order events and provides accurate metrics// T100: Integration test for offline order analytics)	"github.com/stretchr/testify/require"	"github.com/stretchr/testify/assert"	"time"
\ No newline at end of file
+import "testing"
+
+// T100: Integration tests for offline order analytics
+// These scenarios need order-service, Kafka and analytics-service running together, and the
+// helpers that create orders and call the analytics API do not exist yet, so they are skipped.
+const pendingEndToEnd = "end-to-end analytics environment not available yet"
+
+// Offline and online orders are counted separately with the order_type filter, and together without it
+func TestOfflineOrdersAnalytics_OrderTypeFiltering_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
+
+// Offline order revenue counts paid orders only; pending installments are reported as outstanding
+func TestOfflineOrdersAnalytics_PaymentStatusBreakdown_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
+
+// Offline orders captured on different days land in their own daily buckets
+func TestOfflineOrdersAnalytics_TimeSeriesData_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
+
+// Offline revenue is broken down by cash, bank transfer and QRIS
+func TestOfflineOrdersAnalytics_PaymentMethodBreakdown_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
+
+// Installment revenue and outstanding balances follow each recorded payment
+func TestOfflineOrdersAnalytics_InstallmentTracking_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
+
+// A tenant's analytics never include another tenant's offline orders
+func TestOfflineOrdersAnalytics_TenantIsolation_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
+
+// The dashboard endpoint combines online and offline totals
+func TestOfflineOrdersAnalytics_DashboardIntegration_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
+
+// Replaying offline order events rebuilds the same metrics
+func TestOfflineOrdersAnalytics_EventReplayScenario_EndToEnd(t *testing.T) {
+	t.Skip(pendingEndToEnd)
+}
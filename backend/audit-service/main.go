@@ -106,6 +106,19 @@ func main() {
 	go consentConsumer.Start(ctx)
 	log.Info().Str("consent_topic", kafkaConsentTopic).Msg("Consent consumer started")
 
+	// Tenant deletion saga: delete the tenant's consent records when tenant-service asks
+	kafkaTenantEventsTopic := utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC")
+	tenantEventsProducer := queue.NewKafkaProducer([]string{kafkaBrokers}, kafkaTenantEventsTopic)
+	defer tenantEventsProducer.Close()
+	tenantConsumerConfig := queue.KafkaConsumerConfig{
+		Brokers:     kafkaBrokers,
+		Topic:       kafkaTenantEventsTopic,
+		GroupID:     serviceName + "-tenant-consumer",
+		StartOffset: -1, // Latest
+	}
+	tenantConsumer := queue.NewTenantConsumer(tenantConsumerConfig, consentRepo, tenantEventsProducer)
+	go tenantConsumer.Start(ctx)
+
 	// Initialize Echo HTTP server
 	e := echo.New()
 	e.HideBanner = true
@@ -163,6 +176,7 @@ func main() {
 	api.GET("/consent/purposes/:purpose_code", consentHandler.GetConsentPurposeByCode)
 	api.POST("/consent/grant", consentHandler.GrantConsent)
 	api.GET("/consent/status", consentHandler.GetConsentStatus)
+	api.POST("/consent/status/batch", consentHandler.GetConsentStatusBatch)
 	api.POST("/consent/revoke", consentHandler.RevokeConsent)
 	api.GET("/consent/history", consentHandler.GetConsentHistory)
 	api.GET("/privacy-policy", consentHandler.GetPrivacyPolicy)
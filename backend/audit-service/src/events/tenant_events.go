@@ -0,0 +1,61 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantDeletionParticipant is the name tenant-service asks this service by in deletion requests
+const TenantDeletionParticipant = "audit-service"
+
+// TenantEvent is a message on the tenant-events topic
+// tenant-service publishes tenant.deletion.requested; the services it names answer with
+// tenant.deletion.acknowledged once they have deleted their part of the tenant's data.
+type TenantEvent struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	TenantID  string          `json:"tenant_id"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// TenantDeletionRequest is the data of a tenant.deletion.requested event
+type TenantDeletionRequest struct {
+	DeletionID string   `json:"deletion_id"`
+	Services   []string `json:"services"`
+}
+
+// Includes reports whether service is asked to delete its part of the tenant's data
+func (r *TenantDeletionRequest) Includes(service string) bool {
+	for _, s := range r.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTenantDeletionAcknowledgedEvent answers a deletion request with what was deleted, or why it failed
+func NewTenantDeletionAcknowledgedEvent(tenantID, deletionID string, deleted map[string]int64, cause error) *TenantEvent {
+	data := map[string]interface{}{
+		"deletion_id": deletionID,
+		"service":     TenantDeletionParticipant,
+		"status":      "completed",
+		"deleted":     deleted,
+	}
+	if cause != nil {
+		data["status"] = "failed"
+		data["error"] = cause.Error()
+	}
+	raw, _ := json.Marshal(data)
+
+	return &TenantEvent{
+		EventID:   uuid.New().String(),
+		EventType: "tenant.deletion.acknowledged",
+		TenantID:  tenantID,
+		Data:      raw,
+		Timestamp: time.Now(),
+	}
+}
@@ -62,6 +62,9 @@ func (h *QueryHandler) ListAuditEvents(c echo.Context) error {
 	if resourceID := c.QueryParam("resource_id"); resourceID != "" {
 		filter.ResourceID = &resourceID
 	}
+	if requestID := c.QueryParam("request_id"); requestID != "" {
+		filter.RequestID = &requestID
+	}
 
 	// Time range filters
 	if startTimeStr := c.QueryParam("start_time"); startTimeStr != "" {
@@ -265,6 +268,10 @@ func (h *QueryHandler) ListTenantAuditEvents(c echo.Context) error {
 	if actorID := c.QueryParam("actor_id"); actorID != "" {
 		filter.ActorID = &actorID
 	}
+	// Correlation ID, to follow one request across services
+	if requestID := c.QueryParam("request_id"); requestID != "" {
+		filter.RequestID = &requestID
+	}
 
 	// Date range filters
 	if startTimeStr := c.QueryParam("start_time"); startTimeStr != "" {
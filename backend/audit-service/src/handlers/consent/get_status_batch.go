@@ -0,0 +1,108 @@
+package consent
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxConsentStatusBatchSize is the most subjects a single batch status lookup may ask for
+const MaxConsentStatusBatchSize = 1000
+
+// GetConsentStatusBatchRequest represents the request body for a bulk consent status lookup
+type GetConsentStatusBatchRequest struct {
+	SubjectType  string   `json:"subject_type" validate:"required,oneof=tenant guest"`
+	SubjectIDs   []string `json:"subject_ids" validate:"required,min=1,max=1000"`
+	PurposeCodes []string `json:"purpose_codes"` // Defaults to all purposes for the subject type
+}
+
+// GetConsentStatusBatch returns per-purpose consent status for many subjects at once
+// Services use it to gate processing (e.g. marketing sends) without one call per subject.
+// POST /api/v1/consent/status/batch
+func (h *Handler) GetConsentStatusBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "MISSING_TENANT_ID",
+				"message": "Tenant ID is required",
+			},
+		})
+	}
+
+	var req GetConsentStatusBatchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_REQUEST",
+				"message": "Invalid request body",
+			},
+		})
+	}
+
+	if req.SubjectType != "tenant" && req.SubjectType != "guest" {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_SUBJECT_TYPE",
+				"message": "subject_type must be 'tenant' or 'guest'",
+			},
+		})
+	}
+
+	if len(req.SubjectIDs) == 0 || len(req.SubjectIDs) > MaxConsentStatusBatchSize {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INVALID_BATCH_SIZE",
+				"message": "subject_ids must contain between 1 and 1000 IDs",
+			},
+		})
+	}
+
+	// Deduplicate and validate subject IDs; they are UUIDs for both subject types
+	seen := make(map[string]bool, len(req.SubjectIDs))
+	subjectIDs := make([]string, 0, len(req.SubjectIDs))
+	for _, id := range req.SubjectIDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error": map[string]string{
+					"code":    "INVALID_SUBJECT_ID",
+					"message": "Invalid subject ID: " + id,
+				},
+			})
+		}
+		normalized := parsed.String()
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		subjectIDs = append(subjectIDs, normalized)
+	}
+
+	statuses, err := h.consentService.GetConsentStatusBatch(ctx, tenantID, req.SubjectType, subjectIDs, req.PurposeCodes)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("subject_type", req.SubjectType).
+			Int("subject_count", len(subjectIDs)).
+			Msg("Failed to retrieve batch consent status")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"error": map[string]string{
+				"code":    "INTERNAL_ERROR",
+				"message": "Failed to retrieve consent status",
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"subject_type": req.SubjectType,
+			"statuses":     statuses,
+		},
+	})
+}
@@ -0,0 +1,118 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/pos/audit-service/src/events"
+	"github.com/pos/audit-service/src/repository"
+)
+
+// TenantConsumer takes part in the tenant deletion saga run by tenant-service
+// On tenant.deletion.requested naming this service it deletes the tenant's consent records and answers
+// with tenant.deletion.acknowledged. Audit events are never deleted: the trail, with the deletion
+// certificate tenant-service records at the end, outlives the tenant.
+type TenantConsumer struct {
+	reader       *kafka.Reader
+	consentRepo  *repository.ConsentRepository
+	tenantEvents *KafkaProducer
+}
+
+// NewTenantConsumer creates a new Kafka consumer for tenant events
+func NewTenantConsumer(config KafkaConsumerConfig, consentRepo *repository.ConsentRepository, tenantEvents *KafkaProducer) *TenantConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        []string{config.Brokers},
+		Topic:          config.Topic,
+		GroupID:        config.GroupID,
+		StartOffset:    config.StartOffset,
+		MinBytes:       1,
+		MaxBytes:       10e6,
+		MaxWait:        500 * time.Millisecond,
+		CommitInterval: 1 * time.Second,
+	})
+
+	return &TenantConsumer{
+		reader:       reader,
+		consentRepo:  consentRepo,
+		tenantEvents: tenantEvents,
+	}
+}
+
+// Start begins consuming tenant events from Kafka
+func (c *TenantConsumer) Start(ctx context.Context) {
+	log.Info().Str("topic", c.reader.Config().Topic).Msg("Tenant consumer started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Tenant consumer shutting down")
+			if err := c.reader.Close(); err != nil {
+				log.Error().Err(err).Msg("Failed to close Kafka reader")
+			}
+			return
+		default:
+			msg, err := c.reader.FetchMessage(ctx)
+			if err != nil {
+				if err == context.Canceled {
+					return
+				}
+				log.Error().Err(err).Msg("Failed to fetch Kafka message")
+				time.Sleep(1 * time.Second)
+				continue
+			}
+
+			if err := c.processMessage(ctx, msg); err != nil {
+				log.Error().
+					Err(err).
+					Str("partition", fmt.Sprintf("%d", msg.Partition)).
+					Str("offset", fmt.Sprintf("%d", msg.Offset)).
+					Msg("Failed to process tenant event")
+			}
+
+			if err := c.reader.CommitMessages(ctx, msg); err != nil {
+				log.Error().Err(err).Msg("Failed to commit Kafka offset")
+			}
+		}
+	}
+}
+
+// processMessage deletes the tenant's consents for a deletion request naming this service; other
+// tenant events are ignored. Deleting is safe to repeat when tenant-service asks again.
+func (c *TenantConsumer) processMessage(ctx context.Context, msg kafka.Message) error {
+	var event events.TenantEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal tenant event: %w", err)
+	}
+	if event.EventType != "tenant.deletion.requested" {
+		return nil
+	}
+	var req events.TenantDeletionRequest
+	if err := json.Unmarshal(event.Data, &req); err != nil {
+		return fmt.Errorf("failed to unmarshal tenant deletion request: %w", err)
+	}
+	if !req.Includes(events.TenantDeletionParticipant) {
+		return nil
+	}
+	if _, err := uuid.Parse(event.TenantID); err != nil {
+		return fmt.Errorf("tenant deletion request has invalid tenant_id %q", event.TenantID)
+	}
+
+	deleted, err := c.consentRepo.DeleteTenantConsents(ctx, event.TenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", event.TenantID).Msg("Failed to delete tenant consents")
+	} else {
+		log.Info().Str("tenant_id", event.TenantID).Interface("deleted", deleted).Msg("Tenant consents deleted")
+	}
+
+	ack := events.NewTenantDeletionAcknowledgedEvent(event.TenantID, req.DeletionID, deleted, err)
+	if err := c.tenantEvents.Publish(ctx, event.TenantID, ack); err != nil {
+		return fmt.Errorf("failed to acknowledge tenant deletion %s: %w", req.DeletionID, err)
+	}
+	return nil
+}
@@ -121,6 +121,7 @@ type AuditQueryFilter struct {
 	Action       *string
 	ResourceType *string
 	ResourceID   *string
+	RequestID    *string // Correlation ID of the originating HTTP request
 	StartTime    *time.Time
 	EndTime      *time.Time
 	Limit        int
@@ -166,6 +167,11 @@ func (r *AuditRepository) List(ctx context.Context, filter AuditQueryFilter) ([]
 		args = append(args, *filter.ResourceID)
 		argIdx++
 	}
+	if filter.RequestID != nil {
+		query += fmt.Sprintf(" AND request_id = $%d", argIdx)
+		args = append(args, *filter.RequestID)
+		argIdx++
+	}
 	if filter.StartTime != nil {
 		query += fmt.Sprintf(" AND timestamp >= $%d", argIdx)
 		args = append(args, *filter.StartTime)
@@ -261,6 +267,11 @@ func (r *AuditRepository) Count(ctx context.Context, filter AuditQueryFilter) (i
 		args = append(args, *filter.ResourceID)
 		argIdx++
 	}
+	if filter.RequestID != nil {
+		query += fmt.Sprintf(" AND request_id = $%d", argIdx)
+		args = append(args, *filter.RequestID)
+		argIdx++
+	}
 	if filter.StartTime != nil {
 		query += fmt.Sprintf(" AND timestamp >= $%d", argIdx)
 		args = append(args, *filter.StartTime)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/pos/audit-service/src/models"
 	"github.com/pos/audit-service/src/utils"
 )
@@ -461,6 +462,44 @@ func (r *ConsentRepository) GetActiveConsents(ctx context.Context, tenantID, sub
 	return records, nil
 }
 
+// GetActivePurposesForSubjects returns the purposes each subject currently consents to
+// All subjects are resolved in a single query that uses the active-consent indexes;
+// subject IDs must be UUIDs. Subjects without any active consent are absent from the result.
+func (r *ConsentRepository) GetActivePurposesForSubjects(ctx context.Context, tenantID, subjectType string, subjectIDs []string) (map[string][]string, error) {
+	query := `
+		SELECT DISTINCT COALESCE(cr.subject_id::text, cr.guest_order_id::text) as subject_id,
+		       cp.purpose_code
+		FROM consent_records cr
+		JOIN consent_purposes cp ON cr.purpose_id = cp.id
+		WHERE cr.tenant_id = $1
+		  AND cr.subject_type = $2
+		  AND (cr.subject_id = ANY($3::uuid[]) OR cr.guest_order_id = ANY($3::uuid[]))
+		  AND cr.granted = true
+		  AND cr.revoked_at IS NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, subjectType, pq.Array(subjectIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active consents: %w", err)
+	}
+	defer rows.Close()
+
+	purposes := make(map[string][]string)
+	for rows.Next() {
+		var subjectID, purposeCode string
+		if err := rows.Scan(&subjectID, &purposeCode); err != nil {
+			return nil, fmt.Errorf("failed to scan consent purpose: %w", err)
+		}
+		purposes[subjectID] = append(purposes[subjectID], purposeCode)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return purposes, nil
+}
+
 // RevokeConsent marks a consent record as revoked
 func (r *ConsentRepository) RevokeConsent(ctx context.Context, recordID uuid.UUID) error {
 	query := `
@@ -551,6 +590,35 @@ func (r *ConsentRepository) GetConsentHistory(ctx context.Context, tenantID, sub
 	return records, nil
 }
 
+// DeleteTenantConsents deletes a tenant's consent records and processed consent event markers when the
+// tenant is deleted, returning how many of each were deleted. The audit trail itself is kept.
+func (r *ConsentRepository) DeleteTenantConsents(ctx context.Context, tenantID string) (map[string]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted := map[string]int64{}
+	for kind, query := range map[string]string{
+		"consent_records":          `DELETE FROM consent_records WHERE tenant_id = $1`,
+		"processed_consent_events": `DELETE FROM processed_consent_events WHERE tenant_id = $1`,
+	} {
+		result, err := tx.ExecContext(ctx, query, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete tenant %s: %w", kind, err)
+		}
+		if deleted[kind], err = result.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to count deleted %s: %w", kind, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tenant consent deletion: %w", err)
+	}
+	return deleted, nil
+}
+
 // IsEventProcessed checks if a consent event has already been processed (idempotency)
 func (r *ConsentRepository) IsEventProcessed(ctx context.Context, eventID string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM processed_consent_events WHERE event_id = $1)`
@@ -17,6 +17,7 @@ import (
 type ConsentService struct {
 	consentRepo *repository.ConsentRepository
 	producer    *queue.KafkaProducer
+	statusCache *consentStatusCache
 }
 
 // NewConsentService creates a new consent service
@@ -24,6 +25,7 @@ func NewConsentService(consentRepo *repository.ConsentRepository, producer *queu
 	return &ConsentService{
 		consentRepo: consentRepo,
 		producer:    producer,
+		statusCache: newConsentStatusCache(ConsentStatusCacheTTL),
 	}
 }
 
@@ -122,6 +124,8 @@ func (s *ConsentService) GrantConsents(ctx context.Context, req ConsentGrantRequ
 		}
 	}
 
+	s.statusCache.invalidate(req.TenantID, req.SubjectType, req.SubjectID)
+
 	return nil
 }
 
@@ -169,6 +173,7 @@ func (s *ConsentService) RevokeConsent(ctx context.Context, req RevokeConsentReq
 	if err := s.consentRepo.RevokeConsent(ctx, targetRecord.RecordID); err != nil {
 		return fmt.Errorf("failed to revoke consent: %w", err)
 	}
+	s.statusCache.invalidate(req.TenantID, req.SubjectType, req.SubjectID)
 
 	// Publish ConsentRevokedEvent to audit topic for compliance trail
 	event := events.ConsentRevokedEvent{
@@ -215,6 +220,58 @@ func (s *ConsentService) GetConsentStatus(ctx context.Context, tenantID, subject
 	return status, nil
 }
 
+// GetConsentStatusBatch retrieves consent status for many subjects of one type at once
+// Subjects not in the cache are loaded with a single query. The result maps each subject ID
+// to every requested purpose; purposeCodes defaults to all purposes for the subject type.
+func (s *ConsentService) GetConsentStatusBatch(ctx context.Context, tenantID, subjectType string, subjectIDs, purposeCodes []string) (map[string]map[string]bool, error) {
+	if len(purposeCodes) == 0 {
+		purposes, err := s.consentRepo.ListConsentPurposes(ctx, "en", subjectType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list consent purposes: %w", err)
+		}
+		for _, purpose := range purposes {
+			purposeCodes = append(purposeCodes, purpose.PurposeCode)
+		}
+	}
+
+	now := time.Now()
+	active := make(map[string]map[string]bool, len(subjectIDs))
+	var missing []string
+	for _, subjectID := range subjectIDs {
+		if purposes, ok := s.statusCache.get(tenantID, subjectType, subjectID, now); ok {
+			active[subjectID] = purposes
+			continue
+		}
+		missing = append(missing, subjectID)
+	}
+
+	if len(missing) > 0 {
+		loaded, err := s.consentRepo.GetActivePurposesForSubjects(ctx, tenantID, subjectType, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get active consents: %w", err)
+		}
+		for _, subjectID := range missing {
+			purposes := make(map[string]bool, len(loaded[subjectID]))
+			for _, code := range loaded[subjectID] {
+				purposes[code] = true
+			}
+			s.statusCache.set(tenantID, subjectType, subjectID, purposes, now)
+			active[subjectID] = purposes
+		}
+	}
+
+	statuses := make(map[string]map[string]bool, len(subjectIDs))
+	for _, subjectID := range subjectIDs {
+		status := make(map[string]bool, len(purposeCodes))
+		for _, code := range purposeCodes {
+			status[code] = active[subjectID][code]
+		}
+		statuses[subjectID] = status
+	}
+
+	return statuses, nil
+}
+
 // GetConsentHistory retrieves full consent history for a subject
 func (s *ConsentService) GetConsentHistory(ctx context.Context, tenantID, subjectType, subjectID string) ([]*models.ConsentRecord, error) {
 	history, err := s.consentRepo.GetConsentHistory(ctx, tenantID, subjectType, subjectID)
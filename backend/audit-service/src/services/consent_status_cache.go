@@ -0,0 +1,81 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// ConsentStatusCacheTTL bounds how stale a cached consent status can be.
+// Grants and revocations made through this instance invalidate the entry immediately;
+// the TTL covers consents recorded by other instances or the consent consumer.
+const ConsentStatusCacheTTL = 60 * time.Second
+
+// consentStatusCacheSweepSize is the entry count at which expired entries are swept on write
+const consentStatusCacheSweepSize = 50000
+
+// consentStatusCache keeps each subject's active consent purposes in memory
+type consentStatusCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]consentStatusEntry
+}
+
+type consentStatusEntry struct {
+	purposes  map[string]bool
+	expiresAt time.Time
+}
+
+func newConsentStatusCache(ttl time.Duration) *consentStatusCache {
+	return &consentStatusCache{
+		ttl:     ttl,
+		entries: make(map[string]consentStatusEntry),
+	}
+}
+
+func consentStatusCacheKey(tenantID, subjectType, subjectID string) string {
+	return tenantID + ":" + subjectType + ":" + subjectID
+}
+
+// get returns a subject's active purposes if a fresh entry exists
+func (c *consentStatusCache) get(tenantID, subjectType, subjectID string, now time.Time) (map[string]bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[consentStatusCacheKey(tenantID, subjectType, subjectID)]
+	if !ok || now.After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.purposes, true
+}
+
+// set stores a subject's active purposes; subjects with no consent are cached too
+func (c *consentStatusCache) set(tenantID, subjectType, subjectID string, purposes map[string]bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= consentStatusCacheSweepSize {
+		c.evictExpiredLocked(now)
+	}
+	c.entries[consentStatusCacheKey(tenantID, subjectType, subjectID)] = consentStatusEntry{
+		purposes:  purposes,
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// invalidate drops a subject's entry after its consent changed
+func (c *consentStatusCache) invalidate(tenantID, subjectType, subjectID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, consentStatusCacheKey(tenantID, subjectType, subjectID))
+}
+
+// evictExpiredLocked removes stale entries so subjects looked up once do not stay in memory
+// The caller must hold the write lock.
+func (c *consentStatusCache) evictExpiredLocked(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/services"
+)
+
+// AccountLockoutHandler lets owners and managers unlock accounts locked after failed logins
+type AccountLockoutHandler struct {
+	lockout     *services.AccountLockout
+	authService *services.AuthService
+	jwtService  *services.JWTService
+}
+
+func NewAccountLockoutHandler(lockout *services.AccountLockout, authService *services.AuthService, jwtService *services.JWTService) *AccountLockoutHandler {
+	return &AccountLockoutHandler{
+		lockout:     lockout,
+		authService: authService,
+		jwtService:  jwtService,
+	}
+}
+
+// Unlock handles POST /users/:id/unlock
+func (h *AccountLockoutHandler) Unlock(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	if session.Role != "owner" && session.Role != "manager" {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	if err := h.lockout.Unlock(c.Request().Context(), session, c.Param("id"), c.RealIP(), c.Request().UserAgent()); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": getLocalizedMessage(locale, "lockout.userNotFound"),
+			})
+		}
+		c.Logger().Errorf("Failed to unlock account: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": getLocalizedMessage(locale, "lockout.unlocked"),
+	})
+}
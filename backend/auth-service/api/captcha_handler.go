@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/services"
+	"github.com/pos/auth-service/src/utils"
+)
+
+// CaptchaHandler serves the CAPTCHA widget configuration and the per-tenant CAPTCHA policy
+type CaptchaHandler struct {
+	captchaService *services.CaptchaService
+	authService    *services.AuthService
+	jwtService     *services.JWTService
+}
+
+func NewCaptchaHandler(captchaService *services.CaptchaService, authService *services.AuthService, jwtService *services.JWTService) *CaptchaHandler {
+	return &CaptchaHandler{
+		captchaService: captchaService,
+		authService:    authService,
+		jwtService:     jwtService,
+	}
+}
+
+// GetConfig handles GET /captcha/config
+// Public: login and password reset pages use it to decide whether to render the widget.
+func (h *CaptchaHandler) GetConfig(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.captchaService.Config())
+}
+
+// GetSettings handles GET /captcha/settings
+func (h *CaptchaHandler) GetSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	settings, err := h.captchaService.GetSettings(c.Request().Context(), session)
+	if err != nil {
+		c.Logger().Errorf("Failed to get CAPTCHA settings: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /captcha/settings
+// Only the owner may turn CAPTCHA off for the tenant.
+func (h *CaptchaHandler) UpdateSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	if session.Role != "owner" {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	var req models.UpdateCaptchaSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	settings, err := h.captchaService.UpdateSettings(c.Request().Context(), session, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		c.Logger().Errorf("Failed to update CAPTCHA settings: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// captchaError writes the response for a request whose CAPTCHA check did not pass
+func captchaError(c echo.Context, locale string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrCaptchaRequired):
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":           getLocalizedMessage(locale, "captcha.required"),
+			"captchaRequired": true,
+		})
+	case errors.Is(err, utils.ErrCaptchaInvalid):
+		c.Logger().Warnf("CAPTCHA rejected from ip=%s: %v", c.RealIP(), err)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":           getLocalizedMessage(locale, "captcha.invalid"),
+			"captchaRequired": true,
+		})
+	case errors.Is(err, utils.ErrCaptchaUnavailable):
+		c.Logger().Errorf("CAPTCHA verification unavailable: %v", err)
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": getLocalizedMessage(locale, "captcha.unavailable"),
+		})
+	}
+	c.Logger().Errorf("CAPTCHA check failed: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": getLocalizedMessage(locale, "errors.internalServer"),
+	})
+}
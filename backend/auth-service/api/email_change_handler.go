@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/services"
+)
+
+// EmailChangeHandler serves the two-step change of a user's login email
+type EmailChangeHandler struct {
+	emailChangeService *services.EmailChangeService
+	authService        *services.AuthService
+	jwtService         *services.JWTService
+}
+
+func NewEmailChangeHandler(emailChangeService *services.EmailChangeService, authService *services.AuthService, jwtService *services.JWTService) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		emailChangeService: emailChangeService,
+		authService:        authService,
+		jwtService:         jwtService,
+	}
+}
+
+// Request handles POST /email-change
+// The API gateway requires a recent step-up re-authentication, which impersonation sessions cannot get.
+func (h *EmailChangeHandler) Request(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	var req models.EmailChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	change, err := h.emailChangeService.Request(c.Request().Context(), session, req.NewEmail, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return emailChangeError(c, locale, err)
+	}
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"message":   getLocalizedMessage(locale, "emailChange.requested"),
+		"newEmail":  change.NewEmail,
+		"expiresAt": change.ExpiresAt,
+	})
+}
+
+// Confirm handles POST /email-change/confirm
+// The link emailed to the current address opens a frontend page that posts the token here.
+func (h *EmailChangeHandler) Confirm(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	var req models.EmailChangeTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	change, err := h.emailChangeService.Confirm(c.Request().Context(), req.Token, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return emailChangeError(c, locale, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":   getLocalizedMessage(locale, "emailChange.confirmed"),
+		"newEmail":  change.NewEmail,
+		"expiresAt": change.ExpiresAt,
+	})
+}
+
+// Verify handles POST /email-change/verify
+// The user is logged out everywhere, including in this browser.
+func (h *EmailChangeHandler) Verify(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	var req models.EmailChangeTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	change, err := h.emailChangeService.Verify(c.Request().Context(), req.Token, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return emailChangeError(c, locale, err)
+	}
+
+	clearAuthCookie(c)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":  getLocalizedMessage(locale, "emailChange.completed"),
+		"newEmail": change.NewEmail,
+	})
+}
+
+// emailChangeError writes the response for an email change step that did not succeed
+func emailChangeError(c echo.Context, locale string, err error) error {
+	switch {
+	case errors.Is(err, services.ErrEmailChangeInvalid):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "emailChange.invalid"),
+		})
+	case errors.Is(err, services.ErrEmailChangeUnchanged):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "emailChange.unchanged"),
+		})
+	case errors.Is(err, services.ErrEmailChangeInUse):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": getLocalizedMessage(locale, "emailChange.inUse"),
+		})
+	case errors.Is(err, services.ErrEmailChangeTooMany):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{
+			"error": getLocalizedMessage(locale, "emailChange.tooMany"),
+		})
+	case errors.Is(err, services.ErrEmailChangeLink):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "emailChange.linkInvalid"),
+		})
+	case errors.Is(err, services.ErrSessionNotFound):
+		clearAuthCookie(c)
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.expired"),
+		})
+	}
+
+	c.Logger().Errorf("Email change failed: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": getLocalizedMessage(locale, "errors.internalServer"),
+	})
+}
@@ -1,21 +1,92 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"net/http"
+	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/labstack/echo/v4"
 	"github.com/pos/auth-service/src/utils"
+	"github.com/segmentio/kafka-go"
 )
 
-func HealthCheck(c echo.Context) error {
+// readyCheckTimeout bounds each dependency check so a hung dependency can't stall the probe
+const readyCheckTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	db           *sql.DB
+	redisClient  *redis.Client
+	kafkaBrokers []string
+}
+
+func NewHealthHandler(db *sql.DB, redisClient *redis.Client, kafkaBrokers []string) *HealthHandler {
+	return &HealthHandler{
+		db:           db,
+		redisClient:  redisClient,
+		kafkaBrokers: kafkaBrokers,
+	}
+}
+
+// HealthCheck reports the process is alive
+// GET /health
+func (h *HealthHandler) HealthCheck(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{
 		"status":  "ok",
 		"service": utils.GetEnv("SERVICE_NAME"),
 	})
 }
 
-func ReadyCheck(c echo.Context) error {
-	return c.JSON(http.StatusOK, map[string]string{
+// ReadyCheck reports whether the service can take traffic
+// Verifies the database, Redis and at least one Kafka broker are reachable
+// GET /ready
+func (h *HealthHandler) ReadyCheck(c echo.Context) error {
+	ctx := c.Request().Context()
+	checks := map[string]string{
+		"database": checkDependency(ctx, h.db.PingContext),
+		"redis": checkDependency(ctx, func(ctx context.Context) error {
+			return h.redisClient.Ping(ctx).Err()
+		}),
+		"kafka": checkDependency(ctx, h.pingKafka),
+	}
+
+	for name, status := range checks {
+		if status != "ok" {
+			c.Logger().Errorf("Readiness check failed: %s not reachable", name)
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"status": "not_ready",
+				"checks": checks,
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
 		"status": "ready",
+		"checks": checks,
 	})
 }
+
+// pingKafka succeeds when any configured broker accepts a connection
+func (h *HealthHandler) pingKafka(ctx context.Context) error {
+	var lastErr error
+	for _, broker := range h.kafkaBrokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn.Close()
+		return nil
+	}
+	return lastErr
+}
+
+func checkDependency(ctx context.Context, ping func(context.Context) error) string {
+	ctx, cancel := context.WithTimeout(ctx, readyCheckTimeout)
+	defer cancel()
+	if err := ping(ctx); err != nil {
+		return "unreachable"
+	}
+	return "ok"
+}
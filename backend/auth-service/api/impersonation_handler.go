@@ -0,0 +1,120 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/services"
+	"github.com/rs/zerolog/log"
+)
+
+// ImpersonationHandler lets platform operators act as a tenant user for support
+type ImpersonationHandler struct {
+	impersonationService *services.ImpersonationService
+	jwtService           *services.JWTService
+}
+
+func NewImpersonationHandler(impersonationService *services.ImpersonationService, jwtService *services.JWTService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+		jwtService:           jwtService,
+	}
+}
+
+// Start handles POST /impersonation
+// The operator authenticates with their platform API key as a Bearer token; the
+// impersonation token is set as the auth cookie like a regular login.
+func (h *ImpersonationHandler) Start(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	ctx := c.Request().Context()
+
+	apiKey := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	operator, err := h.impersonationService.AuthenticateOperator(ctx, apiKey)
+	if err != nil {
+		if errors.Is(err, services.ErrOperatorUnauthorized) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "impersonation.unauthorized"),
+			})
+		}
+		log.Error().Msgf("Failed to authenticate platform operator: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	var req models.StartImpersonationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	response, token, err := h.impersonationService.Start(ctx, operator, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImpersonationReason):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "impersonation.reasonRequired"),
+			})
+		case errors.Is(err, services.ErrImpersonationDuration):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "impersonation.invalidDuration"),
+			})
+		case errors.Is(err, services.ErrImpersonationTarget):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": getLocalizedMessage(locale, "impersonation.userNotFound"),
+			})
+		}
+		log.Error().Msgf("Failed to start impersonation: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	setAuthCookie(c, token)
+	return c.JSON(http.StatusOK, response)
+}
+
+// Stop handles POST /impersonation/stop
+func (h *ImpersonationHandler) Stop(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	cookie, err := c.Cookie("auth_token")
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.notFound"),
+		})
+	}
+	claims, err := h.jwtService.Validate(cookie.Value)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.invalid"),
+		})
+	}
+
+	if err := h.impersonationService.Stop(c.Request().Context(), claims.SessionID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrSessionNotFound):
+			clearAuthCookie(c)
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.session.expired"),
+			})
+		case errors.Is(err, services.ErrNotImpersonating):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "impersonation.notActive"),
+			})
+		}
+		log.Error().Msgf("Failed to stop impersonation: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	clearAuthCookie(c)
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": getLocalizedMessage(locale, "impersonation.stopped"),
+	})
+}
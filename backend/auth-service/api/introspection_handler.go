@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/services"
+)
+
+// IntrospectionHandler serves RFC 7662 token introspection to backend services
+// It is not routed through the API gateway; callers reach auth-service on the internal network.
+type IntrospectionHandler struct {
+	introspectionService *services.IntrospectionService
+}
+
+func NewIntrospectionHandler(introspectionService *services.IntrospectionService) *IntrospectionHandler {
+	return &IntrospectionHandler{introspectionService: introspectionService}
+}
+
+// Introspect handles POST /oauth/introspect
+// Clients authenticate with HTTP Basic client credentials and send the token as a form field.
+func (h *IntrospectionHandler) Introspect(c echo.Context) error {
+	clientID, secret, ok := c.Request().BasicAuth()
+	if !ok || !h.introspectionService.AuthenticateClient(clientID, secret) {
+		c.Response().Header().Set("WWW-Authenticate", `Basic realm="introspection"`)
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "invalid_client",
+		})
+	}
+
+	token := c.FormValue("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid_request",
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, h.introspectionService.Introspect(c.Request().Context(), clientID, token))
+}
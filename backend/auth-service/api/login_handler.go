@@ -1,7 +1,12 @@
 package api
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -11,12 +16,16 @@ import (
 )
 
 type LoginHandler struct {
-	authService *services.AuthService
+	authService      *services.AuthService
+	captchaService   *services.CaptchaService
+	magicLinkService *services.MagicLinkService
 }
 
-func NewLoginHandler(authService *services.AuthService) *LoginHandler {
+func NewLoginHandler(authService *services.AuthService, captchaService *services.CaptchaService, magicLinkService *services.MagicLinkService) *LoginHandler {
 	return &LoginHandler{
-		authService: authService,
+		authService:      authService,
+		captchaService:   captchaService,
+		magicLinkService: magicLinkService,
 	}
 }
 
@@ -47,9 +56,35 @@ func (h *LoginHandler) Login(c echo.Context) error {
 	c.Logger().Infof("Login attempt: email=%s, ip=%s",
 		maskEmail(req.Email), ipAddress)
 
+	if err := h.captchaService.VerifyForEmail(c.Request().Context(), req.Email, req.CaptchaToken, ipAddress); err != nil {
+		return captchaError(c, locale, err)
+	}
+
 	// Attempt login
-	response, token, err := h.authService.Login(c.Request().Context(), &req, ipAddress, userAgent)
+	response, token, err := h.authService.Login(loginContext(c), &req, ipAddress, userAgent)
 	if err != nil {
+		if verifyErr, ok := err.(*services.LoginVerificationRequiredError); ok {
+			c.Logger().Warnf("Login from new device held for verification: email=%s, ip=%s",
+				maskEmail(req.Email), ipAddress)
+			return loginVerificationRequired(c, locale, h.magicLinkService, verifyErr)
+		}
+
+		if changeErr, ok := err.(*services.PasswordChangeRequiredError); ok {
+			c.Logger().Infof("Login held for password change (%s): email=%s",
+				changeErr.Reason, maskEmail(req.Email))
+			messageKey := "auth.password.changeRequired"
+			if changeErr.Reason == models.PasswordChangeExpired {
+				messageKey = "auth.password.expired"
+			}
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"error":       getLocalizedMessage(locale, messageKey),
+				"code":        "PASSWORD_CHANGE_REQUIRED",
+				"reason":      changeErr.Reason,
+				"changeToken": changeErr.ChangeToken,
+				"expiresAt":   changeErr.ExpiresAt,
+			})
+		}
+
 		// Handle specific errors
 		if rateLimitErr, ok := err.(*services.RateLimitError); ok {
 			c.Logger().Warnf("Rate limit exceeded for email=%s",
@@ -64,6 +99,23 @@ func (h *LoginHandler) Login(c echo.Context) error {
 			})
 		}
 
+		if lockedErr, ok := err.(*services.AccountLockedError); ok {
+			c.Logger().Warnf("Login attempt for locked account: email=%s",
+				maskEmail(req.Email))
+
+			retryAfterSeconds := int(lockedErr.RetryAfter().Seconds()) + 1
+			c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+
+			return c.JSON(http.StatusLocked, map[string]interface{}{
+				"error":       getLocalizedMessage(locale, "auth.login.accountLocked"),
+				"retryAfter":  retryAfterSeconds,
+				"lockedUntil": lockedErr.LockedUntil,
+			})
+		}
+
+		if limitErr, ok := err.(*services.SessionLimitError); ok {
+			return sessionLimitReached(c, locale, limitErr)
+		}
 		if statusErr, ok := err.(*services.UserStatusError); ok {
 			c.Logger().Warnf("Login attempt for %s account: email=%s",
 				statusErr.Status, maskEmail(req.Email))
@@ -80,6 +132,12 @@ func (h *LoginHandler) Login(c echo.Context) error {
 			})
 		}
 
+		if err == services.ErrSSORequired {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.login.ssoRequired"),
+			})
+		}
+
 		// Generic error
 		c.Logger().Errorf("Login failed for email=%s: %v", maskEmail(req.Email), err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -87,8 +145,20 @@ func (h *LoginHandler) Login(c echo.Context) error {
 		})
 	}
 
-	// Set JWT token in HTTP-only cookie
-	// Use Secure flag only in production (HTTPS)
+	setAuthCookie(c, token)
+
+	// Log successful login
+	c.Logger().Infof("Login successful: user=%s, tenant=%s, ip=%s",
+		response.User.ID, response.User.TenantID, ipAddress)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Helper functions
+
+// setAuthCookie sets the JWT token in an HTTP-only cookie
+// The Secure flag is only set in production (HTTPS).
+func setAuthCookie(c echo.Context, token string) {
 	isProduction := c.Request().Header.Get("X-Forwarded-Proto") == "https"
 	cookie := &http.Cookie{
 		Name:     "auth_token",
@@ -100,15 +170,52 @@ func (h *LoginHandler) Login(c echo.Context) error {
 		MaxAge:   utils.GetEnvInt("SESSION_TTL_MINUTES") * 60,
 	}
 	c.SetCookie(cookie)
+}
 
-	// Log successful login
-	c.Logger().Infof("Login successful: user=%s, tenant=%s, ip=%s",
-		response.User.ID, response.User.TenantID, ipAddress)
-
-	return c.JSON(http.StatusOK, response)
+// loginVerificationRequired emails the link confirming a login held back as unusual and says so
+func loginVerificationRequired(c echo.Context, locale string, magicLinkService *services.MagicLinkService, verifyErr *services.LoginVerificationRequiredError) error {
+	if err := magicLinkService.SendVerificationLink(c.Request().Context(), verifyErr.TenantID, verifyErr.UserID); err != nil {
+		c.Logger().Errorf("Failed to send login verification link: %v", err)
+	}
+	return c.JSON(http.StatusForbidden, map[string]interface{}{
+		"error":                getLocalizedMessage(locale, "auth.login.newDevice"),
+		"verificationRequired": true,
+	})
 }
 
-// Helper functions
+// deviceCookieMaxAge keeps the device cookie long enough to recognise returning devices
+const deviceCookieMaxAge = 400 * 24 * 60 * 60
+
+// loginContext returns the request context carrying the device a login comes from
+// A browser without a device cookie gets a new one here, so its first login counts as a new device.
+// The country comes from the header the edge proxy sets (LOGIN_COUNTRY_HEADER, e.g. CF-IPCountry).
+func loginContext(c echo.Context) context.Context {
+	device := services.LoginDevice{}
+	if cookie, err := c.Cookie("device_id"); err == nil && len(cookie.Value) == 32 {
+		device.ID = cookie.Value
+	} else {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err == nil {
+			device.ID = hex.EncodeToString(buf)
+		}
+	}
+	if header := os.Getenv("LOGIN_COUNTRY_HEADER"); header != "" {
+		device.Country = c.Request().Header.Get(header)
+	}
+
+	if device.ID != "" {
+		c.SetCookie(&http.Cookie{
+			Name:     "device_id",
+			Value:    device.ID,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   c.Request().Header.Get("X-Forwarded-Proto") == "https",
+			SameSite: http.SameSiteLaxMode,
+			MaxAge:   deviceCookieMaxAge,
+		})
+	}
+	return services.WithLoginDevice(c.Request().Context(), device)
+}
 
 func getLocaleFromHeader(acceptLanguage string) string {
 	if acceptLanguage == "" {
@@ -129,30 +236,126 @@ func getLocaleFromHeader(acceptLanguage string) string {
 func getLocalizedMessage(locale, key string) string {
 	messages := map[string]map[string]string{
 		"en": {
-			"validation.invalidRequest":    "Invalid request format",
-			"validation.requiredFields":    "Email and password are required",
-			"auth.login.failed":            "Invalid email or password",
-			"auth.login.rateLimitExceeded": "Too many login attempts. Please try again later.",
-			"auth.login.accountDisabled":   "Account is disabled. Please contact support.",
-			"auth.logout.success":          "Successfully logged out",
-			"auth.session.notFound":        "Session not found",
-			"auth.session.invalid":         "Invalid session",
-			"auth.session.expired":         "Session expired",
-			"errors.internalServer":        "An error occurred. Please try again later.",
-			"verification.success":         "Account verified successfully.",
+			"validation.invalidRequest":     "Invalid request format",
+			"validation.requiredFields":     "Email and password are required",
+			"validation.passwordPolicy":     "Password does not meet the password requirements",
+			"auth.login.failed":             "Invalid email or password",
+			"auth.login.rateLimitExceeded":  "Too many login attempts. Please try again later.",
+			"auth.login.accountDisabled":    "Account is disabled. Please contact support.",
+			"auth.logout.success":           "Successfully logged out",
+			"auth.session.notFound":         "Session not found",
+			"auth.session.invalid":          "Invalid session",
+			"auth.session.expired":          "Session expired",
+			"errors.internalServer":         "An error occurred. Please try again later.",
+			"verification.success":          "Account verified successfully.",
+			"passkey.loginFailed":           "Passkey could not be verified. Please try again or log in with your password.",
+			"passkey.verificationFailed":    "Passkey could not be verified. Please try again.",
+			"passkey.challengeExpired":      "Passkey request expired. Please try again.",
+			"passkey.notFound":              "Passkey not found",
+			"passkey.exists":                "This passkey is already registered",
+			"passkey.invalidName":           "Passkey name must be at most 100 characters",
+			"auth.login.ssoRequired":        "Your business requires signing in with Google.",
+			"auth.forbidden":                "You do not have permission to perform this action",
+			"sso.notConfigured":             "Google sign-in is not available on this server",
+			"sso.invalidPolicy":             "Google sign-in must be enabled to require it for all logins",
+			"auth.login.accountLocked":      "Account is temporarily locked after too many failed logins. Please try again later or ask your manager to unlock it.",
+			"lockout.userNotFound":          "User not found",
+			"lockout.unlocked":              "Account unlocked",
+			"impersonation.unauthorized":    "Invalid platform operator key",
+			"impersonation.reasonRequired":  "A reason of at most 500 characters is required to impersonate a user",
+			"impersonation.invalidDuration": "Impersonation duration exceeds the allowed maximum",
+			"impersonation.userNotFound":    "Active user not found in this tenant",
+			"impersonation.notActive":       "This session is not an impersonation",
+			"impersonation.stopped":         "Impersonation ended",
+			"magicLink.sent":                "If an account exists for this email, a login link has been sent",
+			"magicLink.invalid":             "This login link is invalid or has expired. Please request a new one.",
+			"auth.login.newDevice":          "This login is from a new device or location. We've emailed you a link to confirm it's you.",
+			"auth.password.changeRequired":  "You must set a new password before you can continue",
+			"auth.password.expired":         "Your password has expired. Please set a new one.",
+			"passwordRotation.invalidToken": "Your password change session has expired. Please log in again.",
+			"passwordRotation.invalidAge":   "Maximum password age must be between 0 and 3650 days",
+			"captcha.required":              "Please complete the CAPTCHA challenge",
+			"captcha.invalid":               "CAPTCHA verification failed. Please try again.",
+			"captcha.unavailable":           "CAPTCHA verification is temporarily unavailable. Please try again later.",
+			"stepUp.failed":                 "We couldn't confirm it's you. Please try again.",
+			"stepUp.impersonation":          "Sensitive operations are not available while impersonating a user",
+			"pin.invalid":                   "Incorrect PIN. Please try again.",
+			"pin.locked":                    "Too many incorrect PINs. Please try again later or sign in with your password.",
+			"pin.userLocked":                "Your PIN is locked after too many incorrect entries. Ask an owner or manager to reset it.",
+			"pin.terminalRequired":          "This device is not set up for PIN sign-in. Ask a manager to enable it.",
+			"pin.enrollNotAllowed":          "Sign in with your own account to set up this device for PIN sign-in",
+			"pin.notPinSession":             "You are not signed in with a PIN",
+			"auth.login.sessionLimit":       "You are already signed in on the maximum number of devices. Sign out on another device first.",
+			"sessionLimits.invalid":         "Session limits must be between 0 and 20, and the limit action must be reject or evict_oldest",
+			"emailChange.invalid":           "Enter a valid email address",
+			"emailChange.unchanged":         "This is already your email address",
+			"emailChange.inUse":             "This email address is already used by another account",
+			"emailChange.tooMany":           "Too many email change requests. Please try again later.",
+			"emailChange.linkInvalid":       "This link is invalid or has expired. Please request the email change again.",
+			"emailChange.requested":         "We sent a confirmation link to your current email address",
+			"emailChange.confirmed":         "Change confirmed. We sent a verification link to your new email address.",
+			"emailChange.completed":         "Your email address has been changed. Please log in with your new email.",
 		},
 		"id": {
-			"validation.invalidRequest":    "Format permintaan tidak valid",
-			"validation.requiredFields":    "Email dan kata sandi wajib diisi",
-			"auth.login.failed":            "Email atau kata sandi tidak valid",
-			"auth.login.rateLimitExceeded": "Terlalu banyak percobaan login. Silakan coba lagi nanti.",
-			"auth.login.accountDisabled":   "Akun dinonaktifkan. Silakan hubungi dukungan.",
-			"auth.logout.success":          "Berhasil keluar",
-			"auth.session.notFound":        "Sesi tidak ditemukan",
-			"auth.session.invalid":         "Sesi tidak valid",
-			"auth.session.expired":         "Sesi kedaluwarsa",
-			"errors.internalServer":        "Terjadi kesalahan. Silakan coba lagi nanti.",
-			"verification.success":         "Akun berhasil diverifikasi.",
+			"validation.invalidRequest":     "Format permintaan tidak valid",
+			"validation.requiredFields":     "Email dan kata sandi wajib diisi",
+			"validation.passwordPolicy":     "Kata sandi tidak memenuhi persyaratan kata sandi",
+			"auth.login.failed":             "Email atau kata sandi tidak valid",
+			"auth.login.rateLimitExceeded":  "Terlalu banyak percobaan login. Silakan coba lagi nanti.",
+			"auth.login.accountDisabled":    "Akun dinonaktifkan. Silakan hubungi dukungan.",
+			"auth.logout.success":           "Berhasil keluar",
+			"auth.session.notFound":         "Sesi tidak ditemukan",
+			"auth.session.invalid":          "Sesi tidak valid",
+			"auth.session.expired":          "Sesi kedaluwarsa",
+			"errors.internalServer":         "Terjadi kesalahan. Silakan coba lagi nanti.",
+			"verification.success":          "Akun berhasil diverifikasi.",
+			"passkey.loginFailed":           "Passkey tidak dapat diverifikasi. Silakan coba lagi atau masuk dengan kata sandi.",
+			"passkey.verificationFailed":    "Passkey tidak dapat diverifikasi. Silakan coba lagi.",
+			"passkey.challengeExpired":      "Permintaan passkey kedaluwarsa. Silakan coba lagi.",
+			"passkey.notFound":              "Passkey tidak ditemukan",
+			"passkey.exists":                "Passkey ini sudah terdaftar",
+			"passkey.invalidName":           "Nama passkey maksimal 100 karakter",
+			"auth.login.ssoRequired":        "Bisnis Anda mewajibkan masuk dengan Google.",
+			"auth.forbidden":                "Anda tidak memiliki izin untuk melakukan tindakan ini",
+			"sso.notConfigured":             "Masuk dengan Google tidak tersedia di server ini",
+			"sso.invalidPolicy":             "Masuk dengan Google harus diaktifkan agar dapat diwajibkan untuk semua login",
+			"auth.login.accountLocked":      "Akun dikunci sementara karena terlalu banyak percobaan login gagal. Silakan coba lagi nanti atau minta manajer Anda membukanya.",
+			"lockout.userNotFound":          "Pengguna tidak ditemukan",
+			"lockout.unlocked":              "Akun berhasil dibuka",
+			"impersonation.unauthorized":    "Kunci operator platform tidak valid",
+			"impersonation.reasonRequired":  "Alasan maksimal 500 karakter wajib diisi untuk meniru pengguna",
+			"impersonation.invalidDuration": "Durasi peniruan melebihi batas maksimum",
+			"impersonation.userNotFound":    "Pengguna aktif tidak ditemukan di tenant ini",
+			"impersonation.notActive":       "Sesi ini bukan sesi peniruan",
+			"impersonation.stopped":         "Peniruan diakhiri",
+			"magicLink.sent":                "Jika akun untuk email ini ada, tautan masuk telah dikirim",
+			"magicLink.invalid":             "Tautan masuk ini tidak valid atau sudah kedaluwarsa. Silakan minta tautan baru.",
+			"auth.login.newDevice":          "Login ini berasal dari perangkat atau lokasi baru. Kami telah mengirim tautan ke email Anda untuk mengonfirmasi bahwa ini Anda.",
+			"auth.password.changeRequired":  "Anda harus mengatur kata sandi baru sebelum melanjutkan",
+			"auth.password.expired":         "Kata sandi Anda telah kedaluwarsa. Silakan atur kata sandi baru.",
+			"passwordRotation.invalidToken": "Sesi penggantian kata sandi Anda telah berakhir. Silakan masuk kembali.",
+			"passwordRotation.invalidAge":   "Usia maksimum kata sandi harus antara 0 dan 3650 hari",
+			"captcha.required":              "Silakan selesaikan tantangan CAPTCHA",
+			"captcha.invalid":               "Verifikasi CAPTCHA gagal. Silakan coba lagi.",
+			"captcha.unavailable":           "Verifikasi CAPTCHA sedang tidak tersedia. Silakan coba lagi nanti.",
+			"stepUp.failed":                 "Kami tidak dapat memastikan bahwa ini Anda. Silakan coba lagi.",
+			"stepUp.impersonation":          "Operasi sensitif tidak tersedia saat meniru pengguna",
+			"pin.invalid":                   "PIN salah. Silakan coba lagi.",
+			"pin.locked":                    "Terlalu banyak PIN salah. Silakan coba lagi nanti atau masuk dengan kata sandi Anda.",
+			"pin.userLocked":                "PIN Anda terkunci karena terlalu banyak PIN salah. Minta pemilik atau manajer untuk meresetnya.",
+			"pin.terminalRequired":          "Perangkat ini belum diatur untuk masuk dengan PIN. Minta manajer untuk mengaktifkannya.",
+			"pin.enrollNotAllowed":          "Masuk dengan akun Anda sendiri untuk mengatur perangkat ini agar bisa masuk dengan PIN",
+			"pin.notPinSession":             "Anda tidak masuk dengan PIN",
+			"auth.login.sessionLimit":       "Anda sudah masuk di jumlah perangkat maksimum. Keluar dari perangkat lain terlebih dahulu.",
+			"sessionLimits.invalid":         "Batas sesi harus antara 0 dan 20, dan tindakan batas harus reject atau evict_oldest",
+			"emailChange.invalid":           "Masukkan alamat email yang valid",
+			"emailChange.unchanged":         "Ini sudah alamat email Anda",
+			"emailChange.inUse":             "Alamat email ini sudah digunakan oleh akun lain",
+			"emailChange.tooMany":           "Terlalu banyak permintaan perubahan email. Silakan coba lagi nanti.",
+			"emailChange.linkInvalid":       "Tautan ini tidak valid atau sudah kedaluwarsa. Silakan minta perubahan email lagi.",
+			"emailChange.requested":         "Kami telah mengirim tautan konfirmasi ke alamat email Anda saat ini",
+			"emailChange.confirmed":         "Perubahan dikonfirmasi. Kami telah mengirim tautan verifikasi ke alamat email baru Anda.",
+			"emailChange.completed":         "Alamat email Anda telah diubah. Silakan masuk dengan email baru Anda.",
 		},
 	}
 
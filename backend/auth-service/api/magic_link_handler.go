@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/services"
+)
+
+// MagicLinkHandler serves passwordless login through links sent by email
+type MagicLinkHandler struct {
+	magicLinkService *services.MagicLinkService
+	captchaService   *services.CaptchaService
+}
+
+func NewMagicLinkHandler(magicLinkService *services.MagicLinkService, captchaService *services.CaptchaService) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		magicLinkService: magicLinkService,
+		captchaService:   captchaService,
+	}
+}
+
+type MagicLinkRequest struct {
+	Email        string `json:"email"`
+	CaptchaToken string `json:"captchaToken,omitempty"`
+}
+
+type MagicLinkConsumeRequest struct {
+	Token string `json:"token"`
+}
+
+// Request handles POST /magic-link/request
+// The answer is the same whether or not the email has an account.
+func (h *MagicLinkHandler) Request(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	var req MagicLinkRequest
+	if err := c.Bind(&req); err != nil || strings.TrimSpace(req.Email) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	if err := h.captchaService.VerifyForEmail(c.Request().Context(), req.Email, req.CaptchaToken, c.RealIP()); err != nil {
+		return captchaError(c, locale, err)
+	}
+
+	if err := h.magicLinkService.RequestLink(c.Request().Context(), req.Email); err != nil {
+		c.Logger().Errorf("Failed to send magic link: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": getLocalizedMessage(locale, "magicLink.sent"),
+	})
+}
+
+// Consume handles POST /magic-link/consume
+// The emailed link opens a frontend page that posts the token here. Consuming on POST rather
+// than on the GET of the link keeps mail scanners that prefetch links from using it up.
+// On success it sets the auth cookie and answers like POST /login.
+func (h *MagicLinkHandler) Consume(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	var req MagicLinkConsumeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	ipAddress := c.RealIP()
+	response, token, err := h.magicLinkService.Consume(loginContext(c), req.Token, ipAddress, c.Request().UserAgent())
+	if err != nil {
+		if limitErr, ok := err.(*services.SessionLimitError); ok {
+			return sessionLimitReached(c, locale, limitErr)
+		}
+		if statusErr, ok := err.(*services.UserStatusError); ok {
+			c.Logger().Warnf("Magic link login attempt for %s account", statusErr.Status)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.login.accountDisabled"),
+			})
+		}
+		if err == services.ErrMagicLinkInvalid || err == services.ErrInvalidCredentials {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "magicLink.invalid"),
+			})
+		}
+		if err == services.ErrSSORequired {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.login.ssoRequired"),
+			})
+		}
+
+		c.Logger().Errorf("Magic link login failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	setAuthCookie(c, token)
+
+	c.Logger().Infof("Magic link login successful: user=%s, tenant=%s, ip=%s",
+		response.User.ID, response.User.TenantID, ipAddress)
+
+	return c.JSON(http.StatusOK, response)
+}
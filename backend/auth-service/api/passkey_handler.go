@@ -0,0 +1,257 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/services"
+)
+
+// PasskeyHandler handles passkey (WebAuthn) registration, login and management
+type PasskeyHandler struct {
+	passkeyService *services.PasskeyService
+	authService    *services.AuthService
+	jwtService     *services.JWTService
+}
+
+func NewPasskeyHandler(passkeyService *services.PasskeyService, authService *services.AuthService, jwtService *services.JWTService) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+		authService:    authService,
+		jwtService:     jwtService,
+	}
+}
+
+// currentSession returns the session of the auth_token cookie, or writes a 401 and returns nil
+func currentSession(c echo.Context, locale string, jwtService *services.JWTService, authService *services.AuthService) (*models.SessionData, error) {
+	cookie, err := c.Cookie("auth_token")
+	if err != nil {
+		return nil, c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.notFound"),
+		})
+	}
+
+	claims, err := jwtService.Validate(cookie.Value)
+	if err != nil {
+		return nil, c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.invalid"),
+		})
+	}
+
+	sessionData, err := authService.ValidateSession(c.Request().Context(), claims.SessionID)
+	if err != nil {
+		if err == services.ErrSessionNotFound {
+			return nil, c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.session.expired"),
+			})
+		}
+		c.Logger().Errorf("Failed to validate session: %v", err)
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return sessionData, nil
+}
+
+// passkeyError writes the error of a passkey management operation
+func passkeyError(c echo.Context, locale string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrPasskeyNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": getLocalizedMessage(locale, "passkey.notFound"),
+		})
+	case errors.Is(err, repository.ErrPasskeyExists):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": getLocalizedMessage(locale, "passkey.exists"),
+		})
+	case errors.Is(err, services.ErrInvalidPasskeyName):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "passkey.invalidName"),
+		})
+	case errors.Is(err, services.ErrPasskeyChallenge):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "passkey.challengeExpired"),
+		})
+	case errors.Is(err, services.ErrPasskeyVerification):
+		c.Logger().Warnf("Passkey verification failed: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "passkey.verificationFailed"),
+		})
+	}
+
+	c.Logger().Errorf("Passkey operation failed: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": getLocalizedMessage(locale, "errors.internalServer"),
+	})
+}
+
+// RegistrationOptions handles POST /passkeys/register/options
+// Returns the options for navigator.credentials.create() for the logged-in user.
+func (h *PasskeyHandler) RegistrationOptions(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	// An impersonating operator must not leave a credential on the user's account
+	if session.Impersonation != nil {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	options, err := h.passkeyService.BeginRegistration(c.Request().Context(), session)
+	if err != nil {
+		return passkeyError(c, locale, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"publicKey": options,
+	})
+}
+
+// Register handles POST /passkeys/register
+func (h *PasskeyHandler) Register(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	// An impersonating operator must not leave a credential on the user's account
+	if session.Impersonation != nil {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	var req models.PasskeyRegistrationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(c.Request().Context(), session, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return passkeyError(c, locale, err)
+	}
+	return c.JSON(http.StatusCreated, passkey)
+}
+
+// LoginOptions handles POST /passkeys/login/options
+// Returns the options for navigator.credentials.get(); no email is needed.
+func (h *PasskeyHandler) LoginOptions(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	options, err := h.passkeyService.BeginLogin(c.Request().Context())
+	if err != nil {
+		return passkeyError(c, locale, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"publicKey": options,
+	})
+}
+
+// Login handles POST /passkeys/login
+// On success it sets the auth cookie and answers like POST /login.
+func (h *PasskeyHandler) Login(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	var req models.PasskeyLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	ipAddress := c.RealIP()
+	response, token, err := h.passkeyService.FinishLogin(loginContext(c), &req, ipAddress, c.Request().UserAgent())
+	if err != nil {
+		if limitErr, ok := err.(*services.SessionLimitError); ok {
+			return sessionLimitReached(c, locale, limitErr)
+		}
+		if statusErr, ok := err.(*services.UserStatusError); ok {
+			c.Logger().Warnf("Passkey login attempt for %s account", statusErr.Status)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.login.accountDisabled"),
+			})
+		}
+		if err == services.ErrInvalidCredentials {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "passkey.loginFailed"),
+			})
+		}
+		if err == services.ErrSSORequired {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.login.ssoRequired"),
+			})
+		}
+
+		c.Logger().Errorf("Passkey login failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	setAuthCookie(c, token)
+
+	c.Logger().Infof("Passkey login successful: user=%s, tenant=%s, ip=%s",
+		response.User.ID, response.User.TenantID, ipAddress)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ListPasskeys handles GET /passkeys
+func (h *PasskeyHandler) ListPasskeys(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	passkeys, err := h.passkeyService.ListPasskeys(c.Request().Context(), session)
+	if err != nil {
+		return passkeyError(c, locale, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"passkeys": passkeys,
+	})
+}
+
+// RenamePasskey handles PATCH /passkeys/:id
+func (h *PasskeyHandler) RenamePasskey(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	var req models.RenamePasskeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	passkey, err := h.passkeyService.RenamePasskey(c.Request().Context(), session, c.Param("id"), req.Name, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return passkeyError(c, locale, err)
+	}
+	return c.JSON(http.StatusOK, passkey)
+}
+
+// RevokePasskey handles DELETE /passkeys/:id
+func (h *PasskeyHandler) RevokePasskey(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	if err := h.passkeyService.RevokePasskey(c.Request().Context(), session, c.Param("id"), c.RealIP(), c.Request().UserAgent()); err != nil {
+		return passkeyError(c, locale, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
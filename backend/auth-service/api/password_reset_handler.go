@@ -5,27 +5,31 @@ import (
 	"net/http"
 
 	"github.com/pos/auth-service/src/services"
+	"github.com/pos/auth-service/src/utils"
 
 	"github.com/labstack/echo/v4"
 )
 
 type PasswordResetHandler struct {
 	passwordResetService *services.PasswordResetService
+	captchaService       *services.CaptchaService
 }
 
-func NewPasswordResetHandler(passwordResetService *services.PasswordResetService) *PasswordResetHandler {
+func NewPasswordResetHandler(passwordResetService *services.PasswordResetService, captchaService *services.CaptchaService) *PasswordResetHandler {
 	return &PasswordResetHandler{
 		passwordResetService: passwordResetService,
+		captchaService:       captchaService,
 	}
 }
 
 type RequestResetRequest struct {
-	Email string `json:"email" validate:"required,email"`
+	Email        string `json:"email" validate:"required,email"`
+	CaptchaToken string `json:"captcha_token"`
 }
 
 type ResetPasswordRequest struct {
 	Token       string `json:"token" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=8"`
+	NewPassword string `json:"new_password" validate:"required"`
 }
 
 func (h *PasswordResetHandler) RequestReset(c echo.Context) error {
@@ -38,6 +42,10 @@ func (h *PasswordResetHandler) RequestReset(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
+	if err := h.captchaService.VerifyForEmail(c.Request().Context(), req.Email, req.CaptchaToken, c.RealIP()); err != nil {
+		return captchaError(c, getLocaleFromHeader(c.Request().Header.Get("Accept-Language")), err)
+	}
+
 	token, err := h.passwordResetService.RequestReset(req.Email)
 	if err != nil {
 		c.Logger().Error("Failed to request password reset: ", err)
@@ -71,6 +79,13 @@ func (h *PasswordResetHandler) ResetPassword(c echo.Context) error {
 
 	err := h.passwordResetService.ResetPassword(req.Token, req.NewPassword)
 	if err != nil {
+		if policyErr, ok := err.(*utils.PasswordPolicyError); ok {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":      "Password does not meet the password requirements",
+				"details":    policyErr.Error(),
+				"violations": policyErr.Violations,
+			})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
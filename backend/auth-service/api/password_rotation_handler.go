@@ -0,0 +1,206 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/services"
+	"github.com/pos/auth-service/src/utils"
+)
+
+// PasswordRotationHandler serves the tenant password rotation policy, forced password changes
+// and the endpoint a held-back login sets its new password through
+type PasswordRotationHandler struct {
+	rotation             *services.PasswordRotation
+	passwordResetService *services.PasswordResetService
+	magicLinkService     *services.MagicLinkService
+	authService          *services.AuthService
+	jwtService           *services.JWTService
+}
+
+func NewPasswordRotationHandler(
+	rotation *services.PasswordRotation,
+	passwordResetService *services.PasswordResetService,
+	magicLinkService *services.MagicLinkService,
+	authService *services.AuthService,
+	jwtService *services.JWTService,
+) *PasswordRotationHandler {
+	return &PasswordRotationHandler{
+		rotation:             rotation,
+		passwordResetService: passwordResetService,
+		magicLinkService:     magicLinkService,
+		authService:          authService,
+		jwtService:           jwtService,
+	}
+}
+
+// ChangeRequired handles POST /password/change-required
+// It takes the change token a login answered with and the new password, and on success sets
+// the auth cookie and answers like POST /login.
+func (h *PasswordRotationHandler) ChangeRequired(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	var req models.ChangeRequiredPasswordRequest
+	if err := c.Bind(&req); err != nil || req.NewPassword == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	ctx := loginContext(c)
+	tenantID, userID, err := h.rotation.ChangeGrant(ctx, req.ChangeToken)
+	if err != nil {
+		if errors.Is(err, services.ErrPasswordChangeTokenInvalid) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "passwordRotation.invalidToken"),
+			})
+		}
+		c.Logger().Errorf("Failed to read password change token: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	if err := h.passwordResetService.ChangeRequiredPassword(ctx, tenantID, userID, req.NewPassword); err != nil {
+		if policyErr, ok := err.(*utils.PasswordPolicyError); ok {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":      getLocalizedMessage(locale, "validation.passwordPolicy"),
+				"details":    policyErr.Error(),
+				"violations": policyErr.Violations,
+			})
+		}
+		c.Logger().Errorf("Failed to change required password: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	h.rotation.RevokeChangeToken(ctx, req.ChangeToken)
+
+	ipAddress := c.RealIP()
+	response, token, err := h.authService.LoginAfterPasswordChange(ctx, tenantID, userID, ipAddress, c.Request().UserAgent())
+	if err != nil {
+		if verifyErr, ok := err.(*services.LoginVerificationRequiredError); ok {
+			return loginVerificationRequired(c, locale, h.magicLinkService, verifyErr)
+		}
+		if limitErr, ok := err.(*services.SessionLimitError); ok {
+			return sessionLimitReached(c, locale, limitErr)
+		}
+		if statusErr, ok := err.(*services.UserStatusError); ok {
+			c.Logger().Warnf("Password change login for %s account", statusErr.Status)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.login.accountDisabled"),
+			})
+		}
+		c.Logger().Errorf("Login after password change failed: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	setAuthCookie(c, token)
+
+	c.Logger().Infof("Login after required password change: user=%s, tenant=%s, ip=%s",
+		response.User.ID, response.User.TenantID, ipAddress)
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetSettings handles GET /password-rotation
+func (h *PasswordRotationHandler) GetSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	settings, err := h.rotation.GetSettings(c.Request().Context(), session)
+	if err != nil {
+		c.Logger().Errorf("Failed to get password rotation policy: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /password-rotation
+func (h *PasswordRotationHandler) UpdateSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	if session.Role != "owner" {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	var req models.UpdatePasswordRotationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	settings, err := h.rotation.UpdateSettings(c.Request().Context(), session, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPasswordMaxAge) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "passwordRotation.invalidAge"),
+			})
+		}
+		c.Logger().Errorf("Failed to update password rotation policy: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// ForceChange handles POST /password-rotation/force-change
+// Only the owner may force users, or the whole tenant, to change their password.
+func (h *PasswordRotationHandler) ForceChange(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	if session.Role != "owner" {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	var req models.ForcePasswordChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	// currentSession has validated the cookie; its claims name the session to keep
+	cookie, _ := c.Cookie("auth_token")
+	claims, err := h.jwtService.Validate(cookie.Value)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.invalid"),
+		})
+	}
+
+	result, err := h.rotation.ForceChange(c.Request().Context(), session, claims.SessionID, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidUserIDs) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+			})
+		}
+		c.Logger().Errorf("Failed to force password change: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/services"
+)
+
+// pinTerminalCookie holds the token of a device enrolled for PIN quick-switching
+const pinTerminalCookie = "pos_terminal"
+
+// PinSwitchHandler lets staff switch users on a shared POS terminal with their PIN
+type PinSwitchHandler struct {
+	pinSwitchService *services.PinSwitchService
+	authService      *services.AuthService
+	jwtService       *services.JWTService
+}
+
+func NewPinSwitchHandler(pinSwitchService *services.PinSwitchService, authService *services.AuthService, jwtService *services.JWTService) *PinSwitchHandler {
+	return &PinSwitchHandler{
+		pinSwitchService: pinSwitchService,
+		authService:      authService,
+		jwtService:       jwtService,
+	}
+}
+
+// EnrollTerminal handles POST /pin/terminal
+// Run from a manager's regular session; the device keeps the terminal cookie after they switch away.
+func (h *PinSwitchHandler) EnrollTerminal(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	terminal, token, err := h.pinSwitchService.EnrollTerminal(c.Request().Context(), session)
+	if err != nil {
+		if errors.Is(err, services.ErrPinTerminalSession) {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "pin.enrollNotAllowed"),
+			})
+		}
+		c.Logger().Errorf("Failed to enroll PIN terminal: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	h.setTerminalCookie(c, token, int(h.pinSwitchService.TerminalTTL().Seconds()))
+	return c.JSON(http.StatusOK, terminal)
+}
+
+// RemoveTerminal handles DELETE /pin/terminal
+func (h *PinSwitchHandler) RemoveTerminal(c echo.Context) error {
+	if cookie, err := c.Cookie(pinTerminalCookie); err == nil {
+		if err := h.pinSwitchService.RemoveTerminal(c.Request().Context(), cookie.Value); err != nil {
+			c.Logger().Errorf("Failed to remove PIN terminal: %v", err)
+		}
+	}
+	h.setTerminalCookie(c, "", -1)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListUsers handles GET /pin/users
+func (h *PinSwitchHandler) ListUsers(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	terminal, err := h.terminal(c, locale)
+	if terminal == nil {
+		return err
+	}
+
+	users, err := h.pinSwitchService.ListUsers(c.Request().Context(), terminal)
+	if err != nil {
+		c.Logger().Errorf("Failed to list PIN users: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"users": users,
+	})
+}
+
+// Switch handles POST /pin/switch
+func (h *PinSwitchHandler) Switch(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	terminal, err := h.terminal(c, locale)
+	if terminal == nil {
+		return err
+	}
+
+	var req models.PinSwitchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	// The session the terminal was on, so a previous cashier's PIN session can be ended
+	var previousSessionID string
+	if cookie, err := c.Cookie("auth_token"); err == nil {
+		if claims, err := h.jwtService.Validate(cookie.Value); err == nil {
+			previousSessionID = claims.SessionID
+		}
+	}
+
+	response, token, err := h.pinSwitchService.Switch(c.Request().Context(), terminal, previousSessionID, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		if limitErr, ok := err.(*services.SessionLimitError); ok {
+			return sessionLimitReached(c, locale, limitErr)
+		}
+		if _, ok := err.(*services.UserStatusError); ok {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.login.accountDisabled"),
+			})
+		}
+		switch {
+		case errors.Is(err, services.ErrPinInvalid):
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "pin.invalid"),
+			})
+		case errors.Is(err, services.ErrPinLocked):
+			return c.JSON(http.StatusTooManyRequests, map[string]string{
+				"error": getLocalizedMessage(locale, "pin.locked"),
+			})
+		case errors.Is(err, services.ErrPinUserLocked):
+			return c.JSON(http.StatusLocked, map[string]string{
+				"error": getLocalizedMessage(locale, "pin.userLocked"),
+			})
+		}
+		c.Logger().Errorf("Failed to switch user by PIN: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	setAuthCookie(c, token)
+	return c.JSON(http.StatusOK, response)
+}
+
+// Lock handles POST /pin/lock
+// Ends the cashier's PIN session; the terminal stays enrolled for the next switch.
+func (h *PinSwitchHandler) Lock(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+
+	cookie, err := c.Cookie("auth_token")
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.notFound"),
+		})
+	}
+	claims, err := h.jwtService.Validate(cookie.Value)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.invalid"),
+		})
+	}
+
+	if err := h.pinSwitchService.Lock(c.Request().Context(), claims.SessionID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrSessionNotFound):
+			clearAuthCookie(c)
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "auth.session.expired"),
+			})
+		case errors.Is(err, services.ErrNotPinSession):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "pin.notPinSession"),
+			})
+		}
+		c.Logger().Errorf("Failed to lock PIN session: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	clearAuthCookie(c)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// terminal returns the enrolled terminal of the request, or writes the error response and returns nil
+func (h *PinSwitchHandler) terminal(c echo.Context, locale string) (*models.PinTerminal, error) {
+	var token string
+	if cookie, err := c.Cookie(pinTerminalCookie); err == nil {
+		token = cookie.Value
+	}
+
+	terminal, err := h.pinSwitchService.Terminal(c.Request().Context(), token)
+	if err != nil {
+		if errors.Is(err, services.ErrPinTerminalInvalid) {
+			return nil, c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": getLocalizedMessage(locale, "pin.terminalRequired"),
+			})
+		}
+		c.Logger().Errorf("Failed to read PIN terminal: %v", err)
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return terminal, nil
+}
+
+func (h *PinSwitchHandler) setTerminalCookie(c echo.Context, token string, maxAge int) {
+	isProduction := c.Request().Header.Get("X-Forwarded-Proto") == "https"
+	c.SetCookie(&http.Cookie{
+		Name:     pinTerminalCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   isProduction,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   maxAge,
+	})
+}
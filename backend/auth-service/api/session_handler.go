@@ -60,16 +60,29 @@ func (h *SessionHandler) GetSession(c echo.Context) error {
 		})
 	}
 
+	access, err := h.authService.ResolveAccess(c.Request().Context(), sessionData.TenantID, sessionData.UserID, sessionData.Role)
+	if err != nil {
+		c.Logger().Errorf("Failed to resolve permissions: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
 	// Return session information
 	response := models.SessionResponse{
 		Valid: true,
 		User: &models.UserInfo{
-			ID:        sessionData.UserID,
-			Email:     sessionData.Email,
-			TenantID:  sessionData.TenantID,
-			Role:      sessionData.Role,
-			FirstName: sessionData.FirstName,
-			LastName:  sessionData.LastName,
+			ID:             sessionData.UserID,
+			Email:          sessionData.Email,
+			TenantID:       sessionData.TenantID,
+			Role:           sessionData.Role,
+			FirstName:      sessionData.FirstName,
+			LastName:       sessionData.LastName,
+			Permissions:    access.Permissions,
+			CustomRole:     access.CustomRoleName,
+			OutletID:       access.OutletID,
+			AvatarURL:      h.authService.AvatarURL(c.Request().Context(), sessionData.TenantID, sessionData.UserID),
+			ImpersonatedBy: sessionData.Impersonation,
 		},
 		TenantID: sessionData.TenantID,
 	}
@@ -122,8 +135,17 @@ func (h *SessionHandler) RefreshSession(c echo.Context) error {
 		})
 	}
 
-	// Session is valid - generate new JWT token
-	newToken, err := h.jwtService.Generate(sessionID, sessionData.UserID, sessionData.TenantID, sessionData.Email, sessionData.Role)
+	// Permissions are resolved again so changes to the user's custom role reach the new token
+	access, err := h.authService.ResolveAccess(c.Request().Context(), sessionData.TenantID, sessionData.UserID, sessionData.Role)
+	if err != nil {
+		log.Error().Msgf("Failed to resolve permissions for refresh: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+
+	// Session is valid - generate new JWT token; impersonation and PIN session tokens keep their flag and end with the session
+	newToken, err := h.jwtService.GenerateForSession(sessionID, sessionData, access)
 	if err != nil {
 		log.Error().Msgf("Failed to generate new JWT token: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -148,12 +170,17 @@ func (h *SessionHandler) RefreshSession(c echo.Context) error {
 	response := models.SessionResponse{
 		Valid: true,
 		User: &models.UserInfo{
-			ID:        sessionData.UserID,
-			Email:     sessionData.Email,
-			TenantID:  sessionData.TenantID,
-			Role:      sessionData.Role,
-			FirstName: sessionData.FirstName,
-			LastName:  sessionData.LastName,
+			ID:             sessionData.UserID,
+			Email:          sessionData.Email,
+			TenantID:       sessionData.TenantID,
+			Role:           sessionData.Role,
+			FirstName:      sessionData.FirstName,
+			LastName:       sessionData.LastName,
+			Permissions:    access.Permissions,
+			CustomRole:     access.CustomRoleName,
+			OutletID:       access.OutletID,
+			AvatarURL:      h.authService.AvatarURL(c.Request().Context(), sessionData.TenantID, sessionData.UserID),
+			ImpersonatedBy: sessionData.Impersonation,
 		},
 		TenantID: sessionData.TenantID,
 	}
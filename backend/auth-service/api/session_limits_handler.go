@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/services"
+)
+
+// SessionLimitsHandler manages a tenant's limit on concurrent sessions per user
+type SessionLimitsHandler struct {
+	sessionLimits *services.SessionLimits
+	authService   *services.AuthService
+	jwtService    *services.JWTService
+}
+
+func NewSessionLimitsHandler(sessionLimits *services.SessionLimits, authService *services.AuthService, jwtService *services.JWTService) *SessionLimitsHandler {
+	return &SessionLimitsHandler{
+		sessionLimits: sessionLimits,
+		authService:   authService,
+		jwtService:    jwtService,
+	}
+}
+
+// GetSettings handles GET /session-limits
+func (h *SessionLimitsHandler) GetSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	settings, err := h.sessionLimits.GetSettings(c.Request().Context(), session)
+	if err != nil {
+		c.Logger().Errorf("Failed to get session limits: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /session-limits
+func (h *SessionLimitsHandler) UpdateSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	if session.Role != "owner" {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	var req models.UpdateSessionLimitsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	settings, err := h.sessionLimits.UpdateSettings(c.Request().Context(), session, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSessionLimits) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "sessionLimits.invalid"),
+			})
+		}
+		c.Logger().Errorf("Failed to update session limits: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// sessionLimitReached refuses a login because the user already has as many sessions as the tenant allows
+func sessionLimitReached(c echo.Context, locale string, limitErr *services.SessionLimitError) error {
+	return c.JSON(http.StatusConflict, map[string]interface{}{
+		"error": getLocalizedMessage(locale, "auth.login.sessionLimit"),
+		"code":  "SESSION_LIMIT_REACHED",
+		"limit": limitErr.Limit,
+	})
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/services"
+)
+
+// SSOHandler handles single sign-on through Google and the tenant's SSO settings
+// The login endpoints are browser navigations, so they answer with redirects: to Google,
+// then to the dashboard, or back to the login page with an sso_error code.
+type SSOHandler struct {
+	ssoService  *services.SSOService
+	authService *services.AuthService
+	jwtService  *services.JWTService
+	frontendURL string
+}
+
+func NewSSOHandler(ssoService *services.SSOService, authService *services.AuthService, jwtService *services.JWTService, frontendURL string) *SSOHandler {
+	return &SSOHandler{
+		ssoService:  ssoService,
+		authService: authService,
+		jwtService:  jwtService,
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// GoogleLogin handles GET /sso/google/login?tenant=<slug>
+func (h *SSOHandler) GoogleLogin(c echo.Context) error {
+	authURL, err := h.ssoService.BeginGoogleLogin(c.Request().Context(), c.QueryParam("tenant"))
+	if err != nil {
+		return h.redirectToLogin(c, err)
+	}
+	return c.Redirect(http.StatusFound, authURL)
+}
+
+// GoogleCallback handles GET /sso/google/callback, Google's redirect back after sign-in
+func (h *SSOHandler) GoogleCallback(c echo.Context) error {
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		c.Logger().Infof("Google sign-in was not completed: %s", providerErr)
+		return c.Redirect(http.StatusFound, h.loginURL("cancelled"))
+	}
+
+	ipAddress := c.RealIP()
+	response, token, err := h.ssoService.FinishGoogleLogin(loginContext(c), c.QueryParam("code"), c.QueryParam("state"), ipAddress, c.Request().UserAgent())
+	if err != nil {
+		return h.redirectToLogin(c, err)
+	}
+
+	setAuthCookie(c, token)
+
+	c.Logger().Infof("SSO login successful: user=%s, tenant=%s, ip=%s",
+		response.User.ID, response.User.TenantID, ipAddress)
+
+	return c.Redirect(http.StatusFound, h.frontendURL+"/dashboard")
+}
+
+// redirectToLogin sends the browser back to the login page with the reason the SSO login failed
+func (h *SSOHandler) redirectToLogin(c echo.Context, err error) error {
+	code := "failed"
+	switch {
+	case errors.Is(err, services.ErrSSONotEnabled):
+		code = "not_enabled"
+	case errors.Is(err, services.ErrSSONotConfigured):
+		code = "not_configured"
+	case errors.Is(err, services.ErrSSOState):
+		code = "expired"
+	case errors.Is(err, services.ErrSSOEmailRequired):
+		code = "email_not_verified"
+	case errors.Is(err, services.ErrSSOUserNotFound), errors.Is(err, services.ErrInvalidCredentials):
+		code = "account_not_found"
+	case errors.Is(err, services.ErrSSOVerification):
+		c.Logger().Warnf("SSO login rejected: %v", err)
+	default:
+		var statusErr *services.UserStatusError
+		var limitErr *services.SessionLimitError
+		if errors.As(err, &statusErr) {
+			code = "account_disabled"
+		} else if errors.As(err, &limitErr) {
+			code = "session_limit"
+		} else {
+			c.Logger().Errorf("SSO login failed: %v", err)
+		}
+	}
+	return c.Redirect(http.StatusFound, h.loginURL(code))
+}
+
+func (h *SSOHandler) loginURL(code string) string {
+	return h.frontendURL + "/login?" + url.Values{"sso_error": {code}}.Encode()
+}
+
+// GetSettings handles GET /sso/settings
+func (h *SSOHandler) GetSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+
+	settings, err := h.ssoService.GetSettings(c.Request().Context(), session)
+	if err != nil {
+		c.Logger().Errorf("Failed to get SSO settings: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /sso/settings
+// Only the owner may change how everyone in the tenant logs in.
+func (h *SSOHandler) UpdateSettings(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return err
+	}
+	if session.Role != "owner" {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.forbidden"),
+		})
+	}
+
+	var req models.UpdateSSOSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	settings, err := h.ssoService.UpdateSettings(c.Request().Context(), session, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidSSOPolicy):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "sso.invalidPolicy"),
+			})
+		case errors.Is(err, services.ErrSSONotConfigured):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": getLocalizedMessage(locale, "sso.notConfigured"),
+			})
+		}
+		c.Logger().Errorf("Failed to update SSO settings: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": getLocalizedMessage(locale, "errors.internalServer"),
+		})
+	}
+	return c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/services"
+)
+
+// StepUpHandler re-authenticates the logged-in user before sensitive operations
+// Success sets the step_up_token cookie the API gateway checks on flagged routes.
+type StepUpHandler struct {
+	stepUpService *services.StepUpService
+	authService   *services.AuthService
+	jwtService    *services.JWTService
+}
+
+func NewStepUpHandler(stepUpService *services.StepUpService, authService *services.AuthService, jwtService *services.JWTService) *StepUpHandler {
+	return &StepUpHandler{
+		stepUpService: stepUpService,
+		authService:   authService,
+		jwtService:    jwtService,
+	}
+}
+
+// session returns the current session and its ID, or writes the error response and returns nil
+func (h *StepUpHandler) session(c echo.Context, locale string) (*models.SessionData, string, error) {
+	session, err := currentSession(c, locale, h.jwtService, h.authService)
+	if session == nil {
+		return nil, "", err
+	}
+	// currentSession has validated the cookie; its claims name the session to bind the token to
+	cookie, _ := c.Cookie("auth_token")
+	claims, err := h.jwtService.Validate(cookie.Value)
+	if err != nil {
+		return nil, "", c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.session.invalid"),
+		})
+	}
+	return session, claims.SessionID, nil
+}
+
+// Password handles POST /step-up/password
+func (h *StepUpHandler) Password(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, sessionID, err := h.session(c, locale)
+	if session == nil {
+		return err
+	}
+
+	var req models.StepUpPasswordRequest
+	if err := c.Bind(&req); err != nil || req.Password == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	response, token, err := h.stepUpService.WithPassword(c.Request().Context(), session, sessionID, req.Password, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return stepUpError(c, locale, err)
+	}
+	h.setStepUpCookie(c, token)
+	return c.JSON(http.StatusOK, response)
+}
+
+// PasskeyOptions handles POST /step-up/passkey/options
+func (h *StepUpHandler) PasskeyOptions(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, _, err := h.session(c, locale)
+	if session == nil {
+		return err
+	}
+
+	options, err := h.stepUpService.PasskeyOptions(c.Request().Context(), session)
+	if err != nil {
+		return stepUpError(c, locale, err)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"publicKey": options,
+	})
+}
+
+// Passkey handles POST /step-up/passkey
+func (h *StepUpHandler) Passkey(c echo.Context) error {
+	locale := getLocaleFromHeader(c.Request().Header.Get("Accept-Language"))
+	session, sessionID, err := h.session(c, locale)
+	if session == nil {
+		return err
+	}
+
+	var req models.PasskeyLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": getLocalizedMessage(locale, "validation.invalidRequest"),
+		})
+	}
+
+	response, token, err := h.stepUpService.WithPasskey(c.Request().Context(), session, sessionID, &req, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		return stepUpError(c, locale, err)
+	}
+	h.setStepUpCookie(c, token)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *StepUpHandler) setStepUpCookie(c echo.Context, token string) {
+	isProduction := c.Request().Header.Get("X-Forwarded-Proto") == "https"
+	c.SetCookie(&http.Cookie{
+		Name:     "step_up_token",
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   isProduction,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(h.stepUpService.TTL().Seconds()),
+	})
+}
+
+// stepUpError writes the response for a re-authentication that did not succeed
+func stepUpError(c echo.Context, locale string, err error) error {
+	if lockedErr, ok := err.(*services.AccountLockedError); ok {
+		retryAfterSeconds := int(lockedErr.RetryAfter().Seconds()) + 1
+		c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+		return c.JSON(http.StatusLocked, map[string]interface{}{
+			"error":       getLocalizedMessage(locale, "auth.login.accountLocked"),
+			"retryAfter":  retryAfterSeconds,
+			"lockedUntil": lockedErr.LockedUntil,
+		})
+	}
+	if _, ok := err.(*services.UserStatusError); ok {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "auth.login.accountDisabled"),
+		})
+	}
+	switch {
+	case errors.Is(err, services.ErrStepUpImpersonation):
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": getLocalizedMessage(locale, "stepUp.impersonation"),
+		})
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": getLocalizedMessage(locale, "stepUp.failed"),
+		})
+	case errors.Is(err, repository.ErrPasskeyNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": getLocalizedMessage(locale, "passkey.notFound"),
+		})
+	}
+
+	c.Logger().Errorf("Step-up authentication failed: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": getLocalizedMessage(locale, "errors.internalServer"),
+	})
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"database/sql"
 	stdlog "log"
+	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/labstack/echo/v4"
@@ -107,23 +112,67 @@ func main() {
 	}
 	defer auditPublisher.Close()
 
-	authService, err := services.NewAuthService(db, sessionManager, jwtService, rateLimiter, eventPublisher, auditPublisher)
+	// Per-account lockout with exponential backoff, on top of the per-email rate limit
+	lockoutThreshold := utils.GetEnvInt("ACCOUNT_LOCKOUT_THRESHOLD")
+	lockoutBaseDelay := utils.GetEnvInt("ACCOUNT_LOCKOUT_BASE_SECONDS")
+	lockoutMaxDelay := utils.GetEnvInt("ACCOUNT_LOCKOUT_MAX_SECONDS")
+	accountLockout := services.NewAccountLockout(repository.NewAccountLockoutRepository(db), auditPublisher, lockoutThreshold, lockoutBaseDelay, lockoutMaxDelay)
+
+	// Logins from a new device or country are alerted on and, when configured, confirmed by email
+	requireLoginVerification := os.Getenv("LOGIN_NEW_DEVICE_REQUIRE_VERIFICATION") == "true"
+	loginAnomalies := services.NewLoginAnomalyDetector(repository.NewKnownDeviceRepository(db), requireLoginVerification)
+
+	// Password rotation: tenant maximum password age and forced changes, enforced on password login
+	passwordRotation := services.NewPasswordRotation(repository.NewPasswordRotationRepository(db), redisClient, sessionManager, auditPublisher)
+
+	// Concurrent session limits per user, by role, rejecting new logins or evicting the oldest session
+	sessionLimits := services.NewSessionLimits(repository.NewSessionLimitRepository(db), sessionManager, auditPublisher)
+
+	// Avatars are stored by user-service; sessions carry presigned URLs to them
+	avatarURLs, err := services.NewAvatarURLs(db)
+	if err != nil {
+		log.Fatalf("Failed to initialize avatar storage: %v", err)
+	}
+
+	authService, err := services.NewAuthService(db, sessionManager, jwtService, rateLimiter, accountLockout, loginAnomalies, passwordRotation, sessionLimits, avatarURLs, eventPublisher, auditPublisher)
 	if err != nil {
 		log.Fatalf("Failed to initialize AuthService: %v", err)
 	}
 
+	// End the sessions of users deactivated in user-service
+	consumerCtx, stopConsumers := context.WithCancel(context.Background())
+	userEventHandler := services.NewUserEventHandler(authService, sessionManager, auditPublisher)
+	userEventsConsumer := queue.NewKafkaConsumer(kafkaBrokers, utils.GetEnv("KAFKA_USER_EVENTS_TOPIC"), utils.GetEnv("SERVICE_NAME"), userEventHandler.Handle)
+	go userEventsConsumer.Start(consumerCtx)
+
+	// End the sessions of tenants suspended or scheduled for deletion in tenant-service
+	tenantEventHandler := services.NewTenantEventHandler(userEventHandler)
+	tenantEventsConsumer := queue.NewKafkaConsumer(kafkaBrokers, utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC"), utils.GetEnv("SERVICE_NAME"), tenantEventHandler.Handle)
+	go tenantEventsConsumer.Start(consumerCtx)
+
 	// Initialize VaultClient for password reset service
 	vaultClient, err := utils.NewVaultClient()
 	if err != nil {
 		log.Fatalf("Failed to initialize VaultClient for password reset: %v", err)
 	}
 
-	// Health checks
-	e.GET("/health", api.HealthCheck)
-	e.GET("/ready", api.ReadyCheck)
+	// Health checks; readiness verifies the database, Redis and Kafka
+	healthHandler := api.NewHealthHandler(db, redisClient, kafkaBrokers)
+	e.GET("/health", healthHandler.HealthCheck)
+	e.GET("/ready", healthHandler.ReadyCheck)
+
+	// CAPTCHA on login and password reset, switched per environment and per tenant
+	captchaService := services.NewCaptchaService(repository.NewCaptchaRepository(db), utils.LoadCaptchaVerifier(), authService, auditPublisher)
+	captchaHandler := api.NewCaptchaHandler(captchaService, authService, jwtService)
+	e.GET("/captcha/config", captchaHandler.GetConfig)
+	e.GET("/captcha/settings", captchaHandler.GetSettings)
+	e.PUT("/captcha/settings", captchaHandler.UpdateSettings)
+
+	// Magic link (passwordless email) login, also used to confirm logins from new devices
+	magicLinkService := services.NewMagicLinkService(redisClient, authService, eventPublisher, utils.GetEnvInt("MAGIC_LINK_TTL_MINUTES"))
 
 	// Auth endpoints
-	loginHandler := api.NewLoginHandler(authService)
+	loginHandler := api.NewLoginHandler(authService, captchaService, magicLinkService)
 	e.POST("/login", loginHandler.Login)
 
 	sessionHandler := api.NewSessionHandler(authService, jwtService)
@@ -142,13 +191,125 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize PasswordResetRepository: %v", err)
 	}
-	passwordResetService := services.NewPasswordResetService(passwordResetRepo, db, eventPublisher, vaultClient)
-	passwordResetHandler := api.NewPasswordResetHandler(passwordResetService)
+	passwordPolicy := utils.LoadPasswordPolicy()
+	passwordHistorySize := utils.GetEnvInt("PASSWORD_HISTORY_SIZE")
+	passwordResetService := services.NewPasswordResetService(passwordResetRepo, db, eventPublisher, vaultClient, passwordPolicy, passwordHistorySize)
+	passwordResetHandler := api.NewPasswordResetHandler(passwordResetService, captchaService)
 	e.POST("/password-reset/request", passwordResetHandler.RequestReset)
 	e.POST("/password-reset/reset", passwordResetHandler.ResetPassword)
 
-	// Start server
+	// Password rotation endpoints
+	passwordRotationHandler := api.NewPasswordRotationHandler(passwordRotation, passwordResetService, magicLinkService, authService, jwtService)
+	e.POST("/password/change-required", passwordRotationHandler.ChangeRequired)
+	e.GET("/password-rotation", passwordRotationHandler.GetSettings)
+	e.PUT("/password-rotation", passwordRotationHandler.UpdateSettings)
+	e.POST("/password-rotation/force-change", passwordRotationHandler.ForceChange)
+
+	// Account lockout endpoints
+	accountLockoutHandler := api.NewAccountLockoutHandler(accountLockout, authService, jwtService)
+	e.POST("/users/:id/unlock", accountLockoutHandler.Unlock)
+
+	// Passkey (WebAuthn) endpoints
+	webAuthnConfig := services.WebAuthnConfig{
+		RPID:    utils.GetEnv("WEBAUTHN_RP_ID"),
+		RPName:  utils.GetEnv("WEBAUTHN_RP_NAME"),
+		Origins: strings.Split(utils.GetEnv("WEBAUTHN_ORIGINS"), ","),
+	}
+	passkeyRepo := repository.NewPasskeyRepository(db)
+	passkeyService := services.NewPasskeyService(passkeyRepo, redisClient, authService, auditPublisher, webAuthnConfig)
+	passkeyHandler := api.NewPasskeyHandler(passkeyService, authService, jwtService)
+	e.POST("/passkeys/login/options", passkeyHandler.LoginOptions)
+	e.POST("/passkeys/login", passkeyHandler.Login)
+	e.POST("/passkeys/register/options", passkeyHandler.RegistrationOptions)
+	e.POST("/passkeys/register", passkeyHandler.Register)
+	e.GET("/passkeys", passkeyHandler.ListPasskeys)
+	e.PATCH("/passkeys/:id", passkeyHandler.RenamePasskey)
+	e.DELETE("/passkeys/:id", passkeyHandler.RevokePasskey)
+
+	// Step-up authentication before sensitive operations, enforced by the API gateway
+	stepUpService := services.NewStepUpService(authService, passkeyService, jwtService, auditPublisher, utils.GetEnvInt("STEP_UP_TTL_MINUTES"))
+	stepUpHandler := api.NewStepUpHandler(stepUpService, authService, jwtService)
+	e.POST("/step-up/password", stepUpHandler.Password)
+	e.POST("/step-up/passkey/options", stepUpHandler.PasskeyOptions)
+	e.POST("/step-up/passkey", stepUpHandler.Passkey)
+
+	// Login email change, confirmed from the current address and verified at the new one
+	emailChangeService := services.NewEmailChangeService(repository.NewEmailChangeRepository(db), authService, sessionManager, eventPublisher, auditPublisher, utils.GetEnvInt("EMAIL_CHANGE_TTL_HOURS"))
+	emailChangeHandler := api.NewEmailChangeHandler(emailChangeService, authService, jwtService)
+	e.POST("/email-change", emailChangeHandler.Request)
+	e.POST("/email-change/confirm", emailChangeHandler.Confirm)
+	e.POST("/email-change/verify", emailChangeHandler.Verify)
+
+	// Per-tenant limits on concurrent sessions per user
+	sessionLimitsHandler := api.NewSessionLimitsHandler(sessionLimits, authService, jwtService)
+	e.GET("/session-limits", sessionLimitsHandler.GetSettings)
+	e.PUT("/session-limits", sessionLimitsHandler.UpdateSettings)
+
+	// Cashier PIN quick-switch on shared terminals a manager enrolled from their own session
+	pinSwitchService := services.NewPinSwitchService(redisClient, repository.NewPinRepository(db), authService, sessionManager, jwtService, auditPublisher, utils.GetEnvInt("PIN_SESSION_TTL_MINUTES"), utils.GetEnvInt("PIN_TERMINAL_TTL_HOURS"))
+	pinSwitchHandler := api.NewPinSwitchHandler(pinSwitchService, authService, jwtService)
+	e.POST("/pin/terminal", pinSwitchHandler.EnrollTerminal)
+	e.DELETE("/pin/terminal", pinSwitchHandler.RemoveTerminal)
+	e.GET("/pin/users", pinSwitchHandler.ListUsers)
+	e.POST("/pin/switch", pinSwitchHandler.Switch)
+	e.POST("/pin/lock", pinSwitchHandler.Lock)
+
+	// RFC 7662 token introspection for backend services, authenticated with client credentials
+	introspectionService := services.NewIntrospectionService(redisClient, jwtService, sessionManager, os.Getenv("INTROSPECTION_CLIENTS"), utils.GetEnvInt("INTROSPECTION_CACHE_TTL_SECONDS"))
+	introspectionHandler := api.NewIntrospectionHandler(introspectionService)
+	e.POST("/oauth/introspect", introspectionHandler.Introspect)
+
+	// Single sign-on endpoints; Google sign-in stays unavailable until its client is configured
+	googleConfig := services.GoogleOIDCConfig{
+		ClientID:     os.Getenv("GOOGLE_OAUTH_CLIENT_ID"),
+		ClientSecret: os.Getenv("GOOGLE_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("GOOGLE_OAUTH_REDIRECT_URL"),
+	}
+	ssoService := services.NewSSOService(repository.NewSSORepository(db), redisClient, authService, auditPublisher, googleConfig)
+	ssoHandler := api.NewSSOHandler(ssoService, authService, jwtService, utils.GetEnv("FRONTEND_DOMAIN"))
+	e.GET("/sso/google/login", ssoHandler.GoogleLogin)
+	e.GET("/sso/google/callback", ssoHandler.GoogleCallback)
+	e.GET("/sso/settings", ssoHandler.GetSettings)
+	e.PUT("/sso/settings", ssoHandler.UpdateSettings)
+
+	// Magic link (passwordless email) login endpoints
+	magicLinkHandler := api.NewMagicLinkHandler(magicLinkService, captchaService)
+	e.POST("/magic-link/request", magicLinkHandler.Request)
+	e.POST("/magic-link/consume", magicLinkHandler.Consume)
+
+	// Platform operator impersonation endpoints
+	impersonationMaxMinutes := utils.GetEnvInt("IMPERSONATION_MAX_MINUTES")
+	impersonationService := services.NewImpersonationService(repository.NewPlatformOperatorRepository(db), authService, sessionManager, jwtService, auditPublisher, impersonationMaxMinutes)
+	impersonationHandler := api.NewImpersonationHandler(impersonationService, jwtService)
+	e.POST("/impersonation", impersonationHandler.Start)
+	e.POST("/impersonation/stop", impersonationHandler.Stop)
+
+	// Start server in a goroutine
 	port := utils.GetEnv("PORT")
 	stdlog.Printf("Auth service starting on port %s", port)
-	e.Logger.Fatal(e.Start(":" + port))
+	go func() {
+		if err := e.Start(":" + port); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	// Wait for an interrupt or termination signal, then drain in-flight requests
+	// so deferred closes of the database, Redis and Kafka clients run
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	stdlog.Println("Shutting down auth service gracefully...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		stdlog.Printf("Server forced to shutdown: %v", err)
+	}
+	stopConsumers()
+	if err := redisClient.Close(); err != nil {
+		stdlog.Printf("Failed to close Redis client: %v", err)
+	}
+
+	stdlog.Println("Auth service stopped")
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TenantCaptchaSettings is a tenant's CAPTCHA policy for login and password reset
+// It only takes effect when CAPTCHA is enabled for the environment.
+type TenantCaptchaSettings struct {
+	TenantID  string     `json:"tenantId"`
+	Enabled   bool       `json:"enabled"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy *string    `json:"updatedBy,omitempty"`
+}
+
+// UpdateCaptchaSettingsRequest turns CAPTCHA on or off for a tenant
+type UpdateCaptchaSettingsRequest struct {
+	Enabled bool `json:"enabled"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Steps of a login email change
+const (
+	EmailChangePendingConfirmation = "pending_confirmation" // Waiting for the link sent to the current address
+	EmailChangePendingVerification = "pending_verification" // Waiting for the link sent to the new address
+	EmailChangeCompleted           = "completed"
+	EmailChangeCancelled           = "cancelled" // Replaced by a newer request
+)
+
+// EmailChange is a user's request to change their login email
+type EmailChange struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenantId"`
+	UserID      string     `json:"userId"`
+	NewEmail    string     `json:"newEmail"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// EmailChangeRequest starts a change of the logged-in user's login email
+type EmailChangeRequest struct {
+	NewEmail string `json:"newEmail"`
+}
+
+// EmailChangeTokenRequest carries the token of a link emailed during an email change
+type EmailChangeTokenRequest struct {
+	Token string `json:"token"`
+}
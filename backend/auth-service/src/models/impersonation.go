@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PlatformOperator is a member of the platform support team who may impersonate tenant users
+type PlatformOperator struct {
+	ID    string
+	Email string
+	Name  string
+}
+
+// Impersonation marks a session that an operator started on behalf of a tenant user
+type Impersonation struct {
+	OperatorID    string    `json:"operatorId"`
+	OperatorEmail string    `json:"operatorEmail"`
+	Reason        string    `json:"reason"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// StartImpersonationRequest is the payload an operator sends to impersonate a user
+type StartImpersonationRequest struct {
+	TenantID        string `json:"tenantId"`
+	UserID          string `json:"userId"`
+	Reason          string `json:"reason"`
+	DurationMinutes int    `json:"durationMinutes,omitempty"` // Defaults to, and is capped at, the configured maximum
+}
+
+// ImpersonationResponse describes the impersonation session that was started
+type ImpersonationResponse struct {
+	User      UserInfo  `json:"user"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// IntrospectionResponse describes a token to a backend service, in the shape of RFC 7662
+// Inactive tokens carry nothing but active=false, so callers learn nothing about why.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	TokenType string `json:"token_type,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	JWTID     string `json:"jti,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	// Claims backend services authorize with
+	TenantID       string   `json:"tenant_id,omitempty"`
+	SessionID      string   `json:"session_id,omitempty"`
+	Role           string   `json:"role,omitempty"`
+	Permissions    []string `json:"permissions,omitempty"`
+	CustomRoleID   string   `json:"custom_role_id,omitempty"`
+	ImpersonatorID string   `json:"impersonator_id,omitempty"`
+	PinTerminalID  string   `json:"pin_terminal_id,omitempty"`
+}
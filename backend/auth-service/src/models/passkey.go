@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+)
+
+// Passkey is a WebAuthn credential a staff member registered to log in without a password
+type Passkey struct {
+	ID           string     `json:"id"`
+	TenantID     string     `json:"tenantId"`
+	UserID       string     `json:"userId"`
+	CredentialID []byte     `json:"-"`
+	PublicKey    []byte     `json:"-"` // COSE_Key from the authenticator
+	Algorithm    int64      `json:"algorithm"`
+	SignCount    uint32     `json:"-"`
+	Transports   []string   `json:"transports,omitempty"`
+	Name         string     `json:"name"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	LastUsedAt   *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// PasskeyChallenge is a pending registration or login ceremony, kept in Redis until used
+type PasskeyChallenge struct {
+	Ceremony string `json:"ceremony"` // webauthn.create or webauthn.get
+	UserID   string `json:"userId,omitempty"`
+	TenantID string `json:"tenantId,omitempty"`
+}
+
+// PasskeyRegistrationRequest finishes a registration with the authenticator's response
+// Binary fields are base64url encoded, as produced by PublicKeyCredential.toJSON().
+type PasskeyRegistrationRequest struct {
+	Name       string `json:"name"`
+	Credential struct {
+		ID       string `json:"id"`
+		RawID    string `json:"rawId"`
+		Type     string `json:"type"`
+		Response struct {
+			ClientDataJSON    string   `json:"clientDataJSON"`
+			AttestationObject string   `json:"attestationObject"`
+			Transports        []string `json:"transports,omitempty"`
+		} `json:"response"`
+	} `json:"credential"`
+}
+
+// PasskeyLoginRequest finishes a login with the authenticator's assertion
+type PasskeyLoginRequest struct {
+	Credential struct {
+		ID       string `json:"id"`
+		RawID    string `json:"rawId"`
+		Type     string `json:"type"`
+		Response struct {
+			ClientDataJSON    string `json:"clientDataJSON"`
+			AuthenticatorData string `json:"authenticatorData"`
+			Signature         string `json:"signature"`
+			UserHandle        string `json:"userHandle,omitempty"`
+		} `json:"response"`
+	} `json:"credential"`
+}
+
+// RenamePasskeyRequest renames one of the user's passkeys
+type RenamePasskeyRequest struct {
+	Name string `json:"name"`
+}
+
+// PasskeyCredentialDescriptor identifies a credential in WebAuthn options
+type PasskeyCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// PasskeyCredentialParam is a key type the server accepts for new credentials
+type PasskeyCredentialParam struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"` // COSE algorithm identifier
+}
+
+// PasskeyCreationOptions is the publicKey argument of navigator.credentials.create()
+type PasskeyCreationOptions struct {
+	Challenge string `json:"challenge"`
+	RP        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParam      `json:"pubKeyCredParams"`
+	Timeout                int64                         `json:"timeout"`
+	Attestation            string                        `json:"attestation"`
+	ExcludeCredentials     []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		AuthenticatorAttachment string `json:"authenticatorAttachment"`
+		ResidentKey             string `json:"residentKey"`
+		RequireResidentKey      bool   `json:"requireResidentKey"`
+		UserVerification        string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+}
+
+// PasskeyRequestOptions is the publicKey argument of navigator.credentials.get()
+// allowCredentials is empty so the authenticator offers every passkey it holds for the site.
+type PasskeyRequestOptions struct {
+	Challenge        string                        `json:"challenge"`
+	RPID             string                        `json:"rpId"`
+	Timeout          int64                         `json:"timeout"`
+	UserVerification string                        `json:"userVerification"`
+	AllowCredentials []PasskeyCredentialDescriptor `json:"allowCredentials"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Why a login must set a new password before it gets a session
+const (
+	PasswordChangeRequired = "required" // Flagged by an owner or a bulk reset
+	PasswordChangeExpired  = "expired"  // Older than the tenant's maximum password age
+)
+
+// TenantPasswordRotation is a tenant's password rotation policy
+type TenantPasswordRotation struct {
+	TenantID   string     `json:"tenantId"`
+	MaxAgeDays int        `json:"maxAgeDays"` // 0 means passwords never expire
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy  *string    `json:"updatedBy,omitempty"`
+}
+
+// UpdatePasswordRotationRequest changes a tenant's maximum password age
+type UpdatePasswordRotationRequest struct {
+	MaxAgeDays int `json:"maxAgeDays"`
+}
+
+// ForcePasswordChangeRequest flags users to change their password at next login
+// An empty list flags every user of the tenant, for when its credentials are compromised.
+type ForcePasswordChangeRequest struct {
+	UserIDs        []string `json:"userIds"`
+	RevokeSessions bool     `json:"revokeSessions"` // Also log the users out everywhere
+}
+
+// ForcePasswordChangeResponse reports how many users were flagged
+type ForcePasswordChangeResponse struct {
+	Flagged int `json:"flagged"`
+}
+
+// ChangeRequiredPasswordRequest sets the new password a login was held back for
+type ChangeRequiredPasswordRequest struct {
+	ChangeToken string `json:"changeToken"`
+	NewPassword string `json:"newPassword"`
+}
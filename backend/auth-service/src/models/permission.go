@@ -0,0 +1,89 @@
+package models
+
+// Named permissions carried in the JWT and enforced by the API gateway
+const (
+	PermissionProductsRead    = "products.read"
+	PermissionProductsWrite   = "products.write"
+	PermissionCatalogPublish  = "catalog.publish"
+	PermissionOrdersRead      = "orders.read"
+	PermissionOrdersWrite     = "orders.write"
+	PermissionOrdersRefund    = "orders.refund"   // Refund orders from the drawer or file refund requests
+	PermissionRefundsApprove  = "refunds.approve" // Approve refund requests above the tenant threshold
+	PermissionAnalyticsRead   = "analytics.read"  // Dashboards and settlement reports
+	PermissionSettingsWrite   = "settings.write"  // Order settings, vouchers, promotions and notifications
+	PermissionTenantWrite     = "tenant.write"    // Tenant configuration: payment gateways, delivery, branding
+	PermissionUsersInvite     = "users.invite"
+	PermissionAuditRead       = "audit.read"
+	PermissionDataRightsWrite = "data_rights.write" // UU PDP data export and user deletion
+	PermissionRolesManage     = "roles.manage"      // Define custom roles and assign them; never granted by a custom role
+)
+
+// rolePermissions maps each role to the permissions it grants
+var rolePermissions = map[string][]string{
+	"owner": {
+		PermissionProductsRead,
+		PermissionProductsWrite,
+		PermissionCatalogPublish,
+		PermissionOrdersRead,
+		PermissionOrdersWrite,
+		PermissionOrdersRefund,
+		PermissionRefundsApprove,
+		PermissionAnalyticsRead,
+		PermissionSettingsWrite,
+		PermissionTenantWrite,
+		PermissionUsersInvite,
+		PermissionAuditRead,
+		PermissionDataRightsWrite,
+		PermissionRolesManage,
+	},
+	"manager": {
+		PermissionProductsRead,
+		PermissionProductsWrite,
+		PermissionOrdersRead,
+		PermissionOrdersWrite,
+		PermissionOrdersRefund,
+		PermissionRefundsApprove,
+		PermissionAnalyticsRead,
+		PermissionSettingsWrite,
+		PermissionUsersInvite,
+	},
+	"cashier": {
+		PermissionOrdersRead,
+		PermissionOrdersWrite,
+		PermissionOrdersRefund,
+	},
+}
+
+// PermissionsForRole returns the permissions a role grants; unknown roles get none
+func PermissionsForRole(role string) []string {
+	permissions := rolePermissions[role]
+	return append(make([]string, 0, len(permissions)), permissions...)
+}
+
+// IsAssignablePermission reports whether a custom role may grant the permission
+func IsAssignablePermission(permission string) bool {
+	if permission == PermissionRolesManage {
+		return false
+	}
+	for _, p := range rolePermissions["owner"] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessGrant is what a user may do: the permissions of their custom role when one is
+// assigned, otherwise those of their base role
+type AccessGrant struct {
+	Permissions    []string
+	CustomRoleID   string
+	CustomRoleName string
+	// Outlet the user works at; empty for users working at every outlet
+	OutletID string
+}
+
+// RoleAccess returns the grant of a user without a custom role
+func RoleAccess(role string) *AccessGrant {
+	return &AccessGrant{Permissions: PermissionsForRole(role)}
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// PinSwitch marks a short-lived session a cashier started with their PIN on a shared terminal
+type PinSwitch struct {
+	TerminalID string    `json:"terminalId"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// PinTerminal is a device a manager enrolled for PIN quick-switching
+type PinTerminal struct {
+	ID         string    `json:"-"`
+	TenantID   string    `json:"tenantId"`
+	EnrolledBy string    `json:"enrolledBy"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// PinSwitchRequest is the payload a cashier sends to switch in on a terminal
+type PinSwitchRequest struct {
+	UserID string `json:"userId"`
+	Pin    string `json:"pin"`
+}
+
+// PinUser is a staff member the switch screen offers, because they have a PIN
+type PinUser struct {
+	ID        string `json:"id"`
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+	Role      string `json:"role"`
+}
+
+// PinSwitchResponse describes the session a cashier switched into
+type PinSwitchResponse struct {
+	User      UserInfo  `json:"user"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -26,12 +26,18 @@ type SessionData struct {
 	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
 	CreatedAt int64  `json:"createdAt"`
+	// Set when a platform operator started the session on the user's behalf
+	Impersonation *Impersonation `json:"impersonation,omitempty"`
+	// Set when a cashier switched in with their PIN on a shared terminal
+	PinSwitch *PinSwitch `json:"pinSwitch,omitempty"`
 }
 
 // LoginRequest represents the login request payload
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// Solved CAPTCHA token, required when CAPTCHA is enabled for the user's tenant
+	CaptchaToken string `json:"captchaToken,omitempty"`
 }
 
 // LoginResponse represents the login response
@@ -49,6 +55,15 @@ type UserInfo struct {
 	FirstName string `json:"firstName,omitempty"`
 	LastName  string `json:"lastName,omitempty"`
 	Locale    string `json:"locale"`
+	// Permissions granted by the role or custom role, for the frontend to hide actions the user cannot take
+	Permissions []string `json:"permissions"`
+	CustomRole  string   `json:"customRole,omitempty"`
+	// Outlet the user works at; empty for users working at every outlet
+	OutletID string `json:"outletId,omitempty"`
+	// Presigned URL of the user's avatar, valid for AVATAR_URL_TTL_SECONDS
+	AvatarURL string `json:"avatarUrl,omitempty"`
+	// Set while a platform operator impersonates the user, so the UI can show a banner
+	ImpersonatedBy *Impersonation `json:"impersonatedBy,omitempty"`
 }
 
 // SessionResponse represents session validation response
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// What a login that exceeds the session limit does
+const (
+	SessionLimitReject      = "reject"       // The new login is refused
+	SessionLimitEvictOldest = "evict_oldest" // The oldest sessions are logged out to make room
+)
+
+// TenantSessionLimits is a tenant's limit on concurrent sessions per user, by role
+// A limit of 0 means unlimited.
+type TenantSessionLimits struct {
+	TenantID           string     `json:"tenantId"`
+	OwnerMaxSessions   int        `json:"ownerMaxSessions"`
+	ManagerMaxSessions int        `json:"managerMaxSessions"`
+	CashierMaxSessions int        `json:"cashierMaxSessions"`
+	OnLimit            string     `json:"onLimit"`
+	UpdatedAt          *time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy          *string    `json:"updatedBy,omitempty"`
+}
+
+// MaxSessions returns the limit for a base role, 0 when unlimited
+func (l *TenantSessionLimits) MaxSessions(role string) int {
+	switch role {
+	case "owner":
+		return l.OwnerMaxSessions
+	case "manager":
+		return l.ManagerMaxSessions
+	case "cashier":
+		return l.CashierMaxSessions
+	}
+	return 0
+}
+
+// UpdateSessionLimitsRequest changes a tenant's concurrent session limits
+type UpdateSessionLimitsRequest struct {
+	OwnerMaxSessions   int    `json:"ownerMaxSessions"`
+	ManagerMaxSessions int    `json:"managerMaxSessions"`
+	CashierMaxSessions int    `json:"cashierMaxSessions"`
+	OnLimit            string `json:"onLimit"`
+}
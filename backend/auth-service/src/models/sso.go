@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// SSO identity providers
+const (
+	SSOProviderGoogle = "google"
+)
+
+// TenantSSOSettings is a tenant's single sign-on policy
+type TenantSSOSettings struct {
+	TenantID      string     `json:"tenantId"`
+	GoogleEnabled bool       `json:"googleEnabled"`
+	SSOOnly       bool       `json:"ssoOnly"` // Password and passkey logins are refused
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy     *string    `json:"updatedBy,omitempty"`
+}
+
+// UpdateSSOSettingsRequest changes a tenant's single sign-on policy
+type UpdateSSOSettingsRequest struct {
+	GoogleEnabled bool `json:"googleEnabled"`
+	SSOOnly       bool `json:"ssoOnly"`
+}
+
+// UserIdentity links a user to their account at an identity provider
+type UserIdentity struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenantId"`
+	UserID      string     `json:"userId"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"-"` // Provider's stable account ID (the ID token's sub)
+	LinkedAt    time.Time  `json:"linkedAt"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+}
+
+// SSOLoginState is kept between the redirect to the provider and its callback
+type SSOLoginState struct {
+	Provider     string `json:"provider"`
+	TenantID     string `json:"tenantId,omitempty"` // Empty when the tenant is found from the email
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+}
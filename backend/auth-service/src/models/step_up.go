@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Step-up authentication methods
+const (
+	StepUpPassword = "password"
+	StepUpPasskey  = "passkey"
+)
+
+// StepUpPasswordRequest confirms the logged-in user's identity with their password
+type StepUpPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// StepUpResponse tells the client until when sensitive operations are allowed without asking again
+type StepUpResponse struct {
+	Method    string    `json:"method"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type User struct {
 	ID           string
 	TenantID     string
@@ -10,4 +12,9 @@ type User struct {
 	FirstName    string
 	LastName     string
 	Locale       string
+	LockedUntil  *time.Time
+	// Password rotation: a flagged user, or one whose password outlived the tenant's maximum age,
+	// must set a new password at their next password login
+	MustChangePassword bool
+	PasswordChangedAt  time.Time
 }
@@ -70,6 +70,28 @@ func (p *EventPublisher) PublishUserLogin(ctx context.Context, tenantID, userID,
 	return p.publish(ctx, event)
 }
 
+// PublishNewDeviceLogin announces a login from a device or country the user had not logged in from
+func (p *EventPublisher) PublishNewDeviceLogin(ctx context.Context, tenantID, userID, email, name, ipAddress, userAgent, country string, newDevice, newCountry bool) error {
+	event := NotificationEvent{
+		EventID:   uuid.New().String(),
+		EventType: "security.new_device_login",
+		TenantID:  tenantID,
+		UserID:    userID,
+		Data: map[string]interface{}{
+			"email":       email,
+			"name":        name,
+			"ip_address":  ipAddress,
+			"user_agent":  userAgent,
+			"country":     country,
+			"new_device":  newDevice,
+			"new_country": newCountry,
+		},
+		Timestamp: time.Now(),
+	}
+
+	return p.publish(ctx, event)
+}
+
 func (p *EventPublisher) PublishPasswordResetRequested(ctx context.Context, tenantID, userID, email, name, resetToken string) error {
 	event := NotificationEvent{
 		EventID:   uuid.New().String(),
@@ -87,6 +109,25 @@ func (p *EventPublisher) PublishPasswordResetRequested(ctx context.Context, tena
 	return p.publish(ctx, event)
 }
 
+func (p *EventPublisher) PublishMagicLinkRequested(ctx context.Context, tenantID, userID, email, name, loginToken, purpose string, expiresInMinutes int) error {
+	event := NotificationEvent{
+		EventID:   uuid.New().String(),
+		EventType: "login.magic_link_requested",
+		TenantID:  tenantID,
+		UserID:    userID,
+		Data: map[string]interface{}{
+			"email":              email,
+			"name":               name,
+			"login_token":        loginToken,
+			"purpose":            purpose,
+			"expires_in_minutes": expiresInMinutes,
+		},
+		Timestamp: time.Now(),
+	}
+
+	return p.publish(ctx, event)
+}
+
 func (p *EventPublisher) PublishPasswordChanged(ctx context.Context, tenantID, userID, email, name string) error {
 	event := NotificationEvent{
 		EventID:   uuid.New().String(),
@@ -103,6 +144,63 @@ func (p *EventPublisher) PublishPasswordChanged(ctx context.Context, tenantID, u
 	return p.publish(ctx, event)
 }
 
+// PublishEmailChangeConfirmRequested asks the current address to confirm a change of login email
+func (p *EventPublisher) PublishEmailChangeConfirmRequested(ctx context.Context, tenantID, userID, email, name, newEmail, token string, expiresInHours int) error {
+	event := NotificationEvent{
+		EventID:   uuid.New().String(),
+		EventType: "email.change_confirm_requested",
+		TenantID:  tenantID,
+		UserID:    userID,
+		Data: map[string]interface{}{
+			"email":            email,
+			"name":             name,
+			"new_email":        newEmail,
+			"token":            token,
+			"expires_in_hours": expiresInHours,
+		},
+		Timestamp: time.Now(),
+	}
+
+	return p.publish(ctx, event)
+}
+
+// PublishEmailChangeVerifyRequested asks the new address to verify it belongs to the user
+func (p *EventPublisher) PublishEmailChangeVerifyRequested(ctx context.Context, tenantID, userID, newEmail, name, token string, expiresInHours int) error {
+	event := NotificationEvent{
+		EventID:   uuid.New().String(),
+		EventType: "email.change_verify_requested",
+		TenantID:  tenantID,
+		UserID:    userID,
+		Data: map[string]interface{}{
+			"email":            newEmail,
+			"name":             name,
+			"token":            token,
+			"expires_in_hours": expiresInHours,
+		},
+		Timestamp: time.Now(),
+	}
+
+	return p.publish(ctx, event)
+}
+
+// PublishEmailChanged tells the previous address that the login email was changed
+func (p *EventPublisher) PublishEmailChanged(ctx context.Context, tenantID, userID, previousEmail, name, newEmail string) error {
+	event := NotificationEvent{
+		EventID:   uuid.New().String(),
+		EventType: "email.changed",
+		TenantID:  tenantID,
+		UserID:    userID,
+		Data: map[string]interface{}{
+			"email":     previousEmail,
+			"name":      name,
+			"new_email": newEmail,
+		},
+		Timestamp: time.Now(),
+	}
+
+	return p.publish(ctx, event)
+}
+
 func (p *EventPublisher) publish(ctx context.Context, event NotificationEvent) error {
 	data, err := json.Marshal(event)
 	if err != nil {
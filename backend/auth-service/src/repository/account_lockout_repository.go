@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// AccountLockoutRepository tracks failed password logins on the users table
+type AccountLockoutRepository struct {
+	db *sql.DB
+}
+
+func NewAccountLockoutRepository(db *sql.DB) *AccountLockoutRepository {
+	return &AccountLockoutRepository{db: db}
+}
+
+// RecordFailedLogin counts a failed password login and returns the consecutive failures
+// The count starts over when the previous failure is older than resetBefore.
+func (r *AccountLockoutRepository) RecordFailedLogin(ctx context.Context, userID string, resetBefore time.Time) (int, error) {
+	var attempts int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE users SET
+			failed_login_attempts = CASE
+				WHEN last_failed_login_at < $2 THEN 1
+				ELSE failed_login_attempts + 1
+			END,
+			last_failed_login_at = NOW()
+		WHERE id = $1
+		RETURNING failed_login_attempts
+	`, userID, resetBefore).Scan(&attempts)
+	if err == sql.ErrNoRows {
+		return 0, ErrUserNotFound
+	}
+	return attempts, err
+}
+
+// Lock refuses password logins for a user until the given time
+func (r *AccountLockoutRepository) Lock(ctx context.Context, userID string, until time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET locked_until = $2 WHERE id = $1`, userID, until)
+	return err
+}
+
+// Unlock clears a user's lock and failed login count; lockedUntil is the lock that was cleared, if any
+func (r *AccountLockoutRepository) Unlock(ctx context.Context, tenantID, userID string) (lockedUntil *time.Time, err error) {
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE users u SET
+			failed_login_attempts = 0,
+			last_failed_login_at = NULL,
+			locked_until = NULL
+		FROM (SELECT id, locked_until FROM users WHERE id = $1 AND tenant_id = $2 FOR UPDATE) previous
+		WHERE u.id = previous.id
+		RETURNING previous.locked_until
+	`, userID, tenantID).Scan(&lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	return lockedUntil, err
+}
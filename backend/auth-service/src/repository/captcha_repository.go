@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pos/auth-service/src/models"
+)
+
+// CaptchaRepository stores tenants' CAPTCHA policy
+type CaptchaRepository struct {
+	db *sql.DB
+}
+
+func NewCaptchaRepository(db *sql.DB) *CaptchaRepository {
+	return &CaptchaRepository{db: db}
+}
+
+// GetSettings returns a tenant's CAPTCHA policy; tenants that never changed it have CAPTCHA enabled
+func (r *CaptchaRepository) GetSettings(ctx context.Context, tenantID string) (*models.TenantCaptchaSettings, error) {
+	settings := &models.TenantCaptchaSettings{TenantID: tenantID, Enabled: true}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT enabled, updated_at, updated_by
+		FROM tenant_captcha_settings
+		WHERE tenant_id = $1
+	`, tenantID).Scan(&settings.Enabled, &settings.UpdatedAt, &settings.UpdatedBy)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or replaces a tenant's CAPTCHA policy
+func (r *CaptchaRepository) SaveSettings(ctx context.Context, settings *models.TenantCaptchaSettings) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO tenant_captcha_settings (tenant_id, enabled, updated_at, updated_by)
+		VALUES ($1, $2, NOW(), $3)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by
+		RETURNING updated_at
+	`, settings.TenantID, settings.Enabled, settings.UpdatedBy).Scan(&settings.UpdatedAt)
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/pos/auth-service/src/models"
+)
+
+var ErrEmailInUse = errors.New("email is already used by another account")
+
+// EmailChangeRepository stores login email change requests and applies them to users
+// New emails are stored as the caller encrypted them; tokens only as their hash.
+type EmailChangeRepository struct {
+	db *sql.DB
+}
+
+func NewEmailChangeRepository(db *sql.DB) *EmailChangeRepository {
+	return &EmailChangeRepository{db: db}
+}
+
+// EmailInUse reports whether an account that is not deleted, in any tenant, logs in with the email
+func (r *EmailChangeRepository) EmailInUse(ctx context.Context, encryptedEmail string) (bool, error) {
+	var inUse bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM users WHERE email = $1 AND status <> 'deleted')
+	`, encryptedEmail).Scan(&inUse)
+	return inUse, err
+}
+
+// CountRecent returns how many email changes the user requested since the given time
+func (r *EmailChangeRepository) CountRecent(ctx context.Context, userID string, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM email_change_requests WHERE user_id = $1 AND created_at > $2
+	`, userID, since).Scan(&count)
+	return count, err
+}
+
+// Create stores a new request and cancels the user's pending one, so only the latest link works
+func (r *EmailChangeRepository) Create(ctx context.Context, change *models.EmailChange, encryptedEmail, tokenHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE email_change_requests SET status = 'cancelled'
+		WHERE user_id = $1 AND status IN ('pending_confirmation', 'pending_verification')
+	`, change.UserID); err != nil {
+		return err
+	}
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO email_change_requests (tenant_id, user_id, new_email, token_hash, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, change.TenantID, change.UserID, encryptedEmail, tokenHash, change.Status, change.ExpiresAt).Scan(&change.ID, &change.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindPending returns the unexpired request at the given step whose current token has this hash,
+// with its new email still encrypted, or nil when there is none
+func (r *EmailChangeRepository) FindPending(ctx context.Context, tokenHash, status string) (*models.EmailChange, error) {
+	change := &models.EmailChange{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, user_id, new_email, status, expires_at, confirmed_at, completed_at, created_at
+		FROM email_change_requests
+		WHERE token_hash = $1 AND status = $2 AND expires_at > NOW()
+	`, tokenHash, status).Scan(
+		&change.ID,
+		&change.TenantID,
+		&change.UserID,
+		&change.NewEmail,
+		&change.Status,
+		&change.ExpiresAt,
+		&change.ConfirmedAt,
+		&change.CompletedAt,
+		&change.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// MarkConfirmed moves a request to verification at the new address under a new token
+func (r *EmailChangeRepository) MarkConfirmed(ctx context.Context, change *models.EmailChange, tokenHash string) error {
+	return r.db.QueryRowContext(ctx, `
+		UPDATE email_change_requests
+		SET status = 'pending_verification', token_hash = $2, expires_at = $3, confirmed_at = NOW()
+		WHERE id = $1 AND status = 'pending_confirmation'
+		RETURNING status, confirmed_at
+	`, change.ID, tokenHash, change.ExpiresAt).Scan(&change.Status, &change.ConfirmedAt)
+}
+
+// Complete replaces the user's email and its search hash and closes the request in one transaction,
+// returning the user's previous encrypted email, or ErrEmailInUse when another account took the
+// address since the request was made
+func (r *EmailChangeRepository) Complete(ctx context.Context, change *models.EmailChange, encryptedEmail, emailHash string) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var inUse bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM users WHERE email = $1 AND status <> 'deleted' AND id <> $2)
+	`, encryptedEmail, change.UserID).Scan(&inUse); err != nil {
+		return "", err
+	}
+	if inUse {
+		return "", ErrEmailInUse
+	}
+
+	var previousEmail string
+	if err := tx.QueryRowContext(ctx, `
+		SELECT email FROM users WHERE id = $1 AND tenant_id = $2 FOR UPDATE
+	`, change.UserID, change.TenantID).Scan(&previousEmail); err != nil {
+		return "", err
+	}
+
+	// Opening the link sent to the new address proves the user owns it
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users
+		SET email = $1, email_hash = $2, email_verified = true, email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $3 AND tenant_id = $4
+	`, encryptedEmail, emailHash, change.UserID, change.TenantID); err != nil {
+		return "", err
+	}
+
+	if err := tx.QueryRowContext(ctx, `
+		UPDATE email_change_requests SET status = 'completed', completed_at = NOW()
+		WHERE id = $1 AND status = 'pending_verification'
+		RETURNING status, completed_at
+	`, change.ID).Scan(&change.Status, &change.CompletedAt); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return previousEmail, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// KnownDeviceRepository stores the devices and countries users have logged in from
+type KnownDeviceRepository struct {
+	db *sql.DB
+}
+
+func NewKnownDeviceRepository(db *sql.DB) *KnownDeviceRepository {
+	return &KnownDeviceRepository{db: db}
+}
+
+// LoginHistory reports whether a user has any known login, and whether the device and
+// country were among them; an empty deviceHash or country counts as known
+func (r *KnownDeviceRepository) LoginHistory(ctx context.Context, userID, deviceHash, country string) (hasHistory, deviceKnown, countryKnown bool, err error) {
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) > 0,
+			$2 = '' OR COALESCE(BOOL_OR(device_hash = $2), false),
+			$3 = '' OR COALESCE(BOOL_OR(country_code = $3), false)
+		FROM user_known_devices
+		WHERE user_id = $1
+	`, userID, deviceHash, country).Scan(&hasHistory, &deviceKnown, &countryKnown)
+	return hasHistory, deviceKnown, countryKnown, err
+}
+
+// Remember records a login from the device and country, or refreshes when it was last seen
+func (r *KnownDeviceRepository) Remember(ctx context.Context, tenantID, userID, deviceHash, country, userAgent, ipAddress string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_known_devices (tenant_id, user_id, device_hash, country_code, user_agent, last_ip_address)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, device_hash, country_code) DO UPDATE SET
+			user_agent = EXCLUDED.user_agent,
+			last_ip_address = EXCLUDED.last_ip_address,
+			last_seen_at = NOW()
+	`, tenantID, userID, deviceHash, country, userAgent, ipAddress)
+	return err
+}
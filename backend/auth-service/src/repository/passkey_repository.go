@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/pos/auth-service/src/models"
+)
+
+var (
+	ErrPasskeyNotFound = errors.New("passkey not found")
+	ErrPasskeyExists   = errors.New("passkey is already registered")
+)
+
+// PasskeyRepository stores the WebAuthn credentials staff log in with
+type PasskeyRepository struct {
+	db *sql.DB
+}
+
+func NewPasskeyRepository(db *sql.DB) *PasskeyRepository {
+	return &PasskeyRepository{db: db}
+}
+
+const passkeyColumns = `id, tenant_id, user_id, credential_id, public_key, algorithm, sign_count, transports, name, created_at, last_used_at`
+
+func scanPasskey(row interface{ Scan(...interface{}) error }) (*models.Passkey, error) {
+	var passkey models.Passkey
+	var signCount int64
+	if err := row.Scan(
+		&passkey.ID,
+		&passkey.TenantID,
+		&passkey.UserID,
+		&passkey.CredentialID,
+		&passkey.PublicKey,
+		&passkey.Algorithm,
+		&signCount,
+		pq.Array(&passkey.Transports),
+		&passkey.Name,
+		&passkey.CreatedAt,
+		&passkey.LastUsedAt,
+	); err != nil {
+		return nil, err
+	}
+	passkey.SignCount = uint32(signCount)
+	return &passkey, nil
+}
+
+// Create stores a newly registered passkey
+func (r *PasskeyRepository) Create(ctx context.Context, passkey *models.Passkey) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_passkeys (tenant_id, user_id, credential_id, public_key, algorithm, sign_count, transports, name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, passkey.TenantID, passkey.UserID, passkey.CredentialID, passkey.PublicKey, passkey.Algorithm,
+		int64(passkey.SignCount), pq.Array(passkey.Transports), passkey.Name,
+	).Scan(&passkey.ID, &passkey.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrPasskeyExists
+	}
+	return err
+}
+
+// GetByCredentialID finds the passkey an authenticator signed with
+func (r *PasskeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*models.Passkey, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+passkeyColumns+` FROM user_passkeys WHERE credential_id = $1`, credentialID)
+	passkey, err := scanPasskey(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrPasskeyNotFound
+	}
+	return passkey, err
+}
+
+// ListByUser returns a user's passkeys, newest first
+func (r *PasskeyRepository) ListByUser(ctx context.Context, tenantID, userID string) ([]*models.Passkey, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+passkeyColumns+`
+		FROM user_passkeys
+		WHERE tenant_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+	`, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	passkeys := []*models.Passkey{}
+	for rows.Next() {
+		passkey, err := scanPasskey(rows)
+		if err != nil {
+			return nil, err
+		}
+		passkeys = append(passkeys, passkey)
+	}
+	return passkeys, rows.Err()
+}
+
+// Rename changes the name of one of a user's passkeys
+func (r *PasskeyRepository) Rename(ctx context.Context, tenantID, userID, passkeyID, name string) (*models.Passkey, error) {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE user_passkeys SET name = $4
+		WHERE id = $1 AND tenant_id = $2 AND user_id = $3
+		RETURNING `+passkeyColumns,
+		passkeyID, tenantID, userID, name)
+	passkey, err := scanPasskey(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrPasskeyNotFound
+	}
+	return passkey, err
+}
+
+// Delete revokes one of a user's passkeys
+func (r *PasskeyRepository) Delete(ctx context.Context, tenantID, userID, passkeyID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM user_passkeys WHERE id = $1 AND tenant_id = $2 AND user_id = $3
+	`, passkeyID, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrPasskeyNotFound
+	}
+	return nil
+}
+
+// RecordUse stores the authenticator's new signature counter after a login
+// The update only applies while the stored counter is the one that was checked, so two
+// logins racing with a cloned credential cannot both succeed.
+func (r *PasskeyRepository) RecordUse(ctx context.Context, passkey *models.Passkey, signCount uint32) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_passkeys SET sign_count = $2, last_used_at = NOW()
+		WHERE id = $1 AND sign_count = $3
+	`, passkey.ID, int64(signCount), int64(passkey.SignCount))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
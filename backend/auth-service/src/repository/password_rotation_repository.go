@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/pos/auth-service/src/models"
+)
+
+// PasswordRotationRepository stores tenants' password rotation policy and the users' change flags
+type PasswordRotationRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordRotationRepository(db *sql.DB) *PasswordRotationRepository {
+	return &PasswordRotationRepository{db: db}
+}
+
+// GetSettings returns a tenant's rotation policy; tenants that never set one have no maximum age
+func (r *PasswordRotationRepository) GetSettings(ctx context.Context, tenantID string) (*models.TenantPasswordRotation, error) {
+	settings := &models.TenantPasswordRotation{TenantID: tenantID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT max_age_days, updated_at, updated_by
+		FROM tenant_password_rotation
+		WHERE tenant_id = $1
+	`, tenantID).Scan(&settings.MaxAgeDays, &settings.UpdatedAt, &settings.UpdatedBy)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or replaces a tenant's rotation policy
+func (r *PasswordRotationRepository) SaveSettings(ctx context.Context, settings *models.TenantPasswordRotation) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO tenant_password_rotation (tenant_id, max_age_days, updated_at, updated_by)
+		VALUES ($1, $2, NOW(), $3)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			max_age_days = EXCLUDED.max_age_days,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by
+		RETURNING updated_at
+	`, settings.TenantID, settings.MaxAgeDays, settings.UpdatedBy).Scan(&settings.UpdatedAt)
+}
+
+// FlagMustChange makes the given users, or all of the tenant's users when userIDs is empty,
+// change their password at next login and returns the IDs flagged
+func (r *PasswordRotationRepository) FlagMustChange(ctx context.Context, tenantID string, userIDs []string) ([]string, error) {
+	var filter interface{}
+	if len(userIDs) > 0 {
+		filter = pq.Array(userIDs)
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE users SET must_change_password = true, updated_at = NOW()
+		WHERE tenant_id = $1 AND status <> 'deleted'
+			AND ($2::uuid[] IS NULL OR id = ANY($2::uuid[]))
+		RETURNING id
+	`, tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flagged := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		flagged = append(flagged, id)
+	}
+	return flagged, rows.Err()
+}
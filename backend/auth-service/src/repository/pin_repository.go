@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PinRepository reads the quick-switch PINs users set in the user service
+type PinRepository struct {
+	db *sql.DB
+}
+
+func NewPinRepository(db *sql.DB) *PinRepository {
+	return &PinRepository{db: db}
+}
+
+// GetHash returns a user's PIN hash, empty when they have no PIN, and whether their PIN is locked
+func (r *PinRepository) GetHash(ctx context.Context, tenantID, userID string) (string, bool, error) {
+	var pinHash sql.NullString
+	var locked bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT pin_hash, pin_locked_at IS NOT NULL FROM users WHERE id = $1 AND tenant_id = $2
+	`, userID, tenantID).Scan(&pinHash, &locked)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	return pinHash.String, locked, err
+}
+
+// RecordFailure counts a wrong PIN and locks the PIN once maxFailures are reached; it reports whether the PIN is now locked
+func (r *PinRepository) RecordFailure(ctx context.Context, tenantID, userID string, maxFailures int) (bool, error) {
+	var locked bool
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE users
+		SET pin_failed_attempts = pin_failed_attempts + 1,
+		    pin_locked_at = CASE WHEN pin_failed_attempts + 1 >= $3 THEN COALESCE(pin_locked_at, NOW()) ELSE pin_locked_at END
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING pin_locked_at IS NOT NULL
+	`, userID, tenantID, maxFailures).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return locked, err
+}
+
+// ClearFailures forgets the wrong PINs entered before a successful switch
+func (r *PinRepository) ClearFailures(ctx context.Context, tenantID, userID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET pin_failed_attempts = 0
+		WHERE id = $1 AND tenant_id = $2 AND pin_failed_attempts > 0
+	`, userID, tenantID)
+	return err
+}
+
+// ListUserIDs returns the active users of a tenant who have a PIN
+func (r *PinRepository) ListUserIDs(ctx context.Context, tenantID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM users
+		WHERE tenant_id = $1 AND status = 'active' AND pin_hash IS NOT NULL
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/pos/auth-service/src/models"
+)
+
+// RoleRepository reads the custom roles owners assign to staff; user-service manages them
+type RoleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// GetUserCustomRole returns the custom role assigned to a user, or nil when they have none
+func (r *RoleRepository) GetUserCustomRole(ctx context.Context, tenantID, userID string) (*models.AccessGrant, error) {
+	grant := &models.AccessGrant{}
+	var permissions pq.StringArray
+	err := r.db.QueryRowContext(ctx, `
+		SELECT tr.id, tr.name, tr.permissions
+		FROM users u
+		JOIN tenant_roles tr ON tr.id = u.custom_role_id AND tr.tenant_id = u.tenant_id
+		WHERE u.id = $1 AND u.tenant_id = $2
+	`, userID, tenantID).Scan(&grant.CustomRoleID, &grant.CustomRoleName, &permissions)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	grant.Permissions = permissions
+	return grant, nil
+}
+
+// GetUserOutlet returns the outlet a user works at, or "" when they work at every outlet
+func (r *RoleRepository) GetUserOutlet(ctx context.Context, tenantID, userID string) (string, error) {
+	var outletID sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT outlet_id FROM users WHERE id = $1 AND tenant_id = $2
+	`, userID, tenantID).Scan(&outletID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return outletID.String, nil
+}
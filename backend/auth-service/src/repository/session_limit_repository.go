@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pos/auth-service/src/models"
+)
+
+// SessionLimitRepository stores tenants' concurrent session limits
+type SessionLimitRepository struct {
+	db *sql.DB
+}
+
+func NewSessionLimitRepository(db *sql.DB) *SessionLimitRepository {
+	return &SessionLimitRepository{db: db}
+}
+
+// GetSettings returns a tenant's session limits; tenants that never set them have none
+func (r *SessionLimitRepository) GetSettings(ctx context.Context, tenantID string) (*models.TenantSessionLimits, error) {
+	settings := &models.TenantSessionLimits{TenantID: tenantID, OnLimit: models.SessionLimitReject}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT owner_max_sessions, manager_max_sessions, cashier_max_sessions, on_limit, updated_at, updated_by
+		FROM tenant_session_limits
+		WHERE tenant_id = $1
+	`, tenantID).Scan(
+		&settings.OwnerMaxSessions,
+		&settings.ManagerMaxSessions,
+		&settings.CashierMaxSessions,
+		&settings.OnLimit,
+		&settings.UpdatedAt,
+		&settings.UpdatedBy,
+	)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or replaces a tenant's session limits
+func (r *SessionLimitRepository) SaveSettings(ctx context.Context, settings *models.TenantSessionLimits) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO tenant_session_limits (tenant_id, owner_max_sessions, manager_max_sessions, cashier_max_sessions, on_limit, updated_at, updated_by)
+		VALUES ($1, $2, $3, $4, $5, NOW(), $6)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			owner_max_sessions = EXCLUDED.owner_max_sessions,
+			manager_max_sessions = EXCLUDED.manager_max_sessions,
+			cashier_max_sessions = EXCLUDED.cashier_max_sessions,
+			on_limit = EXCLUDED.on_limit,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by
+		RETURNING updated_at
+	`, settings.TenantID, settings.OwnerMaxSessions, settings.ManagerMaxSessions, settings.CashierMaxSessions,
+		settings.OnLimit, settings.UpdatedBy).Scan(&settings.UpdatedAt)
+}
@@ -295,6 +295,28 @@ func (r *SessionRepository) DeleteExpired(ctx context.Context, retentionDays int
 	return rows, nil
 }
 
+// FindUserIDsByTenantID returns the users of a tenant that have active sessions
+func (r *SessionRepository) FindUserIDsByTenantID(ctx context.Context, tenantID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT user_id FROM sessions
+		WHERE tenant_id = $1 AND terminated_at IS NULL
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users with sessions by tenant: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan session user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
 // FindByUserID finds all active sessions for a user
 func (r *SessionRepository) FindByUserID(ctx context.Context, userID string) ([]*models.Session, error) {
 	query := `
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/pos/auth-service/src/models"
+)
+
+var ErrIdentityLinked = errors.New("identity is already linked to another user")
+
+// SSORepository stores tenants' single sign-on policy and the provider accounts linked to users
+type SSORepository struct {
+	db *sql.DB
+}
+
+func NewSSORepository(db *sql.DB) *SSORepository {
+	return &SSORepository{db: db}
+}
+
+// GetSettings returns a tenant's SSO policy; tenants that never configured SSO get it disabled
+func (r *SSORepository) GetSettings(ctx context.Context, tenantID string) (*models.TenantSSOSettings, error) {
+	settings := &models.TenantSSOSettings{TenantID: tenantID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT google_enabled, sso_only, updated_at, updated_by
+		FROM tenant_sso_settings
+		WHERE tenant_id = $1
+	`, tenantID).Scan(&settings.GoogleEnabled, &settings.SSOOnly, &settings.UpdatedAt, &settings.UpdatedBy)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SaveSettings creates or replaces a tenant's SSO policy
+func (r *SSORepository) SaveSettings(ctx context.Context, settings *models.TenantSSOSettings) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO tenant_sso_settings (tenant_id, google_enabled, sso_only, updated_at, updated_by)
+		VALUES ($1, $2, $3, NOW(), $4)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			google_enabled = EXCLUDED.google_enabled,
+			sso_only = EXCLUDED.sso_only,
+			updated_at = EXCLUDED.updated_at,
+			updated_by = EXCLUDED.updated_by
+		RETURNING updated_at
+	`, settings.TenantID, settings.GoogleEnabled, settings.SSOOnly, settings.UpdatedBy).Scan(&settings.UpdatedAt)
+}
+
+// GetTenantIDBySlug resolves the tenant a login page belongs to; empty when there is no active tenant
+func (r *SSORepository) GetTenantIDBySlug(ctx context.Context, slug string) (string, error) {
+	var tenantID string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id FROM tenants WHERE slug = $1 AND status = 'active'
+	`, slug).Scan(&tenantID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return tenantID, err
+}
+
+// GetIdentity finds the user a provider account is linked to in a tenant
+func (r *SSORepository) GetIdentity(ctx context.Context, tenantID, provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, user_id, provider, subject, linked_at, last_login_at
+		FROM user_identities
+		WHERE tenant_id = $1 AND provider = $2 AND subject = $3
+	`, tenantID, provider, subject).Scan(
+		&identity.ID,
+		&identity.TenantID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.LinkedAt,
+		&identity.LastLoginAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// LinkIdentity links a provider account to a user
+// A user has at most one account per provider; linking another replaces it.
+func (r *SSORepository) LinkIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_identities (tenant_id, user_id, provider, subject)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, user_id, provider) DO UPDATE SET
+			subject = EXCLUDED.subject,
+			linked_at = NOW(),
+			last_login_at = NULL
+		RETURNING id, linked_at
+	`, identity.TenantID, identity.UserID, identity.Provider, identity.Subject).Scan(&identity.ID, &identity.LinkedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrIdentityLinked
+	}
+	return err
+}
+
+// RecordLogin stamps the last time a linked account was used to log in
+func (r *SSORepository) RecordLogin(ctx context.Context, identityID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_identities SET last_login_at = NOW() WHERE id = $1
+	`, identityID)
+	return err
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// failedLoginResetAfter is the quiet period after which failed logins stop counting
+const failedLoginResetAfter = 24 * time.Hour
+
+// AccountLockout locks a user's password login after repeated failures
+// Unlike the Redis rate limiter, which throttles an email for a fixed window, the lockout
+// is kept on the user and grows: the threshold-th failure locks the account for the base
+// delay, and every further failure doubles it, up to the maximum. Passkey and SSO logins
+// do not use the password and are not locked.
+type AccountLockout struct {
+	lockoutRepo    *repository.AccountLockoutRepository
+	auditPublisher *utils.AuditPublisher
+	threshold      int
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+}
+
+func NewAccountLockout(lockoutRepo *repository.AccountLockoutRepository, auditPublisher *utils.AuditPublisher, threshold, baseDelaySeconds, maxDelaySeconds int) *AccountLockout {
+	return &AccountLockout{
+		lockoutRepo:    lockoutRepo,
+		auditPublisher: auditPublisher,
+		threshold:      threshold,
+		baseDelay:      time.Duration(baseDelaySeconds) * time.Second,
+		maxDelay:       time.Duration(maxDelaySeconds) * time.Second,
+	}
+}
+
+// lockoutDelay returns how long an account is locked after the given consecutive failures
+func (l *AccountLockout) lockoutDelay(attempts int) time.Duration {
+	if l.threshold <= 0 || attempts < l.threshold {
+		return 0
+	}
+	delay := l.baseDelay
+	for i := l.threshold; i < attempts; i++ {
+		delay *= 2
+		if delay >= l.maxDelay {
+			return l.maxDelay
+		}
+	}
+	if delay > l.maxDelay {
+		return l.maxDelay
+	}
+	return delay
+}
+
+// Check returns an AccountLockedError while the user is locked
+func (l *AccountLockout) Check(user *models.User) error {
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return &AccountLockedError{LockedUntil: *user.LockedUntil}
+	}
+	return nil
+}
+
+// RecordFailure counts a wrong password and locks the account once it reaches the threshold
+// It returns an AccountLockedError when this failure locked the account.
+func (l *AccountLockout) RecordFailure(ctx context.Context, user *models.User, ipAddress, userAgent string) error {
+	attempts, err := l.lockoutRepo.RecordFailedLogin(ctx, user.ID, time.Now().Add(-failedLoginResetAfter))
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	delay := l.lockoutDelay(attempts)
+	if delay == 0 {
+		return nil
+	}
+	lockedUntil := time.Now().Add(delay)
+	if err := l.lockoutRepo.Lock(ctx, user.ID, lockedUntil); err != nil {
+		return fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	log.Warn().Str("tenant_id", user.TenantID).Str("user_id", user.ID).Int("failed_attempts", attempts).
+		Time("locked_until", lockedUntil).Msg("Account locked after failed logins")
+
+	if l.auditPublisher != nil {
+		auditEvent := &utils.AuditEvent{
+			TenantID:     user.TenantID,
+			ActorType:    "system",
+			Action:       "UPDATE",
+			ResourceType: "user",
+			ResourceID:   user.ID,
+			IPAddress:    &ipAddress,
+			UserAgent:    &userAgent,
+			Metadata: map[string]interface{}{
+				"event":           "account_locked",
+				"failed_attempts": attempts,
+				"locked_until":    lockedUntil.UTC().Format(time.RFC3339),
+				"lock_seconds":    int(delay.Seconds()),
+			},
+		}
+		if err := l.auditPublisher.Publish(ctx, auditEvent); err != nil {
+			log.Debug().Msgf("Failed to publish account lockout audit event: %v\n", err)
+		}
+	}
+	return &AccountLockedError{LockedUntil: lockedUntil}
+}
+
+// Unlock lets an owner or manager clear the lock of a user in their tenant
+func (l *AccountLockout) Unlock(ctx context.Context, session *models.SessionData, userID, ipAddress, userAgent string) error {
+	lockedUntil, err := l.lockoutRepo.Unlock(ctx, session.TenantID, userID)
+	if err != nil {
+		return err
+	}
+
+	if l.auditPublisher != nil {
+		actorID := session.UserID
+		metadata := map[string]interface{}{
+			"event": "account_unlocked",
+		}
+		if lockedUntil != nil {
+			metadata["locked_until"] = lockedUntil.UTC().Format(time.RFC3339)
+		}
+		auditEvent := &utils.AuditEvent{
+			TenantID:     session.TenantID,
+			ActorType:    "user",
+			ActorID:      &actorID,
+			Action:       "UPDATE",
+			ResourceType: "user",
+			ResourceID:   userID,
+			IPAddress:    &ipAddress,
+			UserAgent:    &userAgent,
+			Metadata:     metadata,
+		}
+		if err := l.auditPublisher.Publish(ctx, auditEvent); err != nil {
+			log.Debug().Msgf("Failed to publish account unlock audit event: %v\n", err)
+		}
+	}
+	log.Info().Str("tenant_id", session.TenantID).Str("user_id", userID).Str("unlocked_by", session.UserID).Msg("Account unlocked")
+	return nil
+}
+
+type AccountLockedError struct {
+	LockedUntil time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account is locked until %s", e.LockedUntil.Format(time.RFC3339))
+}
+
+// RetryAfter is how long until the account can log in again
+func (e *AccountLockedError) RetryAfter() time.Duration {
+	if d := time.Until(e.LockedUntil); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -15,15 +15,23 @@ import (
 
 type EventPublisher interface {
 	PublishUserLogin(ctx context.Context, tenantID, userID, email, name, ipAddress, userAgent string) error
+	PublishNewDeviceLogin(ctx context.Context, tenantID, userID, email, name, ipAddress, userAgent, country string, newDevice, newCountry bool) error
 }
 
 type AuthService struct {
 	db                      *sql.DB
 	sessionRepo             *repository.SessionRepository
 	accountVerificationRepo *repository.AccountVerificationRepository
+	ssoRepo                 *repository.SSORepository
+	roleRepo                *repository.RoleRepository
 	sessionManager          *SessionManager
 	jwtService              *JWTService
 	rateLimiter             *RateLimiter
+	lockout                 *AccountLockout
+	anomalies               *LoginAnomalyDetector
+	rotation                *PasswordRotation
+	sessionLimits           *SessionLimits
+	avatars                 *AvatarURLs
 	eventPublisher          EventPublisher
 	encryptor               utils.Encryptor
 	auditPublisher          *utils.AuditPublisher
@@ -34,6 +42,11 @@ func NewAuthService(
 	sessionManager *SessionManager,
 	jwtService *JWTService,
 	rateLimiter *RateLimiter,
+	lockout *AccountLockout,
+	anomalies *LoginAnomalyDetector,
+	rotation *PasswordRotation,
+	sessionLimits *SessionLimits,
+	avatars *AvatarURLs,
 	eventPublisher EventPublisher,
 	auditPublisher *utils.AuditPublisher,
 ) (*AuthService, error) {
@@ -52,9 +65,16 @@ func NewAuthService(
 		db:                      db,
 		sessionRepo:             sessionRepo,
 		accountVerificationRepo: repository.NewVerifyAccountRepository(db),
+		ssoRepo:                 repository.NewSSORepository(db),
+		roleRepo:                repository.NewRoleRepository(db),
 		sessionManager:          sessionManager,
 		jwtService:              jwtService,
 		rateLimiter:             rateLimiter,
+		lockout:                 lockout,
+		anomalies:               anomalies,
+		rotation:                rotation,
+		sessionLimits:           sessionLimits,
+		avatars:                 avatars,
 		eventPublisher:          eventPublisher,
 		encryptor:               vaultClient,
 		auditPublisher:          auditPublisher,
@@ -138,6 +158,11 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 
 	log.Debug().Msgf("DEBUG: User found - ID: %s, Status: %s, Hash length: %d\n", user.ID, user.Status, len(user.PasswordHash))
 
+	// A locked account is refused before its password is checked, so guessing stops
+	if err := s.lockout.Check(user); err != nil {
+		return nil, "", err
+	}
+
 	// Verify password
 	log.Debug().Msgf("DEBUG: Comparing password (input length: %d)\n", len(req.Password))
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
@@ -145,6 +170,7 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 		log.Debug().Msgf("DEBUG: Password comparison failed: %v\n", err)
 		// Increment failed attempts
 		s.rateLimiter.IncrementLoginAttempts(ctx, req.Email, tenantID)
+		lockErr := s.lockout.RecordFailure(ctx, user, ipAddress, userAgent)
 
 		// T103: Publish LoginFailureEvent
 		if s.auditPublisher != nil {
@@ -171,6 +197,13 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 			}
 		}
 
+		if _, ok := lockErr.(*AccountLockedError); ok {
+			return nil, "", lockErr
+		}
+		if lockErr != nil {
+			log.Debug().Msgf("Warning: failed to record failed login: %v\n", lockErr)
+		}
+
 		return nil, "", ErrInvalidCredentials
 	}
 
@@ -184,6 +217,40 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 	// Reset rate limit on successful authentication
 	s.rateLimiter.ResetLoginAttempts(ctx, req.Email, tenantID)
 
+	// Checked only after the password, so the tenant's policy is not revealed to guessers
+	if err := s.checkSSOOnly(ctx, tenantID); err != nil {
+		return nil, "", err
+	}
+
+	// A flagged or expired password gets a change token instead of a session
+	if err := s.rotation.Check(ctx, user); err != nil {
+		return nil, "", err
+	}
+
+	return s.startSession(ctx, user, ipAddress, userAgent, "password")
+}
+
+// startSession creates the session and JWT of an authenticated, active user
+// loginMethod is recorded in the audit trail: password, passkey or the SSO provider.
+func (s *AuthService) startSession(ctx context.Context, user *models.User, ipAddress, userAgent, loginMethod string) (*models.LoginResponse, string, error) {
+	// A login from a new device or country gets its own alert, and may have to be confirmed by email first.
+	// The check fails open: losing the login history must not lock everyone out.
+	anomaly, err := s.anomalies.Evaluate(ctx, user)
+	if err != nil {
+		log.Debug().Msgf("Warning: failed to check login history: %v\n", err)
+		anomaly = nil
+	}
+	if s.anomalies.RequiresVerification(anomaly, loginMethod) {
+		log.Warn().Str("tenant_id", user.TenantID).Str("user_id", user.ID).
+			Bool("new_device", anomaly.NewDevice).Bool("new_country", anomaly.NewCountry).
+			Msg("Login from new device held for email verification")
+		return nil, "", &LoginVerificationRequiredError{TenantID: user.TenantID, UserID: user.ID}
+	}
+
+	if err := s.enforceSessionLimit(ctx, user, ipAddress, userAgent); err != nil {
+		return nil, "", err
+	}
+
 	// Create session in Redis
 	sessionID, err := s.sessionManager.Create(ctx, user)
 	if err != nil {
@@ -208,8 +275,14 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 		log.Debug().Msgf("Warning: failed to create session audit record: %v\n", err)
 	}
 
+	access, err := s.ResolveAccess(ctx, user.TenantID, user.ID, user.Role)
+	if err != nil {
+		s.sessionManager.Delete(ctx, sessionID)
+		return nil, "", err
+	}
+
 	// Generate JWT token
-	token, err := s.jwtService.Generate(sessionID, user.ID, user.TenantID, user.Email, user.Role)
+	token, err := s.jwtService.Generate(sessionID, user.ID, user.TenantID, user.Email, user.Role, access)
 	if err != nil {
 		// Cleanup session
 		s.sessionManager.Delete(ctx, sessionID)
@@ -219,6 +292,10 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 	// Update last login time
 	s.updateLastLogin(ctx, user.ID)
 
+	if err := s.anomalies.Remember(ctx, user, ipAddress, userAgent); err != nil {
+		log.Debug().Msgf("Warning: failed to record login device: %v\n", err)
+	}
+
 	// T103: Publish LoginSuccessEvent
 	if s.auditPublisher != nil {
 		encEmail, _ := s.encryptor.EncryptWithContext(ctx, user.Email, "user:email")
@@ -235,9 +312,14 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 			UserAgent:    &userAgent,
 			Metadata: map[string]interface{}{
 				"email":        encEmail,
-				"login_method": "password",
+				"login_method": loginMethod,
 			},
 		}
+		if anomaly != nil {
+			auditEvent.Metadata["new_device"] = anomaly.NewDevice
+			auditEvent.Metadata["new_country"] = anomaly.NewCountry
+			auditEvent.Metadata["country"] = anomaly.Country
+		}
 		if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
 			log.Debug().Msgf("Failed to publish login success audit event: %v\n", err)
 		}
@@ -250,7 +332,13 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 			name += " " + user.LastName
 		}
 		go func() {
-			if err := s.eventPublisher.PublishUserLogin(context.Background(), user.TenantID, user.ID, user.Email, name, ipAddress, userAgent); err != nil {
+			var err error
+			if anomaly != nil {
+				err = s.eventPublisher.PublishNewDeviceLogin(context.Background(), user.TenantID, user.ID, user.Email, name, ipAddress, userAgent, anomaly.Country, anomaly.NewDevice, anomaly.NewCountry)
+			} else {
+				err = s.eventPublisher.PublishUserLogin(context.Background(), user.TenantID, user.ID, user.Email, name, ipAddress, userAgent)
+			}
+			if err != nil {
 				log.Debug().Msgf("Warning: failed to publish login event: %v\n", err)
 			}
 		}()
@@ -258,13 +346,17 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 
 	response := &models.LoginResponse{
 		User: models.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			TenantID:  user.TenantID,
-			Role:      user.Role,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Locale:    user.Locale,
+			ID:          user.ID,
+			Email:       user.Email,
+			TenantID:    user.TenantID,
+			Role:        user.Role,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Locale:      user.Locale,
+			Permissions: access.Permissions,
+			CustomRole:  access.CustomRoleName,
+			OutletID:    access.OutletID,
+			AvatarURL:   s.avatars.URL(ctx, user.TenantID, user.ID),
 		},
 		Message: "Login successful",
 	}
@@ -272,6 +364,171 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, ipAdd
 	return response, token, nil
 }
 
+// enforceSessionLimit makes room for one more session of the user under the tenant's concurrent
+// session limit, or returns a SessionLimitError. Like the anomaly check it fails open.
+func (s *AuthService) enforceSessionLimit(ctx context.Context, user *models.User, ipAddress, userAgent string) error {
+	evict, err := s.sessionLimits.Check(ctx, user)
+	if err != nil {
+		if _, ok := err.(*SessionLimitError); ok {
+			return err
+		}
+		log.Debug().Msgf("Warning: failed to check session limit: %v\n", err)
+		return nil
+	}
+
+	for _, sessionID := range evict {
+		if err := s.Logout(ctx, sessionID); err != nil {
+			log.Debug().Msgf("Warning: failed to evict session over the limit: %v\n", err)
+			continue
+		}
+		if s.auditPublisher != nil {
+			evictedID := sessionID
+			auditEvent := &utils.AuditEvent{
+				TenantID:     user.TenantID,
+				ActorType:    "system",
+				SessionID:    &evictedID,
+				Action:       "LOGOUT",
+				ResourceType: "user",
+				ResourceID:   user.ID,
+				IPAddress:    &ipAddress,
+				UserAgent:    &userAgent,
+				Metadata: map[string]interface{}{
+					"event":  "session_evicted",
+					"reason": "session_limit",
+				},
+			}
+			if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+				log.Debug().Msgf("Failed to publish session eviction audit event: %v\n", err)
+			}
+		}
+		log.Info().Str("tenant_id", user.TenantID).Str("user_id", user.ID).Msg("Oldest session evicted: concurrent session limit reached")
+	}
+	return nil
+}
+
+// LoginWithPasskey starts a session for a user whose passkey assertion was verified
+func (s *AuthService) LoginWithPasskey(ctx context.Context, tenantID, userID, ipAddress, userAgent string) (*models.LoginResponse, string, error) {
+	if err := s.checkSSOOnly(ctx, tenantID); err != nil {
+		return nil, "", err
+	}
+	return s.loginVerifiedUser(ctx, tenantID, userID, ipAddress, userAgent, "passkey")
+}
+
+// LoginWithMagicLink starts a session for a user who opened a login link sent to their email
+func (s *AuthService) LoginWithMagicLink(ctx context.Context, tenantID, userID, ipAddress, userAgent string) (*models.LoginResponse, string, error) {
+	if err := s.checkSSOOnly(ctx, tenantID); err != nil {
+		return nil, "", err
+	}
+	return s.loginVerifiedUser(ctx, tenantID, userID, ipAddress, userAgent, "magic_link")
+}
+
+// LoginAfterPasswordChange starts the session a password login was held back for until it set a new password
+func (s *AuthService) LoginAfterPasswordChange(ctx context.Context, tenantID, userID, ipAddress, userAgent string) (*models.LoginResponse, string, error) {
+	return s.loginVerifiedUser(ctx, tenantID, userID, ipAddress, userAgent, "password")
+}
+
+// LoginWithSSO starts a session for a user whose identity provider account was verified
+func (s *AuthService) LoginWithSSO(ctx context.Context, tenantID, userID, provider, ipAddress, userAgent string) (*models.LoginResponse, string, error) {
+	return s.loginVerifiedUser(ctx, tenantID, userID, ipAddress, userAgent, provider)
+}
+
+// loginVerifiedUser starts a session for a user authenticated by other means than a password
+func (s *AuthService) loginVerifiedUser(ctx context.Context, tenantID, userID, ipAddress, userAgent, loginMethod string) (*models.LoginResponse, string, error) {
+	user, err := s.getUserByID(ctx, tenantID, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("authentication failed: %w", err)
+	}
+	if user == nil {
+		return nil, "", ErrInvalidCredentials
+	}
+	if user.Status != "active" {
+		return nil, "", &UserStatusError{Status: user.Status}
+	}
+
+	return s.startSession(ctx, user, ipAddress, userAgent, loginMethod)
+}
+
+// ReauthenticatePassword checks the password of a logged-in user before a sensitive operation
+// Wrong passwords count toward the account lockout just like failed logins.
+func (s *AuthService) ReauthenticatePassword(ctx context.Context, session *models.SessionData, password, ipAddress, userAgent string) error {
+	user, err := s.getUserByEmailAndTenant(ctx, session.Email, session.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil || user.ID != session.UserID {
+		return ErrInvalidCredentials
+	}
+	if err := s.lockout.Check(user); err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		lockErr := s.lockout.RecordFailure(ctx, user, ipAddress, userAgent)
+		if _, ok := lockErr.(*AccountLockedError); ok {
+			return lockErr
+		}
+		if lockErr != nil {
+			log.Debug().Msgf("Warning: failed to record failed re-authentication: %v\n", lockErr)
+		}
+		return ErrInvalidCredentials
+	}
+
+	if user.Status != "active" {
+		return &UserStatusError{Status: user.Status}
+	}
+	return nil
+}
+
+// checkSSOOnly refuses logins that bypass single sign-on for tenants that enforce it
+func (s *AuthService) checkSSOOnly(ctx context.Context, tenantID string) error {
+	settings, err := s.ssoRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get SSO settings: %w", err)
+	}
+	if settings.SSOOnly {
+		return ErrSSORequired
+	}
+	return nil
+}
+
+// ResolveAccess returns the permissions a user holds right now
+// Owners always hold every permission of their role. Other users get the permissions of
+// their custom role when one is assigned; permissions a custom role may not grant are
+// dropped rather than trusted.
+func (s *AuthService) ResolveAccess(ctx context.Context, tenantID, userID, role string) (*models.AccessGrant, error) {
+	if role == "owner" {
+		return models.RoleAccess(role), nil
+	}
+
+	access, err := s.roleRepo.GetUserCustomRole(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load custom role: %w", err)
+	}
+	if access == nil {
+		access = models.RoleAccess(role)
+	} else {
+		permissions := make([]string, 0, len(access.Permissions))
+		for _, permission := range access.Permissions {
+			if models.IsAssignablePermission(permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+		access.Permissions = permissions
+	}
+
+	// Owners see every outlet; other staff are scoped to the outlet they work at
+	access.OutletID, err = s.roleRepo.GetUserOutlet(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load outlet: %w", err)
+	}
+	return access, nil
+}
+
+// AvatarURL returns a presigned URL to the user's avatar, or an empty string
+func (s *AuthService) AvatarURL(ctx context.Context, tenantID, userID string) string {
+	return s.avatars.URL(ctx, tenantID, userID)
+}
+
 // ValidateSession validates a session and returns session data
 func (s *AuthService) ValidateSession(ctx context.Context, sessionID string) (*models.SessionData, error) {
 	// Check if session exists in Redis
@@ -296,12 +553,21 @@ func (s *AuthService) ValidateSession(ctx context.Context, sessionID string) (*m
 
 // Logout terminates a session
 func (s *AuthService) Logout(ctx context.Context, sessionID string) error {
+	sessionData, err := s.sessionManager.Get(ctx, sessionID)
+	if err != nil {
+		log.Debug().Msgf("Warning: failed to read session before logout: %v\n", err)
+	}
+
 	// Delete from Redis
-	err := s.sessionManager.Delete(ctx, sessionID)
+	err = s.sessionManager.Delete(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("failed to delete session from Redis: %w", err)
 	}
 
+	if sessionData != nil && sessionData.Impersonation != nil {
+		s.auditImpersonationStopped(ctx, sessionID, sessionData)
+	}
+
 	// Mark as terminated in PostgreSQL
 	err = s.sessionRepo.Delete(ctx, sessionID)
 	if err != nil {
@@ -349,7 +615,8 @@ func (s *AuthService) getUserByEmailAndTenant(ctx context.Context, email, tenant
 	}
 
 	query := `
-		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale
+		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale, locked_until,
+			must_change_password, password_changed_at
 		FROM users
 		WHERE tenant_id = $1 AND email = $2 AND status = 'active'
 		LIMIT 1
@@ -370,6 +637,9 @@ func (s *AuthService) getUserByEmailAndTenant(ctx context.Context, email, tenant
 		&firstName,
 		&lastName,
 		&user.Locale,
+		&user.LockedUntil,
+		&user.MustChangePassword,
+		&user.PasswordChangedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -382,11 +652,58 @@ func (s *AuthService) getUserByEmailAndTenant(ctx context.Context, email, tenant
 		return nil, fmt.Errorf("failed to query user: %w", err)
 	}
 
+	if err := s.decryptUser(ctx, user, encryptedEmail, firstName, lastName); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Msgf("DEBUG: User found and verified\n")
+	return user, nil
+}
+
+// getUserByID loads a user of a tenant whatever their status, for logins that did not start from an email
+func (s *AuthService) getUserByID(ctx context.Context, tenantID, userID string) (*models.User, error) {
+	query := `
+		SELECT id, tenant_id, email, role, status, first_name, last_name, locale
+		FROM users
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	user := &models.User{}
+	var firstName, lastName sql.NullString
+	var encryptedEmail string
+
+	err := s.db.QueryRowContext(ctx, query, userID, tenantID).Scan(
+		&user.ID,
+		&user.TenantID,
+		&encryptedEmail,
+		&user.Role,
+		&user.Status,
+		&firstName,
+		&lastName,
+		&user.Locale,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	if err := s.decryptUser(ctx, user, encryptedEmail, firstName, lastName); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// decryptUser fills in a user's email and names from their encrypted columns
+// Names that cannot be decrypted are left empty; the email is required.
+func (s *AuthService) decryptUser(ctx context.Context, user *models.User, encryptedEmail string, firstName, lastName sql.NullString) error {
+	var err error
 	// Decrypt email
 	user.Email, err = s.encryptor.DecryptWithContext(ctx, encryptedEmail, "user:email")
 	if err != nil {
 		log.Debug().Msgf("DEBUG: Failed to decrypt email: %v\n", err)
-		return nil, fmt.Errorf("failed to decrypt email: %w", err)
+		return fmt.Errorf("failed to decrypt email: %w", err)
 	}
 
 	// Decrypt first_name if present
@@ -411,8 +728,7 @@ func (s *AuthService) getUserByEmailAndTenant(ctx context.Context, email, tenant
 		}
 	}
 
-	log.Debug().Msgf("DEBUG: User found and verified\n")
-	return user, nil
+	return nil
 }
 
 func (s *AuthService) getTenantIDByEmail(ctx context.Context, email string) (string, error) {
@@ -441,8 +757,13 @@ func (s *AuthService) getTenantIDByEmail(ctx context.Context, email string) (str
 	return tenantID, nil
 }
 
+// updateLastLogin records a successful login, which also clears failed password logins
 func (s *AuthService) updateLastLogin(ctx context.Context, userID string) {
-	query := `UPDATE users SET last_login_at = $1 WHERE id = $2`
+	query := `
+		UPDATE users
+		SET last_login_at = $1, failed_login_attempts = 0, last_failed_login_at = NULL, locked_until = NULL
+		WHERE id = $2
+	`
 	_, err := s.db.ExecContext(ctx, query, time.Now(), userID)
 	if err != nil {
 		// Non-fatal error
@@ -456,6 +777,7 @@ var (
 	ErrInvalidCredentials    = fmt.Errorf("invalid email or password")
 	ErrSessionNotFound       = fmt.Errorf("session not found")
 	ErrInvalidOrExpiredToken = fmt.Errorf("invalid or expired token")
+	ErrSSORequired           = fmt.Errorf("tenant requires single sign-on")
 )
 
 type RateLimitError struct {
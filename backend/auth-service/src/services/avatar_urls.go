@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// AvatarURLs presigns the avatars user-service stores, for login and session responses
+// A nil AvatarURLs, used when no object storage is configured, returns no URLs.
+type AvatarURLs struct {
+	db     *sql.DB
+	client *minio.Client
+	bucket string
+	urlTTL time.Duration
+}
+
+// NewAvatarURLs reads the optional S3_* variables shared with user-service; without them it returns nil
+func NewAvatarURLs(db *sql.DB) (*AvatarURLs, error) {
+	endpoint := os.Getenv("S3_ENDPOINT")
+	bucket := os.Getenv("S3_BUCKET_NAME")
+	accessKey := os.Getenv("S3_ACCESS_KEY")
+	secretKey := os.Getenv("S3_SECRET_KEY")
+	if endpoint == "" || bucket == "" || accessKey == "" || secretKey == "" {
+		return nil, nil
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: os.Getenv("S3_USE_SSL") == "true",
+		Region: os.Getenv("S3_REGION"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	return &AvatarURLs{
+		db:     db,
+		client: client,
+		bucket: bucket,
+		urlTTL: time.Duration(utils.GetEnvInt("AVATAR_URL_TTL_SECONDS")) * time.Second,
+	}, nil
+}
+
+// URL returns a presigned URL to the user's avatar, or an empty string when they have none
+// Failures are logged and leave the URL out rather than failing the login or session check.
+func (a *AvatarURLs) URL(ctx context.Context, tenantID, userID string) string {
+	if a == nil {
+		return ""
+	}
+
+	var storageKey sql.NullString
+	err := a.db.QueryRowContext(ctx, `
+		SELECT avatar_storage_key FROM users WHERE id = $1 AND tenant_id = $2
+	`, userID, tenantID).Scan(&storageKey)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Warn().Err(err).Str("user_id", userID).Msg("Failed to read avatar")
+		}
+		return ""
+	}
+	if !storageKey.Valid {
+		return ""
+	}
+
+	url, err := a.client.PresignedGetObject(ctx, a.bucket, storageKey.String, a.urlTTL, nil)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", userID).Msg("Failed to presign avatar URL")
+		return ""
+	}
+	return url.String()
+}
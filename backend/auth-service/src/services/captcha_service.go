@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// CaptchaService decides whether a login or password reset must carry a solved CAPTCHA and checks it
+// CAPTCHA is switched on per environment (CAPTCHA_ENABLED) and can then be turned off per tenant.
+// Emails that belong to no tenant always need one, so probing unknown addresses costs the same.
+type CaptchaService struct {
+	captchaRepo    *repository.CaptchaRepository
+	verifier       *utils.CaptchaVerifier
+	authService    *AuthService
+	auditPublisher *utils.AuditPublisher
+}
+
+func NewCaptchaService(captchaRepo *repository.CaptchaRepository, verifier *utils.CaptchaVerifier, authService *AuthService, auditPublisher *utils.AuditPublisher) *CaptchaService {
+	return &CaptchaService{
+		captchaRepo:    captchaRepo,
+		verifier:       verifier,
+		authService:    authService,
+		auditPublisher: auditPublisher,
+	}
+}
+
+// Config returns what the frontend needs to render the CAPTCHA widget
+func (s *CaptchaService) Config() *utils.CaptchaVerifier {
+	return s.verifier
+}
+
+// VerifyForEmail checks the CAPTCHA token of a request made for the account with this email
+func (s *CaptchaService) VerifyForEmail(ctx context.Context, email, token, remoteIP string) error {
+	if !s.verifier.Enabled {
+		return nil
+	}
+
+	tenantID, err := s.authService.getTenantIDByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to lookup tenant: %w", err)
+	}
+	if tenantID != "" {
+		settings, err := s.captchaRepo.GetSettings(ctx, tenantID)
+		if err != nil {
+			return fmt.Errorf("failed to get CAPTCHA settings: %w", err)
+		}
+		if !settings.Enabled {
+			return nil
+		}
+	}
+
+	return s.verifier.Verify(ctx, token, remoteIP)
+}
+
+// GetSettings returns the CAPTCHA policy of the logged-in user's tenant
+func (s *CaptchaService) GetSettings(ctx context.Context, session *models.SessionData) (*models.TenantCaptchaSettings, error) {
+	return s.captchaRepo.GetSettings(ctx, session.TenantID)
+}
+
+// UpdateSettings changes the CAPTCHA policy of the logged-in owner's tenant
+func (s *CaptchaService) UpdateSettings(ctx context.Context, session *models.SessionData, req *models.UpdateCaptchaSettingsRequest, ipAddress, userAgent string) (*models.TenantCaptchaSettings, error) {
+	previous, err := s.captchaRepo.GetSettings(ctx, session.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CAPTCHA settings: %w", err)
+	}
+
+	userID := session.UserID
+	settings := &models.TenantCaptchaSettings{
+		TenantID:  session.TenantID,
+		Enabled:   req.Enabled,
+		UpdatedBy: &userID,
+	}
+	if err := s.captchaRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save CAPTCHA settings: %w", err)
+	}
+
+	if s.auditPublisher != nil {
+		auditEvent := &utils.AuditEvent{
+			TenantID:     session.TenantID,
+			ActorType:    "user",
+			ActorID:      &userID,
+			Action:       "UPDATE",
+			ResourceType: "captcha_settings",
+			ResourceID:   session.TenantID,
+			IPAddress:    &ipAddress,
+			UserAgent:    &userAgent,
+			BeforeValue:  map[string]interface{}{"enabled": previous.Enabled},
+			AfterValue:   map[string]interface{}{"enabled": settings.Enabled},
+		}
+		if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+			log.Debug().Msgf("Failed to publish CAPTCHA settings audit event: %v\n", err)
+		}
+	}
+	return settings, nil
+}
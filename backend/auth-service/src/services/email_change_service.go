@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/queue"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// maxEmailChangeRequests is how many email changes a user can start within an hour
+const maxEmailChangeRequests = 3
+
+var (
+	ErrEmailChangeInvalid   = errors.New("new email is not a valid address")
+	ErrEmailChangeUnchanged = errors.New("new email is the current one")
+	ErrEmailChangeInUse     = errors.New("new email is already used by another account")
+	ErrEmailChangeTooMany   = errors.New("too many email change requests")
+	ErrEmailChangeLink      = errors.New("email change link is invalid or expired")
+)
+
+// EmailChangeService changes a user's login email in two steps
+// The current address first confirms the change, so a hijacked session alone cannot move the
+// account; the new address then proves it belongs to the user. Only then are the encrypted email
+// and its search hash replaced, and the user's sessions ended.
+type EmailChangeService struct {
+	changeRepo     *repository.EmailChangeRepository
+	authService    *AuthService
+	sessionManager *SessionManager
+	eventPublisher *queue.EventPublisher
+	auditPublisher *utils.AuditPublisher
+	ttl            time.Duration
+}
+
+func NewEmailChangeService(changeRepo *repository.EmailChangeRepository, authService *AuthService, sessionManager *SessionManager, eventPublisher *queue.EventPublisher, auditPublisher *utils.AuditPublisher, ttlHours int) *EmailChangeService {
+	return &EmailChangeService{
+		changeRepo:     changeRepo,
+		authService:    authService,
+		sessionManager: sessionManager,
+		eventPublisher: eventPublisher,
+		auditPublisher: auditPublisher,
+		ttl:            time.Duration(ttlHours) * time.Hour,
+	}
+}
+
+func emailChangeTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Request starts changing the logged-in user's email and sends the confirmation link to the current address
+func (s *EmailChangeService) Request(ctx context.Context, session *models.SessionData, newEmail, ipAddress, userAgent string) (*models.EmailChange, error) {
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	if address, err := mail.ParseAddress(newEmail); err != nil || address.Address != newEmail {
+		return nil, ErrEmailChangeInvalid
+	}
+
+	user, err := s.authService.getUserByID(ctx, session.TenantID, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrSessionNotFound
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return nil, ErrEmailChangeUnchanged
+	}
+
+	encryptedEmail, err := s.authService.encryptor.EncryptWithContext(ctx, newEmail, "user:email")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt new email: %w", err)
+	}
+	inUse, err := s.changeRepo.EmailInUse(ctx, encryptedEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check new email: %w", err)
+	}
+	if inUse {
+		return nil, ErrEmailChangeInUse
+	}
+
+	recent, err := s.changeRepo.CountRecent(ctx, user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count email change requests: %w", err)
+	}
+	if recent >= maxEmailChangeRequests {
+		return nil, ErrEmailChangeTooMany
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	change := &models.EmailChange{
+		TenantID:  user.TenantID,
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		Status:    models.EmailChangePendingConfirmation,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := s.changeRepo.Create(ctx, change, encryptedEmail, emailChangeTokenHash(token)); err != nil {
+		return nil, fmt.Errorf("failed to store email change request: %w", err)
+	}
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if err := s.eventPublisher.PublishEmailChangeConfirmRequested(ctx, user.TenantID, user.ID, user.Email, name, newEmail, token, int(s.ttl.Hours())); err != nil {
+		return nil, fmt.Errorf("failed to publish email change confirmation event: %w", err)
+	}
+
+	s.publishAudit(ctx, user.TenantID, user.ID, "CREATE", "email_change", change.ID, ipAddress, userAgent, nil,
+		map[string]interface{}{"new_email": encryptedEmail, "status": change.Status})
+	log.Info().Str("tenant_id", user.TenantID).Str("user_id", user.ID).Msg("Email change requested")
+	return change, nil
+}
+
+// Confirm accepts the change from the link sent to the current address and sends the
+// verification link to the new one
+func (s *EmailChangeService) Confirm(ctx context.Context, token, ipAddress, userAgent string) (*models.EmailChange, error) {
+	change, err := s.pending(ctx, token, models.EmailChangePendingConfirmation)
+	if err != nil {
+		return nil, err
+	}
+	encryptedEmail := change.NewEmail
+	change.NewEmail, err = s.authService.encryptor.DecryptWithContext(ctx, encryptedEmail, "user:email")
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt new email: %w", err)
+	}
+
+	user, err := s.authService.getUserByID(ctx, change.TenantID, change.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrEmailChangeLink
+	}
+
+	verifyToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	change.ExpiresAt = time.Now().Add(s.ttl)
+	if err := s.changeRepo.MarkConfirmed(ctx, change, emailChangeTokenHash(verifyToken)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEmailChangeLink
+		}
+		return nil, fmt.Errorf("failed to confirm email change: %w", err)
+	}
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if err := s.eventPublisher.PublishEmailChangeVerifyRequested(ctx, change.TenantID, change.UserID, change.NewEmail, name, verifyToken, int(s.ttl.Hours())); err != nil {
+		return nil, fmt.Errorf("failed to publish email change verification event: %w", err)
+	}
+
+	s.publishAudit(ctx, change.TenantID, change.UserID, "UPDATE", "email_change", change.ID, ipAddress, userAgent,
+		map[string]interface{}{"status": models.EmailChangePendingConfirmation},
+		map[string]interface{}{"new_email": encryptedEmail, "status": change.Status})
+	return change, nil
+}
+
+// Verify completes the change from the link sent to the new address
+// The user's sessions are ended so every device signs in again with the new email.
+func (s *EmailChangeService) Verify(ctx context.Context, token, ipAddress, userAgent string) (*models.EmailChange, error) {
+	change, err := s.pending(ctx, token, models.EmailChangePendingVerification)
+	if err != nil {
+		return nil, err
+	}
+	encryptedEmail := change.NewEmail
+	change.NewEmail, err = s.authService.encryptor.DecryptWithContext(ctx, encryptedEmail, "user:email")
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt new email: %w", err)
+	}
+
+	// Loaded before the change so the alert can go to the previous address
+	user, err := s.authService.getUserByID(ctx, change.TenantID, change.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrEmailChangeLink
+	}
+
+	previousEmail, err := s.changeRepo.Complete(ctx, change, encryptedEmail, utils.HashForSearch(change.NewEmail))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEmailInUse):
+			return nil, ErrEmailChangeInUse
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrEmailChangeLink
+		}
+		return nil, fmt.Errorf("failed to change email: %w", err)
+	}
+
+	s.publishAudit(ctx, change.TenantID, change.UserID, "UPDATE", "user", change.UserID, ipAddress, userAgent,
+		map[string]interface{}{"email": previousEmail},
+		map[string]interface{}{"email": encryptedEmail})
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if err := s.eventPublisher.PublishEmailChanged(ctx, change.TenantID, change.UserID, user.Email, name, change.NewEmail); err != nil {
+		log.Error().Err(err).Str("user_id", change.UserID).Msg("Failed to publish email changed event")
+	}
+
+	s.endSessions(ctx, change.UserID)
+	log.Info().Str("tenant_id", change.TenantID).Str("user_id", change.UserID).Msg("Email changed")
+	return change, nil
+}
+
+// pending returns the unexpired request waiting at the given step for this token
+func (s *EmailChangeService) pending(ctx context.Context, token, status string) (*models.EmailChange, error) {
+	if token == "" {
+		return nil, ErrEmailChangeLink
+	}
+	change, err := s.changeRepo.FindPending(ctx, emailChangeTokenHash(token), status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email change request: %w", err)
+	}
+	if change == nil {
+		return nil, ErrEmailChangeLink
+	}
+	return change, nil
+}
+
+// endSessions logs the user out everywhere; sessions carry the email they were started with
+func (s *EmailChangeService) endSessions(ctx context.Context, userID string) {
+	sessionIDs, err := s.sessionManager.ActiveSessions(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID).Msg("Failed to list sessions after email change")
+		return
+	}
+	for _, sessionID := range sessionIDs {
+		if err := s.authService.Logout(ctx, sessionID); err != nil {
+			log.Error().Err(err).Str("user_id", userID).Msg("Failed to end session after email change")
+		}
+	}
+}
+
+func (s *EmailChangeService) publishAudit(ctx context.Context, tenantID, userID, action, resourceType, resourceID, ipAddress, userAgent string, before, after map[string]interface{}) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+		BeforeValue:  before,
+		AfterValue:   after,
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish email change audit event: %v\n", err)
+	}
+}
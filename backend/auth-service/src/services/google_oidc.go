@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Google's OpenID Connect endpoints (https://accounts.google.com/.well-known/openid-configuration)
+const (
+	googleAuthEndpoint  = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenEndpoint = "https://oauth2.googleapis.com/token"
+	googleJWKSEndpoint  = "https://www.googleapis.com/oauth2/v3/certs"
+
+	googleKeysTTL          = time.Hour
+	googleKeysRefreshDelay = time.Minute
+)
+
+var googleIssuers = []string{"https://accounts.google.com", "accounts.google.com"}
+
+var ErrSSOVerification = errors.New("identity provider response could not be verified")
+
+// GoogleOIDCConfig is the OAuth client registered in Google Cloud Console
+type GoogleOIDCConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // Must match an authorized redirect URI of the client
+}
+
+// googleIDClaims are the ID token claims the login relies on
+type googleIDClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// googleOIDCClient runs the authorization code flow against Google
+type googleOIDCClient struct {
+	config     GoogleOIDCConfig
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	keysExpiry  time.Time
+	keysFetched time.Time
+}
+
+func newGoogleOIDCClient(config GoogleOIDCConfig) *googleOIDCClient {
+	return &googleOIDCClient{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// authURL is where the browser is sent to sign in with Google
+func (g *googleOIDCClient) authURL(state, nonce, codeChallenge string) string {
+	params := url.Values{
+		"client_id":             {g.config.ClientID},
+		"redirect_uri":          {g.config.RedirectURL},
+		"response_type":         {"code"},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+		"prompt":                {"select_account"},
+	}
+	return googleAuthEndpoint + "?" + params.Encode()
+}
+
+// exchangeCode trades the callback's authorization code for an ID token
+func (g *googleOIDCClient) exchangeCode(ctx context.Context, code, codeVerifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {g.config.RedirectURL},
+		"client_id":     {g.config.ClientID},
+		"client_secret": {g.config.ClientSecret},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, googleTokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		// An invalid or replayed code is the user's problem, not an outage
+		if resp.StatusCode == http.StatusBadRequest {
+			return "", fmt.Errorf("%w: token endpoint rejected the code: %s", ErrSSOVerification, body)
+		}
+		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, body)
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil || token.IDToken == "" {
+		return "", fmt.Errorf("%w: token response has no ID token", ErrSSOVerification)
+	}
+	return token.IDToken, nil
+}
+
+// verifyIDToken checks the ID token's signature, issuer, audience, expiry and nonce
+func (g *googleOIDCClient) verifyIDToken(ctx context.Context, raw, nonce string) (*googleIDClaims, error) {
+	claims := &googleIDClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+	_, err := parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return g.signingKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSSOVerification, err)
+	}
+
+	issuerValid := false
+	for _, issuer := range googleIssuers {
+		if claims.VerifyIssuer(issuer, true) {
+			issuerValid = true
+			break
+		}
+	}
+	if !issuerValid {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrSSOVerification, claims.Issuer)
+	}
+	if !claims.VerifyAudience(g.config.ClientID, true) {
+		return nil, fmt.Errorf("%w: token was issued to another client", ErrSSOVerification)
+	}
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrSSOVerification)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrSSOVerification)
+	}
+	return claims, nil
+}
+
+// signingKey returns Google's public key with the given ID, refetching the key set when
+// it expired or when Google rotated to a key that is not cached yet
+func (g *googleOIDCClient) signingKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key, ok := g.keys[kid]
+	stale := time.Now().After(g.keysExpiry)
+	if ok && !stale {
+		return key, nil
+	}
+	if !stale && time.Since(g.keysFetched) < googleKeysRefreshDelay {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := g.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g.keys = keys
+	g.keysFetched = time.Now()
+	g.keysExpiry = g.keysFetched.Add(googleKeysTTL)
+
+	if key, ok := g.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (g *googleOIDCClient) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, googleJWKSEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signing keys endpoint returned %d", resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("failed to decode signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, err := decodeBase64URL(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := decodeBase64URL(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+	}
+	return keys, nil
+}
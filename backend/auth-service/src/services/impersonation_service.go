@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const maxImpersonationReasonLength = 500
+
+var (
+	ErrOperatorUnauthorized  = errors.New("invalid platform operator key")
+	ErrImpersonationReason   = errors.New("impersonation reason is required")
+	ErrImpersonationTarget   = errors.New("impersonation target not found")
+	ErrNotImpersonating      = errors.New("session is not an impersonation")
+	ErrImpersonationDuration = errors.New("impersonation duration out of range")
+)
+
+// ImpersonationService lets platform operators act as a tenant user for support
+// Impersonation sessions are flagged in the session and JWT, expire after at most the
+// configured maximum, and are never renewed. Starting and stopping one is audited with the
+// operator as actor, and the gateway stamps the operator on every downstream request.
+type ImpersonationService struct {
+	operatorRepo   *repository.PlatformOperatorRepository
+	authService    *AuthService
+	sessionManager *SessionManager
+	jwtService     *JWTService
+	auditPublisher *utils.AuditPublisher
+	maxDuration    time.Duration
+}
+
+func NewImpersonationService(
+	operatorRepo *repository.PlatformOperatorRepository,
+	authService *AuthService,
+	sessionManager *SessionManager,
+	jwtService *JWTService,
+	auditPublisher *utils.AuditPublisher,
+	maxDurationMinutes int,
+) *ImpersonationService {
+	return &ImpersonationService{
+		operatorRepo:   operatorRepo,
+		authService:    authService,
+		sessionManager: sessionManager,
+		jwtService:     jwtService,
+		auditPublisher: auditPublisher,
+		maxDuration:    time.Duration(maxDurationMinutes) * time.Minute,
+	}
+}
+
+// AuthenticateOperator resolves the operator owning an API key
+func (s *ImpersonationService) AuthenticateOperator(ctx context.Context, apiKey string) (*models.PlatformOperator, error) {
+	if apiKey == "" {
+		return nil, ErrOperatorUnauthorized
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	operator, err := s.operatorRepo.FindActiveByAPIKeyHash(ctx, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up platform operator: %w", err)
+	}
+	if operator == nil {
+		return nil, ErrOperatorUnauthorized
+	}
+	return operator, nil
+}
+
+// Start opens an impersonation session as the requested user and returns its token
+func (s *ImpersonationService) Start(ctx context.Context, operator *models.PlatformOperator, req *models.StartImpersonationRequest, ipAddress, userAgent string) (*models.ImpersonationResponse, string, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || len(reason) > maxImpersonationReasonLength {
+		return nil, "", ErrImpersonationReason
+	}
+
+	duration := s.maxDuration
+	if req.DurationMinutes != 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+		if duration <= 0 || duration > s.maxDuration {
+			return nil, "", ErrImpersonationDuration
+		}
+	}
+
+	if req.TenantID == "" || req.UserID == "" {
+		return nil, "", ErrImpersonationTarget
+	}
+	user, err := s.authService.getUserByID(ctx, req.TenantID, req.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if user == nil || user.Status != "active" {
+		return nil, "", ErrImpersonationTarget
+	}
+
+	access, err := s.authService.ResolveAccess(ctx, user.TenantID, user.ID, user.Role)
+	if err != nil {
+		return nil, "", err
+	}
+
+	impersonation := &models.Impersonation{
+		OperatorID:    operator.ID,
+		OperatorEmail: operator.Email,
+		Reason:        reason,
+		ExpiresAt:     time.Now().Add(duration),
+	}
+	sessionData, sessionID, err := s.sessionManager.CreateImpersonation(ctx, user, impersonation)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create impersonation session: %w", err)
+	}
+
+	// Record the session alongside regular ones so it shows in the user's session history
+	session := &models.Session{
+		SessionID: sessionID,
+		TenantID:  user.TenantID,
+		UserID:    user.ID,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		ExpiresAt: impersonation.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.authService.sessionRepo.Create(ctx, session); err != nil {
+		log.Debug().Msgf("Warning: failed to create impersonation session record: %v\n", err)
+	}
+
+	token, err := s.jwtService.GenerateImpersonation(sessionID, sessionData, access)
+	if err != nil {
+		s.sessionManager.Delete(ctx, sessionID)
+		return nil, "", err
+	}
+
+	publishImpersonationAudit(ctx, s.auditPublisher, "LOGIN", sessionID, sessionData, ipAddress, userAgent, map[string]interface{}{
+		"event":      "impersonation_started",
+		"reason":     reason,
+		"expires_at": impersonation.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+	log.Warn().Str("operator_id", operator.ID).Str("tenant_id", user.TenantID).Str("user_id", user.ID).
+		Time("expires_at", impersonation.ExpiresAt).Msg("Impersonation started")
+
+	response := &models.ImpersonationResponse{
+		User: models.UserInfo{
+			ID:             user.ID,
+			Email:          user.Email,
+			TenantID:       user.TenantID,
+			Role:           user.Role,
+			FirstName:      user.FirstName,
+			LastName:       user.LastName,
+			Locale:         user.Locale,
+			Permissions:    access.Permissions,
+			CustomRole:     access.CustomRoleName,
+			OutletID:       access.OutletID,
+			ImpersonatedBy: impersonation,
+		},
+		ExpiresAt: impersonation.ExpiresAt,
+	}
+	return response, token, nil
+}
+
+// Stop ends an impersonation session before it expires
+func (s *ImpersonationService) Stop(ctx context.Context, sessionID string) error {
+	sessionData, err := s.authService.ValidateSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if sessionData.Impersonation == nil {
+		return ErrNotImpersonating
+	}
+	return s.authService.Logout(ctx, sessionID)
+}
+
+// auditImpersonationStopped records the end of an impersonation session, whichever way it was ended
+func (s *AuthService) auditImpersonationStopped(ctx context.Context, sessionID string, sessionData *models.SessionData) {
+	started := time.Unix(sessionData.CreatedAt, 0)
+	publishImpersonationAudit(ctx, s.auditPublisher, "LOGOUT", sessionID, sessionData, "", "", map[string]interface{}{
+		"event":            "impersonation_stopped",
+		"duration_seconds": int(time.Since(started).Seconds()),
+	})
+	log.Warn().Str("operator_id", sessionData.Impersonation.OperatorID).Str("tenant_id", sessionData.TenantID).
+		Str("user_id", sessionData.UserID).Msg("Impersonation stopped")
+}
+
+// publishImpersonationAudit records an impersonation event against the impersonated user, with the operator as actor
+func publishImpersonationAudit(ctx context.Context, auditPublisher *utils.AuditPublisher, action, sessionID string, sessionData *models.SessionData, ipAddress, userAgent string, metadata map[string]interface{}) {
+	if auditPublisher == nil {
+		return
+	}
+	operatorID := sessionData.Impersonation.OperatorID
+	metadata["operator_id"] = operatorID
+	metadata["login_method"] = "impersonation"
+	auditEvent := &utils.AuditEvent{
+		TenantID:     sessionData.TenantID,
+		ActorType:    "admin",
+		ActorID:      &operatorID,
+		SessionID:    &sessionID,
+		Action:       action,
+		ResourceType: "user",
+		ResourceID:   sessionData.UserID,
+		Metadata:     metadata,
+	}
+	if ipAddress != "" {
+		auditEvent.IPAddress = &ipAddress
+	}
+	if userAgent != "" {
+		auditEvent.UserAgent = &userAgent
+	}
+	if err := auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish impersonation audit event: %v\n", err)
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pos/auth-service/src/models"
+	"github.com/rs/zerolog/log"
+)
+
+// IntrospectionService lets backend services validate session tokens without the JWT secret
+// A token is active while its signature and expiry hold and its session still exists, so logged
+// out and terminated sessions turn inactive. Results are cached in Redis under the token's SHA-256
+// hash for at most cacheTTL, which bounds how long a revocation takes to reach callers.
+type IntrospectionService struct {
+	redis          *redis.Client
+	jwtService     *JWTService
+	sessionManager *SessionManager
+	clients        map[string]string
+	cacheTTL       time.Duration
+}
+
+// NewIntrospectionService takes the allowed clients as comma-separated client_id:secret pairs
+func NewIntrospectionService(redisClient *redis.Client, jwtService *JWTService, sessionManager *SessionManager, clients string, cacheTTLSeconds int) *IntrospectionService {
+	return &IntrospectionService{
+		redis:          redisClient,
+		jwtService:     jwtService,
+		sessionManager: sessionManager,
+		clients:        parseIntrospectionClients(clients),
+		cacheTTL:       time.Duration(cacheTTLSeconds) * time.Second,
+	}
+}
+
+func parseIntrospectionClients(clients string) map[string]string {
+	parsed := make(map[string]string)
+	for _, pair := range strings.Split(clients, ",") {
+		clientID, secret, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || clientID == "" || secret == "" {
+			continue
+		}
+		parsed[clientID] = secret
+	}
+	return parsed
+}
+
+// AuthenticateClient reports whether the caller is a configured backend service
+func (s *IntrospectionService) AuthenticateClient(clientID, secret string) bool {
+	expected, ok := s.clients[clientID]
+	if !ok {
+		// Compare anyway so unknown clients take as long as wrong secrets
+		expected = strings.Repeat("x", len(secret)+1)
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(secret)) == 1 && ok
+}
+
+// Introspect describes a token; errors reading the cache or sessions make the token inactive
+func (s *IntrospectionService) Introspect(ctx context.Context, clientID, token string) *models.IntrospectionResponse {
+	inactive := &models.IntrospectionResponse{Active: false}
+	if token == "" {
+		return inactive
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	cacheKey := "introspection:" + hex.EncodeToString(sum[:])
+	if cached, err := s.redis.Get(ctx, cacheKey).Bytes(); err == nil {
+		var response models.IntrospectionResponse
+		if err := json.Unmarshal(cached, &response); err == nil {
+			if !response.Active || time.Now().Unix() < response.ExpiresAt {
+				response.ClientID = clientID
+				return &response
+			}
+		}
+	}
+
+	response := s.introspect(ctx, token)
+	s.cache(ctx, cacheKey, response)
+	if response.Active {
+		response.ClientID = clientID
+	}
+	return response
+}
+
+func (s *IntrospectionService) introspect(ctx context.Context, token string) *models.IntrospectionResponse {
+	claims, err := s.jwtService.Validate(token)
+	if err != nil {
+		return &models.IntrospectionResponse{Active: false}
+	}
+	exists, err := s.sessionManager.Exists(ctx, claims.SessionID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check session during token introspection")
+		return &models.IntrospectionResponse{Active: false}
+	}
+	if !exists {
+		return &models.IntrospectionResponse{Active: false}
+	}
+
+	response := &models.IntrospectionResponse{
+		Active:       true,
+		TokenType:    "access_token",
+		Username:     claims.Email,
+		Subject:      claims.Subject,
+		Issuer:       claims.Issuer,
+		JWTID:        claims.ID,
+		TenantID:     claims.TenantID,
+		SessionID:    claims.SessionID,
+		Role:         claims.Role,
+		Permissions:  claims.Permissions,
+		CustomRoleID: claims.CustomRoleID,
+	}
+	if claims.ExpiresAt != nil {
+		response.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		response.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.Impersonation != nil {
+		response.ImpersonatorID = claims.Impersonation.OperatorID
+	}
+	if claims.PinSwitch != nil {
+		response.PinTerminalID = claims.PinSwitch.TerminalID
+	}
+	return response
+}
+
+// cache stores a result for the cache TTL, never past the token's own expiry
+func (s *IntrospectionService) cache(ctx context.Context, key string, response *models.IntrospectionResponse) {
+	ttl := s.cacheTTL
+	if response.Active {
+		if untilExpiry := time.Until(time.Unix(response.ExpiresAt, 0)); untilExpiry < ttl {
+			ttl = untilExpiry
+		}
+	}
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Set(ctx, key, data, ttl).Err(); err != nil {
+		log.Debug().Msgf("Warning: failed to cache token introspection: %v\n", err)
+	}
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/pos/auth-service/src/models"
 )
 
 type JWTService struct {
@@ -18,9 +21,44 @@ type JWTClaims struct {
 	TenantID  string `json:"tenantId"`
 	Email     string `json:"email"`
 	Role      string `json:"role"`
+	// Permissions granted by the role or custom role, enforced by the API gateway
+	Permissions  []string `json:"permissions"`
+	CustomRoleID string   `json:"customRoleId,omitempty"`
+	// Outlet the user works at, forwarded by the API gateway as X-Outlet-ID
+	OutletID string `json:"outletId,omitempty"`
+	// Set on tokens a platform operator uses to act as the user
+	Impersonation *ImpersonationClaims `json:"impersonation,omitempty"`
+	// Set on tokens of a cashier who switched in with their PIN on a shared terminal
+	PinSwitch *PinSwitchClaims `json:"pinSwitch,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ImpersonationClaims identify the operator behind an impersonation token
+type ImpersonationClaims struct {
+	OperatorID    string           `json:"operatorId"`
+	OperatorEmail string           `json:"operatorEmail"`
+	ExpiresAt     *jwt.NumericDate `json:"expiresAt"`
+}
+
+// PinSwitchClaims identify the terminal a PIN session was started on
+type PinSwitchClaims struct {
+	TerminalID string           `json:"terminalId"`
+	ExpiresAt  *jwt.NumericDate `json:"expiresAt"`
+}
+
+// StepUpClaims prove that the user of a session re-authenticated recently
+// The API gateway requires them on sensitive routes. They are signed with a key derived from the
+// JWT secret, so a step-up token can never pass as a session token or the other way round.
+type StepUpClaims struct {
+	SessionID string `json:"sessionId"`
+	UserID    string `json:"userId"`
+	Method    string `json:"method"` // password or passkey
+	jwt.RegisteredClaims
+}
+
+// stepUpAudience marks step-up tokens; the gateway checks it along with the derived key
+const stepUpAudience = "pos-step-up"
+
 func NewJWTService(secret string, expirationMinutes int) *JWTService {
 	return &JWTService{
 		secret:     []byte(secret),
@@ -29,14 +67,68 @@ func NewJWTService(secret string, expirationMinutes int) *JWTService {
 }
 
 // Generate creates a new JWT token
-func (s *JWTService) Generate(sessionID, userID, tenantID, email, role string) (string, error) {
+// A nil access grants the permissions of the role.
+func (s *JWTService) Generate(sessionID, userID, tenantID, email, role string, access *models.AccessGrant) (string, error) {
+	return s.sign(s.newClaims(sessionID, userID, tenantID, email, role, access))
+}
+
+// GenerateImpersonation creates a token for an impersonation session
+// The token is flagged with the operator's identity and never outlives the impersonation.
+func (s *JWTService) GenerateImpersonation(sessionID string, session *models.SessionData, access *models.AccessGrant) (string, error) {
+	if session.Impersonation == nil {
+		return "", fmt.Errorf("session is not an impersonation")
+	}
+	claims := s.newClaims(sessionID, session.UserID, session.TenantID, session.Email, session.Role, access)
+	claims.Impersonation = &ImpersonationClaims{
+		OperatorID:    session.Impersonation.OperatorID,
+		OperatorEmail: session.Impersonation.OperatorEmail,
+		ExpiresAt:     jwt.NewNumericDate(session.Impersonation.ExpiresAt),
+	}
+	return s.sign(claims)
+}
+
+// GeneratePinSwitch creates a token for a cashier's PIN session
+// The token never outlives the PIN session, whatever the regular token expiration.
+func (s *JWTService) GeneratePinSwitch(sessionID string, session *models.SessionData, access *models.AccessGrant) (string, error) {
+	if session.PinSwitch == nil {
+		return "", fmt.Errorf("session is not a PIN switch")
+	}
+	claims := s.newClaims(sessionID, session.UserID, session.TenantID, session.Email, session.Role, access)
+	claims.PinSwitch = &PinSwitchClaims{
+		TerminalID: session.PinSwitch.TerminalID,
+		ExpiresAt:  jwt.NewNumericDate(session.PinSwitch.ExpiresAt),
+	}
+	return s.sign(claims)
+}
+
+// GenerateForSession creates a new token for an existing session, as when it is refreshed
+// Impersonation and PIN sessions keep their flag, and their tokens still end with them.
+func (s *JWTService) GenerateForSession(sessionID string, session *models.SessionData, access *models.AccessGrant) (string, error) {
+	switch {
+	case session.Impersonation != nil:
+		return s.GenerateImpersonation(sessionID, session, access)
+	case session.PinSwitch != nil:
+		return s.GeneratePinSwitch(sessionID, session, access)
+	default:
+		return s.Generate(sessionID, session.UserID, session.TenantID, session.Email, session.Role, access)
+	}
+}
+
+func (s *JWTService) newClaims(sessionID, userID, tenantID, email, role string, access *models.AccessGrant) JWTClaims {
+	if access == nil {
+		access = models.RoleAccess(role)
+	}
+
 	now := time.Now()
-	claims := JWTClaims{
-		SessionID: sessionID,
-		UserID:    userID,
-		TenantID:  tenantID,
-		Email:     email,
-		Role:      role,
+	return JWTClaims{
+		SessionID:    sessionID,
+		UserID:       userID,
+		TenantID:     tenantID,
+		Email:        email,
+		Role:         role,
+		Permissions:  access.Permissions,
+		CustomRoleID: access.CustomRoleID,
+		OutletID:     access.OutletID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.expiration)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -46,6 +138,15 @@ func (s *JWTService) Generate(sessionID, userID, tenantID, email, role string) (
 			ID:        sessionID,
 		},
 	}
+}
+
+func (s *JWTService) sign(claims JWTClaims) (string, error) {
+	if claims.Impersonation != nil && claims.Impersonation.ExpiresAt.Before(claims.ExpiresAt.Time) {
+		claims.ExpiresAt = claims.Impersonation.ExpiresAt
+	}
+	if claims.PinSwitch != nil && claims.PinSwitch.ExpiresAt.Before(claims.ExpiresAt.Time) {
+		claims.ExpiresAt = claims.PinSwitch.ExpiresAt
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(s.secret)
@@ -56,6 +157,38 @@ func (s *JWTService) Generate(sessionID, userID, tenantID, email, role string) (
 	return tokenString, nil
 }
 
+// stepUpKey derives the step-up signing key from the JWT secret; the API gateway derives the same key
+func (s *JWTService) stepUpKey() []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("step-up"))
+	return mac.Sum(nil)
+}
+
+// GenerateStepUp creates a step-up token for a session whose user just re-authenticated
+func (s *JWTService) GenerateStepUp(sessionID, userID, method string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := StepUpClaims{
+		SessionID: sessionID,
+		UserID:    userID,
+		Method:    method,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "pos-auth-service",
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{stepUpAudience},
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.stepUpKey())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign step-up token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
 // Validate validates and parses a JWT token
 func (s *JWTService) Validate(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -103,5 +236,12 @@ func (s *JWTService) RefreshToken(oldTokenString string) (string, error) {
 	}
 
 	// Generate new token with same claims but new expiration
-	return s.Generate(claims.SessionID, claims.UserID, claims.TenantID, claims.Email, claims.Role)
+	var access *models.AccessGrant
+	if claims.Permissions != nil {
+		access = &models.AccessGrant{Permissions: claims.Permissions, CustomRoleID: claims.CustomRoleID, OutletID: claims.OutletID}
+	}
+	refreshed := s.newClaims(claims.SessionID, claims.UserID, claims.TenantID, claims.Email, claims.Role, access)
+	refreshed.Impersonation = claims.Impersonation
+	refreshed.PinSwitch = claims.PinSwitch
+	return s.sign(refreshed)
 }
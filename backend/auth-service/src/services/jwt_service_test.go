@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pos/auth-service/src/models"
+)
+
+func TestGenerateForSessionKeepsPinSwitch(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 15)
+	pinEndsAt := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	session := &models.SessionData{
+		UserID:    "user-1",
+		TenantID:  "tenant-1",
+		Email:     "cashier@example.com",
+		Role:      "cashier",
+		PinSwitch: &models.PinSwitch{TerminalID: "terminal-1", ExpiresAt: pinEndsAt},
+	}
+
+	token, err := jwtService.GenerateForSession("session-1", session, nil)
+	if err != nil {
+		t.Fatalf("GenerateForSession() error = %v", err)
+	}
+	claims, err := jwtService.Validate(token)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if claims.PinSwitch == nil || claims.PinSwitch.TerminalID != "terminal-1" {
+		t.Fatalf("refreshed token lost the PIN switch claim: %+v", claims.PinSwitch)
+	}
+	if !claims.ExpiresAt.Time.Equal(pinEndsAt) {
+		t.Errorf("refreshed token expires at %v, want the PIN session's end %v", claims.ExpiresAt.Time, pinEndsAt)
+	}
+}
+
+func TestGenerateForSessionRegularSession(t *testing.T) {
+	jwtService := NewJWTService("test-secret", 15)
+	session := &models.SessionData{UserID: "user-1", TenantID: "tenant-1", Email: "owner@example.com", Role: "owner"}
+
+	token, err := jwtService.GenerateForSession("session-1", session, nil)
+	if err != nil {
+		t.Fatalf("GenerateForSession() error = %v", err)
+	}
+	claims, err := jwtService.Validate(token)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if claims.PinSwitch != nil || claims.Impersonation != nil {
+		t.Errorf("regular session token carries a PIN switch or impersonation claim")
+	}
+	if remaining := time.Until(claims.ExpiresAt.Time); remaining < 14*time.Minute || remaining > 15*time.Minute {
+		t.Errorf("regular session token expires in %v, want the full 15 minutes", remaining)
+	}
+}
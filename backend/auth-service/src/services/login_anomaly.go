@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+)
+
+// LoginDevice identifies where a login request comes from
+type LoginDevice struct {
+	ID      string // Long-lived random device cookie; empty on a browser that never logged in
+	Country string // ISO 3166-1 alpha-2 code set by the edge proxy; empty when unknown
+}
+
+type loginDeviceKey struct{}
+
+// WithLoginDevice attaches the requesting device to the context of a login
+// Login handlers set it so every login method reaches the anomaly check without extra arguments.
+func WithLoginDevice(ctx context.Context, device LoginDevice) context.Context {
+	return context.WithValue(ctx, loginDeviceKey{}, device)
+}
+
+func loginDeviceFromContext(ctx context.Context) LoginDevice {
+	device, _ := ctx.Value(loginDeviceKey{}).(LoginDevice)
+	return device
+}
+
+// LoginAnomaly is what was new about a login compared to the user's earlier ones
+type LoginAnomaly struct {
+	NewDevice  bool
+	NewCountry bool
+	Country    string
+}
+
+// LoginAnomalyDetector spots logins from a device or country the user has not logged in from
+// A user's very first tracked login only seeds the history; nothing is unusual yet.
+type LoginAnomalyDetector struct {
+	deviceRepo          *repository.KnownDeviceRepository
+	requireVerification bool
+}
+
+func NewLoginAnomalyDetector(deviceRepo *repository.KnownDeviceRepository, requireVerification bool) *LoginAnomalyDetector {
+	return &LoginAnomalyDetector{
+		deviceRepo:          deviceRepo,
+		requireVerification: requireVerification,
+	}
+}
+
+func deviceHash(deviceID string) string {
+	if deviceID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(deviceID))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeCountry keeps two-letter country codes; proxies send XX when the country is unknown
+func normalizeCountry(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if len(country) != 2 || country == "XX" || country[0] < 'A' || country[0] > 'Z' || country[1] < 'A' || country[1] > 'Z' {
+		return ""
+	}
+	return country
+}
+
+// Evaluate returns the anomaly of a login by this user from the device in ctx, or nil when nothing is new
+func (d *LoginAnomalyDetector) Evaluate(ctx context.Context, user *models.User) (*LoginAnomaly, error) {
+	device := loginDeviceFromContext(ctx)
+	country := normalizeCountry(device.Country)
+
+	hasHistory, deviceKnown, countryKnown, err := d.deviceRepo.LoginHistory(ctx, user.ID, deviceHash(device.ID), country)
+	if err != nil {
+		return nil, err
+	}
+	if !hasHistory || (deviceKnown && countryKnown) {
+		return nil, nil
+	}
+	return &LoginAnomaly{
+		NewDevice:  !deviceKnown,
+		NewCountry: !countryKnown,
+		Country:    country,
+	}, nil
+}
+
+// RequiresVerification reports whether an unusual login must be confirmed by email before it gets a session
+// Only password logins are held back: passkeys are bound to the device, SSO is verified by the
+// provider, and the confirmation itself is a magic link.
+func (d *LoginAnomalyDetector) RequiresVerification(anomaly *LoginAnomaly, loginMethod string) bool {
+	return d.requireVerification && anomaly != nil && loginMethod == "password"
+}
+
+// Remember adds the device and country in ctx to the user's known logins
+func (d *LoginAnomalyDetector) Remember(ctx context.Context, user *models.User, ipAddress, userAgent string) error {
+	device := loginDeviceFromContext(ctx)
+	return d.deviceRepo.Remember(ctx, user.TenantID, user.ID, deviceHash(device.ID), normalizeCountry(device.Country), userAgent, ipAddress)
+}
+
+// LoginVerificationRequiredError holds back a login from a new device or country until the
+// user confirms it through the link emailed to them
+type LoginVerificationRequiredError struct {
+	TenantID string
+	UserID   string
+}
+
+func (e *LoginVerificationRequiredError) Error() string {
+	return "login from a new device or country must be verified"
+}
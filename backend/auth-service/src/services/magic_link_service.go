@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/queue"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// maxMagicLinkRequests is how many links a user can be sent within one link lifetime
+const maxMagicLinkRequests = 3
+
+// Why a login link was sent, so the email can explain it
+const (
+	MagicLinkPurposeLogin        = "login"
+	MagicLinkPurposeVerifyDevice = "verify_device" // Confirms a password login from a new device or country
+)
+
+var ErrMagicLinkInvalid = errors.New("login link is invalid or expired")
+
+// MagicLinkService logs users in through a one-time link emailed to them
+// Tokens live in Redis under their SHA-256 hash, so a leaked Redis dump cannot be replayed,
+// and are deleted as they are read, so each link works exactly once.
+type MagicLinkService struct {
+	redis          *redis.Client
+	authService    *AuthService
+	eventPublisher *queue.EventPublisher
+	ttl            time.Duration
+}
+
+type magicLinkGrant struct {
+	TenantID string `json:"tenantId"`
+	UserID   string `json:"userId"`
+}
+
+func NewMagicLinkService(redisClient *redis.Client, authService *AuthService, eventPublisher *queue.EventPublisher, ttlMinutes int) *MagicLinkService {
+	return &MagicLinkService{
+		redis:          redisClient,
+		authService:    authService,
+		eventPublisher: eventPublisher,
+		ttl:            time.Duration(ttlMinutes) * time.Minute,
+	}
+}
+
+func magicLinkKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "magic_link:" + hex.EncodeToString(sum[:])
+}
+
+// RequestLink emails a login link to the user with this email
+// It reports success whether or not a link was sent, so callers cannot probe which emails
+// have accounts, which tenants require SSO or who has been rate limited.
+func (s *MagicLinkService) RequestLink(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	masker := utils.NewLogMasker()
+
+	tenantID, err := s.authService.getTenantIDByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to lookup tenant: %w", err)
+	}
+	if tenantID == "" {
+		return nil
+	}
+	user, err := s.authService.getUserByEmailAndTenant(ctx, email, tenantID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	if err := s.authService.checkSSOOnly(ctx, tenantID); err != nil {
+		if errors.Is(err, ErrSSORequired) {
+			log.Info().Str("tenant_id", tenantID).Msgf("Magic link not sent to %s: tenant requires SSO", masker.MaskEmail(email))
+			return nil
+		}
+		return err
+	}
+
+	return s.sendLink(ctx, user, MagicLinkPurposeLogin)
+}
+
+// SendVerificationLink emails the link that confirms a password login held back as unusual
+// Opening it logs the user in and makes the device a known one.
+func (s *MagicLinkService) SendVerificationLink(ctx context.Context, tenantID, userID string) error {
+	user, err := s.authService.getUserByID(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidCredentials
+	}
+	return s.sendLink(ctx, user, MagicLinkPurposeVerifyDevice)
+}
+
+// sendLink stores a new login token for the user and publishes the email carrying it
+func (s *MagicLinkService) sendLink(ctx context.Context, user *models.User, purpose string) error {
+	tenantID := user.TenantID
+	requestsKey := "magic_link_requests:" + user.ID
+	requests, err := s.redis.Incr(ctx, requestsKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to count login link requests: %w", err)
+	}
+	if requests == 1 {
+		s.redis.Expire(ctx, requestsKey, s.ttl)
+	}
+	if requests > maxMagicLinkRequests {
+		log.Warn().Str("tenant_id", tenantID).Str("user_id", user.ID).Msg("Magic link request rate limited")
+		return nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(magicLinkGrant{TenantID: tenantID, UserID: user.ID})
+	if err != nil {
+		return err
+	}
+	if err := s.redis.Set(ctx, magicLinkKey(token), data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store login link: %w", err)
+	}
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if err := s.eventPublisher.PublishMagicLinkRequested(ctx, tenantID, user.ID, user.Email, name, token, purpose, int(s.ttl.Minutes())); err != nil {
+		s.redis.Del(ctx, magicLinkKey(token))
+		return fmt.Errorf("failed to publish login link event: %w", err)
+	}
+	log.Info().Str("tenant_id", tenantID).Str("user_id", user.ID).Str("purpose", purpose).Msg("Magic link sent")
+	return nil
+}
+
+// Consume exchanges a login link token for a session
+func (s *MagicLinkService) Consume(ctx context.Context, token, ipAddress, userAgent string) (*models.LoginResponse, string, error) {
+	if token == "" {
+		return nil, "", ErrMagicLinkInvalid
+	}
+	data, err := s.redis.GetDel(ctx, magicLinkKey(token)).Result()
+	if err == redis.Nil {
+		return nil, "", ErrMagicLinkInvalid
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read login link: %w", err)
+	}
+
+	var grant magicLinkGrant
+	if err := json.Unmarshal([]byte(data), &grant); err != nil {
+		return nil, "", fmt.Errorf("failed to decode login link: %w", err)
+	}
+	return s.authService.LoginWithMagicLink(ctx, grant.TenantID, grant.UserID, ipAddress, userAgent)
+}
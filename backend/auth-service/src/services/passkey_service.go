@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// passkeyChallengeTTL is how long a ceremony may take, and the timeout given to the browser
+	passkeyChallengeTTL = 5 * time.Minute
+
+	maxPasskeyNameLength = 100
+	defaultPasskeyName   = "Passkey"
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+)
+
+var ErrInvalidPasskeyName = fmt.Errorf("passkey name must be at most %d characters", maxPasskeyNameLength)
+
+// PasskeyService registers staff passkeys and logs staff in with them
+// Passkeys are discoverable credentials on platform authenticators, so a shared tablet
+// can offer every staff member's passkey without anyone typing an email first.
+type PasskeyService struct {
+	passkeyRepo    *repository.PasskeyRepository
+	redis          *redis.Client
+	authService    *AuthService
+	auditPublisher *utils.AuditPublisher
+	config         WebAuthnConfig
+}
+
+func NewPasskeyService(
+	passkeyRepo *repository.PasskeyRepository,
+	redisClient *redis.Client,
+	authService *AuthService,
+	auditPublisher *utils.AuditPublisher,
+	config WebAuthnConfig,
+) *PasskeyService {
+	return &PasskeyService{
+		passkeyRepo:    passkeyRepo,
+		redis:          redisClient,
+		authService:    authService,
+		auditPublisher: auditPublisher,
+		config:         config,
+	}
+}
+
+// BeginRegistration returns the options for creating a passkey for the logged-in user
+func (s *PasskeyService) BeginRegistration(ctx context.Context, session *models.SessionData) (*models.PasskeyCreationOptions, error) {
+	challenge, err := s.newChallenge(ctx, models.PasskeyChallenge{
+		Ceremony: ceremonyCreate,
+		UserID:   session.UserID,
+		TenantID: session.TenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.passkeyRepo.ListByUser(ctx, session.TenantID, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+
+	options := &models.PasskeyCreationOptions{
+		Challenge:          challenge,
+		Timeout:            passkeyChallengeTTL.Milliseconds(),
+		Attestation:        "none",
+		ExcludeCredentials: []models.PasskeyCredentialDescriptor{},
+	}
+	options.RP.ID = s.config.RPID
+	options.RP.Name = s.config.RPName
+	options.User.ID = base64.RawURLEncoding.EncodeToString([]byte(session.UserID))
+	options.User.Name = session.Email
+	options.User.DisplayName = strings.TrimSpace(session.FirstName + " " + session.LastName)
+	if options.User.DisplayName == "" {
+		options.User.DisplayName = session.Email
+	}
+	for _, alg := range supportedPasskeyAlgorithms {
+		options.PubKeyCredParams = append(options.PubKeyCredParams, models.PasskeyCredentialParam{Type: "public-key", Alg: alg})
+	}
+	options.AuthenticatorSelection.AuthenticatorAttachment = "platform"
+	options.AuthenticatorSelection.ResidentKey = "required"
+	options.AuthenticatorSelection.RequireResidentKey = true
+	options.AuthenticatorSelection.UserVerification = "required"
+	for _, passkey := range existing {
+		options.ExcludeCredentials = append(options.ExcludeCredentials, models.PasskeyCredentialDescriptor{
+			Type:       "public-key",
+			ID:         base64.RawURLEncoding.EncodeToString(passkey.CredentialID),
+			Transports: passkey.Transports,
+		})
+	}
+	return options, nil
+}
+
+// FinishRegistration verifies the authenticator's response and stores the new passkey
+func (s *PasskeyService) FinishRegistration(ctx context.Context, session *models.SessionData, req *models.PasskeyRegistrationRequest, ipAddress, userAgent string) (*models.Passkey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = defaultPasskeyName
+	}
+	if len(name) > maxPasskeyNameLength {
+		return nil, ErrInvalidPasskeyName
+	}
+
+	clientDataJSON, err := decodeBase64URL(req.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed client data", ErrPasskeyVerification)
+	}
+	challenge, err := s.config.parseClientData(clientDataJSON, ceremonyCreate)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.consumeChallenge(ctx, challenge, ceremonyCreate)
+	if err != nil {
+		return nil, err
+	}
+	if pending.UserID != session.UserID || pending.TenantID != session.TenantID {
+		return nil, ErrPasskeyChallenge
+	}
+
+	attestationObject, err := decodeBase64URL(req.Credential.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed attestation object", ErrPasskeyVerification)
+	}
+	authData, err := s.config.parseAttestationObject(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	rawID, err := decodeBase64URL(req.Credential.RawID)
+	if err != nil || !sameCredentialID(rawID, authData.credentialID) {
+		return nil, fmt.Errorf("%w: credential ID does not match", ErrPasskeyVerification)
+	}
+	algorithm, err := coseAlgorithm(authData.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey := &models.Passkey{
+		TenantID:     session.TenantID,
+		UserID:       session.UserID,
+		CredentialID: authData.credentialID,
+		PublicKey:    authData.publicKey,
+		Algorithm:    algorithm,
+		SignCount:    authData.signCount,
+		Transports:   req.Credential.Response.Transports,
+		Name:         name,
+	}
+	if err := s.passkeyRepo.Create(ctx, passkey); err != nil {
+		if errors.Is(err, repository.ErrPasskeyExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save passkey: %w", err)
+	}
+
+	s.publishAudit(ctx, session, "CREATE", passkey, ipAddress, userAgent)
+	log.Info().Str("user_id", session.UserID).Str("passkey_id", passkey.ID).Msg("Passkey registered")
+	return passkey, nil
+}
+
+// BeginLogin returns the options for logging in with any passkey registered for this site
+func (s *PasskeyService) BeginLogin(ctx context.Context) (*models.PasskeyRequestOptions, error) {
+	challenge, err := s.newChallenge(ctx, models.PasskeyChallenge{Ceremony: ceremonyGet})
+	if err != nil {
+		return nil, err
+	}
+	return &models.PasskeyRequestOptions{
+		Challenge:        challenge,
+		RPID:             s.config.RPID,
+		Timeout:          passkeyChallengeTTL.Milliseconds(),
+		UserVerification: "required",
+		AllowCredentials: []models.PasskeyCredentialDescriptor{},
+	}, nil
+}
+
+// FinishLogin verifies a passkey assertion and starts a session for its owner
+// Any verification failure is reported as ErrInvalidCredentials.
+func (s *PasskeyService) FinishLogin(ctx context.Context, req *models.PasskeyLoginRequest, ipAddress, userAgent string) (*models.LoginResponse, string, error) {
+	passkey, err := s.verifyAssertion(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrPasskeyVerification) || errors.Is(err, ErrPasskeyChallenge) || errors.Is(err, repository.ErrPasskeyNotFound) {
+			log.Warn().Err(err).Msg("Passkey login rejected")
+			return nil, "", ErrInvalidCredentials
+		}
+		return nil, "", err
+	}
+	return s.authService.LoginWithPasskey(ctx, passkey.TenantID, passkey.UserID, ipAddress, userAgent)
+}
+
+// verifyAssertion checks an assertion against the stored passkey and advances its signature counter
+func (s *PasskeyService) verifyAssertion(ctx context.Context, req *models.PasskeyLoginRequest) (*models.Passkey, error) {
+	clientDataJSON, err := decodeBase64URL(req.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed client data", ErrPasskeyVerification)
+	}
+	challenge, err := s.config.parseClientData(clientDataJSON, ceremonyGet)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.consumeChallenge(ctx, challenge, ceremonyGet); err != nil {
+		return nil, err
+	}
+
+	rawID, err := decodeBase64URL(req.Credential.RawID)
+	if err != nil || len(rawID) == 0 {
+		return nil, fmt.Errorf("%w: malformed credential ID", ErrPasskeyVerification)
+	}
+	passkey, err := s.passkeyRepo.GetByCredentialID(ctx, rawID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Credential.Response.UserHandle != "" {
+		userHandle, err := decodeBase64URL(req.Credential.Response.UserHandle)
+		if err != nil || string(userHandle) != passkey.UserID {
+			return nil, fmt.Errorf("%w: user handle does not match", ErrPasskeyVerification)
+		}
+	}
+
+	authDataRaw, err := decodeBase64URL(req.Credential.Response.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed authenticator data", ErrPasskeyVerification)
+	}
+	authData, err := s.config.parseAuthenticatorData(authDataRaw)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decodeBase64URL(req.Credential.Response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrPasskeyVerification)
+	}
+	if err := verifyAssertionSignature(passkey.PublicKey, authDataRaw, clientDataJSON, signature); err != nil {
+		return nil, err
+	}
+
+	// Authenticators that count signatures must count up; a step back means a cloned key
+	if (authData.signCount != 0 || passkey.SignCount != 0) && authData.signCount <= passkey.SignCount {
+		return nil, fmt.Errorf("%w: signature counter went backwards", ErrPasskeyVerification)
+	}
+	recorded, err := s.passkeyRepo.RecordUse(ctx, passkey, authData.signCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record passkey use: %w", err)
+	}
+	if !recorded {
+		return nil, fmt.Errorf("%w: passkey was used concurrently", ErrPasskeyVerification)
+	}
+	return passkey, nil
+}
+
+// ListPasskeys returns the logged-in user's passkeys
+func (s *PasskeyService) ListPasskeys(ctx context.Context, session *models.SessionData) ([]*models.Passkey, error) {
+	return s.passkeyRepo.ListByUser(ctx, session.TenantID, session.UserID)
+}
+
+// RenamePasskey renames one of the logged-in user's passkeys
+func (s *PasskeyService) RenamePasskey(ctx context.Context, session *models.SessionData, passkeyID, name, ipAddress, userAgent string) (*models.Passkey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxPasskeyNameLength {
+		return nil, ErrInvalidPasskeyName
+	}
+
+	passkey, err := s.passkeyRepo.Rename(ctx, session.TenantID, session.UserID, passkeyID, name)
+	if err != nil {
+		return nil, err
+	}
+	s.publishAudit(ctx, session, "UPDATE", passkey, ipAddress, userAgent)
+	return passkey, nil
+}
+
+// RevokePasskey deletes one of the logged-in user's passkeys so it can no longer log in
+func (s *PasskeyService) RevokePasskey(ctx context.Context, session *models.SessionData, passkeyID, ipAddress, userAgent string) error {
+	if err := s.passkeyRepo.Delete(ctx, session.TenantID, session.UserID, passkeyID); err != nil {
+		return err
+	}
+	s.publishAudit(ctx, session, "DELETE", &models.Passkey{ID: passkeyID}, ipAddress, userAgent)
+	log.Info().Str("user_id", session.UserID).Str("passkey_id", passkeyID).Msg("Passkey revoked")
+	return nil
+}
+
+// newChallenge stores a random single-use challenge for a ceremony and returns it base64url encoded
+func (s *PasskeyService) newChallenge(ctx context.Context, pending models.PasskeyChallenge) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(buf)
+
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal challenge: %w", err)
+	}
+	if err := s.redis.Set(ctx, passkeyChallengeKey(challenge), data, passkeyChallengeTTL).Err(); err != nil {
+		return "", fmt.Errorf("failed to store challenge in Redis: %w", err)
+	}
+	return challenge, nil
+}
+
+// consumeChallenge takes a pending challenge out of Redis so it cannot be answered twice
+func (s *PasskeyService) consumeChallenge(ctx context.Context, challenge, ceremony string) (*models.PasskeyChallenge, error) {
+	data, err := s.redis.GetDel(ctx, passkeyChallengeKey(challenge)).Result()
+	if err == redis.Nil {
+		return nil, ErrPasskeyChallenge
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge from Redis: %w", err)
+	}
+
+	var pending models.PasskeyChallenge
+	if err := json.Unmarshal([]byte(data), &pending); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal challenge: %w", err)
+	}
+	if pending.Ceremony != ceremony {
+		return nil, ErrPasskeyChallenge
+	}
+	return &pending, nil
+}
+
+func passkeyChallengeKey(challenge string) string {
+	return fmt.Sprintf("passkey_challenge:%s", challenge)
+}
+
+// publishAudit records a change to a user's passkeys in the audit trail
+func (s *PasskeyService) publishAudit(ctx context.Context, session *models.SessionData, action string, passkey *models.Passkey, ipAddress, userAgent string) {
+	if s.auditPublisher == nil {
+		return
+	}
+	userID := session.UserID
+	metadata := map[string]interface{}{}
+	if passkey.Name != "" {
+		metadata["name"] = passkey.Name
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     session.TenantID,
+		ActorType:    "user",
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: "passkey",
+		ResourceID:   passkey.ID,
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+		Metadata:     metadata,
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish passkey audit event: %v\n", err)
+	}
+}
+
+// BeginStepUp returns the options for confirming the logged-in user's identity with one of their passkeys
+func (s *PasskeyService) BeginStepUp(ctx context.Context, session *models.SessionData) (*models.PasskeyRequestOptions, error) {
+	passkeys, err := s.passkeyRepo.ListByUser(ctx, session.TenantID, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	if len(passkeys) == 0 {
+		return nil, repository.ErrPasskeyNotFound
+	}
+
+	challenge, err := s.newChallenge(ctx, models.PasskeyChallenge{
+		Ceremony: ceremonyGet,
+		UserID:   session.UserID,
+		TenantID: session.TenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	options := &models.PasskeyRequestOptions{
+		Challenge:        challenge,
+		RPID:             s.config.RPID,
+		Timeout:          passkeyChallengeTTL.Milliseconds(),
+		UserVerification: "required",
+		AllowCredentials: []models.PasskeyCredentialDescriptor{},
+	}
+	for _, passkey := range passkeys {
+		options.AllowCredentials = append(options.AllowCredentials, models.PasskeyCredentialDescriptor{
+			Type:       "public-key",
+			ID:         base64.RawURLEncoding.EncodeToString(passkey.CredentialID),
+			Transports: passkey.Transports,
+		})
+	}
+	return options, nil
+}
+
+// VerifyStepUp checks an assertion made with one of the logged-in user's own passkeys
+// Any verification failure is reported as ErrInvalidCredentials.
+func (s *PasskeyService) VerifyStepUp(ctx context.Context, session *models.SessionData, req *models.PasskeyLoginRequest) error {
+	passkey, err := s.verifyAssertion(ctx, req)
+	if err != nil {
+		if errors.Is(err, ErrPasskeyVerification) || errors.Is(err, ErrPasskeyChallenge) || errors.Is(err, repository.ErrPasskeyNotFound) {
+			log.Warn().Err(err).Str("user_id", session.UserID).Msg("Passkey step-up rejected")
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+	if passkey.UserID != session.UserID || passkey.TenantID != session.TenantID {
+		log.Warn().Str("user_id", session.UserID).Str("passkey_user_id", passkey.UserID).Msg("Passkey step-up with another user's passkey")
+		return ErrInvalidCredentials
+	}
+	return nil
+}
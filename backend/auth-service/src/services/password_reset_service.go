@@ -23,14 +23,18 @@ type PasswordResetService struct {
 	userDB         *sql.DB
 	eventPublisher *queue.EventPublisher
 	encryptor      utils.Encryptor
+	passwordPolicy *utils.PasswordPolicy
+	historySize    int // A new password may not match the current one or the previous historySize-1
 }
 
-func NewPasswordResetService(resetRepo *repository.PasswordResetRepository, userDB *sql.DB, eventPublisher *queue.EventPublisher, encryptor utils.Encryptor) *PasswordResetService {
+func NewPasswordResetService(resetRepo *repository.PasswordResetRepository, userDB *sql.DB, eventPublisher *queue.EventPublisher, encryptor utils.Encryptor, passwordPolicy *utils.PasswordPolicy, historySize int) *PasswordResetService {
 	return &PasswordResetService{
 		resetRepo:      resetRepo,
 		userDB:         userDB,
 		eventPublisher: eventPublisher,
 		encryptor:      encryptor,
+		passwordPolicy: passwordPolicy,
+		historySize:    historySize,
 	}
 }
 
@@ -129,16 +133,50 @@ func (s *PasswordResetService) ResetPassword(token, newPassword string) error {
 		return err
 	}
 
+	if err := s.setPassword(context.Background(), resetToken.UserID, resetToken.TenantID, newPassword); err != nil {
+		return err
+	}
+
+	return s.resetRepo.MarkAsUsed(resetToken.ID)
+}
+
+// ChangeRequiredPassword sets the new password of a user whose login was held back by password rotation
+func (s *PasswordResetService) ChangeRequiredPassword(ctx context.Context, tenantID, userID, newPassword string) error {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+	parsedTenantID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return err
+	}
+	return s.setPassword(ctx, parsedUserID, parsedTenantID, newPassword)
+}
+
+// setPassword replaces a user's password after checking it against the policy and their recent
+// passwords, and notifies the user
+func (s *PasswordResetService) setPassword(ctx context.Context, userID, tenantID uuid.UUID, newPassword string) error {
+	if err := s.passwordPolicy.Validate(ctx, newPassword); err != nil {
+		return err
+	}
+
 	// Get user details for notification
-	var encryptedEmail, encryptedFirstName, encryptedLastName string
-	query := `SELECT email, first_name, last_name FROM users WHERE id = $1 AND tenant_id = $2`
-	err = s.userDB.QueryRow(query, resetToken.UserID, resetToken.TenantID).Scan(&encryptedEmail, &encryptedFirstName, &encryptedLastName)
+	var encryptedEmail, encryptedFirstName, encryptedLastName, currentHash string
+	query := `SELECT email, first_name, last_name, password_hash FROM users WHERE id = $1 AND tenant_id = $2`
+	err := s.userDB.QueryRowContext(ctx, query, userID, tenantID).Scan(&encryptedEmail, &encryptedFirstName, &encryptedLastName, &currentHash)
 	if err != nil {
 		return err
 	}
 
+	reused, err := s.isRecentPassword(ctx, userID, currentHash, newPassword)
+	if err != nil {
+		return err
+	}
+	if reused {
+		return utils.NewPasswordReusedError()
+	}
+
 	// Decrypt email, first_name, and last_name
-	ctx := context.Background()
 	email, err := s.encryptor.DecryptWithContext(ctx, encryptedEmail, "user:email")
 	if err != nil {
 		return err
@@ -157,20 +195,13 @@ func (s *PasswordResetService) ResetPassword(token, newPassword string) error {
 		return err
 	}
 
-	updateQuery := `UPDATE users SET password_hash = $1 WHERE id = $2 AND tenant_id = $3`
-	_, err = s.userDB.Exec(updateQuery, string(hashedPassword), resetToken.UserID, resetToken.TenantID)
-	if err != nil {
-		return err
-	}
-
-	err = s.resetRepo.MarkAsUsed(resetToken.ID)
-	if err != nil {
+	if err := s.replacePassword(ctx, userID, tenantID, currentHash, string(hashedPassword)); err != nil {
 		return err
 	}
 
 	// Publish password changed event
 	name := firstName + " " + lastName
-	if err := s.eventPublisher.PublishPasswordChanged(ctx, resetToken.TenantID.String(), resetToken.UserID.String(), email, name); err != nil {
+	if err := s.eventPublisher.PublishPasswordChanged(ctx, tenantID.String(), userID.String(), email, name); err != nil {
 		// Log error but don't fail the request
 		log.Printf("Error publishing password changed event: %v", err)
 	} else {
@@ -180,6 +211,81 @@ func (s *PasswordResetService) ResetPassword(token, newPassword string) error {
 	return nil
 }
 
+// isRecentPassword reports whether the password matches the current one or a remembered previous one
+func (s *PasswordResetService) isRecentPassword(ctx context.Context, userID uuid.UUID, currentHash, password string) (bool, error) {
+	if s.historySize <= 0 {
+		return false, nil
+	}
+	hashes := []string{currentHash}
+
+	if s.historySize > 1 {
+		rows, err := s.userDB.QueryContext(ctx, `
+			SELECT password_hash FROM user_password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2
+		`, userID, s.historySize-1)
+		if err != nil {
+			return false, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var hash string
+			if err := rows.Scan(&hash); err != nil {
+				return false, err
+			}
+			hashes = append(hashes, hash)
+		}
+		if err := rows.Err(); err != nil {
+			return false, err
+		}
+	}
+
+	for _, hash := range hashes {
+		if hash != "" && bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// replacePassword sets the new password hash and remembers the old one for the reuse check
+func (s *PasswordResetService) replacePassword(ctx context.Context, userID, tenantID uuid.UUID, oldHash, newHash string) error {
+	tx, err := s.userDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// A new password restarts the rotation clock and clears any forced change
+	updateQuery := `
+		UPDATE users SET password_hash = $1, password_changed_at = NOW(), must_change_password = false
+		WHERE id = $2 AND tenant_id = $3
+	`
+	if _, err := tx.ExecContext(ctx, updateQuery, newHash, userID, tenantID); err != nil {
+		return err
+	}
+
+	if s.historySize > 1 && oldHash != "" {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO user_password_history (user_id, tenant_id, password_hash) VALUES ($1, $2, $3)
+		`, userID, tenantID, oldHash); err != nil {
+			return err
+		}
+		// Only the hashes the reuse check can still reach are kept
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM user_password_history
+			WHERE user_id = $1 AND id NOT IN (
+				SELECT id FROM user_password_history WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2
+			)
+		`, userID, s.historySize-1); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func generateSecureToken(length int) (string, error) {
 	bytes := make([]byte, length)
 	if _, err := rand.Read(bytes); err != nil {
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// passwordChangeTokenTTL is how long a held-back login has to set its new password
+	passwordChangeTokenTTL = 10 * time.Minute
+	maxPasswordAgeDays     = 3650
+)
+
+var (
+	ErrPasswordChangeTokenInvalid = errors.New("password change token is invalid or expired")
+	ErrInvalidPasswordMaxAge      = errors.New("maximum password age out of range")
+	ErrInvalidUserIDs             = errors.New("invalid user IDs")
+)
+
+// PasswordRotation enforces a tenant's maximum password age and the "must change on next login" flag
+// A password login that is due for a change gets no session: it gets a short-lived change token
+// instead, which the change-password-required endpoint exchanges, with the new password, for one.
+// Passkey, SSO and magic link logins do not use the password and are not held back.
+type PasswordRotation struct {
+	rotationRepo   *repository.PasswordRotationRepository
+	redis          *redis.Client
+	sessionManager *SessionManager
+	auditPublisher *utils.AuditPublisher
+}
+
+type passwordChangeGrant struct {
+	TenantID string `json:"tenantId"`
+	UserID   string `json:"userId"`
+}
+
+func NewPasswordRotation(rotationRepo *repository.PasswordRotationRepository, redisClient *redis.Client, sessionManager *SessionManager, auditPublisher *utils.AuditPublisher) *PasswordRotation {
+	return &PasswordRotation{
+		rotationRepo:   rotationRepo,
+		redis:          redisClient,
+		sessionManager: sessionManager,
+		auditPublisher: auditPublisher,
+	}
+}
+
+func passwordChangeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "password_change:" + hex.EncodeToString(sum[:])
+}
+
+// Check returns a PasswordChangeRequiredError carrying a change token when the user must set a new password
+func (r *PasswordRotation) Check(ctx context.Context, user *models.User) error {
+	reason := ""
+	if user.MustChangePassword {
+		reason = models.PasswordChangeRequired
+	} else {
+		settings, err := r.rotationRepo.GetSettings(ctx, user.TenantID)
+		if err != nil {
+			return fmt.Errorf("failed to get password rotation policy: %w", err)
+		}
+		maxAge := time.Duration(settings.MaxAgeDays) * 24 * time.Hour
+		if maxAge > 0 && time.Since(user.PasswordChangedAt) > maxAge {
+			reason = models.PasswordChangeExpired
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(passwordChangeGrant{TenantID: user.TenantID, UserID: user.ID})
+	if err != nil {
+		return err
+	}
+	if err := r.redis.Set(ctx, passwordChangeKey(token), data, passwordChangeTokenTTL).Err(); err != nil {
+		return fmt.Errorf("failed to store password change token: %w", err)
+	}
+	return &PasswordChangeRequiredError{
+		Reason:      reason,
+		ChangeToken: token,
+		ExpiresAt:   time.Now().Add(passwordChangeTokenTTL),
+	}
+}
+
+// ChangeGrant returns the user a change token was issued to
+// The token is not used up here, so a new password rejected by the policy can be retried.
+func (r *PasswordRotation) ChangeGrant(ctx context.Context, token string) (tenantID, userID string, err error) {
+	if token == "" {
+		return "", "", ErrPasswordChangeTokenInvalid
+	}
+	data, err := r.redis.Get(ctx, passwordChangeKey(token)).Result()
+	if err == redis.Nil {
+		return "", "", ErrPasswordChangeTokenInvalid
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read password change token: %w", err)
+	}
+	var grant passwordChangeGrant
+	if err := json.Unmarshal([]byte(data), &grant); err != nil {
+		return "", "", fmt.Errorf("failed to decode password change token: %w", err)
+	}
+	return grant.TenantID, grant.UserID, nil
+}
+
+// RevokeChangeToken uses up a change token once the new password is set
+func (r *PasswordRotation) RevokeChangeToken(ctx context.Context, token string) {
+	if err := r.redis.Del(ctx, passwordChangeKey(token)).Err(); err != nil {
+		log.Debug().Msgf("Warning: failed to delete password change token: %v\n", err)
+	}
+}
+
+// GetSettings returns the rotation policy of the logged-in user's tenant
+func (r *PasswordRotation) GetSettings(ctx context.Context, session *models.SessionData) (*models.TenantPasswordRotation, error) {
+	return r.rotationRepo.GetSettings(ctx, session.TenantID)
+}
+
+// UpdateSettings changes the maximum password age of the logged-in owner's tenant
+func (r *PasswordRotation) UpdateSettings(ctx context.Context, session *models.SessionData, req *models.UpdatePasswordRotationRequest, ipAddress, userAgent string) (*models.TenantPasswordRotation, error) {
+	if req.MaxAgeDays < 0 || req.MaxAgeDays > maxPasswordAgeDays {
+		return nil, ErrInvalidPasswordMaxAge
+	}
+
+	previous, err := r.rotationRepo.GetSettings(ctx, session.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password rotation policy: %w", err)
+	}
+
+	userID := session.UserID
+	settings := &models.TenantPasswordRotation{
+		TenantID:   session.TenantID,
+		MaxAgeDays: req.MaxAgeDays,
+		UpdatedBy:  &userID,
+	}
+	if err := r.rotationRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save password rotation policy: %w", err)
+	}
+
+	r.publishAudit(ctx, session, ipAddress, userAgent,
+		map[string]interface{}{"max_age_days": previous.MaxAgeDays},
+		map[string]interface{}{"max_age_days": settings.MaxAgeDays})
+	return settings, nil
+}
+
+// ForceChange flags users of the owner's tenant to change their password at next login
+// With no user IDs every user is flagged, for a tenant whose credentials leaked. Revoking
+// sessions logs the users out at once; the owner's own session is kept.
+func (r *PasswordRotation) ForceChange(ctx context.Context, session *models.SessionData, sessionID string, req *models.ForcePasswordChangeRequest, ipAddress, userAgent string) (*models.ForcePasswordChangeResponse, error) {
+	for _, userID := range req.UserIDs {
+		if _, err := uuid.Parse(userID); err != nil {
+			return nil, ErrInvalidUserIDs
+		}
+	}
+
+	flagged, err := r.rotationRepo.FlagMustChange(ctx, session.TenantID, req.UserIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flag users for password change: %w", err)
+	}
+
+	if req.RevokeSessions && len(flagged) > 0 {
+		if err := r.sessionManager.DeleteForUsers(ctx, flagged, sessionID); err != nil {
+			return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+
+	scope := "users"
+	if len(req.UserIDs) == 0 {
+		scope = "tenant"
+	}
+	r.publishAudit(ctx, session, ipAddress, userAgent, nil, map[string]interface{}{
+		"forced_change":   true,
+		"scope":           scope,
+		"user_ids":        flagged,
+		"revoke_sessions": req.RevokeSessions,
+	})
+	log.Warn().Str("tenant_id", session.TenantID).Str("scope", scope).Int("flagged", len(flagged)).
+		Bool("revoke_sessions", req.RevokeSessions).Msg("Password change forced")
+
+	return &models.ForcePasswordChangeResponse{Flagged: len(flagged)}, nil
+}
+
+func (r *PasswordRotation) publishAudit(ctx context.Context, session *models.SessionData, ipAddress, userAgent string, before, after map[string]interface{}) {
+	if r.auditPublisher == nil {
+		return
+	}
+	userID := session.UserID
+	auditEvent := &utils.AuditEvent{
+		TenantID:     session.TenantID,
+		ActorType:    "user",
+		ActorID:      &userID,
+		Action:       "UPDATE",
+		ResourceType: "password_rotation",
+		ResourceID:   session.TenantID,
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+		BeforeValue:  before,
+		AfterValue:   after,
+	}
+	if err := r.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish password rotation audit event: %v\n", err)
+	}
+}
+
+// PasswordChangeRequiredError holds back a password login until a new password is set
+type PasswordChangeRequiredError struct {
+	Reason      string
+	ChangeToken string
+	ExpiresAt   time.Time
+}
+
+func (e *PasswordChangeRequiredError) Error() string {
+	return fmt.Sprintf("password change required (%s)", e.Reason)
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// maxPinFailures is how many wrong PINs in a row lock a user's PIN until it is set again or reset by an owner or manager
+	maxPinFailures = 5
+	// maxTerminalPinFailures stops a terminal from guessing across many users' PINs
+	maxTerminalPinFailures = 20
+	pinFailureWindow       = 15 * time.Minute
+)
+
+var (
+	ErrPinInvalid         = errors.New("invalid user or PIN")
+	ErrPinLocked          = errors.New("too many wrong PINs")
+	ErrPinUserLocked      = errors.New("PIN is locked after too many wrong entries")
+	ErrPinTerminalInvalid = errors.New("terminal is not enrolled for PIN switching")
+	ErrPinTerminalSession = errors.New("session cannot enroll a terminal")
+	ErrNotPinSession      = errors.New("session is not a PIN switch")
+)
+
+// PinSwitchService lets staff switch users quickly on a shared POS terminal with a PIN
+// A manager first enrolls the device from their own session; the terminal token lives in Redis under
+// its SHA-256 hash and in an HttpOnly cookie. On an enrolled terminal, a cashier's PIN opens a
+// short-lived session that is never renewed, and switching or locking ends the previous one.
+type PinSwitchService struct {
+	redis          *redis.Client
+	pinRepo        *repository.PinRepository
+	authService    *AuthService
+	sessionManager *SessionManager
+	jwtService     *JWTService
+	auditPublisher *utils.AuditPublisher
+	sessionTTL     time.Duration
+	terminalTTL    time.Duration
+}
+
+func NewPinSwitchService(
+	redisClient *redis.Client,
+	pinRepo *repository.PinRepository,
+	authService *AuthService,
+	sessionManager *SessionManager,
+	jwtService *JWTService,
+	auditPublisher *utils.AuditPublisher,
+	sessionTTLMinutes int,
+	terminalTTLHours int,
+) *PinSwitchService {
+	return &PinSwitchService{
+		redis:          redisClient,
+		pinRepo:        pinRepo,
+		authService:    authService,
+		sessionManager: sessionManager,
+		jwtService:     jwtService,
+		auditPublisher: auditPublisher,
+		sessionTTL:     time.Duration(sessionTTLMinutes) * time.Minute,
+		terminalTTL:    time.Duration(terminalTTLHours) * time.Hour,
+	}
+}
+
+// TerminalTTL is how long an enrolled terminal stays enrolled
+func (s *PinSwitchService) TerminalTTL() time.Duration {
+	return s.terminalTTL
+}
+
+// pinTerminalID is the Redis-side identity of a terminal token, also recorded on its PIN sessions
+func pinTerminalID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// EnrollTerminal makes the device of a regular session a shared terminal and returns its token
+func (s *PinSwitchService) EnrollTerminal(ctx context.Context, session *models.SessionData) (*models.PinTerminal, string, error) {
+	if session.Impersonation != nil || session.PinSwitch != nil {
+		return nil, "", ErrPinTerminalSession
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	terminal := &models.PinTerminal{
+		ID:         pinTerminalID(token),
+		TenantID:   session.TenantID,
+		EnrolledBy: session.UserID,
+		ExpiresAt:  time.Now().Add(s.terminalTTL),
+	}
+	data, err := json.Marshal(terminal)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.redis.Set(ctx, "pin_terminal:"+terminal.ID, data, s.terminalTTL).Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to store terminal: %w", err)
+	}
+
+	log.Info().Str("tenant_id", session.TenantID).Str("user_id", session.UserID).Msg("PIN terminal enrolled")
+	return terminal, token, nil
+}
+
+// Terminal resolves an enrolled terminal from its token
+func (s *PinSwitchService) Terminal(ctx context.Context, token string) (*models.PinTerminal, error) {
+	if token == "" {
+		return nil, ErrPinTerminalInvalid
+	}
+	id := pinTerminalID(token)
+	data, err := s.redis.Get(ctx, "pin_terminal:"+id).Result()
+	if err == redis.Nil {
+		return nil, ErrPinTerminalInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal: %w", err)
+	}
+
+	var terminal models.PinTerminal
+	if err := json.Unmarshal([]byte(data), &terminal); err != nil {
+		return nil, fmt.Errorf("failed to decode terminal: %w", err)
+	}
+	terminal.ID = id
+	return &terminal, nil
+}
+
+// RemoveTerminal stops a device from being a shared terminal
+func (s *PinSwitchService) RemoveTerminal(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.redis.Del(ctx, "pin_terminal:"+pinTerminalID(token)).Err()
+}
+
+// ListUsers returns the staff of the terminal's tenant who can switch in with a PIN
+func (s *PinSwitchService) ListUsers(ctx context.Context, terminal *models.PinTerminal) ([]models.PinUser, error) {
+	userIDs, err := s.pinRepo.ListUserIDs(ctx, terminal.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PIN users: %w", err)
+	}
+
+	users := make([]models.PinUser, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := s.authService.getUserByID(ctx, terminal.TenantID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			continue
+		}
+		users = append(users, models.PinUser{
+			ID:        user.ID,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Role:      user.Role,
+		})
+	}
+	return users, nil
+}
+
+// Switch opens a PIN session for a cashier on the terminal and returns its token
+// previousSessionID is the session the terminal was on, if any; a PIN session of the same
+// terminal is ended so only one cashier is signed in on it at a time.
+func (s *PinSwitchService) Switch(ctx context.Context, terminal *models.PinTerminal, previousSessionID string, req *models.PinSwitchRequest, ipAddress, userAgent string) (*models.PinSwitchResponse, string, error) {
+	terminalFailuresKey := "pin_failures:terminal:" + terminal.ID
+	if failures, _ := s.redis.Get(ctx, terminalFailuresKey).Int(); failures >= maxTerminalPinFailures {
+		return nil, "", ErrPinLocked
+	}
+	if req.UserID == "" || req.Pin == "" {
+		return nil, "", ErrPinInvalid
+	}
+
+	user, err := s.authService.getUserByID(ctx, terminal.TenantID, req.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if user == nil {
+		s.recordFailure(ctx, terminalFailuresKey)
+		return nil, "", ErrPinInvalid
+	}
+	pinHash, locked, err := s.pinRepo.GetHash(ctx, user.TenantID, user.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load PIN: %w", err)
+	}
+	if locked {
+		s.publishAudit(ctx, terminal, user.TenantID, user.ID, "", ipAddress, userAgent, ErrPinUserLocked)
+		return nil, "", ErrPinUserLocked
+	}
+	if pinHash == "" || bcrypt.CompareHashAndPassword([]byte(pinHash), []byte(req.Pin)) != nil {
+		s.recordFailure(ctx, terminalFailuresKey)
+		if pinHash != "" {
+			locked, err = s.pinRepo.RecordFailure(ctx, user.TenantID, user.ID, maxPinFailures)
+			if err != nil {
+				log.Debug().Msgf("Warning: failed to count wrong PIN: %v\n", err)
+			}
+		}
+		s.publishAudit(ctx, terminal, user.TenantID, user.ID, "", ipAddress, userAgent, ErrPinInvalid)
+		if locked {
+			log.Info().Str("tenant_id", user.TenantID).Str("user_id", user.ID).Msg("PIN locked after too many wrong entries")
+			return nil, "", ErrPinUserLocked
+		}
+		return nil, "", ErrPinInvalid
+	}
+	if user.Status != "active" {
+		return nil, "", &UserStatusError{Status: user.Status}
+	}
+	if err := s.pinRepo.ClearFailures(ctx, user.TenantID, user.ID); err != nil {
+		log.Debug().Msgf("Warning: failed to clear wrong PIN count: %v\n", err)
+	}
+
+	s.endPinSession(ctx, terminal, previousSessionID)
+	if err := s.authService.enforceSessionLimit(ctx, user, ipAddress, userAgent); err != nil {
+		return nil, "", err
+	}
+
+	pinSwitch := &models.PinSwitch{
+		TerminalID: terminal.ID,
+		ExpiresAt:  time.Now().Add(s.sessionTTL),
+	}
+	if pinSwitch.ExpiresAt.After(terminal.ExpiresAt) {
+		pinSwitch.ExpiresAt = terminal.ExpiresAt
+	}
+	access, err := s.authService.ResolveAccess(ctx, user.TenantID, user.ID, user.Role)
+	if err != nil {
+		return nil, "", err
+	}
+	sessionData, sessionID, err := s.sessionManager.CreatePinSwitch(ctx, user, pinSwitch)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create PIN session: %w", err)
+	}
+
+	// Record the session alongside regular ones so it shows in the user's session history
+	session := &models.Session{
+		SessionID: sessionID,
+		TenantID:  user.TenantID,
+		UserID:    user.ID,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		ExpiresAt: pinSwitch.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.authService.sessionRepo.Create(ctx, session); err != nil {
+		log.Debug().Msgf("Warning: failed to create PIN session record: %v\n", err)
+	}
+
+	token, err := s.jwtService.GeneratePinSwitch(sessionID, sessionData, access)
+	if err != nil {
+		s.sessionManager.Delete(ctx, sessionID)
+		return nil, "", err
+	}
+	s.authService.updateLastLogin(ctx, user.ID)
+	s.publishAudit(ctx, terminal, user.TenantID, user.ID, sessionID, ipAddress, userAgent, nil)
+
+	response := &models.PinSwitchResponse{
+		User: models.UserInfo{
+			ID:          user.ID,
+			Email:       user.Email,
+			TenantID:    user.TenantID,
+			Role:        user.Role,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Locale:      user.Locale,
+			Permissions: access.Permissions,
+			CustomRole:  access.CustomRoleName,
+			OutletID:    access.OutletID,
+		},
+		ExpiresAt: pinSwitch.ExpiresAt,
+	}
+	return response, token, nil
+}
+
+// Lock ends the current PIN session, leaving the terminal on the switch screen
+func (s *PinSwitchService) Lock(ctx context.Context, sessionID string) error {
+	sessionData, err := s.authService.ValidateSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if sessionData.PinSwitch == nil {
+		return ErrNotPinSession
+	}
+	return s.authService.Logout(ctx, sessionID)
+}
+
+// endPinSession logs out the previous PIN session of a terminal; regular sessions are left alone
+func (s *PinSwitchService) endPinSession(ctx context.Context, terminal *models.PinTerminal, sessionID string) {
+	if sessionID == "" {
+		return
+	}
+	sessionData, err := s.sessionManager.Get(ctx, sessionID)
+	if err != nil || sessionData == nil || sessionData.PinSwitch == nil || sessionData.PinSwitch.TerminalID != terminal.ID {
+		return
+	}
+	if err := s.authService.Logout(ctx, sessionID); err != nil {
+		log.Debug().Msgf("Warning: failed to end previous PIN session: %v\n", err)
+	}
+}
+
+func (s *PinSwitchService) recordFailure(ctx context.Context, key string) {
+	failures, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		log.Debug().Msgf("Warning: failed to count wrong PIN: %v\n", err)
+		return
+	}
+	if failures == 1 {
+		s.redis.Expire(ctx, key, pinFailureWindow)
+	}
+}
+
+// publishAudit records a PIN switch, or a failed one when err is set
+func (s *PinSwitchService) publishAudit(ctx context.Context, terminal *models.PinTerminal, tenantID, userID, sessionID, ipAddress, userAgent string, err error) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &userID,
+		Action:       "LOGIN",
+		ResourceType: "authentication",
+		ResourceID:   userID,
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+		Metadata: map[string]interface{}{
+			"login_method":         "pin",
+			"terminal_enrolled_by": terminal.EnrolledBy,
+		},
+	}
+	if sessionID != "" {
+		auditEvent.SessionID = &sessionID
+	}
+	if err != nil {
+		auditEvent.Metadata["failure_reason"] = err.Error()
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish PIN switch audit event: %v\n", err)
+	}
+}
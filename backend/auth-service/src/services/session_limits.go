@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const maxSessionLimit = 20
+
+var ErrInvalidSessionLimits = errors.New("session limits out of range")
+
+// SessionLimitError refuses a login because the user already has as many sessions as allowed
+type SessionLimitError struct {
+	Limit int
+}
+
+func (e *SessionLimitError) Error() string {
+	return fmt.Sprintf("concurrent session limit of %d reached", e.Limit)
+}
+
+// SessionLimits enforces a tenant's limit on concurrent sessions per user
+// Depending on the policy, a login over the limit is refused or the user's oldest sessions are
+// logged out to make room. Operator impersonation sessions neither count nor get evicted.
+type SessionLimits struct {
+	limitRepo      *repository.SessionLimitRepository
+	sessionManager *SessionManager
+	auditPublisher *utils.AuditPublisher
+}
+
+func NewSessionLimits(limitRepo *repository.SessionLimitRepository, sessionManager *SessionManager, auditPublisher *utils.AuditPublisher) *SessionLimits {
+	return &SessionLimits{
+		limitRepo:      limitRepo,
+		sessionManager: sessionManager,
+		auditPublisher: auditPublisher,
+	}
+}
+
+// Check returns the sessions to log out before the user may start one more,
+// or a SessionLimitError when the tenant refuses logins over the limit
+func (l *SessionLimits) Check(ctx context.Context, user *models.User) ([]string, error) {
+	settings, err := l.limitRepo.GetSettings(ctx, user.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session limits: %w", err)
+	}
+	limit := settings.MaxSessions(user.Role)
+	if limit == 0 {
+		return nil, nil
+	}
+
+	active, err := l.sessionManager.ActiveSessions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) < limit {
+		return nil, nil
+	}
+
+	if settings.OnLimit != models.SessionLimitEvictOldest {
+		log.Warn().Str("tenant_id", user.TenantID).Str("user_id", user.ID).Int("limit", limit).
+			Msg("Login refused: concurrent session limit reached")
+		return nil, &SessionLimitError{Limit: limit}
+	}
+	return active[:len(active)-limit+1], nil
+}
+
+// GetSettings returns the session limits of the logged-in user's tenant
+func (l *SessionLimits) GetSettings(ctx context.Context, session *models.SessionData) (*models.TenantSessionLimits, error) {
+	return l.limitRepo.GetSettings(ctx, session.TenantID)
+}
+
+// UpdateSettings changes the session limits of the logged-in owner's tenant
+// New limits apply to the next login; sessions already open are not ended.
+func (l *SessionLimits) UpdateSettings(ctx context.Context, session *models.SessionData, req *models.UpdateSessionLimitsRequest, ipAddress, userAgent string) (*models.TenantSessionLimits, error) {
+	for _, limit := range []int{req.OwnerMaxSessions, req.ManagerMaxSessions, req.CashierMaxSessions} {
+		if limit < 0 || limit > maxSessionLimit {
+			return nil, ErrInvalidSessionLimits
+		}
+	}
+	if req.OnLimit == "" {
+		req.OnLimit = models.SessionLimitReject
+	}
+	if req.OnLimit != models.SessionLimitReject && req.OnLimit != models.SessionLimitEvictOldest {
+		return nil, ErrInvalidSessionLimits
+	}
+
+	previous, err := l.limitRepo.GetSettings(ctx, session.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session limits: %w", err)
+	}
+
+	userID := session.UserID
+	settings := &models.TenantSessionLimits{
+		TenantID:           session.TenantID,
+		OwnerMaxSessions:   req.OwnerMaxSessions,
+		ManagerMaxSessions: req.ManagerMaxSessions,
+		CashierMaxSessions: req.CashierMaxSessions,
+		OnLimit:            req.OnLimit,
+		UpdatedBy:          &userID,
+	}
+	if err := l.limitRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save session limits: %w", err)
+	}
+
+	l.publishAudit(ctx, session, ipAddress, userAgent, sessionLimitsAuditValue(previous), sessionLimitsAuditValue(settings))
+	return settings, nil
+}
+
+func sessionLimitsAuditValue(settings *models.TenantSessionLimits) map[string]interface{} {
+	return map[string]interface{}{
+		"owner_max_sessions":   settings.OwnerMaxSessions,
+		"manager_max_sessions": settings.ManagerMaxSessions,
+		"cashier_max_sessions": settings.CashierMaxSessions,
+		"on_limit":             settings.OnLimit,
+	}
+}
+
+func (l *SessionLimits) publishAudit(ctx context.Context, session *models.SessionData, ipAddress, userAgent string, before, after map[string]interface{}) {
+	if l.auditPublisher == nil {
+		return
+	}
+	userID := session.UserID
+	auditEvent := &utils.AuditEvent{
+		TenantID:     session.TenantID,
+		ActorType:    "user",
+		ActorID:      &userID,
+		Action:       "UPDATE",
+		ResourceType: "session_limits",
+		ResourceID:   session.TenantID,
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+		BeforeValue:  before,
+		AfterValue:   after,
+	}
+	if err := l.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish session limits audit event: %v\n", err)
+	}
+}
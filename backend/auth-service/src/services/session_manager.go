@@ -25,9 +25,35 @@ func NewSessionManager(redisClient *redis.Client, ttlMinutes int) *SessionManage
 
 // Create creates a new session in Redis
 func (sm *SessionManager) Create(ctx context.Context, user *models.User) (string, error) {
-	sessionID := uuid.New().String()
+	return sm.create(ctx, newSessionData(user), sm.ttl)
+}
 
-	sessionData := models.SessionData{
+// CreateImpersonation creates a session an operator uses on the user's behalf
+// It lives only until the impersonation expires, whatever the regular session TTL.
+func (sm *SessionManager) CreateImpersonation(ctx context.Context, user *models.User, impersonation *models.Impersonation) (*models.SessionData, string, error) {
+	sessionData := newSessionData(user)
+	sessionData.Impersonation = impersonation
+	sessionID, err := sm.create(ctx, sessionData, time.Until(impersonation.ExpiresAt))
+	if err != nil {
+		return nil, "", err
+	}
+	return &sessionData, sessionID, nil
+}
+
+// CreatePinSwitch creates the session of a cashier who switched in with their PIN
+// It lives only until the PIN switch expires and is never renewed.
+func (sm *SessionManager) CreatePinSwitch(ctx context.Context, user *models.User, pinSwitch *models.PinSwitch) (*models.SessionData, string, error) {
+	sessionData := newSessionData(user)
+	sessionData.PinSwitch = pinSwitch
+	sessionID, err := sm.create(ctx, sessionData, time.Until(pinSwitch.ExpiresAt))
+	if err != nil {
+		return nil, "", err
+	}
+	return &sessionData, sessionID, nil
+}
+
+func newSessionData(user *models.User) models.SessionData {
+	return models.SessionData{
 		UserID:    user.ID,
 		TenantID:  user.TenantID,
 		Email:     user.Email,
@@ -36,6 +62,10 @@ func (sm *SessionManager) Create(ctx context.Context, user *models.User) (string
 		LastName:  user.LastName,
 		CreatedAt: time.Now().Unix(),
 	}
+}
+
+func (sm *SessionManager) create(ctx context.Context, sessionData models.SessionData, ttl time.Duration) (string, error) {
+	sessionID := uuid.New().String()
 
 	data, err := json.Marshal(sessionData)
 	if err != nil {
@@ -43,14 +73,50 @@ func (sm *SessionManager) Create(ctx context.Context, user *models.User) (string
 	}
 
 	key := fmt.Sprintf("session:%s", sessionID)
-	err = sm.redis.Set(ctx, key, data, sm.ttl).Err()
+	err = sm.redis.Set(ctx, key, data, ttl).Err()
 	if err != nil {
 		return "", fmt.Errorf("failed to store session in Redis: %w", err)
 	}
 
+	// Index the user's own sessions for the concurrent session limit; an operator's
+	// impersonation session is not the user's and does not count
+	if sessionData.Impersonation == nil {
+		indexKey := userSessionsKey(sessionData.UserID)
+		sm.redis.ZAdd(ctx, indexKey, &redis.Z{Score: float64(time.Now().UnixNano()), Member: sessionID})
+		sm.redis.Expire(ctx, indexKey, sm.ttl)
+	}
+
 	return sessionID, nil
 }
 
+func userSessionsKey(userID string) string {
+	return fmt.Sprintf("user_sessions:%s", userID)
+}
+
+// ActiveSessions returns the IDs of a user's live sessions, oldest first
+// Sessions that expired or were deleted are dropped from the index as they are found.
+func (sm *SessionManager) ActiveSessions(ctx context.Context, userID string) ([]string, error) {
+	indexKey := userSessionsKey(userID)
+	sessionIDs, err := sm.redis.ZRange(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user sessions: %w", err)
+	}
+
+	active := make([]string, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		exists, err := sm.Exists(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			active = append(active, sessionID)
+		} else {
+			sm.redis.ZRem(ctx, indexKey, sessionID)
+		}
+	}
+	return active, nil
+}
+
 // Get retrieves a session from Redis
 func (sm *SessionManager) Get(ctx context.Context, sessionID string) (*models.SessionData, error) {
 	key := fmt.Sprintf("session:%s", sessionID)
@@ -150,6 +216,45 @@ func (sm *SessionManager) DeleteByUserID(ctx context.Context, userID string) err
 	return nil
 }
 
+// DeleteForUsers deletes the sessions of many users in a single scan, keeping exceptSessionID
+func (sm *SessionManager) DeleteForUsers(ctx context.Context, userIDs []string, exceptSessionID string) error {
+	targets := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		targets[userID] = true
+	}
+	exceptKey := fmt.Sprintf("session:%s", exceptSessionID)
+
+	iter := sm.redis.Scan(ctx, 0, "session:*", 0).Iterator()
+	var keysToDelete []string
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if key == exceptKey {
+			continue
+		}
+		data, err := sm.redis.Get(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+		var sessionData models.SessionData
+		if err := json.Unmarshal([]byte(data), &sessionData); err != nil {
+			continue
+		}
+		if targets[sessionData.UserID] {
+			keysToDelete = append(keysToDelete, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan Redis keys: %w", err)
+	}
+
+	if len(keysToDelete) > 0 {
+		if err := sm.redis.Del(ctx, keysToDelete...).Err(); err != nil {
+			return fmt.Errorf("failed to delete user sessions: %w", err)
+		}
+	}
+	return nil
+}
+
 // GetTTL returns the remaining TTL for a session
 func (sm *SessionManager) GetTTL(ctx context.Context, sessionID string) (time.Duration, error) {
 	key := fmt.Sprintf("session:%s", sessionID)
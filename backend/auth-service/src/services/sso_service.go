@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/repository"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// ssoStateTTL is how long the user may take to sign in at the provider
+const ssoStateTTL = 10 * time.Minute
+
+var (
+	ErrSSOState         = errors.New("sign-in request is missing or expired")
+	ErrSSONotEnabled    = errors.New("single sign-on is not enabled for this tenant")
+	ErrSSONotConfigured = errors.New("single sign-on is not configured on this server")
+	ErrSSOEmailRequired = errors.New("identity provider did not return a verified email")
+	ErrSSOUserNotFound  = errors.New("no active user has this email")
+	ErrInvalidSSOPolicy = errors.New("SSO-only login requires an enabled identity provider")
+)
+
+// SSOService logs staff in through an OpenID Connect identity provider (Google)
+// A provider account is linked to the tenant's user with the same verified email on its
+// first login; later logins follow the link even if the user changes their email.
+type SSOService struct {
+	ssoRepo        *repository.SSORepository
+	redis          *redis.Client
+	authService    *AuthService
+	auditPublisher *utils.AuditPublisher
+	google         *googleOIDCClient
+}
+
+func NewSSOService(
+	ssoRepo *repository.SSORepository,
+	redisClient *redis.Client,
+	authService *AuthService,
+	auditPublisher *utils.AuditPublisher,
+	googleConfig GoogleOIDCConfig,
+) *SSOService {
+	return &SSOService{
+		ssoRepo:        ssoRepo,
+		redis:          redisClient,
+		authService:    authService,
+		auditPublisher: auditPublisher,
+		google:         newGoogleOIDCClient(googleConfig),
+	}
+}
+
+func (s *SSOService) googleConfigured() bool {
+	return s.google.config.ClientID != "" && s.google.config.ClientSecret != "" && s.google.config.RedirectURL != ""
+}
+
+// BeginGoogleLogin returns the Google URL the browser is redirected to
+// tenantSlug scopes the login to a tenant; without it the tenant is found from the email.
+func (s *SSOService) BeginGoogleLogin(ctx context.Context, tenantSlug string) (string, error) {
+	if !s.googleConfigured() {
+		return "", ErrSSONotConfigured
+	}
+
+	state := models.SSOLoginState{Provider: models.SSOProviderGoogle}
+	if tenantSlug != "" {
+		tenantID, err := s.ssoRepo.GetTenantIDBySlug(ctx, tenantSlug)
+		if err != nil {
+			return "", fmt.Errorf("failed to lookup tenant: %w", err)
+		}
+		if tenantID == "" {
+			return "", ErrSSONotEnabled
+		}
+		settings, err := s.ssoRepo.GetSettings(ctx, tenantID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get SSO settings: %w", err)
+		}
+		if !settings.GoogleEnabled {
+			return "", ErrSSONotEnabled
+		}
+		state.TenantID = tenantID
+	}
+
+	var err error
+	if state.Nonce, err = randomToken(); err != nil {
+		return "", err
+	}
+	if state.CodeVerifier, err = randomToken(); err != nil {
+		return "", err
+	}
+	stateToken, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SSO state: %w", err)
+	}
+	if err := s.redis.Set(ctx, ssoStateKey(stateToken), data, ssoStateTTL).Err(); err != nil {
+		return "", fmt.Errorf("failed to store SSO state in Redis: %w", err)
+	}
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+	return s.google.authURL(stateToken, state.Nonce, base64.RawURLEncoding.EncodeToString(challenge[:])), nil
+}
+
+// FinishGoogleLogin handles Google's callback and starts a session for the linked user
+func (s *SSOService) FinishGoogleLogin(ctx context.Context, code, stateToken, ipAddress, userAgent string) (*models.LoginResponse, string, error) {
+	if !s.googleConfigured() {
+		return nil, "", ErrSSONotConfigured
+	}
+	state, err := s.consumeState(ctx, stateToken, models.SSOProviderGoogle)
+	if err != nil {
+		return nil, "", err
+	}
+	if code == "" {
+		return nil, "", fmt.Errorf("%w: callback has no authorization code", ErrSSOVerification)
+	}
+
+	rawIDToken, err := s.google.exchangeCode(ctx, code, state.CodeVerifier)
+	if err != nil {
+		return nil, "", err
+	}
+	claims, err := s.google.verifyIDToken(ctx, rawIDToken, state.Nonce)
+	if err != nil {
+		return nil, "", err
+	}
+	// Linking by email is only safe when Google vouches for the address
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || !claims.EmailVerified {
+		return nil, "", ErrSSOEmailRequired
+	}
+
+	tenantID := state.TenantID
+	if tenantID == "" {
+		if tenantID, err = s.authService.getTenantIDByEmail(ctx, email); err != nil {
+			return nil, "", fmt.Errorf("failed to lookup tenant: %w", err)
+		}
+		if tenantID == "" {
+			return nil, "", ErrSSOUserNotFound
+		}
+	}
+	settings, err := s.ssoRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get SSO settings: %w", err)
+	}
+	if !settings.GoogleEnabled {
+		return nil, "", ErrSSONotEnabled
+	}
+
+	identity, err := s.ssoRepo.GetIdentity(ctx, tenantID, models.SSOProviderGoogle, claims.Subject)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get linked identity: %w", err)
+	}
+	if identity == nil {
+		if identity, err = s.linkByEmail(ctx, tenantID, email, claims.Subject, ipAddress, userAgent); err != nil {
+			return nil, "", err
+		}
+	}
+
+	response, token, err := s.authService.LoginWithSSO(ctx, tenantID, identity.UserID, models.SSOProviderGoogle, ipAddress, userAgent)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.ssoRepo.RecordLogin(ctx, identity.ID); err != nil {
+		log.Debug().Msgf("Warning: failed to record SSO login: %v\n", err)
+	}
+	return response, token, nil
+}
+
+// linkByEmail links a provider account to the tenant's active user with the same email
+func (s *SSOService) linkByEmail(ctx context.Context, tenantID, email, subject, ipAddress, userAgent string) (*models.UserIdentity, error) {
+	user, err := s.authService.getUserByEmailAndTenant(ctx, email, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrSSOUserNotFound
+	}
+
+	identity := &models.UserIdentity{
+		TenantID: tenantID,
+		UserID:   user.ID,
+		Provider: models.SSOProviderGoogle,
+		Subject:  subject,
+	}
+	if err := s.ssoRepo.LinkIdentity(ctx, identity); err != nil {
+		if errors.Is(err, repository.ErrIdentityLinked) {
+			return nil, fmt.Errorf("%w: account is linked to another user", ErrSSOVerification)
+		}
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	if s.auditPublisher != nil {
+		userID := user.ID
+		auditEvent := &utils.AuditEvent{
+			TenantID:     tenantID,
+			ActorType:    "user",
+			ActorID:      &userID,
+			Action:       "CREATE",
+			ResourceType: "user_identity",
+			ResourceID:   identity.ID,
+			IPAddress:    &ipAddress,
+			UserAgent:    &userAgent,
+			Metadata: map[string]interface{}{
+				"provider": identity.Provider,
+				"user_id":  user.ID,
+			},
+		}
+		if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+			log.Debug().Msgf("Failed to publish identity link audit event: %v\n", err)
+		}
+	}
+	log.Info().Str("tenant_id", tenantID).Str("user_id", user.ID).Str("provider", identity.Provider).Msg("SSO identity linked")
+	return identity, nil
+}
+
+// GetSettings returns the SSO policy of the logged-in user's tenant
+func (s *SSOService) GetSettings(ctx context.Context, session *models.SessionData) (*models.TenantSSOSettings, error) {
+	return s.ssoRepo.GetSettings(ctx, session.TenantID)
+}
+
+// UpdateSettings changes the SSO policy of the logged-in owner's tenant
+func (s *SSOService) UpdateSettings(ctx context.Context, session *models.SessionData, req *models.UpdateSSOSettingsRequest, ipAddress, userAgent string) (*models.TenantSSOSettings, error) {
+	// Enforcing SSO without a working provider would lock every user out
+	if req.SSOOnly && !req.GoogleEnabled {
+		return nil, ErrInvalidSSOPolicy
+	}
+	if req.GoogleEnabled && !s.googleConfigured() {
+		return nil, ErrSSONotConfigured
+	}
+
+	previous, err := s.ssoRepo.GetSettings(ctx, session.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SSO settings: %w", err)
+	}
+
+	userID := session.UserID
+	settings := &models.TenantSSOSettings{
+		TenantID:      session.TenantID,
+		GoogleEnabled: req.GoogleEnabled,
+		SSOOnly:       req.SSOOnly,
+		UpdatedBy:     &userID,
+	}
+	if err := s.ssoRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save SSO settings: %w", err)
+	}
+
+	if s.auditPublisher != nil {
+		auditEvent := &utils.AuditEvent{
+			TenantID:     session.TenantID,
+			ActorType:    "user",
+			ActorID:      &userID,
+			Action:       "UPDATE",
+			ResourceType: "sso_settings",
+			ResourceID:   session.TenantID,
+			IPAddress:    &ipAddress,
+			UserAgent:    &userAgent,
+			BeforeValue: map[string]interface{}{
+				"google_enabled": previous.GoogleEnabled,
+				"sso_only":       previous.SSOOnly,
+			},
+			AfterValue: map[string]interface{}{
+				"google_enabled": settings.GoogleEnabled,
+				"sso_only":       settings.SSOOnly,
+			},
+		}
+		if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+			log.Debug().Msgf("Failed to publish SSO settings audit event: %v\n", err)
+		}
+	}
+	return settings, nil
+}
+
+// consumeState takes a pending sign-in out of Redis so a callback cannot be replayed
+func (s *SSOService) consumeState(ctx context.Context, stateToken, provider string) (*models.SSOLoginState, error) {
+	if stateToken == "" {
+		return nil, ErrSSOState
+	}
+	data, err := s.redis.GetDel(ctx, ssoStateKey(stateToken)).Result()
+	if err == redis.Nil {
+		return nil, ErrSSOState
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SSO state from Redis: %w", err)
+	}
+
+	var state models.SSOLoginState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SSO state: %w", err)
+	}
+	if state.Provider != provider {
+		return nil, ErrSSOState
+	}
+	return &state, nil
+}
+
+func ssoStateKey(state string) string {
+	return fmt.Sprintf("sso_state:%s", state)
+}
+
+// randomToken returns 32 random bytes, base64url encoded
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/pos/auth-service/src/models"
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrStepUpImpersonation refuses step-up to a platform operator acting as a user
+var ErrStepUpImpersonation = errors.New("impersonation sessions cannot perform sensitive operations")
+
+// StepUpService re-authenticates a logged-in user before high-risk operations
+// A successful password or passkey check yields a short-lived step-up token bound to the session,
+// which the API gateway requires on flagged routes such as payment credential changes and PII exports.
+type StepUpService struct {
+	authService    *AuthService
+	passkeyService *PasskeyService
+	jwtService     *JWTService
+	auditPublisher *utils.AuditPublisher
+	ttl            time.Duration
+}
+
+func NewStepUpService(authService *AuthService, passkeyService *PasskeyService, jwtService *JWTService, auditPublisher *utils.AuditPublisher, ttlMinutes int) *StepUpService {
+	return &StepUpService{
+		authService:    authService,
+		passkeyService: passkeyService,
+		jwtService:     jwtService,
+		auditPublisher: auditPublisher,
+		ttl:            time.Duration(ttlMinutes) * time.Minute,
+	}
+}
+
+// TTL is how long a step-up token stays valid
+func (s *StepUpService) TTL() time.Duration {
+	return s.ttl
+}
+
+// WithPassword issues a step-up token once the session's user has re-entered their password
+func (s *StepUpService) WithPassword(ctx context.Context, session *models.SessionData, sessionID, password, ipAddress, userAgent string) (*models.StepUpResponse, string, error) {
+	if session.Impersonation != nil {
+		return nil, "", ErrStepUpImpersonation
+	}
+	if err := s.authService.ReauthenticatePassword(ctx, session, password, ipAddress, userAgent); err != nil {
+		s.publishAudit(ctx, session, models.StepUpPassword, err, ipAddress, userAgent)
+		return nil, "", err
+	}
+	return s.issue(ctx, session, sessionID, models.StepUpPassword, ipAddress, userAgent)
+}
+
+// PasskeyOptions returns the options for confirming the session's user with one of their passkeys
+func (s *StepUpService) PasskeyOptions(ctx context.Context, session *models.SessionData) (*models.PasskeyRequestOptions, error) {
+	if session.Impersonation != nil {
+		return nil, ErrStepUpImpersonation
+	}
+	return s.passkeyService.BeginStepUp(ctx, session)
+}
+
+// WithPasskey issues a step-up token once the session's user has signed a challenge with their passkey
+func (s *StepUpService) WithPasskey(ctx context.Context, session *models.SessionData, sessionID string, req *models.PasskeyLoginRequest, ipAddress, userAgent string) (*models.StepUpResponse, string, error) {
+	if session.Impersonation != nil {
+		return nil, "", ErrStepUpImpersonation
+	}
+	if err := s.passkeyService.VerifyStepUp(ctx, session, req); err != nil {
+		s.publishAudit(ctx, session, models.StepUpPasskey, err, ipAddress, userAgent)
+		return nil, "", err
+	}
+	return s.issue(ctx, session, sessionID, models.StepUpPasskey, ipAddress, userAgent)
+}
+
+func (s *StepUpService) issue(ctx context.Context, session *models.SessionData, sessionID, method, ipAddress, userAgent string) (*models.StepUpResponse, string, error) {
+	token, expiresAt, err := s.jwtService.GenerateStepUp(sessionID, session.UserID, method, s.ttl)
+	if err != nil {
+		return nil, "", err
+	}
+	s.publishAudit(ctx, session, method, nil, ipAddress, userAgent)
+	return &models.StepUpResponse{Method: method, ExpiresAt: expiresAt}, token, nil
+}
+
+// publishAudit records a step-up attempt; failures carry the reason
+func (s *StepUpService) publishAudit(ctx context.Context, session *models.SessionData, method string, failure error, ipAddress, userAgent string) {
+	if s.auditPublisher == nil {
+		return
+	}
+	metadata := map[string]interface{}{
+		"step_up":      true,
+		"login_method": method,
+	}
+	if failure != nil {
+		reason := "error"
+		switch failure.(type) {
+		case *AccountLockedError:
+			reason = "account_locked"
+		case *UserStatusError:
+			reason = "account_disabled"
+		}
+		if failure == ErrInvalidCredentials {
+			reason = "invalid_credentials"
+		}
+		metadata["failure_reason"] = reason
+	}
+
+	userID := session.UserID
+	auditEvent := &utils.AuditEvent{
+		TenantID:     session.TenantID,
+		ActorType:    "user",
+		ActorID:      &userID,
+		Action:       "LOGIN",
+		ResourceType: "authentication",
+		ResourceID:   session.UserID,
+		IPAddress:    &ipAddress,
+		UserAgent:    &userAgent,
+		Metadata:     metadata,
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish step-up audit event: %v\n", err)
+	}
+}
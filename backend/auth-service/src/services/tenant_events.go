@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+)
+
+// TenantEvent is a tenant lifecycle change published by tenant-service on the tenant-events topic
+type TenantEvent struct {
+	EventID   string                 `json:"event_id"`
+	EventType string                 `json:"event_type"`
+	TenantID  string                 `json:"tenant_id"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// tenantBlockingEvents end every session of the tenant's users, with the audited reason
+var tenantBlockingEvents = map[string]string{
+	"tenant.suspended":        "tenant_suspended",
+	"tenant.deletion_pending": "tenant_deletion_pending",
+}
+
+// TenantEventHandler ends the sessions of all users of tenants suspended or scheduled for deletion
+// The gateway already refuses their requests; this keeps the sessions from outliving a reactivation.
+type TenantEventHandler struct {
+	users *UserEventHandler
+}
+
+func NewTenantEventHandler(users *UserEventHandler) *TenantEventHandler {
+	return &TenantEventHandler{users: users}
+}
+
+// Handle processes one message from the tenant-events topic; other event types are ignored
+func (h *TenantEventHandler) Handle(ctx context.Context, payload []byte) error {
+	var event TenantEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Error().Err(err).Msg("Discarding malformed tenant event")
+		return nil
+	}
+	reason, ok := tenantBlockingEvents[event.EventType]
+	if !ok || event.TenantID == "" {
+		return nil
+	}
+
+	userIDs, err := h.users.authService.sessionRepo.FindUserIDsByTenantID(ctx, event.TenantID)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := h.users.endSessions(ctx, event.TenantID, userID, event.EventID, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
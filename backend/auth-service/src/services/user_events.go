@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pos/auth-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// UserEvent is an account lifecycle change published by user-service on the user-events topic
+type UserEvent struct {
+	EventID   string                 `json:"event_id"`
+	EventType string                 `json:"event_type"`
+	TenantID  string                 `json:"tenant_id"`
+	UserID    string                 `json:"user_id"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// UserEventHandler ends the sessions of users deactivated in user-service
+// Login already refuses suspended users; this closes the sessions they opened before. Introspection
+// results cached by backend services may stay active for up to the introspection cache TTL.
+type UserEventHandler struct {
+	authService    *AuthService
+	sessionManager *SessionManager
+	auditPublisher *utils.AuditPublisher
+}
+
+func NewUserEventHandler(authService *AuthService, sessionManager *SessionManager, auditPublisher *utils.AuditPublisher) *UserEventHandler {
+	return &UserEventHandler{
+		authService:    authService,
+		sessionManager: sessionManager,
+		auditPublisher: auditPublisher,
+	}
+}
+
+// Handle processes one message from the user-events topic; other event types are ignored
+func (h *UserEventHandler) Handle(ctx context.Context, payload []byte) error {
+	var event UserEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Error().Err(err).Msg("Discarding malformed user event")
+		return nil
+	}
+	if event.EventType != "user.deactivated" || event.UserID == "" {
+		return nil
+	}
+	return h.endSessions(ctx, event.TenantID, event.UserID, event.EventID, "user_deactivated")
+}
+
+// endSessions logs out every session of the user, auditing each with reason
+// The Redis index misses operator impersonation sessions, so the session records are read as well.
+func (h *UserEventHandler) endSessions(ctx context.Context, tenantID, userID, eventID, reason string) error {
+	sessionIDs, err := h.sessionManager.ActiveSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	records, err := h.authService.sessionRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to find sessions of user: %w", err)
+	}
+	for _, record := range records {
+		sessionIDs = append(sessionIDs, record.SessionID)
+	}
+
+	seen := make(map[string]bool, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		if seen[sessionID] {
+			continue
+		}
+		seen[sessionID] = true
+
+		if err := h.authService.Logout(ctx, sessionID); err != nil {
+			log.Error().Err(err).Str("user_id", userID).Str("reason", reason).Msg("Failed to end session")
+			continue
+		}
+		h.publishAudit(ctx, tenantID, userID, eventID, reason, sessionID)
+	}
+
+	log.Info().Str("tenant_id", tenantID).Str("user_id", userID).Str("reason", reason).Int("sessions", len(seen)).
+		Msg("Sessions of user ended")
+	return nil
+}
+
+func (h *UserEventHandler) publishAudit(ctx context.Context, tenantID, userID, eventID, reason, sessionID string) {
+	if h.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "system",
+		SessionID:    &sessionID,
+		Action:       "LOGOUT",
+		ResourceType: "user",
+		ResourceID:   userID,
+		Metadata: map[string]interface{}{
+			"event":    "session_revoked",
+			"reason":   reason,
+			"event_id": eventID,
+		},
+	}
+	if err := h.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		log.Debug().Msgf("Failed to publish session revocation audit event: %v\n", err)
+	}
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/pos/auth-service/src/utils"
+)
+
+// COSE algorithm identifiers accepted for passkeys, in order of preference
+const (
+	coseAlgES256 int64 = -7
+	coseAlgEdDSA int64 = -8
+	coseAlgRS256 int64 = -257
+)
+
+// supportedPasskeyAlgorithms is offered to authenticators when registering a passkey
+var supportedPasskeyAlgorithms = []int64{coseAlgES256, coseAlgEdDSA, coseAlgRS256}
+
+// Authenticator data flags (WebAuthn §6.1)
+const (
+	authDataUserPresent      byte = 0x01
+	authDataUserVerified     byte = 0x04
+	authDataAttestedCredData byte = 0x40
+)
+
+var (
+	ErrPasskeyVerification = errors.New("passkey verification failed")
+	ErrPasskeyChallenge    = errors.New("passkey challenge is missing or expired")
+)
+
+// WebAuthnConfig identifies this site to authenticators
+type WebAuthnConfig struct {
+	RPID    string   // Domain the passkeys are bound to, e.g. pos.example.com
+	RPName  string   // Shown by the authenticator when creating a passkey
+	Origins []string // Origins allowed to run ceremonies, e.g. https://pos.example.com
+}
+
+// clientData is the part of CollectedClientData the server checks
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// authenticatorData is parsed authenticator data, with the attested credential when present
+type authenticatorData struct {
+	raw          []byte
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte // COSE_Key
+}
+
+// decodeBase64URL decodes the unpadded base64url used by WebAuthn JSON, tolerating padding
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(trimBase64Padding(s))
+}
+
+func trimBase64Padding(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// parseClientData checks the ceremony type and origin and returns the challenge it answers
+func (cfg WebAuthnConfig) parseClientData(raw []byte, ceremony string) (string, error) {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return "", fmt.Errorf("%w: malformed client data", ErrPasskeyVerification)
+	}
+	if data.Type != ceremony {
+		return "", fmt.Errorf("%w: unexpected ceremony %q", ErrPasskeyVerification, data.Type)
+	}
+	if data.CrossOrigin {
+		return "", fmt.Errorf("%w: cross-origin ceremony", ErrPasskeyVerification)
+	}
+	allowed := false
+	for _, origin := range cfg.Origins {
+		if data.Origin == origin {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", fmt.Errorf("%w: origin %q not allowed", ErrPasskeyVerification, data.Origin)
+	}
+	if data.Challenge == "" {
+		return "", ErrPasskeyChallenge
+	}
+	return trimBase64Padding(data.Challenge), nil
+}
+
+// parseAuthenticatorData checks the RP ID hash and the user presence and verification flags
+func (cfg WebAuthnConfig) parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrPasskeyVerification)
+	}
+	data := &authenticatorData{
+		raw:       raw,
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+
+	expected := sha256.Sum256([]byte(cfg.RPID))
+	if subtle.ConstantTimeCompare(data.rpIDHash, expected[:]) != 1 {
+		return nil, fmt.Errorf("%w: passkey belongs to another site", ErrPasskeyVerification)
+	}
+	// Staff log in on shared devices, so the authenticator must check who is holding it
+	if data.flags&authDataUserPresent == 0 || data.flags&authDataUserVerified == 0 {
+		return nil, fmt.Errorf("%w: user was not verified", ErrPasskeyVerification)
+	}
+
+	if data.flags&authDataAttestedCredData != 0 {
+		rest := raw[37:]
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrPasskeyVerification)
+		}
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: invalid credential ID", ErrPasskeyVerification)
+		}
+		data.credentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		// The COSE key runs up to the extensions, if any
+		_, after, err := utils.DecodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid credential public key", ErrPasskeyVerification)
+		}
+		data.publicKey = rest[:len(rest)-len(after)]
+	}
+	return data, nil
+}
+
+// parseAttestationObject returns the authenticator data of a registration
+// Attestation statements are not checked: options ask for "none", so the server
+// trusts the passkey because the logged-in user created it, not because of its make.
+func (cfg WebAuthnConfig) parseAttestationObject(raw []byte) (*authenticatorData, error) {
+	decoded, _, err := utils.DecodeCBOR(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed attestation object", ErrPasskeyVerification)
+	}
+	object, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed attestation object", ErrPasskeyVerification)
+	}
+	authData, ok := object["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: attestation object has no authenticator data", ErrPasskeyVerification)
+	}
+
+	data, err := cfg.parseAuthenticatorData(authData)
+	if err != nil {
+		return nil, err
+	}
+	if data.credentialID == nil {
+		return nil, fmt.Errorf("%w: no credential was created", ErrPasskeyVerification)
+	}
+	return data, nil
+}
+
+// coseAlgorithm returns the algorithm of a COSE key after checking it can be used
+func coseAlgorithm(coseKey []byte) (int64, error) {
+	_, alg, err := parseCOSEKey(coseKey)
+	return alg, err
+}
+
+// parseCOSEKey converts a COSE_Key (RFC 9053) to a Go public key
+func parseCOSEKey(coseKey []byte) (crypto.PublicKey, int64, error) {
+	decoded, _, err := utils.DecodeCBOR(coseKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: invalid public key", ErrPasskeyVerification)
+	}
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: invalid public key", ErrPasskeyVerification)
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+	switch {
+	case alg == coseAlgES256 && kty == 2:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			break
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			break
+		}
+		return pub, alg, nil
+	case alg == coseAlgEdDSA && kty == 1:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			break
+		}
+		return ed25519.PublicKey(x), alg, nil
+	case alg == coseAlgRS256 && kty == 3:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			break
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, alg, nil
+	}
+	return nil, 0, fmt.Errorf("%w: unsupported public key type", ErrPasskeyVerification)
+}
+
+// verifyAssertionSignature checks an assertion signature over authenticatorData || SHA-256(clientDataJSON)
+func verifyAssertionSignature(coseKey, authData, clientDataJSON, signature []byte) error {
+	key, _, err := parseCOSEKey(coseKey)
+	if err != nil {
+		return err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+	digest := sha256.Sum256(signed)
+
+	valid := false
+	switch pub := key.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(pub, digest[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, signed, signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+	}
+	if !valid {
+		return fmt.Errorf("%w: invalid signature", ErrPasskeyVerification)
+	}
+	return nil
+}
+
+// sameCredentialID compares the credential ID the client reported with the one it signed for
+func sameCredentialID(a, b []byte) bool {
+	return len(a) > 0 && bytes.Equal(a, b)
+}
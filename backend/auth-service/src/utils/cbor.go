@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCBOR is returned for malformed or unsupported CBOR input
+var ErrInvalidCBOR = errors.New("invalid CBOR data")
+
+// maxCBORDepth bounds nesting so hostile input cannot exhaust the stack
+const maxCBORDepth = 16
+
+// DecodeCBOR decodes one CBOR data item and returns it with the bytes that follow it
+// This covers the subset WebAuthn uses (RFC 8949 without tags, floats or indefinite
+// lengths): unsigned and negative integers become int64, byte strings []byte, text
+// strings string, arrays []interface{} and maps map[interface{}]interface{}.
+func DecodeCBOR(data []byte) (interface{}, []byte, error) {
+	return decodeCBOR(data, 0)
+}
+
+func decodeCBOR(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > maxCBORDepth || len(data) == 0 {
+		return nil, nil, ErrInvalidCBOR
+	}
+
+	major := data[0] >> 5
+	arg, rest, err := cborArgument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0: // Unsigned integer
+		if arg > 1<<63-1 {
+			return nil, nil, ErrInvalidCBOR
+		}
+		return int64(arg), rest, nil
+	case 1: // Negative integer, -1 - arg
+		if arg > 1<<63-1 {
+			return nil, nil, ErrInvalidCBOR
+		}
+		return -1 - int64(arg), rest, nil
+	case 2, 3: // Byte or text string
+		if arg > uint64(len(rest)) {
+			return nil, nil, ErrInvalidCBOR
+		}
+		value := rest[:arg]
+		if major == 3 {
+			return string(value), rest[arg:], nil
+		}
+		return append([]byte(nil), value...), rest[arg:], nil
+	case 4: // Array
+		if arg > uint64(len(rest)) {
+			return nil, nil, ErrInvalidCBOR
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var item interface{}
+			item, rest, err = decodeCBOR(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case 5: // Map; keys must be integers or text strings
+		if arg > uint64(len(rest)) {
+			return nil, nil, ErrInvalidCBOR
+		}
+		entries := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			var key, value interface{}
+			key, rest, err = decodeCBOR(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, ErrInvalidCBOR
+			}
+			value, rest, err = decodeCBOR(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			entries[key] = value
+		}
+		return entries, rest, nil
+	case 7: // Simple values
+		switch data[0] & 0x1f {
+		case 20:
+			return false, rest, nil
+		case 21:
+			return true, rest, nil
+		case 22:
+			return nil, rest, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: unsupported item 0x%02x", ErrInvalidCBOR, data[0])
+}
+
+// cborArgument reads the argument of a data item's initial byte and returns the bytes after it
+func cborArgument(data []byte) (uint64, []byte, error) {
+	info := data[0] & 0x1f
+	rest := data[1:]
+	switch {
+	case info < 24:
+		return uint64(info), rest, nil
+	case info == 24 && len(rest) >= 1:
+		return uint64(rest[0]), rest[1:], nil
+	case info == 25 && len(rest) >= 2:
+		return uint64(binary.BigEndian.Uint16(rest)), rest[2:], nil
+	case info == 26 && len(rest) >= 4:
+		return uint64(binary.BigEndian.Uint32(rest)), rest[4:], nil
+	case info == 27 && len(rest) >= 8:
+		return binary.BigEndian.Uint64(rest), rest[8:], nil
+	}
+	return 0, nil, ErrInvalidCBOR
+}
@@ -0,0 +1,6 @@
+-- Migration: 000065_add_cash_tender_to_payment_records.down.sql
+-- Purpose: Rollback cash tender tracking on payment_records
+
+ALTER TABLE payment_records
+DROP COLUMN IF EXISTS change_amount,
+DROP COLUMN IF EXISTS amount_tendered;
@@ -0,0 +1,10 @@
+-- Migration: 000065_add_cash_tender_to_payment_records.up.sql
+-- Purpose: Track cash tendered and change given for in-store cash payments
+
+ALTER TABLE payment_records
+ADD COLUMN IF NOT EXISTS amount_tendered INTEGER CHECK (amount_tendered IS NULL OR amount_tendered >= amount_paid),
+ADD COLUMN IF NOT EXISTS change_amount INTEGER CHECK (change_amount IS NULL OR change_amount >= 0);
+
+COMMENT ON COLUMN payment_records.amount_tendered IS 'Cash handed over by the customer (cash payments only)';
+
+COMMENT ON COLUMN payment_records.change_amount IS 'Change returned to the customer (amount_tendered - amount_paid)';
@@ -0,0 +1,11 @@
+-- Migration: 000066_add_offline_batch_ingestion.down.sql
+-- Purpose: Rollback offline batch ingestion support
+
+DROP INDEX IF EXISTS idx_guest_orders_stock_oversold;
+
+DROP INDEX IF EXISTS idx_guest_orders_tenant_client_order_id;
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS stock_oversold,
+DROP COLUMN IF EXISTS synced_at,
+DROP COLUMN IF EXISTS client_order_id;
@@ -0,0 +1,24 @@
+-- Migration: 000066_add_offline_batch_ingestion.up.sql
+-- Purpose: Support idempotent ingestion of orders captured while a terminal was offline
+-- Features: client-generated order IDs, sync timestamp, oversell flag
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS client_order_id UUID,
+ADD COLUMN IF NOT EXISTS synced_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS stock_oversold BOOLEAN NOT NULL DEFAULT false;
+
+-- A client-generated order ID may only be ingested once per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_guest_orders_tenant_client_order_id ON guest_orders (tenant_id, client_order_id)
+WHERE
+    client_order_id IS NOT NULL;
+
+-- Partial index for oversell review queue
+CREATE INDEX IF NOT EXISTS idx_guest_orders_stock_oversold ON guest_orders (tenant_id, created_at DESC)
+WHERE
+    stock_oversold = true;
+
+COMMENT ON COLUMN guest_orders.client_order_id IS 'UUID generated by the POS terminal when the order was captured offline';
+
+COMMENT ON COLUMN guest_orders.synced_at IS 'When an offline-captured order was ingested by the server';
+
+COMMENT ON COLUMN guest_orders.stock_oversold IS 'True when stock was insufficient at ingestion time and needs review';
@@ -0,0 +1,7 @@
+-- Migration: 000067_add_card_details_to_payment_records.down.sql
+-- Purpose: Rollback EDC card details on payment records
+
+ALTER TABLE payment_records
+DROP COLUMN IF EXISTS card_last4,
+DROP COLUMN IF EXISTS approval_code,
+DROP COLUMN IF EXISTS card_type;
@@ -0,0 +1,13 @@
+-- Migration: 000067_add_card_details_to_payment_records.up.sql
+-- Purpose: Record card payments taken on an external EDC terminal
+
+ALTER TABLE payment_records
+ADD COLUMN IF NOT EXISTS card_type VARCHAR(20) CHECK (card_type IS NULL OR card_type IN ('visa', 'mastercard', 'jcb', 'amex', 'gpn', 'other')),
+ADD COLUMN IF NOT EXISTS approval_code VARCHAR(20),
+ADD COLUMN IF NOT EXISTS card_last4 CHAR(4) CHECK (card_last4 IS NULL OR card_last4 ~ '^[0-9]{4}$');
+
+COMMENT ON COLUMN payment_records.card_type IS 'Card network reported by the EDC terminal (card payments only)';
+
+COMMENT ON COLUMN payment_records.approval_code IS 'Authorization code printed on the EDC slip';
+
+COMMENT ON COLUMN payment_records.card_last4 IS 'Last four digits of the card number; the full PAN is never stored';
@@ -0,0 +1,6 @@
+-- Migration: 000068_create_tenant_domains.down.sql
+-- Purpose: Rollback tenant custom domain mapping
+
+DROP INDEX IF EXISTS idx_tenant_domains_tenant_id;
+DROP INDEX IF EXISTS idx_tenant_domains_domain;
+DROP TABLE IF EXISTS tenant_domains;
@@ -0,0 +1,22 @@
+-- Migration: 000068_create_tenant_domains.up.sql
+-- Purpose: Map tenant storefront custom domains so the API Gateway can allow them as CORS origins
+
+CREATE TABLE IF NOT EXISTS tenant_domains (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    domain VARCHAR(253) NOT NULL CHECK (domain = LOWER(domain)),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- A domain can only point at one tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_domains_domain ON tenant_domains (domain);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_domains_tenant_id ON tenant_domains (tenant_id);
+
+COMMENT ON TABLE tenant_domains IS 'Custom storefront domains registered by tenants';
+
+COMMENT ON COLUMN tenant_domains.domain IS 'Lowercase hostname (optionally with port), without scheme or path, e.g. shop.example.com';
+
+COMMENT ON COLUMN tenant_domains.is_active IS 'Inactive domains are no longer accepted as CORS origins';
@@ -0,0 +1,10 @@
+-- Migration: 000069_add_payment_method_details_to_payment_transactions.down.sql
+-- Purpose: Rollback multi-method payment details on payment_transactions
+
+ALTER TABLE payment_transactions
+DROP COLUMN IF EXISTS snap_token,
+DROP COLUMN IF EXISTS redirect_url,
+DROP COLUMN IF EXISTS deeplink_url,
+DROP COLUMN IF EXISTS va_number,
+DROP COLUMN IF EXISTS bank,
+DROP COLUMN IF EXISTS payment_method;
@@ -0,0 +1,22 @@
+-- Migration: 000069_add_payment_method_details_to_payment_transactions.up.sql
+-- Purpose: Support GoPay, bank transfer virtual accounts and credit card (Snap) alongside QRIS
+
+ALTER TABLE payment_transactions
+ADD COLUMN IF NOT EXISTS payment_method VARCHAR(30) NOT NULL DEFAULT 'qris' CHECK (payment_method IN ('qris', 'gopay', 'bank_transfer', 'credit_card')),
+ADD COLUMN IF NOT EXISTS bank VARCHAR(20),
+ADD COLUMN IF NOT EXISTS va_number VARCHAR(50),
+ADD COLUMN IF NOT EXISTS deeplink_url TEXT,
+ADD COLUMN IF NOT EXISTS redirect_url TEXT,
+ADD COLUMN IF NOT EXISTS snap_token VARCHAR(255);
+
+COMMENT ON COLUMN payment_transactions.payment_method IS 'Payment method selected by the customer at checkout';
+
+COMMENT ON COLUMN payment_transactions.bank IS 'Acquiring bank for bank transfer virtual accounts (bca, bni, bri, permata)';
+
+COMMENT ON COLUMN payment_transactions.va_number IS 'Virtual account number the customer transfers to';
+
+COMMENT ON COLUMN payment_transactions.deeplink_url IS 'GoPay app deeplink for mobile checkout';
+
+COMMENT ON COLUMN payment_transactions.redirect_url IS 'Snap payment page URL for credit card payments';
+
+COMMENT ON COLUMN payment_transactions.snap_token IS 'Snap token for embedding the credit card payment popup';
@@ -0,0 +1,7 @@
+-- Migration: 000070_add_split_payments.down.sql
+-- Purpose: Rollback split payment tracking
+
+DROP INDEX IF EXISTS idx_payment_transactions_split_parts;
+
+ALTER TABLE payment_transactions
+DROP COLUMN IF EXISTS is_split_part;
@@ -0,0 +1,10 @@
+-- Migration: 000070_add_split_payments.up.sql
+-- Purpose: Allow an order to be settled by several payments (e.g. part cash, part QRIS)
+
+ALTER TABLE payment_transactions
+ADD COLUMN IF NOT EXISTS is_split_part BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_payment_transactions_split_parts ON payment_transactions (order_id)
+WHERE is_split_part = TRUE;
+
+COMMENT ON COLUMN payment_transactions.is_split_part IS 'TRUE when the charge covers only part of the order total; midtrans_order_id is then {order_reference}_S{n}';
@@ -0,0 +1,11 @@
+-- Migration: 000071_add_correlation_ids.down.sql
+-- Purpose: Rollback correlation ID tracking
+
+DROP INDEX IF EXISTS idx_audit_events_request_id;
+DROP INDEX IF EXISTS idx_notifications_correlation_id;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS correlation_id;
+
+ALTER TABLE event_outbox
+DROP COLUMN IF EXISTS correlation_id;
@@ -0,0 +1,17 @@
+-- Migration: 000071_add_correlation_ids.up.sql
+-- Purpose: Carry the originating request ID through outbox events, notifications and audit lookups
+
+ALTER TABLE event_outbox
+ADD COLUMN IF NOT EXISTS correlation_id VARCHAR(100);
+
+ALTER TABLE notifications
+ADD COLUMN IF NOT EXISTS correlation_id VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_correlation_id ON notifications (tenant_id, correlation_id)
+WHERE correlation_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_request_id ON audit_events (tenant_id, request_id)
+WHERE request_id IS NOT NULL;
+
+COMMENT ON COLUMN event_outbox.correlation_id IS 'X-Request-ID of the HTTP request that created the event; sent as the correlation_id Kafka header';
+COMMENT ON COLUMN notifications.correlation_id IS 'X-Request-ID of the HTTP request whose event triggered this notification';
@@ -0,0 +1,10 @@
+-- Migration: 000072_add_payment_gateway_selection.down.sql
+-- Purpose: Rollback per-tenant payment gateway selection
+
+ALTER TABLE payment_transactions
+DROP COLUMN IF EXISTS payment_gateway;
+
+ALTER TABLE tenant_configs
+DROP COLUMN IF EXISTS xendit_callback_token,
+DROP COLUMN IF EXISTS xendit_secret_key,
+DROP COLUMN IF EXISTS payment_gateway;
@@ -0,0 +1,17 @@
+-- Migration: 000072_add_payment_gateway_selection.up.sql
+-- Purpose: Let each tenant choose its online payment gateway (Midtrans or Xendit)
+
+ALTER TABLE tenant_configs
+ADD COLUMN IF NOT EXISTS payment_gateway VARCHAR(20) NOT NULL DEFAULT 'midtrans' CHECK (
+    payment_gateway IN ('midtrans', 'xendit')
+),
+ADD COLUMN IF NOT EXISTS xendit_secret_key TEXT,
+ADD COLUMN IF NOT EXISTS xendit_callback_token TEXT;
+
+ALTER TABLE payment_transactions
+ADD COLUMN IF NOT EXISTS payment_gateway VARCHAR(20) NOT NULL DEFAULT 'midtrans';
+
+COMMENT ON COLUMN tenant_configs.payment_gateway IS 'Gateway used for new online payments: midtrans or xendit';
+COMMENT ON COLUMN tenant_configs.xendit_secret_key IS 'Tenant-specific Xendit secret API key (encrypted)';
+COMMENT ON COLUMN tenant_configs.xendit_callback_token IS 'Xendit webhook verification token, compared with the x-callback-token header (encrypted)';
+COMMENT ON COLUMN payment_transactions.payment_gateway IS 'Gateway that created the charge; for Xendit, midtrans_transaction_id holds the Xendit object ID and midtrans_order_id the reference/external ID';
@@ -0,0 +1,13 @@
+-- Migration: 000073_add_order_auto_completion.down.sql
+-- Purpose: Rollback order auto-completion settings and dispute flag
+
+DROP INDEX IF EXISTS idx_guest_orders_paid_awaiting_completion;
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS dispute_reason,
+DROP COLUMN IF EXISTS disputed_at;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS auto_complete_timezone,
+DROP COLUMN IF EXISTS auto_complete_after_hours,
+DROP COLUMN IF EXISTS auto_complete_mode;
@@ -0,0 +1,22 @@
+-- Migration: 000073_add_order_auto_completion.up.sql
+-- Purpose: Per-tenant rule for automatically completing PAID orders, with a dispute flag to exclude orders
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS auto_complete_mode VARCHAR(20) NOT NULL DEFAULT 'disabled'
+    CHECK (auto_complete_mode IN ('disabled', 'after_paid', 'end_of_day')),
+ADD COLUMN IF NOT EXISTS auto_complete_after_hours INTEGER NOT NULL DEFAULT 4
+    CHECK (auto_complete_after_hours > 0),
+ADD COLUMN IF NOT EXISTS auto_complete_timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta';
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS disputed_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS dispute_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_guest_orders_paid_awaiting_completion ON guest_orders (tenant_id, paid_at)
+WHERE status = 'PAID';
+
+COMMENT ON COLUMN order_settings.auto_complete_mode IS 'disabled, after_paid (complete auto_complete_after_hours after payment) or end_of_day (complete orders paid before local midnight)';
+COMMENT ON COLUMN order_settings.auto_complete_after_hours IS 'Hours after paid_at before an order is auto-completed in after_paid mode';
+COMMENT ON COLUMN order_settings.auto_complete_timezone IS 'IANA timezone used to find the end of the business day in end_of_day mode';
+COMMENT ON COLUMN guest_orders.disputed_at IS 'Set by staff while an order is disputed; disputed orders are never auto-completed';
+COMMENT ON COLUMN guest_orders.dispute_reason IS 'Staff note explaining the dispute';
@@ -0,0 +1,9 @@
+-- Migration: 000074_create_vouchers.down.sql
+-- Purpose: Rollback vouchers and order discounts
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS voucher_code,
+DROP COLUMN IF EXISTS discount_amount;
+
+DROP TABLE IF EXISTS voucher_redemptions;
+DROP TABLE IF EXISTS vouchers;
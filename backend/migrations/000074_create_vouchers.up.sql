@@ -0,0 +1,49 @@
+-- Migration: 000074_create_vouchers.up.sql
+-- Purpose: Tenant voucher / discount codes and the orders that redeemed them
+
+CREATE TABLE IF NOT EXISTS vouchers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    description TEXT,
+    discount_type VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value INTEGER NOT NULL CHECK (discount_value > 0),
+    max_discount_amount INTEGER CHECK (max_discount_amount > 0),
+    min_subtotal INTEGER NOT NULL DEFAULT 0 CHECK (min_subtotal >= 0),
+    usage_limit INTEGER CHECK (usage_limit > 0),
+    per_customer_limit INTEGER CHECK (per_customer_limit > 0),
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT vouchers_percent_range CHECK (discount_type <> 'percent' OR discount_value <= 100),
+    CONSTRAINT vouchers_validity_window CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_from < valid_until),
+    CONSTRAINT vouchers_tenant_code_unique UNIQUE (tenant_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    voucher_id UUID NOT NULL REFERENCES vouchers(id) ON DELETE RESTRICT,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL UNIQUE REFERENCES guest_orders(id) ON DELETE CASCADE,
+    customer_key VARCHAR(64) NOT NULL,
+    discount_amount INTEGER NOT NULL CHECK (discount_amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_customer ON voucher_redemptions (voucher_id, customer_key);
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS discount_amount INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS voucher_code VARCHAR(50);
+
+COMMENT ON TABLE vouchers IS 'Discount codes a tenant offers at checkout';
+COMMENT ON COLUMN vouchers.code IS 'Upper-cased code entered by the customer, unique per tenant';
+COMMENT ON COLUMN vouchers.discount_value IS 'Percentage (1-100) for percent vouchers, amount in IDR for fixed vouchers';
+COMMENT ON COLUMN vouchers.max_discount_amount IS 'Optional cap on the discount of a percent voucher';
+COMMENT ON COLUMN vouchers.usage_count IS 'Redemptions by orders that are not cancelled';
+COMMENT ON COLUMN voucher_redemptions.customer_key IS 'SHA-256 of tenant and normalized phone number, used for per-customer limits without storing PII';
+COMMENT ON COLUMN guest_orders.discount_amount IS 'Voucher discount subtracted from subtotal_amount; total_amount = subtotal_amount - discount_amount + delivery_fee';
+COMMENT ON COLUMN guest_orders.voucher_code IS 'Voucher code applied to the order, if any';
@@ -0,0 +1,8 @@
+-- Migration: 000075_create_promotions.down.sql
+-- Purpose: Rollback promotions and order promotion discounts
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS promotion_discount_amount;
+
+DROP TABLE IF EXISTS order_promotions;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,68 @@
+-- Migration: 000075_create_promotions.up.sql
+-- Purpose: Automatic promotions (BOGO, scheduled and category discounts) and the orders they were applied to
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    promotion_type VARCHAR(20) NOT NULL CHECK (promotion_type IN ('percent_off', 'fixed_off', 'bogo')),
+    scope VARCHAR(20) NOT NULL DEFAULT 'order' CHECK (scope IN ('order', 'category', 'product')),
+    category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+    product_id UUID REFERENCES products(id) ON DELETE CASCADE,
+    discount_value INTEGER NOT NULL CHECK (discount_value > 0),
+    max_discount_amount INTEGER CHECK (max_discount_amount > 0),
+    buy_quantity INTEGER CHECK (buy_quantity > 0),
+    get_quantity INTEGER CHECK (get_quantity > 0),
+    min_subtotal INTEGER NOT NULL DEFAULT 0 CHECK (min_subtotal >= 0),
+    days_of_week SMALLINT[],
+    start_time TIME,
+    end_time TIME,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    stackable BOOLEAN NOT NULL DEFAULT TRUE,
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT promotions_percent_range CHECK (promotion_type = 'fixed_off' OR discount_value <= 100),
+    CONSTRAINT promotions_scope_target CHECK (
+        (scope = 'order' AND category_id IS NULL AND product_id IS NULL) OR
+        (scope = 'category' AND category_id IS NOT NULL AND product_id IS NULL) OR
+        (scope = 'product' AND product_id IS NOT NULL AND category_id IS NULL)
+    ),
+    CONSTRAINT promotions_bogo_quantities CHECK (promotion_type <> 'bogo' OR (buy_quantity IS NOT NULL AND get_quantity IS NOT NULL)),
+    CONSTRAINT promotions_time_window CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CONSTRAINT promotions_validity_window CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_from < valid_until)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_tenant_active ON promotions (tenant_id) WHERE is_active = TRUE;
+
+CREATE TABLE IF NOT EXISTS order_promotions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    promotion_id UUID REFERENCES promotions(id) ON DELETE SET NULL,
+    promotion_name VARCHAR(100) NOT NULL,
+    promotion_type VARCHAR(20) NOT NULL,
+    discount_amount INTEGER NOT NULL CHECK (discount_amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_promotions_order ON order_promotions (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_promotions_promotion ON order_promotions (promotion_id);
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS promotion_discount_amount INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON TABLE promotions IS 'Rules-based promotions applied automatically while pricing a cart';
+COMMENT ON COLUMN promotions.discount_value IS 'Percentage (1-100) for percent_off and bogo, amount in IDR for fixed_off';
+COMMENT ON COLUMN promotions.buy_quantity IS 'BOGO: units the customer pays for in each group';
+COMMENT ON COLUMN promotions.get_quantity IS 'BOGO: cheapest units in each group discounted by discount_value percent';
+COMMENT ON COLUMN promotions.days_of_week IS 'Days the promotion runs (0 = Sunday ... 6 = Saturday) in timezone; NULL means every day';
+COMMENT ON COLUMN promotions.start_time IS 'Local start of the daily window; a window ending before it wraps past midnight';
+COMMENT ON COLUMN promotions.stackable IS 'Stackable promotions combine; a non-stackable one only applies alone when it beats the stackable total';
+COMMENT ON COLUMN promotions.priority IS 'Higher priority promotions are applied first';
+COMMENT ON TABLE order_promotions IS 'Promotions applied to an order at checkout, with the name kept for reporting';
+COMMENT ON COLUMN guest_orders.promotion_discount_amount IS 'Automatic promotion discount; total_amount = subtotal_amount - promotion_discount_amount - discount_amount + delivery_fee';
@@ -0,0 +1,10 @@
+-- Migration: 000076_create_loyalty.down.sql
+-- Purpose: Rollback loyalty points
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS loyalty_discount_amount,
+DROP COLUMN IF EXISTS loyalty_points_redeemed;
+
+DROP TABLE IF EXISTS loyalty_transactions;
+DROP TABLE IF EXISTS loyalty_accounts;
+DROP TABLE IF EXISTS loyalty_programs;
@@ -0,0 +1,51 @@
+-- Migration: 000076_create_loyalty.up.sql
+-- Purpose: Customer loyalty points earned on paid guest orders and redeemed at checkout
+
+CREATE TABLE IF NOT EXISTS loyalty_programs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL UNIQUE REFERENCES tenants(id) ON DELETE CASCADE,
+    is_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    spend_per_point INTEGER NOT NULL DEFAULT 10000 CHECK (spend_per_point > 0),
+    point_value INTEGER NOT NULL DEFAULT 100 CHECK (point_value > 0),
+    min_redeem_points INTEGER NOT NULL DEFAULT 10 CHECK (min_redeem_points > 0),
+    max_redeem_percent INTEGER NOT NULL DEFAULT 50 CHECK (max_redeem_percent BETWEEN 1 AND 100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    phone_hash VARCHAR(64) NOT NULL,
+    points_balance INTEGER NOT NULL DEFAULT 0 CHECK (points_balance >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT loyalty_accounts_tenant_phone_unique UNIQUE (tenant_id, phone_hash)
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES loyalty_accounts(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    transaction_type VARCHAR(20) NOT NULL CHECK (transaction_type IN ('earn', 'redeem', 'earn_reversal', 'redeem_refund')),
+    points INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT loyalty_transactions_order_type_unique UNIQUE (order_id, transaction_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_account ON loyalty_transactions (account_id, created_at DESC);
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS loyalty_points_redeemed INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS loyalty_discount_amount INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON TABLE loyalty_programs IS 'Per-tenant loyalty settings; points are only earned and redeemed while is_enabled';
+COMMENT ON COLUMN loyalty_programs.spend_per_point IS 'IDR a customer spends on goods to earn one point';
+COMMENT ON COLUMN loyalty_programs.point_value IS 'IDR discount one point is worth when redeemed';
+COMMENT ON COLUMN loyalty_programs.max_redeem_percent IS 'Largest share of the amount due (after promotions and vouchers, before delivery) payable with points';
+COMMENT ON TABLE loyalty_accounts IS 'Returning guests identified by phone hash, without storing the phone number';
+COMMENT ON COLUMN loyalty_accounts.phone_hash IS 'SHA-256 of tenant and normalized phone number, the same key vouchers use for per-customer limits';
+COMMENT ON TABLE loyalty_transactions IS 'Points ledger; points are positive for earn and redeem_refund, negative for redeem and earn_reversal';
+COMMENT ON COLUMN guest_orders.loyalty_points_redeemed IS 'Points redeemed at checkout';
+COMMENT ON COLUMN guest_orders.loyalty_discount_amount IS 'Discount paid with points; total_amount = subtotal_amount - promotion_discount_amount - discount_amount - loyalty_discount_amount + delivery_fee';
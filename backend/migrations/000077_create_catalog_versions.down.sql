@@ -0,0 +1,5 @@
+-- Migration: 000077_create_catalog_versions.down.sql
+-- Purpose: Rollback catalog versions
+
+DROP TABLE IF EXISTS catalog_version_changes;
+DROP TABLE IF EXISTS catalog_versions;
@@ -0,0 +1,51 @@
+-- Migration: 000077_create_catalog_versions.up.sql
+-- Purpose: Draft catalog versions that stage product changes and publish them together, now or at a scheduled time
+
+CREATE TABLE IF NOT EXISTS catalog_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    notes TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'scheduled', 'published', 'rolled_back', 'cancelled', 'failed')),
+    publish_at TIMESTAMP,
+    published_at TIMESTAMP,
+    rolled_back_at TIMESTAMP,
+    failure_reason TEXT,
+    created_by UUID,
+    published_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT catalog_versions_scheduled_publish_at CHECK (status <> 'scheduled' OR publish_at IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_versions_tenant ON catalog_versions (tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_catalog_versions_due ON catalog_versions (publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_catalog_versions_published ON catalog_versions (tenant_id, published_at DESC) WHERE status = 'published';
+
+CREATE TABLE IF NOT EXISTS catalog_version_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version_id UUID NOT NULL REFERENCES catalog_versions(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(255),
+    description TEXT,
+    category_id UUID,
+    selling_price DECIMAL(10,2) CHECK (selling_price >= 0),
+    primary_photo_id UUID,
+    archived BOOLEAN,
+    previous_state JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT catalog_version_changes_product_unique UNIQUE (version_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_version_changes_version ON catalog_version_changes (version_id);
+
+COMMENT ON TABLE catalog_versions IS 'A set of staged product changes that go live together; only the latest published version of a tenant can be rolled back';
+COMMENT ON COLUMN catalog_versions.status IS 'Changes can be edited until the version is published or cancelled; scheduled versions are published by the catalog publish job once publish_at has passed';
+COMMENT ON COLUMN catalog_versions.failure_reason IS 'Why the last scheduled publish was rejected, e.g. a staged photo was deleted';
+COMMENT ON COLUMN catalog_versions.published_by IS 'User who published the version; NULL when the scheduler published it';
+COMMENT ON TABLE catalog_version_changes IS 'One staged change per product; NULL fields leave the product value unchanged';
+COMMENT ON COLUMN catalog_version_changes.primary_photo_id IS 'Already uploaded product photo to make primary on publish';
+COMMENT ON COLUMN catalog_version_changes.archived IS 'TRUE takes the product off the menu on publish, FALSE puts it back';
+COMMENT ON COLUMN catalog_version_changes.previous_state IS 'Product values captured at publish, restored on rollback';
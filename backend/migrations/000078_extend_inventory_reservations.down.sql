@@ -0,0 +1,8 @@
+-- Migration: 000078_extend_inventory_reservations.down.sql
+-- Purpose: Rollback reservation changes for cashier and synced orders
+
+DROP INDEX IF EXISTS idx_inventory_reservations_order_status;
+
+COMMENT ON TABLE inventory_reservations IS 'Temporary holds on inventory during checkout (15min TTL)';
+
+COMMENT ON COLUMN inventory_reservations.status IS 'active: held, expired: TTL passed, converted: order paid, released: cancelled';
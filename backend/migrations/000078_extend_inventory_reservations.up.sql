@@ -0,0 +1,9 @@
+-- Migration: 000078_extend_inventory_reservations.up.sql
+-- Purpose: Reservations now cover cashier and synced terminal orders, not only guest checkout
+
+-- Releasing an order's stock looks up its active and converted rows
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_order_status ON inventory_reservations (order_id, status);
+
+COMMENT ON TABLE inventory_reservations IS 'Stock held or allocated per order for every channel (guest checkout 15min TTL, unpaid cashier orders 24h TTL)';
+
+COMMENT ON COLUMN inventory_reservations.status IS 'active: held, expired: TTL passed, converted: stock deducted for a paid or completed sale, released: cancelled or returned to stock';
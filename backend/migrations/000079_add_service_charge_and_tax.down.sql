@@ -0,0 +1,12 @@
+-- Migration: 000079_add_service_charge_and_tax.down.sql
+-- Purpose: Rollback service charge and tax
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS service_charge_rate,
+DROP COLUMN IF EXISTS service_charge_amount,
+DROP COLUMN IF EXISTS tax_rate,
+DROP COLUMN IF EXISTS tax_amount;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS service_charge_percent,
+DROP COLUMN IF EXISTS tax_percent;
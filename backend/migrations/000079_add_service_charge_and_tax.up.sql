@@ -0,0 +1,21 @@
+-- Migration: 000079_add_service_charge_and_tax.up.sql
+-- Purpose: Tenant-configured service charge and tax (PPN) applied at checkout and stored per order
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS service_charge_percent NUMERIC(5,2) NOT NULL DEFAULT 0
+    CHECK (service_charge_percent >= 0 AND service_charge_percent <= 100),
+ADD COLUMN IF NOT EXISTS tax_percent NUMERIC(5,2) NOT NULL DEFAULT 0
+    CHECK (tax_percent >= 0 AND tax_percent <= 100);
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS service_charge_rate NUMERIC(5,2) NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS service_charge_amount INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS tax_rate NUMERIC(5,2) NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS tax_amount INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN order_settings.service_charge_percent IS 'Service charge applied to the discounted item subtotal at checkout; 0 disables it';
+COMMENT ON COLUMN order_settings.tax_percent IS 'Tax (PPN) applied to the discounted item subtotal plus service charge at checkout; 0 disables it';
+COMMENT ON COLUMN guest_orders.service_charge_rate IS 'Service charge percentage in effect when the order was placed';
+COMMENT ON COLUMN guest_orders.service_charge_amount IS 'Service charge line; total_amount = subtotal_amount - promotion_discount_amount - discount_amount + service_charge_amount + tax_amount - loyalty_discount_amount + delivery_fee';
+COMMENT ON COLUMN guest_orders.tax_rate IS 'Tax percentage in effect when the order was placed';
+COMMENT ON COLUMN guest_orders.tax_amount IS 'Tax line, charged on the discounted subtotal plus service charge';
@@ -0,0 +1,5 @@
+-- Migration: 000080_add_product_photo_renditions.down.sql
+-- Purpose: Rollback product photo renditions
+
+ALTER TABLE product_photos
+DROP COLUMN IF EXISTS renditions;
@@ -0,0 +1,7 @@
+-- Migration: 000080_add_product_photo_renditions.up.sql
+-- Purpose: Track AVIF/WebP/JPEG renditions generated for each product photo so public photo URLs can negotiate the format
+
+ALTER TABLE product_photos
+ADD COLUMN IF NOT EXISTS renditions JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN product_photos.renditions IS 'Storage key per rendition format ({"avif": "...", "webp": "...", "jpeg": "..."}); renditions are derived copies and are not counted in file_size_bytes or the tenant storage quota';
@@ -0,0 +1,14 @@
+-- Migration: 000081_add_scheduled_order_slots.down.sql
+-- Purpose: Rollback scheduled order slots
+
+DROP INDEX IF EXISTS idx_guest_orders_scheduled_for;
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS scheduled_for;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS scheduling_enabled,
+DROP COLUMN IF EXISTS business_hours,
+DROP COLUMN IF EXISTS slot_duration_minutes,
+DROP COLUMN IF EXISTS slot_capacity,
+DROP COLUMN IF EXISTS scheduling_max_days_ahead;
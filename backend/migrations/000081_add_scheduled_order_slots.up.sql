@@ -0,0 +1,27 @@
+-- Migration: 000081_add_scheduled_order_slots.up.sql
+-- Purpose: Let guests schedule pickup/delivery for a future slot within business hours, with a per-slot capacity
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS scheduling_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS business_hours JSONB NOT NULL DEFAULT '{}'::jsonb,
+ADD COLUMN IF NOT EXISTS slot_duration_minutes INTEGER NOT NULL DEFAULT 30
+    CHECK (slot_duration_minutes BETWEEN 5 AND 240),
+ADD COLUMN IF NOT EXISTS slot_capacity INTEGER NOT NULL DEFAULT 0
+    CHECK (slot_capacity >= 0),
+ADD COLUMN IF NOT EXISTS scheduling_max_days_ahead INTEGER NOT NULL DEFAULT 7
+    CHECK (scheduling_max_days_ahead BETWEEN 0 AND 60);
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ;
+
+-- Slot capacity is counted per tenant and slot start
+CREATE INDEX IF NOT EXISTS idx_guest_orders_scheduled_for
+    ON guest_orders (tenant_id, scheduled_for)
+    WHERE scheduled_for IS NOT NULL AND status <> 'CANCELLED';
+
+COMMENT ON COLUMN order_settings.scheduling_enabled IS 'Whether guests may choose a future pickup/delivery slot at checkout';
+COMMENT ON COLUMN order_settings.business_hours IS 'Opening hours per weekday in auto_complete_timezone, e.g. {"monday": {"open": "08:00", "close": "21:00"}}; missing days are closed';
+COMMENT ON COLUMN order_settings.slot_duration_minutes IS 'Length of a scheduling slot; slots start at opening time';
+COMMENT ON COLUMN order_settings.slot_capacity IS 'Most non-cancelled orders per slot; 0 means unlimited';
+COMMENT ON COLUMN order_settings.scheduling_max_days_ahead IS 'How many days ahead a slot may be booked; 0 allows today only';
+COMMENT ON COLUMN guest_orders.scheduled_for IS 'Start of the pickup/delivery slot chosen at checkout; NULL means as soon as possible';
@@ -0,0 +1,8 @@
+-- Migration: 000082_add_order_limits.down.sql
+-- Purpose: Rollback order limits
+
+COMMENT ON COLUMN order_settings.min_order_amount IS NULL;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS max_items_per_order,
+DROP COLUMN IF EXISTS min_order_amount_by_delivery_type;
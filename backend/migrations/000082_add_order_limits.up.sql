@@ -0,0 +1,11 @@
+-- Migration: 000082_add_order_limits.up.sql
+-- Purpose: Minimum order amount per delivery type and a maximum number of items per order
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS min_order_amount_by_delivery_type JSONB NOT NULL DEFAULT '{}'::jsonb,
+ADD COLUMN IF NOT EXISTS max_items_per_order INTEGER NOT NULL DEFAULT 0
+    CHECK (max_items_per_order >= 0);
+
+COMMENT ON COLUMN order_settings.min_order_amount IS 'Minimum item subtotal (before discounts) for online orders; applies to delivery types without an override';
+COMMENT ON COLUMN order_settings.min_order_amount_by_delivery_type IS 'Minimum item subtotal per delivery type, e.g. {"delivery": 50000, "dine_in": 0}; overrides min_order_amount';
+COMMENT ON COLUMN order_settings.max_items_per_order IS 'Most item units (summed quantities) per online order; 0 means unlimited';
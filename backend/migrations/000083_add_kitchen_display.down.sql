@@ -0,0 +1,14 @@
+-- Migration: 000083_add_kitchen_display.down.sql
+-- Purpose: Rollback kitchen display
+
+DROP INDEX IF EXISTS idx_order_items_prep_ready_at;
+DROP INDEX IF EXISTS idx_guest_orders_kitchen_active;
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS kitchen_updated_at,
+DROP COLUMN IF EXISTS kitchen_bumped_at;
+
+ALTER TABLE order_items
+DROP COLUMN IF EXISTS prep_ready_at,
+DROP COLUMN IF EXISTS prep_started_at,
+DROP COLUMN IF EXISTS prep_status;
@@ -0,0 +1,28 @@
+-- Migration: 000083_add_kitchen_display.up.sql
+-- Purpose: Kitchen display (KDS) - per-item preparation status, bump/recall and prep-time tracking
+
+ALTER TABLE order_items
+ADD COLUMN IF NOT EXISTS prep_status VARCHAR(20) NOT NULL DEFAULT 'queued'
+    CHECK (prep_status IN ('queued', 'preparing', 'ready')),
+ADD COLUMN IF NOT EXISTS prep_started_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS prep_ready_at TIMESTAMP;
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS kitchen_bumped_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS kitchen_updated_at TIMESTAMP;
+
+-- The kitchen screen lists a tenant's paid orders that have not been bumped
+CREATE INDEX IF NOT EXISTS idx_guest_orders_kitchen_active
+    ON guest_orders (tenant_id, paid_at)
+    WHERE status = 'PAID' AND kitchen_bumped_at IS NULL;
+
+-- Prep-time metrics read items by when they became ready
+CREATE INDEX IF NOT EXISTS idx_order_items_prep_ready_at
+    ON order_items (prep_ready_at)
+    WHERE prep_ready_at IS NOT NULL;
+
+COMMENT ON COLUMN order_items.prep_status IS 'Kitchen preparation status: queued, preparing or ready';
+COMMENT ON COLUMN order_items.prep_started_at IS 'When the kitchen started preparing the item';
+COMMENT ON COLUMN order_items.prep_ready_at IS 'When the item was marked ready (or its order bumped)';
+COMMENT ON COLUMN guest_orders.kitchen_bumped_at IS 'When the kitchen bumped the order off the kitchen screen; cleared by a recall';
+COMMENT ON COLUMN guest_orders.kitchen_updated_at IS 'Last kitchen change (item status, bump or recall); drives the kitchen feed cursor';
@@ -0,0 +1,4 @@
+-- Migration: 000084_create_feature_usage_daily.down.sql
+-- Purpose: Rollback feature usage telemetry
+
+DROP TABLE IF EXISTS feature_usage_daily;
@@ -0,0 +1,22 @@
+-- Migration: 000084_create_feature_usage_daily.up.sql
+-- Purpose: Daily per-tenant feature usage counts for product decisions (no PII)
+
+CREATE TABLE IF NOT EXISTS feature_usage_daily (
+    usage_date DATE NOT NULL,
+    tenant_id UUID NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    feature VARCHAR(64) NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0 CHECK (request_count >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (usage_date, tenant_id, feature)
+);
+
+-- Operator dashboard queries group by feature over a date range
+CREATE INDEX IF NOT EXISTS idx_feature_usage_daily_feature
+    ON feature_usage_daily (feature, usage_date);
+
+CREATE INDEX IF NOT EXISTS idx_feature_usage_daily_tenant
+    ON feature_usage_daily (tenant_id, usage_date);
+
+COMMENT ON TABLE feature_usage_daily IS 'Successful uses of tracked features per tenant per UTC day, rolled up from Redis counters by analytics-service';
+COMMENT ON COLUMN feature_usage_daily.feature IS 'Feature name, e.g. product_photos, checkout_delivery, voucher_redemption';
+COMMENT ON COLUMN feature_usage_daily.request_count IS 'Number of successful requests that used the feature that day';
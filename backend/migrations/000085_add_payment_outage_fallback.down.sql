@@ -0,0 +1,12 @@
+-- Migration: 000085_add_payment_outage_fallback.down.sql
+-- Purpose: Rollback payment outage fallback
+
+DROP TABLE IF EXISTS payment_charge_retries;
+
+DROP INDEX IF EXISTS idx_guest_orders_payment_fallback;
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS payment_fallback;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS payment_outage_policy;
@@ -0,0 +1,35 @@
+-- Migration: 000085_add_payment_outage_fallback.up.sql
+-- Purpose: Graceful degradation when the payment gateway is unreachable at checkout
+
+-- reject: checkout fails as before; pay_at_counter: the order is placed unpaid and staff collect payment
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS payment_outage_policy VARCHAR(20) NOT NULL DEFAULT 'reject'
+    CHECK (payment_outage_policy IN ('reject', 'pay_at_counter'));
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS payment_fallback VARCHAR(20)
+    CHECK (payment_fallback IN ('UNPAID_OFFLINE'));
+
+-- Staff look up the pending orders they have to collect payment for
+CREATE INDEX IF NOT EXISTS idx_guest_orders_payment_fallback
+    ON guest_orders (tenant_id, created_at)
+    WHERE payment_fallback IS NOT NULL AND status = 'PENDING';
+
+-- Online charges that could not be created during an outage, retried until the gateway recovers
+CREATE TABLE IF NOT EXISTS payment_charge_retries (
+    order_id UUID PRIMARY KEY REFERENCES guest_orders(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    payment_method VARCHAR(20) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_charge_retries_next_attempt
+    ON payment_charge_retries (next_attempt_at);
+
+COMMENT ON COLUMN order_settings.payment_outage_policy IS 'What checkout does when the payment gateway is unreachable: reject or pay_at_counter';
+COMMENT ON COLUMN guest_orders.payment_fallback IS 'UNPAID_OFFLINE when the order was placed during a gateway outage and payment is collected by staff';
+COMMENT ON TABLE payment_charge_retries IS 'QRIS charges queued during a gateway outage; removed once created, paid otherwise or expired';
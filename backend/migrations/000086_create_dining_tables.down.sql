@@ -0,0 +1,6 @@
+-- Migration: 000086_create_dining_tables.down.sql
+-- Purpose: Rollback dine-in tables
+
+DROP INDEX IF EXISTS idx_guest_orders_open_table;
+
+DROP TABLE IF EXISTS dining_tables;
@@ -0,0 +1,25 @@
+-- Migration: 000086_create_dining_tables.up.sql
+-- Purpose: Dine-in tables with per-table QR codes; orders are seated at a table by table_number
+
+CREATE TABLE IF NOT EXISTS dining_tables (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    capacity INTEGER CHECK (capacity > 0),
+    qr_token VARCHAR(64) NOT NULL UNIQUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Table names are matched against guest_orders.table_number without regard to case
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dining_tables_tenant_name ON dining_tables (tenant_id, LOWER(name));
+
+-- Occupancy reads a tenant's open dine-in orders by table
+CREATE INDEX IF NOT EXISTS idx_guest_orders_open_table
+    ON guest_orders (tenant_id, LOWER(table_number))
+    WHERE delivery_type = 'dine_in' AND status IN ('PENDING', 'PAID');
+
+COMMENT ON TABLE dining_tables IS 'Dine-in tables a tenant seats guests at';
+COMMENT ON COLUMN dining_tables.name IS 'Label shown to guests and staff (e.g. 12, Patio 3); orders reference it in guest_orders.table_number';
+COMMENT ON COLUMN dining_tables.qr_token IS 'Random token in the table QR code URL; rotating it invalidates printed codes';
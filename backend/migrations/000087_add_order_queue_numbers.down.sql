@@ -0,0 +1,10 @@
+-- Migration: 000087_add_order_queue_numbers.down.sql
+-- Purpose: Rollback daily order queue numbers
+
+DROP INDEX IF EXISTS idx_guest_orders_queue_number;
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS queue_date,
+DROP COLUMN IF EXISTS queue_number;
+
+DROP TABLE IF EXISTS order_queue_counters;
@@ -0,0 +1,22 @@
+-- Migration: 000087_add_order_queue_numbers.up.sql
+-- Purpose: Short daily queue numbers for paid orders, shown on the tenant's "now serving" display
+
+CREATE TABLE IF NOT EXISTS order_queue_counters (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    queue_date DATE NOT NULL,
+    last_number INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, queue_date)
+);
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS queue_number INTEGER,
+ADD COLUMN IF NOT EXISTS queue_date DATE;
+
+-- One number per tenant and business day; also serves the display's lookup of today's queue
+CREATE UNIQUE INDEX IF NOT EXISTS idx_guest_orders_queue_number
+    ON guest_orders (tenant_id, queue_date, queue_number)
+    WHERE queue_number IS NOT NULL;
+
+COMMENT ON TABLE order_queue_counters IS 'Last queue number handed out per tenant and business day';
+COMMENT ON COLUMN guest_orders.queue_number IS 'Daily queue number assigned when the order is paid, starting at 1 each business day';
+COMMENT ON COLUMN guest_orders.queue_date IS 'Business day of queue_number in the tenant''s timezone (order_settings.auto_complete_timezone)';
@@ -0,0 +1,7 @@
+-- Migration: 000088_create_print_jobs.down.sql
+-- Purpose: Rollback the print job queue
+
+DROP INDEX IF EXISTS idx_print_jobs_tenant_created;
+DROP INDEX IF EXISTS idx_print_jobs_queue;
+
+DROP TABLE IF EXISTS print_jobs;
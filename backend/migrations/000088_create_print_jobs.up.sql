@@ -0,0 +1,30 @@
+-- Migration: 000088_create_print_jobs.up.sql
+-- Purpose: Queue of rendered ESC/POS receipts and kitchen tickets, polled and acknowledged by a tenant's local print agent
+
+CREATE TABLE IF NOT EXISTS print_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('receipt', 'kitchen_ticket')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'printing', 'printed', 'failed')),
+    paper_width INTEGER NOT NULL CHECK (paper_width IN (32, 48)),
+    payload BYTEA NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    claimed_at TIMESTAMP,
+    printed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Print agents claim a tenant's oldest waiting job
+CREATE INDEX IF NOT EXISTS idx_print_jobs_queue
+    ON print_jobs (tenant_id, created_at)
+    WHERE status IN ('pending', 'printing');
+
+CREATE INDEX IF NOT EXISTS idx_print_jobs_tenant_created ON print_jobs (tenant_id, created_at DESC);
+
+COMMENT ON TABLE print_jobs IS 'Receipts and kitchen tickets waiting for, or handled by, a tenant''s print agent';
+COMMENT ON COLUMN print_jobs.payload IS 'Rendered ESC/POS byte stream, sent as-is to the printer';
+COMMENT ON COLUMN print_jobs.attempts IS 'Times the job was handed to a print agent; it fails after 3';
+COMMENT ON COLUMN print_jobs.claimed_at IS 'When a print agent took the job; unacknowledged claims are handed out again after 2 minutes';
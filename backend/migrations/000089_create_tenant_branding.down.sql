@@ -0,0 +1,4 @@
+-- Migration: 000089_create_tenant_branding.down.sql
+-- Purpose: Rollback tenant branding
+
+DROP TABLE IF EXISTS tenant_branding;
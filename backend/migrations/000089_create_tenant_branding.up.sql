@@ -0,0 +1,29 @@
+-- Migration: 000089_create_tenant_branding.up.sql
+-- Purpose: Store per-tenant branding printed on PDF invoices and whether invoice emails carry the PDF
+
+CREATE TABLE IF NOT EXISTS tenant_branding (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    display_name VARCHAR(255),
+    logo_url TEXT,
+    primary_color VARCHAR(7) NOT NULL DEFAULT '#1F2937' CHECK (primary_color ~ '^#[0-9A-Fa-f]{6}$'),
+    address TEXT,
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    tax_id VARCHAR(32),
+    invoice_footer TEXT,
+    attach_invoice_pdf BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE tenant_branding IS 'Tenant branding used on PDF invoices; tenants without a row use the defaults';
+
+COMMENT ON COLUMN tenant_branding.display_name IS 'Name printed on invoices; falls back to tenants.business_name';
+
+COMMENT ON COLUMN tenant_branding.logo_url IS 'HTTPS URL of a PNG or JPEG logo; invoices render without it when it cannot be fetched';
+
+COMMENT ON COLUMN tenant_branding.primary_color IS 'Accent color of the invoice header and table, as #RRGGBB';
+
+COMMENT ON COLUMN tenant_branding.tax_id IS 'Tax registration number (NPWP) printed on invoices';
+
+COMMENT ON COLUMN tenant_branding.attach_invoice_pdf IS 'Attach the PDF invoice to order invoice emails';
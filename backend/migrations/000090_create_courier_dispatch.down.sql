@@ -0,0 +1,11 @@
+-- Migration: 000090_create_courier_dispatch.down.sql
+-- Purpose: Rollback courier dispatch
+
+ALTER TABLE guest_orders DROP COLUMN IF EXISTS delivery_status;
+
+DROP INDEX IF EXISTS idx_courier_bookings_order;
+DROP INDEX IF EXISTS idx_courier_bookings_external;
+DROP INDEX IF EXISTS idx_courier_bookings_active_order;
+
+DROP TABLE IF EXISTS courier_bookings;
+DROP TABLE IF EXISTS courier_settings;
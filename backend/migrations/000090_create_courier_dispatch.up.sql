@@ -0,0 +1,69 @@
+-- Migration: 000090_create_courier_dispatch.up.sql
+-- Purpose: Book GoSend/GrabExpress couriers for paid delivery orders and track their delivery status
+
+CREATE TABLE IF NOT EXISTS courier_settings (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    provider VARCHAR(20) CHECK (provider IN ('gosend', 'grabexpress')),
+    auto_dispatch BOOLEAN NOT NULL DEFAULT FALSE,
+    service_type VARCHAR(20) NOT NULL DEFAULT 'instant' CHECK (service_type IN ('instant', 'same_day')),
+    pickup_name VARCHAR(255),
+    pickup_phone VARCHAR(50),
+    pickup_address TEXT,
+    pickup_latitude DECIMAL(10, 8),
+    pickup_longitude DECIMAL(11, 8),
+    pickup_notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS courier_bookings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('gosend', 'grabexpress')),
+    service_type VARCHAR(20) NOT NULL CHECK (service_type IN ('instant', 'same_day')),
+    status VARCHAR(20) NOT NULL DEFAULT 'requesting'
+        CHECK (status IN ('requesting', 'finding_driver', 'driver_assigned', 'picked_up', 'delivered', 'cancelled', 'failed')),
+    external_id VARCHAR(100),
+    tracking_url TEXT,
+    driver_name VARCHAR(255),
+    driver_phone VARCHAR(50),
+    vehicle_plate VARCHAR(20),
+    quoted_fee INTEGER,
+    final_fee INTEGER,
+    last_error TEXT,
+    auto_dispatched BOOLEAN NOT NULL DEFAULT FALSE,
+    booked_at TIMESTAMP,
+    picked_up_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- At most one live booking per order; cancelled and failed bookings can be booked again
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courier_bookings_active_order
+    ON courier_bookings (order_id)
+    WHERE status NOT IN ('cancelled', 'failed');
+
+-- Courier webhooks identify bookings by the provider's ID
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courier_bookings_external
+    ON courier_bookings (provider, external_id)
+    WHERE external_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_courier_bookings_order ON courier_bookings (order_id, created_at DESC);
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS delivery_status VARCHAR(20);
+
+COMMENT ON TABLE courier_settings IS 'Courier provider and pickup point used to book deliveries for a tenant';
+COMMENT ON COLUMN courier_settings.auto_dispatch IS 'Book a courier automatically once a delivery order is paid';
+COMMENT ON COLUMN courier_settings.pickup_latitude IS 'Store location the courier picks orders up from';
+
+COMMENT ON TABLE courier_bookings IS 'Couriers booked with GoSend or GrabExpress for delivery orders';
+COMMENT ON COLUMN courier_bookings.external_id IS 'Booking reference of the provider (GoSend order number, GrabExpress delivery ID)';
+COMMENT ON COLUMN courier_bookings.quoted_fee IS 'Courier fee in IDR quoted when booking';
+COMMENT ON COLUMN courier_bookings.final_fee IS 'Courier fee in IDR reported by the provider once known';
+COMMENT ON COLUMN courier_bookings.status IS 'Delivery status mapped from provider webhooks; requesting means the booking call has not returned yet';
+
+COMMENT ON COLUMN guest_orders.delivery_status IS 'Status of the latest courier booking, NULL when no courier was booked';
@@ -0,0 +1,8 @@
+-- Migration: 000091_create_delivery_zones.down.sql
+-- Purpose: Rollback delivery zones
+
+ALTER TABLE delivery_addresses
+DROP COLUMN IF EXISTS updated_at,
+DROP COLUMN IF EXISTS delivery_zone_id;
+
+DROP TABLE IF EXISTS delivery_zones;
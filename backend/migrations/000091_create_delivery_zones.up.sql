@@ -0,0 +1,41 @@
+-- Migration: 000091_create_delivery_zones.up.sql
+-- Purpose: Price deliveries by the zone a geocoded address falls in instead of one flat fee
+
+CREATE TABLE IF NOT EXISTS delivery_zones (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    zone_type VARCHAR(10) NOT NULL CHECK (zone_type IN ('radius', 'polygon')),
+    center_latitude DECIMAL(10, 8),
+    center_longitude DECIMAL(11, 8),
+    min_radius_km DECIMAL(6, 2) NOT NULL DEFAULT 0 CHECK (min_radius_km >= 0),
+    max_radius_km DECIMAL(6, 2),
+    polygon JSONB,
+    fee INTEGER NOT NULL CHECK (fee >= 0),
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT delivery_zones_radius_check CHECK (
+        zone_type <> 'radius'
+        OR (center_latitude IS NOT NULL AND center_longitude IS NOT NULL AND max_radius_km > min_radius_km)
+    ),
+    CONSTRAINT delivery_zones_polygon_check CHECK (zone_type <> 'polygon' OR polygon IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_delivery_zones_tenant_name ON delivery_zones (tenant_id, LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_delivery_zones_tenant_active ON delivery_zones (tenant_id, priority) WHERE is_active;
+
+-- Bring delivery_addresses in line with the zone an order was priced with
+ALTER TABLE delivery_addresses
+ADD COLUMN IF NOT EXISTS delivery_zone_id UUID REFERENCES delivery_zones(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+COMMENT ON TABLE delivery_zones IS 'Delivery areas of a tenant with the fee charged for addresses inside them';
+COMMENT ON COLUMN delivery_zones.zone_type IS 'radius: a band between min_radius_km and max_radius_km around the center; polygon: the area inside polygon';
+COMMENT ON COLUMN delivery_zones.polygon IS 'Polygon vertices as a JSON array of {latitude, longitude}';
+COMMENT ON COLUMN delivery_zones.priority IS 'When zones overlap the active zone with the lowest priority wins';
+COMMENT ON COLUMN delivery_zones.fee IS 'Delivery fee in IDR for addresses in this zone';
+
+COMMENT ON COLUMN delivery_addresses.delivery_zone_id IS 'Zone the delivery fee was resolved from, NULL for the flat default fee';
+COMMENT ON COLUMN delivery_addresses.service_area_zone IS 'Name of the zone at checkout, kept when the zone is renamed or deleted';
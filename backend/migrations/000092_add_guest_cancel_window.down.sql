@@ -0,0 +1,5 @@
+-- Migration: 000092_add_guest_cancel_window.down.sql
+-- Purpose: Rollback guest cancellation window
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS guest_cancel_window_minutes;
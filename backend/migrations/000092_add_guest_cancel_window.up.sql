@@ -0,0 +1,8 @@
+-- Migration: 000092_add_guest_cancel_window.up.sql
+-- Purpose: Let guests cancel their own unpaid orders for a while after checkout
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS guest_cancel_window_minutes INTEGER NOT NULL DEFAULT 15
+    CHECK (guest_cancel_window_minutes >= 0 AND guest_cancel_window_minutes <= 1440);
+
+COMMENT ON COLUMN order_settings.guest_cancel_window_minutes IS 'Minutes after checkout during which a guest may cancel their own PENDING order; 0 disables guest cancellation';
@@ -0,0 +1,6 @@
+-- Migration: 000093_add_manual_payment_confirmation.down.sql
+-- Purpose: Rollback manual payment confirmation audit columns
+
+ALTER TABLE payment_transactions
+DROP COLUMN IF EXISTS manual_confirmation_reason,
+DROP COLUMN IF EXISTS manually_confirmed_by;
@@ -0,0 +1,9 @@
+-- Migration: 000093_add_manual_payment_confirmation.up.sql
+-- Purpose: Record who confirmed an online payment by hand when the gateway webhook never arrived, and why
+
+ALTER TABLE payment_transactions
+ADD COLUMN IF NOT EXISTS manually_confirmed_by VARCHAR(255),
+ADD COLUMN IF NOT EXISTS manual_confirmation_reason TEXT;
+
+COMMENT ON COLUMN payment_transactions.manually_confirmed_by IS 'Staff member who marked the charge as paid without a gateway notification; NULL when settled by webhook';
+COMMENT ON COLUMN payment_transactions.manual_confirmation_reason IS 'Reason given for the manual payment confirmation';
@@ -0,0 +1,5 @@
+-- Migration: 000094_add_payment_expiry.down.sql
+-- Purpose: Rollback per-tenant payment expiry
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS payment_expiry_minutes;
@@ -0,0 +1,8 @@
+-- Migration: 000094_add_payment_expiry.up.sql
+-- Purpose: Let each tenant choose how long guests have to pay a QRIS or e-wallet charge
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS payment_expiry_minutes INTEGER NOT NULL DEFAULT 15
+    CHECK (payment_expiry_minutes >= 10 AND payment_expiry_minutes <= 60);
+
+COMMENT ON COLUMN order_settings.payment_expiry_minutes IS 'Minutes a QRIS or e-wallet charge stays payable; checkout stock is held for the same time. Virtual accounts keep their own 1 hour expiry';
@@ -0,0 +1,4 @@
+-- Migration: 000095_create_settlement_reports.down.sql
+-- Purpose: Rollback daily settlement reports
+
+DROP TABLE IF EXISTS settlement_reports;
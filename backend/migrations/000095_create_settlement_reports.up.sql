@@ -0,0 +1,23 @@
+-- Migration: 000095_create_settlement_reports.up.sql
+-- Purpose: Keep a daily summary of settled online payments per tenant, checked against the payment gateway, for payout reconciliation
+
+CREATE TABLE IF NOT EXISTS settlement_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    report_date DATE NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    transaction_count INTEGER NOT NULL DEFAULT 0,
+    total_amount BIGINT NOT NULL DEFAULT 0,
+    mismatch_count INTEGER NOT NULL DEFAULT 0,
+    lines JSONB NOT NULL DEFAULT '[]',
+    mismatches JSONB NOT NULL DEFAULT '[]',
+    generated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT settlement_reports_tenant_date_key UNIQUE (tenant_id, report_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_reports_mismatches ON settlement_reports (tenant_id, report_date) WHERE mismatch_count > 0;
+
+COMMENT ON TABLE settlement_reports IS 'Settled online payments per tenant and local day, compared with the gateway; regenerated on demand';
+COMMENT ON COLUMN settlement_reports.timezone IS 'IANA time zone the report day was cut in (the tenant auto-complete time zone)';
+COMMENT ON COLUMN settlement_reports.lines IS 'JSON array of {gateway, payment_type, count, amount} for charges settled that day';
+COMMENT ON COLUMN settlement_reports.mismatches IS 'JSON array of charges whose recorded status or amount disagrees with the gateway';
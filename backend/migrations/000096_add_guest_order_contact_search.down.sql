@@ -0,0 +1,11 @@
+-- Migration: 000096_add_guest_order_contact_search.down.sql
+-- Purpose: Rollback guest order contact search
+
+DROP INDEX IF EXISTS idx_guest_orders_tenant_email_hash;
+
+DROP INDEX IF EXISTS idx_guest_orders_tenant_phone_hash;
+
+ALTER TABLE guest_orders
+DROP COLUMN IF EXISTS customer_phone_hash;
+
+COMMENT ON COLUMN guest_orders.customer_email_hash IS 'HMAC-SHA256 hash of customer_email for efficient lookups';
@@ -0,0 +1,16 @@
+-- Migration: 000096_add_guest_order_contact_search.up.sql
+-- Purpose: Let admins search orders by customer phone or email, which are stored encrypted
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS customer_phone_hash VARCHAR(64);
+
+-- customer_email_hash was added in 000043 but never written; searches are tenant-scoped
+CREATE INDEX IF NOT EXISTS idx_guest_orders_tenant_phone_hash ON guest_orders (tenant_id, customer_phone_hash)
+WHERE customer_phone_hash IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_guest_orders_tenant_email_hash ON guest_orders (tenant_id, customer_email_hash)
+WHERE customer_email_hash IS NOT NULL;
+
+COMMENT ON COLUMN guest_orders.customer_phone_hash IS 'HMAC-SHA256 hash of the normalized customer_phone (digits, 0-prefixed) for admin search; NULL once anonymized';
+
+COMMENT ON COLUMN guest_orders.customer_email_hash IS 'HMAC-SHA256 hash of the lowercased customer_email for admin search; NULL once anonymized';
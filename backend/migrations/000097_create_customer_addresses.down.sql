@@ -0,0 +1,4 @@
+-- Migration: 000097_create_customer_addresses.down.sql
+-- Purpose: Rollback saved guest delivery addresses
+
+DROP TABLE IF EXISTS customer_addresses;
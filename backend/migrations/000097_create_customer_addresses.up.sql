@@ -0,0 +1,26 @@
+-- Migration: 000097_create_customer_addresses.up.sql
+-- Purpose: Let guests who verified their phone keep delivery addresses to pick at checkout instead of retyping them
+
+CREATE TABLE IF NOT EXISTS customer_addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    phone_hash VARCHAR(64) NOT NULL,
+    address_hash VARCHAR(64) NOT NULL,
+    label VARCHAR(50),
+    address_text TEXT NOT NULL,
+    latitude DECIMAL(10, 8),
+    longitude DECIMAL(11, 8),
+    geocoded_address TEXT,
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT customer_addresses_guest_address_key UNIQUE (tenant_id, phone_hash, address_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_addresses_guest ON customer_addresses (tenant_id, phone_hash, last_used_at DESC);
+
+COMMENT ON TABLE customer_addresses IS 'Delivery addresses saved by guests who verified their phone by OTP';
+COMMENT ON COLUMN customer_addresses.phone_hash IS 'HMAC search hash of the guest''s normalized phone number';
+COMMENT ON COLUMN customer_addresses.address_hash IS 'HMAC search hash of the normalized address text, so an address is saved once';
+COMMENT ON COLUMN customer_addresses.address_text IS 'Encrypted address as typed by the guest';
+COMMENT ON COLUMN customer_addresses.geocoded_address IS 'Encrypted formatted address from geocoding; with latitude/longitude it spares a geocoding lookup at checkout';
@@ -0,0 +1,4 @@
+-- Migration: 000098_create_order_note_attachments.down.sql
+-- Purpose: Rollback order note photo attachments
+
+DROP TABLE IF EXISTS order_note_attachments;
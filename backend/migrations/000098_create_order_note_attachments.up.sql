@@ -0,0 +1,18 @@
+-- Migration: 000098_create_order_note_attachments.up.sql
+-- Purpose: Let staff attach photos (damaged items, proof of an issue) to order notes
+
+CREATE TABLE IF NOT EXISTS order_note_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    note_id UUID NOT NULL REFERENCES order_notes(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    storage_key VARCHAR(500) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_note_attachments_order ON order_note_attachments (order_id);
+
+COMMENT ON TABLE order_note_attachments IS 'Photos attached to order notes, stored in the object storage shared with product photos';
+COMMENT ON COLUMN order_note_attachments.storage_key IS 'Object key under order-notes/; URLs are presigned on read';
@@ -0,0 +1,5 @@
+-- Migration: 000099_create_merchant_webhooks.down.sql
+-- Purpose: Rollback merchant outbound webhooks
+
+DROP TABLE IF EXISTS merchant_webhook_deliveries;
+DROP TABLE IF EXISTS merchant_webhooks;
@@ -0,0 +1,39 @@
+-- Migration: 000099_create_merchant_webhooks.up.sql
+-- Purpose: Let tenants receive signed order lifecycle events at their own webhook URLs
+
+CREATE TABLE IF NOT EXISTS merchant_webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    secret_encrypted TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_webhooks_tenant ON merchant_webhooks (tenant_id) WHERE is_active;
+
+CREATE TABLE IF NOT EXISTS merchant_webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES merchant_webhooks(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_webhook_deliveries_due ON merchant_webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_merchant_webhook_deliveries_webhook ON merchant_webhook_deliveries (webhook_id, created_at DESC);
+
+COMMENT ON TABLE merchant_webhooks IS 'Tenant endpoints receiving order.created/paid/cancelled/refunded events';
+COMMENT ON COLUMN merchant_webhooks.secret_encrypted IS 'Vault-encrypted signing secret; payloads are signed with HMAC-SHA256';
+COMMENT ON TABLE merchant_webhook_deliveries IS 'Delivery log and retry queue of merchant webhook events';
+COMMENT ON COLUMN merchant_webhook_deliveries.event_id IS 'Shared by every webhook receiving the same event, for deduplication by receivers';
@@ -0,0 +1,5 @@
+-- Migration: 000100_create_order_returns.down.sql
+-- Purpose: Rollback order returns
+
+DROP TABLE IF EXISTS order_return_items;
+DROP TABLE IF EXISTS order_returns;
@@ -0,0 +1,53 @@
+-- Migration: 000100_create_order_returns.up.sql
+-- Purpose: RMA-style returns against order items, resolved by refund, exchange order or store credit
+
+CREATE TABLE IF NOT EXISTS order_returns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    return_number VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'approved', 'rejected', 'received', 'completed')),
+    resolution VARCHAR(20) NOT NULL CHECK (resolution IN ('refund', 'exchange', 'store_credit')),
+    reason TEXT,
+    credit_amount INTEGER NOT NULL CHECK (credit_amount >= 0),
+    rejection_reason TEXT,
+    refund_id VARCHAR(255),
+    store_credit_voucher_id UUID REFERENCES vouchers(id) ON DELETE SET NULL,
+    store_credit_code VARCHAR(50),
+    exchange_order_id UUID REFERENCES guest_orders(id) ON DELETE SET NULL,
+    requested_by UUID,
+    approved_by UUID,
+    received_by UUID,
+    completed_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    approved_at TIMESTAMP,
+    rejected_at TIMESTAMP,
+    received_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, return_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_returns_order ON order_returns (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_returns_tenant_status ON order_returns (tenant_id, status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS order_return_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    return_id UUID NOT NULL REFERENCES order_returns(id) ON DELETE CASCADE,
+    order_item_id UUID NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price INTEGER NOT NULL CHECK (unit_price >= 0),
+    reason TEXT,
+    restocked BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_return_items_return ON order_return_items (return_id);
+CREATE INDEX IF NOT EXISTS idx_order_return_items_order_item ON order_return_items (order_item_id);
+
+COMMENT ON TABLE order_returns IS 'Return merchandise authorizations: requested, approved or rejected, received (restocked), then completed with their resolution';
+COMMENT ON COLUMN order_returns.credit_amount IS 'Value credited back to the customer, capped at what the order has not already credited';
+COMMENT ON COLUMN order_returns.store_credit_code IS 'Single-use fixed voucher issued for store credit, or for the remainder of an exchange';
+COMMENT ON COLUMN order_return_items.restocked IS 'Whether the received goods were put back into stock; damaged goods are not';
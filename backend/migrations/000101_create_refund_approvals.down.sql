@@ -0,0 +1,7 @@
+-- Migration: 000101_create_refund_approvals.down.sql
+-- Purpose: Rollback refund approvals
+
+DROP TABLE IF EXISTS refund_approvals;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS refund_approval_threshold;
@@ -0,0 +1,36 @@
+-- Migration: 000101_create_refund_approvals.up.sql
+-- Purpose: Two-step refunds: cashiers request, managers approve, owners approve refunds above a threshold
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS refund_approval_threshold INTEGER NOT NULL DEFAULT 0
+    CHECK (refund_approval_threshold >= 0);
+
+COMMENT ON COLUMN order_settings.refund_approval_threshold IS 'Refunds above this amount need owner approval; 0 lets managers approve any amount';
+
+CREATE TABLE IF NOT EXISTS refund_approvals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    approver_role VARCHAR(20) NOT NULL CHECK (approver_role IN ('owner', 'manager')),
+    requested_by UUID,
+    requested_by_name VARCHAR(255),
+    requested_by_role VARCHAR(20) NOT NULL,
+    decided_by UUID,
+    decided_by_name VARCHAR(255),
+    decision_note TEXT,
+    refund_id VARCHAR(255),
+    gateway VARCHAR(20),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP
+);
+
+-- One open request per order, so the same refund cannot be queued twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refund_approvals_pending_order ON refund_approvals (order_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_refund_approvals_tenant_status ON refund_approvals (tenant_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_refund_approvals_order ON refund_approvals (order_id);
+
+COMMENT ON TABLE refund_approvals IS 'Refunds requested by staff: pending until approved (and sent to the gateway) or rejected. Refunds made directly by an approver are recorded as approved';
+COMMENT ON COLUMN refund_approvals.approver_role IS 'Lowest role allowed to approve: owner above the tenant threshold, manager otherwise';
@@ -0,0 +1,9 @@
+-- Migration: 000102_add_reservation_ttl.down.sql
+-- Purpose: Rollback reservation TTL settings and hold extensions
+
+ALTER TABLE inventory_reservations
+DROP COLUMN IF EXISTS extension_count;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS reservation_extension_minutes,
+DROP COLUMN IF EXISTS reservation_ttl_minutes;
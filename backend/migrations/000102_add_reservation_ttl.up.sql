@@ -0,0 +1,16 @@
+-- Migration: 000102_add_reservation_ttl.up.sql
+-- Purpose: Per-tenant checkout stock hold and guest extensions of an order's hold while paying
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS reservation_ttl_minutes INTEGER NOT NULL DEFAULT 15
+    CHECK (reservation_ttl_minutes >= 10 AND reservation_ttl_minutes <= 120),
+ADD COLUMN IF NOT EXISTS reservation_extension_minutes INTEGER NOT NULL DEFAULT 10
+    CHECK (reservation_extension_minutes >= 0 AND reservation_extension_minutes <= 60);
+
+COMMENT ON COLUMN order_settings.reservation_ttl_minutes IS 'Minutes checkout stock is held for QRIS and e-wallet orders; never shorter than payment_expiry_minutes';
+COMMENT ON COLUMN order_settings.reservation_extension_minutes IS 'Minutes added each time a guest extends an unpaid order''s stock hold; 0 disables extensions';
+
+ALTER TABLE inventory_reservations
+ADD COLUMN IF NOT EXISTS extension_count INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN inventory_reservations.extension_count IS 'How many times the hold was extended while the order was being paid';
@@ -0,0 +1,12 @@
+-- Migration: 000103_add_item_out_of_stock.down.sql
+-- Purpose: Rollback item-level out-of-stock status
+
+UPDATE order_items SET prep_status = 'queued' WHERE prep_status = 'out_of_stock';
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_prep_status_check;
+
+ALTER TABLE order_items
+ADD CONSTRAINT order_items_prep_status_check
+    CHECK (prep_status IN ('queued', 'preparing', 'ready'));
+
+COMMENT ON COLUMN order_items.prep_status IS 'Kitchen preparation status: queued, preparing or ready';
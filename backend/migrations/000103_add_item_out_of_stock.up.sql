@@ -0,0 +1,10 @@
+-- Migration: 000103_add_item_out_of_stock.up.sql
+-- Purpose: Item-level fulfillment - let the kitchen mark an order item out of stock so the rest can be handed out
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_prep_status_check;
+
+ALTER TABLE order_items
+ADD CONSTRAINT order_items_prep_status_check
+    CHECK (prep_status IN ('queued', 'preparing', 'ready', 'out_of_stock'));
+
+COMMENT ON COLUMN order_items.prep_status IS 'Kitchen preparation status: queued, preparing, ready or out_of_stock';
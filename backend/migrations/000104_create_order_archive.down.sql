@@ -0,0 +1,6 @@
+-- Migration: 000104_create_order_archive.down.sql
+-- Purpose: Rollback order cold storage (drops the archive tables and all their partitions)
+
+DROP TABLE IF EXISTS archived_payment_transactions;
+DROP TABLE IF EXISTS archived_order_items;
+DROP TABLE IF EXISTS archived_guest_orders;
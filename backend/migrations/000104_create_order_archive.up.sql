@@ -0,0 +1,73 @@
+-- Migration: 000104_create_order_archive.up.sql
+-- Purpose: Cold storage for closed orders - monthly partitioned archive tables for guest orders, their items and payments
+
+-- Partitions are created by the order archive job for each month it archives (see OrderPartitionService).
+-- Archived rows keep no customer data: the archive is the operational and finance record of the order.
+CREATE TABLE IF NOT EXISTS archived_guest_orders (
+    id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    order_reference VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    order_type VARCHAR(20) NOT NULL,
+    delivery_type VARCHAR(20) NOT NULL,
+    table_number VARCHAR(50),
+    subtotal_amount INTEGER NOT NULL,
+    delivery_fee INTEGER NOT NULL,
+    promotion_discount_amount INTEGER NOT NULL DEFAULT 0,
+    discount_amount INTEGER NOT NULL DEFAULT 0,
+    loyalty_discount_amount INTEGER NOT NULL DEFAULT 0,
+    service_charge_amount INTEGER NOT NULL DEFAULT 0,
+    tax_amount INTEGER NOT NULL DEFAULT 0,
+    total_amount INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    paid_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, created_at)
+)
+PARTITION BY RANGE (created_at);
+
+CREATE TABLE IF NOT EXISTS archived_order_items (
+    id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    product_sku VARCHAR(100),
+    quantity INTEGER NOT NULL,
+    unit_price INTEGER NOT NULL,
+    total_price INTEGER NOT NULL,
+    order_created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id, order_created_at)
+)
+PARTITION BY RANGE (order_created_at);
+
+-- Gateway charges (payment_transactions) and staff-recorded payments (payment_records) side by side
+CREATE TABLE IF NOT EXISTS archived_payment_transactions (
+    id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    source VARCHAR(10) NOT NULL CHECK (source IN ('gateway', 'staff')),
+    method VARCHAR(50),
+    reference VARCHAR(255),
+    amount INTEGER NOT NULL,
+    status VARCHAR(50),
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    order_created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (id, order_created_at)
+)
+PARTITION BY RANGE (order_created_at);
+
+-- Reference lookups (guest order page) and tenant lookups reach into every partition
+CREATE INDEX IF NOT EXISTS idx_archived_guest_orders_reference ON archived_guest_orders (order_reference);
+CREATE INDEX IF NOT EXISTS idx_archived_guest_orders_tenant ON archived_guest_orders (tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_archived_guest_orders_id ON archived_guest_orders (id);
+CREATE INDEX IF NOT EXISTS idx_archived_order_items_order ON archived_order_items (order_id);
+CREATE INDEX IF NOT EXISTS idx_archived_payment_transactions_order ON archived_payment_transactions (order_id);
+
+COMMENT ON TABLE archived_guest_orders IS 'Closed orders moved out of guest_orders by the order archive job, partitioned monthly by created_at';
+COMMENT ON COLUMN archived_guest_orders.archived_at IS 'When the order was moved to cold storage';
+COMMENT ON TABLE archived_order_items IS 'Items of archived orders, partitioned monthly by the order''s created_at';
+COMMENT ON TABLE archived_payment_transactions IS 'Payments of archived orders, partitioned monthly by the order''s created_at';
+COMMENT ON COLUMN archived_payment_transactions.source IS 'gateway: online charge from payment_transactions; staff: payment from payment_records';
+COMMENT ON COLUMN archived_payment_transactions.reference IS 'Gateway transaction ID or staff receipt number';
@@ -0,0 +1,4 @@
+-- Migration: 000105_create_delivery_proofs.down.sql
+-- Purpose: Rollback proof of delivery
+
+DROP TABLE IF EXISTS delivery_proofs;
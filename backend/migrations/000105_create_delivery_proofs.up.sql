@@ -0,0 +1,20 @@
+-- Migration: 000105_create_delivery_proofs.up.sql
+-- Purpose: Proof of delivery - recipient name and photo recorded when a delivery order is handed over
+
+CREATE TABLE IF NOT EXISTS delivery_proofs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL UNIQUE REFERENCES guest_orders(id) ON DELETE CASCADE,
+    recipient_name TEXT NOT NULL,
+    photo_storage_key VARCHAR(255),
+    photo_content_type VARCHAR(50),
+    photo_size_bytes BIGINT,
+    captured_by_user_id UUID,
+    captured_by_name VARCHAR(255),
+    delivered_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE delivery_proofs IS 'Proof of delivery for delivery orders, one per order; removed when the guest''s data is anonymized';
+COMMENT ON COLUMN delivery_proofs.recipient_name IS 'Encrypted name of the person who received the order';
+COMMENT ON COLUMN delivery_proofs.photo_storage_key IS 'Object storage key of the delivery photo: delivery-proofs/{tenant_id}/{order_id}/{proof_id}{ext}';
+COMMENT ON COLUMN delivery_proofs.captured_by_name IS 'Driver or staff member who recorded the delivery';
@@ -0,0 +1,10 @@
+-- Migration: 000106_add_order_reference_pattern.down.sql
+-- Purpose: Rollback tenant order reference patterns
+-- Note: order_reference stays VARCHAR(40); patterned references may already be longer than 20 characters
+
+DROP TABLE IF EXISTS order_reference_sequences;
+
+DROP INDEX IF EXISTS idx_order_settings_reference_prefix;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS order_reference_pattern;
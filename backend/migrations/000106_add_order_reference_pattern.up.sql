@@ -0,0 +1,25 @@
+-- Migration: 000106_add_order_reference_pattern.up.sql
+-- Purpose: Tenant order reference patterns (e.g. WARUNG-YYYYMMDD-####) with a per-tenant daily sequence
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS order_reference_pattern VARCHAR(32);
+
+COMMENT ON COLUMN order_settings.order_reference_pattern IS 'Order reference pattern; NULL uses the global GO-XXXXXX generator';
+
+-- The prefix identifies a tenant's references, so no two tenants share one
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_settings_reference_prefix
+    ON order_settings (split_part(order_reference_pattern, '-', 1))
+    WHERE order_reference_pattern IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS order_reference_sequences (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    sequence_date DATE NOT NULL,
+    last_value INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, sequence_date)
+);
+
+COMMENT ON TABLE order_reference_sequences IS 'Last order reference sequence number used per tenant and local day';
+
+-- Patterned references can be longer than GO-XXXXXX
+ALTER TABLE guest_orders ALTER COLUMN order_reference TYPE VARCHAR(40);
+ALTER TABLE archived_guest_orders ALTER COLUMN order_reference TYPE VARCHAR(40);
@@ -0,0 +1,9 @@
+-- Migration: 000107_add_order_sla.down.sql
+-- Purpose: Rollback order SLA targets and late-order alerts
+
+DROP TABLE IF EXISTS order_sla_alerts;
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS sla_at_risk_percent,
+DROP COLUMN IF EXISTS sla_ready_minutes,
+DROP COLUMN IF EXISTS sla_preparing_minutes;
@@ -0,0 +1,24 @@
+-- Migration: 000107_add_order_sla.up.sql
+-- Purpose: Per-tenant SLA targets for paid orders in the kitchen, and the late-order alerts sent for them
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS sla_preparing_minutes INTEGER NOT NULL DEFAULT 0
+    CHECK (sla_preparing_minutes >= 0 AND sla_preparing_minutes <= 240),
+ADD COLUMN IF NOT EXISTS sla_ready_minutes INTEGER NOT NULL DEFAULT 0
+    CHECK (sla_ready_minutes >= 0 AND sla_ready_minutes <= 240),
+ADD COLUMN IF NOT EXISTS sla_at_risk_percent INTEGER NOT NULL DEFAULT 80
+    CHECK (sla_at_risk_percent >= 1 AND sla_at_risk_percent <= 99);
+
+COMMENT ON COLUMN order_settings.sla_preparing_minutes IS 'Minutes from payment until preparation should start; 0 does not time this stage';
+COMMENT ON COLUMN order_settings.sla_ready_minutes IS 'Minutes from preparation start until the order should be ready; 0 does not time this stage';
+COMMENT ON COLUMN order_settings.sla_at_risk_percent IS 'Share of a stage target after which the order is flagged at risk';
+
+-- One alert per order and late stage
+CREATE TABLE IF NOT EXISTS order_sla_alerts (
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    stage VARCHAR(20) NOT NULL CHECK (stage IN ('waiting', 'preparing')),
+    alerted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, stage)
+);
+
+COMMENT ON TABLE order_sla_alerts IS 'Late-order alerts sent to staff, so each late stage of an order is reported once';
@@ -0,0 +1,5 @@
+-- Migration: 000108_create_cashier_shifts.down.sql
+-- Purpose: Rollback cashier shifts and cash refunds
+
+DROP TABLE IF EXISTS cash_refunds;
+DROP TABLE IF EXISTS cashier_shifts;
@@ -0,0 +1,47 @@
+-- Migration: 000108_create_cashier_shifts.up.sql
+-- Purpose: Cashier shifts on a cash drawer, from opening float to cash count, and the cash refunds paid from them
+
+CREATE TABLE IF NOT EXISTS cashier_shifts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    cashier_id UUID NOT NULL REFERENCES users(id),
+    cashier_name VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opening_float INTEGER NOT NULL CHECK (opening_float >= 0),
+    cash_sales INTEGER NOT NULL DEFAULT 0,
+    cash_refunds INTEGER NOT NULL DEFAULT 0,
+    expected_cash INTEGER NOT NULL DEFAULT 0,
+    counted_cash INTEGER CHECK (counted_cash IS NULL OR counted_cash >= 0),
+    variance INTEGER,
+    close_note TEXT,
+    closed_by UUID,
+    closed_by_name VARCHAR(255),
+    opened_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP,
+    CHECK ((status = 'open') = (closed_at IS NULL))
+);
+
+-- One open shift per cashier
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cashier_shifts_open_cashier ON cashier_shifts (tenant_id, cashier_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_cashier_shifts_tenant_opened ON cashier_shifts (tenant_id, opened_at DESC);
+
+COMMENT ON TABLE cashier_shifts IS 'Cashier sessions on a cash drawer. Cash totals are computed while open and frozen at close';
+COMMENT ON COLUMN cashier_shifts.cash_sales IS 'Cash payments recorded by the cashier during the shift, net of change';
+COMMENT ON COLUMN cashier_shifts.expected_cash IS 'opening_float + cash_sales - cash_refunds';
+COMMENT ON COLUMN cashier_shifts.variance IS 'counted_cash - expected_cash; negative when the drawer is short';
+
+CREATE TABLE IF NOT EXISTS cash_refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shift_id UUID NOT NULL REFERENCES cashier_shifts(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES guest_orders(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    recorded_by UUID,
+    recorded_by_name VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cash_refunds_shift ON cash_refunds (shift_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_cash_refunds_order ON cash_refunds (order_id);
+
+COMMENT ON TABLE cash_refunds IS 'Cash paid back to customers from a shift drawer, limited to the cash paid for the order';
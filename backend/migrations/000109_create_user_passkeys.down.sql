@@ -0,0 +1,4 @@
+-- Migration: 000109_create_user_passkeys.down.sql
+-- Purpose: Rollback user passkeys
+
+DROP TABLE IF EXISTS user_passkeys;
@@ -0,0 +1,22 @@
+-- Migration: 000109_create_user_passkeys.up.sql
+-- Purpose: Store WebAuthn passkeys staff can log in with instead of a password
+
+CREATE TABLE IF NOT EXISTS user_passkeys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    algorithm INTEGER NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    transports TEXT[] NOT NULL DEFAULT '{}',
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_user_passkeys_user ON user_passkeys(tenant_id, user_id);
+
+COMMENT ON TABLE user_passkeys IS 'WebAuthn credentials (passkeys) registered by staff for passwordless login';
+COMMENT ON COLUMN user_passkeys.public_key IS 'COSE-encoded credential public key';
+COMMENT ON COLUMN user_passkeys.sign_count IS 'Last signature counter reported by the authenticator, used to detect cloned credentials';
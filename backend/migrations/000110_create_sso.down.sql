@@ -0,0 +1,5 @@
+-- Migration: 000110_create_sso.down.sql
+-- Purpose: Rollback Google single sign-on tables
+
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS tenant_sso_settings;
@@ -0,0 +1,28 @@
+-- Migration: 000110_create_sso.up.sql
+-- Purpose: Google single sign-on: per-tenant SSO policy and provider accounts linked to users
+
+CREATE TABLE IF NOT EXISTS tenant_sso_settings (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    google_enabled BOOLEAN NOT NULL DEFAULT false,
+    sso_only BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT chk_sso_only_needs_provider CHECK (NOT sso_only OR google_enabled)
+);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('google')),
+    subject VARCHAR(255) NOT NULL,
+    linked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT uq_user_identities_subject UNIQUE (tenant_id, provider, subject),
+    CONSTRAINT uq_user_identities_user UNIQUE (tenant_id, user_id, provider)
+);
+
+COMMENT ON TABLE tenant_sso_settings IS 'Per-tenant single sign-on policy; tenants without a row have SSO disabled';
+COMMENT ON COLUMN tenant_sso_settings.sso_only IS 'When true, password and passkey logins are refused';
+COMMENT ON TABLE user_identities IS 'Identity provider accounts linked to users by verified email on first SSO login';
+COMMENT ON COLUMN user_identities.subject IS 'Stable account ID from the provider (ID token sub claim)';
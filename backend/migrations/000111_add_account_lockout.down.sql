@@ -0,0 +1,9 @@
+-- Migration: 000111_add_account_lockout.down.sql
+-- Purpose: Rollback account lockout columns
+
+DROP INDEX IF EXISTS idx_users_locked_until;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS last_failed_login_at,
+    DROP COLUMN IF EXISTS failed_login_attempts;
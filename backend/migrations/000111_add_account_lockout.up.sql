@@ -0,0 +1,14 @@
+-- Migration: 000111_add_account_lockout.up.sql
+-- Purpose: Track failed password logins per user and lock accounts with exponential backoff
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_failed_login_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_locked_until ON users (tenant_id, locked_until)
+WHERE
+    locked_until IS NOT NULL;
+
+COMMENT ON COLUMN users.failed_login_attempts IS 'Consecutive failed password logins; reset by a successful login, an admin unlock or a quiet period';
+COMMENT ON COLUMN users.locked_until IS 'Password logins are refused until this time; doubles with each failure past the lockout threshold';
@@ -0,0 +1,4 @@
+-- Migration: 000112_create_user_password_history.down.sql
+-- Purpose: Rollback user password history
+
+DROP TABLE IF EXISTS user_password_history;
@@ -0,0 +1,14 @@
+-- Migration: 000112_create_user_password_history.up.sql
+-- Purpose: Remember previous password hashes so a reset cannot reuse a recent password
+
+CREATE TABLE IF NOT EXISTS user_password_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_password_history_user ON user_password_history (user_id, created_at DESC);
+
+COMMENT ON TABLE user_password_history IS 'Previous bcrypt password hashes, pruned to PASSWORD_HISTORY_SIZE - 1 per user';
@@ -0,0 +1,6 @@
+-- Migration: 000113_create_tenant_roles.down.sql
+-- Purpose: Rollback custom tenant roles
+
+DROP INDEX IF EXISTS idx_users_custom_role_id;
+ALTER TABLE users DROP COLUMN IF EXISTS custom_role_id;
+DROP TABLE IF EXISTS tenant_roles;
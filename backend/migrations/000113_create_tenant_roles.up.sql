@@ -0,0 +1,23 @@
+-- Migration: 000113_create_tenant_roles.up.sql
+-- Purpose: Let owners define custom roles composed of named permissions and assign them to staff
+
+CREATE TABLE IF NOT EXISTS tenant_roles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    description VARCHAR(255),
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_tenant_roles_tenant_name ON tenant_roles (tenant_id, LOWER(name));
+
+-- Deleting a role returns its users to the permissions of their base role
+ALTER TABLE users ADD COLUMN IF NOT EXISTS custom_role_id UUID REFERENCES tenant_roles(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_users_custom_role_id ON users (custom_role_id) WHERE custom_role_id IS NOT NULL;
+
+COMMENT ON TABLE tenant_roles IS 'Owner-defined roles; their permissions replace those of the assigned user''s base role';
+COMMENT ON COLUMN users.custom_role_id IS 'Custom role granting the user''s permissions; the base role still applies to service-level checks';
@@ -0,0 +1,4 @@
+-- Migration: 000114_create_platform_operators.down.sql
+-- Purpose: Rollback platform operators
+
+DROP TABLE IF EXISTS platform_operators;
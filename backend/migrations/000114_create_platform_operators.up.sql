@@ -0,0 +1,16 @@
+-- Migration: 000114_create_platform_operators.up.sql
+-- Purpose: Platform support operators who may impersonate tenant users
+-- Operators are provisioned out of band; only the SHA-256 hash of their API key is stored.
+
+CREATE TABLE IF NOT EXISTS platform_operators (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    api_key_hash CHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'disabled')),
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE platform_operators IS 'Platform staff allowed to start time-limited impersonation sessions';
+COMMENT ON COLUMN platform_operators.api_key_hash IS 'Hex SHA-256 of the operator API key sent as a Bearer token';
@@ -0,0 +1,4 @@
+-- Migration: 000115_create_tenant_captcha_settings.down.sql
+-- Purpose: Rollback per-tenant CAPTCHA settings
+
+DROP TABLE IF EXISTS tenant_captcha_settings;
@@ -0,0 +1,12 @@
+-- Migration: 000115_create_tenant_captcha_settings.up.sql
+-- Purpose: Per-tenant switch for CAPTCHA on login and password reset, on top of the environment-wide CAPTCHA_ENABLED
+
+CREATE TABLE IF NOT EXISTS tenant_captcha_settings (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE tenant_captcha_settings IS 'Per-tenant CAPTCHA policy; tenants without a row require CAPTCHA whenever it is enabled for the environment';
+COMMENT ON COLUMN tenant_captcha_settings.enabled IS 'When false, the tenant''s users log in and reset passwords without solving a CAPTCHA';
@@ -0,0 +1,4 @@
+-- Migration: 000116_create_user_known_devices.down.sql
+-- Purpose: Rollback known login devices
+
+DROP TABLE IF EXISTS user_known_devices;
@@ -0,0 +1,21 @@
+-- Migration: 000116_create_user_known_devices.up.sql
+-- Purpose: Devices and countries each user has logged in from, to alert on logins from new ones
+
+CREATE TABLE IF NOT EXISTS user_known_devices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_hash VARCHAR(64) NOT NULL,
+    country_code VARCHAR(2) NOT NULL DEFAULT '',
+    user_agent TEXT,
+    last_ip_address VARCHAR(45),
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_user_known_devices UNIQUE (user_id, device_hash, country_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_known_devices_user ON user_known_devices(tenant_id, user_id);
+
+COMMENT ON TABLE user_known_devices IS 'One row per device and country a user has logged in from';
+COMMENT ON COLUMN user_known_devices.device_hash IS 'SHA-256 of the long-lived device cookie; empty when the browser sent none';
+COMMENT ON COLUMN user_known_devices.country_code IS 'ISO 3166-1 alpha-2 country from the edge proxy header; empty when unknown';
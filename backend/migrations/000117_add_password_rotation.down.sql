@@ -0,0 +1,8 @@
+-- Migration: 000117_add_password_rotation.down.sql
+-- Purpose: Rollback forced credential rotation
+
+DROP TABLE IF EXISTS tenant_password_rotation;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS must_change_password,
+    DROP COLUMN IF EXISTS password_changed_at;
@@ -0,0 +1,19 @@
+-- Migration: 000117_add_password_rotation.up.sql
+-- Purpose: Forced credential rotation: password age and change-on-next-login flag per user, max password age per tenant
+
+-- Existing users start their password age at this migration, as the real change date is unknown
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS tenant_password_rotation (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    max_age_days INTEGER NOT NULL DEFAULT 0 CHECK (max_age_days >= 0 AND max_age_days <= 3650),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+COMMENT ON COLUMN users.password_changed_at IS 'When the password was last set, for the tenant maximum password age';
+COMMENT ON COLUMN users.must_change_password IS 'When true, the next password login must set a new password before a session is issued';
+COMMENT ON TABLE tenant_password_rotation IS 'Per-tenant password rotation policy; tenants without a row never expire passwords';
+COMMENT ON COLUMN tenant_password_rotation.max_age_days IS 'Days a password stays valid before it must be changed at login; 0 disables expiry';
@@ -0,0 +1,9 @@
+-- Migration: 000118_create_scim_provisioning.down.sql
+-- Purpose: Rollback SCIM provisioning
+
+DROP INDEX IF EXISTS idx_users_scim_external_id;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS scim_groups,
+    DROP COLUMN IF EXISTS scim_external_id;
+DROP TABLE IF EXISTS tenant_scim_group_mappings;
+DROP TABLE IF EXISTS tenant_scim_tokens;
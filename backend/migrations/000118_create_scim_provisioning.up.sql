@@ -0,0 +1,38 @@
+-- Migration: 000118_create_scim_provisioning.up.sql
+-- Purpose: SCIM 2.0 user provisioning from identity providers: per-tenant API tokens, group-to-role mappings, external IDs
+
+CREATE TABLE IF NOT EXISTS tenant_scim_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    token_prefix VARCHAR(16) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_tenant_scim_tokens_tenant ON tenant_scim_tokens (tenant_id);
+
+CREATE TABLE IF NOT EXISTS tenant_scim_group_mappings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    group_name VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('manager', 'cashier')),
+    custom_role_id UUID REFERENCES tenant_roles(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_tenant_scim_group_mappings_name ON tenant_scim_group_mappings (tenant_id, LOWER(group_name));
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS scim_external_id VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS scim_groups JSONB;
+
+CREATE UNIQUE INDEX idx_users_scim_external_id ON users (tenant_id, scim_external_id) WHERE scim_external_id IS NOT NULL;
+
+COMMENT ON TABLE tenant_scim_tokens IS 'Bearer tokens an identity provider uses to call the SCIM endpoint; only the SHA-256 hash is stored';
+COMMENT ON TABLE tenant_scim_group_mappings IS 'Identity provider groups and the base role (and optional custom role) their members are provisioned with';
+COMMENT ON COLUMN users.scim_external_id IS 'Identifier the identity provider knows the user by (SCIM externalId)';
+COMMENT ON COLUMN users.scim_groups IS 'Identity provider groups last provisioned for the user; their mapping sets the user''s role';
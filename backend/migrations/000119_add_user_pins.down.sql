@@ -0,0 +1,6 @@
+-- Migration: 000119_add_user_pins.down.sql
+-- Purpose: Rollback cashier PINs
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS pin_updated_at,
+    DROP COLUMN IF EXISTS pin_hash;
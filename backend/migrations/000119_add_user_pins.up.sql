@@ -0,0 +1,8 @@
+-- Migration: 000119_add_user_pins.up.sql
+-- Purpose: Cashier PINs for quick user switching on shared POS terminals
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS pin_hash VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS pin_updated_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.pin_hash IS 'bcrypt hash of the quick-switch PIN; NULL when the user has no PIN';
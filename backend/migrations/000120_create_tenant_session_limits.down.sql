@@ -0,0 +1,4 @@
+-- Migration: 000120_create_tenant_session_limits.down.sql
+-- Purpose: Rollback concurrent session limits
+
+DROP TABLE IF EXISTS tenant_session_limits;
@@ -0,0 +1,16 @@
+-- Migration: 000120_create_tenant_session_limits.up.sql
+-- Purpose: Per-tenant limit on concurrent sessions per user, by role, and what happens when a login exceeds it
+
+CREATE TABLE IF NOT EXISTS tenant_session_limits (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    owner_max_sessions INTEGER NOT NULL DEFAULT 0 CHECK (owner_max_sessions >= 0 AND owner_max_sessions <= 20),
+    manager_max_sessions INTEGER NOT NULL DEFAULT 0 CHECK (manager_max_sessions >= 0 AND manager_max_sessions <= 20),
+    cashier_max_sessions INTEGER NOT NULL DEFAULT 0 CHECK (cashier_max_sessions >= 0 AND cashier_max_sessions <= 20),
+    on_limit VARCHAR(20) NOT NULL DEFAULT 'reject' CHECK (on_limit IN ('reject', 'evict_oldest')),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE tenant_session_limits IS 'Per-tenant concurrent session limits; tenants without a row allow unlimited sessions';
+COMMENT ON COLUMN tenant_session_limits.cashier_max_sessions IS 'Concurrent sessions each cashier may have; 0 means unlimited';
+COMMENT ON COLUMN tenant_session_limits.on_limit IS 'reject refuses the new login; evict_oldest logs out the oldest sessions to make room';
@@ -0,0 +1,4 @@
+-- Migration: 000121_create_email_change_requests.down.sql
+-- Purpose: Rollback email change requests
+
+DROP TABLE IF EXISTS email_change_requests;
@@ -0,0 +1,23 @@
+-- Migration: 000121_create_email_change_requests.up.sql
+-- Purpose: Two-step login email changes, confirmed from the current address and verified at the new one
+
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email VARCHAR(512) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    status VARCHAR(30) NOT NULL DEFAULT 'pending_confirmation'
+        CHECK (status IN ('pending_confirmation', 'pending_verification', 'completed', 'cancelled')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_change_requests_token ON email_change_requests(token_hash);
+CREATE INDEX IF NOT EXISTS idx_email_change_requests_user ON email_change_requests(user_id, created_at);
+
+COMMENT ON TABLE email_change_requests IS 'Login email changes; a new request cancels the user''s pending one';
+COMMENT ON COLUMN email_change_requests.new_email IS 'Requested address, encrypted with the user:email context';
+COMMENT ON COLUMN email_change_requests.token_hash IS 'SHA-256 of the token emailed for the current step: confirmation from the old address, then verification at the new one';
@@ -0,0 +1,12 @@
+-- Migration: 000122_add_invitation_expiry.down.sql
+-- Purpose: Rollback invitation lifecycle changes
+
+DROP INDEX IF EXISTS idx_invitations_pending_email;
+ALTER TABLE invitations ADD CONSTRAINT unique_tenant_email_invitation UNIQUE (tenant_id, email, status);
+
+UPDATE invitations SET status = 'cancelled' WHERE status = 'revoked';
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_status_check;
+ALTER TABLE invitations ADD CONSTRAINT invitations_status_check
+    CHECK (status IN ('pending', 'accepted', 'expired', 'cancelled'));
+
+COMMENT ON COLUMN invitations.expires_at IS 'Invitation expiration (typically 7 days)';
@@ -0,0 +1,12 @@
+-- Migration: 000122_add_invitation_expiry.up.sql
+-- Purpose: Invitation lifecycle: revoked status, and one pending invitation per address instead of one per status
+
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_status_check;
+ALTER TABLE invitations ADD CONSTRAINT invitations_status_check
+    CHECK (status IN ('pending', 'accepted', 'expired', 'cancelled', 'revoked'));
+
+-- The old constraint allowed a single expired invitation per address, so expiring a second one failed
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS unique_tenant_email_invitation;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invitations_pending_email ON invitations (tenant_id, email) WHERE status = 'pending';
+
+COMMENT ON COLUMN invitations.expires_at IS 'Invitation expiration (INVITATION_TTL_HOURS); opening an expired link sends a new one';
@@ -0,0 +1,4 @@
+-- Migration: 000123_create_user_data_exports.down.sql
+-- Purpose: Rollback personal data exports
+
+DROP TABLE IF EXISTS user_data_exports;
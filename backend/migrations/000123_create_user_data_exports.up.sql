@@ -0,0 +1,23 @@
+-- Migration: 000123_create_user_data_exports.up.sql
+-- Purpose: Personal data exports (UU PDP data access requests), assembled in the background and downloaded with a signed link
+
+CREATE TABLE IF NOT EXISTS user_data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format VARCHAR(10) NOT NULL DEFAULT 'json' CHECK (format IN ('json', 'zip')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    bundle TEXT,
+    size_bytes INTEGER,
+    error_msg TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_data_exports_user ON user_data_exports(tenant_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_user_data_exports_expires ON user_data_exports(expires_at) WHERE expires_at IS NOT NULL;
+
+COMMENT ON TABLE user_data_exports IS 'Personal data bundles requested by users; deleted once the download link expires';
+COMMENT ON COLUMN user_data_exports.bundle IS 'Base64 of the JSON or ZIP bundle, encrypted with the user_data_export:bundle context';
+COMMENT ON COLUMN user_data_exports.expires_at IS 'End of the download link lifetime, set when the bundle is ready';
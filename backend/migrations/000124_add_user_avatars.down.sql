@@ -0,0 +1,6 @@
+-- Migration: 000124_add_user_avatars.down.sql
+-- Purpose: Rollback user avatars
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS avatar_updated_at,
+DROP COLUMN IF EXISTS avatar_storage_key;
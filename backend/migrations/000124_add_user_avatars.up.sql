@@ -0,0 +1,8 @@
+-- Migration: 000124_add_user_avatars.up.sql
+-- Purpose: Profile pictures of users, stored in the object storage shared with product photos
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS avatar_storage_key VARCHAR(512),
+ADD COLUMN IF NOT EXISTS avatar_updated_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN users.avatar_storage_key IS 'Object storage key of the avatar: avatars/{tenant_id}/{user_id}/{avatar_id}.jpg';
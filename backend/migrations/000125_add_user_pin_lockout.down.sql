@@ -0,0 +1,6 @@
+-- Migration: 000125_add_user_pin_lockout.down.sql
+-- Purpose: Rollback quick-switch PIN lockout
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS pin_locked_at,
+    DROP COLUMN IF EXISTS pin_failed_attempts;
@@ -0,0 +1,9 @@
+-- Migration: 000125_add_user_pin_lockout.up.sql
+-- Purpose: Lock a user's quick-switch PIN after repeated wrong entries until the PIN is set or reset again
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS pin_failed_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS pin_locked_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.pin_failed_attempts IS 'Wrong PIN entries since the last successful PIN switch or PIN change';
+COMMENT ON COLUMN users.pin_locked_at IS 'When PIN switching was locked for the user; NULL while the PIN can be used';
@@ -0,0 +1,11 @@
+-- Migration: 000126_create_user_notification_preferences.down.sql
+-- Purpose: Rollback per-event, per-channel notification preferences
+
+DELETE FROM notifications WHERE type = 'in_app';
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications
+    ADD CONSTRAINT notifications_type_check CHECK (type IN ('email', 'sms', 'push'));
+
+COMMENT ON COLUMN users.receive_order_notifications IS 'Whether user receives email notifications for paid orders';
+
+DROP TABLE IF EXISTS user_notification_preferences;
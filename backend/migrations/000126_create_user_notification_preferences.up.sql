@@ -0,0 +1,32 @@
+-- Migration: 000126_create_user_notification_preferences.up.sql
+-- Purpose: Per-user notification preferences as a matrix of staff event type and delivery channel
+
+CREATE TABLE IF NOT EXISTS user_notification_preferences (
+    tenant_id UUID NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('email', 'whatsapp', 'push', 'in_app')),
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, event_type, channel)
+);
+
+CREATE INDEX idx_user_notification_preferences_tenant ON user_notification_preferences (tenant_id, event_type, channel)
+WHERE enabled = true;
+
+-- Staff who opted in to order emails keep receiving them for every order event
+INSERT INTO user_notification_preferences (tenant_id, user_id, event_type, channel, enabled)
+SELECT u.tenant_id, u.id, e.event_type, 'email', true
+FROM users u
+CROSS JOIN (VALUES ('order.paid'), ('order.manual_payment_required'), ('order.sla_breached')) AS e (event_type)
+WHERE u.receive_order_notifications = true
+ON CONFLICT DO NOTHING;
+
+-- In-app notifications are stored for the recipient's notification list
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications
+    ADD CONSTRAINT notifications_type_check CHECK (type IN ('email', 'sms', 'push', 'in_app'));
+
+COMMENT ON TABLE user_notification_preferences IS 'Whether a user receives a staff event on a channel; a missing cell falls back to the event''s default';
+COMMENT ON COLUMN user_notification_preferences.event_type IS 'Staff notification event, e.g. order.paid or refund.approval_requested';
+COMMENT ON COLUMN users.receive_order_notifications IS 'Deprecated: mirrors the order.paid email cell of user_notification_preferences';
@@ -0,0 +1,6 @@
+-- Migration: 000127_create_teams.down.sql
+-- Purpose: Rollback staff teams
+
+DROP TABLE IF EXISTS team_notification_routes;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,50 @@
+-- Migration: 000127_create_teams.up.sql
+-- Purpose: Group staff into teams (Kitchen, Front-of-house, Back office, ...) used to route staff notifications and filter staff lists
+
+CREATE TABLE IF NOT EXISTS teams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    description VARCHAR(255),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_teams_tenant_name ON teams (tenant_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user ON team_members (user_id);
+
+CREATE TABLE IF NOT EXISTS team_notification_routes (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    delivery_types TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, event_type)
+);
+
+CREATE INDEX idx_team_notification_routes_event ON team_notification_routes (tenant_id, event_type);
+
+-- Every existing tenant starts with the usual teams; owners can rename or delete them
+INSERT INTO teams (tenant_id, name, description)
+SELECT t.id, d.name, d.description
+FROM tenants t
+CROSS JOIN (VALUES
+    ('Kitchen', 'Prepares orders'),
+    ('Front-of-house', 'Serves customers and takes payments'),
+    ('Back office', 'Stock, reporting and administration')
+) AS d(name, description)
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE teams IS 'Tenant-defined groups of staff; a user may belong to several teams';
+COMMENT ON TABLE team_notification_routes IS 'Staff notification events sent to a team''s members; members still opt out per channel in user_notification_preferences';
+COMMENT ON COLUMN team_notification_routes.delivery_types IS 'Order delivery types (pickup, delivery, dine_in) the route is limited to; empty matches every order';
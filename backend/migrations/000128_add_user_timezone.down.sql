@@ -0,0 +1,4 @@
+-- Migration: 000128_add_user_timezone.down.sql
+-- Purpose: Rollback user timezones
+
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,7 @@
+-- Migration: 000128_add_user_timezone.up.sql
+-- Purpose: Let each user pick the timezone notifications and analytics are shown in; NULL keeps the server's timezone
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+COMMENT ON COLUMN users.timezone IS 'IANA timezone name such as Asia/Jakarta; NULL uses the server timezone';
@@ -0,0 +1,8 @@
+-- Migration: 000129_create_outlets.down.sql
+-- Purpose: Rollback outlets
+
+ALTER TABLE guest_orders DROP COLUMN IF EXISTS outlet_id;
+ALTER TABLE products DROP COLUMN IF EXISTS outlet_id;
+ALTER TABLE users DROP COLUMN IF EXISTS outlet_id;
+
+DROP TABLE IF EXISTS outlets;
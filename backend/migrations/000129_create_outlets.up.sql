@@ -0,0 +1,41 @@
+-- Migration: 000129_create_outlets.up.sql
+-- Purpose: Let a tenant run several outlets (branches) and scope staff, products and orders to one of them
+
+CREATE TABLE IF NOT EXISTS outlets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    address TEXT,
+    latitude NUMERIC(9, 6) CHECK (latitude BETWEEN -90 AND 90),
+    longitude NUMERIC(9, 6) CHECK (longitude BETWEEN -180 AND 180),
+    phone VARCHAR(30),
+    email VARCHAR(255),
+    business_hours JSONB NOT NULL DEFAULT '{}'::jsonb,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outlets_tenant_name ON outlets (tenant_id, LOWER(name));
+
+-- NULL outlet_id means the row is not tied to a branch: staff working at every outlet,
+-- products sold everywhere, and orders placed before the tenant added outlets
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES outlets(id) ON DELETE RESTRICT;
+
+ALTER TABLE products
+ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES outlets(id) ON DELETE RESTRICT;
+
+ALTER TABLE guest_orders
+ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES outlets(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_users_outlet ON users (outlet_id) WHERE outlet_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_products_outlet ON products (outlet_id) WHERE outlet_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_guest_orders_outlet ON guest_orders (tenant_id, outlet_id, created_at) WHERE outlet_id IS NOT NULL;
+
+COMMENT ON TABLE outlets IS 'Branches of a tenant; an outlet in use by staff, products or orders can only be deactivated, not deleted';
+COMMENT ON COLUMN outlets.business_hours IS 'Opening hours per weekday, e.g. {"monday": {"open": "08:00", "close": "21:00"}}; missing days are closed';
+COMMENT ON COLUMN users.outlet_id IS 'Outlet the user works at, carried in their JWT; NULL for users working at every outlet';
+COMMENT ON COLUMN products.outlet_id IS 'Outlet the product is sold at; NULL for products sold at every outlet';
+COMMENT ON COLUMN guest_orders.outlet_id IS 'Outlet the order was placed at; NULL when the tenant has a single location';
@@ -0,0 +1,6 @@
+-- Migration: 000130_create_subscription_billing.down.sql
+-- Purpose: Rollback subscription billing
+
+DROP TABLE IF EXISTS subscription_invoices;
+DROP TABLE IF EXISTS tenant_subscriptions;
+DROP TABLE IF EXISTS subscription_plans;
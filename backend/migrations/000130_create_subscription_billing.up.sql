@@ -0,0 +1,65 @@
+-- Migration: 000130_create_subscription_billing.up.sql
+-- Purpose: Subscription plans with their limits, each tenant's subscription, and the monthly invoices
+-- tenants pay to the platform through Midtrans
+
+CREATE TABLE IF NOT EXISTS subscription_plans (
+    code VARCHAR(20) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    monthly_price INTEGER NOT NULL CHECK (monthly_price >= 0), -- IDR
+    -- NULL limits are unlimited
+    max_products INTEGER CHECK (max_products >= 0),
+    max_staff INTEGER CHECK (max_staff >= 0),
+    max_outlets INTEGER CHECK (max_outlets >= 0),
+    max_monthly_orders INTEGER CHECK (max_monthly_orders >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO subscription_plans (code, name, monthly_price, max_products, max_staff, max_outlets, max_monthly_orders)
+VALUES
+    ('free', 'Free', 0, 50, 3, 1, 500),
+    ('pro', 'Pro', 299000, NULL, NULL, 10, NULL)
+ON CONFLICT (code) DO NOTHING;
+
+-- Tenants without a row are on the free plan; the row is created on first use
+CREATE TABLE IF NOT EXISTS tenant_subscriptions (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    plan_code VARCHAR(20) NOT NULL REFERENCES subscription_plans(code),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'past_due')),
+    current_period_start TIMESTAMPTZ NOT NULL,
+    current_period_end TIMESTAMPTZ NOT NULL,
+    grace_until TIMESTAMPTZ, -- Set while past_due; the tenant is moved to the free plan after it
+    scheduled_plan_code VARCHAR(20) REFERENCES subscription_plans(code), -- Lower plan taking over when the period ends
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (current_period_end > current_period_start)
+);
+
+CREATE TABLE IF NOT EXISTS subscription_invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('upgrade', 'renewal')),
+    plan_code VARCHAR(20) NOT NULL REFERENCES subscription_plans(code),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'paid', 'void')),
+    due_at TIMESTAMPTZ NOT NULL,
+    paid_at TIMESTAMPTZ,
+    payment_attempts INTEGER NOT NULL DEFAULT 0,
+    midtrans_order_id VARCHAR(50), -- Charge of the latest payment attempt
+    payment_type VARCHAR(50),
+    redirect_url TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One renewal invoice per billing period
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_invoices_renewal
+    ON subscription_invoices (tenant_id, period_start)
+    WHERE kind = 'renewal' AND status != 'void';
+CREATE INDEX IF NOT EXISTS idx_subscription_invoices_tenant ON subscription_invoices (tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_subscription_invoices_open ON subscription_invoices (due_at) WHERE status = 'open';
+
+COMMENT ON TABLE subscription_invoices IS 'Monthly subscription invoices paid by tenants to the platform Midtrans account';
+COMMENT ON COLUMN subscription_invoices.kind IS 'upgrade: first payment for a higher plan; renewal: next period of the current plan';
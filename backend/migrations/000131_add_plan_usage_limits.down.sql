@@ -0,0 +1,9 @@
+-- Migration: 000131_add_plan_usage_limits.down.sql
+-- Purpose: Rollback plan usage limits
+
+DROP INDEX IF EXISTS idx_notifications_tenant_sent;
+DROP INDEX IF EXISTS idx_guest_orders_tenant_created;
+
+ALTER TABLE subscription_plans
+DROP COLUMN IF EXISTS max_monthly_notifications,
+DROP COLUMN IF EXISTS max_storage_bytes;
@@ -0,0 +1,15 @@
+-- Migration: 000131_add_plan_usage_limits.up.sql
+-- Purpose: Limit photo storage and notifications per plan, alongside the existing order, product, staff and outlet limits
+
+ALTER TABLE subscription_plans
+ADD COLUMN IF NOT EXISTS max_storage_bytes BIGINT CHECK (max_storage_bytes >= 0),
+ADD COLUMN IF NOT EXISTS max_monthly_notifications INTEGER CHECK (max_monthly_notifications >= 0);
+
+COMMENT ON COLUMN subscription_plans.max_storage_bytes IS 'Photo storage limit; NULL falls back to tenants.storage_quota_bytes';
+COMMENT ON COLUMN subscription_plans.max_monthly_notifications IS 'Order, receipt and reminder notifications per billing period; account security messages are never held back';
+
+UPDATE subscription_plans SET max_storage_bytes = 1073741824, max_monthly_notifications = 1000 WHERE code = 'free';
+
+-- Monthly usage is counted from the start of the billing period
+CREATE INDEX IF NOT EXISTS idx_guest_orders_tenant_created ON guest_orders (tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_tenant_sent ON notifications (tenant_id, sent_at) WHERE status = 'sent';
@@ -0,0 +1,7 @@
+-- Migration: 000132_add_store_hours_policy.down.sql
+-- Purpose: Rollback store holidays, ordering cutoff and closed order policy
+
+ALTER TABLE order_settings
+DROP COLUMN IF EXISTS closed_order_policy,
+DROP COLUMN IF EXISTS ordering_cutoff_minutes,
+DROP COLUMN IF EXISTS holidays;
@@ -0,0 +1,13 @@
+-- Migration: 000132_add_store_hours_policy.up.sql
+-- Purpose: Holidays with special hours, a last-order cutoff and what checkout does with orders placed while the store is closed
+
+ALTER TABLE order_settings
+ADD COLUMN IF NOT EXISTS holidays JSONB NOT NULL DEFAULT '[]'::jsonb,
+ADD COLUMN IF NOT EXISTS ordering_cutoff_minutes INTEGER NOT NULL DEFAULT 0
+    CHECK (ordering_cutoff_minutes BETWEEN 0 AND 240),
+ADD COLUMN IF NOT EXISTS closed_order_policy VARCHAR(20) NOT NULL DEFAULT 'accept'
+    CHECK (closed_order_policy IN ('accept', 'reject', 'schedule'));
+
+COMMENT ON COLUMN order_settings.holidays IS 'Dates that override business_hours, e.g. [{"date": "2026-12-25", "name": "Christmas"}]; a date without open/close is closed all day';
+COMMENT ON COLUMN order_settings.ordering_cutoff_minutes IS 'Immediate orders stop this many minutes before closing time';
+COMMENT ON COLUMN order_settings.closed_order_policy IS 'Checkout outside opening hours: accept (as before), reject, or schedule into the next open slot';
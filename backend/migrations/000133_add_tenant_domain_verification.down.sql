@@ -0,0 +1,6 @@
+-- Migration: 000133_add_tenant_domain_verification.down.sql
+-- Purpose: Rollback custom domain verification
+
+ALTER TABLE tenant_domains
+DROP COLUMN IF EXISTS verified_at,
+DROP COLUMN IF EXISTS verification_token;
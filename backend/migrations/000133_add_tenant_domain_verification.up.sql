@@ -0,0 +1,17 @@
+-- Migration: 000133_add_tenant_domain_verification.up.sql
+-- Purpose: Verify custom storefront domains through a DNS TXT record before they resolve to a tenant
+
+ALTER TABLE tenant_domains
+ADD COLUMN IF NOT EXISTS verification_token VARCHAR(64),
+ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
+
+-- Domains registered before verification existed get a token and must be verified like new ones
+UPDATE tenant_domains
+SET verification_token = REPLACE(gen_random_uuid()::text, '-', '')
+WHERE verification_token IS NULL;
+
+ALTER TABLE tenant_domains
+ALTER COLUMN verification_token SET NOT NULL;
+
+COMMENT ON COLUMN tenant_domains.verification_token IS 'Value the tenant publishes in the _pos-verification.<domain> TXT record';
+COMMENT ON COLUMN tenant_domains.verified_at IS 'When the TXT record was found; unverified domains neither resolve to the tenant nor pass CORS';
@@ -0,0 +1,9 @@
+-- Migration: 000134_extend_tenant_branding.down.sql
+-- Purpose: Rollback uploaded logos, secondary color, receipt text and social links
+
+ALTER TABLE tenant_branding
+DROP COLUMN IF EXISTS social_links,
+DROP COLUMN IF EXISTS receipt_footer,
+DROP COLUMN IF EXISTS receipt_header,
+DROP COLUMN IF EXISTS secondary_color,
+DROP COLUMN IF EXISTS logo_storage_key;
@@ -0,0 +1,15 @@
+-- Migration: 000134_extend_tenant_branding.up.sql
+-- Purpose: Uploaded logos, a secondary brand color, receipt header/footer text and social links in tenant branding
+
+ALTER TABLE tenant_branding
+ADD COLUMN IF NOT EXISTS logo_storage_key VARCHAR(512),
+ADD COLUMN IF NOT EXISTS secondary_color VARCHAR(7) CHECK (secondary_color ~ '^#[0-9A-Fa-f]{6}$'),
+ADD COLUMN IF NOT EXISTS receipt_header VARCHAR(500),
+ADD COLUMN IF NOT EXISTS receipt_footer VARCHAR(500),
+ADD COLUMN IF NOT EXISTS social_links JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN tenant_branding.logo_storage_key IS 'Object storage key of an uploaded logo; takes the place of logo_url when set';
+COMMENT ON COLUMN tenant_branding.secondary_color IS 'Second storefront brand color, as #RRGGBB';
+COMMENT ON COLUMN tenant_branding.receipt_header IS 'Text printed under the store name on customer receipts';
+COMMENT ON COLUMN tenant_branding.receipt_footer IS 'Text printed at the end of customer receipts instead of the default thank-you line';
+COMMENT ON COLUMN tenant_branding.social_links IS 'Social profile URLs by platform, e.g. {"instagram": "https://instagram.com/warungmakan"}';
@@ -0,0 +1,20 @@
+-- Migration: 000135_add_tenant_lifecycle.down.sql
+-- Purpose: Rollback tenant suspension and pending deletion
+
+ALTER TABLE tenants
+DROP COLUMN IF EXISTS status_changed_by,
+DROP COLUMN IF EXISTS status_changed_at,
+DROP COLUMN IF EXISTS status_reason;
+
+UPDATE tenants SET status = 'suspended' WHERE status = 'pending_deletion';
+
+ALTER TABLE tenants
+DROP CONSTRAINT IF EXISTS tenants_status_check,
+ADD CONSTRAINT tenants_status_check CHECK (
+    status IN (
+        'active',
+        'suspended',
+        'deleted',
+        'inactive'
+    )
+);
@@ -0,0 +1,24 @@
+-- Migration: 000135_add_tenant_lifecycle.up.sql
+-- Purpose: Tenant suspension and pending deletion, set by platform operators
+-- Suspended and pending-deletion tenants are refused by the API gateway and the public endpoints.
+
+ALTER TABLE tenants
+DROP CONSTRAINT IF EXISTS tenants_status_check,
+ADD CONSTRAINT tenants_status_check CHECK (
+    status IN (
+        'active',
+        'suspended',
+        'pending_deletion',
+        'deleted',
+        'inactive'
+    )
+);
+
+ALTER TABLE tenants
+ADD COLUMN IF NOT EXISTS status_reason VARCHAR(500),
+ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS status_changed_by UUID REFERENCES platform_operators(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN tenants.status_reason IS 'Why the tenant was suspended or scheduled for deletion, shown to operators';
+COMMENT ON COLUMN tenants.status_changed_at IS 'When a platform operator last changed the tenant status';
+COMMENT ON COLUMN tenants.status_changed_by IS 'Platform operator who last changed the tenant status';
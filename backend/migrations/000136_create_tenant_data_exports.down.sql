@@ -0,0 +1,4 @@
+-- Migration: 000136_create_tenant_data_exports.down.sql
+-- Purpose: Rollback tenant data exports
+
+DROP TABLE IF EXISTS tenant_data_exports;
@@ -0,0 +1,22 @@
+-- Migration: 000136_create_tenant_data_exports.up.sql
+-- Purpose: Full tenant data exports (data portability for backups and tenants leaving the platform), assembled in the background into an archive in object storage
+
+CREATE TABLE IF NOT EXISTS tenant_data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    storage_key TEXT,
+    size_bytes BIGINT,
+    error_msg TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_data_exports_tenant ON tenant_data_exports(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tenant_data_exports_expires ON tenant_data_exports(expires_at) WHERE expires_at IS NOT NULL;
+
+COMMENT ON TABLE tenant_data_exports IS 'Archives of all of a tenant''s data requested by its owner; the archive is deleted once the download link expires';
+COMMENT ON COLUMN tenant_data_exports.storage_key IS 'Object storage key of the ZIP archive, set when the export is ready';
+COMMENT ON COLUMN tenant_data_exports.expires_at IS 'End of the download link lifetime, set when the archive is ready';
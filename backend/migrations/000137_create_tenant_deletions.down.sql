@@ -0,0 +1,5 @@
+-- Migration: 000137_create_tenant_deletions.down.sql
+-- Purpose: Rollback tenant deletion saga
+
+DROP TABLE IF EXISTS tenant_deletion_steps;
+DROP TABLE IF EXISTS tenant_deletions;
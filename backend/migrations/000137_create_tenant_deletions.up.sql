@@ -0,0 +1,39 @@
+-- Migration: 000137_create_tenant_deletions.up.sql
+-- Purpose: Tenant deletion saga - tenant-service asks each service to delete the tenant's data over
+-- tenant-events and records their acknowledgements; the tenant row goes last
+-- The records outlive the tenant, so they do not reference it.
+
+CREATE TABLE IF NOT EXISTS tenant_deletions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    tenant_slug VARCHAR(255) NOT NULL,
+    requested_by UUID REFERENCES platform_operators(id) ON DELETE SET NULL,
+    reason VARCHAR(500),
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'completed')),
+    stage INTEGER NOT NULL DEFAULT 1,
+    error_msg TEXT,
+    certificate_id UUID,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+-- One deletion at a time per tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_deletions_in_progress ON tenant_deletions(tenant_id) WHERE status = 'in_progress';
+CREATE INDEX IF NOT EXISTS idx_tenant_deletions_tenant ON tenant_deletions(tenant_id, requested_at DESC);
+
+CREATE TABLE IF NOT EXISTS tenant_deletion_steps (
+    deletion_id UUID NOT NULL REFERENCES tenant_deletions(id) ON DELETE CASCADE,
+    service VARCHAR(50) NOT NULL,
+    stage INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed', 'failed')),
+    deleted JSONB NOT NULL DEFAULT '{}',
+    error_msg TEXT,
+    acknowledged_at TIMESTAMPTZ,
+    PRIMARY KEY (deletion_id, service)
+);
+
+COMMENT ON TABLE tenant_deletions IS 'Cross-service tenant deletions; a completed one is the deletion certificate';
+COMMENT ON COLUMN tenant_deletions.stage IS 'Stage whose services were last asked to delete; later stages wait for earlier ones';
+COMMENT ON COLUMN tenant_deletions.certificate_id IS 'ID of the deletion certificate, also sent in the certificate audit event';
+COMMENT ON TABLE tenant_deletion_steps IS 'One service''s part of a tenant deletion and its acknowledgement';
+COMMENT ON COLUMN tenant_deletion_steps.deleted IS 'What the service reported deleting, e.g. {"orders": 120}';
@@ -0,0 +1,6 @@
+-- Migration: 000138_create_order_reporting_views.down.sql
+-- Purpose: Rollback order reporting views
+
+DROP INDEX IF EXISTS idx_archived_order_items_product;
+DROP VIEW IF EXISTS reporting_order_items;
+DROP VIEW IF EXISTS reporting_guest_orders;
@@ -0,0 +1,25 @@
+-- Migration: 000138_create_order_reporting_views.up.sql
+-- Purpose: Reporting views over live and archived orders, so analytics keeps counting orders moved to cold storage
+
+-- An order is either live or archived, never both: the archive job moves it in one transaction
+CREATE OR REPLACE VIEW reporting_guest_orders AS
+SELECT id, tenant_id, order_reference, status, order_type, delivery_type,
+    subtotal_amount, delivery_fee, total_amount, created_at, paid_at, completed_at, cancelled_at
+FROM guest_orders
+UNION ALL
+SELECT id, tenant_id, order_reference, status, order_type, delivery_type,
+    subtotal_amount, delivery_fee, total_amount, created_at, paid_at, completed_at, cancelled_at
+FROM archived_guest_orders;
+
+CREATE OR REPLACE VIEW reporting_order_items AS
+SELECT id, order_id, product_id, product_name, quantity, unit_price, total_price
+FROM order_items
+UNION ALL
+SELECT id, order_id, product_id, product_name, quantity, unit_price, total_price
+FROM archived_order_items;
+
+-- Product and category reports join items by product
+CREATE INDEX IF NOT EXISTS idx_archived_order_items_product ON archived_order_items (product_id);
+
+COMMENT ON VIEW reporting_guest_orders IS 'Live and archived orders for analytics; archived orders carry no customer data';
+COMMENT ON VIEW reporting_order_items IS 'Items of live and archived orders for analytics';
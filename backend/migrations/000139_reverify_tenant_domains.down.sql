@@ -0,0 +1,15 @@
+-- Migration: 000139_reverify_tenant_domains.down.sql
+-- Purpose: Rollback partial domain uniqueness (re-verification cannot be undone)
+
+-- The full unique index allows one claim per domain; keep the verified one, else the oldest
+DELETE FROM tenant_domains d
+WHERE d.verified_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM tenant_domains o
+    WHERE o.domain = d.domain AND o.id <> d.id
+      AND (o.verified_at IS NOT NULL OR o.created_at < d.created_at)
+  );
+
+DROP INDEX IF EXISTS idx_tenant_domains_tenant_domain;
+DROP INDEX IF EXISTS idx_tenant_domains_domain;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_domains_domain ON tenant_domains (domain);
@@ -0,0 +1,15 @@
+-- Migration: 000139_reverify_tenant_domains.up.sql
+-- Purpose: Only verified custom domains are unique, and domains never checked through DNS must be verified again
+
+-- 000133 first marked every existing domain verified as of its creation; none of those had their TXT record checked
+UPDATE tenant_domains
+SET verified_at = NULL,
+    updated_at = NOW()
+WHERE verified_at = created_at;
+
+-- Unverified claims must not block the domain's owner: only one tenant can hold a verified domain,
+-- and verifying a domain removes other tenants' unverified claims on it
+DROP INDEX IF EXISTS idx_tenant_domains_domain;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_domains_domain ON tenant_domains (domain) WHERE verified_at IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_domains_tenant_domain ON tenant_domains (tenant_id, domain);
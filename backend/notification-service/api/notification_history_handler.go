@@ -83,6 +83,11 @@ func (h *NotificationHistoryHandler) GetNotificationHistory(c echo.Context) erro
 		filters["order_reference"] = orderRef
 	}
 
+	// Correlation ID filter (request ID of the originating HTTP request)
+	if correlationID := c.QueryParam("correlation_id"); correlationID != "" {
+		filters["correlation_id"] = correlationID
+	}
+
 	// Status filter
 	if status := c.QueryParam("status"); status != "" {
 		validStatuses := map[string]bool{
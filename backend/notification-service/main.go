@@ -13,6 +13,7 @@ import (
 	emw "github.com/labstack/echo/v4/middleware"
 	_ "github.com/lib/pq"
 	"github.com/pos/notification-service/api"
+	"github.com/pos/notification-service/src/config"
 	"github.com/pos/notification-service/middleware"
 	"github.com/pos/notification-service/src/observability"
 	"github.com/pos/notification-service/src/queue"
@@ -58,8 +59,15 @@ func main() {
 	e.GET("/health", api.HealthCheck)
 	e.GET("/ready", api.ReadyCheck)
 
+	// Redis coordinates send attempts across replicas
+	redisClient, err := config.NewRedisClient()
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
+
 	// Notification service
-	notificationService, err := services.NewNotificationService(db)
+	notificationService, err := services.NewNotificationService(db, services.NewRedisSendLock(redisClient))
 	if err != nil {
 		log.Fatalf("Failed to create notification service: %v", err)
 	}
@@ -106,6 +114,17 @@ func main() {
 	// Start consumer in background
 	go consumer.Start(ctx)
 
+	// Tenant deletion saga: delete the tenant's notifications when tenant-service asks
+	tenantEvents := queue.NewKafkaProducer(kafkaBrokers, utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC"))
+	defer tenantEvents.Close()
+	tenantEventsConsumer := queue.NewKafkaConsumer(
+		kafkaBrokers,
+		utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC"),
+		kafkaGroupID,
+		services.NewTenantEventHandler(notificationService, tenantEvents).Handle,
+	)
+	go tenantEventsConsumer.Start(ctx)
+
 	// Start retry worker in background
 	retryWorker, err := services.NewRetryWorker(db, notificationService)
 	if err != nil {
@@ -122,6 +141,7 @@ func main() {
 		log.Println("Shutting down notification service...")
 		cancel()
 		consumer.Close()
+		tenantEventsConsumer.Close()
 		e.Close()
 	}()
 
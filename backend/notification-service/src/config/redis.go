@@ -0,0 +1,26 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/pos/notification-service/src/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisClient connects to the Redis instance shared by all notification-service replicas
+func NewRedisClient() (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     utils.GetEnv("REDIS_HOST"),
+		Password: utils.GetEnv("REDIS_PASSWORD"),
+		DB:       utils.GetEnvInt("REDIS_DB"),
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	log.Println("Redis connection established")
+	return client, nil
+}
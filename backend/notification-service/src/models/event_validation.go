@@ -97,6 +97,26 @@ func ValidateOrderPaidEventMetadata(metadata *OrderPaidEventMetadata) error {
 		return fmt.Errorf("delivery_fee must be >= 0")
 	}
 
+	if metadata.DiscountAmount < 0 {
+		return fmt.Errorf("discount_amount must be >= 0")
+	}
+
+	if metadata.PromotionDiscountAmount < 0 {
+		return fmt.Errorf("promotion_discount_amount must be >= 0")
+	}
+
+	if metadata.LoyaltyDiscountAmount < 0 {
+		return fmt.Errorf("loyalty_discount_amount must be >= 0")
+	}
+
+	if metadata.ServiceChargeAmount < 0 {
+		return fmt.Errorf("service_charge_amount must be >= 0")
+	}
+
+	if metadata.TaxAmount < 0 {
+		return fmt.Errorf("tax_amount must be >= 0")
+	}
+
 	if metadata.TotalAmount < 0 {
 		return fmt.Errorf("total_amount must be >= 0")
 	}
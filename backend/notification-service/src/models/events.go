@@ -13,22 +13,32 @@ type OrderPaidEvent struct {
 
 // OrderPaidEventMetadata contains the order details for the event
 type OrderPaidEventMetadata struct {
-	OrderID         string      `json:"order_id" validate:"required"`
-	OrderReference  string      `json:"order_reference" validate:"required"`
-	TransactionID   string      `json:"transaction_id" validate:"required"`
-	CustomerName    string      `json:"customer_name" validate:"required"`
-	CustomerPhone   string      `json:"customer_phone" validate:"required"`
-	CustomerEmail   string      `json:"customer_email,omitempty"`
-	DeliveryType    string      `json:"delivery_type" validate:"required"` // "delivery", "pickup", "dine_in"
-	DeliveryAddress string      `json:"delivery_address,omitempty"`
-	TableNumber     string      `json:"table_number,omitempty"`
-	Items           []OrderItem `json:"items" validate:"required,min=1"`
-	SubtotalAmount  int         `json:"subtotal_amount" validate:"required,min=0"`
-	DeliveryFee     int         `json:"delivery_fee" validate:"min=0"`
-	TotalAmount     int         `json:"total_amount" validate:"required,min=0"`
-	PaymentMethod   string      `json:"payment_method" validate:"required"`
-	PaidAt          time.Time   `json:"paid_at" validate:"required"`
-	CreatedAt       time.Time   `json:"created_at" validate:"required"`
+	OrderID                 string      `json:"order_id" validate:"required"`
+	OrderReference          string      `json:"order_reference" validate:"required"`
+	TransactionID           string      `json:"transaction_id" validate:"required"`
+	CustomerName            string      `json:"customer_name" validate:"required"`
+	CustomerPhone           string      `json:"customer_phone" validate:"required"`
+	CustomerEmail           string      `json:"customer_email,omitempty"`
+	DeliveryType            string      `json:"delivery_type" validate:"required"` // "delivery", "pickup", "dine_in"
+	DeliveryAddress         string      `json:"delivery_address,omitempty"`
+	TableNumber             string      `json:"table_number,omitempty"`
+	ScheduledFor            *time.Time  `json:"scheduled_for,omitempty"` // Booked pickup/delivery slot; nil for ASAP orders
+	Items                   []OrderItem `json:"items" validate:"required,min=1"`
+	SubtotalAmount          int         `json:"subtotal_amount" validate:"required,min=0"`
+	DeliveryFee             int         `json:"delivery_fee" validate:"min=0"`
+	DiscountAmount          int         `json:"discount_amount" validate:"min=0"`
+	VoucherCode             string      `json:"voucher_code,omitempty"`
+	PromotionDiscountAmount int         `json:"promotion_discount_amount" validate:"min=0"`
+	LoyaltyPointsRedeemed   int         `json:"loyalty_points_redeemed" validate:"min=0"`
+	LoyaltyDiscountAmount   int         `json:"loyalty_discount_amount" validate:"min=0"`
+	ServiceChargeRate       float64     `json:"service_charge_rate" validate:"min=0"`
+	ServiceChargeAmount     int         `json:"service_charge_amount" validate:"min=0"`
+	TaxRate                 float64     `json:"tax_rate" validate:"min=0"`
+	TaxAmount               int         `json:"tax_amount" validate:"min=0"`
+	TotalAmount             int         `json:"total_amount" validate:"required,min=0"`
+	PaymentMethod           string      `json:"payment_method" validate:"required"`
+	PaidAt                  time.Time   `json:"paid_at" validate:"required"`
+	CreatedAt               time.Time   `json:"created_at" validate:"required"`
 }
 
 // OrderItem represents an item in an order
@@ -21,6 +21,7 @@ const (
 	NotificationStatusSent      NotificationStatus = "sent"
 	NotificationStatusFailed    NotificationStatus = "failed"
 	NotificationStatusRetrying  NotificationStatus = "retrying"
+	NotificationStatusCancelled NotificationStatus = "cancelled" // Held back, e.g. over the plan's monthly limit; never retried
 )
 
 // Notification represents a notification record
@@ -38,6 +39,7 @@ type Notification struct {
 	FailedAt    *time.Time         `json:"failed_at,omitempty" db:"failed_at"`
 	ErrorMsg    *string            `json:"error_msg,omitempty" db:"error_msg"`
 	RetryCount  int                `json:"retry_count" db:"retry_count"`
+	CorrelationID *string          `json:"correlation_id,omitempty" db:"correlation_id"`
 	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
 }
@@ -62,6 +64,8 @@ type NotificationEvent struct {
 	UserID    string                 `json:"user_id,omitempty"`
 	Data      map[string]interface{} `json:"data"`
 	Timestamp time.Time              `json:"timestamp"`
+	// CorrelationID is the request ID of the HTTP request that triggered the event
+	CorrelationID string `json:"correlation_id,omitempty"`
 }
 
 // NotificationResponse represents the API response
@@ -2,22 +2,33 @@ package models
 
 // StaffNotificationData contains the data for staff order notification emails
 type StaffNotificationData struct {
-	OrderID         string                  `json:"order_id"`
-	OrderReference  string                  `json:"order_reference"`
-	TransactionID   string                  `json:"transaction_id"`
-	CustomerName    string                  `json:"customer_name"`
-	CustomerEmail   string                  `json:"customer_email,omitempty"`
-	CustomerPhone   string                  `json:"customer_phone"`
-	DeliveryType    string                  `json:"delivery_type"`
-	DeliveryAddress string                  `json:"delivery_address,omitempty"`
-	TableNumber     string                  `json:"table_number,omitempty"`
-	Items           []StaffNotificationItem `json:"items"`
-	SubtotalAmount  string                  `json:"subtotal_amount"`
-	DeliveryFee     string                  `json:"delivery_fee,omitempty"`
-	TotalAmount     string                  `json:"total_amount"`
-	PaymentMethod   string                  `json:"payment_method"`
-	PaidAt          string                  `json:"paid_at"`
-	CreatedAt       string                  `json:"created_at"` // Order creation time
+	OrderID           string                  `json:"order_id"`
+	OrderReference    string                  `json:"order_reference"`
+	TransactionID     string                  `json:"transaction_id"`
+	CustomerName      string                  `json:"customer_name"`
+	CustomerEmail     string                  `json:"customer_email,omitempty"`
+	CustomerPhone     string                  `json:"customer_phone"`
+	DeliveryType      string                  `json:"delivery_type"`
+	DeliveryAddress   string                  `json:"delivery_address,omitempty"`
+	TableNumber       string                  `json:"table_number,omitempty"`
+	ScheduledFor      string                  `json:"scheduled_for,omitempty"`
+	Items             []StaffNotificationItem `json:"items"`
+	SubtotalAmount    string                  `json:"subtotal_amount"`
+	DeliveryFee       string                  `json:"delivery_fee,omitempty"`
+	DiscountAmount    string                  `json:"discount_amount,omitempty"`
+	VoucherCode       string                  `json:"voucher_code,omitempty"`
+	PromotionDiscount string                  `json:"promotion_discount,omitempty"`
+	PromotionNames    string                  `json:"promotion_names,omitempty"`
+	LoyaltyDiscount   string                  `json:"loyalty_discount,omitempty"`
+	LoyaltyPoints     int                     `json:"loyalty_points,omitempty"`
+	ServiceCharge     string                  `json:"service_charge,omitempty"`
+	ServiceChargeRate string                  `json:"service_charge_rate,omitempty"`
+	Tax               string                  `json:"tax,omitempty"`
+	TaxRate           string                  `json:"tax_rate,omitempty"`
+	TotalAmount       string                  `json:"total_amount"`
+	PaymentMethod     string                  `json:"payment_method"`
+	PaidAt            string                  `json:"paid_at"`
+	CreatedAt         string                  `json:"created_at"` // Order creation time
 }
 
 // StaffNotificationItem represents an order item in staff notification
@@ -36,9 +47,20 @@ type CustomerReceiptData struct {
 	DeliveryType      string                `json:"delivery_type"`
 	DeliveryAddress   string                `json:"delivery_address,omitempty"`
 	TableNumber       string                `json:"table_number,omitempty"`
+	ScheduledFor      string                `json:"scheduled_for,omitempty"`
 	Items             []CustomerReceiptItem `json:"items"`
 	SubtotalAmount    string                `json:"subtotal_amount"`
 	DeliveryFee       string                `json:"delivery_fee,omitempty"`
+	DiscountAmount    string                `json:"discount_amount,omitempty"`
+	VoucherCode       string                `json:"voucher_code,omitempty"`
+	PromotionDiscount string                `json:"promotion_discount,omitempty"`
+	PromotionNames    string                `json:"promotion_names,omitempty"`
+	LoyaltyDiscount   string                `json:"loyalty_discount,omitempty"`
+	LoyaltyPoints     int                   `json:"loyalty_points,omitempty"`
+	ServiceCharge     string                `json:"service_charge,omitempty"`
+	ServiceChargeRate string                `json:"service_charge_rate,omitempty"`
+	Tax               string                `json:"tax,omitempty"`
+	TaxRate           string                `json:"tax_rate,omitempty"`
 	TotalAmount       string                `json:"total_amount"`
 	PaymentMethod     string                `json:"payment_method"`
 	PaidAt            string                `json:"paid_at"`
@@ -1,8 +1,14 @@
 package providers
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/smtp"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -49,8 +55,15 @@ func (e *EmailError) IsRetryable() bool {
 	}
 }
 
+// Attachment is a file sent along with an email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
 type EmailProvider interface {
-	Send(to, subject, body string, isHTML bool) error
+	Send(to, subject, body string, isHTML bool, attachments ...Attachment) error
 }
 
 type SMTPEmailProvider struct {
@@ -84,7 +97,7 @@ func NewSMTPEmailProvider() *SMTPEmailProvider {
 	}
 }
 
-func (p *SMTPEmailProvider) Send(to, subject, body string, isHTML bool) error {
+func (p *SMTPEmailProvider) Send(to, subject, body string, isHTML bool, attachments ...Attachment) error {
 	e := email.NewEmail()
 	e.From = p.from
 	e.To = []string{to}
@@ -96,9 +109,22 @@ func (p *SMTPEmailProvider) Send(to, subject, body string, isHTML bool) error {
 		e.Text = []byte(body)
 	}
 
+	for _, attachment := range attachments {
+		if _, err := e.Attach(bytes.NewReader(attachment.Content), attachment.Filename, attachment.ContentType); err != nil {
+			return &EmailError{
+				Type:    EmailErrorTypeUnknown,
+				Message: fmt.Sprintf("failed to attach %s", attachment.Filename),
+				Err:     err,
+			}
+		}
+	}
+
 	// If email sending is disabled, just log the email
 	if !p.enable {
 		fmt.Printf("[EMAIL] To: %s, Subject: %s\n%s\n", to, subject, body)
+		for _, attachment := range attachments {
+			fmt.Printf("[EMAIL] Attachment: %s (%s, %d bytes)\n", attachment.Filename, attachment.ContentType, len(attachment.Content))
+		}
 		return nil
 	}
 
@@ -200,3 +226,137 @@ func (p *MockPushProvider) Send(token, title, body string, data map[string]strin
 	fmt.Printf("[PUSH] Token: %s, Title: %s, Body: %s, Data: %v\n", token, title, body, data)
 	return nil
 }
+
+// TextMessageProvider sends short text messages by SMS or WhatsApp
+type TextMessageProvider interface {
+	Send(channel, to, body string) error
+}
+
+// NewTextMessageProvider returns the SMS and WhatsApp provider chosen by SMS_PROVIDER
+// "twilio" sends through Twilio; "mock" only logs that a message went out and is meant for local
+// development. Returns nil when SMS_PROVIDER is not set, so text messages fail rather than go nowhere.
+func NewTextMessageProvider() (TextMessageProvider, error) {
+	switch provider := os.Getenv("SMS_PROVIDER"); provider {
+	case "":
+		return nil, nil
+	case "twilio":
+		return NewTwilioTextMessageProvider(
+			os.Getenv("SMS_API_KEY"),
+			os.Getenv("SMS_API_SECRET"),
+			os.Getenv("SMS_FROM_NUMBER"),
+			os.Getenv("WHATSAPP_FROM_NUMBER"),
+		)
+	case "mock":
+		return NewMockTextMessageProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS_PROVIDER %q", provider)
+	}
+}
+
+const twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// TwilioTextMessageProvider sends SMS and WhatsApp messages through Twilio's Messages API
+type TwilioTextMessageProvider struct {
+	accountSID   string
+	authToken    string
+	smsFrom      string
+	whatsAppFrom string
+	apiURL       string
+	client       *http.Client
+}
+
+// NewTwilioTextMessageProvider creates a Twilio provider
+// WhatsApp messages are sent from whatsAppFrom, or from the SMS number when it is empty.
+func NewTwilioTextMessageProvider(accountSID, authToken, smsFrom, whatsAppFrom string) (*TwilioTextMessageProvider, error) {
+	if accountSID == "" || authToken == "" || smsFrom == "" {
+		return nil, fmt.Errorf("SMS_API_KEY, SMS_API_SECRET and SMS_FROM_NUMBER are required for Twilio")
+	}
+	if whatsAppFrom == "" {
+		whatsAppFrom = smsFrom
+	}
+	return &TwilioTextMessageProvider{
+		accountSID:   accountSID,
+		authToken:    authToken,
+		smsFrom:      smsFrom,
+		whatsAppFrom: whatsAppFrom,
+		apiURL:       twilioAPIURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *TwilioTextMessageProvider) Send(channel, to, body string) error {
+	to = ToE164(to)
+	from := p.smsFrom
+	if channel == "whatsapp" {
+		to = "whatsapp:" + to
+		from = "whatsapp:" + p.whatsAppFrom
+	}
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", from)
+	form.Set("Body", body)
+
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("%s/Accounts/%s/Messages.json", p.apiURL, url.PathEscape(p.accountSID)),
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build Twilio request: %w", err)
+	}
+	req.SetBasicAuth(p.accountSID, p.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s message: %w", channel, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		// Twilio's error body names the problem without echoing the message
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&apiErr)
+		return fmt.Errorf("twilio returned status %d (code %d): %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+	}
+	return nil
+}
+
+// ToE164 formats an Indonesian phone number as E.164, e.g. 0812-3456-7890 -> +6281234567890
+// Numbers already starting with + keep their country code.
+func ToE164(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	number := digits.String()
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(phone), "+"):
+		return "+" + number
+	case strings.HasPrefix(number, "0"):
+		return "+62" + number[1:]
+	case strings.HasPrefix(number, "62"):
+		return "+" + number
+	default:
+		return "+62" + number
+	}
+}
+
+// MockTextMessageProvider logs text messages instead of sending them, for local development
+// Messages can carry verification codes, so only the channel, the last digits of the
+// recipient and the message length are logged.
+type MockTextMessageProvider struct{}
+
+func NewMockTextMessageProvider() *MockTextMessageProvider {
+	return &MockTextMessageProvider{}
+}
+
+func (p *MockTextMessageProvider) Send(channel, to, body string) error {
+	fmt.Printf("[%s] To: %s, %d characters (not sent, SMS_PROVIDER=mock)\n", strings.ToUpper(channel), utils.Mask(to), len(body))
+	return nil
+}
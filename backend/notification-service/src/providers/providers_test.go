@@ -0,0 +1,122 @@
+package providers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestToE164(t *testing.T) {
+	tests := []struct {
+		name     string
+		phone    string
+		expected string
+	}{
+		{name: "Local number", phone: "081234567890", expected: "+6281234567890"},
+		{name: "Local number with separators", phone: "0812-3456-7890", expected: "+6281234567890"},
+		{name: "Country code without plus", phone: "6281234567890", expected: "+6281234567890"},
+		{name: "Already E.164", phone: "+6281234567890", expected: "+6281234567890"},
+		{name: "Foreign number keeps its country code", phone: "+1 (415) 555-0100", expected: "+14155550100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ToE164(tt.phone); result != tt.expected {
+				t.Errorf("ToE164(%q) = %q, expected %q", tt.phone, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTwilioTextMessageProvider_Send(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		got = r
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	provider, err := NewTwilioTextMessageProvider("AC123", "token", "+15005550006", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	provider.apiURL = server.URL
+
+	t.Run("SMS", func(t *testing.T) {
+		if err := provider.Send("sms", "081234567890", "Kode: 123456"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.URL.Path != "/Accounts/AC123/Messages.json" {
+			t.Errorf("unexpected path %q", got.URL.Path)
+		}
+		if user, pass, ok := got.BasicAuth(); !ok || user != "AC123" || pass != "token" {
+			t.Errorf("unexpected basic auth %q:%q", user, pass)
+		}
+		if to, from := got.PostForm.Get("To"), got.PostForm.Get("From"); to != "+6281234567890" || from != "+15005550006" {
+			t.Errorf("unexpected To %q / From %q", to, from)
+		}
+		if body := got.PostForm.Get("Body"); body != "Kode: 123456" {
+			t.Errorf("unexpected Body %q", body)
+		}
+	})
+
+	t.Run("WhatsApp defaults to the SMS sender", func(t *testing.T) {
+		if err := provider.Send("whatsapp", "081234567890", "Kode: 123456"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if to, from := got.PostForm.Get("To"), got.PostForm.Get("From"); to != "whatsapp:+6281234567890" || from != "whatsapp:+15005550006" {
+			t.Errorf("unexpected To %q / From %q", to, from)
+		}
+	})
+}
+
+func TestTwilioTextMessageProvider_SendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code": 21211, "message": "The 'To' number is not a valid phone number."}`))
+	}))
+	defer server.Close()
+
+	provider, _ := NewTwilioTextMessageProvider("AC123", "token", "+15005550006", "")
+	provider.apiURL = server.URL
+
+	if err := provider.Send("sms", "0812", "Kode: 123456"); err == nil {
+		t.Fatal("expected an error for a rejected message")
+	}
+}
+
+func TestNewTextMessageProvider(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv("SMS_PROVIDER", "")
+		provider, err := NewTextMessageProvider()
+		if err != nil || provider != nil {
+			t.Errorf("expected no provider, got %v, %v", provider, err)
+		}
+	})
+
+	t.Run("Mock only when asked for", func(t *testing.T) {
+		t.Setenv("SMS_PROVIDER", "mock")
+		provider, err := NewTextMessageProvider()
+		if _, ok := provider.(*MockTextMessageProvider); err != nil || !ok {
+			t.Errorf("expected the mock provider, got %T, %v", provider, err)
+		}
+	})
+
+	t.Run("Twilio requires credentials", func(t *testing.T) {
+		t.Setenv("SMS_PROVIDER", "twilio")
+		t.Setenv("SMS_API_KEY", "")
+		if _, err := NewTextMessageProvider(); err == nil {
+			t.Error("expected an error without credentials")
+		}
+	})
+
+	t.Run("Unknown provider", func(t *testing.T) {
+		t.Setenv("SMS_PROVIDER", "carrier-pigeon")
+		if _, err := NewTextMessageProvider(); err == nil {
+			t.Error("expected an error for an unknown provider")
+		}
+	})
+}
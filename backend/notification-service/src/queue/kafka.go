@@ -9,6 +9,9 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
+// KafkaConsumer reads notification events as part of a consumer group
+// Producers key events by transaction, so all events for one payment share a partition
+// and are handled, in order, by whichever replica the group assigned that partition to.
 type KafkaConsumer struct {
 	reader  *kafka.Reader
 	handler func(context.Context, []byte) error
@@ -23,6 +26,8 @@ func NewKafkaConsumer(brokers []string, topic string, groupID string, handler fu
 		MaxBytes:       10e6, // 10MB
 		CommitInterval: time.Second,
 		StartOffset:    kafka.FirstOffset,
+		// Assign whole partitions to replicas so a key is never split across them
+		GroupBalancers: []kafka.GroupBalancer{kafka.RangeGroupBalancer{}},
 	})
 
 	return &KafkaConsumer{
@@ -41,19 +46,25 @@ func (c *KafkaConsumer) Start(ctx context.Context) {
 			c.reader.Close()
 			return
 		default:
-			msg, err := c.reader.ReadMessage(ctx)
+			// Fetch without committing so the offset only advances once the event was handled;
+			// if this replica dies mid-send, the partition's next owner re-reads the event and
+			// the send lock and sent-notification check keep it from being delivered twice
+			msg, err := c.reader.FetchMessage(ctx)
 			if err != nil {
 				log.Printf("Error reading message: %v", err)
 				continue
 			}
 
-			log.Printf("Received message: topic=%s partition=%d offset=%d",
-				msg.Topic, msg.Partition, msg.Offset)
+			log.Printf("Received message: topic=%s partition=%d offset=%d key=%s",
+				msg.Topic, msg.Partition, msg.Offset, string(msg.Key))
 
 			if err := c.handler(ctx, msg.Value); err != nil {
+				// Failed sends are stored and picked up by the retry worker
 				log.Printf("Error handling message: %v", err)
-				// Don't commit on error - will be reprocessed
-				continue
+			}
+
+			if err := c.reader.CommitMessages(ctx, msg); err != nil {
+				log.Printf("Error committing message: partition=%d offset=%d: %v", msg.Partition, msg.Offset, err)
 			}
 		}
 	}
@@ -117,6 +117,13 @@ func (r *NotificationRepository) decryptSensitiveMetadata(ctx context.Context, m
 }
 
 func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	// Link the record to the request that triggered it
+	if notification.CorrelationID == nil {
+		if correlationID := utils.CorrelationIDFromContext(ctx); correlationID != "" {
+			notification.CorrelationID = &correlationID
+		}
+	}
+
 	// Encrypt PII fields with context
 	encryptedRecipient, err := r.encryptor.EncryptWithContext(ctx, notification.Recipient, "notification:recipient")
 	if err != nil {
@@ -135,8 +142,8 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *model
 	}
 
 	query := `
-		INSERT INTO notifications (tenant_id, user_id, type, status, event_type, subject, body, recipient, metadata)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO notifications (tenant_id, user_id, type, status, event_type, subject, body, recipient, metadata, correlation_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id, created_at, updated_at`
 
 	// Extract event_type from metadata if present
@@ -168,6 +175,7 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *model
 		encryptedBody,
 		encryptedRecipient,
 		metadataJSON,
+		notification.CorrelationID,
 	).Scan(&notification.ID, &notification.CreatedAt, &notification.UpdatedAt)
 }
 
@@ -184,7 +192,7 @@ func (r *NotificationRepository) UpdateStatus(ctx context.Context, id string, st
 func (r *NotificationRepository) FindByID(ctx context.Context, id string) (*models.Notification, error) {
 	query := `
 		SELECT id, tenant_id, user_id, type, status, subject, body, recipient, 
-		       metadata, sent_at, failed_at, error_msg, retry_count, created_at, updated_at
+		       metadata, sent_at, failed_at, error_msg, retry_count, correlation_id, created_at, updated_at
 		FROM notifications
 		WHERE id = $1`
 
@@ -204,6 +212,7 @@ func (r *NotificationRepository) FindByID(ctx context.Context, id string) (*mode
 		&notification.FailedAt,
 		&notification.ErrorMsg,
 		&notification.RetryCount,
+		&notification.CorrelationID,
 		&notification.CreatedAt,
 		&notification.UpdatedAt,
 	)
@@ -238,6 +247,15 @@ func (r *NotificationRepository) FindByID(ctx context.Context, id string) (*mode
 	return notification, nil
 }
 
+// DeleteAllByTenant deletes every notification of a tenant being deleted and returns how many were deleted
+func (r *NotificationRepository) DeleteAllByTenant(ctx context.Context, tenantID string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM notifications WHERE tenant_id = $1`, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tenant notifications: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // HasSentOrderNotification checks if a notification has already been sent for a given transaction_id
 // This prevents duplicate notifications for the same order payment
 func (r *NotificationRepository) HasSentOrderNotification(ctx context.Context, tenantID, transactionID string) (bool, error) {
@@ -259,11 +277,39 @@ func (r *NotificationRepository) HasSentOrderNotification(ctx context.Context, t
 	return exists, nil
 }
 
+// NotificationPlanUsage returns the tenant's plan name, its monthly notification limit (nil when
+// unlimited) and the notifications sent since the start of the billing period
+// Tenants without a subscription are on the free plan for the calendar month.
+func (r *NotificationRepository) NotificationPlanUsage(ctx context.Context, tenantID string) (string, *int, int, error) {
+	query := `
+		WITH s AS (
+			SELECT plan_code, current_period_start FROM tenant_subscriptions WHERE tenant_id = $1
+		)
+		SELECT p.name, p.max_monthly_notifications,
+			(SELECT COUNT(*) FROM notifications
+				WHERE tenant_id = $1 AND status = 'sent'
+				  AND sent_at >= COALESCE((SELECT current_period_start FROM s), date_trunc('month', NOW())))
+		FROM subscription_plans p
+		WHERE p.code = COALESCE((SELECT plan_code FROM s), 'free')`
+
+	var planName string
+	var maxNotifications sql.NullInt64
+	var sent int
+	if err := r.db.QueryRowContext(ctx, query, tenantID).Scan(&planName, &maxNotifications, &sent); err != nil {
+		return "", nil, 0, err
+	}
+	if !maxNotifications.Valid {
+		return planName, nil, sent, nil
+	}
+	max := int(maxNotifications.Int64)
+	return planName, &max, sent, nil
+}
+
 // GetByID retrieves a notification by ID
 func (r *NotificationRepository) GetByID(id string) (*models.Notification, error) {
 	query := `
 		SELECT id, tenant_id, user_id, type, status, event_type, subject, body, recipient,
-		       metadata, sent_at, failed_at, error_msg, retry_count, created_at, updated_at
+		       metadata, sent_at, failed_at, error_msg, retry_count, correlation_id, created_at, updated_at
 		FROM notifications
 		WHERE id = $1`
 
@@ -287,6 +333,7 @@ func (r *NotificationRepository) GetByID(id string) (*models.Notification, error
 		&notification.FailedAt,
 		&notification.ErrorMsg,
 		&notification.RetryCount,
+		&notification.CorrelationID,
 		&notification.CreatedAt,
 		&notification.UpdatedAt,
 	)
@@ -344,7 +391,7 @@ func (r *NotificationRepository) GetNotificationHistory(filters map[string]inter
 	query := `
 		SELECT id, event_type, type, recipient, subject, status,
 		       sent_at, failed_at, error_msg, retry_count, created_at,
-		       metadata->>'order_reference' as order_reference
+		       metadata->>'order_reference' as order_reference, correlation_id
 		FROM notifications
 		WHERE tenant_id = $1`
 
@@ -358,6 +405,12 @@ func (r *NotificationRepository) GetNotificationHistory(filters map[string]inter
 		args = append(args, orderRef)
 	}
 
+	if correlationID, ok := filters["correlation_id"]; ok {
+		paramCount++
+		query += fmt.Sprintf(" AND correlation_id = $%d", paramCount)
+		args = append(args, correlationID)
+	}
+
 	if status, ok := filters["status"]; ok {
 		paramCount++
 		query += fmt.Sprintf(" AND status = $%d", paramCount)
@@ -415,7 +468,7 @@ func (r *NotificationRepository) GetNotificationHistory(filters map[string]inter
 	for rows.Next() {
 		var id, eventType, notifType, encryptedRecipient, subject, status string
 		var sentAt, failedAt sql.NullTime
-		var errorMsg, orderReference sql.NullString
+		var errorMsg, orderReference, correlationID sql.NullString
 		var retryCount int
 		var createdAt time.Time
 
@@ -432,6 +485,7 @@ func (r *NotificationRepository) GetNotificationHistory(filters map[string]inter
 			&retryCount,
 			&createdAt,
 			&orderReference,
+			&correlationID,
 		)
 		if err != nil {
 			return nil, err
@@ -471,6 +525,10 @@ func (r *NotificationRepository) GetNotificationHistory(filters map[string]inter
 			notification["order_reference"] = orderReference.String
 		}
 
+		if correlationID.Valid {
+			notification["correlation_id"] = correlationID.String
+		}
+
 		notifications = append(notifications, notification)
 	}
 
@@ -495,6 +553,12 @@ func (r *NotificationRepository) CountNotifications(filters map[string]interface
 		args = append(args, orderRef)
 	}
 
+	if correlationID, ok := filters["correlation_id"]; ok {
+		paramCount++
+		query += fmt.Sprintf(" AND correlation_id = $%d", paramCount)
+		args = append(args, correlationID)
+	}
+
 	if status, ok := filters["status"]; ok {
 		paramCount++
 		query += fmt.Sprintf(" AND status = $%d", paramCount)
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pos/notification-service/src/models"
+	"github.com/pos/notification-service/src/providers"
+)
+
+const (
+	// maxInvoicePDFBytes bounds the invoice PDF downloaded from the order service
+	maxInvoicePDFBytes = 5 << 20
+	// invoicePDFTimeout bounds the download so a slow order service cannot hold up the email
+	invoicePDFTimeout = 10 * time.Second
+)
+
+// invoiceAttachments returns the PDF invoice of order.invoice notifications whose tenant attaches it
+// The PDF is downloaded from the order service on every send, so resent invoices carry it too.
+// A failed download is logged and the email goes out without the attachment.
+func (s *NotificationService) invoiceAttachments(ctx context.Context, notification *models.Notification) []providers.Attachment {
+	if attach, _ := notification.Metadata["attach_invoice_pdf"].(bool); !attach {
+		return nil
+	}
+	orderReference, _ := notification.Metadata["order_reference"].(string)
+	if orderReference == "" || s.orderServiceURL == "" {
+		return nil
+	}
+
+	pdf, err := s.fetchInvoicePDF(ctx, orderReference)
+	if err != nil {
+		log.Printf("[INVOICE_PDF] Sending invoice %s without PDF: %v", orderReference, err)
+		return nil
+	}
+
+	return []providers.Attachment{{
+		Filename:    fmt.Sprintf("invoice-%s.pdf", orderReference),
+		ContentType: "application/pdf",
+		Content:     pdf,
+	}}
+}
+
+func (s *NotificationService) fetchInvoicePDF(ctx context.Context, orderReference string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, invoicePDFTimeout)
+	defer cancel()
+
+	invoiceURL := fmt.Sprintf("%s/api/v1/public/orders/%s/invoice.pdf", s.orderServiceURL, url.PathEscape(orderReference))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, invoiceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("order service returned %d", resp.StatusCode)
+	}
+
+	pdf, err := io.ReadAll(io.LimitReader(resp.Body, maxInvoicePDFBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(pdf) > maxInvoicePDFBytes {
+		return nil, fmt.Errorf("invoice PDF is larger than %d bytes", maxInvoicePDFBytes)
+	}
+	return pdf, nil
+}
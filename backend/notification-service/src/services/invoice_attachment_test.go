@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pos/notification-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invoiceNotification(attach bool) *models.Notification {
+	return &models.Notification{
+		Metadata: map[string]interface{}{
+			"event_type":         "order.invoice",
+			"order_reference":    "ORD-ABC123",
+			"attach_invoice_pdf": attach,
+		},
+	}
+}
+
+func TestInvoiceAttachments_DownloadsPDF(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/public/orders/ORD-ABC123/invoice.pdf", r.URL.Path)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.3 invoice"))
+	}))
+	defer server.Close()
+
+	s := &NotificationService{orderServiceURL: server.URL}
+	attachments := s.invoiceAttachments(context.Background(), invoiceNotification(true))
+
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "invoice-ORD-ABC123.pdf", attachments[0].Filename)
+	assert.Equal(t, "application/pdf", attachments[0].ContentType)
+	assert.Equal(t, []byte("%PDF-1.3 invoice"), attachments[0].Content)
+}
+
+func TestInvoiceAttachments_OnlyWhenTenantAttachesPDF(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the invoice must not be downloaded")
+	}))
+	defer server.Close()
+
+	s := &NotificationService{orderServiceURL: server.URL}
+	assert.Empty(t, s.invoiceAttachments(context.Background(), invoiceNotification(false)))
+	assert.Empty(t, s.invoiceAttachments(context.Background(), &models.Notification{}))
+}
+
+func TestInvoiceAttachments_FailedDownloadSendsWithoutPDF(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	s := &NotificationService{orderServiceURL: server.URL}
+	assert.Empty(t, s.invoiceAttachments(context.Background(), invoiceNotification(true)))
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -19,16 +20,20 @@ import (
 )
 
 type NotificationService struct {
-	repo          *repository.NotificationRepository
-	emailProvider providers.EmailProvider
-	pushProvider  providers.PushProvider
-	templates     map[string]*template.Template
-	frontendURL   string
-	db            *sql.DB
-	encryptor     utils.Encryptor
+	repo            *repository.NotificationRepository
+	emailProvider   providers.EmailProvider
+	pushProvider    providers.PushProvider
+	textProvider    providers.TextMessageProvider // SMS and WhatsApp; nil when none is configured
+	templates       map[string]*template.Template
+	frontendURL     string
+	orderServiceURL string       // Serves the invoice PDFs attached to invoice emails
+	httpClient      *http.Client // Downloads invoice PDFs; nil uses http.DefaultClient
+	db              *sql.DB
+	encryptor       utils.Encryptor
+	sendLock        SendLock // Serializes send attempts across replicas; nil sends without locking
 }
 
-func NewNotificationService(db *sql.DB) (*NotificationService, error) {
+func NewNotificationService(db *sql.DB, sendLock SendLock) (*NotificationService, error) {
 	repo, err := repository.NewNotificationRepositoryWithVault(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create notification repository: %w", err)
@@ -40,14 +45,26 @@ func NewNotificationService(db *sql.DB) (*NotificationService, error) {
 		return nil, fmt.Errorf("failed to create vault client: %w", err)
 	}
 
+	textProvider, err := providers.NewTextMessageProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure text message provider: %w", err)
+	}
+	if textProvider == nil {
+		log.Printf("Warning: SMS_PROVIDER is not set; SMS and WhatsApp notifications will fail")
+	}
+
 	service := &NotificationService{
-		repo:          repo,
-		emailProvider: providers.NewSMTPEmailProvider(),
-		pushProvider:  providers.NewMockPushProvider(),
-		templates:     make(map[string]*template.Template),
-		frontendURL:   utils.GetEnv("FRONTEND_DOMAIN"),
-		db:            db,
-		encryptor:     encryptor,
+		repo:            repo,
+		emailProvider:   providers.NewSMTPEmailProvider(),
+		pushProvider:    providers.NewMockPushProvider(),
+		textProvider:    textProvider,
+		templates:       make(map[string]*template.Template),
+		frontendURL:     utils.GetEnv("FRONTEND_DOMAIN"),
+		orderServiceURL: utils.GetEnv("ORDER_SERVICE_URL"),
+		httpClient:      &http.Client{},
+		db:              db,
+		encryptor:       encryptor,
+		sendLock:        sendLock,
 	}
 
 	// Load all templates
@@ -65,12 +82,24 @@ func (s *NotificationService) loadTemplates() error {
 		"registration.html",
 		"login_alert.html",
 		"password_reset.html",
+		"magic_link.html",
+		"new_device_login.html",
 		"password_changed.html",
+		"email_change.html",
+		"email_changed.html",
 		"team_invitation.html",
 		"order_invoice.html",
+		"order_payment_instructions.html",
 		"order_staff_notification.html",
+		"order_manual_payment_required.html",
 		"user_deletion_warning.html",
 		"guest_data_deleted.html",
+		"refund_approval_requested.html",
+		"order_sla_breached.html",
+		"data_export_ready.html",
+		// Indonesian variants, picked by localizedTemplate for users whose locale is id
+		"order_staff_notification.id.html",
+		"data_export_ready.id.html",
 	}
 
 	// Get custom template functions
@@ -99,7 +128,10 @@ func (s *NotificationService) HandleEvent(ctx context.Context, eventData []byte)
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
-	log.Printf("Processing event: %s for tenant: %s", event.EventType, event.TenantID)
+	log.Printf("Processing event: %s for tenant: %s (correlation_id: %s)", event.EventType, event.TenantID, event.CorrelationID)
+
+	// Notifications created while handling the event inherit its correlation ID
+	ctx = utils.WithCorrelationID(ctx, event.CorrelationID)
 
 	switch event.EventType {
 	case "user.registered":
@@ -110,16 +142,38 @@ func (s *NotificationService) HandleEvent(ctx context.Context, eventData []byte)
 		return s.handlePasswordResetRequest(ctx, event)
 	case "password.changed":
 		return s.handlePasswordChanged(ctx, event)
+	case "security.new_device_login":
+		return s.handleNewDeviceLogin(ctx, event)
+	case "login.magic_link_requested":
+		return s.handleMagicLinkRequest(ctx, event)
+	case "email.change_confirm_requested", "email.change_verify_requested":
+		return s.handleEmailChangeRequest(ctx, event)
+	case "email.changed":
+		return s.handleEmailChanged(ctx, event)
+	case "data_export.ready":
+		return s.handleDataExportReady(ctx, event)
 	case "invitation.created":
 		return s.handleTeamInvitation(ctx, event)
 	case "order.invoice":
 		return s.handleOrderInvoice(ctx, event)
+	case "order.payment_instructions":
+		return s.handlePaymentInstructions(ctx, event)
 	case "order.paid":
 		return s.handleOrderPaid(ctx, event)
+	case "order.manual_payment_required":
+		return s.handleManualPaymentRequired(ctx, event)
 	case "user_deletion_warning":
 		return s.handleUserDeletionWarning(ctx, event)
 	case "guest_data_deleted":
 		return s.handleGuestDataDeleted(ctx, event)
+	case "guest.order_history_otp":
+		return s.handleOrderHistoryOTP(ctx, event)
+	case "cart.abandoned":
+		return s.handleCartAbandoned(ctx, event)
+	case "refund.approval_requested":
+		return s.handleRefundApprovalRequested(ctx, event)
+	case "order.sla_breached":
+		return s.handleOrderSLABreached(ctx, event)
 	default:
 		log.Printf("Unknown event type: %s", event.EventType)
 		return nil
@@ -202,6 +256,55 @@ func (s *NotificationService) handleUserLogin(ctx context.Context, event models.
 	return s.sendEmail(ctx, notification)
 }
 
+// handleNewDeviceLogin processes security.new_device_login events
+// It replaces the regular login alert when the device or country had not been seen for the user.
+func (s *NotificationService) handleNewDeviceLogin(ctx context.Context, event models.NotificationEvent) error {
+	email, _ := event.Data["email"].(string)
+	name, _ := event.Data["name"].(string)
+	ipAddress, _ := event.Data["ip_address"].(string)
+	userAgent, _ := event.Data["user_agent"].(string)
+	country, _ := event.Data["country"].(string)
+	newDevice, _ := event.Data["new_device"].(bool)
+	newCountry, _ := event.Data["new_country"].(bool)
+
+	subject := "Login from a new device"
+	if newCountry && !newDevice {
+		subject = "Login from a new location"
+	}
+	body := s.renderTemplate("new_device_login", map[string]interface{}{
+		"Name":       name,
+		"IPAddress":  ipAddress,
+		"UserAgent":  userAgent,
+		"Country":    country,
+		"NewDevice":  newDevice,
+		"NewCountry": newCountry,
+		"Time":       time.Now().Format("2006-01-02 15:04:05"),
+	})
+
+	metadata := event.Data
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["event_type"] = event.EventType
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		UserID:    &event.UserID,
+		Type:      models.NotificationTypeEmail,
+		Status:    models.NotificationStatusPending,
+		Subject:   subject,
+		Body:      body,
+		Recipient: email,
+		Metadata:  metadata,
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return s.sendEmail(ctx, notification)
+}
+
 func (s *NotificationService) handlePasswordResetRequest(ctx context.Context, event models.NotificationEvent) error {
 	email, _ := event.Data["email"].(string)
 	name, _ := event.Data["name"].(string)
@@ -239,6 +342,206 @@ func (s *NotificationService) handlePasswordResetRequest(ctx context.Context, ev
 	return s.sendEmail(ctx, notification)
 }
 
+// handleMagicLinkRequest processes login.magic_link_requested events
+// The link logs the user in, so its token is not kept in the notification metadata.
+func (s *NotificationService) handleMagicLinkRequest(ctx context.Context, event models.NotificationEvent) error {
+	email, _ := event.Data["email"].(string)
+	name, _ := event.Data["name"].(string)
+	loginToken, _ := event.Data["login_token"].(string)
+
+	if email == "" || loginToken == "" {
+		return fmt.Errorf("email and login_token are required for magic link emails")
+	}
+
+	expiresInMinutes := 15
+	if val, ok := event.Data["expires_in_minutes"].(float64); ok {
+		expiresInMinutes = int(val)
+	}
+
+	// verify_device links confirm a password login from a new device rather than replace the password
+	purpose, _ := event.Data["purpose"].(string)
+	verifyDevice := purpose == "verify_device"
+
+	subject := "Your login link"
+	if verifyDevice {
+		subject = "Confirm your login from a new device"
+	}
+	body := s.renderTemplate("magic_link", map[string]interface{}{
+		"Name":             name,
+		"URL":              fmt.Sprintf("%s/magic-link?token=%s", s.frontendURL, loginToken),
+		"ExpiresInMinutes": expiresInMinutes,
+		"VerifyDevice":     verifyDevice,
+	})
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		UserID:    &event.UserID,
+		Type:      models.NotificationTypeEmail,
+		Status:    models.NotificationStatusPending,
+		Subject:   subject,
+		Body:      body,
+		Recipient: email,
+		Metadata: map[string]interface{}{
+			"event_type": event.EventType,
+			"name":       name,
+			"purpose":    purpose,
+		},
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return s.sendEmail(ctx, notification)
+}
+
+// handleEmailChangeRequest processes email.change_confirm_requested and email.change_verify_requested events
+// The confirmation goes to the current address and the verification to the new one. Like login
+// links, the token is not kept in the notification metadata.
+func (s *NotificationService) handleEmailChangeRequest(ctx context.Context, event models.NotificationEvent) error {
+	email, _ := event.Data["email"].(string)
+	name, _ := event.Data["name"].(string)
+	newEmail, _ := event.Data["new_email"].(string)
+	token, _ := event.Data["token"].(string)
+
+	if email == "" || token == "" {
+		return fmt.Errorf("email and token are required for email change emails")
+	}
+
+	expiresInHours := 24
+	if val, ok := event.Data["expires_in_hours"].(float64); ok {
+		expiresInHours = int(val)
+	}
+
+	verify := event.EventType == "email.change_verify_requested"
+	subject := "Confirm your email change"
+	url := fmt.Sprintf("%s/email-change/confirm?token=%s", s.frontendURL, token)
+	if verify {
+		subject = "Verify your new email address"
+		url = fmt.Sprintf("%s/email-change/verify?token=%s", s.frontendURL, token)
+	}
+	body := s.renderTemplate("email_change", map[string]interface{}{
+		"Name":           name,
+		"NewEmail":       newEmail,
+		"URL":            url,
+		"ExpiresInHours": expiresInHours,
+		"Verify":         verify,
+	})
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		UserID:    &event.UserID,
+		Type:      models.NotificationTypeEmail,
+		Status:    models.NotificationStatusPending,
+		Subject:   subject,
+		Body:      body,
+		Recipient: email,
+		Metadata: map[string]interface{}{
+			"event_type": event.EventType,
+			"name":       name,
+		},
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return s.sendEmail(ctx, notification)
+}
+
+// handleEmailChanged processes email.changed events, alerting the previous address
+func (s *NotificationService) handleEmailChanged(ctx context.Context, event models.NotificationEvent) error {
+	email, _ := event.Data["email"].(string)
+	name, _ := event.Data["name"].(string)
+	newEmail, _ := event.Data["new_email"].(string)
+
+	subject := "Your login email has been changed"
+	body := s.renderTemplate("email_changed", map[string]interface{}{
+		"Name":     name,
+		"NewEmail": newEmail,
+		"Time":     time.Now().Format("2006-01-02 15:04:05"),
+	})
+
+	metadata := event.Data
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["event_type"] = event.EventType
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		UserID:    &event.UserID,
+		Type:      models.NotificationTypeEmail,
+		Status:    models.NotificationStatusPending,
+		Subject:   subject,
+		Body:      body,
+		Recipient: email,
+		Metadata:  metadata,
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return s.sendEmail(ctx, notification)
+}
+
+// handleDataExportReady processes data_export.ready events, sending the signed download link of a personal data export
+// The link is kept out of the notification metadata: anyone holding it can download the bundle.
+func (s *NotificationService) handleDataExportReady(ctx context.Context, event models.NotificationEvent) error {
+	email, _ := event.Data["email"].(string)
+	name, _ := event.Data["name"].(string)
+	downloadPath, _ := event.Data["download_path"].(string)
+	exportID, _ := event.Data["export_id"].(string)
+	format, _ := event.Data["format"].(string)
+	locale, _ := event.Data["locale"].(string)
+	timezone, _ := event.Data["timezone"].(string)
+
+	if email == "" || downloadPath == "" {
+		return fmt.Errorf("email and download_path are required for data export emails")
+	}
+
+	expiresAt := ""
+	if val, ok := event.Data["expires_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, val); err == nil {
+			expiresAt = utils.FormatDateTimeIn(t, locale, timezone)
+		}
+	}
+
+	subject := "Your personal data export is ready"
+	if locale == "id" {
+		subject = "Ekspor data pribadi Anda sudah siap"
+	}
+	body := s.renderTemplate(s.localizedTemplate("data_export_ready", locale), map[string]interface{}{
+		"Name":      name,
+		"URL":       s.frontendURL + downloadPath,
+		"Format":    strings.ToUpper(format),
+		"ExpiresAt": expiresAt,
+	})
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		UserID:    &event.UserID,
+		Type:      models.NotificationTypeEmail,
+		Status:    models.NotificationStatusPending,
+		Subject:   subject,
+		Body:      body,
+		Recipient: email,
+		Metadata: map[string]interface{}{
+			"event_type": event.EventType,
+			"name":       name,
+			"export_id":  exportID,
+			"format":     format,
+		},
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return s.sendEmail(ctx, notification)
+}
+
 func (s *NotificationService) handlePasswordChanged(ctx context.Context, event models.NotificationEvent) error {
 	email, _ := event.Data["email"].(string)
 	name, _ := event.Data["name"].(string)
@@ -323,6 +626,12 @@ func (s *NotificationService) handleOrderInvoice(ctx context.Context, event mode
 	// Convert amounts from interface{} to numbers
 	subtotalAmount := 0
 	deliveryFee := 0
+	discountAmount := 0
+	promotionDiscount := 0
+	loyaltyDiscount := 0
+	loyaltyPoints := 0
+	serviceCharge := 0
+	tax := 0
 	totalAmount := 0
 
 	if val, ok := event.Data["subtotal_amount"].(float64); ok {
@@ -331,9 +640,42 @@ func (s *NotificationService) handleOrderInvoice(ctx context.Context, event mode
 	if val, ok := event.Data["delivery_fee"].(float64); ok {
 		deliveryFee = int(val)
 	}
+	if val, ok := event.Data["discount_amount"].(float64); ok {
+		discountAmount = int(val)
+	}
+	if val, ok := event.Data["loyalty_discount_amount"].(float64); ok {
+		loyaltyDiscount = int(val)
+	}
+	if val, ok := event.Data["loyalty_points_redeemed"].(float64); ok {
+		loyaltyPoints = int(val)
+	}
+	if val, ok := event.Data["service_charge_amount"].(float64); ok {
+		serviceCharge = int(val)
+	}
+	if val, ok := event.Data["tax_amount"].(float64); ok {
+		tax = int(val)
+	}
 	if val, ok := event.Data["total_amount"].(float64); ok {
 		totalAmount = int(val)
 	}
+	voucherCode, _ := event.Data["voucher_code"].(string)
+	serviceChargeRate, _ := event.Data["service_charge_rate"].(float64)
+	taxRate, _ := event.Data["tax_rate"].(float64)
+
+	// Automatic promotions are summed into one line labelled with their names
+	promotionNames := []string{}
+	if promotionsData, ok := event.Data["promotions"].([]interface{}); ok {
+		for _, promotionInterface := range promotionsData {
+			if promotionMap, ok := promotionInterface.(map[string]interface{}); ok {
+				if amount, ok := promotionMap["discount_amount"].(float64); ok {
+					promotionDiscount += int(amount)
+				}
+				if name, ok := promotionMap["name"].(string); ok && name != "" {
+					promotionNames = append(promotionNames, name)
+				}
+			}
+		}
+	}
 
 	// Parse items
 	type InvoiceItem struct {
@@ -374,18 +716,55 @@ func (s *NotificationService) handleOrderInvoice(ctx context.Context, event mode
 		deliveryFeeStr = formatIDR(deliveryFee)
 	}
 
+	discountAmountStr := ""
+	if discountAmount > 0 {
+		discountAmountStr = formatIDR(discountAmount)
+	}
+
+	promotionDiscountStr := ""
+	if promotionDiscount > 0 {
+		promotionDiscountStr = formatIDR(promotionDiscount)
+	}
+
+	loyaltyDiscountStr := ""
+	if loyaltyDiscount > 0 {
+		loyaltyDiscountStr = formatIDR(loyaltyDiscount)
+	}
+
+	serviceChargeStr, serviceChargeRateStr := "", ""
+	if serviceCharge > 0 {
+		serviceChargeStr = formatIDR(serviceCharge)
+		serviceChargeRateStr = utils.FormatPercent(serviceChargeRate)
+	}
+
+	taxStr, taxRateStr := "", ""
+	if tax > 0 {
+		taxStr = formatIDR(tax)
+		taxRateStr = utils.FormatPercent(taxRate)
+	}
+
 	// Prepare template data
 	templateData := map[string]interface{}{
-		"OrderReference": orderReference,
-		"CustomerName":   customerName,
-		"CustomerEmail":  email,
-		"DeliveryType":   deliveryType,
-		"CreatedAt":      createdAt.Format("02 January 2006 15:04"),
-		"SubtotalAmount": formatIDR(subtotalAmount),
-		"DeliveryFee":    deliveryFeeStr,
-		"TotalAmount":    formatIDR(totalAmount),
-		"Items":          items,
-		"OrderURL":       fmt.Sprintf("%s/orders/%s", s.frontendURL, orderReference),
+		"OrderReference":    orderReference,
+		"CustomerName":      customerName,
+		"CustomerEmail":     email,
+		"DeliveryType":      deliveryType,
+		"CreatedAt":         createdAt.Format("02 January 2006 15:04"),
+		"SubtotalAmount":    formatIDR(subtotalAmount),
+		"DeliveryFee":       deliveryFeeStr,
+		"DiscountAmount":    discountAmountStr,
+		"VoucherCode":       voucherCode,
+		"PromotionDiscount": promotionDiscountStr,
+		"PromotionNames":    strings.Join(promotionNames, ", "),
+		"LoyaltyDiscount":   loyaltyDiscountStr,
+		"LoyaltyPoints":     loyaltyPoints,
+		"ServiceCharge":     serviceChargeStr,
+		"ServiceChargeRate": serviceChargeRateStr,
+		"Tax":               taxStr,
+		"TaxRate":           taxRateStr,
+		"TotalAmount":       formatIDR(totalAmount),
+		"Items":             items,
+		"OrderURL":          fmt.Sprintf("%s/orders/%s", s.frontendURL, orderReference),
 	}
 
 	// Render items with formatted prices
@@ -427,6 +806,251 @@ func (s *NotificationService) handleOrderInvoice(ctx context.Context, event mode
 	return s.sendEmail(ctx, notification)
 }
 
+// vaBankNames maps Midtrans/Xendit bank codes to the names customers know
+var vaBankNames = map[string]string{
+	"bca":     "BCA",
+	"bni":     "BNI",
+	"bri":     "BRI",
+	"permata": "Permata",
+	"mandiri": "Mandiri",
+}
+
+// paymentInstructionsLocation is the zone payment deadlines are shown in
+var paymentInstructionsLocation = time.FixedZone("WIB", 7*60*60)
+
+// handlePaymentInstructions processes order.payment_instructions events
+// Guests who pay by bank transfer are emailed the virtual account number, the amount
+// and the deadline, after which the order is cancelled and its stock released.
+func (s *NotificationService) handlePaymentInstructions(ctx context.Context, event models.NotificationEvent) error {
+	email, _ := event.Data["customer_email"].(string)
+	customerName, _ := event.Data["customer_name"].(string)
+	orderReference, _ := event.Data["order_reference"].(string)
+	bank, _ := event.Data["bank"].(string)
+	vaNumber, _ := event.Data["va_number"].(string)
+	expiresAtRaw, _ := event.Data["expires_at"].(string)
+
+	if email == "" || orderReference == "" || vaNumber == "" {
+		return fmt.Errorf("customer_email, order_reference and va_number are required for payment instructions")
+	}
+
+	totalAmount := 0
+	if val, ok := event.Data["total_amount"].(float64); ok {
+		totalAmount = int(val)
+	}
+
+	bankName, ok := vaBankNames[strings.ToLower(bank)]
+	if !ok {
+		bankName = strings.ToUpper(bank)
+	}
+
+	expiresAt := expiresAtRaw
+	if t, err := time.Parse(time.RFC3339, expiresAtRaw); err == nil {
+		expiresAt = t.In(paymentInstructionsLocation).Format("02 January 2006 15:04 MST")
+	}
+
+	if customerName == "" {
+		customerName = "Customer"
+	}
+
+	subject := fmt.Sprintf("Payment Instructions - %s", orderReference)
+	body := s.renderTemplate("order_payment_instructions", map[string]interface{}{
+		"OrderReference": orderReference,
+		"CustomerName":   customerName,
+		"Bank":           strings.ToLower(bank),
+		"BankName":       bankName,
+		"VANumber":       vaNumber,
+		"TotalAmount":    utils.FormatCurrencyIDR(totalAmount),
+		"ExpiresAt":      expiresAt,
+		"OrderURL":       fmt.Sprintf("%s/orders/%s", s.frontendURL, orderReference),
+	})
+
+	// Add event_type to metadata
+	metadata := event.Data
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["event_type"] = event.EventType
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		Type:      models.NotificationTypeEmail,
+		Status:    models.NotificationStatusPending,
+		Subject:   subject,
+		Body:      body,
+		Recipient: email,
+		Metadata:  metadata,
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return s.sendEmail(ctx, notification)
+}
+
+// handleManualPaymentRequired processes order.manual_payment_required events
+// The order was placed while the payment gateway was down, so staff have to collect payment
+// at the counter or on delivery.
+func (s *NotificationService) handleManualPaymentRequired(ctx context.Context, event models.NotificationEvent) error {
+	orderReference, _ := event.Data["order_reference"].(string)
+	customerName, _ := event.Data["customer_name"].(string)
+	deliveryType, _ := event.Data["delivery_type"].(string)
+	tableNumber, _ := event.Data["table_number"].(string)
+	paymentMethod, _ := event.Data["payment_method"].(string)
+	paymentRetryQueued, _ := event.Data["payment_retry_queued"].(bool)
+
+	if orderReference == "" {
+		return fmt.Errorf("order_reference is required for manual payment notifications")
+	}
+
+	totalAmount := 0
+	if val, ok := event.Data["total_amount"].(float64); ok {
+		totalAmount = int(val)
+	}
+
+	recipients, err := s.queryStaffRecipients(ctx, event.TenantID, event.EventType, deliveryType)
+	if err != nil {
+		return fmt.Errorf("failed to query staff recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		log.Printf("[MANUAL_PAYMENT] No staff members configured to receive notifications for tenant %s", event.TenantID)
+		return nil
+	}
+
+	if customerName == "" {
+		customerName = "Guest"
+	}
+
+	subject := fmt.Sprintf("Collect Payment - %s", orderReference)
+	body := s.renderTemplate("order_manual_payment_required", map[string]interface{}{
+		"OrderReference":     orderReference,
+		"CustomerName":       customerName,
+		"DeliveryType":       deliveryType,
+		"TableNumber":        tableNumber,
+		"PaymentMethod":      strings.ToUpper(paymentMethod),
+		"PaymentRetryQueued": paymentRetryQueued,
+		"TotalAmount":        utils.FormatCurrencyIDR(totalAmount),
+	})
+
+	metadata := event.Data
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["event_type"] = event.EventType
+
+	sent, attempted := s.notifyStaff(ctx, event.TenantID, recipients, sameStaffMessage(subject, body), metadata)
+	log.Printf("[MANUAL_PAYMENT] Sent %d/%d staff notifications for order %s", sent, attempted, orderReference)
+	return nil
+}
+
+// handleRefundApprovalRequested processes refund.approval_requested events
+// Notifies the staff whose role can approve the refund: owners only when the amount is above
+// the tenant's threshold, otherwise owners and managers. Approvers get it by email unless they opted out.
+func (s *NotificationService) handleRefundApprovalRequested(ctx context.Context, event models.NotificationEvent) error {
+	orderReference, _ := event.Data["order_reference"].(string)
+	reason, _ := event.Data["reason"].(string)
+	approverRole, _ := event.Data["approver_role"].(string)
+	requestedByName, _ := event.Data["requested_by_name"].(string)
+	requestedByRole, _ := event.Data["requested_by_role"].(string)
+
+	if orderReference == "" {
+		return fmt.Errorf("order_reference is required for refund approval notifications")
+	}
+
+	amount := 0
+	if val, ok := event.Data["amount"].(float64); ok {
+		amount = int(val)
+	}
+
+	approverRoles := []string{"owner", "manager"}
+	if approverRole == "owner" {
+		approverRoles = []string{"owner"}
+	}
+	approvers, err := s.queryStaffRecipients(ctx, event.TenantID, event.EventType, "", approverRoles...)
+	if err != nil {
+		return fmt.Errorf("failed to query refund approvers: %w", err)
+	}
+	if len(approvers) == 0 {
+		log.Printf("[REFUND_APPROVAL] No active approvers for tenant %s", event.TenantID)
+		return nil
+	}
+
+	if requestedByName == "" {
+		requestedByName = "A staff member"
+	}
+
+	subject := fmt.Sprintf("Refund Approval Needed - %s", orderReference)
+	body := s.renderTemplate("refund_approval_requested", map[string]interface{}{
+		"OrderReference":  orderReference,
+		"Reason":          reason,
+		"ApproverRole":    approverRole,
+		"RequestedByName": requestedByName,
+		"RequestedByRole": requestedByRole,
+		"Amount":          utils.FormatCurrencyIDR(amount),
+	})
+
+	metadata := event.Data
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["event_type"] = event.EventType
+
+	sent, attempted := s.notifyStaff(ctx, event.TenantID, approvers, sameStaffMessage(subject, body), metadata)
+	log.Printf("[REFUND_APPROVAL] Sent %d/%d approver notifications for order %s", sent, attempted, orderReference)
+	return nil
+}
+
+// handleOrderSLABreached processes order.sla_breached events
+// Notifies the staff who chose to receive late order alerts that a paid order ran over the tenant's
+// SLA target for starting or finishing its preparation.
+func (s *NotificationService) handleOrderSLABreached(ctx context.Context, event models.NotificationEvent) error {
+	orderReference, _ := event.Data["order_reference"].(string)
+	stage, _ := event.Data["stage"].(string)
+	deliveryType, _ := event.Data["delivery_type"].(string)
+	tableNumber, _ := event.Data["table_number"].(string)
+
+	if orderReference == "" {
+		return fmt.Errorf("order_reference is required for late order notifications")
+	}
+
+	elapsedMinutes, targetMinutes := 0, 0
+	if val, ok := event.Data["elapsed_minutes"].(float64); ok {
+		elapsedMinutes = int(val)
+	}
+	if val, ok := event.Data["target_minutes"].(float64); ok {
+		targetMinutes = int(val)
+	}
+
+	recipients, err := s.queryStaffRecipients(ctx, event.TenantID, event.EventType, deliveryType)
+	if err != nil {
+		return fmt.Errorf("failed to query staff recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		log.Printf("[ORDER_SLA] No staff members configured to receive notifications for tenant %s", event.TenantID)
+		return nil
+	}
+
+	subject := fmt.Sprintf("Order Running Late - %s", orderReference)
+	body := s.renderTemplate("order_sla_breached", map[string]interface{}{
+		"OrderReference": orderReference,
+		"Stage":          stage,
+		"DeliveryType":   deliveryType,
+		"TableNumber":    tableNumber,
+		"ElapsedMinutes": elapsedMinutes,
+		"TargetMinutes":  targetMinutes,
+	})
+
+	metadata := event.Data
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["event_type"] = event.EventType
+
+	sent, attempted := s.notifyStaff(ctx, event.TenantID, recipients, sameStaffMessage(subject, body), metadata)
+	log.Printf("[ORDER_SLA] Sent %d/%d staff notifications for order %s", sent, attempted, orderReference)
+	return nil
+}
+
 // handleUserDeletionWarning processes user_deletion_warning events and sends 30-day deletion notice (T136)
 // Sent 60 days after soft delete to warn users their account will be permanently deleted in 30 days
 func (s *NotificationService) handleUserDeletionWarning(ctx context.Context, event models.NotificationEvent) error {
@@ -584,6 +1208,175 @@ func (s *NotificationService) handleGuestDataDeleted(ctx context.Context, event
 	return s.sendEmail(ctx, notification)
 }
 
+// handleOrderHistoryOTP processes guest.order_history_otp events
+// Sends a returning guest the code that unlocks their order history, by SMS or WhatsApp.
+// The code is not stored: the recorded notification, which staff can read in the notification
+// history, has it withheld, and only the message handed to the provider carries it.
+func (s *NotificationService) handleOrderHistoryOTP(ctx context.Context, event models.NotificationEvent) error {
+	phone, _ := event.Data["phone"].(string)
+	channel, _ := event.Data["channel"].(string)
+	code, _ := event.Data["code"].(string)
+
+	if phone == "" || code == "" {
+		return fmt.Errorf("phone and code are required for order history verification")
+	}
+	if channel == "" {
+		channel = "sms"
+	}
+
+	expiresInMinutes := 5
+	if val, ok := event.Data["expires_in_minutes"].(float64); ok {
+		expiresInMinutes = int(val)
+	}
+
+	const bodyFormat = "Kode verifikasi riwayat pesanan Anda: %s. Berlaku %d menit. Jangan bagikan kode ini kepada siapa pun."
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		Type:      models.NotificationTypeSMS,
+		Status:    models.NotificationStatusPending,
+		Body:      fmt.Sprintf(bodyFormat, "******", expiresInMinutes),
+		Recipient: phone,
+		Metadata: map[string]interface{}{
+			"event_type": event.EventType,
+			"channel":    channel,
+		},
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	notification.Body = fmt.Sprintf(bodyFormat, code, expiresInMinutes)
+	return s.sendTextMessage(ctx, notification, channel)
+}
+
+// handleCartAbandoned processes cart.abandoned events
+// Reminds a guest about the cart they left, by email when they gave one and by SMS otherwise.
+// The resume link restores the cart, so it is not kept in the notification metadata.
+func (s *NotificationService) handleCartAbandoned(ctx context.Context, event models.NotificationEvent) error {
+	customerName, _ := event.Data["customer_name"].(string)
+	email, _ := event.Data["customer_email"].(string)
+	phone, _ := event.Data["customer_phone"].(string)
+	resumeURL, _ := event.Data["resume_url"].(string)
+
+	if resumeURL == "" || (email == "" && phone == "") {
+		return fmt.Errorf("resume_url and customer_email or customer_phone are required for cart reminders")
+	}
+
+	itemCount := 0
+	if val, ok := event.Data["item_count"].(float64); ok {
+		itemCount = int(val)
+	}
+	totalAmount := 0
+	if val, ok := event.Data["total_amount"].(float64); ok {
+		totalAmount = int(val)
+	}
+
+	metadata := map[string]interface{}{
+		"event_type": event.EventType,
+		"item_count": itemCount,
+	}
+
+	if email == "" {
+		notification := &models.Notification{
+			TenantID:  event.TenantID,
+			Type:      models.NotificationTypeSMS,
+			Status:    models.NotificationStatusPending,
+			Body:      fmt.Sprintf("Keranjang Anda (%d item) masih menunggu. Lanjutkan pesanan Anda: %s", itemCount, resumeURL),
+			Recipient: phone,
+			Metadata:  metadata,
+		}
+		if err := s.repo.Create(ctx, notification); err != nil {
+			return fmt.Errorf("failed to create notification: %w", err)
+		}
+		return s.sendTextMessage(ctx, notification, "sms")
+	}
+
+	items := []map[string]interface{}{}
+	if rawItems, ok := event.Data["items"].([]interface{}); ok {
+		for _, raw := range rawItems {
+			item, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			quantity := 0
+			if val, ok := item["quantity"].(float64); ok {
+				quantity = int(val)
+			}
+			items = append(items, map[string]interface{}{
+				"ProductName": item["product_name"],
+				"Quantity":    quantity,
+			})
+		}
+	}
+
+	if customerName == "" {
+		customerName = "there"
+	}
+
+	body := s.renderTemplate("cart_abandoned", map[string]interface{}{
+		"CustomerName": customerName,
+		"Items":        items,
+		"ItemCount":    itemCount,
+		"TotalAmount":  utils.FormatCurrencyIDR(totalAmount),
+		"ResumeURL":    resumeURL,
+	})
+
+	notification := &models.Notification{
+		TenantID:  event.TenantID,
+		Type:      models.NotificationTypeEmail,
+		Status:    models.NotificationStatusPending,
+		Subject:   "You left something in your cart",
+		Body:      body,
+		Recipient: email,
+		Metadata:  metadata,
+	}
+
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	return s.sendEmail(ctx, notification)
+}
+
+// sendTextMessage sends an SMS or WhatsApp notification and records the outcome
+// Failed messages are not retried; verification codes expire before a retry would run
+// and a late cart reminder is worse than none.
+func (s *NotificationService) sendTextMessage(ctx context.Context, notification *models.Notification, channel string) error {
+	if err := s.holdOverPlanLimit(ctx, notification); err != nil {
+		return err
+	}
+
+	var err error
+	if s.textProvider == nil {
+		err = fmt.Errorf("no SMS or WhatsApp provider is configured")
+	} else {
+		err = s.textProvider.Send(channel, notification.Recipient, notification.Body)
+	}
+
+	now := time.Now()
+	if err != nil {
+		errorMsg := err.Error()
+		notification.Status = models.NotificationStatusFailed
+		notification.FailedAt = &now
+		notification.ErrorMsg = &errorMsg
+		log.Printf("[TEXT_SEND_FAILED] ID=%s Channel=%s Error=%v", notification.ID, channel, err)
+		s.trackMetric("notification.text.failed", 1, map[string]string{"channel": channel})
+	} else {
+		notification.Status = models.NotificationStatusSent
+		notification.SentAt = &now
+		log.Printf("[TEXT_SEND_SUCCESS] ID=%s Channel=%s", notification.ID, channel)
+		s.trackMetric("notification.text.sent", 1, map[string]string{"channel": channel})
+	}
+
+	if updateErr := s.repo.UpdateStatus(ctx, notification.ID, notification.Status, notification.SentAt, notification.FailedAt, notification.ErrorMsg); updateErr != nil {
+		log.Printf("Failed to update notification status: %v", updateErr)
+	}
+
+	return err
+}
+
 // handleOrderPaid processes order.paid events and sends notifications to staff
 func (s *NotificationService) handleOrderPaid(ctx context.Context, event models.NotificationEvent) error {
 	// Convert the generic NotificationEvent to OrderPaidEvent
@@ -614,6 +1407,17 @@ func (s *NotificationService) handleOrderPaid(ctx context.Context, event models.
 	log.Printf("[ORDER_PAID] Processing event for order %s (transaction: %s, tenant: %s)",
 		orderEvent.Data.OrderID, orderEvent.Data.TransactionID, orderEvent.TenantID)
 
+	// Hold the transaction's send lock across the duplicate check and the sends, so two
+	// replicas handling a redelivered event cannot both pass the check
+	lockKey := orderPaidSendLockKey(orderEvent.TenantID, orderEvent.Data.TransactionID)
+	return s.withSendLock(ctx, lockKey, func(ctx context.Context) error {
+		return s.sendOrderPaidNotifications(ctx, &orderEvent)
+	})
+}
+
+// sendOrderPaidNotifications sends the staff notifications and customer receipt for a paid
+// order unless they were already sent for its transaction
+func (s *NotificationService) sendOrderPaidNotifications(ctx context.Context, orderEvent *models.OrderPaidEvent) error {
 	// Check for duplicate notifications
 	alreadySent, err := s.repo.HasSentOrderNotification(ctx, orderEvent.TenantID, orderEvent.Data.TransactionID)
 	if err != nil {
@@ -638,14 +1442,14 @@ func (s *NotificationService) handleOrderPaid(ctx context.Context, event models.
 	}
 
 	// Send staff notifications
-	if err := s.sendStaffNotifications(ctx, &orderEvent); err != nil {
+	if err := s.sendStaffNotifications(ctx, orderEvent); err != nil {
 		log.Printf("[ORDER_PAID] Failed to send staff notifications: %v", err)
 		return fmt.Errorf("failed to send staff notifications: %w", err)
 	}
 
 	// Send customer receipt if email provided
 	if orderEvent.Data.CustomerEmail != "" {
-		if err := s.sendCustomerReceipt(ctx, &orderEvent); err != nil {
+		if err := s.sendCustomerReceipt(ctx, orderEvent); err != nil {
 			log.Printf("[ORDER_PAID] Failed to send customer receipt: %v", err)
 			// Don't fail the whole operation if customer receipt fails
 		}
@@ -655,115 +1459,56 @@ func (s *NotificationService) handleOrderPaid(ctx context.Context, event models.
 	return nil
 }
 
-// queryStaffRecipients gets all staff users who should receive order notifications
-func (s *NotificationService) queryStaffRecipients(ctx context.Context, tenantID string) ([]string, error) {
-	log.Printf("[ORDER_PAID] Querying staff recipients for tenant %s", tenantID)
-
-	query := `
-		SELECT id, email
-		FROM users
-		WHERE tenant_id = $1
-		  AND status = 'active'
-		  AND receive_order_notifications = true
-	`
-
-	rows, err := s.db.QueryContext(ctx, query, tenantID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query staff recipients: %w", err)
-	}
-	defer rows.Close()
-
-	var emails []string
-	for rows.Next() {
-		var id, encryptedEmail string
-		if err := rows.Scan(&id, &encryptedEmail); err != nil {
-			log.Printf("[ORDER_PAID] Error scanning staff row: %v", err)
-			continue
-		}
-
-		// Decrypt email address with context (user:email is the encryption context used during encryption)
-		email, err := s.encryptor.DecryptWithContext(ctx, encryptedEmail, "user:email")
-		if err != nil {
-			log.Printf("[ORDER_PAID] Failed to decrypt email for user %s: %v", id, err)
-			continue // Skip this user
-		}
-
-		emails = append(emails, email)
-		log.Printf("[ORDER_PAID] Found staff recipient: %s (ID: %s)", email, id)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating staff rows: %w", err)
-	}
-
-	log.Printf("[ORDER_PAID] Found %d staff recipients for tenant %s", len(emails), tenantID)
-	return emails, nil
-}
-
-// sendStaffNotifications sends order notification emails to all configured staff members
+// sendStaffNotifications notifies the staff who chose to receive paid orders, or whose team they are
+// routed to, on their chosen channels
 func (s *NotificationService) sendStaffNotifications(ctx context.Context, orderEvent *models.OrderPaidEvent) error {
 	// Query staff recipients
-	staffEmails, err := s.queryStaffRecipients(ctx, orderEvent.TenantID)
+	recipients, err := s.queryStaffRecipients(ctx, orderEvent.TenantID, "order.paid", orderEvent.Data.DeliveryType)
 	if err != nil {
 		return fmt.Errorf("failed to query staff recipients: %w", err)
 	}
 
-	if len(staffEmails) == 0 {
+	if len(recipients) == 0 {
 		log.Printf("[ORDER_PAID] No staff members configured to receive notifications for tenant %s",
 			orderEvent.TenantID)
 		return nil
 	}
 
-	// Convert event to template data
-	staffData := convertOrderEventToStaffData(orderEvent)
-
-	// Render template
-	body, err := s.renderStaffNotificationTemplate(staffData)
-	if err != nil {
-		return fmt.Errorf("failed to render staff notification template: %w", err)
+	metadata := map[string]interface{}{
+		"event_type":     "order.paid.staff",
+		"order_id":       orderEvent.Data.OrderID,
+		"transaction_id": orderEvent.Data.TransactionID,
+		"customer_name":  orderEvent.Data.CustomerName,
+		"total_amount":   orderEvent.Data.TotalAmount,
+		"payment_method": orderEvent.Data.PaymentMethod,
 	}
 
-	subject := fmt.Sprintf("New Order Paid - %s", orderEvent.Data.OrderReference)
-
-	// Send notification to each staff member
-	successCount := 0
-	for _, email := range staffEmails {
-		log.Printf("[ORDER_PAID] Sending notification to staff: %s", email)
-
-		// Create notification metadata
-		metadata := map[string]interface{}{
-			"event_type":     "order.paid.staff",
-			"order_id":       orderEvent.Data.OrderID,
-			"transaction_id": orderEvent.Data.TransactionID,
-			"customer_name":  orderEvent.Data.CustomerName,
-			"total_amount":   orderEvent.Data.TotalAmount,
-			"payment_method": orderEvent.Data.PaymentMethod,
-		}
-
-		notification := &models.Notification{
-			TenantID:  orderEvent.TenantID,
-			Type:      models.NotificationTypeEmail,
-			Status:    models.NotificationStatusPending,
-			Subject:   subject,
-			Body:      body,
-			Recipient: email,
-			Metadata:  metadata,
-		}
-
-		if err := s.repo.Create(ctx, notification); err != nil {
-			log.Printf("[ORDER_PAID] Failed to create notification record for %s: %v", email, err)
+	// Render the notification in each staff member's language and timezone, once per combination
+	rendered := make(map[string][2]string)
+	for _, recipient := range recipients {
+		key := recipient.Locale + "|" + recipient.Timezone
+		if _, ok := rendered[key]; ok {
 			continue
 		}
-
-		if err := s.sendEmail(ctx, notification); err != nil {
-			log.Printf("[ORDER_PAID] Failed to send email to %s: %v", email, err)
-			continue
+		staffData := convertOrderEventToStaffData(orderEvent, recipient.Locale, recipient.Timezone)
+		body, err := s.renderStaffNotificationTemplate(staffData, recipient.Locale)
+		if err != nil {
+			return fmt.Errorf("failed to render staff notification template: %w", err)
 		}
-
-		successCount++
+		subject := fmt.Sprintf("New Order Paid - %s", orderEvent.Data.OrderReference)
+		if recipient.Locale == "id" {
+			subject = fmt.Sprintf("Pesanan Baru Dibayar - %s", orderEvent.Data.OrderReference)
+		}
+		rendered[key] = [2]string{subject, body}
+	}
+	message := func(recipient staffRecipient) (string, string) {
+		message := rendered[recipient.Locale+"|"+recipient.Timezone]
+		return message[0], message[1]
 	}
 
-	log.Printf("[ORDER_PAID] Successfully sent %d/%d staff notifications", successCount, len(staffEmails))
+	// Send notification to each staff member on the channels they chose
+	sent, attempted := s.notifyStaff(ctx, orderEvent.TenantID, recipients, message, metadata)
+	log.Printf("[ORDER_PAID] Successfully sent %d/%d staff notifications", sent, attempted)
 	return nil
 }
 
@@ -820,8 +1565,14 @@ func (s *NotificationService) sendCustomerReceipt(ctx context.Context, orderEven
 }
 
 func (s *NotificationService) sendEmail(ctx context.Context, notification *models.Notification) error {
+	if err := s.holdOverPlanLimit(ctx, notification); err != nil {
+		return err
+	}
+
+	attachments := s.invoiceAttachments(ctx, notification)
+
 	startTime := time.Now()
-	err := s.emailProvider.Send(notification.Recipient, notification.Subject, notification.Body, true)
+	err := s.emailProvider.Send(notification.Recipient, notification.Subject, notification.Body, true, attachments...)
 	duration := time.Since(startTime)
 
 	now := time.Now()
@@ -903,6 +1654,17 @@ func (s *NotificationService) trackMetric(name string, value int64, tags map[str
 	log.Printf("[METRIC] %s=%d%s", name, value, tagStr)
 }
 
+// localizedTemplate returns the name of a template's variant in the given locale, e.g.
+// order_staff_notification.id, or the English template when there is no such variant
+func (s *NotificationService) localizedTemplate(templateName, locale string) string {
+	if localized := templateName + "." + locale; locale != "" {
+		if _, ok := s.templates[localized]; ok {
+			return localized
+		}
+	}
+	return templateName
+}
+
 func (s *NotificationService) renderTemplate(templateName string, data map[string]interface{}) string {
 	tmpl, ok := s.templates[templateName]
 	if !ok {
@@ -973,7 +1735,7 @@ func (s *NotificationService) SendTestNotification(tenantID, recipientEmail, not
 			},
 		}
 
-		body, err = s.renderStaffNotificationTemplate(testData)
+		body, err = s.renderStaffNotificationTemplate(testData, "en")
 		if err != nil {
 			return "", fmt.Errorf("failed to render staff notification template: %w", err)
 		}
@@ -1067,6 +1829,9 @@ func (s *NotificationService) GetNotificationHistory(tenantID string, filters ma
 	if orderRef, ok := filters["order_reference"]; ok {
 		queryFilters["order_reference"] = orderRef
 	}
+	if correlationID, ok := filters["correlation_id"]; ok {
+		queryFilters["correlation_id"] = correlationID
+	}
 	if status, ok := filters["status"]; ok {
 		queryFilters["status"] = status
 	}
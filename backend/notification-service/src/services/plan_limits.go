@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/pos/notification-service/src/models"
+)
+
+// ErrNotificationLimitReached is returned for a notification held back because the tenant's plan
+// allows no more this billing period
+var ErrNotificationLimitReached = errors.New("monthly notification limit of the tenant's plan reached")
+
+// planLimitedEventTypes are the notifications held back once a tenant reaches their plan's monthly
+// limit. Account, security and payment messages are always sent; they still count as sent.
+var planLimitedEventTypes = map[string]bool{
+	models.EventTypeOrderPaidStaff:    true,
+	models.EventTypeOrderPaidCustomer: true,
+	"order.invoice":                   true,
+	"cart.abandoned":                  true,
+	"order.sla_breached":              true,
+}
+
+// holdOverPlanLimit cancels a limited notification when the tenant's plan allows no more this
+// billing period, and returns ErrNotificationLimitReached for it
+// The limit is not enforced when it cannot be checked; a lost receipt is worse than one over the limit.
+func (s *NotificationService) holdOverPlanLimit(ctx context.Context, notification *models.Notification) error {
+	eventType, _ := notification.Metadata["event_type"].(string)
+	if !planLimitedEventTypes[eventType] {
+		return nil
+	}
+
+	planName, max, sent, err := s.repo.NotificationPlanUsage(ctx, notification.TenantID)
+	if err != nil {
+		log.Printf("Failed to check notification limit for tenant %s: %v", notification.TenantID, err)
+		return nil
+	}
+	if max == nil || sent < *max {
+		return nil
+	}
+
+	errorMsg := fmt.Sprintf("the %s plan allows %d notifications per month; upgrade the plan to send more", planName, *max)
+	notification.Status = models.NotificationStatusCancelled
+	notification.ErrorMsg = &errorMsg
+	if err := s.repo.UpdateStatus(ctx, notification.ID, notification.Status, nil, nil, notification.ErrorMsg); err != nil {
+		log.Printf("Failed to update notification status: %v", err)
+	}
+
+	log.Printf("[PLAN_LIMIT] ID=%s Tenant=%s EventType=%s held back: %s", notification.ID, notification.TenantID, eventType, errorMsg)
+	s.trackMetric("notification.plan_limited", 1, map[string]string{"event_type": eventType})
+	return ErrNotificationLimitReached
+}
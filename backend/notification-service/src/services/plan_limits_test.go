@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pos/notification-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoldOverPlanLimit_AccountMessagesAreNeverMetered(t *testing.T) {
+	// No repository: a metered notification would need one to count the tenant's usage
+	s := &NotificationService{}
+
+	for _, eventType := range []string{"password.reset_requested", "security.new_device_login", "invitation.created", "order.payment_instructions", ""} {
+		notification := &models.Notification{
+			TenantID: "tenant-1",
+			Metadata: map[string]interface{}{"event_type": eventType},
+		}
+		assert.NoError(t, s.holdOverPlanLimit(context.Background(), notification), eventType)
+	}
+}
+
+func TestPlanLimitedEventTypes(t *testing.T) {
+	for _, eventType := range []string{"order.paid.staff", "order.paid.customer", "order.invoice", "cart.abandoned", "order.sla_breached"} {
+		assert.True(t, planLimitedEventTypes[eventType], eventType)
+	}
+}
@@ -66,6 +66,7 @@ func (w *RetryWorker) processFailedNotifications(ctx context.Context) {
 		       metadata, sent_at, failed_at, error_msg, retry_count, created_at, updated_at
 		FROM notifications
 		WHERE status = 'failed'
+		  AND type <> 'sms' -- Text messages carry short-lived verification codes; guests request a new one
 		  AND retry_count < 3
 		  AND (
 		    (retry_count = 0 AND failed_at < $1) OR  -- 1st retry after 1 minute
@@ -127,7 +128,7 @@ func (w *RetryWorker) processFailedNotifications(ctx context.Context) {
 		var retryErr error
 		switch notification.Type {
 		case models.NotificationTypeEmail:
-			retryErr = w.service.sendEmail(ctx, &notification)
+			retryErr = w.retryEmail(ctx, &notification)
 		case models.NotificationTypePush:
 			// TODO: Implement push retry
 			log.Printf("Push notification retry not yet implemented")
@@ -152,3 +153,20 @@ func (w *RetryWorker) processFailedNotifications(ctx context.Context) {
 		log.Printf("Retry worker processed %d notifications", retryCount)
 	}
 }
+
+// retryEmail resends a failed email while holding its send lock
+// Every replica runs a retry worker over the same table, so the notification is reloaded
+// under the lock and skipped if another replica retried it since this batch was queried.
+func (w *RetryWorker) retryEmail(ctx context.Context, notification *models.Notification) error {
+	return w.service.withSendLock(ctx, notificationSendLockKey(notification.ID), func(ctx context.Context) error {
+		current, err := w.repo.FindByID(ctx, notification.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reload notification: %w", err)
+		}
+		if current.Status != models.NotificationStatusFailed || !current.UpdatedAt.Equal(notification.UpdatedAt) {
+			log.Printf("Notification %s was already retried by another replica - skipping", notification.ID)
+			return nil
+		}
+		return w.service.sendEmail(ctx, notification)
+	})
+}
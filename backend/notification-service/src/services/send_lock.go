@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SendLockTTL bounds how long one replica may hold a send lock
+// It must outlast a full send attempt (recipient lookup, rendering and SMTP for every
+// recipient) so a slow replica is never overtaken, yet free the event soon after a crash.
+const SendLockTTL = 2 * time.Minute
+
+// SendLock is a distributed mutex that lets only one replica attempt a send at a time
+type SendLock interface {
+	// Acquire takes the lock for key; acquired is false if another holder has it.
+	// The returned token must be passed to Release.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (token string, acquired bool, err error)
+	// Release frees the lock if it is still held with token
+	Release(ctx context.Context, key, token string) error
+}
+
+// RedisSendLock implements SendLock with SET NX and a token-checked delete
+type RedisSendLock struct {
+	client *redis.Client
+}
+
+// NewRedisSendLock creates a send lock backed by the shared Redis instance
+func NewRedisSendLock(client *redis.Client) *RedisSendLock {
+	return &RedisSendLock{client: client}
+}
+
+// releaseSendLockScript deletes the key only if it still holds our token,
+// so a replica whose lock expired cannot free a lock another replica now holds
+var releaseSendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Acquire takes the lock for key if no other replica holds it
+func (l *RedisSendLock) Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token, err := newSendLockToken()
+	if err != nil {
+		return "", false, err
+	}
+
+	acquired, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to acquire send lock: %w", err)
+	}
+	return token, acquired, nil
+}
+
+// Release frees the lock if it is still held with token
+func (l *RedisSendLock) Release(ctx context.Context, key, token string) error {
+	if err := releaseSendLockScript.Run(ctx, l.client, []string{key}, token).Err(); err != nil {
+		return fmt.Errorf("failed to release send lock: %w", err)
+	}
+	return nil
+}
+
+func newSendLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate send lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// orderPaidSendLockKey is the lock guarding the notifications for one paid transaction
+func orderPaidSendLockKey(tenantID, transactionID string) string {
+	return fmt.Sprintf("notification:send-lock:order-paid:%s:%s", tenantID, transactionID)
+}
+
+// notificationSendLockKey is the lock guarding a retry of one stored notification
+func notificationSendLockKey(notificationID string) string {
+	return "notification:send-lock:notification:" + notificationID
+}
+
+// withSendLock runs send while holding the distributed lock for key
+// The idempotency check must happen inside send: the lock only makes check-then-send
+// atomic across replicas. When another replica holds the lock the event is already being
+// handled there, so it is skipped. If Redis is unavailable, send still runs and the
+// database check alone guards against duplicates, as with a single replica.
+func (s *NotificationService) withSendLock(ctx context.Context, key string, send func(ctx context.Context) error) error {
+	if s.sendLock == nil {
+		return send(ctx)
+	}
+
+	token, acquired, err := s.sendLock.Acquire(ctx, key, SendLockTTL)
+	if err != nil {
+		log.Printf("[SEND_LOCK] Failed to acquire %s, continuing without lock: %v", key, err)
+		return send(ctx)
+	}
+	if !acquired {
+		log.Printf("[SEND_LOCK] %s is held by another replica - skipping", key)
+		s.trackMetric("notification.duplicate.prevented", 1, map[string]string{
+			"reason": "send_lock",
+		})
+		return nil
+	}
+
+	defer func() {
+		// Release even if the caller's context was cancelled mid-send
+		if err := s.sendLock.Release(context.WithoutCancel(ctx), key, token); err != nil {
+			log.Printf("[SEND_LOCK] %v (key %s will expire in %s)", err, key, SendLockTTL)
+		}
+	}()
+
+	return send(ctx)
+}
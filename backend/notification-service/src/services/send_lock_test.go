@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pos/notification-service/src/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySendLock mimics RedisSendLock (SET NX PX plus token-checked delete) in memory
+type memorySendLock struct {
+	mu    sync.Mutex
+	locks map[string]memorySendLockEntry
+	seq   int
+}
+
+type memorySendLockEntry struct {
+	token     string
+	expiresAt time.Time
+}
+
+func newMemorySendLock() *memorySendLock {
+	return &memorySendLock{locks: make(map[string]memorySendLockEntry)}
+}
+
+func (l *memorySendLock) Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, ok := l.locks[key]; ok && time.Now().Before(entry.expiresAt) {
+		return "", false, nil
+	}
+	l.seq++
+	token := fmt.Sprintf("token-%d", l.seq)
+	l.locks[key] = memorySendLockEntry{token: token, expiresAt: time.Now().Add(ttl)}
+	return token, true, nil
+}
+
+func (l *memorySendLock) Release(ctx context.Context, key, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, ok := l.locks[key]; ok && entry.token == token {
+		delete(l.locks, key)
+	}
+	return nil
+}
+
+// failingSendLock simulates Redis being unreachable
+type failingSendLock struct{}
+
+func (failingSendLock) Acquire(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	return "", false, errors.New("connection refused")
+}
+
+func (failingSendLock) Release(ctx context.Context, key, token string) error {
+	return nil
+}
+
+// countingEmailProvider records every email handed to the provider
+type countingEmailProvider struct {
+	sent  atomic.Int32
+	delay time.Duration
+}
+
+func (p *countingEmailProvider) Send(to, subject, body string, isHTML bool, attachments ...providers.Attachment) error {
+	time.Sleep(p.delay)
+	p.sent.Add(1)
+	return nil
+}
+
+// sentStore stands in for the notifications table checked by HasSentOrderNotification
+type sentStore struct {
+	mu   sync.Mutex
+	sent map[string]bool
+}
+
+func (s *sentStore) has(transactionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sent[transactionID]
+}
+
+func (s *sentStore) mark(transactionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent[transactionID] = true
+}
+
+// handleOrderPaidOn runs the same check-then-send sequence as handleOrderPaid under the send lock
+func handleOrderPaidOn(replica *NotificationService, store *sentStore, tenantID, transactionID string) error {
+	ctx := context.Background()
+	return replica.withSendLock(ctx, orderPaidSendLockKey(tenantID, transactionID), func(ctx context.Context) error {
+		if store.has(transactionID) {
+			return nil
+		}
+		if err := replica.emailProvider.Send("owner@example.com", "New Order Paid", "body", true); err != nil {
+			return err
+		}
+		store.mark(transactionID)
+		return nil
+	})
+}
+
+func TestWithSendLock_ExactlyOneEmailAcrossReplicas(t *testing.T) {
+	lock := newMemorySendLock()
+	provider := &countingEmailProvider{delay: 20 * time.Millisecond}
+	store := &sentStore{sent: make(map[string]bool)}
+
+	replicas := make([]*NotificationService, 3)
+	for i := range replicas {
+		replicas[i] = &NotificationService{emailProvider: provider, sendLock: lock}
+	}
+
+	// Each replica receives the same event (e.g. redelivered during a rebalance) several times
+	var wg sync.WaitGroup
+	for _, replica := range replicas {
+		for attempt := 0; attempt < 5; attempt++ {
+			wg.Add(1)
+			go func(replica *NotificationService) {
+				defer wg.Done()
+				assert.NoError(t, handleOrderPaidOn(replica, store, "tenant-1", "TXN-1"))
+			}(replica)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), provider.sent.Load())
+	assert.Empty(t, lock.locks, "lock must be released after the send")
+}
+
+func TestWithSendLock_DistinctEventsAreNotSerialized(t *testing.T) {
+	lock := newMemorySendLock()
+	provider := &countingEmailProvider{delay: 10 * time.Millisecond}
+	store := &sentStore{sent: make(map[string]bool)}
+
+	replicas := make([]*NotificationService, 3)
+	for i := range replicas {
+		replicas[i] = &NotificationService{emailProvider: provider, sendLock: lock}
+	}
+
+	var wg sync.WaitGroup
+	for i, replica := range replicas {
+		wg.Add(1)
+		go func(replica *NotificationService, transactionID string) {
+			defer wg.Done()
+			assert.NoError(t, handleOrderPaidOn(replica, store, "tenant-1", transactionID))
+		}(replica, fmt.Sprintf("TXN-%d", i))
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(3), provider.sent.Load())
+}
+
+func TestWithSendLock_SequentialRedeliveryIsDeduplicated(t *testing.T) {
+	lock := newMemorySendLock()
+	provider := &countingEmailProvider{}
+	store := &sentStore{sent: make(map[string]bool)}
+	replica := &NotificationService{emailProvider: provider, sendLock: lock}
+
+	require.NoError(t, handleOrderPaidOn(replica, store, "tenant-1", "TXN-1"))
+	require.NoError(t, handleOrderPaidOn(replica, store, "tenant-1", "TXN-1"))
+
+	assert.Equal(t, int32(1), provider.sent.Load())
+}
+
+func TestWithSendLock_RedisUnavailableStillSends(t *testing.T) {
+	provider := &countingEmailProvider{}
+	store := &sentStore{sent: make(map[string]bool)}
+	replica := &NotificationService{emailProvider: provider, sendLock: failingSendLock{}}
+
+	require.NoError(t, handleOrderPaidOn(replica, store, "tenant-1", "TXN-1"))
+	require.NoError(t, handleOrderPaidOn(replica, store, "tenant-1", "TXN-1"))
+
+	assert.Equal(t, int32(1), provider.sent.Load())
+}
+
+func TestWithSendLock_SendErrorReleasesLock(t *testing.T) {
+	lock := newMemorySendLock()
+	replica := &NotificationService{sendLock: lock}
+	key := orderPaidSendLockKey("tenant-1", "TXN-1")
+
+	err := replica.withSendLock(context.Background(), key, func(ctx context.Context) error {
+		return errors.New("smtp down")
+	})
+	assert.Error(t, err)
+
+	_, acquired, err := lock.Acquire(context.Background(), key, SendLockTTL)
+	require.NoError(t, err)
+	assert.True(t, acquired, "a failed send must not keep the event locked")
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pos/notification-service/src/models"
+)
+
+// Channels a staff member can receive a staff notification on, as in user-service's preference matrix
+const (
+	staffChannelEmail    = "email"
+	staffChannelWhatsApp = "whatsapp"
+	staffChannelPush     = "push"
+	staffChannelInApp    = "in_app"
+)
+
+var staffChannels = []string{staffChannelEmail, staffChannelWhatsApp, staffChannelPush, staffChannelInApp}
+
+// staffRecipient is a staff member a staff notification goes to and the channels they chose for it
+type staffRecipient struct {
+	UserID   string
+	Email    string
+	Channels []string
+	Locale   string
+	Timezone string // Empty keeps the server's timezone
+}
+
+// staffMessage renders the subject and body of a staff notification for one recipient
+type staffMessage func(recipient staffRecipient) (subject, body string)
+
+// sameStaffMessage sends every recipient the same subject and body
+func sameStaffMessage(subject, body string) staffMessage {
+	return func(staffRecipient) (string, string) {
+		return subject, body
+	}
+}
+
+// defaultStaffPreference is whether a staff member receives an event on a channel they never set
+// Must match models.DefaultNotificationPreference in user-service. Events routed to one of the
+// staff member's teams are also on in-app by default: teams work the floor, at the POS rather than their inbox.
+func defaultStaffPreference(eventType, channel string, teamRouted bool) bool {
+	if teamRouted && channel == staffChannelInApp {
+		return true
+	}
+	return eventType == "refund.approval_requested" && channel == staffChannelEmail
+}
+
+// resolveStaffChannels returns the channels a staff member receives an event on, given the cells they set
+// and whether the event is routed to one of their teams
+func resolveStaffChannels(eventType string, stored map[string]bool, teamRouted bool) []string {
+	channels := make([]string, 0, len(staffChannels))
+	for _, channel := range staffChannels {
+		enabled, ok := stored[channel]
+		if !ok {
+			enabled = defaultStaffPreference(eventType, channel, teamRouted)
+		}
+		if enabled {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// queryStaffRecipients gets the active staff, of the given roles when any, who receive eventType on at least one channel
+// deliveryType is the order's, matched against team routes limited to some delivery types; empty for events not about an order.
+func (s *NotificationService) queryStaffRecipients(ctx context.Context, tenantID, eventType, deliveryType string, roles ...string) ([]staffRecipient, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.id, u.email, u.locale, COALESCE(u.timezone, ''), p.channel, p.enabled,
+			EXISTS (
+				SELECT 1
+				FROM team_members tm
+				JOIN team_notification_routes r ON r.team_id = tm.team_id AND r.event_type = $2
+				WHERE tm.user_id = u.id
+				  AND (cardinality(r.delivery_types) = 0 OR $4 = ANY(r.delivery_types))
+			) AS team_routed
+		FROM users u
+		LEFT JOIN user_notification_preferences p ON p.user_id = u.id AND p.event_type = $2
+		WHERE u.tenant_id = $1
+		  AND u.status = 'active'
+		  AND (cardinality($3::text[]) = 0 OR u.role = ANY($3))
+		ORDER BY u.created_at, u.id
+	`, tenantID, eventType, pq.Array(roles), deliveryType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query staff recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	emails := make(map[string]string)
+	settings := make(map[string]staffRecipient)
+	stored := make(map[string]map[string]bool)
+	teamRouted := make(map[string]bool)
+	for rows.Next() {
+		var id, encryptedEmail, locale, timezone string
+		var channel *string
+		var enabled *bool
+		var routed bool
+		if err := rows.Scan(&id, &encryptedEmail, &locale, &timezone, &channel, &enabled, &routed); err != nil {
+			return nil, fmt.Errorf("failed to scan staff recipient: %w", err)
+		}
+		if _, seen := stored[id]; !seen {
+			userIDs = append(userIDs, id)
+			emails[id] = encryptedEmail
+			settings[id] = staffRecipient{Locale: locale, Timezone: timezone}
+			stored[id] = make(map[string]bool)
+			teamRouted[id] = routed
+		}
+		if channel != nil && enabled != nil {
+			stored[id][*channel] = *enabled
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating staff rows: %w", err)
+	}
+
+	recipients := make([]staffRecipient, 0, len(userIDs))
+	for _, id := range userIDs {
+		channels := resolveStaffChannels(eventType, stored[id], teamRouted[id])
+		if len(channels) == 0 {
+			continue
+		}
+
+		// user:email is the encryption context user-service writes staff emails with
+		email, err := s.encryptor.DecryptWithContext(ctx, emails[id], "user:email")
+		if err != nil {
+			log.Printf("[STAFF_NOTIFY] Failed to decrypt email for user %s: %v", id, err)
+			continue
+		}
+		recipients = append(recipients, staffRecipient{
+			UserID:   id,
+			Email:    email,
+			Channels: channels,
+			Locale:   settings[id].Locale,
+			Timezone: settings[id].Timezone,
+		})
+	}
+
+	log.Printf("[STAFF_NOTIFY] Found %d recipients of %s for tenant %s", len(recipients), eventType, tenantID)
+	return recipients, nil
+}
+
+// notifyStaff delivers a staff notification to each recipient on the channels they chose
+// Staff have no WhatsApp number or push device on file yet, so those channels are skipped.
+// Returns how many deliveries succeeded out of how many were attempted.
+func (s *NotificationService) notifyStaff(ctx context.Context, tenantID string, recipients []staffRecipient, message staffMessage, metadata map[string]interface{}) (int, int) {
+	sent, attempted := 0, 0
+	for _, recipient := range recipients {
+		userID := recipient.UserID
+		subject, body := message(recipient)
+		for _, channel := range recipient.Channels {
+			switch channel {
+			case staffChannelEmail:
+				attempted++
+				notification := &models.Notification{
+					TenantID:  tenantID,
+					UserID:    &userID,
+					Type:      models.NotificationTypeEmail,
+					Status:    models.NotificationStatusPending,
+					Subject:   subject,
+					Body:      body,
+					Recipient: recipient.Email,
+					Metadata:  metadata,
+				}
+				if err := s.repo.Create(ctx, notification); err != nil {
+					log.Printf("[STAFF_NOTIFY] Failed to create email notification for user %s: %v", userID, err)
+					continue
+				}
+				if err := s.sendEmail(ctx, notification); err != nil {
+					log.Printf("[STAFF_NOTIFY] Failed to send email to user %s: %v", userID, err)
+					continue
+				}
+				sent++
+			case staffChannelInApp:
+				attempted++
+				if err := s.storeInApp(ctx, tenantID, userID, subject, body, metadata); err != nil {
+					log.Printf("[STAFF_NOTIFY] Failed to store in-app notification for user %s: %v", userID, err)
+					continue
+				}
+				sent++
+			default:
+				log.Printf("[STAFF_NOTIFY] Skipping %s for user %s: no %s address on file", channel, userID, channel)
+			}
+		}
+	}
+	return sent, attempted
+}
+
+// storeInApp records an in-app notification; it is delivered as soon as it is stored
+func (s *NotificationService) storeInApp(ctx context.Context, tenantID, userID, subject, body string, metadata map[string]interface{}) error {
+	notification := &models.Notification{
+		TenantID:  tenantID,
+		UserID:    &userID,
+		Type:      models.NotificationTypeInApp,
+		Status:    models.NotificationStatusPending,
+		Subject:   subject,
+		Body:      body,
+		Recipient: userID,
+		Metadata:  metadata,
+	}
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.repo.UpdateStatus(ctx, notification.ID, models.NotificationStatusSent, &now, nil, nil)
+}
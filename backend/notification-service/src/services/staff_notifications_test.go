@@ -0,0 +1,65 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestResolveStaffChannels verifies chosen channels win and unset ones fall back to the event's or team route's default
+func TestResolveStaffChannels(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		stored    map[string]bool
+		routed    bool
+		want      []string
+	}{
+		{
+			name:      "order alerts are opt-in",
+			eventType: "order.paid",
+			stored:    nil,
+			want:      []string{},
+		},
+		{
+			name:      "order alerts on chosen channels",
+			eventType: "order.paid",
+			stored:    map[string]bool{"email": true, "in_app": true, "push": false},
+			want:      []string{"email", "in_app"},
+		},
+		{
+			name:      "refund approvals are emailed by default",
+			eventType: "refund.approval_requested",
+			stored:    map[string]bool{"whatsapp": true},
+			want:      []string{"email", "whatsapp"},
+		},
+		{
+			name:      "refund approval email opt-out",
+			eventType: "refund.approval_requested",
+			stored:    map[string]bool{"email": false},
+			want:      []string{},
+		},
+		{
+			name:      "team routes default to in-app",
+			eventType: "order.paid",
+			stored:    nil,
+			routed:    true,
+			want:      []string{"in_app"},
+		},
+		{
+			name:      "team route in-app opt-out",
+			eventType: "order.paid",
+			stored:    map[string]bool{"in_app": false, "email": true},
+			routed:    true,
+			want:      []string{"email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveStaffChannels(tt.eventType, tt.stored, tt.routed)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveStaffChannels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -33,8 +33,8 @@ func (s *TemplateService) LoadTemplate(name string, path string) error {
 }
 
 // renderStaffNotificationTemplate renders the staff notification email template
-func (s *NotificationService) renderStaffNotificationTemplate(data *models.StaffNotificationData) (string, error) {
-	tmpl, ok := s.templates["order_staff_notification"]
+func (s *NotificationService) renderStaffNotificationTemplate(data *models.StaffNotificationData, locale string) (string, error) {
+	tmpl, ok := s.templates[s.localizedTemplate("order_staff_notification", locale)]
 	if !ok {
 		return "", fmt.Errorf("template not found: order_staff_notification")
 	}
@@ -63,7 +63,8 @@ func (s *NotificationService) renderCustomerReceiptTemplate(data *models.Custome
 }
 
 // convertOrderEventToStaffData converts OrderPaidEvent to StaffNotificationData
-func convertOrderEventToStaffData(event *models.OrderPaidEvent) *models.StaffNotificationData {
+// Dates are shown on the wall clock of the staff member's timezone, with month names in their locale.
+func convertOrderEventToStaffData(event *models.OrderPaidEvent, locale, timezone string) *models.StaffNotificationData {
 	items := make([]models.StaffNotificationItem, len(event.Data.Items))
 	for i, item := range event.Data.Items {
 		items[i] = models.StaffNotificationItem{
@@ -79,23 +80,65 @@ func convertOrderEventToStaffData(event *models.OrderPaidEvent) *models.StaffNot
 		deliveryFee = utils.FormatCurrency(event.Data.DeliveryFee)
 	}
 
+	discountAmount := ""
+	if event.Data.DiscountAmount > 0 {
+		discountAmount = utils.FormatCurrency(event.Data.DiscountAmount)
+	}
+
+	promotionDiscount := ""
+	if event.Data.PromotionDiscountAmount > 0 {
+		promotionDiscount = utils.FormatCurrency(event.Data.PromotionDiscountAmount)
+	}
+
+	loyaltyDiscount := ""
+	if event.Data.LoyaltyDiscountAmount > 0 {
+		loyaltyDiscount = utils.FormatCurrency(event.Data.LoyaltyDiscountAmount)
+	}
+
+	serviceCharge, serviceChargeRate := "", ""
+	if event.Data.ServiceChargeAmount > 0 {
+		serviceCharge = utils.FormatCurrency(event.Data.ServiceChargeAmount)
+		serviceChargeRate = utils.FormatPercent(event.Data.ServiceChargeRate)
+	}
+
+	tax, taxRate := "", ""
+	if event.Data.TaxAmount > 0 {
+		tax = utils.FormatCurrency(event.Data.TaxAmount)
+		taxRate = utils.FormatPercent(event.Data.TaxRate)
+	}
+
+	scheduledFor := ""
+	if event.Data.ScheduledFor != nil {
+		scheduledFor = utils.FormatDateTimeIn(*event.Data.ScheduledFor, locale, timezone)
+	}
+
 	return &models.StaffNotificationData{
-		OrderID:         event.Data.OrderID,
-		OrderReference:  event.Data.OrderReference,
-		TransactionID:   event.Data.TransactionID,
-		CustomerName:    event.Data.CustomerName,
-		CustomerEmail:   event.Data.CustomerEmail,
-		CustomerPhone:   event.Data.CustomerPhone,
-		DeliveryType:    event.Data.DeliveryType,
-		DeliveryAddress: event.Data.DeliveryAddress,
-		TableNumber:     event.Data.TableNumber,
-		Items:           items,
-		SubtotalAmount:  utils.FormatCurrency(event.Data.SubtotalAmount),
-		DeliveryFee:     deliveryFee,
-		TotalAmount:     utils.FormatCurrency(event.Data.TotalAmount),
-		PaymentMethod:   event.Data.PaymentMethod,
-		PaidAt:          event.Data.PaidAt.Format("02 January 2006 15:04"),
-		CreatedAt:       event.Data.CreatedAt.Format("02 January 2006 15:04"),
+		OrderID:           event.Data.OrderID,
+		OrderReference:    event.Data.OrderReference,
+		TransactionID:     event.Data.TransactionID,
+		CustomerName:      event.Data.CustomerName,
+		CustomerEmail:     event.Data.CustomerEmail,
+		CustomerPhone:     event.Data.CustomerPhone,
+		DeliveryType:      event.Data.DeliveryType,
+		DeliveryAddress:   event.Data.DeliveryAddress,
+		TableNumber:       event.Data.TableNumber,
+		ScheduledFor:      scheduledFor,
+		Items:             items,
+		SubtotalAmount:    utils.FormatCurrency(event.Data.SubtotalAmount),
+		DeliveryFee:       deliveryFee,
+		DiscountAmount:    discountAmount,
+		VoucherCode:       event.Data.VoucherCode,
+		PromotionDiscount: promotionDiscount,
+		LoyaltyDiscount:   loyaltyDiscount,
+		LoyaltyPoints:     event.Data.LoyaltyPointsRedeemed,
+		ServiceCharge:     serviceCharge,
+		ServiceChargeRate: serviceChargeRate,
+		Tax:               tax,
+		TaxRate:           taxRate,
+		TotalAmount:       utils.FormatCurrency(event.Data.TotalAmount),
+		PaymentMethod:     event.Data.PaymentMethod,
+		PaidAt:            utils.FormatDateTimeIn(event.Data.PaidAt, locale, timezone),
+		CreatedAt:         utils.FormatDateTimeIn(event.Data.CreatedAt, locale, timezone),
 	}
 }
 
@@ -116,6 +159,38 @@ func convertOrderEventToCustomerData(event *models.OrderPaidEvent, frontendURL s
 		deliveryFee = utils.FormatCurrency(event.Data.DeliveryFee)
 	}
 
+	discountAmount := ""
+	if event.Data.DiscountAmount > 0 {
+		discountAmount = utils.FormatCurrency(event.Data.DiscountAmount)
+	}
+
+	promotionDiscount := ""
+	if event.Data.PromotionDiscountAmount > 0 {
+		promotionDiscount = utils.FormatCurrency(event.Data.PromotionDiscountAmount)
+	}
+
+	loyaltyDiscount := ""
+	if event.Data.LoyaltyDiscountAmount > 0 {
+		loyaltyDiscount = utils.FormatCurrency(event.Data.LoyaltyDiscountAmount)
+	}
+
+	serviceCharge, serviceChargeRate := "", ""
+	if event.Data.ServiceChargeAmount > 0 {
+		serviceCharge = utils.FormatCurrency(event.Data.ServiceChargeAmount)
+		serviceChargeRate = utils.FormatPercent(event.Data.ServiceChargeRate)
+	}
+
+	tax, taxRate := "", ""
+	if event.Data.TaxAmount > 0 {
+		tax = utils.FormatCurrency(event.Data.TaxAmount)
+		taxRate = utils.FormatPercent(event.Data.TaxRate)
+	}
+
+	scheduledFor := ""
+	if event.Data.ScheduledFor != nil {
+		scheduledFor = event.Data.ScheduledFor.Format("02 January 2006 15:04")
+	}
+
 	return &models.CustomerReceiptData{
 		OrderReference:    event.Data.OrderReference,
 		CustomerName:      event.Data.CustomerName,
@@ -123,9 +198,19 @@ func convertOrderEventToCustomerData(event *models.OrderPaidEvent, frontendURL s
 		DeliveryType:      event.Data.DeliveryType,
 		DeliveryAddress:   event.Data.DeliveryAddress,
 		TableNumber:       event.Data.TableNumber,
+		ScheduledFor:      scheduledFor,
 		Items:             items,
 		SubtotalAmount:    utils.FormatCurrency(event.Data.SubtotalAmount),
 		DeliveryFee:       deliveryFee,
+		DiscountAmount:    discountAmount,
+		VoucherCode:       event.Data.VoucherCode,
+		PromotionDiscount: promotionDiscount,
+		LoyaltyDiscount:   loyaltyDiscount,
+		LoyaltyPoints:     event.Data.LoyaltyPointsRedeemed,
+		ServiceCharge:     serviceCharge,
+		ServiceChargeRate: serviceChargeRate,
+		Tax:               tax,
+		TaxRate:           taxRate,
 		TotalAmount:       utils.FormatCurrency(event.Data.TotalAmount),
 		PaymentMethod:     event.Data.PaymentMethod,
 		PaidAt:            event.Data.PaidAt.Format("02 January 2006 15:04"),
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/notification-service/src/queue"
+)
+
+// tenantDeletionParticipant is the name tenant-service asks this service by in deletion requests
+const tenantDeletionParticipant = "notification-service"
+
+// tenantEvent is a message on the tenant-events topic
+type tenantEvent struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	TenantID  string          `json:"tenant_id"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// TenantEventHandler takes part in the tenant deletion saga run by tenant-service
+// It deletes the tenant's notification history, which holds its customers' and staff's contact details,
+// and acknowledges with the count. Unsent notifications go too, so the retry worker stops sending them.
+type TenantEventHandler struct {
+	notifications *NotificationService
+	tenantEvents  *queue.KafkaProducer
+}
+
+func NewTenantEventHandler(notifications *NotificationService, tenantEvents *queue.KafkaProducer) *TenantEventHandler {
+	return &TenantEventHandler{
+		notifications: notifications,
+		tenantEvents:  tenantEvents,
+	}
+}
+
+// Handle processes one message from the tenant-events topic; other event types are ignored
+func (h *TenantEventHandler) Handle(ctx context.Context, payload []byte) error {
+	tenantID, deletionID, ok := parseTenantDeletionRequest(payload)
+	if !ok {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"deletion_id": deletionID,
+		"service":     tenantDeletionParticipant,
+		"status":      "completed",
+	}
+	deleted, err := h.notifications.repo.DeleteAllByTenant(ctx, tenantID)
+	if err != nil {
+		log.Printf("Failed to delete notifications of tenant %s: %v", tenantID, err)
+		data["status"] = "failed"
+		data["error"] = err.Error()
+	} else {
+		data["deleted"] = map[string]int64{"notifications": deleted}
+	}
+
+	ack, _ := json.Marshal(data)
+	reply := tenantEvent{
+		EventID:   uuid.New().String(),
+		EventType: "tenant.deletion.acknowledged",
+		TenantID:  tenantID,
+		Data:      ack,
+		Timestamp: time.Now(),
+	}
+	if err := h.tenantEvents.Publish(ctx, tenantID, reply); err != nil {
+		return fmt.Errorf("failed to acknowledge tenant deletion %s: %w", deletionID, err)
+	}
+	return nil
+}
+
+// parseTenantDeletionRequest returns the tenant and deletion of a tenant.deletion.requested event naming
+// this service; ok is false for any other message
+func parseTenantDeletionRequest(payload []byte) (tenantID, deletionID string, ok bool) {
+	var event tenantEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.EventType != "tenant.deletion.requested" {
+		return "", "", false
+	}
+	var req struct {
+		DeletionID string   `json:"deletion_id"`
+		Services   []string `json:"services"`
+	}
+	if err := json.Unmarshal(event.Data, &req); err != nil {
+		return "", "", false
+	}
+	if _, err := uuid.Parse(event.TenantID); err != nil {
+		return "", "", false
+	}
+	for _, service := range req.Services {
+		if service == tenantDeletionParticipant {
+			return event.TenantID, req.DeletionID, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,52 @@
+package services
+
+import "testing"
+
+// TestParseTenantDeletionRequest verifies only deletion requests naming this service are acted on
+func TestParseTenantDeletionRequest(t *testing.T) {
+	const tenantID = "7b1f6c1e-2d4a-4f59-9a57-0c7e3b6f1a2d"
+
+	tests := []struct {
+		name    string
+		payload string
+		ok      bool
+	}{
+		{
+			name:    "request naming this service",
+			payload: `{"event_type":"tenant.deletion.requested","tenant_id":"` + tenantID + `","data":{"deletion_id":"d-1","services":["product-service","notification-service"]}}`,
+			ok:      true,
+		},
+		{
+			name:    "request for other services",
+			payload: `{"event_type":"tenant.deletion.requested","tenant_id":"` + tenantID + `","data":{"deletion_id":"d-1","services":["user-service"]}}`,
+		},
+		{
+			name:    "other tenant event",
+			payload: `{"event_type":"tenant.suspended","tenant_id":"` + tenantID + `","data":{"reason":"fraud"}}`,
+		},
+		{
+			name:    "acknowledgement",
+			payload: `{"event_type":"tenant.deletion.acknowledged","tenant_id":"` + tenantID + `","data":{"deletion_id":"d-1","service":"notification-service"}}`,
+		},
+		{
+			name:    "invalid tenant ID",
+			payload: `{"event_type":"tenant.deletion.requested","tenant_id":"acme","data":{"deletion_id":"d-1","services":["notification-service"]}}`,
+		},
+		{
+			name:    "malformed message",
+			payload: `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTenant, gotDeletion, ok := parseTenantDeletionRequest([]byte(tt.payload))
+			if ok != tt.ok {
+				t.Fatalf("parseTenantDeletionRequest() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && (gotTenant != tenantID || gotDeletion != "d-1") {
+				t.Errorf("parseTenantDeletionRequest() = %q, %q, want %q, %q", gotTenant, gotDeletion, tenantID, "d-1")
+			}
+		})
+	}
+}
@@ -94,6 +94,13 @@ func (ap *AuditPublisher) Publish(ctx context.Context, event *AuditEvent) error
 	// Set service name
 	event.ServiceName = ap.serviceName
 
+	// Link the audit entry to the originating request when the caller did not set one
+	if event.RequestID == nil {
+		if correlationID := CorrelationIDFromContext(ctx); correlationID != "" {
+			event.RequestID = &correlationID
+		}
+	}
+
 	// Validate required fields
 	if err := ap.validateEvent(event); err != nil {
 		return fmt.Errorf("invalid audit event: %w", err)
@@ -131,6 +138,7 @@ func (ap *AuditPublisher) PublishBatch(ctx context.Context, events []*AuditEvent
 	}
 
 	messages := make([]kafka.Message, len(events))
+	correlationID := CorrelationIDFromContext(ctx)
 
 	for i, event := range events {
 		if event == nil {
@@ -150,6 +158,10 @@ func (ap *AuditPublisher) PublishBatch(ctx context.Context, events []*AuditEvent
 		// Set service name
 		event.ServiceName = ap.serviceName
 
+		if event.RequestID == nil && correlationID != "" {
+			event.RequestID = &correlationID
+		}
+
 		// Validate
 		if err := ap.validateEvent(event); err != nil {
 			return fmt.Errorf("invalid audit event at index %d: %w", i, err)
@@ -0,0 +1,26 @@
+package utils
+
+import "context"
+
+// CorrelationIDKafkaHeader is the Kafka message header that carries the correlation ID.
+// It originates from the X-Request-ID assigned by the API gateway.
+const CorrelationIDKafkaHeader = "correlation_id"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID of the originating request
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	if correlationID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context, or ""
+func CorrelationIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -11,6 +12,7 @@ import (
 func GetTemplateFuncMap() template.FuncMap {
 	return template.FuncMap{
 		"formatCurrency": FormatCurrency,
+		"formatPercent":  FormatPercent,
 		"formatDate":     FormatDate,
 		"formatTime":     FormatTime,
 		"upper":          strings.ToUpper,
@@ -40,6 +42,12 @@ func FormatCurrency(amount int) string {
 	return result.String()
 }
 
+// FormatPercent formats a percentage rate without trailing zeros, using a decimal comma
+// Example: 11 -> "11", 2.5 -> "2,5"
+func FormatPercent(rate float64) string {
+	return strings.Replace(strconv.FormatFloat(rate, 'f', -1, 64), ".", ",", 1)
+}
+
 // FormatDate formats a date string to a readable format
 func FormatDate(dateStr string) string {
 	// This is a simple implementation, you may want to parse and format properly
@@ -51,3 +59,24 @@ func FormatTime(timeStr string) string {
 	// This is a simple implementation, you may want to parse and format properly
 	return timeStr
 }
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// FormatDateTimeIn formats a time on the wall clock of a user's timezone, with month names in their locale
+// Example: 2026-03-05T08:30:00Z, "id", "Asia/Jakarta" -> "05 Maret 2026 15:30"
+// An empty or unknown timezone keeps the server's; locales other than "id" get English month names.
+func FormatDateTimeIn(t time.Time, locale, timezone string) string {
+	if loc, err := time.LoadLocation(timezone); err == nil && timezone != "" {
+		t = t.In(loc)
+	} else {
+		t = t.In(time.Local)
+	}
+
+	if locale == "id" {
+		return fmt.Sprintf("%02d %s %d %s", t.Day(), indonesianMonths[t.Month()-1], t.Year(), t.Format("15:04"))
+	}
+	return t.Format("02 January 2006 15:04")
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 )
 
 func TestFormatCurrency(t *testing.T) {
@@ -51,3 +52,75 @@ func TestFormatCurrency(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		expected string
+	}{
+		{
+			name:     "Whole percentage",
+			rate:     11,
+			expected: "11",
+		},
+		{
+			name:     "Fractional percentage",
+			rate:     2.5,
+			expected: "2,5",
+		},
+		{
+			name:     "Zero",
+			rate:     0,
+			expected: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatPercent(tt.rate)
+			if result != tt.expected {
+				t.Errorf("FormatPercent(%v) = %s; want %s", tt.rate, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatDateTimeIn(t *testing.T) {
+	paidAt := time.Date(2026, time.March, 5, 20, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		locale   string
+		timezone string
+		expected string
+	}{
+		{
+			name:     "English in UTC",
+			locale:   "en",
+			timezone: "UTC",
+			expected: "05 March 2026 20:30",
+		},
+		{
+			name:     "Indonesian in Jakarta",
+			locale:   "id",
+			timezone: "Asia/Jakarta",
+			expected: "06 Maret 2026 03:30",
+		},
+		{
+			name:     "English in Makassar",
+			locale:   "en",
+			timezone: "Asia/Makassar",
+			expected: "06 March 2026 04:30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatDateTimeIn(paidAt, tt.locale, tt.timezone)
+			if result != tt.expected {
+				t.Errorf("FormatDateTimeIn(%s, %s) = %s; want %s", tt.locale, tt.timezone, result, tt.expected)
+			}
+		})
+	}
+}
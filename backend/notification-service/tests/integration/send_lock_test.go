@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pos/notification-service/src/services"
+)
+
+// TestRedisSendLockAcrossReplicas checks that three replicas, each with its own Redis
+// connection, send exactly one email for the same event
+func TestRedisSendLockAcrossReplicas(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	redisHost := os.Getenv("REDIS_HOST")
+	if redisHost == "" {
+		t.Skip("REDIS_HOST not set")
+	}
+
+	ctx := context.Background()
+	key := "notification:send-lock:test:" + time.Now().Format("150405.000000000")
+
+	var sent atomic.Int32
+	var alreadySent atomic.Bool
+	var wg sync.WaitGroup
+	for replica := 0; replica < 3; replica++ {
+		client := redis.NewClient(&redis.Options{Addr: redisHost, Password: os.Getenv("REDIS_PASSWORD")})
+		defer client.Close()
+		require.NoError(t, client.Ping(ctx).Err())
+		lock := services.NewRedisSendLock(client)
+
+		for attempt := 0; attempt < 5; attempt++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				token, acquired, err := lock.Acquire(ctx, key, services.SendLockTTL)
+				if !assert.NoError(t, err) || !acquired {
+					return
+				}
+				defer func() {
+					assert.NoError(t, lock.Release(ctx, key, token))
+				}()
+
+				// Check-then-send, as handleOrderPaid does against the notifications table
+				if alreadySent.Load() {
+					return
+				}
+				time.Sleep(20 * time.Millisecond)
+				sent.Add(1)
+				alreadySent.Store(true)
+			}()
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), sent.Load())
+}
+
+// TestRedisSendLockReleaseRequiresToken checks that a replica cannot free a lock it no longer holds
+func TestRedisSendLockReleaseRequiresToken(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	redisHost := os.Getenv("REDIS_HOST")
+	if redisHost == "" {
+		t.Skip("REDIS_HOST not set")
+	}
+
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: redisHost, Password: os.Getenv("REDIS_PASSWORD")})
+	defer client.Close()
+	lock := services.NewRedisSendLock(client)
+	key := "notification:send-lock:test-token:" + time.Now().Format("150405.000000000")
+
+	token, acquired, err := lock.Acquire(ctx, key, services.SendLockTTL)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	require.NoError(t, lock.Release(ctx, key, "stale-token"))
+	_, acquired, err = lock.Acquire(ctx, key, services.SendLockTTL)
+	require.NoError(t, err)
+	assert.False(t, acquired, "a stale token must not release the lock")
+
+	require.NoError(t, lock.Release(ctx, key, token))
+	_, acquired, err = lock.Acquire(ctx, key, time.Second)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
@@ -1,25 +1,47 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 
+	"github.com/point-of-sale-system/order-service/src/middleware"
 	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/projection"
+	"github.com/point-of-sale-system/order-service/src/repository"
 	"github.com/point-of-sale-system/order-service/src/services"
 )
 
 // AdminOrderHandler handles admin order management operations
 type AdminOrderHandler struct {
-	orderService *services.OrderService
+	orderService         *services.OrderService
+	paymentService       *services.PaymentService
+	orderEditService     *services.OrderEditService
+	noteService          *services.OrderNoteService
+	settingsRepo         *repository.OrderSettingsRepository
+	refundService        *services.RefundApprovalService
+	deliveryProofService *services.DeliveryProofService
 }
 
 // NewAdminOrderHandler creates a new admin order handler
-func NewAdminOrderHandler(orderService *services.OrderService) *AdminOrderHandler {
+func NewAdminOrderHandler(orderService *services.OrderService, paymentService *services.PaymentService, orderEditService *services.OrderEditService, noteService *services.OrderNoteService, settingsRepo *repository.OrderSettingsRepository, refundService *services.RefundApprovalService, deliveryProofService *services.DeliveryProofService) *AdminOrderHandler {
 	return &AdminOrderHandler{
-		orderService: orderService,
+		orderService:         orderService,
+		paymentService:       paymentService,
+		orderEditService:     orderEditService,
+		noteService:          noteService,
+		settingsRepo:         settingsRepo,
+		refundService:        refundService,
+		deliveryProofService: deliveryProofService,
 	}
 }
 
@@ -57,6 +79,29 @@ func (h *AdminOrderHandler) ListOrders(c echo.Context) error {
 		statusFilter = &status
 	}
 
+	// Anonymized orders are listed by default and flagged with is_anonymized
+	anonymizedFilter := models.AnonymizedFilterInclude
+	if anonymizedParam := c.QueryParam("anonymized"); anonymizedParam != "" {
+		anonymizedFilter = models.AnonymizedFilter(anonymizedParam)
+		if !anonymizedFilter.IsValid() {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid anonymized filter. Must be: include, exclude, or only",
+			})
+		}
+	}
+
+	// Customer contacts are encrypted, so search matches an exact phone number or email
+	var contactSearch *models.ContactSearch
+	if searchParam := c.QueryParam("search"); searchParam != "" {
+		search, err := models.ParseContactSearch(searchParam)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		contactSearch = search
+	}
+
 	// Pagination
 	limit, _ := strconv.Atoi(c.QueryParam("limit"))
 	if limit <= 0 || limit > 100 {
@@ -69,7 +114,7 @@ func (h *AdminOrderHandler) ListOrders(c echo.Context) error {
 	}
 
 	// Get orders
-	orders, err := h.orderService.ListOrdersByTenant(ctx, tenantID, statusFilter, limit, offset)
+	orders, err := h.orderService.ListOrdersByTenant(ctx, tenantID, statusFilter, anonymizedFilter, contactSearch, limit, offset)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -80,15 +125,38 @@ func (h *AdminOrderHandler) ListOrders(c echo.Context) error {
 		})
 	}
 
+	// Cashiers, managers and owners each see a different projection of the order
+	level := projection.ForRole(middleware.GetUserRole(c))
+
+	// Due times come from the scheduled slot or, for ASAP orders, the estimated prep time
+	prepMinutes := 0
+	settings, err := h.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to fetch order settings for due times")
+	} else {
+		prepMinutes = settings.EstimatedPrepTime
+	}
+	now := time.Now()
+
 	// Fetch items and latest note for each order
 	ordersWithItems := make([]map[string]interface{}, 0, len(orders))
 	for _, order := range orders {
+		order.RedactPII()
+
 		items, err := h.orderService.GetOrderItems(ctx, order.ID)
 		if err != nil {
 			log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to fetch order items")
 			items = []models.OrderItem{} // Empty array on error
 		}
 
+		// Item cost is only looked up for owners
+		var costs map[string]int
+		if level == projection.LevelFull {
+			if costs, err = h.orderService.GetOrderItemCosts(ctx, order.ID); err != nil {
+				log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to fetch order item costs")
+			}
+		}
+
 		// Get latest note only
 		notes, notesErr := h.orderService.GetOrderNotes(ctx, order.ID)
 		var latestNote *models.OrderNote
@@ -96,11 +164,20 @@ func (h *AdminOrderHandler) ListOrders(c echo.Context) error {
 			latestNote = notes[0] // Already sorted by created_at DESC
 		}
 
-		ordersWithItems = append(ordersWithItems, map[string]interface{}{
-			"order":       order,
-			"items":       items,
+		entry := map[string]interface{}{
+			"order":       projection.Order(order, level),
+			"items":       projection.OrderItems(items, costs, level),
 			"latest_note": latestNote,
-		})
+			"due_at":      order.DueAt(prepMinutes),
+			"is_late":     order.IsLate(now, prepMinutes),
+		}
+		// Paid ASAP orders are flagged at risk or late against the tenant's SLA targets
+		if settings != nil {
+			if sla, ok := settings.OrderSLAFor(order, items, now); ok {
+				entry["sla"] = sla
+			}
+		}
+		ordersWithItems = append(ordersWithItems, entry)
 	}
 
 	log.Info().
@@ -168,6 +245,122 @@ func (h *AdminOrderHandler) GetOrder(c echo.Context) error {
 		})
 	}
 
+	order.RedactPII()
+
+	// Notes come with presigned URLs for their photo attachments
+	notes, err := h.noteService.GetNotes(ctx, order.ID)
+	if err != nil {
+		log.Warn().Err(err).Str("order_id", orderID).Msg("Failed to fetch order notes")
+		notes = []*models.OrderNote{}
+	}
+
+	// Items carry their kitchen status so staff can hand out a partially ready order
+	items, err := h.orderService.GetOrderItems(ctx, order.ID)
+	if err != nil {
+		log.Warn().Err(err).Str("order_id", orderID).Msg("Failed to fetch order items")
+	}
+	if items == nil {
+		items = []models.OrderItem{}
+	}
+
+	proof, err := h.deliveryProofService.GetProof(ctx, order.ID)
+	if err != nil {
+		log.Warn().Err(err).Str("order_id", orderID).Msg("Failed to fetch proof of delivery")
+	}
+
+	return c.JSON(http.StatusOK, struct {
+		*models.GuestOrder
+		Items         []models.OrderItem        `json:"items"`
+		Fulfillment   models.FulfillmentSummary `json:"fulfillment"`
+		OrderNotes    []*models.OrderNote       `json:"order_notes"`
+		DeliveryProof *models.DeliveryProof     `json:"delivery_proof,omitempty"`
+	}{
+		GuestOrder:    projection.Order(order, projection.ForRole(middleware.GetUserRole(c))),
+		Items:         items,
+		Fulfillment:   models.SummarizeOrderItems(items),
+		OrderNotes:    notes,
+		DeliveryProof: proof,
+	})
+}
+
+// ListArchivedOrders handles GET /admin/orders/archive
+// Returns anonymized orders without customer data for finance and operations
+func (h *AdminOrderHandler) ListArchivedOrders(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	orders, err := h.orderService.ListArchivedOrders(ctx, tenantID, limit, offset)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("Failed to list archived orders")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve archived orders",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"orders": orders,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(orders),
+		},
+	})
+}
+
+// GetArchivedOrder handles GET /admin/orders/archive/:id
+// Returns a single anonymized order with its items and payments
+func (h *AdminOrderHandler) GetArchivedOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	order, err := h.orderService.GetArchivedOrder(ctx, tenantID, orderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, models.ErrOrderNotArchived):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Order is not anonymized; use the standard order endpoint",
+			})
+		}
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to get archived order")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve archived order",
+		})
+	}
+
 	return c.JSON(http.StatusOK, order)
 }
 
@@ -261,6 +454,8 @@ func (h *AdminOrderHandler) UpdateOrderStatus(c echo.Context) error {
 		})
 	}
 
+	h.orderService.AuditStatusChange(ctx, order, newStatus, c.Request().Header.Get("X-User-ID"))
+
 	log.Info().
 		Str("order_id", orderID).
 		Str("order_reference", order.OrderReference).
@@ -273,13 +468,47 @@ func (h *AdminOrderHandler) UpdateOrderStatus(c echo.Context) error {
 	})
 }
 
+// BatchUpdateOrderStatus handles POST /admin/orders/batch-status
+// Transitions up to 100 orders at once and reports the outcome for each order.
+func (h *AdminOrderHandler) BatchUpdateOrderStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.BatchOrderStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+	if err := req.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	userName := c.Request().Header.Get("X-User-Name")
+	if userName == "" {
+		userName = c.Request().Header.Get("X-User-Email")
+	}
+
+	response := h.orderService.BatchUpdateOrderStatus(ctx, tenantID, &req, c.Request().Header.Get("X-User-ID"), userName)
+	return c.JSON(http.StatusOK, response)
+}
+
 // AddOrderNoteRequest represents the request to add a note to an order
 type AddOrderNoteRequest struct {
-	Note string `json:"note" validate:"required,min=1,max=1000"`
+	Note string `json:"note" form:"note" validate:"required,min=1,max=1000"`
 }
 
 // AddOrderNote handles POST /admin/orders/:id/notes
 // Implements T090: Add notes/comments for courier tracking
+// A multipart/form-data request may attach photos as "attachments" files next to the "note" field.
 func (h *AdminOrderHandler) AddOrderNote(c echo.Context) error {
 	ctx := c.Request().Context()
 	orderID := c.Param("id")
@@ -305,6 +534,13 @@ func (h *AdminOrderHandler) AddOrderNote(c echo.Context) error {
 			"error": "Invalid request body",
 		})
 	}
+	uploads, closeUploads, err := noteAttachmentUploads(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	defer closeUploads()
 
 	// Verify tenant ownership
 	order, err := h.orderService.GetOrderByID(ctx, orderID)
@@ -343,8 +579,20 @@ func (h *AdminOrderHandler) AddOrderNote(c echo.Context) error {
 	}
 
 	// Add note
-	err = h.orderService.AddOrderNote(ctx, orderID, req.Note, userName)
+	note, err := h.noteService.AddNote(ctx, order, req.Note, userName, uploads)
 	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrTooManyNoteAttachments),
+			errors.Is(err, models.ErrNoteAttachmentTooLarge),
+			errors.Is(err, models.ErrNoteAttachmentType):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrNoteAttachmentsNotAvailable):
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{
+				"error": err.Error(),
+			})
+		}
 		log.Error().
 			Err(err).
 			Str("order_id", orderID).
@@ -359,8 +607,778 @@ func (h *AdminOrderHandler) AddOrderNote(c echo.Context) error {
 		Str("order_reference", order.OrderReference).
 		Msg("Note added to order by admin")
 
-	return c.JSON(http.StatusCreated, map[string]string{
+	return c.JSON(http.StatusCreated, map[string]interface{}{
 		"message": "Note added successfully",
+		"note":    note,
+	})
+}
+
+// noteAttachmentUploads opens the "attachments" files of a multipart note request
+// The content type is sniffed from each file rather than trusted from the client.
+// The returned func closes the opened files; a JSON request has no attachments.
+func noteAttachmentUploads(c echo.Context) ([]models.NoteAttachmentUpload, func(), error) {
+	noop := func() {}
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return nil, noop, nil
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, noop, errors.New("Invalid multipart form")
+	}
+
+	var uploads []models.NoteAttachmentUpload
+	var files []io.Closer
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	for _, header := range form.File["attachments"] {
+		upload, file, err := openImageUpload(header)
+		if err != nil {
+			closeAll()
+			return nil, noop, errors.New("Invalid attachment")
+		}
+		files = append(files, file)
+		uploads = append(uploads, upload)
+	}
+	return uploads, closeAll, nil
+}
+
+// openImageUpload opens an uploaded file and sniffs its content type from the content
+// The caller closes the returned file once the upload has been stored.
+func openImageUpload(header *multipart.FileHeader) (models.NoteAttachmentUpload, io.Closer, error) {
+	file, err := header.Open()
+	if err != nil {
+		return models.NoteAttachmentUpload{}, nil, err
+	}
+
+	sniff := make([]byte, 512)
+	n, _ := io.ReadFull(file, sniff)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return models.NoteAttachmentUpload{}, nil, err
+	}
+	return models.NoteAttachmentUpload{
+		Filename:    filepath.Base(header.Filename),
+		ContentType: http.DetectContentType(sniff[:n]),
+		Size:        header.Size,
+		Content:     file,
+	}, file, nil
+}
+
+// EditOrderItems handles PUT /admin/orders/:id/items
+// Replaces the items of a PENDING online order; the customer's pending charge is
+// cancelled and a new one is created for the repriced total
+func (h *AdminOrderHandler) EditOrderItems(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var changes models.EditOrderItemsRequest
+	if err := c.Bind(&changes); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	userName := c.Request().Header.Get("X-User-Name")
+	if userName == "" {
+		userName = c.Request().Header.Get("X-User-Email")
+	}
+
+	result, err := h.orderEditService.EditOrderItems(ctx, &services.EditOrderRequest{
+		OrderID:        orderID,
+		TenantID:       tenantID,
+		EditedByUserID: userID,
+		EditedByName:   userName,
+		Changes:        changes,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, models.ErrNoOnlinePayment):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrInvalidOrderEdit):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrOrderNotEditable):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case services.IsStockError(err):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error":   "insufficient stock",
+				"message": err.Error(),
+			})
+		case errors.Is(err, models.ErrVoucherMinSubtotal), errors.Is(err, models.ErrOrderEditBelowLoyaltyDiscount):
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrPaymentMethodNotOfferedByGateway):
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"error": "Payment method is not available for this store",
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to edit order")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to edit order",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// RecordCashPayment handles POST /admin/orders/:id/payments/cash
+// Settles a pending order with cash collected in store, bypassing Midtrans
+func (h *AdminOrderHandler) RecordCashPayment(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	if orderID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "order_id is required",
+		})
+	}
+
+	// Get tenant ID from header (API Gateway injects from session)
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	// Get user ID from header (injected by API Gateway from JWT)
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	// Parse request
+	var req services.CashPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.AmountTendered <= 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "amount_tendered must be greater than 0",
+		})
+	}
+
+	req.OrderID = orderID
+	req.TenantID = tenantID
+	req.RecordedByUserID = userID
+
+	result, err := h.paymentService.ProcessCashPayment(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, services.ErrOrderNotPayable), errors.Is(err, models.ErrSplitAmountExceedsBalance):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrInsufficientTender):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to record cash payment")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to record cash payment",
+		})
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("order_reference", result.OrderReference).
+		Str("recorded_by", userID).
+		Msg("Cash payment recorded by staff")
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// RecordCardPayment handles POST /admin/orders/:id/payments/card
+// Records a card payment taken on an external EDC terminal and closes the order as PAID
+func (h *AdminOrderHandler) RecordCardPayment(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	if orderID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "order_id is required",
+		})
+	}
+
+	// Get tenant ID from header (API Gateway injects from session)
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	// Get user ID from header (injected by API Gateway from JWT)
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	// Parse request
+	var req services.CardPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.OrderID = orderID
+	req.TenantID = tenantID
+	req.RecordedByUserID = userID
+
+	result, err := h.paymentService.ProcessCardPayment(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, services.ErrOrderNotPayable), errors.Is(err, models.ErrSplitAmountExceedsBalance):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrInvalidCardType),
+			errors.Is(err, models.ErrInvalidApprovalCode),
+			errors.Is(err, models.ErrInvalidCardLast4):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to record card payment")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to record card payment",
+		})
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("order_reference", result.OrderReference).
+		Str("recorded_by", userID).
+		Msg("EDC card payment recorded by staff")
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// RecordSplitPayment handles POST /admin/orders/:id/payments/split
+// Records one part of an order paid across several methods (cash, EDC card, or QRIS).
+// The order becomes PAID only when settled parts cover the total.
+func (h *AdminOrderHandler) RecordSplitPayment(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req services.SplitPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.OrderID = orderID
+	req.TenantID = tenantID
+	req.RecordedByUserID = userID
+
+	result, err := h.paymentService.ProcessSplitPayment(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, services.ErrOrderNotPayable), errors.Is(err, models.ErrSplitAmountExceedsBalance):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrInvalidSplitMethod),
+			errors.Is(err, models.ErrInvalidPaymentAmount),
+			errors.Is(err, models.ErrInsufficientTender),
+			errors.Is(err, models.ErrInvalidCardType),
+			errors.Is(err, models.ErrInvalidApprovalCode),
+			errors.Is(err, models.ErrInvalidCardLast4):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Str("method", string(req.Method)).
+			Msg("Failed to record split payment")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to record split payment",
+		})
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("order_reference", result.OrderReference).
+		Str("method", string(req.Method)).
+		Int("outstanding", result.Outstanding).
+		Str("recorded_by", userID).
+		Msg("Split payment part recorded by staff")
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetPaymentSummary handles GET /admin/orders/:id/payments
+// Lists every payment part applied to the order with the remaining balance
+func (h *AdminOrderHandler) GetPaymentSummary(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	summary, err := h.paymentService.GetPaymentSummary(ctx, orderID, tenantID)
+	if err != nil {
+		if errors.Is(err, services.ErrOrderNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		}
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to get payment summary")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve payments",
+		})
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
+
+// RefundOnlinePayment handles POST /admin/orders/:id/payments/refund
+// Refunds a settled online payment through the gateway that charged it when the caller's
+// role may approve a refund of that size; otherwise files a refund request (202) for an approver
+func (h *AdminOrderHandler) RefundOnlinePayment(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req services.RefundRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "reason is required",
+		})
+	}
+
+	req.OrderID = orderID
+	req.TenantID = tenantID
+
+	approval, refund, err := h.refundService.RequestRefund(ctx, &req, refundActor(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, models.ErrNoOnlinePayment):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrPaymentNotSettled):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrRefundNotSupported):
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrInvalidPaymentAmount):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrRefundAlreadyRequested):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrRefundExceedsPayment):
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to refund online payment")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to refund payment",
+		})
+	}
+
+	if refund == nil {
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"refund_request": approval,
+		})
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("gateway", string(refund.Gateway)).
+		Str("refund_id", refund.RefundID).
+		Str("requested_by", userID).
+		Msg("Online payment refund requested by staff")
+
+	return c.JSON(http.StatusOK, refund)
+}
+
+// GetGatewayPaymentStatus handles GET /admin/orders/:id/payments/gateway-status
+// Queries the payment gateway for the live status of the order's online payment
+func (h *AdminOrderHandler) GetGatewayPaymentStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	status, err := h.paymentService.GetGatewayPaymentStatus(ctx, orderID, tenantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, models.ErrNoOnlinePayment):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to get gateway payment status")
+		return c.JSON(http.StatusBadGateway, map[string]string{
+			"error": "Failed to retrieve payment status from gateway",
+		})
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+// ReconcilePayment handles POST /admin/orders/:id/payments/reconcile
+// Re-checks the order's online payment with the gateway and applies a settled or
+// failed status whose webhook never arrived
+func (h *AdminOrderHandler) ReconcilePayment(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	result, err := h.paymentService.ReconcilePayment(ctx, orderID, tenantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, models.ErrNoOnlinePayment):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to reconcile payment with gateway")
+		return c.JSON(http.StatusBadGateway, map[string]string{
+			"error": "Failed to reconcile payment with gateway",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ConfirmPaymentManually handles POST /admin/orders/:id/payments/confirm
+// Marks an order's online payment as paid when the gateway webhook never arrived
+func (h *AdminOrderHandler) ConfirmPaymentManually(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req services.ManualPaymentConfirmationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" || len(req.Reason) > 500 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "reason is required and must be at most 500 characters",
+		})
+	}
+
+	req.OrderID = orderID
+	req.TenantID = tenantID
+	req.ConfirmedBy = c.Request().Header.Get("X-User-Name")
+	if req.ConfirmedBy == "" {
+		req.ConfirmedBy = c.Request().Header.Get("X-User-Email")
+	}
+	if req.ConfirmedBy == "" {
+		req.ConfirmedBy = userID
+	}
+
+	result, err := h.paymentService.ConfirmPaymentManually(ctx, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, models.ErrNoOnlinePayment):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrOrderNotPayable):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to confirm payment manually")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to confirm payment",
+		})
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("order_reference", result.OrderReference).
+		Str("confirmed_by", userID).
+		Msg("Online payment confirmed manually by staff")
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// MarkOrderDisputed handles POST /admin/orders/:id/dispute
+// Disputed orders are excluded from automatic completion until the dispute is cleared
+func (h *AdminOrderHandler) MarkOrderDisputed(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.SetOrderDisputeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	userName := c.Request().Header.Get("X-User-Name")
+	if userName == "" {
+		userName = c.Request().Header.Get("X-User-Email")
+	}
+
+	if err := h.orderService.MarkOrderDisputed(ctx, orderID, tenantID, req.Reason, userName); err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		case errors.Is(err, models.ErrOrderNotDisputable):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrDisputeReasonEmpty):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to mark order as disputed")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to mark order as disputed",
+		})
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("tenant_id", tenantID).
+		Msg("Order marked as disputed by admin")
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Order marked as disputed",
+	})
+}
+
+// ClearOrderDispute handles DELETE /admin/orders/:id/dispute
+func (h *AdminOrderHandler) ClearOrderDispute(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	userName := c.Request().Header.Get("X-User-Name")
+	if userName == "" {
+		userName = c.Request().Header.Get("X-User-Email")
+	}
+
+	if err := h.orderService.ClearOrderDispute(ctx, orderID, tenantID, userName); err != nil {
+		if errors.Is(err, services.ErrOrderNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		}
+
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to clear order dispute")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to clear order dispute",
+		})
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("tenant_id", tenantID).
+		Msg("Order dispute cleared by admin")
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Order dispute cleared",
 	})
 }
 
@@ -373,7 +1391,21 @@ func (h *AdminOrderHandler) RegisterRoutes(e *echo.Echo) {
 	// admin.Use(middleware.JWTAuth()) // To be implemented
 
 	admin.GET("", h.ListOrders)
+	admin.GET("/archive", h.ListArchivedOrders)
+	admin.GET("/archive/:id", h.GetArchivedOrder)
 	admin.GET("/:id", h.GetOrder)
 	admin.PATCH("/:id/status", h.UpdateOrderStatus)
+	admin.POST("/batch-status", h.BatchUpdateOrderStatus)
 	admin.POST("/:id/notes", h.AddOrderNote)
+	admin.PUT("/:id/items", h.EditOrderItems, middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager))
+	admin.POST("/:id/dispute", h.MarkOrderDisputed)
+	admin.DELETE("/:id/dispute", h.ClearOrderDispute)
+	admin.POST("/:id/payments/cash", h.RecordCashPayment)
+	admin.POST("/:id/payments/card", h.RecordCardPayment)
+	admin.POST("/:id/payments/split", h.RecordSplitPayment)
+	admin.GET("/:id/payments", h.GetPaymentSummary)
+	admin.POST("/:id/payments/refund", h.RefundOnlinePayment)
+	admin.GET("/:id/payments/gateway-status", h.GetGatewayPaymentStatus)
+	admin.POST("/:id/payments/reconcile", h.ReconcilePayment)
+	admin.POST("/:id/payments/confirm", h.ConfirmPaymentManually, middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager))
 }
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// BillingHandler serves tenants' subscription plan, invoices and the platform's Midtrans notifications for them
+type BillingHandler struct {
+	billingService *services.BillingService
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(billingService *services.BillingService) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+	}
+}
+
+// billingErrorStatus maps billing errors to HTTP status codes; 0 means unexpected
+func billingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrPlanNotFound), errors.Is(err, models.ErrInvoiceNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrAlreadyOnPlan), errors.Is(err, models.ErrInvoiceNotPayable):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBillingNotConfigured), errors.Is(err, models.ErrPaymentGatewayUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return 0
+}
+
+// billingError writes a billing error response, logging unexpected ones
+func billingError(c echo.Context, tenantID string, err error, message string) error {
+	if status := billingErrorStatus(err); status != 0 {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	log.Error().Err(err).Str("tenant_id", tenantID).Msg(message)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
+
+// ListPlans handles GET /admin/billing/plans
+func (h *BillingHandler) ListPlans(c echo.Context) error {
+	plans, err := h.billingService.ListPlans(c.Request().Context())
+	if err != nil {
+		return billingError(c, c.Request().Header.Get("X-Tenant-ID"), err, "Failed to retrieve plans")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"plans": plans,
+	})
+}
+
+// GetSubscription handles GET /admin/billing/subscription
+func (h *BillingHandler) GetSubscription(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	sub, err := h.billingService.GetSubscription(c.Request().Context(), tenantID)
+	if err != nil {
+		return billingError(c, tenantID, err, "Failed to retrieve subscription")
+	}
+
+	return c.JSON(http.StatusOK, sub)
+}
+
+// ChangePlan handles PUT /admin/billing/subscription
+// Upgrades answer with the invoice to pay before the plan changes.
+func (h *BillingHandler) ChangePlan(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.ChangePlanRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	resp, err := h.billingService.ChangePlan(c.Request().Context(), tenantID, &req)
+	if err != nil {
+		return billingError(c, tenantID, err, "Failed to change plan")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetUsage handles GET /admin/billing/usage
+func (h *BillingHandler) GetUsage(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	usage, err := h.billingService.GetUsage(c.Request().Context(), tenantID)
+	if err != nil {
+		return billingError(c, tenantID, err, "Failed to retrieve usage")
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
+
+// ListInvoices handles GET /admin/billing/invoices
+func (h *BillingHandler) ListInvoices(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	invoices, err := h.billingService.ListInvoices(c.Request().Context(), tenantID)
+	if err != nil {
+		return billingError(c, tenantID, err, "Failed to retrieve invoices")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"invoices": invoices,
+	})
+}
+
+// PayInvoice handles POST /admin/billing/invoices/:id/pay
+// The response's redirect_url is the Snap payment page for this attempt.
+func (h *BillingHandler) PayInvoice(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	invoice, err := h.billingService.PayInvoice(c.Request().Context(), tenantID, c.Param("id"))
+	if err != nil {
+		return billingError(c, tenantID, err, "Failed to start invoice payment")
+	}
+
+	return c.JSON(http.StatusOK, invoice)
+}
+
+// HandleMidtransNotification handles POST /webhooks/payments/midtrans/billing-notification
+// Only charges created by PayInvoice on the platform account are sent here.
+func (h *BillingHandler) HandleMidtransNotification(c echo.Context) error {
+	var notification services.MidtransNotification
+	if err := c.Bind(&notification); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid notification payload",
+		})
+	}
+
+	log.Info().
+		Str("order_id", notification.OrderID).
+		Str("transaction_id", notification.TransactionID).
+		Str("transaction_status", notification.TransactionStatus).
+		Str("payment_type", notification.PaymentType).
+		Str("remote_addr", c.RealIP()).
+		Msg("Received subscription payment notification")
+
+	err := h.billingService.ProcessNotification(c.Request().Context(), &notification)
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusOK, map[string]string{
+			"status": "success",
+		})
+	case errors.Is(err, services.ErrInvalidBillingSignature):
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Invalid signature",
+		})
+	case errors.Is(err, models.ErrInvoiceNotFound):
+		// Not ours to retry
+		return c.JSON(http.StatusOK, map[string]string{
+			"status": "ignored",
+		})
+	}
+
+	// Other failures are retried by Midtrans
+	log.Error().
+		Err(err).
+		Str("order_id", notification.OrderID).
+		Msg("Failed to process subscription payment notification")
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Failed to process notification",
+	})
+}
+
+// RegisterRoutes registers billing routes; plans and invoices are managed by the owner
+func (h *BillingHandler) RegisterRoutes(e *echo.Echo) {
+	owners := middleware.RequireRole(middleware.RoleOwner)
+
+	e.GET("/api/v1/admin/billing/plans", h.ListPlans, owners)
+	e.GET("/api/v1/admin/billing/subscription", h.GetSubscription, owners)
+	e.PUT("/api/v1/admin/billing/subscription", h.ChangePlan, owners)
+	e.GET("/api/v1/admin/billing/usage", h.GetUsage, owners)
+	e.GET("/api/v1/admin/billing/invoices", h.ListInvoices, owners)
+	e.POST("/api/v1/admin/billing/invoices/:id/pay", h.PayInvoice, owners)
+
+	// Public: verified with the platform account's server key
+	e.POST("/api/v1/webhooks/payments/midtrans/billing-notification", h.HandleMidtransNotification)
+}
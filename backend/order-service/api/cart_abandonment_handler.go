@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// CartAbandonmentHandler lets guests opt in to cart reminders and restore a cart from one
+type CartAbandonmentHandler struct {
+	abandonmentService *services.CartAbandonmentService
+	cartService        *services.CartService
+}
+
+// NewCartAbandonmentHandler creates a new cart abandonment handler
+func NewCartAbandonmentHandler(abandonmentService *services.CartAbandonmentService, cartService *services.CartService) *CartAbandonmentHandler {
+	return &CartAbandonmentHandler{
+		abandonmentService: abandonmentService,
+		cartService:        cartService,
+	}
+}
+
+// cartAbandonmentErrorStatus maps cart abandonment errors to HTTP status codes; 0 means unexpected
+func cartAbandonmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrCartContactRequired),
+		errors.Is(err, models.ErrInvalidCartContactEmail),
+		errors.Is(err, models.ErrInvalidCartContactPhone):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrCartResumeNotFound):
+		return http.StatusNotFound
+	}
+	return 0
+}
+
+// SaveContact handles PUT /api/v1/public/:tenantId/cart/contact
+// The guest is reminded at this contact if they leave the cart idle.
+func (h *CartAbandonmentHandler) SaveContact(c echo.Context) error {
+	tenantID := c.Param("tenantId")
+	sessionID := c.Request().Header.Get("X-Session-Id")
+
+	if sessionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
+	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var req models.CartContact
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.abandonmentService.SaveContact(c.Request().Context(), tenantID, cartID, &req); err != nil {
+		if status := cartAbandonmentErrorStatus(err); status != 0 {
+			return echo.NewHTTPError(status, err.Error())
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to save cart contact")
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to save cart contact")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResumeCart handles POST /api/v1/public/:tenantId/cart/resume
+// Restores the cart behind a reminder's link into the guest's current cart.
+func (h *CartAbandonmentHandler) ResumeCart(c echo.Context) error {
+	tenantID := c.Param("tenantId")
+	sessionID := c.Request().Header.Get("X-Session-Id")
+
+	if sessionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
+	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var req models.ResumeCartRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	if _, err := h.abandonmentService.ResumeCart(c.Request().Context(), tenantID, cartID, req.Token); err != nil {
+		if status := cartAbandonmentErrorStatus(err); status != 0 {
+			return echo.NewHTTPError(status, err.Error())
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to resume cart")
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to resume cart")
+	}
+
+	// Return the cart checked against current stock and priced like any other cart read
+	cart, err := h.cartService.GetCart(c.Request().Context(), tenantID, cartID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get cart")
+	}
+	return c.JSON(http.StatusOK, cart)
+}
@@ -1,11 +1,15 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
 	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/repository"
 	"github.com/point-of-sale-system/order-service/src/services"
 )
@@ -16,15 +20,33 @@ type CartHandler struct {
 
 func NewCartHandler() *CartHandler {
 	ttl := time.Duration(config.GetEnvAsInt("CART_SESSION_TTL")) * time.Second
-	cartRepo := repository.NewCartRepository(config.GetRedis(), ttl)
+	customerTTL := time.Duration(config.GetEnvAsInt("CUSTOMER_CART_TTL")) * time.Second
+	cartRepo := repository.NewCartRepository(config.GetRedis(), ttl, customerTTL)
 	reservationRepo := repository.NewReservationRepository(config.GetDB())
-	cartService := services.NewCartService(cartRepo, reservationRepo, config.GetDB())
+	promotionService := services.NewPromotionService(repository.NewPromotionRepository(config.GetDB()))
+	settingsRepo := repository.NewOrderSettingsRepository(config.GetDB())
+	sessionRepo := repository.NewCustomerSessionRepository(config.GetRedis())
+	cartService := services.NewCartService(cartRepo, reservationRepo, promotionService, settingsRepo, sessionRepo, config.GetDB())
 
 	return &CartHandler{
 		cartService: cartService,
 	}
 }
 
+// resolveCartID returns the cart a public request works on: the device session's cart, or
+// with a customer session bearer token, the guest's cross-device cart
+func resolveCartID(c echo.Context, cartService *services.CartService, tenantID, sessionID string) (string, error) {
+	cartID, err := cartService.ResolveCartID(c.Request().Context(), tenantID, sessionID, customerSessionToken(c))
+	if errors.Is(err, models.ErrCustomerSessionRequired) {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to resolve customer cart")
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "failed to get cart")
+	}
+	return cartID, nil
+}
+
 // NewCartHandlerWithService creates a new CartHandler with an existing CartService
 func NewCartHandlerWithService(cartService *services.CartService) *CartHandler {
 	return &CartHandler{
@@ -39,9 +61,13 @@ func (h *CartHandler) GetCart(c echo.Context) error {
 	if sessionID == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
 	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
 
 	// GetCart now automatically validates and adjusts cart items
-	cart, err := h.cartService.GetCart(c.Request().Context(), tenantID, sessionID)
+	cart, err := h.cartService.GetCart(c.Request().Context(), tenantID, cartID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get cart")
 	}
@@ -63,6 +89,10 @@ func (h *CartHandler) AddItem(c echo.Context) error {
 	if sessionID == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
 	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
 
 	var req AddItemRequest
 	if err := c.Bind(&req); err != nil {
@@ -72,12 +102,18 @@ func (h *CartHandler) AddItem(c echo.Context) error {
 	cart, err := h.cartService.AddItem(
 		c.Request().Context(),
 		tenantID,
-		sessionID,
+		cartID,
 		req.ProductID,
 		req.ProductName,
 		req.Quantity,
 		req.UnitPrice,
 	)
+	if errors.Is(err, models.ErrMaxItemsExceeded) {
+		return echo.NewHTTPError(http.StatusBadRequest, map[string]string{
+			"error":   "max_items_exceeded",
+			"message": err.Error(),
+		})
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, map[string]string{
 			"error":   "failed to add item to cart",
@@ -100,6 +136,10 @@ func (h *CartHandler) UpdateItem(c echo.Context) error {
 	if sessionID == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
 	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
 
 	var req UpdateItemRequest
 	if err := c.Bind(&req); err != nil {
@@ -109,10 +149,16 @@ func (h *CartHandler) UpdateItem(c echo.Context) error {
 	cart, err := h.cartService.UpdateItem(
 		c.Request().Context(),
 		tenantID,
-		sessionID,
+		cartID,
 		productID,
 		req.Quantity,
 	)
+	if errors.Is(err, models.ErrMaxItemsExceeded) {
+		return echo.NewHTTPError(http.StatusBadRequest, map[string]string{
+			"error":   "max_items_exceeded",
+			"message": err.Error(),
+		})
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, map[string]string{
 			"error":   "failed to update cart item",
@@ -131,11 +177,15 @@ func (h *CartHandler) RemoveItem(c echo.Context) error {
 	if sessionID == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
 	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
 
 	cart, err := h.cartService.RemoveItem(
 		c.Request().Context(),
 		tenantID,
-		sessionID,
+		cartID,
 		productID,
 	)
 	if err != nil {
@@ -152,8 +202,12 @@ func (h *CartHandler) ClearCart(c echo.Context) error {
 	if sessionID == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
 	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
 
-	if err := h.cartService.ClearCart(c.Request().Context(), tenantID, sessionID); err != nil {
+	if err := h.cartService.ClearCart(c.Request().Context(), tenantID, cartID); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to clear cart")
 	}
 
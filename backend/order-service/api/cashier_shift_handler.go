@@ -0,0 +1,297 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// CashierShiftHandler handles cashier shifts and their cash drawer reports
+type CashierShiftHandler struct {
+	shiftService *services.CashierShiftService
+}
+
+// NewCashierShiftHandler creates a new cashier shift handler
+func NewCashierShiftHandler(shiftService *services.CashierShiftService) *CashierShiftHandler {
+	return &CashierShiftHandler{
+		shiftService: shiftService,
+	}
+}
+
+// cashierShiftErrorStatus maps cashier shift errors to HTTP status codes; 0 means unexpected
+func cashierShiftErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrShiftNotFound), errors.Is(err, models.ErrNoOpenShift),
+		errors.Is(err, services.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidOpeningFloat), errors.Is(err, models.ErrInvalidCountedCash),
+		errors.Is(err, models.ErrInvalidCashRefund), errors.Is(err, models.ErrCashRefundReason):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrShiftAlreadyOpen), errors.Is(err, models.ErrShiftClosed):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrCashRefundExceedsCash):
+		return http.StatusUnprocessableEntity
+	}
+	return 0
+}
+
+// shiftActor reads the staff member from the headers set by the API gateway
+func shiftActor(c echo.Context) services.ShiftActor {
+	name := c.Request().Header.Get("X-User-Name")
+	if name == "" {
+		name = c.Request().Header.Get("X-User-Email")
+	}
+	return services.ShiftActor{
+		UserID: c.Request().Header.Get("X-User-ID"),
+		Name:   name,
+	}
+}
+
+// cashierShiftError writes the error of a cashier shift operation
+func cashierShiftError(c echo.Context, err error, failure string) error {
+	if code := cashierShiftErrorStatus(err); code != 0 {
+		return c.JSON(code, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	log.Error().Err(err).Str("id", c.Param("id")).Msg(failure)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": failure,
+	})
+}
+
+// OpenShift handles POST /admin/shifts/open
+func (h *CashierShiftHandler) OpenShift(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+	actor := shiftActor(c)
+	if actor.UserID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req models.OpenShiftRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	shift, err := h.shiftService.OpenShift(c.Request().Context(), tenantID, &req, actor)
+	if err != nil {
+		return cashierShiftError(c, err, "Failed to open shift")
+	}
+	return c.JSON(http.StatusCreated, shift)
+}
+
+// GetCurrentShift handles GET /admin/shifts/current
+// Returns the caller's open shift with its running cash totals.
+func (h *CashierShiftHandler) GetCurrentShift(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+	actor := shiftActor(c)
+	if actor.UserID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	report, err := h.shiftService.GetCurrentShift(c.Request().Context(), tenantID, actor)
+	if err != nil {
+		return cashierShiftError(c, err, "Failed to retrieve shift")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// RecordCashRefund handles POST /admin/shifts/current/cash-refunds
+// Records cash paid back for a cash-paid order from the caller's drawer.
+func (h *CashierShiftHandler) RecordCashRefund(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+	actor := shiftActor(c)
+	if actor.UserID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req models.CashRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	refund, err := h.shiftService.RecordCashRefund(c.Request().Context(), tenantID, &req, actor)
+	if err != nil {
+		return cashierShiftError(c, err, "Failed to record cash refund")
+	}
+	return c.JSON(http.StatusCreated, refund)
+}
+
+// CloseCurrentShift handles POST /admin/shifts/current/close
+// Compares the cash the caller counted with the cash the drawer should hold.
+func (h *CashierShiftHandler) CloseCurrentShift(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+	actor := shiftActor(c)
+	if actor.UserID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req models.CloseShiftRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	report, err := h.shiftService.CloseCurrentShift(c.Request().Context(), tenantID, &req, actor)
+	if err != nil {
+		return cashierShiftError(c, err, "Failed to close shift")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// ListShifts handles GET /admin/shifts
+// Optional filters: cashier_id, status (open or closed)
+func (h *CashierShiftHandler) ListShifts(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var cashierID *string
+	if param := c.QueryParam("cashier_id"); param != "" {
+		cashierID = &param
+	}
+
+	var status *models.CashierShiftStatus
+	if param := c.QueryParam("status"); param != "" {
+		s := models.CashierShiftStatus(param)
+		switch s {
+		case models.CashierShiftOpen, models.CashierShiftClosed:
+			status = &s
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid status filter",
+			})
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	shifts, err := h.shiftService.ListShifts(c.Request().Context(), tenantID, cashierID, status, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list shifts")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve shifts",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"shifts": shifts,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(shifts),
+		},
+	})
+}
+
+// GetShiftReport handles GET /admin/shifts/:id
+func (h *CashierShiftHandler) GetShiftReport(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	report, err := h.shiftService.GetShiftReport(c.Request().Context(), tenantID, c.Param("id"))
+	if err != nil {
+		return cashierShiftError(c, err, "Failed to retrieve shift")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// CloseShift handles POST /admin/shifts/:id/close
+// Lets a manager close a shift a cashier left open.
+func (h *CashierShiftHandler) CloseShift(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+	actor := shiftActor(c)
+	if actor.UserID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req models.CloseShiftRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	report, err := h.shiftService.CloseShift(c.Request().Context(), tenantID, c.Param("id"), &req, actor)
+	if err != nil {
+		return cashierShiftError(c, err, "Failed to close shift")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// RegisterRoutes registers cashier shift routes
+// Every role works its own drawer; managers see and close everyone's shifts.
+func (h *CashierShiftHandler) RegisterRoutes(e *echo.Echo) {
+	staff := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier)
+	managers := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	e.POST("/api/v1/admin/shifts/open", h.OpenShift, staff)
+	e.GET("/api/v1/admin/shifts/current", h.GetCurrentShift, staff)
+	e.POST("/api/v1/admin/shifts/current/cash-refunds", h.RecordCashRefund, staff)
+	e.POST("/api/v1/admin/shifts/current/close", h.CloseCurrentShift, staff)
+
+	e.GET("/api/v1/admin/shifts", h.ListShifts, managers)
+	e.GET("/api/v1/admin/shifts/:id", h.GetShiftReport, managers)
+	e.POST("/api/v1/admin/shifts/:id/close", h.CloseShift, managers)
+}
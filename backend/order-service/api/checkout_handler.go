@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -19,7 +20,6 @@ import (
 	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/repository"
 	"github.com/point-of-sale-system/order-service/src/services"
-	"github.com/point-of-sale-system/order-service/src/utils"
 	"github.com/point-of-sale-system/order-service/src/validators"
 )
 
@@ -29,11 +29,21 @@ type CheckoutHandler struct {
 	cartService        *services.CartService
 	inventoryService   *services.InventoryService
 	paymentService     *services.PaymentService
+	voucherService     *services.VoucherService
+	promotionService   *services.PromotionService
+	loyaltyService     *services.LoyaltyService
 	geocodingService   *services.GeocodingService
 	deliveryFeeService *services.DeliveryFeeService
 	addressRepo        *repository.AddressRepository
 	settingsRepo       *repository.OrderSettingsRepository
 	guestOrderRepo     *repository.GuestOrderRepository
+	staffHub           *services.StaffOrderHub
+	webhookService     *services.MerchantWebhookService
+	tableService       *services.TableService
+	addressService     *services.CustomerAddressService
+	proofService       *services.DeliveryProofService
+	referenceGenerator *services.OrderReferenceGenerator
+	billingService     *services.BillingService
 	kafkaProducer      interface { // Interface for Kafka producer
 		Publish(ctx context.Context, key string, value interface{}) error
 	}
@@ -48,11 +58,21 @@ func NewCheckoutHandler(
 	cartService *services.CartService,
 	inventoryService *services.InventoryService,
 	paymentService *services.PaymentService,
+	voucherService *services.VoucherService,
+	promotionService *services.PromotionService,
+	loyaltyService *services.LoyaltyService,
 	geocodingService *services.GeocodingService,
 	deliveryFeeService *services.DeliveryFeeService,
 	addressRepo *repository.AddressRepository,
 	settingsRepo *repository.OrderSettingsRepository,
 	guestOrderRepo *repository.GuestOrderRepository,
+	staffHub *services.StaffOrderHub,
+	webhookService *services.MerchantWebhookService,
+	tableService *services.TableService,
+	addressService *services.CustomerAddressService,
+	proofService *services.DeliveryProofService,
+	referenceGenerator *services.OrderReferenceGenerator,
+	billingService *services.BillingService,
 	kafkaProducer interface {
 		Publish(ctx context.Context, key string, value interface{}) error
 	},
@@ -66,11 +86,21 @@ func NewCheckoutHandler(
 		cartService:        cartService,
 		inventoryService:   inventoryService,
 		paymentService:     paymentService,
+		voucherService:     voucherService,
+		promotionService:   promotionService,
+		loyaltyService:     loyaltyService,
 		geocodingService:   geocodingService,
 		deliveryFeeService: deliveryFeeService,
 		addressRepo:        addressRepo,
 		settingsRepo:       settingsRepo,
 		guestOrderRepo:     guestOrderRepo,
+		staffHub:           staffHub,
+		webhookService:     webhookService,
+		tableService:       tableService,
+		addressService:     addressService,
+		proofService:       proofService,
+		referenceGenerator: referenceGenerator,
+		billingService:     billingService,
 		kafkaProducer:      kafkaProducer,
 		consentProducer:    consentProducer,
 	}
@@ -82,9 +112,17 @@ type CheckoutRequest struct {
 	CustomerPhone   string   `json:"customer_phone"`
 	CustomerEmail   *string  `json:"customer_email,omitempty"`
 	DeliveryAddress *string  `json:"delivery_address,omitempty"`
+	SavedAddressID  string   `json:"saved_address_id,omitempty"` // A verified guest's saved address; takes precedence over delivery_address
 	TableNumber     *string  `json:"table_number,omitempty"`
+	TableToken      string   `json:"table_token,omitempty"` // Scanned table QR code; takes precedence over table_number
 	Notes           *string  `json:"notes,omitempty"`
 	Consents        []string `json:"consents"` // Optional consents granted (required consents implicit)
+	PaymentMethod   string   `json:"payment_method,omitempty"` // qris (default), gopay, bank_transfer, credit_card
+	Bank            string   `json:"bank,omitempty"`           // Required for bank_transfer: bca, bni, bri, permata
+	VoucherCode     string   `json:"voucher_code,omitempty"`   // Defaults to the voucher applied on the cart
+	RedeemPoints    int      `json:"redeem_points,omitempty"`  // Loyalty points to pay with
+
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"` // Slot start for a scheduled pickup/delivery; omit for ASAP
 }
 
 type CheckoutResponse struct {
@@ -92,10 +130,32 @@ type CheckoutResponse struct {
 	OrderID        string    `json:"order_id"`
 	Status         string    `json:"status"`
 	Total          int64     `json:"total"`
+	DiscountAmount int64     `json:"discount_amount"`
+	VoucherCode    *string   `json:"voucher_code,omitempty"`
 	DeliveryType   string    `json:"delivery_type"`
+	PaymentMethod  string    `json:"payment_method"`
 	PaymentURL     *string   `json:"payment_url,omitempty"`
 	PaymentToken   *string   `json:"payment_token,omitempty"`
+	DeeplinkURL    *string   `json:"deeplink_url,omitempty"`
+	Bank           *string   `json:"bank,omitempty"`
+	VANumber       *string   `json:"va_number,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
+
+	PaymentFallback    *string `json:"payment_fallback,omitempty"`     // UNPAID_OFFLINE: the gateway was down, pay at the counter or on delivery
+	PaymentRetryQueued bool    `json:"payment_retry_queued,omitempty"` // A QRIS code will appear on the order page once the gateway recovers
+
+	ScheduledFor *time.Time `json:"scheduled_for,omitempty"`
+
+	PromotionDiscount int64                     `json:"promotion_discount"`
+	Promotions        []models.AppliedPromotion `json:"promotions,omitempty"`
+
+	LoyaltyPointsRedeemed int   `json:"loyalty_points_redeemed"`
+	LoyaltyDiscount       int64 `json:"loyalty_discount"`
+
+	ServiceChargeRate float64 `json:"service_charge_rate"`
+	ServiceCharge     int64   `json:"service_charge"`
+	TaxRate           float64 `json:"tax_rate"`
+	Tax               int64   `json:"tax"`
 }
 
 // CreateOrder handles POST /public/checkout/:tenant_id
@@ -118,6 +178,22 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
+	// Identified guests check out their cross-device cart
+	cartID, err := h.cartService.ResolveCartID(ctx, tenantID, sessionID, customerSessionToken(c))
+	if errors.Is(err, models.ErrCustomerSessionRequired) {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error":   "customer_session_expired",
+			"message": err.Error(),
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to resolve customer cart")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   "internal_error",
+			"message": "Failed to retrieve cart",
+		})
+	}
+
 	// Parse request
 	var req CheckoutRequest
 	if err := c.Bind(&req); err != nil {
@@ -127,6 +203,30 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
+	// A verified guest can pick a saved address instead of typing one
+	var savedAddress *models.CustomerAddress
+	if req.SavedAddressID != "" && req.DeliveryType == "delivery" {
+		savedAddress, err = h.addressService.Get(ctx, tenantID, customerSessionToken(c), req.SavedAddressID)
+		switch {
+		case errors.Is(err, models.ErrCustomerSessionRequired):
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error":   "customer_session_expired",
+				"message": err.Error(),
+			})
+		case errors.Is(err, models.ErrCustomerAddressNotFound):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error":   "saved_address_not_found",
+				"message": err.Error(),
+			})
+		case err != nil:
+			log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get saved address")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create order",
+			})
+		}
+		req.DeliveryAddress = &savedAddress.FullAddress
+	}
+
 	// Validate delivery type
 	validDeliveryTypes := map[string]bool{
 		"pickup":   true,
@@ -140,6 +240,30 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
+	// Only pickup and delivery orders can be placed for a later slot
+	if req.ScheduledFor != nil && req.DeliveryType == "dine_in" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_scheduled_time",
+			"message": models.ErrSchedulingNotAllowed.Error(),
+		})
+	}
+
+	// Validate payment method selector (defaults to QRIS for older clients)
+	if req.PaymentMethod == "" {
+		req.PaymentMethod = string(models.CheckoutPaymentQRIS)
+	}
+	req.Bank = strings.ToLower(strings.TrimSpace(req.Bank))
+	if err := models.ValidateCheckoutPayment(models.CheckoutPaymentMethod(req.PaymentMethod), req.Bank); err != nil {
+		message := "Invalid payment method. Must be: qris, gopay, bank_transfer, or credit_card"
+		if errors.Is(err, models.ErrUnsupportedBank) {
+			message = "Invalid bank for bank transfer. Must be: " + strings.Join(models.SupportedVABanks, ", ")
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid_payment_method",
+			"message": message,
+		})
+	}
+
 	// Validate optional consent codes (required consents are implicit)
 	if err := validators.ValidateGuestConsents(req.Consents); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -184,8 +308,42 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
+	// Seat dine-in orders at the scanned or typed table
+	if req.DeliveryType == "dine_in" {
+		tableNumber, err := h.tableService.ResolveCheckoutTable(ctx, tenantID, req.TableToken, req.TableNumber)
+		if err != nil {
+			if errors.Is(err, models.ErrUnknownTable) || errors.Is(err, models.ErrTableInactive) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error":   "invalid_table",
+					"message": err.Error(),
+				})
+			}
+			log.Error().Err(err).
+				Str("tenant_id", tenantID).
+				Msg("Failed to resolve dine-in table")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error":   "validation_failed",
+				"message": "Failed to validate table",
+			})
+		}
+		req.TableNumber = tableNumber
+	}
+
+	// Merchants over their plan's monthly orders stop taking new ones until they upgrade
+	if err := h.billingService.CheckMonthlyOrderLimit(ctx, tenantID); err != nil {
+		var limitErr *models.PlanLimitError
+		if errors.As(err, &limitErr) {
+			return c.JSON(http.StatusForbidden, limitErr.Response())
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to check monthly order limit")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   "validation_failed",
+			"message": "Failed to check order limit",
+		})
+	}
+
 	// Get cart from Redis
-	cart, err := h.getCartFromRedis(ctx, tenantID, sessionID)
+	cart, err := h.getCartFromRedis(ctx, tenantID, cartID)
 	if err != nil {
 		log.Error().Err(err).
 			Str("tenant_id", tenantID).
@@ -204,6 +362,136 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
+	// Get order settings for order limits, delivery fee and charges
+	settings, err := h.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get order settings")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create order",
+		})
+	}
+
+	// Outside opening hours the tenant's policy decides: take the order anyway, refuse it,
+	// or book it into the next slot with room (dine-in cannot be booked, so it is refused)
+	if req.ScheduledFor == nil && !settings.OpenForOrders(time.Now()) {
+		switch settings.ClosedOrderPolicy {
+		case models.ClosedOrderReject:
+			return storeClosed(c, settings)
+		case models.ClosedOrderSchedule:
+			if req.DeliveryType == "dine_in" {
+				return storeClosed(c, settings)
+			}
+			slots, err := h.bookableSlots(ctx, tenantID, settings)
+			if err != nil {
+				log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to find the next open slot")
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to create order",
+				})
+			}
+			slot := models.FirstOpenSlot(slots)
+			if slot == nil {
+				return storeClosed(c, settings)
+			}
+			log.Info().
+				Str("tenant_id", tenantID).
+				Time("scheduled_for", slot.Start).
+				Msg("Store closed - scheduling order into the next open slot")
+			req.ScheduledFor = &slot.Start
+		}
+	}
+
+	// Enforce the tenant's minimum order amount (on the item subtotal) and item limit
+	if err := settings.CheckOrderLimits(models.DeliveryType(req.DeliveryType), cart.GetTotal(), cart.GetItemCount()); err != nil {
+		code := "max_items_exceeded"
+		if errors.Is(err, models.ErrBelowMinOrderAmount) {
+			code = "below_min_order_amount"
+		}
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":            code,
+			"message":          err.Error(),
+			"min_order_amount": settings.MinOrderAmountFor(models.DeliveryType(req.DeliveryType)),
+			"max_items":        settings.MaxItemsPerOrder,
+		})
+	}
+
+	// Apply automatic promotions as of now; the stored cart pricing is never trusted
+	if err := h.promotionService.PriceCart(ctx, cart); err != nil {
+		log.Error().Err(err).
+			Str("tenant_id", tenantID).
+			Msg("Failed to apply promotions")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create order",
+		})
+	}
+
+	// Resolve the voucher (explicit code wins over the one applied on the cart)
+	voucherCode := req.VoucherCode
+	if voucherCode == "" {
+		voucherCode = cart.VoucherCode
+	}
+	subtotal := cart.GetTotal()
+	voucher, discountAmount, err := h.voucherService.PriceCheckout(ctx, tenantID, voucherCode, cart.GetTotalAfterPromotions(), req.CustomerPhone)
+	if err != nil {
+		if voucherErrorStatus(err) != 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error":   "voucher_not_applicable",
+				"message": err.Error(),
+			})
+		}
+		log.Error().Err(err).
+			Str("tenant_id", tenantID).
+			Str("voucher_code", voucherCode).
+			Msg("Failed to price voucher")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create order",
+		})
+	}
+
+	// Loyalty points pay for part of what is left after promotions and the voucher
+	loyaltyAccount, pointsRedeemed, loyaltyDiscount, err := h.loyaltyService.PriceRedemption(
+		ctx, tenantID, customerSessionToken(c), req.CustomerPhone, req.RedeemPoints, cart.GetTotalAfterPromotions()-discountAmount)
+	if err != nil {
+		if errors.Is(err, models.ErrLoyaltyVerificationRequired) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error":   "loyalty_verification_required",
+				"message": err.Error(),
+			})
+		}
+		if loyaltyErrorStatus(err) != 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error":   "loyalty_not_applicable",
+				"message": err.Error(),
+			})
+		}
+		log.Error().Err(err).
+			Str("tenant_id", tenantID).
+			Msg("Failed to price loyalty redemption")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create order",
+		})
+	}
+
+	// Price delivery for the address, before any lock is taken for the slow lookups
+	var deliveryAddress *models.DeliveryAddress
+	if strings.ToLower(req.DeliveryType) == "delivery" {
+		if savedAddress != nil && savedAddress.HasCoordinates() {
+			deliveryAddress, err = h.quoteDeliveryLocation(ctx, tenantID, savedAddress.FullAddress, h.addressService.Location(savedAddress), settings)
+		} else {
+			deliveryAddress, err = h.quoteDeliveryAddress(ctx, tenantID, *req.DeliveryAddress, settings)
+		}
+		if err != nil {
+			if rejection := deliveryRejection(err); rejection != nil {
+				return c.JSON(http.StatusBadRequest, rejection)
+			}
+			log.Error().Err(err).
+				Str("tenant_id", tenantID).
+				Msg("Failed to price delivery")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create order",
+			})
+		}
+	}
+
 	// Begin transaction
 	tx, err := h.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -226,8 +514,8 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
-	// Generate order reference
-	orderReference, err := utils.GenerateOrderReference()
+	// Generate order reference from the tenant's pattern, or GO-XXXXXX when it has none
+	orderReference, err := h.referenceGenerator.Next(ctx, tx, settings)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to generate order reference")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -235,30 +523,56 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
-	// Get order settings for delivery fee
-	settings, err := h.settingsRepo.GetOrCreate(ctx, tenantID)
-	if err != nil {
-		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get order settings")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to create order",
-		})
+	// A scheduled slot must be open, far enough ahead to prepare the order and not fully booked.
+	// The slot lock is held until commit so concurrent checkouts cannot overbook it.
+	if req.ScheduledFor != nil {
+		if err := settings.ValidateScheduledSlot(*req.ScheduledFor, time.Now()); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error":   "invalid_scheduled_time",
+				"message": err.Error(),
+			})
+		}
+
+		booked, err := h.guestOrderRepo.LockScheduledSlot(ctx, tx, tenantID, *req.ScheduledFor)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to check scheduled slot capacity")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create order",
+			})
+		}
+		if settings.SlotFull(booked) {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error":   "slot_full",
+				"message": models.ErrScheduledSlotFull.Error(),
+			})
+		}
+
+		scheduledFor := req.ScheduledFor.UTC()
+		req.ScheduledFor = &scheduledFor
 	}
 
 	// Calculate delivery fee based on delivery type and settings
 	// Only charge delivery fee if enabled in settings and delivery type is delivery
 	deliveryFee := 0
-	if settings.ChargeDeliveryFee && strings.ToLower(req.DeliveryType) == "delivery" {
-		deliveryFee = settings.DefaultDeliveryFee
-		log.Info().
+	if settings.ChargeDeliveryFee && deliveryAddress != nil {
+		deliveryFee = deliveryAddress.CalculatedFee
+		logEvent := log.Info().
 			Str("tenant_id", tenantID).
-			Int("delivery_fee", deliveryFee).
-			Msg("Applying delivery fee from settings")
+			Int("delivery_fee", deliveryFee)
+		if deliveryAddress.ZoneID != nil {
+			logEvent = logEvent.Str("zone_id", *deliveryAddress.ZoneID)
+		}
+		logEvent.Msg("Applying delivery fee")
 	} else if !settings.ChargeDeliveryFee && strings.ToLower(req.DeliveryType) == "delivery" {
 		log.Info().
 			Str("tenant_id", tenantID).
 			Msg("Delivery fee collection disabled - tenant handles fees externally")
 	}
 
+	// Service charge and tax apply to what is paid for the items after promotions and the voucher,
+	// before loyalty points (a means of payment) and the delivery fee
+	serviceCharge, tax := settings.ChargeLines(cart.GetTotalAfterPromotions() - discountAmount)
+
 	// Create order
 	order := &models.GuestOrder{
 		TenantID:       tenantID,
@@ -271,9 +585,23 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		CustomerEmail:  req.CustomerEmail,
 		TableNumber:    req.TableNumber,
 		Notes:          req.Notes,
-		SubtotalAmount: cart.GetTotal(),
+		ScheduledFor:   req.ScheduledFor,
+		SubtotalAmount: subtotal,
 		DeliveryFee:    deliveryFee,
-		TotalAmount:    cart.GetTotal() + deliveryFee,
+		DiscountAmount: discountAmount,
+		TotalAmount:    subtotal - cart.PromotionDiscount - discountAmount + serviceCharge + tax - loyaltyDiscount + deliveryFee,
+
+		PromotionDiscountAmount: cart.PromotionDiscount,
+		LoyaltyPointsRedeemed:   pointsRedeemed,
+		LoyaltyDiscountAmount:   loyaltyDiscount,
+
+		ServiceChargeRate:   settings.ServiceChargePercent,
+		ServiceChargeAmount: serviceCharge,
+		TaxRate:             settings.TaxPercent,
+		TaxAmount:           tax,
+	}
+	if voucher != nil {
+		order.VoucherCode = &voucher.Code
 	}
 
 	// Insert order
@@ -287,6 +615,66 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
+	// Keep the address with the zone and fee it was priced with
+	if deliveryAddress != nil {
+		deliveryAddress.OrderID = orderID
+		if err := h.addressRepo.Create(ctx, tx, deliveryAddress); err != nil {
+			log.Error().Err(err).
+				Str("order_id", orderID).
+				Msg("Failed to save delivery address")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create order",
+			})
+		}
+	}
+
+	// Record the promotions applied to the order
+	if err := h.promotionService.RecordForOrder(ctx, tx, tenantID, orderID, cart.Promotions); err != nil {
+		log.Error().Err(err).
+			Str("order_id", orderID).
+			Msg("Failed to record order promotions")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create order",
+		})
+	}
+
+	// Redeem the voucher in the same transaction so usage limits hold under concurrent checkouts
+	if voucher != nil {
+		if err := h.voucherService.Redeem(ctx, tx, voucher, orderID, req.CustomerPhone, discountAmount); err != nil {
+			if errors.Is(err, models.ErrVoucherUsageLimitReached) || errors.Is(err, models.ErrVoucherCustomerLimitReached) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error":   "voucher_not_applicable",
+					"message": err.Error(),
+				})
+			}
+			log.Error().Err(err).
+				Str("order_id", orderID).
+				Str("voucher_code", voucher.Code).
+				Msg("Failed to redeem voucher")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create order",
+			})
+		}
+	}
+
+	// Spend the points in the same transaction so they cannot be used by two checkouts
+	if loyaltyAccount != nil {
+		if err := h.loyaltyService.Redeem(ctx, tx, loyaltyAccount, orderID, pointsRedeemed); err != nil {
+			if errors.Is(err, models.ErrLoyaltyInsufficientPoints) {
+				return c.JSON(http.StatusConflict, map[string]string{
+					"error":   "loyalty_not_applicable",
+					"message": err.Error(),
+				})
+			}
+			log.Error().Err(err).
+				Str("order_id", orderID).
+				Msg("Failed to redeem loyalty points")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create order",
+			})
+		}
+	}
+
 	// Insert order items
 	for _, item := range cart.Items {
 		orderItem := &models.OrderItem{
@@ -309,8 +697,9 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		}
 	}
 
-	// Create inventory reservations with 15min TTL
-	if err := h.inventoryService.CreateReservations(ctx, tx, orderID, cart.Items); err != nil {
+	// Create inventory reservations held for the tenant's reservation TTL, at least until the payment expires
+	reservationTTL := services.CheckoutReservationTTL(models.CheckoutPaymentMethod(req.PaymentMethod), settings)
+	if err := h.inventoryService.CreateReservations(ctx, tx, orderID, cart.Items, reservationTTL); err != nil {
 		log.Error().Err(err).
 			Str("order_id", orderID).
 			Str("order_reference", orderReference).
@@ -320,40 +709,68 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 	}
 
 	// Clear cart from Redis
-	if err := h.clearCart(ctx, tenantID, sessionID); err != nil {
+	if err := h.clearCart(ctx, tenantID, cartID); err != nil {
 		log.Warn().Err(err).
 			Str("tenant_id", tenantID).
 			Str("session_id", sessionID).
 			Msg("Failed to clear cart after order creation")
 	}
 
-	// Create QRIS charge and get QR code URL (T066)
-	var paymentURL *string
-
+	// Create the online payment for the selected method through the tenant's gateway (T066)
 	// Update order with ID for payment service
 	order.ID = orderID
 	order.CreatedAt = time.Now()
 	// TotalAmount already set correctly with delivery fee
 
-	qrisResp, err := h.paymentService.CreateQRISCharge(ctx, order, cart.Items)
-	if err != nil {
+	payment, err := h.paymentService.CreateCheckoutPayment(ctx, order, cart.Items, services.CheckoutPaymentRequest{
+		Method: models.CheckoutPaymentMethod(req.PaymentMethod),
+		Bank:   req.Bank,
+		Expiry: settings.PaymentExpiry(),
+	})
+	paymentRetryQueued := false
+	if errors.Is(err, models.ErrPaymentGatewayUnavailable) && settings.PaymentOutagePolicy == models.PaymentOutagePayAtCounter {
+		// The gateway is down: take the order anyway and let staff collect payment
+		log.Warn().Err(err).
+			Str("order_id", orderID).
+			Str("order_reference", orderReference).
+			Str("payment_method", req.PaymentMethod).
+			Msg("Payment gateway unavailable - placing order for payment at the counter")
+		paymentRetryQueued, err = h.paymentService.FallBackToManualPayment(ctx, tx, order, models.CheckoutPaymentMethod(req.PaymentMethod), err)
+		if err != nil {
+			log.Error().Err(err).
+				Str("order_id", orderID).
+				Msg("Failed to fall back to manual payment")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to create order",
+			})
+		}
+		payment = &models.PaymentTransaction{} // No online charge yet, so no payment details to return
+	} else if err != nil {
 		log.Error().Err(err).
 			Str("order_id", orderID).
 			Str("order_reference", orderReference).
-			Msg("Failed to create QRIS charge")
+			Str("payment_method", req.PaymentMethod).
+			Msg("Failed to create payment")
+		if errors.Is(err, models.ErrPaymentMethodNotOfferedByGateway) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Payment method is not available for this store",
+			})
+		}
 		// Return error - payment is required to proceed
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create payment",
 		})
 	}
 
-	// Save QRIS payment info to database
-	if err := h.paymentService.SaveQRISPaymentInfo(ctx, tx, orderID, order.TotalAmount, qrisResp); err != nil {
-		log.Error().Err(err).
-			Str("order_id", orderID).
-			Str("transaction_id", qrisResp.TransactionID).
-			Msg("Failed to save QRIS payment info")
-		// Continue - payment was created, info will be saved via webhook
+	// Save payment info to database
+	if order.PaymentFallback == nil {
+		if err := h.paymentService.SaveCheckoutPayment(ctx, tx, payment); err != nil {
+			log.Error().Err(err).
+				Str("order_id", orderID).
+				Str("payment_method", req.PaymentMethod).
+				Msg("Failed to save payment info")
+			// Continue - payment was created, info will be saved via webhook
+		}
 	}
 
 	// Commit transaction
@@ -364,9 +781,22 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		})
 	}
 
-	// Get QR code URL from actions array
-	if len(qrisResp.Actions) > 0 {
-		paymentURL = &qrisResp.Actions[0].URL
+	// Verified guests find the address among their saved ones next time
+	if token := customerSessionToken(c); token != "" && deliveryAddress != nil {
+		if err := h.addressService.Remember(ctx, tenantID, token, deliveryAddress); err != nil {
+			log.Warn().Err(err).Str("order_id", orderID).Msg("Failed to save delivery address for guest")
+		}
+	}
+
+	// Payment URL is the QR code for QRIS, the app deeplink for GoPay and the Snap page for cards
+	var paymentURL *string
+	switch {
+	case payment.QRCodeURL != nil:
+		paymentURL = payment.QRCodeURL
+	case payment.DeeplinkURL != nil:
+		paymentURL = payment.DeeplinkURL
+	case payment.RedirectURL != nil:
+		paymentURL = payment.RedirectURL
 	}
 
 	log.Info().
@@ -375,13 +805,29 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		Str("delivery_type", req.DeliveryType).
 		Int64("total", int64(order.TotalAmount)).
 		Int("delivery_fee", deliveryFee).
-		Str("transaction_id", qrisResp.TransactionID).
-		Str("qr_code_url", *paymentURL).
-		Msg("Order created successfully with QRIS payment")
+		Int("discount_amount", discountAmount).
+		Int("promotion_discount", cart.PromotionDiscount).
+		Int("loyalty_discount", loyaltyDiscount).
+		Str("payment_method", req.PaymentMethod).
+		Msg("Order created successfully with Midtrans payment")
+
+	// Push the new order to the tenant's staff dashboards and webhooks
+	h.staffHub.Publish(ctx, models.NewStaffOrderEvent(models.StaffOrderEventCreated, order))
+	h.webhookService.Publish(ctx, models.WebhookEventOrderCreated, order, nil)
 
 	// Publish invoice notification event if customer provided email
 	if req.CustomerEmail != nil && *req.CustomerEmail != "" {
-		h.publishInvoiceEvent(ctx, orderID, orderReference, tenantID, order, cart.Items, req.CustomerEmail)
+		h.publishInvoiceEvent(ctx, orderID, orderReference, tenantID, order, cart.Items, cart.Promotions, req.CustomerEmail)
+	}
+
+	// Bank transfer guests also get the VA number and payment deadline by email
+	if req.CustomerEmail != nil && *req.CustomerEmail != "" && payment.VANumber != nil {
+		h.publishPaymentInstructionsEvent(ctx, tenantID, order, payment, *req.CustomerEmail)
+	}
+
+	// Staff have to collect payment for orders placed during a gateway outage
+	if order.PaymentFallback != nil {
+		h.publishManualPaymentRequiredEvent(ctx, tenantID, order, req.PaymentMethod, paymentRetryQueued)
 	}
 
 	// Publish ConsentGrantedEvent to Kafka (async, after transaction committed)
@@ -418,15 +864,53 @@ func (h *CheckoutHandler) CreateOrder(c echo.Context) error {
 		}()
 	}
 
+	// Feature usage telemetry: which ordering options tenants' guests actually use
+	usedFeatures := []string{"checkout_" + req.DeliveryType}
+	if req.ScheduledFor != nil {
+		usedFeatures = append(usedFeatures, "scheduled_orders")
+	}
+	if order.PromotionDiscountAmount > 0 {
+		usedFeatures = append(usedFeatures, "promotion_discounts")
+	}
+	if order.LoyaltyPointsRedeemed > 0 {
+		usedFeatures = append(usedFeatures, "loyalty_redemption")
+	}
+	if order.PaymentFallback != nil {
+		usedFeatures = append(usedFeatures, "payment_outage_fallback")
+	}
+	go services.RecordFeatureUsage(h.redisClient, tenantID, usedFeatures...)
+
 	return c.JSON(http.StatusCreated, CheckoutResponse{
 		OrderReference: orderReference,
 		OrderID:        orderID,
 		Status:         "PENDING",
 		Total:          int64(order.TotalAmount),
+		DiscountAmount: int64(order.DiscountAmount),
+		VoucherCode:    order.VoucherCode,
 		DeliveryType:   req.DeliveryType,
+		PaymentMethod:  req.PaymentMethod,
 		PaymentURL:     paymentURL,
-		PaymentToken:   nil, // Not used for QRIS
+		PaymentToken:   payment.SnapToken, // Only set for credit card (Snap popup)
+		DeeplinkURL:    payment.DeeplinkURL,
+		Bank:           payment.Bank,
+		VANumber:       payment.VANumber,
 		CreatedAt:      order.CreatedAt,
+
+		PaymentFallback:    order.PaymentFallback,
+		PaymentRetryQueued: paymentRetryQueued,
+
+		ScheduledFor: order.ScheduledFor,
+
+		PromotionDiscount: int64(order.PromotionDiscountAmount),
+		Promotions:        cart.Promotions,
+
+		LoyaltyPointsRedeemed: order.LoyaltyPointsRedeemed,
+		LoyaltyDiscount:       int64(order.LoyaltyDiscountAmount),
+
+		ServiceChargeRate: order.ServiceChargeRate,
+		ServiceCharge:     int64(order.ServiceChargeAmount),
+		TaxRate:           order.TaxRate,
+		Tax:               int64(order.TaxAmount),
 	})
 }
 
@@ -475,7 +959,7 @@ func (h *CheckoutHandler) validateConditionalFields(req *CheckoutRequest) error
 
 	case "dine_in":
 	// Table number is optional for dine-in
-	// No validation needed
+	// Matched to the tenant's tables after validation
 
 	case "pickup":
 		// No additional fields required for pickup
@@ -492,8 +976,8 @@ func (h *CheckoutHandler) validateDeliveryTypeWithTenant(ctx context.Context, te
 	return true, nil
 }
 
-func (h *CheckoutHandler) getCartFromRedis(ctx context.Context, tenantID, sessionID string) (*models.Cart, error) {
-	key := fmt.Sprintf("cart:%s:%s", tenantID, sessionID)
+func (h *CheckoutHandler) getCartFromRedis(ctx context.Context, tenantID, cartID string) (*models.Cart, error) {
+	key := fmt.Sprintf("cart:%s:%s", tenantID, cartID)
 	data, err := h.redisClient.Get(ctx, key).Result()
 	if err != nil {
 		return nil, err
@@ -537,85 +1021,161 @@ func (h *CheckoutHandler) insertOrderItem(ctx context.Context, tx *sql.Tx, item
 	return err
 }
 
-func (h *CheckoutHandler) clearCart(ctx context.Context, tenantID, sessionID string) error {
-	key := fmt.Sprintf("cart:%s:%s", tenantID, sessionID)
+func (h *CheckoutHandler) clearCart(ctx context.Context, tenantID, cartID string) error {
+	key := fmt.Sprintf("cart:%s:%s", tenantID, cartID)
 	return h.redisClient.Del(ctx, key).Err()
 }
 
-// processDeliveryAddressAndFee handles geocoding and delivery fee calculation for delivery orders
-// Implements T080-T083: Geocode address, validate service area, calculate delivery fee
-func (h *CheckoutHandler) processDeliveryAddressAndFee(
+// deliveryRejection is the response for an address the tenant does not deliver to; nil if err is unexpected
+func deliveryRejection(err error) map[string]string {
+	switch {
+	case errors.Is(err, models.ErrOutsideDeliveryZones):
+		return map[string]string{
+			"error":   "outside_delivery_area",
+			"message": err.Error(),
+		}
+	case errors.Is(err, models.ErrDeliveryAddressNotLocated):
+		return map[string]string{
+			"error":   "address_not_found",
+			"message": err.Error(),
+		}
+	}
+	return nil
+}
+
+// quoteDeliveryAddress geocodes a delivery address and prices it
+// Tenants on flat pricing accept an address that cannot be located, just without coordinates.
+func (h *CheckoutHandler) quoteDeliveryAddress(
 	ctx context.Context,
-	tx *sql.Tx,
-	orderID string,
 	tenantID string,
-	deliveryAddress string,
-	serviceArea *models.ServiceArea,
-	deliveryFeeConfig *services.DeliveryFeeConfig,
-) (int, error) {
-	// T080: Geocode the delivery address
-	geocodingResult, err := h.geocodingService.GeocodeAddress(ctx, deliveryAddress)
+	addressText string,
+	settings *models.OrderSettings,
+) (*models.DeliveryAddress, error) {
+	addressText = strings.TrimSpace(addressText)
+
+	location, err := h.geocodingService.GeocodeAddress(ctx, addressText)
 	if err != nil {
-		return 0, fmt.Errorf("failed to geocode address: %w", err)
+		log.Warn().Err(err).
+			Str("tenant_id", tenantID).
+			Msg("Failed to geocode delivery address")
+		location = nil
 	}
 
-	// T081: Validate service area
-	isWithinArea, distance, err := h.geocodingService.ValidateServiceArea(
-		ctx,
-		geocodingResult.Latitude,
-		geocodingResult.Longitude,
-		serviceArea,
-	)
+	return h.quoteDeliveryLocation(ctx, tenantID, addressText, location, settings)
+}
+
+// quoteDeliveryLocation prices delivery to an address already geocoded; location is nil if it was not located
+func (h *CheckoutHandler) quoteDeliveryLocation(
+	ctx context.Context,
+	tenantID string,
+	addressText string,
+	location *services.GeocodingResult,
+	settings *models.OrderSettings,
+) (*models.DeliveryAddress, error) {
+	quote, err := h.deliveryFeeService.QuoteDelivery(ctx, tenantID, location, settings)
 	if err != nil {
-		return 0, fmt.Errorf("failed to validate service area: %w", err)
+		return nil, err
 	}
 
-	if !isWithinArea {
-		return 0, fmt.Errorf("delivery address is outside service area")
+	address := &models.DeliveryAddress{
+		TenantID:             tenantID,
+		FullAddress:          addressText,
+		ServiceAreaValidated: quote.Method != services.DeliveryPricingFlat,
+		CalculatedFee:        quote.Fee,
+		DistanceKm:           quote.DistanceKm,
 	}
+	if location != nil {
+		address.Latitude = location.Latitude
+		address.Longitude = location.Longitude
+		address.GeocodingResult = &location.FormattedAddress
+	}
+	if quote.Zone != nil {
+		address.ZoneID = &quote.Zone.ID
+		address.ZoneName = &quote.Zone.Name
+	}
+	return address, nil
+}
 
-	// T082: Calculate delivery fee
-	var deliveryFee int
-	var zoneID *string
+// QuoteDeliveryFee handles POST /public/:tenantId/delivery/quote
+// Shows the guest the delivery fee for an address before they confirm checkout.
+func (h *CheckoutHandler) QuoteDeliveryFee(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
 
-	// Determine zone ID if using zone-based pricing
-	if serviceArea.Type == "polygon" {
-		// For polygon areas, we could map coordinates to zone IDs
-		// For now, we'll use nil and let the fee service use distance to centroid
-		zoneID = nil
+	var req struct {
+		DeliveryAddress string `json:"delivery_address"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+	if len(strings.TrimSpace(req.DeliveryAddress)) < 10 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "delivery_address must be at least 10 characters",
+		})
 	}
 
-	if deliveryFeeConfig != nil {
-		deliveryFee, err = h.deliveryFeeService.CalculateFee(ctx, distance, zoneID, deliveryFeeConfig)
-		if err != nil {
-			return 0, fmt.Errorf("failed to calculate delivery fee: %w", err)
+	settings, err := h.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get order settings")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to quote delivery fee",
+		})
+	}
+	if !settings.DeliveryEnabled {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Delivery is not available",
+		})
+	}
+
+	address, err := h.quoteDeliveryAddress(ctx, tenantID, req.DeliveryAddress, settings)
+	if err != nil {
+		if rejection := deliveryRejection(err); rejection != nil {
+			return c.JSON(http.StatusBadRequest, rejection)
 		}
-	} else {
-		// No delivery fee config means free delivery or manual fee setting
-		deliveryFee = 0
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to quote delivery fee")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to quote delivery fee",
+		})
 	}
 
-	// T083: Create delivery_address record with geocoded coordinates and calculated fee
-	deliveryAddressRecord := &models.DeliveryAddress{
-		ID:                   generateUUID(), // Helper function needed
-		OrderID:              orderID,
-		TenantID:             tenantID,
-		FullAddress:          geocodingResult.FormattedAddress,
-		Latitude:             geocodingResult.Latitude,
-		Longitude:            geocodingResult.Longitude,
-		GeocodingResult:      &geocodingResult.FormattedAddress,
-		ServiceAreaValidated: isWithinArea,
-		CalculatedFee:        deliveryFee,
-		DistanceKm:           &distance,
-		ZoneID:               zoneID,
+	// Same rule as checkout: tenants that do not charge delivery quote it as free
+	deliveryFee := 0
+	if settings.ChargeDeliveryFee {
+		deliveryFee = address.CalculatedFee
 	}
 
-	// Save delivery address to database
-	if err := h.addressRepo.Create(ctx, deliveryAddressRecord); err != nil {
-		return 0, fmt.Errorf("failed to create delivery address record: %w", err)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"delivery_fee":      deliveryFee,
+		"distance_km":       address.DistanceKm,
+		"zone_name":         address.ZoneName,
+		"formatted_address": address.GeocodingResult,
+	})
+}
+
+// getColdStoredOrder answers the guest order page for an order moved to cold storage
+// Archived orders are closed and keep no customer data, so only the order and its items are returned.
+func (h *CheckoutHandler) getColdStoredOrder(c echo.Context, orderReference string) error {
+	order, err := repository.NewOrderArchiveRepository(h.db).GetByReference(c.Request().Context(), orderReference)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "order not found",
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("order_reference", orderReference).Msg("Failed to fetch archived order")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to fetch order",
+		})
 	}
 
-	return deliveryFee, nil
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"order":    order,
+		"items":    order.Items,
+		"notes":    []*models.OrderNote{},
+		"archived": true,
+	})
 }
 
 // GetPublicOrder handles GET /public/orders/:orderReference
@@ -639,6 +1199,9 @@ func (h *CheckoutHandler) GetPublicOrder(c echo.Context) error {
 		})
 	}
 	order, err := orderRepo.GetOrderByReference(ctx, orderReference)
+	if errors.Is(err, sql.ErrNoRows) {
+		return h.getColdStoredOrder(c, orderReference)
+	}
 	if err != nil {
 		log.Error().Err(err).Str("order_reference", orderReference).Msg("Failed to fetch order")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -682,6 +1245,22 @@ func (h *CheckoutHandler) GetPublicOrder(c echo.Context) error {
 		"order": order,
 		"items": items,
 		"notes": notes,
+		// Item-level kitchen progress, so the page can show a partially ready order
+		"fulfillment": models.SummarizeOrderItems(items),
+	}
+
+	// A delivered order shows who received it, without the staff details
+	if proof, err := h.proofService.GetProof(ctx, order.ID); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to fetch proof of delivery")
+	} else if proof != nil {
+		response["delivery_proof"] = proof.ForGuest()
+	}
+
+	// Tell the order page whether, and until when, the guest may cancel
+	if settings, err := h.settingsRepo.GetOrCreate(ctx, order.TenantID); err != nil {
+		log.Warn().Err(err).Str("tenant_id", order.TenantID).Msg("Failed to fetch order settings for guest cancellation")
+	} else if deadline, ok := settings.GuestCancelDeadline(order); ok && time.Now().Before(deadline) {
+		response["guest_cancel_until"] = deadline.Format(time.RFC3339)
 	}
 
 	if payment != nil {
@@ -694,7 +1273,7 @@ func (h *CheckoutHandler) GetPublicOrder(c echo.Context) error {
 			expiryTime := payment.ExpiryTime
 			log.Debug().Str("expiry_time", expiryTime.Format(time.RFC3339)).Msg("the payment expiry time for payment expiry calculation")
 
-			remainingTime = max(int64(expiryTime.Add(-7*time.Hour).Sub(now).Seconds()), 0)
+			remainingTime = max(int64(expiryTime.Sub(now).Seconds()), 0)
 			log.Debug().Str("remaining_time", fmt.Sprintf("%d", remainingTime)).Msg("the remaining time for payment expiry")
 
 			// Format as RFC3339 to ensure timezone is preserved
@@ -710,26 +1289,72 @@ func (h *CheckoutHandler) GetPublicOrder(c echo.Context) error {
 			"server_time":        now.Format(time.RFC3339),
 			"remaining_time":     remainingTime,
 			"payment_type":       payment.PaymentType,
+			"payment_method":     payment.PaymentMethod,
+			"deeplink_url":       payment.DeeplinkURL,
+			"redirect_url":       payment.RedirectURL,
+			"bank":               payment.Bank,
+			"va_number":          payment.VANumber,
+			"expired":            payment.ExpiryTime != nil && now.After(*payment.ExpiryTime),
 		}
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
-// getTenantDeliveryConfig fetches service area and delivery fee configuration from tenant service
-// This is a placeholder that should be replaced with actual tenant-service API call
-func (h *CheckoutHandler) getTenantDeliveryConfig(ctx context.Context, tenantID string) (*models.ServiceArea, *services.DeliveryFeeConfig, error) {
-	// TODO: Implement actual HTTP call to tenant-service
-	// GET /api/v1/tenants/:tenant_id/delivery-config
-	// For now, return nil to indicate no automatic delivery fee calculation
-	return nil, nil, nil
+// GetScheduleSlots handles GET /public/:tenantId/schedule/slots
+// Lists the pickup/delivery slots a guest can book now, with remaining capacity when limited.
+func (h *CheckoutHandler) GetScheduleSlots(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	settings, err := h.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get order settings")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve slots",
+		})
+	}
+
+	slots, err := h.bookableSlots(ctx, tenantID, settings)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to count scheduled orders")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve slots",
+		})
+	}
+
+	now := time.Now()
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"scheduling_enabled":    settings.SchedulingEnabled,
+		"timezone":              settings.Location().String(),
+		"slot_duration_minutes": settings.SlotDurationMinutes,
+		"open_now":              settings.OpenForOrders(now),
+		"next_opening_at":       settings.NextOpening(now),
+		"slots":                 slots,
+	})
 }
 
-// generateUUID generates a UUID for delivery address
-// This should use a proper UUID library like github.com/google/uuid
-func generateUUID() string {
-	// Placeholder - should use proper UUID generation
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+// bookableSlots lists the tenant's slots from now with remaining capacity filled in when limited
+func (h *CheckoutHandler) bookableSlots(ctx context.Context, tenantID string, settings *models.OrderSettings) ([]models.TimeSlot, error) {
+	slots := settings.Slots(time.Now())
+	if len(slots) > 0 && settings.SlotCapacity > 0 {
+		booked, err := h.guestOrderRepo.CountScheduledBySlot(ctx, tenantID, slots[0].Start, slots[len(slots)-1].End)
+		if err != nil {
+			return nil, err
+		}
+		settings.FillRemaining(slots, booked)
+	}
+	return slots, nil
+}
+
+// storeClosed refuses a checkout placed outside opening hours, telling the guest when ordering reopens
+func storeClosed(c echo.Context, settings *models.OrderSettings) error {
+	return c.JSON(http.StatusBadRequest, map[string]interface{}{
+		"error":           "store_closed",
+		"message":         models.ErrStoreClosed.Error(),
+		"next_opening_at": settings.NextOpening(time.Now()),
+		"timezone":        settings.Location().String(),
+	})
 }
 
 // publishInvoiceEvent publishes an invoice notification event to Kafka
@@ -740,6 +1365,7 @@ func (h *CheckoutHandler) publishInvoiceEvent(
 	tenantID string,
 	order *models.GuestOrder,
 	items []models.CartItem,
+	promotions []models.AppliedPromotion,
 	customerEmail *string,
 ) {
 	if h.kafkaProducer == nil {
@@ -758,6 +1384,23 @@ func (h *CheckoutHandler) publishInvoiceEvent(
 		})
 	}
 
+	invoicePromotions := make([]map[string]interface{}, 0, len(promotions))
+	for _, p := range promotions {
+		invoicePromotions = append(invoicePromotions, map[string]interface{}{
+			"name":            p.Name,
+			"discount_amount": p.DiscountAmount,
+		})
+	}
+
+	// Tenants can have the PDF invoice attached; the notification service downloads it
+	attachInvoicePDF := false
+	branding, err := repository.NewInvoiceBrandingRepository(h.db).GetByTenantID(ctx, tenantID)
+	if err != nil {
+		log.Warn().Err(err).Str("order_reference", orderReference).Msg("Sending invoice email without PDF")
+	} else {
+		attachInvoicePDF = branding.AttachInvoicePDF
+	}
+
 	// Create event payload
 	event := map[string]interface{}{
 		"event_type": "order.invoice",
@@ -771,9 +1414,21 @@ func (h *CheckoutHandler) publishInvoiceEvent(
 			"delivery_type":   order.DeliveryType,
 			"subtotal_amount": order.SubtotalAmount,
 			"delivery_fee":    order.DeliveryFee,
+			"discount_amount": order.DiscountAmount,
+			"voucher_code":    order.VoucherCode,
 			"total_amount":    order.TotalAmount,
+
+			"loyalty_points_redeemed": order.LoyaltyPointsRedeemed,
+			"loyalty_discount_amount": order.LoyaltyDiscountAmount,
+			"service_charge_rate":     order.ServiceChargeRate,
+			"service_charge_amount":   order.ServiceChargeAmount,
+			"tax_rate":                order.TaxRate,
+			"tax_amount":              order.TaxAmount,
 			"items":           orderItems,
+			"promotions":      invoicePromotions,
 			"created_at":      order.CreatedAt.Format(time.RFC3339),
+
+			"attach_invoice_pdf": attachInvoicePDF,
 		},
 	}
 
@@ -790,3 +1445,92 @@ func (h *CheckoutHandler) publishInvoiceEvent(
 			Msg("Invoice notification event published successfully")
 	}
 }
+
+// publishPaymentInstructionsEvent publishes the virtual account payment instructions for an order
+func (h *CheckoutHandler) publishPaymentInstructionsEvent(
+	ctx context.Context,
+	tenantID string,
+	order *models.GuestOrder,
+	payment *models.PaymentTransaction,
+	customerEmail string,
+) {
+	if h.kafkaProducer == nil {
+		log.Warn().Msg("Kafka producer not initialized, skipping payment instructions notification")
+		return
+	}
+
+	data := map[string]interface{}{
+		"order_id":        order.ID,
+		"order_reference": order.OrderReference,
+		"customer_name":   order.CustomerName,
+		"customer_email":  customerEmail,
+		"total_amount":    payment.Amount,
+		"bank":            payment.Bank,
+		"va_number":       *payment.VANumber,
+	}
+	if payment.ExpiryTime != nil {
+		data["expires_at"] = payment.ExpiryTime.UTC().Format(time.RFC3339)
+	}
+
+	event := map[string]interface{}{
+		"event_type": "order.payment_instructions",
+		"tenant_id":  tenantID,
+		"user_id":    "", // Empty for guest orders
+		"data":       data,
+	}
+
+	if err := h.kafkaProducer.Publish(ctx, order.OrderReference, event); err != nil {
+		log.Error().Err(err).
+			Str("order_reference", order.OrderReference).
+			Msg("Failed to publish payment instructions event")
+		return
+	}
+	log.Info().
+		Str("order_reference", order.OrderReference).
+		Msg("Payment instructions event published successfully")
+}
+
+// publishManualPaymentRequiredEvent tells staff to collect payment for an order placed during a gateway outage
+func (h *CheckoutHandler) publishManualPaymentRequiredEvent(
+	ctx context.Context,
+	tenantID string,
+	order *models.GuestOrder,
+	paymentMethod string,
+	paymentRetryQueued bool,
+) {
+	if h.kafkaProducer == nil {
+		log.Warn().Msg("Kafka producer not initialized, skipping manual payment notification")
+		return
+	}
+
+	if paymentMethod == "" {
+		paymentMethod = string(models.CheckoutPaymentQRIS)
+	}
+
+	event := map[string]interface{}{
+		"event_type": "order.manual_payment_required",
+		"tenant_id":  tenantID,
+		"user_id":    "", // Empty for guest orders
+		"data": map[string]interface{}{
+			"order_id":             order.ID,
+			"order_reference":      order.OrderReference,
+			"customer_name":        order.CustomerName,
+			"delivery_type":        order.DeliveryType,
+			"table_number":         order.TableNumber,
+			"total_amount":         order.TotalAmount,
+			"payment_method":       paymentMethod,
+			"payment_retry_queued": paymentRetryQueued,
+			"created_at":           order.CreatedAt.UTC().Format(time.RFC3339),
+		},
+	}
+
+	if err := h.kafkaProducer.Publish(ctx, order.OrderReference, event); err != nil {
+		log.Error().Err(err).
+			Str("order_reference", order.OrderReference).
+			Msg("Failed to publish manual payment required event")
+		return
+	}
+	log.Info().
+		Str("order_reference", order.OrderReference).
+		Msg("Manual payment required event published successfully")
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// CourierHandler books GoSend/GrabExpress couriers for delivery orders and receives their webhooks
+type CourierHandler struct {
+	courierService *services.CourierService
+}
+
+// NewCourierHandler creates a new courier handler
+func NewCourierHandler(courierService *services.CourierService) *CourierHandler {
+	return &CourierHandler{
+		courierService: courierService,
+	}
+}
+
+// courierErrorStatus maps courier errors to HTTP status codes; 0 means unexpected
+func courierErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrCourierBookingNotFound),
+		errors.Is(err, services.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrCourierBookingActive),
+		errors.Is(err, models.ErrCourierBookingFinished):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidCourierSettings):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrCourierNotConfigured),
+		errors.Is(err, models.ErrOrderNotDispatchable),
+		errors.Is(err, models.ErrDeliveryAddressUnusable):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrCourierBookingRejected):
+		return http.StatusBadGateway
+	case errors.Is(err, models.ErrCourierUnavailable):
+		return http.StatusServiceUnavailable
+	}
+	return 0
+}
+
+// GetCourierSettings handles GET /admin/settings/courier
+func (h *CourierHandler) GetCourierSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	settings, err := h.courierService.GetSettings(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get courier settings")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve courier settings",
+		})
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateCourierSettings handles PUT /admin/settings/courier
+func (h *CourierHandler) UpdateCourierSettings(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.UpdateCourierSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	settings, err := h.courierService.UpdateSettings(ctx, tenantID, &req)
+	if err != nil {
+		if status := courierErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to update courier settings")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update courier settings",
+		})
+	}
+
+	return c.JSON(http.StatusOK, settings)
+}
+
+// ListOrderCouriers handles GET /admin/orders/:id/courier
+func (h *CourierHandler) ListOrderCouriers(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	bookings, err := h.courierService.ListBookings(ctx, tenantID, orderID)
+	if err != nil {
+		if status := courierErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to list courier bookings")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve courier bookings",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"bookings": bookings,
+	})
+}
+
+// DispatchOrder handles POST /admin/orders/:id/courier
+// A refused booking is returned with the error so staff can see why it failed.
+func (h *CourierHandler) DispatchOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	booking, err := h.courierService.Dispatch(ctx, tenantID, orderID, false)
+	if err != nil {
+		if status := courierErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]interface{}{
+				"error":   err.Error(),
+				"booking": booking,
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to book courier")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to book courier",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, booking)
+}
+
+// CancelOrderCourier handles POST /admin/orders/:id/courier/cancel
+func (h *CourierHandler) CancelOrderCourier(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	userName := c.Request().Header.Get("X-User-Name")
+	booking, err := h.courierService.CancelBooking(ctx, tenantID, orderID, req.Reason, userName)
+	if err != nil {
+		if status := courierErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to cancel courier booking")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to cancel courier booking",
+		})
+	}
+
+	return c.JSON(http.StatusOK, booking)
+}
+
+// HandleCourierWebhook handles POST /webhooks/couriers/:provider
+// Unknown bookings are answered with 404 so the provider does not keep retrying a mistake forever.
+func (h *CourierHandler) HandleCourierWebhook(provider models.CourierProvider) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, 1<<20))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid webhook payload",
+			})
+		}
+
+		err = h.courierService.HandleWebhook(ctx, provider, c.Request().Header, body)
+		switch {
+		case err == nil:
+			return c.JSON(http.StatusOK, map[string]string{
+				"status": "ok",
+			})
+		case errors.Is(err, models.ErrInvalidCourierWebhook):
+			log.Warn().Err(err).Str("provider", string(provider)).Str("remote_addr", c.RealIP()).Msg("Rejected courier webhook")
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "Invalid webhook",
+			})
+		case errors.Is(err, models.ErrCourierBookingNotFound),
+			errors.Is(err, models.ErrCourierNotConfigured):
+			log.Warn().Err(err).Str("provider", string(provider)).Msg("Courier webhook for unknown booking")
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error().Err(err).Str("provider", string(provider)).Msg("Failed to process courier webhook")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to process webhook",
+		})
+	}
+}
+
+// RegisterRoutes registers courier settings, booking and webhook routes
+func (h *CourierHandler) RegisterRoutes(e *echo.Echo) {
+	staff := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier)
+	managers := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	e.GET("/api/v1/admin/settings/courier", h.GetCourierSettings, managers)
+	e.PUT("/api/v1/admin/settings/courier", h.UpdateCourierSettings, managers)
+
+	e.GET("/api/v1/admin/orders/:id/courier", h.ListOrderCouriers, staff)
+	e.POST("/api/v1/admin/orders/:id/courier", h.DispatchOrder, staff)
+	e.POST("/api/v1/admin/orders/:id/courier/cancel", h.CancelOrderCourier, staff)
+
+	// Public webhook endpoints; the provider's shared token is verified in the service layer
+	e.POST("/api/v1/webhooks/couriers/gosend", h.HandleCourierWebhook(models.CourierGoSend))
+	e.POST("/api/v1/webhooks/couriers/grabexpress", h.HandleCourierWebhook(models.CourierGrabExpress))
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// CustomerAddressHandler lets guests who verified their phone manage saved delivery addresses
+// Every route requires the customer session token from order history verification as a bearer token.
+type CustomerAddressHandler struct {
+	addressService *services.CustomerAddressService
+}
+
+// NewCustomerAddressHandler creates a new saved address handler
+func NewCustomerAddressHandler(addressService *services.CustomerAddressService) *CustomerAddressHandler {
+	return &CustomerAddressHandler{
+		addressService: addressService,
+	}
+}
+
+// customerAddressErrorStatus maps saved address errors to HTTP status codes; 0 means unexpected
+func customerAddressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidCustomerAddress):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrCustomerSessionRequired):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrCustomerAddressNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrCustomerAddressLimit):
+		return http.StatusConflict
+	}
+	return 0
+}
+
+// ListAddresses handles GET /api/v1/public/:tenantId/addresses
+func (h *CustomerAddressHandler) ListAddresses(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	addresses, err := h.addressService.List(ctx, tenantID, customerSessionToken(c))
+	if err != nil {
+		if status := customerAddressErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list saved addresses")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve addresses",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"addresses": addresses,
+	})
+}
+
+// SaveAddress handles POST /api/v1/public/:tenantId/addresses
+func (h *CustomerAddressHandler) SaveAddress(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	var req models.SaveCustomerAddressRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	address, err := h.addressService.Save(ctx, tenantID, customerSessionToken(c), &req)
+	if err != nil {
+		if status := customerAddressErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to save address")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to save address",
+		})
+	}
+
+	return c.JSON(http.StatusOK, address)
+}
+
+// DeleteAddress handles DELETE /api/v1/public/:tenantId/addresses/:id
+func (h *CustomerAddressHandler) DeleteAddress(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	if err := h.addressService.Delete(ctx, tenantID, customerSessionToken(c), c.Param("id")); err != nil {
+		if status := customerAddressErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to delete saved address")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete address",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// DeliveryProofHandler lets drivers and staff record who received a delivery order
+// The proof is also shown in the admin order detail and on the guest order page.
+type DeliveryProofHandler struct {
+	proofService *services.DeliveryProofService
+	orderService *services.OrderService
+}
+
+// NewDeliveryProofHandler creates a new proof of delivery handler
+func NewDeliveryProofHandler(proofService *services.DeliveryProofService, orderService *services.OrderService) *DeliveryProofHandler {
+	return &DeliveryProofHandler{
+		proofService: proofService,
+		orderService: orderService,
+	}
+}
+
+// deliveryProofErrorStatus maps proof of delivery errors to HTTP status codes; 0 means unexpected
+func deliveryProofErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrDeliveryProofRecipient), errors.Is(err, models.ErrNoteAttachmentTooLarge),
+		errors.Is(err, models.ErrNoteAttachmentType):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrOrderNotDeliverable), errors.Is(err, models.ErrDeliveryProofExists):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrNoteAttachmentsNotAvailable):
+		return http.StatusServiceUnavailable
+	}
+	return 0
+}
+
+// CompleteDelivery handles POST /admin/orders/:id/delivery-proof
+// Multipart form: recipient_name and an optional photo (JPEG, PNG or WebP).
+// A paid delivery order is completed once the proof is saved.
+func (h *DeliveryProofHandler) CompleteDelivery(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var photo *models.NoteAttachmentUpload
+	if header, err := c.FormFile("photo"); err == nil {
+		upload, file, err := openImageUpload(header)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid photo",
+			})
+		}
+		defer file.Close()
+		photo = &upload
+	} else if !errors.Is(err, http.ErrMissingFile) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	name := c.Request().Header.Get("X-User-Name")
+	if name == "" {
+		name = c.Request().Header.Get("X-User-Email")
+	}
+	actor := services.DeliveryProofActor{
+		UserID: c.Request().Header.Get("X-User-ID"),
+		Name:   name,
+	}
+
+	proof, err := h.proofService.CompleteDelivery(ctx, tenantID, orderID, c.FormValue("recipient_name"), photo, actor)
+	if err != nil {
+		if code := deliveryProofErrorStatus(err); code != 0 {
+			return c.JSON(code, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to record proof of delivery")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to record proof of delivery",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, proof)
+}
+
+// GetDeliveryProof handles GET /admin/orders/:id/delivery-proof
+func (h *DeliveryProofHandler) GetDeliveryProof(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	order, err := h.orderService.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Order not found",
+		})
+	}
+
+	proof, err := h.proofService.GetProof(ctx, order.ID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to get proof of delivery")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve proof of delivery",
+		})
+	}
+	if proof == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Order has no proof of delivery",
+		})
+	}
+
+	return c.JSON(http.StatusOK, proof)
+}
+
+// RegisterRoutes registers proof of delivery routes
+func (h *DeliveryProofHandler) RegisterRoutes(e *echo.Echo) {
+	staff := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier)
+
+	e.POST("/api/v1/admin/orders/:id/delivery-proof", h.CompleteDelivery, staff)
+	e.GET("/api/v1/admin/orders/:id/delivery-proof", h.GetDeliveryProof, staff)
+}
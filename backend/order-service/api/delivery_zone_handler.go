@@ -0,0 +1,198 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// DeliveryZoneHandler manages the zones delivery orders are priced by
+type DeliveryZoneHandler struct {
+	deliveryFeeService *services.DeliveryFeeService
+}
+
+// NewDeliveryZoneHandler creates a new delivery zone handler
+func NewDeliveryZoneHandler(deliveryFeeService *services.DeliveryFeeService) *DeliveryZoneHandler {
+	return &DeliveryZoneHandler{
+		deliveryFeeService: deliveryFeeService,
+	}
+}
+
+// deliveryZoneErrorStatus maps delivery zone errors to HTTP status codes; 0 means unexpected
+func deliveryZoneErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrDeliveryZoneNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrDeliveryZoneNameExists):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidDeliveryZone):
+		return http.StatusBadRequest
+	}
+	return 0
+}
+
+// ListDeliveryZones handles GET /admin/settings/delivery-zones
+func (h *DeliveryZoneHandler) ListDeliveryZones(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	activeOnly := c.QueryParam("active") == "true"
+	zones, err := h.deliveryFeeService.ListZones(ctx, tenantID, activeOnly)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list delivery zones")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve delivery zones",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"zones": zones,
+	})
+}
+
+// GetDeliveryZone handles GET /admin/settings/delivery-zones/:id
+func (h *DeliveryZoneHandler) GetDeliveryZone(c echo.Context) error {
+	ctx := c.Request().Context()
+	zoneID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	zone, err := h.deliveryFeeService.GetZone(ctx, tenantID, zoneID)
+	if err != nil {
+		if status := deliveryZoneErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("zone_id", zoneID).Msg("Failed to get delivery zone")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve delivery zone",
+		})
+	}
+
+	return c.JSON(http.StatusOK, zone)
+}
+
+// CreateDeliveryZone handles POST /admin/settings/delivery-zones
+func (h *DeliveryZoneHandler) CreateDeliveryZone(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.DeliveryZoneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	zone, err := h.deliveryFeeService.CreateZone(ctx, tenantID, &req)
+	if err != nil {
+		if status := deliveryZoneErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to create delivery zone")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create delivery zone",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, zone)
+}
+
+// UpdateDeliveryZone handles PUT /admin/settings/delivery-zones/:id
+func (h *DeliveryZoneHandler) UpdateDeliveryZone(c echo.Context) error {
+	ctx := c.Request().Context()
+	zoneID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.DeliveryZoneRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	zone, err := h.deliveryFeeService.UpdateZone(ctx, tenantID, zoneID, &req)
+	if err != nil {
+		if status := deliveryZoneErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("zone_id", zoneID).Msg("Failed to update delivery zone")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update delivery zone",
+		})
+	}
+
+	return c.JSON(http.StatusOK, zone)
+}
+
+// DeleteDeliveryZone handles DELETE /admin/settings/delivery-zones/:id
+func (h *DeliveryZoneHandler) DeleteDeliveryZone(c echo.Context) error {
+	ctx := c.Request().Context()
+	zoneID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	if err := h.deliveryFeeService.DeleteZone(ctx, tenantID, zoneID); err != nil {
+		if status := deliveryZoneErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("zone_id", zoneID).Msg("Failed to delete delivery zone")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete delivery zone",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RegisterRoutes registers delivery zone routes
+func (h *DeliveryZoneHandler) RegisterRoutes(e *echo.Echo) {
+	managers := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	e.GET("/api/v1/admin/settings/delivery-zones", h.ListDeliveryZones, managers)
+	e.POST("/api/v1/admin/settings/delivery-zones", h.CreateDeliveryZone, managers)
+	e.GET("/api/v1/admin/settings/delivery-zones/:id", h.GetDeliveryZone, managers)
+	e.PUT("/api/v1/admin/settings/delivery-zones/:id", h.UpdateDeliveryZone, managers)
+	e.DELETE("/api/v1/admin/settings/delivery-zones/:id", h.DeleteDeliveryZone, managers)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// GuestCancellationHandler lets guests cancel their own unpaid orders
+type GuestCancellationHandler struct {
+	cancellationService *services.GuestCancellationService
+}
+
+// NewGuestCancellationHandler creates a new guest cancellation handler
+func NewGuestCancellationHandler(cancellationService *services.GuestCancellationService) *GuestCancellationHandler {
+	return &GuestCancellationHandler{
+		cancellationService: cancellationService,
+	}
+}
+
+// CancelOrder handles POST /public/orders/:orderReference/cancel
+// The order reference is the guest's proof of ownership, as on the public order page.
+func (h *GuestCancellationHandler) CancelOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderReference := c.Param("orderReference")
+
+	order, err := h.cancellationService.CancelOrder(ctx, orderReference)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "order not found",
+			})
+		case errors.Is(err, models.ErrGuestCancelNotAllowed),
+			errors.Is(err, models.ErrGuestCancelWindowClosed),
+			errors.Is(err, models.ErrGuestCancelPaymentStarted):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_reference", orderReference).Msg("Failed to cancel order for guest")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to cancel order",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"order_reference": order.OrderReference,
+		"status":          order.Status,
+	})
+}
@@ -27,9 +27,10 @@ func NewGuestDataHandler(
 	encryptor utils.Encryptor,
 	auditPublisher *utils.AuditPublisher,
 	notificationProducer *queue.KafkaProducer,
+	attachmentStorage *services.AttachmentStorage,
 ) *GuestDataHandler {
 	guestDataService := services.NewGuestDataService(db, encryptor)
-	guestDeletionService := services.NewGuestDeletionService(db, encryptor, auditPublisher)
+	guestDeletionService := services.NewGuestDeletionService(db, encryptor, auditPublisher, attachmentStorage)
 
 	return &GuestDataHandler{
 		guestDataService:     guestDataService,
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// InvoiceHandler serves branded PDF invoices to staff and to guests holding an order reference
+type InvoiceHandler struct {
+	invoiceService *services.InvoiceService
+}
+
+// NewInvoiceHandler creates a new invoice handler
+func NewInvoiceHandler(invoiceService *services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+// GetOrderInvoice handles GET /admin/orders/:id/invoice.pdf
+func (h *InvoiceHandler) GetOrderInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	order, pdf, err := h.invoiceService.RenderForTenant(ctx, tenantID, orderID)
+	if errors.Is(err, services.ErrOrderNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to render invoice")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to render invoice",
+		})
+	}
+
+	return sendInvoice(c, order, pdf)
+}
+
+// GetPublicInvoice handles GET /public/orders/:orderReference/invoice.pdf
+// The order reference is the guest's proof of the order, as for the public order lookup.
+func (h *InvoiceHandler) GetPublicInvoice(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderReference := c.Param("orderReference")
+
+	order, pdf, err := h.invoiceService.RenderForReference(ctx, orderReference)
+	if errors.Is(err, services.ErrOrderNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "order not found",
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("order_reference", orderReference).Msg("Failed to render invoice")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to render invoice",
+		})
+	}
+
+	return sendInvoice(c, order, pdf)
+}
+
+func sendInvoice(c echo.Context, order *models.GuestOrder, pdf []byte) error {
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="invoice-%s.pdf"`, order.OrderReference))
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+// RegisterRoutes registers the staff invoice route
+func (h *InvoiceHandler) RegisterRoutes(e *echo.Echo) {
+	staff := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier)
+
+	e.GET("/api/v1/admin/orders/:id/invoice.pdf", h.GetOrderInvoice, staff)
+}
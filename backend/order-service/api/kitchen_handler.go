@@ -0,0 +1,280 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// KitchenHandler serves the kitchen display (KDS) API
+type KitchenHandler struct {
+	kitchenService *services.KitchenService
+}
+
+// NewKitchenHandler creates a new kitchen handler
+func NewKitchenHandler(kitchenService *services.KitchenService) *KitchenHandler {
+	return &KitchenHandler{
+		kitchenService: kitchenService,
+	}
+}
+
+// UpdateItemPrepStatusRequest is the body of PATCH /admin/kitchen/orders/:id/items/:itemId
+type UpdateItemPrepStatusRequest struct {
+	Status models.PrepStatus `json:"status"`
+}
+
+// kitchenErrorStatus maps kitchen errors to HTTP status codes; 0 means unexpected
+func kitchenErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidPrepStatus):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrKitchenOrderNotActive):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrKitchenOrderNotBumped):
+		return http.StatusConflict
+	}
+	return 0
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter
+func parseTimeParam(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// ListOrders handles GET /admin/kitchen/orders
+// Without since it returns every paid order on the kitchen screen. Kitchen screens then poll
+// with since set to the previous response's cursor to get only what changed.
+func (h *KitchenHandler) ListOrders(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	since, err := parseTimeParam(c, "since")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "since must be an RFC 3339 timestamp",
+		})
+	}
+
+	feed, err := h.kitchenService.GetFeed(ctx, tenantID, since)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list kitchen orders")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve kitchen orders",
+		})
+	}
+
+	return c.JSON(http.StatusOK, feed)
+}
+
+// ListBumpedOrders handles GET /admin/kitchen/orders/bumped
+func (h *KitchenHandler) ListBumpedOrders(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	orders, err := h.kitchenService.ListBumped(ctx, tenantID, limit)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list bumped kitchen orders")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve bumped orders",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"orders": orders,
+	})
+}
+
+// UpdateItemStatus handles PATCH /admin/kitchen/orders/:id/items/:itemId
+func (h *KitchenHandler) UpdateItemStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req UpdateItemPrepStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	orderID := c.Param("id")
+	itemID := c.Param("itemId")
+	if err := h.kitchenService.UpdateItemStatus(ctx, tenantID, orderID, itemID, req.Status); err != nil {
+		if status := kitchenErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Str("item_id", itemID).Msg("Failed to update item prep status")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update item status",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"order_id":    orderID,
+		"item_id":     itemID,
+		"prep_status": req.Status,
+	})
+}
+
+// BumpOrder handles POST /admin/kitchen/orders/:id/bump
+func (h *KitchenHandler) BumpOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	orderID := c.Param("id")
+	if err := h.kitchenService.BumpOrder(ctx, tenantID, orderID); err != nil {
+		if status := kitchenErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to bump kitchen order")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to bump order",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"order_id": orderID,
+		"bumped":   true,
+	})
+}
+
+// RecallOrder handles POST /admin/kitchen/orders/:id/recall
+func (h *KitchenHandler) RecallOrder(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	orderID := c.Param("id")
+	if err := h.kitchenService.RecallOrder(ctx, tenantID, orderID); err != nil {
+		if status := kitchenErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to recall kitchen order")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to recall order",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"order_id": orderID,
+		"bumped":   false,
+	})
+}
+
+// GetMetrics handles GET /admin/kitchen/metrics
+// from and to are RFC 3339 timestamps and default to the last 24 hours.
+func (h *KitchenHandler) GetMetrics(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	to := time.Now().UTC()
+	from := to.Add(-24 * time.Hour)
+	if t, err := parseTimeParam(c, "to"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "to must be an RFC 3339 timestamp",
+		})
+	} else if t != nil {
+		to = *t
+		from = to.Add(-24 * time.Hour)
+	}
+	if t, err := parseTimeParam(c, "from"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "from must be an RFC 3339 timestamp",
+		})
+	} else if t != nil {
+		from = *t
+	}
+	if !from.Before(to) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "from must be before to",
+		})
+	}
+	if to.Sub(from) > 93*24*time.Hour {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "The metrics period cannot be longer than 93 days",
+		})
+	}
+
+	metrics, err := h.kitchenService.GetMetrics(ctx, tenantID, from, to)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get kitchen metrics")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve kitchen metrics",
+		})
+	}
+
+	return c.JSON(http.StatusOK, metrics)
+}
+
+// RegisterRoutes registers kitchen display routes
+func (h *KitchenHandler) RegisterRoutes(e *echo.Echo) {
+	kitchen := e.Group("/api/v1/admin/kitchen")
+
+	kitchen.GET("/orders", h.ListOrders)
+	kitchen.GET("/orders/bumped", h.ListBumpedOrders)
+	kitchen.PATCH("/orders/:id/items/:itemId", h.UpdateItemStatus)
+	kitchen.POST("/orders/:id/bump", h.BumpOrder)
+	kitchen.POST("/orders/:id/recall", h.RecallOrder)
+	kitchen.GET("/metrics", h.GetMetrics)
+}
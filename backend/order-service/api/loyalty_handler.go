@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// LoyaltyHandler handles loyalty program settings and guest balance lookups
+type LoyaltyHandler struct {
+	loyaltyService *services.LoyaltyService
+}
+
+// NewLoyaltyHandler creates a new loyalty handler
+func NewLoyaltyHandler(loyaltyService *services.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService: loyaltyService,
+	}
+}
+
+// loyaltyErrorStatus maps loyalty errors to HTTP status codes; 0 means unexpected
+func loyaltyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidLoyaltyProgram),
+		errors.Is(err, models.ErrLoyaltyPhoneRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrLoyaltyDisabled),
+		errors.Is(err, models.ErrLoyaltyBelowMinimum),
+		errors.Is(err, models.ErrLoyaltyInsufficientPoints),
+		errors.Is(err, models.ErrLoyaltyOrderTooSmall):
+		return http.StatusUnprocessableEntity
+	}
+	return 0
+}
+
+// GetLoyaltyProgram handles GET /admin/settings/loyalty
+func (h *LoyaltyHandler) GetLoyaltyProgram(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	program, err := h.loyaltyService.GetProgram(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get loyalty program")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve loyalty program",
+		})
+	}
+
+	return c.JSON(http.StatusOK, program)
+}
+
+// UpdateLoyaltyProgram handles PUT /admin/settings/loyalty
+func (h *LoyaltyHandler) UpdateLoyaltyProgram(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.UpdateLoyaltyProgramRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	program, err := h.loyaltyService.UpdateProgram(ctx, tenantID, &req)
+	if err != nil {
+		if status := loyaltyErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to update loyalty program")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update loyalty program",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Bool("is_enabled", program.IsEnabled).
+		Msg("Loyalty program updated")
+
+	return c.JSON(http.StatusOK, program)
+}
+
+// GetBalance handles POST /public/:tenantId/loyalty/balance
+// The phone number is sent in the body so it stays out of URLs and access logs. The balance is
+// only included for a guest whose customer session (bearer token) verified that phone.
+func (h *LoyaltyHandler) GetBalance(c echo.Context) error {
+	tenantID := c.Param("tenantId")
+
+	var req models.LoyaltyBalanceRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	balance, err := h.loyaltyService.GetBalance(c.Request().Context(), tenantID, customerSessionToken(c), req.Phone)
+	if err != nil {
+		if status := loyaltyErrorStatus(err); status != 0 {
+			return echo.NewHTTPError(status, err.Error())
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get loyalty balance")
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get loyalty balance")
+	}
+
+	return c.JSON(http.StatusOK, balance)
+}
+
+// RegisterRoutes registers admin loyalty routes
+func (h *LoyaltyHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/api/v1/admin/settings/loyalty")
+
+	admin.GET("", h.GetLoyaltyProgram)
+	admin.PUT("", h.UpdateLoyaltyProgram)
+}
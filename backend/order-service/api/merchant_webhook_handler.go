@@ -0,0 +1,257 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// MerchantWebhookHandler manages the webhook URLs tenants receive order events at
+type MerchantWebhookHandler struct {
+	webhookService *services.MerchantWebhookService
+}
+
+// NewMerchantWebhookHandler creates a new merchant webhook handler
+func NewMerchantWebhookHandler(webhookService *services.MerchantWebhookService) *MerchantWebhookHandler {
+	return &MerchantWebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// merchantWebhookErrorStatus maps merchant webhook errors to HTTP status codes; 0 means unexpected
+func merchantWebhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrMerchantWebhookNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidMerchantWebhook):
+		return http.StatusBadRequest
+	}
+	return 0
+}
+
+// ListWebhooks handles GET /admin/settings/webhooks
+func (h *MerchantWebhookHandler) ListWebhooks(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list merchant webhooks")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve webhooks",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"webhooks": webhooks,
+		"events":   models.MerchantWebhookEvents,
+	})
+}
+
+// CreateWebhook handles POST /admin/settings/webhooks
+// The response carries the signing secret; it cannot be read again, only rotated.
+func (h *MerchantWebhookHandler) CreateWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.MerchantWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(ctx, tenantID, &req)
+	if err != nil {
+		if status := merchantWebhookErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to create merchant webhook")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create webhook",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, webhook)
+}
+
+// UpdateWebhook handles PUT /admin/settings/webhooks/:id
+func (h *MerchantWebhookHandler) UpdateWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+	webhookID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.MerchantWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(ctx, tenantID, webhookID, &req)
+	if err != nil {
+		if status := merchantWebhookErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("webhook_id", webhookID).Msg("Failed to update merchant webhook")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update webhook",
+		})
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook handles DELETE /admin/settings/webhooks/:id
+func (h *MerchantWebhookHandler) DeleteWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+	webhookID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	if err := h.webhookService.DeleteWebhook(ctx, tenantID, webhookID); err != nil {
+		if status := merchantWebhookErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("webhook_id", webhookID).Msg("Failed to delete merchant webhook")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete webhook",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RotateSecret handles POST /admin/settings/webhooks/:id/rotate-secret
+func (h *MerchantWebhookHandler) RotateSecret(c echo.Context) error {
+	ctx := c.Request().Context()
+	webhookID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	webhook, err := h.webhookService.RotateSecret(ctx, tenantID, webhookID)
+	if err != nil {
+		if status := merchantWebhookErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("webhook_id", webhookID).Msg("Failed to rotate merchant webhook secret")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to rotate webhook secret",
+		})
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// ListDeliveries handles GET /admin/settings/webhooks/:id/deliveries
+// Optional status filter: pending, succeeded or failed
+func (h *MerchantWebhookHandler) ListDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+	webhookID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var status *models.WebhookDeliveryStatus
+	if param := c.QueryParam("status"); param != "" {
+		s := models.WebhookDeliveryStatus(param)
+		switch s {
+		case models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+			status = &s
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid status filter",
+			})
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(ctx, tenantID, webhookID, status, limit, offset)
+	if err != nil {
+		if status := merchantWebhookErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("webhook_id", webhookID).Msg("Failed to list merchant webhook deliveries")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve webhook deliveries",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(deliveries),
+		},
+	})
+}
+
+// RegisterRoutes registers merchant webhook routes
+func (h *MerchantWebhookHandler) RegisterRoutes(e *echo.Echo) {
+	managers := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	e.GET("/api/v1/admin/settings/webhooks", h.ListWebhooks, managers)
+	e.POST("/api/v1/admin/settings/webhooks", h.CreateWebhook, managers)
+	e.PUT("/api/v1/admin/settings/webhooks/:id", h.UpdateWebhook, managers)
+	e.DELETE("/api/v1/admin/settings/webhooks/:id", h.DeleteWebhook, managers)
+	e.POST("/api/v1/admin/settings/webhooks/:id/rotate-secret", h.RotateSecret, managers)
+	e.GET("/api/v1/admin/settings/webhooks/:id/deliveries", h.ListDeliveries, managers)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -63,11 +64,20 @@ func (h *OfflineOrderHandler) CreateOfflineOrder(c echo.Context) error {
 	// Create offline order
 	order, err := h.offlineOrderService.CreateOfflineOrder(ctx, &req)
 	if err != nil {
+		var limitErr *models.PlanLimitError
+		if errors.As(err, &limitErr) {
+			return c.JSON(http.StatusForbidden, limitErr.Response())
+		}
 		log.Error().
 			Err(err).
 			Str("tenant_id", tenantID).
 			Str("user_id", userID).
 			Msg("Failed to create offline order")
+		if services.IsStockError(err) {
+			return c.JSON(offlineStockErrorStatus(err), map[string]string{
+				"error": err.Error(),
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create offline order",
 		})
@@ -100,7 +110,7 @@ func (h *OfflineOrderHandler) ListOfflineOrders(c echo.Context) error {
 	// Parse query parameters
 	filters := services.ListOfflineOrdersFilters{
 		Status:      c.QueryParam("status"),      // Optional: filter by status
-		SearchQuery: c.QueryParam("search"),      // Optional: search by order_reference
+		SearchQuery: c.QueryParam("search"),      // Optional: search by order_reference, customer phone or email
 		Limit:       20,                          // Default limit
 		Offset:      0,                           // Default offset
 	}
@@ -412,6 +422,11 @@ func (h *OfflineOrderHandler) UpdateOfflineOrder(c echo.Context) error {
 			Msg("Failed to update offline order")
 		
 		// Check for specific error types
+		if services.IsStockError(err) {
+			return c.JSON(offlineStockErrorStatus(err), map[string]string{
+				"error": err.Error(),
+			})
+		}
 		if strings.Contains(err.Error(), "cannot edit order with status") {
 			return c.JSON(http.StatusForbidden, map[string]string{
 				"error": err.Error(),
@@ -558,6 +573,62 @@ func (h *OfflineOrderHandler) DeleteOfflineOrder(c echo.Context) error {
 	})
 }
 
+// IngestOfflineBatch handles POST /api/v1/admin/orders/offline-batch
+// Syncs orders queued on a terminal while it was offline and reports a per-order status
+func (h *OfflineOrderHandler) IngestOfflineBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	userID := c.Request().Header.Get("X-User-ID")
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user authentication is required",
+		})
+	}
+
+	var req services.IngestOfflineBatchRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warn().Err(err).Msg("Failed to bind offline batch request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if len(req.Orders) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "orders must contain at least one order",
+		})
+	}
+	if len(req.Orders) > services.MaxOfflineBatchSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": "too many orders in batch",
+		})
+	}
+
+	req.TenantID = tenantID
+	req.RecordedByUserID = userID
+
+	response, err := h.offlineOrderService.IngestOfflineBatch(ctx, &req)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("user_id", userID).
+			Msg("Failed to ingest offline order batch")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to ingest offline orders",
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
 // ============================================================================
 // Route Registration
 // ============================================================================
@@ -590,5 +661,16 @@ func RegisterOfflineOrderRoutes(e *echo.Echo, handler *OfflineOrderHandler, jwtM
 	// T095: Apply RequireRole middleware to DELETE route
 	offlineOrders.DELETE("/:id", handler.DeleteOfflineOrder, requireRoleMiddleware("owner", "manager"))
 	
+	// Offline terminal sync: replay orders queued while the device had no connection
+	e.POST("/api/v1/admin/orders/offline-batch", handler.IngestOfflineBatch, jwtMiddleware, rateLimitMiddleware)
+	
 	log.Info().Msg("Offline order routes registered successfully with rate limiting")
-}
\ No newline at end of file
+}
+
+// offlineStockErrorStatus maps a stock rejection to its HTTP status
+func offlineStockErrorStatus(err error) int {
+	if errors.Is(err, models.ErrInsufficientStock) {
+		return http.StatusConflict
+	}
+	return http.StatusUnprocessableEntity
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+const (
+	// orderEventsHeartbeat keeps idle streams open through proxies
+	orderEventsHeartbeat = 15 * time.Second
+	// orderEventsMaxDuration bounds a stream; EventSource reconnects and gets a fresh snapshot
+	orderEventsMaxDuration = 30 * time.Minute
+	// orderEventsRetryMillis is the reconnect delay suggested to the browser
+	orderEventsRetryMillis = 3000
+)
+
+// OrderEventsHandler streams order status changes to guests over Server-Sent Events
+type OrderEventsHandler struct {
+	orderService *services.OrderService
+	broadcaster  *services.OrderStatusBroadcaster
+}
+
+// NewOrderEventsHandler creates a new order events handler
+func NewOrderEventsHandler(orderService *services.OrderService, broadcaster *services.OrderStatusBroadcaster) *OrderEventsHandler {
+	return &OrderEventsHandler{
+		orderService: orderService,
+		broadcaster:  broadcaster,
+	}
+}
+
+// StreamOrderEvents handles GET /public/orders/:orderReference/events
+// The stream opens with a snapshot of the current status, then pushes status and payment
+// events as they happen. It ends once the order is complete or cancelled.
+func (h *OrderEventsHandler) StreamOrderEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderReference := c.Param("orderReference")
+
+	// Subscribe before reading the snapshot so no transition falls between the two
+	events, unsubscribe := h.broadcaster.Subscribe(orderReference)
+	defer unsubscribe()
+
+	snapshot, err := h.orderService.GetStatusSnapshot(ctx, orderReference)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Order not found",
+			})
+		}
+		log.Error().Err(err).Str("order_reference", orderReference).Msg("Failed to get order status snapshot")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve order",
+		})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", orderEventsRetryMillis); err != nil {
+		return nil
+	}
+	if err := writeOrderEvent(res, snapshot); err != nil || snapshot.IsFinal() {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(orderEventsHeartbeat)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(orderEventsMaxDuration)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline.C:
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the guest reconnects and gets a fresh snapshot
+				return nil
+			}
+			if err := writeOrderEvent(res, &event); err != nil || event.IsFinal() {
+				return nil
+			}
+		}
+	}
+}
+
+// writeOrderEvent writes one SSE event named after the event type and flushes it
+func writeOrderEvent(res *echo.Response, event *models.OrderStatusEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
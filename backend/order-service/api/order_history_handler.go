@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// OrderHistoryHandler lets returning guests see their past orders after verifying their phone
+type OrderHistoryHandler struct {
+	orderHistoryService *services.OrderHistoryService
+}
+
+// NewOrderHistoryHandler creates a new order history handler
+func NewOrderHistoryHandler(orderHistoryService *services.OrderHistoryService) *OrderHistoryHandler {
+	return &OrderHistoryHandler{
+		orderHistoryService: orderHistoryService,
+	}
+}
+
+// orderHistoryErrorStatus maps order history errors to HTTP status codes; 0 means unexpected
+func orderHistoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidOTPChannel),
+		errors.Is(err, models.ErrInvalidOrderHistoryPhone):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrOrderHistoryOTPCooldown):
+		return http.StatusTooManyRequests
+	case errors.Is(err, models.ErrOrderHistoryOTPInvalid),
+		errors.Is(err, models.ErrCustomerSessionRequired):
+		return http.StatusUnauthorized
+	}
+	return 0
+}
+
+// RequestOTP handles POST /api/v1/public/:tenantId/order-history/otp
+// The response is the same whether or not the number has orders.
+func (h *OrderHistoryHandler) RequestOTP(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	var req models.OrderHistoryOTPRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.orderHistoryService.RequestOTP(ctx, tenantID, &req); err != nil {
+		if status := orderHistoryErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to send order history code")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to send verification code",
+		})
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"message":            "If this number has orders with us, a verification code has been sent",
+		"expires_in_seconds": int(models.OrderHistoryOTPTTL.Seconds()),
+	})
+}
+
+// VerifyOTP handles POST /api/v1/public/:tenantId/order-history/verify
+func (h *OrderHistoryHandler) VerifyOTP(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	var req models.OrderHistoryVerifyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	session, err := h.orderHistoryService.VerifyOTP(ctx, tenantID, &req)
+	if err != nil {
+		if status := orderHistoryErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to verify order history code")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to verify code",
+		})
+	}
+
+	return c.JSON(http.StatusOK, session)
+}
+
+// ListOrders handles GET /api/v1/public/:tenantId/order-history
+// Requires the session token from VerifyOTP as a bearer token.
+func (h *OrderHistoryHandler) ListOrders(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+	token := customerSessionToken(c)
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	orders, err := h.orderHistoryService.ListOrders(ctx, tenantID, token, limit, offset)
+	if err != nil {
+		if status := orderHistoryErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list order history")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve orders",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"orders": orders,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// customerSessionToken reads the bearer token of a guest who verified their phone
+func customerSessionToken(c echo.Context) string {
+	return strings.TrimSpace(strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer "))
+}
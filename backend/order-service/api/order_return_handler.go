@@ -0,0 +1,294 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// OrderReturnHandler handles the returns (RMA) workflow
+type OrderReturnHandler struct {
+	returnService *services.OrderReturnService
+}
+
+// NewOrderReturnHandler creates a new order return handler
+func NewOrderReturnHandler(returnService *services.OrderReturnService) *OrderReturnHandler {
+	return &OrderReturnHandler{
+		returnService: returnService,
+	}
+}
+
+// orderReturnErrorStatus maps return errors to HTTP status codes; 0 means unexpected
+func orderReturnErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound), errors.Is(err, models.ErrReturnNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidReturn), errors.Is(err, models.ErrReturnQuantityExceeded):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrOrderNotReturnable),
+		errors.Is(err, models.ErrReturnStatusConflict),
+		errors.Is(err, models.ErrReturnNothingToCredit),
+		errors.Is(err, models.ErrPaymentNotSettled),
+		services.IsStockError(err):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrExchangeExceedsCredit),
+		errors.Is(err, models.ErrNoOnlinePayment),
+		errors.Is(err, models.ErrRefundNotSupported),
+		errors.Is(err, models.ErrRefundExceedsPayment):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrRefundApprovalRequired):
+		return http.StatusForbidden
+	}
+	return 0
+}
+
+// returnActor reads the staff member from the headers set by the API gateway
+func returnActor(c echo.Context) services.ReturnActor {
+	name := c.Request().Header.Get("X-User-Name")
+	if name == "" {
+		name = c.Request().Header.Get("X-User-Email")
+	}
+	return services.ReturnActor{
+		UserID: c.Request().Header.Get("X-User-ID"),
+		Name:   name,
+		Role:   string(middleware.GetUserRole(c)),
+	}
+}
+
+// returnResponse writes a return, or the error of the step that produced it
+func returnResponse(c echo.Context, ret *models.OrderReturn, err error, status int, failure string) error {
+	if err != nil {
+		if code := orderReturnErrorStatus(err); code != 0 {
+			return c.JSON(code, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("id", c.Param("id")).Msg(failure)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": failure,
+		})
+	}
+	return c.JSON(status, ret)
+}
+
+// CreateReturn handles POST /admin/orders/:id/returns
+func (h *OrderReturnHandler) CreateReturn(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.CreateReturnRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	ret, err := h.returnService.CreateReturn(ctx, tenantID, c.Param("id"), &req, returnActor(c))
+	return returnResponse(c, ret, err, http.StatusCreated, "Failed to create return")
+}
+
+// ListOrderReturns handles GET /admin/orders/:id/returns
+func (h *OrderReturnHandler) ListOrderReturns(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	returns, err := h.returnService.ListOrderReturns(ctx, tenantID, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to list order returns")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve returns",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"returns": returns,
+	})
+}
+
+// ListReturns handles GET /admin/returns
+// Optional status filter: requested, approved, rejected, received or completed
+func (h *OrderReturnHandler) ListReturns(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var status *models.ReturnStatus
+	if param := c.QueryParam("status"); param != "" {
+		s := models.ReturnStatus(param)
+		switch s {
+		case models.ReturnStatusRequested, models.ReturnStatusApproved, models.ReturnStatusRejected,
+			models.ReturnStatusReceived, models.ReturnStatusCompleted:
+			status = &s
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid status filter",
+			})
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	returns, err := h.returnService.ListReturns(ctx, tenantID, status, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list returns")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve returns",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"returns": returns,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(returns),
+		},
+	})
+}
+
+// GetReturn handles GET /admin/returns/:id
+func (h *OrderReturnHandler) GetReturn(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	ret, err := h.returnService.GetReturn(ctx, tenantID, c.Param("id"))
+	return returnResponse(c, ret, err, http.StatusOK, "Failed to retrieve return")
+}
+
+// ApproveReturn handles POST /admin/returns/:id/approve
+func (h *OrderReturnHandler) ApproveReturn(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	ret, err := h.returnService.ApproveReturn(ctx, tenantID, c.Param("id"), returnActor(c))
+	return returnResponse(c, ret, err, http.StatusOK, "Failed to approve return")
+}
+
+// RejectReturn handles POST /admin/returns/:id/reject
+func (h *OrderReturnHandler) RejectReturn(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.RejectReturnRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	ret, err := h.returnService.RejectReturn(ctx, tenantID, c.Param("id"), &req, returnActor(c))
+	return returnResponse(c, ret, err, http.StatusOK, "Failed to reject return")
+}
+
+// ReceiveReturn handles POST /admin/returns/:id/receive
+// Items listed in damaged_item_ids are not put back into stock.
+func (h *OrderReturnHandler) ReceiveReturn(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.ReceiveReturnRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	ret, err := h.returnService.ReceiveReturn(ctx, tenantID, c.Param("id"), &req, returnActor(c))
+	return returnResponse(c, ret, err, http.StatusOK, "Failed to receive return")
+}
+
+// CompleteReturn handles POST /admin/returns/:id/complete
+// Issues the refund, exchange order or store credit the return was opened for.
+func (h *OrderReturnHandler) CompleteReturn(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.CompleteReturnRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	ret, err := h.returnService.CompleteReturn(ctx, tenantID, c.Param("id"), &req, returnActor(c))
+	return returnResponse(c, ret, err, http.StatusOK, "Failed to complete return")
+}
+
+// RegisterRoutes registers order return routes
+// Any staff member can open and receive a return; approving, rejecting and issuing
+// the resolution move money or stock value and are kept to managers.
+func (h *OrderReturnHandler) RegisterRoutes(e *echo.Echo) {
+	managers := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	e.POST("/api/v1/admin/orders/:id/returns", h.CreateReturn)
+	e.GET("/api/v1/admin/orders/:id/returns", h.ListOrderReturns)
+
+	e.GET("/api/v1/admin/returns", h.ListReturns)
+	e.GET("/api/v1/admin/returns/:id", h.GetReturn)
+	e.POST("/api/v1/admin/returns/:id/approve", h.ApproveReturn, managers)
+	e.POST("/api/v1/admin/returns/:id/reject", h.RejectReturn, managers)
+	e.POST("/api/v1/admin/returns/:id/receive", h.ReceiveReturn)
+	e.POST("/api/v1/admin/returns/:id/complete", h.CompleteReturn, managers)
+}
@@ -1,23 +1,28 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/point-of-sale-system/order-service/src/middleware"
 	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/services"
 	"github.com/rs/zerolog/log"
 )
 
 // OrderSettingsHandler handles order settings operations
 type OrderSettingsHandler struct {
-	repo *repository.OrderSettingsRepository
+	repo            *repository.OrderSettingsRepository
+	autoCompleteJob *services.OrderAutoCompleteJob
 }
 
 // NewOrderSettingsHandler creates a new order settings handler
-func NewOrderSettingsHandler(repo *repository.OrderSettingsRepository) *OrderSettingsHandler {
+func NewOrderSettingsHandler(repo *repository.OrderSettingsRepository, autoCompleteJob *services.OrderAutoCompleteJob) *OrderSettingsHandler {
 	return &OrderSettingsHandler{
-		repo: repo,
+		repo:            repo,
+		autoCompleteJob: autoCompleteJob,
 	}
 }
 
@@ -97,8 +102,92 @@ func (h *OrderSettingsHandler) UpdateOrderSettings(c echo.Context) error {
 		})
 	}
 
+	if err := req.ValidateAutoComplete(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateCharges(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateScheduling(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateStoreHours(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateLimits(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidatePaymentOutage(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateGuestCancellation(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidatePaymentExpiry(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateReservation(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateSLA(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateOrderReference(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := req.ValidateRefundApproval(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Managers approve refunds up to the threshold, so only owners may move it
+	if req.RefundApprovalThreshold != nil && middleware.GetUserRole(c) != middleware.RoleOwner {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "only owners can change refund_approval_threshold",
+		})
+	}
+
 	// Update settings
 	settings, err := h.repo.Update(ctx, tenantID, &req)
+	if errors.Is(err, models.ErrOrderReferencePrefixTaken) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -116,6 +205,33 @@ func (h *OrderSettingsHandler) UpdateOrderSettings(c echo.Context) error {
 	return c.JSON(http.StatusOK, settings)
 }
 
+// PreviewAutoComplete handles GET /admin/settings/orders/auto-complete/preview
+// Dry run of the tenant's auto-complete rule: lists the PAID orders it would complete
+// now and the disputed orders it would skip
+func (h *OrderSettingsHandler) PreviewAutoComplete(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.QueryParam("tenant_id")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	report, err := h.autoCompleteJob.Preview(ctx, tenantID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("Failed to preview order auto-complete")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to preview auto-complete",
+		})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
 // RegisterRoutes registers order settings routes
 func (h *OrderSettingsHandler) RegisterRoutes(e *echo.Echo) {
 	// Admin routes for order settings
@@ -124,4 +240,5 @@ func (h *OrderSettingsHandler) RegisterRoutes(e *echo.Echo) {
 
 	admin.GET("/orders", h.GetOrderSettings)
 	admin.PUT("/orders", h.UpdateOrderSettings)
+	admin.GET("/orders/auto-complete/preview", h.PreviewAutoComplete)
 }
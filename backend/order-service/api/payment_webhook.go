@@ -2,15 +2,18 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 
+	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/services"
 )
 
-// PaymentWebhookHandler handles Midtrans payment webhook notifications
+// PaymentWebhookHandler handles Midtrans and Xendit payment webhook notifications
 type PaymentWebhookHandler struct {
 	paymentService *services.PaymentService
 }
@@ -54,7 +57,7 @@ func (h *PaymentWebhookHandler) HandleMidtransNotification(c echo.Context) error
 		Msg("Received Midtrans webhook notification")
 
 	// Process notification (includes signature verification, idempotency check, status updates)
-	err := h.paymentService.ProcessNotification(ctx, &notification)
+	err := h.paymentService.ProcessNotification(ctx, models.PaymentGatewayMidtrans, &notification, c.Request().Header)
 	if err != nil {
 		// Log error but return 200 to prevent Midtrans retries
 		// Invalid signatures or duplicate notifications should not trigger retries
@@ -92,10 +95,89 @@ func (h *PaymentWebhookHandler) HandleMidtransNotification(c echo.Context) error
 	})
 }
 
+// HandleXenditCallback handles POST /payments/xendit/callback
+// Xendit callbacks are normalized to Midtrans notifications and verified with the
+// tenant's callback token
+func (h *PaymentWebhookHandler) HandleXenditCallback(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("remote_addr", c.RealIP()).
+			Msg("Failed to read Xendit callback")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid callback payload",
+		})
+	}
+
+	notification, err := services.ParseXenditCallback(body)
+	if errors.Is(err, services.ErrUnrecognizedXenditCallback) {
+		// Other callback types (e.g. disbursements) are acknowledged and ignored
+		log.Info().
+			RawJSON("callback", body).
+			Str("remote_addr", c.RealIP()).
+			Msg("Ignoring unrecognized Xendit callback")
+		return c.JSON(http.StatusOK, map[string]string{
+			"status": "ignored",
+		})
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("remote_addr", c.RealIP()).
+			Msg("Failed to parse Xendit callback")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid callback payload",
+		})
+	}
+
+	log.Info().
+		RawJSON("callback", body).
+		Str("order_id", notification.OrderID).
+		Str("transaction_id", notification.TransactionID).
+		Str("transaction_status", notification.TransactionStatus).
+		Str("payment_type", notification.PaymentType).
+		Str("gross_amount", notification.GrossAmount).
+		Str("remote_addr", c.RealIP()).
+		Msg("Received Xendit callback")
+
+	if err := h.paymentService.ProcessNotification(ctx, models.PaymentGatewayXendit, notification, c.Request().Header); err != nil {
+		log.Error().
+			Err(err).
+			Str("order_id", notification.OrderID).
+			Str("transaction_id", notification.TransactionID).
+			Msg("Failed to process Xendit callback")
+
+		if err.Error() == "invalid signature" {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Invalid callback token",
+			})
+		}
+
+		return c.JSON(http.StatusOK, map[string]string{
+			"status": "acknowledged",
+			"note":   "notification received but processing failed - manual intervention required",
+		})
+	}
+
+	log.Info().
+		Str("order_id", notification.OrderID).
+		Str("transaction_id", notification.TransactionID).
+		Str("transaction_status", notification.TransactionStatus).
+		Msg("Xendit callback processed successfully")
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"status": "success",
+	})
+}
+
 // RegisterRoutes registers payment webhook routes
 func (h *PaymentWebhookHandler) RegisterRoutes(e *echo.Echo) {
 	// Public webhook endpoint (no auth required - Midtrans sends notifications here)
 	// Signature verification is handled in the service layer
 	// Route matches API gateway path: /api/v1/webhooks/payments/midtrans/notification
 	e.POST("/api/v1/webhooks/payments/midtrans/notification", h.HandleMidtransNotification)
+	e.POST("/api/v1/webhooks/payments/xendit/callback", h.HandleXenditCallback)
 }
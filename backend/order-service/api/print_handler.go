@@ -0,0 +1,218 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// PrintHandler queues receipts and kitchen tickets and serves them to a tenant's local print agent
+// The print agent signs in as a staff member, polls for the next job and acknowledges it once printed.
+type PrintHandler struct {
+	printService *services.PrintService
+}
+
+// NewPrintHandler creates a new print handler
+func NewPrintHandler(printService *services.PrintService) *PrintHandler {
+	return &PrintHandler{
+		printService: printService,
+	}
+}
+
+// printErrorStatus maps print errors to HTTP status codes; 0 means unexpected
+func printErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrPrintJobNotFound),
+		errors.Is(err, services.ErrOrderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrPrintJobNotClaimed),
+		errors.Is(err, models.ErrPrintJobQueued):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidPrintJob):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrOrderNotPrintable):
+		return http.StatusUnprocessableEntity
+	}
+	return 0
+}
+
+// QueueOrderPrint handles POST /admin/orders/:id/print
+func (h *PrintHandler) QueueOrderPrint(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.CreatePrintJobsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	jobs, err := h.printService.QueueOrder(ctx, tenantID, orderID, &req)
+	if err != nil {
+		if status := printErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to queue print jobs")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to queue print jobs",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"jobs": jobs,
+	})
+}
+
+// ListPrintJobs handles GET /admin/print-jobs
+func (h *PrintHandler) ListPrintJobs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var status *models.PrintJobStatus
+	if param := c.QueryParam("status"); param != "" {
+		s := models.PrintJobStatus(param)
+		status = &s
+	}
+
+	jobs, err := h.printService.ListJobs(ctx, tenantID, status)
+	if err != nil {
+		if status := printErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list print jobs")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve print jobs",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"jobs": jobs,
+	})
+}
+
+// ClaimPrintJob handles POST /admin/print-jobs/claim
+// Returns the next job with its base64 ESC/POS payload, or 204 when the queue is empty.
+func (h *PrintHandler) ClaimPrintJob(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	job, err := h.printService.ClaimNext(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to claim print job")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to claim print job",
+		})
+	}
+	if job == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// AcknowledgePrintJob handles POST /admin/print-jobs/:id/ack
+func (h *PrintHandler) AcknowledgePrintJob(c echo.Context) error {
+	ctx := c.Request().Context()
+	jobID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.AckPrintJobRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	job, err := h.printService.Acknowledge(ctx, tenantID, jobID, &req)
+	if err != nil {
+		if status := printErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("print_job_id", jobID).Msg("Failed to acknowledge print job")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to acknowledge print job",
+		})
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// RetryPrintJob handles POST /admin/print-jobs/:id/retry
+func (h *PrintHandler) RetryPrintJob(c echo.Context) error {
+	ctx := c.Request().Context()
+	jobID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	job, err := h.printService.Retry(ctx, tenantID, jobID)
+	if err != nil {
+		if status := printErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("print_job_id", jobID).Msg("Failed to retry print job")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retry print job",
+		})
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// RegisterRoutes registers the print queue routes
+func (h *PrintHandler) RegisterRoutes(e *echo.Echo) {
+	staff := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier)
+
+	e.POST("/api/v1/admin/orders/:id/print", h.QueueOrderPrint, staff)
+
+	admin := e.Group("/api/v1/admin/print-jobs")
+	admin.Use(staff)
+	admin.GET("", h.ListPrintJobs)
+	admin.POST("/claim", h.ClaimPrintJob)
+	admin.POST("/:id/ack", h.AcknowledgePrintJob)
+	admin.POST("/:id/retry", h.RetryPrintJob)
+}
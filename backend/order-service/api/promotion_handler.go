@@ -0,0 +1,237 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// PromotionHandler handles promotion management and order promotion records
+type PromotionHandler struct {
+	promotionService *services.PromotionService
+}
+
+// NewPromotionHandler creates a new promotion handler
+func NewPromotionHandler(promotionService *services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+	}
+}
+
+// promotionErrorStatus maps promotion errors to HTTP status codes; 0 means unexpected
+func promotionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrPromotionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidPromotion):
+		return http.StatusBadRequest
+	}
+	return 0
+}
+
+// ListPromotions handles GET /admin/promotions
+func (h *PromotionHandler) ListPromotions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	promotions, err := h.promotionService.ListPromotions(ctx, tenantID, c.QueryParam("active") == "true")
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list promotions")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve promotions",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"promotions": promotions,
+	})
+}
+
+// GetPromotion handles GET /admin/promotions/:id
+func (h *PromotionHandler) GetPromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+	promotionID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	promotion, err := h.promotionService.GetPromotion(ctx, tenantID, promotionID)
+	if err != nil {
+		if status := promotionErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("promotion_id", promotionID).Msg("Failed to get promotion")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve promotion",
+		})
+	}
+
+	return c.JSON(http.StatusOK, promotion)
+}
+
+// CreatePromotion handles POST /admin/promotions
+func (h *PromotionHandler) CreatePromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	promotion, err := h.promotionService.CreatePromotion(ctx, tenantID, &req)
+	if err != nil {
+		if status := promotionErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to create promotion")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create promotion",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("promotion_id", promotion.ID).
+		Str("promotion_type", string(promotion.PromotionType)).
+		Msg("Promotion created")
+
+	return c.JSON(http.StatusCreated, promotion)
+}
+
+// UpdatePromotion handles PUT /admin/promotions/:id
+func (h *PromotionHandler) UpdatePromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+	promotionID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.PromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	promotion, err := h.promotionService.UpdatePromotion(ctx, tenantID, promotionID, &req)
+	if err != nil {
+		if status := promotionErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("promotion_id", promotionID).Msg("Failed to update promotion")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update promotion",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("promotion_id", promotion.ID).
+		Msg("Promotion updated")
+
+	return c.JSON(http.StatusOK, promotion)
+}
+
+// DeletePromotion handles DELETE /admin/promotions/:id
+func (h *PromotionHandler) DeletePromotion(c echo.Context) error {
+	ctx := c.Request().Context()
+	promotionID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	if err := h.promotionService.DeletePromotion(ctx, tenantID, promotionID); err != nil {
+		if status := promotionErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("promotion_id", promotionID).Msg("Failed to delete promotion")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete promotion",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("promotion_id", promotionID).
+		Msg("Promotion deleted")
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListOrderPromotions handles GET /admin/orders/:id/promotions
+func (h *PromotionHandler) ListOrderPromotions(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	promotions, err := h.promotionService.ListOrderPromotions(ctx, tenantID, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to list order promotions")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve order promotions",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"promotions": promotions,
+	})
+}
+
+// RegisterRoutes registers admin promotion routes
+func (h *PromotionHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/api/v1/admin/promotions")
+
+	admin.GET("", h.ListPromotions)
+	admin.POST("", h.CreatePromotion)
+	admin.GET("/:id", h.GetPromotion)
+	admin.PUT("/:id", h.UpdatePromotion)
+	admin.DELETE("/:id", h.DeletePromotion)
+
+	e.GET("/api/v1/admin/orders/:id/promotions", h.ListOrderPromotions)
+}
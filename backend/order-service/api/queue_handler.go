@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// QueueHandler serves the public "now serving" display of a tenant's daily order queue
+type QueueHandler struct {
+	queueRepo *repository.QueueRepository
+}
+
+// NewQueueHandler creates a new queue handler
+func NewQueueHandler(queueRepo *repository.QueueRepository) *QueueHandler {
+	return &QueueHandler{
+		queueRepo: queueRepo,
+	}
+}
+
+// GetQueueDisplay handles GET /public/:tenantId/queue
+// Screens at the pickup counter poll this; it shows queue numbers only.
+func (h *QueueHandler) GetQueueDisplay(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	queueDate, entries, err := h.queueRepo.GetOpenQueue(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get queue display")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve queue",
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, models.NewQueueDisplay(queueDate, entries, time.Now().UTC()))
+}
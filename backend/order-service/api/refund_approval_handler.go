@@ -0,0 +1,234 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// RefundApprovalHandler handles refund requests waiting for an approver
+// Requests are filed through POST /admin/orders/:id/payments/refund.
+type RefundApprovalHandler struct {
+	refundService *services.RefundApprovalService
+}
+
+// NewRefundApprovalHandler creates a new refund approval handler
+func NewRefundApprovalHandler(refundService *services.RefundApprovalService) *RefundApprovalHandler {
+	return &RefundApprovalHandler{
+		refundService: refundService,
+	}
+}
+
+// refundApprovalErrorStatus maps refund approval errors to HTTP status codes; 0 means unexpected
+func refundApprovalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound), errors.Is(err, models.ErrRefundApprovalNotFound),
+		errors.Is(err, models.ErrNoOnlinePayment):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrRefundRejectionNote):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrRefundApprovalForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrRefundApprovalNotPending), errors.Is(err, models.ErrPaymentNotSettled):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrRefundExceedsPayment), errors.Is(err, models.ErrRefundNotSupported):
+		return http.StatusUnprocessableEntity
+	}
+	return 0
+}
+
+// refundActor reads the staff member from the headers set by the API gateway
+func refundActor(c echo.Context) services.RefundActor {
+	name := c.Request().Header.Get("X-User-Name")
+	if name == "" {
+		name = c.Request().Header.Get("X-User-Email")
+	}
+	return services.RefundActor{
+		UserID: c.Request().Header.Get("X-User-ID"),
+		Name:   name,
+		Role:   string(middleware.GetUserRole(c)),
+	}
+}
+
+// refundApprovalError writes the error of a refund approval operation
+func refundApprovalError(c echo.Context, err error, failure string) error {
+	if code := refundApprovalErrorStatus(err); code != 0 {
+		return c.JSON(code, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	log.Error().Err(err).Str("id", c.Param("id")).Msg(failure)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": failure,
+	})
+}
+
+// ListRefundApprovals handles GET /admin/refund-requests
+// Optional status filter: pending, approved or rejected
+func (h *RefundApprovalHandler) ListRefundApprovals(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var status *models.RefundApprovalStatus
+	if param := c.QueryParam("status"); param != "" {
+		s := models.RefundApprovalStatus(param)
+		switch s {
+		case models.RefundApprovalPending, models.RefundApprovalApproved, models.RefundApprovalRejected:
+			status = &s
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid status filter",
+			})
+		}
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	approvals, err := h.refundService.ListRefundApprovals(ctx, tenantID, status, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list refund requests")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve refund requests",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"refund_requests": approvals,
+		"pagination": map[string]int{
+			"limit":  limit,
+			"offset": offset,
+			"count":  len(approvals),
+		},
+	})
+}
+
+// ListOrderRefundApprovals handles GET /admin/orders/:id/refund-requests
+func (h *RefundApprovalHandler) ListOrderRefundApprovals(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	approvals, err := h.refundService.ListOrderRefundApprovals(ctx, tenantID, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to list order refund requests")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve refund requests",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"refund_requests": approvals,
+	})
+}
+
+// GetRefundApproval handles GET /admin/refund-requests/:id
+func (h *RefundApprovalHandler) GetRefundApproval(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	approval, err := h.refundService.GetRefundApproval(ctx, tenantID, c.Param("id"))
+	if err != nil {
+		return refundApprovalError(c, err, "Failed to retrieve refund request")
+	}
+	return c.JSON(http.StatusOK, approval)
+}
+
+// ApproveRefundApproval handles POST /admin/refund-requests/:id/approve
+// Executes the refund through the gateway; requests above the threshold need an owner.
+func (h *RefundApprovalHandler) ApproveRefundApproval(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.RefundDecisionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	approval, refund, err := h.refundService.ApproveRefund(ctx, tenantID, c.Param("id"), &req, refundActor(c))
+	if err != nil {
+		return refundApprovalError(c, err, "Failed to approve refund request")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"refund_request": approval,
+		"refund":         refund,
+	})
+}
+
+// RejectRefundApproval handles POST /admin/refund-requests/:id/reject
+func (h *RefundApprovalHandler) RejectRefundApproval(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.RefundDecisionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	approval, err := h.refundService.RejectRefund(ctx, tenantID, c.Param("id"), &req, refundActor(c))
+	if err != nil {
+		return refundApprovalError(c, err, "Failed to reject refund request")
+	}
+	return c.JSON(http.StatusOK, approval)
+}
+
+// RegisterRoutes registers refund approval routes
+// Cashiers file requests through the refund endpoint but cannot see or decide the queue.
+func (h *RefundApprovalHandler) RegisterRoutes(e *echo.Echo) {
+	managers := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	e.GET("/api/v1/admin/orders/:id/refund-requests", h.ListOrderRefundApprovals, managers)
+
+	e.GET("/api/v1/admin/refund-requests", h.ListRefundApprovals, managers)
+	e.GET("/api/v1/admin/refund-requests/:id", h.GetRefundApproval, managers)
+	e.POST("/api/v1/admin/refund-requests/:id/approve", h.ApproveRefundApproval, managers)
+	e.POST("/api/v1/admin/refund-requests/:id/reject", h.RejectRefundApproval, managers)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// ReorderHandler lets guests start a new cart from one of their past orders
+type ReorderHandler struct {
+	reorderService *services.ReorderService
+	cartService    *services.CartService
+}
+
+// NewReorderHandler creates a new re-order handler
+func NewReorderHandler(reorderService *services.ReorderService, cartService *services.CartService) *ReorderHandler {
+	return &ReorderHandler{
+		reorderService: reorderService,
+		cartService:    cartService,
+	}
+}
+
+// Reorder handles POST /api/v1/public/:tenantId/orders/:orderReference/reorder
+// Returns the rebuilt cart and the items that were dropped, reduced or repriced.
+func (h *ReorderHandler) Reorder(c echo.Context) error {
+	tenantID := c.Param("tenantId")
+	orderReference := c.Param("orderReference")
+	sessionID := c.Request().Header.Get("X-Session-Id")
+
+	if sessionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
+	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.reorderService.Reorder(c.Request().Context(), tenantID, cartID, orderReference)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, "order not found")
+		case errors.Is(err, models.ErrMaxItemsExceeded):
+			return echo.NewHTTPError(http.StatusBadRequest, map[string]string{
+				"error":   "max_items_exceeded",
+				"message": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("order_reference", orderReference).Msg("Failed to re-order")
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to re-order")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// ReservationExtensionHandler lets guests extend their order's stock hold while paying
+type ReservationExtensionHandler struct {
+	extensionService *services.ReservationExtensionService
+}
+
+// NewReservationExtensionHandler creates a new reservation extension handler
+func NewReservationExtensionHandler(extensionService *services.ReservationExtensionService) *ReservationExtensionHandler {
+	return &ReservationExtensionHandler{
+		extensionService: extensionService,
+	}
+}
+
+// ExtendReservation handles POST /public/orders/:orderReference/reservation/extend
+// The order reference is the guest's proof of ownership, as on the public order page.
+func (h *ReservationExtensionHandler) ExtendReservation(c echo.Context) error {
+	ctx := c.Request().Context()
+	orderReference := c.Param("orderReference")
+
+	extension, err := h.extensionService.ExtendReservation(ctx, orderReference)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrderNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "order not found",
+			})
+		case errors.Is(err, models.ErrReservationExtensionDisabled):
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, models.ErrOrderNotAwaitingPayment),
+			errors.Is(err, models.ErrReservationExtensionLimit),
+			errors.Is(err, models.ErrReservationNotHeld):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("order_reference", orderReference).Msg("Failed to extend reservation")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to extend reservation",
+		})
+	}
+
+	return c.JSON(http.StatusOK, extension)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// SettlementReportHandler serves the daily settlement reports finance reconciles payouts with
+type SettlementReportHandler struct {
+	settlementReportService *services.SettlementReportService
+}
+
+// NewSettlementReportHandler creates a new settlement report handler
+func NewSettlementReportHandler(settlementReportService *services.SettlementReportService) *SettlementReportHandler {
+	return &SettlementReportHandler{
+		settlementReportService: settlementReportService,
+	}
+}
+
+// settlementReportErrorStatus maps settlement report errors to HTTP status codes; 0 means unexpected
+func settlementReportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidSettlementDate):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrSettlementReportNotFound):
+		return http.StatusNotFound
+	}
+	return 0
+}
+
+// GetSettlementReport handles GET /admin/settlement-reports/:date
+// The report is generated on first request if the daily job has not built it yet.
+func (h *SettlementReportHandler) GetSettlementReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	reportDate := c.Param("date")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	report, err := h.settlementReportService.GetReport(ctx, tenantID, reportDate)
+	if err != nil {
+		if status := settlementReportErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("report_date", reportDate).Msg("Failed to get settlement report")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve settlement report",
+		})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// RegenerateSettlementReport handles POST /admin/settlement-reports/:date/regenerate
+// Used after mismatches have been resolved, or when late webhooks changed the day's charges.
+func (h *SettlementReportHandler) RegenerateSettlementReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	reportDate := c.Param("date")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	report, err := h.settlementReportService.GenerateReport(ctx, tenantID, reportDate)
+	if err != nil {
+		if status := settlementReportErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("report_date", reportDate).Msg("Failed to regenerate settlement report")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to generate settlement report",
+		})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// RegisterRoutes registers settlement report routes
+func (h *SettlementReportHandler) RegisterRoutes(e *echo.Echo) {
+	managers := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	e.GET("/api/v1/admin/settlement-reports/:date", h.GetSettlementReport, managers)
+	e.POST("/api/v1/admin/settlement-reports/:date/regenerate", h.RegenerateSettlementReport, managers)
+}
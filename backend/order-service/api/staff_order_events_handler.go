@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+const (
+	// staffOrderEventsPing keeps idle connections open through proxies
+	staffOrderEventsPing = 30 * time.Second
+	// staffOrderEventsMaxDuration bounds a connection so the gateway re-checks the staff session on reconnect
+	staffOrderEventsMaxDuration = 30 * time.Minute
+	// staffOrderEventsWriteTimeout drops dashboards that stopped reading
+	staffOrderEventsWriteTimeout = 10 * time.Second
+)
+
+// staffOrderPing is the heartbeat message; dashboards ignore it
+var staffOrderPing = map[string]string{"type": "ping"}
+
+// StaffOrderEventsHandler pushes new and newly paid orders to staff dashboards over WebSocket
+type StaffOrderEventsHandler struct {
+	hub *services.StaffOrderHub
+}
+
+// NewStaffOrderEventsHandler creates a new staff order events handler
+func NewStaffOrderEventsHandler(hub *services.StaffOrderHub) *StaffOrderEventsHandler {
+	return &StaffOrderEventsHandler{hub: hub}
+}
+
+// StreamOrderEvents handles GET /admin/orders/live (WebSocket upgrade)
+// Each message is a JSON order.created or order.paid event for the session's tenant, plus a
+// periodic ping. Nothing is replayed, so dashboards should reload the order list when they
+// (re)connect.
+func (h *StaffOrderEventsHandler) StreamOrderEvents(c echo.Context) error {
+	// API Gateway injects X-Tenant-ID from authenticated session
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	server := websocket.Server{
+		// Only the API gateway reaches this service, and it checks the browser origin
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.serve(conn, tenantID)
+		},
+	}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// serve relays the tenant's events to one dashboard connection until either side goes away
+func (h *StaffOrderEventsHandler) serve(conn *websocket.Conn, tenantID string) {
+	defer conn.Close()
+
+	events, unsubscribe := h.hub.Subscribe(tenantID)
+	defer unsubscribe()
+
+	// Dashboards send nothing; reading only notices when the connection closes
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard string
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}()
+
+	ping := time.NewTicker(staffOrderEventsPing)
+	defer ping.Stop()
+	deadline := time.NewTimer(staffOrderEventsMaxDuration)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-deadline.C:
+			return
+		case <-ping.C:
+			if err := sendStaffOrderMessage(conn, staffOrderPing); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the dashboard reconnects and reloads its list
+				return
+			}
+			if err := sendStaffOrderMessage(conn, &event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendStaffOrderMessage writes one JSON message, giving up on dashboards that stopped reading
+func sendStaffOrderMessage(conn *websocket.Conn, message interface{}) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(staffOrderEventsWriteTimeout)); err != nil {
+		return err
+	}
+	return websocket.JSON.Send(conn, message)
+}
+
+// RegisterRoutes registers the staff order events route
+func (h *StaffOrderEventsHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/admin/orders/live", h.StreamOrderEvents,
+		middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier))
+}
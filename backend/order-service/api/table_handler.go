@@ -0,0 +1,375 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// TableHandler handles dine-in table management, table QR codes and table service actions
+type TableHandler struct {
+	tableService *services.TableService
+}
+
+// NewTableHandler creates a new table handler
+func NewTableHandler(tableService *services.TableService) *TableHandler {
+	return &TableHandler{
+		tableService: tableService,
+	}
+}
+
+// tableErrorStatus maps table errors to HTTP status codes; 0 means unexpected
+func tableErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrTableNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrTableNameExists),
+		errors.Is(err, models.ErrTableOccupied),
+		errors.Is(err, models.ErrTableHasUnpaidOrders):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidTable):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrTableInactive):
+		return http.StatusUnprocessableEntity
+	}
+	return 0
+}
+
+// ListTables handles GET /admin/tables
+func (h *TableHandler) ListTables(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	tables, err := h.tableService.ListTables(ctx, tenantID, c.QueryParam("active") == "true")
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list tables")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve tables",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tables": tables,
+	})
+}
+
+// GetTable handles GET /admin/tables/:id
+func (h *TableHandler) GetTable(c echo.Context) error {
+	ctx := c.Request().Context()
+	tableID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	table, err := h.tableService.GetTable(ctx, tenantID, tableID)
+	if err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to get table")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve table",
+		})
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+// CreateTable handles POST /admin/tables
+func (h *TableHandler) CreateTable(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.CreateTableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	table, err := h.tableService.CreateTable(ctx, tenantID, &req)
+	if err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to create table")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create table",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("table_id", table.ID).
+		Str("name", table.Name).
+		Msg("Table created")
+
+	return c.JSON(http.StatusCreated, table)
+}
+
+// UpdateTable handles PATCH /admin/tables/:id
+func (h *TableHandler) UpdateTable(c echo.Context) error {
+	ctx := c.Request().Context()
+	tableID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.UpdateTableRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	table, err := h.tableService.UpdateTable(ctx, tenantID, tableID, &req)
+	if err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to update table")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update table",
+		})
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+// DeleteTable handles DELETE /admin/tables/:id
+func (h *TableHandler) DeleteTable(c echo.Context) error {
+	ctx := c.Request().Context()
+	tableID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	if err := h.tableService.DeleteTable(ctx, tenantID, tableID); err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to delete table")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete table",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetTableQRCode handles GET /admin/tables/:id/qr
+// Returns a printable PNG; ?size= sets its width in pixels.
+func (h *TableHandler) GetTableQRCode(c echo.Context) error {
+	ctx := c.Request().Context()
+	tableID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	size := services.DefaultTableQRSize
+	if sizeParam := c.QueryParam("size"); sizeParam != "" {
+		parsed, err := strconv.Atoi(sizeParam)
+		if err != nil || parsed < services.MinTableQRSize || parsed > services.MaxTableQRSize {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("size must be between %d and %d", services.MinTableQRSize, services.MaxTableQRSize),
+			})
+		}
+		size = parsed
+	}
+
+	png, err := h.tableService.QRCodePNG(ctx, tenantID, tableID, size)
+	if err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to render table QR code")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to render table QR code",
+		})
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.Blob(http.StatusOK, "image/png", png)
+}
+
+// RotateTableQRCode handles POST /admin/tables/:id/qr/rotate
+func (h *TableHandler) RotateTableQRCode(c echo.Context) error {
+	ctx := c.Request().Context()
+	tableID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	table, err := h.tableService.RotateQRToken(ctx, tenantID, tableID)
+	if err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to rotate table QR code")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to rotate table QR code",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("table_id", tableID).
+		Msg("Table QR code rotated")
+
+	return c.JSON(http.StatusOK, table)
+}
+
+// MergeTables handles POST /admin/tables/:id/merge
+func (h *TableHandler) MergeTables(c echo.Context) error {
+	ctx := c.Request().Context()
+	tableID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.MergeTablesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	table, err := h.tableService.MergeTables(ctx, tenantID, tableID, req.SourceTableID)
+	if err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to merge tables")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to merge tables",
+		})
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+// CloseTable handles POST /admin/tables/:id/close
+func (h *TableHandler) CloseTable(c echo.Context) error {
+	ctx := c.Request().Context()
+	tableID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	result, err := h.tableService.CloseTable(ctx, tenantID, tableID)
+	if err != nil {
+		if status := tableErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to close table")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to close table",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetPublicTable handles GET /public/:tenantId/tables/:token
+// The guest menu resolves a scanned table QR code to the table name.
+func (h *TableHandler) GetPublicTable(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenantId")
+
+	table, err := h.tableService.ResolvePublicTable(ctx, tenantID, c.Param("token"))
+	if err != nil {
+		if errors.Is(err, models.ErrTableNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to resolve table QR code")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve table",
+		})
+	}
+
+	return c.JSON(http.StatusOK, table)
+}
+
+// RegisterRoutes registers admin table routes
+// Staff at the floor view and serve tables; only owners and managers change the floor plan.
+func (h *TableHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/api/v1/admin/tables")
+	admin.Use(middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager, middleware.RoleCashier))
+	planners := middleware.RequireRole(middleware.RoleOwner, middleware.RoleManager)
+
+	admin.GET("", h.ListTables)
+	admin.POST("", h.CreateTable, planners)
+	admin.GET("/:id", h.GetTable)
+	admin.PATCH("/:id", h.UpdateTable, planners)
+	admin.DELETE("/:id", h.DeleteTable, planners)
+	admin.GET("/:id/qr", h.GetTableQRCode)
+	admin.POST("/:id/qr/rotate", h.RotateTableQRCode, planners)
+	admin.POST("/:id/merge", h.MergeTables)
+	admin.POST("/:id/close", h.CloseTable)
+}
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+)
+
+// VoucherHandler handles voucher management and applying vouchers to carts
+type VoucherHandler struct {
+	voucherService *services.VoucherService
+	cartService    *services.CartService
+}
+
+// NewVoucherHandler creates a new voucher handler
+func NewVoucherHandler(voucherService *services.VoucherService, cartService *services.CartService) *VoucherHandler {
+	return &VoucherHandler{
+		voucherService: voucherService,
+		cartService:    cartService,
+	}
+}
+
+// voucherErrorStatus maps voucher errors to HTTP status codes; 0 means unexpected
+func voucherErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrVoucherNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrVoucherCodeExists),
+		errors.Is(err, models.ErrVoucherInUse):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrInvalidVoucher),
+		errors.Is(err, services.ErrCartEmpty):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrVoucherInactive),
+		errors.Is(err, models.ErrVoucherNotStarted),
+		errors.Is(err, models.ErrVoucherExpired),
+		errors.Is(err, models.ErrVoucherMinSubtotal),
+		errors.Is(err, models.ErrVoucherUsageLimitReached),
+		errors.Is(err, models.ErrVoucherCustomerLimitReached),
+		errors.Is(err, models.ErrVoucherCustomerPhoneRequired):
+		return http.StatusUnprocessableEntity
+	}
+	return 0
+}
+
+// ListVouchers handles GET /admin/vouchers
+func (h *VoucherHandler) ListVouchers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	vouchers, err := h.voucherService.ListVouchers(ctx, tenantID, c.QueryParam("active") == "true")
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list vouchers")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve vouchers",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"vouchers": vouchers,
+	})
+}
+
+// GetVoucher handles GET /admin/vouchers/:id
+func (h *VoucherHandler) GetVoucher(c echo.Context) error {
+	ctx := c.Request().Context()
+	voucherID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	voucher, err := h.voucherService.GetVoucher(ctx, tenantID, voucherID)
+	if err != nil {
+		if status := voucherErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("voucher_id", voucherID).Msg("Failed to get voucher")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve voucher",
+		})
+	}
+
+	return c.JSON(http.StatusOK, voucher)
+}
+
+// CreateVoucher handles POST /admin/vouchers
+func (h *VoucherHandler) CreateVoucher(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.VoucherRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	voucher, err := h.voucherService.CreateVoucher(ctx, tenantID, &req)
+	if err != nil {
+		if status := voucherErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to create voucher")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create voucher",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("voucher_id", voucher.ID).
+		Str("code", voucher.Code).
+		Msg("Voucher created")
+
+	return c.JSON(http.StatusCreated, voucher)
+}
+
+// UpdateVoucher handles PUT /admin/vouchers/:id
+func (h *VoucherHandler) UpdateVoucher(c echo.Context) error {
+	ctx := c.Request().Context()
+	voucherID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req models.VoucherRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	voucher, err := h.voucherService.UpdateVoucher(ctx, tenantID, voucherID, &req)
+	if err != nil {
+		if status := voucherErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("voucher_id", voucherID).Msg("Failed to update voucher")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update voucher",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("voucher_id", voucher.ID).
+		Msg("Voucher updated")
+
+	return c.JSON(http.StatusOK, voucher)
+}
+
+// DeleteVoucher handles DELETE /admin/vouchers/:id
+func (h *VoucherHandler) DeleteVoucher(c echo.Context) error {
+	ctx := c.Request().Context()
+	voucherID := c.Param("id")
+
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	if err := h.voucherService.DeleteVoucher(ctx, tenantID, voucherID); err != nil {
+		if status := voucherErrorStatus(err); status != 0 {
+			return c.JSON(status, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("voucher_id", voucherID).Msg("Failed to delete voucher")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete voucher",
+		})
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("voucher_id", voucherID).
+		Msg("Voucher deleted")
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ApplyVoucher handles POST /public/:tenantId/cart/voucher
+// Validates the code against the current cart and keeps it for checkout
+func (h *VoucherHandler) ApplyVoucher(c echo.Context) error {
+	tenantID := c.Param("tenantId")
+	sessionID := c.Request().Header.Get("X-Session-Id")
+
+	if sessionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
+	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var req models.ApplyVoucherRequest
+	if err := c.Bind(&req); err != nil || req.Code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "voucher code is required")
+	}
+
+	summary, err := h.voucherService.ApplyToCart(c.Request().Context(), tenantID, cartID, req.Code)
+	if err != nil {
+		if status := voucherErrorStatus(err); status != 0 {
+			return echo.NewHTTPError(status, map[string]string{
+				"error":   "voucher_not_applicable",
+				"message": err.Error(),
+			})
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to apply voucher")
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to apply voucher")
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
+
+// RemoveVoucher handles DELETE /public/:tenantId/cart/voucher
+func (h *VoucherHandler) RemoveVoucher(c echo.Context) error {
+	tenantID := c.Param("tenantId")
+	sessionID := c.Request().Header.Get("X-Session-Id")
+
+	if sessionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "X-Session-Id header required")
+	}
+	cartID, err := resolveCartID(c, h.cartService, tenantID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	cart, err := h.voucherService.RemoveFromCart(c.Request().Context(), tenantID, cartID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to remove voucher")
+	}
+
+	return c.JSON(http.StatusOK, cart)
+}
+
+// RegisterRoutes registers admin voucher routes
+func (h *VoucherHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/api/v1/admin/vouchers")
+
+	admin.GET("", h.ListVouchers)
+	admin.POST("", h.CreateVoucher)
+	admin.GET("/:id", h.GetVoucher)
+	admin.PUT("/:id", h.UpdateVoucher)
+	admin.DELETE("/:id", h.DeleteVoucher)
+}
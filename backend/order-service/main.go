@@ -12,6 +12,7 @@ import (
 	"github.com/point-of-sale-system/order-service/api"
 	"github.com/point-of-sale-system/order-service/src/config"
 	customMiddleware "github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/observability"
 	"github.com/point-of-sale-system/order-service/src/queue"
 	"github.com/point-of-sale-system/order-service/src/repository"
@@ -59,6 +60,7 @@ func main() {
 	// Middleware
 	e.Use(middleware.Recover())
 	e.Use(middleware.RequestID())
+	e.Use(customMiddleware.CorrelationID)
 	// Note: CORS is handled by API Gateway, not by individual services
 
 	// Rate limiting for public endpoints
@@ -101,16 +103,23 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to initialize AddressRepository")
 	}
 
+	// Initialize promotion engine (applied automatically whenever a cart is priced)
+	promotionRepo := repository.NewPromotionRepository(config.GetDB())
+	promotionService := services.NewPromotionService(promotionRepo)
+
 	// Initialize cart service (shared between cart handler and checkout handler)
+	// Guests who verified their phone get a cart that follows them across devices
 	ttl := time.Duration(config.GetEnvAsInt("CART_SESSION_TTL")) * time.Second
-	cartRepo := repository.NewCartRepository(config.GetRedis(), ttl)
+	customerTTL := time.Duration(config.GetEnvAsInt("CUSTOMER_CART_TTL")) * time.Second
+	cartRepo := repository.NewCartRepository(config.GetRedis(), ttl, customerTTL)
 	reservationRepo := repository.NewReservationRepository(config.GetDB())
-	cartService := services.NewCartService(cartRepo, reservationRepo, config.GetDB())
+	customerSessionRepo := repository.NewCustomerSessionRepository(config.GetRedis())
+	cartService := services.NewCartService(cartRepo, reservationRepo, promotionService, orderSettingsRepo, customerSessionRepo, config.GetDB())
 
 	// Initialize Kafka producer for notifications (needed by order service)
 	kafkaBrokers := config.GetEnvAsString("KAFKA_BROKERS")
 	brokerList := []string{kafkaBrokers}
-	kafkaProducer := queue.NewKafkaProducer(brokerList, config.GetEnvAsString("KAFKA_TOPIC"))
+	kafkaProducer := queue.NewKeyedKafkaProducer(brokerList, config.GetEnvAsString("KAFKA_TOPIC"))
 	log.Info().Strs("brokers", brokerList).Msg("Kafka producer initialized")
 
 	// Initialize dedicated Kafka producer for consent events
@@ -127,16 +136,40 @@ func main() {
 	}
 	defer auditPublisher.Close()
 
+	// Initialize voucher engine (discount codes applied on the cart and redeemed at checkout)
+	voucherRepo := repository.NewVoucherRepository(config.GetDB())
+	voucherService := services.NewVoucherService(voucherRepo, cartRepo, promotionService)
+
+	// Initialize loyalty points (earned on paid orders, redeemed at checkout)
+	loyaltyRepo := repository.NewLoyaltyRepository(config.GetDB())
+	loyaltyService := services.NewLoyaltyService(loyaltyRepo, customerSessionRepo)
+
+	// Order status changes are relayed between replicas through Redis to guests' SSE streams
+	statusBroadcaster := services.NewOrderStatusBroadcaster(config.GetRedis())
+
+	// New and newly paid orders are pushed to staff dashboards over WebSocket, also relayed through Redis
+	staffOrderHub := services.NewStaffOrderHub(config.GetRedis())
+
+	// Vault encryption for guest data and merchant webhook secrets
+	vaultEncryptor, err := utils.NewVaultClient()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize VaultClient")
+	}
+
+	// Order lifecycle events are POSTed, signed, to the tenant's own webhook URLs
+	merchantWebhookService := services.NewMerchantWebhookService(repository.NewMerchantWebhookRepository(config.GetDB(), vaultEncryptor))
+
 	// Initialize order service (with Kafka producer and all repos for event publishing)
-	orderService := services.NewOrderService(config.GetDB(), orderRepo, addressRepo, paymentRepo, kafkaProducer)
+	// Closed orders past ORDER_ARCHIVE_AFTER_MONTHS live in the monthly partitioned archive tables
+	orderArchiveRepo := repository.NewOrderArchiveRepository(config.GetDB())
+	orderService := services.NewOrderService(config.GetDB(), orderRepo, addressRepo, paymentRepo, voucherRepo, loyaltyService, kafkaProducer, statusBroadcaster, staffOrderHub, merchantWebhookService, orderArchiveRepo, auditPublisher)
 
 	// Initialize payment service (needs orderService for adding notes)
 	paymentService := services.NewPaymentService(config.GetDB(), paymentRepo, orderRepo, inventoryService, orderService)
 
 	// Initialize geocoding and delivery fee services
-	// TODO: Initialize Google Maps client properly
-	geocodingService := services.NewGeocodingService(nil, config.GetRedis())
-	deliveryFeeService := services.NewDeliveryFeeService()
+	geocodingService := services.NewGeocodingService(config.GetMapsClient(), config.GetRedis())
+	deliveryFeeService := services.NewDeliveryFeeService(repository.NewDeliveryZoneRepository(config.GetDB()))
 
 	// Initialize guest order repository with encryption
 	guestOrderRepo, err := repository.NewGuestOrderRepositoryWithVault(config.GetDB(), auditPublisher)
@@ -157,7 +190,19 @@ func main() {
 	}
 	eventPublisher := services.NewEventPublisher(config.GetDB(), eventPublisherConfig)
 	paymentCalculator := services.NewPaymentCalculator()
+	// Tenants with an order reference pattern number their orders per local day
+	referenceGenerator := services.NewOrderReferenceGenerator(repository.NewOrderReferenceRepository(config.GetDB()), orderSettingsRepo)
 	
+	// Subscription plans, invoiced monthly and paid to the platform's own Midtrans account;
+	// new orders are checked against the plan's monthly limit
+	billingService := services.NewBillingService(
+		repository.NewSubscriptionRepository(config.GetDB()),
+		config.GetBillingCredentials(),
+		auditPublisher,
+		config.GetEnvAsIntWithDefault("BILLING_GRACE_DAYS", models.DefaultBillingGraceDays),
+		config.GetEnvAsIntWithDefault("BILLING_INVOICE_LEAD_DAYS", models.DefaultBillingInvoiceLeadDays),
+	)
+
 	offlineOrderService := services.NewOfflineOrderService(
 		config.GetDB(),
 		offlineOrderRepo,
@@ -166,42 +211,225 @@ func main() {
 		outboxRepo,
 		eventPublisher,
 		paymentCalculator,
+		inventoryService,
+		referenceGenerator,
+		billingService,
 	)
 	
 	offlineOrderHandler := api.NewOfflineOrderHandler(offlineOrderService)
 
 	// Initialize handlers
 	webhookHandler := api.NewPaymentWebhookHandler(paymentService)
-	adminOrderHandler := api.NewAdminOrderHandler(orderService)
-	orderSettingsHandler := api.NewOrderSettingsHandler(orderSettingsRepo)
+	// Order edits before payment (reprice, swap stock holds, replace the gateway charge)
+	orderEditService := services.NewOrderEditService(
+		config.GetDB(),
+		orderRepo,
+		paymentRepo,
+		voucherRepo,
+		promotionService,
+		inventoryService,
+		paymentService,
+		orderService,
+	)
+	// Refunds above the tenant's threshold wait for an owner; cashiers can only file requests
+	refundApprovalService := services.NewRefundApprovalService(
+		config.GetDB(),
+		repository.NewRefundApprovalRepository(config.GetDB()),
+		orderSettingsRepo,
+		paymentService,
+		orderService,
+		kafkaProducer,
+	)
+	refundApprovalHandler := api.NewRefundApprovalHandler(refundApprovalService)
+	// Cashier shifts: opening float, cash sales and refunds, counted cash at close
+	cashierShiftService := services.NewCashierShiftService(
+		config.GetDB(),
+		repository.NewCashierShiftRepository(config.GetDB()),
+		paymentRepo,
+		orderService,
+	)
+	cashierShiftHandler := api.NewCashierShiftHandler(cashierShiftService)
+	// Returns (RMA): request, approve, receive and restock, then refund, exchange or store credit
+	orderReturnService := services.NewOrderReturnService(
+		config.GetDB(),
+		repository.NewOrderReturnRepository(config.GetDB()),
+		orderRepo,
+		reservationRepo,
+		voucherRepo,
+		refundApprovalService,
+		offlineOrderService,
+		orderService,
+	)
+	orderReturnHandler := api.NewOrderReturnHandler(orderReturnService)
+	guestCancellationService := services.NewGuestCancellationService(orderRepo, paymentRepo, orderSettingsRepo, inventoryService, paymentService, orderService)
+	guestCancellationHandler := api.NewGuestCancellationHandler(guestCancellationService)
+	// Guests still paying can extend their order's stock hold a few times
+	reservationExtensionHandler := api.NewReservationExtensionHandler(
+		services.NewReservationExtensionService(orderRepo, reservationRepo, orderSettingsRepo),
+	)
+	// Dine-in tables: QR codes link to the guest menu, seated orders are matched by table name
+	tableService := services.NewTableService(
+		config.GetDB(),
+		repository.NewTableRepository(config.GetDB()),
+		orderService,
+		config.GetEnvAsString("FRONTEND_DOMAIN"),
+	)
+	tableHandler := api.NewTableHandler(tableService)
+	// Daily queue numbers are assigned on payment; the pickup counter display reads them
+	queueHandler := api.NewQueueHandler(repository.NewQueueRepository(config.GetDB()))
+	// Order note photos and uploaded tenant logos are kept in the object storage shared with product photos, when configured
+	var attachmentStorage *services.AttachmentStorage
+	if storageConfig := config.GetStorageConfig(); storageConfig.Configured() {
+		attachmentStorage, err = services.NewAttachmentStorage(storageConfig)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize attachment storage")
+		}
+	}
+	// Receipts and kitchen tickets are rendered as ESC/POS and queued for the tenant's print agent
+	invoiceBrandingRepo := repository.NewInvoiceBrandingRepository(config.GetDB())
+	printService := services.NewPrintService(repository.NewPrintJobRepository(config.GetDB()), orderRepo, orderSettingsRepo, invoiceBrandingRepo)
+	printHandler := api.NewPrintHandler(printService)
+	// Branded PDF invoices, optionally attached to the invoice email by the notification service
+	invoiceService := services.NewInvoiceService(orderRepo, invoiceBrandingRepo, orderSettingsRepo, attachmentStorage)
+	invoiceHandler := api.NewInvoiceHandler(invoiceService)
+	// Third-party couriers for delivery orders; only providers with platform credentials are offered
+	var couriers []services.Courier
+	if creds := config.GetGoSendCredentials(); creds.Configured() {
+		couriers = append(couriers, services.NewGoSendCourier(creds))
+	}
+	if creds := config.GetGrabExpressCredentials(); creds.Configured() {
+		couriers = append(couriers, services.NewGrabExpressCourier(creds))
+	}
+	courierService := services.NewCourierService(repository.NewCourierRepository(config.GetDB()), orderRepo, addressRepo, couriers...)
+	courierHandler := api.NewCourierHandler(courierService)
+	deliveryZoneHandler := api.NewDeliveryZoneHandler(deliveryFeeService)
+	settlementReportService := services.NewSettlementReportService(config.GetDB(), paymentService)
+	settlementReportHandler := api.NewSettlementReportHandler(settlementReportService)
+	merchantWebhookHandler := api.NewMerchantWebhookHandler(merchantWebhookService)
+	orderNoteService := services.NewOrderNoteService(orderRepo, attachmentStorage)
+	// Proof of delivery: encrypted recipient name and an optional photo in the same storage
+	deliveryProofService := services.NewDeliveryProofService(
+		repository.NewDeliveryProofRepository(config.GetDB(), vaultEncryptor),
+		orderService,
+		attachmentStorage,
+	)
+	deliveryProofHandler := api.NewDeliveryProofHandler(deliveryProofService, orderService)
+	adminOrderHandler := api.NewAdminOrderHandler(orderService, paymentService, orderEditService, orderNoteService, orderSettingsRepo, refundApprovalService, deliveryProofService)
+	// Auto-complete sweeper for PAID orders; ORDER_AUTO_COMPLETE_DRY_RUN=true only reports
+	autoCompleteJob := services.NewOrderAutoCompleteJob(
+		orderService,
+		orderSettingsRepo,
+		auditPublisher,
+		config.GetEnvAsBool("ORDER_AUTO_COMPLETE_DRY_RUN", false),
+	)
+	orderSettingsHandler := api.NewOrderSettingsHandler(orderSettingsRepo, autoCompleteJob)
+	// Kitchen display (KDS): paid order stream, item prep status, bump/recall, prep metrics
+	kitchenRepo := repository.NewKitchenRepository(config.GetDB())
+	kitchenService := services.NewKitchenService(kitchenRepo, orderSettingsRepo)
+	kitchenHandler := api.NewKitchenHandler(kitchenService)
 	cartHandler := api.NewCartHandlerWithService(cartService)
+	voucherHandler := api.NewVoucherHandler(voucherService, cartService)
+	promotionHandler := api.NewPromotionHandler(promotionService)
+	loyaltyHandler := api.NewLoyaltyHandler(loyaltyService)
+	// Verified guests keep delivery addresses, geocoded once, to pick at checkout
+	customerAddressService := services.NewCustomerAddressService(
+		repository.NewCustomerAddressRepository(config.GetDB(), vaultEncryptor),
+		customerSessionRepo,
+		geocodingService,
+	)
+	customerAddressHandler := api.NewCustomerAddressHandler(customerAddressService)
 	checkoutHandler := api.NewCheckoutHandler(
 		config.GetDB(),
 		config.GetRedis(),
 		cartService,
 		inventoryService,
 		paymentService,
+		voucherService,
+		promotionService,
+		loyaltyService,
 		geocodingService,
 		deliveryFeeService,
 		addressRepo,
 		orderSettingsRepo,
 		guestOrderRepo,
+		staffOrderHub,
+		merchantWebhookService,
+		tableService,
+		customerAddressService,
+		deliveryProofService,
+		referenceGenerator,
+		billingService,
 		kafkaProducer,
 		consentProducer, // Dedicated producer for consent-events topic
 	)
 
 	// Initialize guest data handler (T144-T145)
-	vaultEncryptor, err := utils.NewVaultClient()
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to initialize VaultClient for guest data handler")
-	}
-	guestDataHandler := api.NewGuestDataHandler(config.GetDB(), vaultEncryptor, auditPublisher, kafkaProducer)
+	guestDataHandler := api.NewGuestDataHandler(config.GetDB(), vaultEncryptor, auditPublisher, kafkaProducer, attachmentStorage)
+	orderEventsHandler := api.NewOrderEventsHandler(orderService, statusBroadcaster)
+	// Returning guests verify their phone by OTP to list past orders
+	orderHistoryHandler := api.NewOrderHistoryHandler(services.NewOrderHistoryService(orderRepo, customerSessionRepo, config.GetRedis(), kafkaProducer))
+	staffOrderEventsHandler := api.NewStaffOrderEventsHandler(staffOrderHub)
+	// Carts left idle with a reminder contact get a recovery message linking back to them
+	cartAbandonmentService := services.NewCartAbandonmentService(
+		cartRepo,
+		repository.NewCartAbandonmentRepository(config.GetRedis(), vaultEncryptor),
+		repository.NewTableRepository(config.GetDB()),
+		kafkaProducer,
+		config.GetEnvAsString("FRONTEND_DOMAIN"),
+		config.GetEnvAsDuration("CART_ABANDONMENT_THRESHOLD"),
+	)
+	cartAbandonmentHandler := api.NewCartAbandonmentHandler(cartAbandonmentService, cartService)
+	// Past orders can be re-added to the cart at today's prices and stock
+	reorderHandler := api.NewReorderHandler(services.NewReorderService(orderRepo, reservationRepo, cartRepo, cartService), cartService)
+
+	billingHandler := api.NewBillingHandler(billingService)
 
 	// Start reservation cleanup job in background
 	cleanupJob := services.NewReservationCleanupJob(inventoryService)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go cleanupJob.Start(ctx)
+	go autoCompleteJob.Start(ctx)
+	go statusBroadcaster.Start(ctx)
+	go staffOrderHub.Start(ctx)
+	// QRIS charges queued while the payment gateway was down are created once it recovers
+	chargeRetryJob := services.NewChargeRetryJob(paymentService)
+	go chargeRetryJob.Start(ctx)
+	// Charges that expired without a final webhook are re-checked with the gateway
+	paymentReconciliationJob := services.NewPaymentReconciliationJob(paymentService)
+	go paymentReconciliationJob.Start(ctx)
+	// Yesterday's settlement report is built for each tenant once their day has ended
+	settlementReportJob := services.NewSettlementReportJob(settlementReportService)
+	go settlementReportJob.Start(ctx)
+	// Idle carts with a reminder contact are announced as cart.abandoned
+	cartAbandonmentJob := services.NewCartAbandonmentJob(cartAbandonmentService)
+	go cartAbandonmentJob.Start(ctx)
+	// Queued merchant webhook deliveries are sent and failed ones retried with backoff
+	merchantWebhookJob := services.NewMerchantWebhookJob(merchantWebhookService)
+	go merchantWebhookJob.Start(ctx)
+	// Paid delivery orders of tenants with automatic dispatch get a courier booked
+	courierDispatchJob := services.NewCourierDispatchJob(courierService)
+	go courierDispatchJob.Start(ctx)
+	// Closed orders older than ORDER_ARCHIVE_AFTER_MONTHS are moved to cold storage; 0 disables it
+	orderArchiveJob := services.NewOrderArchiveJob(
+		config.GetDB(),
+		orderArchiveRepo,
+		services.NewOrderPartitionService(config.GetDB()),
+		config.GetEnvAsIntWithDefault("ORDER_ARCHIVE_AFTER_MONTHS", 0),
+	)
+	go orderArchiveJob.Start(ctx)
+	// Kitchen orders running over the tenant's SLA targets are announced as order.sla_breached
+	orderSLAAlertJob := services.NewOrderSLAAlertJob(orderSettingsRepo, kitchenRepo, repository.NewOrderSLARepository(config.GetDB()), kafkaProducer)
+	go orderSLAAlertJob.Start(ctx)
+	// Renewals are invoiced, unpaid ones get a grace period, then the tenant moves to the free plan
+	billingJob := services.NewBillingJob(billingService)
+	go billingJob.Start(ctx)
+	// Tenant deletion saga: delete the tenant's orders and attachments when tenant-service asks
+	tenantEvents := queue.NewKafkaProducer(brokerList, config.GetEnvAsString("KAFKA_TENANT_EVENTS_TOPIC"))
+	defer tenantEvents.Close()
+	tenantEventHandler := services.NewTenantEventHandler(repository.NewTenantDeletionRepository(config.GetDB()), attachmentStorage, tenantEvents)
+	tenantEventsConsumer := queue.NewKafkaConsumer(brokerList, config.GetEnvAsString("KAFKA_TENANT_EVENTS_TOPIC"), serviceName, tenantEventHandler.Handle)
+	go tenantEventsConsumer.Start(ctx)
 
 	// Public cart routes (guest shopping)
 	publicCart := e.Group("/api/v1/public/:tenantId")
@@ -211,12 +439,34 @@ func main() {
 	publicCart.PATCH("/cart/items/:productId", cartHandler.UpdateItem)
 	publicCart.DELETE("/cart/items/:productId", cartHandler.RemoveItem)
 	publicCart.DELETE("/cart", cartHandler.ClearCart)
+	publicCart.POST("/cart/voucher", voucherHandler.ApplyVoucher)
+	publicCart.DELETE("/cart/voucher", voucherHandler.RemoveVoucher)
+	publicCart.PUT("/cart/contact", cartAbandonmentHandler.SaveContact)
+	publicCart.POST("/cart/resume", cartAbandonmentHandler.ResumeCart)
+	publicCart.POST("/orders/:orderReference/reorder", reorderHandler.Reorder)
+	publicCart.POST("/loyalty/balance", loyaltyHandler.GetBalance)
+	publicCart.GET("/tables/:token", tableHandler.GetPublicTable)
+	publicCart.GET("/queue", queueHandler.GetQueueDisplay)
 
 	// Public checkout routes
 	publicCart.POST("/checkout", checkoutHandler.CreateOrder)
+	publicCart.GET("/schedule/slots", checkoutHandler.GetScheduleSlots)
+	publicCart.POST("/delivery/quote", checkoutHandler.QuoteDeliveryFee)
+
+	// Public order history routes (phone OTP verification)
+	publicCart.POST("/order-history/otp", orderHistoryHandler.RequestOTP)
+	publicCart.POST("/order-history/verify", orderHistoryHandler.VerifyOTP)
+	publicCart.GET("/order-history", orderHistoryHandler.ListOrders)
+	publicCart.GET("/addresses", customerAddressHandler.ListAddresses)
+	publicCart.POST("/addresses", customerAddressHandler.SaveAddress)
+	publicCart.DELETE("/addresses/:id", customerAddressHandler.DeleteAddress)
 
 	// Public order lookup route (no tenantId needed for order reference)
 	e.GET("/api/v1/public/orders/:orderReference", checkoutHandler.GetPublicOrder)
+	e.GET("/api/v1/public/orders/:orderReference/events", orderEventsHandler.StreamOrderEvents)
+	e.GET("/api/v1/public/orders/:orderReference/invoice.pdf", invoiceHandler.GetPublicInvoice)
+	e.POST("/api/v1/public/orders/:orderReference/cancel", guestCancellationHandler.CancelOrder, customMiddleware.RateLimit())
+	e.POST("/api/v1/public/orders/:orderReference/reservation/extend", reservationExtensionHandler.ExtendReservation, customMiddleware.RateLimit())
 
 	// Guest data rights routes (T147) - public but require order_reference + email/phone verification
 	e.GET("/api/v1/public/orders/:order_reference/data", guestDataHandler.GetGuestData)
@@ -227,7 +477,24 @@ func main() {
 
 	// Admin routes (JWT auth will be added in future)
 	adminOrderHandler.RegisterRoutes(e)
+	orderReturnHandler.RegisterRoutes(e)
+	refundApprovalHandler.RegisterRoutes(e)
+	cashierShiftHandler.RegisterRoutes(e)
+	staffOrderEventsHandler.RegisterRoutes(e)
 	orderSettingsHandler.RegisterRoutes(e)
+	kitchenHandler.RegisterRoutes(e)
+	tableHandler.RegisterRoutes(e)
+	printHandler.RegisterRoutes(e)
+	invoiceHandler.RegisterRoutes(e)
+	deliveryProofHandler.RegisterRoutes(e)
+	courierHandler.RegisterRoutes(e)
+	deliveryZoneHandler.RegisterRoutes(e)
+	settlementReportHandler.RegisterRoutes(e)
+	merchantWebhookHandler.RegisterRoutes(e)
+	voucherHandler.RegisterRoutes(e)
+	promotionHandler.RegisterRoutes(e)
+	loyaltyHandler.RegisterRoutes(e)
+	billingHandler.RegisterRoutes(e)
 
 	// Offline order routes (US1-US4)
 	// Authentication is handled by API Gateway (injects X-User-ID, X-User-Role headers)
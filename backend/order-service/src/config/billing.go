@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+// BillingCredentials are the platform's own Midtrans account, which tenants pay subscription invoices to
+// Without a server key, invoices are still issued but cannot be paid online.
+type BillingCredentials struct {
+	ServerKey   string
+	Environment string // "production" or "sandbox"
+	WebhookURL  string // Override notification URL for subscription charges
+}
+
+// Configured reports whether subscription invoices can be paid
+func (c BillingCredentials) Configured() bool {
+	return c.ServerKey != ""
+}
+
+// SnapClient returns a Snap client for the platform account
+func (c BillingCredentials) SnapClient() *snap.Client {
+	env := midtrans.Sandbox
+	if c.Environment == "production" {
+		env = midtrans.Production
+	}
+
+	var snapClient snap.Client
+	snapClient.New(c.ServerKey, env)
+	if c.WebhookURL != "" {
+		snapClient.Options.SetPaymentOverrideNotification(c.WebhookURL)
+	}
+	return &snapClient
+}
+
+// GetBillingCredentials returns the platform Midtrans credentials used for subscription billing
+func GetBillingCredentials() BillingCredentials {
+	return BillingCredentials{
+		ServerKey:   os.Getenv("BILLING_MIDTRANS_SERVER_KEY"),
+		Environment: envOrDefault("BILLING_MIDTRANS_ENVIRONMENT", "sandbox"),
+		WebhookURL:  os.Getenv("BILLING_MIDTRANS_WEBHOOK_URL"),
+	}
+}
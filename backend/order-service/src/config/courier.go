@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+)
+
+// Default courier API base URLs, overridable (e.g. for the providers' sandboxes or a mock server)
+const (
+	defaultGoSendAPIURL      = "https://kilat-api.gojekapi.com"
+	defaultGrabExpressAPIURL = "https://partner-api.grab.com"
+)
+
+// CourierCredentials are the platform's partner credentials with a courier provider
+// Providers without credentials are not offered to tenants.
+type CourierCredentials struct {
+	APIURL       string
+	ClientID     string
+	ClientSecret string // GoSend Pass-Key or GrabExpress OAuth client secret
+	WebhookToken string // Shared secret the provider sends with every webhook
+}
+
+// Configured reports whether the provider can be called
+func (c CourierCredentials) Configured() bool {
+	return c.ClientID != "" && c.ClientSecret != "" && c.WebhookToken != ""
+}
+
+// GetGoSendCredentials returns the GoSend (Gojek Kilat API) credentials
+func GetGoSendCredentials() CourierCredentials {
+	return CourierCredentials{
+		APIURL:       envOrDefault("GOSEND_API_URL", defaultGoSendAPIURL),
+		ClientID:     os.Getenv("GOSEND_CLIENT_ID"),
+		ClientSecret: os.Getenv("GOSEND_PASS_KEY"),
+		WebhookToken: os.Getenv("GOSEND_WEBHOOK_TOKEN"),
+	}
+}
+
+// GetGrabExpressCredentials returns the GrabExpress Delivery API credentials
+func GetGrabExpressCredentials() CourierCredentials {
+	return CourierCredentials{
+		APIURL:       envOrDefault("GRAB_EXPRESS_API_URL", defaultGrabExpressAPIURL),
+		ClientID:     os.Getenv("GRAB_EXPRESS_CLIENT_ID"),
+		ClientSecret: os.Getenv("GRAB_EXPRESS_CLIENT_SECRET"),
+		WebhookToken: os.Getenv("GRAB_EXPRESS_WEBHOOK_TOKEN"),
+	}
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TenantDeliveryFeeConfig is how a tenant prices deliveries by distance, from tenant-service
+type TenantDeliveryFeeConfig struct {
+	TenantID        string   `json:"tenant_id"`
+	Enabled         bool     `json:"enabled"`
+	Type            string   `json:"type"` // flat or distance
+	OriginLatitude  *float64 `json:"origin_latitude"`
+	OriginLongitude *float64 `json:"origin_longitude"`
+	BaseFee         int      `json:"base_fee"`
+	PerKmFee        int      `json:"per_km_fee"`
+	IncludedKm      float64  `json:"included_km"`
+	MaxFee          int      `json:"max_fee"` // 0 means uncapped
+}
+
+// DistancePricing reports whether deliveries are priced by distance from the tenant's origin
+func (c *TenantDeliveryFeeConfig) DistancePricing() bool {
+	return c.Enabled && c.Type == "distance" && c.OriginLatitude != nil && c.OriginLongitude != nil
+}
+
+// GetDeliveryFeeConfigForTenant fetches the tenant's delivery pricing from tenant-service
+func GetDeliveryFeeConfigForTenant(ctx context.Context, tenantID string) (*TenantDeliveryFeeConfig, error) {
+	url := fmt.Sprintf("%s/api/v1/admin/tenants/%s/delivery-fee-config", tenantServiceURL, tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// tenant-service only answers for the tenant named in the header
+	req.Header.Set("X-Tenant-ID", tenantID)
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tenant delivery fee config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tenant-service returned status: %d", resp.StatusCode)
+	}
+
+	var config TenantDeliveryFeeConfig
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &config, nil
+}
@@ -18,6 +18,16 @@ func GetEnvAsInt(key string) int {
 	panic("Environment variable " + key + " is not set or is not a valid integer")
 }
 
+// GetEnvAsIntWithDefault returns an environment variable as an integer, or the default when unset or invalid
+func GetEnvAsIntWithDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intVal, err := strconv.Atoi(value); err == nil {
+			return intVal
+		}
+	}
+	return defaultValue
+}
+
 // GetEnvAsString returns an environment variable as a string with a default value
 func GetEnvAsString(key string) string {
 	if value := os.Getenv(key); value != "" {
@@ -36,3 +46,13 @@ func GetEnvAsDuration(key string) time.Duration {
 	// throw error: missing environment variable
 	panic("Environment variable " + key + " is not set or is not a valid duration")
 }
+
+// GetEnvAsBool returns an environment variable as a boolean, or the default when unset or invalid
+func GetEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Default Xendit API base URL, overridable with XENDIT_API_URL (e.g. for a mock server)
+const defaultXenditAPIURL = "https://api.xendit.co"
+
+// TenantPaymentGatewayConfig represents the tenant's gateway selection from tenant-service
+type TenantPaymentGatewayConfig struct {
+	TenantID            string `json:"tenant_id"`
+	Provider            string `json:"provider"`
+	XenditSecretKey     string `json:"xendit_secret_key"`
+	XenditCallbackToken string `json:"xendit_callback_token"`
+	XenditConfigured    bool   `json:"xendit_configured"`
+	MidtransConfigured  bool   `json:"midtrans_configured"`
+}
+
+// GetPaymentGatewayConfigForTenant fetches the tenant's payment gateway selection and Xendit credentials
+func GetPaymentGatewayConfigForTenant(ctx context.Context, tenantID string) (*TenantPaymentGatewayConfig, error) {
+	url := fmt.Sprintf("%s/api/v1/admin/tenants/%s/payment-gateway-config", tenantServiceURL, tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tenant payment gateway config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tenant-service returned status: %d", resp.StatusCode)
+	}
+
+	var config TenantPaymentGatewayConfig
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &config, nil
+}
+
+// GetXenditAPIURL returns the Xendit API base URL
+func GetXenditAPIURL() string {
+	if url := os.Getenv("XENDIT_API_URL"); url != "" {
+		return url
+	}
+	return defaultXenditAPIURL
+}
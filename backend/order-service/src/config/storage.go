@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// defaultAttachmentURLTTL is how long a presigned order attachment URL stays valid
+const defaultAttachmentURLTTL = 15 * time.Minute
+
+// StorageConfig is the object storage (S3/MinIO) shared with product-service
+// Order attachments live under their own key prefix in the same bucket.
+type StorageConfig struct {
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	BucketName      string
+	Region          string
+	UseSSL          bool
+	URLTTL          time.Duration
+}
+
+// Configured reports whether attachments can be stored; without storage they are refused
+func (c StorageConfig) Configured() bool {
+	return c.Endpoint != "" && c.BucketName != "" && c.AccessKeyID != "" && c.SecretAccessKey != ""
+}
+
+// GetStorageConfig returns the object storage configuration
+func GetStorageConfig() StorageConfig {
+	ttl := defaultAttachmentURLTTL
+	if value := os.Getenv("ATTACHMENT_URL_TTL"); value != "" {
+		ttl = GetEnvAsDuration("ATTACHMENT_URL_TTL")
+	}
+
+	return StorageConfig{
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+		AccessKeyID:     os.Getenv("S3_ACCESS_KEY"),
+		SecretAccessKey: os.Getenv("S3_SECRET_KEY"),
+		BucketName:      os.Getenv("S3_BUCKET_NAME"),
+		Region:          os.Getenv("S3_REGION"),
+		UseSSL:          GetEnvAsBool("S3_USE_SSL", false),
+		URLTTL:          ttl,
+	}
+}
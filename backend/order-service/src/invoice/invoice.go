@@ -0,0 +1,326 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register the logo decoders used by image.DecodeConfig
+	_ "image/png"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/receipt"
+)
+
+// A4 page layout in millimetres
+const (
+	pageMargin   = 15.0
+	contentWidth = 210.0 - 2*pageMargin
+	logoHeight   = 18.0
+	rowHeight    = 6.0
+	footerHeight = 12.0
+)
+
+// Item table column widths; together they span contentWidth
+var columnWidths = [4]float64{95, 20, 32.5, 32.5}
+
+type rgb struct{ r, g, b int }
+
+var (
+	textColor  = rgb{31, 41, 55}
+	mutedColor = rgb{107, 114, 128}
+	ruleColor  = rgb{229, 231, 235}
+)
+
+// Render draws an A4 PDF invoice for an order in the tenant's branding
+// logo is the tenant's PNG or JPEG logo; the header is drawn without it when it is empty or unreadable.
+// Times are printed in loc, the store timezone.
+func Render(branding *models.InvoiceBranding, logo []byte, order *models.GuestOrder, items []models.OrderItem, loc *time.Location) ([]byte, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	accent := parseColor(branding.PrimaryColor)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle("Invoice "+order.OrderReference, true)
+	pdf.SetAuthor(branding.Name(), true)
+	pdf.SetCreationDate(order.CreatedAt)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	if footer := strings.TrimSpace(branding.InvoiceFooter); footer != "" {
+		// Keep the item table clear of the footer
+		pdf.SetAutoPageBreak(true, pageMargin+footerHeight)
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(-pageMargin - footerHeight + 2)
+			setColor(pdf, mutedColor)
+			pdf.SetFont("Helvetica", "", 8)
+			pdf.MultiCell(contentWidth, 4, tr(footer), "", "C", false)
+		})
+	}
+
+	pdf.AddPage()
+
+	// Accent bar across the top of the page
+	pdf.SetFillColor(accent.r, accent.g, accent.b)
+	pdf.Rect(0, 0, 210, 5, "F")
+
+	// Merchant block, under the logo
+	y := pageMargin
+	if imageType := logoType(logo); imageType != "" {
+		options := fpdf.ImageOptions{ImageType: imageType}
+		pdf.RegisterImageOptionsReader("logo", options, bytes.NewReader(logo))
+		pdf.ImageOptions("logo", pageMargin, y, 0, logoHeight, false, options, 0, "")
+		y += logoHeight + 4
+	}
+
+	pdf.SetXY(pageMargin, y)
+	setColor(pdf, textColor)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.MultiCell(105, 7, tr(branding.Name()), "", "L", false)
+
+	setColor(pdf, mutedColor)
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range merchantDetails(branding) {
+		pdf.SetX(pageMargin)
+		pdf.MultiCell(105, 4.5, tr(line), "", "L", false)
+	}
+	merchantBottom := pdf.GetY()
+
+	// Invoice block, top right
+	pdf.SetXY(125, pageMargin)
+	pdf.SetTextColor(accent.r, accent.g, accent.b)
+	pdf.SetFont("Helvetica", "B", 22)
+	pdf.CellFormat(70, 10, "INVOICE", "", 2, "R", false, 0, "")
+	pdf.Ln(2)
+
+	details := [][2]string{
+		{"Invoice no.", order.OrderReference},
+		{"Date", order.CreatedAt.In(loc).Format("02 Jan 2006 15:04")},
+	}
+	if order.PaidAt != nil {
+		details = append(details, [2]string{"Paid", order.PaidAt.In(loc).Format("02 Jan 2006 15:04")})
+	}
+	details = append(details, [2]string{"Status", statusLabel(order.Status)})
+	for _, row := range details {
+		pdf.SetX(125)
+		setColor(pdf, mutedColor)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(25, 5, row[0], "", 0, "L", false, 0, "")
+		setColor(pdf, textColor)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(45, 5, tr(row[1]), "", 1, "R", false, 0, "")
+	}
+
+	// Billed to
+	pdf.SetY(max(merchantBottom, pdf.GetY()) + 8)
+	setColor(pdf, mutedColor)
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.CellFormat(contentWidth, 5, "BILLED TO", "", 1, "L", false, 0, "")
+	setColor(pdf, textColor)
+	pdf.SetFont("Helvetica", "", 10)
+	if order.CustomerName != "" {
+		pdf.CellFormat(contentWidth, 5, tr(order.CustomerName), "", 1, "L", false, 0, "")
+	}
+	fulfilment := fulfilmentLabel(order)
+	if order.QueueNumber != nil {
+		fulfilment += " - Queue #" + strconv.Itoa(*order.QueueNumber)
+	}
+	pdf.CellFormat(contentWidth, 5, tr(fulfilment), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	// Items
+	header := func() {
+		pdf.SetFillColor(accent.r, accent.g, accent.b)
+		onAccent := contrastColor(accent)
+		pdf.SetTextColor(onAccent.r, onAccent.g, onAccent.b)
+		pdf.SetFont("Helvetica", "B", 9)
+		for i, title := range []string{"Item", "Qty", "Unit price", "Amount"} {
+			align := "R"
+			switch i {
+			case 0:
+				align = "L"
+			case 1:
+				align = "C"
+			}
+			pdf.CellFormat(columnWidths[i], 8, title, "", 0, align, true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	header()
+
+	_, pageHeight := pdf.GetPageSize()
+	_, bottomMargin := pdf.GetAutoPageBreak()
+	pdf.SetDrawColor(ruleColor.r, ruleColor.g, ruleColor.b)
+	for _, item := range items {
+		pdf.SetFont("Helvetica", "", 9)
+		lines := pdf.SplitText(tr(item.ProductName), columnWidths[0]-2)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		height := rowHeight * float64(len(lines))
+		if pdf.GetY()+height > pageHeight-bottomMargin {
+			pdf.AddPage()
+			header()
+			pdf.SetFont("Helvetica", "", 9)
+		}
+
+		x, top := pdf.GetXY()
+		setColor(pdf, textColor)
+		pdf.MultiCell(columnWidths[0], rowHeight, strings.Join(lines, "\n"), "", "L", false)
+		pdf.SetXY(x+columnWidths[0], top)
+		pdf.CellFormat(columnWidths[1], rowHeight, strconv.Itoa(item.Quantity), "", 0, "C", false, 0, "")
+		pdf.CellFormat(columnWidths[2], rowHeight, receipt.FormatRupiah(item.UnitPrice), "", 0, "R", false, 0, "")
+		pdf.CellFormat(columnWidths[3], rowHeight, receipt.FormatRupiah(item.TotalPrice), "", 0, "R", false, 0, "")
+		pdf.Line(pageMargin, top+height, pageMargin+contentWidth, top+height)
+		pdf.SetXY(pageMargin, top+height)
+	}
+	pdf.Ln(4)
+
+	// Totals
+	total := func(label, amount string, bold bool) {
+		style, labelColor := "", mutedColor
+		if bold {
+			style, labelColor = "B", textColor
+		}
+		pdf.SetX(pageMargin + contentWidth - 85)
+		setColor(pdf, labelColor)
+		pdf.SetFont("Helvetica", style, 9)
+		pdf.CellFormat(52.5, rowHeight, tr(label), "", 0, "L", false, 0, "")
+		setColor(pdf, textColor)
+		pdf.CellFormat(32.5, rowHeight, amount, "", 1, "R", false, 0, "")
+	}
+
+	total("Subtotal", receipt.FormatRupiah(order.SubtotalAmount), false)
+	if order.PromotionDiscountAmount > 0 {
+		total("Promotion", "-"+receipt.FormatRupiah(order.PromotionDiscountAmount), false)
+	}
+	if order.DiscountAmount > 0 {
+		voucher := "Voucher"
+		if order.VoucherCode != nil {
+			voucher += " " + *order.VoucherCode
+		}
+		total(voucher, "-"+receipt.FormatRupiah(order.DiscountAmount), false)
+	}
+	if order.LoyaltyDiscountAmount > 0 {
+		total(fmt.Sprintf("Loyalty points (%d)", order.LoyaltyPointsRedeemed), "-"+receipt.FormatRupiah(order.LoyaltyDiscountAmount), false)
+	}
+	if order.DeliveryFee > 0 {
+		total("Delivery fee", receipt.FormatRupiah(order.DeliveryFee), false)
+	}
+	if order.ServiceChargeAmount > 0 {
+		total(fmt.Sprintf("Service charge (%s%%)", formatRate(order.ServiceChargeRate)), receipt.FormatRupiah(order.ServiceChargeAmount), false)
+	}
+	if order.TaxAmount > 0 {
+		total(fmt.Sprintf("Tax (%s%%)", formatRate(order.TaxRate)), receipt.FormatRupiah(order.TaxAmount), false)
+	}
+
+	pdf.SetDrawColor(accent.r, accent.g, accent.b)
+	y = pdf.GetY() + 1
+	pdf.Line(pageMargin+contentWidth-85, y, pageMargin+contentWidth, y)
+	pdf.Ln(2)
+	total("Total", receipt.FormatRupiah(order.TotalAmount), true)
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// merchantDetails are the address and contact lines under the merchant name
+func merchantDetails(branding *models.InvoiceBranding) []string {
+	lines := []string{}
+	if branding.Address != "" {
+		lines = append(lines, branding.Address)
+	}
+
+	contact := []string{}
+	for _, value := range []string{branding.Phone, branding.Email} {
+		if value != "" {
+			contact = append(contact, value)
+		}
+	}
+	if len(contact) > 0 {
+		lines = append(lines, strings.Join(contact, " | "))
+	}
+
+	if branding.TaxID != "" {
+		lines = append(lines, "NPWP "+branding.TaxID)
+	}
+	return lines
+}
+
+// statusLabel is the payment state printed on the invoice
+func statusLabel(status models.OrderStatus) string {
+	switch status {
+	case models.OrderStatusPaid, models.OrderStatusComplete:
+		return "PAID"
+	case models.OrderStatusCancelled:
+		return "CANCELLED"
+	}
+	return "UNPAID"
+}
+
+// fulfilmentLabel names how the order is handed over, with the table for dine-in
+func fulfilmentLabel(order *models.GuestOrder) string {
+	switch order.DeliveryType {
+	case models.DeliveryTypeDineIn:
+		if order.TableNumber != nil && *order.TableNumber != "" {
+			return "Dine in - Table " + *order.TableNumber
+		}
+		return "Dine in"
+	case models.DeliveryTypeDelivery:
+		return "Delivery"
+	}
+	return "Pickup"
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// logoType returns the fpdf image type of a PNG or JPEG logo, or "" when it cannot be drawn
+func logoType(logo []byte) string {
+	if len(logo) == 0 {
+		return ""
+	}
+	_, format, err := image.DecodeConfig(bytes.NewReader(logo))
+	if err != nil {
+		return ""
+	}
+	switch format {
+	case "png":
+		return "PNG"
+	case "jpeg":
+		return "JPG"
+	}
+	return ""
+}
+
+// parseColor reads a #RRGGBB color, falling back to the default invoice color
+func parseColor(hex string) rgb {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if len(hex) != 7 || err != nil {
+		value, _ = strconv.ParseUint(strings.TrimPrefix(models.DefaultInvoiceColor, "#"), 16, 32)
+	}
+	return rgb{int(value >> 16 & 0xFF), int(value >> 8 & 0xFF), int(value & 0xFF)}
+}
+
+// contrastColor picks white or dark text, whichever reads better on background
+func contrastColor(background rgb) rgb {
+	luminance := 0.299*float64(background.r) + 0.587*float64(background.g) + 0.114*float64(background.b)
+	if luminance > 160 {
+		return textColor
+	}
+	return rgb{255, 255, 255}
+}
+
+func setColor(pdf *fpdf.Fpdf, color rgb) {
+	pdf.SetTextColor(color.r, color.g, color.b)
+}
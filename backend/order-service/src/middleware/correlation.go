@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/point-of-sale-system/order-service/src/observability"
+)
+
+// CorrelationID stores the request ID in the request context so Kafka events,
+// outbox rows and audit events published while handling the request carry it.
+// Must run after echo's RequestID middleware, which sets the response header.
+func CorrelationID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		correlationID := c.Response().Header().Get(echo.HeaderXRequestID)
+		if correlationID == "" {
+			correlationID = req.Header.Get(observability.CorrelationIDHeader)
+		}
+		if correlationID != "" {
+			c.SetRequest(req.WithContext(observability.WithCorrelationID(req.Context(), correlationID)))
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrOrderNotArchived is returned when the archival read path is used for an order that still holds customer data
+var ErrOrderNotArchived = errors.New("order is not anonymized")
+
+// AnonymizedFilter controls how anonymized orders appear in admin order listings
+type AnonymizedFilter string
+
+const (
+	AnonymizedFilterInclude AnonymizedFilter = "include"
+	AnonymizedFilterExclude AnonymizedFilter = "exclude"
+	AnonymizedFilterOnly    AnonymizedFilter = "only"
+)
+
+// IsValid checks if the anonymized filter is a known value
+func (f AnonymizedFilter) IsValid() bool {
+	switch f {
+	case AnonymizedFilterInclude, AnonymizedFilterExclude, AnonymizedFilterOnly:
+		return true
+	}
+	return false
+}
+
+// RedactPII clears customer-identifying fields on anonymized orders.
+// Anonymization leaves placeholder values in the database; admin responses
+// return empty fields instead so they are never mistaken for real customers.
+func (o *GuestOrder) RedactPII() {
+	if !o.IsAnonymized {
+		return
+	}
+	o.CustomerName = ""
+	o.CustomerPhone = ""
+	o.CustomerEmail = nil
+	o.IPAddress = nil
+	o.UserAgent = nil
+	o.SessionID = ""
+}
+
+// ArchivedOrder is the operational and finance view of an anonymized order.
+// It carries amounts, items and payments but no customer linkage.
+type ArchivedOrder struct {
+	ID             string          `json:"id"`
+	OrderReference string          `json:"order_reference"`
+	TenantID       string          `json:"tenant_id"`
+	Status         OrderStatus     `json:"status"`
+	OrderType      OrderType       `json:"order_type"`
+	DeliveryType   DeliveryType    `json:"delivery_type"`
+	TableNumber    *string         `json:"table_number,omitempty"`
+	SubtotalAmount int             `json:"subtotal_amount"`
+	DeliveryFee    int             `json:"delivery_fee"`
+	TotalAmount    int             `json:"total_amount"`
+	CreatedAt      time.Time       `json:"created_at"`
+	PaidAt         *time.Time      `json:"paid_at,omitempty"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	CancelledAt    *time.Time      `json:"cancelled_at,omitempty"`
+	IsAnonymized   bool            `json:"is_anonymized"`
+	AnonymizedAt   *time.Time      `json:"anonymized_at,omitempty"`
+	Items          []OrderItem     `json:"items"`
+	Payments       []PaymentRecord `json:"payments"`
+
+	// Set once the order has been moved to cold storage; its payments are then ColdPayments
+	ColdStoredAt *time.Time    `json:"cold_stored_at,omitempty"`
+	ColdPayments []ColdPayment `json:"cold_payments,omitempty"`
+}
+
+// ColdPayment is a gateway charge or staff-recorded payment of an order in cold storage
+type ColdPayment struct {
+	ID        string     `json:"id"`
+	Source    string     `json:"source"`              // gateway or staff
+	Method    *string    `json:"method,omitempty"`    // Gateway payment type or staff payment method
+	Reference *string    `json:"reference,omitempty"` // Gateway transaction ID or staff receipt number
+	Amount    int        `json:"amount"`
+	Status    *string    `json:"status,omitempty"`
+	PaidAt    *time.Time `json:"paid_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ColdStorageCandidate is a closed order old enough to be moved to cold storage
+type ColdStorageCandidate struct {
+	OrderID   string
+	CreatedAt time.Time
+}
+
+// NewArchivedOrder builds the archival view of an anonymized order
+func NewArchivedOrder(order *GuestOrder, items []OrderItem, payments []PaymentRecord) (*ArchivedOrder, error) {
+	if !order.IsAnonymized {
+		return nil, ErrOrderNotArchived
+	}
+	if items == nil {
+		items = []OrderItem{}
+	}
+	if payments == nil {
+		payments = []PaymentRecord{}
+	}
+
+	return &ArchivedOrder{
+		ID:             order.ID,
+		OrderReference: order.OrderReference,
+		TenantID:       order.TenantID,
+		Status:         order.Status,
+		OrderType:      order.OrderType,
+		DeliveryType:   order.DeliveryType,
+		TableNumber:    order.TableNumber,
+		SubtotalAmount: order.SubtotalAmount,
+		DeliveryFee:    order.DeliveryFee,
+		TotalAmount:    order.TotalAmount,
+		CreatedAt:      order.CreatedAt,
+		PaidAt:         order.PaidAt,
+		CompletedAt:    order.CompletedAt,
+		CancelledAt:    order.CancelledAt,
+		IsAnonymized:   true,
+		AnonymizedAt:   order.AnonymizedAt,
+		Items:          items,
+		Payments:       payments,
+	}, nil
+}
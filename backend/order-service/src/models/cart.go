@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // CartItem represents an item in the guest's shopping cart
 type CartItem struct {
 	ProductID   string `json:"product_id"`
@@ -15,6 +17,13 @@ type Cart struct {
 	SessionID string     `json:"session_id"`
 	Items     []CartItem `json:"items"`
 	UpdatedAt string     `json:"updated_at"`
+
+	// VoucherCode is applied at checkout; it is re-validated against the final subtotal
+	VoucherCode string `json:"voucher_code,omitempty"`
+
+	// Promotions are recalculated whenever the cart is priced; never trusted from storage
+	Promotions        []AppliedPromotion `json:"promotions,omitempty"`
+	PromotionDiscount int                `json:"promotion_discount"`
 }
 
 // GetTotal calculates the total cart amount
@@ -26,6 +35,11 @@ func (c *Cart) GetTotal() int {
 	return total
 }
 
+// GetTotalAfterPromotions returns the cart total less automatic promotion discounts
+func (c *Cart) GetTotalAfterPromotions() int {
+	return c.GetTotal() - c.PromotionDiscount
+}
+
 // GetItemCount returns total number of items in cart
 func (c *Cart) GetItemCount() int {
 	count := 0
@@ -34,3 +48,45 @@ func (c *Cart) GetItemCount() int {
 	}
 	return count
 }
+
+// CustomerCartID is the cart ID of a guest identified by phone search hash
+// It takes the place of the session ID so the cart follows the guest across devices.
+func CustomerCartID(phoneHash string) string {
+	return "customer:" + phoneHash
+}
+
+// IsCustomerCartID reports whether a cart ID belongs to an identified guest rather than a device session
+func IsCustomerCartID(cartID string) bool {
+	return strings.HasPrefix(cartID, "customer:")
+}
+
+// Merge folds an anonymous cart into this one when its guest identifies themselves
+// A product in both carts keeps the larger quantity, so merging the same cart twice
+// changes nothing; the anonymous cart's voucher wins as the one applied most recently.
+func (c *Cart) Merge(other *Cart) {
+	if other == nil {
+		return
+	}
+
+	for _, item := range other.Items {
+		found := false
+		for i := range c.Items {
+			if c.Items[i].ProductID == item.ProductID {
+				if item.Quantity > c.Items[i].Quantity {
+					c.Items[i].Quantity = item.Quantity
+				}
+				c.Items[i].UnitPrice = item.UnitPrice
+				c.Items[i].TotalPrice = c.Items[i].Quantity * c.Items[i].UnitPrice
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.Items = append(c.Items, item)
+		}
+	}
+
+	if other.VoucherCode != "" {
+		c.VoucherCode = other.VoucherCode
+	}
+}
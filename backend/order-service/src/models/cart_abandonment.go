@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CartResumeTTL is how long a recovery link can restore an abandoned cart
+const CartResumeTTL = 7 * 24 * time.Hour
+
+// Cart abandonment errors
+var (
+	ErrCartContactRequired     = errors.New("an email or phone number is required")
+	ErrInvalidCartContactEmail = errors.New("email is not a valid email address")
+	ErrInvalidCartContactPhone = errors.New("phone must be a full phone number")
+	ErrCartResumeNotFound      = errors.New("cart link is invalid or has expired")
+)
+
+// CartContact is how a guest asked to be reminded about a cart they leave behind
+type CartContact struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// Validate normalizes the contact; at least one of email and phone is required
+func (c *CartContact) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if len(c.Name) > 100 {
+		c.Name = c.Name[:100]
+	}
+
+	if c.Email != "" {
+		c.Email = NormalizeCustomerEmail(c.Email)
+		if addr, err := mail.ParseAddress(c.Email); err != nil || addr.Address != c.Email {
+			return ErrInvalidCartContactEmail
+		}
+	}
+	if c.Phone != "" {
+		c.Phone = NormalizeCustomerPhone(c.Phone)
+		if len(c.Phone) < minSearchPhoneDigits {
+			return ErrInvalidCartContactPhone
+		}
+	}
+
+	if c.Email == "" && c.Phone == "" {
+		return ErrCartContactRequired
+	}
+	return nil
+}
+
+// CartResume is the snapshot of an abandoned cart a recovery link restores
+// It is kept apart from the cart so the link still works after the cart expires.
+type CartResume struct {
+	TenantID    string     `json:"tenant_id"`
+	Items       []CartItem `json:"items"`
+	VoucherCode string     `json:"voucher_code,omitempty"`
+}
+
+// ResumeCartRequest restores an abandoned cart into the guest's current cart
+type ResumeCartRequest struct {
+	Token string `json:"token"`
+}
+
+// CartResumeURL is the public menu link that restores an abandoned cart
+func CartResumeURL(menuBaseURL, tenantSlug, token string) string {
+	return strings.TrimRight(menuBaseURL, "/") + "/menu/" + url.PathEscape(tenantSlug) + "?resume_cart=" + url.QueryEscape(token)
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// CashierShiftStatus is whether a cashier's shift is still taking cash
+type CashierShiftStatus string
+
+const (
+	CashierShiftOpen   CashierShiftStatus = "open"
+	CashierShiftClosed CashierShiftStatus = "closed"
+)
+
+// MaxCashRefundReasonLength bounds the reason given for a cash refund
+const MaxCashRefundReasonLength = 500
+
+var (
+	ErrShiftNotFound         = errors.New("shift not found")
+	ErrShiftAlreadyOpen      = errors.New("you already have an open shift; close it before opening a new one")
+	ErrNoOpenShift           = errors.New("you have no open shift")
+	ErrShiftClosed           = errors.New("shift is already closed")
+	ErrInvalidOpeningFloat   = errors.New("opening_float must be 0 or more")
+	ErrInvalidCountedCash    = errors.New("counted_cash must be 0 or more")
+	ErrInvalidCashRefund     = errors.New("cash refund amount must be greater than 0")
+	ErrCashRefundReason      = errors.New("a reason of at most 500 characters is required for a cash refund")
+	ErrCashRefundExceedsCash = errors.New("cash refunds would exceed the cash paid for this order")
+)
+
+// CashierShift is one cashier's session on a cash drawer, from opening float to cash count
+// While the shift is open its cash totals are computed from the cash payments the cashier
+// recorded and the cash refunds paid from the drawer; closing freezes them.
+type CashierShift struct {
+	ID           string             `json:"id"`
+	TenantID     string             `json:"tenant_id"`
+	CashierID    string             `json:"cashier_id"`
+	CashierName  *string            `json:"cashier_name,omitempty"`
+	Status       CashierShiftStatus `json:"status"`
+	OpeningFloat int                `json:"opening_float"`
+	CashSales    int                `json:"cash_sales"`    // Cash payments recorded by the cashier, net of change
+	CashRefunds  int                `json:"cash_refunds"`  // Cash paid back to customers from the drawer
+	ExpectedCash int                `json:"expected_cash"` // Opening float + cash sales - cash refunds
+	CountedCash  *int               `json:"counted_cash,omitempty"`
+	Variance     *int               `json:"variance,omitempty"` // Counted - expected; negative when cash is short
+	CloseNote    *string            `json:"close_note,omitempty"`
+	ClosedBy     *string            `json:"closed_by,omitempty"`
+	ClosedByName *string            `json:"closed_by_name,omitempty"`
+	OpenedAt     time.Time          `json:"opened_at"`
+	ClosedAt     *time.Time         `json:"closed_at,omitempty"`
+}
+
+// CashRefund is cash paid back to a customer from the drawer during a shift
+type CashRefund struct {
+	ID             string    `json:"id"`
+	ShiftID        string    `json:"shift_id"`
+	OrderID        string    `json:"order_id"`
+	OrderReference string    `json:"order_reference,omitempty"`
+	Amount         int       `json:"amount"`
+	Reason         string    `json:"reason"`
+	RecordedBy     *string   `json:"recorded_by,omitempty"`
+	RecordedByName *string   `json:"recorded_by_name,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ShiftReport is a shift with the detail behind its cash totals
+type ShiftReport struct {
+	*CashierShift
+	CashSaleCount int          `json:"cash_sale_count"`
+	Refunds       []CashRefund `json:"refunds"`
+}
+
+// OpenShiftRequest opens a shift with the cash placed in the drawer
+type OpenShiftRequest struct {
+	OpeningFloat int `json:"opening_float"`
+}
+
+// CloseShiftRequest closes a shift with the cash counted in the drawer
+type CloseShiftRequest struct {
+	CountedCash *int   `json:"counted_cash"`
+	Note        string `json:"note,omitempty"`
+}
+
+// CashRefundRequest records cash paid back for an order from the open shift's drawer
+type CashRefundRequest struct {
+	OrderID string `json:"order_id"`
+	Amount  int    `json:"amount"`
+	Reason  string `json:"reason"`
+}
+
+// Validate checks the opening float
+func (r *OpenShiftRequest) Validate() error {
+	if r.OpeningFloat < 0 {
+		return ErrInvalidOpeningFloat
+	}
+	return nil
+}
+
+// Validate checks the counted cash
+func (r *CloseShiftRequest) Validate() error {
+	if r.CountedCash == nil || *r.CountedCash < 0 {
+		return ErrInvalidCountedCash
+	}
+	r.Note = strings.TrimSpace(r.Note)
+	return nil
+}
+
+// Validate checks the refund amount and trims the reason
+func (r *CashRefundRequest) Validate() error {
+	if r.Amount <= 0 {
+		return ErrInvalidCashRefund
+	}
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" || len(r.Reason) > MaxCashRefundReasonLength {
+		return ErrCashRefundReason
+	}
+	return nil
+}
+
+// SetCashTotals records the shift's cash movements and the cash the drawer should hold
+func (s *CashierShift) SetCashTotals(cashSales, cashRefunds int) {
+	s.CashSales = cashSales
+	s.CashRefunds = cashRefunds
+	s.ExpectedCash = s.OpeningFloat + cashSales - cashRefunds
+}
+
+// Reconcile records the counted cash and its difference from the expected cash
+func (s *CashierShift) Reconcile(countedCash int) {
+	variance := countedCash - s.ExpectedCash
+	s.CountedCash = &countedCash
+	s.Variance = &variance
+}
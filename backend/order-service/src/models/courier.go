@@ -0,0 +1,245 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CourierProvider identifies the third-party courier a delivery is booked with
+type CourierProvider string
+
+const (
+	CourierGoSend      CourierProvider = "gosend"
+	CourierGrabExpress CourierProvider = "grabexpress"
+)
+
+// CourierServiceType is how fast the courier delivers
+type CourierServiceType string
+
+const (
+	CourierServiceInstant CourierServiceType = "instant"
+	CourierServiceSameDay CourierServiceType = "same_day"
+)
+
+// DeliveryStatus is where a courier delivery is, mapped from the provider's own vocabulary
+type DeliveryStatus string
+
+const (
+	DeliveryRequesting     DeliveryStatus = "requesting"     // Booking sent, the provider has not answered yet
+	DeliveryFindingDriver  DeliveryStatus = "finding_driver" // Booked, waiting for a driver to accept
+	DeliveryDriverAssigned DeliveryStatus = "driver_assigned"
+	DeliveryPickedUp       DeliveryStatus = "picked_up"
+	DeliveryDelivered      DeliveryStatus = "delivered"
+	DeliveryCancelled      DeliveryStatus = "cancelled" // By staff or the provider
+	DeliveryFailed         DeliveryStatus = "failed"    // Booking refused, no driver found or undeliverable
+)
+
+const (
+	// CourierBookingTimeout is how long a booking may stay requesting before it is treated as failed
+	CourierBookingTimeout = 5 * time.Minute
+	// CourierDispatchLeadTime is how long before a scheduled delivery slot a courier is booked automatically
+	CourierDispatchLeadTime = 30 * time.Minute
+)
+
+var (
+	ErrInvalidCourierSettings  = errors.New("invalid courier settings")
+	ErrCourierNotConfigured    = errors.New("courier delivery is not configured")
+	ErrCourierUnavailable      = errors.New("courier provider is unavailable")
+	ErrCourierBookingNotFound  = errors.New("courier booking not found")
+	ErrCourierBookingRejected  = errors.New("courier rejected the booking")
+	ErrCourierBookingActive    = errors.New("order already has an active courier booking")
+	ErrCourierBookingFinished  = errors.New("courier booking can no longer be cancelled")
+	ErrOrderNotDispatchable    = errors.New("only paid delivery orders can be dispatched")
+	ErrInvalidCourierWebhook   = errors.New("invalid courier webhook")
+	ErrDeliveryAddressUnusable = errors.New("delivery address has no coordinates")
+)
+
+// IsValid checks if the courier provider is supported
+func (p CourierProvider) IsValid() bool {
+	switch p {
+	case CourierGoSend, CourierGrabExpress:
+		return true
+	}
+	return false
+}
+
+// DisplayName is the provider's brand name, used in order notes
+func (p CourierProvider) DisplayName() string {
+	switch p {
+	case CourierGoSend:
+		return "GoSend"
+	case CourierGrabExpress:
+		return "GrabExpress"
+	}
+	return string(p)
+}
+
+// IsValid checks if the service type is supported
+func (t CourierServiceType) IsValid() bool {
+	switch t {
+	case CourierServiceInstant, CourierServiceSameDay:
+		return true
+	}
+	return false
+}
+
+// IsFinal reports whether the delivery can no longer change
+func (s DeliveryStatus) IsFinal() bool {
+	switch s {
+	case DeliveryDelivered, DeliveryCancelled, DeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// progress orders the non-final statuses so late webhooks cannot move a delivery backwards
+func (s DeliveryStatus) progress() int {
+	switch s {
+	case DeliveryRequesting:
+		return 0
+	case DeliveryFindingDriver:
+		return 1
+	case DeliveryDriverAssigned:
+		return 2
+	case DeliveryPickedUp:
+		return 3
+	}
+	return 4
+}
+
+// CanMoveTo reports whether a delivery in status s accepts an update to next
+// Final statuses never change; otherwise a delivery only moves forward or ends.
+func (s DeliveryStatus) CanMoveTo(next DeliveryStatus) bool {
+	if s.IsFinal() {
+		return false
+	}
+	return next.IsFinal() || next.progress() > s.progress()
+}
+
+// CourierSettings is how a tenant's delivery orders are handed to couriers
+type CourierSettings struct {
+	TenantID        string             `json:"tenant_id"`
+	Provider        *CourierProvider   `json:"provider"` // Nil disables courier booking
+	AutoDispatch    bool               `json:"auto_dispatch"`
+	ServiceType     CourierServiceType `json:"service_type"`
+	PickupName      string             `json:"pickup_name"`
+	PickupPhone     string             `json:"pickup_phone"`
+	PickupAddress   string             `json:"pickup_address"`
+	PickupLatitude  *float64           `json:"pickup_latitude"`
+	PickupLongitude *float64           `json:"pickup_longitude"`
+	PickupNotes     string             `json:"pickup_notes"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// UpdateCourierSettingsRequest changes the fields that are set
+// An empty provider turns courier booking off.
+type UpdateCourierSettingsRequest struct {
+	Provider        *string             `json:"provider"`
+	AutoDispatch    *bool               `json:"auto_dispatch"`
+	ServiceType     *CourierServiceType `json:"service_type"`
+	PickupName      *string             `json:"pickup_name"`
+	PickupPhone     *string             `json:"pickup_phone"`
+	PickupAddress   *string             `json:"pickup_address"`
+	PickupLatitude  *float64            `json:"pickup_latitude"`
+	PickupLongitude *float64            `json:"pickup_longitude"`
+	PickupNotes     *string             `json:"pickup_notes"`
+}
+
+// Apply copies the set fields of req onto s and validates the result
+func (s *CourierSettings) Apply(req *UpdateCourierSettingsRequest) error {
+	if req.Provider != nil {
+		s.Provider = nil
+		if *req.Provider != "" {
+			provider := CourierProvider(*req.Provider)
+			if !provider.IsValid() {
+				return fmt.Errorf("%w: provider must be gosend or grabexpress", ErrInvalidCourierSettings)
+			}
+			s.Provider = &provider
+		}
+	}
+	if req.AutoDispatch != nil {
+		s.AutoDispatch = *req.AutoDispatch
+	}
+	if req.ServiceType != nil {
+		if !req.ServiceType.IsValid() {
+			return fmt.Errorf("%w: service_type must be instant or same_day", ErrInvalidCourierSettings)
+		}
+		s.ServiceType = *req.ServiceType
+	}
+	set := func(dst *string, src *string) {
+		if src != nil {
+			*dst = strings.TrimSpace(*src)
+		}
+	}
+	set(&s.PickupName, req.PickupName)
+	set(&s.PickupPhone, req.PickupPhone)
+	set(&s.PickupAddress, req.PickupAddress)
+	set(&s.PickupNotes, req.PickupNotes)
+	if req.PickupLatitude != nil {
+		s.PickupLatitude = req.PickupLatitude
+	}
+	if req.PickupLongitude != nil {
+		s.PickupLongitude = req.PickupLongitude
+	}
+
+	if s.PickupLatitude != nil && (*s.PickupLatitude < -90 || *s.PickupLatitude > 90) {
+		return fmt.Errorf("%w: pickup_latitude must be between -90 and 90", ErrInvalidCourierSettings)
+	}
+	if s.PickupLongitude != nil && (*s.PickupLongitude < -180 || *s.PickupLongitude > 180) {
+		return fmt.Errorf("%w: pickup_longitude must be between -180 and 180", ErrInvalidCourierSettings)
+	}
+	if s.Provider != nil {
+		if s.PickupName == "" || s.PickupPhone == "" || s.PickupAddress == "" || s.PickupLatitude == nil || s.PickupLongitude == nil {
+			return fmt.Errorf("%w: pickup_name, pickup_phone, pickup_address and pickup coordinates are required to book couriers", ErrInvalidCourierSettings)
+		}
+	}
+	return nil
+}
+
+// CourierBooking is a courier booked for a delivery order
+type CourierBooking struct {
+	ID             string             `json:"id"`
+	TenantID       string             `json:"tenant_id"`
+	OrderID        string             `json:"order_id"`
+	OrderReference string             `json:"order_reference"`
+	Provider       CourierProvider    `json:"provider"`
+	ServiceType    CourierServiceType `json:"service_type"`
+	Status         DeliveryStatus     `json:"status"`
+	ExternalID     *string            `json:"external_id,omitempty"` // Provider booking reference
+	TrackingURL    *string            `json:"tracking_url,omitempty"`
+	DriverName     *string            `json:"driver_name,omitempty"`
+	DriverPhone    *string            `json:"driver_phone,omitempty"`
+	VehiclePlate   *string            `json:"vehicle_plate,omitempty"`
+	QuotedFee      *int               `json:"quoted_fee,omitempty"`
+	FinalFee       *int               `json:"final_fee,omitempty"`
+	LastError      *string            `json:"last_error,omitempty"`
+	AutoDispatched bool               `json:"auto_dispatched"`
+	BookedAt       *time.Time         `json:"booked_at,omitempty"`
+	PickedUpAt     *time.Time         `json:"picked_up_at,omitempty"`
+	DeliveredAt    *time.Time         `json:"delivered_at,omitempty"`
+	CancelledAt    *time.Time         `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// CourierUpdate is a provider webhook normalized to our delivery statuses
+type CourierUpdate struct {
+	Provider     CourierProvider
+	ExternalID   string
+	RawStatus    string
+	Status       DeliveryStatus
+	TrackingURL  string
+	DriverName   string
+	DriverPhone  string
+	VehiclePlate string
+	Fee          int    // 0 when the webhook carries no fee
+	Reason       string // Why a delivery was cancelled or failed
+}
+
+// CourierDispatchCandidate is a paid delivery order waiting for an automatic courier booking
+type CourierDispatchCandidate struct {
+	TenantID string
+	OrderID  string
+}
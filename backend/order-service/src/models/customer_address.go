@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxCustomerAddresses is how many delivery addresses a guest can keep with a tenant
+// Addresses remembered at checkout push out the least recently used one.
+const MaxCustomerAddresses = 10
+
+// Saved address errors
+var (
+	ErrCustomerAddressNotFound = errors.New("saved address not found")
+	ErrInvalidCustomerAddress  = errors.New("address must be at least 10 characters")
+	ErrCustomerAddressLimit    = errors.New("saved address limit reached; remove an address first")
+)
+
+// CustomerAddress is a delivery address kept for a guest who verified their phone
+// The coordinates are the geocoding result from when it was saved, so picking the
+// address at checkout needs no new lookup.
+type CustomerAddress struct {
+	ID              string    `json:"id"`
+	TenantID        string    `json:"-"`
+	PhoneHash       string    `json:"-"`
+	Label           *string   `json:"label,omitempty"`
+	FullAddress     string    `json:"full_address"`
+	Latitude        *float64  `json:"latitude,omitempty"`
+	Longitude       *float64  `json:"longitude,omitempty"`
+	GeocodedAddress *string   `json:"geocoded_address,omitempty"`
+	LastUsedAt      time.Time `json:"last_used_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// HasCoordinates reports whether the address was located when it was saved
+func (a *CustomerAddress) HasCoordinates() bool {
+	return a.Latitude != nil && a.Longitude != nil
+}
+
+// SaveCustomerAddressRequest saves a delivery address for later checkouts
+type SaveCustomerAddressRequest struct {
+	Label   string `json:"label,omitempty"` // e.g. "Home", "Office"
+	Address string `json:"address"`
+}
+
+// Validate trims the request and checks the address is complete enough to deliver to
+func (r *SaveCustomerAddressRequest) Validate() error {
+	r.Label = strings.TrimSpace(r.Label)
+	if len(r.Label) > 50 {
+		r.Label = r.Label[:50]
+	}
+	r.Address = strings.TrimSpace(r.Address)
+	if len(r.Address) < 10 {
+		return ErrInvalidCustomerAddress
+	}
+	return nil
+}
+
+// NormalizeAddressText lowercases an address and collapses its whitespace
+// Two spellings that differ only in case or spacing are saved as one address.
+func NormalizeAddressText(address string) string {
+	return strings.ToLower(strings.Join(strings.Fields(address), " "))
+}
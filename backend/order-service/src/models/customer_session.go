@@ -0,0 +1,20 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// CustomerSessionTTL is how long a device stays identified after its guest verifies their phone
+const CustomerSessionTTL = 24 * time.Hour
+
+// ErrCustomerSessionRequired is returned for a missing, expired or other-tenant customer session token
+var ErrCustomerSessionRequired = errors.New("customer session is missing or has expired")
+
+// CustomerSession identifies a guest who verified their phone by OTP
+// The token is sent as a bearer token to list past orders and to use the guest's
+// cross-device cart.
+type CustomerSession struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -17,6 +17,7 @@ type DeliveryAddress struct {
 	CalculatedFee        int       `json:"calculated_fee"`
 	DistanceKm           *float64  `json:"distance_km,omitempty"`
 	ZoneID               *string   `json:"zone_id,omitempty"`
+	ZoneName             *string   `json:"zone_name,omitempty"` // Zone name at checkout
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
 }
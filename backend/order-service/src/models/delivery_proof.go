@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxRecipientNameLength caps the recipient name recorded with a proof of delivery
+const MaxRecipientNameLength = 255
+
+// Proof of delivery errors
+var (
+	ErrDeliveryProofRecipient = fmt.Errorf("recipient_name is required and must be at most %d characters", MaxRecipientNameLength)
+	ErrDeliveryProofExists    = errors.New("order already has a proof of delivery")
+	ErrOrderNotDeliverable    = errors.New("only paid or completed delivery orders take a proof of delivery")
+)
+
+// DeliveryProof is what the driver or staff member recorded when handing over a delivery order
+// The recipient name is stored encrypted; PhotoURL is presigned when the proof is read and is never stored.
+type DeliveryProof struct {
+	ID               string    `json:"id"`
+	OrderID          string    `json:"order_id"`
+	RecipientName    string    `json:"recipient_name"`
+	PhotoStorageKey  *string   `json:"-"`
+	PhotoURL         string    `json:"photo_url,omitempty"`
+	CapturedByUserID *string   `json:"captured_by_user_id,omitempty"`
+	CapturedByName   *string   `json:"captured_by_name,omitempty"`
+	DeliveredAt      time.Time `json:"delivered_at"`
+}
+
+// ForGuest returns the proof as shown on the guest order page, without staff details
+func (p *DeliveryProof) ForGuest() *DeliveryProof {
+	return &DeliveryProof{
+		ID:            p.ID,
+		OrderID:       p.OrderID,
+		RecipientName: p.RecipientName,
+		PhotoURL:      p.PhotoURL,
+		DeliveredAt:   p.DeliveredAt,
+	}
+}
+
+// NormalizeRecipientName trims the recipient name and checks it is present and not too long
+func NormalizeRecipientName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > MaxRecipientNameLength {
+		return "", ErrDeliveryProofRecipient
+	}
+	return name, nil
+}
+
+// CanTakeDeliveryProof reports whether an order can be handed over with a proof of delivery
+func (o *GuestOrder) CanTakeDeliveryProof() bool {
+	if o.DeliveryType != DeliveryTypeDelivery {
+		return false
+	}
+	return o.Status == OrderStatusPaid || o.Status == OrderStatusComplete
+}
+
+// DeliveryProofStorageKey returns where a proof of delivery photo is stored
+// Format: delivery-proofs/{tenant_id}/{order_id}/{proof_id}{ext}
+func DeliveryProofStorageKey(tenantID, orderID, proofID, contentType string) string {
+	return fmt.Sprintf("delivery-proofs/%s/%s/%s%s", tenantID, orderID, proofID, noteAttachmentExtensions[contentType])
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeliveryZoneType is how a delivery zone's area is described
+type DeliveryZoneType string
+
+const (
+	DeliveryZoneRadius  DeliveryZoneType = "radius"  // Band between min and max radius around a center point
+	DeliveryZonePolygon DeliveryZoneType = "polygon" // Area inside a polygon
+)
+
+var (
+	ErrInvalidDeliveryZone       = errors.New("invalid delivery zone")
+	ErrDeliveryZoneNotFound      = errors.New("delivery zone not found")
+	ErrDeliveryZoneNameExists    = errors.New("a delivery zone with this name already exists")
+	ErrOutsideDeliveryZones      = errors.New("delivery address is outside the delivery area")
+	ErrDeliveryAddressNotLocated = errors.New("delivery address could not be located")
+)
+
+// DeliveryZone is an area a tenant delivers to and the fee charged there
+type DeliveryZone struct {
+	ID              string           `json:"id"`
+	TenantID        string           `json:"tenant_id"`
+	Name            string           `json:"name"`
+	Type            DeliveryZoneType `json:"zone_type"`
+	CenterLatitude  *float64         `json:"center_latitude,omitempty"`
+	CenterLongitude *float64         `json:"center_longitude,omitempty"`
+	MinRadiusKm     float64          `json:"min_radius_km"`
+	MaxRadiusKm     *float64         `json:"max_radius_km,omitempty"`
+	Polygon         []LatLng         `json:"polygon,omitempty"`
+	Fee             int              `json:"fee"`      // In IDR
+	Priority        int              `json:"priority"` // Lowest wins when zones overlap
+	IsActive        bool             `json:"is_active"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// DeliveryZoneRequest creates a delivery zone or replaces its settings
+type DeliveryZoneRequest struct {
+	Name            string           `json:"name"`
+	Type            DeliveryZoneType `json:"zone_type"`
+	CenterLatitude  *float64         `json:"center_latitude,omitempty"`
+	CenterLongitude *float64         `json:"center_longitude,omitempty"`
+	MinRadiusKm     float64          `json:"min_radius_km"`
+	MaxRadiusKm     *float64         `json:"max_radius_km,omitempty"`
+	Polygon         []LatLng         `json:"polygon,omitempty"`
+	Fee             int              `json:"fee"`
+	Priority        int              `json:"priority"`
+	IsActive        *bool            `json:"is_active,omitempty"` // Defaults to true
+}
+
+// Validate trims the name and checks the zone's shape and fee
+func (r *DeliveryZoneRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || len(r.Name) > 100 {
+		return fmt.Errorf("%w: name must be 1-100 characters", ErrInvalidDeliveryZone)
+	}
+	if r.Fee < 0 {
+		return fmt.Errorf("%w: fee must be non-negative", ErrInvalidDeliveryZone)
+	}
+
+	switch r.Type {
+	case DeliveryZoneRadius:
+		if r.CenterLatitude == nil || r.CenterLongitude == nil {
+			return fmt.Errorf("%w: radius zones need center_latitude and center_longitude", ErrInvalidDeliveryZone)
+		}
+		if !validLatLng(*r.CenterLatitude, *r.CenterLongitude) {
+			return fmt.Errorf("%w: center coordinates are out of range", ErrInvalidDeliveryZone)
+		}
+		if r.MinRadiusKm < 0 {
+			return fmt.Errorf("%w: min_radius_km must be non-negative", ErrInvalidDeliveryZone)
+		}
+		if r.MaxRadiusKm == nil || *r.MaxRadiusKm <= r.MinRadiusKm || *r.MaxRadiusKm > 9999 {
+			return fmt.Errorf("%w: max_radius_km must be greater than min_radius_km and at most 9999", ErrInvalidDeliveryZone)
+		}
+		r.Polygon = nil
+	case DeliveryZonePolygon:
+		if len(r.Polygon) < 3 {
+			return fmt.Errorf("%w: polygon zones need at least 3 points", ErrInvalidDeliveryZone)
+		}
+		for _, point := range r.Polygon {
+			if !validLatLng(point.Latitude, point.Longitude) {
+				return fmt.Errorf("%w: polygon coordinates are out of range", ErrInvalidDeliveryZone)
+			}
+		}
+		r.CenterLatitude, r.CenterLongitude, r.MaxRadiusKm = nil, nil, nil
+		r.MinRadiusKm = 0
+	default:
+		return fmt.Errorf("%w: zone_type must be radius or polygon", ErrInvalidDeliveryZone)
+	}
+
+	return nil
+}
+
+func validLatLng(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxTableNameLength matches guest_orders.table_number
+const MaxTableNameLength = 50
+
+var (
+	ErrInvalidTable         = errors.New("invalid table")
+	ErrTableNotFound        = errors.New("table not found")
+	ErrTableNameExists      = errors.New("a table with this name already exists")
+	ErrTableInactive        = errors.New("table is not in use")
+	ErrTableOccupied        = errors.New("table has open orders; close it first")
+	ErrTableHasUnpaidOrders = errors.New("table has unpaid orders; collect payment or cancel them first")
+	ErrUnknownTable         = errors.New("unknown table")
+)
+
+// DiningTable is a dine-in table guests are seated at
+// Orders are seated at a table by their table_number, matched case-insensitively.
+type DiningTable struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	Capacity  *int      `json:"capacity,omitempty"`
+	QRToken   string    `json:"qr_token"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableOccupancy is a table with the orders currently open at it
+// Open orders are dine-in orders that are still PENDING or PAID.
+type TableOccupancy struct {
+	DiningTable
+	Occupied      bool       `json:"occupied"`
+	OpenOrders    int        `json:"open_orders"`
+	UnpaidOrders  int        `json:"unpaid_orders"`
+	OpenAmount    int        `json:"open_amount"`              // Total of the open orders
+	OccupiedSince *time.Time `json:"occupied_since,omitempty"` // When the oldest open order was placed
+}
+
+// TableOrder is an open order seated at a table
+type TableOrder struct {
+	ID             string      `json:"id"`
+	OrderReference string      `json:"order_reference"`
+	Status         OrderStatus `json:"status"`
+	TotalAmount    int         `json:"total_amount"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// TableDetail is a table with its open orders
+type TableDetail struct {
+	TableOccupancy
+	Orders []TableOrder `json:"orders"`
+}
+
+// PublicTable is what a guest's menu learns from a table QR code
+type PublicTable struct {
+	Name string `json:"name"`
+}
+
+// CreateTableRequest adds a table
+type CreateTableRequest struct {
+	Name     string `json:"name"`
+	Capacity *int   `json:"capacity,omitempty"`
+}
+
+// Validate trims the name and checks the table fields
+func (r *CreateTableRequest) Validate() error {
+	r.Name = NormalizeTableName(r.Name)
+	if err := validateTableName(r.Name); err != nil {
+		return err
+	}
+	return validateTableCapacity(r.Capacity)
+}
+
+// UpdateTableRequest renames, resizes or (de)activates a table; omitted fields are kept
+type UpdateTableRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Capacity *int    `json:"capacity,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+// Validate trims the name and checks the changed fields
+func (r *UpdateTableRequest) Validate() error {
+	if r.Name != nil {
+		name := NormalizeTableName(*r.Name)
+		r.Name = &name
+		if err := validateTableName(name); err != nil {
+			return err
+		}
+	}
+	return validateTableCapacity(r.Capacity)
+}
+
+func validateTableName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTable)
+	}
+	if utf8.RuneCountInString(name) > MaxTableNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidTable, MaxTableNameLength)
+	}
+	return nil
+}
+
+func validateTableCapacity(capacity *int) error {
+	if capacity != nil && *capacity <= 0 {
+		return fmt.Errorf("%w: capacity must be positive", ErrInvalidTable)
+	}
+	return nil
+}
+
+// MergeTablesRequest moves another table's open orders onto the table in the path
+type MergeTablesRequest struct {
+	SourceTableID string `json:"source_table_id"`
+}
+
+// CloseTableResult lists the orders completed when a table was closed
+type CloseTableResult struct {
+	TableID         string   `json:"table_id"`
+	CompletedOrders []string `json:"completed_orders"` // Order references
+}
+
+// NormalizeTableName trims a table name as typed by staff or a guest
+func NormalizeTableName(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// TableQRURL is the public menu link encoded in a table's QR code
+// The menu resolves the token to the table and pre-fills it at checkout.
+func TableQRURL(menuBaseURL, tenantSlug, qrToken string) string {
+	return strings.TrimRight(menuBaseURL, "/") + "/menu/" + url.PathEscape(tenantSlug) + "?table=" + url.QueryEscape(qrToken)
+}
@@ -18,6 +18,8 @@ type EventOutbox struct {
 	PublishedAt  *time.Time      `json:"published_at,omitempty"` // NULL = pending, NOT NULL = published
 	RetryCount   int             `json:"retry_count"`
 	LastError    *string         `json:"last_error,omitempty"`
+	// CorrelationID is the request ID of the HTTP request that produced the event
+	CorrelationID *string `json:"correlation_id,omitempty"`
 }
 
 // EventPayload represents the structure of event_payload JSONB
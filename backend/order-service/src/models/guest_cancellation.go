@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxGuestCancelWindowMinutes is the longest window a tenant can give guests to cancel
+const MaxGuestCancelWindowMinutes = 1440
+
+// Guest cancellation errors
+var (
+	ErrInvalidGuestCancelWindow  = errors.New("guest_cancel_window_minutes must be between 0 and 1440")
+	ErrGuestCancelNotAllowed     = errors.New("only unpaid orders can be cancelled")
+	ErrGuestCancelWindowClosed   = errors.New("the time to cancel this order has passed")
+	ErrGuestCancelPaymentStarted = errors.New("the order has already been paid")
+)
+
+// ValidateGuestCancellation checks the guest cancellation window if it is being changed
+func (r *UpdateOrderSettingsRequest) ValidateGuestCancellation() error {
+	if r.GuestCancelWindowMinutes != nil && (*r.GuestCancelWindowMinutes < 0 || *r.GuestCancelWindowMinutes > MaxGuestCancelWindowMinutes) {
+		return ErrInvalidGuestCancelWindow
+	}
+	return nil
+}
+
+// GuestCancelDeadline returns until when a guest may cancel the order themselves
+// ok is false when guest cancellation is disabled or the order is no longer PENDING.
+func (s *OrderSettings) GuestCancelDeadline(order *GuestOrder) (deadline time.Time, ok bool) {
+	if s.GuestCancelWindowMinutes <= 0 || order.Status != OrderStatusPending || order.OrderType == OrderTypeOffline {
+		return time.Time{}, false
+	}
+	return order.CreatedAt.Add(time.Duration(s.GuestCancelWindowMinutes) * time.Minute), true
+}
+
+// CheckGuestCancel returns why a guest may not cancel the order at now, or nil
+func (s *OrderSettings) CheckGuestCancel(order *GuestOrder, now time.Time) error {
+	deadline, ok := s.GuestCancelDeadline(order)
+	if !ok {
+		if order.Status == OrderStatusPending && order.OrderType != OrderTypeOffline {
+			return ErrGuestCancelWindowClosed
+		}
+		return ErrGuestCancelNotAllowed
+	}
+	if now.After(deadline) {
+		return ErrGuestCancelWindowClosed
+	}
+	return nil
+}
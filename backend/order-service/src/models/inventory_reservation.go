@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"sort"
 	"time"
 )
 
@@ -14,6 +16,27 @@ const (
 	ReservationStatusReleased  ReservationStatus = "released"
 )
 
+// Inventory errors shared by every order channel
+var (
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrProductUnavailable = errors.New("product not found or unavailable")
+)
+
+// Reservation extension errors
+var (
+	ErrReservationExtensionDisabled = errors.New("this store does not allow extending reservations")
+	ErrReservationExtensionLimit    = errors.New("this order's reservation cannot be extended any further")
+	ErrReservationNotHeld           = errors.New("this order no longer has reserved stock to extend")
+	ErrOrderNotAwaitingPayment      = errors.New("only unpaid orders can extend their reservation")
+)
+
+// ReservationExtension is the result of extending an order's stock hold
+type ReservationExtension struct {
+	OrderReference      string    `json:"order_reference"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	ExtensionsRemaining int       `json:"extensions_remaining"`
+}
+
 // InventoryReservation represents a temporary hold on product inventory
 type InventoryReservation struct {
 	ID         string            `json:"id"`
@@ -40,3 +63,60 @@ func (s *ReservationStatus) Scan(value interface{}) error {
 	*s = ReservationStatus(value.(string))
 	return nil
 }
+
+// ReservationLine is a quantity of one product to hold or allocate for an order
+type ReservationLine struct {
+	ProductID   string
+	ProductName string
+	Quantity    int
+}
+
+// StockShortfall describes a line that was allocated beyond the stock on hand
+type StockShortfall struct {
+	ProductID   string
+	ProductName string
+	Requested   int
+	Shortfall   int
+}
+
+// MergeReservationLines sums the quantities of lines for the same product
+// Lines are returned ordered by product ID so row locks are always taken in the same order
+func MergeReservationLines(lines []ReservationLine) []ReservationLine {
+	byProduct := make(map[string]int, len(lines))
+	merged := make([]ReservationLine, 0, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			continue
+		}
+		if i, ok := byProduct[line.ProductID]; ok {
+			merged[i].Quantity += line.Quantity
+			continue
+		}
+		byProduct[line.ProductID] = len(merged)
+		merged = append(merged, line)
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ProductID < merged[j].ProductID })
+	return merged
+}
+
+// UncoveredReservationLines returns the part of each line not already held or allocated
+// by the order's active and converted reservations
+func UncoveredReservationLines(lines []ReservationLine, reservations []*InventoryReservation) []ReservationLine {
+	covered := make(map[string]int)
+	for _, reservation := range reservations {
+		if reservation.Status == ReservationStatusActive || reservation.Status == ReservationStatusConverted {
+			covered[reservation.ProductID] += reservation.Quantity
+		}
+	}
+
+	var uncovered []ReservationLine
+	for _, line := range MergeReservationLines(lines) {
+		remaining := line.Quantity - covered[line.ProductID]
+		if remaining > 0 {
+			line.Quantity = remaining
+			uncovered = append(uncovered, line)
+		}
+	}
+	return uncovered
+}
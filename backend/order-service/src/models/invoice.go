@@ -0,0 +1,30 @@
+package models
+
+// DefaultInvoiceColor is the accent color of invoices whose tenant picked none
+const DefaultInvoiceColor = "#1F2937"
+
+// InvoiceBranding is the tenant branding printed on PDF invoices and receipts, managed in the tenant service
+type InvoiceBranding struct {
+	BusinessName     string
+	DisplayName      string // Printed instead of the business name when set
+	LogoURL          string
+	LogoStorageKey   string // Uploaded logo in object storage; used instead of LogoURL when set
+	PrimaryColor     string // #RRGGBB
+	Address          string
+	Phone            string
+	Email            string
+	TaxID            string // NPWP
+	InvoiceFooter    string
+	AttachInvoicePDF bool // Attach the PDF to order invoice emails
+	ReceiptHeader    string
+	ReceiptFooter    string
+	SocialLinks      map[string]string // Profile URL by platform
+}
+
+// Name is the merchant name printed on invoices
+func (b *InvoiceBranding) Name() string {
+	if b.DisplayName != "" {
+		return b.DisplayName
+	}
+	return b.BusinessName
+}
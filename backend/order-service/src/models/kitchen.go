@@ -0,0 +1,169 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// PrepStatus is a kitchen preparation status of an order item
+type PrepStatus string
+
+const (
+	PrepStatusQueued     PrepStatus = "queued"
+	PrepStatusPreparing  PrepStatus = "preparing"
+	PrepStatusReady      PrepStatus = "ready"
+	PrepStatusOutOfStock PrepStatus = "out_of_stock" // Cannot be made; the rest of the order is handed out without it
+)
+
+// Kitchen display errors
+var (
+	ErrInvalidPrepStatus     = errors.New("status must be queued, preparing, ready or out_of_stock")
+	ErrKitchenOrderNotActive = errors.New("order is not on the kitchen screen")
+	ErrKitchenOrderNotBumped = errors.New("only bumped, paid orders can be recalled")
+)
+
+// IsValid reports whether s is a known preparation status
+func (s PrepStatus) IsValid() bool {
+	switch s {
+	case PrepStatusQueued, PrepStatusPreparing, PrepStatusReady, PrepStatusOutOfStock:
+		return true
+	}
+	return false
+}
+
+// KitchenItem is an order item as shown on the kitchen screen
+type KitchenItem struct {
+	ID            string     `json:"id"`
+	ProductID     string     `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	Quantity      int        `json:"quantity"`
+	PrepStatus    PrepStatus `json:"prep_status"`
+	PrepStartedAt *time.Time `json:"prep_started_at,omitempty"`
+	PrepReadyAt   *time.Time `json:"prep_ready_at,omitempty"`
+}
+
+// KitchenOrder is a paid order as shown on the kitchen screen
+// Customer details are left out; the kitchen works from the reference and table number.
+type KitchenOrder struct {
+	ID             string        `json:"id"`
+	OrderReference string        `json:"order_reference"`
+	OrderType      OrderType     `json:"order_type"`
+	DeliveryType   DeliveryType  `json:"delivery_type"`
+	TableNumber    *string       `json:"table_number,omitempty"`
+	Notes          *string       `json:"notes,omitempty"`
+	ScheduledFor   *time.Time    `json:"scheduled_for,omitempty"`
+	PaidAt         time.Time     `json:"paid_at"`
+	DueAt          time.Time     `json:"due_at"`
+	IsLate         bool          `json:"is_late"`
+	PrepStatus     PrepStatus    `json:"prep_status"` // Rolled up from the items
+	PartiallyReady bool          `json:"partially_ready"`
+	BumpedAt       *time.Time    `json:"bumped_at,omitempty"`
+	Items          []KitchenItem `json:"items"`
+}
+
+// FulfillmentSummary is the item-level progress of an order
+// Out-of-stock items are left out of the roll-up: an order whose other items are all ready
+// is ready, and one with some items ready is partially ready and can be handed out in part.
+type FulfillmentSummary struct {
+	Status          PrepStatus `json:"status"`
+	PartiallyReady  bool       `json:"partially_ready"`
+	ItemsTotal      int        `json:"items_total"`
+	ItemsReady      int        `json:"items_ready"`
+	ItemsOutOfStock int        `json:"items_out_of_stock"`
+}
+
+// SummarizeFulfillment rolls item preparation statuses up into an order's progress
+// The order is queued until any item is started and out of stock only when every item is.
+func SummarizeFulfillment(statuses []PrepStatus) FulfillmentSummary {
+	summary := FulfillmentSummary{ItemsTotal: len(statuses)}
+	started := 0
+	for _, status := range statuses {
+		switch status {
+		case PrepStatusReady:
+			summary.ItemsReady++
+			started++
+		case PrepStatusPreparing:
+			started++
+		case PrepStatusOutOfStock:
+			summary.ItemsOutOfStock++
+		}
+	}
+
+	fulfillable := summary.ItemsTotal - summary.ItemsOutOfStock
+	switch {
+	case summary.ItemsTotal > 0 && fulfillable == 0:
+		summary.Status = PrepStatusOutOfStock
+	case fulfillable > 0 && summary.ItemsReady == fulfillable:
+		summary.Status = PrepStatusReady
+	case started > 0:
+		summary.Status = PrepStatusPreparing
+	default:
+		summary.Status = PrepStatusQueued
+	}
+	summary.PartiallyReady = summary.ItemsReady > 0 && summary.ItemsReady < fulfillable
+	return summary
+}
+
+// RollUpPrepStatus derives the order's status from its items; see SummarizeFulfillment
+func (o *KitchenOrder) RollUpPrepStatus() PrepStatus {
+	return o.Fulfillment().Status
+}
+
+// Fulfillment summarizes the order's item-level progress
+func (o *KitchenOrder) Fulfillment() FulfillmentSummary {
+	statuses := make([]PrepStatus, len(o.Items))
+	for i, item := range o.Items {
+		statuses[i] = item.PrepStatus
+	}
+	return SummarizeFulfillment(statuses)
+}
+
+// SetTiming fills in the due time, lateness and rolled-up status at the given time
+// Due times follow GuestOrder.DueAt: the scheduled slot, or prepMinutes after payment.
+func (o *KitchenOrder) SetTiming(now time.Time, prepMinutes int) {
+	paidAt := o.PaidAt
+	order := &GuestOrder{Status: OrderStatusPaid, PaidAt: &paidAt, ScheduledFor: o.ScheduledFor}
+	o.DueAt = order.DueAt(prepMinutes)
+	fulfillment := o.Fulfillment()
+	o.PrepStatus = fulfillment.Status
+	o.PartiallyReady = fulfillment.PartiallyReady
+	o.IsLate = o.BumpedAt == nil && order.IsLate(now, prepMinutes)
+}
+
+// Timing returns when the order reached each kitchen stage; see OrderItemsTiming
+func (o *KitchenOrder) Timing() OrderTiming {
+	items := make([]OrderItem, len(o.Items))
+	for i, item := range o.Items {
+		items[i] = OrderItem{PrepStatus: item.PrepStatus, PrepStartedAt: item.PrepStartedAt, PrepReadyAt: item.PrepReadyAt}
+	}
+	return OrderItemsTiming(o.PaidAt, items)
+}
+
+// KitchenFeed is a page of the kitchen order stream
+// Without a cursor it holds every active order. With one it holds the active orders changed
+// since then, and Removed lists orders that left the screen (bumped, completed or cancelled).
+type KitchenFeed struct {
+	Orders  []KitchenOrder `json:"orders"`
+	Removed []string       `json:"removed"`
+	Cursor  time.Time      `json:"cursor"` // Pass back as since to get the next changes
+}
+
+// ProductPrepMetrics summarizes how long one product took to prepare
+type ProductPrepMetrics struct {
+	ProductID      string  `json:"product_id"`
+	ProductName    string  `json:"product_name"`
+	ItemsReady     int     `json:"items_ready"`
+	AvgWaitSeconds float64 `json:"avg_wait_seconds"` // Payment to preparation start
+	AvgPrepSeconds float64 `json:"avg_prep_seconds"` // Preparation start to ready
+}
+
+// KitchenMetrics summarizes kitchen performance over a period
+// Ticket time runs from payment until the order is bumped.
+type KitchenMetrics struct {
+	From             time.Time            `json:"from"`
+	To               time.Time            `json:"to"`
+	OrdersBumped     int                  `json:"orders_bumped"`
+	AvgTicketSeconds float64              `json:"avg_ticket_seconds"`
+	P90TicketSeconds float64              `json:"p90_ticket_seconds"`
+	Products         []ProductPrepMetrics `json:"products"`
+}
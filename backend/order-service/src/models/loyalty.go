@@ -0,0 +1,145 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LoyaltyTransactionType is the kind of points ledger entry
+type LoyaltyTransactionType string
+
+const (
+	LoyaltyEarn         LoyaltyTransactionType = "earn"
+	LoyaltyRedeem       LoyaltyTransactionType = "redeem"
+	LoyaltyEarnReversal LoyaltyTransactionType = "earn_reversal" // Points earned by an order that was cancelled
+	LoyaltyRedeemRefund LoyaltyTransactionType = "redeem_refund" // Points given back when an order that used them was cancelled
+)
+
+var (
+	ErrInvalidLoyaltyProgram     = errors.New("invalid loyalty program")
+	ErrLoyaltyDisabled           = errors.New("loyalty program is not enabled")
+	ErrLoyaltyBelowMinimum       = errors.New("points to redeem are below the program minimum")
+	ErrLoyaltyInsufficientPoints = errors.New("not enough loyalty points")
+	ErrLoyaltyOrderTooSmall      = errors.New("order amount is too small to redeem points")
+	ErrLoyaltyPhoneRequired      = errors.New("customer phone is required")
+	// Points belong to whoever verified the phone number by OTP, not to whoever types it in
+	ErrLoyaltyVerificationRequired = errors.New("verify your phone number to use loyalty points")
+)
+
+// LoyaltyProgram is a tenant's loyalty settings
+type LoyaltyProgram struct {
+	TenantID         string    `json:"tenant_id"`
+	IsEnabled        bool      `json:"is_enabled"`
+	SpendPerPoint    int       `json:"spend_per_point"`    // IDR spent to earn one point
+	PointValue       int       `json:"point_value"`        // IDR discount per redeemed point
+	MinRedeemPoints  int       `json:"min_redeem_points"`  // Smallest redemption
+	MaxRedeemPercent int       `json:"max_redeem_percent"` // Largest share of the amount due payable with points
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// DefaultLoyaltyProgram returns the settings of a tenant that never configured loyalty
+func DefaultLoyaltyProgram(tenantID string) *LoyaltyProgram {
+	return &LoyaltyProgram{
+		TenantID:         tenantID,
+		IsEnabled:        false,
+		SpendPerPoint:    10000,
+		PointValue:       100,
+		MinRedeemPoints:  10,
+		MaxRedeemPercent: 50,
+	}
+}
+
+// UpdateLoyaltyProgramRequest changes a tenant's loyalty settings; nil fields are left as they are
+type UpdateLoyaltyProgramRequest struct {
+	IsEnabled        *bool `json:"is_enabled"`
+	SpendPerPoint    *int  `json:"spend_per_point"`
+	PointValue       *int  `json:"point_value"`
+	MinRedeemPoints  *int  `json:"min_redeem_points"`
+	MaxRedeemPercent *int  `json:"max_redeem_percent"`
+}
+
+// Validate checks the loyalty settings that are being changed
+func (r *UpdateLoyaltyProgramRequest) Validate() error {
+	if r.SpendPerPoint != nil && *r.SpendPerPoint < 1 {
+		return fmt.Errorf("%w: spend_per_point must be greater than 0", ErrInvalidLoyaltyProgram)
+	}
+	if r.PointValue != nil && *r.PointValue < 1 {
+		return fmt.Errorf("%w: point_value must be greater than 0", ErrInvalidLoyaltyProgram)
+	}
+	if r.MinRedeemPoints != nil && *r.MinRedeemPoints < 1 {
+		return fmt.Errorf("%w: min_redeem_points must be greater than 0", ErrInvalidLoyaltyProgram)
+	}
+	if r.MaxRedeemPercent != nil && (*r.MaxRedeemPercent < 1 || *r.MaxRedeemPercent > 100) {
+		return fmt.Errorf("%w: max_redeem_percent must be between 1 and 100", ErrInvalidLoyaltyProgram)
+	}
+	return nil
+}
+
+// PointsEarned returns the points earned by spending amount on goods
+func (p *LoyaltyProgram) PointsEarned(amount int) int {
+	if !p.IsEnabled || p.SpendPerPoint <= 0 || amount <= 0 {
+		return 0
+	}
+	return amount / p.SpendPerPoint
+}
+
+// Redemption works out how many of the requested points can be used on an order
+// amountDue is the order amount after promotions and vouchers, before delivery.
+// Requests above max_redeem_percent are reduced to the largest allowed redemption.
+func (p *LoyaltyProgram) Redemption(requested, balance, amountDue int) (points int, discount int, err error) {
+	if !p.IsEnabled {
+		return 0, 0, ErrLoyaltyDisabled
+	}
+	if requested < p.MinRedeemPoints {
+		return 0, 0, ErrLoyaltyBelowMinimum
+	}
+	if requested > balance {
+		return 0, 0, ErrLoyaltyInsufficientPoints
+	}
+
+	points = requested
+	if maxPoints := amountDue * p.MaxRedeemPercent / 100 / p.PointValue; points > maxPoints {
+		points = maxPoints
+	}
+	if points < p.MinRedeemPoints {
+		return 0, 0, ErrLoyaltyOrderTooSmall
+	}
+	return points, points * p.PointValue, nil
+}
+
+// LoyaltyAccount is a returning guest's points balance
+type LoyaltyAccount struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	PhoneHash     string    `json:"-"`
+	PointsBalance int       `json:"points_balance"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// LoyaltyBalanceRequest looks up a guest's balance by phone
+type LoyaltyBalanceRequest struct {
+	Phone string `json:"phone"`
+}
+
+// LoyaltyBalance is what the public menu shows a guest about their points
+// The balance fields stay zero unless Verified: the guest's customer session verified the phone.
+type LoyaltyBalance struct {
+	Enabled          bool `json:"enabled"`
+	Verified         bool `json:"verified"`
+	PointsBalance    int  `json:"points_balance"`
+	BalanceValue     int  `json:"balance_value"` // points_balance * point_value
+	PointValue       int  `json:"point_value"`
+	SpendPerPoint    int  `json:"spend_per_point"`
+	MinRedeemPoints  int  `json:"min_redeem_points"`
+	MaxRedeemPercent int  `json:"max_redeem_percent"`
+}
+
+// CustomerPhoneHash identifies a returning customer of a tenant without storing the phone number
+func CustomerPhoneHash(tenantID, phone string) string {
+	sum := sha256.Sum256([]byte(tenantID + ":" + NormalizeCustomerPhone(phone)))
+	return hex.EncodeToString(sum[:])
+}
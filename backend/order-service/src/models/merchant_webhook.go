@@ -0,0 +1,282 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MerchantWebhookEvent names an order lifecycle event a tenant can subscribe to
+type MerchantWebhookEvent string
+
+const (
+	WebhookEventOrderCreated   MerchantWebhookEvent = "order.created"
+	WebhookEventOrderPaid      MerchantWebhookEvent = "order.paid"
+	WebhookEventOrderCancelled MerchantWebhookEvent = "order.cancelled"
+	WebhookEventOrderRefunded  MerchantWebhookEvent = "order.refunded"
+)
+
+// MerchantWebhookEvents lists every event a webhook can subscribe to
+var MerchantWebhookEvents = []MerchantWebhookEvent{
+	WebhookEventOrderCreated,
+	WebhookEventOrderPaid,
+	WebhookEventOrderCancelled,
+	WebhookEventOrderRefunded,
+}
+
+// WebhookDeliveryStatus is where a webhook delivery stands
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for its first or next attempt
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // The endpoint answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Gave up after MaxWebhookAttempts
+)
+
+// Webhook delivery retry policy: the delay doubles from the base up to the cap
+const (
+	MaxWebhookAttempts   = 8
+	WebhookRetryBase     = 30 * time.Second
+	WebhookRetryMaxDelay = time.Hour
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+var (
+	ErrInvalidMerchantWebhook   = errors.New("invalid webhook")
+	ErrMerchantWebhookNotFound  = errors.New("webhook not found")
+	ErrWebhookAddressNotAllowed = errors.New("webhook address is not public")
+)
+
+// nonPublicWebhookNetworks are ranges webhooks are never delivered to, besides the loopback,
+// private, link-local, multicast and unspecified addresses net.IP recognizes itself
+var nonPublicWebhookNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "This" network
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // Benchmarking
+	mustParseCIDR("240.0.0.0/4"),   // Reserved
+	mustParseCIDR("64:ff9b::/96"),  // NAT64, which reaches IPv4 addresses
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// MerchantWebhook is a tenant's endpoint that receives signed order events
+// Secret is only filled in when it was just generated; it is stored encrypted.
+type MerchantWebhook struct {
+	ID        string                 `json:"id"`
+	TenantID  string                 `json:"tenant_id"`
+	URL       string                 `json:"url"`
+	Events    []MerchantWebhookEvent `json:"events"`
+	IsActive  bool                   `json:"is_active"`
+	Secret    string                 `json:"secret,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// MerchantWebhookRequest creates a webhook or replaces its settings
+type MerchantWebhookRequest struct {
+	URL      string                 `json:"url"`
+	Events   []MerchantWebhookEvent `json:"events"`
+	IsActive *bool                  `json:"is_active,omitempty"` // Defaults to true
+}
+
+// Validate trims the URL, removes duplicate events and checks both
+func (r *MerchantWebhookRequest) Validate() error {
+	r.URL = strings.TrimSpace(r.URL)
+	if err := ValidateWebhookURL(r.URL); err != nil {
+		return err
+	}
+
+	if len(r.Events) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event", ErrInvalidMerchantWebhook)
+	}
+	seen := make(map[MerchantWebhookEvent]bool, len(r.Events))
+	events := make([]MerchantWebhookEvent, 0, len(r.Events))
+	for _, event := range r.Events {
+		if !event.Valid() {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidMerchantWebhook, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	r.Events = events
+	return nil
+}
+
+// ValidateWebhookURL checks that a webhook endpoint is an https URL on a public host
+// Host names are only checked for obviously local ones here; the addresses they resolve to are
+// checked by CheckWebhookAddress each time a delivery connects.
+func ValidateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute https URL", ErrInvalidMerchantWebhook)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicWebhookIP(ip) {
+			return fmt.Errorf("%w: url must not point to a private or local address", ErrInvalidMerchantWebhook)
+		}
+		return nil
+	}
+	// Single-label names are internal service names, never a merchant's endpoint
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || !strings.Contains(host, ".") {
+		return fmt.Errorf("%w: url must use a public host name", ErrInvalidMerchantWebhook)
+	}
+	return nil
+}
+
+// IsPublicWebhookIP reports whether webhooks may be delivered to ip
+func IsPublicWebhookIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicWebhookNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckWebhookAddress refuses a resolved "ip:port" address that is not public
+// Deliveries check the address they are about to connect to, after DNS resolution, so a host
+// name later pointed at an internal address is refused as well.
+func CheckWebhookAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressNotAllowed, address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicWebhookIP(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressNotAllowed, host)
+	}
+	return nil
+}
+
+// Valid reports whether the event is one webhooks can subscribe to
+func (e MerchantWebhookEvent) Valid() bool {
+	for _, event := range MerchantWebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// MerchantWebhookDelivery is one event sent, or to be sent, to one webhook
+type MerchantWebhookDelivery struct {
+	ID             string                `json:"id"`
+	WebhookID      string                `json:"webhook_id"`
+	TenantID       string                `json:"tenant_id"`
+	EventID        string                `json:"event_id"`
+	EventType      MerchantWebhookEvent  `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty"`
+	LastError      *string               `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+
+	// Endpoint of the webhook, loaded when a delivery is claimed for sending
+	URL             string `json:"-"`
+	EncryptedSecret string `json:"-"`
+}
+
+// MerchantWebhookPayload is the JSON body POSTed to a webhook
+// The event ID is the same for every webhook receiving the event, so receivers can deduplicate.
+type MerchantWebhookPayload struct {
+	ID         string               `json:"id"`
+	Type       MerchantWebhookEvent `json:"type"`
+	TenantID   string               `json:"tenant_id"`
+	OccurredAt time.Time            `json:"occurred_at"`
+	Data       WebhookOrderData     `json:"data"`
+}
+
+// WebhookOrderData is the order an event is about; customer contact details are left out
+type WebhookOrderData struct {
+	OrderID        string         `json:"order_id"`
+	OrderReference string         `json:"order_reference"`
+	Status         OrderStatus    `json:"status"`
+	OrderType      OrderType      `json:"order_type"`
+	DeliveryType   DeliveryType   `json:"delivery_type"`
+	TableNumber    *string        `json:"table_number,omitempty"`
+	SubtotalAmount int            `json:"subtotal_amount"`
+	TotalAmount    int            `json:"total_amount"`
+	CreatedAt      time.Time      `json:"created_at"`
+	PaidAt         *time.Time     `json:"paid_at,omitempty"`
+	CancelledAt    *time.Time     `json:"cancelled_at,omitempty"`
+	Refund         *WebhookRefund `json:"refund,omitempty"`
+}
+
+// WebhookRefund describes the refund of an order.refunded event
+type WebhookRefund struct {
+	RefundID string `json:"refund_id"`
+	Amount   int    `json:"amount"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// NewWebhookOrderData builds the webhook view of an order's current state
+func NewWebhookOrderData(order *GuestOrder) WebhookOrderData {
+	return WebhookOrderData{
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Status:         order.Status,
+		OrderType:      order.OrderType,
+		DeliveryType:   order.DeliveryType,
+		TableNumber:    order.TableNumber,
+		SubtotalAmount: order.SubtotalAmount,
+		TotalAmount:    order.TotalAmount,
+		CreatedAt:      order.CreatedAt,
+		PaidAt:         order.PaidAt,
+		CancelledAt:    order.CancelledAt,
+	}
+}
+
+// WebhookRetryDelay returns how long to wait before the next attempt after attempts failures
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := WebhookRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= WebhookRetryMaxDelay {
+			return WebhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature of a delivery
+// The HMAC-SHA256 covers "{timestamp}.{body}" so a captured request cannot be replayed later
+// with a fresh timestamp.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
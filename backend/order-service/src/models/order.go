@@ -1,10 +1,14 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ErrClientOrderIDExists is returned when an order synced from an offline terminal was already synced
+var ErrClientOrderIDExists = errors.New("offline order was already synced")
+
 // OrderStatus represents the lifecycle of an order
 type OrderStatus string
 
@@ -43,37 +47,61 @@ const (
 
 // GuestOrder represents an order placed by an unauthenticated guest
 type GuestOrder struct {
-	ID             string       `json:"id"`
-	OrderReference string       `json:"order_reference"`
-	TenantID       string       `json:"tenant_id"`
-	Status         OrderStatus  `json:"status"`
-	SubtotalAmount int          `json:"subtotal_amount"` // In smallest currency unit (IDR cents)
-	DeliveryFee    int          `json:"delivery_fee"`
-	TotalAmount    int          `json:"total_amount"`
-	CustomerName   string       `json:"customer_name"`
-	CustomerPhone  string       `json:"customer_phone"`
-	CustomerEmail  *string      `json:"customer_email,omitempty"`
-	DeliveryType   DeliveryType `json:"delivery_type"`
-	TableNumber    *string      `json:"table_number,omitempty"`
-	Notes          *string      `json:"notes,omitempty"`
-	CreatedAt      time.Time    `json:"created_at"`
-	PaidAt         *time.Time   `json:"paid_at,omitempty"`
-	CompletedAt    *time.Time   `json:"completed_at,omitempty"`
-	CancelledAt    *time.Time   `json:"cancelled_at,omitempty"`
-	SessionID      string       `json:"session_id,omitempty"`
-	IPAddress      *string      `json:"ip_address,omitempty"`
-	UserAgent      *string      `json:"user_agent,omitempty"`
-	IsAnonymized   bool         `json:"is_anonymized"`
-	AnonymizedAt   *time.Time   `json:"anonymized_at,omitempty"`
-	TenantSlug     string       `json:"tenant_slug"`
+	ID                      string       `json:"id"`
+	OrderReference          string       `json:"order_reference"`
+	TenantID                string       `json:"tenant_id"`
+	Status                  OrderStatus  `json:"status"`
+	SubtotalAmount          int          `json:"subtotal_amount"` // In smallest currency unit (IDR cents)
+	DeliveryFee             int          `json:"delivery_fee"`
+	DiscountAmount          int          `json:"discount_amount"` // Voucher discount, subtracted from the subtotal
+	VoucherCode             *string      `json:"voucher_code,omitempty"`
+	PromotionDiscountAmount int          `json:"promotion_discount_amount"` // Automatic promotions, subtracted before the voucher
+	LoyaltyPointsRedeemed   int          `json:"loyalty_points_redeemed"`
+	LoyaltyDiscountAmount   int          `json:"loyalty_discount_amount"` // Paid with loyalty points, subtracted after the voucher
+	ServiceChargeRate       float64      `json:"service_charge_rate"`     // Percentage in effect when the order was placed
+	ServiceChargeAmount     int          `json:"service_charge_amount"`
+	TaxRate                 float64      `json:"tax_rate"` // Tax (PPN) percentage in effect when the order was placed
+	TaxAmount               int          `json:"tax_amount"`
+	TotalAmount             int          `json:"total_amount"`
+	CustomerName            string       `json:"customer_name"`
+	CustomerPhone           string       `json:"customer_phone"`
+	CustomerEmail           *string      `json:"customer_email,omitempty"`
+	DeliveryType            DeliveryType `json:"delivery_type"`
+	TableNumber             *string      `json:"table_number,omitempty"`
+	Notes                   *string      `json:"notes,omitempty"`
+	ScheduledFor            *time.Time   `json:"scheduled_for,omitempty"` // Chosen pickup/delivery slot; nil means as soon as possible
+	CreatedAt               time.Time    `json:"created_at"`
+	PaidAt                  *time.Time   `json:"paid_at,omitempty"`
+	CompletedAt             *time.Time   `json:"completed_at,omitempty"`
+	CancelledAt             *time.Time   `json:"cancelled_at,omitempty"`
+	SessionID               string       `json:"session_id,omitempty"`
+	IPAddress               *string      `json:"ip_address,omitempty"`
+	UserAgent               *string      `json:"user_agent,omitempty"`
+	IsAnonymized            bool         `json:"is_anonymized"`
+	AnonymizedAt            *time.Time   `json:"anonymized_at,omitempty"`
+	TenantSlug              string       `json:"tenant_slug"`
 
 	// Offline order fields (Phase: 008-offline-orders)
-	OrderType              OrderType      `json:"order_type"`
-	DataConsentGiven       bool           `json:"data_consent_given"`
-	ConsentMethod          *ConsentMethod `json:"consent_method,omitempty"`
-	RecordedByUserID       *string        `json:"recorded_by_user_id,omitempty"`
-	LastModifiedByUserID   *string        `json:"last_modified_by_user_id,omitempty"`
-	LastModifiedAt         *time.Time     `json:"last_modified_at,omitempty"`
+	OrderType            OrderType      `json:"order_type"`
+	DataConsentGiven     bool           `json:"data_consent_given"`
+	ConsentMethod        *ConsentMethod `json:"consent_method,omitempty"`
+	RecordedByUserID     *string        `json:"recorded_by_user_id,omitempty"`
+	LastModifiedByUserID *string        `json:"last_modified_by_user_id,omitempty"`
+	LastModifiedAt       *time.Time     `json:"last_modified_at,omitempty"`
+
+	// Offline batch ingestion fields
+	ClientOrderID *string    `json:"client_order_id,omitempty"`
+	SyncedAt      *time.Time `json:"synced_at,omitempty"`
+	StockOversold bool       `json:"stock_oversold,omitempty"`
+
+	// Payment gateway outage fallback
+	PaymentFallback *string `json:"payment_fallback,omitempty"` // UNPAID_OFFLINE: payment is collected by staff
+
+	// Daily queue number, assigned on payment
+	QueueNumber *int `json:"queue_number,omitempty"`
+
+	// Courier delivery progress, mirrored from the latest courier booking
+	DeliveryStatus *DeliveryStatus `json:"delivery_status,omitempty"`
 }
 
 // CreateOrderRequest represents the request to create a new order
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrOrderNotDisputable = errors.New("only PAID orders can be marked as disputed")
+	ErrDisputeReasonEmpty = errors.New("dispute reason is required")
+)
+
+// AutoCompleteCandidate is a PAID order old enough to be auto-completed
+type AutoCompleteCandidate struct {
+	OrderID        string    `json:"order_id"`
+	OrderReference string    `json:"order_reference"`
+	PaidAt         time.Time `json:"paid_at"`
+	Disputed       bool      `json:"disputed"`
+}
+
+// AutoCompleteReport summarizes one auto-complete sweep for a tenant
+// In a dry run Completed lists the orders that would have been completed.
+type AutoCompleteReport struct {
+	TenantID  string                   `json:"tenant_id"`
+	Mode      AutoCompleteMode         `json:"mode"`
+	Cutoff    time.Time                `json:"cutoff"`
+	DryRun    bool                     `json:"dry_run"`
+	Completed []*AutoCompleteCandidate `json:"completed"`
+	Excluded  []*AutoCompleteCandidate `json:"excluded"` // Disputed orders left open
+	Failed    int                      `json:"failed"`
+}
+
+// SetOrderDisputeRequest flags an order as disputed so it is never auto-completed
+type SetOrderDisputeRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
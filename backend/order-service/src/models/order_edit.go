@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Order edit errors
+var (
+	ErrOrderNotEditable              = errors.New("only PENDING online orders awaiting payment can be edited")
+	ErrInvalidOrderEdit              = errors.New("invalid order edit")
+	ErrOrderEditBelowLoyaltyDiscount = errors.New("edited order would cost less than the loyalty points already redeemed on it")
+)
+
+// EditOrderItemsRequest replaces the items of a PENDING online order before it is paid
+type EditOrderItemsRequest struct {
+	Items  []OrderItemInput `json:"items"`
+	Reason string           `json:"reason,omitempty"` // Appended to the audit note
+}
+
+// Validate checks the replacement items
+func (r *EditOrderItemsRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidOrderEdit)
+	}
+	for _, item := range r.Items {
+		if item.ProductID == "" || item.ProductName == "" {
+			return fmt.Errorf("%w: product_id and product_name are required", ErrInvalidOrderEdit)
+		}
+		if item.Quantity < 1 {
+			return fmt.Errorf("%w: quantity must be at least 1", ErrInvalidOrderEdit)
+		}
+		if item.UnitPrice < 0 {
+			return fmt.Errorf("%w: unit_price cannot be negative", ErrInvalidOrderEdit)
+		}
+	}
+	if len(r.Reason) > 500 {
+		return fmt.Errorf("%w: reason must be at most 500 characters", ErrInvalidOrderEdit)
+	}
+	return nil
+}
+
+// Reprice recalculates the order amounts after its items were edited
+// The service charge and tax use the rates recorded when the order was placed and the
+// delivery fee is kept. Loyalty points are already spent, so an edit that would leave
+// less to pay than the points cover is rejected rather than silently forfeiting them.
+func (o *GuestOrder) Reprice(subtotal, promotionDiscount, voucherDiscount int) error {
+	discounted := subtotal - promotionDiscount - voucherDiscount
+	if discounted < o.LoyaltyDiscountAmount {
+		return ErrOrderEditBelowLoyaltyDiscount
+	}
+
+	rates := OrderSettings{ServiceChargePercent: o.ServiceChargeRate, TaxPercent: o.TaxRate}
+	serviceCharge, tax := rates.ChargeLines(discounted)
+
+	o.SubtotalAmount = subtotal
+	o.PromotionDiscountAmount = promotionDiscount
+	o.DiscountAmount = voucherDiscount
+	o.ServiceChargeAmount = serviceCharge
+	o.TaxAmount = tax
+	o.TotalAmount = discounted + serviceCharge + tax - o.LoyaltyDiscountAmount + o.DeliveryFee
+	return nil
+}
+
+// revisedChargeSeparator joins the order reference and revision in gateway order IDs.
+// Order references never contain an underscore, so the suffix cannot be confused with one.
+const revisedChargeSeparator = "_R"
+
+// RevisedChargeOrderID builds the gateway order ID for the charge created after an order edit.
+// Gateways refuse to reuse an order ID, so each replacement charge is numbered after the reference.
+func RevisedChargeOrderID(orderReference string, revision int) string {
+	return fmt.Sprintf("%s%s%d", orderReference, revisedChargeSeparator, revision)
+}
+
+// ParseRevisedChargeOrderID extracts the order reference from a revised charge's gateway order ID.
+// Returns false when the ID belongs to the order's original charge.
+func ParseRevisedChargeOrderID(gatewayOrderID string) (string, bool) {
+	idx := strings.LastIndex(gatewayOrderID, revisedChargeSeparator)
+	if idx <= 0 {
+		return "", false
+	}
+	revision, err := strconv.Atoi(gatewayOrderID[idx+len(revisedChargeSeparator):])
+	if err != nil || revision < 1 {
+		return "", false
+	}
+	return gatewayOrderID[:idx], true
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// OTPChannel is how a guest receives their order history verification code
+type OTPChannel string
+
+const (
+	OTPChannelSMS      OTPChannel = "sms"
+	OTPChannelWhatsApp OTPChannel = "whatsapp"
+)
+
+// Order history verification limits
+const (
+	OrderHistoryOTPLength     = 6
+	OrderHistoryOTPTTL        = 5 * time.Minute
+	OrderHistoryOTPCooldown   = time.Minute // Between codes sent to the same number
+	OrderHistoryOTPMaxAttempt = 5           // Wrong codes before the code is discarded
+)
+
+// Order history errors
+var (
+	ErrInvalidOTPChannel        = errors.New("channel must be sms or whatsapp")
+	ErrInvalidOrderHistoryPhone = errors.New("phone must be a full phone number")
+	ErrOrderHistoryOTPCooldown  = errors.New("a code was sent recently; please wait before requesting another")
+	ErrOrderHistoryOTPInvalid   = errors.New("verification code is invalid or has expired")
+)
+
+// OrderHistoryOTPRequest asks for a verification code to see past orders
+type OrderHistoryOTPRequest struct {
+	Phone   string     `json:"phone"`
+	Channel OTPChannel `json:"channel"` // Defaults to sms
+}
+
+// Validate normalizes the request, returning the normalized phone number
+func (r *OrderHistoryOTPRequest) Validate() (string, error) {
+	if r.Channel == "" {
+		r.Channel = OTPChannelSMS
+	}
+	if r.Channel != OTPChannelSMS && r.Channel != OTPChannelWhatsApp {
+		return "", ErrInvalidOTPChannel
+	}
+	return normalizeOrderHistoryPhone(r.Phone)
+}
+
+// OrderHistoryVerifyRequest exchanges a verification code for a customer session
+type OrderHistoryVerifyRequest struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
+
+// Validate normalizes the request, returning the normalized phone number
+func (r *OrderHistoryVerifyRequest) Validate() (string, error) {
+	r.Code = strings.TrimSpace(r.Code)
+	if len(r.Code) != OrderHistoryOTPLength {
+		return "", ErrOrderHistoryOTPInvalid
+	}
+	return normalizeOrderHistoryPhone(r.Phone)
+}
+
+// normalizeOrderHistoryPhone applies the contact search rules to a guest's phone number
+func normalizeOrderHistoryPhone(phone string) (string, error) {
+	search, err := ParseContactSearch(phone)
+	if err != nil || search.Email {
+		return "", ErrInvalidOrderHistoryPhone
+	}
+	return search.Value, nil
+}
+
+// OrderHistoryEntry is one past order as shown to the guest who placed it
+// Customer details are left out; the guest opens the order by reference for more.
+type OrderHistoryEntry struct {
+	OrderReference string       `json:"order_reference"`
+	Status         OrderStatus  `json:"status"`
+	DeliveryType   DeliveryType `json:"delivery_type"`
+	TotalAmount    int          `json:"total_amount"`
+	ItemCount      int          `json:"item_count"`
+	CreatedAt      time.Time    `json:"created_at"`
+	PaidAt         *time.Time   `json:"paid_at,omitempty"`
+	CompletedAt    *time.Time   `json:"completed_at,omitempty"`
+	CancelledAt    *time.Time   `json:"cancelled_at,omitempty"`
+}
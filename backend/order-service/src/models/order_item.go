@@ -7,15 +7,27 @@ import (
 
 // OrderItem represents a line item in a guest order
 type OrderItem struct {
-	ID          string    `json:"id"`
-	OrderID     string    `json:"order_id"`
-	ProductID   string    `json:"product_id"`
-	ProductName string    `json:"product_name"`
-	ProductSKU  *string   `json:"product_sku,omitempty"`
-	Quantity    int       `json:"quantity"`
-	UnitPrice   int       `json:"unit_price"`  // Price at time of order (IDR cents)
-	TotalPrice  int       `json:"total_price"` // quantity * unit_price
-	CreatedAt   time.Time `json:"created_at"`
+	ID            string     `json:"id"`
+	OrderID       string     `json:"order_id"`
+	ProductID     string     `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	ProductSKU    *string    `json:"product_sku,omitempty"`
+	Quantity      int        `json:"quantity"`
+	UnitPrice     int        `json:"unit_price"`            // Price at time of order (IDR cents)
+	TotalPrice    int        `json:"total_price"`           // quantity * unit_price
+	PrepStatus    PrepStatus `json:"prep_status,omitempty"` // Kitchen status; empty where not loaded
+	PrepStartedAt *time.Time `json:"prep_started_at,omitempty"`
+	PrepReadyAt   *time.Time `json:"prep_ready_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// SummarizeOrderItems rolls the kitchen status of an order's items up; see SummarizeFulfillment
+func SummarizeOrderItems(items []OrderItem) FulfillmentSummary {
+	statuses := make([]PrepStatus, len(items))
+	for i, item := range items {
+		statuses[i] = item.PrepStatus
+	}
+	return SummarizeFulfillment(statuses)
 }
 
 // Validate checks if the order item is valid
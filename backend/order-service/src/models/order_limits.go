@@ -0,0 +1,103 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Order limit errors
+var (
+	ErrBelowMinOrderAmount     = errors.New("order subtotal is below the minimum order amount")
+	ErrMaxItemsExceeded        = errors.New("order has more items than allowed")
+	ErrInvalidMinOrderAmounts  = errors.New("min_order_amount_by_delivery_type must map delivery, pickup or dine_in to a non-negative amount")
+	ErrInvalidMaxItemsPerOrder = errors.New("max_items_per_order cannot be negative")
+)
+
+// MinOrderAmounts overrides min_order_amount for individual delivery types
+type MinOrderAmounts map[DeliveryType]int
+
+// Scan implements sql.Scanner for MinOrderAmounts (JSONB)
+func (m *MinOrderAmounts) Scan(value interface{}) error {
+	*m = MinOrderAmounts{}
+	if value == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into MinOrderAmounts", value)
+	}
+	return json.Unmarshal(data, m)
+}
+
+// Value implements driver.Valuer for MinOrderAmounts (JSONB)
+func (m MinOrderAmounts) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Validate checks delivery types and that amounts are not negative
+func (m MinOrderAmounts) Validate() error {
+	for deliveryType, amount := range m {
+		switch deliveryType {
+		case DeliveryTypeDelivery, DeliveryTypePickup, DeliveryTypeDineIn:
+		default:
+			return ErrInvalidMinOrderAmounts
+		}
+		if amount < 0 {
+			return ErrInvalidMinOrderAmounts
+		}
+	}
+	return nil
+}
+
+// ValidateLimits checks the order limit fields that are being changed
+func (r *UpdateOrderSettingsRequest) ValidateLimits() error {
+	if r.MinOrderAmountByDeliveryType != nil {
+		if err := r.MinOrderAmountByDeliveryType.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.MaxItemsPerOrder != nil && *r.MaxItemsPerOrder < 0 {
+		return ErrInvalidMaxItemsPerOrder
+	}
+	return nil
+}
+
+// MinOrderAmountFor returns the minimum item subtotal for a delivery type
+// A delivery type without an override uses min_order_amount.
+func (s *OrderSettings) MinOrderAmountFor(deliveryType DeliveryType) int {
+	if amount, ok := s.MinOrderAmountByDeliveryType[deliveryType]; ok {
+		return amount
+	}
+	return s.MinOrderAmount
+}
+
+// CheckItemLimit returns ErrMaxItemsExceeded when itemCount units exceed max_items_per_order
+func (s *OrderSettings) CheckItemLimit(itemCount int) error {
+	if s.MaxItemsPerOrder > 0 && itemCount > s.MaxItemsPerOrder {
+		return fmt.Errorf("%w: at most %d items per order (cart has %d)", ErrMaxItemsExceeded, s.MaxItemsPerOrder, itemCount)
+	}
+	return nil
+}
+
+// CheckOrderLimits checks an order's item subtotal (before discounts) and unit count
+func (s *OrderSettings) CheckOrderLimits(deliveryType DeliveryType, subtotal, itemCount int) error {
+	if minAmount := s.MinOrderAmountFor(deliveryType); subtotal < minAmount {
+		return fmt.Errorf("%w: %s orders must be at least %d (subtotal is %d)", ErrBelowMinOrderAmount, deliveryType, minAmount, subtotal)
+	}
+	return s.CheckItemLimit(itemCount)
+}
@@ -1,16 +1,22 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
 
 // OrderNote represents a note/comment added to an order
 // Used for courier tracking, admin comments, status updates, etc.
 type OrderNote struct {
-	ID              string    `json:"id"`
-	OrderID         string    `json:"order_id"`
-	Note            string    `json:"note"`
-	CreatedByUserID *string   `json:"created_by_user_id,omitempty"`
-	CreatedByName   *string   `json:"created_by_name,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              string                `json:"id"`
+	OrderID         string                `json:"order_id"`
+	Note            string                `json:"note"`
+	CreatedByUserID *string               `json:"created_by_user_id,omitempty"`
+	CreatedByName   *string               `json:"created_by_name,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	Attachments     []OrderNoteAttachment `json:"attachments,omitempty"`
 }
 
 // CreateOrderNoteRequest represents the request to create a note
@@ -19,3 +25,68 @@ type CreateOrderNoteRequest struct {
 	CreatedByUserID *string `json:"created_by_user_id,omitempty"`
 	CreatedByName   *string `json:"created_by_name,omitempty"`
 }
+
+// Order note attachment limits
+const (
+	MaxNoteAttachments        = 5
+	MaxNoteAttachmentSizeByte = 10 * 1024 * 1024
+)
+
+// noteAttachmentExtensions maps the accepted image types to the extension they are stored with
+var noteAttachmentExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// Order note attachment errors
+var (
+	ErrTooManyNoteAttachments      = fmt.Errorf("a note can have at most %d attachments", MaxNoteAttachments)
+	ErrNoteAttachmentTooLarge      = fmt.Errorf("attachments must be at most %d MB", MaxNoteAttachmentSizeByte/(1024*1024))
+	ErrNoteAttachmentType          = errors.New("attachments must be JPEG, PNG or WebP images")
+	ErrNoteAttachmentsNotAvailable = errors.New("attachment storage is not configured")
+)
+
+// OrderNoteAttachment is a photo attached to an order note, e.g. of a damaged item
+// URL is presigned when the note is read and is never stored.
+type OrderNoteAttachment struct {
+	ID          string    `json:"id"`
+	NoteID      string    `json:"note_id"`
+	StorageKey  string    `json:"-"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	URL         string    `json:"url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NoteAttachmentUpload is an attachment file received with a new note
+// ContentType is sniffed from the file's content, not taken from the client.
+type NoteAttachmentUpload struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+// ValidateNoteAttachments checks the attachments of a new note against the limits
+func ValidateNoteAttachments(uploads []NoteAttachmentUpload) error {
+	if len(uploads) > MaxNoteAttachments {
+		return ErrTooManyNoteAttachments
+	}
+	for _, upload := range uploads {
+		if _, ok := noteAttachmentExtensions[upload.ContentType]; !ok {
+			return ErrNoteAttachmentType
+		}
+		if upload.Size > MaxNoteAttachmentSizeByte {
+			return ErrNoteAttachmentTooLarge
+		}
+	}
+	return nil
+}
+
+// NoteAttachmentStorageKey returns where an attachment is stored
+// Format: order-notes/{tenant_id}/{order_id}/{attachment_id}{ext}
+func NoteAttachmentStorageKey(tenantID, orderID, attachmentID, contentType string) string {
+	return fmt.Sprintf("order-notes/%s/%s/%s%s", tenantID, orderID, attachmentID, noteAttachmentExtensions[contentType])
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// QueueDisplay is a tenant's "now serving" board for today's paid orders
+// Orders leave the board once completed or cancelled.
+type QueueDisplay struct {
+	QueueDate  string       `json:"queue_date"`  // Business day in the tenant's timezone, YYYY-MM-DD
+	NowServing []QueueEntry `json:"now_serving"` // Bumped by the kitchen and ready to collect, most recent first
+	Preparing  []QueueEntry `json:"preparing"`   // Paid and still in the kitchen, in queue order
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// QueueEntry is an order on the queue display
+// Guests are called by number only; no order details are shown publicly.
+type QueueEntry struct {
+	QueueNumber int        `json:"queue_number"`
+	ReadyAt     *time.Time `json:"ready_at,omitempty"`
+}
+
+// NewQueueDisplay splits today's open queue into ready and preparing orders
+// entries must be in queue order.
+func NewQueueDisplay(queueDate string, entries []QueueEntry, now time.Time) *QueueDisplay {
+	display := &QueueDisplay{
+		QueueDate:  queueDate,
+		NowServing: []QueueEntry{},
+		Preparing:  []QueueEntry{},
+		UpdatedAt:  now,
+	}
+
+	for _, entry := range entries {
+		if entry.ReadyAt == nil {
+			display.Preparing = append(display.Preparing, entry)
+			continue
+		}
+		display.NowServing = append(display.NowServing, entry)
+	}
+
+	// The most recently called numbers lead the board
+	sort.SliceStable(display.NowServing, func(i, j int) bool {
+		return display.NowServing[i].ReadyAt.After(*display.NowServing[j].ReadyAt)
+	})
+	return display
+}
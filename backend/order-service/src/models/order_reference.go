@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Order reference pattern limits
+// A formatted reference is as long as its pattern until the daily sequence outgrows its digits.
+const (
+	MaxOrderReferencePatternLength  = 32
+	MinOrderReferencePrefixLength   = 2
+	MaxOrderReferencePrefixLength   = 10
+	MinOrderReferenceSequenceDigits = 3
+	MaxOrderReferenceSequenceDigits = 6
+
+	// DefaultOrderReferencePrefix is used by the global generator, so no tenant may claim it
+	DefaultOrderReferencePrefix = "GO"
+)
+
+var (
+	ErrInvalidOrderReferencePattern = fmt.Errorf(
+		"order_reference_pattern must start with a %d-%d character prefix and a dash, contain YYYY or YY, MM, DD and a %d-%d digit #### sequence, use only A-Z, 0-9 and dashes and be at most %d characters",
+		MinOrderReferencePrefixLength, MaxOrderReferencePrefixLength,
+		MinOrderReferenceSequenceDigits, MaxOrderReferenceSequenceDigits,
+		MaxOrderReferencePatternLength,
+	)
+	ErrOrderReferencePrefixReserved = errors.New("order reference prefix GO is reserved")
+	ErrOrderReferencePrefixTaken    = errors.New("order reference prefix is already used by another tenant")
+)
+
+// orderReferenceTokens are the placeholders of a pattern, longest first so YYYY wins over YY
+var orderReferenceTokens = []string{"YYYY", "YY", "MM", "DD"}
+
+// NormalizeOrderReferencePattern upper-cases and checks a tenant's order reference pattern
+// Example: WARUNG-YYYYMMDD-#### gives WARUNG-20261016-0001 for the day's first order.
+// The prefix before the first dash is literal and identifies the tenant's references.
+func NormalizeOrderReferencePattern(pattern string) (string, error) {
+	pattern = strings.ToUpper(strings.TrimSpace(pattern))
+	if len(pattern) > MaxOrderReferencePatternLength {
+		return "", ErrInvalidOrderReferencePattern
+	}
+
+	prefix, rest, ok := strings.Cut(pattern, "-")
+	if !ok || len(prefix) < MinOrderReferencePrefixLength || len(prefix) > MaxOrderReferencePrefixLength || rest == "" {
+		return "", ErrInvalidOrderReferencePattern
+	}
+	for _, c := range pattern {
+		if !((c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '#') {
+			return "", ErrInvalidOrderReferencePattern
+		}
+	}
+	if strings.Contains(prefix, "#") {
+		return "", ErrInvalidOrderReferencePattern
+	}
+	if prefix == DefaultOrderReferencePrefix {
+		return "", ErrOrderReferencePrefixReserved
+	}
+
+	seen := map[string]bool{}
+	sequences := 0
+	for i := 0; i < len(rest); {
+		if rest[i] == '#' {
+			n := len(rest[i:]) - len(strings.TrimLeft(rest[i:], "#"))
+			if n < MinOrderReferenceSequenceDigits || n > MaxOrderReferenceSequenceDigits {
+				return "", ErrInvalidOrderReferencePattern
+			}
+			sequences++
+			i += n
+			continue
+		}
+		token := orderReferenceToken(rest[i:])
+		if token == "" {
+			i++
+			continue
+		}
+		seen[token] = true
+		i += len(token)
+	}
+
+	// The day's date and the daily sequence together keep a tenant's references unique
+	if sequences != 1 || !(seen["YYYY"] || seen["YY"]) || !seen["MM"] || !seen["DD"] {
+		return "", ErrInvalidOrderReferencePattern
+	}
+	return pattern, nil
+}
+
+// OrderReferencePrefix returns the literal prefix of a pattern, before its first dash
+func OrderReferencePrefix(pattern string) string {
+	prefix, _, _ := strings.Cut(pattern, "-")
+	return prefix
+}
+
+// FormatOrderReference fills a normalized pattern with the day and the day's sequence number
+// A sequence that outgrows its digits is written in full rather than wrapped.
+func FormatOrderReference(pattern string, day time.Time, sequence int) string {
+	prefix, rest, _ := strings.Cut(pattern, "-")
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('-')
+	for i := 0; i < len(rest); {
+		if rest[i] == '#' {
+			n := len(rest[i:]) - len(strings.TrimLeft(rest[i:], "#"))
+			fmt.Fprintf(&b, "%0*d", n, sequence)
+			i += n
+			continue
+		}
+		token := orderReferenceToken(rest[i:])
+		switch token {
+		case "YYYY":
+			fmt.Fprintf(&b, "%04d", day.Year())
+		case "YY":
+			fmt.Fprintf(&b, "%02d", day.Year()%100)
+		case "MM":
+			fmt.Fprintf(&b, "%02d", int(day.Month()))
+		case "DD":
+			fmt.Fprintf(&b, "%02d", day.Day())
+		default:
+			b.WriteByte(rest[i])
+			i++
+			continue
+		}
+		i += len(token)
+	}
+	return b.String()
+}
+
+// orderReferenceToken returns the placeholder s starts with, or "" for a literal character
+func orderReferenceToken(s string) string {
+	for _, token := range orderReferenceTokens {
+		if strings.HasPrefix(s, token) {
+			return token
+		}
+	}
+	return ""
+}
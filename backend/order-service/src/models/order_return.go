@@ -0,0 +1,237 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReturnStatus is where a return (RMA) stands
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested" // Waiting for a manager's decision
+	ReturnStatusApproved  ReturnStatus = "approved"  // The customer may bring the goods back
+	ReturnStatusRejected  ReturnStatus = "rejected"
+	ReturnStatusReceived  ReturnStatus = "received"  // Goods are back and restocked unless damaged
+	ReturnStatusCompleted ReturnStatus = "completed" // The resolution has been issued
+)
+
+// ReturnResolution is what the customer gets for the returned goods
+type ReturnResolution string
+
+const (
+	ReturnResolutionRefund      ReturnResolution = "refund"       // Refunded through the gateway that took the payment
+	ReturnResolutionExchange    ReturnResolution = "exchange"     // Replacement goods in a new order paid with the credit
+	ReturnResolutionStoreCredit ReturnResolution = "store_credit" // A single-use voucher worth the credit
+)
+
+// StoreCreditCodePrefix starts the voucher codes issued as store credit
+const StoreCreditCodePrefix = "CREDIT-"
+
+var (
+	ErrInvalidReturn          = errors.New("invalid return")
+	ErrReturnNotFound         = errors.New("return not found")
+	ErrOrderNotReturnable     = errors.New("only PAID or COMPLETE orders can have goods returned")
+	ErrReturnQuantityExceeded = errors.New("return quantity exceeds what is left to return on the order item")
+	ErrReturnStatusConflict   = errors.New("return is not in a status that allows this")
+	ErrExchangeExceedsCredit  = errors.New("exchange items cost more than the return credit")
+	ErrReturnNothingToCredit  = errors.New("the order has already been credited in full")
+)
+
+// returnTransitions lists the statuses a return can move to from each status
+var returnTransitions = map[ReturnStatus][]ReturnStatus{
+	ReturnStatusRequested: {ReturnStatusApproved, ReturnStatusRejected},
+	ReturnStatusApproved:  {ReturnStatusReceived, ReturnStatusRejected},
+	ReturnStatusReceived:  {ReturnStatusCompleted},
+}
+
+// OrderReturn is a return merchandise authorization against an order's items
+type OrderReturn struct {
+	ID                   string            `json:"id"`
+	TenantID             string            `json:"tenant_id"`
+	OrderID              string            `json:"order_id"`
+	OrderReference       string            `json:"order_reference,omitempty"`
+	ReturnNumber         string            `json:"return_number"`
+	Status               ReturnStatus      `json:"status"`
+	Resolution           ReturnResolution  `json:"resolution"`
+	Reason               *string           `json:"reason,omitempty"`
+	CreditAmount         int               `json:"credit_amount"`
+	RejectionReason      *string           `json:"rejection_reason,omitempty"`
+	RefundID             *string           `json:"refund_id,omitempty"`
+	StoreCreditVoucherID *string           `json:"store_credit_voucher_id,omitempty"`
+	StoreCreditCode      *string           `json:"store_credit_code,omitempty"`
+	ExchangeOrderID      *string           `json:"exchange_order_id,omitempty"`
+	RequestedBy          *string           `json:"requested_by,omitempty"`
+	ApprovedBy           *string           `json:"approved_by,omitempty"`
+	ReceivedBy           *string           `json:"received_by,omitempty"`
+	CompletedBy          *string           `json:"completed_by,omitempty"`
+	Items                []OrderReturnItem `json:"items"`
+	CreatedAt            time.Time         `json:"created_at"`
+	ApprovedAt           *time.Time        `json:"approved_at,omitempty"`
+	RejectedAt           *time.Time        `json:"rejected_at,omitempty"`
+	ReceivedAt           *time.Time        `json:"received_at,omitempty"`
+	CompletedAt          *time.Time        `json:"completed_at,omitempty"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+}
+
+// OrderReturnItem is a quantity of one order item being returned
+type OrderReturnItem struct {
+	ID          string  `json:"id"`
+	ReturnID    string  `json:"return_id"`
+	OrderItemID string  `json:"order_item_id"`
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   int     `json:"unit_price"` // Price paid on the original order
+	Reason      *string `json:"reason,omitempty"`
+	Restocked   bool    `json:"restocked"`
+}
+
+// CanTransition reports whether the return can move to the given status
+func (r *OrderReturn) CanTransition(to ReturnStatus) bool {
+	for _, status := range returnTransitions[r.Status] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateReturnRequest opens a return against an order's items
+type CreateReturnRequest struct {
+	Resolution ReturnResolution    `json:"resolution"`
+	Reason     *string             `json:"reason,omitempty"`
+	Items      []ReturnItemRequest `json:"items"`
+}
+
+// ReturnItemRequest is a quantity of an order item to return
+type ReturnItemRequest struct {
+	OrderItemID string  `json:"order_item_id"`
+	Quantity    int     `json:"quantity"`
+	Reason      *string `json:"reason,omitempty"`
+}
+
+// Validate checks the resolution and requested items
+func (r *CreateReturnRequest) Validate() error {
+	switch r.Resolution {
+	case ReturnResolutionRefund, ReturnResolutionExchange, ReturnResolutionStoreCredit:
+	default:
+		return fmt.Errorf("%w: resolution must be refund, exchange or store_credit", ErrInvalidReturn)
+	}
+	if len(r.Items) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidReturn)
+	}
+	for _, item := range r.Items {
+		if item.OrderItemID == "" {
+			return fmt.Errorf("%w: order_item_id is required", ErrInvalidReturn)
+		}
+		if item.Quantity < 1 {
+			return fmt.Errorf("%w: quantity must be at least 1", ErrInvalidReturn)
+		}
+		if item.Reason != nil && len(*item.Reason) > 500 {
+			return fmt.Errorf("%w: item reason must be at most 500 characters", ErrInvalidReturn)
+		}
+	}
+	if r.Reason != nil && len(*r.Reason) > 500 {
+		return fmt.Errorf("%w: reason must be at most 500 characters", ErrInvalidReturn)
+	}
+	return nil
+}
+
+// RejectReturnRequest declines a return
+type RejectReturnRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ReceiveReturnRequest records that the returned goods are back
+// Every item is restocked except the damaged ones.
+type ReceiveReturnRequest struct {
+	DamagedItemIDs []string `json:"damaged_item_ids,omitempty"`
+}
+
+// CompleteReturnRequest issues a received return's resolution
+// Exchange returns list the replacement goods; any credit they leave over becomes store credit.
+type CompleteReturnRequest struct {
+	ExchangeItems []CreateOrderItemReq `json:"exchange_items,omitempty"`
+}
+
+// BuildReturnItems turns requested quantities into return items priced as on the order
+// returned holds the quantity of each order item already on other, not rejected, returns.
+// Requests for the same order item are merged. Also returns the items' total value.
+func BuildReturnItems(orderItems []OrderItem, returned map[string]int, requested []ReturnItemRequest) ([]OrderReturnItem, int, error) {
+	byID := make(map[string]OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		byID[item.ID] = item
+	}
+
+	var items []OrderReturnItem
+	index := make(map[string]int, len(requested))
+	value := 0
+	for _, req := range requested {
+		orderItem, ok := byID[req.OrderItemID]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: order item %s is not on the order", ErrInvalidReturn, req.OrderItemID)
+		}
+
+		if i, seen := index[req.OrderItemID]; seen {
+			items[i].Quantity += req.Quantity
+			if items[i].Reason == nil {
+				items[i].Reason = req.Reason
+			}
+		} else {
+			index[req.OrderItemID] = len(items)
+			items = append(items, OrderReturnItem{
+				OrderItemID: orderItem.ID,
+				ProductID:   orderItem.ProductID,
+				ProductName: orderItem.ProductName,
+				Quantity:    req.Quantity,
+				UnitPrice:   orderItem.UnitPrice,
+				Reason:      req.Reason,
+			})
+		}
+		value += req.Quantity * orderItem.UnitPrice
+	}
+
+	for _, item := range items {
+		if item.Quantity > byID[item.OrderItemID].Quantity-returned[item.OrderItemID] {
+			return nil, 0, fmt.Errorf("%w: %s", ErrReturnQuantityExceeded, item.ProductName)
+		}
+	}
+	return items, value, nil
+}
+
+// ReturnCredit caps a return's value at what is left of the order total after earlier returns
+// Discounts and loyalty points already lowered the total, so they are never credited twice.
+func ReturnCredit(value, orderTotal, alreadyCredited int) int {
+	remaining := orderTotal - alreadyCredited
+	if remaining < 0 {
+		remaining = 0
+	}
+	if value > remaining {
+		return remaining
+	}
+	return value
+}
+
+// SplitExchangeCredit returns the cost of the exchange items and the credit they leave over
+func SplitExchangeCredit(credit int, items []CreateOrderItemReq) (int, int, error) {
+	if len(items) == 0 {
+		return 0, 0, fmt.Errorf("%w: exchange_items are required for an exchange", ErrInvalidReturn)
+	}
+
+	total := 0
+	for _, item := range items {
+		if item.ProductID == "" || item.ProductName == "" {
+			return 0, 0, fmt.Errorf("%w: exchange items need product_id and product_name", ErrInvalidReturn)
+		}
+		if item.Quantity < 1 || item.UnitPrice < 0 {
+			return 0, 0, fmt.Errorf("%w: exchange items need a quantity of at least 1 and a non-negative unit_price", ErrInvalidReturn)
+		}
+		total += item.Quantity * item.UnitPrice
+	}
+	if total > credit {
+		return 0, 0, ErrExchangeExceedsCredit
+	}
+	return total, credit - total, nil
+}
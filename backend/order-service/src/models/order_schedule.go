@@ -0,0 +1,264 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scheduled order errors
+var (
+	ErrSchedulingDisabled       = errors.New("scheduled orders are not enabled for this store")
+	ErrSchedulingNotAllowed     = errors.New("only pickup and delivery orders can be scheduled")
+	ErrScheduledSlotUnavailable = errors.New("scheduled_for is not the start of a slot within business hours")
+	ErrScheduledSlotTooSoon     = errors.New("scheduled_for is too soon to prepare the order")
+	ErrScheduledSlotTooFar      = errors.New("scheduled_for is too far ahead")
+	ErrScheduledSlotFull        = errors.New("the selected time slot is fully booked")
+	ErrInvalidBusinessHours     = errors.New("business_hours must map weekdays (monday to sunday) to open and close times in HH:MM, with open before close")
+	ErrInvalidSlotDuration      = errors.New("slot_duration_minutes must be between 5 and 240")
+	ErrInvalidSlotCapacity      = errors.New("slot_capacity cannot be negative")
+	ErrInvalidSchedulingHorizon = errors.New("scheduling_max_days_ahead must be between 0 and 60")
+)
+
+// DayHours is a store's opening window on one weekday, as local "HH:MM" times
+type DayHours struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// BusinessHours maps lowercase weekday names to opening windows; missing days are closed
+type BusinessHours map[string]DayHours
+
+// Scan implements sql.Scanner for BusinessHours (JSONB)
+func (h *BusinessHours) Scan(value interface{}) error {
+	*h = BusinessHours{}
+	if value == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into BusinessHours", value)
+	}
+	return json.Unmarshal(data, h)
+}
+
+// Value implements driver.Valuer for BusinessHours (JSONB)
+func (h BusinessHours) Value() (driver.Value, error) {
+	if h == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Validate checks weekday names and that each day opens before it closes
+func (h BusinessHours) Validate() error {
+	for day, hours := range h {
+		if !weekdayNames[day] {
+			return ErrInvalidBusinessHours
+		}
+		openMinutes, okOpen := parseClock(hours.Open)
+		closeMinutes, okClose := parseClock(hours.Close)
+		if !okOpen || !okClose || openMinutes >= closeMinutes {
+			return ErrInvalidBusinessHours
+		}
+	}
+	return nil
+}
+
+// window returns the opening and closing time on the local date of day
+func (h BusinessHours) window(day time.Time) (openAt, closeAt time.Time, ok bool) {
+	hours, found := h[strings.ToLower(day.Weekday().String())]
+	if !found {
+		return time.Time{}, time.Time{}, false
+	}
+	return hours.window(day)
+}
+
+// window returns the opening and closing time of these hours on the local date of day
+func (hours DayHours) window(day time.Time) (openAt, closeAt time.Time, ok bool) {
+	openMinutes, okOpen := parseClock(hours.Open)
+	closeMinutes, okClose := parseClock(hours.Close)
+	if !okOpen || !okClose || openMinutes >= closeMinutes {
+		return time.Time{}, time.Time{}, false
+	}
+
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return midnight.Add(time.Duration(openMinutes) * time.Minute), midnight.Add(time.Duration(closeMinutes) * time.Minute), true
+}
+
+var weekdayNames = map[string]bool{
+	"sunday": true, "monday": true, "tuesday": true, "wednesday": true,
+	"thursday": true, "friday": true, "saturday": true,
+}
+
+// parseClock converts "HH:MM" to minutes after midnight; "24:00" is accepted as a closing time
+func parseClock(clock string) (int, bool) {
+	if clock == "24:00" {
+		return 24 * 60, true
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// TimeSlot is a bookable pickup/delivery window
+type TimeSlot struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Remaining *int      `json:"remaining,omitempty"` // Orders still accepted; omitted when capacity is unlimited
+}
+
+// ValidateScheduling checks the scheduling fields that are being changed
+func (r *UpdateOrderSettingsRequest) ValidateScheduling() error {
+	if r.BusinessHours != nil {
+		if err := r.BusinessHours.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.SlotDurationMinutes != nil && (*r.SlotDurationMinutes < 5 || *r.SlotDurationMinutes > 240) {
+		return ErrInvalidSlotDuration
+	}
+	if r.SlotCapacity != nil && *r.SlotCapacity < 0 {
+		return ErrInvalidSlotCapacity
+	}
+	if r.SchedulingMaxDaysAhead != nil && (*r.SchedulingMaxDaysAhead < 0 || *r.SchedulingMaxDaysAhead > 60) {
+		return ErrInvalidSchedulingHorizon
+	}
+	return nil
+}
+
+// Location returns the store's local timezone
+// auto_complete_timezone doubles as the store timezone; business hours are read in it too.
+func (s *OrderSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.AutoCompleteTimezone)
+	if err != nil || s.AutoCompleteTimezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// slotDuration returns the configured slot length
+func (s *OrderSettings) slotDuration() time.Duration {
+	minutes := s.SlotDurationMinutes
+	if minutes <= 0 {
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// earliestSlotStart is the first moment an order placed now can be ready
+func (s *OrderSettings) earliestSlotStart(now time.Time) time.Time {
+	return now.Add(time.Duration(s.EstimatedPrepTime) * time.Minute)
+}
+
+// bookingHorizonEnd is the end of the last local day that may be booked
+func (s *OrderSettings) bookingHorizonEnd(now time.Time) time.Time {
+	local := now.In(s.Location())
+	return time.Date(local.Year(), local.Month(), local.Day()+s.SchedulingMaxDaysAhead+1, 0, 0, 0, 0, local.Location())
+}
+
+// ValidateScheduledSlot checks that slot is a bookable slot start at the given time
+// A slot must start on a slot boundary counted from opening time, end by closing time,
+// leave at least the estimated prep time and fall within the booking horizon.
+func (s *OrderSettings) ValidateScheduledSlot(slot, now time.Time) error {
+	if !s.SchedulingEnabled {
+		return ErrSchedulingDisabled
+	}
+	if slot.Before(s.earliestSlotStart(now)) {
+		return ErrScheduledSlotTooSoon
+	}
+	if !slot.Before(s.bookingHorizonEnd(now)) {
+		return ErrScheduledSlotTooFar
+	}
+
+	local := slot.In(s.Location())
+	openAt, closeAt, ok := s.openingWindow(local)
+	if !ok || local.Before(openAt) || local.Add(s.slotDuration()).After(closeAt) {
+		return ErrScheduledSlotUnavailable
+	}
+	if local.Sub(openAt)%s.slotDuration() != 0 {
+		return ErrScheduledSlotUnavailable
+	}
+	return nil
+}
+
+// Slots lists the bookable slots from now to the end of the booking horizon
+// Remaining capacity is not filled in; see TimeSlot.Remaining.
+func (s *OrderSettings) Slots(now time.Time) []TimeSlot {
+	slots := []TimeSlot{}
+	if !s.SchedulingEnabled {
+		return slots
+	}
+
+	duration := s.slotDuration()
+	earliest := s.earliestSlotStart(now)
+	local := now.In(s.Location())
+	for day := 0; day <= s.SchedulingMaxDaysAhead; day++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, local.Location())
+		openAt, closeAt, ok := s.openingWindow(date)
+		if !ok {
+			continue
+		}
+		for start := openAt; !start.Add(duration).After(closeAt); start = start.Add(duration) {
+			if start.Before(earliest) {
+				continue
+			}
+			slots = append(slots, TimeSlot{Start: start, End: start.Add(duration)})
+		}
+	}
+	return slots
+}
+
+// SlotFull reports whether a slot already holding booked orders can take no more
+func (s *OrderSettings) SlotFull(booked int) bool {
+	return s.SlotCapacity > 0 && booked >= s.SlotCapacity
+}
+
+// FillRemaining sets each slot's remaining capacity from the orders booked per slot start
+// Slots stay listed when full (Remaining 0) so guests can see the store is busy then.
+func (s *OrderSettings) FillRemaining(slots []TimeSlot, booked map[time.Time]int) {
+	if s.SlotCapacity <= 0 {
+		return
+	}
+	for i := range slots {
+		remaining := s.SlotCapacity - booked[slots[i].Start.UTC()]
+		if remaining < 0 {
+			remaining = 0
+		}
+		slots[i].Remaining = &remaining
+	}
+}
+
+// DueAt is when the order should be ready for pickup or handed to delivery
+// Scheduled orders are due at their slot; others prepMinutes after payment, or after
+// placement while unpaid.
+func (o *GuestOrder) DueAt(prepMinutes int) time.Time {
+	if o.ScheduledFor != nil {
+		return *o.ScheduledFor
+	}
+	from := o.CreatedAt
+	if o.PaidAt != nil {
+		from = *o.PaidAt
+	}
+	return from.Add(time.Duration(prepMinutes) * time.Minute)
+}
+
+// IsLate reports whether a paid order that staff have not completed is past due
+func (o *GuestOrder) IsLate(now time.Time, prepMinutes int) bool {
+	return o.Status == OrderStatusPaid && now.After(o.DueAt(prepMinutes))
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidContactSearch is returned for a contact search that is neither an email nor a phone number
+var ErrInvalidContactSearch = errors.New("search must be a full phone number or email address")
+
+// minSearchPhoneDigits is the shortest phone number accepted as a contact search
+const minSearchPhoneDigits = 8
+
+// ContactSearch is an admin order search on a customer's exact phone number or email
+// Customer contacts are encrypted, so orders are matched on HMAC search hashes of the
+// normalized value rather than by substring.
+type ContactSearch struct {
+	Email bool   // Whether Value is an email rather than a phone number
+	Value string // Normalized phone number or email
+}
+
+// ParseContactSearch reads an admin search query as an email or phone number
+func ParseContactSearch(query string) (*ContactSearch, error) {
+	query = strings.TrimSpace(query)
+	if strings.Contains(query, "@") {
+		return &ContactSearch{Email: true, Value: NormalizeCustomerEmail(query)}, nil
+	}
+	if strings.ContainsAny(query, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		return nil, ErrInvalidContactSearch
+	}
+	phone := NormalizeCustomerPhone(query)
+	if len(phone) < minSearchPhoneDigits {
+		return nil, ErrInvalidContactSearch
+	}
+	return &ContactSearch{Value: phone}, nil
+}
+
+// NormalizeCustomerPhone reduces a phone number to its digits in local 0-prefixed form
+// 0812..., 62812... and +62812... are the same Indonesian number.
+func NormalizeCustomerPhone(phone string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if strings.HasPrefix(normalized, "62") {
+		normalized = "0" + normalized[2:]
+	}
+	return normalized
+}
+
+// NormalizeCustomerEmail lowercases an email and trims surrounding whitespace
+func NormalizeCustomerEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -1,35 +1,263 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// AutoCompleteMode selects when PAID orders are completed automatically
+type AutoCompleteMode string
+
+const (
+	AutoCompleteDisabled  AutoCompleteMode = "disabled"
+	AutoCompleteAfterPaid AutoCompleteMode = "after_paid" // auto_complete_after_hours after payment
+	AutoCompleteEndOfDay  AutoCompleteMode = "end_of_day" // at local midnight in auto_complete_timezone
+)
+
+var (
+	ErrInvalidAutoCompleteMode     = errors.New("auto_complete_mode must be one of: disabled, after_paid, end_of_day")
+	ErrInvalidAutoCompleteHours    = errors.New("auto_complete_after_hours must be greater than 0")
+	ErrInvalidAutoCompleteTimezone = errors.New("auto_complete_timezone must be a valid IANA timezone")
+	ErrInvalidServiceChargePercent = errors.New("service_charge_percent must be between 0 and 100")
+	ErrInvalidTaxPercent           = errors.New("tax_percent must be between 0 and 100")
+	ErrInvalidPaymentExpiry        = errors.New("payment_expiry_minutes must be between 10 and 60")
+	ErrInvalidRefundThreshold      = errors.New("refund_approval_threshold must be non-negative")
+	ErrInvalidReservationTTL       = errors.New("reservation_ttl_minutes must be between 10 and 120")
+	ErrInvalidReservationExtension = errors.New("reservation_extension_minutes must be between 0 and 60")
+)
+
+// Bounds and default for how long a QRIS or e-wallet charge stays payable
+const (
+	MinPaymentExpiryMinutes     = 10
+	MaxPaymentExpiryMinutes     = 60
+	DefaultPaymentExpiryMinutes = 15
+)
+
+// Bounds and defaults for how long checkout stock is held and how much a guest can extend it
+const (
+	MinReservationTTLMinutes           = 10
+	MaxReservationTTLMinutes           = 120
+	DefaultReservationTTLMinutes       = 15
+	MaxReservationExtensionMinutes     = 60
+	DefaultReservationExtensionMinutes = 10
+	MaxReservationExtensions           = 3 // Per order, so a guest cannot hold stock indefinitely
+)
+
+// IsValid checks if the mode is supported
+func (m AutoCompleteMode) IsValid() bool {
+	switch m {
+	case AutoCompleteDisabled, AutoCompleteAfterPaid, AutoCompleteEndOfDay:
+		return true
+	}
+	return false
+}
 
 // OrderSettings represents the order configuration for a tenant
 type OrderSettings struct {
-	ID                       string    `json:"id" db:"id"`
-	TenantID                 string    `json:"tenant_id" db:"tenant_id"`
-	DeliveryEnabled          bool      `json:"delivery_enabled" db:"delivery_enabled"`
-	PickupEnabled            bool      `json:"pickup_enabled" db:"pickup_enabled"`
-	DineInEnabled            bool      `json:"dine_in_enabled" db:"dine_in_enabled"`
-	DefaultDeliveryFee       int       `json:"default_delivery_fee" db:"default_delivery_fee"`
-	MinOrderAmount           int       `json:"min_order_amount" db:"min_order_amount"`
-	MaxDeliveryDistance      float64   `json:"max_delivery_distance" db:"max_delivery_distance"`
-	EstimatedPrepTime        int       `json:"estimated_prep_time" db:"estimated_prep_time"`
-	AutoAcceptOrders         bool      `json:"auto_accept_orders" db:"auto_accept_orders"`
-	RequirePhoneVerification bool      `json:"require_phone_verification" db:"require_phone_verification"`
-	ChargeDeliveryFee        bool      `json:"charge_delivery_fee" db:"charge_delivery_fee"`
-	CreatedAt                time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt                time.Time `json:"updated_at" db:"updated_at"`
+	ID                       string           `json:"id" db:"id"`
+	TenantID                 string           `json:"tenant_id" db:"tenant_id"`
+	DeliveryEnabled          bool             `json:"delivery_enabled" db:"delivery_enabled"`
+	PickupEnabled            bool             `json:"pickup_enabled" db:"pickup_enabled"`
+	DineInEnabled            bool             `json:"dine_in_enabled" db:"dine_in_enabled"`
+	DefaultDeliveryFee       int              `json:"default_delivery_fee" db:"default_delivery_fee"`
+	MinOrderAmount           int              `json:"min_order_amount" db:"min_order_amount"`
+	MaxDeliveryDistance      float64          `json:"max_delivery_distance" db:"max_delivery_distance"`
+	EstimatedPrepTime        int              `json:"estimated_prep_time" db:"estimated_prep_time"`
+	AutoAcceptOrders         bool             `json:"auto_accept_orders" db:"auto_accept_orders"`
+	RequirePhoneVerification bool             `json:"require_phone_verification" db:"require_phone_verification"`
+	ChargeDeliveryFee        bool             `json:"charge_delivery_fee" db:"charge_delivery_fee"`
+	AutoCompleteMode         AutoCompleteMode `json:"auto_complete_mode" db:"auto_complete_mode"`
+	AutoCompleteAfterHours   int              `json:"auto_complete_after_hours" db:"auto_complete_after_hours"`
+	AutoCompleteTimezone     string           `json:"auto_complete_timezone" db:"auto_complete_timezone"`
+	ServiceChargePercent     float64          `json:"service_charge_percent" db:"service_charge_percent"`
+	TaxPercent               float64          `json:"tax_percent" db:"tax_percent"`
+	SchedulingEnabled        bool             `json:"scheduling_enabled" db:"scheduling_enabled"`
+	BusinessHours            BusinessHours    `json:"business_hours" db:"business_hours"`
+	SlotDurationMinutes      int              `json:"slot_duration_minutes" db:"slot_duration_minutes"`
+	SlotCapacity             int              `json:"slot_capacity" db:"slot_capacity"` // 0 means unlimited
+	SchedulingMaxDaysAhead   int              `json:"scheduling_max_days_ahead" db:"scheduling_max_days_ahead"`
+
+	MinOrderAmountByDeliveryType MinOrderAmounts     `json:"min_order_amount_by_delivery_type" db:"min_order_amount_by_delivery_type"` // Overrides min_order_amount per delivery type
+	MaxItemsPerOrder             int                 `json:"max_items_per_order" db:"max_items_per_order"`                             // 0 means unlimited
+	PaymentOutagePolicy          PaymentOutagePolicy `json:"payment_outage_policy" db:"payment_outage_policy"`
+	GuestCancelWindowMinutes     int                 `json:"guest_cancel_window_minutes" db:"guest_cancel_window_minutes"`     // 0 disables guest cancellation
+	PaymentExpiryMinutes         int                 `json:"payment_expiry_minutes" db:"payment_expiry_minutes"`               // QRIS and e-wallet charges; virtual accounts last 1 hour
+	RefundApprovalThreshold      int                 `json:"refund_approval_threshold" db:"refund_approval_threshold"`         // Refunds above it need an owner; 0 lets managers approve any amount
+	ReservationTTLMinutes        int                 `json:"reservation_ttl_minutes" db:"reservation_ttl_minutes"`             // Never shorter than payment_expiry_minutes
+	ReservationExtensionMinutes  int                 `json:"reservation_extension_minutes" db:"reservation_extension_minutes"` // 0 disables guest extensions
+	OrderReferencePattern        *string             `json:"order_reference_pattern" db:"order_reference_pattern"`             // nil uses the global GO-XXXXXX generator
+	SLAPreparingMinutes          int                 `json:"sla_preparing_minutes" db:"sla_preparing_minutes"`                 // Payment to preparation start; 0 is not timed
+	SLAReadyMinutes              int                 `json:"sla_ready_minutes" db:"sla_ready_minutes"`                         // Preparation start to ready; 0 is not timed
+	SLAAtRiskPercent             int                 `json:"sla_at_risk_percent" db:"sla_at_risk_percent"`
+	Holidays                     Holidays            `json:"holidays" db:"holidays"`
+	OrderingCutoffMinutes        int                 `json:"ordering_cutoff_minutes" db:"ordering_cutoff_minutes"` // Immediate orders stop this long before closing
+	ClosedOrderPolicy            ClosedOrderPolicy   `json:"closed_order_policy" db:"closed_order_policy"`
+	CreatedAt                    time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt                    time.Time           `json:"updated_at" db:"updated_at"`
 }
 
 // UpdateOrderSettingsRequest represents the request to update order settings
 type UpdateOrderSettingsRequest struct {
-	DeliveryEnabled          *bool    `json:"delivery_enabled"`
-	PickupEnabled            *bool    `json:"pickup_enabled"`
-	DineInEnabled            *bool    `json:"dine_in_enabled"`
-	DefaultDeliveryFee       *int     `json:"default_delivery_fee"`
-	MinOrderAmount           *int     `json:"min_order_amount"`
-	MaxDeliveryDistance      *float64 `json:"max_delivery_distance"`
-	EstimatedPrepTime        *int     `json:"estimated_prep_time"`
-	AutoAcceptOrders         *bool    `json:"auto_accept_orders"`
-	RequirePhoneVerification *bool    `json:"require_phone_verification"`
-	ChargeDeliveryFee        *bool    `json:"charge_delivery_fee"`
+	DeliveryEnabled          *bool             `json:"delivery_enabled"`
+	PickupEnabled            *bool             `json:"pickup_enabled"`
+	DineInEnabled            *bool             `json:"dine_in_enabled"`
+	DefaultDeliveryFee       *int              `json:"default_delivery_fee"`
+	MinOrderAmount           *int              `json:"min_order_amount"`
+	MaxDeliveryDistance      *float64          `json:"max_delivery_distance"`
+	EstimatedPrepTime        *int              `json:"estimated_prep_time"`
+	AutoAcceptOrders         *bool             `json:"auto_accept_orders"`
+	RequirePhoneVerification *bool             `json:"require_phone_verification"`
+	ChargeDeliveryFee        *bool             `json:"charge_delivery_fee"`
+	AutoCompleteMode         *AutoCompleteMode `json:"auto_complete_mode"`
+	AutoCompleteAfterHours   *int              `json:"auto_complete_after_hours"`
+	AutoCompleteTimezone     *string           `json:"auto_complete_timezone"`
+	ServiceChargePercent     *float64          `json:"service_charge_percent"`
+	TaxPercent               *float64          `json:"tax_percent"`
+	SchedulingEnabled        *bool             `json:"scheduling_enabled"`
+	BusinessHours            *BusinessHours    `json:"business_hours"`
+	SlotDurationMinutes      *int              `json:"slot_duration_minutes"`
+	SlotCapacity             *int              `json:"slot_capacity"`
+	SchedulingMaxDaysAhead   *int              `json:"scheduling_max_days_ahead"`
+
+	MinOrderAmountByDeliveryType *MinOrderAmounts     `json:"min_order_amount_by_delivery_type"` // Replaces all overrides; {} clears them
+	MaxItemsPerOrder             *int                 `json:"max_items_per_order"`
+	PaymentOutagePolicy          *PaymentOutagePolicy `json:"payment_outage_policy"`
+	GuestCancelWindowMinutes     *int                 `json:"guest_cancel_window_minutes"`
+	PaymentExpiryMinutes         *int                 `json:"payment_expiry_minutes"`
+	RefundApprovalThreshold      *int                 `json:"refund_approval_threshold"` // Owner only
+	ReservationTTLMinutes        *int                 `json:"reservation_ttl_minutes"`
+	ReservationExtensionMinutes  *int                 `json:"reservation_extension_minutes"`
+	OrderReferencePattern        *string              `json:"order_reference_pattern"` // "" goes back to the global generator
+	SLAPreparingMinutes          *int                 `json:"sla_preparing_minutes"`
+	SLAReadyMinutes              *int                 `json:"sla_ready_minutes"`
+	SLAAtRiskPercent             *int                 `json:"sla_at_risk_percent"`
+	Holidays                     *Holidays            `json:"holidays"` // Replaces all holidays; [] clears them
+	OrderingCutoffMinutes        *int                 `json:"ordering_cutoff_minutes"`
+	ClosedOrderPolicy            *ClosedOrderPolicy   `json:"closed_order_policy"`
+}
+
+// ValidateAutoComplete checks the auto-completion fields that are being changed
+func (r *UpdateOrderSettingsRequest) ValidateAutoComplete() error {
+	if r.AutoCompleteMode != nil && !r.AutoCompleteMode.IsValid() {
+		return ErrInvalidAutoCompleteMode
+	}
+	if r.AutoCompleteAfterHours != nil && *r.AutoCompleteAfterHours <= 0 {
+		return ErrInvalidAutoCompleteHours
+	}
+	if r.AutoCompleteTimezone != nil {
+		if _, err := time.LoadLocation(*r.AutoCompleteTimezone); err != nil || *r.AutoCompleteTimezone == "" {
+			return ErrInvalidAutoCompleteTimezone
+		}
+	}
+	return nil
+}
+
+// ValidateCharges checks the service charge and tax percentages that are being changed
+func (r *UpdateOrderSettingsRequest) ValidateCharges() error {
+	if r.ServiceChargePercent != nil && (*r.ServiceChargePercent < 0 || *r.ServiceChargePercent > 100) {
+		return ErrInvalidServiceChargePercent
+	}
+	if r.TaxPercent != nil && (*r.TaxPercent < 0 || *r.TaxPercent > 100) {
+		return ErrInvalidTaxPercent
+	}
+	return nil
+}
+
+// ValidatePaymentExpiry checks the payment expiry if it is being changed
+func (r *UpdateOrderSettingsRequest) ValidatePaymentExpiry() error {
+	if r.PaymentExpiryMinutes != nil && (*r.PaymentExpiryMinutes < MinPaymentExpiryMinutes || *r.PaymentExpiryMinutes > MaxPaymentExpiryMinutes) {
+		return ErrInvalidPaymentExpiry
+	}
+	return nil
+}
+
+// ValidateRefundApproval checks the refund approval threshold if it is being changed
+func (r *UpdateOrderSettingsRequest) ValidateRefundApproval() error {
+	if r.RefundApprovalThreshold != nil && *r.RefundApprovalThreshold < 0 {
+		return ErrInvalidRefundThreshold
+	}
+	return nil
+}
+
+// ValidateReservation checks the stock hold settings that are being changed
+func (r *UpdateOrderSettingsRequest) ValidateReservation() error {
+	if r.ReservationTTLMinutes != nil && (*r.ReservationTTLMinutes < MinReservationTTLMinutes || *r.ReservationTTLMinutes > MaxReservationTTLMinutes) {
+		return ErrInvalidReservationTTL
+	}
+	if r.ReservationExtensionMinutes != nil && (*r.ReservationExtensionMinutes < 0 || *r.ReservationExtensionMinutes > MaxReservationExtensionMinutes) {
+		return ErrInvalidReservationExtension
+	}
+	return nil
+}
+
+// ValidateOrderReference normalizes the order reference pattern if it is being changed
+func (r *UpdateOrderSettingsRequest) ValidateOrderReference() error {
+	if r.OrderReferencePattern == nil || strings.TrimSpace(*r.OrderReferencePattern) == "" {
+		return nil
+	}
+	pattern, err := NormalizeOrderReferencePattern(*r.OrderReferencePattern)
+	if err != nil {
+		return err
+	}
+	r.OrderReferencePattern = &pattern
+	return nil
+}
+
+// ReservationTTL returns how long checkout stock is held for QRIS and e-wallet orders
+// It is never shorter than the payment expiry, so an order that can still be paid keeps its stock.
+func (s *OrderSettings) ReservationTTL() time.Duration {
+	minutes := s.ReservationTTLMinutes
+	if minutes <= 0 {
+		minutes = DefaultReservationTTLMinutes
+	}
+	ttl := time.Duration(minutes) * time.Minute
+	if expiry := s.PaymentExpiry(); expiry > ttl {
+		return expiry
+	}
+	return ttl
+}
+
+// ReservationExtension returns how much time one guest extension adds; 0 means extensions are off
+func (s *OrderSettings) ReservationExtension() time.Duration {
+	return time.Duration(s.ReservationExtensionMinutes) * time.Minute
+}
+
+// PaymentExpiry returns how long a QRIS or e-wallet charge stays payable
+func (s *OrderSettings) PaymentExpiry() time.Duration {
+	minutes := s.PaymentExpiryMinutes
+	if minutes <= 0 {
+		minutes = DefaultPaymentExpiryMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ChargeLines returns the service charge and tax for a discounted item subtotal
+// Tax is charged on the subtotal plus service charge; both are rounded to whole rupiah
+func (s *OrderSettings) ChargeLines(subtotal int) (serviceCharge, tax int) {
+	if subtotal <= 0 {
+		return 0, 0
+	}
+	serviceCharge = int(math.Round(float64(subtotal) * s.ServiceChargePercent / 100))
+	tax = int(math.Round(float64(subtotal+serviceCharge) * s.TaxPercent / 100))
+	return serviceCharge, tax
+}
+
+// AutoCompleteCutoff returns the paid_at cutoff for auto-completion at the given time
+// Orders paid before the cutoff are due. ok is false when auto-completion is disabled.
+func (s *OrderSettings) AutoCompleteCutoff(now time.Time) (cutoff time.Time, ok bool) {
+	switch s.AutoCompleteMode {
+	case AutoCompleteAfterPaid:
+		hours := s.AutoCompleteAfterHours
+		if hours <= 0 {
+			hours = 4
+		}
+		return now.Add(-time.Duration(hours) * time.Hour), true
+
+	case AutoCompleteEndOfDay:
+		local := now.In(s.Location())
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location()), true
+	}
+	return time.Time{}, false
 }
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// SLAStage is where a paid order is on its way through the kitchen
+type SLAStage string
+
+const (
+	SLAStageWaiting   SLAStage = "waiting"   // Paid, no item started yet
+	SLAStagePreparing SLAStage = "preparing" // Some item started, not all ready
+	SLAStageReady     SLAStage = "ready"     // Every item that can be made is ready
+)
+
+// SLAStatus compares the time an order spent in a stage with the tenant's target
+type SLAStatus string
+
+const (
+	SLAStatusOnTrack SLAStatus = "on_track"
+	SLAStatusAtRisk  SLAStatus = "at_risk" // Past sla_at_risk_percent of the target
+	SLAStatusLate    SLAStatus = "late"
+)
+
+// Bounds and default for SLA targets
+const (
+	MaxSLATargetMinutes     = 240
+	DefaultSLAAtRiskPercent = 80
+)
+
+var (
+	ErrInvalidSLATarget        = errors.New("sla_preparing_minutes and sla_ready_minutes must be between 0 and 240")
+	ErrInvalidSLAAtRiskPercent = errors.New("sla_at_risk_percent must be between 1 and 99")
+)
+
+// ValidateSLA checks the SLA targets that are being changed
+func (r *UpdateOrderSettingsRequest) ValidateSLA() error {
+	for _, minutes := range []*int{r.SLAPreparingMinutes, r.SLAReadyMinutes} {
+		if minutes != nil && (*minutes < 0 || *minutes > MaxSLATargetMinutes) {
+			return ErrInvalidSLATarget
+		}
+	}
+	if r.SLAAtRiskPercent != nil && (*r.SLAAtRiskPercent < 1 || *r.SLAAtRiskPercent > 99) {
+		return ErrInvalidSLAAtRiskPercent
+	}
+	return nil
+}
+
+// SLAEnabled reports whether the tenant has an SLA target for any stage
+func (s *OrderSettings) SLAEnabled() bool {
+	return s.SLAPreparingMinutes > 0 || s.SLAReadyMinutes > 0
+}
+
+// SLATarget returns the tenant's target for a stage; 0 means the stage is not timed
+// Waiting orders should be started within sla_preparing_minutes of payment,
+// and preparing orders be ready within sla_ready_minutes of being started.
+func (s *OrderSettings) SLATarget(stage SLAStage) time.Duration {
+	switch stage {
+	case SLAStageWaiting:
+		return time.Duration(s.SLAPreparingMinutes) * time.Minute
+	case SLAStagePreparing:
+		return time.Duration(s.SLAReadyMinutes) * time.Minute
+	}
+	return 0
+}
+
+// slaStatus compares elapsed time with a target, given the share of it that counts as at risk
+func slaStatus(elapsed, target time.Duration, atRiskPercent int) SLAStatus {
+	if target <= 0 {
+		return SLAStatusOnTrack
+	}
+	if atRiskPercent <= 0 {
+		atRiskPercent = DefaultSLAAtRiskPercent
+	}
+	switch {
+	case elapsed > target:
+		return SLAStatusLate
+	case elapsed >= target*time.Duration(atRiskPercent)/100:
+		return SLAStatusAtRisk
+	}
+	return SLAStatusOnTrack
+}
+
+// OrderTiming is when a paid order reached each kitchen stage
+type OrderTiming struct {
+	PaidAt        time.Time
+	PrepStartedAt *time.Time // First item started, or made ready without being started
+	ReadyAt       *time.Time // Last item made ready, once every item that can be made is
+}
+
+// OrderItemsTiming derives an order's stage times from its items
+// Out-of-stock items are skipped; the order is ready once every other item is.
+func OrderItemsTiming(paidAt time.Time, items []OrderItem) OrderTiming {
+	timing := OrderTiming{PaidAt: paidAt}
+	var lastReady *time.Time
+	fulfillable, ready := 0, 0
+	for _, item := range items {
+		if item.PrepStatus == PrepStatusOutOfStock {
+			continue
+		}
+		fulfillable++
+		for _, started := range []*time.Time{item.PrepStartedAt, item.PrepReadyAt} {
+			if started != nil && (timing.PrepStartedAt == nil || started.Before(*timing.PrepStartedAt)) {
+				timing.PrepStartedAt = started
+			}
+		}
+		if item.PrepStatus == PrepStatusReady && item.PrepReadyAt != nil {
+			ready++
+			if lastReady == nil || item.PrepReadyAt.After(*lastReady) {
+				lastReady = item.PrepReadyAt
+			}
+		}
+	}
+	if fulfillable > 0 && ready == fulfillable {
+		timing.ReadyAt = lastReady
+	}
+	return timing
+}
+
+// OrderSLA is a paid order's progress against the tenant's SLA targets
+// Status is the current stage's; a ready order is late if any stage ran over.
+type OrderSLA struct {
+	Stage          SLAStage  `json:"stage"`
+	StageStartedAt time.Time `json:"stage_started_at"`
+	Status         SLAStatus `json:"status"`
+	WaitSeconds    int64     `json:"wait_seconds"`           // Payment to first item started, so far
+	PrepSeconds    *int64    `json:"prep_seconds,omitempty"` // First item started to ready, so far
+	TargetSeconds  int64     `json:"target_seconds"`         // For the current stage; 0 when it is not timed
+}
+
+// EvaluateSLA places an order on its SLA timeline at the given time
+func (s *OrderSettings) EvaluateSLA(timing OrderTiming, now time.Time) OrderSLA {
+	waitEnd := now
+	if timing.PrepStartedAt != nil {
+		waitEnd = *timing.PrepStartedAt
+	}
+	wait := waitEnd.Sub(timing.PaidAt)
+	sla := OrderSLA{
+		Stage:          SLAStageWaiting,
+		StageStartedAt: timing.PaidAt,
+		WaitSeconds:    int64(wait.Seconds()),
+	}
+	waitStatus := slaStatus(wait, s.SLATarget(SLAStageWaiting), s.SLAAtRiskPercent)
+	if timing.PrepStartedAt == nil {
+		sla.Status = waitStatus
+		sla.TargetSeconds = int64(s.SLATarget(SLAStageWaiting).Seconds())
+		return sla
+	}
+
+	prepEnd := now
+	if timing.ReadyAt != nil {
+		prepEnd = *timing.ReadyAt
+	}
+	prep := prepEnd.Sub(*timing.PrepStartedAt)
+	prepSeconds := int64(prep.Seconds())
+	sla.PrepSeconds = &prepSeconds
+	prepStatus := slaStatus(prep, s.SLATarget(SLAStagePreparing), s.SLAAtRiskPercent)
+
+	if timing.ReadyAt == nil {
+		sla.Stage = SLAStagePreparing
+		sla.StageStartedAt = *timing.PrepStartedAt
+		sla.Status = prepStatus
+		sla.TargetSeconds = int64(s.SLATarget(SLAStagePreparing).Seconds())
+		return sla
+	}
+
+	sla.Stage = SLAStageReady
+	sla.StageStartedAt = *timing.ReadyAt
+	sla.Status = SLAStatusOnTrack
+	if waitStatus == SLAStatusLate || prepStatus == SLAStatusLate {
+		sla.Status = SLAStatusLate
+	}
+	return sla
+}
+
+// OrderSLAFor evaluates the SLA of an order that is paid and waiting on the kitchen
+// ok is false for other orders, scheduled orders (timed by their slot, see IsLate)
+// and tenants without SLA targets.
+func (s *OrderSettings) OrderSLAFor(order *GuestOrder, items []OrderItem, now time.Time) (sla OrderSLA, ok bool) {
+	if !s.SLAEnabled() || order.Status != OrderStatusPaid || order.PaidAt == nil || order.ScheduledFor != nil {
+		return OrderSLA{}, false
+	}
+	return s.EvaluateSLA(OrderItemsTiming(*order.PaidAt, items), now), true
+}
+
+// OrderSLABreach is a late stage of an order that staff are alerted to once
+type OrderSLABreach struct {
+	TenantID       string    `json:"tenant_id"`
+	OrderID        string    `json:"order_id"`
+	OrderReference string    `json:"order_reference"`
+	DeliveryType   string    `json:"delivery_type"`
+	TableNumber    *string   `json:"table_number,omitempty"`
+	Stage          SLAStage  `json:"stage"`
+	StageStartedAt time.Time `json:"stage_started_at"`
+	ElapsedSeconds int64     `json:"elapsed_seconds"`
+	TargetSeconds  int64     `json:"target_seconds"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+)
+
+// MaxBatchStatusOrders caps how many orders one batch status update may transition
+const MaxBatchStatusOrders = 100
+
+var (
+	ErrBatchStatusEmpty    = errors.New("order_ids must list at least one order")
+	ErrBatchStatusTooLarge = errors.New("order_ids must list at most 100 orders")
+	ErrBatchStatusInvalid  = errors.New("status must be PENDING, PAID, COMPLETE or CANCELLED")
+)
+
+// BatchOrderStatusRequest moves many orders to the same status at once
+type BatchOrderStatusRequest struct {
+	OrderIDs []string    `json:"order_ids"`
+	Status   OrderStatus `json:"status"`
+}
+
+// Validate checks the batch size and target status; duplicate order IDs are dropped
+func (r *BatchOrderStatusRequest) Validate() error {
+	switch r.Status {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusComplete, OrderStatusCancelled:
+	default:
+		return ErrBatchStatusInvalid
+	}
+
+	seen := make(map[string]bool, len(r.OrderIDs))
+	orderIDs := make([]string, 0, len(r.OrderIDs))
+	for _, id := range r.OrderIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		orderIDs = append(orderIDs, id)
+	}
+	r.OrderIDs = orderIDs
+
+	if len(r.OrderIDs) == 0 {
+		return ErrBatchStatusEmpty
+	}
+	if len(r.OrderIDs) > MaxBatchStatusOrders {
+		return ErrBatchStatusTooLarge
+	}
+	return nil
+}
+
+// BatchOrderStatusResult is the outcome of one order in a batch status update
+// Orders already in the target status succeed without being changed.
+type BatchOrderStatusResult struct {
+	OrderID        string      `json:"order_id"`
+	OrderReference string      `json:"order_reference,omitempty"`
+	PreviousStatus OrderStatus `json:"previous_status,omitempty"`
+	Success        bool        `json:"success"`
+	Unchanged      bool        `json:"unchanged,omitempty"`
+	Error          string      `json:"error,omitempty"`
+}
+
+// BatchOrderStatusResponse reports a batch status update order by order
+type BatchOrderStatusResponse struct {
+	Status  OrderStatus              `json:"status"`
+	Updated int                      `json:"updated"`
+	Failed  int                      `json:"failed"`
+	Results []BatchOrderStatusResult `json:"results"`
+}
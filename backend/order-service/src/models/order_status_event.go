@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// OrderStatusEventType names an order status stream event
+type OrderStatusEventType string
+
+const (
+	OrderStatusEventSnapshot OrderStatusEventType = "snapshot" // Current state, sent when a stream opens
+	OrderStatusEventStatus   OrderStatusEventType = "status"   // The order moved to a new status
+	OrderStatusEventPayment  OrderStatusEventType = "payment"  // The payment gateway reported a new transaction status
+)
+
+// OrderStatusEvent is a change pushed to guests following an order
+// It carries no customer details; anyone holding the order reference may receive it.
+type OrderStatusEvent struct {
+	Type           OrderStatusEventType `json:"type"`
+	OrderReference string               `json:"order_reference"`
+	Status         OrderStatus          `json:"status"`
+	PaymentStatus  *string              `json:"payment_status,omitempty"` // Gateway transaction status, e.g. pending, settlement, expire
+	OccurredAt     time.Time            `json:"occurred_at"`
+}
+
+// IsFinal reports whether no further events will follow for the order
+func (e *OrderStatusEvent) IsFinal() bool {
+	return e.Status == OrderStatusComplete || e.Status == OrderStatusCancelled
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// PaymentOutagePolicy selects what checkout does when the payment gateway is unreachable
+type PaymentOutagePolicy string
+
+const (
+	PaymentOutageReject       PaymentOutagePolicy = "reject"         // checkout fails and the guest retries later
+	PaymentOutagePayAtCounter PaymentOutagePolicy = "pay_at_counter" // the order is placed unpaid and staff collect payment
+)
+
+// PaymentFallbackUnpaidOffline flags an order placed during a gateway outage
+// Staff collect payment at the counter (or on delivery) with the in-store payment flow.
+const PaymentFallbackUnpaidOffline = "UNPAID_OFFLINE"
+
+var ErrInvalidPaymentOutagePolicy = errors.New("payment_outage_policy must be one of: reject, pay_at_counter")
+
+// IsValid checks if the policy is supported
+func (p PaymentOutagePolicy) IsValid() bool {
+	switch p {
+	case PaymentOutageReject, PaymentOutagePayAtCounter:
+		return true
+	}
+	return false
+}
+
+// ValidatePaymentOutage checks the payment outage policy if it is being changed
+func (r *UpdateOrderSettingsRequest) ValidatePaymentOutage() error {
+	if r.PaymentOutagePolicy != nil && !r.PaymentOutagePolicy.IsValid() {
+		return ErrInvalidPaymentOutagePolicy
+	}
+	return nil
+}
+
+// Charge retry backoff: the first retry waits ChargeRetryBaseDelay and each
+// following one doubles, up to ChargeRetryMaxDelay
+const (
+	ChargeRetryBaseDelay = 1 * time.Minute
+	ChargeRetryMaxDelay  = 15 * time.Minute
+)
+
+// PaymentChargeRetry is an online charge queued while the gateway was unreachable
+type PaymentChargeRetry struct {
+	OrderID       string                `json:"order_id"`
+	TenantID      string                `json:"tenant_id"`
+	PaymentMethod CheckoutPaymentMethod `json:"payment_method"`
+	Attempts      int                   `json:"attempts"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+	ExpiresAt     time.Time             `json:"expires_at"` // Retries stop with the order's stock reservation
+	LastError     *string               `json:"last_error,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+// QueuesChargeRetry reports whether a charge for the method is retried after an outage
+// Only QRIS is retried: the code can be shown on the order page or at the counter, while
+// VA numbers, GoPay deeplinks and card pages would reach the guest too late to be useful.
+func QueuesChargeRetry(method CheckoutPaymentMethod) bool {
+	return method == CheckoutPaymentQRIS
+}
+
+// ChargeRetryDelay returns how long to wait before the next attempt after the given number of failures
+func ChargeRetryDelay(attempts int) time.Duration {
+	delay := ChargeRetryBaseDelay
+	for i := 1; i < attempts && delay < ChargeRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > ChargeRetryMaxDelay {
+		return ChargeRetryMaxDelay
+	}
+	return delay
+}
+
+// chargeRetryRevisionBase numbers retried charges apart from order edit revisions, which
+// count up from 1, so neither reuses a gateway order ID the other may already have created
+const chargeRetryRevisionBase = 100
+
+// ChargeRetryOrderID builds the gateway order ID for a retried charge
+// Each attempt gets its own ID: an attempt that timed out may still have created the charge.
+func ChargeRetryOrderID(orderReference string, attempt int) string {
+	return RevisedChargeOrderID(orderReference, chargeRetryRevisionBase+attempt)
+}
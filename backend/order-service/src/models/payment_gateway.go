@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// PaymentGatewayProvider identifies the online payment gateway that created a charge
+type PaymentGatewayProvider string
+
+const (
+	PaymentGatewayMidtrans PaymentGatewayProvider = "midtrans"
+	PaymentGatewayXendit   PaymentGatewayProvider = "xendit"
+)
+
+var (
+	ErrUnsupportedPaymentGateway        = errors.New("unsupported payment gateway")
+	ErrPaymentMethodNotOfferedByGateway = errors.New("payment method is not offered by the tenant's payment gateway")
+	ErrRefundNotSupported               = errors.New("refunds are not supported for this payment method")
+	ErrNoOnlinePayment                  = errors.New("order has no online payment")
+	ErrPaymentNotSettled                = errors.New("payment has not been settled")
+	ErrPaymentNotCancellable            = errors.New("payment can no longer be cancelled")
+	ErrPaymentGatewayUnavailable        = errors.New("payment gateway is unavailable")
+)
+
+// IsGatewayOutageStatus reports whether a gateway HTTP status means the gateway itself is
+// failing rather than rejecting the request; 0 means it could not be reached at all
+func IsGatewayOutageStatus(statusCode int) bool {
+	switch {
+	case statusCode == 0, statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooManyRequests:
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// IsValid checks if the payment gateway is supported
+func (p PaymentGatewayProvider) IsValid() bool {
+	switch p {
+	case PaymentGatewayMidtrans, PaymentGatewayXendit:
+		return true
+	}
+	return false
+}
+
+// XenditTransactionStatus translates a Xendit status into Midtrans' status vocabulary
+// Webhooks from every gateway are normalized this way so MapMidtransStatus and the
+// order state machine stay gateway-agnostic. Unknown statuses map to "" (ignored).
+func XenditTransactionStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "SUCCEEDED", "COMPLETED", "PAID", "SETTLED":
+		return "settlement"
+	case "PENDING", "ACTIVE":
+		return "pending"
+	case "EXPIRED":
+		return "expire"
+	case "FAILED":
+		return "failure"
+	default:
+		return ""
+	}
+}
@@ -29,6 +29,11 @@ type PaymentRecord struct {
 	RecordedByUserID      string        `json:"recorded_by_user_id"`         // Staff who recorded the payment
 	Notes                 *string       `json:"notes,omitempty"`
 	ReceiptNumber         *string       `json:"receipt_number,omitempty"`
+	AmountTendered        *int          `json:"amount_tendered,omitempty"`   // Cash handed over by the customer (cash only)
+	ChangeAmount          *int          `json:"change_amount,omitempty"`     // Change returned to the customer (cash only)
+	CardType              *CardType     `json:"card_type,omitempty"`         // Card network from the EDC terminal (card only)
+	ApprovalCode          *string       `json:"approval_code,omitempty"`     // EDC authorization code (card only)
+	CardLast4             *string       `json:"card_last4,omitempty"`        // Last four digits of the card (card only)
 	CreatedAt             time.Time     `json:"created_at"`
 }
 
@@ -37,8 +42,57 @@ var (
 	ErrInvalidPaymentAmount = errors.New("payment amount must be greater than 0")
 	ErrInvalidPaymentMethod = errors.New("invalid payment method")
 	ErrNegativeBalance      = errors.New("remaining balance cannot be negative")
+	ErrInsufficientTender   = errors.New("amount tendered is less than the amount due")
+	ErrInvalidCardType      = errors.New("invalid card type")
+	ErrInvalidApprovalCode  = errors.New("approval code must be 1-20 alphanumeric characters")
+	ErrInvalidCardLast4     = errors.New("card_last4 must be exactly 4 digits")
 )
 
+// CardType represents the card network of a payment taken on an EDC terminal
+type CardType string
+
+const (
+	CardTypeVisa       CardType = "visa"
+	CardTypeMastercard CardType = "mastercard"
+	CardTypeJCB        CardType = "jcb"
+	CardTypeAmex       CardType = "amex"
+	CardTypeGPN        CardType = "gpn" // Gerbang Pembayaran Nasional debit cards
+	CardTypeOther      CardType = "other"
+)
+
+// IsValid checks if the card type is one of the supported networks
+func (ct CardType) IsValid() bool {
+	switch ct {
+	case CardTypeVisa, CardTypeMastercard, CardTypeJCB, CardTypeAmex, CardTypeGPN, CardTypeOther:
+		return true
+	}
+	return false
+}
+
+// ValidateCardDetails checks the details copied from an EDC slip
+func ValidateCardDetails(cardType CardType, approvalCode string, last4 string) error {
+	if !cardType.IsValid() {
+		return ErrInvalidCardType
+	}
+	if len(approvalCode) == 0 || len(approvalCode) > 20 {
+		return ErrInvalidApprovalCode
+	}
+	for _, r := range approvalCode {
+		if !(r >= '0' && r <= '9') && !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z') {
+			return ErrInvalidApprovalCode
+		}
+	}
+	if len(last4) != 4 {
+		return ErrInvalidCardLast4
+	}
+	for _, r := range last4 {
+		if r < '0' || r > '9' {
+			return ErrInvalidCardLast4
+		}
+	}
+	return nil
+}
+
 // Scan implements sql.Scanner for PaymentMethod
 func (pm *PaymentMethod) Scan(value interface{}) error {
 	if value == nil {
@@ -60,6 +114,11 @@ type CreatePaymentRecordRequest struct {
 	RecordedByUserID      string        `json:"recorded_by_user_id" validate:"required,uuid"`
 	Notes                 *string       `json:"notes,omitempty" validate:"omitempty,max=1000"`
 	ReceiptNumber         *string       `json:"receipt_number,omitempty" validate:"omitempty,max=100"`
+	AmountTendered        *int          `json:"amount_tendered,omitempty" validate:"omitempty,min=1"`
+	ChangeAmount          *int          `json:"change_amount,omitempty" validate:"omitempty,min=0"`
+	CardType              *CardType     `json:"card_type,omitempty" validate:"omitempty,oneof=visa mastercard jcb amex gpn other"`
+	ApprovalCode          *string       `json:"approval_code,omitempty" validate:"omitempty,max=20"`
+	CardLast4             *string       `json:"card_last4,omitempty" validate:"omitempty,len=4,numeric"`
 }
 
 // PaymentRecordResponse represents a payment record with additional context
@@ -2,29 +2,133 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 )
 
-// PaymentTransaction represents a Midtrans payment transaction
+// CheckoutPaymentMethod represents the Midtrans payment method chosen at checkout
+type CheckoutPaymentMethod string
+
+const (
+	CheckoutPaymentQRIS         CheckoutPaymentMethod = "qris"
+	CheckoutPaymentGoPay        CheckoutPaymentMethod = "gopay"
+	CheckoutPaymentBankTransfer CheckoutPaymentMethod = "bank_transfer"
+	CheckoutPaymentCreditCard   CheckoutPaymentMethod = "credit_card"
+)
+
+// Banks supported for virtual account bank transfers
+var SupportedVABanks = []string{"bca", "bni", "bri", "permata"}
+
+var (
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+	ErrUnsupportedBank          = errors.New("unsupported bank for virtual account")
+)
+
+// IsValid checks if the checkout payment method is supported
+func (m CheckoutPaymentMethod) IsValid() bool {
+	switch m {
+	case CheckoutPaymentQRIS, CheckoutPaymentGoPay, CheckoutPaymentBankTransfer, CheckoutPaymentCreditCard:
+		return true
+	}
+	return false
+}
+
+// IsSupportedVABank checks if a bank can issue virtual accounts
+func IsSupportedVABank(bank string) bool {
+	for _, b := range SupportedVABanks {
+		if b == bank {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCheckoutPayment checks the payment method selector sent at checkout
+func ValidateCheckoutPayment(method CheckoutPaymentMethod, bank string) error {
+	if !method.IsValid() {
+		return ErrUnsupportedPaymentMethod
+	}
+	if method == CheckoutPaymentBankTransfer && !IsSupportedVABank(bank) {
+		return ErrUnsupportedBank
+	}
+	return nil
+}
+
+// PaymentOutcome is what a Midtrans notification means for the order
+type PaymentOutcome string
+
+const (
+	PaymentOutcomeSuccess PaymentOutcome = "success"
+	PaymentOutcomePending PaymentOutcome = "pending"
+	PaymentOutcomeFailed  PaymentOutcome = "failed"
+	PaymentOutcomeIgnored PaymentOutcome = "ignored"
+)
+
+// MapMidtransStatus maps a Midtrans notification to a payment outcome
+// Card payments settle on "capture" only when fraud screening accepts them;
+// a "challenge" waits for manual review. Other methods settle on "settlement".
+func MapMidtransStatus(paymentType, transactionStatus, fraudStatus string) PaymentOutcome {
+	status := strings.ToLower(transactionStatus)
+	fraud := strings.ToLower(fraudStatus)
+
+	switch status {
+	case "capture":
+		if strings.ToLower(paymentType) != "credit_card" {
+			return PaymentOutcomeSuccess
+		}
+		switch fraud {
+		case "accept", "":
+			return PaymentOutcomeSuccess
+		case "challenge":
+			return PaymentOutcomePending
+		default:
+			return PaymentOutcomeFailed
+		}
+	case "settlement":
+		if fraud == "deny" {
+			return PaymentOutcomeFailed
+		}
+		return PaymentOutcomeSuccess
+	case "pending", "authorize":
+		return PaymentOutcomePending
+	case "cancel", "deny", "expire", "failure":
+		return PaymentOutcomeFailed
+	default:
+		return PaymentOutcomeIgnored
+	}
+}
+
+// PaymentTransaction represents an online payment charge
+// The midtrans_* columns predate gateway selection; for Xendit charges they hold
+// the Xendit object ID and the reference/external ID respectively.
 type PaymentTransaction struct {
-	ID                     string          `json:"id"`
-	OrderID                string          `json:"order_id"`
-	MidtransTransactionID  *string         `json:"midtrans_transaction_id,omitempty"`
-	MidtransOrderID        string          `json:"midtrans_order_id"`
-	Amount                 int             `json:"amount"`
-	PaymentType            *string         `json:"payment_type,omitempty"`
-	TransactionStatus      *string         `json:"transaction_status,omitempty"`
-	FraudStatus            *string         `json:"fraud_status,omitempty"`
-	NotificationPayload    json.RawMessage `json:"notification_payload,omitempty"`
-	SignatureKey           *string         `json:"signature_key,omitempty"`
-	SignatureVerified      bool            `json:"signature_verified"`
-	QRCodeURL              *string         `json:"qr_code_url,omitempty"` // URL to QR code image
-	QRString               *string         `json:"qr_string,omitempty"`   // Raw QRIS string
-	ExpiryTime             *time.Time      `json:"expiry_time,omitempty"` // Payment expiration time
-	CreatedAt              time.Time       `json:"created_at"`
-	NotificationReceivedAt *time.Time      `json:"notification_received_at,omitempty"`
-	SettledAt              *time.Time      `json:"settled_at,omitempty"`
-	IdempotencyKey         *string         `json:"idempotency_key,omitempty"`
+	ID                     string                 `json:"id"`
+	OrderID                string                 `json:"order_id"`
+	MidtransTransactionID  *string                `json:"midtrans_transaction_id,omitempty"`
+	MidtransOrderID        string                 `json:"midtrans_order_id"`
+	Amount                 int                    `json:"amount"`
+	PaymentMethod          CheckoutPaymentMethod  `json:"payment_method"`
+	PaymentType            *string                `json:"payment_type,omitempty"`
+	TransactionStatus      *string                `json:"transaction_status,omitempty"`
+	FraudStatus            *string                `json:"fraud_status,omitempty"`
+	NotificationPayload    json.RawMessage        `json:"notification_payload,omitempty"`
+	SignatureKey           *string                `json:"signature_key,omitempty"`
+	SignatureVerified      bool                   `json:"signature_verified"`
+	QRCodeURL              *string                `json:"qr_code_url,omitempty"`  // URL to QR code image
+	QRString               *string                `json:"qr_string,omitempty"`    // Raw QRIS string
+	Bank                   *string                `json:"bank,omitempty"`         // Virtual account bank
+	VANumber               *string                `json:"va_number,omitempty"`    // Virtual account number
+	DeeplinkURL            *string                `json:"deeplink_url,omitempty"` // GoPay app deeplink
+	RedirectURL            *string                `json:"redirect_url,omitempty"` // Snap payment page
+	SnapToken              *string                `json:"snap_token,omitempty"`   // Snap popup token
+	IsSplitPart            bool                   `json:"is_split_part"`          // Charge covers only part of the order total
+	Gateway                PaymentGatewayProvider `json:"gateway"`                // Gateway that created the charge
+	ExpiryTime             *time.Time             `json:"expiry_time,omitempty"`  // Payment expiration time
+	CreatedAt              time.Time              `json:"created_at"`
+	NotificationReceivedAt *time.Time             `json:"notification_received_at,omitempty"`
+	SettledAt              *time.Time             `json:"settled_at,omitempty"`
+	IdempotencyKey         *string                `json:"idempotency_key,omitempty"`
 }
 
 // GenerateIdempotencyKey creates a unique key for webhook deduplication
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Usage metrics limited by a tenant's plan; monthly ones are counted from the start of the billing period
+const (
+	UsageMonthlyOrders        = "monthly_orders"
+	UsageStorageBytes         = "storage_bytes"
+	UsageMonthlyNotifications = "monthly_notifications"
+	UsageStaffSeats           = "staff_seats"
+	UsageProducts             = "products"
+	UsageOutlets              = "outlets"
+)
+
+// usageLimitLabels describe each metric's limit in plan limit messages
+var usageLimitLabels = map[string]string{
+	UsageMonthlyOrders:        "orders per month",
+	UsageStorageBytes:         "bytes of photo storage",
+	UsageMonthlyNotifications: "notifications per month",
+	UsageStaffSeats:           "staff seats",
+	UsageProducts:             "products",
+	UsageOutlets:              "outlets",
+}
+
+// UsageMetric is how much of one plan limit a tenant has used; a nil limit is unlimited
+type UsageMetric struct {
+	Used  int64  `json:"used"`
+	Limit *int64 `json:"limit"`
+}
+
+// PlanUsage is a tenant's usage against their plan for the current billing period
+type PlanUsage struct {
+	PlanCode    string                  `json:"plan_code"`
+	PlanName    string                  `json:"plan_name"`
+	PeriodStart time.Time               `json:"period_start"`
+	PeriodEnd   time.Time               `json:"period_end"`
+	Usage       map[string]*UsageMetric `json:"usage"`
+}
+
+// PlanLimitError is returned when an action would go over a limit of the tenant's plan
+type PlanLimitError struct {
+	Limit       string // Usage metric, e.g. UsageMonthlyOrders
+	Max         int64
+	PlanName    string
+	UpgradePlan string // Cheapest plan with room for more; empty when there is none
+}
+
+func (e *PlanLimitError) Error() string {
+	return fmt.Sprintf("the %s plan allows %d %s", e.PlanName, e.Max, usageLimitLabels[e.Limit])
+}
+
+// UpgradeHint tells the owner how to lift the limit
+func (e *PlanLimitError) UpgradeHint() string {
+	if e.UpgradePlan == "" {
+		return "Contact support to raise this limit"
+	}
+	return fmt.Sprintf("Upgrade to the %s plan to raise this limit", e.UpgradePlan)
+}
+
+// Response is the body returned with 403 when a plan limit is reached
+func (e *PlanLimitError) Response() map[string]interface{} {
+	return map[string]interface{}{
+		"error":        e.Error(),
+		"code":         "plan_limit_exceeded",
+		"limit":        e.Limit,
+		"max":          e.Max,
+		"upgrade_plan": e.UpgradePlan,
+		"upgrade_hint": e.UpgradeHint(),
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PrintJobKind is what a print job prints
+type PrintJobKind string
+
+const (
+	PrintJobReceipt       PrintJobKind = "receipt"        // Customer receipt with prices and totals
+	PrintJobKitchenTicket PrintJobKind = "kitchen_ticket" // Items and table for the kitchen, no prices
+)
+
+// PrintJobStatus is where a print job is in the queue
+type PrintJobStatus string
+
+const (
+	PrintJobPending  PrintJobStatus = "pending"  // Waiting for a print agent
+	PrintJobPrinting PrintJobStatus = "printing" // Claimed by a print agent, not yet acknowledged
+	PrintJobPrinted  PrintJobStatus = "printed"
+	PrintJobFailed   PrintJobStatus = "failed" // Gave up after MaxPrintAttempts
+)
+
+// Paper widths in characters of the printer's default font
+const (
+	PaperWidth58mm = 32
+	PaperWidth80mm = 48
+)
+
+const (
+	// MaxPrintAttempts is how often a job is handed to print agents before it fails
+	MaxPrintAttempts = 3
+	// PrintClaimTimeout is how long a claimed job waits for an acknowledgement before it is handed out again
+	PrintClaimTimeout = 2 * time.Minute
+)
+
+var (
+	ErrInvalidPrintJob    = errors.New("invalid print job")
+	ErrPrintJobNotFound   = errors.New("print job not found")
+	ErrPrintJobNotClaimed = errors.New("print job is not being printed")
+	ErrPrintJobQueued     = errors.New("print job is still waiting to be printed")
+	ErrOrderNotPrintable  = errors.New("only paid or completed orders can be printed")
+)
+
+// IsValid reports whether k is a known print job kind
+func (k PrintJobKind) IsValid() bool {
+	switch k {
+	case PrintJobReceipt, PrintJobKitchenTicket:
+		return true
+	}
+	return false
+}
+
+// IsValid reports whether s is a known print job status
+func (s PrintJobStatus) IsValid() bool {
+	switch s {
+	case PrintJobPending, PrintJobPrinting, PrintJobPrinted, PrintJobFailed:
+		return true
+	}
+	return false
+}
+
+// PrintJob is a rendered ESC/POS document waiting for, or handled by, a tenant's print agent
+type PrintJob struct {
+	ID             string         `json:"id"`
+	TenantID       string         `json:"tenant_id"`
+	OrderID        string         `json:"order_id"`
+	OrderReference string         `json:"order_reference"`
+	Kind           PrintJobKind   `json:"kind"`
+	Status         PrintJobStatus `json:"status"`
+	PaperWidth     int            `json:"paper_width"`       // Characters per line
+	Payload        []byte         `json:"payload,omitempty"` // ESC/POS bytes, base64 in JSON; only sent to the agent that claims the job
+	Attempts       int            `json:"attempts"`
+	LastError      *string        `json:"last_error,omitempty"`
+	ClaimedAt      *time.Time     `json:"claimed_at,omitempty"`
+	PrintedAt      *time.Time     `json:"printed_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// CreatePrintJobsRequest queues an order's receipt and/or kitchen ticket
+type CreatePrintJobsRequest struct {
+	Kinds      []PrintJobKind `json:"kinds"`                 // Defaults to both
+	PaperWidth int            `json:"paper_width,omitempty"` // 32 (58mm) or 48 (80mm); defaults to 48
+}
+
+// Validate fills in defaults and checks the requested kinds and paper width
+func (r *CreatePrintJobsRequest) Validate() error {
+	if len(r.Kinds) == 0 {
+		r.Kinds = []PrintJobKind{PrintJobKitchenTicket, PrintJobReceipt}
+	}
+	for _, kind := range r.Kinds {
+		if !kind.IsValid() {
+			return fmt.Errorf("%w: kind must be receipt or kitchen_ticket", ErrInvalidPrintJob)
+		}
+	}
+
+	if r.PaperWidth == 0 {
+		r.PaperWidth = PaperWidth80mm
+	}
+	if r.PaperWidth != PaperWidth58mm && r.PaperWidth != PaperWidth80mm {
+		return fmt.Errorf("%w: paper_width must be 32 or 48", ErrInvalidPrintJob)
+	}
+	return nil
+}
+
+// AckPrintJobRequest reports the outcome of printing a claimed job
+type AckPrintJobRequest struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"` // Printer error when not successful
+}
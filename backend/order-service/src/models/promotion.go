@@ -0,0 +1,244 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PromotionType is how a promotion reduces the price of the items it targets
+type PromotionType string
+
+const (
+	PromotionPercentOff PromotionType = "percent_off"
+	PromotionFixedOff   PromotionType = "fixed_off"
+	PromotionBOGO       PromotionType = "bogo"
+)
+
+// PromotionScope selects the cart lines a promotion applies to
+type PromotionScope string
+
+const (
+	PromotionScopeOrder    PromotionScope = "order"
+	PromotionScopeCategory PromotionScope = "category"
+	PromotionScopeProduct  PromotionScope = "product"
+)
+
+var (
+	ErrInvalidPromotion  = errors.New("invalid promotion")
+	ErrPromotionNotFound = errors.New("promotion not found")
+)
+
+var promotionTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// Promotion is a tenant rule applied automatically while pricing a cart
+type Promotion struct {
+	ID                string         `json:"id"`
+	TenantID          string         `json:"tenant_id"`
+	Name              string         `json:"name"`
+	Description       *string        `json:"description,omitempty"`
+	PromotionType     PromotionType  `json:"promotion_type"`
+	Scope             PromotionScope `json:"scope"`
+	CategoryID        *string        `json:"category_id,omitempty"`
+	ProductID         *string        `json:"product_id,omitempty"`
+	DiscountValue     int            `json:"discount_value"`                // Percent for percent_off and bogo, IDR for fixed_off
+	MaxDiscountAmount *int           `json:"max_discount_amount,omitempty"` // Cap for percent_off
+	BuyQuantity       *int           `json:"buy_quantity,omitempty"`        // BOGO only
+	GetQuantity       *int           `json:"get_quantity,omitempty"`        // BOGO only
+	MinSubtotal       int            `json:"min_subtotal"`
+	DaysOfWeek        []int          `json:"days_of_week,omitempty"` // 0 = Sunday; empty = every day
+	StartTime         *string        `json:"start_time,omitempty"`   // Local HH:MM
+	EndTime           *string        `json:"end_time,omitempty"`     // Local HH:MM, exclusive
+	Timezone          string         `json:"timezone"`
+	ValidFrom         *time.Time     `json:"valid_from,omitempty"`
+	ValidUntil        *time.Time     `json:"valid_until,omitempty"`
+	Stackable         bool           `json:"stackable"`
+	Priority          int            `json:"priority"`
+	IsActive          bool           `json:"is_active"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// PromotionRequest creates a promotion or replaces its settings
+type PromotionRequest struct {
+	Name              string         `json:"name"`
+	Description       *string        `json:"description,omitempty"`
+	PromotionType     PromotionType  `json:"promotion_type"`
+	Scope             PromotionScope `json:"scope"`
+	CategoryID        *string        `json:"category_id,omitempty"`
+	ProductID         *string        `json:"product_id,omitempty"`
+	DiscountValue     int            `json:"discount_value"`
+	MaxDiscountAmount *int           `json:"max_discount_amount,omitempty"`
+	BuyQuantity       *int           `json:"buy_quantity,omitempty"`
+	GetQuantity       *int           `json:"get_quantity,omitempty"`
+	MinSubtotal       int            `json:"min_subtotal"`
+	DaysOfWeek        []int          `json:"days_of_week,omitempty"`
+	StartTime         *string        `json:"start_time,omitempty"`
+	EndTime           *string        `json:"end_time,omitempty"`
+	Timezone          string         `json:"timezone,omitempty"` // Defaults to Asia/Jakarta
+	ValidFrom         *time.Time     `json:"valid_from,omitempty"`
+	ValidUntil        *time.Time     `json:"valid_until,omitempty"`
+	Stackable         *bool          `json:"stackable,omitempty"` // Defaults to true
+	Priority          int            `json:"priority"`
+	IsActive          *bool          `json:"is_active,omitempty"` // Defaults to true
+}
+
+// Validate checks the promotion rules and fills in defaults
+func (r *PromotionRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || len(r.Name) > 100 {
+		return fmt.Errorf("%w: name is required (max 100 characters)", ErrInvalidPromotion)
+	}
+
+	switch r.PromotionType {
+	case PromotionPercentOff:
+		if r.DiscountValue < 1 || r.DiscountValue > 100 {
+			return fmt.Errorf("%w: percent_off discount_value must be between 1 and 100", ErrInvalidPromotion)
+		}
+	case PromotionFixedOff:
+		if r.DiscountValue < 1 {
+			return fmt.Errorf("%w: fixed_off discount_value must be greater than 0", ErrInvalidPromotion)
+		}
+	case PromotionBOGO:
+		if r.DiscountValue == 0 {
+			r.DiscountValue = 100 // Free item unless configured otherwise
+		}
+		if r.DiscountValue < 1 || r.DiscountValue > 100 {
+			return fmt.Errorf("%w: bogo discount_value must be between 1 and 100", ErrInvalidPromotion)
+		}
+		if r.BuyQuantity == nil || *r.BuyQuantity < 1 || r.GetQuantity == nil || *r.GetQuantity < 1 {
+			return fmt.Errorf("%w: bogo requires buy_quantity and get_quantity greater than 0", ErrInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("%w: promotion_type must be percent_off, fixed_off or bogo", ErrInvalidPromotion)
+	}
+
+	if r.PromotionType != PromotionBOGO && (r.BuyQuantity != nil || r.GetQuantity != nil) {
+		return fmt.Errorf("%w: buy_quantity and get_quantity only apply to bogo promotions", ErrInvalidPromotion)
+	}
+	if r.MaxDiscountAmount != nil && (r.PromotionType != PromotionPercentOff || *r.MaxDiscountAmount < 1) {
+		return fmt.Errorf("%w: max_discount_amount must be greater than 0 and only applies to percent_off", ErrInvalidPromotion)
+	}
+
+	if r.Scope == "" {
+		r.Scope = PromotionScopeOrder
+	}
+	switch r.Scope {
+	case PromotionScopeOrder:
+		if r.CategoryID != nil || r.ProductID != nil {
+			return fmt.Errorf("%w: order promotions cannot target a category or product", ErrInvalidPromotion)
+		}
+	case PromotionScopeCategory:
+		if r.CategoryID == nil || *r.CategoryID == "" || r.ProductID != nil {
+			return fmt.Errorf("%w: category promotions require category_id only", ErrInvalidPromotion)
+		}
+	case PromotionScopeProduct:
+		if r.ProductID == nil || *r.ProductID == "" || r.CategoryID != nil {
+			return fmt.Errorf("%w: product promotions require product_id only", ErrInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("%w: scope must be order, category or product", ErrInvalidPromotion)
+	}
+
+	if r.MinSubtotal < 0 {
+		return fmt.Errorf("%w: min_subtotal must be non-negative", ErrInvalidPromotion)
+	}
+
+	seen := make(map[int]bool, len(r.DaysOfWeek))
+	for _, day := range r.DaysOfWeek {
+		if day < 0 || day > 6 || seen[day] {
+			return fmt.Errorf("%w: days_of_week must be unique values from 0 (Sunday) to 6 (Saturday)", ErrInvalidPromotion)
+		}
+		seen[day] = true
+	}
+
+	if (r.StartTime == nil) != (r.EndTime == nil) {
+		return fmt.Errorf("%w: start_time and end_time must be set together", ErrInvalidPromotion)
+	}
+	if r.StartTime != nil {
+		if !promotionTimePattern.MatchString(*r.StartTime) || !promotionTimePattern.MatchString(*r.EndTime) {
+			return fmt.Errorf("%w: start_time and end_time must be HH:MM", ErrInvalidPromotion)
+		}
+		if *r.StartTime == *r.EndTime {
+			return fmt.Errorf("%w: start_time and end_time must differ", ErrInvalidPromotion)
+		}
+	}
+
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Jakarta"
+	}
+	if _, err := time.LoadLocation(r.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidPromotion, r.Timezone)
+	}
+
+	if r.ValidFrom != nil && r.ValidUntil != nil && !r.ValidFrom.Before(*r.ValidUntil) {
+		return fmt.Errorf("%w: valid_from must be before valid_until", ErrInvalidPromotion)
+	}
+
+	return nil
+}
+
+// ActiveAt reports whether the promotion's validity window and schedule include the given time
+func (p *Promotion) ActiveAt(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && !now.Before(*p.ValidUntil) {
+		return false
+	}
+
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil || p.Timezone == "" {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+
+	if len(p.DaysOfWeek) > 0 {
+		today := false
+		for _, day := range p.DaysOfWeek {
+			if time.Weekday(day) == local.Weekday() {
+				today = true
+				break
+			}
+		}
+		if !today {
+			return false
+		}
+	}
+
+	if p.StartTime != nil && p.EndTime != nil {
+		clock := local.Format("15:04")
+		start, end := (*p.StartTime)[:5], (*p.EndTime)[:5]
+		if start < end {
+			return clock >= start && clock < end
+		}
+		// Window wraps past midnight, e.g. 22:00-02:00
+		return clock >= start || clock < end
+	}
+
+	return true
+}
+
+// AppliedPromotion is a promotion applied to a cart or order and the discount it gave
+type AppliedPromotion struct {
+	PromotionID    string        `json:"promotion_id"`
+	Name           string        `json:"name"`
+	PromotionType  PromotionType `json:"promotion_type"`
+	DiscountAmount int           `json:"discount_amount"`
+}
+
+// OrderPromotion is the record of a promotion applied to an order at checkout
+type OrderPromotion struct {
+	ID             string        `json:"id"`
+	OrderID        string        `json:"order_id"`
+	PromotionID    *string       `json:"promotion_id,omitempty"` // Nil once the promotion is deleted
+	PromotionName  string        `json:"promotion_name"`
+	PromotionType  PromotionType `json:"promotion_type"`
+	DiscountAmount int           `json:"discount_amount"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
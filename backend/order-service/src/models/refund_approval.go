@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// RefundApprovalStatus is where a staff refund request stands
+type RefundApprovalStatus string
+
+const (
+	RefundApprovalPending  RefundApprovalStatus = "pending"  // Waiting for an approver
+	RefundApprovalApproved RefundApprovalStatus = "approved" // Approved and sent to the gateway
+	RefundApprovalRejected RefundApprovalStatus = "rejected"
+)
+
+// Staff roles as sent by the API gateway in X-User-Role
+const (
+	StaffRoleOwner   = "owner"
+	StaffRoleManager = "manager"
+	StaffRoleCashier = "cashier"
+)
+
+var (
+	ErrRefundApprovalNotFound   = errors.New("refund request not found")
+	ErrRefundApprovalNotPending = errors.New("refund request has already been decided")
+	ErrRefundApprovalForbidden  = errors.New("your role cannot approve this refund")
+	ErrRefundAlreadyRequested   = errors.New("a refund request for this order is already waiting for approval")
+	ErrRefundExceedsPayment     = errors.New("refunds would exceed the amount paid")
+	ErrRefundApprovalRequired   = errors.New("this refund needs approval; submit it as a refund request")
+	ErrRefundRejectionNote      = errors.New("a note is required to reject a refund request")
+)
+
+// RefundApproval is a refund requested by staff and, once approved, sent to the gateway
+type RefundApproval struct {
+	ID              string               `json:"id"`
+	TenantID        string               `json:"tenant_id"`
+	OrderID         string               `json:"order_id"`
+	OrderReference  string               `json:"order_reference,omitempty"`
+	Amount          int                  `json:"amount"`
+	Reason          string               `json:"reason"`
+	Status          RefundApprovalStatus `json:"status"`
+	ApproverRole    string               `json:"approver_role"` // Lowest role allowed to approve
+	RequestedBy     *string              `json:"requested_by,omitempty"`
+	RequestedByName *string              `json:"requested_by_name,omitempty"`
+	RequestedByRole string               `json:"requested_by_role"`
+	DecidedBy       *string              `json:"decided_by,omitempty"`
+	DecidedByName   *string              `json:"decided_by_name,omitempty"`
+	DecisionNote    *string              `json:"decision_note,omitempty"`
+	RefundID        *string              `json:"refund_id,omitempty"`
+	Gateway         *string              `json:"gateway,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	DecidedAt       *time.Time           `json:"decided_at,omitempty"`
+}
+
+// RefundDecisionRequest approves or rejects a refund request
+type RefundDecisionRequest struct {
+	Note string `json:"note,omitempty"` // Required when rejecting
+}
+
+// RefundApproverRole returns the lowest role that may approve a refund of amount
+// Refunds above the tenant's threshold need an owner; a threshold of 0 leaves every
+// refund to managers.
+func RefundApproverRole(amount, threshold int) string {
+	if threshold > 0 && amount > threshold {
+		return StaffRoleOwner
+	}
+	return StaffRoleManager
+}
+
+// CanApproveRefund reports whether a staff role may approve refunds needing approverRole
+func CanApproveRefund(role, approverRole string) bool {
+	switch role {
+	case StaffRoleOwner:
+		return true
+	case StaffRoleManager:
+		return approverRole == StaffRoleManager
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package models
+
+// Reasons a past order's item did not make it into the new cart unchanged
+const (
+	ReorderReasonUnavailable     = "unavailable"      // archived or removed from the catalog
+	ReorderReasonOutOfStock      = "out_of_stock"     // no sellable stock left
+	ReorderReasonQuantityReduced = "quantity_reduced" // fewer units in stock than ordered
+	ReorderReasonPriceChanged    = "price_changed"    // added at today's price
+)
+
+// SellableProduct is a catalog product as it can be sold right now
+type SellableProduct struct {
+	ProductID string
+	Name      string
+	UnitPrice int
+	Available int // available-to-promise stock
+}
+
+// ReorderSubstitution reports how one product of the past order was changed or dropped
+type ReorderSubstitution struct {
+	ProductID        string `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	Reason           string `json:"reason"`
+	OrderedQuantity  int    `json:"ordered_quantity"`
+	Quantity         int    `json:"quantity"`
+	OrderedUnitPrice int    `json:"ordered_unit_price"`
+	UnitPrice        int    `json:"unit_price"`
+}
+
+// ReorderResult is the guest's cart after a re-order, with what differs from the past order
+type ReorderResult struct {
+	Cart          *Cart                 `json:"cart"`
+	Substitutions []ReorderSubstitution `json:"substitutions"`
+}
+
+// PlanReorder builds cart items from a past order's items at today's catalog
+// Lines of the same product are combined; products missing from products are dropped,
+// quantities are capped at available stock, and items are priced at the current price.
+// Every deviation from the past order is reported as a substitution, in order item order.
+func PlanReorder(items []OrderItem, products map[string]SellableProduct) ([]CartItem, []ReorderSubstitution) {
+	cartItems := []CartItem{}
+	substitutions := []ReorderSubstitution{}
+
+	// Combine repeated lines of a product, keeping the first line's position
+	index := map[string]int{}
+	var ordered []OrderItem
+	for _, item := range items {
+		if i, ok := index[item.ProductID]; ok {
+			ordered[i].Quantity += item.Quantity
+			ordered[i].TotalPrice += item.TotalPrice
+			continue
+		}
+		index[item.ProductID] = len(ordered)
+		ordered = append(ordered, item)
+	}
+
+	for _, item := range ordered {
+		substitution := ReorderSubstitution{
+			ProductID:        item.ProductID,
+			ProductName:      item.ProductName,
+			OrderedQuantity:  item.Quantity,
+			OrderedUnitPrice: item.UnitPrice,
+		}
+
+		product, ok := products[item.ProductID]
+		if !ok {
+			substitution.Reason = ReorderReasonUnavailable
+			substitutions = append(substitutions, substitution)
+			continue
+		}
+		substitution.ProductName = product.Name
+		substitution.UnitPrice = product.UnitPrice
+		if product.Available <= 0 {
+			substitution.Reason = ReorderReasonOutOfStock
+			substitutions = append(substitutions, substitution)
+			continue
+		}
+
+		quantity := item.Quantity
+		if quantity > product.Available {
+			quantity = product.Available
+		}
+		substitution.Quantity = quantity
+
+		switch {
+		case quantity < item.Quantity:
+			substitution.Reason = ReorderReasonQuantityReduced
+			substitutions = append(substitutions, substitution)
+		case product.UnitPrice != item.UnitPrice:
+			substitution.Reason = ReorderReasonPriceChanged
+			substitutions = append(substitutions, substitution)
+		}
+
+		cartItems = append(cartItems, CartItem{
+			ProductID:   product.ProductID,
+			ProductName: product.Name,
+			Quantity:    quantity,
+			UnitPrice:   product.UnitPrice,
+			TotalPrice:  quantity * product.UnitPrice,
+		})
+	}
+
+	return cartItems, substitutions
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// SettlementMismatchKind describes how a charge disagrees with the gateway
+type SettlementMismatchKind string
+
+const (
+	SettlementNotSettledAtGateway SettlementMismatchKind = "not_settled_at_gateway" // We recorded a settlement the gateway does not report
+	SettlementUnrecorded          SettlementMismatchKind = "unrecorded_settlement"  // The gateway settled a charge we still show as unpaid
+	SettlementAmountMismatch      SettlementMismatchKind = "amount_mismatch"        // Both settled, for different amounts
+	SettlementCheckFailed         SettlementMismatchKind = "check_failed"           // A settled charge could not be looked up at the gateway
+)
+
+// SettlementReportDateLayout is the format of report dates in URLs and responses
+const SettlementReportDateLayout = "2006-01-02"
+
+var (
+	ErrInvalidSettlementDate    = errors.New("date must be a finished day in YYYY-MM-DD format")
+	ErrSettlementReportNotFound = errors.New("settlement report not found")
+)
+
+// SettlementLine totals the charges settled through one gateway with one payment type
+type SettlementLine struct {
+	Gateway     PaymentGatewayProvider `json:"gateway"`
+	PaymentType string                 `json:"payment_type"`
+	Count       int                    `json:"count"`
+	Amount      int64                  `json:"amount"`
+}
+
+// SettlementMismatch is a charge whose recorded status or amount disagrees with the gateway
+type SettlementMismatch struct {
+	PaymentTransactionID string                 `json:"payment_transaction_id"`
+	OrderID              string                 `json:"order_id"`
+	Charge               string                 `json:"charge"` // Gateway-facing order ID
+	Gateway              PaymentGatewayProvider `json:"gateway"`
+	PaymentType          string                 `json:"payment_type"`
+	Kind                 SettlementMismatchKind `json:"kind"`
+	RecordedStatus       string                 `json:"recorded_status"`
+	RecordedAmount       int                    `json:"recorded_amount"`
+	GatewayStatus        string                 `json:"gateway_status,omitempty"`
+	GatewayAmount        int                    `json:"gateway_amount,omitempty"`
+	Detail               string                 `json:"detail,omitempty"`
+}
+
+// SettlementReport summarizes a tenant's settled online payments for one local day
+type SettlementReport struct {
+	ID               string               `json:"id"`
+	TenantID         string               `json:"tenant_id"`
+	ReportDate       string               `json:"report_date"` // YYYY-MM-DD in Timezone
+	Timezone         string               `json:"timezone"`
+	TransactionCount int                  `json:"transaction_count"`
+	TotalAmount      int64                `json:"total_amount"`
+	MismatchCount    int                  `json:"mismatch_count"`
+	Lines            []SettlementLine     `json:"lines"`
+	Mismatches       []SettlementMismatch `json:"mismatches"`
+	GeneratedAt      time.Time            `json:"generated_at"`
+}
+
+// NewSettlementReport starts an empty report for a tenant's day
+func NewSettlementReport(tenantID, reportDate, timezone string) *SettlementReport {
+	return &SettlementReport{
+		TenantID:   tenantID,
+		ReportDate: reportDate,
+		Timezone:   timezone,
+		Lines:      []SettlementLine{},
+		Mismatches: []SettlementMismatch{},
+	}
+}
+
+// AddSettled counts a settled charge in its gateway and payment type line
+func (r *SettlementReport) AddSettled(gateway PaymentGatewayProvider, paymentType string, amount int) {
+	r.TransactionCount++
+	r.TotalAmount += int64(amount)
+	for i := range r.Lines {
+		if r.Lines[i].Gateway == gateway && r.Lines[i].PaymentType == paymentType {
+			r.Lines[i].Count++
+			r.Lines[i].Amount += int64(amount)
+			return
+		}
+	}
+	r.Lines = append(r.Lines, SettlementLine{Gateway: gateway, PaymentType: paymentType, Count: 1, Amount: int64(amount)})
+	sort.Slice(r.Lines, func(i, j int) bool {
+		if r.Lines[i].Gateway != r.Lines[j].Gateway {
+			return r.Lines[i].Gateway < r.Lines[j].Gateway
+		}
+		return r.Lines[i].PaymentType < r.Lines[j].PaymentType
+	})
+}
+
+// AddMismatch flags a charge for finance to look at
+func (r *SettlementReport) AddMismatch(mismatch SettlementMismatch) {
+	r.Mismatches = append(r.Mismatches, mismatch)
+	r.MismatchCount = len(r.Mismatches)
+}
+
+// ClassifySettlement compares our record of a charge with the gateway's
+// gatewayAmount is 0 when the gateway does not report one. An empty kind means they agree.
+func ClassifySettlement(recordedSettled bool, recordedAmount int, gatewayOutcome PaymentOutcome, gatewayAmount int) SettlementMismatchKind {
+	gatewaySettled := gatewayOutcome == PaymentOutcomeSuccess
+	switch {
+	case recordedSettled && !gatewaySettled:
+		return SettlementNotSettledAtGateway
+	case !recordedSettled && gatewaySettled:
+		return SettlementUnrecorded
+	case recordedSettled && gatewayAmount > 0 && gatewayAmount != recordedAmount:
+		return SettlementAmountMismatch
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SplitPaymentMethod is the method used to pay one part of a split payment
+type SplitPaymentMethod string
+
+const (
+	SplitPaymentCash SplitPaymentMethod = "cash"
+	SplitPaymentCard SplitPaymentMethod = "card"
+	SplitPaymentQRIS SplitPaymentMethod = "qris"
+)
+
+// Split payment errors
+var (
+	ErrInvalidSplitMethod        = errors.New("split payment method must be cash, card, or qris")
+	ErrSplitAmountExceedsBalance = errors.New("payment amount exceeds the outstanding balance")
+)
+
+// IsValid checks if the split payment method is supported
+func (m SplitPaymentMethod) IsValid() bool {
+	switch m {
+	case SplitPaymentCash, SplitPaymentCard, SplitPaymentQRIS:
+		return true
+	}
+	return false
+}
+
+// splitOrderIDSeparator joins the order reference and part number in Midtrans order IDs.
+// Order references never contain an underscore, so the suffix cannot be confused with one.
+const splitOrderIDSeparator = "_S"
+
+// SplitPaymentMidtransOrderID builds the Midtrans order_id for one part of a split payment.
+// Midtrans requires a unique order_id per charge, so parts are numbered after the order reference.
+func SplitPaymentMidtransOrderID(orderReference string, part int) string {
+	return fmt.Sprintf("%s%s%d", orderReference, splitOrderIDSeparator, part)
+}
+
+// ParseSplitPaymentOrderID extracts the order reference from a split payment Midtrans order_id.
+// Returns false when the ID belongs to a regular full-amount charge.
+func ParseSplitPaymentOrderID(midtransOrderID string) (string, bool) {
+	idx := strings.LastIndex(midtransOrderID, splitOrderIDSeparator)
+	if idx <= 0 {
+		return "", false
+	}
+	part, err := strconv.Atoi(midtransOrderID[idx+len(splitOrderIDSeparator):])
+	if err != nil || part < 1 {
+		return "", false
+	}
+	return midtransOrderID[:idx], true
+}
+
+// PaymentBalance summarizes how much of an order has been paid across all payment parts
+type PaymentBalance struct {
+	TotalAmount   int `json:"total_amount"`
+	AmountPaid    int `json:"amount_paid"`    // Recorded in-store payments plus settled QRIS parts
+	AmountPending int `json:"amount_pending"` // QRIS parts awaiting customer payment
+}
+
+// Outstanding returns the amount that can still be collected without overpaying
+func (b PaymentBalance) Outstanding() int {
+	outstanding := b.TotalAmount - b.AmountPaid - b.AmountPending
+	if outstanding < 0 {
+		return 0
+	}
+	return outstanding
+}
+
+// IsCovered reports whether settled payments cover the order total
+func (b PaymentBalance) IsCovered() bool {
+	return b.AmountPaid >= b.TotalAmount
+}
+
+// ValidateSplitAmount checks one part of a split payment against the balance
+func (b PaymentBalance) ValidateSplitAmount(amount int) error {
+	if amount <= 0 {
+		return ErrInvalidPaymentAmount
+	}
+	if amount > b.Outstanding() {
+		return ErrSplitAmountExceedsBalance
+	}
+	return nil
+}
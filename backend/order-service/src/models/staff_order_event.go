@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// StaffOrderEventType names an event pushed to a tenant's staff dashboards
+type StaffOrderEventType string
+
+const (
+	StaffOrderEventCreated StaffOrderEventType = "order.created" // A guest placed an order
+	StaffOrderEventPaid    StaffOrderEventType = "order.paid"    // An order moved to PAID
+)
+
+// StaffOrderEvent is a new or newly paid order pushed to authenticated staff sessions
+// It carries what a dashboard needs to show a notification; the full order is fetched
+// from the admin order API.
+type StaffOrderEvent struct {
+	Type            StaffOrderEventType `json:"type"`
+	TenantID        string              `json:"tenant_id"`
+	OrderID         string              `json:"order_id"`
+	OrderReference  string              `json:"order_reference"`
+	Status          OrderStatus         `json:"status"`
+	DeliveryType    DeliveryType        `json:"delivery_type"`
+	TableNumber     *string             `json:"table_number,omitempty"`
+	QueueNumber     *int                `json:"queue_number,omitempty"`
+	TotalAmount     int                 `json:"total_amount"`
+	PaymentFallback *string             `json:"payment_fallback,omitempty"`
+	ScheduledFor    *time.Time          `json:"scheduled_for,omitempty"`
+	OccurredAt      time.Time           `json:"occurred_at"`
+}
+
+// NewStaffOrderEvent builds a staff event from an order's current state
+func NewStaffOrderEvent(eventType StaffOrderEventType, order *GuestOrder) *StaffOrderEvent {
+	return &StaffOrderEvent{
+		Type:            eventType,
+		TenantID:        order.TenantID,
+		OrderID:         order.ID,
+		OrderReference:  order.OrderReference,
+		Status:          order.Status,
+		DeliveryType:    order.DeliveryType,
+		TableNumber:     order.TableNumber,
+		QueueNumber:     order.QueueNumber,
+		TotalAmount:     order.TotalAmount,
+		PaymentFallback: order.PaymentFallback,
+		ScheduledFor:    order.ScheduledFor,
+		OccurredAt:      time.Now().UTC(),
+	}
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ClosedOrderPolicy selects what checkout does with an immediate order placed while the store is closed
+type ClosedOrderPolicy string
+
+const (
+	ClosedOrderAccept   ClosedOrderPolicy = "accept"   // the order is taken anyway
+	ClosedOrderReject   ClosedOrderPolicy = "reject"   // checkout fails with the next opening time
+	ClosedOrderSchedule ClosedOrderPolicy = "schedule" // the order is booked into the next slot with room
+)
+
+// Store hours errors
+var (
+	ErrStoreClosed              = errors.New("the store is not taking orders right now")
+	ErrInvalidClosedOrderPolicy = errors.New("closed_order_policy must be one of: accept, reject, schedule")
+	ErrInvalidOrderingCutoff    = errors.New("ordering_cutoff_minutes must be between 0 and 240")
+	ErrInvalidHolidays          = errors.New("holidays must have unique YYYY-MM-DD dates, and open and close in HH:MM together with open before close")
+)
+
+// nextOpeningSearchDays is how far ahead NextOpening looks for an opening
+const nextOpeningSearchDays = 31
+
+// IsValid checks if the policy is supported
+func (p ClosedOrderPolicy) IsValid() bool {
+	switch p {
+	case ClosedOrderAccept, ClosedOrderReject, ClosedOrderSchedule:
+		return true
+	}
+	return false
+}
+
+// Holiday replaces a date's business hours: closed all day, or open with special hours
+type Holiday struct {
+	Date  string `json:"date"` // YYYY-MM-DD in the store timezone
+	Name  string `json:"name,omitempty"`
+	Open  string `json:"open,omitempty"` // Both empty means closed all day
+	Close string `json:"close,omitempty"`
+}
+
+// Holidays is a store's list of holiday dates
+type Holidays []Holiday
+
+// Scan implements sql.Scanner for Holidays (JSONB)
+func (h *Holidays) Scan(value interface{}) error {
+	*h = Holidays{}
+	if value == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Holidays", value)
+	}
+	return json.Unmarshal(data, h)
+}
+
+// Value implements driver.Valuer for Holidays (JSONB)
+func (h Holidays) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Validate checks dates are unique and special hours open before they close
+func (h Holidays) Validate() error {
+	seen := make(map[string]bool, len(h))
+	for _, holiday := range h {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil || seen[holiday.Date] {
+			return ErrInvalidHolidays
+		}
+		seen[holiday.Date] = true
+
+		if holiday.Open == "" && holiday.Close == "" {
+			continue
+		}
+		openMinutes, okOpen := parseClock(holiday.Open)
+		closeMinutes, okClose := parseClock(holiday.Close)
+		if !okOpen || !okClose || openMinutes >= closeMinutes {
+			return ErrInvalidHolidays
+		}
+	}
+	return nil
+}
+
+// on returns the holiday falling on the local date of day
+func (h Holidays) on(day time.Time) (Holiday, bool) {
+	date := day.Format("2006-01-02")
+	for _, holiday := range h {
+		if holiday.Date == date {
+			return holiday, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// ValidateStoreHours checks the holiday, cutoff and closed order policy fields that are being changed
+func (r *UpdateOrderSettingsRequest) ValidateStoreHours() error {
+	if r.Holidays != nil {
+		if err := r.Holidays.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.OrderingCutoffMinutes != nil && (*r.OrderingCutoffMinutes < 0 || *r.OrderingCutoffMinutes > 240) {
+		return ErrInvalidOrderingCutoff
+	}
+	if r.ClosedOrderPolicy != nil && !r.ClosedOrderPolicy.IsValid() {
+		return ErrInvalidClosedOrderPolicy
+	}
+	return nil
+}
+
+// openingWindow returns the opening and closing time on the local date of day
+// A holiday replaces the weekday's business hours with its special hours, or closes the store.
+func (s *OrderSettings) openingWindow(day time.Time) (openAt, closeAt time.Time, ok bool) {
+	if holiday, found := s.Holidays.on(day); found {
+		if holiday.Open == "" {
+			return time.Time{}, time.Time{}, false
+		}
+		return DayHours{Open: holiday.Open, Close: holiday.Close}.window(day)
+	}
+	return s.BusinessHours.window(day)
+}
+
+// lastOrderAt is when immediate orders stop on a day closing at closeAt
+func (s *OrderSettings) lastOrderAt(closeAt time.Time) time.Time {
+	return closeAt.Add(-time.Duration(s.OrderingCutoffMinutes) * time.Minute)
+}
+
+// OpenForOrders reports whether an immediate order can be placed at t
+// Stores without business hours never close. Orders stop ordering_cutoff_minutes before closing.
+func (s *OrderSettings) OpenForOrders(t time.Time) bool {
+	if len(s.BusinessHours) == 0 {
+		return true
+	}
+	local := t.In(s.Location())
+	openAt, closeAt, ok := s.openingWindow(local)
+	return ok && !local.Before(openAt) && local.Before(s.lastOrderAt(closeAt))
+}
+
+// NextOpening returns when immediate orders are next taken at or after t
+// nil when the store does not open within the next month.
+func (s *OrderSettings) NextOpening(t time.Time) *time.Time {
+	if s.OpenForOrders(t) {
+		return &t
+	}
+
+	local := t.In(s.Location())
+	for day := 0; day <= nextOpeningSearchDays; day++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, local.Location())
+		openAt, closeAt, ok := s.openingWindow(date)
+		if !ok || !openAt.Before(s.lastOrderAt(closeAt)) || !openAt.After(local) {
+			continue
+		}
+		return &openAt
+	}
+	return nil
+}
+
+// FirstOpenSlot returns the earliest slot that can still take an order, or nil when all are full
+func FirstOpenSlot(slots []TimeSlot) *TimeSlot {
+	for i := range slots {
+		if slots[i].Remaining == nil || *slots[i].Remaining > 0 {
+			return &slots[i]
+		}
+	}
+	return nil
+}
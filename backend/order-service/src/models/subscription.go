@@ -0,0 +1,160 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Plan codes seeded by the subscription billing migration
+const (
+	PlanFree = "free"
+	PlanPro  = "pro"
+)
+
+// SubscriptionStatus is whether a tenant's subscription is paid up
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive  SubscriptionStatus = "active"
+	SubscriptionPastDue SubscriptionStatus = "past_due" // A renewal is unpaid; the plan is kept until grace_until
+)
+
+// SubscriptionInvoiceKind tells an upgrade from a renewal
+type SubscriptionInvoiceKind string
+
+const (
+	SubscriptionInvoiceUpgrade SubscriptionInvoiceKind = "upgrade" // First period of a higher plan, applied once paid
+	SubscriptionInvoiceRenewal SubscriptionInvoiceKind = "renewal" // Next period of the current plan
+)
+
+// SubscriptionInvoiceStatus is where a subscription invoice stands
+type SubscriptionInvoiceStatus string
+
+const (
+	SubscriptionInvoiceOpen SubscriptionInvoiceStatus = "open"
+	SubscriptionInvoicePaid SubscriptionInvoiceStatus = "paid"
+	SubscriptionInvoiceVoid SubscriptionInvoiceStatus = "void" // Replaced, expired, or dropped on downgrade
+)
+
+// Billing defaults, overridable with BILLING_GRACE_DAYS and BILLING_INVOICE_LEAD_DAYS
+const (
+	DefaultBillingGraceDays       = 7
+	DefaultBillingInvoiceLeadDays = 7
+)
+
+// billingChargePrefix starts the Midtrans order ID of every subscription invoice charge
+const billingChargePrefix = "SUB-"
+
+var (
+	ErrPlanNotFound         = errors.New("plan not found")
+	ErrAlreadyOnPlan        = errors.New("the tenant is already on this plan")
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvoiceNotPayable    = errors.New("invoice is already paid or void")
+	ErrBillingNotConfigured = errors.New("subscription payments are not configured")
+)
+
+// SubscriptionPlan is a plan tenants can subscribe to; nil limits are unlimited
+type SubscriptionPlan struct {
+	Code             string `json:"code"`
+	Name             string `json:"name"`
+	MonthlyPrice     int    `json:"monthly_price"` // IDR
+	MaxProducts      *int   `json:"max_products"`
+	MaxStaff         *int   `json:"max_staff"`
+	MaxOutlets       *int   `json:"max_outlets"`
+	MaxMonthlyOrders *int   `json:"max_monthly_orders"`
+
+	MaxStorageBytes         *int64 `json:"max_storage_bytes"` // nil falls back to the tenant's storage quota
+	MaxMonthlyNotifications *int   `json:"max_monthly_notifications"`
+}
+
+// IsPaid reports whether the plan is billed
+func (p *SubscriptionPlan) IsPaid() bool {
+	return p.MonthlyPrice > 0
+}
+
+// TenantSubscription is the plan a tenant is on and the period it has paid for
+type TenantSubscription struct {
+	TenantID           string             `json:"tenant_id"`
+	PlanCode           string             `json:"plan_code"`
+	Plan               *SubscriptionPlan  `json:"plan"`
+	Status             SubscriptionStatus `json:"status"`
+	CurrentPeriodStart time.Time          `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time          `json:"current_period_end"`
+	GraceUntil         *time.Time         `json:"grace_until,omitempty"`
+	ScheduledPlanCode  *string            `json:"scheduled_plan_code,omitempty"` // Lower plan from the next period
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// SubscriptionInvoice is one month of a paid plan, paid through the platform's Midtrans account
+type SubscriptionInvoice struct {
+	ID              string                    `json:"id"`
+	TenantID        string                    `json:"tenant_id"`
+	Kind            SubscriptionInvoiceKind   `json:"kind"`
+	PlanCode        string                    `json:"plan_code"`
+	Amount          int                       `json:"amount"`
+	PeriodStart     time.Time                 `json:"period_start"`
+	PeriodEnd       time.Time                 `json:"period_end"`
+	Status          SubscriptionInvoiceStatus `json:"status"`
+	DueAt           time.Time                 `json:"due_at"`
+	PaidAt          *time.Time                `json:"paid_at,omitempty"`
+	PaymentAttempts int                       `json:"payment_attempts"`
+	MidtransOrderID *string                   `json:"midtrans_order_id,omitempty"`
+	PaymentType     *string                   `json:"payment_type,omitempty"`
+	RedirectURL     *string                   `json:"redirect_url,omitempty"` // Snap payment page of the latest attempt
+	CreatedAt       time.Time                 `json:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+}
+
+// ChangePlanRequest moves a tenant to another plan
+// Higher plans start once their first invoice is paid; lower plans start when the current period ends.
+type ChangePlanRequest struct {
+	PlanCode string `json:"plan_code"`
+}
+
+// ChangePlanResponse is the subscription after a plan change, with the invoice to pay for an upgrade
+type ChangePlanResponse struct {
+	Subscription *TenantSubscription  `json:"subscription"`
+	Invoice      *SubscriptionInvoice `json:"invoice,omitempty"`
+}
+
+// AddBillingMonth returns the same day one month later, or that month's last day when it is shorter
+// Unlike time.AddDate, 31 January is followed by 28 or 29 February rather than early March.
+func AddBillingMonth(t time.Time) time.Time {
+	year, month, day := t.Date()
+	firstOfNext := time.Date(year, month+1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfNext.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month+1, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// IsPayable reports whether the invoice can still be paid
+func (i *SubscriptionInvoice) IsPayable() bool {
+	return i.Status == SubscriptionInvoiceOpen
+}
+
+// BillingChargeOrderID returns the Midtrans order ID for a payment attempt on an invoice
+// Midtrans rejects a reused order ID, so every attempt gets its own.
+func BillingChargeOrderID(invoiceID string, attempt int) string {
+	return billingChargePrefix + invoiceID + "-" + strconv.Itoa(attempt)
+}
+
+// ParseBillingChargeOrderID returns the invoice a subscription charge belongs to
+func ParseBillingChargeOrderID(orderID string) (string, bool) {
+	rest, ok := strings.CutPrefix(orderID, billingChargePrefix)
+	if !ok {
+		return "", false
+	}
+	sep := strings.LastIndex(rest, "-")
+	if sep <= 0 {
+		return "", false
+	}
+	if attempt, err := strconv.Atoi(rest[sep+1:]); err != nil || attempt < 1 {
+		return "", false
+	}
+	return rest[:sep], true
+}
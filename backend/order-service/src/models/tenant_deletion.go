@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TenantDeletionParticipant is the name tenant-service asks this service by in deletion requests
+const TenantDeletionParticipant = "order-service"
+
+// Tenant deletion saga event types on the tenant-events topic
+const (
+	TenantDeletionRequestedEvent    = "tenant.deletion.requested"
+	TenantDeletionAcknowledgedEvent = "tenant.deletion.acknowledged"
+)
+
+// TenantEvent is a message on the tenant-events topic
+type TenantEvent struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	TenantID  string          `json:"tenant_id"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// TenantDeletionRequest is the data of a tenant.deletion.requested event
+type TenantDeletionRequest struct {
+	DeletionID string   `json:"deletion_id"`
+	Services   []string `json:"services"`
+}
+
+// Includes reports whether service is asked to delete its part of the tenant's data
+func (r *TenantDeletionRequest) Includes(service string) bool {
+	for _, s := range r.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantDeletionAck is the data of the tenant.deletion.acknowledged event answering a request
+// Status is "completed" with what was deleted, or "failed" with the error.
+type TenantDeletionAck struct {
+	DeletionID string           `json:"deletion_id"`
+	Service    string           `json:"service"`
+	Status     string           `json:"status"`
+	Deleted    map[string]int64 `json:"deleted"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// NewTenantDeletionAck answers a deletion request with the outcome of deleting the tenant's orders
+func NewTenantDeletionAck(deletionID string, deleted map[string]int64, cause error) *TenantDeletionAck {
+	ack := &TenantDeletionAck{
+		DeletionID: deletionID,
+		Service:    TenantDeletionParticipant,
+		Status:     "completed",
+		Deleted:    deleted,
+	}
+	if ack.Deleted == nil {
+		ack.Deleted = map[string]int64{}
+	}
+	if cause != nil {
+		ack.Status = "failed"
+		ack.Error = cause.Error()
+	}
+	return ack
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DiscountType is how a voucher reduces the order subtotal
+type DiscountType string
+
+const (
+	DiscountTypePercent DiscountType = "percent"
+	DiscountTypeFixed   DiscountType = "fixed"
+)
+
+var (
+	ErrInvalidVoucher               = errors.New("invalid voucher")
+	ErrVoucherNotFound              = errors.New("voucher not found")
+	ErrVoucherCodeExists            = errors.New("a voucher with this code already exists")
+	ErrVoucherInUse                 = errors.New("voucher has been redeemed and cannot be deleted; deactivate it instead")
+	ErrVoucherInactive              = errors.New("voucher is not active")
+	ErrVoucherNotStarted            = errors.New("voucher is not valid yet")
+	ErrVoucherExpired               = errors.New("voucher has expired")
+	ErrVoucherMinSubtotal           = errors.New("order subtotal is below the voucher minimum")
+	ErrVoucherUsageLimitReached     = errors.New("voucher usage limit has been reached")
+	ErrVoucherCustomerLimitReached  = errors.New("voucher has already been used the maximum number of times by this customer")
+	ErrVoucherCustomerPhoneRequired = errors.New("customer phone is required to redeem this voucher")
+)
+
+var voucherCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// Voucher is a tenant discount code redeemable at checkout
+type Voucher struct {
+	ID                string       `json:"id"`
+	TenantID          string       `json:"tenant_id"`
+	Code              string       `json:"code"`
+	Description       *string      `json:"description,omitempty"`
+	DiscountType      DiscountType `json:"discount_type"`
+	DiscountValue     int          `json:"discount_value"`                // Percent (1-100) or IDR amount
+	MaxDiscountAmount *int         `json:"max_discount_amount,omitempty"` // Cap for percent vouchers
+	MinSubtotal       int          `json:"min_subtotal"`
+	UsageLimit        *int         `json:"usage_limit,omitempty"`        // Total redemptions, nil = unlimited
+	PerCustomerLimit  *int         `json:"per_customer_limit,omitempty"` // Redemptions per phone number, nil = unlimited
+	UsageCount        int          `json:"usage_count"`
+	ValidFrom         *time.Time   `json:"valid_from,omitempty"`
+	ValidUntil        *time.Time   `json:"valid_until,omitempty"`
+	IsActive          bool         `json:"is_active"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// VoucherRequest creates a voucher or replaces its settings
+type VoucherRequest struct {
+	Code              string       `json:"code"`
+	Description       *string      `json:"description,omitempty"`
+	DiscountType      DiscountType `json:"discount_type"`
+	DiscountValue     int          `json:"discount_value"`
+	MaxDiscountAmount *int         `json:"max_discount_amount,omitempty"`
+	MinSubtotal       int          `json:"min_subtotal"`
+	UsageLimit        *int         `json:"usage_limit,omitempty"`
+	PerCustomerLimit  *int         `json:"per_customer_limit,omitempty"`
+	ValidFrom         *time.Time   `json:"valid_from,omitempty"`
+	ValidUntil        *time.Time   `json:"valid_until,omitempty"`
+	IsActive          *bool        `json:"is_active,omitempty"` // Defaults to true
+}
+
+// Validate normalizes the code and checks the voucher rules
+func (r *VoucherRequest) Validate() error {
+	r.Code = NormalizeVoucherCode(r.Code)
+	if !voucherCodePattern.MatchString(r.Code) {
+		return fmt.Errorf("%w: code must be 3-50 letters, digits, '-' or '_'", ErrInvalidVoucher)
+	}
+
+	switch r.DiscountType {
+	case DiscountTypePercent:
+		if r.DiscountValue < 1 || r.DiscountValue > 100 {
+			return fmt.Errorf("%w: percent discount_value must be between 1 and 100", ErrInvalidVoucher)
+		}
+	case DiscountTypeFixed:
+		if r.DiscountValue < 1 {
+			return fmt.Errorf("%w: fixed discount_value must be greater than 0", ErrInvalidVoucher)
+		}
+		if r.MaxDiscountAmount != nil {
+			return fmt.Errorf("%w: max_discount_amount only applies to percent vouchers", ErrInvalidVoucher)
+		}
+	default:
+		return fmt.Errorf("%w: discount_type must be percent or fixed", ErrInvalidVoucher)
+	}
+
+	if r.MaxDiscountAmount != nil && *r.MaxDiscountAmount < 1 {
+		return fmt.Errorf("%w: max_discount_amount must be greater than 0", ErrInvalidVoucher)
+	}
+	if r.MinSubtotal < 0 {
+		return fmt.Errorf("%w: min_subtotal must be non-negative", ErrInvalidVoucher)
+	}
+	if r.UsageLimit != nil && *r.UsageLimit < 1 {
+		return fmt.Errorf("%w: usage_limit must be greater than 0", ErrInvalidVoucher)
+	}
+	if r.PerCustomerLimit != nil && *r.PerCustomerLimit < 1 {
+		return fmt.Errorf("%w: per_customer_limit must be greater than 0", ErrInvalidVoucher)
+	}
+	if r.ValidFrom != nil && r.ValidUntil != nil && !r.ValidFrom.Before(*r.ValidUntil) {
+		return fmt.Errorf("%w: valid_from must be before valid_until", ErrInvalidVoucher)
+	}
+
+	return nil
+}
+
+// NormalizeVoucherCode upper-cases and trims a code as entered by a customer
+func NormalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CheckAvailable verifies the voucher can be used for a subtotal at the given time
+// Per-customer limits are checked at checkout, once the customer's phone is known.
+func (v *Voucher) CheckAvailable(subtotal int, now time.Time) error {
+	if !v.IsActive {
+		return ErrVoucherInactive
+	}
+	if v.ValidFrom != nil && now.Before(*v.ValidFrom) {
+		return ErrVoucherNotStarted
+	}
+	if v.ValidUntil != nil && !now.Before(*v.ValidUntil) {
+		return ErrVoucherExpired
+	}
+	if v.UsageLimit != nil && v.UsageCount >= *v.UsageLimit {
+		return ErrVoucherUsageLimitReached
+	}
+	if subtotal < v.MinSubtotal {
+		return ErrVoucherMinSubtotal
+	}
+	return nil
+}
+
+// CalculateDiscount returns the discount for a subtotal, never more than the subtotal
+func (v *Voucher) CalculateDiscount(subtotal int) int {
+	discount := v.DiscountValue
+	if v.DiscountType == DiscountTypePercent {
+		discount = subtotal * v.DiscountValue / 100
+		if v.MaxDiscountAmount != nil && discount > *v.MaxDiscountAmount {
+			discount = *v.MaxDiscountAmount
+		}
+	}
+
+	if discount > subtotal {
+		discount = subtotal
+	}
+	if discount < 0 {
+		discount = 0
+	}
+	return discount
+}
+
+// VoucherCustomerKey identifies a customer for per-customer limits without storing the phone number
+func VoucherCustomerKey(tenantID, phone string) string {
+	return CustomerPhoneHash(tenantID, phone)
+}
+
+// ApplyVoucherRequest applies a voucher code to a cart
+type ApplyVoucherRequest struct {
+	Code string `json:"code"`
+}
+
+// CartVoucherSummary is the discount a voucher gives the current cart
+type CartVoucherSummary struct {
+	VoucherCode       string       `json:"voucher_code"`
+	Description       *string      `json:"description,omitempty"`
+	DiscountType      DiscountType `json:"discount_type"`
+	SubtotalAmount    int          `json:"subtotal_amount"`
+	PromotionDiscount int          `json:"promotion_discount"` // Applied before the voucher
+	DiscountAmount    int          `json:"discount_amount"`
+	TotalAmount       int          `json:"total_amount"` // Before delivery fee
+}
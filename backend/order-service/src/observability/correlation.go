@@ -0,0 +1,29 @@
+package observability
+
+import "context"
+
+// CorrelationIDHeader is the HTTP header that carries the correlation ID.
+// The API gateway assigns one per incoming request and forwards it to backend services.
+const CorrelationIDHeader = "X-Request-ID"
+
+// CorrelationIDKafkaHeader is the Kafka message header that carries the correlation ID
+const CorrelationIDKafkaHeader = "correlation_id"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID of the originating request
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	if correlationID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context, or ""
+func CorrelationIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
@@ -0,0 +1,116 @@
+// Package projection shapes admin order responses to what the caller's role may see
+package projection
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// Level is how much of an order a caller may see
+type Level int
+
+const (
+	// LevelOperational is for cashiers: what is needed to prepare and hand over
+	// the order. Customer contact is masked; no forensic data, cost or margin.
+	LevelOperational Level = iota
+	// LevelContact is for managers: adds full customer contact details
+	LevelContact
+	// LevelFull is for owners: adds request forensics, item cost and margin
+	LevelFull
+)
+
+// ForRole returns the projection level of a role
+// Unknown or missing roles get the most restrictive level.
+func ForRole(role middleware.Role) Level {
+	switch role {
+	case middleware.RoleOwner:
+		return LevelFull
+	case middleware.RoleManager:
+		return LevelContact
+	default:
+		return LevelOperational
+	}
+}
+
+// OrderItem is an order item as returned to admins
+// Cost fields are only set for LevelFull and only when the product still exists.
+type OrderItem struct {
+	models.OrderItem
+	UnitCost  *int `json:"unit_cost,omitempty"`  // Current product cost; items do not snapshot cost
+	CostTotal *int `json:"cost_total,omitempty"` // quantity * unit_cost
+	Margin    *int `json:"margin,omitempty"`     // total_price - cost_total
+}
+
+// Order returns a copy of the order holding only the fields the level may see
+// The input order is not modified.
+func Order(order *models.GuestOrder, level Level) *models.GuestOrder {
+	if order == nil {
+		return nil
+	}
+	projected := *order
+
+	if level < LevelFull {
+		projected.SessionID = ""
+		projected.IPAddress = nil
+		projected.UserAgent = nil
+	}
+
+	if level < LevelContact {
+		projected.CustomerName = MaskName(projected.CustomerName)
+		projected.CustomerPhone = MaskPhone(projected.CustomerPhone)
+		projected.CustomerEmail = nil
+	}
+
+	return &projected
+}
+
+// OrderItems projects order items for the level
+// costs maps product IDs to their unit cost and is ignored below LevelFull.
+func OrderItems(items []models.OrderItem, costs map[string]int, level Level) []OrderItem {
+	projected := make([]OrderItem, 0, len(items))
+	for _, item := range items {
+		view := OrderItem{OrderItem: item}
+		if level == LevelFull {
+			if unitCost, ok := costs[item.ProductID]; ok {
+				costTotal := unitCost * item.Quantity
+				margin := item.TotalPrice - costTotal
+				view.UnitCost = &unitCost
+				view.CostTotal = &costTotal
+				view.Margin = &margin
+			}
+		}
+		projected = append(projected, view)
+	}
+	return projected
+}
+
+// MaskName keeps the first letter of each word of a name
+// Follows the log masking format: "Budi Santoso" becomes "B*** S***".
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = string([]rune(word)[0]) + "***"
+	}
+	return strings.Join(words, " ")
+}
+
+// MaskPhone keeps only the last four digits of a phone number
+// Follows the log masking format: "+628123456789" becomes "******6789".
+func MaskPhone(phone string) string {
+	digits := []rune{}
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) == 0 {
+		return ""
+	}
+	if len(digits) <= 4 {
+		return "******"
+	}
+	return "******" + string(digits[len(digits)-4:])
+}
@@ -6,9 +6,61 @@ import (
 	"log"
 	"time"
 
+	"github.com/point-of-sale-system/order-service/src/observability"
 	"github.com/segmentio/kafka-go"
 )
 
+// KafkaConsumer for consuming events
+type KafkaConsumer struct {
+	reader  *kafka.Reader
+	handler func(context.Context, []byte) error
+}
+
+func NewKafkaConsumer(brokers []string, topic string, groupID string, handler func(context.Context, []byte) error) *KafkaConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        brokers,
+		Topic:          topic,
+		GroupID:        groupID,
+		MinBytes:       10e1, // 100B
+		MaxBytes:       10e6, // 10MB
+		CommitInterval: time.Second,
+		StartOffset:    kafka.LastOffset,
+	})
+
+	return &KafkaConsumer{
+		reader:  reader,
+		handler: handler,
+	}
+}
+
+func (c *KafkaConsumer) Start(ctx context.Context) {
+	log.Printf("Starting Kafka consumer for topic: %s", c.reader.Config().Topic)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Shutting down Kafka consumer...")
+			c.reader.Close()
+			return
+		default:
+			msg, err := c.reader.ReadMessage(ctx)
+			if err != nil {
+				log.Printf("Error reading message: %v", err)
+				continue
+			}
+
+			if err := c.handler(ctx, msg.Value); err != nil {
+				log.Printf("Error handling message: %v", err)
+				continue
+			}
+		}
+	}
+}
+
+func (c *KafkaConsumer) Close() error {
+	return c.reader.Close()
+}
+
 // KafkaProducer for publishing events
 type KafkaProducer struct {
 	writer *kafka.Writer
@@ -41,6 +93,23 @@ func NewKafkaProducer(brokers []string, topic string) *KafkaProducer {
 	return NewKafkaProducerWithConfig(config)
 }
 
+// NewKeyedKafkaProducer creates a Kafka producer that routes messages by key
+// Messages with the same key always go to the same partition, so consumers in a
+// group see them in order on one instance. Messages without a key are spread round-robin.
+func NewKeyedKafkaProducer(brokers []string, topic string) *KafkaProducer {
+	config := KafkaProducerConfig{
+		Brokers:              brokers,
+		Topic:                topic,
+		Balancer:             &kafka.Hash{},
+		MaxAttempts:          3,
+		RequiredAcks:         kafka.RequireOne,
+		Async:                false,
+		Compression:          kafka.Snappy,
+		AllowAutoTopicCreate: true,
+	}
+	return NewKafkaProducerWithConfig(config)
+}
+
 // NewKafkaProducerWithConfig creates a Kafka producer with custom configuration
 func NewKafkaProducerWithConfig(config KafkaProducerConfig) *KafkaProducer {
 	writer := &kafka.Writer{
@@ -74,9 +143,10 @@ func (p *KafkaProducer) Publish(ctx context.Context, key string, value interface
 	}
 
 	msg := kafka.Message{
-		Key:   []byte(key),
-		Value: data,
-		Time:  time.Now(),
+		Key:     []byte(key),
+		Value:   data,
+		Time:    time.Now(),
+		Headers: withCorrelationHeader(ctx, nil),
 	}
 
 	log.Printf("DEBUG: Publishing message to Kafka - Topic: %s, Key: %s, Size: %d bytes",
@@ -111,12 +181,27 @@ func (p *KafkaProducer) PublishWithHeaders(ctx context.Context, key string, valu
 		Key:     []byte(key),
 		Value:   data,
 		Time:    time.Now(),
-		Headers: headers,
+		Headers: withCorrelationHeader(ctx, headers),
 	}
 
 	return p.writer.WriteMessages(ctx, msg)
 }
 
+// withCorrelationHeader appends the request correlation ID from ctx, if any,
+// so consumers can tie the message back to the originating HTTP request
+func withCorrelationHeader(ctx context.Context, headers []kafka.Header) []kafka.Header {
+	correlationID := observability.CorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		return headers
+	}
+	for _, h := range headers {
+		if h.Key == observability.CorrelationIDKafkaHeader {
+			return headers
+		}
+	}
+	return append(headers[:len(headers):len(headers)], kafka.Header{Key: observability.CorrelationIDKafkaHeader, Value: []byte(correlationID)})
+}
+
 // PublishBatch publishes multiple messages in a single batch
 func (p *KafkaProducer) PublishBatch(ctx context.Context, messages []kafka.Message) error {
 	return p.writer.WriteMessages(ctx, messages...)
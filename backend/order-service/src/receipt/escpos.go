@@ -0,0 +1,173 @@
+// Package receipt renders paid orders as ESC/POS byte streams for thermal receipt printers
+package receipt
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ESC/POS commands used by the renderers
+var (
+	cmdInit        = []byte{0x1B, 0x40}             // ESC @: reset the printer
+	cmdAlignLeft   = []byte{0x1B, 0x61, 0x00}       // ESC a 0
+	cmdAlignCenter = []byte{0x1B, 0x61, 0x01}       // ESC a 1
+	cmdBoldOn      = []byte{0x1B, 0x45, 0x01}       // ESC E 1
+	cmdBoldOff     = []byte{0x1B, 0x45, 0x00}       // ESC E 0
+	cmdDoubleOn    = []byte{0x1D, 0x21, 0x11}       // GS ! 0x11: double width and height
+	cmdDoubleOff   = []byte{0x1D, 0x21, 0x00}       // GS ! 0
+	cmdFeedAndCut  = []byte{0x1D, 0x56, 0x42, 0x03} // GS V 66 3: feed past the cutter, partial cut
+)
+
+// Builder accumulates an ESC/POS byte stream
+// All text goes through sanitize, so order data cannot inject printer commands.
+type Builder struct {
+	buf    bytes.Buffer
+	width  int
+	double bool
+}
+
+// NewBuilder starts a stream for paper of width characters
+func NewBuilder(width int) *Builder {
+	b := &Builder{width: width}
+	b.buf.Write(cmdInit)
+	return b
+}
+
+// Center centers the following lines
+func (b *Builder) Center() *Builder {
+	b.buf.Write(cmdAlignCenter)
+	return b
+}
+
+// Left left-aligns the following lines
+func (b *Builder) Left() *Builder {
+	b.buf.Write(cmdAlignLeft)
+	return b
+}
+
+// Bold turns emphasis on or off
+func (b *Builder) Bold(on bool) *Builder {
+	if on {
+		b.buf.Write(cmdBoldOn)
+	} else {
+		b.buf.Write(cmdBoldOff)
+	}
+	return b
+}
+
+// Double turns double width and height on or off; lines then fit half as many characters
+func (b *Builder) Double(on bool) *Builder {
+	b.double = on
+	if on {
+		b.buf.Write(cmdDoubleOn)
+	} else {
+		b.buf.Write(cmdDoubleOff)
+	}
+	return b
+}
+
+// Line prints text, wrapping it at the paper width
+func (b *Builder) Line(text string) *Builder {
+	for _, line := range wrap(sanitize(text), b.lineWidth()) {
+		b.buf.WriteString(line)
+		b.buf.WriteByte('\n')
+	}
+	return b
+}
+
+// Columns prints left and right on one line, wrapping left when both do not fit
+func (b *Builder) Columns(left, right string) *Builder {
+	width := b.lineWidth()
+	left, right = sanitize(left), sanitize(right)
+
+	lines := wrap(left, max(width-len(right)-1, 1))
+	for i, line := range lines {
+		if i == len(lines)-1 {
+			line += strings.Repeat(" ", max(width-len(line)-len(right), 1)) + right
+		}
+		b.buf.WriteString(line)
+		b.buf.WriteByte('\n')
+	}
+	return b
+}
+
+// Rule prints a dashed separator across the paper
+func (b *Builder) Rule() *Builder {
+	b.buf.WriteString(strings.Repeat("-", b.lineWidth()))
+	b.buf.WriteByte('\n')
+	return b
+}
+
+// Feed prints n blank lines
+func (b *Builder) Feed(n int) *Builder {
+	b.buf.WriteString(strings.Repeat("\n", n))
+	return b
+}
+
+// Cut feeds the paper past the cutter and cuts it
+func (b *Builder) Cut() *Builder {
+	b.buf.Write(cmdFeedAndCut)
+	return b
+}
+
+// Bytes returns the stream
+func (b *Builder) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+func (b *Builder) lineWidth() int {
+	if b.double {
+		return max(b.width/2, 1)
+	}
+	return max(b.width, 1)
+}
+
+// sanitize keeps printable ASCII; printers default to code page 437, and control bytes would be read as commands
+func sanitize(text string) string {
+	var out strings.Builder
+	for _, r := range text {
+		switch {
+		case r >= 0x20 && r < 0x7F:
+			out.WriteRune(r)
+		case r == '\t' || r == '\n' || r == '\r':
+			out.WriteByte(' ')
+		default:
+			out.WriteByte('?')
+		}
+	}
+	return out.String()
+}
+
+// wrap splits text into lines of at most width characters, breaking at spaces where possible
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		for len(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case current == "":
+			current = word
+		case len(current)+1+len(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
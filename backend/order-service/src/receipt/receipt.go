@@ -0,0 +1,202 @@
+package receipt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// Store is the merchant information printed on receipts
+type Store struct {
+	Name        string
+	Location    *time.Location // Store timezone for printed times
+	Header      string         // Printed under the name on customer receipts
+	Footer      string         // Replaces the closing "Thank you!" on customer receipts
+	SocialLinks map[string]string
+}
+
+func (s Store) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// CustomerReceipt renders a paid order's receipt for the guest
+func CustomerReceipt(store Store, order *models.GuestOrder, items []models.OrderItem, width int) []byte {
+	b := NewBuilder(width)
+
+	b.Center().Bold(true).Line(store.Name).Bold(false)
+	paragraph(b, store.Header)
+	if order.QueueNumber != nil {
+		b.Feed(1).Line("Queue number").Double(true).Bold(true).Line(strconv.Itoa(*order.QueueNumber)).Bold(false).Double(false)
+	}
+	b.Left().Rule()
+
+	b.Columns("Order", order.OrderReference)
+	b.Columns("Date", printedTime(order, store.location()))
+	b.Columns("Type", deliveryTypeLabel(order))
+	if order.CustomerName != "" {
+		b.Columns("Customer", order.CustomerName)
+	}
+	b.Rule()
+
+	for _, item := range items {
+		b.Line(item.ProductName)
+		b.Columns(fmt.Sprintf("  %d x %s", item.Quantity, FormatRupiah(item.UnitPrice)), FormatRupiah(item.TotalPrice))
+	}
+	b.Rule()
+
+	b.Columns("Subtotal", FormatRupiah(order.SubtotalAmount))
+	if order.PromotionDiscountAmount > 0 {
+		b.Columns("Promotion", "-"+FormatRupiah(order.PromotionDiscountAmount))
+	}
+	if order.DiscountAmount > 0 {
+		voucher := "Voucher"
+		if order.VoucherCode != nil {
+			voucher += " " + *order.VoucherCode
+		}
+		b.Columns(voucher, "-"+FormatRupiah(order.DiscountAmount))
+	}
+	if order.LoyaltyDiscountAmount > 0 {
+		b.Columns(fmt.Sprintf("Points (%d)", order.LoyaltyPointsRedeemed), "-"+FormatRupiah(order.LoyaltyDiscountAmount))
+	}
+	if order.DeliveryFee > 0 {
+		b.Columns("Delivery fee", FormatRupiah(order.DeliveryFee))
+	}
+	if order.ServiceChargeAmount > 0 {
+		b.Columns(fmt.Sprintf("Service charge %s%%", formatRate(order.ServiceChargeRate)), FormatRupiah(order.ServiceChargeAmount))
+	}
+	if order.TaxAmount > 0 {
+		b.Columns(fmt.Sprintf("Tax %s%%", formatRate(order.TaxRate)), FormatRupiah(order.TaxAmount))
+	}
+	b.Bold(true).Columns("TOTAL", FormatRupiah(order.TotalAmount)).Bold(false)
+	b.Rule()
+
+	b.Center()
+	if strings.TrimSpace(store.Footer) != "" {
+		paragraph(b, store.Footer)
+	} else {
+		b.Line("Thank you!")
+	}
+	if links := socialLinks(store.SocialLinks); len(links) > 0 {
+		b.Feed(1)
+		for _, link := range links {
+			b.Line(link)
+		}
+	}
+	b.Feed(2).Cut()
+	return b.Bytes()
+}
+
+// paragraph prints tenant text line by line, keeping the line breaks it was written with
+func paragraph(b *Builder, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		b.Line(strings.TrimSpace(line))
+	}
+}
+
+// socialLinks returns the links to print, sorted by platform and without their https:// prefix
+func socialLinks(links map[string]string) []string {
+	platforms := make([]string, 0, len(links))
+	for platform := range links {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	printed := make([]string, 0, len(platforms))
+	for _, platform := range platforms {
+		link := strings.TrimPrefix(strings.TrimPrefix(links[platform], "https://"), "www.")
+		if link = strings.TrimSuffix(link, "/"); link != "" {
+			printed = append(printed, link)
+		}
+	}
+	return printed
+}
+
+// KitchenTicket renders a paid order for the kitchen: what to make and where it goes, without prices
+func KitchenTicket(store Store, order *models.GuestOrder, items []models.OrderItem, width int) []byte {
+	b := NewBuilder(width)
+
+	b.Center().Double(true).Bold(true)
+	if order.QueueNumber != nil {
+		b.Line("#" + strconv.Itoa(*order.QueueNumber))
+	}
+	b.Line(deliveryTypeLabel(order)).Bold(false).Double(false)
+	b.Left().Rule()
+
+	b.Columns("Order", order.OrderReference)
+	b.Columns("Paid", printedTime(order, store.location()))
+	if order.ScheduledFor != nil {
+		b.Bold(true).Columns("Due", order.ScheduledFor.In(store.location()).Format("02 Jan 15:04")).Bold(false)
+	}
+	b.Rule()
+
+	b.Double(true)
+	for _, item := range items {
+		b.Line(fmt.Sprintf("%dx %s", item.Quantity, item.ProductName))
+	}
+	b.Double(false)
+
+	if order.Notes != nil && strings.TrimSpace(*order.Notes) != "" {
+		b.Rule().Bold(true).Line("Notes:").Bold(false).Line(*order.Notes)
+	}
+
+	b.Feed(3).Cut()
+	return b.Bytes()
+}
+
+// FormatRupiah formats an amount in rupiah with dot thousands separators, e.g. Rp 25.000
+func FormatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.Itoa(amount)
+	var grouped strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(d)
+	}
+	return sign + "Rp " + grouped.String()
+}
+
+// formatRate prints a percentage without trailing zeros, e.g. 11 or 5.5
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// deliveryTypeLabel names how the order is handed over, with the table for dine-in
+func deliveryTypeLabel(order *models.GuestOrder) string {
+	switch order.DeliveryType {
+	case models.DeliveryTypeDineIn:
+		if order.TableNumber != nil && *order.TableNumber != "" {
+			return "Table " + *order.TableNumber
+		}
+		return "Dine in"
+	case models.DeliveryTypeDelivery:
+		return "Delivery"
+	}
+	return "Pickup"
+}
+
+// printedTime is when the order was paid, in the store's timezone
+func printedTime(order *models.GuestOrder, loc *time.Location) string {
+	at := order.CreatedAt
+	if order.PaidAt != nil {
+		at = *order.PaidAt
+	}
+	return at.In(loc).Format("02 Jan 2006 15:04")
+}
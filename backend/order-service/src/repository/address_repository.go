@@ -55,10 +55,18 @@ func (r *AddressRepository) decryptToStringPtrWithContext(ctx context.Context, e
 	return &decrypted, nil
 }
 
-// Create creates a new delivery address record with encrypted PII
+// addressCoordinate stores an unknown coordinate as NULL rather than 0
+func addressCoordinate(address *models.DeliveryAddress, value float64) *float64 {
+	if !address.HasCoordinates() {
+		return nil
+	}
+	return &value
+}
+
+// Create creates a new delivery address record with encrypted PII within tx
 // Encrypts: FullAddress, GeocodingResult
 // Note: Latitude/Longitude remain plaintext for geocoding queries
-func (r *AddressRepository) Create(ctx context.Context, address *models.DeliveryAddress) error {
+func (r *AddressRepository) Create(ctx context.Context, tx *sql.Tx, address *models.DeliveryAddress) error {
 	// Encrypt PII fields with context
 	encryptedAddress, err := r.encryptor.EncryptWithContext(ctx, address.FullAddress, "delivery_address:full_address")
 	if err != nil {
@@ -72,31 +80,25 @@ func (r *AddressRepository) Create(ctx context.Context, address *models.Delivery
 
 	query := `
 		INSERT INTO delivery_addresses (
-			id, order_id, tenant_id, full_address, latitude, longitude,
-			geocoding_result, service_area_validated, calculated_fee,
-			distance_km, zone_id, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			order_id, address_text, latitude, longitude,
+			geocoded_address, is_serviceable, calculated_delivery_fee,
+			distance_km, delivery_zone_id, service_area_zone, geocoded_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, CASE WHEN $3::numeric IS NULL THEN NULL ELSE NOW() END)
+		RETURNING id, created_at, updated_at
 	`
 
-	now := time.Now()
-	address.CreatedAt = now
-	address.UpdatedAt = now
-
-	_, err = r.db.ExecContext(ctx, query,
-		address.ID,
+	err = tx.QueryRowContext(ctx, query,
 		address.OrderID,
-		address.TenantID,
 		encryptedAddress,
-		address.Latitude,
-		address.Longitude,
+		addressCoordinate(address, address.Latitude),
+		addressCoordinate(address, address.Longitude),
 		encryptedGeocodingResult,
 		address.ServiceAreaValidated,
 		address.CalculatedFee,
 		address.DistanceKm,
 		address.ZoneID,
-		address.CreatedAt,
-		address.UpdatedAt,
-	)
+		address.ZoneName,
+	).Scan(&address.ID, &address.CreatedAt, &address.UpdatedAt)
 
 	if err != nil {
 		log.Error().
@@ -120,11 +122,14 @@ func (r *AddressRepository) Create(ctx context.Context, address *models.Delivery
 // GetByOrderID retrieves a delivery address by order ID with decrypted PII
 func (r *AddressRepository) GetByOrderID(ctx context.Context, orderID string) (*models.DeliveryAddress, error) {
 	query := `
-		SELECT id, order_id, tenant_id, full_address, latitude, longitude,
-		       geocoding_result, service_area_validated, calculated_fee,
-		       distance_km, zone_id, created_at, updated_at
-		FROM delivery_addresses
-		WHERE order_id = $1
+		SELECT a.id, a.order_id, o.tenant_id, a.address_text, COALESCE(a.latitude, 0), COALESCE(a.longitude, 0),
+		       COALESCE(a.geocoded_address, ''), a.is_serviceable, COALESCE(a.calculated_delivery_fee, 0),
+		       a.distance_km, a.delivery_zone_id, a.service_area_zone, a.created_at, a.updated_at
+		FROM delivery_addresses a
+		JOIN guest_orders o ON o.id = a.order_id
+		WHERE a.order_id = $1
+		ORDER BY a.created_at DESC
+		LIMIT 1
 	`
 
 	var address models.DeliveryAddress
@@ -142,6 +147,7 @@ func (r *AddressRepository) GetByOrderID(ctx context.Context, orderID string) (*
 		&address.CalculatedFee,
 		&address.DistanceKm,
 		&address.ZoneID,
+		&address.ZoneName,
 		&address.CreatedAt,
 		&address.UpdatedAt,
 	)
@@ -187,29 +193,31 @@ func (r *AddressRepository) Update(ctx context.Context, address *models.Delivery
 
 	query := `
 		UPDATE delivery_addresses
-		SET full_address = $1,
+		SET address_text = $1,
 		    latitude = $2,
 		    longitude = $3,
-		    geocoding_result = $4,
-		    service_area_validated = $5,
-		    calculated_fee = $6,
+		    geocoded_address = NULLIF($4, ''),
+		    is_serviceable = $5,
+		    calculated_delivery_fee = $6,
 		    distance_km = $7,
-		    zone_id = $8,
-		    updated_at = $9
-		WHERE id = $10
+		    delivery_zone_id = $8,
+		    service_area_zone = $9,
+		    updated_at = $10
+		WHERE id = $11
 	`
 
 	address.UpdatedAt = time.Now()
 
 	result, err := r.db.ExecContext(ctx, query,
 		encryptedAddress,
-		address.Latitude,
-		address.Longitude,
+		addressCoordinate(address, address.Latitude),
+		addressCoordinate(address, address.Longitude),
 		encryptedGeocodingResult,
 		address.ServiceAreaValidated,
 		address.CalculatedFee,
 		address.DistanceKm,
 		address.ZoneID,
+		address.ZoneName,
 		address.UpdatedAt,
 		address.ID,
 	)
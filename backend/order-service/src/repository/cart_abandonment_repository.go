@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// idleCartsKey is a sorted set of carts with a reminder contact, scored by last change
+// CartRepository.Save refreshes the score of carts already in the set.
+const idleCartsKey = "cart_abandonment:idle"
+
+// IdleCart is a cart with a reminder contact that has not changed since a cutoff
+type IdleCart struct {
+	TenantID string
+	CartID   string
+}
+
+// CartAbandonmentRepository stores the reminder contacts of carts and the snapshots
+// that recovery links restore. Contacts are encrypted before they reach Redis.
+type CartAbandonmentRepository struct {
+	redis     *redis.Client
+	encryptor utils.Encryptor
+}
+
+// NewCartAbandonmentRepository creates a new cart abandonment repository
+func NewCartAbandonmentRepository(redisClient *redis.Client, encryptor utils.Encryptor) *CartAbandonmentRepository {
+	return &CartAbandonmentRepository{
+		redis:     redisClient,
+		encryptor: encryptor,
+	}
+}
+
+func idleCartMember(tenantID, cartID string) string {
+	return tenantID + ":" + cartID
+}
+
+func cartContactKey(tenantID, cartID string) string {
+	return fmt.Sprintf("cart_contact:%s:%s", tenantID, cartID)
+}
+
+// cartResumeKey keys a snapshot by the hash of its recovery token
+func cartResumeKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("cart_resume:%s", hex.EncodeToString(hash[:]))
+}
+
+// SaveContact stores a cart's reminder contact and starts tracking the cart for inactivity
+func (r *CartAbandonmentRepository) SaveContact(ctx context.Context, tenantID, cartID string, contact *models.CartContact, ttl time.Duration) error {
+	data, err := json.Marshal(contact)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cart contact: %w", err)
+	}
+	encrypted, err := r.encryptor.EncryptWithContext(ctx, string(data), "cart_contact")
+	if err != nil {
+		return fmt.Errorf("failed to encrypt cart contact: %w", err)
+	}
+
+	_, err = r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, cartContactKey(tenantID, cartID), encrypted, ttl)
+		pipe.ZAdd(ctx, idleCartsKey, redis.Z{
+			Score:  float64(time.Now().Unix()),
+			Member: idleCartMember(tenantID, cartID),
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save cart contact: %w", err)
+	}
+	return nil
+}
+
+// GetContact returns a cart's reminder contact, or nil if it has none
+func (r *CartAbandonmentRepository) GetContact(ctx context.Context, tenantID, cartID string) (*models.CartContact, error) {
+	encrypted, err := r.redis.Get(ctx, cartContactKey(tenantID, cartID)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart contact: %w", err)
+	}
+
+	data, err := r.encryptor.DecryptWithContext(ctx, encrypted, "cart_contact")
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt cart contact: %w", err)
+	}
+	var contact models.CartContact
+	if err := json.Unmarshal([]byte(data), &contact); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cart contact: %w", err)
+	}
+	return &contact, nil
+}
+
+// ListIdle returns tracked carts that have not changed since before
+func (r *CartAbandonmentRepository) ListIdle(ctx context.Context, before time.Time, limit int) ([]IdleCart, error) {
+	members, err := r.redis.ZRangeByScore(ctx, idleCartsKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(before.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list idle carts: %w", err)
+	}
+
+	carts := make([]IdleCart, 0, len(members))
+	for _, member := range members {
+		tenantID, cartID, ok := strings.Cut(member, ":")
+		if !ok {
+			continue
+		}
+		carts = append(carts, IdleCart{TenantID: tenantID, CartID: cartID})
+	}
+	return carts, nil
+}
+
+// Forget stops tracking a cart and drops its reminder contact
+func (r *CartAbandonmentRepository) Forget(ctx context.Context, tenantID, cartID string) error {
+	_, err := r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, idleCartsKey, idleCartMember(tenantID, cartID))
+		pipe.Del(ctx, cartContactKey(tenantID, cartID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to forget cart: %w", err)
+	}
+	return nil
+}
+
+// SaveResume stores the snapshot a recovery link restores and returns the link's token
+func (r *CartAbandonmentRepository) SaveResume(ctx context.Context, resume *models.CartResume) (string, error) {
+	token, err := utils.GenerateTableQRToken()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(resume)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cart snapshot: %w", err)
+	}
+	if err := r.redis.Set(ctx, cartResumeKey(token), data, models.CartResumeTTL).Err(); err != nil {
+		return "", fmt.Errorf("failed to save cart snapshot: %w", err)
+	}
+	return token, nil
+}
+
+// GetResume returns the snapshot behind a recovery link
+func (r *CartAbandonmentRepository) GetResume(ctx context.Context, token string) (*models.CartResume, error) {
+	if token == "" {
+		return nil, models.ErrCartResumeNotFound
+	}
+	data, err := r.redis.Get(ctx, cartResumeKey(token)).Result()
+	if err == redis.Nil {
+		return nil, models.ErrCartResumeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart snapshot: %w", err)
+	}
+
+	var resume models.CartResume
+	if err := json.Unmarshal([]byte(data), &resume); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cart snapshot: %w", err)
+	}
+	return &resume, nil
+}
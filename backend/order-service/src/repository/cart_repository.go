@@ -11,17 +11,30 @@ import (
 )
 
 type CartRepository struct {
-	redis *redis.Client
-	ttl   time.Duration
+	redis       *redis.Client
+	ttl         time.Duration
+	customerTTL time.Duration // Carts of identified guests outlive device sessions
 }
 
-func NewCartRepository(redisClient *redis.Client, ttl time.Duration) *CartRepository {
+func NewCartRepository(redisClient *redis.Client, ttl, customerTTL time.Duration) *CartRepository {
+	if customerTTL < ttl {
+		customerTTL = ttl
+	}
 	return &CartRepository{
-		redis: redisClient,
-		ttl:   ttl,
+		redis:       redisClient,
+		ttl:         ttl,
+		customerTTL: customerTTL,
 	}
 }
 
+// TTLFor returns how long a cart is kept after its last change
+func (r *CartRepository) TTLFor(sessionID string) time.Duration {
+	if models.IsCustomerCartID(sessionID) {
+		return r.customerTTL
+	}
+	return r.ttl
+}
+
 func (r *CartRepository) GetCartKey(tenantID, sessionID string) string {
 	return fmt.Sprintf("cart:%s:%s", tenantID, sessionID)
 }
@@ -54,7 +67,16 @@ func (r *CartRepository) Save(ctx context.Context, cart *models.Cart) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal cart: %w", err)
 	}
-	if err := r.redis.Set(ctx, key, data, r.ttl).Err(); err != nil {
+	_, err = r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, r.TTLFor(cart.SessionID))
+		// Restart the inactivity clock of carts tracked for abandonment
+		pipe.ZAddXX(ctx, idleCartsKey, redis.Z{
+			Score:  float64(time.Now().Unix()),
+			Member: idleCartMember(cart.TenantID, cart.SessionID),
+		})
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save cart to redis: %w", err)
 	}
 	return nil
@@ -70,7 +92,7 @@ func (r *CartRepository) Delete(ctx context.Context, tenantID, sessionID string)
 
 func (r *CartRepository) Extend(ctx context.Context, tenantID, sessionID string) error {
 	key := r.GetCartKey(tenantID, sessionID)
-	if err := r.redis.Expire(ctx, key, r.ttl).Err(); err != nil {
+	if err := r.redis.Expire(ctx, key, r.TTLFor(sessionID)).Err(); err != nil {
 		return fmt.Errorf("failed to extend cart TTL: %w", err)
 	}
 	return nil
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// CashierShiftRepository handles cashier shifts and the cash refunds paid from their drawers
+type CashierShiftRepository struct {
+	db *sql.DB
+}
+
+// NewCashierShiftRepository creates a new cashier shift repository
+func NewCashierShiftRepository(db *sql.DB) *CashierShiftRepository {
+	return &CashierShiftRepository{db: db}
+}
+
+const cashierShiftColumns = `
+	id, tenant_id, cashier_id, cashier_name, status, opening_float, cash_sales, cash_refunds,
+	expected_cash, counted_cash, variance, close_note, closed_by, closed_by_name, opened_at, closed_at`
+
+func scanCashierShift(row interface{ Scan(...interface{}) error }) (*models.CashierShift, error) {
+	var shift models.CashierShift
+	if err := row.Scan(
+		&shift.ID,
+		&shift.TenantID,
+		&shift.CashierID,
+		&shift.CashierName,
+		&shift.Status,
+		&shift.OpeningFloat,
+		&shift.CashSales,
+		&shift.CashRefunds,
+		&shift.ExpectedCash,
+		&shift.CountedCash,
+		&shift.Variance,
+		&shift.CloseNote,
+		&shift.ClosedBy,
+		&shift.ClosedByName,
+		&shift.OpenedAt,
+		&shift.ClosedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// Open stores a new open shift
+// Returns ErrShiftAlreadyOpen when the cashier already has one.
+func (r *CashierShiftRepository) Open(ctx context.Context, shift *models.CashierShift) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO cashier_shifts (tenant_id, cashier_id, cashier_name, status, opening_float, expected_cash)
+		VALUES ($1, $2, $3, 'open', $4, $4)
+		RETURNING id, status, opened_at
+	`, shift.TenantID, shift.CashierID, shift.CashierName, shift.OpeningFloat).Scan(&shift.ID, &shift.Status, &shift.OpenedAt)
+	if isUniqueViolation(err) {
+		return models.ErrShiftAlreadyOpen
+	}
+	return err
+}
+
+// Get returns one of a tenant's shifts
+// With a transaction the shift is locked until it ends.
+func (r *CashierShiftRepository) Get(ctx context.Context, tx *sql.Tx, tenantID, shiftID string) (*models.CashierShift, error) {
+	query := `SELECT ` + cashierShiftColumns + ` FROM cashier_shifts WHERE id = $1 AND tenant_id = $2`
+
+	var row *sql.Row
+	if tx != nil {
+		row = tx.QueryRowContext(ctx, query+` FOR UPDATE`, shiftID, tenantID)
+	} else {
+		row = r.db.QueryRowContext(ctx, query, shiftID, tenantID)
+	}
+
+	shift, err := scanCashierShift(row)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrShiftNotFound
+	}
+	return shift, err
+}
+
+// GetOpen returns the cashier's open shift
+// With a transaction the shift is locked until it ends.
+func (r *CashierShiftRepository) GetOpen(ctx context.Context, tx *sql.Tx, tenantID, cashierID string) (*models.CashierShift, error) {
+	query := `SELECT ` + cashierShiftColumns + ` FROM cashier_shifts WHERE tenant_id = $1 AND cashier_id = $2 AND status = 'open'`
+
+	var row *sql.Row
+	if tx != nil {
+		row = tx.QueryRowContext(ctx, query+` FOR UPDATE`, tenantID, cashierID)
+	} else {
+		row = r.db.QueryRowContext(ctx, query, tenantID, cashierID)
+	}
+
+	shift, err := scanCashierShift(row)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNoOpenShift
+	}
+	return shift, err
+}
+
+// List returns a tenant's shifts, newest first, optionally for one cashier or status
+func (r *CashierShiftRepository) List(ctx context.Context, tenantID string, cashierID *string, status *models.CashierShiftStatus, limit, offset int) ([]*models.CashierShift, error) {
+	query := `SELECT ` + cashierShiftColumns + ` FROM cashier_shifts WHERE tenant_id = $1`
+	args := []interface{}{tenantID}
+	if cashierID != nil {
+		args = append(args, *cashierID)
+		query += fmt.Sprintf(` AND cashier_id = $%d`, len(args))
+	}
+	if status != nil {
+		args = append(args, string(*status))
+		query += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+	query += fmt.Sprintf(` ORDER BY opened_at DESC LIMIT %d OFFSET %d`, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shifts := []*models.CashierShift{}
+	for rows.Next() {
+		shift, err := scanCashierShift(rows)
+		if err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, shift)
+	}
+	return shifts, rows.Err()
+}
+
+// GetCashSales totals the cash payments a cashier recorded during a shift, net of change
+// An open shift has no end yet; every payment since it opened counts.
+func (r *CashierShiftRepository) GetCashSales(ctx context.Context, tx *sql.Tx, shift *models.CashierShift) (total, count int, err error) {
+	query := `
+		SELECT COALESCE(SUM(pr.amount_paid), 0), COUNT(*)
+		FROM payment_records pr
+		JOIN guest_orders o ON o.id = pr.order_id
+		WHERE o.tenant_id = $1
+		  AND pr.recorded_by_user_id = $2
+		  AND pr.payment_method = 'cash'
+		  AND pr.payment_date >= $3
+		  AND ($4::timestamp IS NULL OR pr.payment_date < $4)`
+	args := []interface{}{shift.TenantID, shift.CashierID, shift.OpenedAt, shift.ClosedAt}
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&total, &count)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, args...).Scan(&total, &count)
+	}
+	return total, count, err
+}
+
+// GetCashRefundTotal totals the cash refunds paid from a shift's drawer
+func (r *CashierShiftRepository) GetCashRefundTotal(ctx context.Context, tx *sql.Tx, shiftID string) (int, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM cash_refunds WHERE shift_id = $1`
+
+	var total int
+	var err error
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, query, shiftID).Scan(&total)
+	} else {
+		err = r.db.QueryRowContext(ctx, query, shiftID).Scan(&total)
+	}
+	return total, err
+}
+
+// GetOrderCashBalance returns the cash paid for an order and the cash already refunded for it
+func (r *CashierShiftRepository) GetOrderCashBalance(ctx context.Context, tx *sql.Tx, orderID string) (paid, refunded int, err error) {
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COALESCE(SUM(amount_paid), 0) FROM payment_records WHERE order_id = $1 AND payment_method = 'cash'),
+			(SELECT COALESCE(SUM(amount), 0) FROM cash_refunds WHERE order_id = $1)
+	`, orderID).Scan(&paid, &refunded)
+	return paid, refunded, err
+}
+
+// AddCashRefund records cash paid back from a shift's drawer
+func (r *CashierShiftRepository) AddCashRefund(ctx context.Context, tx *sql.Tx, refund *models.CashRefund) error {
+	return tx.QueryRowContext(ctx, `
+		INSERT INTO cash_refunds (shift_id, order_id, amount, reason, recorded_by, recorded_by_name)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, refund.ShiftID, refund.OrderID, refund.Amount, refund.Reason, refund.RecordedBy, refund.RecordedByName,
+	).Scan(&refund.ID, &refund.CreatedAt)
+}
+
+// ListCashRefunds returns the cash refunds paid from a shift's drawer, oldest first
+func (r *CashierShiftRepository) ListCashRefunds(ctx context.Context, shiftID string) ([]models.CashRefund, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cr.id, cr.shift_id, cr.order_id, o.order_reference, cr.amount, cr.reason,
+		       cr.recorded_by, cr.recorded_by_name, cr.created_at
+		FROM cash_refunds cr
+		JOIN guest_orders o ON o.id = cr.order_id
+		WHERE cr.shift_id = $1
+		ORDER BY cr.created_at
+	`, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := []models.CashRefund{}
+	for rows.Next() {
+		var refund models.CashRefund
+		if err := rows.Scan(
+			&refund.ID,
+			&refund.ShiftID,
+			&refund.OrderID,
+			&refund.OrderReference,
+			&refund.Amount,
+			&refund.Reason,
+			&refund.RecordedBy,
+			&refund.RecordedByName,
+			&refund.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	return refunds, rows.Err()
+}
+
+// Close stores a shift's frozen cash totals and count
+func (r *CashierShiftRepository) Close(ctx context.Context, tx *sql.Tx, shift *models.CashierShift, closedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE cashier_shifts
+		SET status = 'closed',
+		    cash_sales = $2,
+		    cash_refunds = $3,
+		    expected_cash = $4,
+		    counted_cash = $5,
+		    variance = $6,
+		    close_note = $7,
+		    closed_by = $8,
+		    closed_by_name = $9,
+		    closed_at = $10
+		WHERE id = $1
+	`, shift.ID, shift.CashSales, shift.CashRefunds, shift.ExpectedCash, shift.CountedCash, shift.Variance,
+		shift.CloseNote, shift.ClosedBy, shift.ClosedByName, closedAt)
+	if err != nil {
+		return err
+	}
+	shift.Status = models.CashierShiftClosed
+	shift.ClosedAt = &closedAt
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// ChargeRetryRepository handles online charges queued during a payment gateway outage
+type ChargeRetryRepository struct {
+	db *sql.DB
+}
+
+// NewChargeRetryRepository creates a new charge retry repository
+func NewChargeRetryRepository(db *sql.DB) *ChargeRetryRepository {
+	return &ChargeRetryRepository{db: db}
+}
+
+// Queue schedules the first retry of an order's charge
+// Must be called within the checkout transaction that placed the order
+func (r *ChargeRetryRepository) Queue(ctx context.Context, tx *sql.Tx, retry *models.PaymentChargeRetry) error {
+	query := `
+		INSERT INTO payment_charge_retries (order_id, tenant_id, payment_method, next_attempt_at, expires_at, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (order_id) DO NOTHING
+		RETURNING created_at
+	`
+	err := tx.QueryRowContext(ctx, query,
+		retry.OrderID,
+		retry.TenantID,
+		retry.PaymentMethod,
+		retry.NextAttemptAt,
+		retry.ExpiresAt,
+		retry.LastError,
+	).Scan(&retry.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil // Already queued
+	}
+	return err
+}
+
+// ClaimDue takes up to limit retries that are due and counts the attempt
+// Claimed retries are pushed back by lease so another replica does not pick them up while
+// the gateway is being called; the caller reschedules or deletes them afterwards.
+func (r *ChargeRetryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.PaymentChargeRetry, error) {
+	query := `
+		UPDATE payment_charge_retries
+		SET attempts = attempts + 1,
+		    next_attempt_at = $2
+		WHERE order_id IN (
+			SELECT order_id FROM payment_charge_retries
+			WHERE next_attempt_at <= $1 AND expires_at > $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING order_id, tenant_id, payment_method, attempts, next_attempt_at, expires_at, last_error, created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var retries []*models.PaymentChargeRetry
+	for rows.Next() {
+		retry := &models.PaymentChargeRetry{}
+		if err := rows.Scan(
+			&retry.OrderID,
+			&retry.TenantID,
+			&retry.PaymentMethod,
+			&retry.Attempts,
+			&retry.NextAttemptAt,
+			&retry.ExpiresAt,
+			&retry.LastError,
+			&retry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		retries = append(retries, retry)
+	}
+
+	return retries, rows.Err()
+}
+
+// Reschedule records a failed attempt and when to try again
+func (r *ChargeRetryRepository) Reschedule(ctx context.Context, orderID string, nextAttemptAt time.Time, lastError string) error {
+	query := `
+		UPDATE payment_charge_retries
+		SET next_attempt_at = $2, last_error = $3
+		WHERE order_id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, orderID, nextAttemptAt, lastError)
+	return err
+}
+
+// Delete removes an order's retry once its charge exists or is no longer needed
+func (r *ChargeRetryRepository) Delete(ctx context.Context, tx *sql.Tx, orderID string) error {
+	query := `DELETE FROM payment_charge_retries WHERE order_id = $1`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, query, orderID)
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, query, orderID)
+	return err
+}
+
+// DeleteStale removes retries that expired or whose order is no longer awaiting payment
+func (r *ChargeRetryRepository) DeleteStale(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		DELETE FROM payment_charge_retries cr
+		USING guest_orders o
+		WHERE o.id = cr.order_id
+		  AND (cr.expires_at <= $1 OR o.status <> 'PENDING')
+	`
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,462 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// CourierRepository handles courier settings and the couriers booked for delivery orders
+// Every booking status change is mirrored to guest_orders.delivery_status in the same transaction.
+type CourierRepository struct {
+	db *sql.DB
+}
+
+// NewCourierRepository creates a new courier repository
+func NewCourierRepository(db *sql.DB) *CourierRepository {
+	return &CourierRepository{db: db}
+}
+
+// GetSettings returns a tenant's courier settings, or disabled defaults when none are saved
+func (r *CourierRepository) GetSettings(ctx context.Context, tenantID string) (*models.CourierSettings, error) {
+	query := `
+SELECT tenant_id, provider, auto_dispatch, service_type, COALESCE(pickup_name, ''), COALESCE(pickup_phone, ''),
+       COALESCE(pickup_address, ''), pickup_latitude, pickup_longitude, COALESCE(pickup_notes, ''), updated_at
+FROM courier_settings
+WHERE tenant_id = $1
+`
+
+	var settings models.CourierSettings
+	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
+		&settings.TenantID,
+		&settings.Provider,
+		&settings.AutoDispatch,
+		&settings.ServiceType,
+		&settings.PickupName,
+		&settings.PickupPhone,
+		&settings.PickupAddress,
+		&settings.PickupLatitude,
+		&settings.PickupLongitude,
+		&settings.PickupNotes,
+		&settings.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return &models.CourierSettings{
+			TenantID:    tenantID,
+			ServiceType: models.CourierServiceInstant,
+		}, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get courier settings")
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpsertSettings saves a tenant's courier settings
+func (r *CourierRepository) UpsertSettings(ctx context.Context, settings *models.CourierSettings) error {
+	query := `
+INSERT INTO courier_settings (tenant_id, provider, auto_dispatch, service_type, pickup_name, pickup_phone,
+                              pickup_address, pickup_latitude, pickup_longitude, pickup_notes)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $9, NULLIF($10, ''))
+ON CONFLICT (tenant_id) DO UPDATE SET
+    provider = EXCLUDED.provider,
+    auto_dispatch = EXCLUDED.auto_dispatch,
+    service_type = EXCLUDED.service_type,
+    pickup_name = EXCLUDED.pickup_name,
+    pickup_phone = EXCLUDED.pickup_phone,
+    pickup_address = EXCLUDED.pickup_address,
+    pickup_latitude = EXCLUDED.pickup_latitude,
+    pickup_longitude = EXCLUDED.pickup_longitude,
+    pickup_notes = EXCLUDED.pickup_notes,
+    updated_at = NOW()
+RETURNING updated_at
+`
+
+	err := r.db.QueryRowContext(ctx, query,
+		settings.TenantID,
+		settings.Provider,
+		settings.AutoDispatch,
+		settings.ServiceType,
+		settings.PickupName,
+		settings.PickupPhone,
+		settings.PickupAddress,
+		settings.PickupLatitude,
+		settings.PickupLongitude,
+		settings.PickupNotes,
+	).Scan(&settings.UpdatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", settings.TenantID).Msg("Failed to save courier settings")
+		return err
+	}
+	return nil
+}
+
+// courierBookingColumns selects a booking b with its order o
+const courierBookingColumns = `b.id, b.tenant_id, b.order_id, o.order_reference, b.provider, b.service_type, b.status,
+	b.external_id, b.tracking_url, b.driver_name, b.driver_phone, b.vehicle_plate, b.quoted_fee, b.final_fee,
+	b.last_error, b.auto_dispatched, b.booked_at, b.picked_up_at, b.delivered_at, b.cancelled_at, b.created_at, b.updated_at`
+
+func scanCourierBooking(row interface{ Scan(...interface{}) error }) (*models.CourierBooking, error) {
+	var booking models.CourierBooking
+	err := row.Scan(
+		&booking.ID,
+		&booking.TenantID,
+		&booking.OrderID,
+		&booking.OrderReference,
+		&booking.Provider,
+		&booking.ServiceType,
+		&booking.Status,
+		&booking.ExternalID,
+		&booking.TrackingURL,
+		&booking.DriverName,
+		&booking.DriverPhone,
+		&booking.VehiclePlate,
+		&booking.QuotedFee,
+		&booking.FinalFee,
+		&booking.LastError,
+		&booking.AutoDispatched,
+		&booking.BookedAt,
+		&booking.PickedUpAt,
+		&booking.DeliveredAt,
+		&booking.CancelledAt,
+		&booking.CreatedAt,
+		&booking.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// setOrderDeliveryStatus mirrors a booking status onto its order within tx
+func setOrderDeliveryStatus(ctx context.Context, tx *sql.Tx, orderID string, status models.DeliveryStatus) error {
+	_, err := tx.ExecContext(ctx, `UPDATE guest_orders SET delivery_status = $2 WHERE id = $1`, orderID, status)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to update order delivery status")
+	}
+	return err
+}
+
+// CreateBooking records a booking in status requesting before the provider is called
+// Returns models.ErrCourierBookingActive when the order already has a live booking.
+func (r *CourierRepository) CreateBooking(ctx context.Context, booking *models.CourierBooking) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+INSERT INTO courier_bookings (tenant_id, order_id, provider, service_type, auto_dispatched)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, status, created_at, updated_at
+`
+
+	err = tx.QueryRowContext(ctx, query, booking.TenantID, booking.OrderID, booking.Provider, booking.ServiceType, booking.AutoDispatched).
+		Scan(&booking.ID, &booking.Status, &booking.CreatedAt, &booking.UpdatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrCourierBookingActive
+	}
+	if err != nil {
+		log.Error().Err(err).Str("order_id", booking.OrderID).Msg("Failed to create courier booking")
+		return err
+	}
+
+	if err := setOrderDeliveryStatus(ctx, tx, booking.OrderID, booking.Status); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MarkBooked stores the provider's booking reference on a requesting booking
+// Returns false when the booking stopped requesting meanwhile, e.g. because it timed out.
+func (r *CourierRepository) MarkBooked(ctx context.Context, booking *models.CourierBooking) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+UPDATE courier_bookings
+SET status = $2, external_id = $3, tracking_url = $4, quoted_fee = $5, booked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status = 'requesting'
+RETURNING booked_at, updated_at
+`
+
+	err = tx.QueryRowContext(ctx, query, booking.ID, booking.Status, booking.ExternalID, booking.TrackingURL, booking.QuotedFee).
+		Scan(&booking.BookedAt, &booking.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", booking.ID).Msg("Failed to mark courier booking as booked")
+		return false, err
+	}
+
+	if err := setOrderDeliveryStatus(ctx, tx, booking.OrderID, booking.Status); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// MarkFailed fails a requesting booking the provider refused or did not answer
+func (r *CourierRepository) MarkFailed(ctx context.Context, booking *models.CourierBooking, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+UPDATE courier_bookings
+SET status = 'failed', last_error = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'requesting'
+`, booking.ID, reason)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", booking.ID).Msg("Failed to mark courier booking as failed")
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil
+	}
+
+	booking.Status = models.DeliveryFailed
+	booking.LastError = &reason
+	if err := setOrderDeliveryStatus(ctx, tx, booking.OrderID, booking.Status); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListByOrder returns an order's bookings, newest first
+func (r *CourierRepository) ListByOrder(ctx context.Context, orderID string) ([]*models.CourierBooking, error) {
+	query := `
+SELECT ` + courierBookingColumns + `
+FROM courier_bookings b
+JOIN guest_orders o ON o.id = b.order_id
+WHERE b.order_id = $1
+ORDER BY b.created_at DESC
+`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to list courier bookings")
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookings := []*models.CourierBooking{}
+	for rows.Next() {
+		booking, err := scanCourierBooking(rows)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, rows.Err()
+}
+
+// GetActiveByOrder returns an order's live booking, or nil when there is none
+func (r *CourierRepository) GetActiveByOrder(ctx context.Context, orderID string) (*models.CourierBooking, error) {
+	query := `
+SELECT ` + courierBookingColumns + `
+FROM courier_bookings b
+JOIN guest_orders o ON o.id = b.order_id
+WHERE b.order_id = $1 AND b.status NOT IN ('cancelled', 'failed')
+`
+
+	booking, err := scanCourierBooking(r.db.QueryRowContext(ctx, query, orderID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to get active courier booking")
+		return nil, err
+	}
+	return booking, nil
+}
+
+// ApplyUpdate applies a provider webhook to the booking it refers to
+// Updates that would move the delivery backwards, or change a finished one, only refresh
+// the driver and tracking details. Returns models.ErrCourierBookingNotFound for unknown
+// bookings and whether the status changed.
+func (r *CourierRepository) ApplyUpdate(ctx context.Context, update *models.CourierUpdate) (*models.CourierBooking, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+SELECT ` + courierBookingColumns + `
+FROM courier_bookings b
+JOIN guest_orders o ON o.id = b.order_id
+WHERE b.provider = $1 AND b.external_id = $2
+FOR UPDATE OF b
+`
+
+	booking, err := scanCourierBooking(tx.QueryRowContext(ctx, query, update.Provider, update.ExternalID))
+	if err == sql.ErrNoRows {
+		return nil, false, models.ErrCourierBookingNotFound
+	}
+	if err != nil {
+		log.Error().Err(err).Str("external_id", update.ExternalID).Msg("Failed to get courier booking")
+		return nil, false, err
+	}
+
+	changed := update.Status != "" && booking.Status.CanMoveTo(update.Status)
+	status := booking.Status
+	if changed {
+		status = update.Status
+	}
+
+	var reason *string
+	if changed && status.IsFinal() && status != models.DeliveryDelivered && update.Reason != "" {
+		reason = &update.Reason
+	}
+	var finalFee *int
+	if update.Fee > 0 {
+		finalFee = &update.Fee
+	}
+
+	err = tx.QueryRowContext(ctx, `
+UPDATE courier_bookings
+SET status = $2,
+    tracking_url = COALESCE(NULLIF($3, ''), tracking_url),
+    driver_name = COALESCE(NULLIF($4, ''), driver_name),
+    driver_phone = COALESCE(NULLIF($5, ''), driver_phone),
+    vehicle_plate = COALESCE(NULLIF($6, ''), vehicle_plate),
+    final_fee = COALESCE($7, final_fee),
+    last_error = COALESCE($8, last_error),
+    picked_up_at = CASE WHEN $2 = 'picked_up' AND picked_up_at IS NULL THEN NOW() ELSE picked_up_at END,
+    delivered_at = CASE WHEN $2 = 'delivered' AND delivered_at IS NULL THEN NOW() ELSE delivered_at END,
+    cancelled_at = CASE WHEN $2 = 'cancelled' AND cancelled_at IS NULL THEN NOW() ELSE cancelled_at END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING tracking_url, driver_name, driver_phone, vehicle_plate, final_fee, last_error,
+          picked_up_at, delivered_at, cancelled_at, updated_at
+`, booking.ID, status, update.TrackingURL, update.DriverName, update.DriverPhone, update.VehiclePlate, finalFee, reason).Scan(
+		&booking.TrackingURL,
+		&booking.DriverName,
+		&booking.DriverPhone,
+		&booking.VehiclePlate,
+		&booking.FinalFee,
+		&booking.LastError,
+		&booking.PickedUpAt,
+		&booking.DeliveredAt,
+		&booking.CancelledAt,
+		&booking.UpdatedAt,
+	)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", booking.ID).Msg("Failed to apply courier update")
+		return nil, false, err
+	}
+	booking.Status = status
+
+	if changed {
+		if err := setOrderDeliveryStatus(ctx, tx, booking.OrderID, status); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return booking, changed, nil
+}
+
+// Cancel marks a live booking as cancelled by staff
+// Returns models.ErrCourierBookingFinished when the booking ended meanwhile.
+func (r *CourierRepository) Cancel(ctx context.Context, booking *models.CourierBooking, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+UPDATE courier_bookings
+SET status = 'cancelled', last_error = $2, cancelled_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status NOT IN ('delivered', 'cancelled', 'failed')
+RETURNING cancelled_at, updated_at
+`, booking.ID, reason).Scan(&booking.CancelledAt, &booking.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrCourierBookingFinished
+	}
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", booking.ID).Msg("Failed to cancel courier booking")
+		return err
+	}
+
+	booking.Status = models.DeliveryCancelled
+	booking.LastError = &reason
+	if err := setOrderDeliveryStatus(ctx, tx, booking.OrderID, booking.Status); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ExpireStaleRequests fails bookings whose booking call never returned, e.g. because the service restarted
+func (r *CourierRepository) ExpireStaleRequests(ctx context.Context, timeout time.Duration) (int64, error) {
+	query := `
+WITH expired AS (
+	UPDATE courier_bookings
+	SET status = 'failed', last_error = 'courier did not answer the booking request', updated_at = NOW()
+	WHERE status = 'requesting' AND created_at < NOW() - ($1 * INTERVAL '1 second')
+	RETURNING order_id
+)
+UPDATE guest_orders o
+SET delivery_status = 'failed'
+FROM expired
+WHERE o.id = expired.order_id
+`
+
+	result, err := r.db.ExecContext(ctx, query, int(timeout.Seconds()))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to expire stale courier bookings")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// FindAutoDispatchable lists paid delivery orders of tenants with automatic dispatch that were never booked
+// Orders paid more than a day ago are left to staff, and scheduled orders wait until leadTime before their slot.
+func (r *CourierRepository) FindAutoDispatchable(ctx context.Context, leadTime time.Duration, limit int) ([]*models.CourierDispatchCandidate, error) {
+	query := `
+SELECT o.tenant_id, o.id
+FROM guest_orders o
+JOIN courier_settings s ON s.tenant_id = o.tenant_id
+WHERE s.auto_dispatch AND s.provider IS NOT NULL
+  AND o.status = 'PAID' AND o.delivery_type = 'delivery'
+  AND o.paid_at > NOW() - INTERVAL '1 day'
+  AND (o.scheduled_for IS NULL OR o.scheduled_for < NOW() + ($1 * INTERVAL '1 second'))
+  AND NOT EXISTS (SELECT 1 FROM courier_bookings b WHERE b.order_id = o.id)
+ORDER BY o.paid_at
+LIMIT $2
+`
+
+	rows, err := r.db.QueryContext(ctx, query, int(leadTime.Seconds()), limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query auto-dispatch candidates")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*models.CourierDispatchCandidate
+	for rows.Next() {
+		var candidate models.CourierDispatchCandidate
+		if err := rows.Scan(&candidate.TenantID, &candidate.OrderID); err != nil {
+			return nil, fmt.Errorf("failed to scan auto-dispatch candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	return candidates, rows.Err()
+}
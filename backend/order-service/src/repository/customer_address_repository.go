@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// CustomerAddressRepository handles the saved delivery addresses of verified guests
+// Addresses are encrypted; guests are found by their phone search hash.
+type CustomerAddressRepository struct {
+	db        *sql.DB
+	encryptor utils.Encryptor
+}
+
+// NewCustomerAddressRepository creates a new saved address repository
+func NewCustomerAddressRepository(db *sql.DB, encryptor utils.Encryptor) *CustomerAddressRepository {
+	return &CustomerAddressRepository{
+		db:        db,
+		encryptor: encryptor,
+	}
+}
+
+const customerAddressColumns = `id, tenant_id, phone_hash, label, address_text, latitude, longitude,
+	COALESCE(geocoded_address, ''), last_used_at, created_at`
+
+func (r *CustomerAddressRepository) scan(ctx context.Context, row interface{ Scan(...interface{}) error }) (*models.CustomerAddress, error) {
+	var address models.CustomerAddress
+	var encryptedAddress, encryptedGeocoded string
+	if err := row.Scan(
+		&address.ID,
+		&address.TenantID,
+		&address.PhoneHash,
+		&address.Label,
+		&encryptedAddress,
+		&address.Latitude,
+		&address.Longitude,
+		&encryptedGeocoded,
+		&address.LastUsedAt,
+		&address.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	var err error
+	address.FullAddress, err = r.encryptor.DecryptWithContext(ctx, encryptedAddress, "customer_address:address_text")
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt address_text: %w", err)
+	}
+	if encryptedGeocoded != "" {
+		geocoded, err := r.encryptor.DecryptWithContext(ctx, encryptedGeocoded, "customer_address:geocoded_address")
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt geocoded_address: %w", err)
+		}
+		address.GeocodedAddress = &geocoded
+	}
+	return &address, nil
+}
+
+// List returns a guest's saved addresses, most recently used first
+func (r *CustomerAddressRepository) List(ctx context.Context, tenantID, phoneHash string) ([]*models.CustomerAddress, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+customerAddressColumns+`
+		FROM customer_addresses
+		WHERE tenant_id = $1 AND phone_hash = $2
+		ORDER BY last_used_at DESC
+	`, tenantID, phoneHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved addresses: %w", err)
+	}
+	defer rows.Close()
+
+	addresses := []*models.CustomerAddress{}
+	for rows.Next() {
+		address, err := r.scan(ctx, rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved address: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+// Get returns one of a guest's saved addresses
+func (r *CustomerAddressRepository) Get(ctx context.Context, tenantID, phoneHash, id string) (*models.CustomerAddress, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+customerAddressColumns+`
+		FROM customer_addresses
+		WHERE id = $1 AND tenant_id = $2 AND phone_hash = $3
+	`, id, tenantID, phoneHash)
+	address, err := r.scan(ctx, row)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrCustomerAddressNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved address: %w", err)
+	}
+	return address, nil
+}
+
+// Count returns how many addresses a guest has saved
+func (r *CustomerAddressRepository) Count(ctx context.Context, tenantID, phoneHash string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM customer_addresses WHERE tenant_id = $1 AND phone_hash = $2
+	`, tenantID, phoneHash).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count saved addresses: %w", err)
+	}
+	return count, nil
+}
+
+// Upsert saves an address, or marks an already saved one as just used
+// A matching address keeps its label unless a new one is given, and takes the new
+// coordinates when it has them.
+func (r *CustomerAddressRepository) Upsert(ctx context.Context, address *models.CustomerAddress) error {
+	encryptedAddress, err := r.encryptor.EncryptWithContext(ctx, address.FullAddress, "customer_address:address_text")
+	if err != nil {
+		return fmt.Errorf("failed to encrypt address_text: %w", err)
+	}
+	encryptedGeocoded := ""
+	if address.GeocodedAddress != nil && *address.GeocodedAddress != "" {
+		encryptedGeocoded, err = r.encryptor.EncryptWithContext(ctx, *address.GeocodedAddress, "customer_address:geocoded_address")
+		if err != nil {
+			return fmt.Errorf("failed to encrypt geocoded_address: %w", err)
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO customer_addresses (
+			tenant_id, phone_hash, address_hash, label, address_text,
+			latitude, longitude, geocoded_address
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))
+		ON CONFLICT (tenant_id, phone_hash, address_hash) DO UPDATE SET
+			label = COALESCE(EXCLUDED.label, customer_addresses.label),
+			address_text = EXCLUDED.address_text,
+			latitude = COALESCE(EXCLUDED.latitude, customer_addresses.latitude),
+			longitude = COALESCE(EXCLUDED.longitude, customer_addresses.longitude),
+			geocoded_address = COALESCE(EXCLUDED.geocoded_address, customer_addresses.geocoded_address),
+			last_used_at = NOW(),
+			updated_at = NOW()
+		RETURNING id, last_used_at, created_at
+	`,
+		address.TenantID,
+		address.PhoneHash,
+		utils.HashForSearch(models.NormalizeAddressText(address.FullAddress)),
+		address.Label,
+		encryptedAddress,
+		address.Latitude,
+		address.Longitude,
+		encryptedGeocoded,
+	).Scan(&address.ID, &address.LastUsedAt, &address.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save address: %w", err)
+	}
+	return nil
+}
+
+// Exists reports whether a guest already saved an address
+func (r *CustomerAddressRepository) Exists(ctx context.Context, tenantID, phoneHash, fullAddress string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM customer_addresses
+			WHERE tenant_id = $1 AND phone_hash = $2 AND address_hash = $3
+		)
+	`, tenantID, phoneHash, utils.HashForSearch(models.NormalizeAddressText(fullAddress))).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up saved address: %w", err)
+	}
+	return exists, nil
+}
+
+// MarkUsed moves a saved address to the top of the guest's list
+func (r *CustomerAddressRepository) MarkUsed(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE customer_addresses SET last_used_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark saved address used: %w", err)
+	}
+	return nil
+}
+
+// Delete removes one of a guest's saved addresses
+func (r *CustomerAddressRepository) Delete(ctx context.Context, tenantID, phoneHash, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM customer_addresses WHERE id = $1 AND tenant_id = $2 AND phone_hash = $3
+	`, id, tenantID, phoneHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved address: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return models.ErrCustomerAddressNotFound
+	}
+	return nil
+}
+
+// TrimToLimit drops a guest's least recently used addresses beyond the limit
+func (r *CustomerAddressRepository) TrimToLimit(ctx context.Context, tenantID, phoneHash string, limit int) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM customer_addresses
+		WHERE tenant_id = $1 AND phone_hash = $2
+		  AND id NOT IN (
+			SELECT id FROM customer_addresses
+			WHERE tenant_id = $1 AND phone_hash = $2
+			ORDER BY last_used_at DESC
+			LIMIT $3
+		  )
+	`, tenantID, phoneHash, limit)
+	if err != nil {
+		return fmt.Errorf("failed to trim saved addresses: %w", err)
+	}
+	return nil
+}
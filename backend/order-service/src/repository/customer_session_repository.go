@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// CustomerSessionRepository stores the sessions of guests who verified their phone
+// Sessions hold the phone's search hash, never the number itself.
+type CustomerSessionRepository struct {
+	redis *redis.Client
+}
+
+// NewCustomerSessionRepository creates a new customer session repository
+func NewCustomerSessionRepository(redisClient *redis.Client) *CustomerSessionRepository {
+	return &CustomerSessionRepository{redis: redisClient}
+}
+
+// customerSessionKey keys a session by the hash of its token
+func customerSessionKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("customer_session:%s", hex.EncodeToString(hash[:]))
+}
+
+// Create opens a session for a tenant's guest identified by phone search hash
+func (r *CustomerSessionRepository) Create(ctx context.Context, tenantID, phoneHash string) (*models.CustomerSession, error) {
+	token, err := utils.GenerateTableQRToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.redis.Set(ctx, customerSessionKey(token), tenantID+":"+phoneHash, models.CustomerSessionTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store customer session: %w", err)
+	}
+	return &models.CustomerSession{
+		Token:     token,
+		ExpiresAt: time.Now().Add(models.CustomerSessionTTL),
+	}, nil
+}
+
+// Resolve returns the phone search hash of a tenant's customer session
+func (r *CustomerSessionRepository) Resolve(ctx context.Context, tenantID, token string) (string, error) {
+	if token == "" {
+		return "", models.ErrCustomerSessionRequired
+	}
+
+	value, err := r.redis.Get(ctx, customerSessionKey(token)).Result()
+	if err == redis.Nil {
+		return "", models.ErrCustomerSessionRequired
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get customer session: %w", err)
+	}
+
+	sessionTenantID, phoneHash, ok := strings.Cut(value, ":")
+	if !ok || sessionTenantID != tenantID {
+		return "", models.ErrCustomerSessionRequired
+	}
+	return phoneHash, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// DeliveryProofRepository handles proofs of delivery; recipient names are encrypted
+type DeliveryProofRepository struct {
+	db        *sql.DB
+	encryptor utils.Encryptor
+}
+
+// NewDeliveryProofRepository creates a new proof of delivery repository
+func NewDeliveryProofRepository(db *sql.DB, encryptor utils.Encryptor) *DeliveryProofRepository {
+	return &DeliveryProofRepository{
+		db:        db,
+		encryptor: encryptor,
+	}
+}
+
+// Create saves a proof of delivery; proof.ID must be set, as it is part of the photo's storage key
+// photo is nil when no photo was taken. Returns ErrDeliveryProofExists when the order already has one.
+func (r *DeliveryProofRepository) Create(ctx context.Context, tenantID string, proof *models.DeliveryProof, photo *models.NoteAttachmentUpload) error {
+	encryptedName, err := r.encryptor.EncryptWithContext(ctx, proof.RecipientName, "delivery_proof:recipient_name")
+	if err != nil {
+		return fmt.Errorf("failed to encrypt recipient_name: %w", err)
+	}
+
+	var contentType *string
+	var size *int64
+	if photo != nil {
+		contentType = &photo.ContentType
+		size = &photo.Size
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO delivery_proofs (
+			id, tenant_id, order_id, recipient_name, photo_storage_key, photo_content_type, photo_size_bytes,
+			captured_by_user_id, captured_by_name
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING delivered_at
+	`, proof.ID, tenantID, proof.OrderID, encryptedName, proof.PhotoStorageKey, contentType, size,
+		proof.CapturedByUserID, proof.CapturedByName,
+	).Scan(&proof.DeliveredAt)
+	if isUniqueViolation(err) {
+		return models.ErrDeliveryProofExists
+	}
+	return err
+}
+
+// GetByOrderID returns an order's proof of delivery, or nil when it has none
+func (r *DeliveryProofRepository) GetByOrderID(ctx context.Context, orderID string) (*models.DeliveryProof, error) {
+	var proof models.DeliveryProof
+	var encryptedName string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, order_id, recipient_name, photo_storage_key, captured_by_user_id, captured_by_name, delivered_at
+		FROM delivery_proofs
+		WHERE order_id = $1
+	`, orderID).Scan(
+		&proof.ID,
+		&proof.OrderID,
+		&encryptedName,
+		&proof.PhotoStorageKey,
+		&proof.CapturedByUserID,
+		&proof.CapturedByName,
+		&proof.DeliveredAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	proof.RecipientName, err = r.encryptor.DecryptWithContext(ctx, encryptedName, "delivery_proof:recipient_name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt recipient_name: %w", err)
+	}
+	return &proof, nil
+}
+
+// DeleteByOrderID removes an order's proof of delivery within tx and returns its photo's storage key
+// Returns nil when the order had no proof or the proof had no photo.
+func (r *DeliveryProofRepository) DeleteByOrderID(ctx context.Context, tx *sql.Tx, orderID string) (*string, error) {
+	var storageKey *string
+	err := tx.QueryRowContext(ctx, `
+		DELETE FROM delivery_proofs
+		WHERE order_id = $1
+		RETURNING photo_storage_key
+	`, orderID).Scan(&storageKey)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return storageKey, err
+}
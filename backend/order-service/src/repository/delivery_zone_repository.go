@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// DeliveryZoneRepository handles database operations for delivery zones
+type DeliveryZoneRepository struct {
+	db *sql.DB
+}
+
+// NewDeliveryZoneRepository creates a new delivery zone repository
+func NewDeliveryZoneRepository(db *sql.DB) *DeliveryZoneRepository {
+	return &DeliveryZoneRepository{db: db}
+}
+
+const deliveryZoneColumns = `
+	id, tenant_id, name, zone_type, center_latitude, center_longitude,
+	min_radius_km, max_radius_km, polygon, fee, priority, is_active, created_at, updated_at`
+
+func scanDeliveryZone(row interface{ Scan(...interface{}) error }) (*models.DeliveryZone, error) {
+	var z models.DeliveryZone
+	var polygon []byte
+	err := row.Scan(
+		&z.ID,
+		&z.TenantID,
+		&z.Name,
+		&z.Type,
+		&z.CenterLatitude,
+		&z.CenterLongitude,
+		&z.MinRadiusKm,
+		&z.MaxRadiusKm,
+		&polygon,
+		&z.Fee,
+		&z.Priority,
+		&z.IsActive,
+		&z.CreatedAt,
+		&z.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(polygon) > 0 {
+		if err := json.Unmarshal(polygon, &z.Polygon); err != nil {
+			return nil, fmt.Errorf("failed to decode polygon of zone %s: %w", z.ID, err)
+		}
+	}
+	return &z, nil
+}
+
+// encodePolygon stores polygon vertices as JSONB, or NULL for radius zones
+func encodePolygon(points []models.LatLng) (interface{}, error) {
+	if len(points) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(points)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// Create inserts a new delivery zone
+func (r *DeliveryZoneRepository) Create(ctx context.Context, tenantID string, req *models.DeliveryZoneRequest) (*models.DeliveryZone, error) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	polygon, err := encodePolygon(req.Polygon)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO delivery_zones (
+			tenant_id, name, zone_type, center_latitude, center_longitude,
+			min_radius_km, max_radius_km, polygon, fee, priority, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + deliveryZoneColumns
+
+	zone, err := scanDeliveryZone(r.db.QueryRowContext(ctx, query,
+		tenantID,
+		req.Name,
+		req.Type,
+		req.CenterLatitude,
+		req.CenterLongitude,
+		req.MinRadiusKm,
+		req.MaxRadiusKm,
+		polygon,
+		req.Fee,
+		req.Priority,
+		isActive,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, models.ErrDeliveryZoneNameExists
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("name", req.Name).Msg("Failed to create delivery zone")
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// Update replaces a delivery zone's settings
+func (r *DeliveryZoneRepository) Update(ctx context.Context, tenantID, zoneID string, req *models.DeliveryZoneRequest) (*models.DeliveryZone, error) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	polygon, err := encodePolygon(req.Polygon)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE delivery_zones
+		SET name = $3,
+			zone_type = $4,
+			center_latitude = $5,
+			center_longitude = $6,
+			min_radius_km = $7,
+			max_radius_km = $8,
+			polygon = $9,
+			fee = $10,
+			priority = $11,
+			is_active = $12,
+			updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING ` + deliveryZoneColumns
+
+	zone, err := scanDeliveryZone(r.db.QueryRowContext(ctx, query,
+		zoneID,
+		tenantID,
+		req.Name,
+		req.Type,
+		req.CenterLatitude,
+		req.CenterLongitude,
+		req.MinRadiusKm,
+		req.MaxRadiusKm,
+		polygon,
+		req.Fee,
+		req.Priority,
+		isActive,
+	))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDeliveryZoneNotFound
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, models.ErrDeliveryZoneNameExists
+		}
+		log.Error().Err(err).Str("zone_id", zoneID).Msg("Failed to update delivery zone")
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// GetByID returns one of a tenant's delivery zones
+func (r *DeliveryZoneRepository) GetByID(ctx context.Context, tenantID, zoneID string) (*models.DeliveryZone, error) {
+	query := `SELECT ` + deliveryZoneColumns + ` FROM delivery_zones WHERE id = $1 AND tenant_id = $2`
+
+	zone, err := scanDeliveryZone(r.db.QueryRowContext(ctx, query, zoneID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDeliveryZoneNotFound
+	}
+	if err != nil {
+		log.Error().Err(err).Str("zone_id", zoneID).Msg("Failed to get delivery zone")
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// List returns a tenant's delivery zones in the order they are matched
+func (r *DeliveryZoneRepository) List(ctx context.Context, tenantID string, activeOnly bool) ([]*models.DeliveryZone, error) {
+	query := `
+		SELECT ` + deliveryZoneColumns + `
+		FROM delivery_zones
+		WHERE tenant_id = $1 AND (NOT $2 OR is_active)
+		ORDER BY priority, created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, activeOnly)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list delivery zones")
+		return nil, err
+	}
+	defer rows.Close()
+
+	zones := []*models.DeliveryZone{}
+	for rows.Next() {
+		zone, err := scanDeliveryZone(rows)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+
+	return zones, rows.Err()
+}
+
+// Delete removes a delivery zone; addresses priced with it keep the zone name
+func (r *DeliveryZoneRepository) Delete(ctx context.Context, tenantID, zoneID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM delivery_zones WHERE id = $1 AND tenant_id = $2`, zoneID, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("zone_id", zoneID).Msg("Failed to delete delivery zone")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrDeliveryZoneNotFound
+	}
+	return nil
+}
@@ -56,6 +56,30 @@ func (r *GuestOrderRepository) decryptToStringPtrWithContext(ctx context.Context
 	return &decrypted, nil
 }
 
+// customerSearchHashes returns the HMAC search hashes admins find an order's phone and email by
+// A hash is nil when its value normalizes to nothing.
+func customerSearchHashes(phone string, email *string) (phoneHash, emailHash *string) {
+	if normalized := models.NormalizeCustomerPhone(phone); normalized != "" {
+		hash := utils.HashForSearch(normalized)
+		phoneHash = &hash
+	}
+	if email != nil {
+		if normalized := models.NormalizeCustomerEmail(*email); normalized != "" {
+			hash := utils.HashForSearch(normalized)
+			emailHash = &hash
+		}
+	}
+	return phoneHash, emailHash
+}
+
+// contactSearchColumn is the guest_orders hash column a contact search matches on
+func contactSearchColumn(search *models.ContactSearch) string {
+	if search.Email {
+		return "customer_email_hash"
+	}
+	return "customer_phone_hash"
+}
+
 // Create inserts a new guest order with encrypted PII fields
 // Encrypts: CustomerName, CustomerPhone, CustomerEmail, IPAddress, UserAgent
 func (r *GuestOrderRepository) Create(ctx context.Context, tx *sql.Tx, order *models.GuestOrder) (string, error) {
@@ -91,10 +115,15 @@ func (r *GuestOrderRepository) Create(ctx context.Context, tx *sql.Tx, order *mo
 			delivery_type, customer_name, customer_phone, customer_email,
 			table_number, notes,
 			subtotal_amount, delivery_fee, total_amount,
-			ip_address, user_agent
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			ip_address, user_agent,
+			discount_amount, voucher_code, promotion_discount_amount,
+			loyalty_points_redeemed, loyalty_discount_amount,
+			service_charge_rate, service_charge_amount, tax_rate, tax_amount,
+			scheduled_for, customer_phone_hash, customer_email_hash
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
 		RETURNING id
 	`
+	phoneHash, emailHash := customerSearchHashes(order.CustomerPhone, order.CustomerEmail)
 
 	var orderID string
 	executor := r.getExecutor(tx)
@@ -116,6 +145,18 @@ func (r *GuestOrderRepository) Create(ctx context.Context, tx *sql.Tx, order *mo
 		order.TotalAmount,
 		encryptedIPAddress,
 		encryptedUserAgent,
+		order.DiscountAmount,
+		order.VoucherCode,
+		order.PromotionDiscountAmount,
+		order.LoyaltyPointsRedeemed,
+		order.LoyaltyDiscountAmount,
+		order.ServiceChargeRate,
+		order.ServiceChargeAmount,
+		order.TaxRate,
+		order.TaxAmount,
+		order.ScheduledFor,
+		phoneHash,
+		emailHash,
 	).Scan(&orderID)
 
 	if err != nil {
@@ -164,9 +205,9 @@ func (r *GuestOrderRepository) GetByReference(ctx context.Context, tenantID, ord
 	query := `
 		SELECT 
 			id, order_reference, tenant_id, session_id, status,
-			subtotal_amount, delivery_fee, total_amount,
+			subtotal_amount, delivery_fee, discount_amount, voucher_code, promotion_discount_amount, loyalty_points_redeemed, loyalty_discount_amount, service_charge_rate, service_charge_amount, tax_rate, tax_amount, total_amount,
 			customer_name, customer_phone, customer_email,
-			delivery_type, table_number, notes,
+			delivery_type, table_number, notes, scheduled_for,
 			created_at, paid_at, completed_at, cancelled_at,
 			ip_address, user_agent,
 			is_anonymized, anonymized_at
@@ -186,6 +227,15 @@ func (r *GuestOrderRepository) GetByReference(ctx context.Context, tenantID, ord
 		&order.Status,
 		&order.SubtotalAmount,
 		&order.DeliveryFee,
+		&order.DiscountAmount,
+		&order.VoucherCode,
+		&order.PromotionDiscountAmount,
+		&order.LoyaltyPointsRedeemed,
+		&order.LoyaltyDiscountAmount,
+		&order.ServiceChargeRate,
+		&order.ServiceChargeAmount,
+		&order.TaxRate,
+		&order.TaxAmount,
 		&order.TotalAmount,
 		&encryptedName,
 		&encryptedPhone,
@@ -193,6 +243,7 @@ func (r *GuestOrderRepository) GetByReference(ctx context.Context, tenantID, ord
 		&order.DeliveryType,
 		&order.TableNumber,
 		&order.Notes,
+		&order.ScheduledFor,
 		&order.CreatedAt,
 		&order.PaidAt,
 		&order.CompletedAt,
@@ -279,7 +330,9 @@ func (r *GuestOrderRepository) MarkAnonymized(ctx context.Context, orderID strin
 			anonymized_at = CURRENT_TIMESTAMP,
 			customer_name = 'ANONYMIZED',
 			customer_phone = 'ANONYMIZED',
-			customer_email = NULL
+			customer_email = NULL,
+			customer_phone_hash = NULL,
+			customer_email_hash = NULL
 		WHERE id = $1
 	`
 
@@ -300,6 +353,50 @@ func (r *GuestOrderRepository) MarkAnonymized(ctx context.Context, orderID strin
 	return nil
 }
 
+// LockScheduledSlot serializes checkouts booking the same slot until tx ends and returns
+// how many orders already hold it; cancelled orders give their place back
+func (r *GuestOrderRepository) LockScheduledSlot(ctx context.Context, tx *sql.Tx, tenantID string, slot time.Time) (int, error) {
+	slot = slot.UTC()
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`,
+		"scheduled-slot:"+tenantID+":"+slot.Format(time.RFC3339)); err != nil {
+		return 0, fmt.Errorf("failed to lock scheduled slot: %w", err)
+	}
+
+	var count int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM guest_orders
+		WHERE tenant_id = $1 AND scheduled_for = $2 AND status <> 'CANCELLED'
+	`, tenantID, slot).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count scheduled slot orders: %w", err)
+	}
+	return count, nil
+}
+
+// CountScheduledBySlot counts non-cancelled orders per scheduled slot start in [from, to)
+func (r *GuestOrderRepository) CountScheduledBySlot(ctx context.Context, tenantID string, from, to time.Time) (map[time.Time]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT scheduled_for, COUNT(*) FROM guest_orders
+		WHERE tenant_id = $1 AND scheduled_for >= $2 AND scheduled_for < $3 AND status <> 'CANCELLED'
+		GROUP BY scheduled_for
+	`, tenantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scheduled orders: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[time.Time]int)
+	for rows.Next() {
+		var slot time.Time
+		var count int
+		if err := rows.Scan(&slot, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled order count: %w", err)
+		}
+		counts[slot.UTC()] = count
+	}
+	return counts, rows.Err()
+}
+
 // getExecutor returns the appropriate SQL executor (transaction or database)
 func (r *GuestOrderRepository) getExecutor(tx *sql.Tx) interface {
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
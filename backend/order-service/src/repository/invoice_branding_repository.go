@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// InvoiceBrandingRepository reads the branding tenants configure in the tenant service
+type InvoiceBrandingRepository struct {
+	db *sql.DB
+}
+
+// NewInvoiceBrandingRepository creates a new invoice branding repository
+func NewInvoiceBrandingRepository(db *sql.DB) *InvoiceBrandingRepository {
+	return &InvoiceBrandingRepository{db: db}
+}
+
+// GetByTenantID returns a tenant's invoice branding, with defaults for tenants that never set any
+func (r *InvoiceBrandingRepository) GetByTenantID(ctx context.Context, tenantID string) (*models.InvoiceBranding, error) {
+	query := `
+		SELECT t.business_name, COALESCE(b.display_name, ''), COALESCE(b.logo_url, ''), COALESCE(b.primary_color, $2),
+		       COALESCE(b.address, ''), COALESCE(b.phone, ''), COALESCE(b.email, ''), COALESCE(b.tax_id, ''),
+		       COALESCE(b.invoice_footer, ''), COALESCE(b.attach_invoice_pdf, FALSE),
+		       COALESCE(b.logo_storage_key, ''), COALESCE(b.receipt_header, ''), COALESCE(b.receipt_footer, ''),
+		       COALESCE(b.social_links, '{}'::jsonb)
+		FROM tenants t
+		LEFT JOIN tenant_branding b ON b.tenant_id = t.id
+		WHERE t.id = $1
+	`
+
+	var b models.InvoiceBranding
+	var socialLinks []byte
+	err := r.db.QueryRowContext(ctx, query, tenantID, models.DefaultInvoiceColor).Scan(
+		&b.BusinessName, &b.DisplayName, &b.LogoURL, &b.PrimaryColor,
+		&b.Address, &b.Phone, &b.Email, &b.TaxID,
+		&b.InvoiceFooter, &b.AttachInvoicePDF,
+		&b.LogoStorageKey, &b.ReceiptHeader, &b.ReceiptFooter,
+		&socialLinks,
+	)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get invoice branding")
+		return nil, err
+	}
+
+	if err := json.Unmarshal(socialLinks, &b.SocialLinks); err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Ignoring unreadable social links")
+		b.SocialLinks = nil
+	}
+
+	return &b, nil
+}
@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// KitchenRepository handles database operations for the kitchen display
+type KitchenRepository struct {
+	db *sql.DB
+}
+
+// NewKitchenRepository creates a new kitchen repository
+func NewKitchenRepository(db *sql.DB) *KitchenRepository {
+	return &KitchenRepository{db: db}
+}
+
+// kitchenChangedAt is the last time an order changed as far as the kitchen screen is concerned
+const kitchenChangedAt = `GREATEST(paid_at, completed_at, cancelled_at, kitchen_updated_at)`
+
+// ListOrders returns a page of the kitchen order stream; see models.KitchenFeed
+// Due times and rolled-up statuses are left to the caller.
+func (r *KitchenRepository) ListOrders(ctx context.Context, tenantID string, since *time.Time) (*models.KitchenFeed, error) {
+	feed := &models.KitchenFeed{Orders: []models.KitchenOrder{}, Removed: []string{}}
+
+	if since != nil {
+		feed.Cursor = *since
+	} else if err := r.db.QueryRowContext(ctx, `SELECT LOCALTIMESTAMP`).Scan(&feed.Cursor); err != nil {
+		return nil, err
+	}
+
+	query := `
+SELECT id, order_reference, order_type, delivery_type, table_number, notes, scheduled_for, paid_at, kitchen_bumped_at,
+       status = 'PAID' AND kitchen_bumped_at IS NULL, ` + kitchenChangedAt + `
+FROM guest_orders
+WHERE tenant_id = $1 AND paid_at IS NOT NULL
+`
+	args := []interface{}{tenantID}
+	if since != nil {
+		query += ` AND ` + kitchenChangedAt + ` > $2`
+		args = append(args, *since)
+	} else {
+		query += ` AND status = 'PAID' AND kitchen_bumped_at IS NULL`
+	}
+	query += ` ORDER BY paid_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to query kitchen orders")
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var order models.KitchenOrder
+		var active bool
+		var changedAt time.Time
+		if err := rows.Scan(
+			&order.ID,
+			&order.OrderReference,
+			&order.OrderType,
+			&order.DeliveryType,
+			&order.TableNumber,
+			&order.Notes,
+			&order.ScheduledFor,
+			&order.PaidAt,
+			&order.BumpedAt,
+			&active,
+			&changedAt,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to scan kitchen order row")
+			return nil, err
+		}
+
+		if changedAt.After(feed.Cursor) {
+			feed.Cursor = changedAt
+		}
+		if active {
+			feed.Orders = append(feed.Orders, order)
+		} else {
+			feed.Removed = append(feed.Removed, order.ID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, feed.Orders); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// ListBumpedOrders returns a tenant's most recently bumped orders that can still be recalled
+func (r *KitchenRepository) ListBumpedOrders(ctx context.Context, tenantID string, limit int) ([]models.KitchenOrder, error) {
+	query := `
+SELECT id, order_reference, order_type, delivery_type, table_number, notes, scheduled_for, paid_at, kitchen_bumped_at
+FROM guest_orders
+WHERE tenant_id = $1 AND status = 'PAID' AND kitchen_bumped_at IS NOT NULL
+ORDER BY kitchen_bumped_at DESC
+LIMIT $2
+`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, limit)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to query bumped kitchen orders")
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.KitchenOrder{}
+	for rows.Next() {
+		var order models.KitchenOrder
+		if err := rows.Scan(
+			&order.ID,
+			&order.OrderReference,
+			&order.OrderType,
+			&order.DeliveryType,
+			&order.TableNumber,
+			&order.Notes,
+			&order.ScheduledFor,
+			&order.PaidAt,
+			&order.BumpedAt,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to scan bumped kitchen order row")
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// loadItems fills in the items of each order
+func (r *KitchenRepository) loadItems(ctx context.Context, orders []models.KitchenOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	orderIDs := make([]string, len(orders))
+	byID := make(map[string]*models.KitchenOrder, len(orders))
+	for i := range orders {
+		orders[i].Items = []models.KitchenItem{}
+		orderIDs[i] = orders[i].ID
+		byID[orders[i].ID] = &orders[i]
+	}
+
+	query := `
+SELECT id, order_id, product_id, product_name, quantity, prep_status, prep_started_at, prep_ready_at
+FROM order_items
+WHERE order_id = ANY($1)
+ORDER BY created_at, id
+`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(orderIDs))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query kitchen order items")
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.KitchenItem
+		var orderID string
+		if err := rows.Scan(
+			&item.ID,
+			&orderID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
+			&item.PrepStatus,
+			&item.PrepStartedAt,
+			&item.PrepReadyAt,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to scan kitchen order item row")
+			return err
+		}
+		if order, ok := byID[orderID]; ok {
+			order.Items = append(order.Items, item)
+		}
+	}
+
+	return rows.Err()
+}
+
+// UpdateItemPrepStatus sets the preparation status of an item on an active kitchen order
+// Starting an item stamps prep_started_at once; moving it back to queued clears both stamps.
+// Marking an item out of stock clears prep_ready_at so it is left out of preparation metrics.
+// Returns false when the item is not on the tenant's kitchen screen.
+func (r *KitchenRepository) UpdateItemPrepStatus(ctx context.Context, tenantID, orderID, itemID string, status models.PrepStatus) (bool, error) {
+	query := `
+WITH updated AS (
+	UPDATE order_items oi
+	SET prep_status = $4::text,
+	    prep_started_at = CASE
+	        WHEN $4::text = 'queued' THEN NULL
+	        WHEN $4::text = 'preparing' THEN COALESCE(oi.prep_started_at, NOW())
+	        ELSE oi.prep_started_at
+	    END,
+	    prep_ready_at = CASE WHEN $4::text = 'ready' THEN COALESCE(oi.prep_ready_at, NOW()) ELSE NULL END
+	FROM guest_orders o
+	WHERE oi.id = $3 AND oi.order_id = $2
+	  AND o.id = oi.order_id AND o.tenant_id = $1
+	  AND o.status = 'PAID' AND o.kitchen_bumped_at IS NULL
+	RETURNING oi.order_id
+)
+UPDATE guest_orders
+SET kitchen_updated_at = NOW()
+WHERE id IN (SELECT order_id FROM updated)
+`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID, orderID, itemID, string(status))
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Str("item_id", itemID).Msg("Failed to update item prep status")
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// BumpOrder takes an active order off the kitchen screen and marks its remaining items ready
+// Items marked out of stock stay out of stock. Returns false when the order is not on the tenant's kitchen screen.
+func (r *KitchenRepository) BumpOrder(ctx context.Context, tenantID, orderID string) (bool, error) {
+	query := `
+WITH bumped AS (
+	UPDATE guest_orders
+	SET kitchen_bumped_at = NOW(),
+	    kitchen_updated_at = NOW()
+	WHERE id = $2 AND tenant_id = $1 AND status = 'PAID' AND kitchen_bumped_at IS NULL
+	RETURNING id
+), ready AS (
+	UPDATE order_items
+	SET prep_status = 'ready',
+	    prep_ready_at = COALESCE(prep_ready_at, NOW())
+	WHERE order_id IN (SELECT id FROM bumped) AND prep_status <> 'out_of_stock'
+)
+SELECT COUNT(*) FROM bumped
+`
+
+	var bumped int
+	if err := r.db.QueryRowContext(ctx, query, tenantID, orderID).Scan(&bumped); err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to bump kitchen order")
+		return false, err
+	}
+	return bumped > 0, nil
+}
+
+// RecallOrder puts a bumped, still paid order back on the kitchen screen
+// Item statuses are kept. Returns false when the order was not bumped or is no longer paid.
+func (r *KitchenRepository) RecallOrder(ctx context.Context, tenantID, orderID string) (bool, error) {
+	query := `
+UPDATE guest_orders
+SET kitchen_bumped_at = NULL,
+    kitchen_updated_at = NOW()
+WHERE id = $2 AND tenant_id = $1 AND status = 'PAID' AND kitchen_bumped_at IS NOT NULL
+`
+
+	result, err := r.db.ExecContext(ctx, query, tenantID, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to recall kitchen order")
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetMetrics summarizes ticket and preparation times in [from, to)
+// Orders are counted by when they were bumped and items by when they became ready.
+// Scheduled orders are paid ahead of time, so they are left out of ticket and wait times.
+func (r *KitchenRepository) GetMetrics(ctx context.Context, tenantID string, from, to time.Time) (*models.KitchenMetrics, error) {
+	metrics := &models.KitchenMetrics{From: from, To: to, Products: []models.ProductPrepMetrics{}}
+
+	ticketQuery := `
+SELECT COUNT(*),
+       COALESCE(AVG(EXTRACT(EPOCH FROM kitchen_bumped_at - paid_at)), 0),
+       COALESCE(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM kitchen_bumped_at - paid_at)), 0)
+FROM guest_orders
+WHERE tenant_id = $1 AND kitchen_bumped_at >= $2 AND kitchen_bumped_at < $3
+  AND paid_at IS NOT NULL AND scheduled_for IS NULL
+`
+
+	if err := r.db.QueryRowContext(ctx, ticketQuery, tenantID, from, to).Scan(
+		&metrics.OrdersBumped,
+		&metrics.AvgTicketSeconds,
+		&metrics.P90TicketSeconds,
+	); err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to query kitchen ticket times")
+		return nil, err
+	}
+
+	productQuery := `
+SELECT oi.product_id, MAX(oi.product_name), COUNT(*),
+       COALESCE(AVG(CASE WHEN o.scheduled_for IS NULL THEN EXTRACT(EPOCH FROM oi.prep_started_at - o.paid_at) END), 0),
+       COALESCE(AVG(EXTRACT(EPOCH FROM oi.prep_ready_at - oi.prep_started_at)), 0)
+FROM order_items oi
+JOIN guest_orders o ON o.id = oi.order_id
+WHERE o.tenant_id = $1 AND oi.prep_ready_at >= $2 AND oi.prep_ready_at < $3
+GROUP BY oi.product_id
+ORDER BY 5 DESC
+`
+
+	rows, err := r.db.QueryContext(ctx, productQuery, tenantID, from, to)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to query product prep times")
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product models.ProductPrepMetrics
+		if err := rows.Scan(
+			&product.ProductID,
+			&product.ProductName,
+			&product.ItemsReady,
+			&product.AvgWaitSeconds,
+			&product.AvgPrepSeconds,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to scan product prep time row")
+			return nil, err
+		}
+		metrics.Products = append(metrics.Products, product)
+	}
+
+	return metrics, rows.Err()
+}
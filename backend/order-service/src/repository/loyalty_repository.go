@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// LoyaltyRepository handles database operations for loyalty programs, accounts and the points ledger
+type LoyaltyRepository struct {
+	db *sql.DB
+}
+
+// NewLoyaltyRepository creates a new loyalty repository
+func NewLoyaltyRepository(db *sql.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{db: db}
+}
+
+// GetProgram retrieves a tenant's loyalty settings
+// Tenants that never configured loyalty get the disabled defaults; nothing is stored.
+func (r *LoyaltyRepository) GetProgram(ctx context.Context, tenantID string) (*models.LoyaltyProgram, error) {
+	query := `
+		SELECT tenant_id, is_enabled, spend_per_point, point_value, min_redeem_points, max_redeem_percent, updated_at
+		FROM loyalty_programs
+		WHERE tenant_id = $1
+	`
+
+	var p models.LoyaltyProgram
+	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
+		&p.TenantID,
+		&p.IsEnabled,
+		&p.SpendPerPoint,
+		&p.PointValue,
+		&p.MinRedeemPoints,
+		&p.MaxRedeemPercent,
+		&p.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return models.DefaultLoyaltyProgram(tenantID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpdateProgram creates or changes a tenant's loyalty settings; nil fields are left as they are
+func (r *LoyaltyRepository) UpdateProgram(ctx context.Context, tenantID string, req *models.UpdateLoyaltyProgramRequest) (*models.LoyaltyProgram, error) {
+	query := `
+		INSERT INTO loyalty_programs (tenant_id, is_enabled, spend_per_point, point_value, min_redeem_points, max_redeem_percent)
+		VALUES ($1, COALESCE($2, FALSE), COALESCE($3, 10000), COALESCE($4, 100), COALESCE($5, 10), COALESCE($6, 50))
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			is_enabled = COALESCE($2, loyalty_programs.is_enabled),
+			spend_per_point = COALESCE($3, loyalty_programs.spend_per_point),
+			point_value = COALESCE($4, loyalty_programs.point_value),
+			min_redeem_points = COALESCE($5, loyalty_programs.min_redeem_points),
+			max_redeem_percent = COALESCE($6, loyalty_programs.max_redeem_percent),
+			updated_at = NOW()
+		RETURNING tenant_id, is_enabled, spend_per_point, point_value, min_redeem_points, max_redeem_percent, updated_at
+	`
+
+	var p models.LoyaltyProgram
+	err := r.db.QueryRowContext(ctx, query,
+		tenantID,
+		req.IsEnabled,
+		req.SpendPerPoint,
+		req.PointValue,
+		req.MinRedeemPoints,
+		req.MaxRedeemPercent,
+	).Scan(
+		&p.TenantID,
+		&p.IsEnabled,
+		&p.SpendPerPoint,
+		&p.PointValue,
+		&p.MinRedeemPoints,
+		&p.MaxRedeemPercent,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to update loyalty program")
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetAccount retrieves a guest's loyalty account by phone hash; nil when the guest has none
+func (r *LoyaltyRepository) GetAccount(ctx context.Context, tenantID, phoneHash string) (*models.LoyaltyAccount, error) {
+	query := `
+		SELECT id, tenant_id, phone_hash, points_balance, created_at, updated_at
+		FROM loyalty_accounts
+		WHERE tenant_id = $1 AND phone_hash = $2
+	`
+
+	var a models.LoyaltyAccount
+	err := r.db.QueryRowContext(ctx, query, tenantID, phoneHash).Scan(
+		&a.ID,
+		&a.TenantID,
+		&a.PhoneHash,
+		&a.PointsBalance,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Redeem takes points off an account for an order inside the checkout transaction
+// The balance is only reduced while it covers the points, so concurrent checkouts
+// cannot spend the same points twice.
+func (r *LoyaltyRepository) Redeem(ctx context.Context, tx *sql.Tx, account *models.LoyaltyAccount, orderID string, points int) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE loyalty_accounts
+		SET points_balance = points_balance - $2, updated_at = NOW()
+		WHERE id = $1 AND points_balance >= $2
+	`, account.ID, points)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrLoyaltyInsufficientPoints
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO loyalty_transactions (account_id, tenant_id, order_id, transaction_type, points)
+		VALUES ($1, $2, $3, $4, $5)
+	`, account.ID, account.TenantID, orderID, models.LoyaltyRedeem, -points)
+	return err
+}
+
+// Earn credits points for a paid order, opening the guest's account on their first order
+// Returns false when the order already earned its points.
+func (r *LoyaltyRepository) Earn(ctx context.Context, tx *sql.Tx, tenantID, phoneHash, orderID string, points int) (bool, error) {
+	var accountID string
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO loyalty_accounts (tenant_id, phone_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (tenant_id, phone_hash) DO UPDATE SET updated_at = NOW()
+		RETURNING id
+	`, tenantID, phoneHash).Scan(&accountID)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO loyalty_transactions (account_id, tenant_id, order_id, transaction_type, points)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_id, transaction_type) DO NOTHING
+	`, accountID, tenantID, orderID, models.LoyaltyEarn, points)
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE loyalty_accounts
+		SET points_balance = points_balance + $2, updated_at = NOW()
+		WHERE id = $1
+	`, accountID, points)
+	return err == nil, err
+}
+
+// ReverseForOrder undoes a cancelled order's points: earned points are taken back and
+// redeemed points are given back. Safe to call more than once per order.
+// Earned points that were already spent are taken back only down to a zero balance.
+func (r *LoyaltyRepository) ReverseForOrder(ctx context.Context, tx *sql.Tx, orderID string) error {
+	_, err := tx.ExecContext(ctx, `
+		WITH reversed AS (
+			INSERT INTO loyalty_transactions (account_id, tenant_id, order_id, transaction_type, points)
+			SELECT account_id, tenant_id, order_id,
+			       CASE transaction_type WHEN 'earn' THEN 'earn_reversal' ELSE 'redeem_refund' END,
+			       -points
+			FROM loyalty_transactions
+			WHERE order_id = $1 AND transaction_type IN ('earn', 'redeem')
+			ON CONFLICT (order_id, transaction_type) DO NOTHING
+			RETURNING account_id, points
+		)
+		UPDATE loyalty_accounts a
+		SET points_balance = GREATEST(a.points_balance + r.points, 0), updated_at = NOW()
+		FROM (SELECT account_id, SUM(points) AS points FROM reversed GROUP BY account_id) r
+		WHERE a.id = r.account_id
+	`, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to reverse loyalty points")
+	}
+	return err
+}
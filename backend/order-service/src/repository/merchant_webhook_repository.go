@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// merchantWebhookSecretContext is the encryption context of webhook signing secrets
+const merchantWebhookSecretContext = "merchant_webhook:secret"
+
+// MerchantWebhookRepository handles tenant webhooks and their delivery log
+type MerchantWebhookRepository struct {
+	db        *sql.DB
+	encryptor utils.Encryptor
+}
+
+// NewMerchantWebhookRepository creates a new merchant webhook repository
+func NewMerchantWebhookRepository(db *sql.DB, encryptor utils.Encryptor) *MerchantWebhookRepository {
+	return &MerchantWebhookRepository{db: db, encryptor: encryptor}
+}
+
+const merchantWebhookColumns = `id, tenant_id, url, events, is_active, created_at, updated_at`
+
+func scanMerchantWebhook(row interface{ Scan(...interface{}) error }) (*models.MerchantWebhook, error) {
+	var webhook models.MerchantWebhook
+	var events []string
+	if err := row.Scan(
+		&webhook.ID,
+		&webhook.TenantID,
+		&webhook.URL,
+		pq.Array(&events),
+		&webhook.IsActive,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	webhook.Events = make([]models.MerchantWebhookEvent, len(events))
+	for i, event := range events {
+		webhook.Events[i] = models.MerchantWebhookEvent(event)
+	}
+	return &webhook, nil
+}
+
+func webhookEventStrings(events []models.MerchantWebhookEvent) []string {
+	values := make([]string, len(events))
+	for i, event := range events {
+		values[i] = string(event)
+	}
+	return values
+}
+
+// Create stores a new webhook with its signing secret
+func (r *MerchantWebhookRepository) Create(ctx context.Context, webhook *models.MerchantWebhook, secret string) error {
+	encrypted, err := r.encryptor.EncryptWithContext(ctx, secret, merchantWebhookSecretContext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO merchant_webhooks (tenant_id, url, events, secret_encrypted, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, webhook.TenantID, webhook.URL, pq.Array(webhookEventStrings(webhook.Events)), encrypted, webhook.IsActive).
+		Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+// Update replaces a webhook's URL, events and active flag
+func (r *MerchantWebhookRepository) Update(ctx context.Context, webhook *models.MerchantWebhook) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE merchant_webhooks
+		SET url = $3, events = $4, is_active = $5, updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING created_at, updated_at
+	`, webhook.ID, webhook.TenantID, webhook.URL, pq.Array(webhookEventStrings(webhook.Events)), webhook.IsActive).
+		Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrMerchantWebhookNotFound
+	}
+	return err
+}
+
+// RotateSecret replaces a webhook's signing secret
+func (r *MerchantWebhookRepository) RotateSecret(ctx context.Context, tenantID, webhookID, secret string) error {
+	encrypted, err := r.encryptor.EncryptWithContext(ctx, secret, merchantWebhookSecretContext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE merchant_webhooks SET secret_encrypted = $3, updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2
+	`, webhookID, tenantID, encrypted)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return models.ErrMerchantWebhookNotFound
+	}
+	return nil
+}
+
+// Delete removes a webhook and its delivery log
+func (r *MerchantWebhookRepository) Delete(ctx context.Context, tenantID, webhookID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM merchant_webhooks WHERE id = $1 AND tenant_id = $2`, webhookID, tenantID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return models.ErrMerchantWebhookNotFound
+	}
+	return nil
+}
+
+// Get returns one of a tenant's webhooks
+func (r *MerchantWebhookRepository) Get(ctx context.Context, tenantID, webhookID string) (*models.MerchantWebhook, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+merchantWebhookColumns+`
+		FROM merchant_webhooks
+		WHERE id = $1 AND tenant_id = $2
+	`, webhookID, tenantID)
+	webhook, err := scanMerchantWebhook(row)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrMerchantWebhookNotFound
+	}
+	return webhook, err
+}
+
+// List returns a tenant's webhooks, oldest first
+func (r *MerchantWebhookRepository) List(ctx context.Context, tenantID string) ([]*models.MerchantWebhook, error) {
+	return r.list(ctx, `
+		SELECT `+merchantWebhookColumns+`
+		FROM merchant_webhooks
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`, tenantID)
+}
+
+// ListSubscribed returns a tenant's active webhooks subscribed to an event
+func (r *MerchantWebhookRepository) ListSubscribed(ctx context.Context, tenantID string, event models.MerchantWebhookEvent) ([]*models.MerchantWebhook, error) {
+	return r.list(ctx, `
+		SELECT `+merchantWebhookColumns+`
+		FROM merchant_webhooks
+		WHERE tenant_id = $1 AND is_active AND $2 = ANY(events)
+	`, tenantID, string(event))
+}
+
+func (r *MerchantWebhookRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.MerchantWebhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*models.MerchantWebhook{}
+	for rows.Next() {
+		webhook, err := scanMerchantWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// DecryptSecret returns the signing secret of a claimed delivery's webhook
+func (r *MerchantWebhookRepository) DecryptSecret(ctx context.Context, encrypted string) (string, error) {
+	return r.encryptor.DecryptWithContext(ctx, encrypted, merchantWebhookSecretContext)
+}
+
+// QueueDelivery schedules a delivery for immediate sending
+func (r *MerchantWebhookRepository) QueueDelivery(ctx context.Context, delivery *models.MerchantWebhookDelivery) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO merchant_webhook_deliveries (webhook_id, tenant_id, event_id, event_type, payload, status, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, 'pending', NOW())
+		RETURNING id, status, attempts, next_attempt_at, created_at
+	`, delivery.WebhookID, delivery.TenantID, delivery.EventID, string(delivery.EventType), []byte(delivery.Payload)).
+		Scan(&delivery.ID, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt)
+}
+
+// ClaimDue takes up to limit due deliveries of active webhooks and counts the attempt
+// Claimed deliveries are pushed back by lease so another replica does not send them while
+// this one does; the caller records the outcome afterwards.
+func (r *MerchantWebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.MerchantWebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE merchant_webhook_deliveries d
+		SET attempts = d.attempts + 1,
+		    next_attempt_at = $2
+		FROM merchant_webhooks w
+		WHERE w.id = d.webhook_id
+		  AND w.is_active
+		  AND d.id IN (
+			SELECT id FROM merchant_webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING d.id, d.webhook_id, d.tenant_id, d.event_id, d.event_type, d.payload, d.attempts, d.created_at,
+		          w.url, w.secret_encrypted
+	`, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.MerchantWebhookDelivery
+	for rows.Next() {
+		delivery := &models.MerchantWebhookDelivery{Status: models.WebhookDeliveryPending}
+		var payload []byte
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.TenantID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Attempts,
+			&delivery.CreatedAt,
+			&delivery.URL,
+			&delivery.EncryptedSecret,
+		); err != nil {
+			return nil, err
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt stores the outcome of a claimed delivery's attempt
+// A pending delivery gets nextAttemptAt; succeeded and failed ones leave the queue.
+func (r *MerchantWebhookRepository) RecordAttempt(ctx context.Context, delivery *models.MerchantWebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE merchant_webhook_deliveries
+		SET status = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6
+		WHERE id = $1
+	`, delivery.ID, string(delivery.Status), delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt)
+	return err
+}
+
+// ListDeliveries returns a webhook's delivery log, newest first, optionally by status
+func (r *MerchantWebhookRepository) ListDeliveries(ctx context.Context, tenantID, webhookID string, status *models.WebhookDeliveryStatus, limit, offset int) ([]*models.MerchantWebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_id, tenant_id, event_id, event_type, payload, status, attempts,
+		       next_attempt_at, last_status_code, last_error, created_at, delivered_at
+		FROM merchant_webhook_deliveries
+		WHERE tenant_id = $1 AND webhook_id = $2`
+	args := []interface{}{tenantID, webhookID}
+	if status != nil {
+		query += ` AND status = $3`
+		args = append(args, string(*status))
+	}
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT %d OFFSET %d`, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.MerchantWebhookDelivery{}
+	for rows.Next() {
+		var delivery models.MerchantWebhookDelivery
+		var payload []byte
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.TenantID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, rows.Err()
+}
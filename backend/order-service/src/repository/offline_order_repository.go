@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/utils"
 )
@@ -104,10 +106,20 @@ func (r *OfflineOrderRepository) CreateOfflineOrder(ctx context.Context, tx *sql
 			table_number, notes,
 			subtotal_amount, delivery_fee, total_amount,
 			data_consent_given, consent_method, recorded_by_user_id,
-			created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+			client_order_id, synced_at, created_at,
+			customer_phone_hash, customer_email_hash, outlet_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+			-- Offline orders belong to the outlet of the staff member who recorded them
+			(SELECT outlet_id FROM users WHERE id = $16 AND tenant_id = $1))
 		RETURNING id
 	`
+	phoneHash, emailHash := customerSearchHashes(order.CustomerPhone, order.CustomerEmail)
+
+	// Orders captured offline keep the terminal's timestamp
+	createdAt := time.Now()
+	if !order.CreatedAt.IsZero() {
+		createdAt = order.CreatedAt
+	}
 
 	var orderID string
 	executor := r.getExecutor(tx)
@@ -130,9 +142,16 @@ func (r *OfflineOrderRepository) CreateOfflineOrder(ctx context.Context, tx *sql
 		order.DataConsentGiven,
 		order.ConsentMethod,
 		order.RecordedByUserID,
-		time.Now(),
+		order.ClientOrderID,
+		order.SyncedAt,
+		createdAt,
+		phoneHash,
+		emailHash,
 	).Scan(&orderID)
 
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "idx_guest_orders_tenant_client_order_id" {
+		return "", models.ErrClientOrderIDExists
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to create offline order: %w", err)
 	}
@@ -249,7 +268,8 @@ func (r *OfflineOrderRepository) GetOfflineOrderByID(ctx context.Context, orderI
 }
 
 // ListOfflineOrders retrieves offline orders with pagination and filtering
-// Supports filtering by status and search by order_reference
+// Supports filtering by status and search by order_reference or exact customer phone/email
+// Anonymized orders are excluded from search results; they are reachable through the archive endpoints
 func (r *OfflineOrderRepository) ListOfflineOrders(ctx context.Context, tenantID string, filters ListOfflineOrdersFilters) ([]models.GuestOrder, int, error) {
 	// Build WHERE clause dynamically based on filters
 	whereClause := "WHERE tenant_id = $1 AND order_type = 'offline'"
@@ -264,8 +284,15 @@ func (r *OfflineOrderRepository) ListOfflineOrders(ctx context.Context, tenantID
 
 	if filters.SearchQuery != "" {
 		argCount++
-		whereClause += fmt.Sprintf(" AND order_reference ILIKE $%d", argCount)
+		searchClause := fmt.Sprintf("order_reference ILIKE $%d", argCount)
 		args = append(args, "%"+filters.SearchQuery+"%")
+		// Queries that read as a phone number or email also match the customer's contact hash
+		if search, err := models.ParseContactSearch(filters.SearchQuery); err == nil {
+			argCount++
+			searchClause += fmt.Sprintf(" OR %s = $%d", contactSearchColumn(search), argCount)
+			args = append(args, utils.HashForSearch(search.Value))
+		}
+		whereClause += " AND (" + searchClause + ") AND is_anonymized = FALSE"
 	}
 
 	// Count total records
@@ -280,11 +307,11 @@ func (r *OfflineOrderRepository) ListOfflineOrders(ctx context.Context, tenantID
 	query := fmt.Sprintf(`
 		SELECT 
 			id, tenant_id, order_reference, status, order_type,
-			delivery_type, customer_name, customer_phone, customer_email,
+			delivery_type, customer_name, customer_phone, COALESCE(customer_email, ''),
 			table_number, notes,
 			subtotal_amount, delivery_fee, total_amount,
 			data_consent_given, consent_method, recorded_by_user_id,
-			created_at, paid_at, completed_at
+			created_at, paid_at, completed_at, is_anonymized, anonymized_at
 		FROM guest_orders
 		%s
 		ORDER BY created_at DESC
@@ -328,30 +355,34 @@ func (r *OfflineOrderRepository) ListOfflineOrders(ctx context.Context, tenantID
 			&order.CreatedAt,
 			&paidAt,
 			&completedAt,
+			&order.IsAnonymized,
+			&order.AnonymizedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan offline order: %w", err)
 		}
 
-		// Decrypt PII fields
-		decryptedName, err := r.encryptor.DecryptWithContext(ctx, encryptedName, "guest_order:customer_name")
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to decrypt customer_name: %w", err)
-		}
-		order.CustomerName = decryptedName
-
-		decryptedPhone, err := r.encryptor.DecryptWithContext(ctx, encryptedPhone, "guest_order:customer_phone")
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to decrypt customer_phone: %w", err)
-		}
-		order.CustomerPhone = decryptedPhone
+		// Decrypt PII fields (anonymized orders only hold placeholders)
+		if !order.IsAnonymized {
+			decryptedName, err := r.encryptor.DecryptWithContext(ctx, encryptedName, "guest_order:customer_name")
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to decrypt customer_name: %w", err)
+			}
+			order.CustomerName = decryptedName
 
-		if encryptedEmail != "" {
-			decryptedEmail, err := r.encryptor.DecryptWithContext(ctx, encryptedEmail, "guest_order:customer_email")
+			decryptedPhone, err := r.encryptor.DecryptWithContext(ctx, encryptedPhone, "guest_order:customer_phone")
 			if err != nil {
-				return nil, 0, fmt.Errorf("failed to decrypt customer_email: %w", err)
+				return nil, 0, fmt.Errorf("failed to decrypt customer_phone: %w", err)
+			}
+			order.CustomerPhone = decryptedPhone
+
+			if encryptedEmail != "" {
+				decryptedEmail, err := r.encryptor.DecryptWithContext(ctx, encryptedEmail, "guest_order:customer_email")
+				if err != nil {
+					return nil, 0, fmt.Errorf("failed to decrypt customer_email: %w", err)
+				}
+				order.CustomerEmail = &decryptedEmail
 			}
-			order.CustomerEmail = &decryptedEmail
 		}
 
 		// Handle nullable fields
@@ -411,6 +442,10 @@ func (r *OfflineOrderRepository) UpdateOfflineOrder(ctx context.Context, tx *sql
 		argCount++
 		query += fmt.Sprintf(", customer_phone = $%d", argCount)
 		args = append(args, encryptedPhone)
+		phoneHash, _ := customerSearchHashes(*updates.CustomerPhone, nil)
+		argCount++
+		query += fmt.Sprintf(", customer_phone_hash = $%d", argCount)
+		args = append(args, phoneHash)
 	}
 
 	if updates.CustomerEmail != nil {
@@ -421,6 +456,10 @@ func (r *OfflineOrderRepository) UpdateOfflineOrder(ctx context.Context, tx *sql
 		argCount++
 		query += fmt.Sprintf(", customer_email = $%d", argCount)
 		args = append(args, encryptedEmail)
+		_, emailHash := customerSearchHashes("", updates.CustomerEmail)
+		argCount++
+		query += fmt.Sprintf(", customer_email_hash = $%d", argCount)
+		args = append(args, emailHash)
 	}
 
 	// Non-encrypted field updates
@@ -549,7 +588,7 @@ func (r *OfflineOrderRepository) UpdateOrderItems(ctx context.Context, tx *sql.T
 // ListOfflineOrdersFilters holds filter parameters for listing offline orders
 type ListOfflineOrdersFilters struct {
 	Status      string // Filter by order status (optional)
-	SearchQuery string // Search by order_reference (optional)
+	SearchQuery string // Search by order_reference, customer phone or email (optional, never matches anonymized orders)
 	Limit       int    // Page size
 	Offset      int    // Page offset
 }
@@ -620,3 +659,33 @@ func (r *OfflineOrderRepository) SoftDeleteOfflineOrder(ctx context.Context, ord
 
 	return nil
 }
+
+// GetOfflineOrderIDByClientOrderID looks up an order previously ingested from an offline terminal
+// Returns an empty string when no order with the given client ID exists for the tenant
+func (r *OfflineOrderRepository) GetOfflineOrderIDByClientOrderID(ctx context.Context, tx *sql.Tx, tenantID, clientOrderID string) (string, error) {
+	query := `
+		SELECT id FROM guest_orders
+		WHERE tenant_id = $1 AND client_order_id = $2 AND order_type = 'offline'
+	`
+
+	var orderID string
+	err := r.getExecutor(tx).QueryRowContext(ctx, query, tenantID, clientOrderID).Scan(&orderID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up order by client order id: %w", err)
+	}
+
+	return orderID, nil
+}
+
+// MarkStockOversold flags an offline order whose items exceeded available stock at sync time
+func (r *OfflineOrderRepository) MarkStockOversold(ctx context.Context, tx *sql.Tx, orderID string) error {
+	query := `UPDATE guest_orders SET stock_oversold = true WHERE id = $1`
+
+	if _, err := r.getExecutor(tx).ExecContext(ctx, query, orderID); err != nil {
+		return fmt.Errorf("failed to flag oversold order: %w", err)
+	}
+	return nil
+}
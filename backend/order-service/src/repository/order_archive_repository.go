@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// OrderArchiveRepository moves closed orders into the partitioned archive tables and reads them back
+type OrderArchiveRepository struct {
+	db *sql.DB
+}
+
+// NewOrderArchiveRepository creates a new order archive repository
+func NewOrderArchiveRepository(db *sql.DB) *OrderArchiveRepository {
+	return &OrderArchiveRepository{db: db}
+}
+
+// ListColdStorageCandidates returns closed orders that were closed before the cutoff, oldest first
+// Orders that anything outside the order itself still relies on stay in the live tables:
+//   - returns, refund requests, cash refunds and payment terms: return, refund and installment history
+//   - voucher redemptions: vouchers count past redemptions against per-customer limits
+//   - loyalty transactions: account balances must keep matching their ledger
+//   - applied promotions: the promotion's redemption history
+//   - delivery proofs, courier bookings and note attachments: delivery records and stored photos
+func (r *OrderArchiveRepository) ListColdStorageCandidates(ctx context.Context, cutoff time.Time, limit int) ([]models.ColdStorageCandidate, error) {
+	query := `
+SELECT o.id, o.created_at
+FROM guest_orders o
+WHERE o.status IN ('COMPLETE', 'CANCELLED')
+  AND COALESCE(o.completed_at, o.cancelled_at, o.created_at) < $1
+  AND NOT EXISTS (SELECT 1 FROM order_returns r WHERE r.order_id = o.id OR r.exchange_order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM refund_approvals a WHERE a.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM cash_refunds cr WHERE cr.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM voucher_redemptions v WHERE v.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM payment_terms pt WHERE pt.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM loyalty_transactions lt WHERE lt.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM order_promotions op WHERE op.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM delivery_proofs dp WHERE dp.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM courier_bookings cb WHERE cb.order_id = o.id)
+  AND NOT EXISTS (SELECT 1 FROM order_note_attachments na WHERE na.order_id = o.id)
+ORDER BY o.created_at
+LIMIT $2
+`
+
+	rows, err := r.db.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list cold storage candidates")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []models.ColdStorageCandidate
+	for rows.Next() {
+		var candidate models.ColdStorageCandidate
+		if err := rows.Scan(&candidate.OrderID, &candidate.CreatedAt); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
+}
+
+// MoveToColdStorage copies a closed order, its items and payments into the archive tables
+// and deletes it from the live tables. The delete cascades to what is left hanging off a
+// cold storage candidate: reservations, addresses, notes, print jobs, SLA alerts and charge retries.
+// Customer data is not copied. Returns false when the order is gone or no longer closed.
+// The partitions for the order's month must exist.
+func (r *OrderArchiveRepository) MoveToColdStorage(ctx context.Context, tx *sql.Tx, orderID string) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+INSERT INTO archived_guest_orders (
+	id, tenant_id, order_reference, status, order_type, delivery_type, table_number,
+	subtotal_amount, delivery_fee, promotion_discount_amount, discount_amount, loyalty_discount_amount,
+	service_charge_amount, tax_amount, total_amount, created_at, paid_at, completed_at, cancelled_at
+)
+SELECT id, tenant_id, order_reference, status, order_type, delivery_type, table_number,
+	subtotal_amount, delivery_fee, promotion_discount_amount, discount_amount, loyalty_discount_amount,
+	service_charge_amount, tax_amount, total_amount, created_at, paid_at, completed_at, cancelled_at
+FROM guest_orders
+WHERE id = $1 AND status IN ('COMPLETE', 'CANCELLED')
+FOR UPDATE
+`, orderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to archive order: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO archived_order_items (
+	id, order_id, product_id, product_name, product_sku, quantity, unit_price, total_price, order_created_at
+)
+SELECT oi.id, oi.order_id, oi.product_id, oi.product_name, oi.product_sku, oi.quantity, oi.unit_price, oi.total_price, o.created_at
+FROM order_items oi
+JOIN guest_orders o ON o.id = oi.order_id
+WHERE oi.order_id = $1
+`, orderID); err != nil {
+		return false, fmt.Errorf("failed to archive order items: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO archived_payment_transactions (
+	id, order_id, source, method, reference, amount, status, paid_at, created_at, order_created_at
+)
+SELECT pt.id, pt.order_id, 'gateway', COALESCE(pt.payment_type, pt.payment_gateway), pt.midtrans_transaction_id,
+	pt.amount, pt.transaction_status, pt.settled_at, pt.created_at, o.created_at
+FROM payment_transactions pt
+JOIN guest_orders o ON o.id = pt.order_id
+WHERE pt.order_id = $1
+UNION ALL
+SELECT pr.id, pr.order_id, 'staff', pr.payment_method, pr.receipt_number,
+	pr.amount_paid, NULL, pr.payment_date, pr.created_at, o.created_at
+FROM payment_records pr
+JOIN guest_orders o ON o.id = pr.order_id
+WHERE pr.order_id = $1
+`, orderID); err != nil {
+		return false, fmt.Errorf("failed to archive order payments: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM guest_orders WHERE id = $1`, orderID); err != nil {
+		return false, fmt.Errorf("failed to delete archived order: %w", err)
+	}
+	return true, nil
+}
+
+const archivedOrderColumns = `
+	id, tenant_id, order_reference, status, order_type, delivery_type, table_number,
+	subtotal_amount, delivery_fee, total_amount, created_at, paid_at, completed_at, cancelled_at, archived_at`
+
+// GetByReference returns an order in cold storage by its reference
+// Returns sql.ErrNoRows when no archived order has the reference.
+func (r *OrderArchiveRepository) GetByReference(ctx context.Context, orderReference string) (*models.ArchivedOrder, error) {
+	query := `SELECT ` + archivedOrderColumns + `
+FROM archived_guest_orders
+WHERE order_reference = $1
+ORDER BY created_at DESC
+LIMIT 1`
+	return r.get(ctx, query, orderReference)
+}
+
+// GetByID returns a tenant's order in cold storage
+// Returns sql.ErrNoRows when the tenant has no archived order with the ID.
+func (r *OrderArchiveRepository) GetByID(ctx context.Context, tenantID, orderID string) (*models.ArchivedOrder, error) {
+	query := `SELECT ` + archivedOrderColumns + `
+FROM archived_guest_orders
+WHERE id = $1 AND tenant_id = $2`
+	return r.get(ctx, query, orderID, tenantID)
+}
+
+// get loads an archived order with its items and payments
+func (r *OrderArchiveRepository) get(ctx context.Context, query string, args ...interface{}) (*models.ArchivedOrder, error) {
+	var order models.ArchivedOrder
+	var coldStoredAt time.Time
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&order.ID,
+		&order.TenantID,
+		&order.OrderReference,
+		&order.Status,
+		&order.OrderType,
+		&order.DeliveryType,
+		&order.TableNumber,
+		&order.SubtotalAmount,
+		&order.DeliveryFee,
+		&order.TotalAmount,
+		&order.CreatedAt,
+		&order.PaidAt,
+		&order.CompletedAt,
+		&order.CancelledAt,
+		&coldStoredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	order.IsAnonymized = true // Customer data is never copied to cold storage
+	order.ColdStoredAt = &coldStoredAt
+	order.Payments = []models.PaymentRecord{}
+
+	if order.Items, err = r.getItems(ctx, order.ID, order.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to get archived order items: %w", err)
+	}
+	if order.ColdPayments, err = r.getPayments(ctx, order.ID, order.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to get archived order payments: %w", err)
+	}
+	return &order, nil
+}
+
+// getItems loads the items of an archived order from its month's partition
+func (r *OrderArchiveRepository) getItems(ctx context.Context, orderID string, orderCreatedAt time.Time) ([]models.OrderItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, order_id, product_id, product_name, product_sku, quantity, unit_price, total_price
+FROM archived_order_items
+WHERE order_id = $1 AND order_created_at = $2
+ORDER BY id
+`, orderID, orderCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.OrderItem{}
+	for rows.Next() {
+		var item models.OrderItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.OrderID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.ProductSKU,
+			&item.Quantity,
+			&item.UnitPrice,
+			&item.TotalPrice,
+		); err != nil {
+			return nil, err
+		}
+		item.CreatedAt = orderCreatedAt
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// getPayments loads the payments of an archived order from its month's partition
+func (r *OrderArchiveRepository) getPayments(ctx context.Context, orderID string, orderCreatedAt time.Time) ([]models.ColdPayment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, source, method, reference, amount, status, paid_at, created_at
+FROM archived_payment_transactions
+WHERE order_id = $1 AND order_created_at = $2
+ORDER BY created_at
+`, orderID, orderCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []models.ColdPayment{}
+	for rows.Next() {
+		var payment models.ColdPayment
+		if err := rows.Scan(
+			&payment.ID,
+			&payment.Source,
+			&payment.Method,
+			&payment.Reference,
+			&payment.Amount,
+			&payment.Status,
+			&payment.PaidAt,
+			&payment.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
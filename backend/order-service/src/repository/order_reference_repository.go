@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// OrderReferenceRepository hands out the per-tenant daily sequence behind patterned order references
+type OrderReferenceRepository struct {
+	db *sql.DB
+}
+
+// NewOrderReferenceRepository creates a new order reference repository
+func NewOrderReferenceRepository(db *sql.DB) *OrderReferenceRepository {
+	return &OrderReferenceRepository{db: db}
+}
+
+// NextSequence returns the tenant's next sequence number for day, starting at 1
+// The row stays locked until tx ends, so concurrent checkouts of a tenant get consecutive numbers.
+func (r *OrderReferenceRepository) NextSequence(ctx context.Context, tx *sql.Tx, tenantID string, day time.Time) (int, error) {
+	var sequence int
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO order_reference_sequences (tenant_id, sequence_date, last_value)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (tenant_id, sequence_date)
+		DO UPDATE SET last_value = order_reference_sequences.last_value + 1
+		RETURNING last_value
+	`, tenantID, day.Format("2006-01-02")).Scan(&sequence)
+	return sequence, err
+}
+
+// Exists reports whether an order reference is taken by a live or an archived order
+func (r *OrderReferenceRepository) Exists(ctx context.Context, tx *sql.Tx, reference string) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM guest_orders WHERE order_reference = $1)
+		    OR EXISTS (SELECT 1 FROM archived_guest_orders WHERE order_reference = $1)
+	`, reference).Scan(&exists)
+	return exists, err
+}
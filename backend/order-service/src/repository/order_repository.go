@@ -49,9 +49,9 @@ func (r *OrderRepository) decryptToStringPtr(ctx context.Context, encrypted stri
 // GetOrderByReference retrieves an order by its reference number
 func (r *OrderRepository) GetOrderByReference(ctx context.Context, orderReference string) (*models.GuestOrder, error) {
 	query := `
-		SELECT od.id, od.order_reference, od.tenant_id, od.status, od.subtotal_amount, od.delivery_fee, od.total_amount,
-					od.customer_name, od.customer_phone, od.customer_email, od.delivery_type, od.table_number, od.notes,
-					od.created_at, od.paid_at, od.completed_at, od.cancelled_at, od.session_id, od.ip_address, od.user_agent, od.is_anonymized,
+		SELECT od.id, od.order_reference, od.tenant_id, od.status, od.subtotal_amount, od.delivery_fee, od.discount_amount, od.voucher_code, od.promotion_discount_amount, od.loyalty_points_redeemed, od.loyalty_discount_amount, od.service_charge_rate, od.service_charge_amount, od.tax_rate, od.tax_amount, od.total_amount,
+					od.customer_name, od.customer_phone, od.customer_email, od.delivery_type, od.table_number, od.notes, od.scheduled_for, od.payment_fallback, od.queue_number, od.delivery_status,
+					od.created_at, od.paid_at, od.completed_at, od.cancelled_at, od.session_id, od.ip_address, od.user_agent, od.order_type, od.is_anonymized,
 					od.anonymized_at, t.slug as tenant_slug
 		FROM guest_orders od
 		LEFT JOIN tenants t ON od.tenant_id = t.id
@@ -70,6 +70,15 @@ func (r *OrderRepository) GetOrderByReference(ctx context.Context, orderReferenc
 		&order.Status,
 		&order.SubtotalAmount,
 		&order.DeliveryFee,
+		&order.DiscountAmount,
+		&order.VoucherCode,
+		&order.PromotionDiscountAmount,
+		&order.LoyaltyPointsRedeemed,
+		&order.LoyaltyDiscountAmount,
+		&order.ServiceChargeRate,
+		&order.ServiceChargeAmount,
+		&order.TaxRate,
+		&order.TaxAmount,
 		&order.TotalAmount,
 		&encryptedName,
 		&encryptedPhone,
@@ -77,6 +86,10 @@ func (r *OrderRepository) GetOrderByReference(ctx context.Context, orderReferenc
 		&order.DeliveryType,
 		&order.TableNumber,
 		&order.Notes,
+		&order.ScheduledFor,
+		&order.PaymentFallback,
+		&order.QueueNumber,
+		&order.DeliveryStatus,
 		&order.CreatedAt,
 		&order.PaidAt,
 		&order.CompletedAt,
@@ -84,6 +97,7 @@ func (r *OrderRepository) GetOrderByReference(ctx context.Context, orderReferenc
 		&sessionID,
 		&encryptedIP,
 		&encryptedUA,
+		&order.OrderType,
 		&order.IsAnonymized,
 		&order.AnonymizedAt,
 		&order.TenantSlug,
@@ -138,9 +152,10 @@ func (r *OrderRepository) GetOrderByReference(ctx context.Context, orderReferenc
 // GetOrderByID retrieves an order by its ID
 func (r *OrderRepository) GetOrderByID(ctx context.Context, orderID string) (*models.GuestOrder, error) {
 	query := `
-SELECT id, order_reference, tenant_id, status, subtotal_amount, delivery_fee, total_amount,
-       customer_name, customer_phone, customer_email, delivery_type, table_number, notes,
-       created_at, paid_at, completed_at, cancelled_at, session_id, ip_address, user_agent
+SELECT id, order_reference, tenant_id, status, subtotal_amount, delivery_fee, discount_amount, voucher_code, promotion_discount_amount, loyalty_points_redeemed, loyalty_discount_amount, service_charge_rate, service_charge_amount, tax_rate, tax_amount, total_amount,
+       customer_name, customer_phone, customer_email, delivery_type, table_number, notes, scheduled_for, payment_fallback, queue_number, delivery_status,
+       created_at, paid_at, completed_at, cancelled_at, session_id, ip_address, user_agent,
+       order_type, is_anonymized, anonymized_at
 FROM guest_orders
 WHERE id = $1
 `
@@ -157,6 +172,15 @@ WHERE id = $1
 		&order.Status,
 		&order.SubtotalAmount,
 		&order.DeliveryFee,
+		&order.DiscountAmount,
+		&order.VoucherCode,
+		&order.PromotionDiscountAmount,
+		&order.LoyaltyPointsRedeemed,
+		&order.LoyaltyDiscountAmount,
+		&order.ServiceChargeRate,
+		&order.ServiceChargeAmount,
+		&order.TaxRate,
+		&order.TaxAmount,
 		&order.TotalAmount,
 		&encryptedName,
 		&encryptedPhone,
@@ -164,6 +188,10 @@ WHERE id = $1
 		&order.DeliveryType,
 		&order.TableNumber,
 		&order.Notes,
+		&order.ScheduledFor,
+		&order.PaymentFallback,
+		&order.QueueNumber,
+		&order.DeliveryStatus,
 		&order.CreatedAt,
 		&order.PaidAt,
 		&order.CompletedAt,
@@ -171,6 +199,9 @@ WHERE id = $1
 		&sessionID,
 		&encryptedIP,
 		&encryptedUA,
+		&order.OrderType,
+		&order.IsAnonymized,
+		&order.AnonymizedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -189,6 +220,11 @@ WHERE id = $1
 		order.SessionID = sessionID.String
 	}
 
+	// Anonymized orders only hold placeholder PII, so there is nothing to decrypt
+	if order.IsAnonymized {
+		return &order, nil
+	}
+
 	// Decrypt PII fields
 	if encryptedName.Valid {
 		if order.CustomerName, err = r.encryptor.DecryptWithContext(ctx, encryptedName.String, "guest_order:customer_name"); err != nil {
@@ -260,6 +296,186 @@ WHERE id = $5
 	return nil
 }
 
+// ReplaceOrderItems swaps an order's items for a new set within tx
+func (r *OrderRepository) ReplaceOrderItems(ctx context.Context, tx *sql.Tx, orderID string, items []models.OrderItem) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_id = $1`, orderID); err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to delete order items")
+		return err
+	}
+
+	query := `
+INSERT INTO order_items (order_id, product_id, product_name, quantity, unit_price, total_price)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+	for _, item := range items {
+		if _, err := tx.ExecContext(ctx, query, orderID, item.ProductID, item.ProductName, item.Quantity, item.UnitPrice, item.TotalPrice); err != nil {
+			log.Error().Err(err).Str("order_id", orderID).Str("product_id", item.ProductID).Msg("Failed to insert order item")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateOrderPricing stores the amounts of a repriced order and who changed it
+func (r *OrderRepository) UpdateOrderPricing(ctx context.Context, tx *sql.Tx, order *models.GuestOrder, modifiedByUserID string) error {
+	query := `
+UPDATE guest_orders
+SET subtotal_amount = $1,
+    promotion_discount_amount = $2,
+    discount_amount = $3,
+    service_charge_amount = $4,
+    tax_amount = $5,
+    total_amount = $6,
+    last_modified_by_user_id = $7,
+    last_modified_at = NOW()
+WHERE id = $8
+`
+
+	_, err := tx.ExecContext(ctx, query,
+		order.SubtotalAmount,
+		order.PromotionDiscountAmount,
+		order.DiscountAmount,
+		order.ServiceChargeAmount,
+		order.TaxAmount,
+		order.TotalAmount,
+		modifiedByUserID,
+		order.ID,
+	)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Msg("Failed to update order pricing")
+		return err
+	}
+
+	return nil
+}
+
+// ListAutoCompleteCandidates lists a tenant's PAID orders paid before the cutoff, oldest first
+// Scheduled orders also wait until their slot has passed the cutoff, so an order paid
+// today for tomorrow is not completed before it is picked up or delivered.
+// Disputed orders are included and flagged so they can be reported as excluded.
+func (r *OrderRepository) ListAutoCompleteCandidates(ctx context.Context, tenantID string, paidBefore time.Time, limit int) ([]*models.AutoCompleteCandidate, error) {
+	query := `
+SELECT id, order_reference, paid_at, disputed_at IS NOT NULL
+FROM guest_orders
+WHERE tenant_id = $1 AND status = 'PAID' AND paid_at < $2
+  AND (scheduled_for IS NULL OR scheduled_for < $2)
+ORDER BY paid_at ASC
+LIMIT $3
+`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, paidBefore, limit)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to query auto-complete candidates")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*models.AutoCompleteCandidate
+	for rows.Next() {
+		var candidate models.AutoCompleteCandidate
+		if err := rows.Scan(&candidate.OrderID, &candidate.OrderReference, &candidate.PaidAt, &candidate.Disputed); err != nil {
+			log.Error().Err(err).Msg("Failed to scan auto-complete candidate row")
+			return nil, err
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	return candidates, rows.Err()
+}
+
+// CompletePaidOrder marks a PAID, undisputed order as COMPLETE
+// Returns false when the order changed status or was disputed since it was listed.
+func (r *OrderRepository) CompletePaidOrder(ctx context.Context, orderID string, completedAt time.Time) (bool, error) {
+	query := `
+UPDATE guest_orders
+SET status = 'COMPLETE',
+    completed_at = $2
+WHERE id = $1 AND status = 'PAID' AND disputed_at IS NULL
+`
+
+	result, err := r.db.ExecContext(ctx, query, orderID, completedAt)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to auto-complete order")
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// SetOrderDispute flags an order as disputed, or clears the flag when reason is nil
+func (r *OrderRepository) SetOrderDispute(ctx context.Context, orderID string, reason *string) error {
+	query := `
+UPDATE guest_orders
+SET disputed_at = CASE WHEN $2::text IS NULL THEN NULL ELSE COALESCE(disputed_at, NOW()) END,
+    dispute_reason = $2
+WHERE id = $1
+`
+
+	if _, err := r.db.ExecContext(ctx, query, orderID, reason); err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to update order dispute")
+		return err
+	}
+
+	return nil
+}
+
+// SetPaymentFallback flags how payment for an order is collected when its online charge could not be created
+func (r *OrderRepository) SetPaymentFallback(ctx context.Context, tx *sql.Tx, orderID, fallback string) error {
+	query := `
+UPDATE guest_orders
+SET payment_fallback = $2
+WHERE id = $1
+`
+
+	if _, err := tx.ExecContext(ctx, query, orderID, fallback); err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to set order payment fallback")
+		return err
+	}
+
+	return nil
+}
+
+// tenantQueueDate is today's business day in the tenant's timezone ($1 is the tenant ID)
+const tenantQueueDate = `(NOW() AT TIME ZONE COALESCE(
+	(SELECT auto_complete_timezone FROM order_settings WHERE tenant_id = $1), 'Asia/Jakarta'))::date`
+
+// AssignQueueNumber gives a paid order the next queue number of the tenant's business day within tx
+// Returns nil when the order already has a number.
+func (r *OrderRepository) AssignQueueNumber(ctx context.Context, tx *sql.Tx, tenantID, orderID string) (*int, error) {
+	query := `
+WITH counter AS (
+	INSERT INTO order_queue_counters (tenant_id, queue_date, last_number)
+	SELECT $1::uuid, ` + tenantQueueDate + `, 1
+	WHERE NOT EXISTS (SELECT 1 FROM guest_orders WHERE id = $2 AND queue_number IS NOT NULL)
+	ON CONFLICT (tenant_id, queue_date) DO UPDATE SET last_number = order_queue_counters.last_number + 1
+	RETURNING queue_date, last_number
+)
+UPDATE guest_orders o
+SET queue_number = counter.last_number, queue_date = counter.queue_date
+FROM counter
+WHERE o.id = $2
+RETURNING o.queue_number
+`
+
+	var queueNumber int
+	err := tx.QueryRowContext(ctx, query, tenantID, orderID).Scan(&queueNumber)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to assign order queue number")
+		return nil, err
+	}
+
+	return &queueNumber, nil
+}
+
 // UpdateOrderNotes updates the notes field of an order
 func (r *OrderRepository) UpdateOrderNotes(ctx context.Context, orderID, notes string) error {
 	query := `
@@ -284,17 +500,21 @@ WHERE id = $2
 	return nil
 }
 
-// ListOrdersByTenant retrieves orders for a tenant with optional status filter
+// ListOrdersByTenant retrieves orders for a tenant with optional status, anonymization and contact filters
+// Anonymized orders have no contact hashes, so a contact search never matches them.
 func (r *OrderRepository) ListOrdersByTenant(
 	ctx context.Context,
 	tenantID string,
 	status *models.OrderStatus,
+	anonymized models.AnonymizedFilter,
+	contact *models.ContactSearch,
 	limit, offset int,
 ) ([]*models.GuestOrder, error) {
 	query := `
-SELECT id, order_reference, tenant_id, status, subtotal_amount, delivery_fee, total_amount,
-       customer_name, customer_phone, customer_email, delivery_type, table_number, notes,
-       created_at, paid_at, completed_at, cancelled_at, session_id, ip_address, user_agent
+SELECT id, order_reference, tenant_id, status, subtotal_amount, delivery_fee, discount_amount, voucher_code, promotion_discount_amount, loyalty_points_redeemed, loyalty_discount_amount, service_charge_rate, service_charge_amount, tax_rate, tax_amount, total_amount,
+       customer_name, customer_phone, customer_email, delivery_type, table_number, notes, scheduled_for, payment_fallback,
+       created_at, paid_at, completed_at, cancelled_at, session_id, ip_address, user_agent,
+       order_type, is_anonymized, anonymized_at
 FROM guest_orders
 WHERE tenant_id = $1
 `
@@ -304,11 +524,24 @@ WHERE tenant_id = $1
 
 	if status != nil {
 		argCount++
-		query += ` AND status = $` + string(rune(argCount+'0'))
+		query += fmt.Sprintf(" AND status = $%d", argCount)
 		args = append(args, *status)
 	}
 
-	query += ` ORDER BY created_at DESC LIMIT $` + string(rune(argCount+1+'0')) + ` OFFSET $` + string(rune(argCount+2+'0'))
+	if contact != nil {
+		argCount++
+		query += fmt.Sprintf(" AND %s = $%d", contactSearchColumn(contact), argCount)
+		args = append(args, utils.HashForSearch(contact.Value))
+	}
+
+	switch anonymized {
+	case models.AnonymizedFilterExclude:
+		query += ` AND is_anonymized = FALSE`
+	case models.AnonymizedFilterOnly:
+		query += ` AND is_anonymized = TRUE`
+	}
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", argCount+1, argCount+2)
 	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -335,6 +568,15 @@ WHERE tenant_id = $1
 			&order.Status,
 			&order.SubtotalAmount,
 			&order.DeliveryFee,
+			&order.DiscountAmount,
+			&order.VoucherCode,
+			&order.PromotionDiscountAmount,
+			&order.LoyaltyPointsRedeemed,
+			&order.LoyaltyDiscountAmount,
+			&order.ServiceChargeRate,
+			&order.ServiceChargeAmount,
+			&order.TaxRate,
+			&order.TaxAmount,
 			&order.TotalAmount,
 			&encryptedName,
 			&encryptedPhone,
@@ -342,6 +584,8 @@ WHERE tenant_id = $1
 			&order.DeliveryType,
 			&order.TableNumber,
 			&order.Notes,
+			&order.ScheduledFor,
+			&order.PaymentFallback,
 			&order.CreatedAt,
 			&order.PaidAt,
 			&order.CompletedAt,
@@ -349,6 +593,9 @@ WHERE tenant_id = $1
 			&sessionID,
 			&encryptedIP,
 			&encryptedUA,
+			&order.OrderType,
+			&order.IsAnonymized,
+			&order.AnonymizedAt,
 		)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to scan order row")
@@ -359,6 +606,12 @@ WHERE tenant_id = $1
 			order.SessionID = sessionID.String
 		}
 
+		// Anonymized orders only hold placeholder PII, so skip decryption
+		if order.IsAnonymized {
+			orders = append(orders, &order)
+			continue
+		}
+
 		// Decrypt PII fields
 		if encryptedName.Valid {
 			if order.CustomerName, err = r.encryptor.DecryptWithContext(ctx, encryptedName.String, "guest_order:customer_name"); err != nil {
@@ -402,10 +655,64 @@ WHERE tenant_id = $1
 	return orders, nil
 }
 
+// HasOrdersByPhoneHash reports whether a tenant has orders placed with a phone number
+// phoneHash is the search hash of the normalized phone number.
+func (r *OrderRepository) HasOrdersByPhoneHash(ctx context.Context, tenantID, phoneHash string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM guest_orders
+	WHERE tenant_id = $1 AND customer_phone_hash = $2 AND deleted_at IS NULL
+)`, tenantID, phoneHash).Scan(&exists)
+	return exists, err
+}
+
+// ListOrderHistory retrieves a guest's orders with a tenant by phone search hash, newest first
+// Anonymized orders have no phone hash, so they are never returned.
+func (r *OrderRepository) ListOrderHistory(ctx context.Context, tenantID, phoneHash string, limit, offset int) ([]*models.OrderHistoryEntry, error) {
+	query := `
+SELECT o.order_reference, o.status, o.delivery_type, o.total_amount,
+       COALESCE((SELECT SUM(i.quantity) FROM order_items i WHERE i.order_id = o.id), 0),
+       o.created_at, o.paid_at, o.completed_at, o.cancelled_at
+FROM guest_orders o
+WHERE o.tenant_id = $1 AND o.customer_phone_hash = $2 AND o.deleted_at IS NULL
+ORDER BY o.created_at DESC
+LIMIT $3 OFFSET $4
+`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, phoneHash, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.OrderHistoryEntry{}
+	for rows.Next() {
+		var entry models.OrderHistoryEntry
+		if err := rows.Scan(
+			&entry.OrderReference,
+			&entry.Status,
+			&entry.DeliveryType,
+			&entry.TotalAmount,
+			&entry.ItemCount,
+			&entry.CreatedAt,
+			&entry.PaidAt,
+			&entry.CompletedAt,
+			&entry.CancelledAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
 // GetOrderItemsByOrderID retrieves all items for a specific order
 func (r *OrderRepository) GetOrderItemsByOrderID(ctx context.Context, orderID string) ([]models.OrderItem, error) {
 	query := `
-SELECT id, order_id, product_id, product_name, unit_price, quantity, total_price
+SELECT id, order_id, product_id, product_name, unit_price, quantity, total_price, prep_status,
+       prep_started_at, prep_ready_at
 FROM order_items
 WHERE order_id = $1
 ORDER BY id
@@ -429,6 +736,9 @@ ORDER BY id
 			&item.UnitPrice,
 			&item.Quantity,
 			&item.TotalPrice,
+			&item.PrepStatus,
+			&item.PrepStartedAt,
+			&item.PrepReadyAt,
 		)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to scan order item row")
@@ -440,6 +750,36 @@ ORDER BY id
 	return items, rows.Err()
 }
 
+// GetOrderItemCosts maps the products of an order to their current unit cost
+// Order items do not snapshot cost, so products that no longer exist are left out.
+func (r *OrderRepository) GetOrderItemCosts(ctx context.Context, orderID string) (map[string]int, error) {
+	query := `
+SELECT DISTINCT oi.product_id, ROUND(p.cost_price)::INTEGER
+FROM order_items oi
+JOIN products p ON p.id = oi.product_id
+WHERE oi.order_id = $1
+`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to query order item costs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	costs := map[string]int{}
+	for rows.Next() {
+		var productID string
+		var unitCost int
+		if err := rows.Scan(&productID, &unitCost); err != nil {
+			return nil, err
+		}
+		costs[productID] = unitCost
+	}
+
+	return costs, rows.Err()
+}
+
 // CreateOrderNote adds a note to an order
 func (r *OrderRepository) CreateOrderNote(ctx context.Context, note *models.OrderNote) error {
 	query := `
@@ -459,6 +799,78 @@ RETURNING id, created_at
 	return nil
 }
 
+// CreateOrderNoteWithAttachments adds a note and its already uploaded attachments to an order
+func (r *OrderRepository) CreateOrderNoteWithAttachments(ctx context.Context, note *models.OrderNote) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+INSERT INTO order_notes (order_id, note, created_by_user_id, created_by_name)
+VALUES ($1, $2, $3, $4)
+RETURNING id, created_at
+`, note.OrderID, note.Note, note.CreatedByUserID, note.CreatedByName).Scan(&note.ID, &note.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", note.OrderID).Msg("Failed to create order note")
+		return err
+	}
+
+	for i := range note.Attachments {
+		attachment := &note.Attachments[i]
+		attachment.NoteID = note.ID
+		err = tx.QueryRowContext(ctx, `
+INSERT INTO order_note_attachments (id, note_id, order_id, storage_key, filename, content_type, size_bytes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING created_at
+`, attachment.ID, note.ID, note.OrderID, attachment.StorageKey, attachment.Filename, attachment.ContentType, attachment.SizeBytes).
+			Scan(&attachment.CreatedAt)
+		if err != nil {
+			log.Error().Err(err).Str("note_id", note.ID).Msg("Failed to create order note attachment")
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetOrderNoteAttachments retrieves the attachments of all notes of an order
+func (r *OrderRepository) GetOrderNoteAttachments(ctx context.Context, orderID string) ([]models.OrderNoteAttachment, error) {
+	query := `
+SELECT id, note_id, storage_key, filename, content_type, size_bytes, created_at
+FROM order_note_attachments
+WHERE order_id = $1
+ORDER BY created_at, id
+`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to query order note attachments")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.OrderNoteAttachment
+	for rows.Next() {
+		var attachment models.OrderNoteAttachment
+		if err := rows.Scan(
+			&attachment.ID,
+			&attachment.NoteID,
+			&attachment.StorageKey,
+			&attachment.Filename,
+			&attachment.ContentType,
+			&attachment.SizeBytes,
+			&attachment.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
 // GetOrderNotesByOrderID retrieves all notes for a specific order
 func (r *OrderRepository) GetOrderNotesByOrderID(ctx context.Context, orderID string) ([]*models.OrderNote, error) {
 	query := `
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// OrderReturnRepository handles returns (RMAs) and their items
+type OrderReturnRepository struct {
+	db *sql.DB
+}
+
+// NewOrderReturnRepository creates a new order return repository
+func NewOrderReturnRepository(db *sql.DB) *OrderReturnRepository {
+	return &OrderReturnRepository{db: db}
+}
+
+// getExecutor returns the transaction when one is given, otherwise the database handle
+func (r *OrderReturnRepository) getExecutor(tx *sql.Tx) interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+const orderReturnColumns = `
+	r.id, r.tenant_id, r.order_id, o.order_reference, r.return_number, r.status, r.resolution, r.reason,
+	r.credit_amount, r.rejection_reason, r.refund_id, r.store_credit_voucher_id, r.store_credit_code,
+	r.exchange_order_id, r.requested_by, r.approved_by, r.received_by, r.completed_by,
+	r.created_at, r.approved_at, r.rejected_at, r.received_at, r.completed_at, r.updated_at`
+
+func scanOrderReturn(row interface{ Scan(...interface{}) error }) (*models.OrderReturn, error) {
+	var ret models.OrderReturn
+	if err := row.Scan(
+		&ret.ID,
+		&ret.TenantID,
+		&ret.OrderID,
+		&ret.OrderReference,
+		&ret.ReturnNumber,
+		&ret.Status,
+		&ret.Resolution,
+		&ret.Reason,
+		&ret.CreditAmount,
+		&ret.RejectionReason,
+		&ret.RefundID,
+		&ret.StoreCreditVoucherID,
+		&ret.StoreCreditCode,
+		&ret.ExchangeOrderID,
+		&ret.RequestedBy,
+		&ret.ApprovedBy,
+		&ret.ReceivedBy,
+		&ret.CompletedBy,
+		&ret.CreatedAt,
+		&ret.ApprovedAt,
+		&ret.RejectedAt,
+		&ret.ReceivedAt,
+		&ret.CompletedAt,
+		&ret.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// LockOrder locks an order row so returns against it are created one at a time
+func (r *OrderReturnRepository) LockOrder(ctx context.Context, tx *sql.Tx, tenantID, orderID string) error {
+	var id string
+	return tx.QueryRowContext(ctx, `
+		SELECT id FROM guest_orders WHERE id = $1 AND tenant_id = $2 FOR UPDATE
+	`, orderID, tenantID).Scan(&id)
+}
+
+// GetReturnedQuantities returns how much of each order item is on returns that were not rejected
+func (r *OrderReturnRepository) GetReturnedQuantities(ctx context.Context, tx *sql.Tx, orderID string) (map[string]int, error) {
+	rows, err := r.getExecutor(tx).QueryContext(ctx, `
+		SELECT ri.order_item_id, SUM(ri.quantity)
+		FROM order_return_items ri
+		JOIN order_returns r ON r.id = ri.return_id
+		WHERE r.order_id = $1 AND r.status <> 'rejected'
+		GROUP BY ri.order_item_id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returned := make(map[string]int)
+	for rows.Next() {
+		var itemID string
+		var quantity int
+		if err := rows.Scan(&itemID, &quantity); err != nil {
+			return nil, err
+		}
+		returned[itemID] = quantity
+	}
+	return returned, rows.Err()
+}
+
+// GetCreditedAmount returns the credit of an order's returns that were not rejected
+func (r *OrderReturnRepository) GetCreditedAmount(ctx context.Context, tx *sql.Tx, orderID string) (int, error) {
+	var credited int
+	err := r.getExecutor(tx).QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(credit_amount), 0) FROM order_returns WHERE order_id = $1 AND status <> 'rejected'
+	`, orderID).Scan(&credited)
+	return credited, err
+}
+
+// Create inserts a return and its items
+// Returns false when the return number is already taken so the caller can pick another.
+func (r *OrderReturnRepository) Create(ctx context.Context, tx *sql.Tx, ret *models.OrderReturn) (bool, error) {
+	_, err := tx.ExecContext(ctx, `SAVEPOINT create_return`)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO order_returns (tenant_id, order_id, return_number, status, resolution, reason, credit_amount, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`, ret.TenantID, ret.OrderID, ret.ReturnNumber, string(ret.Status), string(ret.Resolution), ret.Reason, ret.CreditAmount, ret.RequestedBy).
+		Scan(&ret.ID, &ret.CreatedAt, &ret.UpdatedAt)
+	if isUniqueViolation(err) {
+		if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT create_return`); rbErr != nil {
+			return false, rbErr
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for i := range ret.Items {
+		item := &ret.Items[i]
+		item.ReturnID = ret.ID
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO order_return_items (return_id, order_item_id, product_id, product_name, quantity, unit_price, reason)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, ret.ID, item.OrderItemID, item.ProductID, item.ProductName, item.Quantity, item.UnitPrice, item.Reason).Scan(&item.ID); err != nil {
+			return false, fmt.Errorf("failed to insert return item: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// Get returns one of a tenant's returns with its items
+// With a transaction the return row is locked until it ends.
+func (r *OrderReturnRepository) Get(ctx context.Context, tx *sql.Tx, tenantID, returnID string) (*models.OrderReturn, error) {
+	query := `
+		SELECT ` + orderReturnColumns + `
+		FROM order_returns r
+		JOIN guest_orders o ON o.id = r.order_id
+		WHERE r.id = $1 AND r.tenant_id = $2`
+	if tx != nil {
+		query += ` FOR UPDATE OF r`
+	}
+
+	ret, err := scanOrderReturn(r.getExecutor(tx).QueryRowContext(ctx, query, returnID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrReturnNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, tx, []*models.OrderReturn{ret}); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ListByOrder returns an order's returns, newest first
+func (r *OrderReturnRepository) ListByOrder(ctx context.Context, tenantID, orderID string) ([]*models.OrderReturn, error) {
+	return r.list(ctx, `
+		SELECT `+orderReturnColumns+`
+		FROM order_returns r
+		JOIN guest_orders o ON o.id = r.order_id
+		WHERE r.tenant_id = $1 AND r.order_id = $2
+		ORDER BY r.created_at DESC
+	`, tenantID, orderID)
+}
+
+// List returns a tenant's returns, newest first, optionally by status
+func (r *OrderReturnRepository) List(ctx context.Context, tenantID string, status *models.ReturnStatus, limit, offset int) ([]*models.OrderReturn, error) {
+	query := `
+		SELECT ` + orderReturnColumns + `
+		FROM order_returns r
+		JOIN guest_orders o ON o.id = r.order_id
+		WHERE r.tenant_id = $1`
+	args := []interface{}{tenantID}
+	if status != nil {
+		query += ` AND r.status = $2`
+		args = append(args, string(*status))
+	}
+	query += fmt.Sprintf(` ORDER BY r.created_at DESC LIMIT %d OFFSET %d`, limit, offset)
+
+	return r.list(ctx, query, args...)
+}
+
+func (r *OrderReturnRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.OrderReturn, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returns := []*models.OrderReturn{}
+	for rows.Next() {
+		ret, err := scanOrderReturn(rows)
+		if err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, nil, returns); err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+// loadItems fills in the items of the given returns
+func (r *OrderReturnRepository) loadItems(ctx context.Context, tx *sql.Tx, returns []*models.OrderReturn) error {
+	if len(returns) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(returns))
+	byID := make(map[string]*models.OrderReturn, len(returns))
+	for i, ret := range returns {
+		ids[i] = ret.ID
+		ret.Items = []models.OrderReturnItem{}
+		byID[ret.ID] = ret
+	}
+
+	rows, err := r.getExecutor(tx).QueryContext(ctx, `
+		SELECT id, return_id, order_item_id, product_id, product_name, quantity, unit_price, reason, restocked
+		FROM order_return_items
+		WHERE return_id = ANY($1)
+		ORDER BY product_name
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.OrderReturnItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.ReturnID,
+			&item.OrderItemID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
+			&item.UnitPrice,
+			&item.Reason,
+			&item.Restocked,
+		); err != nil {
+			return err
+		}
+		ret := byID[item.ReturnID]
+		ret.Items = append(ret.Items, item)
+	}
+	return rows.Err()
+}
+
+// Update stores a return's status, resolution outcome and who moved it along
+func (r *OrderReturnRepository) Update(ctx context.Context, tx *sql.Tx, ret *models.OrderReturn) error {
+	return r.getExecutor(tx).QueryRowContext(ctx, `
+		UPDATE order_returns
+		SET status = $2, rejection_reason = $3, refund_id = $4, store_credit_voucher_id = $5, store_credit_code = $6,
+		    exchange_order_id = $7, approved_by = $8, received_by = $9, completed_by = $10,
+		    approved_at = $11, rejected_at = $12, received_at = $13, completed_at = $14, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, ret.ID, string(ret.Status), ret.RejectionReason, ret.RefundID, ret.StoreCreditVoucherID, ret.StoreCreditCode,
+		ret.ExchangeOrderID, ret.ApprovedBy, ret.ReceivedBy, ret.CompletedBy,
+		ret.ApprovedAt, ret.RejectedAt, ret.ReceivedAt, ret.CompletedAt).
+		Scan(&ret.UpdatedAt)
+}
+
+// MarkItemRestocked records that a return item's goods went back into stock
+func (r *OrderReturnRepository) MarkItemRestocked(ctx context.Context, tx *sql.Tx, itemID string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE order_return_items SET restocked = true WHERE id = $1`, itemID)
+	return err
+}
@@ -24,7 +24,14 @@ func (r *OrderSettingsRepository) GetByTenantID(ctx context.Context, tenantID st
 		SELECT id, tenant_id, delivery_enabled, pickup_enabled, dine_in_enabled,
 		       default_delivery_fee, min_order_amount, max_delivery_distance,
 		       estimated_prep_time, auto_accept_orders, require_phone_verification,
-		       charge_delivery_fee, created_at, updated_at
+		       charge_delivery_fee, auto_complete_mode, auto_complete_after_hours,
+		       auto_complete_timezone, service_charge_percent, tax_percent, scheduling_enabled,
+		       business_hours, slot_duration_minutes, slot_capacity, scheduling_max_days_ahead,
+		       holidays, ordering_cutoff_minutes, closed_order_policy,
+		       min_order_amount_by_delivery_type, max_items_per_order, payment_outage_policy, guest_cancel_window_minutes,
+		       payment_expiry_minutes, refund_approval_threshold, reservation_ttl_minutes, reservation_extension_minutes,
+		       order_reference_pattern, sla_preparing_minutes, sla_ready_minutes, sla_at_risk_percent,
+		       created_at, updated_at
 		FROM order_settings
 		WHERE tenant_id = $1
 	`
@@ -43,6 +50,31 @@ func (r *OrderSettingsRepository) GetByTenantID(ctx context.Context, tenantID st
 		&settings.AutoAcceptOrders,
 		&settings.RequirePhoneVerification,
 		&settings.ChargeDeliveryFee,
+		&settings.AutoCompleteMode,
+		&settings.AutoCompleteAfterHours,
+		&settings.AutoCompleteTimezone,
+		&settings.ServiceChargePercent,
+		&settings.TaxPercent,
+		&settings.SchedulingEnabled,
+		&settings.BusinessHours,
+		&settings.SlotDurationMinutes,
+		&settings.SlotCapacity,
+		&settings.SchedulingMaxDaysAhead,
+		&settings.Holidays,
+		&settings.OrderingCutoffMinutes,
+		&settings.ClosedOrderPolicy,
+		&settings.MinOrderAmountByDeliveryType,
+		&settings.MaxItemsPerOrder,
+		&settings.PaymentOutagePolicy,
+		&settings.GuestCancelWindowMinutes,
+		&settings.PaymentExpiryMinutes,
+		&settings.RefundApprovalThreshold,
+		&settings.ReservationTTLMinutes,
+		&settings.ReservationExtensionMinutes,
+		&settings.OrderReferencePattern,
+		&settings.SLAPreparingMinutes,
+		&settings.SLAReadyMinutes,
+		&settings.SLAAtRiskPercent,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
 	)
@@ -68,7 +100,14 @@ func (r *OrderSettingsRepository) Create(ctx context.Context, tenantID string) (
 		RETURNING id, tenant_id, delivery_enabled, pickup_enabled, dine_in_enabled,
 		          default_delivery_fee, min_order_amount, max_delivery_distance,
 		          estimated_prep_time, auto_accept_orders, require_phone_verification,
-		          charge_delivery_fee, created_at, updated_at
+		          charge_delivery_fee, auto_complete_mode, auto_complete_after_hours,
+		          auto_complete_timezone, service_charge_percent, tax_percent, scheduling_enabled,
+		          business_hours, slot_duration_minutes, slot_capacity, scheduling_max_days_ahead,
+		          holidays, ordering_cutoff_minutes, closed_order_policy,
+		          min_order_amount_by_delivery_type, max_items_per_order, payment_outage_policy, guest_cancel_window_minutes,
+		          payment_expiry_minutes, refund_approval_threshold, reservation_ttl_minutes, reservation_extension_minutes,
+		          order_reference_pattern, sla_preparing_minutes, sla_ready_minutes, sla_at_risk_percent,
+		          created_at, updated_at
 	`
 
 	var settings models.OrderSettings
@@ -97,6 +136,31 @@ func (r *OrderSettingsRepository) Create(ctx context.Context, tenantID string) (
 		&settings.AutoAcceptOrders,
 		&settings.RequirePhoneVerification,
 		&settings.ChargeDeliveryFee,
+		&settings.AutoCompleteMode,
+		&settings.AutoCompleteAfterHours,
+		&settings.AutoCompleteTimezone,
+		&settings.ServiceChargePercent,
+		&settings.TaxPercent,
+		&settings.SchedulingEnabled,
+		&settings.BusinessHours,
+		&settings.SlotDurationMinutes,
+		&settings.SlotCapacity,
+		&settings.SchedulingMaxDaysAhead,
+		&settings.Holidays,
+		&settings.OrderingCutoffMinutes,
+		&settings.ClosedOrderPolicy,
+		&settings.MinOrderAmountByDeliveryType,
+		&settings.MaxItemsPerOrder,
+		&settings.PaymentOutagePolicy,
+		&settings.GuestCancelWindowMinutes,
+		&settings.PaymentExpiryMinutes,
+		&settings.RefundApprovalThreshold,
+		&settings.ReservationTTLMinutes,
+		&settings.ReservationExtensionMinutes,
+		&settings.OrderReferencePattern,
+		&settings.SLAPreparingMinutes,
+		&settings.SLAReadyMinutes,
+		&settings.SLAAtRiskPercent,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
 	)
@@ -137,12 +201,44 @@ func (r *OrderSettingsRepository) Update(ctx context.Context, tenantID string, r
 			auto_accept_orders = COALESCE($9, auto_accept_orders),
 			require_phone_verification = COALESCE($10, require_phone_verification),
 			charge_delivery_fee = COALESCE($11, charge_delivery_fee),
+			auto_complete_mode = COALESCE($12, auto_complete_mode),
+			auto_complete_after_hours = COALESCE($13, auto_complete_after_hours),
+			auto_complete_timezone = COALESCE($14, auto_complete_timezone),
+			service_charge_percent = COALESCE($15, service_charge_percent),
+			tax_percent = COALESCE($16, tax_percent),
+			scheduling_enabled = COALESCE($17, scheduling_enabled),
+			business_hours = COALESCE($18::jsonb, business_hours),
+			slot_duration_minutes = COALESCE($19, slot_duration_minutes),
+			slot_capacity = COALESCE($20, slot_capacity),
+			scheduling_max_days_ahead = COALESCE($21, scheduling_max_days_ahead),
+			min_order_amount_by_delivery_type = COALESCE($22::jsonb, min_order_amount_by_delivery_type),
+			max_items_per_order = COALESCE($23, max_items_per_order),
+			payment_outage_policy = COALESCE($24, payment_outage_policy),
+			guest_cancel_window_minutes = COALESCE($25, guest_cancel_window_minutes),
+			payment_expiry_minutes = COALESCE($26, payment_expiry_minutes),
+			refund_approval_threshold = COALESCE($27, refund_approval_threshold),
+			reservation_ttl_minutes = COALESCE($28, reservation_ttl_minutes),
+			reservation_extension_minutes = COALESCE($29, reservation_extension_minutes),
+			order_reference_pattern = CASE WHEN $30::text IS NULL THEN order_reference_pattern ELSE NULLIF($30, '') END,
+			sla_preparing_minutes = COALESCE($31, sla_preparing_minutes),
+			sla_ready_minutes = COALESCE($32, sla_ready_minutes),
+			sla_at_risk_percent = COALESCE($33, sla_at_risk_percent),
+			holidays = COALESCE($34::jsonb, holidays),
+			ordering_cutoff_minutes = COALESCE($35, ordering_cutoff_minutes),
+			closed_order_policy = COALESCE($36, closed_order_policy),
 			updated_at = NOW()
 		WHERE tenant_id = $1
 		RETURNING id, tenant_id, delivery_enabled, pickup_enabled, dine_in_enabled,
 		          default_delivery_fee, min_order_amount, max_delivery_distance,
 		          estimated_prep_time, auto_accept_orders, require_phone_verification,
-		          charge_delivery_fee, created_at, updated_at
+		          charge_delivery_fee, auto_complete_mode, auto_complete_after_hours,
+		          auto_complete_timezone, service_charge_percent, tax_percent, scheduling_enabled,
+		          business_hours, slot_duration_minutes, slot_capacity, scheduling_max_days_ahead,
+		          holidays, ordering_cutoff_minutes, closed_order_policy,
+		          min_order_amount_by_delivery_type, max_items_per_order, payment_outage_policy, guest_cancel_window_minutes,
+		          payment_expiry_minutes, refund_approval_threshold, reservation_ttl_minutes, reservation_extension_minutes,
+		          order_reference_pattern, sla_preparing_minutes, sla_ready_minutes, sla_at_risk_percent,
+		          created_at, updated_at
 	`
 
 	var settings models.OrderSettings
@@ -158,6 +254,31 @@ func (r *OrderSettingsRepository) Update(ctx context.Context, tenantID string, r
 		req.AutoAcceptOrders,
 		req.RequirePhoneVerification,
 		req.ChargeDeliveryFee,
+		req.AutoCompleteMode,
+		req.AutoCompleteAfterHours,
+		req.AutoCompleteTimezone,
+		req.ServiceChargePercent,
+		req.TaxPercent,
+		req.SchedulingEnabled,
+		req.BusinessHours,
+		req.SlotDurationMinutes,
+		req.SlotCapacity,
+		req.SchedulingMaxDaysAhead,
+		req.MinOrderAmountByDeliveryType,
+		req.MaxItemsPerOrder,
+		req.PaymentOutagePolicy,
+		req.GuestCancelWindowMinutes,
+		req.PaymentExpiryMinutes,
+		req.RefundApprovalThreshold,
+		req.ReservationTTLMinutes,
+		req.ReservationExtensionMinutes,
+		req.OrderReferencePattern,
+		req.SLAPreparingMinutes,
+		req.SLAReadyMinutes,
+		req.SLAAtRiskPercent,
+		req.Holidays,
+		req.OrderingCutoffMinutes,
+		req.ClosedOrderPolicy,
 	).Scan(
 		&settings.ID,
 		&settings.TenantID,
@@ -171,9 +292,38 @@ func (r *OrderSettingsRepository) Update(ctx context.Context, tenantID string, r
 		&settings.AutoAcceptOrders,
 		&settings.RequirePhoneVerification,
 		&settings.ChargeDeliveryFee,
+		&settings.AutoCompleteMode,
+		&settings.AutoCompleteAfterHours,
+		&settings.AutoCompleteTimezone,
+		&settings.ServiceChargePercent,
+		&settings.TaxPercent,
+		&settings.SchedulingEnabled,
+		&settings.BusinessHours,
+		&settings.SlotDurationMinutes,
+		&settings.SlotCapacity,
+		&settings.SchedulingMaxDaysAhead,
+		&settings.Holidays,
+		&settings.OrderingCutoffMinutes,
+		&settings.ClosedOrderPolicy,
+		&settings.MinOrderAmountByDeliveryType,
+		&settings.MaxItemsPerOrder,
+		&settings.PaymentOutagePolicy,
+		&settings.GuestCancelWindowMinutes,
+		&settings.PaymentExpiryMinutes,
+		&settings.RefundApprovalThreshold,
+		&settings.ReservationTTLMinutes,
+		&settings.ReservationExtensionMinutes,
+		&settings.OrderReferencePattern,
+		&settings.SLAPreparingMinutes,
+		&settings.SLAReadyMinutes,
+		&settings.SLAAtRiskPercent,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
 	)
+	if isUniqueViolation(err) {
+		// The only unique column a tenant sets is its order reference prefix
+		return nil, models.ErrOrderReferencePrefixTaken
+	}
 	if err != nil {
 		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to update order settings")
 		return nil, err
@@ -195,3 +345,104 @@ func (r *OrderSettingsRepository) GetOrCreate(ctx context.Context, tenantID stri
 
 	return settings, nil
 }
+
+// ListAutoCompleteEnabled returns the settings of every tenant with an auto-complete rule
+func (r *OrderSettingsRepository) ListAutoCompleteEnabled(ctx context.Context) ([]*models.OrderSettings, error) {
+	query := `
+		SELECT id, tenant_id, delivery_enabled, pickup_enabled, dine_in_enabled,
+		       default_delivery_fee, min_order_amount, max_delivery_distance,
+		       estimated_prep_time, auto_accept_orders, require_phone_verification,
+		       charge_delivery_fee, auto_complete_mode, auto_complete_after_hours,
+		       auto_complete_timezone, service_charge_percent, tax_percent, scheduling_enabled,
+		       business_hours, slot_duration_minutes, slot_capacity, scheduling_max_days_ahead,
+		       holidays, ordering_cutoff_minutes, closed_order_policy,
+		       min_order_amount_by_delivery_type, max_items_per_order, payment_outage_policy, guest_cancel_window_minutes,
+		       payment_expiry_minutes, refund_approval_threshold, reservation_ttl_minutes, reservation_extension_minutes,
+		       order_reference_pattern, sla_preparing_minutes, sla_ready_minutes, sla_at_risk_percent,
+		       created_at, updated_at
+		FROM order_settings
+		WHERE auto_complete_mode <> 'disabled'
+		ORDER BY tenant_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*models.OrderSettings
+	for rows.Next() {
+		var settings models.OrderSettings
+		if err := rows.Scan(
+			&settings.ID,
+			&settings.TenantID,
+			&settings.DeliveryEnabled,
+			&settings.PickupEnabled,
+			&settings.DineInEnabled,
+			&settings.DefaultDeliveryFee,
+			&settings.MinOrderAmount,
+			&settings.MaxDeliveryDistance,
+			&settings.EstimatedPrepTime,
+			&settings.AutoAcceptOrders,
+			&settings.RequirePhoneVerification,
+			&settings.ChargeDeliveryFee,
+			&settings.AutoCompleteMode,
+			&settings.AutoCompleteAfterHours,
+			&settings.AutoCompleteTimezone,
+			&settings.ServiceChargePercent,
+			&settings.TaxPercent,
+			&settings.SchedulingEnabled,
+			&settings.BusinessHours,
+			&settings.SlotDurationMinutes,
+			&settings.SlotCapacity,
+			&settings.SchedulingMaxDaysAhead,
+			&settings.Holidays,
+			&settings.OrderingCutoffMinutes,
+			&settings.ClosedOrderPolicy,
+			&settings.MinOrderAmountByDeliveryType,
+			&settings.MaxItemsPerOrder,
+			&settings.PaymentOutagePolicy,
+			&settings.GuestCancelWindowMinutes,
+			&settings.PaymentExpiryMinutes,
+			&settings.RefundApprovalThreshold,
+			&settings.ReservationTTLMinutes,
+			&settings.ReservationExtensionMinutes,
+			&settings.OrderReferencePattern,
+			&settings.SLAPreparingMinutes,
+			&settings.SLAReadyMinutes,
+			&settings.SLAAtRiskPercent,
+			&settings.CreatedAt,
+			&settings.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, &settings)
+	}
+
+	return result, rows.Err()
+}
+
+// ListSLAEnabledTenants returns the tenants with an SLA target for any order stage
+func (r *OrderSettingsRepository) ListSLAEnabledTenants(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tenant_id
+		FROM order_settings
+		WHERE sla_preparing_minutes > 0 OR sla_ready_minutes > 0
+		ORDER BY tenant_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, err
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	return tenantIDs, rows.Err()
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// OrderSLARepository records the late-order alerts sent to staff
+type OrderSLARepository struct {
+	db *sql.DB
+}
+
+// NewOrderSLARepository creates a new order SLA repository
+func NewOrderSLARepository(db *sql.DB) *OrderSLARepository {
+	return &OrderSLARepository{db: db}
+}
+
+// RecordAlert marks a late stage of an order as alerted
+// Returns false when the stage was already alerted, so each late stage is reported once.
+func (r *OrderSLARepository) RecordAlert(ctx context.Context, orderID string, stage models.SLAStage) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO order_sla_alerts (order_id, stage)
+		VALUES ($1, $2)
+		ON CONFLICT (order_id, stage) DO NOTHING
+	`, orderID, stage)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows == 1, err
+}
@@ -25,8 +25,8 @@ func NewOutboxRepository(db *sql.DB) *OutboxRepository {
 func (r *OutboxRepository) Create(ctx context.Context, tx *sql.Tx, event *models.EventOutbox) error {
 	query := `
 		INSERT INTO event_outbox (
-			event_type, event_key, event_payload, topic, created_at, retry_count
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			event_type, event_key, event_payload, topic, created_at, retry_count, correlation_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
 
@@ -39,6 +39,7 @@ func (r *OutboxRepository) Create(ctx context.Context, tx *sql.Tx, event *models
 		event.Topic,
 		time.Now(),
 		0,
+		event.CorrelationID,
 	).Scan(&event.ID)
 
 	if err != nil {
@@ -53,7 +54,7 @@ func (r *OutboxRepository) Create(ctx context.Context, tx *sql.Tx, event *models
 func (r *OutboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]models.EventOutbox, error) {
 	query := `
 		SELECT id, event_type, event_key, event_payload, topic, 
-		       created_at, published_at, retry_count, last_error
+		       created_at, published_at, retry_count, last_error, correlation_id
 		FROM event_outbox
 		WHERE published_at IS NULL
 		ORDER BY created_at ASC
@@ -79,6 +80,7 @@ func (r *OutboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]m
 			&event.PublishedAt,
 			&event.RetryCount,
 			&event.LastError,
+			&event.CorrelationID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event row: %w", err)
@@ -174,7 +176,7 @@ func (r *OutboxRepository) DeletePublishedEvents(ctx context.Context, olderThan
 func (r *OutboxRepository) GetFailedEvents(ctx context.Context, maxRetries int) ([]models.EventOutbox, error) {
 	query := `
 		SELECT id, event_type, event_key, event_payload, topic, 
-		       created_at, published_at, retry_count, last_error
+		       created_at, published_at, retry_count, last_error, correlation_id
 		FROM event_outbox
 		WHERE published_at IS NULL
 		  AND retry_count >= $1
@@ -200,6 +202,7 @@ func (r *OutboxRepository) GetFailedEvents(ctx context.Context, maxRetries int)
 			&event.PublishedAt,
 			&event.RetryCount,
 			&event.LastError,
+			&event.CorrelationID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan event row: %w", err)
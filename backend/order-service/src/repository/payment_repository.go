@@ -19,14 +19,20 @@ func NewPaymentRepository(db *sql.DB) *PaymentRepository {
 
 // CreatePaymentTransaction creates a new payment transaction record
 func (r *PaymentRepository) CreatePaymentTransaction(ctx context.Context, tx *sql.Tx, payment *models.PaymentTransaction) error {
+	if payment.Gateway == "" {
+		payment.Gateway = models.PaymentGatewayMidtrans
+	}
+
 	query := `
 		INSERT INTO payment_transactions (
 			order_id, midtrans_transaction_id, midtrans_order_id, amount,
 			payment_type, transaction_status, fraud_status,
 			notification_payload, signature_key, signature_verified,
 			qr_code_url, qr_string, expiry_time,
-			idempotency_key, notification_received_at, settled_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			idempotency_key, notification_received_at, settled_at,
+			payment_method, bank, va_number, deeplink_url, redirect_url, snap_token,
+			is_split_part, payment_gateway
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 		RETURNING id, created_at
 	`
 
@@ -49,19 +55,29 @@ func (r *PaymentRepository) CreatePaymentTransaction(ctx context.Context, tx *sq
 		payment.IdempotencyKey,
 		payment.NotificationReceivedAt,
 		payment.SettledAt,
+		payment.PaymentMethod,
+		payment.Bank,
+		payment.VANumber,
+		payment.DeeplinkURL,
+		payment.RedirectURL,
+		payment.SnapToken,
+		payment.IsSplitPart,
+		payment.Gateway,
 	).Scan(&payment.ID, &payment.CreatedAt)
 }
 
-// GetPaymentByOrderID retrieves payment transaction by order ID
+// GetPaymentByOrderID retrieves the latest full-amount payment transaction by order ID
 func (r *PaymentRepository) GetPaymentByOrderID(ctx context.Context, orderID string) (*models.PaymentTransaction, error) {
 	query := `
 		SELECT id, order_id, midtrans_transaction_id, midtrans_order_id,
 			amount, payment_type, transaction_status, fraud_status,
 			notification_payload, signature_key, signature_verified,
 			qr_code_url, qr_string, expiry_time,
-			created_at, notification_received_at, settled_at, idempotency_key
+			created_at, notification_received_at, settled_at, idempotency_key,
+			payment_method, bank, va_number, deeplink_url, redirect_url, snap_token,
+			is_split_part, payment_gateway
 		FROM payment_transactions
-		WHERE order_id = $1
+		WHERE order_id = $1 AND is_split_part = FALSE
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
@@ -86,6 +102,14 @@ func (r *PaymentRepository) GetPaymentByOrderID(ctx context.Context, orderID str
 		&payment.NotificationReceivedAt,
 		&payment.SettledAt,
 		&payment.IdempotencyKey,
+		&payment.PaymentMethod,
+		&payment.Bank,
+		&payment.VANumber,
+		&payment.DeeplinkURL,
+		&payment.RedirectURL,
+		&payment.SnapToken,
+		&payment.IsSplitPart,
+		&payment.Gateway,
 	)
 
 	if err == sql.ErrNoRows {
@@ -102,7 +126,9 @@ func (r *PaymentRepository) GetPaymentByTransactionID(ctx context.Context, trans
 			amount, payment_type, transaction_status, fraud_status,
 			notification_payload, signature_key, signature_verified,
 			qr_code_url, qr_string, expiry_time,
-			created_at, notification_received_at, settled_at, idempotency_key
+			created_at, notification_received_at, settled_at, idempotency_key,
+			payment_method, bank, va_number, deeplink_url, redirect_url, snap_token,
+			is_split_part, payment_gateway
 		FROM payment_transactions
 		WHERE midtrans_transaction_id = $1
 	`
@@ -127,6 +153,14 @@ func (r *PaymentRepository) GetPaymentByTransactionID(ctx context.Context, trans
 		&payment.NotificationReceivedAt,
 		&payment.SettledAt,
 		&payment.IdempotencyKey,
+		&payment.PaymentMethod,
+		&payment.Bank,
+		&payment.VANumber,
+		&payment.DeeplinkURL,
+		&payment.RedirectURL,
+		&payment.SnapToken,
+		&payment.IsSplitPart,
+		&payment.Gateway,
 	)
 
 	if err == sql.ErrNoRows {
@@ -143,7 +177,9 @@ func (r *PaymentRepository) GetPaymentByIdempotencyKey(ctx context.Context, idem
 			amount, payment_type, transaction_status, fraud_status,
 			notification_payload, signature_key, signature_verified,
 			qr_code_url, qr_string, expiry_time,
-			created_at, notification_received_at, settled_at, idempotency_key
+			created_at, notification_received_at, settled_at, idempotency_key,
+			payment_method, bank, va_number, deeplink_url, redirect_url, snap_token,
+			is_split_part, payment_gateway
 		FROM payment_transactions
 		WHERE idempotency_key = $1
 	`
@@ -168,6 +204,14 @@ func (r *PaymentRepository) GetPaymentByIdempotencyKey(ctx context.Context, idem
 		&payment.NotificationReceivedAt,
 		&payment.SettledAt,
 		&payment.IdempotencyKey,
+		&payment.PaymentMethod,
+		&payment.Bank,
+		&payment.VANumber,
+		&payment.DeeplinkURL,
+		&payment.RedirectURL,
+		&payment.SnapToken,
+		&payment.IsSplitPart,
+		&payment.Gateway,
 	)
 
 	if err == sql.ErrNoRows {
@@ -209,6 +253,73 @@ func (r *PaymentRepository) UpdatePaymentStatusByTransactionID(ctx context.Conte
 	return err
 }
 
+// ListUnresolvedExpiredCharges returns charges of PENDING orders that expired between
+// expiredAfter and expiredBefore without the gateway reporting a final status, oldest first
+func (r *PaymentRepository) ListUnresolvedExpiredCharges(ctx context.Context, expiredAfter, expiredBefore time.Time, limit int) ([]*models.PaymentTransaction, error) {
+	query := `
+		SELECT pt.id, pt.order_id, pt.midtrans_transaction_id, pt.midtrans_order_id,
+			pt.amount, pt.payment_type, pt.payment_method, pt.transaction_status,
+			pt.expiry_time, pt.created_at, pt.is_split_part, pt.payment_gateway
+		FROM payment_transactions pt
+		JOIN guest_orders o ON o.id = pt.order_id
+		WHERE o.status = 'PENDING'
+		  AND pt.settled_at IS NULL
+		  AND pt.expiry_time > $1 AND pt.expiry_time < $2
+		  AND (pt.transaction_status IS NULL
+		       OR LOWER(pt.transaction_status) NOT IN ('settlement', 'capture', 'cancel', 'deny', 'expire', 'failure'))
+		ORDER BY pt.expiry_time ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, expiredAfter, expiredBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	charges := []*models.PaymentTransaction{}
+	for rows.Next() {
+		charge := &models.PaymentTransaction{}
+		if err := rows.Scan(
+			&charge.ID,
+			&charge.OrderID,
+			&charge.MidtransTransactionID,
+			&charge.MidtransOrderID,
+			&charge.Amount,
+			&charge.PaymentType,
+			&charge.PaymentMethod,
+			&charge.TransactionStatus,
+			&charge.ExpiryTime,
+			&charge.CreatedAt,
+			&charge.IsSplitPart,
+			&charge.Gateway,
+		); err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+
+	return charges, rows.Err()
+}
+
+// ConfirmPaymentManually settles a charge whose gateway notification never arrived
+// The idempotency key is the one the settlement webhook would carry, so a late
+// notification is treated as already processed.
+func (r *PaymentRepository) ConfirmPaymentManually(ctx context.Context, id string, idempotencyKey *string, confirmedBy, reason string) error {
+	query := `
+		UPDATE payment_transactions
+		SET transaction_status = 'settlement',
+		    settled_at = COALESCE(settled_at, NOW()),
+		    idempotency_key = COALESCE($2, idempotency_key),
+		    manually_confirmed_by = $3,
+		    manual_confirmation_reason = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, idempotencyKey, confirmedBy, reason)
+	return err
+}
+
 // ============================================================================
 // Offline Order Payment Methods (T053-T057)
 // ============================================================================
@@ -302,8 +413,9 @@ func (r *PaymentRepository) RecordPayment(ctx context.Context, tx *sql.Tx, req *
 			order_id, payment_terms_id, payment_number,
 			amount_paid, payment_date, payment_method,
 			remaining_balance_after, recorded_by_user_id,
-			notes, receipt_number, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			notes, receipt_number, amount_tendered, change_amount,
+			card_type, approval_code, card_last4, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id
 	`
 
@@ -322,6 +434,11 @@ func (r *PaymentRepository) RecordPayment(ctx context.Context, tx *sql.Tx, req *
 		req.RecordedByUserID,
 		req.Notes,
 		req.ReceiptNumber,
+		req.AmountTendered,
+		req.ChangeAmount,
+		req.CardType,
+		req.ApprovalCode,
+		req.CardLast4,
 		time.Now(),
 	).Scan(&paymentRecordID)
 
@@ -354,7 +471,8 @@ func (r *PaymentRepository) GetPaymentHistory(ctx context.Context, orderID strin
 			id, order_id, payment_terms_id, payment_number,
 			amount_paid, payment_date, payment_method,
 			remaining_balance_after, recorded_by_user_id,
-			notes, receipt_number, created_at
+			notes, receipt_number, amount_tendered, change_amount,
+			card_type, approval_code, card_last4, created_at
 		FROM payment_records
 		WHERE order_id = $1
 		ORDER BY payment_date DESC, payment_number ASC
@@ -381,6 +499,11 @@ func (r *PaymentRepository) GetPaymentHistory(ctx context.Context, orderID strin
 			&record.RecordedByUserID,
 			&record.Notes,
 			&record.ReceiptNumber,
+			&record.AmountTendered,
+			&record.ChangeAmount,
+			&record.CardType,
+			&record.ApprovalCode,
+			&record.CardLast4,
 			&record.CreatedAt,
 		)
 		if err != nil {
@@ -470,3 +593,101 @@ func (r *PaymentRepository) GetPaymentTermsByID(ctx context.Context, paymentTerm
 
 	return &terms, nil
 }
+
+// ============================================================================
+// Split Payment Methods
+// ============================================================================
+
+// LockOrderForPayment takes a row lock on the order so concurrent split payments
+// cannot both pass the outstanding balance check
+func (r *PaymentRepository) LockOrderForPayment(ctx context.Context, tx *sql.Tx, orderID string) error {
+	var id string
+	return tx.QueryRowContext(ctx, `SELECT id FROM guest_orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&id)
+}
+
+// GetPaymentBalance sums every payment applied to an order: staff-recorded payments
+// plus split QRIS parts, which count as pending until Midtrans settles them
+func (r *PaymentRepository) GetPaymentBalance(ctx context.Context, tx *sql.Tx, orderID string, totalAmount int) (models.PaymentBalance, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT SUM(amount_paid) FROM payment_records WHERE order_id = $1), 0)
+			+ COALESCE((
+				SELECT SUM(amount) FROM payment_transactions
+				WHERE order_id = $1 AND is_split_part = TRUE AND settled_at IS NOT NULL
+			), 0),
+			COALESCE((
+				SELECT SUM(amount) FROM payment_transactions
+				WHERE order_id = $1 AND is_split_part = TRUE AND settled_at IS NULL
+				  AND (transaction_status IS NULL OR transaction_status = 'pending')
+				  AND (expiry_time IS NULL OR expiry_time > NOW())
+			), 0)
+	`
+
+	balance := models.PaymentBalance{TotalAmount: totalAmount}
+	err := r.getExecutor(tx).QueryRowContext(ctx, query, orderID).Scan(&balance.AmountPaid, &balance.AmountPending)
+	return balance, err
+}
+
+// CountSplitPaymentParts returns how many split QRIS charges were created for an order
+func (r *PaymentRepository) CountSplitPaymentParts(ctx context.Context, tx *sql.Tx, orderID string) (int, error) {
+	var count int
+	err := r.getExecutor(tx).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM payment_transactions WHERE order_id = $1 AND is_split_part = TRUE`,
+		orderID,
+	).Scan(&count)
+	return count, err
+}
+
+// CountOrderCharges returns how many full-amount charges were created for an order
+// An order gets a new charge each time it is edited before payment
+func (r *PaymentRepository) CountOrderCharges(ctx context.Context, tx *sql.Tx, orderID string) (int, error) {
+	var count int
+	err := r.getExecutor(tx).QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM payment_transactions WHERE order_id = $1 AND is_split_part = FALSE`,
+		orderID,
+	).Scan(&count)
+	return count, err
+}
+
+// ListSplitPaymentParts returns the split QRIS charges for an order, oldest first
+func (r *PaymentRepository) ListSplitPaymentParts(ctx context.Context, orderID string) ([]models.PaymentTransaction, error) {
+	query := `
+		SELECT id, order_id, midtrans_transaction_id, midtrans_order_id,
+			amount, payment_method, transaction_status, qr_code_url, qr_string,
+			expiry_time, created_at, settled_at, payment_gateway
+		FROM payment_transactions
+		WHERE order_id = $1 AND is_split_part = TRUE
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parts := []models.PaymentTransaction{}
+	for rows.Next() {
+		part := models.PaymentTransaction{IsSplitPart: true}
+		if err := rows.Scan(
+			&part.ID,
+			&part.OrderID,
+			&part.MidtransTransactionID,
+			&part.MidtransOrderID,
+			&part.Amount,
+			&part.PaymentMethod,
+			&part.TransactionStatus,
+			&part.QRCodeURL,
+			&part.QRString,
+			&part.ExpiryTime,
+			&part.CreatedAt,
+			&part.SettledAt,
+			&part.Gateway,
+		); err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+
+	return parts, rows.Err()
+}
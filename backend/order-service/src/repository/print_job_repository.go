@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// PrintJobRepository handles the queue of receipts and kitchen tickets for print agents
+type PrintJobRepository struct {
+	db *sql.DB
+}
+
+// NewPrintJobRepository creates a new print job repository
+func NewPrintJobRepository(db *sql.DB) *PrintJobRepository {
+	return &PrintJobRepository{db: db}
+}
+
+// printJobColumns selects a print job p with its order o, without the payload
+const printJobColumns = `p.id, p.tenant_id, p.order_id, o.order_reference, p.kind, p.status, p.paper_width,
+	p.attempts, p.last_error, p.claimed_at, p.printed_at, p.created_at`
+
+func scanPrintJob(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*models.PrintJob, error) {
+	var job models.PrintJob
+	dest := append([]interface{}{
+		&job.ID,
+		&job.TenantID,
+		&job.OrderID,
+		&job.OrderReference,
+		&job.Kind,
+		&job.Status,
+		&job.PaperWidth,
+		&job.Attempts,
+		&job.LastError,
+		&job.ClaimedAt,
+		&job.PrintedAt,
+		&job.CreatedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Create queues a rendered job
+func (r *PrintJobRepository) Create(ctx context.Context, job *models.PrintJob) error {
+	query := `
+INSERT INTO print_jobs (tenant_id, order_id, kind, paper_width, payload)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, status, attempts, created_at
+`
+
+	err := r.db.QueryRowContext(ctx, query, job.TenantID, job.OrderID, job.Kind, job.PaperWidth, job.Payload).
+		Scan(&job.ID, &job.Status, &job.Attempts, &job.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", job.OrderID).Str("kind", string(job.Kind)).Msg("Failed to create print job")
+		return err
+	}
+	return nil
+}
+
+// List returns a tenant's most recent jobs, optionally only those with status
+func (r *PrintJobRepository) List(ctx context.Context, tenantID string, status *models.PrintJobStatus, limit int) ([]*models.PrintJob, error) {
+	query := `
+SELECT ` + printJobColumns + `
+FROM print_jobs p
+JOIN guest_orders o ON o.id = p.order_id
+WHERE p.tenant_id = $1 AND ($2::text IS NULL OR p.status = $2)
+ORDER BY p.created_at DESC
+LIMIT $3
+`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, status, limit)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list print jobs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*models.PrintJob{}
+	for rows.Next() {
+		job, err := scanPrintJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// ClaimNext hands the tenant's oldest waiting job, with its payload, to a print agent
+// Jobs claimed longer than claimTimeout ago without an acknowledgement are handed out again,
+// or failed once they used up maxAttempts. Returns nil when nothing is waiting.
+func (r *PrintJobRepository) ClaimNext(ctx context.Context, tenantID string, claimTimeout time.Duration, maxAttempts int) (*models.PrintJob, error) {
+	timeoutSeconds := int(claimTimeout.Seconds())
+
+	_, err := r.db.ExecContext(ctx, `
+UPDATE print_jobs
+SET status = 'failed', claimed_at = NULL, last_error = COALESCE(last_error, 'print agent did not acknowledge the job')
+WHERE tenant_id = $1 AND status = 'printing'
+  AND claimed_at < NOW() - ($2 * INTERVAL '1 second') AND attempts >= $3
+`, tenantID, timeoutSeconds, maxAttempts)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to expire abandoned print jobs")
+		return nil, err
+	}
+
+	query := `
+UPDATE print_jobs p
+SET status = 'printing', attempts = p.attempts + 1, claimed_at = NOW()
+FROM guest_orders o
+WHERE o.id = p.order_id AND p.id = (
+	SELECT id FROM print_jobs
+	WHERE tenant_id = $1
+	  AND (status = 'pending' OR (status = 'printing' AND claimed_at < NOW() - ($2 * INTERVAL '1 second')))
+	ORDER BY created_at
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + printJobColumns + `, p.payload
+`
+
+	var payload []byte
+	job, err := scanPrintJob(r.db.QueryRowContext(ctx, query, tenantID, timeoutSeconds), &payload)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to claim print job")
+		return nil, err
+	}
+
+	job.Payload = payload
+	return job, nil
+}
+
+// Acknowledge records the outcome of a claimed job
+// A failed print goes back to the queue until it used up maxAttempts.
+func (r *PrintJobRepository) Acknowledge(ctx context.Context, tenantID, jobID string, req *models.AckPrintJobRequest, maxAttempts int) (*models.PrintJob, error) {
+	var lastError *string
+	if !req.Success && req.Error != "" {
+		lastError = &req.Error
+	}
+
+	query := `
+UPDATE print_jobs p
+SET status = CASE WHEN $3 THEN 'printed' WHEN p.attempts >= $5 THEN 'failed' ELSE 'pending' END,
+    printed_at = CASE WHEN $3 THEN NOW() END,
+    last_error = CASE WHEN $3 THEN NULL ELSE COALESCE($4, 'print failed') END,
+    claimed_at = NULL
+FROM guest_orders o
+WHERE o.id = p.order_id AND p.tenant_id = $1 AND p.id = $2 AND p.status = 'printing'
+RETURNING ` + printJobColumns
+
+	job, err := scanPrintJob(r.db.QueryRowContext(ctx, query, tenantID, jobID, req.Success, lastError, maxAttempts))
+	if err == sql.ErrNoRows {
+		return nil, r.missingJobError(ctx, tenantID, jobID, models.ErrPrintJobNotClaimed)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("print_job_id", jobID).Msg("Failed to acknowledge print job")
+		return nil, err
+	}
+	return job, nil
+}
+
+// Retry puts a failed or printed job back in the queue with fresh attempts, e.g. to reprint
+func (r *PrintJobRepository) Retry(ctx context.Context, tenantID, jobID string) (*models.PrintJob, error) {
+	query := `
+UPDATE print_jobs p
+SET status = 'pending', attempts = 0, last_error = NULL, claimed_at = NULL, printed_at = NULL
+FROM guest_orders o
+WHERE o.id = p.order_id AND p.tenant_id = $1 AND p.id = $2 AND p.status IN ('printed', 'failed')
+RETURNING ` + printJobColumns
+
+	job, err := scanPrintJob(r.db.QueryRowContext(ctx, query, tenantID, jobID))
+	if err == sql.ErrNoRows {
+		return nil, r.missingJobError(ctx, tenantID, jobID, models.ErrPrintJobQueued)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("print_job_id", jobID).Msg("Failed to retry print job")
+		return nil, err
+	}
+	return job, nil
+}
+
+// missingJobError tells a job that does not exist from one in the wrong state, returning wrongState for the latter
+func (r *PrintJobRepository) missingJobError(ctx context.Context, tenantID, jobID string, wrongState error) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM print_jobs WHERE tenant_id = $1 AND id = $2)`,
+		tenantID, jobID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return models.ErrPrintJobNotFound
+	}
+	return wrongState
+}
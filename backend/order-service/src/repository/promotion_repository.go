@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// PromotionRepository handles database operations for promotions and their application to orders
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+// NewPromotionRepository creates a new promotion repository
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `
+	id, tenant_id, name, description, promotion_type, scope, category_id, product_id,
+	discount_value, max_discount_amount, buy_quantity, get_quantity, min_subtotal,
+	days_of_week, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), timezone,
+	valid_from, valid_until, stackable, priority, is_active, created_at, updated_at`
+
+func scanPromotion(row interface{ Scan(...interface{}) error }) (*models.Promotion, error) {
+	var p models.Promotion
+	var days pq.Int64Array
+	err := row.Scan(
+		&p.ID,
+		&p.TenantID,
+		&p.Name,
+		&p.Description,
+		&p.PromotionType,
+		&p.Scope,
+		&p.CategoryID,
+		&p.ProductID,
+		&p.DiscountValue,
+		&p.MaxDiscountAmount,
+		&p.BuyQuantity,
+		&p.GetQuantity,
+		&p.MinSubtotal,
+		&days,
+		&p.StartTime,
+		&p.EndTime,
+		&p.Timezone,
+		&p.ValidFrom,
+		&p.ValidUntil,
+		&p.Stackable,
+		&p.Priority,
+		&p.IsActive,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		p.DaysOfWeek = append(p.DaysOfWeek, int(day))
+	}
+	return &p, nil
+}
+
+// daysOfWeekArray converts days to a SMALLINT[] parameter; no days is stored as NULL
+func daysOfWeekArray(days []int) pq.Int64Array {
+	if len(days) == 0 {
+		return nil
+	}
+	arr := make(pq.Int64Array, len(days))
+	for i, day := range days {
+		arr[i] = int64(day)
+	}
+	return arr
+}
+
+// promotionTargetError maps a bad category_id or product_id to ErrInvalidPromotion
+func promotionTargetError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && (pqErr.Code == "23503" || pqErr.Code == "22P02") {
+		return fmt.Errorf("%w: category or product not found", models.ErrInvalidPromotion)
+	}
+	return nil
+}
+
+// Create inserts a new promotion
+func (r *PromotionRepository) Create(ctx context.Context, tenantID string, req *models.PromotionRequest) (*models.Promotion, error) {
+	stackable := true
+	if req.Stackable != nil {
+		stackable = *req.Stackable
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	query := `
+		INSERT INTO promotions (
+			tenant_id, name, description, promotion_type, scope, category_id, product_id,
+			discount_value, max_discount_amount, buy_quantity, get_quantity, min_subtotal,
+			days_of_week, start_time, end_time, timezone, valid_from, valid_until,
+			stackable, priority, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		RETURNING ` + promotionColumns
+
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, query,
+		tenantID,
+		req.Name,
+		req.Description,
+		req.PromotionType,
+		req.Scope,
+		req.CategoryID,
+		req.ProductID,
+		req.DiscountValue,
+		req.MaxDiscountAmount,
+		req.BuyQuantity,
+		req.GetQuantity,
+		req.MinSubtotal,
+		daysOfWeekArray(req.DaysOfWeek),
+		req.StartTime,
+		req.EndTime,
+		req.Timezone,
+		req.ValidFrom,
+		req.ValidUntil,
+		stackable,
+		req.Priority,
+		isActive,
+	))
+	if err != nil {
+		if targetErr := promotionTargetError(err); targetErr != nil {
+			return nil, targetErr
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("name", req.Name).Msg("Failed to create promotion")
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// Update replaces a promotion's settings
+func (r *PromotionRepository) Update(ctx context.Context, tenantID, promotionID string, req *models.PromotionRequest) (*models.Promotion, error) {
+	query := `
+		UPDATE promotions
+		SET name = $3,
+			description = $4,
+			promotion_type = $5,
+			scope = $6,
+			category_id = $7,
+			product_id = $8,
+			discount_value = $9,
+			max_discount_amount = $10,
+			buy_quantity = $11,
+			get_quantity = $12,
+			min_subtotal = $13,
+			days_of_week = $14,
+			start_time = $15,
+			end_time = $16,
+			timezone = $17,
+			valid_from = $18,
+			valid_until = $19,
+			stackable = COALESCE($20, stackable),
+			priority = $21,
+			is_active = COALESCE($22, is_active),
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND id = $2
+		RETURNING ` + promotionColumns
+
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, query,
+		tenantID,
+		promotionID,
+		req.Name,
+		req.Description,
+		req.PromotionType,
+		req.Scope,
+		req.CategoryID,
+		req.ProductID,
+		req.DiscountValue,
+		req.MaxDiscountAmount,
+		req.BuyQuantity,
+		req.GetQuantity,
+		req.MinSubtotal,
+		daysOfWeekArray(req.DaysOfWeek),
+		req.StartTime,
+		req.EndTime,
+		req.Timezone,
+		req.ValidFrom,
+		req.ValidUntil,
+		req.Stackable,
+		req.Priority,
+		req.IsActive,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrPromotionNotFound
+		}
+		if targetErr := promotionTargetError(err); targetErr != nil {
+			return nil, targetErr
+		}
+		log.Error().Err(err).Str("promotion_id", promotionID).Msg("Failed to update promotion")
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// GetByID retrieves a tenant's promotion
+func (r *PromotionRepository) GetByID(ctx context.Context, tenantID, promotionID string) (*models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE tenant_id = $1 AND id = $2`
+
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, query, tenantID, promotionID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrPromotionNotFound
+	}
+	return promotion, err
+}
+
+// List returns a tenant's promotions, highest priority first
+func (r *PromotionRepository) List(ctx context.Context, tenantID string, activeOnly bool) ([]*models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE tenant_id = $1`
+	if activeOnly {
+		query += ` AND is_active = TRUE`
+	}
+	query += ` ORDER BY priority DESC, created_at`
+
+	return r.query(ctx, query, tenantID)
+}
+
+// ListApplicable returns active promotions whose validity window has not ended
+// Schedules and minimum subtotals are checked by the promotion engine.
+func (r *PromotionRepository) ListApplicable(ctx context.Context, tenantID string) ([]*models.Promotion, error) {
+	query := `SELECT ` + promotionColumns + `
+		FROM promotions
+		WHERE tenant_id = $1 AND is_active = TRUE AND (valid_until IS NULL OR valid_until > NOW())
+		ORDER BY priority DESC, created_at`
+
+	return r.query(ctx, query, tenantID)
+}
+
+func (r *PromotionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.Promotion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list promotions")
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []*models.Promotion{}
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+// Delete removes a promotion; orders keep their application records by name
+func (r *PromotionRepository) Delete(ctx context.Context, tenantID, promotionID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM promotions WHERE tenant_id = $1 AND id = $2`, tenantID, promotionID)
+	if err != nil {
+		log.Error().Err(err).Str("promotion_id", promotionID).Msg("Failed to delete promotion")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrPromotionNotFound
+	}
+	return nil
+}
+
+// GetProductCategories maps the given products to their category IDs
+func (r *PromotionRepository) GetProductCategories(ctx context.Context, tenantID string, productIDs []string) (map[string]string, error) {
+	categories := make(map[string]string, len(productIDs))
+	if len(productIDs) == 0 {
+		return categories, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, category_id
+		FROM products
+		WHERE tenant_id = $1 AND id = ANY($2) AND category_id IS NOT NULL
+	`, tenantID, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, categoryID string
+		if err := rows.Scan(&productID, &categoryID); err != nil {
+			return nil, err
+		}
+		categories[productID] = categoryID
+	}
+
+	return categories, rows.Err()
+}
+
+// RecordForOrder stores the promotions applied to an order inside the checkout transaction
+func (r *PromotionRepository) RecordForOrder(ctx context.Context, tx *sql.Tx, tenantID, orderID string, applied []models.AppliedPromotion) error {
+	for _, a := range applied {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO order_promotions (tenant_id, order_id, promotion_id, promotion_name, promotion_type, discount_amount)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, tenantID, orderID, a.PromotionID, a.Name, a.PromotionType, a.DiscountAmount)
+		if err != nil {
+			log.Error().Err(err).Str("order_id", orderID).Str("promotion_id", a.PromotionID).Msg("Failed to record order promotion")
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteForOrder removes the promotions recorded against an order so it can be repriced
+func (r *PromotionRepository) DeleteForOrder(ctx context.Context, tx *sql.Tx, tenantID, orderID string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM order_promotions WHERE tenant_id = $1 AND order_id = $2`, tenantID, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to delete order promotions")
+	}
+	return err
+}
+
+// ListForOrder returns the promotions applied to a tenant's order
+func (r *PromotionRepository) ListForOrder(ctx context.Context, tenantID, orderID string) ([]*models.OrderPromotion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, promotion_id, promotion_name, promotion_type, discount_amount, created_at
+		FROM order_promotions
+		WHERE tenant_id = $1 AND order_id = $2
+		ORDER BY created_at, discount_amount DESC
+	`, tenantID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []*models.OrderPromotion{}
+	for rows.Next() {
+		var p models.OrderPromotion
+		if err := rows.Scan(&p.ID, &p.OrderID, &p.PromotionID, &p.PromotionName, &p.PromotionType, &p.DiscountAmount, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, &p)
+	}
+
+	return promotions, rows.Err()
+}
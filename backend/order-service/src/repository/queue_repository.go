@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// QueueRepository reads the daily order queue shown on a tenant's "now serving" display
+type QueueRepository struct {
+	db *sql.DB
+}
+
+// NewQueueRepository creates a new queue repository
+func NewQueueRepository(db *sql.DB) *QueueRepository {
+	return &QueueRepository{db: db}
+}
+
+// GetOpenQueue returns today's business day and its paid, not yet completed orders in queue order
+// An order is ready to collect once the kitchen has bumped it.
+func (r *QueueRepository) GetOpenQueue(ctx context.Context, tenantID string) (string, []models.QueueEntry, error) {
+	var queueDate string
+	if err := r.db.QueryRowContext(ctx, `SELECT to_char(`+tenantQueueDate+`, 'YYYY-MM-DD')`, tenantID).Scan(&queueDate); err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get tenant queue date")
+		return "", nil, err
+	}
+
+	query := `
+SELECT queue_number, kitchen_bumped_at
+FROM guest_orders
+WHERE tenant_id = $1 AND queue_date = $2::date AND queue_number IS NOT NULL AND status = 'PAID'
+ORDER BY queue_number
+`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, queueDate)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get order queue")
+		return "", nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.QueueEntry{}
+	for rows.Next() {
+		var entry models.QueueEntry
+		if err := rows.Scan(&entry.QueueNumber, &entry.ReadyAt); err != nil {
+			return "", nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return queueDate, entries, rows.Err()
+}
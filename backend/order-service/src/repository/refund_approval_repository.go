@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// RefundApprovalRepository handles staff refund requests and their decisions
+type RefundApprovalRepository struct {
+	db *sql.DB
+}
+
+// NewRefundApprovalRepository creates a new refund approval repository
+func NewRefundApprovalRepository(db *sql.DB) *RefundApprovalRepository {
+	return &RefundApprovalRepository{db: db}
+}
+
+const refundApprovalColumns = `
+	a.id, a.tenant_id, a.order_id, o.order_reference, a.amount, a.reason, a.status, a.approver_role,
+	a.requested_by, a.requested_by_name, a.requested_by_role, a.decided_by, a.decided_by_name,
+	a.decision_note, a.refund_id, a.gateway, a.created_at, a.decided_at`
+
+func scanRefundApproval(row interface{ Scan(...interface{}) error }) (*models.RefundApproval, error) {
+	var approval models.RefundApproval
+	if err := row.Scan(
+		&approval.ID,
+		&approval.TenantID,
+		&approval.OrderID,
+		&approval.OrderReference,
+		&approval.Amount,
+		&approval.Reason,
+		&approval.Status,
+		&approval.ApproverRole,
+		&approval.RequestedBy,
+		&approval.RequestedByName,
+		&approval.RequestedByRole,
+		&approval.DecidedBy,
+		&approval.DecidedByName,
+		&approval.DecisionNote,
+		&approval.RefundID,
+		&approval.Gateway,
+		&approval.CreatedAt,
+		&approval.DecidedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// Create stores a refund request, pending or already decided
+// Returns ErrRefundAlreadyRequested when the order already has a pending request.
+func (r *RefundApprovalRepository) Create(ctx context.Context, approval *models.RefundApproval) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO refund_approvals (
+			tenant_id, order_id, amount, reason, status, approver_role,
+			requested_by, requested_by_name, requested_by_role,
+			decided_by, decided_by_name, decision_note, refund_id, gateway, decided_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, created_at
+	`,
+		approval.TenantID, approval.OrderID, approval.Amount, approval.Reason, string(approval.Status), approval.ApproverRole,
+		approval.RequestedBy, approval.RequestedByName, approval.RequestedByRole,
+		approval.DecidedBy, approval.DecidedByName, approval.DecisionNote, approval.RefundID, approval.Gateway, approval.DecidedAt,
+	).Scan(&approval.ID, &approval.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrRefundAlreadyRequested
+	}
+	return err
+}
+
+// Get returns one of a tenant's refund requests
+// With a transaction the request is locked until it ends.
+func (r *RefundApprovalRepository) Get(ctx context.Context, tx *sql.Tx, tenantID, approvalID string) (*models.RefundApproval, error) {
+	query := `
+		SELECT ` + refundApprovalColumns + `
+		FROM refund_approvals a
+		JOIN guest_orders o ON o.id = a.order_id
+		WHERE a.id = $1 AND a.tenant_id = $2`
+
+	var row *sql.Row
+	if tx != nil {
+		row = tx.QueryRowContext(ctx, query+` FOR UPDATE OF a`, approvalID, tenantID)
+	} else {
+		row = r.db.QueryRowContext(ctx, query, approvalID, tenantID)
+	}
+
+	approval, err := scanRefundApproval(row)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrRefundApprovalNotFound
+	}
+	return approval, err
+}
+
+// List returns a tenant's refund requests, newest first, optionally by status
+func (r *RefundApprovalRepository) List(ctx context.Context, tenantID string, status *models.RefundApprovalStatus, limit, offset int) ([]*models.RefundApproval, error) {
+	query := `
+		SELECT ` + refundApprovalColumns + `
+		FROM refund_approvals a
+		JOIN guest_orders o ON o.id = a.order_id
+		WHERE a.tenant_id = $1`
+	args := []interface{}{tenantID}
+	if status != nil {
+		query += ` AND a.status = $2`
+		args = append(args, string(*status))
+	}
+	query += fmt.Sprintf(` ORDER BY a.created_at DESC LIMIT %d OFFSET %d`, limit, offset)
+
+	return r.list(ctx, query, args...)
+}
+
+// ListByOrder returns an order's refund requests, newest first
+func (r *RefundApprovalRepository) ListByOrder(ctx context.Context, tenantID, orderID string) ([]*models.RefundApproval, error) {
+	return r.list(ctx, `
+		SELECT `+refundApprovalColumns+`
+		FROM refund_approvals a
+		JOIN guest_orders o ON o.id = a.order_id
+		WHERE a.tenant_id = $1 AND a.order_id = $2
+		ORDER BY a.created_at DESC
+	`, tenantID, orderID)
+}
+
+func (r *RefundApprovalRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.RefundApproval, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := []*models.RefundApproval{}
+	for rows.Next() {
+		approval, err := scanRefundApproval(rows)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, approval)
+	}
+	return approvals, rows.Err()
+}
+
+// GetCommittedAmount returns what an order's approved and pending refunds add up to
+// excludeID leaves out the request being decided, which is itself still pending.
+func (r *RefundApprovalRepository) GetCommittedAmount(ctx context.Context, orderID, excludeID string) (int, error) {
+	var committed int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM refund_approvals
+		WHERE order_id = $1 AND status IN ('pending', 'approved') AND id::text <> $2
+	`, orderID, excludeID).Scan(&committed)
+	return committed, err
+}
+
+// Decide stores the decision on a pending refund request
+func (r *RefundApprovalRepository) Decide(ctx context.Context, tx *sql.Tx, approval *models.RefundApproval) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE refund_approvals
+		SET status = $2, decided_by = $3, decided_by_name = $4, decision_note = $5,
+		    refund_id = $6, gateway = $7, decided_at = $8
+		WHERE id = $1
+	`, approval.ID, string(approval.Status), approval.DecidedBy, approval.DecidedByName, approval.DecisionNote,
+		approval.RefundID, approval.Gateway, approval.DecidedAt)
+	return err
+}
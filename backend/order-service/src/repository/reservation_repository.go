@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/point-of-sale-system/order-service/src/models"
 )
 
@@ -16,6 +19,18 @@ func NewReservationRepository(db *sql.DB) *ReservationRepository {
 	return &ReservationRepository{db: db}
 }
 
+// getExecutor returns the transaction when one is given, otherwise the database handle
+func (r *ReservationRepository) getExecutor(tx *sql.Tx) interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+} {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
 // CreateReservation creates a new inventory reservation
 func (r *ReservationRepository) CreateReservation(ctx context.Context, tx *sql.Tx, reservation *models.InventoryReservation) error {
 	query := `
@@ -160,17 +175,139 @@ func (r *ReservationRepository) GetExpiredReservations(ctx context.Context) ([]*
 	return reservations, rows.Err()
 }
 
-// GetTotalReservedQuantity returns the total reserved quantity for a product
-func (r *ReservationRepository) GetTotalReservedQuantity(ctx context.Context, productID string) (int, error) {
+// GetAvailableToPromise returns a product's stock on hand minus its active reservations
+// This is the single definition of sellable stock used by carts, checkout and cashier orders
+// With forUpdate the product row stays locked until tx ends; sql.ErrNoRows means the product
+// does not exist for the tenant or is archived
+func (r *ReservationRepository) GetAvailableToPromise(ctx context.Context, tx *sql.Tx, tenantID, productID string, forUpdate bool) (int, error) {
+	query := `
+		SELECT p.stock_quantity - COALESCE((
+			SELECT SUM(ir.quantity)
+			FROM inventory_reservations ir
+			WHERE ir.product_id = p.id AND ir.status = 'active'
+		), 0)
+		FROM products p
+		WHERE p.id = $1 AND p.tenant_id = $2 AND p.archived_at IS NULL
+	`
+	if forUpdate {
+		query += " FOR UPDATE OF p"
+	}
+
+	var available int
+	err := r.getExecutor(tx).QueryRowContext(ctx, query, productID, tenantID).Scan(&available)
+	return available, err
+}
+
+// GetSellableProducts returns the given products that are still on sale, with their current
+// price and available-to-promise stock; archived and unknown products are left out
+func (r *ReservationRepository) GetSellableProducts(ctx context.Context, tenantID string, productIDs []string) (map[string]models.SellableProduct, error) {
+	products := make(map[string]models.SellableProduct, len(productIDs))
+	if len(productIDs) == 0 {
+		return products, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT p.id, p.name, ROUND(p.selling_price)::INTEGER, p.stock_quantity - COALESCE((
+			SELECT SUM(ir.quantity)
+			FROM inventory_reservations ir
+			WHERE ir.product_id = p.id AND ir.status = 'active'
+		), 0)
+		FROM products p
+		WHERE p.tenant_id = $1 AND p.id = ANY($2) AND p.archived_at IS NULL
+	`, tenantID, pq.Array(productIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sellable products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product models.SellableProduct
+		if err := rows.Scan(&product.ProductID, &product.Name, &product.UnitPrice, &product.Available); err != nil {
+			return nil, fmt.Errorf("failed to scan sellable product: %w", err)
+		}
+		products[product.ProductID] = product
+	}
+
+	return products, rows.Err()
+}
+
+// GetOrderReservationsForUpdate locks an order's active and converted reservations
+func (r *ReservationRepository) GetOrderReservationsForUpdate(ctx context.Context, tx *sql.Tx, orderID string) ([]*models.InventoryReservation, error) {
 	query := `
-		SELECT COALESCE(SUM(quantity), 0)
+		SELECT id, order_id, product_id, quantity, status,
+			   created_at, expires_at, released_at
 		FROM inventory_reservations
-		WHERE product_id = $1 AND status = 'active'
+		WHERE order_id = $1 AND status IN ('active', 'converted')
+		ORDER BY product_id, created_at
+		FOR UPDATE
 	`
 
-	var total int
-	err := r.db.QueryRowContext(ctx, query, productID).Scan(&total)
-	return total, err
+	rows, err := tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []*models.InventoryReservation
+	for rows.Next() {
+		reservation := &models.InventoryReservation{}
+		err := rows.Scan(
+			&reservation.ID,
+			&reservation.OrderID,
+			&reservation.ProductID,
+			&reservation.Quantity,
+			&reservation.Status,
+			&reservation.CreatedAt,
+			&reservation.ExpiresAt,
+			&reservation.ReleasedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+// DeductStock decrements product stock for a completed sale
+// Stock is clamped at zero; the returned shortfall is how many units were sold beyond stock on hand
+func (r *ReservationRepository) DeductStock(ctx context.Context, tx *sql.Tx, tenantID, productID string, quantity int) (int, error) {
+	query := `
+		UPDATE products p
+		SET stock_quantity = GREATEST(p.stock_quantity - $1, 0),
+			updated_at = NOW()
+		FROM (SELECT id, stock_quantity FROM products WHERE id = $2 AND tenant_id = $3 FOR UPDATE) prev
+		WHERE p.id = prev.id
+		RETURNING prev.stock_quantity
+	`
+
+	var previousStock int
+	err := r.getExecutor(tx).QueryRowContext(ctx, query, quantity, productID, tenantID).Scan(&previousStock)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: %s", models.ErrProductUnavailable, productID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to deduct stock for product %s: %w", productID, err)
+	}
+
+	if quantity > previousStock {
+		return quantity - previousStock, nil
+	}
+	return 0, nil
+}
+
+// RestockProduct returns units of a cancelled allocation to stock
+func (r *ReservationRepository) RestockProduct(ctx context.Context, tx *sql.Tx, productID string, quantity int) error {
+	query := `
+		UPDATE products
+		SET stock_quantity = stock_quantity + $1,
+			updated_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := r.getExecutor(tx).ExecContext(ctx, query, quantity, productID)
+	return err
 }
 
 // ConvertReservation converts a reservation to "converted" status
@@ -207,3 +344,60 @@ func (r *ReservationRepository) ReleaseReservation(ctx context.Context, tx *sql.
 	}
 	return err
 }
+
+// ReleaseExpiredReservation releases a reservation only if it is still active and past its expiry
+// Returns false when the hold was extended or settled after it was read, so the sweep leaves it alone.
+func (r *ReservationRepository) ReleaseExpiredReservation(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE inventory_reservations
+		SET status = 'released', released_at = NOW()
+		WHERE id = $1 AND status = 'active' AND expires_at < NOW()
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// ExtendOrderHold adds extension to an order's unexpired reservations, counting it against maxExtensions
+// A hold that already lapsed is not revived: its stock may have been promised to another order.
+// Returns the new expiry and extension count, or nil when nothing could be extended.
+func (r *ReservationRepository) ExtendOrderHold(ctx context.Context, orderID string, extension time.Duration, maxExtensions int) (*time.Time, int, error) {
+	var expiresAt time.Time
+	var extensions int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE inventory_reservations
+		SET expires_at = expires_at + make_interval(secs => $2),
+		    extension_count = extension_count + 1
+		WHERE order_id = $1 AND status = 'active' AND expires_at > NOW() AND extension_count < $3
+		RETURNING expires_at, extension_count
+	`, orderID, extension.Seconds(), maxExtensions).Scan(&expiresAt, &extensions)
+	if err == sql.ErrNoRows {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return &expiresAt, extensions, nil
+}
+
+// CountHeldReservations returns how many of an order's reservations still hold stock
+func (r *ReservationRepository) CountHeldReservations(ctx context.Context, orderID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM inventory_reservations WHERE order_id = $1 AND status = 'active' AND expires_at > NOW()
+	`, orderID).Scan(&count)
+	return count, err
+}
+
+// ExtendReservations moves the expiry of an order's active reservations
+func (r *ReservationRepository) ExtendReservations(ctx context.Context, tx *sql.Tx, orderID string, expiresAt time.Time) error {
+	query := `
+		UPDATE inventory_reservations
+		SET expires_at = $2
+		WHERE order_id = $1 AND status = 'active'
+	`
+	_, err := r.getExecutor(tx).ExecContext(ctx, query, orderID, expiresAt)
+	return err
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// SettlementReportRepository handles database operations for daily settlement reports
+type SettlementReportRepository struct {
+	db *sql.DB
+}
+
+// NewSettlementReportRepository creates a new settlement report repository
+func NewSettlementReportRepository(db *sql.DB) *SettlementReportRepository {
+	return &SettlementReportRepository{db: db}
+}
+
+// ListChargesForDay returns a tenant's online charges settled in [from, to), plus the
+// charges created in that range that have not settled, for checking against the gateway
+func (r *SettlementReportRepository) ListChargesForDay(ctx context.Context, tenantID string, from, to time.Time) ([]*models.PaymentTransaction, error) {
+	query := `
+		SELECT pt.id, pt.order_id, pt.midtrans_transaction_id, pt.midtrans_order_id,
+			pt.amount, pt.payment_type, pt.payment_method, pt.transaction_status,
+			pt.expiry_time, pt.created_at, pt.settled_at, pt.is_split_part, pt.payment_gateway
+		FROM payment_transactions pt
+		JOIN guest_orders o ON o.id = pt.order_id
+		WHERE o.tenant_id = $1
+		  AND ((pt.settled_at >= $2 AND pt.settled_at < $3)
+		       OR (pt.settled_at IS NULL AND pt.created_at >= $2 AND pt.created_at < $3))
+		ORDER BY pt.created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	charges := []*models.PaymentTransaction{}
+	for rows.Next() {
+		charge := &models.PaymentTransaction{}
+		if err := rows.Scan(
+			&charge.ID,
+			&charge.OrderID,
+			&charge.MidtransTransactionID,
+			&charge.MidtransOrderID,
+			&charge.Amount,
+			&charge.PaymentType,
+			&charge.PaymentMethod,
+			&charge.TransactionStatus,
+			&charge.ExpiryTime,
+			&charge.CreatedAt,
+			&charge.SettledAt,
+			&charge.IsSplitPart,
+			&charge.Gateway,
+		); err != nil {
+			return nil, err
+		}
+		charges = append(charges, charge)
+	}
+
+	return charges, rows.Err()
+}
+
+// ListTenantsWithCharges returns the tenants that created online charges since the given time
+func (r *SettlementReportRepository) ListTenantsWithCharges(ctx context.Context, since time.Time) ([]string, error) {
+	query := `
+		SELECT DISTINCT o.tenant_id
+		FROM payment_transactions pt
+		JOIN guest_orders o ON o.id = pt.order_id
+		WHERE pt.created_at >= $1 OR pt.settled_at >= $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tenantIDs := []string{}
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, err
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+
+	return tenantIDs, rows.Err()
+}
+
+// Upsert saves a report, replacing an earlier one for the same tenant and day
+func (r *SettlementReportRepository) Upsert(ctx context.Context, report *models.SettlementReport) error {
+	lines, err := json.Marshal(report.Lines)
+	if err != nil {
+		return fmt.Errorf("failed to encode settlement lines: %w", err)
+	}
+	mismatches, err := json.Marshal(report.Mismatches)
+	if err != nil {
+		return fmt.Errorf("failed to encode settlement mismatches: %w", err)
+	}
+
+	query := `
+		INSERT INTO settlement_reports (
+			tenant_id, report_date, timezone, transaction_count, total_amount,
+			mismatch_count, lines, mismatches, generated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (tenant_id, report_date) DO UPDATE
+		SET timezone = EXCLUDED.timezone,
+			transaction_count = EXCLUDED.transaction_count,
+			total_amount = EXCLUDED.total_amount,
+			mismatch_count = EXCLUDED.mismatch_count,
+			lines = EXCLUDED.lines,
+			mismatches = EXCLUDED.mismatches,
+			generated_at = NOW()
+		RETURNING id, generated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		report.TenantID,
+		report.ReportDate,
+		report.Timezone,
+		report.TransactionCount,
+		report.TotalAmount,
+		report.MismatchCount,
+		string(lines),
+		string(mismatches),
+	).Scan(&report.ID, &report.GeneratedAt)
+}
+
+// Get returns a tenant's report for a day
+func (r *SettlementReportRepository) Get(ctx context.Context, tenantID, reportDate string) (*models.SettlementReport, error) {
+	query := `
+		SELECT id, tenant_id, TO_CHAR(report_date, 'YYYY-MM-DD'), timezone, transaction_count,
+			total_amount, mismatch_count, lines, mismatches, generated_at
+		FROM settlement_reports
+		WHERE tenant_id = $1 AND report_date = $2
+	`
+
+	var report models.SettlementReport
+	var lines, mismatches []byte
+	err := r.db.QueryRowContext(ctx, query, tenantID, reportDate).Scan(
+		&report.ID,
+		&report.TenantID,
+		&report.ReportDate,
+		&report.Timezone,
+		&report.TransactionCount,
+		&report.TotalAmount,
+		&report.MismatchCount,
+		&lines,
+		&mismatches,
+		&report.GeneratedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrSettlementReportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(lines, &report.Lines); err != nil {
+		return nil, fmt.Errorf("failed to decode settlement lines: %w", err)
+	}
+	if err := json.Unmarshal(mismatches, &report.Mismatches); err != nil {
+		return nil, fmt.Errorf("failed to decode settlement mismatches: %w", err)
+	}
+	return &report, nil
+}
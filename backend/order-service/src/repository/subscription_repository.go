@@ -0,0 +1,562 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// SubscriptionRepository handles database operations for plans, tenant subscriptions and their invoices
+type SubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewSubscriptionRepository creates a new subscription repository
+func NewSubscriptionRepository(db *sql.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+const subscriptionPlanColumns = `code, name, monthly_price, max_products, max_staff, max_outlets, max_monthly_orders,
+	max_storage_bytes, max_monthly_notifications`
+
+const tenantSubscriptionColumns = `
+	s.tenant_id, s.plan_code, s.status, s.current_period_start, s.current_period_end,
+	s.grace_until, s.scheduled_plan_code, s.created_at, s.updated_at,
+	p.code, p.name, p.monthly_price, p.max_products, p.max_staff, p.max_outlets, p.max_monthly_orders,
+	p.max_storage_bytes, p.max_monthly_notifications
+`
+
+const subscriptionInvoiceColumns = `
+	id, tenant_id, kind, plan_code, amount, period_start, period_end, status, due_at, paid_at,
+	payment_attempts, midtrans_order_id, payment_type, redirect_url, created_at, updated_at
+`
+
+type subscriptionScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscriptionPlan(row subscriptionScanner) (*models.SubscriptionPlan, error) {
+	plan := &models.SubscriptionPlan{}
+	err := row.Scan(&plan.Code, &plan.Name, &plan.MonthlyPrice,
+		&plan.MaxProducts, &plan.MaxStaff, &plan.MaxOutlets, &plan.MaxMonthlyOrders,
+		&plan.MaxStorageBytes, &plan.MaxMonthlyNotifications)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func scanTenantSubscription(row subscriptionScanner) (*models.TenantSubscription, error) {
+	sub := &models.TenantSubscription{Plan: &models.SubscriptionPlan{}}
+	err := row.Scan(
+		&sub.TenantID, &sub.PlanCode, &sub.Status, &sub.CurrentPeriodStart, &sub.CurrentPeriodEnd,
+		&sub.GraceUntil, &sub.ScheduledPlanCode, &sub.CreatedAt, &sub.UpdatedAt,
+		&sub.Plan.Code, &sub.Plan.Name, &sub.Plan.MonthlyPrice,
+		&sub.Plan.MaxProducts, &sub.Plan.MaxStaff, &sub.Plan.MaxOutlets, &sub.Plan.MaxMonthlyOrders,
+		&sub.Plan.MaxStorageBytes, &sub.Plan.MaxMonthlyNotifications,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func scanSubscriptionInvoice(row subscriptionScanner) (*models.SubscriptionInvoice, error) {
+	invoice := &models.SubscriptionInvoice{}
+	err := row.Scan(
+		&invoice.ID, &invoice.TenantID, &invoice.Kind, &invoice.PlanCode, &invoice.Amount,
+		&invoice.PeriodStart, &invoice.PeriodEnd, &invoice.Status, &invoice.DueAt, &invoice.PaidAt,
+		&invoice.PaymentAttempts, &invoice.MidtransOrderID, &invoice.PaymentType, &invoice.RedirectURL,
+		&invoice.CreatedAt, &invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// ListPlans returns every plan, cheapest first
+func (r *SubscriptionRepository) ListPlans(ctx context.Context) ([]*models.SubscriptionPlan, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+subscriptionPlanColumns+`
+		FROM subscription_plans
+		ORDER BY monthly_price, code
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := []*models.SubscriptionPlan{}
+	for rows.Next() {
+		plan, err := scanSubscriptionPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
+
+// GetPlan returns a plan by code
+func (r *SubscriptionRepository) GetPlan(ctx context.Context, code string) (*models.SubscriptionPlan, error) {
+	plan, err := scanSubscriptionPlan(r.db.QueryRowContext(ctx, `
+		SELECT `+subscriptionPlanColumns+`
+		FROM subscription_plans
+		WHERE code = $1
+	`, code))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrPlanNotFound
+	}
+	return plan, err
+}
+
+// GetOrCreate returns a tenant's subscription, starting them on the free plan the first time
+func (r *SubscriptionRepository) GetOrCreate(ctx context.Context, tenantID string) (*models.TenantSubscription, error) {
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO tenant_subscriptions (tenant_id, plan_code, status, current_period_start, current_period_end)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id) DO NOTHING
+	`, tenantID, models.PlanFree, models.SubscriptionActive, now, models.AddBillingMonth(now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return scanTenantSubscription(r.db.QueryRowContext(ctx, `
+		SELECT `+tenantSubscriptionColumns+`
+		FROM tenant_subscriptions s
+		JOIN subscription_plans p ON p.code = s.plan_code
+		WHERE s.tenant_id = $1
+	`, tenantID))
+}
+
+// SetScheduledPlan schedules a lower plan for the next period, or clears the schedule with nil
+// An open renewal invoice for the next period is voided so the billing job issues it for the new plan.
+func (r *SubscriptionRepository) SetScheduledPlan(ctx context.Context, tenantID string, planCode *string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE subscription_invoices i
+		SET status = $1, updated_at = NOW()
+		FROM tenant_subscriptions s
+		WHERE s.tenant_id = i.tenant_id AND i.tenant_id = $2
+		  AND i.kind = $3 AND i.status = $4 AND i.period_start = s.current_period_end
+	`, models.SubscriptionInvoiceVoid, tenantID, models.SubscriptionInvoiceRenewal, models.SubscriptionInvoiceOpen)
+	if err != nil {
+		return fmt.Errorf("failed to void renewal invoice: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tenant_subscriptions
+		SET scheduled_plan_code = $1, updated_at = NOW()
+		WHERE tenant_id = $2
+	`, planCode, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to schedule plan: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListInvoices returns a tenant's invoices, newest first
+func (r *SubscriptionRepository) ListInvoices(ctx context.Context, tenantID string) ([]*models.SubscriptionInvoice, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+subscriptionInvoiceColumns+`
+		FROM subscription_invoices
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := []*models.SubscriptionInvoice{}
+	for rows.Next() {
+		invoice, err := scanSubscriptionInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, rows.Err()
+}
+
+// GetInvoice returns an invoice; an empty tenant ID matches any tenant (webhooks)
+func (r *SubscriptionRepository) GetInvoice(ctx context.Context, tenantID, invoiceID string) (*models.SubscriptionInvoice, error) {
+	invoice, err := scanSubscriptionInvoice(r.db.QueryRowContext(ctx, `
+		SELECT `+subscriptionInvoiceColumns+`
+		FROM subscription_invoices
+		WHERE id = $1 AND ($2 = '' OR tenant_id::text = $2)
+	`, invoiceID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrInvoiceNotFound
+	}
+	return invoice, err
+}
+
+// FindOpenUpgrade returns the tenant's unpaid upgrade invoice, or nil
+func (r *SubscriptionRepository) FindOpenUpgrade(ctx context.Context, tenantID string) (*models.SubscriptionInvoice, error) {
+	invoice, err := scanSubscriptionInvoice(r.db.QueryRowContext(ctx, `
+		SELECT `+subscriptionInvoiceColumns+`
+		FROM subscription_invoices
+		WHERE tenant_id = $1 AND kind = $2 AND status = $3
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, tenantID, models.SubscriptionInvoiceUpgrade, models.SubscriptionInvoiceOpen))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return invoice, err
+}
+
+// CreateInvoice stores a new open invoice
+func (r *SubscriptionRepository) CreateInvoice(ctx context.Context, invoice *models.SubscriptionInvoice) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO subscription_invoices (tenant_id, kind, plan_code, amount, period_start, period_end, status, due_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, payment_attempts, created_at, updated_at
+	`, invoice.TenantID, invoice.Kind, invoice.PlanCode, invoice.Amount,
+		invoice.PeriodStart, invoice.PeriodEnd, invoice.Status, invoice.DueAt,
+	).Scan(&invoice.ID, &invoice.PaymentAttempts, &invoice.CreatedAt, &invoice.UpdatedAt)
+}
+
+// VoidOpenUpgrades voids a tenant's unpaid upgrade invoices
+func (r *SubscriptionRepository) VoidOpenUpgrades(ctx context.Context, tenantID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE subscription_invoices
+		SET status = $1, updated_at = NOW()
+		WHERE tenant_id = $2 AND kind = $3 AND status = $4
+	`, models.SubscriptionInvoiceVoid, tenantID, models.SubscriptionInvoiceUpgrade, models.SubscriptionInvoiceOpen)
+	return err
+}
+
+// StartPaymentAttempt records a new charge for an open invoice
+// It fails with ErrInvoiceNotPayable when the invoice was paid or voided meanwhile.
+func (r *SubscriptionRepository) StartPaymentAttempt(ctx context.Context, invoice *models.SubscriptionInvoice, midtransOrderID, redirectURL string) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE subscription_invoices
+		SET payment_attempts = payment_attempts + 1, midtrans_order_id = $1, redirect_url = $2, updated_at = NOW()
+		WHERE id = $3 AND status = $4
+		RETURNING payment_attempts, midtrans_order_id, redirect_url, updated_at
+	`, midtransOrderID, redirectURL, invoice.ID, models.SubscriptionInvoiceOpen,
+	).Scan(&invoice.PaymentAttempts, &invoice.MidtransOrderID, &invoice.RedirectURL, &invoice.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrInvoiceNotPayable
+	}
+	return err
+}
+
+// MarkInvoicePaid settles an invoice, moves the subscription to its plan and period, and voids the
+// tenant's other open invoices. Returns false when the invoice was not open (already paid or void).
+func (r *SubscriptionRepository) MarkInvoicePaid(ctx context.Context, invoiceID, paymentType string, paidAt time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// An upgrade's month starts when it is paid
+	var tenantID, planCode string
+	var periodStart, periodEnd time.Time
+	err = tx.QueryRowContext(ctx, `
+		UPDATE subscription_invoices
+		SET status = $1, paid_at = $2, payment_type = NULLIF($3, ''),
+			period_start = CASE WHEN kind = $4 THEN $2 ELSE period_start END,
+			period_end = CASE WHEN kind = $4 THEN $5 ELSE period_end END,
+			updated_at = NOW()
+		WHERE id = $6 AND status = $7
+		RETURNING tenant_id, plan_code, period_start, period_end
+	`, models.SubscriptionInvoicePaid, paidAt, paymentType, models.SubscriptionInvoiceUpgrade,
+		models.AddBillingMonth(paidAt), invoiceID, models.SubscriptionInvoiceOpen,
+	).Scan(&tenantID, &planCode, &periodStart, &periodEnd)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tenant_subscriptions (tenant_id, plan_code, status, current_period_start, current_period_end)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET plan_code = EXCLUDED.plan_code, status = EXCLUDED.status,
+			current_period_start = EXCLUDED.current_period_start, current_period_end = EXCLUDED.current_period_end,
+			grace_until = NULL, scheduled_plan_code = NULL, updated_at = NOW()
+	`, tenantID, planCode, models.SubscriptionActive, periodStart, periodEnd)
+	if err != nil {
+		return false, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	// Whatever else was open is settled by this payment
+	_, err = tx.ExecContext(ctx, `
+		UPDATE subscription_invoices
+		SET status = $1, updated_at = NOW()
+		WHERE tenant_id = $2 AND status = $3
+	`, models.SubscriptionInvoiceVoid, tenantID, models.SubscriptionInvoiceOpen)
+	if err != nil {
+		return false, fmt.Errorf("failed to void invoices: %w", err)
+	}
+
+	return true, tx.Commit()
+}
+
+// CreateDueRenewals issues the next period's invoice for paid subscriptions ending within the lead time
+// The next period is billed at the scheduled plan, if any, and falls due when the current period ends.
+// Returns the tenants invoiced.
+func (r *SubscriptionRepository) CreateDueRenewals(ctx context.Context, lead time.Duration) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		INSERT INTO subscription_invoices (tenant_id, kind, plan_code, amount, period_start, period_end, status, due_at)
+		SELECT s.tenant_id, $1, p.code, p.monthly_price, s.current_period_end,
+			s.current_period_end + INTERVAL '1 month', $2, s.current_period_end
+		FROM tenant_subscriptions s
+		JOIN subscription_plans p ON p.code = COALESCE(s.scheduled_plan_code, s.plan_code)
+		WHERE p.monthly_price > 0
+		  AND s.status = $3
+		  AND s.current_period_end <= NOW() + ($4 * INTERVAL '1 second')
+		  AND NOT EXISTS (
+			SELECT 1 FROM subscription_invoices i
+			WHERE i.tenant_id = s.tenant_id AND i.kind = $1 AND i.status != $5
+			  AND i.period_start = s.current_period_end
+		  )
+		ON CONFLICT DO NOTHING
+		RETURNING tenant_id
+	`, models.SubscriptionInvoiceRenewal, models.SubscriptionInvoiceOpen, models.SubscriptionActive,
+		int(lead.Seconds()), models.SubscriptionInvoiceVoid)
+	if err != nil {
+		return nil, err
+	}
+	return scanTenantIDs(rows)
+}
+
+// MarkPastDue moves active subscriptions with an overdue renewal invoice to past_due
+// The tenant keeps their plan until the grace period after the due date ends. Returns the tenants affected.
+func (r *SubscriptionRepository) MarkPastDue(ctx context.Context, grace time.Duration) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE tenant_subscriptions s
+		SET status = $1, grace_until = i.due_at + ($2 * INTERVAL '1 second'), updated_at = NOW()
+		FROM subscription_invoices i
+		WHERE i.tenant_id = s.tenant_id
+		  AND i.kind = $3 AND i.status = $4 AND i.due_at <= NOW()
+		  AND i.period_start = s.current_period_end
+		  AND s.status = $5
+		RETURNING s.tenant_id
+	`, models.SubscriptionPastDue, int(grace.Seconds()), models.SubscriptionInvoiceRenewal,
+		models.SubscriptionInvoiceOpen, models.SubscriptionActive)
+	if err != nil {
+		return nil, err
+	}
+	return scanTenantIDs(rows)
+}
+
+// ListGraceExpired returns the tenants whose grace period for an unpaid renewal has ended
+func (r *SubscriptionRepository) ListGraceExpired(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tenant_id
+		FROM tenant_subscriptions
+		WHERE status = $1 AND grace_until <= NOW()
+	`, models.SubscriptionPastDue)
+	if err != nil {
+		return nil, err
+	}
+	return scanTenantIDs(rows)
+}
+
+// DowngradeToFree moves a tenant to the free plan from now and voids their open invoices
+func (r *SubscriptionRepository) DowngradeToFree(ctx context.Context, tenantID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE subscription_invoices
+		SET status = $1, updated_at = NOW()
+		WHERE tenant_id = $2 AND status = $3
+	`, models.SubscriptionInvoiceVoid, tenantID, models.SubscriptionInvoiceOpen)
+	if err != nil {
+		return fmt.Errorf("failed to void invoices: %w", err)
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tenant_subscriptions
+		SET plan_code = $1, status = $2, current_period_start = $3, current_period_end = $4,
+			grace_until = NULL, scheduled_plan_code = NULL, updated_at = NOW()
+		WHERE tenant_id = $5
+	`, models.PlanFree, models.SubscriptionActive, now, models.AddBillingMonth(now), tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to downgrade subscription: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ApplyScheduledFreePlans moves subscriptions scheduled for a free plan onto it once their period ends
+// Scheduled paid plans start through their renewal invoice instead. Returns the tenants moved.
+func (r *SubscriptionRepository) ApplyScheduledFreePlans(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE tenant_subscriptions s
+		SET plan_code = s.scheduled_plan_code, scheduled_plan_code = NULL,
+			current_period_start = s.current_period_end,
+			current_period_end = s.current_period_end + INTERVAL '1 month',
+			updated_at = NOW()
+		FROM subscription_plans p
+		WHERE p.code = s.scheduled_plan_code AND p.monthly_price = 0
+		  AND s.status = $1 AND s.current_period_end <= NOW()
+		RETURNING s.tenant_id
+	`, models.SubscriptionActive)
+	if err != nil {
+		return nil, err
+	}
+	return scanTenantIDs(rows)
+}
+
+// RollOverFreePeriods starts the next period for free subscriptions whose period has ended
+func (r *SubscriptionRepository) RollOverFreePeriods(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_subscriptions s
+		SET current_period_start = s.current_period_end,
+			current_period_end = s.current_period_end + INTERVAL '1 month',
+			updated_at = NOW()
+		FROM subscription_plans p
+		WHERE p.code = s.plan_code AND p.monthly_price = 0 AND s.current_period_end <= NOW()
+	`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// VoidExpiredUpgrades voids upgrade invoices left unpaid past their due date
+func (r *SubscriptionRepository) VoidExpiredUpgrades(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE subscription_invoices
+		SET status = $1, updated_at = NOW()
+		WHERE kind = $2 AND status = $3 AND due_at <= NOW()
+	`, models.SubscriptionInvoiceVoid, models.SubscriptionInvoiceUpgrade, models.SubscriptionInvoiceOpen)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// planLimitColumns are the subscription_plans columns limiting each usage metric
+var planLimitColumns = map[string]string{
+	models.UsageMonthlyOrders:        "max_monthly_orders",
+	models.UsageStorageBytes:         "max_storage_bytes",
+	models.UsageMonthlyNotifications: "max_monthly_notifications",
+	models.UsageStaffSeats:           "max_staff",
+	models.UsageProducts:             "max_products",
+	models.UsageOutlets:              "max_outlets",
+}
+
+// GetTenantPlan returns the plan a tenant is on and the start of their billing period
+// Unlike GetOrCreate it writes nothing: tenants without a subscription are on the free plan for
+// the calendar month.
+func (r *SubscriptionRepository) GetTenantPlan(ctx context.Context, tenantID string) (*models.SubscriptionPlan, time.Time, error) {
+	var periodStart time.Time
+	plan := &models.SubscriptionPlan{}
+	err := r.db.QueryRowContext(ctx, `
+		WITH s AS (
+			SELECT plan_code, current_period_start FROM tenant_subscriptions WHERE tenant_id = $1
+		)
+		SELECT `+subscriptionPlanColumns+`,
+			COALESCE((SELECT current_period_start FROM s), date_trunc('month', NOW()))
+		FROM subscription_plans
+		WHERE code = COALESCE((SELECT plan_code FROM s), $2)
+	`, tenantID, models.PlanFree).Scan(&plan.Code, &plan.Name, &plan.MonthlyPrice,
+		&plan.MaxProducts, &plan.MaxStaff, &plan.MaxOutlets, &plan.MaxMonthlyOrders,
+		&plan.MaxStorageBytes, &plan.MaxMonthlyNotifications, &periodStart)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, models.ErrPlanNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return plan, periodStart, nil
+}
+
+// CountOrdersSince returns how many orders a tenant has taken since a time, online and offline
+func (r *SubscriptionRepository) CountOrdersSince(ctx context.Context, tenantID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM guest_orders WHERE tenant_id = $1 AND created_at >= $2
+	`, tenantID, since).Scan(&count)
+	return count, err
+}
+
+// GetUsage returns a tenant's usage of every plan limit since the start of their billing period
+// The storage limit is returned too, as plans without one fall back to the tenant's storage quota.
+func (r *SubscriptionRepository) GetUsage(ctx context.Context, tenantID string, periodStart time.Time) (map[string]int64, int64, error) {
+	var orders, storage, notifications, seats, products, outlets, storageQuota int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM guest_orders WHERE tenant_id = $1 AND created_at >= $2),
+			COALESCE((SELECT storage_used_bytes FROM tenants WHERE id = $1), 0),
+			(SELECT COUNT(*) FROM notifications WHERE tenant_id = $1 AND status = 'sent' AND sent_at >= $2),
+			(SELECT COUNT(*) FROM users
+				WHERE tenant_id = $1 AND status NOT IN ('suspended', 'deleted') AND deleted_at IS NULL)
+			+ (SELECT COUNT(*) FROM invitations
+				WHERE tenant_id = $1 AND status = 'pending' AND expires_at > NOW()),
+			(SELECT COUNT(*) FROM products WHERE tenant_id = $1 AND archived_at IS NULL),
+			(SELECT COUNT(*) FROM outlets WHERE tenant_id = $1),
+			COALESCE((SELECT storage_quota_bytes FROM tenants WHERE id = $1), 0)
+	`, tenantID, periodStart).Scan(&orders, &storage, &notifications, &seats, &products, &outlets, &storageQuota)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return map[string]int64{
+		models.UsageMonthlyOrders:        orders,
+		models.UsageStorageBytes:         storage,
+		models.UsageMonthlyNotifications: notifications,
+		models.UsageStaffSeats:           seats,
+		models.UsageProducts:             products,
+		models.UsageOutlets:              outlets,
+	}, storageQuota, nil
+}
+
+// FindUpgradePlan returns the cheapest plan allowing at least needed of a metric, or "" when none does
+func (r *SubscriptionRepository) FindUpgradePlan(ctx context.Context, metric string, needed int64) (string, error) {
+	column, ok := planLimitColumns[metric]
+	if !ok {
+		return "", fmt.Errorf("unknown usage metric %q", metric)
+	}
+
+	var code string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT code FROM subscription_plans
+		WHERE `+column+` IS NULL OR `+column+` >= $1
+		ORDER BY monthly_price, code
+		LIMIT 1
+	`, needed).Scan(&code)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return code, err
+}
+
+func scanTenantIDs(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	tenantIDs := []string{}
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, err
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	return tenantIDs, rows.Err()
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// TableRepository handles database operations for dine-in tables and the orders seated at them
+type TableRepository struct {
+	db *sql.DB
+}
+
+// NewTableRepository creates a new table repository
+func NewTableRepository(db *sql.DB) *TableRepository {
+	return &TableRepository{db: db}
+}
+
+const tableColumns = `t.id, t.tenant_id, t.name, t.capacity, t.qr_token, t.is_active, t.created_at, t.updated_at`
+
+// openTableOrders matches the open dine-in orders seated at table t
+const openTableOrders = `
+	o.tenant_id = t.tenant_id
+	AND o.delivery_type = 'dine_in'
+	AND o.status IN ('PENDING', 'PAID')
+	AND LOWER(o.table_number) = LOWER(t.name)`
+
+// tableOccupancyQuery selects tables with a summary of their open orders
+const tableOccupancyQuery = `
+	SELECT ` + tableColumns + `,
+		occ.open_orders, occ.unpaid_orders, COALESCE(occ.open_amount, 0), occ.occupied_since
+	FROM dining_tables t
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS open_orders,
+			COUNT(*) FILTER (WHERE o.status = 'PENDING') AS unpaid_orders,
+			SUM(o.total_amount) AS open_amount,
+			MIN(o.created_at) AS occupied_since
+		FROM guest_orders o
+		WHERE ` + openTableOrders + `
+	) occ`
+
+func scanTable(row interface{ Scan(...interface{}) error }) (*models.DiningTable, error) {
+	var t models.DiningTable
+	err := row.Scan(
+		&t.ID,
+		&t.TenantID,
+		&t.Name,
+		&t.Capacity,
+		&t.QRToken,
+		&t.IsActive,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func scanTableOccupancy(row interface{ Scan(...interface{}) error }) (*models.TableOccupancy, error) {
+	var t models.TableOccupancy
+	err := row.Scan(
+		&t.ID,
+		&t.TenantID,
+		&t.Name,
+		&t.Capacity,
+		&t.QRToken,
+		&t.IsActive,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+		&t.OpenOrders,
+		&t.UnpaidOrders,
+		&t.OpenAmount,
+		&t.OccupiedSince,
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.Occupied = t.OpenOrders > 0
+	return &t, nil
+}
+
+// Create inserts a new table
+func (r *TableRepository) Create(ctx context.Context, tenantID string, req *models.CreateTableRequest, qrToken string) (*models.DiningTable, error) {
+	query := `
+		INSERT INTO dining_tables AS t (tenant_id, name, capacity, qr_token)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + tableColumns
+
+	table, err := scanTable(r.db.QueryRowContext(ctx, query, tenantID, req.Name, req.Capacity, qrToken))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, models.ErrTableNameExists
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("name", req.Name).Msg("Failed to create table")
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// List returns a tenant's tables with their occupancy, in name order
+func (r *TableRepository) List(ctx context.Context, tenantID string, activeOnly bool) ([]*models.TableOccupancy, error) {
+	query := tableOccupancyQuery + ` WHERE t.tenant_id = $1`
+	if activeOnly {
+		query += ` AND t.is_active = TRUE`
+	}
+	// Shorter names first so numbered tables sort 2 before 10
+	query += ` ORDER BY LENGTH(t.name), LOWER(t.name)`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list tables")
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []*models.TableOccupancy{}
+	for rows.Next() {
+		table, err := scanTableOccupancy(rows)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+// GetByID retrieves a tenant's table with its occupancy
+func (r *TableRepository) GetByID(ctx context.Context, tenantID, tableID string) (*models.TableOccupancy, error) {
+	query := tableOccupancyQuery + ` WHERE t.tenant_id = $1 AND t.id = $2`
+
+	table, err := scanTableOccupancy(r.db.QueryRowContext(ctx, query, tenantID, tableID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTableNotFound
+	}
+	return table, err
+}
+
+// GetForUpdate retrieves and locks a tenant's table until tx ends
+func (r *TableRepository) GetForUpdate(ctx context.Context, tx *sql.Tx, tenantID, tableID string) (*models.DiningTable, error) {
+	query := `SELECT ` + tableColumns + ` FROM dining_tables t WHERE t.tenant_id = $1 AND t.id = $2 FOR UPDATE`
+
+	table, err := scanTable(tx.QueryRowContext(ctx, query, tenantID, tableID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTableNotFound
+	}
+	return table, err
+}
+
+// GetByToken retrieves a tenant's table by its QR code token
+func (r *TableRepository) GetByToken(ctx context.Context, tenantID, qrToken string) (*models.DiningTable, error) {
+	query := `SELECT ` + tableColumns + ` FROM dining_tables t WHERE t.tenant_id = $1 AND t.qr_token = $2`
+
+	table, err := scanTable(r.db.QueryRowContext(ctx, query, tenantID, qrToken))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTableNotFound
+	}
+	return table, err
+}
+
+// GetByName retrieves a tenant's table by name, ignoring case
+func (r *TableRepository) GetByName(ctx context.Context, tenantID, name string) (*models.DiningTable, error) {
+	query := `SELECT ` + tableColumns + ` FROM dining_tables t WHERE t.tenant_id = $1 AND LOWER(t.name) = LOWER($2)`
+
+	table, err := scanTable(r.db.QueryRowContext(ctx, query, tenantID, name))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTableNotFound
+	}
+	return table, err
+}
+
+// HasActiveTables reports whether the tenant manages its tables
+func (r *TableRepository) HasActiveTables(ctx context.Context, tenantID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM dining_tables WHERE tenant_id = $1 AND is_active = TRUE)`,
+		tenantID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// ListOpenOrders returns the open orders seated at a table, oldest first
+func (r *TableRepository) ListOpenOrders(ctx context.Context, table *models.DiningTable) ([]models.TableOrder, error) {
+	query := `
+		SELECT o.id, o.order_reference, o.status, o.total_amount, o.created_at
+		FROM guest_orders o, (SELECT $1::uuid AS tenant_id, $2::text AS name) t
+		WHERE ` + openTableOrders + `
+		ORDER BY o.created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, table.TenantID, table.Name)
+	if err != nil {
+		log.Error().Err(err).Str("table_id", table.ID).Msg("Failed to list table orders")
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.TableOrder{}
+	for rows.Next() {
+		var order models.TableOrder
+		if err := rows.Scan(&order.ID, &order.OrderReference, &order.Status, &order.TotalAmount, &order.CreatedAt); err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+// Update changes a table's name, capacity or active flag; nil fields are kept
+func (r *TableRepository) Update(ctx context.Context, tx *sql.Tx, tenantID, tableID string, req *models.UpdateTableRequest) (*models.DiningTable, error) {
+	query := `
+		UPDATE dining_tables AS t
+		SET name = COALESCE($3, name),
+			capacity = COALESCE($4, capacity),
+			is_active = COALESCE($5, is_active),
+			updated_at = NOW()
+		WHERE t.tenant_id = $1 AND t.id = $2
+		RETURNING ` + tableColumns
+
+	table, err := scanTable(tx.QueryRowContext(ctx, query, tenantID, tableID, req.Name, req.Capacity, req.IsActive))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrTableNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, models.ErrTableNameExists
+		}
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to update table")
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// MoveOpenOrders seats the open orders at one table name at another, returning how many moved
+func (r *TableRepository) MoveOpenOrders(ctx context.Context, tx *sql.Tx, tenantID, fromName, toName string) (int64, error) {
+	query := `
+		UPDATE guest_orders o
+		SET table_number = $3
+		FROM (SELECT $1::uuid AS tenant_id, $2::text AS name) t
+		WHERE ` + openTableOrders
+
+	result, err := tx.ExecContext(ctx, query, tenantID, fromName, toName)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("from", fromName).Str("to", toName).Msg("Failed to move table orders")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// SetQRToken replaces a table's QR code token
+func (r *TableRepository) SetQRToken(ctx context.Context, tenantID, tableID, qrToken string) (*models.DiningTable, error) {
+	query := `
+		UPDATE dining_tables AS t
+		SET qr_token = $3, updated_at = NOW()
+		WHERE t.tenant_id = $1 AND t.id = $2
+		RETURNING ` + tableColumns
+
+	table, err := scanTable(r.db.QueryRowContext(ctx, query, tenantID, tableID, qrToken))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTableNotFound
+	}
+	return table, err
+}
+
+// Delete removes a table; its orders keep their table_number
+func (r *TableRepository) Delete(ctx context.Context, tenantID, tableID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM dining_tables WHERE tenant_id = $1 AND id = $2`, tenantID, tableID)
+	if err != nil {
+		log.Error().Err(err).Str("table_id", tableID).Msg("Failed to delete table")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrTableNotFound
+	}
+	return nil
+}
+
+// GetTenantSlug returns the slug of the tenant's public menu
+func (r *TableRepository) GetTenantSlug(ctx context.Context, tenantID string) (string, error) {
+	var slug string
+	err := r.db.QueryRowContext(ctx, `SELECT slug FROM tenants WHERE id = $1`, tenantID).Scan(&slug)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get tenant slug")
+	}
+	return slug, err
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TenantDeletionRepository deletes a tenant's orders when the tenant is deleted
+type TenantDeletionRepository struct {
+	db *sql.DB
+}
+
+// NewTenantDeletionRepository creates a new tenant deletion repository
+func NewTenantDeletionRepository(db *sql.DB) *TenantDeletionRepository {
+	return &TenantDeletionRepository{db: db}
+}
+
+// tenantOrderDeletes run in order: archived rows have no foreign keys and are found through their order,
+// and cashier shifts reference the staff who worked them, so they must go before the tenant's users.
+// Live orders take their items, payments, notes, proofs and the rest with them.
+var tenantOrderDeletes = []struct {
+	kind  string
+	query string
+}{
+	{"archived_order_items", `DELETE FROM archived_order_items WHERE order_id IN (SELECT id FROM archived_guest_orders WHERE tenant_id = $1)`},
+	{"archived_payment_transactions", `DELETE FROM archived_payment_transactions WHERE order_id IN (SELECT id FROM archived_guest_orders WHERE tenant_id = $1)`},
+	{"archived_orders", `DELETE FROM archived_guest_orders WHERE tenant_id = $1`},
+	{"orders", `DELETE FROM guest_orders WHERE tenant_id = $1`},
+	{"cashier_shifts", `DELETE FROM cashier_shifts WHERE tenant_id = $1`},
+}
+
+// DeleteTenantOrders deletes all of a tenant's live and archived orders and its cashier shifts in one
+// transaction, returning how many rows of each kind were deleted
+func (r *TenantDeletionRepository) DeleteTenantOrders(ctx context.Context, tenantID string) (map[string]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted := make(map[string]int64, len(tenantOrderDeletes))
+	for _, d := range tenantOrderDeletes {
+		result, err := tx.ExecContext(ctx, d.query, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete tenant %s: %w", d.kind, err)
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count deleted %s: %w", d.kind, err)
+		}
+		deleted[d.kind] = count
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tenant order deletion: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// VoucherRepository handles database operations for vouchers and their redemptions
+type VoucherRepository struct {
+	db *sql.DB
+}
+
+// NewVoucherRepository creates a new voucher repository
+func NewVoucherRepository(db *sql.DB) *VoucherRepository {
+	return &VoucherRepository{db: db}
+}
+
+const voucherColumns = `
+	id, tenant_id, code, description, discount_type, discount_value,
+	max_discount_amount, min_subtotal, usage_limit, per_customer_limit,
+	usage_count, valid_from, valid_until, is_active, created_at, updated_at`
+
+func scanVoucher(row interface{ Scan(...interface{}) error }) (*models.Voucher, error) {
+	var v models.Voucher
+	err := row.Scan(
+		&v.ID,
+		&v.TenantID,
+		&v.Code,
+		&v.Description,
+		&v.DiscountType,
+		&v.DiscountValue,
+		&v.MaxDiscountAmount,
+		&v.MinSubtotal,
+		&v.UsageLimit,
+		&v.PerCustomerLimit,
+		&v.UsageCount,
+		&v.ValidFrom,
+		&v.ValidUntil,
+		&v.IsActive,
+		&v.CreatedAt,
+		&v.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// Create inserts a new voucher
+func (r *VoucherRepository) Create(ctx context.Context, tenantID string, req *models.VoucherRequest) (*models.Voucher, error) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	query := `
+		INSERT INTO vouchers (
+			tenant_id, code, description, discount_type, discount_value,
+			max_discount_amount, min_subtotal, usage_limit, per_customer_limit,
+			valid_from, valid_until, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + voucherColumns
+
+	voucher, err := scanVoucher(r.db.QueryRowContext(ctx, query,
+		tenantID,
+		req.Code,
+		req.Description,
+		req.DiscountType,
+		req.DiscountValue,
+		req.MaxDiscountAmount,
+		req.MinSubtotal,
+		req.UsageLimit,
+		req.PerCustomerLimit,
+		req.ValidFrom,
+		req.ValidUntil,
+		isActive,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, models.ErrVoucherCodeExists
+		}
+		log.Error().Err(err).Str("tenant_id", tenantID).Str("code", req.Code).Msg("Failed to create voucher")
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+// Update replaces a voucher's settings; usage_count is kept
+func (r *VoucherRepository) Update(ctx context.Context, tenantID, voucherID string, req *models.VoucherRequest) (*models.Voucher, error) {
+	query := `
+		UPDATE vouchers
+		SET code = $3,
+			description = $4,
+			discount_type = $5,
+			discount_value = $6,
+			max_discount_amount = $7,
+			min_subtotal = $8,
+			usage_limit = $9,
+			per_customer_limit = $10,
+			valid_from = $11,
+			valid_until = $12,
+			is_active = COALESCE($13, is_active),
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND id = $2
+		RETURNING ` + voucherColumns
+
+	voucher, err := scanVoucher(r.db.QueryRowContext(ctx, query,
+		tenantID,
+		voucherID,
+		req.Code,
+		req.Description,
+		req.DiscountType,
+		req.DiscountValue,
+		req.MaxDiscountAmount,
+		req.MinSubtotal,
+		req.UsageLimit,
+		req.PerCustomerLimit,
+		req.ValidFrom,
+		req.ValidUntil,
+		req.IsActive,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, models.ErrVoucherNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, models.ErrVoucherCodeExists
+		}
+		log.Error().Err(err).Str("voucher_id", voucherID).Msg("Failed to update voucher")
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+// GetByID retrieves a tenant's voucher
+func (r *VoucherRepository) GetByID(ctx context.Context, tenantID, voucherID string) (*models.Voucher, error) {
+	query := `SELECT ` + voucherColumns + ` FROM vouchers WHERE tenant_id = $1 AND id = $2`
+
+	voucher, err := scanVoucher(r.db.QueryRowContext(ctx, query, tenantID, voucherID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrVoucherNotFound
+	}
+	return voucher, err
+}
+
+// GetByCode retrieves a tenant's voucher by its normalized code
+func (r *VoucherRepository) GetByCode(ctx context.Context, tenantID, code string) (*models.Voucher, error) {
+	query := `SELECT ` + voucherColumns + ` FROM vouchers WHERE tenant_id = $1 AND code = $2`
+
+	voucher, err := scanVoucher(r.db.QueryRowContext(ctx, query, tenantID, code))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrVoucherNotFound
+	}
+	return voucher, err
+}
+
+// List returns a tenant's vouchers, newest first
+func (r *VoucherRepository) List(ctx context.Context, tenantID string, activeOnly bool) ([]*models.Voucher, error) {
+	query := `SELECT ` + voucherColumns + ` FROM vouchers WHERE tenant_id = $1`
+	if activeOnly {
+		query += ` AND is_active = TRUE`
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list vouchers")
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := []*models.Voucher{}
+	for rows.Next() {
+		voucher, err := scanVoucher(rows)
+		if err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, voucher)
+	}
+
+	return vouchers, rows.Err()
+}
+
+// Delete removes a voucher that has never been redeemed
+func (r *VoucherRepository) Delete(ctx context.Context, tenantID, voucherID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM vouchers WHERE tenant_id = $1 AND id = $2`, tenantID, voucherID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return models.ErrVoucherInUse
+		}
+		log.Error().Err(err).Str("voucher_id", voucherID).Msg("Failed to delete voucher")
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrVoucherNotFound
+	}
+	return nil
+}
+
+// customerRedemptionsQuery counts a customer's redemptions of a voucher on orders that were not cancelled
+const customerRedemptionsQuery = `
+	SELECT COUNT(*)
+	FROM voucher_redemptions vr
+	JOIN guest_orders o ON o.id = vr.order_id
+	WHERE vr.voucher_id = $1 AND vr.customer_key = $2 AND o.status <> 'CANCELLED'
+`
+
+// CountCustomerRedemptions counts a customer's redemptions of a voucher on orders that were not cancelled
+func (r *VoucherRepository) CountCustomerRedemptions(ctx context.Context, voucherID, customerKey string) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, customerRedemptionsQuery, voucherID, customerKey).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Redeem records a voucher redemption for an order inside the checkout transaction
+// The voucher row is locked first, so concurrent checkouts redeem it one at a time: the
+// customer's redemptions are counted again under the lock, and the usage counter is only
+// incremented while it is below usage_limit.
+func (r *VoucherRepository) Redeem(ctx context.Context, tx *sql.Tx, voucher *models.Voucher, orderID, customerKey string, discountAmount int) error {
+	var perCustomerLimit sql.NullInt64
+	err := tx.QueryRowContext(ctx,
+		`SELECT per_customer_limit FROM vouchers WHERE id = $1 FOR UPDATE`,
+		voucher.ID,
+	).Scan(&perCustomerLimit)
+	if err == sql.ErrNoRows {
+		return models.ErrVoucherNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if perCustomerLimit.Valid {
+		var used int
+		if err := tx.QueryRowContext(ctx, customerRedemptionsQuery, voucher.ID, customerKey).Scan(&used); err != nil {
+			return fmt.Errorf("failed to count customer redemptions: %w", err)
+		}
+		if used >= int(perCustomerLimit.Int64) {
+			return models.ErrVoucherCustomerLimitReached
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE vouchers
+		SET usage_count = usage_count + 1, updated_at = NOW()
+		WHERE id = $1 AND (usage_limit IS NULL OR usage_count < usage_limit)
+	`, voucher.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrVoucherUsageLimitReached
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO voucher_redemptions (voucher_id, tenant_id, order_id, customer_key, discount_amount)
+		VALUES ($1, $2, $3, $4, $5)
+	`, voucher.ID, voucher.TenantID, orderID, customerKey, discountAmount)
+	return err
+}
+
+// UpdateRedemptionDiscount records the new discount after an order was repriced
+func (r *VoucherRepository) UpdateRedemptionDiscount(ctx context.Context, tx *sql.Tx, orderID string, discountAmount int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE voucher_redemptions SET discount_amount = $2 WHERE order_id = $1
+	`, orderID, discountAmount)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to update voucher redemption")
+	}
+	return err
+}
+
+// ReleaseForOrder gives back the usage taken by a cancelled order's redemption
+// The redemption row is kept for reporting; per-customer counts ignore cancelled orders.
+func (r *VoucherRepository) ReleaseForOrder(ctx context.Context, tx *sql.Tx, orderID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE vouchers v
+		SET usage_count = GREATEST(v.usage_count - 1, 0), updated_at = NOW()
+		FROM voucher_redemptions vr
+		WHERE vr.voucher_id = v.id AND vr.order_id = $1
+	`, orderID)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to release voucher redemption")
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+)
+
+// AttachmentStorage keeps order note attachments in the object storage shared with product-service
+type AttachmentStorage struct {
+	client *minio.Client
+	bucket string
+	urlTTL time.Duration
+}
+
+// NewAttachmentStorage creates a storage client for order attachments
+func NewAttachmentStorage(cfg config.StorageConfig) (*AttachmentStorage, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	return &AttachmentStorage{
+		client: client,
+		bucket: cfg.BucketName,
+		urlTTL: cfg.URLTTL,
+	}, nil
+}
+
+// Upload stores an attachment under its storage key
+func (s *AttachmentStorage) Upload(ctx context.Context, storageKey string, reader io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, storageKey, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload attachment: %w", err)
+	}
+	return nil
+}
+
+// PresignedURL returns a short-lived URL to view an attachment
+func (s *AttachmentStorage) PresignedURL(ctx context.Context, storageKey string) (string, error) {
+	url, err := s.client.PresignedGetObject(ctx, s.bucket, storageKey, s.urlTTL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign attachment URL: %w", err)
+	}
+	return url.String(), nil
+}
+
+// Download reads an object of at most maxBytes from the bucket
+// Tenant logos uploaded through the tenant service live in the same bucket.
+func (s *AttachmentStorage) Download(ctx context.Context, storageKey string, maxBytes int64) ([]byte, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, storageKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", storageKey, err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", storageKey, err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%s is larger than %d bytes", storageKey, maxBytes)
+	}
+	return data, nil
+}
+
+// Delete removes an attachment
+func (s *AttachmentStorage) Delete(ctx context.Context, storageKey string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, storageKey, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object whose key starts with prefix and returns how many were removed
+func (s *AttachmentStorage) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var removed int64
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return removed, fmt.Errorf("failed to list %s: %w", prefix, object.Err)
+		}
+		if err := s.Delete(ctx, object.Key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// ErrInvalidBillingSignature is returned for subscription payment notifications not signed by the platform account
+var ErrInvalidBillingSignature = errors.New("invalid signature")
+
+// BillingService manages tenants' subscription plans and the invoices they pay to the platform
+// Invoices are paid through Snap on the platform's own Midtrans account, not the tenant's.
+// There is no proration: an upgrade starts a new month when it is paid, a downgrade waits for
+// the current period to end.
+type BillingService struct {
+	repo           *repository.SubscriptionRepository
+	credentials    config.BillingCredentials
+	auditPublisher utils.AuditPublisherInterface
+	grace          time.Duration // How long a tenant keeps a paid plan after a renewal falls due unpaid
+	invoiceLead    time.Duration // How long before a period ends its renewal is invoiced
+}
+
+// NewBillingService creates a new billing service
+func NewBillingService(
+	repo *repository.SubscriptionRepository,
+	credentials config.BillingCredentials,
+	auditPublisher utils.AuditPublisherInterface,
+	graceDays int,
+	invoiceLeadDays int,
+) *BillingService {
+	return &BillingService{
+		repo:           repo,
+		credentials:    credentials,
+		auditPublisher: auditPublisher,
+		grace:          time.Duration(graceDays) * 24 * time.Hour,
+		invoiceLead:    time.Duration(invoiceLeadDays) * 24 * time.Hour,
+	}
+}
+
+// ListPlans returns the plans tenants can choose from
+func (s *BillingService) ListPlans(ctx context.Context) ([]*models.SubscriptionPlan, error) {
+	return s.repo.ListPlans(ctx)
+}
+
+// GetSubscription returns a tenant's subscription; tenants start on the free plan
+func (s *BillingService) GetSubscription(ctx context.Context, tenantID string) (*models.TenantSubscription, error) {
+	return s.repo.GetOrCreate(ctx, tenantID)
+}
+
+// ListInvoices returns a tenant's subscription invoices, newest first
+func (s *BillingService) ListInvoices(ctx context.Context, tenantID string) ([]*models.SubscriptionInvoice, error) {
+	return s.repo.ListInvoices(ctx, tenantID)
+}
+
+// GetUsage returns a tenant's usage of each plan limit for the current billing period
+func (s *BillingService) GetUsage(ctx context.Context, tenantID string) (*models.PlanUsage, error) {
+	sub, err := s.repo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	used, storageQuota, err := s.repo.GetUsage(ctx, tenantID, sub.CurrentPeriodStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count usage: %w", err)
+	}
+
+	storageLimit := sub.Plan.MaxStorageBytes
+	if storageLimit == nil {
+		storageLimit = &storageQuota
+	}
+	limits := map[string]*int64{
+		models.UsageMonthlyOrders:        intLimit(sub.Plan.MaxMonthlyOrders),
+		models.UsageStorageBytes:         storageLimit,
+		models.UsageMonthlyNotifications: intLimit(sub.Plan.MaxMonthlyNotifications),
+		models.UsageStaffSeats:           intLimit(sub.Plan.MaxStaff),
+		models.UsageProducts:             intLimit(sub.Plan.MaxProducts),
+		models.UsageOutlets:              intLimit(sub.Plan.MaxOutlets),
+	}
+
+	usage := &models.PlanUsage{
+		PlanCode:    sub.Plan.Code,
+		PlanName:    sub.Plan.Name,
+		PeriodStart: sub.CurrentPeriodStart,
+		PeriodEnd:   sub.CurrentPeriodEnd,
+		Usage:       make(map[string]*models.UsageMetric, len(limits)),
+	}
+	for metric, limit := range limits {
+		usage.Usage[metric] = &models.UsageMetric{Used: used[metric], Limit: limit}
+	}
+	return usage, nil
+}
+
+// CheckMonthlyOrderLimit returns a *models.PlanLimitError when the tenant's plan allows no more
+// orders this billing period
+func (s *BillingService) CheckMonthlyOrderLimit(ctx context.Context, tenantID string) error {
+	plan, periodStart, err := s.repo.GetTenantPlan(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get plan: %w", err)
+	}
+	if plan.MaxMonthlyOrders == nil {
+		return nil
+	}
+
+	count, err := s.repo.CountOrdersSince(ctx, tenantID, periodStart)
+	if err != nil {
+		return fmt.Errorf("failed to count orders: %w", err)
+	}
+	max := int64(*plan.MaxMonthlyOrders)
+	if count < max {
+		return nil
+	}
+
+	upgrade, err := s.repo.FindUpgradePlan(ctx, models.UsageMonthlyOrders, count+1)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to find upgrade plan")
+	}
+	return &models.PlanLimitError{
+		Limit:       models.UsageMonthlyOrders,
+		Max:         max,
+		PlanName:    plan.Name,
+		UpgradePlan: upgrade,
+	}
+}
+
+func intLimit(limit *int) *int64 {
+	if limit == nil {
+		return nil
+	}
+	v := int64(*limit)
+	return &v
+}
+
+// ChangePlan moves a tenant to another plan
+// A more expensive plan gets an upgrade invoice and starts once it is paid; a cheaper one is
+// scheduled for the next period. Choosing the current plan again cancels a scheduled change.
+func (s *BillingService) ChangePlan(ctx context.Context, tenantID string, req *models.ChangePlanRequest) (*models.ChangePlanResponse, error) {
+	plan, err := s.repo.GetPlan(ctx, strings.TrimSpace(req.PlanCode))
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case plan.Code == sub.PlanCode:
+		if sub.ScheduledPlanCode == nil {
+			return nil, models.ErrAlreadyOnPlan
+		}
+		if err := s.repo.SetScheduledPlan(ctx, tenantID, nil); err != nil {
+			return nil, err
+		}
+		sub.ScheduledPlanCode = nil
+		return &models.ChangePlanResponse{Subscription: sub}, nil
+
+	case plan.MonthlyPrice <= sub.Plan.MonthlyPrice:
+		if err := s.repo.SetScheduledPlan(ctx, tenantID, &plan.Code); err != nil {
+			return nil, err
+		}
+		sub.ScheduledPlanCode = &plan.Code
+		return &models.ChangePlanResponse{Subscription: sub}, nil
+	}
+
+	// Only one upgrade can be waiting for payment
+	existing, err := s.repo.FindOpenUpgrade(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.PlanCode == plan.Code {
+		return &models.ChangePlanResponse{Subscription: sub, Invoice: existing}, nil
+	}
+	if err := s.repo.VoidOpenUpgrades(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invoice := &models.SubscriptionInvoice{
+		TenantID:    tenantID,
+		Kind:        models.SubscriptionInvoiceUpgrade,
+		PlanCode:    plan.Code,
+		Amount:      plan.MonthlyPrice,
+		PeriodStart: now,
+		PeriodEnd:   models.AddBillingMonth(now),
+		Status:      models.SubscriptionInvoiceOpen,
+		DueAt:       now.Add(s.grace),
+	}
+	if err := s.repo.CreateInvoice(ctx, invoice); err != nil {
+		return nil, fmt.Errorf("failed to create upgrade invoice: %w", err)
+	}
+
+	return &models.ChangePlanResponse{Subscription: sub, Invoice: invoice}, nil
+}
+
+// PayInvoice starts a Snap payment for an open invoice and returns it with the payment page URL
+// Every call is a new attempt, so a failed or abandoned payment can simply be retried.
+func (s *BillingService) PayInvoice(ctx context.Context, tenantID, invoiceID string) (*models.SubscriptionInvoice, error) {
+	if _, err := uuid.Parse(invoiceID); err != nil {
+		return nil, models.ErrInvoiceNotFound
+	}
+	if !s.credentials.Configured() {
+		return nil, models.ErrBillingNotConfigured
+	}
+
+	invoice, err := s.repo.GetInvoice(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.IsPayable() {
+		return nil, models.ErrInvoiceNotPayable
+	}
+	plan, err := s.repo.GetPlan(ctx, invoice.PlanCode)
+	if err != nil {
+		return nil, err
+	}
+
+	orderID := models.BillingChargeOrderID(invoice.ID, invoice.PaymentAttempts+1)
+	snapResp, snapErr := s.credentials.SnapClient().CreateTransaction(&snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  orderID,
+			GrossAmt: int64(invoice.Amount),
+		},
+		Items: &[]midtrans.ItemDetails{{
+			ID:    plan.Code,
+			Name:  plan.Name + " plan, " + invoice.PeriodStart.Format("2 Jan 2006"),
+			Price: int64(invoice.Amount),
+			Qty:   1,
+		}},
+	})
+	if snapErr != nil {
+		log.Error().
+			Err(snapErr).
+			Str("tenant_id", tenantID).
+			Str("invoice_id", invoice.ID).
+			Msg("Failed to create subscription Snap transaction")
+		if models.IsGatewayOutageStatus(snapErr.StatusCode) {
+			return nil, fmt.Errorf("%w: failed to create payment: %w", models.ErrPaymentGatewayUnavailable, snapErr)
+		}
+		return nil, fmt.Errorf("failed to create payment: %w", snapErr)
+	}
+
+	if err := s.repo.StartPaymentAttempt(ctx, invoice, orderID, snapResp.RedirectURL); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// ProcessNotification applies a Midtrans notification for a subscription charge
+// A settled charge pays the invoice; failed charges leave it open to be paid again, and an unpaid
+// renewal is handled by the billing job's grace period.
+func (s *BillingService) ProcessNotification(ctx context.Context, notification *MidtransNotification) error {
+	invoiceID, ok := models.ParseBillingChargeOrderID(notification.OrderID)
+	if !ok {
+		return models.ErrInvoiceNotFound
+	}
+	if _, err := uuid.Parse(invoiceID); err != nil {
+		return models.ErrInvoiceNotFound
+	}
+	if !s.credentials.Configured() {
+		return models.ErrBillingNotConfigured
+	}
+	if midtransSignature(notification, s.credentials.ServerKey) != notification.SignatureKey {
+		log.Warn().
+			Str("order_id", notification.OrderID).
+			Msg("Subscription payment signature verification failed")
+		return ErrInvalidBillingSignature
+	}
+
+	invoice, err := s.repo.GetInvoice(ctx, "", invoiceID)
+	if err != nil {
+		return err
+	}
+
+	switch models.MapMidtransStatus(notification.PaymentType, notification.TransactionStatus, notification.FraudStatus) {
+	case models.PaymentOutcomeSuccess:
+		paid, err := s.repo.MarkInvoicePaid(ctx, invoice.ID, notification.PaymentType, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to mark invoice paid: %w", err)
+		}
+		if !paid {
+			// Paid twice, or after the invoice was voided: finance refunds it by hand
+			log.Warn().
+				Str("tenant_id", invoice.TenantID).
+				Str("invoice_id", invoice.ID).
+				Str("order_id", notification.OrderID).
+				Str("invoice_status", string(invoice.Status)).
+				Msg("Payment received for a subscription invoice that is no longer open")
+			return nil
+		}
+		log.Info().
+			Str("tenant_id", invoice.TenantID).
+			Str("invoice_id", invoice.ID).
+			Str("plan_code", invoice.PlanCode).
+			Msg("Subscription invoice paid")
+		s.publishAudit(ctx, invoice.TenantID, "subscription_paid", map[string]interface{}{
+			"invoice_id": invoice.ID,
+			"plan_code":  invoice.PlanCode,
+			"kind":       invoice.Kind,
+			"amount":     invoice.Amount,
+		})
+
+	case models.PaymentOutcomeFailed:
+		log.Info().
+			Str("tenant_id", invoice.TenantID).
+			Str("invoice_id", invoice.ID).
+			Str("order_id", notification.OrderID).
+			Str("transaction_status", notification.TransactionStatus).
+			Msg("Subscription payment failed - invoice stays open")
+	}
+
+	return nil
+}
+
+// RunBillingCycle advances every subscription: scheduled free plans start, renewals are invoiced,
+// overdue renewals enter their grace period, and tenants still unpaid after it move to the free plan
+func (s *BillingService) RunBillingCycle(ctx context.Context) {
+	if tenantIDs, err := s.repo.ApplyScheduledFreePlans(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to apply scheduled plan changes")
+	} else {
+		for _, tenantID := range tenantIDs {
+			s.publishAudit(ctx, tenantID, "scheduled_downgrade", nil)
+		}
+	}
+
+	if _, err := s.repo.RollOverFreePeriods(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to roll over free subscription periods")
+	}
+
+	if tenantIDs, err := s.repo.CreateDueRenewals(ctx, s.invoiceLead); err != nil {
+		log.Error().Err(err).Msg("Failed to create renewal invoices")
+	} else if len(tenantIDs) > 0 {
+		log.Info().Int("count", len(tenantIDs)).Msg("Created subscription renewal invoices")
+	}
+
+	if tenantIDs, err := s.repo.MarkPastDue(ctx, s.grace); err != nil {
+		log.Error().Err(err).Msg("Failed to mark overdue subscriptions")
+	} else {
+		for _, tenantID := range tenantIDs {
+			log.Warn().Str("tenant_id", tenantID).Msg("Subscription renewal unpaid - grace period started")
+			s.publishAudit(ctx, tenantID, "past_due", nil)
+		}
+	}
+
+	tenantIDs, err := s.repo.ListGraceExpired(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list subscriptions past their grace period")
+	}
+	for _, tenantID := range tenantIDs {
+		if err := s.repo.DowngradeToFree(ctx, tenantID); err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to downgrade unpaid subscription")
+			continue
+		}
+		log.Warn().Str("tenant_id", tenantID).Msg("Subscription downgraded to the free plan after its grace period")
+		s.publishAudit(ctx, tenantID, "unpaid_downgrade", nil)
+	}
+
+	if _, err := s.repo.VoidExpiredUpgrades(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to void expired upgrade invoices")
+	}
+}
+
+// publishAudit records a subscription change in the audit trail
+func (s *BillingService) publishAudit(ctx context.Context, tenantID, trigger string, metadata map[string]interface{}) {
+	if s.auditPublisher == nil {
+		return
+	}
+
+	auditEvent := utils.NewSystemEvent(tenantID, "UPDATE", "tenant_subscription", tenantID)
+	auditEvent.Metadata = map[string]interface{}{"trigger": trigger}
+	for key, value := range metadata {
+		auditEvent.Metadata[key] = value
+	}
+
+	auditCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := s.auditPublisher.Publish(auditCtx, auditEvent); err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to publish subscription audit event")
+	}
+}
+
+// BillingJob runs the subscription billing cycle every hour
+type BillingJob struct {
+	service  *BillingService
+	interval time.Duration
+	stopChan chan struct{}
+}
+
+// NewBillingJob creates the billing worker
+func NewBillingJob(service *BillingService) *BillingJob {
+	return &BillingJob{
+		service:  service,
+		interval: time.Hour,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins the billing loop; it blocks until stopped
+func (j *BillingJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting subscription billing job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.service.RunBillingCycle(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping subscription billing job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping subscription billing job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the billing loop
+func (j *BillingJob) Stop() {
+	close(j.stopChan)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/queue"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// CartAbandonmentService reminds guests about carts they left behind
+// Guests opt in by leaving an email or phone number on the cart. A cart that then sits
+// idle past the threshold gets one cart.abandoned event, carrying a link that restores it.
+type CartAbandonmentService struct {
+	cartRepo             *repository.CartRepository
+	abandonmentRepo      *repository.CartAbandonmentRepository
+	tableRepo            *repository.TableRepository
+	notificationProducer *queue.KafkaProducer
+	menuBaseURL          string
+	threshold            time.Duration
+}
+
+// NewCartAbandonmentService creates a new cart abandonment service
+// menuBaseURL is the guest frontend origin recovery links point to.
+func NewCartAbandonmentService(
+	cartRepo *repository.CartRepository,
+	abandonmentRepo *repository.CartAbandonmentRepository,
+	tableRepo *repository.TableRepository,
+	notificationProducer *queue.KafkaProducer,
+	menuBaseURL string,
+	threshold time.Duration,
+) *CartAbandonmentService {
+	return &CartAbandonmentService{
+		cartRepo:             cartRepo,
+		abandonmentRepo:      abandonmentRepo,
+		tableRepo:            tableRepo,
+		notificationProducer: notificationProducer,
+		menuBaseURL:          menuBaseURL,
+		threshold:            threshold,
+	}
+}
+
+// SaveContact records where to remind the guest if they leave the cart behind
+func (s *CartAbandonmentService) SaveContact(ctx context.Context, tenantID, cartID string, contact *models.CartContact) error {
+	if err := contact.Validate(); err != nil {
+		return err
+	}
+	return s.abandonmentRepo.SaveContact(ctx, tenantID, cartID, contact, s.cartRepo.TTLFor(cartID))
+}
+
+// ResumeCart restores an abandoned cart from a recovery link into the guest's current cart
+func (s *CartAbandonmentService) ResumeCart(ctx context.Context, tenantID, cartID, token string) (*models.Cart, error) {
+	resume, err := s.abandonmentRepo.GetResume(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if resume.TenantID != tenantID {
+		return nil, models.ErrCartResumeNotFound
+	}
+
+	cart, err := s.cartRepo.Get(ctx, tenantID, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	cart.Merge(&models.Cart{Items: resume.Items, VoucherCode: resume.VoucherCode})
+	if err := s.cartRepo.Save(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to save cart: %w", err)
+	}
+	return cart, nil
+}
+
+// DetectAbandonedCarts emits cart.abandoned for tracked carts idle past the threshold
+// Each cart is reminded about once; emptied, expired and checked-out carts are dropped quietly.
+func (s *CartAbandonmentService) DetectAbandonedCarts(ctx context.Context, limit int) (int, error) {
+	idle, err := s.abandonmentRepo.ListIdle(ctx, time.Now().Add(-s.threshold), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	emitted := 0
+	for _, candidate := range idle {
+		sent, err := s.remind(ctx, candidate)
+		if err != nil {
+			log.Error().Err(err).
+				Str("tenant_id", candidate.TenantID).
+				Msg("Failed to send abandoned cart reminder")
+		}
+		if sent {
+			emitted++
+		}
+		// A failed reminder is not retried, so a broken cart cannot hold up the queue
+		if err := s.abandonmentRepo.Forget(ctx, candidate.TenantID, candidate.CartID); err != nil {
+			log.Error().Err(err).Str("tenant_id", candidate.TenantID).Msg("Failed to stop tracking cart")
+		}
+	}
+	return emitted, nil
+}
+
+// remind publishes the cart.abandoned event for one idle cart
+func (s *CartAbandonmentService) remind(ctx context.Context, candidate repository.IdleCart) (bool, error) {
+	cart, err := s.cartRepo.Get(ctx, candidate.TenantID, candidate.CartID)
+	if err != nil {
+		return false, err
+	}
+	if len(cart.Items) == 0 {
+		return false, nil
+	}
+	contact, err := s.abandonmentRepo.GetContact(ctx, candidate.TenantID, candidate.CartID)
+	if err != nil || contact == nil {
+		return false, err
+	}
+
+	slug, err := s.tableRepo.GetTenantSlug(ctx, candidate.TenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tenant slug: %w", err)
+	}
+	token, err := s.abandonmentRepo.SaveResume(ctx, &models.CartResume{
+		TenantID:    candidate.TenantID,
+		Items:       cart.Items,
+		VoucherCode: cart.VoucherCode,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if s.notificationProducer == nil {
+		return false, errors.New("kafka producer not initialized")
+	}
+
+	items := make([]map[string]interface{}, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, map[string]interface{}{
+			"product_name": item.ProductName,
+			"quantity":     item.Quantity,
+		})
+	}
+	event := map[string]interface{}{
+		"event_type": "cart.abandoned",
+		"tenant_id":  candidate.TenantID,
+		"user_id":    "", // Empty for guests
+		"data": map[string]interface{}{
+			"customer_name":  contact.Name,
+			"customer_email": contact.Email,
+			"customer_phone": contact.Phone,
+			"items":          items,
+			"item_count":     cart.GetItemCount(),
+			"total_amount":   cart.GetTotal(),
+			"resume_url":     models.CartResumeURL(s.menuBaseURL, slug, token),
+			"expires_at":     time.Now().Add(models.CartResumeTTL).Format(time.RFC3339),
+		},
+	}
+	if err := s.notificationProducer.Publish(ctx, candidate.TenantID, event); err != nil {
+		return false, fmt.Errorf("failed to publish cart.abandoned event: %w", err)
+	}
+	return true, nil
+}
+
+// CartAbandonmentJob periodically looks for abandoned carts
+type CartAbandonmentJob struct {
+	service   *CartAbandonmentService
+	interval  time.Duration
+	batchSize int
+	stopChan  chan struct{}
+}
+
+// NewCartAbandonmentJob creates the cart abandonment worker
+func NewCartAbandonmentJob(service *CartAbandonmentService) *CartAbandonmentJob {
+	return &CartAbandonmentJob{
+		service:   service,
+		interval:  time.Minute,
+		batchSize: 100,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start begins the detection loop; it blocks until stopped
+func (j *CartAbandonmentJob) Start(ctx context.Context) {
+	log.Info().Dur("threshold", j.service.threshold).Msg("Starting cart abandonment job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			emitted, err := j.service.DetectAbandonedCarts(ctx, j.batchSize)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to detect abandoned carts")
+			} else if emitted > 0 {
+				log.Info().Int("reminded", emitted).Msg("Sent abandoned cart reminders")
+			}
+		case <-j.stopChan:
+			log.Info().Msg("Stopping cart abandonment job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping cart abandonment job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the detection loop
+func (j *CartAbandonmentJob) Stop() {
+	close(j.stopChan)
+}
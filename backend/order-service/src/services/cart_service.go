@@ -5,21 +5,85 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/repository"
 )
 
 type CartService struct {
-	cartRepo        *repository.CartRepository
-	reservationRepo *repository.ReservationRepository
-	db              *sql.DB
+	cartRepo         *repository.CartRepository
+	reservationRepo  *repository.ReservationRepository
+	promotionService *PromotionService
+	settingsRepo     *repository.OrderSettingsRepository
+	sessionRepo      *repository.CustomerSessionRepository
+	db               *sql.DB
 }
 
-func NewCartService(cartRepo *repository.CartRepository, reservationRepo *repository.ReservationRepository, db *sql.DB) *CartService {
+func NewCartService(cartRepo *repository.CartRepository, reservationRepo *repository.ReservationRepository, promotionService *PromotionService, settingsRepo *repository.OrderSettingsRepository, sessionRepo *repository.CustomerSessionRepository, db *sql.DB) *CartService {
 	return &CartService{
-		cartRepo:        cartRepo,
-		reservationRepo: reservationRepo,
-		db:              db,
+		cartRepo:         cartRepo,
+		reservationRepo:  reservationRepo,
+		promotionService: promotionService,
+		settingsRepo:     settingsRepo,
+		sessionRepo:      sessionRepo,
+		db:               db,
+	}
+}
+
+// ResolveCartID returns the ID of the cart a request works on
+// Without a customer session token this is the device session ID. With one, it is the
+// guest's cross-device cart, into which the device's anonymous cart is merged first.
+func (s *CartService) ResolveCartID(ctx context.Context, tenantID, sessionID, customerToken string) (string, error) {
+	if customerToken == "" {
+		return sessionID, nil
+	}
+
+	phoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, customerToken)
+	if err != nil {
+		return "", err
+	}
+	cartID := models.CustomerCartID(phoneHash)
+
+	anonymous, err := s.cartRepo.Get(ctx, tenantID, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get session cart: %w", err)
+	}
+	if len(anonymous.Items) == 0 && anonymous.VoucherCode == "" {
+		return cartID, nil
+	}
+
+	cart, err := s.cartRepo.Get(ctx, tenantID, cartID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get customer cart: %w", err)
+	}
+	cart.Merge(anonymous)
+	if err := s.cartRepo.Save(ctx, cart); err != nil {
+		return "", fmt.Errorf("failed to save merged cart: %w", err)
+	}
+	if err := s.cartRepo.Delete(ctx, tenantID, sessionID); err != nil {
+		return "", fmt.Errorf("failed to clear session cart: %w", err)
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("session_id", sessionID).
+		Int("item_count", cart.GetItemCount()).
+		Msg("Merged session cart into customer cart")
+	return cartID, nil
+}
+
+// priceCart applies automatic promotions to a cart being returned to the guest
+// A pricing failure only hides the discount; checkout prices the cart again and fails hard.
+func (s *CartService) priceCart(ctx context.Context, cart *models.Cart) {
+	if s.promotionService == nil {
+		return
+	}
+	if err := s.promotionService.PriceCart(ctx, cart); err != nil {
+		log.Warn().Err(err).
+			Str("tenant_id", cart.TenantID).
+			Str("session_id", cart.SessionID).
+			Msg("Failed to apply promotions to cart")
 	}
 }
 
@@ -34,6 +98,7 @@ func (s *CartService) GetCart(ctx context.Context, tenantID, sessionID string) (
 		return nil, err
 	}
 
+	s.priceCart(ctx, cart)
 	return cart, nil
 }
 
@@ -48,10 +113,8 @@ func (s *CartService) ValidateAndAdjustCart(ctx context.Context, cart *models.Ca
 	itemsToKeep := []models.CartItem{}
 
 	for _, item := range cart.Items {
-		// Get product stock from database
-		var stockQty int
-		query := `SELECT stock_quantity FROM products WHERE id = $1 AND tenant_id = $2 AND archived_at IS NULL`
-		err := s.db.QueryRowContext(ctx, query, item.ProductID, cart.TenantID).Scan(&stockQty)
+		// Available-to-promise stock (stock on hand minus active reservations)
+		availableStock, err := s.reservationRepo.GetAvailableToPromise(ctx, nil, cart.TenantID, item.ProductID, false)
 		if err == sql.ErrNoRows {
 			// Product no longer exists or archived - remove from cart
 			adjusted = true
@@ -61,15 +124,6 @@ func (s *CartService) ValidateAndAdjustCart(ctx context.Context, cart *models.Ca
 			return fmt.Errorf("failed to check product stock: %w", err)
 		}
 
-		// Get total reserved quantity for this product
-		reservedQty, err := s.reservationRepo.GetTotalReservedQuantity(ctx, item.ProductID)
-		if err != nil {
-			return fmt.Errorf("failed to check reservations: %w", err)
-		}
-
-		// Calculate available stock
-		availableStock := stockQty - reservedQty
-
 		if availableStock <= 0 {
 			// No stock available - remove item from cart
 			adjusted = true
@@ -112,7 +166,10 @@ func (s *CartService) AddItem(ctx context.Context, tenantID, sessionID, productI
 		}
 	}
 
-	// Validate stock availability
+	// Validate the tenant's item limit and stock availability
+	if err := s.validateItemLimit(ctx, tenantID, cart.GetItemCount()+quantity); err != nil {
+		return nil, err
+	}
 	if err := s.validateStock(ctx, tenantID, productID, newQuantity); err != nil {
 		return nil, err
 	}
@@ -141,6 +198,7 @@ func (s *CartService) AddItem(ctx context.Context, tenantID, sessionID, productI
 		return nil, fmt.Errorf("failed to save cart: %w", err)
 	}
 
+	s.priceCart(ctx, cart)
 	return cart, nil
 }
 
@@ -150,8 +208,18 @@ func (s *CartService) UpdateItem(ctx context.Context, tenantID, sessionID, produ
 		return nil, fmt.Errorf("failed to get cart: %w", err)
 	}
 
-	// Validate stock availability if increasing quantity
+	// Validate the item limit and stock availability if increasing quantity
 	if quantity > 0 {
+		newCount := cart.GetItemCount() + quantity
+		for _, item := range cart.Items {
+			if item.ProductID == productID {
+				newCount -= item.Quantity
+				break
+			}
+		}
+		if err := s.validateItemLimit(ctx, tenantID, newCount); err != nil {
+			return nil, err
+		}
 		if err := s.validateStock(ctx, tenantID, productID, quantity); err != nil {
 			return nil, err
 		}
@@ -179,6 +247,7 @@ func (s *CartService) UpdateItem(ctx context.Context, tenantID, sessionID, produ
 		return nil, fmt.Errorf("failed to save cart: %w", err)
 	}
 
+	s.priceCart(ctx, cart)
 	return cart, nil
 }
 
@@ -199,6 +268,7 @@ func (s *CartService) RemoveItem(ctx context.Context, tenantID, sessionID, produ
 		return nil, fmt.Errorf("failed to save cart: %w", err)
 	}
 
+	s.priceCart(ctx, cart)
 	return cart, nil
 }
 
@@ -206,12 +276,23 @@ func (s *CartService) ClearCart(ctx context.Context, tenantID, sessionID string)
 	return s.cartRepo.Delete(ctx, tenantID, sessionID)
 }
 
+// validateItemLimit checks a cart's unit count against the tenant's max_items_per_order
+// Growing a cart past the limit is refused here so guests learn before checkout.
+func (s *CartService) validateItemLimit(ctx context.Context, tenantID string, itemCount int) error {
+	if s.settingsRepo == nil {
+		return nil
+	}
+	settings, err := s.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load order settings: %w", err)
+	}
+	return settings.CheckItemLimit(itemCount)
+}
+
 // validateStock checks if the requested quantity is available (stock - active reservations)
 func (s *CartService) validateStock(ctx context.Context, tenantID, productID string, requestedQty int) error {
-	// Get product stock from database
-	var stockQty int
-	query := `SELECT stock_quantity FROM products WHERE id = $1 AND tenant_id = $2 AND archived_at IS NULL`
-	err := s.db.QueryRowContext(ctx, query, productID, tenantID).Scan(&stockQty)
+	// Available-to-promise stock (stock on hand minus active reservations)
+	availableStock, err := s.reservationRepo.GetAvailableToPromise(ctx, nil, tenantID, productID, false)
 	if err == sql.ErrNoRows {
 		return fmt.Errorf("product not found or unavailable")
 	}
@@ -219,15 +300,6 @@ func (s *CartService) validateStock(ctx context.Context, tenantID, productID str
 		return fmt.Errorf("failed to check product stock: %w", err)
 	}
 
-	// Get total reserved quantity for this product
-	reservedQty, err := s.reservationRepo.GetTotalReservedQuantity(ctx, productID)
-	if err != nil {
-		return fmt.Errorf("failed to check reservations: %w", err)
-	}
-
-	// Calculate available stock
-	availableStock := stockQty - reservedQty
-
 	if requestedQty > availableStock {
 		return fmt.Errorf("insufficient stock: only %d available (requested: %d)", availableStock, requestedQty)
 	}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// CashierShiftService opens and closes cashier shifts and reconciles their cash drawers
+// A cashier has at most one open shift. Its cash sales are the cash payments the cashier
+// records while it is open; cash refunds are paid from its drawer against cash-paid orders.
+type CashierShiftService struct {
+	db           *sql.DB
+	shiftRepo    *repository.CashierShiftRepository
+	paymentRepo  *repository.PaymentRepository
+	orderService *OrderService
+}
+
+// NewCashierShiftService creates a new cashier shift service
+func NewCashierShiftService(
+	db *sql.DB,
+	shiftRepo *repository.CashierShiftRepository,
+	paymentRepo *repository.PaymentRepository,
+	orderService *OrderService,
+) *CashierShiftService {
+	return &CashierShiftService{
+		db:           db,
+		shiftRepo:    shiftRepo,
+		paymentRepo:  paymentRepo,
+		orderService: orderService,
+	}
+}
+
+// ShiftActor is the staff member working or closing a shift
+type ShiftActor struct {
+	UserID string
+	Name   string
+}
+
+// OpenShift opens a shift for the actor with the cash placed in the drawer
+func (s *CashierShiftService) OpenShift(ctx context.Context, tenantID string, req *models.OpenShiftRequest, actor ShiftActor) (*models.CashierShift, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	shift := &models.CashierShift{
+		TenantID:     tenantID,
+		CashierID:    actor.UserID,
+		CashierName:  optionalString(actor.Name),
+		OpeningFloat: req.OpeningFloat,
+	}
+	if err := s.shiftRepo.Open(ctx, shift); err != nil {
+		return nil, err
+	}
+	shift.SetCashTotals(0, 0)
+
+	log.Info().
+		Str("shift_id", shift.ID).
+		Str("tenant_id", tenantID).
+		Str("cashier_id", actor.UserID).
+		Int("opening_float", shift.OpeningFloat).
+		Msg("Cashier shift opened")
+	return shift, nil
+}
+
+// GetCurrentShift returns the actor's open shift with its running totals
+func (s *CashierShiftService) GetCurrentShift(ctx context.Context, tenantID string, actor ShiftActor) (*models.ShiftReport, error) {
+	shift, err := s.shiftRepo.GetOpen(ctx, nil, tenantID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.report(ctx, shift)
+}
+
+// GetShiftReport returns one of the tenant's shifts with its cash detail
+func (s *CashierShiftService) GetShiftReport(ctx context.Context, tenantID, shiftID string) (*models.ShiftReport, error) {
+	shift, err := s.shiftRepo.Get(ctx, nil, tenantID, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	return s.report(ctx, shift)
+}
+
+// ListShifts returns the tenant's shifts, newest first; open shifts show their running totals
+func (s *CashierShiftService) ListShifts(ctx context.Context, tenantID string, cashierID *string, status *models.CashierShiftStatus, limit, offset int) ([]*models.CashierShift, error) {
+	shifts, err := s.shiftRepo.List(ctx, tenantID, cashierID, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for _, shift := range shifts {
+		if shift.Status != models.CashierShiftOpen {
+			continue
+		}
+		if _, err := s.setRunningTotals(ctx, nil, shift); err != nil {
+			return nil, err
+		}
+	}
+	return shifts, nil
+}
+
+// RecordCashRefund pays cash back for an order from the actor's open shift
+// The refund is limited to the cash paid for the order less earlier cash refunds.
+func (s *CashierShiftService) RecordCashRefund(ctx context.Context, tenantID string, req *models.CashRefundRequest, actor ShiftActor) (*models.CashRefund, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderService.GetOrderByID(ctx, req.OrderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return nil, ErrOrderNotFound
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	shift, err := s.shiftRepo.GetOpen(ctx, tx, tenantID, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.paymentRepo.LockOrderForPayment(ctx, tx, order.ID); err != nil {
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+
+	paid, refunded, err := s.shiftRepo.GetOrderCashBalance(ctx, tx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order cash balance: %w", err)
+	}
+	if refunded+req.Amount > paid {
+		return nil, models.ErrCashRefundExceedsCash
+	}
+
+	refund := &models.CashRefund{
+		ShiftID:        shift.ID,
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Amount:         req.Amount,
+		Reason:         req.Reason,
+		RecordedBy:     optionalString(actor.UserID),
+		RecordedByName: optionalString(actor.Name),
+	}
+	if err := s.shiftRepo.AddCashRefund(ctx, tx, refund); err != nil {
+		return nil, fmt.Errorf("failed to record cash refund: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit cash refund: %w", err)
+	}
+
+	note := fmt.Sprintf("Refunded %d in cash. Reason: %s", refund.Amount, refund.Reason)
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, actor.Name); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add cash refund note")
+	}
+
+	log.Info().
+		Str("shift_id", shift.ID).
+		Str("order_id", order.ID).
+		Int("amount", refund.Amount).
+		Msg("Cash refund paid from drawer")
+	return refund, nil
+}
+
+// CloseCurrentShift closes the actor's open shift with the cash they counted
+func (s *CashierShiftService) CloseCurrentShift(ctx context.Context, tenantID string, req *models.CloseShiftRequest, actor ShiftActor) (*models.ShiftReport, error) {
+	return s.close(ctx, req, actor, func(tx *sql.Tx) (*models.CashierShift, error) {
+		return s.shiftRepo.GetOpen(ctx, tx, tenantID, actor.UserID)
+	})
+}
+
+// CloseShift closes any of the tenant's open shifts, for a manager closing a drawer left open
+func (s *CashierShiftService) CloseShift(ctx context.Context, tenantID, shiftID string, req *models.CloseShiftRequest, actor ShiftActor) (*models.ShiftReport, error) {
+	return s.close(ctx, req, actor, func(tx *sql.Tx) (*models.CashierShift, error) {
+		shift, err := s.shiftRepo.Get(ctx, tx, tenantID, shiftID)
+		if err != nil {
+			return nil, err
+		}
+		if shift.Status != models.CashierShiftOpen {
+			return nil, models.ErrShiftClosed
+		}
+		return shift, nil
+	})
+}
+
+// close freezes a locked open shift's totals and compares them with the counted cash
+func (s *CashierShiftService) close(ctx context.Context, req *models.CloseShiftRequest, actor ShiftActor, lock func(tx *sql.Tx) (*models.CashierShift, error)) (*models.ShiftReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	shift, err := lock(tx)
+	if err != nil {
+		return nil, err
+	}
+	saleCount, err := s.setRunningTotals(ctx, tx, shift)
+	if err != nil {
+		return nil, err
+	}
+
+	shift.Reconcile(*req.CountedCash)
+	shift.CloseNote = optionalString(req.Note)
+	shift.ClosedBy = optionalString(actor.UserID)
+	shift.ClosedByName = optionalString(actor.Name)
+	if err := s.shiftRepo.Close(ctx, tx, shift, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to close shift: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit shift close: %w", err)
+	}
+
+	log.Info().
+		Str("shift_id", shift.ID).
+		Str("cashier_id", shift.CashierID).
+		Int("expected_cash", shift.ExpectedCash).
+		Int("counted_cash", *shift.CountedCash).
+		Int("variance", *shift.Variance).
+		Msg("Cashier shift closed")
+
+	refunds, err := s.shiftRepo.ListCashRefunds(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash refunds: %w", err)
+	}
+	return &models.ShiftReport{CashierShift: shift, CashSaleCount: saleCount, Refunds: refunds}, nil
+}
+
+// report adds the cash detail to a shift; an open shift's totals are computed up to now
+func (s *CashierShiftService) report(ctx context.Context, shift *models.CashierShift) (*models.ShiftReport, error) {
+	var saleCount int
+	var err error
+	if shift.Status == models.CashierShiftOpen {
+		saleCount, err = s.setRunningTotals(ctx, nil, shift)
+	} else {
+		_, saleCount, err = s.shiftRepo.GetCashSales(ctx, nil, shift)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.shiftRepo.ListCashRefunds(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash refunds: %w", err)
+	}
+	return &models.ShiftReport{CashierShift: shift, CashSaleCount: saleCount, Refunds: refunds}, nil
+}
+
+// setRunningTotals computes an open shift's cash totals and returns its number of cash sales
+func (s *CashierShiftService) setRunningTotals(ctx context.Context, tx *sql.Tx, shift *models.CashierShift) (int, error) {
+	sales, saleCount, err := s.shiftRepo.GetCashSales(ctx, tx, shift)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cash sales: %w", err)
+	}
+	refunds, err := s.shiftRepo.GetCashRefundTotal(ctx, tx, shift.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cash refunds: %w", err)
+	}
+	shift.SetCashTotals(sales, refunds)
+	return saleCount, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// Courier is implemented by each third-party courier a tenant can book deliveries with
+// Webhooks are normalized to models.CourierUpdate before they reach the booking.
+type Courier interface {
+	// Provider identifies the courier; it is stored on every booking it makes
+	Provider() models.CourierProvider
+	// Book asks the courier to pick an order up at the store and deliver it
+	Book(ctx context.Context, req *CourierBookingRequest) (*CourierBookingResult, error)
+	// Cancel calls off a booking that has not been delivered
+	Cancel(ctx context.Context, booking *models.CourierBooking) error
+	// ParseWebhook verifies that a webhook came from the courier and normalizes it
+	// An empty Status in the result means the webhook does not change the delivery status.
+	ParseWebhook(headers http.Header, body []byte) (*models.CourierUpdate, error)
+}
+
+// CourierBookingRequest is a paid delivery order to hand to a courier
+type CourierBookingRequest struct {
+	Order       *models.GuestOrder
+	Items       []models.OrderItem
+	Settings    *models.CourierSettings // Pickup point and service type
+	Destination *models.DeliveryAddress
+}
+
+// itemSummary describes the parcel for the driver, e.g. "2x Es Kopi Susu, 1x Croissant"
+func (r *CourierBookingRequest) itemSummary() string {
+	parts := make([]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		parts = append(parts, fmt.Sprintf("%dx %s", item.Quantity, item.ProductName))
+	}
+	summary := strings.Join(parts, ", ")
+	if summary == "" {
+		summary = "Order " + r.Order.OrderReference
+	}
+	if len(summary) > 250 {
+		summary = summary[:247] + "..."
+	}
+	return summary
+}
+
+// CourierBookingResult is the courier's answer to a booking
+type CourierBookingResult struct {
+	ExternalID  string
+	Status      models.DeliveryStatus
+	TrackingURL string
+	Fee         int // Quoted courier fee in IDR; 0 when the courier did not quote one
+}
+
+// verifyCourierWebhookToken compares the shared secret a courier sends with its webhooks
+func verifyCourierWebhookToken(expected, received string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(received)) == 1
+}
+
+// courierRequest sends a JSON request to a courier API and decodes the JSON response into out
+// Failures the courier cannot be blamed for, such as timeouts and 5xx, wrap models.ErrCourierUnavailable.
+func courierRequest(ctx context.Context, client *http.Client, provider models.CourierProvider, method, url string, headers map[string]string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s request: %w", provider, err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", provider, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Error().Err(err).Str("provider", string(provider)).Str("url", url).Msg("Failed to call courier API")
+		return fmt.Errorf("%w: %s request failed: %w", models.ErrCourierUnavailable, provider, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", provider, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message := strings.TrimSpace(string(respBody))
+		if len(message) > 300 {
+			message = message[:300]
+		}
+		log.Error().
+			Str("provider", string(provider)).
+			Int("status_code", resp.StatusCode).
+			Str("response", message).
+			Str("url", url).
+			Msg("Courier request failed")
+		if models.IsGatewayOutageStatus(resp.StatusCode) {
+			return fmt.Errorf("%w: %s returned status %d", models.ErrCourierUnavailable, provider, resp.StatusCode)
+		}
+		return fmt.Errorf("%s rejected the request with status %d: %s", provider, resp.StatusCode, message)
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", provider, err)
+		}
+	}
+	return nil
+}
+
+// formatLatLong formats coordinates as "lat,long" with the precision couriers expect
+func formatLatLong(latitude, longitude float64) string {
+	return strconv.FormatFloat(latitude, 'f', 6, 64) + "," + strconv.FormatFloat(longitude, 'f', 6, 64)
+}
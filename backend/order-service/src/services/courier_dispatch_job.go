@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CourierDispatchJob books couriers for paid delivery orders of tenants with automatic dispatch
+// and fails bookings the provider never answered, so they can be booked again.
+type CourierDispatchJob struct {
+	service   *CourierService
+	interval  time.Duration
+	batchSize int
+	stopChan  chan struct{}
+}
+
+// NewCourierDispatchJob creates the courier dispatch job
+func NewCourierDispatchJob(service *CourierService) *CourierDispatchJob {
+	return &CourierDispatchJob{
+		service:   service,
+		interval:  30 * time.Second,
+		batchSize: 50,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start begins the dispatch loop; it blocks until stopped
+func (j *CourierDispatchJob) Start(ctx context.Context) {
+	log.Info().Dur("interval", j.interval).Msg("Starting courier dispatch job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.run(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping courier dispatch job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping courier dispatch job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the job
+func (j *CourierDispatchJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *CourierDispatchJob) run(ctx context.Context) {
+	expired, err := j.service.ExpireStaleRequests(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to expire stale courier bookings")
+	} else if expired > 0 {
+		log.Warn().Int64("count", expired).Msg("Expired courier bookings without a provider answer")
+	}
+
+	dispatched, err := j.service.AutoDispatch(ctx, j.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to auto-dispatch couriers")
+		return
+	}
+	if dispatched > 0 {
+		log.Info().Int("count", dispatched).Msg("Couriers booked automatically")
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// CourierService books third-party couriers for paid delivery orders and tracks their progress
+type CourierService struct {
+	courierRepo *repository.CourierRepository
+	orderRepo   *repository.OrderRepository
+	addressRepo *repository.AddressRepository
+	couriers    map[models.CourierProvider]Courier
+}
+
+// NewCourierService creates a new courier service with the couriers the platform has credentials for
+func NewCourierService(
+	courierRepo *repository.CourierRepository,
+	orderRepo *repository.OrderRepository,
+	addressRepo *repository.AddressRepository,
+	couriers ...Courier,
+) *CourierService {
+	byProvider := make(map[models.CourierProvider]Courier, len(couriers))
+	for _, courier := range couriers {
+		byProvider[courier.Provider()] = courier
+	}
+	return &CourierService{
+		courierRepo: courierRepo,
+		orderRepo:   orderRepo,
+		addressRepo: addressRepo,
+		couriers:    byProvider,
+	}
+}
+
+// GetSettings returns a tenant's courier settings
+func (s *CourierService) GetSettings(ctx context.Context, tenantID string) (*models.CourierSettings, error) {
+	return s.courierRepo.GetSettings(ctx, tenantID)
+}
+
+// UpdateSettings changes a tenant's courier settings
+// Only providers the platform has credentials for can be chosen.
+func (s *CourierService) UpdateSettings(ctx context.Context, tenantID string, req *models.UpdateCourierSettingsRequest) (*models.CourierSettings, error) {
+	settings, err := s.courierRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier settings: %w", err)
+	}
+
+	if err := settings.Apply(req); err != nil {
+		return nil, err
+	}
+	if settings.Provider != nil {
+		if _, ok := s.couriers[*settings.Provider]; !ok {
+			return nil, fmt.Errorf("%w: %s is not available", models.ErrInvalidCourierSettings, settings.Provider.DisplayName())
+		}
+	}
+
+	if err := s.courierRepo.UpsertSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save courier settings: %w", err)
+	}
+	return settings, nil
+}
+
+// ListBookings returns the couriers booked for one of a tenant's orders, newest first
+func (s *CourierService) ListBookings(ctx context.Context, tenantID, orderID string) ([]*models.CourierBooking, error) {
+	if _, err := s.getTenantOrder(ctx, tenantID, orderID); err != nil {
+		return nil, err
+	}
+	return s.courierRepo.ListByOrder(ctx, orderID)
+}
+
+// Dispatch books a courier for a paid delivery order with the tenant's provider
+// The booking is recorded before the provider is called, so an order is never booked twice.
+// When the provider refuses the booking it is kept as failed and returned with the error.
+func (s *CourierService) Dispatch(ctx context.Context, tenantID, orderID string, auto bool) (*models.CourierBooking, error) {
+	order, err := s.getTenantOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPaid || order.DeliveryType != models.DeliveryTypeDelivery {
+		return nil, models.ErrOrderNotDispatchable
+	}
+
+	settings, err := s.courierRepo.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier settings: %w", err)
+	}
+	if settings.Provider == nil {
+		return nil, models.ErrCourierNotConfigured
+	}
+	courier, ok := s.couriers[*settings.Provider]
+	if !ok {
+		return nil, models.ErrCourierNotConfigured
+	}
+
+	destination, err := s.addressRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery address: %w", err)
+	}
+	if destination == nil || !destination.HasCoordinates() {
+		return nil, models.ErrDeliveryAddressUnusable
+	}
+
+	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	booking := &models.CourierBooking{
+		TenantID:       tenantID,
+		OrderID:        orderID,
+		OrderReference: order.OrderReference,
+		Provider:       courier.Provider(),
+		ServiceType:    settings.ServiceType,
+		AutoDispatched: auto,
+	}
+	if err := s.courierRepo.CreateBooking(ctx, booking); err != nil {
+		return nil, err
+	}
+
+	result, err := courier.Book(ctx, &CourierBookingRequest{
+		Order:       order,
+		Items:       items,
+		Settings:    settings,
+		Destination: destination,
+	})
+	if err != nil {
+		if markErr := s.courierRepo.MarkFailed(ctx, booking, err.Error()); markErr != nil {
+			log.Error().Err(markErr).Str("booking_id", booking.ID).Msg("Failed to record failed courier booking")
+		}
+		if !errors.Is(err, models.ErrCourierUnavailable) {
+			err = fmt.Errorf("%w: %v", models.ErrCourierBookingRejected, err)
+		}
+		return booking, err
+	}
+
+	booking.Status = result.Status
+	booking.ExternalID = &result.ExternalID
+	if result.TrackingURL != "" {
+		booking.TrackingURL = &result.TrackingURL
+	}
+	if result.Fee > 0 {
+		booking.QuotedFee = &result.Fee
+	}
+
+	booked, err := s.courierRepo.MarkBooked(ctx, booking)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save courier booking: %w", err)
+	}
+	if !booked {
+		// The booking timed out while the provider was answering; call the courier off
+		// so the order does not end up with a driver nobody tracks.
+		if cancelErr := courier.Cancel(ctx, booking); cancelErr != nil {
+			log.Error().Err(cancelErr).Str("booking_id", booking.ID).Str("external_id", result.ExternalID).
+				Msg("Failed to cancel courier booking that timed out")
+		}
+		return nil, fmt.Errorf("%w: booking timed out", models.ErrCourierUnavailable)
+	}
+
+	note := fmt.Sprintf("Courier booked with %s (ref %s)", booking.Provider.DisplayName(), result.ExternalID)
+	if booking.QuotedFee != nil {
+		note += fmt.Sprintf(", fee Rp %d", *booking.QuotedFee)
+	}
+	s.addNote(ctx, booking, note)
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("provider", string(booking.Provider)).
+		Str("external_id", result.ExternalID).
+		Bool("auto", auto).
+		Msg("Courier booked")
+	return booking, nil
+}
+
+// CancelBooking calls off the live courier booking of an order
+func (s *CourierService) CancelBooking(ctx context.Context, tenantID, orderID, reason, userName string) (*models.CourierBooking, error) {
+	if _, err := s.getTenantOrder(ctx, tenantID, orderID); err != nil {
+		return nil, err
+	}
+
+	booking, err := s.courierRepo.GetActiveByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get courier booking: %w", err)
+	}
+	if booking == nil {
+		return nil, models.ErrCourierBookingNotFound
+	}
+	if booking.Status == models.DeliveryPickedUp {
+		// The driver already has the food; it has to be sorted out with the provider
+		return nil, models.ErrCourierBookingFinished
+	}
+
+	courier, ok := s.couriers[booking.Provider]
+	if !ok {
+		return nil, models.ErrCourierNotConfigured
+	}
+	if err := courier.Cancel(ctx, booking); err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "cancelled by staff"
+	}
+	if err := s.courierRepo.Cancel(ctx, booking, reason); err != nil {
+		return nil, err
+	}
+
+	name := userName
+	if name == "" {
+		name = "Admin"
+	}
+	s.addNote(ctx, booking, fmt.Sprintf("%s courier cancelled by %s: %s", booking.Provider.DisplayName(), name, reason))
+	return booking, nil
+}
+
+// HandleWebhook applies a courier webhook to the booking it refers to
+// Returns models.ErrInvalidCourierWebhook when the webhook cannot be verified and
+// models.ErrCourierBookingNotFound when it refers to a booking we never made.
+func (s *CourierService) HandleWebhook(ctx context.Context, provider models.CourierProvider, headers http.Header, body []byte) error {
+	courier, ok := s.couriers[provider]
+	if !ok {
+		return models.ErrCourierNotConfigured
+	}
+
+	update, err := courier.ParseWebhook(headers, body)
+	if err != nil {
+		return err
+	}
+
+	booking, changed, err := s.courierRepo.ApplyUpdate(ctx, update)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("provider", string(provider)).
+		Str("external_id", update.ExternalID).
+		Str("raw_status", update.RawStatus).
+		Str("status", string(booking.Status)).
+		Bool("changed", changed).
+		Msg("Courier webhook processed")
+
+	if changed {
+		s.addNote(ctx, booking, deliveryNote(booking, update))
+	}
+	return nil
+}
+
+// AutoDispatch books couriers for paid delivery orders of tenants that dispatch automatically
+func (s *CourierService) AutoDispatch(ctx context.Context, limit int) (int, error) {
+	candidates, err := s.courierRepo.FindAutoDispatchable(ctx, models.CourierDispatchLeadTime, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, candidate := range candidates {
+		if _, err := s.Dispatch(ctx, candidate.TenantID, candidate.OrderID, true); err != nil {
+			// Failed bookings stay on the order for staff to retry by hand
+			log.Warn().Err(err).Str("order_id", candidate.OrderID).Msg("Automatic courier booking failed")
+			continue
+		}
+		dispatched++
+	}
+	return dispatched, nil
+}
+
+// ExpireStaleRequests fails bookings whose provider never answered
+func (s *CourierService) ExpireStaleRequests(ctx context.Context) (int64, error) {
+	return s.courierRepo.ExpireStaleRequests(ctx, models.CourierBookingTimeout)
+}
+
+func (s *CourierService) getTenantOrder(ctx context.Context, tenantID, orderID string) (*models.GuestOrder, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && order.TenantID != tenantID) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+// addNote records courier progress in the order history; a missing note is not worth failing over
+func (s *CourierService) addNote(ctx context.Context, booking *models.CourierBooking, note string) {
+	createdBy := booking.Provider.DisplayName()
+	err := s.orderRepo.CreateOrderNote(ctx, &models.OrderNote{
+		OrderID:       booking.OrderID,
+		Note:          note,
+		CreatedByName: &createdBy,
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("order_id", booking.OrderID).Msg("Failed to add courier note")
+	}
+}
+
+// deliveryNote describes a delivery status change for the order history
+func deliveryNote(booking *models.CourierBooking, update *models.CourierUpdate) string {
+	switch booking.Status {
+	case models.DeliveryFindingDriver:
+		return "Courier is looking for a driver"
+	case models.DeliveryDriverAssigned:
+		if update.DriverName != "" {
+			note := "Driver assigned: " + update.DriverName
+			if update.VehiclePlate != "" {
+				note += " (" + update.VehiclePlate + ")"
+			}
+			return note
+		}
+		return "Driver assigned"
+	case models.DeliveryPickedUp:
+		return "Driver picked up the order"
+	case models.DeliveryDelivered:
+		return "Order delivered"
+	case models.DeliveryCancelled, models.DeliveryFailed:
+		note := "Delivery " + string(booking.Status)
+		if update.Reason != "" {
+			note += ": " + update.Reason
+		}
+		return note
+	}
+	return "Delivery status: " + string(booking.Status)
+}
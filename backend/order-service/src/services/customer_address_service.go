@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// CustomerAddressService keeps the delivery addresses of guests who verified their phone
+// Addresses are saved with their geocoding result, so checking out to a saved address
+// skips the geocoding lookup.
+type CustomerAddressService struct {
+	addressRepo      *repository.CustomerAddressRepository
+	sessionRepo      *repository.CustomerSessionRepository
+	geocodingService *GeocodingService
+}
+
+// NewCustomerAddressService creates a new saved address service
+func NewCustomerAddressService(
+	addressRepo *repository.CustomerAddressRepository,
+	sessionRepo *repository.CustomerSessionRepository,
+	geocodingService *GeocodingService,
+) *CustomerAddressService {
+	return &CustomerAddressService{
+		addressRepo:      addressRepo,
+		sessionRepo:      sessionRepo,
+		geocodingService: geocodingService,
+	}
+}
+
+// List returns the saved addresses of the guest a customer session was opened for
+func (s *CustomerAddressService) List(ctx context.Context, tenantID, token string) ([]*models.CustomerAddress, error) {
+	phoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.addressRepo.List(ctx, tenantID, phoneHash)
+}
+
+// Get returns one saved address of the guest a customer session was opened for
+func (s *CustomerAddressService) Get(ctx context.Context, tenantID, token, id string) (*models.CustomerAddress, error) {
+	phoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, token)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCustomerAddressNotFound
+	}
+	return s.addressRepo.Get(ctx, tenantID, phoneHash, id)
+}
+
+// Save geocodes and saves an address the guest typed in
+// An address that cannot be located is still saved; checkout then prices it like a typed one.
+func (s *CustomerAddressService) Save(ctx context.Context, tenantID, token string, req *models.SaveCustomerAddressRequest) (*models.CustomerAddress, error) {
+	phoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.addressRepo.Exists(ctx, tenantID, phoneHash, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		count, err := s.addressRepo.Count(ctx, tenantID, phoneHash)
+		if err != nil {
+			return nil, err
+		}
+		if count >= models.MaxCustomerAddresses {
+			return nil, models.ErrCustomerAddressLimit
+		}
+	}
+
+	address := &models.CustomerAddress{
+		TenantID:    tenantID,
+		PhoneHash:   phoneHash,
+		FullAddress: req.Address,
+	}
+	if req.Label != "" {
+		address.Label = &req.Label
+	}
+
+	location, err := s.geocodingService.GeocodeAddress(ctx, req.Address)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to geocode saved address")
+	} else {
+		address.Latitude = &location.Latitude
+		address.Longitude = &location.Longitude
+		address.GeocodedAddress = &location.FormattedAddress
+	}
+
+	if err := s.addressRepo.Upsert(ctx, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Delete removes one of the guest's saved addresses
+func (s *CustomerAddressService) Delete(ctx context.Context, tenantID, token, id string) error {
+	phoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, token)
+	if err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return models.ErrCustomerAddressNotFound
+	}
+	return s.addressRepo.Delete(ctx, tenantID, phoneHash, id)
+}
+
+// Remember saves the address a verified guest just checked out to
+// The guest's least recently used addresses make room for it past the limit.
+func (s *CustomerAddressService) Remember(ctx context.Context, tenantID, token string, delivered *models.DeliveryAddress) error {
+	phoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, token)
+	if err != nil {
+		return err
+	}
+
+	address := &models.CustomerAddress{
+		TenantID:        tenantID,
+		PhoneHash:       phoneHash,
+		FullAddress:     delivered.FullAddress,
+		GeocodedAddress: delivered.GeocodingResult,
+	}
+	if delivered.HasCoordinates() {
+		address.Latitude = &delivered.Latitude
+		address.Longitude = &delivered.Longitude
+	}
+
+	if err := s.addressRepo.Upsert(ctx, address); err != nil {
+		return err
+	}
+	return s.addressRepo.TrimToLimit(ctx, tenantID, phoneHash, models.MaxCustomerAddresses)
+}
+
+// Location returns the geocoding result saved with an address, or nil if it was never located
+func (s *CustomerAddressService) Location(address *models.CustomerAddress) *GeocodingResult {
+	if !address.HasCoordinates() {
+		return nil
+	}
+	result := &GeocodingResult{
+		Latitude:  *address.Latitude,
+		Longitude: *address.Longitude,
+	}
+	if address.GeocodedAddress != nil {
+		result.FormattedAddress = *address.GeocodedAddress
+	}
+	return result
+}
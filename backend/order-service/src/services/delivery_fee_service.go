@@ -2,171 +2,179 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"math"
 
 	"github.com/rs/zerolog/log"
-) // DeliveryFeeConfig represents delivery fee configuration for a tenant
-type DeliveryFeeConfig struct {
-	Type           string         `json:"type"` // "distance" or "zone"
-	DistanceTiers  []DistanceTier `json:"distance_tiers,omitempty"`
-	ZoneFees       map[string]int `json:"zone_fees,omitempty"`
-	BaseFee        int            `json:"base_fee"`
-	FreeDeliveryKm *float64       `json:"free_delivery_km,omitempty"`
-}
 
-// DistanceTier represents a distance-based pricing tier
-type DistanceTier struct {
-	MaxDistanceKm float64 `json:"max_distance_km"`
-	FeeAmount     int     `json:"fee_amount"`
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// How a delivery quote was priced
+const (
+	DeliveryPricingZone     = "zone"
+	DeliveryPricingDistance = "distance"
+	DeliveryPricingFlat     = "flat"
+)
+
+// DeliveryQuote is the fee for delivering to an address and how it was priced
+type DeliveryQuote struct {
+	Method     string
+	Zone       *models.DeliveryZone // Set for zone pricing
+	Fee        int
+	DistanceKm *float64 // From the zone center or the tenant's origin
 }
 
 // DeliveryFeeService handles delivery fee calculation
 // Implements T077-T079: Delivery fee service with distance and zone-based pricing
 type DeliveryFeeService struct {
+	zoneRepo *repository.DeliveryZoneRepository
 }
 
 // NewDeliveryFeeService creates a new delivery fee service
-func NewDeliveryFeeService() *DeliveryFeeService {
-	return &DeliveryFeeService{}
-}
-
-// CalculateFee calculates the delivery fee based on distance or zone
-// Implements T077-T079: Fee calculation with distance tiers and zone-based pricing
-func (s *DeliveryFeeService) CalculateFee(ctx context.Context, distanceKm float64, zoneID *string, config *DeliveryFeeConfig) (int, error) {
-	if config == nil {
-		return 0, errors.New("delivery fee config is not configured")
-	}
-
-	// Check for free delivery
-	if config.FreeDeliveryKm != nil && distanceKm <= *config.FreeDeliveryKm {
-		log.Info().
-			Float64("distance_km", distanceKm).
-			Float64("free_delivery_threshold", *config.FreeDeliveryKm).
-			Msg("Free delivery applied")
-		return 0, nil
+func NewDeliveryFeeService(zoneRepo *repository.DeliveryZoneRepository) *DeliveryFeeService {
+	return &DeliveryFeeService{
+		zoneRepo: zoneRepo,
 	}
+}
 
-	// T078: Distance-based tier matching
-	if config.Type == "distance" {
-		fee, err := s.calculateDistanceBasedFee(distanceKm, config)
-		if err != nil {
-			return 0, err
-		}
+// ListZones returns a tenant's delivery zones in the order they are matched
+func (s *DeliveryFeeService) ListZones(ctx context.Context, tenantID string, activeOnly bool) ([]*models.DeliveryZone, error) {
+	return s.zoneRepo.List(ctx, tenantID, activeOnly)
+}
 
-		log.Info().
-			Float64("distance_km", distanceKm).
-			Int("calculated_fee", fee).
-			Str("method", "distance").
-			Msg("Delivery fee calculated")
+// GetZone returns one of a tenant's delivery zones
+func (s *DeliveryFeeService) GetZone(ctx context.Context, tenantID, zoneID string) (*models.DeliveryZone, error) {
+	return s.zoneRepo.GetByID(ctx, tenantID, zoneID)
+}
 
-		return fee, nil
+// CreateZone validates and creates a delivery zone
+func (s *DeliveryFeeService) CreateZone(ctx context.Context, tenantID string, req *models.DeliveryZoneRequest) (*models.DeliveryZone, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
+	return s.zoneRepo.Create(ctx, tenantID, req)
+}
 
-	// T079: Zone-based fee lookup
-	if config.Type == "zone" {
-		if zoneID == nil {
-			return 0, errors.New("zone_id is required for zone-based pricing")
-		}
-
-		fee, err := s.calculateZoneBasedFee(*zoneID, config)
-		if err != nil {
-			return 0, err
-		}
-
-		log.Info().
-			Str("zone_id", *zoneID).
-			Int("calculated_fee", fee).
-			Str("method", "zone").
-			Msg("Delivery fee calculated")
-
-		return fee, nil
+// UpdateZone validates and replaces a delivery zone's settings
+func (s *DeliveryFeeService) UpdateZone(ctx context.Context, tenantID, zoneID string, req *models.DeliveryZoneRequest) (*models.DeliveryZone, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
+	return s.zoneRepo.Update(ctx, tenantID, zoneID, req)
+}
 
-	return 0, fmt.Errorf("unsupported delivery fee type: %s", config.Type)
+// DeleteZone removes a delivery zone
+func (s *DeliveryFeeService) DeleteZone(ctx context.Context, tenantID, zoneID string) error {
+	return s.zoneRepo.Delete(ctx, tenantID, zoneID)
 }
 
-// calculateDistanceBasedFee calculates fee based on distance tiers
-// Implements T078: Distance-based tier matching
-func (s *DeliveryFeeService) calculateDistanceBasedFee(distanceKm float64, config *DeliveryFeeConfig) (int, error) {
-	if len(config.DistanceTiers) == 0 {
-		log.Warn().Msg("No distance tiers configured, using base fee")
-		return config.BaseFee, nil
+// QuoteDelivery prices delivery to a geocoded address
+// Active delivery zones take precedence, then the tenant's distance pricing from
+// tenant-service, then the flat default fee of the order settings. Only flat pricing
+// accepts a nil location; the others refuse addresses they cannot locate or that lie
+// outside the zones or beyond the settings' max delivery distance.
+func (s *DeliveryFeeService) QuoteDelivery(ctx context.Context, tenantID string, location *GeocodingResult, settings *models.OrderSettings) (*DeliveryQuote, error) {
+	zones, err := s.zoneRepo.List(ctx, tenantID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delivery zones: %w", err)
 	}
-
-	// Find the matching tier
-	for _, tier := range config.DistanceTiers {
-		if distanceKm <= tier.MaxDistanceKm {
-			log.Debug().
-				Float64("distance_km", distanceKm).
-				Float64("tier_max_distance", tier.MaxDistanceKm).
-				Int("tier_fee", tier.FeeAmount).
-				Msg("Matched distance tier")
-			return tier.FeeAmount, nil
-		}
+	if len(zones) > 0 {
+		return quoteByZone(tenantID, zones, location)
 	}
 
-	// If distance exceeds all tiers, use the highest tier fee
-	highestTier := config.DistanceTiers[len(config.DistanceTiers)-1]
-	log.Warn().
-		Float64("distance_km", distanceKm).
-		Float64("highest_tier_max", highestTier.MaxDistanceKm).
-		Int("fee", highestTier.FeeAmount).
-		Msg("Distance exceeds all tiers, using highest tier fee")
-
-	return highestTier.FeeAmount, nil
-}
-
-// calculateZoneBasedFee calculates fee based on delivery zone
-// Implements T079: Zone-based fee lookup
-func (s *DeliveryFeeService) calculateZoneBasedFee(zoneID string, config *DeliveryFeeConfig) (int, error) {
-	if len(config.ZoneFees) == 0 {
-		log.Warn().Msg("No zone fees configured, using base fee")
-		return config.BaseFee, nil
+	feeConfig, err := config.GetDeliveryFeeConfigForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant delivery fee config: %w", err)
+	}
+	if !feeConfig.DistancePricing() {
+		return &DeliveryQuote{Method: DeliveryPricingFlat, Fee: settings.DefaultDeliveryFee}, nil
+	}
+	if location == nil {
+		return nil, models.ErrDeliveryAddressNotLocated
 	}
 
-	fee, exists := config.ZoneFees[zoneID]
-	if !exists {
-		log.Warn().
-			Str("zone_id", zoneID).
-			Msg("Zone not found in fee configuration, using base fee")
-		return config.BaseFee, nil
+	distance := haversineDistanceKm(*feeConfig.OriginLatitude, *feeConfig.OriginLongitude, location.Latitude, location.Longitude)
+	if settings.MaxDeliveryDistance > 0 && distance > settings.MaxDeliveryDistance {
+		log.Info().
+			Str("tenant_id", tenantID).
+			Float64("distance_km", distance).
+			Float64("max_distance_km", settings.MaxDeliveryDistance).
+			Msg("Delivery address beyond max delivery distance")
+		return nil, models.ErrOutsideDeliveryZones
 	}
 
-	log.Debug().
-		Str("zone_id", zoneID).
-		Int("zone_fee", fee).
-		Msg("Matched zone fee")
+	fee := DistanceDeliveryFee(feeConfig, distance)
+	log.Info().
+		Str("tenant_id", tenantID).
+		Float64("distance_km", distance).
+		Int("calculated_fee", fee).
+		Str("method", DeliveryPricingDistance).
+		Msg("Delivery fee calculated")
 
-	return fee, nil
+	return &DeliveryQuote{Method: DeliveryPricingDistance, Fee: fee, DistanceKm: &distance}, nil
 }
 
-// ValidateConfig validates the delivery fee configuration
-func (s *DeliveryFeeService) ValidateConfig(config *DeliveryFeeConfig) error {
-	if config == nil {
-		return errors.New("config cannot be nil")
+// quoteByZone prices delivery by the first active zone containing the location
+func quoteByZone(tenantID string, zones []*models.DeliveryZone, location *GeocodingResult) (*DeliveryQuote, error) {
+	if location == nil {
+		return nil, models.ErrDeliveryAddressNotLocated
 	}
 
-	if config.Type != "distance" && config.Type != "zone" {
-		return fmt.Errorf("invalid type: must be 'distance' or 'zone', got '%s'", config.Type)
+	zone, distance := MatchDeliveryZone(zones, location.Latitude, location.Longitude)
+	if zone == nil {
+		log.Info().
+			Str("tenant_id", tenantID).
+			Float64("latitude", location.Latitude).
+			Float64("longitude", location.Longitude).
+			Msg("Delivery address outside all delivery zones")
+		return nil, models.ErrOutsideDeliveryZones
 	}
 
-	if config.Type == "distance" && len(config.DistanceTiers) == 0 {
-		return errors.New("distance_tiers cannot be empty for distance-based pricing")
-	}
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("zone_id", zone.ID).
+		Int("calculated_fee", zone.Fee).
+		Str("method", DeliveryPricingZone).
+		Msg("Delivery fee calculated")
 
-	if config.Type == "zone" && len(config.ZoneFees) == 0 {
-		return errors.New("zone_fees cannot be empty for zone-based pricing")
+	return &DeliveryQuote{Method: DeliveryPricingZone, Zone: zone, Fee: zone.Fee, DistanceKm: distance}, nil
+}
+
+// DistanceDeliveryFee is the base fee plus the per-km fee for every started km beyond
+// the included distance, capped at the max fee when one is set
+func DistanceDeliveryFee(feeConfig *config.TenantDeliveryFeeConfig, distanceKm float64) int {
+	fee := feeConfig.BaseFee
+	if extraKm := distanceKm - feeConfig.IncludedKm; extraKm > 0 {
+		fee += int(math.Ceil(extraKm)) * feeConfig.PerKmFee
+	}
+	if feeConfig.MaxFee > 0 && fee > feeConfig.MaxFee {
+		fee = feeConfig.MaxFee
 	}
+	return fee
+}
 
-	// Validate distance tiers are sorted
-	if config.Type == "distance" {
-		for i := 1; i < len(config.DistanceTiers); i++ {
-			if config.DistanceTiers[i].MaxDistanceKm <= config.DistanceTiers[i-1].MaxDistanceKm {
-				return fmt.Errorf("distance tiers must be sorted in ascending order")
+// MatchDeliveryZone returns the first zone containing the point, and its distance from the
+// center for radius zones. zones must be ordered by priority.
+func MatchDeliveryZone(zones []*models.DeliveryZone, latitude, longitude float64) (*models.DeliveryZone, *float64) {
+	for _, zone := range zones {
+		switch zone.Type {
+		case models.DeliveryZoneRadius:
+			if zone.CenterLatitude == nil || zone.CenterLongitude == nil || zone.MaxRadiusKm == nil {
+				continue
+			}
+			distance := haversineDistanceKm(latitude, longitude, *zone.CenterLatitude, *zone.CenterLongitude)
+			// Bands include their outer edge so adjacent bands leave no gap
+			if distance >= zone.MinRadiusKm && distance <= *zone.MaxRadiusKm {
+				return zone, &distance
+			}
+		case models.DeliveryZonePolygon:
+			if pointInPolygon(latitude, longitude, zone.Polygon) {
+				return zone, nil
 			}
 		}
 	}
-
-	return nil
+	return nil, nil
 }
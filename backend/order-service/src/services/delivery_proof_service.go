@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// DeliveryProofService records proofs of delivery and completes the delivered orders
+// storage is nil when no object storage is configured; proofs then cannot carry a photo.
+type DeliveryProofService struct {
+	proofRepo    *repository.DeliveryProofRepository
+	orderService *OrderService
+	storage      *AttachmentStorage
+}
+
+// NewDeliveryProofService creates a new proof of delivery service
+func NewDeliveryProofService(proofRepo *repository.DeliveryProofRepository, orderService *OrderService, storage *AttachmentStorage) *DeliveryProofService {
+	return &DeliveryProofService{
+		proofRepo:    proofRepo,
+		orderService: orderService,
+		storage:      storage,
+	}
+}
+
+// DeliveryProofActor is the driver or staff member recording a delivery
+type DeliveryProofActor struct {
+	UserID string
+	Name   string
+}
+
+// CompleteDelivery records who received a delivery order, with an optional photo, and completes the order
+// A PAID order is moved to COMPLETE; a COMPLETE order without a proof only gets the proof.
+// The photo is uploaded before the proof is saved; if saving fails it is removed again.
+func (s *DeliveryProofService) CompleteDelivery(ctx context.Context, tenantID, orderID, recipientName string, photo *models.NoteAttachmentUpload, actor DeliveryProofActor) (*models.DeliveryProof, error) {
+	order, err := s.orderService.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return nil, ErrOrderNotFound
+	}
+	if !order.CanTakeDeliveryProof() {
+		return nil, models.ErrOrderNotDeliverable
+	}
+
+	recipientName, err = models.NormalizeRecipientName(recipientName)
+	if err != nil {
+		return nil, err
+	}
+	if photo != nil {
+		if err := models.ValidateNoteAttachments([]models.NoteAttachmentUpload{*photo}); err != nil {
+			return nil, err
+		}
+		if s.storage == nil {
+			return nil, models.ErrNoteAttachmentsNotAvailable
+		}
+	}
+
+	proof := &models.DeliveryProof{
+		ID:               uuid.NewString(),
+		OrderID:          order.ID,
+		RecipientName:    recipientName,
+		CapturedByUserID: optionalString(actor.UserID),
+		CapturedByName:   optionalString(actor.Name),
+	}
+
+	if photo != nil {
+		storageKey := models.DeliveryProofStorageKey(order.TenantID, order.ID, proof.ID, photo.ContentType)
+		if err := s.storage.Upload(ctx, storageKey, photo.Content, photo.Size, photo.ContentType); err != nil {
+			return nil, err
+		}
+		proof.PhotoStorageKey = &storageKey
+	}
+
+	if err := s.proofRepo.Create(ctx, order.TenantID, proof, photo); err != nil {
+		s.removePhoto(ctx, proof.PhotoStorageKey)
+		if errors.Is(err, models.ErrDeliveryProofExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to save proof of delivery: %w", err)
+	}
+
+	// The proof stands even if the order cannot be completed now; staff can still complete it by hand
+	if order.Status == models.OrderStatusPaid {
+		if err := s.orderService.UpdateOrderStatus(ctx, order.ID, models.OrderStatusComplete); err != nil {
+			log.Error().Err(err).Str("order_id", order.ID).Msg("Failed to complete order after proof of delivery")
+		}
+	}
+
+	notedBy := actor.Name
+	if notedBy == "" {
+		notedBy = "Admin"
+	}
+	note := "Proof of delivery recorded"
+	if proof.PhotoStorageKey != nil {
+		note += " with a photo"
+	}
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, notedBy); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add proof of delivery note")
+	}
+
+	s.presign(ctx, proof)
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Bool("has_photo", proof.PhotoStorageKey != nil).
+		Msg("Proof of delivery recorded")
+	return proof, nil
+}
+
+// GetProof returns an order's proof of delivery with a presigned photo URL, or nil when it has none
+func (s *DeliveryProofService) GetProof(ctx context.Context, orderID string) (*models.DeliveryProof, error) {
+	proof, err := s.proofRepo.GetByOrderID(ctx, orderID)
+	if err != nil || proof == nil {
+		return nil, err
+	}
+	s.presign(ctx, proof)
+	return proof, nil
+}
+
+// presign fills in the photo URL; a photo whose URL cannot be signed is left without one
+func (s *DeliveryProofService) presign(ctx context.Context, proof *models.DeliveryProof) {
+	if s.storage == nil || proof.PhotoStorageKey == nil {
+		return
+	}
+	url, err := s.storage.PresignedURL(ctx, *proof.PhotoStorageKey)
+	if err != nil {
+		log.Warn().Err(err).Str("proof_id", proof.ID).Msg("Failed to presign proof of delivery photo")
+		return
+	}
+	proof.PhotoURL = url
+}
+
+// removePhoto deletes the photo of a proof that could not be saved
+func (s *DeliveryProofService) removePhoto(ctx context.Context, storageKey *string) {
+	if storageKey == nil {
+		return
+	}
+	if err := s.storage.Delete(ctx, *storageKey); err != nil {
+		log.Warn().Err(err).Str("storage_key", *storageKey).Msg("Failed to remove orphaned proof of delivery photo")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 
 	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/observability"
 	"github.com/point-of-sale-system/order-service/src/repository"
 	"github.com/segmentio/kafka-go"
 )
@@ -65,6 +66,10 @@ func (ep *EventPublisher) CreateEvent(ctx context.Context, tx *sql.Tx, req *mode
 		EventPayload: req.EventPayload,
 		Topic:        req.Topic,
 	}
+	// Persist the correlation ID so the worker can attach it when the event is published later
+	if correlationID := observability.CorrelationIDFromContext(ctx); correlationID != "" {
+		event.CorrelationID = &correlationID
+	}
 
 	return ep.outboxRepo.Create(ctx, tx, event)
 }
@@ -156,6 +161,12 @@ func (ep *EventPublisher) publishEventToKafka(ctx context.Context, event *models
 			{Key: "event-id", Value: []byte(event.ID)},
 		},
 	}
+	if event.CorrelationID != nil && *event.CorrelationID != "" {
+		message.Headers = append(message.Headers, kafka.Header{
+			Key:   observability.CorrelationIDKafkaHeader,
+			Value: []byte(*event.CorrelationID),
+		})
+	}
 
 	// Write message to Kafka
 	if err := writer.WriteMessages(ctx, message); err != nil {
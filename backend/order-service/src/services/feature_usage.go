@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// Feature usage counters share the API gateway's format: one Redis hash per UTC day
+// ("feature_usage:YYYY-MM-DD") with "<tenant_id>:<feature>" fields, rolled up by
+// analytics-service. Only tenant IDs and feature names are stored.
+const (
+	featureUsageKeyPrefix = "feature_usage:"
+	featureUsageKeyTTL    = 8 * 24 * time.Hour
+)
+
+// RecordFeatureUsage counts one use of each feature for a tenant today
+// It is meant to run in its own goroutine; failures are only logged.
+func RecordFeatureUsage(redisClient *redis.Client, tenantID string, features ...string) {
+	if redisClient == nil || tenantID == "" || len(features) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	key := featureUsageKeyPrefix + time.Now().UTC().Format("2006-01-02")
+	pipe := redisClient.Pipeline()
+	for _, feature := range features {
+		pipe.HIncrBy(ctx, key, tenantID+":"+feature, 1)
+	}
+	pipe.Expire(ctx, key, featureUsageKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to record feature usage")
+	}
+}
@@ -63,6 +63,10 @@ func (s *GeocodingService) GeocodeAddress(ctx context.Context, address string) (
 		return cachedResult, nil
 	}
 
+	if s.mapsClient == nil {
+		return nil, errors.New("geocoding is not configured")
+	}
+
 	// Call Google Maps Geocoding API
 	req := &maps.GeocodingRequest{
 		Address: address,
@@ -158,6 +162,11 @@ func (s *GeocodingService) ValidateServiceArea(ctx context.Context, latitude, lo
 // calculateHaversineDistance calculates the distance between two lat/lng points using Haversine formula
 // Implements T075: Haversine distance calculation
 func (s *GeocodingService) calculateHaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	return haversineDistanceKm(lat1, lon1, lat2, lon2)
+}
+
+// haversineDistanceKm is the great-circle distance between two lat/lng points in kilometers
+func haversineDistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
 	// Convert degrees to radians
 	lat1Rad := lat1 * math.Pi / 180
 	lat2Rad := lat2 * math.Pi / 180
@@ -178,6 +187,11 @@ func (s *GeocodingService) calculateHaversineDistance(lat1, lon1, lat2, lon2 flo
 // isPointInPolygon checks if a point is inside a polygon using ray-casting algorithm
 // Implements T076: Point-in-polygon validation
 func (s *GeocodingService) isPointInPolygon(lat, lng float64, polygonPoints []models.LatLng) bool {
+	return pointInPolygon(lat, lng, polygonPoints)
+}
+
+// pointInPolygon checks if a point is inside a polygon using ray-casting
+func pointInPolygon(lat, lng float64, polygonPoints []models.LatLng) bool {
 	if len(polygonPoints) < 3 {
 		return false
 	}
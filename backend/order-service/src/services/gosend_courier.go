@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// GoSendCourier books deliveries through the GoSend (Gojek Kilat) partner API
+// Webhooks are authenticated with the token configured for the partner account.
+type GoSendCourier struct {
+	creds  config.CourierCredentials
+	client *http.Client
+}
+
+// NewGoSendCourier creates the GoSend courier adapter
+func NewGoSendCourier(creds config.CourierCredentials) *GoSendCourier {
+	creds.APIURL = strings.TrimRight(creds.APIURL, "/")
+	return &GoSendCourier{
+		creds:  creds,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Provider identifies the courier
+func (g *GoSendCourier) Provider() models.CourierProvider {
+	return models.CourierGoSend
+}
+
+type goSendRoute struct {
+	OriginName         string `json:"originName"`
+	OriginNote         string `json:"originNote"`
+	OriginContactName  string `json:"originContactName"`
+	OriginContactPhone string `json:"originContactPhone"`
+	OriginLatLong      string `json:"originLatLong"`
+	OriginAddress      string `json:"originAddress"`
+	DestinationName    string `json:"destinationName"`
+	DestinationNote    string `json:"destinationNote"`
+	DestinationContact string `json:"destinationContactName"`
+	DestinationPhone   string `json:"destinationContactPhone"`
+	DestinationLatLong string `json:"destinationLatLong"`
+	DestinationAddress string `json:"destinationAddress"`
+	Item               string `json:"item"`
+	StoreOrderID       string `json:"storeOrderId"`
+}
+
+type goSendBooking struct {
+	PaymentType    int           `json:"paymentType"`
+	ShipmentMethod string        `json:"shipment_method"`
+	Routes         []goSendRoute `json:"routes"`
+}
+
+type goSendBookingResponse struct {
+	ID      int    `json:"id"`
+	OrderNo string `json:"orderNo"`
+}
+
+type goSendWebhook struct {
+	EntityID           string `json:"entity_id"`
+	BookingID          string `json:"booking_id"`
+	Status             string `json:"status"`
+	DriverName         string `json:"driver_name"`
+	DriverPhone        string `json:"driver_phone"`
+	VehicleNumber      string `json:"vehicle_number"`
+	LiveTrackingURL    string `json:"live_tracking_url"`
+	Price              int    `json:"price"`
+	CancellationReason string `json:"cancellation_reason"`
+}
+
+// goSendShipmentMethods maps our service types to GoSend shipment methods
+var goSendShipmentMethods = map[models.CourierServiceType]string{
+	models.CourierServiceInstant: "Instant",
+	models.CourierServiceSameDay: "SameDay",
+}
+
+// goSendStatuses maps GoSend booking statuses to delivery statuses
+// Statuses that are missing, such as on_hold, leave the delivery as it is.
+var goSendStatuses = map[string]models.DeliveryStatus{
+	"confirmed":        models.DeliveryFindingDriver,
+	"allocated":        models.DeliveryDriverAssigned,
+	"out_for_pickup":   models.DeliveryDriverAssigned,
+	"picked":           models.DeliveryPickedUp,
+	"out_for_delivery": models.DeliveryPickedUp,
+	"delivered":        models.DeliveryDelivered,
+	"cancelled":        models.DeliveryCancelled,
+	"rejected":         models.DeliveryFailed,
+	"no_driver":        models.DeliveryFailed,
+}
+
+func (g *GoSendCourier) headers() map[string]string {
+	return map[string]string{
+		"Client-ID": g.creds.ClientID,
+		"Pass-Key":  g.creds.ClientSecret,
+	}
+}
+
+// Book creates a GoSend booking from the store to the guest
+func (g *GoSendCourier) Book(ctx context.Context, req *CourierBookingRequest) (*CourierBookingResult, error) {
+	settings := req.Settings
+	method, ok := goSendShipmentMethods[settings.ServiceType]
+	if !ok {
+		method = goSendShipmentMethods[models.CourierServiceInstant]
+	}
+
+	origin := formatLatLong(*settings.PickupLatitude, *settings.PickupLongitude)
+	destination := formatLatLong(req.Destination.Latitude, req.Destination.Longitude)
+	destinationNote := ""
+	if req.Order.Notes != nil {
+		destinationNote = *req.Order.Notes
+	}
+
+	booking := goSendBooking{
+		PaymentType:    3, // Corporate billing
+		ShipmentMethod: method,
+		Routes: []goSendRoute{{
+			OriginName:         settings.PickupName,
+			OriginNote:         settings.PickupNotes,
+			OriginContactName:  settings.PickupName,
+			OriginContactPhone: settings.PickupPhone,
+			OriginLatLong:      origin,
+			OriginAddress:      settings.PickupAddress,
+			DestinationName:    req.Order.CustomerName,
+			DestinationNote:    destinationNote,
+			DestinationContact: req.Order.CustomerName,
+			DestinationPhone:   req.Order.CustomerPhone,
+			DestinationLatLong: destination,
+			DestinationAddress: req.Destination.FullAddress,
+			Item:               req.itemSummary(),
+			StoreOrderID:       req.Order.OrderReference,
+		}},
+	}
+
+	var resp goSendBookingResponse
+	if err := courierRequest(ctx, g.client, g.Provider(), http.MethodPost, g.creds.APIURL+"/gokilat/v10/booking", g.headers(), booking, &resp); err != nil {
+		return nil, err
+	}
+	if resp.OrderNo == "" {
+		return nil, fmt.Errorf("gosend booking response has no order number")
+	}
+
+	result := &CourierBookingResult{
+		ExternalID: resp.OrderNo,
+		Status:     models.DeliveryFindingDriver,
+	}
+	if fee, err := g.estimate(ctx, origin, destination, method); err == nil {
+		result.Fee = fee
+	}
+	return result, nil
+}
+
+// estimate asks GoSend what the booking costs; the fee is informational so failures are ignored
+func (g *GoSendCourier) estimate(ctx context.Context, origin, destination, method string) (int, error) {
+	var resp map[string]struct {
+		Serviceable bool `json:"serviceable"`
+		Price       struct {
+			TotalPrice int `json:"total_price"`
+		} `json:"price"`
+	}
+	url := fmt.Sprintf("%s/gokilat/v10/calculate/price?origin=%s&destination=%s&paymentType=3", g.creds.APIURL, origin, destination)
+	if err := courierRequest(ctx, g.client, g.Provider(), http.MethodGet, url, g.headers(), nil, &resp); err != nil {
+		return 0, err
+	}
+	quote, ok := resp[method]
+	if !ok || !quote.Serviceable {
+		return 0, fmt.Errorf("gosend %s is not serviceable", method)
+	}
+	return quote.Price.TotalPrice, nil
+}
+
+// Cancel cancels a GoSend booking
+func (g *GoSendCourier) Cancel(ctx context.Context, booking *models.CourierBooking) error {
+	if booking.ExternalID == nil {
+		return nil
+	}
+	body := map[string]string{"orderNo": *booking.ExternalID}
+	return courierRequest(ctx, g.client, g.Provider(), http.MethodPut, g.creds.APIURL+"/gokilat/v10/booking/cancel", g.headers(), body, nil)
+}
+
+// ParseWebhook verifies the GoSend webhook token and normalizes the status update
+func (g *GoSendCourier) ParseWebhook(headers http.Header, body []byte) (*models.CourierUpdate, error) {
+	if !verifyCourierWebhookToken(g.creds.WebhookToken, headers.Get("Authorization")) {
+		return nil, fmt.Errorf("%w: token mismatch", models.ErrInvalidCourierWebhook)
+	}
+
+	var payload goSendWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidCourierWebhook, err)
+	}
+
+	// entity_id is the order number returned by the booking call
+	externalID := payload.EntityID
+	if externalID == "" {
+		externalID = payload.BookingID
+	}
+	if externalID == "" {
+		return nil, fmt.Errorf("%w: missing booking reference", models.ErrInvalidCourierWebhook)
+	}
+
+	rawStatus := strings.ToLower(payload.Status)
+	return &models.CourierUpdate{
+		Provider:     g.Provider(),
+		ExternalID:   externalID,
+		RawStatus:    rawStatus,
+		Status:       goSendStatuses[rawStatus],
+		TrackingURL:  payload.LiveTrackingURL,
+		DriverName:   payload.DriverName,
+		DriverPhone:  payload.DriverPhone,
+		VehiclePlate: payload.VehicleNumber,
+		Fee:          payload.Price,
+		Reason:       payload.CancellationReason,
+	}, nil
+}
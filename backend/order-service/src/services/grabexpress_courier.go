@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// grabExpressTokenScope is the OAuth scope of the GrabExpress Delivery API
+const grabExpressTokenScope = "grab_express.partner_deliveries"
+
+// GrabExpressCourier books deliveries through the GrabExpress Delivery API
+// Access tokens come from the client-credentials grant and are reused until shortly before they expire.
+type GrabExpressCourier struct {
+	creds  config.CourierCredentials
+	client *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGrabExpressCourier creates the GrabExpress courier adapter
+func NewGrabExpressCourier(creds config.CourierCredentials) *GrabExpressCourier {
+	creds.APIURL = strings.TrimRight(creds.APIURL, "/")
+	return &GrabExpressCourier{
+		creds:  creds,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Provider identifies the courier
+func (g *GrabExpressCourier) Provider() models.CourierProvider {
+	return models.CourierGrabExpress
+}
+
+type grabExpressCoordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type grabExpressAddress struct {
+	Address     string                 `json:"address"`
+	Keywords    string                 `json:"keywords,omitempty"`
+	Coordinates grabExpressCoordinates `json:"coordinates"`
+}
+
+type grabExpressContact struct {
+	FirstName   string `json:"firstName"`
+	Phone       string `json:"phone"`
+	SMSEnabled  bool   `json:"smsEnabled"`
+	Instruction string `json:"instruction,omitempty"`
+}
+
+type grabExpressPackage struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	Price       int    `json:"price"`
+}
+
+type grabExpressDelivery struct {
+	MerchantOrderID string               `json:"merchantOrderID"`
+	ServiceType     string               `json:"serviceType"`
+	VehicleType     string               `json:"vehicleType"`
+	CodType         string               `json:"codType"`
+	PaymentMethod   string               `json:"paymentMethod"`
+	Packages        []grabExpressPackage `json:"packages"`
+	Origin          grabExpressAddress   `json:"origin"`
+	Destination     grabExpressAddress   `json:"destination"`
+	Sender          grabExpressContact   `json:"sender"`
+	Recipient       grabExpressContact   `json:"recipient"`
+}
+
+type grabExpressDeliveryResponse struct {
+	DeliveryID string `json:"deliveryID"`
+	Status     string `json:"status"`
+	TrackURL   string `json:"trackURL"`
+	Quote      struct {
+		Amount float64 `json:"amount"`
+	} `json:"quote"`
+}
+
+type grabExpressWebhook struct {
+	DeliveryID   string `json:"deliveryID"`
+	Status       string `json:"status"`
+	TrackURL     string `json:"trackURL"`
+	FailedReason string `json:"failedReason"`
+	Driver       struct {
+		Name         string `json:"name"`
+		Phone        string `json:"phone"`
+		LicensePlate string `json:"licensePlate"`
+	} `json:"driver"`
+}
+
+// grabExpressServiceTypes maps our service types to GrabExpress service types
+var grabExpressServiceTypes = map[models.CourierServiceType]string{
+	models.CourierServiceInstant: "INSTANT",
+	models.CourierServiceSameDay: "SAME_DAY",
+}
+
+// grabExpressStatuses maps GrabExpress delivery statuses to delivery statuses
+var grabExpressStatuses = map[string]models.DeliveryStatus{
+	"QUEUEING":         models.DeliveryFindingDriver,
+	"ALLOCATING":       models.DeliveryFindingDriver,
+	"PENDING_PICKUP":   models.DeliveryDriverAssigned,
+	"PICKING_UP":       models.DeliveryDriverAssigned,
+	"PENDING_DROP_OFF": models.DeliveryPickedUp,
+	"IN_DELIVERY":      models.DeliveryPickedUp,
+	"COMPLETED":        models.DeliveryDelivered,
+	"CANCELED":         models.DeliveryCancelled,
+	"FAILED":           models.DeliveryFailed,
+	"RETURNED":         models.DeliveryFailed,
+}
+
+// accessToken returns a cached OAuth token, requesting a new one when it is about to expire
+func (g *GrabExpressCourier) accessToken(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.token != "" && time.Now().Before(g.tokenExpiry) {
+		return g.token, nil
+	}
+
+	body := map[string]string{
+		"client_id":     g.creds.ClientID,
+		"client_secret": g.creds.ClientSecret,
+		"grant_type":    "client_credentials",
+		"scope":         grabExpressTokenScope,
+	}
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := courierRequest(ctx, g.client, g.Provider(), http.MethodPost, g.creds.APIURL+"/grabid/v1/oauth2/token", nil, body, &resp); err != nil {
+		return "", err
+	}
+	if resp.AccessToken == "" {
+		return "", fmt.Errorf("grabexpress token response has no access token")
+	}
+
+	g.token = resp.AccessToken
+	// Renew a minute early so a token never expires mid-request
+	g.tokenExpiry = time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - time.Minute)
+	return g.token, nil
+}
+
+func (g *GrabExpressCourier) authorizedRequest(ctx context.Context, method, path string, body, out interface{}) error {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	return courierRequest(ctx, g.client, g.Provider(), method, g.creds.APIURL+path, headers, body, out)
+}
+
+// Book creates a GrabExpress delivery from the store to the guest
+func (g *GrabExpressCourier) Book(ctx context.Context, req *CourierBookingRequest) (*CourierBookingResult, error) {
+	settings := req.Settings
+	serviceType, ok := grabExpressServiceTypes[settings.ServiceType]
+	if !ok {
+		serviceType = grabExpressServiceTypes[models.CourierServiceInstant]
+	}
+
+	packages := make([]grabExpressPackage, 0, len(req.Items))
+	for _, item := range req.Items {
+		packages = append(packages, grabExpressPackage{
+			Name:        item.ProductName,
+			Description: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.UnitPrice,
+		})
+	}
+	if len(packages) == 0 {
+		packages = append(packages, grabExpressPackage{Name: req.itemSummary(), Description: req.itemSummary(), Quantity: 1})
+	}
+
+	instruction := ""
+	if req.Order.Notes != nil {
+		instruction = *req.Order.Notes
+	}
+
+	delivery := grabExpressDelivery{
+		MerchantOrderID: req.Order.OrderReference,
+		ServiceType:     serviceType,
+		VehicleType:     "BIKE",
+		CodType:         "REGULAR",
+		PaymentMethod:   "CASHLESS",
+		Packages:        packages,
+		Origin: grabExpressAddress{
+			Address:     settings.PickupAddress,
+			Keywords:    settings.PickupName,
+			Coordinates: grabExpressCoordinates{Latitude: *settings.PickupLatitude, Longitude: *settings.PickupLongitude},
+		},
+		Destination: grabExpressAddress{
+			Address:     req.Destination.FullAddress,
+			Coordinates: grabExpressCoordinates{Latitude: req.Destination.Latitude, Longitude: req.Destination.Longitude},
+		},
+		Sender: grabExpressContact{
+			FirstName:   settings.PickupName,
+			Phone:       settings.PickupPhone,
+			Instruction: settings.PickupNotes,
+		},
+		Recipient: grabExpressContact{
+			FirstName:   req.Order.CustomerName,
+			Phone:       req.Order.CustomerPhone,
+			SMSEnabled:  true,
+			Instruction: instruction,
+		},
+	}
+
+	var resp grabExpressDeliveryResponse
+	if err := g.authorizedRequest(ctx, http.MethodPost, "/grab-express/v1/deliveries", delivery, &resp); err != nil {
+		return nil, err
+	}
+	if resp.DeliveryID == "" {
+		return nil, fmt.Errorf("grabexpress delivery response has no delivery ID")
+	}
+
+	status := grabExpressStatuses[resp.Status]
+	if status == "" || status.IsFinal() {
+		status = models.DeliveryFindingDriver
+	}
+	return &CourierBookingResult{
+		ExternalID:  resp.DeliveryID,
+		Status:      status,
+		TrackingURL: resp.TrackURL,
+		Fee:         int(resp.Quote.Amount),
+	}, nil
+}
+
+// Cancel cancels a GrabExpress delivery
+func (g *GrabExpressCourier) Cancel(ctx context.Context, booking *models.CourierBooking) error {
+	if booking.ExternalID == nil {
+		return nil
+	}
+	return g.authorizedRequest(ctx, http.MethodDelete, "/grab-express/v1/deliveries/"+url.PathEscape(*booking.ExternalID), nil, nil)
+}
+
+// ParseWebhook verifies the GrabExpress webhook token and normalizes the status update
+func (g *GrabExpressCourier) ParseWebhook(headers http.Header, body []byte) (*models.CourierUpdate, error) {
+	if !verifyCourierWebhookToken(g.creds.WebhookToken, headers.Get("Authorization")) {
+		return nil, fmt.Errorf("%w: token mismatch", models.ErrInvalidCourierWebhook)
+	}
+
+	var payload grabExpressWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidCourierWebhook, err)
+	}
+	if payload.DeliveryID == "" {
+		return nil, fmt.Errorf("%w: missing delivery ID", models.ErrInvalidCourierWebhook)
+	}
+
+	rawStatus := strings.ToUpper(payload.Status)
+	return &models.CourierUpdate{
+		Provider:     g.Provider(),
+		ExternalID:   payload.DeliveryID,
+		RawStatus:    rawStatus,
+		Status:       grabExpressStatuses[rawStatus],
+		TrackingURL:  payload.TrackURL,
+		DriverName:   payload.Driver.Name,
+		DriverPhone:  payload.Driver.Phone,
+		VehiclePlate: payload.Driver.LicensePlate,
+		Reason:       payload.FailedReason,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// GuestCancellationService lets guests cancel their own unpaid orders from the public order page
+// The pending gateway charge is expired first, so a guest can never pay an order that was
+// cancelled; then the stock holds are released and the order is moved to CANCELLED.
+type GuestCancellationService struct {
+	orderRepo        *repository.OrderRepository
+	paymentRepo      *repository.PaymentRepository
+	settingsRepo     *repository.OrderSettingsRepository
+	inventoryService *InventoryService
+	paymentService   *PaymentService
+	orderService     *OrderService
+}
+
+// NewGuestCancellationService creates a new guest cancellation service
+func NewGuestCancellationService(
+	orderRepo *repository.OrderRepository,
+	paymentRepo *repository.PaymentRepository,
+	settingsRepo *repository.OrderSettingsRepository,
+	inventoryService *InventoryService,
+	paymentService *PaymentService,
+	orderService *OrderService,
+) *GuestCancellationService {
+	return &GuestCancellationService{
+		orderRepo:        orderRepo,
+		paymentRepo:      paymentRepo,
+		settingsRepo:     settingsRepo,
+		inventoryService: inventoryService,
+		paymentService:   paymentService,
+		orderService:     orderService,
+	}
+}
+
+// CancelOrder cancels a PENDING order within the tenant's guest cancellation window
+// Orders with any payment already collected, in store or through the gateway, are refused.
+func (s *GuestCancellationService) CancelOrder(ctx context.Context, orderReference string) (*models.GuestOrder, error) {
+	order, err := s.orderRepo.GetOrderByReference(ctx, orderReference)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && order == nil) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	settings, err := s.settingsRepo.GetOrCreate(ctx, order.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order settings: %w", err)
+	}
+	if err := settings.CheckGuestCancel(order, time.Now()); err != nil {
+		return nil, err
+	}
+
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, nil, order.ID, order.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment balance: %w", err)
+	}
+	if balance.AmountPaid > 0 || balance.AmountPending > 0 {
+		return nil, models.ErrGuestCancelPaymentStarted
+	}
+
+	if err := s.expirePendingCharge(ctx, order); err != nil {
+		return nil, err
+	}
+
+	// A failed release is left to the reservation cleanup job, as for expired payments
+	if err := s.inventoryService.ReleaseReservations(ctx, order.ID); err != nil {
+		log.Error().
+			Err(err).
+			Str("order_id", order.ID).
+			Msg("Failed to release inventory reservations for guest cancellation")
+	}
+
+	if err := s.orderService.UpdateOrderStatus(ctx, order.ID, models.OrderStatusCancelled); err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	if err := s.orderService.AddOrderNote(ctx, order.ID, "Order cancelled by the guest before payment.", "Guest"); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add guest cancellation note")
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("tenant_id", order.TenantID).
+		Msg("Order cancelled by guest")
+
+	order.Status = models.OrderStatusCancelled
+	return order, nil
+}
+
+// expirePendingCharge voids the order's online charge if it can still be paid
+func (s *GuestCancellationService) expirePendingCharge(ctx context.Context, order *models.GuestOrder) error {
+	payment, err := s.paymentRepo.GetPaymentByOrderID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment == nil {
+		return nil
+	}
+	if payment.SettledAt != nil {
+		return models.ErrGuestCancelPaymentStarted
+	}
+	// Charges the gateway already ended need no call
+	if payment.TransactionStatus != nil && *payment.TransactionStatus != "pending" {
+		return nil
+	}
+
+	gateway, err := s.paymentService.gatewayByProvider(payment.Gateway)
+	if err != nil {
+		return err
+	}
+	if err := gateway.Cancel(ctx, order.TenantID, payment); err != nil {
+		if errors.Is(err, models.ErrPaymentNotCancellable) {
+			return models.ErrGuestCancelPaymentStarted
+		}
+		return fmt.Errorf("failed to expire payment: %w", err)
+	}
+	return nil
+}
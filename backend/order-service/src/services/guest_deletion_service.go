@@ -17,18 +17,23 @@ import (
 type GuestDeletionService struct {
 	orderRepo      *repository.OrderRepository
 	addressRepo    *repository.AddressRepository
+	proofRepo      *repository.DeliveryProofRepository
+	storage        *AttachmentStorage
 	db             *sql.DB
 	encryptor      utils.Encryptor
 	auditPublisher *utils.AuditPublisher
 }
 
 // NewGuestDeletionService creates a new guest deletion service
-func NewGuestDeletionService(db *sql.DB, encryptor utils.Encryptor, auditPublisher *utils.AuditPublisher) *GuestDeletionService {
+// storage may be nil when no object storage is configured.
+func NewGuestDeletionService(db *sql.DB, encryptor utils.Encryptor, auditPublisher *utils.AuditPublisher, storage *AttachmentStorage) *GuestDeletionService {
 	orderRepo := repository.NewOrderRepository(db, encryptor)
 	addressRepo := repository.NewAddressRepository(db, encryptor)
 	return &GuestDeletionService{
 		orderRepo:      orderRepo,
 		addressRepo:    addressRepo,
+		proofRepo:      repository.NewDeliveryProofRepository(db, encryptor),
+		storage:        storage,
 		db:             db,
 		encryptor:      encryptor,
 		auditPublisher: auditPublisher,
@@ -73,12 +78,24 @@ func (s *GuestDeletionService) AnonymizeGuestData(ctx context.Context, orderRefe
 
 	now := time.Now()
 
+	// Saved delivery addresses belong to the guest's phone, so they go with it
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM customer_addresses
+		WHERE tenant_id = $1
+		  AND phone_hash = (SELECT customer_phone_hash FROM guest_orders WHERE order_reference = $2)
+	`, order.TenantID, orderReference)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved addresses: %w", err)
+	}
+
 	// T141: Anonymize order PII - replace with generic values
 	anonymizeOrderQuery := `
 		UPDATE guest_orders
 		SET customer_name = $1,
 		    customer_phone = $2,
 		    customer_email = NULL,
+		    customer_phone_hash = NULL,
+		    customer_email_hash = NULL,
 		    ip_address = NULL,
 		    is_anonymized = TRUE,
 		    anonymized_at = $3
@@ -114,11 +131,24 @@ func (s *GuestDeletionService) AnonymizeGuestData(ctx context.Context, orderRefe
 		}
 	}
 
+	// The proof of delivery names the recipient and may show them in its photo
+	proofPhotoKey, err := s.proofRepo.DeleteByOrderID(ctx, tx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete proof of delivery: %w", err)
+	}
+
 	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit anonymization transaction: %w", err)
 	}
 
+	if proofPhotoKey != nil && s.storage != nil {
+		if err := s.storage.Delete(ctx, *proofPhotoKey); err != nil {
+			// The proof row is already gone; the orphaned photo is only reachable by its key
+			fmt.Printf("Failed to delete proof of delivery photo %s: %v\n", *proofPhotoKey, err)
+		}
+	}
+
 	// T143: Publish GuestDataAnonymizedEvent to audit topic
 	auditEvent := &utils.AuditEvent{
 		EventID:      uuid.New(),
@@ -140,6 +170,7 @@ func (s *GuestDeletionService) AnonymizeGuestData(ctx context.Context, orderRefe
 				"customer_email",
 				"ip_address",
 				"delivery_address",
+				"delivery_proof",
 			},
 		},
 	}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,11 +15,20 @@ import (
 )
 
 const (
-	ReservationTTL       = 15 * time.Minute
-	InventoryCachePrefix = "inventory:"
-	InventoryCacheTTL    = 5 * time.Minute
+	// ReservationTTL holds checkout stock when the tenant has not set a payment expiry
+	ReservationTTL = models.DefaultPaymentExpiryMinutes * time.Minute
+	// BankTransferReservationTTL holds stock for checkouts paid by virtual account.
+	// The VA is created with the same expiry, so stock is released when it can no longer be paid.
+	BankTransferReservationTTL = 1 * time.Hour
+	// ManualOrderReservationTTL holds stock for cashier-recorded orders that are awaiting payment
+	ManualOrderReservationTTL = 24 * time.Hour
 )
 
+// InventoryService is the shared reservation component for every order channel
+// Guest checkout, cashier orders and synced terminal orders all hold, allocate and
+// release stock through it, so available-to-promise stock is computed in one place.
+// Availability is not cached: it is read from Postgres (stock on hand minus active
+// reservations) on every check, so there is no second copy that could drift.
 type InventoryService struct {
 	db              *sql.DB
 	redisClient     *redis.Client
@@ -33,52 +43,109 @@ func NewInventoryService(db *sql.DB, redisClient *redis.Client) *InventoryServic
 	}
 }
 
-// CheckAvailabilityWithLock checks if products are available and locks them for reservation
-// Uses SELECT FOR UPDATE to prevent race conditions
-func (s *InventoryService) CheckAvailabilityWithLock(ctx context.Context, tx *sql.Tx, tenantID string, items []models.CartItem) error {
+// cartReservationLines converts cart items to reservation lines
+func cartReservationLines(items []models.CartItem) []models.ReservationLine {
+	lines := make([]models.ReservationLine, 0, len(items))
 	for _, item := range items {
-		// Get product quantity with row-level lock
-		var currentQuantity int
-		query := `
-SELECT stock_quantity
-FROM products
-WHERE tenant_id = $1 AND id = $2
-FOR UPDATE
-`
-
-		err := tx.QueryRowContext(ctx, query, tenantID, item.ProductID).Scan(&currentQuantity)
+		lines = append(lines, models.ReservationLine{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+		})
+	}
+	return lines
+}
+
+// AvailableToPromise returns the stock of a product that can still be sold
+func (s *InventoryService) AvailableToPromise(ctx context.Context, tenantID, productID string) (int, error) {
+	available, err := s.reservationRepo.GetAvailableToPromise(ctx, nil, tenantID, productID, false)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: %s", models.ErrProductUnavailable, productID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get available stock: %w", err)
+	}
+
+	if available < 0 {
+		available = 0
+	}
+	return available, nil
+}
+
+// EnsureAvailable checks that every line fits in available-to-promise stock
+// Product rows stay locked until tx ends, so a reservation or allocation made in the
+// same transaction cannot be oversold by a concurrent order
+func (s *InventoryService) EnsureAvailable(ctx context.Context, tx *sql.Tx, tenantID string, lines []models.ReservationLine) error {
+	for _, line := range models.MergeReservationLines(lines) {
+		available, err := s.reservationRepo.GetAvailableToPromise(ctx, tx, tenantID, line.ProductID, true)
 		if err == sql.ErrNoRows {
-			return fmt.Errorf("product %s not found", item.ProductID)
+			return fmt.Errorf("%w: %s", models.ErrProductUnavailable, line.ProductID)
 		}
 		if err != nil {
-			return fmt.Errorf("failed to check product %s: %w", item.ProductID, err)
+			return fmt.Errorf("failed to check product %s: %w", line.ProductID, err)
 		}
 
-		// Calculate available inventory (current - active reservations)
-		reserved, err := s.reservationRepo.GetTotalReservedQuantity(ctx, item.ProductID)
-		if err != nil {
-			return fmt.Errorf("failed to get reservations for product %s: %w", item.ProductID, err)
-		}
-
-		available := currentQuantity - reserved
-		if available < item.Quantity {
-			return fmt.Errorf("insufficient stock for product %s (available: %d, requested: %d)",
-				item.ProductName, available, item.Quantity)
+		if available < line.Quantity {
+			return fmt.Errorf("%w for product %s (available: %d, requested: %d)",
+				models.ErrInsufficientStock, line.ProductName, available, line.Quantity)
 		}
 	}
 
 	return nil
 }
 
-// CreateReservations creates inventory reservations for cart items
-func (s *InventoryService) CreateReservations(ctx context.Context, tx *sql.Tx, orderID string, items []models.CartItem) error {
-	expiresAt := time.Now().Add(ReservationTTL)
+// CheckAvailabilityWithLock checks if products are available and locks them for reservation
+// Uses SELECT FOR UPDATE to prevent race conditions
+func (s *InventoryService) CheckAvailabilityWithLock(ctx context.Context, tx *sql.Tx, tenantID string, items []models.CartItem) error {
+	return s.EnsureAvailable(ctx, tx, tenantID, cartReservationLines(items))
+}
 
-	for _, item := range items {
+// ReservationTTLFor returns how long checkout stock is held for a payment method
+// paymentExpiry is the tenant's expiry for QRIS and e-wallet charges. Gateways expire
+// the payment after the same time, so an order cannot be paid once its stock has
+// been released.
+func ReservationTTLFor(method models.CheckoutPaymentMethod, paymentExpiry time.Duration) time.Duration {
+	if method == models.CheckoutPaymentBankTransfer {
+		return BankTransferReservationTTL
+	}
+	if paymentExpiry <= 0 {
+		return ReservationTTL
+	}
+	return paymentExpiry
+}
+
+// CheckoutReservationTTL returns how long a tenant holds checkout stock for a payment method
+// The tenant's reservation TTL can hold stock longer than the charge stays payable, never shorter.
+func CheckoutReservationTTL(method models.CheckoutPaymentMethod, settings *models.OrderSettings) time.Duration {
+	ttl := ReservationTTLFor(method, settings.PaymentExpiry())
+	if hold := settings.ReservationTTL(); hold > ttl {
+		return hold
+	}
+	return ttl
+}
+
+// CreateReservations creates inventory reservations for cart items held for ttl
+func (s *InventoryService) CreateReservations(ctx context.Context, tx *sql.Tx, orderID string, items []models.CartItem, ttl time.Duration) error {
+	return s.createReservations(ctx, tx, orderID, models.MergeReservationLines(cartReservationLines(items)), ttl)
+}
+
+// Reserve checks availability and holds stock for an unpaid order until the TTL passes
+func (s *InventoryService) Reserve(ctx context.Context, tx *sql.Tx, tenantID, orderID string, lines []models.ReservationLine, ttl time.Duration) error {
+	lines = models.MergeReservationLines(lines)
+	if err := s.EnsureAvailable(ctx, tx, tenantID, lines); err != nil {
+		return err
+	}
+	return s.createReservations(ctx, tx, orderID, lines, ttl)
+}
+
+func (s *InventoryService) createReservations(ctx context.Context, tx *sql.Tx, orderID string, lines []models.ReservationLine, ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl)
+
+	for _, line := range lines {
 		reservation := &models.InventoryReservation{
 			OrderID:   orderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
 			Status:    models.ReservationStatusActive,
 			ExpiresAt: expiresAt,
 		}
@@ -87,16 +154,16 @@ func (s *InventoryService) CreateReservations(ctx context.Context, tx *sql.Tx, o
 		if err != nil {
 			log.Error().Err(err).
 				Str("order_id", orderID).
-				Str("product_id", item.ProductID).
+				Str("product_id", line.ProductID).
 				Msg("Failed to create reservation")
-			return fmt.Errorf("failed to create reservation for product %s: %w", item.ProductID, err)
+			return fmt.Errorf("failed to create reservation for product %s: %w", line.ProductID, err)
 		}
 
 		log.Info().
 			Str("reservation_id", reservation.ID).
 			Str("order_id", orderID).
-			Str("product_id", item.ProductID).
-			Int("quantity", item.Quantity).
+			Str("product_id", line.ProductID).
+			Int("quantity", line.Quantity).
 			Time("expires_at", expiresAt).
 			Msg("Reservation created")
 	}
@@ -104,54 +171,126 @@ func (s *InventoryService) CreateReservations(ctx context.Context, tx *sql.Tx, o
 	return nil
 }
 
-// ConvertReservationsToPermanent converts reservations to permanent inventory allocation after payment
-func (s *InventoryService) ConvertReservationsToPermanent(ctx context.Context, orderID string) error {
-	// Get all reservations for the order
-	reservations, err := s.reservationRepo.GetReservationsByOrderID(ctx, orderID)
+// ExtendReservations holds an order's reserved stock for ttl from now
+func (s *InventoryService) ExtendReservations(ctx context.Context, tx *sql.Tx, orderID string, ttl time.Duration) error {
+	if err := s.reservationRepo.ExtendReservations(ctx, tx, orderID, time.Now().Add(ttl)); err != nil {
+		return fmt.Errorf("failed to extend reservations: %w", err)
+	}
+	return nil
+}
+
+// Allocate checks availability and permanently deducts stock for a sale completed at the counter
+func (s *InventoryService) Allocate(ctx context.Context, tx *sql.Tx, tenantID, orderID string, lines []models.ReservationLine) error {
+	if err := s.EnsureAvailable(ctx, tx, tenantID, lines); err != nil {
+		return err
+	}
+
+	shortfalls, err := s.FulfilOrder(ctx, tx, tenantID, orderID, lines)
 	if err != nil {
-		return fmt.Errorf("failed to get reservations for order %s: %w", orderID, err)
+		return err
 	}
+	if len(shortfalls) > 0 {
+		// Stock is locked by EnsureAvailable, so this only happens if stock went negative elsewhere
+		return fmt.Errorf("%w for product %s", models.ErrInsufficientStock, shortfalls[0].ProductName)
+	}
+	return nil
+}
 
-	// Begin transaction for atomic conversion
-	tx, err := s.db.BeginTx(ctx, nil)
+// FulfilOrder settles the stock of a paid order
+// Active reservations are converted and any quantity they do not cover (a lapsed hold, or an
+// order that never reserved) is allocated directly. Quantities already converted are skipped,
+// so calling it again for the same order never deducts twice. The sale has already happened,
+// so it never rejects for lack of stock; units sold beyond stock on hand are returned as shortfalls
+func (s *InventoryService) FulfilOrder(ctx context.Context, tx *sql.Tx, tenantID, orderID string, lines []models.ReservationLine) ([]models.StockShortfall, error) {
+	reservations, err := s.reservationRepo.GetOrderReservationsForUpdate(ctx, tx, orderID)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to get reservations for order %s: %w", orderID, err)
+	}
+
+	var shortfalls []models.StockShortfall
+	deduct := func(productID, productName string, quantity int) error {
+		shortfall, err := s.reservationRepo.DeductStock(ctx, tx, tenantID, productID, quantity)
+		if err != nil {
+			return err
+		}
+		if shortfall > 0 {
+			shortfalls = append(shortfalls, models.StockShortfall{
+				ProductID:   productID,
+				ProductName: productName,
+				Requested:   quantity,
+				Shortfall:   shortfall,
+			})
+		}
+		return nil
+	}
+
+	names := make(map[string]string, len(lines))
+	for _, line := range lines {
+		names[line.ProductID] = line.ProductName
+	}
+
+	for _, reservation := range reservations {
+		if reservation.Status != models.ReservationStatusActive {
+			continue
+		}
+		if err := s.reservationRepo.ConvertReservation(ctx, tx, reservation.ID); err != nil {
+			return nil, fmt.Errorf("failed to convert reservation %s: %w", reservation.ID, err)
+		}
+		if err := deduct(reservation.ProductID, names[reservation.ProductID], reservation.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for _, line := range models.UncoveredReservationLines(lines, reservations) {
+		if err := deduct(line.ProductID, line.ProductName, line.Quantity); err != nil {
+			return nil, err
+		}
+
+		allocation := &models.InventoryReservation{
+			OrderID:   orderID,
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Status:    models.ReservationStatusConverted,
+			ExpiresAt: now,
+		}
+		if err := s.reservationRepo.CreateReservation(ctx, tx, allocation); err != nil {
+			return nil, fmt.Errorf("failed to record allocation for product %s: %w", line.ProductID, err)
+		}
+	}
+
+	log.Info().
+		Str("order_id", orderID).
+		Str("tenant_id", tenantID).
+		Int("shortfalls", len(shortfalls)).
+		Msg("Order stock fulfilled")
+
+	return shortfalls, nil
+}
+
+// ConvertReservations converts an order's active reservations within tx
+// Unlike FulfilOrder it fails when stock no longer covers a reservation
+func (s *InventoryService) ConvertReservations(ctx context.Context, tx *sql.Tx, tenantID, orderID string) error {
+	reservations, err := s.reservationRepo.GetOrderReservationsForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return fmt.Errorf("failed to get reservations for order %s: %w", orderID, err)
 	}
-	defer tx.Rollback()
 
 	for _, reservation := range reservations {
 		if reservation.Status != models.ReservationStatusActive {
-			log.Warn().
-				Str("reservation_id", reservation.ID).
-				Str("status", string(reservation.Status)).
-				Msg("Skipping non-active reservation")
 			continue
 		}
 
-		// Convert reservation status
 		if err := s.reservationRepo.ConvertReservation(ctx, tx, reservation.ID); err != nil {
 			return fmt.Errorf("failed to convert reservation %s: %w", reservation.ID, err)
 		}
 
-		// Decrement product quantity permanently
-		query := `
-UPDATE products
-SET stock_quantity = stock_quantity - $1,
-    updated_at = NOW()
-WHERE id = $2 AND stock_quantity >= $1
-`
-		result, err := tx.ExecContext(ctx, query, reservation.Quantity, reservation.ProductID)
+		shortfall, err := s.reservationRepo.DeductStock(ctx, tx, tenantID, reservation.ProductID, reservation.Quantity)
 		if err != nil {
 			return fmt.Errorf("failed to decrement product %s quantity: %w", reservation.ProductID, err)
 		}
-
-		rows, err := result.RowsAffected()
-		if err != nil {
-			return fmt.Errorf("failed to check affected rows: %w", err)
-		}
-
-		if rows == 0 {
-			return fmt.Errorf("insufficient stock for product %s during conversion", reservation.ProductID)
+		if shortfall > 0 {
+			return fmt.Errorf("%w for product %s during conversion", models.ErrInsufficientStock, reservation.ProductID)
 		}
 
 		log.Info().
@@ -162,6 +301,21 @@ WHERE id = $2 AND stock_quantity >= $1
 			Msg("Reservation converted to permanent allocation")
 	}
 
+	return nil
+}
+
+// ConvertReservationsToPermanent converts reservations to permanent inventory allocation after payment
+func (s *InventoryService) ConvertReservationsToPermanent(ctx context.Context, tenantID, orderID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.ConvertReservations(ctx, tx, tenantID, orderID); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit conversion transaction: %w", err)
 	}
@@ -169,6 +323,33 @@ WHERE id = $2 AND stock_quantity >= $1
 	return nil
 }
 
+// ReleaseOrderStock gives back everything an order holds within tx
+// Active reservations are released and converted allocations are returned to stock,
+// so an unpaid order's items can be replaced or the order removed. It reports whether
+// any stock had been allocated rather than only reserved
+func (s *InventoryService) ReleaseOrderStock(ctx context.Context, tx *sql.Tx, orderID string) (bool, error) {
+	reservations, err := s.reservationRepo.GetOrderReservationsForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get reservations for order %s: %w", orderID, err)
+	}
+
+	allocated := false
+	for _, reservation := range reservations {
+		if reservation.Status == models.ReservationStatusConverted {
+			allocated = true
+			if err := s.reservationRepo.RestockProduct(ctx, tx, reservation.ProductID, reservation.Quantity); err != nil {
+				return false, fmt.Errorf("failed to restock product %s: %w", reservation.ProductID, err)
+			}
+		}
+
+		if err := s.reservationRepo.ReleaseReservation(ctx, tx, reservation.ID); err != nil {
+			return false, fmt.Errorf("failed to release reservation %s: %w", reservation.ID, err)
+		}
+	}
+
+	return allocated, nil
+}
+
 // ReleaseReservations releases reservations (for expired or cancelled orders)
 func (s *InventoryService) ReleaseReservations(ctx context.Context, orderID string) error {
 	reservations, err := s.reservationRepo.GetReservationsByOrderID(ctx, orderID)
@@ -198,26 +379,7 @@ func (s *InventoryService) ReleaseReservations(ctx context.Context, orderID stri
 	return nil
 }
 
-// GetAvailableInventory calculates available inventory for a product
-func (s *InventoryService) GetAvailableInventory(ctx context.Context, tenantID, productID string) (int, error) {
-	// Get current quantity from database
-	var currentQuantity int
-	query := `SELECT quantity FROM products WHERE tenant_id = $1 AND id = $2`
-	err := s.db.QueryRowContext(ctx, query, tenantID, productID).Scan(&currentQuantity)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get product quantity: %w", err)
-	}
-
-	// Get total active reservations
-	reserved, err := s.reservationRepo.GetTotalReservedQuantity(ctx, productID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get reserved quantity: %w", err)
-	}
-
-	available := currentQuantity - reserved
-	if available < 0 {
-		available = 0
-	}
-
-	return available, nil
+// IsStockError reports whether err is a stock rejection rather than an internal failure
+func IsStockError(err error) bool {
+	return errors.Is(err, models.ErrInsufficientStock) || errors.Is(err, models.ErrProductUnavailable)
 }
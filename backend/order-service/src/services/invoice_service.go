@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/invoice"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+const (
+	// maxInvoiceLogoBytes bounds the tenant logo downloaded for an invoice
+	maxInvoiceLogoBytes = 1 << 20
+	// invoiceLogoTimeout bounds the logo download so a slow host cannot stall the invoice
+	invoiceLogoTimeout = 5 * time.Second
+)
+
+// InvoiceService renders branded PDF invoices
+// storage is nil when no object storage is configured; uploaded logos are then left off invoices.
+type InvoiceService struct {
+	orderRepo    *repository.OrderRepository
+	brandingRepo *repository.InvoiceBrandingRepository
+	settingsRepo *repository.OrderSettingsRepository
+	storage      *AttachmentStorage
+	httpClient   *http.Client
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(orderRepo *repository.OrderRepository, brandingRepo *repository.InvoiceBrandingRepository, settingsRepo *repository.OrderSettingsRepository, storage *AttachmentStorage) *InvoiceService {
+	return &InvoiceService{
+		orderRepo:    orderRepo,
+		brandingRepo: brandingRepo,
+		settingsRepo: settingsRepo,
+		storage:      storage,
+		httpClient:   &http.Client{Timeout: invoiceLogoTimeout},
+	}
+}
+
+// RenderForTenant renders the invoice of one of a tenant's orders
+func (s *InvoiceService) RenderForTenant(ctx context.Context, tenantID, orderID string) (*models.GuestOrder, []byte, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && order.TenantID != tenantID) {
+		return nil, nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	pdf, err := s.render(ctx, order)
+	return order, pdf, err
+}
+
+// RenderForReference renders the invoice of the order a guest holds the reference of
+func (s *InvoiceService) RenderForReference(ctx context.Context, orderReference string) (*models.GuestOrder, []byte, error) {
+	order, err := s.orderRepo.GetOrderByReference(ctx, orderReference)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && order == nil) {
+		return nil, nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	pdf, err := s.render(ctx, order)
+	return order, pdf, err
+}
+
+func (s *InvoiceService) render(ctx context.Context, order *models.GuestOrder) ([]byte, error) {
+	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	branding, err := s.brandingRepo.GetByTenantID(ctx, order.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice branding: %w", err)
+	}
+
+	settings, err := s.settingsRepo.GetOrCreate(ctx, order.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order settings: %w", err)
+	}
+
+	var logo []byte
+	switch {
+	case branding.LogoStorageKey != "" && s.storage != nil:
+		logo, err = s.storage.Download(ctx, branding.LogoStorageKey, maxInvoiceLogoBytes)
+	case branding.LogoURL != "":
+		logo, err = s.fetchLogo(ctx, branding.LogoURL)
+	}
+	if err != nil {
+		// A broken logo should not keep guests from their invoice
+		log.Warn().Err(err).Str("tenant_id", order.TenantID).Msg("Rendering invoice without logo")
+		logo = nil
+	}
+
+	return invoice.Render(branding, logo, order, items, settings.Location())
+}
+
+// fetchLogo downloads a tenant logo over HTTPS; the renderer skips anything that is not a PNG or JPEG
+func (s *InvoiceService) fetchLogo(ctx context.Context, logoURL string) ([]byte, error) {
+	parsed, err := url.Parse(logoURL)
+	if err != nil || parsed.Scheme != "https" {
+		return nil, fmt.Errorf("logo URL must use https")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("logo download returned %d", resp.StatusCode)
+	}
+
+	logo, err := io.ReadAll(io.LimitReader(resp.Body, maxInvoiceLogoBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(logo) > maxInvoiceLogoBytes {
+		return nil, fmt.Errorf("logo is larger than %d bytes", maxInvoiceLogoBytes)
+	}
+	return logo, nil
+}
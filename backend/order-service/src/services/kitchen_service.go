@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// KitchenService runs the kitchen display: the paid order stream, item preparation
+// status, bump/recall and prep-time metrics
+type KitchenService struct {
+	kitchenRepo  *repository.KitchenRepository
+	settingsRepo *repository.OrderSettingsRepository
+}
+
+// NewKitchenService creates a new kitchen service
+func NewKitchenService(kitchenRepo *repository.KitchenRepository, settingsRepo *repository.OrderSettingsRepository) *KitchenService {
+	return &KitchenService{
+		kitchenRepo:  kitchenRepo,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// prepMinutes returns the tenant's estimated prep time, or 0 when settings cannot be read
+func (s *KitchenService) prepMinutes(ctx context.Context, tenantID string) int {
+	settings, err := s.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to fetch order settings for kitchen due times")
+		return 0
+	}
+	return settings.EstimatedPrepTime
+}
+
+// GetFeed returns the kitchen orders, or those changed since a previous feed's cursor
+// Orders are sorted by due time so the next order to hand out comes first.
+func (s *KitchenService) GetFeed(ctx context.Context, tenantID string, since *time.Time) (*models.KitchenFeed, error) {
+	feed, err := s.kitchenRepo.ListOrders(ctx, tenantID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	prepMinutes := s.prepMinutes(ctx, tenantID)
+	now := time.Now()
+	for i := range feed.Orders {
+		feed.Orders[i].SetTiming(now, prepMinutes)
+	}
+	sort.SliceStable(feed.Orders, func(i, j int) bool {
+		return feed.Orders[i].DueAt.Before(feed.Orders[j].DueAt)
+	})
+
+	return feed, nil
+}
+
+// ListBumped returns the most recently bumped orders that can be recalled, newest first
+func (s *KitchenService) ListBumped(ctx context.Context, tenantID string, limit int) ([]models.KitchenOrder, error) {
+	orders, err := s.kitchenRepo.ListBumpedOrders(ctx, tenantID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	prepMinutes := s.prepMinutes(ctx, tenantID)
+	now := time.Now()
+	for i := range orders {
+		orders[i].SetTiming(now, prepMinutes)
+	}
+	return orders, nil
+}
+
+// UpdateItemStatus moves an item of an active kitchen order to a preparation status
+func (s *KitchenService) UpdateItemStatus(ctx context.Context, tenantID, orderID, itemID string, status models.PrepStatus) error {
+	if !status.IsValid() {
+		return models.ErrInvalidPrepStatus
+	}
+
+	updated, err := s.kitchenRepo.UpdateItemPrepStatus(ctx, tenantID, orderID, itemID, status)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return models.ErrKitchenOrderNotActive
+	}
+	return nil
+}
+
+// BumpOrder takes an order off the kitchen screen once it has been handed out
+func (s *KitchenService) BumpOrder(ctx context.Context, tenantID, orderID string) error {
+	bumped, err := s.kitchenRepo.BumpOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return err
+	}
+	if !bumped {
+		return models.ErrKitchenOrderNotActive
+	}
+
+	log.Info().Str("tenant_id", tenantID).Str("order_id", orderID).Msg("Kitchen order bumped")
+	return nil
+}
+
+// RecallOrder puts a bumped order back on the kitchen screen
+func (s *KitchenService) RecallOrder(ctx context.Context, tenantID, orderID string) error {
+	recalled, err := s.kitchenRepo.RecallOrder(ctx, tenantID, orderID)
+	if err != nil {
+		return err
+	}
+	if !recalled {
+		return models.ErrKitchenOrderNotBumped
+	}
+
+	log.Info().Str("tenant_id", tenantID).Str("order_id", orderID).Msg("Kitchen order recalled")
+	return nil
+}
+
+// GetMetrics summarizes ticket and preparation times in [from, to)
+func (s *KitchenService) GetMetrics(ctx context.Context, tenantID string, from, to time.Time) (*models.KitchenMetrics, error) {
+	return s.kitchenRepo.GetMetrics(ctx, tenantID, from, to)
+}
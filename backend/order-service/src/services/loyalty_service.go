@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// LoyaltyService manages loyalty programs and moves points as guest orders are placed, paid and cancelled
+// Balances are shown and points spent only for guests who verified their phone by OTP.
+type LoyaltyService struct {
+	loyaltyRepo *repository.LoyaltyRepository
+	sessionRepo *repository.CustomerSessionRepository
+}
+
+// NewLoyaltyService creates a new loyalty service
+func NewLoyaltyService(loyaltyRepo *repository.LoyaltyRepository, sessionRepo *repository.CustomerSessionRepository) *LoyaltyService {
+	return &LoyaltyService{
+		loyaltyRepo: loyaltyRepo,
+		sessionRepo: sessionRepo,
+	}
+}
+
+// GetProgram retrieves a tenant's loyalty settings
+func (s *LoyaltyService) GetProgram(ctx context.Context, tenantID string) (*models.LoyaltyProgram, error) {
+	return s.loyaltyRepo.GetProgram(ctx, tenantID)
+}
+
+// UpdateProgram validates and changes a tenant's loyalty settings
+func (s *LoyaltyService) UpdateProgram(ctx context.Context, tenantID string, req *models.UpdateLoyaltyProgramRequest) (*models.LoyaltyProgram, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.loyaltyRepo.UpdateProgram(ctx, tenantID, req)
+}
+
+// GetBalance returns a tenant's loyalty settings, with the guest's points balance when their
+// customer session verifies the phone
+// Unverified guests only see the settings, so the response reveals nothing about the phone number.
+func (s *LoyaltyService) GetBalance(ctx context.Context, tenantID, sessionToken, phone string) (*models.LoyaltyBalance, error) {
+	program, err := s.loyaltyRepo.GetProgram(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+
+	balance := &models.LoyaltyBalance{
+		Enabled:          program.IsEnabled,
+		PointValue:       program.PointValue,
+		SpendPerPoint:    program.SpendPerPoint,
+		MinRedeemPoints:  program.MinRedeemPoints,
+		MaxRedeemPercent: program.MaxRedeemPercent,
+	}
+	if !program.IsEnabled || strings.TrimSpace(phone) == "" {
+		return balance, nil
+	}
+
+	err = s.verifyPhone(ctx, tenantID, sessionToken, phone)
+	if errors.Is(err, models.ErrLoyaltyVerificationRequired) {
+		return balance, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	balance.Verified = true
+
+	account, err := s.loyaltyRepo.GetAccount(ctx, tenantID, models.CustomerPhoneHash(tenantID, phone))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loyalty account: %w", err)
+	}
+	if account != nil {
+		balance.PointsBalance = account.PointsBalance
+		balance.BalanceValue = account.PointsBalance * program.PointValue
+	}
+	return balance, nil
+}
+
+// PriceRedemption checks a checkout's points redemption and returns the points used and their discount
+// amountDue is the order amount after promotions and vouchers, before delivery. Returns a
+// nil account when no points are requested. The guest's customer session must verify the phone.
+func (s *LoyaltyService) PriceRedemption(ctx context.Context, tenantID, sessionToken, phone string, requested, amountDue int) (*models.LoyaltyAccount, int, int, error) {
+	if requested <= 0 {
+		return nil, 0, 0, nil
+	}
+	if strings.TrimSpace(phone) == "" {
+		return nil, 0, 0, models.ErrLoyaltyPhoneRequired
+	}
+	if err := s.verifyPhone(ctx, tenantID, sessionToken, phone); err != nil {
+		return nil, 0, 0, err
+	}
+
+	program, err := s.loyaltyRepo.GetProgram(ctx, tenantID)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+
+	account, err := s.loyaltyRepo.GetAccount(ctx, tenantID, models.CustomerPhoneHash(tenantID, phone))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get loyalty account: %w", err)
+	}
+	balance := 0
+	if account != nil {
+		balance = account.PointsBalance
+	}
+
+	points, discount, err := program.Redemption(requested, balance, amountDue)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return account, points, discount, nil
+}
+
+// verifyPhone checks that the customer session belongs to a guest who verified this phone
+// Returns ErrLoyaltyVerificationRequired for a missing or expired session or another phone's.
+func (s *LoyaltyService) verifyPhone(ctx context.Context, tenantID, sessionToken, phone string) error {
+	sessionPhoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, sessionToken)
+	if errors.Is(err, models.ErrCustomerSessionRequired) {
+		return models.ErrLoyaltyVerificationRequired
+	}
+	if err != nil {
+		return err
+	}
+
+	search, err := models.ParseContactSearch(phone)
+	if err != nil || search.Email || !hmac.Equal([]byte(utils.HashForSearch(search.Value)), []byte(sessionPhoneHash)) {
+		return models.ErrLoyaltyVerificationRequired
+	}
+	return nil
+}
+
+// Redeem spends the points on an order inside the checkout transaction
+func (s *LoyaltyService) Redeem(ctx context.Context, tx *sql.Tx, account *models.LoyaltyAccount, orderID string, points int) error {
+	if err := s.loyaltyRepo.Redeem(ctx, tx, account, orderID, points); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("tenant_id", account.TenantID).
+		Str("order_id", orderID).
+		Int("points", points).
+		Msg("Loyalty points redeemed")
+	return nil
+}
+
+// AccrueForOrder credits the points earned by a paid order inside its status transaction
+// Points are earned on what the guest paid for goods, after all discounts and without
+// delivery. Orders without a phone number earn nothing.
+func (s *LoyaltyService) AccrueForOrder(ctx context.Context, tx *sql.Tx, order *models.GuestOrder) error {
+	if strings.TrimSpace(order.CustomerPhone) == "" {
+		return nil
+	}
+
+	program, err := s.loyaltyRepo.GetProgram(ctx, order.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get loyalty program: %w", err)
+	}
+
+	points := program.PointsEarned(order.TotalAmount - order.DeliveryFee)
+	if points == 0 {
+		return nil
+	}
+
+	earned, err := s.loyaltyRepo.Earn(ctx, tx, order.TenantID, models.CustomerPhoneHash(order.TenantID, order.CustomerPhone), order.ID, points)
+	if err != nil {
+		return err
+	}
+	if earned {
+		log.Info().
+			Str("tenant_id", order.TenantID).
+			Str("order_id", order.ID).
+			Int("points", points).
+			Msg("Loyalty points earned")
+	}
+	return nil
+}
+
+// ReverseForOrder takes back the points earned by a cancelled order and gives back the points it used
+func (s *LoyaltyService) ReverseForOrder(ctx context.Context, tx *sql.Tx, orderID string) error {
+	return s.loyaltyRepo.ReverseForOrder(ctx, tx, orderID)
+}
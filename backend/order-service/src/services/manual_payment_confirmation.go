@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// ManualPaymentConfirmationRequest is a staff request to mark an order's online payment
+// as paid when the gateway's settlement webhook never arrived
+type ManualPaymentConfirmationRequest struct {
+	OrderID     string `json:"-"`
+	TenantID    string `json:"-"`
+	ConfirmedBy string `json:"-"`
+	Reason      string `json:"reason" validate:"required,max=500"`
+}
+
+// ManualPaymentConfirmation summarizes a manually confirmed payment
+type ManualPaymentConfirmation struct {
+	OrderID        string                        `json:"order_id"`
+	OrderReference string                        `json:"order_reference"`
+	Status         models.OrderStatus            `json:"status"`
+	Gateway        models.PaymentGatewayProvider `json:"gateway"`
+	Amount         int                           `json:"amount"`
+	ConfirmedBy    string                        `json:"confirmed_by"`
+	Reason         string                        `json:"reason"`
+}
+
+// ConfirmPaymentManually settles a pending order's online charge without a gateway notification
+// The charge is recorded as settled with who confirmed it and why, then the order goes
+// through the same success path as a settlement webhook: PAID, order.paid published and
+// inventory reservations converted. A settlement webhook arriving later is ignored.
+func (s *PaymentService) ConfirmPaymentManually(ctx context.Context, req *ManualPaymentConfirmationRequest) (*ManualPaymentConfirmation, error) {
+	order, payment, err := s.getOnlinePayment(ctx, req.OrderID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, nil, order.ID, order.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment balance: %w", err)
+	}
+	if err := CheckManualPaymentConfirmation(order, balance.AmountPaid); err != nil {
+		return nil, err
+	}
+
+	notification, idempotencyKey := ManualSettlementNotification(payment)
+	if err := s.paymentRepo.ConfirmPaymentManually(ctx, payment.ID, idempotencyKey, req.ConfirmedBy, req.Reason); err != nil {
+		return nil, fmt.Errorf("failed to record manual payment confirmation: %w", err)
+	}
+
+	paymentStatus := notification.TransactionStatus
+	s.orderService.broadcastStatus(ctx, models.OrderStatusEventPayment, order.OrderReference, order.Status, &paymentStatus)
+
+	if err := s.handlePaymentSuccess(ctx, order.ID, order.TenantID, notification); err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("Payment of %d via %s confirmed manually without a gateway notification. Reason: %s",
+		payment.Amount, payment.Gateway, req.Reason)
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, req.ConfirmedBy); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add manual payment confirmation note")
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("gateway", string(payment.Gateway)).
+		Int("amount", payment.Amount).
+		Str("confirmed_by", req.ConfirmedBy).
+		Msg("Online payment confirmed manually - order PAID")
+
+	return &ManualPaymentConfirmation{
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Status:         models.OrderStatusPaid,
+		Gateway:        payment.Gateway,
+		Amount:         payment.Amount,
+		ConfirmedBy:    req.ConfirmedBy,
+		Reason:         req.Reason,
+	}, nil
+}
+
+// CheckManualPaymentConfirmation reports whether an order's online charge may be confirmed manually
+// Only PENDING orders qualify, and not once part of the order has been paid: split payments
+// settle part by part, so the full-amount charge no longer describes the order.
+func CheckManualPaymentConfirmation(order *models.GuestOrder, amountPaid int) error {
+	if !order.RequiresPayment() {
+		return ErrOrderNotPayable
+	}
+	if amountPaid > 0 {
+		return fmt.Errorf("%w: part of the order has already been paid", ErrOrderNotPayable)
+	}
+	return nil
+}
+
+// ManualSettlementNotification describes a manually confirmed charge as its settlement webhook would
+// The returned idempotency key is the one that webhook would carry, so a late notification is
+// treated as already processed; it is nil when the charge's transaction ID was never learned.
+func ManualSettlementNotification(payment *models.PaymentTransaction) (*MidtransNotification, *string) {
+	notification := &MidtransNotification{
+		TransactionStatus: "settlement",
+		OrderID:           payment.MidtransOrderID,
+		GrossAmount:       strconv.Itoa(payment.Amount),
+	}
+	var idempotencyKey *string
+	if payment.MidtransTransactionID != nil {
+		notification.TransactionID = *payment.MidtransTransactionID
+		key := notification.TransactionID + ":" + notification.TransactionStatus
+		idempotencyKey = &key
+	}
+	if payment.PaymentType != nil {
+		notification.PaymentType = *payment.PaymentType
+	}
+	return notification, idempotencyKey
+}
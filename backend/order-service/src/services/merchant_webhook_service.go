@@ -0,0 +1,338 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// webhookResponseExcerpt is how much of a failing endpoint's response body is kept in the delivery log
+const webhookResponseExcerpt = 512
+
+// MerchantWebhookService manages tenants' webhooks and delivers order events to them
+// Events are queued as deliveries when they happen and sent by MerchantWebhookJob, so a
+// slow or failing merchant endpoint never holds up checkout or payment handling.
+type MerchantWebhookService struct {
+	repo       *repository.MerchantWebhookRepository
+	httpClient *http.Client
+}
+
+// NewMerchantWebhookService creates a new merchant webhook service
+func NewMerchantWebhookService(repo *repository.MerchantWebhookRepository) *MerchantWebhookService {
+	return &MerchantWebhookService{
+		repo:       repo,
+		httpClient: NewWebhookHTTPClient(10 * time.Second),
+	}
+}
+
+// NewWebhookHTTPClient returns the client deliveries are sent with
+// Merchants choose the URL and can read the response excerpt in the delivery log, so the client
+// only connects to public addresses, checked after DNS resolution, never goes through a proxy and
+// does not follow redirects: a redirect is logged as the non-2xx answer it is.
+func NewWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return models.CheckWebhookAddress(address)
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// generateWebhookSecret returns a new random signing secret
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// ListWebhooks returns a tenant's webhooks
+func (s *MerchantWebhookService) ListWebhooks(ctx context.Context, tenantID string) ([]*models.MerchantWebhook, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+// CreateWebhook registers a webhook; its signing secret is returned only this once
+func (s *MerchantWebhookService) CreateWebhook(ctx context.Context, tenantID string, req *models.MerchantWebhookRequest) (*models.MerchantWebhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook := &models.MerchantWebhook{
+		TenantID: tenantID,
+		URL:      req.URL,
+		Events:   req.Events,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	}
+	if err := s.repo.Create(ctx, webhook, secret); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	webhook.Secret = secret
+	return webhook, nil
+}
+
+// UpdateWebhook replaces a webhook's URL, events and active flag; the secret is kept
+func (s *MerchantWebhookService) UpdateWebhook(ctx context.Context, tenantID, webhookID string, req *models.MerchantWebhookRequest) (*models.MerchantWebhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	webhook := &models.MerchantWebhook{
+		ID:       webhookID,
+		TenantID: tenantID,
+		URL:      req.URL,
+		Events:   req.Events,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	}
+	if err := s.repo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook; its pending deliveries are dropped with it
+func (s *MerchantWebhookService) DeleteWebhook(ctx context.Context, tenantID, webhookID string) error {
+	return s.repo.Delete(ctx, tenantID, webhookID)
+}
+
+// RotateSecret gives a webhook a new signing secret and returns the webhook with it
+// Deliveries still queued are signed with the new secret when they are sent.
+func (s *MerchantWebhookService) RotateSecret(ctx context.Context, tenantID, webhookID string) (*models.MerchantWebhook, error) {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	if err := s.repo.RotateSecret(ctx, tenantID, webhookID, secret); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.repo.Get(ctx, tenantID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = secret
+	return webhook, nil
+}
+
+// ListDeliveries returns a webhook's delivery log
+func (s *MerchantWebhookService) ListDeliveries(ctx context.Context, tenantID, webhookID string, status *models.WebhookDeliveryStatus, limit, offset int) ([]*models.MerchantWebhookDelivery, error) {
+	if _, err := s.repo.Get(ctx, tenantID, webhookID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, tenantID, webhookID, status, limit, offset)
+}
+
+// Publish queues an order event for every active webhook of the tenant subscribed to it
+// Failures are logged, never returned: webhooks must not fail the order change itself.
+func (s *MerchantWebhookService) Publish(ctx context.Context, event models.MerchantWebhookEvent, order *models.GuestOrder, refund *models.WebhookRefund) {
+	if s == nil || order == nil {
+		return
+	}
+
+	webhooks, err := s.repo.ListSubscribed(ctx, order.TenantID, event)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Str("event", string(event)).Msg("Failed to list merchant webhooks")
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	eventID := uuid.NewString()
+	data := models.NewWebhookOrderData(order)
+	data.Refund = refund
+	payload, err := json.Marshal(&models.MerchantWebhookPayload{
+		ID:         eventID,
+		Type:       event,
+		TenantID:   order.TenantID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Msg("Failed to marshal merchant webhook payload")
+		return
+	}
+
+	for _, webhook := range webhooks {
+		delivery := &models.MerchantWebhookDelivery{
+			WebhookID: webhook.ID,
+			TenantID:  order.TenantID,
+			EventID:   eventID,
+			EventType: event,
+			Payload:   payload,
+		}
+		if err := s.repo.QueueDelivery(ctx, delivery); err != nil {
+			log.Error().Err(err).
+				Str("webhook_id", webhook.ID).
+				Str("order_id", order.ID).
+				Str("event", string(event)).
+				Msg("Failed to queue merchant webhook delivery")
+		}
+	}
+}
+
+// DeliverDue sends up to limit due deliveries
+func (s *MerchantWebhookService) DeliverDue(ctx context.Context, lease time.Duration, limit int) {
+	deliveries, err := s.repo.ClaimDue(ctx, time.Now(), lease, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to claim due merchant webhook deliveries")
+		return
+	}
+
+	for _, delivery := range deliveries {
+		s.deliver(ctx, delivery)
+		if err := s.repo.RecordAttempt(ctx, delivery); err != nil {
+			log.Error().Err(err).Str("delivery_id", delivery.ID).Msg("Failed to record merchant webhook attempt")
+		}
+	}
+}
+
+// deliver makes one attempt at a claimed delivery and sets its outcome
+func (s *MerchantWebhookService) deliver(ctx context.Context, delivery *models.MerchantWebhookDelivery) {
+	statusCode, err := s.send(ctx, delivery)
+	if statusCode != 0 {
+		delivery.LastStatusCode = &statusCode
+	}
+
+	now := time.Now()
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.LastError = nil
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	message := err.Error()
+	delivery.LastError = &message
+	if delivery.Attempts >= models.MaxWebhookAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		log.Warn().
+			Str("delivery_id", delivery.ID).
+			Str("webhook_id", delivery.WebhookID).
+			Int("attempts", delivery.Attempts).
+			Str("error", message).
+			Msg("Giving up on merchant webhook delivery")
+		return
+	}
+	next := now.Add(models.WebhookRetryDelay(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+// send POSTs a delivery's signed payload; any non-2xx answer is an error
+func (s *MerchantWebhookService) send(ctx context.Context, delivery *models.MerchantWebhookDelivery) (int, error) {
+	// Webhooks registered before URLs were restricted are checked again on every attempt
+	if err := models.ValidateWebhookURL(delivery.URL); err != nil {
+		return 0, err
+	}
+
+	secret, err := s.repo.DecryptSecret(ctx, delivery.EncryptedSecret)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "POS-Webhooks/1.0")
+	req.Header.Set(models.WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(models.WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(models.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(models.WebhookSignatureHeader, models.SignWebhookPayload(secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseExcerpt))
+		return resp.StatusCode, fmt.Errorf("endpoint answered %d: %s", resp.StatusCode, body)
+	}
+	return resp.StatusCode, nil
+}
+
+// MerchantWebhookJob sends queued merchant webhook deliveries and retries failed ones
+type MerchantWebhookJob struct {
+	webhookService *MerchantWebhookService
+	interval       time.Duration
+	lease          time.Duration
+	batchSize      int
+	stopChan       chan struct{}
+}
+
+// NewMerchantWebhookJob creates the merchant webhook delivery worker
+func NewMerchantWebhookJob(webhookService *MerchantWebhookService) *MerchantWebhookJob {
+	return &MerchantWebhookJob{
+		webhookService: webhookService,
+		interval:       5 * time.Second,
+		lease:          time.Minute, // Longer than a delivery's request timeout
+		batchSize:      50,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the delivery loop; it blocks until stopped
+func (j *MerchantWebhookJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting merchant webhook job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.webhookService.DeliverDue(ctx, j.lease, j.batchSize)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping merchant webhook job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping merchant webhook job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the delivery loop
+func (j *MerchantWebhookJob) Stop() {
+	close(j.stopChan)
+}
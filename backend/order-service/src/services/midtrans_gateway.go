@@ -0,0 +1,529 @@
+package services
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// MidtransGateway charges through Midtrans with the tenant's own credentials
+// QRIS, GoPay and bank transfer use the Core API; credit cards go through Snap so
+// card details never touch our servers.
+type MidtransGateway struct{}
+
+// NewMidtransGateway creates the Midtrans payment gateway adapter
+func NewMidtransGateway() *MidtransGateway {
+	return &MidtransGateway{}
+}
+
+// Provider identifies the gateway
+func (g *MidtransGateway) Provider() models.PaymentGatewayProvider {
+	return models.PaymentGatewayMidtrans
+}
+
+// CreateCharge starts a Midtrans payment using the requested method
+func (g *MidtransGateway) CreateCharge(ctx context.Context, req *GatewayChargeRequest) (*models.PaymentTransaction, error) {
+	chargeReq := buildChargeRequest(req)
+
+	switch req.Method {
+	case models.CheckoutPaymentQRIS:
+		chargeReq.PaymentType = coreapi.PaymentTypeQris
+
+	case models.CheckoutPaymentGoPay:
+		chargeReq.PaymentType = coreapi.PaymentTypeGopay
+		chargeReq.Gopay = &coreapi.GopayDetails{EnableCallback: true}
+
+	case models.CheckoutPaymentBankTransfer:
+		chargeReq.PaymentType = coreapi.PaymentTypeBankTransfer
+		chargeReq.BankTransfer = &coreapi.BankTransferDetails{Bank: midtrans.Bank(req.Bank)}
+
+	case models.CheckoutPaymentCreditCard:
+		return g.createCreditCardSnapPayment(ctx, req)
+
+	default:
+		return nil, models.ErrUnsupportedPaymentMethod
+	}
+
+	// The charge lapses when the checkout's stock reservation does
+	chargeReq.CustomExpiry = &coreapi.CustomExpiry{
+		ExpiryDuration: int(req.expiry() / time.Minute),
+		Unit:           "minute",
+	}
+
+	resp, err := g.executeCharge(ctx, req.Order, chargeReq)
+	if err != nil {
+		return nil, err
+	}
+	return buildCoreAPIPaymentTransaction(req, resp), nil
+}
+
+// buildChargeRequest builds the common Core API charge payload for an order
+// Partial charges omit line items: Midtrans requires them to sum to the gross amount.
+func buildChargeRequest(req *GatewayChargeRequest) *coreapi.ChargeReq {
+	order := req.Order
+	customerEmail := ""
+	if order.CustomerEmail != nil {
+		customerEmail = *order.CustomerEmail
+	}
+
+	chargeReq := &coreapi.ChargeReq{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  req.referenceID(),
+			GrossAmt: int64(req.amount()),
+		},
+		CustomerDetails: &midtrans.CustomerDetails{
+			FName: order.CustomerName,
+			Phone: order.CustomerPhone,
+			Email: customerEmail,
+		},
+	}
+	if !req.isPartial() && len(req.Items) > 0 {
+		chargeReq.Items = midtransOrderItems(req.Order, req.Items)
+	}
+	return chargeReq
+}
+
+// midtransOrderItems builds the line items for a full-amount charge
+// Delivery fee, service charge, tax, promotion, voucher and loyalty discounts are added as adjustment lines so
+// the items sum to the gross amount, as Midtrans requires.
+func midtransOrderItems(order *models.GuestOrder, items []models.CartItem) *[]midtrans.ItemDetails {
+	lineItems := convertCartItemsToMidtransItems(items)
+	if order.DeliveryFee > 0 {
+		*lineItems = append(*lineItems, midtrans.ItemDetails{
+			ID:    "delivery-fee",
+			Price: int64(order.DeliveryFee),
+			Qty:   1,
+			Name:  "Delivery Fee",
+		})
+	}
+	if order.ServiceChargeAmount > 0 {
+		*lineItems = append(*lineItems, midtrans.ItemDetails{
+			ID:    "service-charge",
+			Price: int64(order.ServiceChargeAmount),
+			Qty:   1,
+			Name:  fmt.Sprintf("Service Charge (%s%%)", strconv.FormatFloat(order.ServiceChargeRate, 'f', -1, 64)),
+		})
+	}
+	if order.TaxAmount > 0 {
+		*lineItems = append(*lineItems, midtrans.ItemDetails{
+			ID:    "tax",
+			Price: int64(order.TaxAmount),
+			Qty:   1,
+			Name:  fmt.Sprintf("PPN (%s%%)", strconv.FormatFloat(order.TaxRate, 'f', -1, 64)),
+		})
+	}
+	if order.PromotionDiscountAmount > 0 {
+		*lineItems = append(*lineItems, midtrans.ItemDetails{
+			ID:    "promotion",
+			Price: -int64(order.PromotionDiscountAmount),
+			Qty:   1,
+			Name:  "Promotions",
+		})
+	}
+	if order.DiscountAmount > 0 {
+		name := "Discount"
+		if order.VoucherCode != nil {
+			name = "Discount " + *order.VoucherCode
+		}
+		if len(name) > 50 { // Midtrans item name limit
+			name = name[:50]
+		}
+		*lineItems = append(*lineItems, midtrans.ItemDetails{
+			ID:    "discount",
+			Price: -int64(order.DiscountAmount),
+			Qty:   1,
+			Name:  name,
+		})
+	}
+	if order.LoyaltyDiscountAmount > 0 {
+		*lineItems = append(*lineItems, midtrans.ItemDetails{
+			ID:    "loyalty",
+			Price: -int64(order.LoyaltyDiscountAmount),
+			Qty:   1,
+			Name:  fmt.Sprintf("Loyalty Points (%d)", order.LoyaltyPointsRedeemed),
+		})
+	}
+	return lineItems
+}
+
+// executeCharge sends a Core API charge with the tenant's Midtrans credentials
+func (g *MidtransGateway) executeCharge(ctx context.Context, order *models.GuestOrder, chargeReq *coreapi.ChargeReq) (*coreapi.ChargeResponse, error) {
+	midtransConfig, err := config.GetMidtransConfigForTenant(ctx, order.TenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", order.TenantID).Msg("Failed to fetch tenant Midtrans config")
+		return nil, fmt.Errorf("failed to get Midtrans configuration: %w", err)
+	}
+
+	if !midtransConfig.IsConfigured {
+		log.Error().Str("tenant_id", order.TenantID).Msg("Midtrans not configured for tenant")
+		return nil, fmt.Errorf("Midtrans is not configured for this tenant")
+	}
+
+	midtransCoreAPI, err := config.GetCoreAPIClientForTenant(ctx, order.TenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", order.TenantID).Msg("Failed to get Core API client for tenant")
+		return nil, fmt.Errorf("failed to get Core API client: %w", err)
+	}
+
+	resp, chargeErr := midtransCoreAPI.ChargeTransaction(chargeReq)
+	if chargeErr != nil {
+		log.Error().Err(chargeErr).Str("payment_type", string(chargeReq.PaymentType)).Msg("Failed to execute charge request")
+		if models.IsGatewayOutageStatus(chargeErr.StatusCode) {
+			return nil, fmt.Errorf("%w: failed to execute request: %w", models.ErrPaymentGatewayUnavailable, chargeErr)
+		}
+		return nil, fmt.Errorf("failed to execute request: %w", chargeErr)
+	}
+
+	if resp.StatusCode != strconv.Itoa(http.StatusCreated) && resp.StatusCode != strconv.Itoa(http.StatusOK) {
+		log.Error().
+			Str("status_code", resp.StatusCode).
+			Str("status_message", resp.StatusMessage).
+			Str("payment_type", string(chargeReq.PaymentType)).
+			Str("order_id", resp.OrderID).
+			Msg("Charge request failed")
+		if statusCode, err := strconv.Atoi(resp.StatusCode); err == nil && models.IsGatewayOutageStatus(statusCode) {
+			return nil, fmt.Errorf("%w: charge request failed with status %s: %s", models.ErrPaymentGatewayUnavailable, resp.StatusCode, resp.StatusMessage)
+		}
+		return nil, fmt.Errorf("charge request failed with status %s: %s", resp.StatusCode, resp.StatusMessage)
+	}
+
+	log.Info().
+		Str("tenant_id", order.TenantID).
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("transaction_id", resp.TransactionID).
+		Str("payment_type", string(chargeReq.PaymentType)).
+		Msg("Charge created successfully with tenant-specific credentials")
+
+	return resp, nil
+}
+
+// createCreditCardSnapPayment creates a Snap transaction limited to credit card payments
+func (g *MidtransGateway) createCreditCardSnapPayment(ctx context.Context, req *GatewayChargeRequest) (*models.PaymentTransaction, error) {
+	order := req.Order
+	snapClient, err := config.GetSnapClientForTenant(ctx, order.TenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", order.TenantID).Msg("Failed to get Snap client for tenant")
+		return nil, fmt.Errorf("failed to get Snap client: %w", err)
+	}
+
+	customerEmail := ""
+	if order.CustomerEmail != nil {
+		customerEmail = *order.CustomerEmail
+	}
+
+	snapReq := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  req.referenceID(),
+			GrossAmt: int64(req.amount()),
+		},
+		CustomerDetail: &midtrans.CustomerDetails{
+			FName: order.CustomerName,
+			Phone: order.CustomerPhone,
+			Email: customerEmail,
+		},
+		EnabledPayments: []snap.SnapPaymentType{snap.PaymentTypeCreditCard},
+		CreditCard: &snap.CreditCardDetails{
+			Secure: true, // Require 3DS
+		},
+	}
+	if !req.isPartial() && len(req.Items) > 0 {
+		snapReq.Items = midtransOrderItems(req.Order, req.Items)
+	}
+
+	snapResp, snapErr := snapClient.CreateTransaction(snapReq)
+	if snapErr != nil {
+		log.Error().
+			Err(snapErr).
+			Str("order_id", order.ID).
+			Str("order_reference", order.OrderReference).
+			Msg("Failed to create credit card Snap transaction")
+		if models.IsGatewayOutageStatus(snapErr.StatusCode) {
+			return nil, fmt.Errorf("%w: failed to create payment: %w", models.ErrPaymentGatewayUnavailable, snapErr)
+		}
+		return nil, fmt.Errorf("failed to create payment: %w", snapErr)
+	}
+
+	pending := "pending"
+	paymentType := "credit_card"
+	expiryTime := time.Now().Add(24 * time.Hour) // Snap tokens are valid for 24 hours
+	return &models.PaymentTransaction{
+		OrderID:           order.ID,
+		MidtransOrderID:   req.referenceID(),
+		Amount:            req.amount(),
+		PaymentMethod:     models.CheckoutPaymentCreditCard,
+		PaymentType:       &paymentType,
+		TransactionStatus: &pending,
+		RedirectURL:       &snapResp.RedirectURL,
+		SnapToken:         &snapResp.Token,
+		ExpiryTime:        &expiryTime,
+		Gateway:           models.PaymentGatewayMidtrans,
+	}, nil
+}
+
+// buildCoreAPIPaymentTransaction maps a Core API charge response to a payment transaction
+// Each method exposes a different customer action: a QR code, an app deeplink or a VA number
+func buildCoreAPIPaymentTransaction(req *GatewayChargeRequest, chargeResp *coreapi.ChargeResponse) *models.PaymentTransaction {
+	transactionID := chargeResp.TransactionID
+	paymentType := chargeResp.PaymentType
+	transactionStatus := chargeResp.TransactionStatus
+	fraudStatus := chargeResp.FraudStatus
+
+	chargeJSON, err := json.Marshal(chargeResp)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal charge response")
+		chargeJSON = json.RawMessage(`{}`)
+	}
+
+	expiryTime := time.Now().Add(req.expiry()).UTC()
+	if chargeResp.ExpiryTime != "" {
+		parsed, err := ParseMidtransTime(chargeResp.ExpiryTime)
+		if err != nil {
+			log.Error().Err(err).Str("expiry_time", chargeResp.ExpiryTime).Msg("Failed to parse expiry time, using default")
+		} else {
+			expiryTime = parsed
+		}
+	}
+
+	idempotencyKey := transactionID + ":" + strings.ToLower(transactionStatus)
+
+	payment := &models.PaymentTransaction{
+		OrderID:               req.Order.ID,
+		MidtransTransactionID: &transactionID,
+		MidtransOrderID:       chargeResp.OrderID,
+		Amount:                req.amount(),
+		PaymentMethod:         req.Method,
+		PaymentType:           &paymentType,
+		TransactionStatus:     &transactionStatus,
+		FraudStatus:           &fraudStatus,
+		NotificationPayload:   chargeJSON,
+		ExpiryTime:            &expiryTime,
+		SignatureVerified:     false, // Will be verified on webhook
+		IdempotencyKey:        &idempotencyKey,
+		Gateway:               models.PaymentGatewayMidtrans,
+	}
+
+	for _, action := range chargeResp.Actions {
+		url := action.URL
+		switch action.Name {
+		case "generate-qr-code":
+			payment.QRCodeURL = &url
+		case "deeplink-redirect":
+			payment.DeeplinkURL = &url
+		}
+	}
+	if chargeResp.QRString != "" {
+		payment.QRString = &chargeResp.QRString
+	}
+
+	// Permata returns its VA number in a dedicated field
+	if chargeResp.PermataVaNumber != "" {
+		bank := "permata"
+		payment.Bank = &bank
+		payment.VANumber = &chargeResp.PermataVaNumber
+	} else if len(chargeResp.VaNumbers) > 0 {
+		payment.Bank = &chargeResp.VaNumbers[0].Bank
+		payment.VANumber = &chargeResp.VaNumbers[0].VANumber
+	}
+
+	return payment
+}
+
+// midtransLocation is the zone of Midtrans timestamps, which carry no offset (WIB)
+var midtransLocation = time.FixedZone("WIB", 7*60*60)
+
+// ParseMidtransTime parses a Midtrans "2006-01-02 15:04:05" timestamp as UTC
+// payment_transactions.expiry_time has no time zone, so times are stored in UTC.
+func ParseMidtransTime(value string) (time.Time, error) {
+	parsed, err := time.ParseInLocation("2006-01-02 15:04:05", value, midtransLocation)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.UTC(), nil
+}
+
+// parseMidtransAmount parses a Midtrans "10000.00" gross amount to whole rupiah; 0 if unparseable
+func parseMidtransAmount(value string) int {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return int(math.Round(amount))
+}
+
+// VerifyWebhook verifies the Midtrans signature using the tenant-specific server key
+// Implements T059: SHA512 signature verification
+func (g *MidtransGateway) VerifyWebhook(ctx context.Context, tenantID string, notification *MidtransNotification, headers http.Header) bool {
+	// Fetch tenant-specific Midtrans server key
+	serverKey, err := config.GetMidtransServerKeyForTenant(ctx, tenantID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("order_id", notification.OrderID).
+			Msg("Failed to fetch tenant Midtrans server key for signature verification")
+		return false
+	}
+
+	calculatedSignature := midtransSignature(notification, serverKey)
+
+	// Compare signatures
+	isValid := calculatedSignature == notification.SignatureKey
+
+	if !isValid {
+		log.Warn().
+			Str("tenant_id", tenantID).
+			Str("order_id", notification.OrderID).
+			Str("expected_signature", calculatedSignature).
+			Str("received_signature", notification.SignatureKey).
+			Msg("Signature verification failed")
+	}
+
+	return isValid
+}
+
+// midtransSignature returns the SHA512 signature Midtrans sends with a notification:
+// order_id + status_code + gross_amount + server_key
+func midtransSignature(notification *MidtransNotification, serverKey string) string {
+	hash := sha512.New()
+	hash.Write([]byte(notification.OrderID + notification.StatusCode + notification.GrossAmount + serverKey))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Refund refunds a settled Midtrans charge
+// Bank transfers cannot be refunded through Midtrans and must be returned manually.
+func (g *MidtransGateway) Refund(ctx context.Context, tenantID string, payment *models.PaymentTransaction, amount int, reason string) (*GatewayRefund, error) {
+	if payment.PaymentMethod == models.CheckoutPaymentBankTransfer {
+		return nil, models.ErrRefundNotSupported
+	}
+
+	coreAPIClient, err := config.GetCoreAPIClientForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Core API client: %w", err)
+	}
+
+	refundKey := fmt.Sprintf("%s-refund-%d", payment.MidtransOrderID, time.Now().Unix())
+	resp, refundErr := coreAPIClient.RefundTransaction(payment.MidtransOrderID, &coreapi.RefundReq{
+		RefundKey: refundKey,
+		Amount:    int64(amount),
+		Reason:    reason,
+	})
+	if refundErr != nil {
+		log.Error().Err(refundErr).Str("midtrans_order_id", payment.MidtransOrderID).Msg("Failed to refund Midtrans transaction")
+		return nil, fmt.Errorf("failed to refund transaction: %w", refundErr)
+	}
+
+	if resp.StatusCode != strconv.Itoa(http.StatusOK) {
+		return nil, fmt.Errorf("refund request failed with status %s: %s", resp.StatusCode, resp.StatusMessage)
+	}
+
+	return &GatewayRefund{
+		Gateway:  models.PaymentGatewayMidtrans,
+		RefundID: refundKey,
+		Amount:   amount,
+		Status:   resp.TransactionStatus,
+	}, nil
+}
+
+// GetStatus fetches the transaction status from Midtrans by order ID
+// A Snap transaction the customer never opened is unknown to Midtrans (404); once its
+// token has lapsed it can no longer be paid and is reported as expired.
+func (g *MidtransGateway) GetStatus(ctx context.Context, tenantID string, payment *models.PaymentTransaction) (*GatewayPaymentStatus, error) {
+	coreAPIClient, err := config.GetCoreAPIClientForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Core API client: %w", err)
+	}
+
+	resp, statusErr := coreAPIClient.CheckTransaction(payment.MidtransOrderID)
+	if statusErr != nil {
+		if statusErr.StatusCode == http.StatusNotFound && payment.ExpiryTime != nil && time.Now().After(*payment.ExpiryTime) {
+			return &GatewayPaymentStatus{
+				Gateway:     models.PaymentGatewayMidtrans,
+				ReferenceID: payment.MidtransOrderID,
+				Status:      "expire",
+				Outcome:     models.PaymentOutcomeFailed,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to check transaction: %w", statusErr)
+	}
+
+	return &GatewayPaymentStatus{
+		Gateway:       models.PaymentGatewayMidtrans,
+		TransactionID: resp.TransactionID,
+		ReferenceID:   resp.OrderID,
+		Status:        resp.TransactionStatus,
+		Outcome:       models.MapMidtransStatus(resp.PaymentType, resp.TransactionStatus, resp.FraudStatus),
+		GrossAmount:   parseMidtransAmount(resp.GrossAmount),
+	}, nil
+}
+
+// Cancel voids a pending Midtrans charge
+// QRIS, GoPay and virtual account charges are expired, which is how Midtrans ends an
+// unpaid charge; card charges are cancelled. A credit card Snap transaction the customer
+// never opened is unknown to Midtrans (404) and is treated as already void.
+func (g *MidtransGateway) Cancel(ctx context.Context, tenantID string, payment *models.PaymentTransaction) error {
+	coreAPIClient, err := config.GetCoreAPIClientForTenant(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get Core API client: %w", err)
+	}
+
+	if payment.PaymentMethod != models.CheckoutPaymentCreditCard {
+		return g.expire(coreAPIClient, payment)
+	}
+
+	resp, cancelErr := coreAPIClient.CancelTransaction(payment.MidtransOrderID)
+	if cancelErr != nil {
+		if cancelErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		log.Error().Err(cancelErr).Str("midtrans_order_id", payment.MidtransOrderID).Msg("Failed to cancel Midtrans transaction")
+		return fmt.Errorf("failed to cancel transaction: %w", cancelErr)
+	}
+
+	switch resp.StatusCode {
+	case strconv.Itoa(http.StatusOK), strconv.Itoa(http.StatusNotFound):
+		return nil
+	case strconv.Itoa(http.StatusPreconditionFailed):
+		// Midtrans refuses to modify transactions that have already settled
+		return models.ErrPaymentNotCancellable
+	default:
+		return fmt.Errorf("cancel request failed with status %s: %s", resp.StatusCode, resp.StatusMessage)
+	}
+}
+
+// expire ends an unpaid Midtrans charge so it can no longer be paid
+func (g *MidtransGateway) expire(coreAPIClient *coreapi.Client, payment *models.PaymentTransaction) error {
+	resp, expireErr := coreAPIClient.ExpireTransaction(payment.MidtransOrderID)
+	if expireErr != nil {
+		if expireErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		log.Error().Err(expireErr).Str("midtrans_order_id", payment.MidtransOrderID).Msg("Failed to expire Midtrans transaction")
+		return fmt.Errorf("failed to expire transaction: %w", expireErr)
+	}
+
+	switch resp.StatusCode {
+	// Midtrans answers a successful expiry with 407 (transaction expired)
+	case strconv.Itoa(http.StatusOK), strconv.Itoa(http.StatusNotFound), "407":
+		return nil
+	case strconv.Itoa(http.StatusPreconditionFailed):
+		return models.ErrPaymentNotCancellable
+	default:
+		return fmt.Errorf("expire request failed with status %s: %s", resp.StatusCode, resp.StatusMessage)
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/point-of-sale-system/order-service/src/models"
 	"github.com/point-of-sale-system/order-service/src/observability"
 	"github.com/point-of-sale-system/order-service/src/repository"
@@ -29,6 +31,9 @@ type OfflineOrderService struct {
 	outboxRepo             *repository.OutboxRepository
 	eventPublisher         *EventPublisher
 	paymentCalculator      *PaymentCalculator
+	inventoryService       *InventoryService
+	referenceGenerator     *OrderReferenceGenerator
+	billingService         *BillingService
 	tracer                 trace.Tracer // T113: OpenTelemetry tracer
 }
 
@@ -41,16 +46,22 @@ func NewOfflineOrderService(
 	outboxRepo *repository.OutboxRepository,
 	eventPublisher *EventPublisher,
 	paymentCalculator *PaymentCalculator,
+	inventoryService *InventoryService,
+	referenceGenerator *OrderReferenceGenerator,
+	billingService *BillingService,
 ) *OfflineOrderService {
 	return &OfflineOrderService{
-		db:                db,
-		offlineOrderRepo:  offlineOrderRepo,
-		orderItemRepo:     orderItemRepo,
-		paymentRepo:       paymentRepo,
-		outboxRepo:        outboxRepo,
-		eventPublisher:    eventPublisher,
-		paymentCalculator: paymentCalculator,
-		tracer:            otel.Tracer("offline-order-service"), // T113: Initialize tracer
+		db:                 db,
+		offlineOrderRepo:   offlineOrderRepo,
+		orderItemRepo:      orderItemRepo,
+		paymentRepo:        paymentRepo,
+		outboxRepo:         outboxRepo,
+		eventPublisher:     eventPublisher,
+		paymentCalculator:  paymentCalculator,
+		inventoryService:   inventoryService,
+		referenceGenerator: referenceGenerator,
+		billingService:     billingService,
+		tracer:             otel.Tracer("offline-order-service"), // T113: Initialize tracer
 	}
 }
 
@@ -113,6 +124,12 @@ func (s *OfflineOrderService) CreateOfflineOrder(ctx context.Context, req *Creat
 		return nil, fmt.Errorf("consent method is required when data consent is given")
 	}
 
+	// Offline orders count towards the plan's monthly orders too
+	if err := s.billingService.CheckMonthlyOrderLimit(ctx, req.TenantID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
 	// Begin transaction for atomic operation
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -120,8 +137,12 @@ func (s *OfflineOrderService) CreateOfflineOrder(ctx context.Context, req *Creat
 	}
 	defer tx.Rollback() //nolint:errcheck
 
-	// Generate order reference (GO-XXXXXX format)
-	orderReference := s.generateOrderReference()
+	// Generate order reference from the tenant's pattern, or GO-XXXXXX when it has none
+	orderReference, err := s.referenceGenerator.NextForTenant(ctx, tx, req.TenantID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to generate order reference: %w", err)
+	}
 
 	// Calculate totals from items
 	var subtotalAmount int
@@ -256,6 +277,17 @@ func (s *OfflineOrderService) CreateOfflineOrder(ctx context.Context, req *Creat
 			return nil, fmt.Errorf("failed to update order status: %w", err)
 		}
 		order.Status = models.OrderStatusPaid
+		if order.QueueNumber, err = s.orderItemRepo.AssignQueueNumber(ctx, tx, order.TenantID, orderID); err != nil {
+			return nil, fmt.Errorf("failed to assign queue number: %w", err)
+		}
+	}
+
+	// Full and installment sales hand the goods over at the counter, so their stock is
+	// allocated now; orders recorded without payment only hold stock until they are paid
+	if err := s.holdOfflineOrderStock(ctx, tx, order, req.PaymentInfo != nil, createItemReservationLines(req.Items)); err != nil {
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("error.type", "stock_unavailable"))
+		return nil, err
 	}
 
 	// Publish offline_order.created event to audit trail (T034)
@@ -329,12 +361,45 @@ func (s *OfflineOrderService) CreateOfflineOrder(ctx context.Context, req *Creat
 	return order, nil
 }
 
-// generateOrderReference generates a unique order reference in GO-XXXXXX format
-// Implements T031: Order reference generator
-func (s *OfflineOrderService) generateOrderReference() string {
-	// Generate 6-digit random number with leading zeros
-	randomNum := rand.Intn(1000000)
-	return fmt.Sprintf("GO-%06d", randomNum)
+// holdOfflineOrderStock allocates stock for a completed sale or reserves it for an unpaid order
+func (s *OfflineOrderService) holdOfflineOrderStock(ctx context.Context, tx *sql.Tx, order *models.GuestOrder, allocate bool, lines []models.ReservationLine) error {
+	var err error
+	if allocate || order.Status == models.OrderStatusPaid {
+		err = s.inventoryService.Allocate(ctx, tx, order.TenantID, order.ID, lines)
+	} else {
+		err = s.inventoryService.Reserve(ctx, tx, order.TenantID, order.ID, lines, ManualOrderReservationTTL)
+	}
+	if err != nil {
+		if IsStockError(err) {
+			return err
+		}
+		return fmt.Errorf("failed to hold stock: %w", err)
+	}
+	return nil
+}
+
+func createItemReservationLines(items []models.CreateOrderItemReq) []models.ReservationLine {
+	lines := make([]models.ReservationLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, models.ReservationLine{ProductID: item.ProductID, ProductName: item.ProductName, Quantity: item.Quantity})
+	}
+	return lines
+}
+
+func inputItemReservationLines(items []models.OrderItemInput) []models.ReservationLine {
+	lines := make([]models.ReservationLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, models.ReservationLine{ProductID: item.ProductID, ProductName: item.ProductName, Quantity: item.Quantity})
+	}
+	return lines
+}
+
+func orderItemReservationLines(items []models.OrderItem) []models.ReservationLine {
+	lines := make([]models.ReservationLine, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, models.ReservationLine{ProductID: item.ProductID, ProductName: item.ProductName, Quantity: item.Quantity})
+	}
+	return lines
 }
 
 // GetOfflineOrderByID retrieves an offline order with authorization check
@@ -547,6 +612,25 @@ func (s *OfflineOrderService) RecordPayment(ctx context.Context, req *RecordPaym
 		if err != nil {
 			return nil, fmt.Errorf("failed to update order status: %w", err)
 		}
+		if _, err := s.orderItemRepo.AssignQueueNumber(ctx, tx, req.TenantID, req.OrderID); err != nil {
+			return nil, fmt.Errorf("failed to assign queue number: %w", err)
+		}
+
+		// Convert the order's reservation; a hold that lapsed before payment is allocated anyway
+		items, err := s.orderItemRepo.GetOrderItemsByOrderID(ctx, req.OrderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order items: %w", err)
+		}
+		shortfalls, err := s.inventoryService.FulfilOrder(ctx, tx, req.TenantID, req.OrderID, orderItemReservationLines(items))
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate stock: %w", err)
+		}
+		if len(shortfalls) > 0 {
+			log.Warn().
+				Str("order_id", req.OrderID).
+				Int("oversold_items", len(shortfalls)).
+				Msg("Paid offline order exceeded available stock")
+		}
 	}
 
 	// T062: Publish payment.received event
@@ -710,6 +794,15 @@ func (s *OfflineOrderService) UpdateOfflineOrder(ctx context.Context, req *Updat
 		if err != nil {
 			return nil, fmt.Errorf("failed to update order items: %w", err)
 		}
+
+		// Give back what the old items held, then hold stock for the new ones the same way
+		allocated, err := s.inventoryService.ReleaseOrderStock(ctx, tx, req.OrderID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to release order stock: %w", err)
+		}
+		if err := s.holdOfflineOrderStock(ctx, tx, existingOrder, allocated, inputItemReservationLines(req.ModelUpdates.Items)); err != nil {
+			return nil, err
+		}
 		
 		// Add totals to change log
 		changes["subtotal_amount"] = map[string]interface{}{
@@ -909,6 +1002,13 @@ func (s *OfflineOrderService) DeleteOfflineOrder(ctx context.Context, req *Delet
 		return fmt.Errorf("failed to delete offline order: %w", err)
 	}
 
+	// Return any held or allocated stock; a removed order never completes its sale
+	if _, err := s.inventoryService.ReleaseOrderStock(ctx, tx, req.OrderID); err != nil {
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("error.type", "stock_release_failed"))
+		return fmt.Errorf("failed to release order stock: %w", err)
+	}
+
 	// Publish deletion event to audit trail (T093)
 	eventPayload := map[string]interface{}{
 		"order_id":           req.OrderID,
@@ -973,3 +1073,367 @@ func (s *OfflineOrderService) DeleteOfflineOrder(ctx context.Context, req *Delet
 
 	return nil
 }
+
+// MaxOfflineBatchSize caps how many queued orders a terminal may sync in one request
+const MaxOfflineBatchSize = 100
+
+// Per-order outcomes reported back to the syncing terminal
+const (
+	OfflineBatchStatusAccepted  = "accepted"
+	OfflineBatchStatusDuplicate = "duplicate"
+	OfflineBatchStatusRejected  = "rejected"
+)
+
+// OfflineBatchOrder is a single order captured while the terminal was offline
+// ClientOrderID is generated on the device and makes re-sending the same order idempotent
+type OfflineBatchOrder struct {
+	ClientOrderID    string                      `json:"client_order_id" validate:"required,uuid"`
+	CreatedAt        time.Time                   `json:"created_at" validate:"required"`
+	CustomerName     string                      `json:"customer_name" validate:"required,min=2,max=255"`
+	CustomerPhone    string                      `json:"customer_phone" validate:"required,min=10,max=20"`
+	CustomerEmail    *string                     `json:"customer_email,omitempty" validate:"omitempty,email"`
+	DeliveryType     models.DeliveryType         `json:"delivery_type" validate:"required,oneof=pickup delivery dine_in"`
+	TableNumber      *string                     `json:"table_number,omitempty"`
+	Notes            *string                     `json:"notes,omitempty"`
+	Items            []models.CreateOrderItemReq `json:"items" validate:"required,min=1,dive"`
+	DataConsentGiven bool                        `json:"data_consent_given" validate:"required"`
+	ConsentMethod    *models.ConsentMethod       `json:"consent_method" validate:"required_if=DataConsentGiven true"`
+	PaymentInfo      *PaymentInfo                `json:"payment,omitempty"`
+}
+
+// IngestOfflineBatchRequest represents a batch of queued offline orders
+type IngestOfflineBatchRequest struct {
+	TenantID         string              `json:"-"`
+	RecordedByUserID string              `json:"-"`
+	Orders           []OfflineBatchOrder `json:"orders" validate:"required,min=1,max=100,dive"`
+}
+
+// OversoldItem describes a product that was sold offline beyond its available stock
+type OversoldItem struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Requested   int    `json:"requested"`
+	Shortfall   int    `json:"shortfall"`
+}
+
+// OfflineBatchOrderResult is the acceptance status of one order in a batch
+type OfflineBatchOrderResult struct {
+	ClientOrderID  string         `json:"client_order_id"`
+	Status         string         `json:"status"`
+	OrderID        string         `json:"order_id,omitempty"`
+	OrderReference string         `json:"order_reference,omitempty"`
+	Reason         string         `json:"reason,omitempty"`
+	StockOversold  bool           `json:"stock_oversold"`
+	OversoldItems  []OversoldItem `json:"oversold_items,omitempty"`
+}
+
+// IngestOfflineBatchResponse summarises a batch ingestion
+type IngestOfflineBatchResponse struct {
+	Results   []OfflineBatchOrderResult `json:"results"`
+	Accepted  int                       `json:"accepted"`
+	Duplicate int                       `json:"duplicate"`
+	Rejected  int                       `json:"rejected"`
+}
+
+// ValidateOfflineBatchOrder checks a queued order before it is written
+// Returned errors are reported to the terminal as the rejection reason
+func ValidateOfflineBatchOrder(order *OfflineBatchOrder, now time.Time) error {
+	if _, err := uuid.Parse(order.ClientOrderID); err != nil {
+		return fmt.Errorf("client_order_id must be a valid UUID")
+	}
+	if order.CreatedAt.IsZero() {
+		return fmt.Errorf("created_at is required")
+	}
+	// Allow a little clock drift between the terminal and the server
+	if order.CreatedAt.After(now.Add(5 * time.Minute)) {
+		return fmt.Errorf("created_at cannot be in the future")
+	}
+	if order.CustomerName == "" || order.CustomerPhone == "" {
+		return fmt.Errorf("customer_name and customer_phone are required")
+	}
+	if len(order.Items) == 0 {
+		return fmt.Errorf("order must contain at least one item")
+	}
+	for _, item := range order.Items {
+		if item.ProductID == "" {
+			return fmt.Errorf("product_id is required for every item")
+		}
+		if item.Quantity <= 0 {
+			return fmt.Errorf("quantity for product %s must be greater than 0", item.ProductID)
+		}
+		if item.UnitPrice < 0 {
+			return fmt.Errorf("unit_price for product %s cannot be negative", item.ProductID)
+		}
+	}
+	if !order.DataConsentGiven || order.ConsentMethod == nil {
+		return fmt.Errorf("data consent is required for offline orders (UU PDP compliance)")
+	}
+	if order.PaymentInfo != nil {
+		switch order.PaymentInfo.Type {
+		case "full":
+			if order.PaymentInfo.Amount == nil || order.PaymentInfo.Method == nil {
+				return fmt.Errorf("amount and method are required for full payment")
+			}
+			// The order is recorded as paid in full, so the amount must cover it
+			var total int
+			for _, item := range order.Items {
+				total += item.Quantity * item.UnitPrice
+			}
+			if *order.PaymentInfo.Amount < total {
+				return fmt.Errorf("full payment amount %d is less than the order total %d", *order.PaymentInfo.Amount, total)
+			}
+		default:
+			return fmt.Errorf("only full payments can be synced from offline terminals")
+		}
+	}
+	return nil
+}
+
+// IngestOfflineBatch writes orders queued on a terminal while it was offline
+// Orders are replayed in the order they were captured, each in its own transaction,
+// so one bad order does not block the rest of the batch. Orders already synced
+// (same client_order_id), including by a concurrent sync, are reported as duplicates. Stock is deducted for every
+// accepted order; sales beyond available stock are accepted but flagged as oversold.
+func (s *OfflineOrderService) IngestOfflineBatch(ctx context.Context, req *IngestOfflineBatchRequest) (*IngestOfflineBatchResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "IngestOfflineBatch",
+		trace.WithAttributes(
+			attribute.String("tenant_id", req.TenantID),
+			attribute.Int("batch_size", len(req.Orders)),
+		),
+	)
+	defer span.End()
+
+	if len(req.Orders) == 0 {
+		return nil, fmt.Errorf("batch must contain at least one order")
+	}
+	if len(req.Orders) > MaxOfflineBatchSize {
+		return nil, fmt.Errorf("batch cannot contain more than %d orders", MaxOfflineBatchSize)
+	}
+
+	orders := make([]OfflineBatchOrder, len(req.Orders))
+	copy(orders, req.Orders)
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+
+	response := &IngestOfflineBatchResponse{
+		Results: make([]OfflineBatchOrderResult, 0, len(orders)),
+	}
+
+	now := time.Now()
+	for i := range orders {
+		result := s.ingestOfflineBatchOrder(ctx, req.TenantID, req.RecordedByUserID, &orders[i], now)
+
+		switch result.Status {
+		case OfflineBatchStatusAccepted:
+			response.Accepted++
+		case OfflineBatchStatusDuplicate:
+			response.Duplicate++
+		default:
+			response.Rejected++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	span.SetAttributes(
+		attribute.Int("accepted", response.Accepted),
+		attribute.Int("duplicate", response.Duplicate),
+		attribute.Int("rejected", response.Rejected),
+	)
+
+	log.Info().
+		Str("tenant_id", req.TenantID).
+		Str("recorded_by", req.RecordedByUserID).
+		Int("accepted", response.Accepted).
+		Int("duplicate", response.Duplicate).
+		Int("rejected", response.Rejected).
+		Msg("Offline order batch ingested")
+
+	return response, nil
+}
+
+// ingestOfflineBatchOrder writes a single queued order and reports its outcome
+func (s *OfflineOrderService) ingestOfflineBatchOrder(ctx context.Context, tenantID, userID string, entry *OfflineBatchOrder, now time.Time) OfflineBatchOrderResult {
+	result := OfflineBatchOrderResult{ClientOrderID: entry.ClientOrderID}
+
+	reject := func(reason string) OfflineBatchOrderResult {
+		result.Status = OfflineBatchStatusRejected
+		result.Reason = reason
+		return result
+	}
+
+	if err := ValidateOfflineBatchOrder(entry, now); err != nil {
+		return reject(err.Error())
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to begin transaction for offline order")
+		return reject("internal error")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	existingID, err := s.offlineOrderRepo.GetOfflineOrderIDByClientOrderID(ctx, tx, tenantID, entry.ClientOrderID)
+	if err != nil {
+		log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to check for duplicate offline order")
+		return reject("internal error")
+	}
+	if existingID != "" {
+		result.Status = OfflineBatchStatusDuplicate
+		result.OrderID = existingID
+		return result
+	}
+
+	var subtotalAmount int
+	for _, item := range entry.Items {
+		subtotalAmount += item.Quantity * item.UnitPrice
+	}
+
+	orderReference, err := s.referenceGenerator.NextForTenant(ctx, tx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to generate order reference for synced offline order")
+		return reject("internal error")
+	}
+
+	clientOrderID := entry.ClientOrderID
+	syncedAt := now
+	order := &models.GuestOrder{
+		TenantID:         tenantID,
+		OrderReference:   orderReference,
+		Status:           models.OrderStatusPending,
+		OrderType:        models.OrderTypeOffline,
+		DeliveryType:     entry.DeliveryType,
+		CustomerName:     entry.CustomerName,
+		CustomerPhone:    entry.CustomerPhone,
+		CustomerEmail:    entry.CustomerEmail,
+		TableNumber:      entry.TableNumber,
+		Notes:            entry.Notes,
+		SubtotalAmount:   subtotalAmount,
+		TotalAmount:      subtotalAmount,
+		DataConsentGiven: entry.DataConsentGiven,
+		ConsentMethod:    entry.ConsentMethod,
+		RecordedByUserID: &userID,
+		ClientOrderID:    &clientOrderID,
+		SyncedAt:         &syncedAt,
+		CreatedAt:        entry.CreatedAt,
+	}
+
+	orderID, err := s.offlineOrderRepo.CreateOfflineOrder(ctx, tx, order)
+	if errors.Is(err, models.ErrClientOrderIDExists) {
+		// A concurrent sync of the same order won the race; report the order it created
+		result.Status = OfflineBatchStatusDuplicate
+		result.OrderID, _ = s.offlineOrderRepo.GetOfflineOrderIDByClientOrderID(ctx, nil, tenantID, entry.ClientOrderID)
+		return result
+	}
+	if err != nil {
+		log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to create synced offline order")
+		return reject("failed to create order")
+	}
+	order.ID = orderID
+
+	insertItemQuery := `
+		INSERT INTO order_items (order_id, product_id, product_name, quantity, unit_price, total_price)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, item := range entry.Items {
+		if _, err := tx.ExecContext(ctx, insertItemQuery, orderID, item.ProductID, item.ProductName,
+			item.Quantity, item.UnitPrice, item.Quantity*item.UnitPrice); err != nil {
+			log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to insert synced order item")
+			return reject("failed to create order items")
+		}
+	}
+
+	if entry.PaymentInfo != nil && entry.PaymentInfo.Type == "full" {
+		paymentRecordReq := &models.CreatePaymentRecordRequest{
+			OrderID:               orderID,
+			PaymentNumber:         0,
+			AmountPaid:            *entry.PaymentInfo.Amount,
+			PaymentMethod:         *entry.PaymentInfo.Method,
+			RemainingBalanceAfter: 0,
+			RecordedByUserID:      userID,
+		}
+		if _, err := s.paymentRepo.RecordPayment(ctx, tx, paymentRecordReq); err != nil {
+			log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to record synced payment")
+			return reject("failed to record payment")
+		}
+
+		// Payment happened at the terminal, so paid_at is the capture time; the sale was
+		// handed over there too, so it takes no queue number
+		if _, err := tx.ExecContext(ctx, "UPDATE guest_orders SET status = $1, paid_at = $2 WHERE id = $3",
+			models.OrderStatusPaid, entry.CreatedAt, orderID); err != nil {
+			log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to mark synced order as paid")
+			return reject("failed to update order status")
+		}
+		order.Status = models.OrderStatusPaid
+	}
+
+	// Reconcile stock: the sale already happened, so never reject for lack of stock
+	shortfalls, err := s.inventoryService.FulfilOrder(ctx, tx, tenantID, orderID, createItemReservationLines(entry.Items))
+	if err != nil {
+		log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to deduct stock for synced order")
+		if errors.Is(err, models.ErrProductUnavailable) {
+			return reject(err.Error())
+		}
+		return reject("failed to deduct stock")
+	}
+	for _, shortfall := range shortfalls {
+		result.OversoldItems = append(result.OversoldItems, OversoldItem{
+			ProductID:   shortfall.ProductID,
+			ProductName: shortfall.ProductName,
+			Requested:   shortfall.Requested,
+			Shortfall:   shortfall.Shortfall,
+		})
+	}
+
+	if len(result.OversoldItems) > 0 {
+		if err := s.offlineOrderRepo.MarkStockOversold(ctx, tx, orderID); err != nil {
+			log.Error().Err(err).Str("order_id", orderID).Msg("Failed to flag oversold order")
+			return reject("internal error")
+		}
+		result.StockOversold = true
+		log.Warn().
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Int("oversold_items", len(result.OversoldItems)).
+			Msg("Synced offline order exceeded available stock")
+	}
+
+	eventPayload := map[string]interface{}{
+		"order_id":            orderID,
+		"order_reference":     order.OrderReference,
+		"client_order_id":     entry.ClientOrderID,
+		"tenant_id":           tenantID,
+		"total_amount":        order.TotalAmount,
+		"status":              order.Status,
+		"recorded_by_user_id": userID,
+		"stock_oversold":      result.StockOversold,
+		"created_at":          entry.CreatedAt.Format(time.RFC3339),
+		"synced_at":           now.Format(time.RFC3339),
+	}
+	eventPayloadJSON, err := json.Marshal(eventPayload)
+	if err != nil {
+		return reject("internal error")
+	}
+	eventReq := &models.CreateEventOutboxRequest{
+		EventType:    "offline_order.synced",
+		EventKey:     orderID,
+		EventPayload: eventPayloadJSON,
+		Topic:        "offline-orders-audit",
+	}
+	if err := s.eventPublisher.CreateEvent(ctx, tx, eventReq); err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to create audit event")
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Str("client_order_id", entry.ClientOrderID).Msg("Failed to commit synced offline order")
+		return reject("internal error")
+	}
+
+	observability.OfflineOrdersTotal.WithLabelValues(string(order.Status), tenantID).Inc()
+	observability.OfflineOrderRevenue.WithLabelValues(tenantID).Add(float64(order.TotalAmount))
+
+	result.Status = OfflineBatchStatusAccepted
+	result.OrderID = orderID
+	result.OrderReference = order.OrderReference
+	return result
+}
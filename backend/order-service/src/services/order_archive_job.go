@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// OrderArchiveJob moves closed orders older than a configured number of months to cold storage
+// Archived orders leave guest_orders and everything that hangs off it, so live queries only
+// see orders within the window; analytics reads them through the reporting views, and the
+// guest order page and the admin archive still find them by reference or ID.
+// A zero window disables the job.
+type OrderArchiveJob struct {
+	db          *sql.DB
+	archiveRepo *repository.OrderArchiveRepository
+	partitions  *OrderPartitionService
+	afterMonths int
+	interval    time.Duration
+	batchSize   int
+	stopChan    chan struct{}
+}
+
+// NewOrderArchiveJob creates the order archive job
+func NewOrderArchiveJob(db *sql.DB, archiveRepo *repository.OrderArchiveRepository, partitions *OrderPartitionService, afterMonths int) *OrderArchiveJob {
+	return &OrderArchiveJob{
+		db:          db,
+		archiveRepo: archiveRepo,
+		partitions:  partitions,
+		afterMonths: afterMonths,
+		interval:    1 * time.Hour, // Run every hour
+		batchSize:   500,           // Orders per run
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins the archive loop; it blocks until stopped
+func (j *OrderArchiveJob) Start(ctx context.Context) {
+	if j.afterMonths <= 0 {
+		log.Info().Msg("Order archive job disabled")
+		return
+	}
+	log.Info().Int("after_months", j.afterMonths).Msg("Starting order archive job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	j.archive(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			j.archive(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping order archive job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping order archive job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the archive job
+func (j *OrderArchiveJob) Stop() {
+	close(j.stopChan)
+}
+
+// ArchiveCutoff returns when orders must have closed by to be archived at now
+func ArchiveCutoff(now time.Time, afterMonths int) time.Time {
+	return now.AddDate(0, -afterMonths, 0)
+}
+
+func (j *OrderArchiveJob) archive(ctx context.Context) {
+	cutoff := ArchiveCutoff(time.Now(), j.afterMonths)
+
+	candidates, err := j.archiveRepo.ListColdStorageCandidates(ctx, cutoff, j.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list orders to archive")
+		return
+	}
+	if len(candidates) == 0 {
+		log.Debug().Msg("No orders to archive")
+		return
+	}
+
+	archivedCount := 0
+	failedCount := 0
+
+	for _, candidate := range candidates {
+		if err := j.partitions.EnsurePartitions(ctx, candidate.CreatedAt); err != nil {
+			log.Error().Err(err).Str("order_id", candidate.OrderID).Msg("Failed to create archive partitions")
+			failedCount++
+			continue
+		}
+
+		archived, err := j.moveToColdStorage(ctx, candidate.OrderID)
+		if err != nil {
+			log.Error().Err(err).Str("order_id", candidate.OrderID).Msg("Failed to archive order")
+			failedCount++
+			continue
+		}
+		if archived {
+			archivedCount++
+		}
+	}
+
+	log.Info().
+		Time("cutoff", cutoff).
+		Int("total", len(candidates)).
+		Int("archived", archivedCount).
+		Int("failed", failedCount).
+		Msg("Completed order archive run")
+}
+
+// moveToColdStorage archives one order in its own transaction
+func (j *OrderArchiveJob) moveToColdStorage(ctx context.Context, orderID string) (bool, error) {
+	tx, err := j.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	archived, err := j.archiveRepo.MoveToColdStorage(ctx, tx, orderID)
+	if err != nil || !archived {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// OrderAutoCompleteJob completes PAID orders that staff never closed, following each
+// tenant's auto-complete rule. Disputed orders are always left open.
+// In dry-run mode the sweep only logs what it would complete.
+type OrderAutoCompleteJob struct {
+	orderService   *OrderService
+	settingsRepo   *repository.OrderSettingsRepository
+	auditPublisher utils.AuditPublisherInterface
+	interval       time.Duration
+	batchSize      int
+	dryRun         bool
+	stopChan       chan struct{}
+}
+
+// NewOrderAutoCompleteJob creates the auto-complete sweeper
+func NewOrderAutoCompleteJob(
+	orderService *OrderService,
+	settingsRepo *repository.OrderSettingsRepository,
+	auditPublisher utils.AuditPublisherInterface,
+	dryRun bool,
+) *OrderAutoCompleteJob {
+	return &OrderAutoCompleteJob{
+		orderService:   orderService,
+		settingsRepo:   settingsRepo,
+		auditPublisher: auditPublisher,
+		interval:       15 * time.Minute, // Run every 15 minutes
+		batchSize:      200,              // Orders per tenant per sweep
+		dryRun:         dryRun,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the sweeper loop; it blocks until stopped
+func (j *OrderAutoCompleteJob) Start(ctx context.Context) {
+	log.Info().Bool("dry_run", j.dryRun).Msg("Starting order auto-complete job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	j.sweep(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			j.sweep(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping order auto-complete job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping order auto-complete job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the sweeper
+func (j *OrderAutoCompleteJob) Stop() {
+	close(j.stopChan)
+}
+
+// Preview reports what the tenant's rule would complete right now, without changing anything
+func (j *OrderAutoCompleteJob) Preview(ctx context.Context, tenantID string) (*models.AutoCompleteReport, error) {
+	settings, err := j.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order settings: %w", err)
+	}
+
+	return j.RunForTenant(ctx, settings, time.Now(), true)
+}
+
+// sweep applies every enabled tenant rule
+func (j *OrderAutoCompleteJob) sweep(ctx context.Context) {
+	log.Debug().Msg("Running order auto-complete sweep")
+
+	tenants, err := j.settingsRepo.ListAutoCompleteEnabled(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list tenants with auto-complete enabled")
+		return
+	}
+
+	for _, settings := range tenants {
+		report, err := j.RunForTenant(ctx, settings, time.Now(), j.dryRun)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", settings.TenantID).Msg("Order auto-complete failed for tenant")
+			continue
+		}
+
+		if len(report.Completed) == 0 && len(report.Excluded) == 0 && report.Failed == 0 {
+			continue
+		}
+
+		log.Info().
+			Str("tenant_id", report.TenantID).
+			Str("mode", string(report.Mode)).
+			Time("cutoff", report.Cutoff).
+			Bool("dry_run", report.DryRun).
+			Int("completed", len(report.Completed)).
+			Int("excluded_disputed", len(report.Excluded)).
+			Int("failed", report.Failed).
+			Msg("Completed order auto-complete sweep for tenant")
+	}
+}
+
+// RunForTenant completes the tenant's PAID orders that are past the rule's cutoff
+func (j *OrderAutoCompleteJob) RunForTenant(ctx context.Context, settings *models.OrderSettings, now time.Time, dryRun bool) (*models.AutoCompleteReport, error) {
+	report := &models.AutoCompleteReport{
+		TenantID:  settings.TenantID,
+		Mode:      settings.AutoCompleteMode,
+		DryRun:    dryRun,
+		Completed: []*models.AutoCompleteCandidate{},
+		Excluded:  []*models.AutoCompleteCandidate{},
+	}
+
+	cutoff, ok := settings.AutoCompleteCutoff(now)
+	if !ok {
+		return report, nil
+	}
+	report.Cutoff = cutoff
+
+	candidates, err := j.orderService.orderRepo.ListAutoCompleteCandidates(ctx, settings.TenantID, cutoff, j.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auto-complete candidates: %w", err)
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Disputed {
+			report.Excluded = append(report.Excluded, candidate)
+			continue
+		}
+
+		if dryRun {
+			report.Completed = append(report.Completed, candidate)
+			continue
+		}
+
+		completed, err := j.orderService.orderRepo.CompletePaidOrder(ctx, candidate.OrderID, now)
+		if err != nil {
+			report.Failed++
+			continue
+		}
+		if !completed {
+			// Completed by staff or disputed since it was listed
+			continue
+		}
+
+		report.Completed = append(report.Completed, candidate)
+		j.orderService.broadcastStatus(ctx, models.OrderStatusEventStatus, candidate.OrderReference, models.OrderStatusComplete, nil)
+		j.recordAutoCompletion(ctx, settings, candidate, cutoff)
+	}
+
+	return report, nil
+}
+
+// recordAutoCompletion leaves an order note and an audit trail for an auto-completed order
+func (j *OrderAutoCompleteJob) recordAutoCompletion(ctx context.Context, settings *models.OrderSettings, candidate *models.AutoCompleteCandidate, cutoff time.Time) {
+	note := fmt.Sprintf("Order automatically completed by the %s auto-complete rule (paid at %s)",
+		settings.AutoCompleteMode, candidate.PaidAt.Format(time.RFC3339))
+	if err := j.orderService.AddOrderNote(ctx, candidate.OrderID, note, "System"); err != nil {
+		log.Warn().Err(err).Str("order_id", candidate.OrderID).Msg("Failed to add auto-complete note")
+	}
+
+	log.Info().
+		Str("tenant_id", settings.TenantID).
+		Str("order_id", candidate.OrderID).
+		Str("order_reference", candidate.OrderReference).
+		Str("mode", string(settings.AutoCompleteMode)).
+		Msg("Order auto-completed")
+
+	if j.auditPublisher == nil {
+		return
+	}
+
+	auditEvent := utils.NewSystemEvent(settings.TenantID, "UPDATE", "guest_order", candidate.OrderID)
+	auditEvent.BeforeValue = map[string]interface{}{"status": models.OrderStatusPaid}
+	auditEvent.AfterValue = map[string]interface{}{"status": models.OrderStatusComplete}
+	auditEvent.Metadata = map[string]interface{}{
+		"trigger":         "auto_complete",
+		"rule":            settings.AutoCompleteMode,
+		"order_reference": candidate.OrderReference,
+		"paid_at":         candidate.PaidAt,
+		"cutoff":          cutoff,
+	}
+
+	auditCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := j.auditPublisher.Publish(auditCtx, auditEvent); err != nil {
+		log.Warn().Err(err).Str("order_id", candidate.OrderID).Msg("Failed to publish auto-complete audit event")
+	}
+}
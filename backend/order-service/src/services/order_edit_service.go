@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// OrderEditService lets staff change the items of an online order before it is paid
+// The order is repriced, its stock holds are swapped for the new items and the pending
+// gateway charge is cancelled and replaced with one for the new total.
+type OrderEditService struct {
+	db               *sql.DB
+	orderRepo        *repository.OrderRepository
+	paymentRepo      *repository.PaymentRepository
+	voucherRepo      *repository.VoucherRepository
+	promotionService *PromotionService
+	inventoryService *InventoryService
+	paymentService   *PaymentService
+	orderService     *OrderService
+}
+
+// NewOrderEditService creates a new order edit service
+func NewOrderEditService(
+	db *sql.DB,
+	orderRepo *repository.OrderRepository,
+	paymentRepo *repository.PaymentRepository,
+	voucherRepo *repository.VoucherRepository,
+	promotionService *PromotionService,
+	inventoryService *InventoryService,
+	paymentService *PaymentService,
+	orderService *OrderService,
+) *OrderEditService {
+	return &OrderEditService{
+		db:               db,
+		orderRepo:        orderRepo,
+		paymentRepo:      paymentRepo,
+		voucherRepo:      voucherRepo,
+		promotionService: promotionService,
+		inventoryService: inventoryService,
+		paymentService:   paymentService,
+		orderService:     orderService,
+	}
+}
+
+// EditOrderRequest is a staff edit of a pending order's items
+type EditOrderRequest struct {
+	OrderID        string
+	TenantID       string
+	EditedByUserID string
+	EditedByName   string
+	Changes        models.EditOrderItemsRequest
+}
+
+// EditOrderResult is the repriced order and the charge the customer now has to pay
+type EditOrderResult struct {
+	Order   *models.GuestOrder         `json:"order"`
+	Items   []models.OrderItem         `json:"items"`
+	Payment *models.PaymentTransaction `json:"payment"`
+}
+
+// EditOrderItems replaces the items of a PENDING online order
+// Promotions are re-applied as of now and the voucher discount is recalculated for the new
+// subtotal. The old charge is cancelled before the new one is created, so a customer can
+// never pay both; if the gateway reports it already paid, the edit is refused.
+func (s *OrderEditService) EditOrderItems(ctx context.Context, req *EditOrderRequest) (*EditOrderResult, error) {
+	if err := req.Changes.Validate(); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, req.OrderID)
+	if err != nil || order == nil || order.TenantID != req.TenantID {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status != models.OrderStatusPending || order.OrderType == models.OrderTypeOffline {
+		return nil, models.ErrOrderNotEditable
+	}
+
+	payment, err := s.paymentRepo.GetPaymentByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment == nil {
+		return nil, models.ErrNoOnlinePayment
+	}
+	if payment.SettledAt != nil {
+		return nil, models.ErrOrderNotEditable
+	}
+
+	previousItems, err := s.orderRepo.GetOrderItemsByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+	previousTotal := order.TotalAmount
+
+	cart := &models.Cart{TenantID: order.TenantID}
+	for _, item := range req.Changes.Items {
+		cart.Items = append(cart.Items, models.CartItem{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TotalPrice:  item.UnitPrice * item.Quantity,
+		})
+	}
+	if err := s.promotionService.PriceCart(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to apply promotions: %w", err)
+	}
+
+	voucherDiscount, err := s.repriceVoucher(ctx, order, cart.GetTotalAfterPromotions())
+	if err != nil {
+		return nil, err
+	}
+	if err := order.Reprice(cart.GetTotal(), cart.PromotionDiscount, voucherDiscount); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := s.paymentRepo.LockOrderForPayment(ctx, tx, order.ID); err != nil {
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+
+	items := make([]models.OrderItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, models.OrderItem{
+			OrderID:     order.ID,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TotalPrice:  item.TotalPrice,
+		})
+	}
+	if err := s.orderRepo.ReplaceOrderItems(ctx, tx, order.ID, items); err != nil {
+		return nil, fmt.Errorf("failed to replace order items: %w", err)
+	}
+	if err := s.orderRepo.UpdateOrderPricing(ctx, tx, order, req.EditedByUserID); err != nil {
+		return nil, fmt.Errorf("failed to update order pricing: %w", err)
+	}
+	if err := s.promotionService.ReplaceForOrder(ctx, tx, order.TenantID, order.ID, cart.Promotions); err != nil {
+		return nil, fmt.Errorf("failed to record order promotions: %w", err)
+	}
+	if order.VoucherCode != nil {
+		if err := s.voucherRepo.UpdateRedemptionDiscount(ctx, tx, order.ID, voucherDiscount); err != nil {
+			return nil, fmt.Errorf("failed to update voucher redemption: %w", err)
+		}
+	}
+
+	// Give back what the old items held, then re-check and reserve stock for the new ones
+	if _, err := s.inventoryService.ReleaseOrderStock(ctx, tx, order.ID); err != nil {
+		return nil, fmt.Errorf("failed to release order stock: %w", err)
+	}
+	// The replacement charge is payable for the tenant's full payment expiry; the stock is held at least as long
+	expiry := s.paymentService.paymentExpiry(ctx, order.TenantID)
+	reservationTTL := s.paymentService.checkoutReservationTTL(ctx, order.TenantID, payment.PaymentMethod)
+	if err := s.inventoryService.Reserve(ctx, tx, order.TenantID, order.ID, cartReservationLines(cart.Items), reservationTTL); err != nil {
+		if IsStockError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	newPayment, err := s.replaceCharge(ctx, tx, order, cart.Items, payment, expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		// The old charge is already cancelled; its cancellation webhook will cancel the order
+		log.Error().
+			Err(err).
+			Str("order_id", order.ID).
+			Str("cancelled_charge", payment.MidtransOrderID).
+			Msg("Failed to commit order edit after cancelling its charge")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	note := fmt.Sprintf("Order edited before payment. Items: %s -> %s. Total: %d -> %d. Charge %s cancelled and replaced by %s.",
+		describeOrderItems(previousItems), describeOrderItems(items), previousTotal, order.TotalAmount,
+		payment.MidtransOrderID, newPayment.MidtransOrderID)
+	if req.Changes.Reason != "" {
+		note += " Reason: " + req.Changes.Reason
+	}
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, req.EditedByName); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add order edit note")
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("edited_by", req.EditedByUserID).
+		Int("previous_total", previousTotal).
+		Int("total", order.TotalAmount).
+		Str("charge", newPayment.MidtransOrderID).
+		Msg("Pending order edited")
+
+	return &EditOrderResult{Order: order, Items: items, Payment: newPayment}, nil
+}
+
+// repriceVoucher recalculates the order's voucher discount for the new post-promotion subtotal
+// The voucher was already redeemed at checkout, so only its minimum subtotal is re-checked.
+// A voucher deleted since keeps its original discount, capped at the new subtotal.
+func (s *OrderEditService) repriceVoucher(ctx context.Context, order *models.GuestOrder, subtotal int) (int, error) {
+	if order.VoucherCode == nil {
+		return 0, nil
+	}
+
+	voucher, err := s.voucherRepo.GetByCode(ctx, order.TenantID, *order.VoucherCode)
+	if errors.Is(err, models.ErrVoucherNotFound) {
+		if order.DiscountAmount > subtotal {
+			return subtotal, nil
+		}
+		return order.DiscountAmount, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get voucher: %w", err)
+	}
+
+	if subtotal < voucher.MinSubtotal {
+		return 0, models.ErrVoucherMinSubtotal
+	}
+	return voucher.CalculateDiscount(subtotal), nil
+}
+
+// replaceCharge cancels the order's pending charge and saves a new one for the repriced total
+// The new charge uses the same payment method through the tenant's current gateway.
+func (s *OrderEditService) replaceCharge(ctx context.Context, tx *sql.Tx, order *models.GuestOrder, items []models.CartItem, previous *models.PaymentTransaction, expiry time.Duration) (*models.PaymentTransaction, error) {
+	previousGateway, err := s.paymentService.gatewayByProvider(previous.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	if err := previousGateway.Cancel(ctx, order.TenantID, previous); err != nil {
+		log.Error().
+			Err(err).
+			Str("order_id", order.ID).
+			Str("charge", previous.MidtransOrderID).
+			Msg("Failed to cancel charge for order edit")
+		if errors.Is(err, models.ErrPaymentNotCancellable) {
+			return nil, models.ErrOrderNotEditable
+		}
+		return nil, fmt.Errorf("failed to cancel previous payment: %w", err)
+	}
+
+	revision, err := s.paymentRepo.CountOrderCharges(ctx, tx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count order charges: %w", err)
+	}
+
+	gateway, err := s.paymentService.gatewayForTenant(ctx, order.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	bank := ""
+	if previous.Bank != nil {
+		bank = *previous.Bank
+	}
+	payment, err := gateway.CreateCharge(ctx, &GatewayChargeRequest{
+		Order:       order,
+		Items:       items,
+		Method:      previous.PaymentMethod,
+		Bank:        bank,
+		ReferenceID: models.RevisedChargeOrderID(order.OrderReference, revision),
+		Expiry:      expiry,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	if err := s.paymentService.SaveCheckoutPayment(ctx, tx, payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// describeOrderItems summarizes items for the audit note, e.g. "2x Latte, 1x Croissant"
+func describeOrderItems(items []models.OrderItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%dx %s", item.Quantity, item.ProductName))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/queue"
+	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// OrderHistoryService lets returning guests list their past orders with a tenant
+// after proving they own the phone number the orders were placed with.
+// Orders are found by the phone's search hash; only hashes are kept in Redis.
+type OrderHistoryService struct {
+	orderRepo            *repository.OrderRepository
+	sessionRepo          *repository.CustomerSessionRepository
+	redisClient          *redis.Client
+	notificationProducer *queue.KafkaProducer
+}
+
+// NewOrderHistoryService creates a new order history service
+func NewOrderHistoryService(
+	orderRepo *repository.OrderRepository,
+	sessionRepo *repository.CustomerSessionRepository,
+	redisClient *redis.Client,
+	notificationProducer *queue.KafkaProducer,
+) *OrderHistoryService {
+	return &OrderHistoryService{
+		orderRepo:            orderRepo,
+		sessionRepo:          sessionRepo,
+		redisClient:          redisClient,
+		notificationProducer: notificationProducer,
+	}
+}
+
+func orderHistoryOTPKey(tenantID, phoneHash string) string {
+	return fmt.Sprintf("order_history:otp:%s:%s", tenantID, phoneHash)
+}
+
+func orderHistoryCooldownKey(tenantID, phoneHash string) string {
+	return fmt.Sprintf("order_history:otp_cooldown:%s:%s", tenantID, phoneHash)
+}
+
+// otpCodeHash binds a code to the tenant and phone it was sent for
+func otpCodeHash(tenantID, phoneHash, code string) string {
+	hash := sha256.Sum256([]byte(tenantID + ":" + phoneHash + ":" + code))
+	return hex.EncodeToString(hash[:])
+}
+
+// RequestOTP sends a verification code to a phone number that has orders with the tenant
+// Numbers without orders get no code but the same response, so the endpoint does not
+// reveal who has ordered.
+func (s *OrderHistoryService) RequestOTP(ctx context.Context, tenantID string, req *models.OrderHistoryOTPRequest) error {
+	phone, err := req.Validate()
+	if err != nil {
+		return err
+	}
+	phoneHash := utils.HashForSearch(phone)
+
+	allowed, err := s.redisClient.SetNX(ctx, orderHistoryCooldownKey(tenantID, phoneHash), 1, models.OrderHistoryOTPCooldown).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check code cooldown: %w", err)
+	}
+	if !allowed {
+		return models.ErrOrderHistoryOTPCooldown
+	}
+
+	hasOrders, err := s.orderRepo.HasOrdersByPhoneHash(ctx, tenantID, phoneHash)
+	if err != nil {
+		return fmt.Errorf("failed to look up orders: %w", err)
+	}
+	if !hasOrders {
+		return nil
+	}
+
+	code, err := utils.GenerateOTPCode(models.OrderHistoryOTPLength)
+	if err != nil {
+		return err
+	}
+
+	otpKey := orderHistoryOTPKey(tenantID, phoneHash)
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, otpKey, "code_hash", otpCodeHash(tenantID, phoneHash, code), "attempts", 0)
+		pipe.Expire(ctx, otpKey, models.OrderHistoryOTPTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store verification code: %w", err)
+	}
+
+	s.publishOTPEvent(ctx, tenantID, phone, req.Channel, code)
+	return nil
+}
+
+// VerifyOTP checks a verification code and opens a customer session
+func (s *OrderHistoryService) VerifyOTP(ctx context.Context, tenantID string, req *models.OrderHistoryVerifyRequest) (*models.CustomerSession, error) {
+	phone, err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+	phoneHash := utils.HashForSearch(phone)
+	otpKey := orderHistoryOTPKey(tenantID, phoneHash)
+
+	storedHash, err := s.redisClient.HGet(ctx, otpKey, "code_hash").Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, models.ErrOrderHistoryOTPInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get verification code: %w", err)
+	}
+
+	if !hmac.Equal([]byte(storedHash), []byte(otpCodeHash(tenantID, phoneHash, req.Code))) {
+		attempts, err := s.redisClient.HIncrBy(ctx, otpKey, "attempts", 1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count verification attempt: %w", err)
+		}
+		if attempts >= models.OrderHistoryOTPMaxAttempt {
+			s.redisClient.Del(ctx, otpKey)
+		}
+		return nil, models.ErrOrderHistoryOTPInvalid
+	}
+
+	// Codes are single use; a concurrent request that deleted it first wins
+	deleted, err := s.redisClient.Del(ctx, otpKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume verification code: %w", err)
+	}
+	if deleted == 0 {
+		return nil, models.ErrOrderHistoryOTPInvalid
+	}
+
+	return s.sessionRepo.Create(ctx, tenantID, phoneHash)
+}
+
+// ListOrders returns the orders of the guest a customer session was opened for
+func (s *OrderHistoryService) ListOrders(ctx context.Context, tenantID, token string, limit, offset int) ([]*models.OrderHistoryEntry, error) {
+	phoneHash, err := s.sessionRepo.Resolve(ctx, tenantID, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.orderRepo.ListOrderHistory(ctx, tenantID, phoneHash, limit, offset)
+}
+
+// publishOTPEvent asks the notification service to send the code by SMS or WhatsApp
+func (s *OrderHistoryService) publishOTPEvent(ctx context.Context, tenantID, phone string, channel models.OTPChannel, code string) {
+	if s.notificationProducer == nil {
+		log.Warn().Msg("Kafka producer not initialized, skipping order history code")
+		return
+	}
+
+	event := map[string]interface{}{
+		"event_type": "guest.order_history_otp",
+		"tenant_id":  tenantID,
+		"user_id":    "", // Empty for guests
+		"data": map[string]interface{}{
+			"phone":              phone,
+			"channel":            channel,
+			"code":               code,
+			"expires_in_minutes": int(models.OrderHistoryOTPTTL / time.Minute),
+		},
+	}
+
+	if err := s.notificationProducer.Publish(ctx, tenantID, event); err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to publish order history code event")
+	}
+}
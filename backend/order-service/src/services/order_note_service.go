@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// OrderNoteService adds staff notes with photo attachments and reads them back with viewable URLs
+// storage is nil when no object storage is configured; notes then cannot carry attachments.
+type OrderNoteService struct {
+	orderRepo *repository.OrderRepository
+	storage   *AttachmentStorage
+}
+
+// NewOrderNoteService creates a new order note service
+func NewOrderNoteService(orderRepo *repository.OrderRepository, storage *AttachmentStorage) *OrderNoteService {
+	return &OrderNoteService{
+		orderRepo: orderRepo,
+		storage:   storage,
+	}
+}
+
+// AddNote adds a note with attachments to an order
+// Files are uploaded before the note is saved; if saving fails they are removed again.
+func (s *OrderNoteService) AddNote(ctx context.Context, order *models.GuestOrder, text, userName string, uploads []models.NoteAttachmentUpload) (*models.OrderNote, error) {
+	if err := models.ValidateNoteAttachments(uploads); err != nil {
+		return nil, err
+	}
+	if len(uploads) > 0 && s.storage == nil {
+		return nil, models.ErrNoteAttachmentsNotAvailable
+	}
+
+	createdByName := userName
+	if createdByName == "" {
+		createdByName = "Admin"
+	}
+	note := &models.OrderNote{
+		OrderID:       order.ID,
+		Note:          text,
+		CreatedByName: &createdByName,
+	}
+
+	for _, upload := range uploads {
+		attachment := models.OrderNoteAttachment{
+			ID:          uuid.NewString(),
+			Filename:    upload.Filename,
+			ContentType: upload.ContentType,
+			SizeBytes:   upload.Size,
+		}
+		attachment.StorageKey = models.NoteAttachmentStorageKey(order.TenantID, order.ID, attachment.ID, upload.ContentType)
+		if err := s.storage.Upload(ctx, attachment.StorageKey, upload.Content, upload.Size, upload.ContentType); err != nil {
+			s.removeUploaded(ctx, note.Attachments)
+			return nil, err
+		}
+		note.Attachments = append(note.Attachments, attachment)
+	}
+
+	if err := s.orderRepo.CreateOrderNoteWithAttachments(ctx, note); err != nil {
+		s.removeUploaded(ctx, note.Attachments)
+		return nil, fmt.Errorf("failed to create order note: %w", err)
+	}
+
+	s.presign(ctx, note.Attachments)
+	log.Info().
+		Str("order_id", order.ID).
+		Str("note_id", note.ID).
+		Int("attachment_count", len(note.Attachments)).
+		Msg("Note added to order")
+	return note, nil
+}
+
+// GetNotes returns an order's notes, newest first, with presigned attachment URLs
+func (s *OrderNoteService) GetNotes(ctx context.Context, orderID string) ([]*models.OrderNote, error) {
+	notes, err := s.orderRepo.GetOrderNotesByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order notes: %w", err)
+	}
+	attachments, err := s.orderRepo.GetOrderNoteAttachments(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order note attachments: %w", err)
+	}
+	s.presign(ctx, attachments)
+
+	byNote := make(map[string][]models.OrderNoteAttachment, len(attachments))
+	for _, attachment := range attachments {
+		byNote[attachment.NoteID] = append(byNote[attachment.NoteID], attachment)
+	}
+	for _, note := range notes {
+		note.Attachments = byNote[note.ID]
+	}
+	return notes, nil
+}
+
+// presign fills in viewable URLs; an attachment whose URL cannot be signed is listed without one
+func (s *OrderNoteService) presign(ctx context.Context, attachments []models.OrderNoteAttachment) {
+	if s.storage == nil {
+		return
+	}
+	for i := range attachments {
+		url, err := s.storage.PresignedURL(ctx, attachments[i].StorageKey)
+		if err != nil {
+			log.Warn().Err(err).Str("attachment_id", attachments[i].ID).Msg("Failed to presign order note attachment")
+			continue
+		}
+		attachments[i].URL = url
+	}
+}
+
+// removeUploaded deletes the files of a note that could not be saved
+func (s *OrderNoteService) removeUploaded(ctx context.Context, attachments []models.OrderNoteAttachment) {
+	for _, attachment := range attachments {
+		if err := s.storage.Delete(ctx, attachment.StorageKey); err != nil {
+			log.Warn().Err(err).Str("storage_key", attachment.StorageKey).Msg("Failed to remove orphaned order note attachment")
+		}
+	}
+}
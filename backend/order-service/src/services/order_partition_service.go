@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// archivePartitionedTables are the cold storage tables, partitioned monthly by the order's created_at
+var archivePartitionedTables = []string{
+	"archived_guest_orders",
+	"archived_order_items",
+	"archived_payment_transactions",
+}
+
+// OrderPartitionService manages the monthly partitions of the order archive tables
+// Orders are archived long after they were placed, so partitions are created on demand
+// for the months being archived rather than ahead of time.
+type OrderPartitionService struct {
+	db      *sql.DB
+	ensured map[string]bool // Months whose partitions are known to exist
+}
+
+// NewOrderPartitionService creates a new order partition service
+func NewOrderPartitionService(db *sql.DB) *OrderPartitionService {
+	return &OrderPartitionService{db: db, ensured: make(map[string]bool)}
+}
+
+// EnsurePartitions makes sure every archive table has a partition for the month
+func (s *OrderPartitionService) EnsurePartitions(ctx context.Context, month time.Time) error {
+	suffix := month.UTC().Format("2006_01")
+	if s.ensured[suffix] {
+		return nil
+	}
+
+	for _, table := range archivePartitionedTables {
+		partitionName, _, _ := ArchivePartition(table, month)
+
+		exists, err := s.PartitionExists(ctx, partitionName)
+		if err != nil {
+			return fmt.Errorf("failed to check partition existence: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		if err := s.CreatePartition(ctx, table, month); err != nil {
+			return fmt.Errorf("failed to create partition %s: %w", partitionName, err)
+		}
+		log.Info().Str("partition", partitionName).Msg("Created monthly archive partition")
+	}
+
+	s.ensured[suffix] = true
+	return nil
+}
+
+// PartitionExists checks if a partition table exists
+func (s *OrderPartitionService) PartitionExists(ctx context.Context, partitionName string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.relname = $1
+			AND n.nspname = 'public'
+		)
+	`
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, query, partitionName).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to query partition existence: %w", err)
+	}
+	return exists, nil
+}
+
+// ArchivePartition returns the name and range of the archive table's partition holding the month
+// Partition range: [start_of_month, start_of_next_month), in UTC.
+func ArchivePartition(table string, month time.Time) (string, time.Time, time.Time) {
+	month = month.UTC()
+	startOfMonth := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	startOfNextMonth := startOfMonth.AddDate(0, 1, 0)
+	return fmt.Sprintf("%s_%s", table, month.Format("2006_01")), startOfMonth, startOfNextMonth
+}
+
+// CreatePartition creates a monthly partition of an archive table
+// Indexes declared on the parent table are created on the partition by PostgreSQL.
+func (s *OrderPartitionService) CreatePartition(ctx context.Context, table string, month time.Time) error {
+	partitionName, startOfMonth, startOfNextMonth := ArchivePartition(table, month)
+
+	// The archive columns are TIMESTAMP without time zone, so the bounds are written without one
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s PARTITION OF %s
+		FOR VALUES FROM ('%s') TO ('%s')
+	`, partitionName, table, startOfMonth.Format("2006-01-02"), startOfNextMonth.Format("2006-01-02"))
+
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create partition table: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+// maxOrderReferenceAttempts bounds the sequence numbers tried when a patterned reference is taken
+// That only happens when a tenant reuses a prefix another tenant released on the same day.
+const maxOrderReferenceAttempts = 5
+
+// OrderReferenceGenerator creates order references from the tenant's pattern or the global generator
+type OrderReferenceGenerator struct {
+	referenceRepo *repository.OrderReferenceRepository
+	settingsRepo  *repository.OrderSettingsRepository
+}
+
+// NewOrderReferenceGenerator creates a new order reference generator
+func NewOrderReferenceGenerator(referenceRepo *repository.OrderReferenceRepository, settingsRepo *repository.OrderSettingsRepository) *OrderReferenceGenerator {
+	return &OrderReferenceGenerator{
+		referenceRepo: referenceRepo,
+		settingsRepo:  settingsRepo,
+	}
+}
+
+// Next returns the reference for a new order of the tenant, within the tx that inserts the order
+// Without a pattern the reference is a random GO-XXXXXX; with one, the day is the tenant's local day.
+func (g *OrderReferenceGenerator) Next(ctx context.Context, tx *sql.Tx, settings *models.OrderSettings) (string, error) {
+	if settings == nil || settings.OrderReferencePattern == nil {
+		return utils.GenerateOrderReference()
+	}
+
+	day := time.Now().In(settings.Location())
+	for attempt := 0; attempt < maxOrderReferenceAttempts; attempt++ {
+		sequence, err := g.referenceRepo.NextSequence(ctx, tx, settings.TenantID, day)
+		if err != nil {
+			return "", fmt.Errorf("failed to get order reference sequence: %w", err)
+		}
+
+		reference := models.FormatOrderReference(*settings.OrderReferencePattern, day, sequence)
+		exists, err := g.referenceRepo.Exists(ctx, tx, reference)
+		if err != nil {
+			return "", fmt.Errorf("failed to check order reference: %w", err)
+		}
+		if !exists {
+			return reference, nil
+		}
+	}
+	return "", fmt.Errorf("no free order reference after %d attempts", maxOrderReferenceAttempts)
+}
+
+// NextForTenant loads the tenant's settings and returns the reference for its new order
+func (g *OrderReferenceGenerator) NextForTenant(ctx context.Context, tx *sql.Tx, tenantID string) (string, error) {
+	settings, err := g.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get order settings: %w", err)
+	}
+	return g.Next(ctx, tx, settings)
+}
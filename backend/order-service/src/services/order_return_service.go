@@ -0,0 +1,468 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// maxCodeAttempts bounds retries when a generated return number or credit code is taken
+const maxCodeAttempts = 5
+
+// OrderReturnService runs the returns (RMA) workflow for retail tenants
+// A return is requested against an order's items, approved or rejected by a manager,
+// received (restocking everything that is not damaged) and finally completed by issuing
+// its resolution: a gateway refund, an exchange order or store credit.
+type OrderReturnService struct {
+	db                  *sql.DB
+	returnRepo          *repository.OrderReturnRepository
+	orderRepo           *repository.OrderRepository
+	reservationRepo     *repository.ReservationRepository
+	voucherRepo         *repository.VoucherRepository
+	refundService       *RefundApprovalService
+	offlineOrderService *OfflineOrderService
+	orderService        *OrderService
+}
+
+// NewOrderReturnService creates a new order return service
+func NewOrderReturnService(
+	db *sql.DB,
+	returnRepo *repository.OrderReturnRepository,
+	orderRepo *repository.OrderRepository,
+	reservationRepo *repository.ReservationRepository,
+	voucherRepo *repository.VoucherRepository,
+	refundService *RefundApprovalService,
+	offlineOrderService *OfflineOrderService,
+	orderService *OrderService,
+) *OrderReturnService {
+	return &OrderReturnService{
+		db:                  db,
+		returnRepo:          returnRepo,
+		orderRepo:           orderRepo,
+		reservationRepo:     reservationRepo,
+		voucherRepo:         voucherRepo,
+		refundService:       refundService,
+		offlineOrderService: offlineOrderService,
+		orderService:        orderService,
+	}
+}
+
+// ReturnActor is the staff member moving a return along
+type ReturnActor struct {
+	UserID string
+	Name   string
+	Role   string // Refunds above the approval threshold need an owner
+}
+
+// generateReturnNumber returns a random RMA-XXXXXX return number
+func generateReturnNumber() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("RMA-%06d", n.Int64()), nil
+}
+
+// generateStoreCreditCode returns a random voucher code for store credit
+func generateStoreCreditCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return models.StoreCreditCodePrefix + strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// CreateReturn opens a return against a PAID or COMPLETE order's items
+// The credit is the items' order price, capped at what earlier returns left of the order total.
+func (s *OrderReturnService) CreateReturn(ctx context.Context, tenantID, orderID string, req *models.CreateReturnRequest, actor ReturnActor) (*models.OrderReturn, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status != models.OrderStatusPaid && order.Status != models.OrderStatusComplete {
+		return nil, models.ErrOrderNotReturnable
+	}
+
+	orderItems, err := s.orderRepo.GetOrderItemsByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := s.returnRepo.LockOrder(ctx, tx, tenantID, order.ID); err != nil {
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+	returned, err := s.returnRepo.GetReturnedQuantities(ctx, tx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get returned quantities: %w", err)
+	}
+	credited, err := s.returnRepo.GetCreditedAmount(ctx, tx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credited amount: %w", err)
+	}
+
+	items, value, err := models.BuildReturnItems(orderItems, returned, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	credit := models.ReturnCredit(value, order.TotalAmount, credited)
+	if credit == 0 {
+		return nil, models.ErrReturnNothingToCredit
+	}
+
+	ret := &models.OrderReturn{
+		TenantID:       tenantID,
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Status:         models.ReturnStatusRequested,
+		Resolution:     req.Resolution,
+		Reason:         req.Reason,
+		CreditAmount:   credit,
+		RequestedBy:    actorID(actor),
+		Items:          items,
+	}
+
+	created := false
+	for attempt := 0; attempt < maxCodeAttempts && !created; attempt++ {
+		if ret.ReturnNumber, err = generateReturnNumber(); err != nil {
+			return nil, fmt.Errorf("failed to generate return number: %w", err)
+		}
+		if created, err = s.returnRepo.Create(ctx, tx, ret); err != nil {
+			return nil, fmt.Errorf("failed to create return: %w", err)
+		}
+	}
+	if !created {
+		return nil, fmt.Errorf("failed to create return: no free return number after %d attempts", maxCodeAttempts)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.addOrderNote(ctx, ret, fmt.Sprintf("Return %s requested (%s, credit %d)", ret.ReturnNumber, ret.Resolution, ret.CreditAmount), actor)
+
+	log.Info().
+		Str("return_id", ret.ID).
+		Str("return_number", ret.ReturnNumber).
+		Str("order_id", order.ID).
+		Str("resolution", string(ret.Resolution)).
+		Int("credit_amount", credit).
+		Msg("Order return requested")
+
+	return ret, nil
+}
+
+// GetReturn returns one of a tenant's returns
+func (s *OrderReturnService) GetReturn(ctx context.Context, tenantID, returnID string) (*models.OrderReturn, error) {
+	return s.returnRepo.Get(ctx, nil, tenantID, returnID)
+}
+
+// ListOrderReturns returns an order's returns
+func (s *OrderReturnService) ListOrderReturns(ctx context.Context, tenantID, orderID string) ([]*models.OrderReturn, error) {
+	return s.returnRepo.ListByOrder(ctx, tenantID, orderID)
+}
+
+// ListReturns returns a tenant's returns, optionally by status
+func (s *OrderReturnService) ListReturns(ctx context.Context, tenantID string, status *models.ReturnStatus, limit, offset int) ([]*models.OrderReturn, error) {
+	return s.returnRepo.List(ctx, tenantID, status, limit, offset)
+}
+
+// ApproveReturn lets the customer bring the goods back
+func (s *OrderReturnService) ApproveReturn(ctx context.Context, tenantID, returnID string, actor ReturnActor) (*models.OrderReturn, error) {
+	return s.transition(ctx, tenantID, returnID, models.ReturnStatusApproved, actor, func(tx *sql.Tx, ret *models.OrderReturn, now time.Time) error {
+		ret.ApprovedAt = &now
+		ret.ApprovedBy = actorID(actor)
+		return nil
+	})
+}
+
+// RejectReturn declines a return; its items and credit become available to other returns again
+func (s *OrderReturnService) RejectReturn(ctx context.Context, tenantID, returnID string, req *models.RejectReturnRequest, actor ReturnActor) (*models.OrderReturn, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || len(reason) > 500 {
+		return nil, fmt.Errorf("%w: reason is required and must be at most 500 characters", models.ErrInvalidReturn)
+	}
+
+	return s.transition(ctx, tenantID, returnID, models.ReturnStatusRejected, actor, func(tx *sql.Tx, ret *models.OrderReturn, now time.Time) error {
+		ret.RejectedAt = &now
+		ret.RejectionReason = &reason
+		return nil
+	})
+}
+
+// ReceiveReturn records that the goods are back and restocks all but the damaged items
+func (s *OrderReturnService) ReceiveReturn(ctx context.Context, tenantID, returnID string, req *models.ReceiveReturnRequest, actor ReturnActor) (*models.OrderReturn, error) {
+	damaged := make(map[string]bool, len(req.DamagedItemIDs))
+	for _, id := range req.DamagedItemIDs {
+		damaged[id] = true
+	}
+
+	return s.transition(ctx, tenantID, returnID, models.ReturnStatusReceived, actor, func(tx *sql.Tx, ret *models.OrderReturn, now time.Time) error {
+		known := 0
+		for i := range ret.Items {
+			item := &ret.Items[i]
+			if damaged[item.ID] {
+				known++
+				continue
+			}
+			if err := s.reservationRepo.RestockProduct(ctx, tx, item.ProductID, item.Quantity); err != nil {
+				return fmt.Errorf("failed to restock product %s: %w", item.ProductID, err)
+			}
+			if err := s.returnRepo.MarkItemRestocked(ctx, tx, item.ID); err != nil {
+				return fmt.Errorf("failed to mark return item restocked: %w", err)
+			}
+			item.Restocked = true
+		}
+		if known != len(damaged) {
+			return fmt.Errorf("%w: damaged_item_ids must be items of this return", models.ErrInvalidReturn)
+		}
+
+		ret.ReceivedAt = &now
+		ret.ReceivedBy = actorID(actor)
+		return nil
+	})
+}
+
+// CompleteReturn issues a received return's resolution
+// The return stays locked while the refund, exchange order or voucher is issued, so two
+// staff completing it at once cannot credit the customer twice.
+func (s *OrderReturnService) CompleteReturn(ctx context.Context, tenantID, returnID string, req *models.CompleteReturnRequest, actor ReturnActor) (*models.OrderReturn, error) {
+	return s.transition(ctx, tenantID, returnID, models.ReturnStatusCompleted, actor, func(tx *sql.Tx, ret *models.OrderReturn, now time.Time) error {
+		var err error
+		switch ret.Resolution {
+		case models.ReturnResolutionRefund:
+			err = s.issueRefund(ctx, ret, actor)
+		case models.ReturnResolutionExchange:
+			err = s.issueExchange(ctx, ret, req.ExchangeItems, actor)
+		case models.ReturnResolutionStoreCredit:
+			err = s.issueStoreCredit(ctx, ret, ret.CreditAmount)
+		}
+		if err != nil {
+			return err
+		}
+
+		ret.CompletedAt = &now
+		ret.CompletedBy = actorID(actor)
+		return nil
+	})
+}
+
+// transition moves a locked return to a new status after apply has updated it
+func (s *OrderReturnService) transition(
+	ctx context.Context,
+	tenantID, returnID string,
+	to models.ReturnStatus,
+	actor ReturnActor,
+	apply func(tx *sql.Tx, ret *models.OrderReturn, now time.Time) error,
+) (*models.OrderReturn, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	ret, err := s.returnRepo.Get(ctx, tx, tenantID, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if !ret.CanTransition(to) {
+		return nil, fmt.Errorf("%w: %s to %s", models.ErrReturnStatusConflict, ret.Status, to)
+	}
+
+	if err := apply(tx, ret, time.Now()); err != nil {
+		return nil, err
+	}
+	ret.Status = to
+
+	if err := s.returnRepo.Update(ctx, tx, ret); err != nil {
+		return nil, fmt.Errorf("failed to update return: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		if to == models.ReturnStatusCompleted {
+			log.Error().Err(err).
+				Str("return_id", ret.ID).
+				Str("resolution", string(ret.Resolution)).
+				Msg("Return resolution was issued but the return could not be marked completed")
+		}
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.addOrderNote(ctx, ret, returnNote(ret), actor)
+
+	log.Info().
+		Str("return_id", ret.ID).
+		Str("return_number", ret.ReturnNumber).
+		Str("status", string(ret.Status)).
+		Str("by", actor.UserID).
+		Msg("Order return updated")
+
+	return ret, nil
+}
+
+// issueRefund refunds the credit through the gateway that took the order's payment
+// The actor must be allowed to approve a refund of that size themselves.
+func (s *OrderReturnService) issueRefund(ctx context.Context, ret *models.OrderReturn, actor ReturnActor) error {
+	refund, err := s.refundService.RefundNow(ctx, &RefundRequest{
+		OrderID:  ret.OrderID,
+		TenantID: ret.TenantID,
+		Amount:   ret.CreditAmount,
+		Reason:   "Return " + ret.ReturnNumber,
+	}, RefundActor{UserID: actor.UserID, Name: actor.Name, Role: actor.Role})
+	if err != nil {
+		return err
+	}
+	ret.RefundID = &refund.RefundID
+	return nil
+}
+
+// issueExchange records the replacement goods as a paid offline order for the same customer
+// Whatever credit the exchange leaves over is issued as store credit.
+func (s *OrderReturnService) issueExchange(ctx context.Context, ret *models.OrderReturn, items []models.CreateOrderItemReq, actor ReturnActor) error {
+	exchangeTotal, remainder, err := models.SplitExchangeCredit(ret.CreditAmount, items)
+	if err != nil {
+		return err
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, ret.OrderID)
+	if err != nil || order == nil {
+		return ErrOrderNotFound
+	}
+
+	consentMethod := models.ConsentMethodDigital // Guests consent at online checkout
+	if order.ConsentMethod != nil {
+		consentMethod = *order.ConsentMethod
+	}
+	deliveryType := order.DeliveryType
+	if deliveryType == models.DeliveryTypeDelivery {
+		deliveryType = models.DeliveryTypePickup // Exchanges are handed over at the counter
+	}
+	notes := fmt.Sprintf("Exchange for return %s of order %s", ret.ReturnNumber, ret.OrderReference)
+	method := models.PaymentMethodOther
+
+	exchange, err := s.offlineOrderService.CreateOfflineOrder(ctx, &CreateOfflineOrderRequest{
+		TenantID:         ret.TenantID,
+		CustomerName:     order.CustomerName,
+		CustomerPhone:    order.CustomerPhone,
+		CustomerEmail:    order.CustomerEmail,
+		DeliveryType:     deliveryType,
+		Notes:            &notes,
+		Items:            items,
+		DataConsentGiven: true,
+		ConsentMethod:    &consentMethod,
+		RecordedByUserID: actor.UserID,
+		PaymentInfo: &PaymentInfo{
+			Type:   "full",
+			Amount: &exchangeTotal,
+			Method: &method,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create exchange order: %w", err)
+	}
+	ret.ExchangeOrderID = &exchange.ID
+
+	if remainder > 0 {
+		return s.issueStoreCredit(ctx, ret, remainder)
+	}
+	return nil
+}
+
+// issueStoreCredit creates a single-use fixed voucher worth amount
+// A fixed voucher never discounts more than the order it is used on, so store credit
+// is meant to be spent in one purchase.
+func (s *OrderReturnService) issueStoreCredit(ctx context.Context, ret *models.OrderReturn, amount int) error {
+	description := fmt.Sprintf("Store credit for return %s of order %s", ret.ReturnNumber, ret.OrderReference)
+	usageLimit := 1
+
+	for attempt := 0; attempt < maxCodeAttempts; attempt++ {
+		code, err := generateStoreCreditCode()
+		if err != nil {
+			return fmt.Errorf("failed to generate store credit code: %w", err)
+		}
+
+		voucher, err := s.voucherRepo.Create(ctx, ret.TenantID, &models.VoucherRequest{
+			Code:          code,
+			Description:   &description,
+			DiscountType:  models.DiscountTypeFixed,
+			DiscountValue: amount,
+			UsageLimit:    &usageLimit,
+		})
+		if errors.Is(err, models.ErrVoucherCodeExists) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create store credit voucher: %w", err)
+		}
+
+		ret.StoreCreditVoucherID = &voucher.ID
+		ret.StoreCreditCode = &voucher.Code
+		return nil
+	}
+	return fmt.Errorf("failed to create store credit voucher: no free code after %d attempts", maxCodeAttempts)
+}
+
+// addOrderNote records a return step on the original order; failures are only logged
+func (s *OrderReturnService) addOrderNote(ctx context.Context, ret *models.OrderReturn, note string, actor ReturnActor) {
+	if err := s.orderService.AddOrderNote(ctx, ret.OrderID, note, actor.Name); err != nil {
+		log.Warn().Err(err).Str("return_id", ret.ID).Msg("Failed to add return note to order")
+	}
+}
+
+// returnNote describes the step a return just took
+func returnNote(ret *models.OrderReturn) string {
+	switch ret.Status {
+	case models.ReturnStatusApproved:
+		return fmt.Sprintf("Return %s approved", ret.ReturnNumber)
+	case models.ReturnStatusRejected:
+		return fmt.Sprintf("Return %s rejected: %s", ret.ReturnNumber, *ret.RejectionReason)
+	case models.ReturnStatusReceived:
+		restocked := 0
+		for _, item := range ret.Items {
+			if item.Restocked {
+				restocked++
+			}
+		}
+		return fmt.Sprintf("Return %s received; %d of %d items restocked", ret.ReturnNumber, restocked, len(ret.Items))
+	case models.ReturnStatusCompleted:
+		switch {
+		case ret.RefundID != nil:
+			return fmt.Sprintf("Return %s completed: refunded %d (refund %s)", ret.ReturnNumber, ret.CreditAmount, *ret.RefundID)
+		case ret.ExchangeOrderID != nil && ret.StoreCreditCode != nil:
+			return fmt.Sprintf("Return %s completed: exchanged (order %s), remainder issued as store credit %s", ret.ReturnNumber, *ret.ExchangeOrderID, *ret.StoreCreditCode)
+		case ret.ExchangeOrderID != nil:
+			return fmt.Sprintf("Return %s completed: exchanged (order %s)", ret.ReturnNumber, *ret.ExchangeOrderID)
+		case ret.StoreCreditCode != nil:
+			return fmt.Sprintf("Return %s completed: store credit %s worth %d issued", ret.ReturnNumber, *ret.StoreCreditCode, ret.CreditAmount)
+		}
+	}
+	return fmt.Sprintf("Return %s is now %s", ret.ReturnNumber, ret.Status)
+}
+
+// actorID returns the staff member's user ID, or nil when the gateway did not send one
+func actorID(actor ReturnActor) *string {
+	if actor.UserID == "" {
+		return nil
+	}
+	id := actor.UserID
+	return &id
+}
@@ -3,22 +3,33 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/observability"
 	"github.com/point-of-sale-system/order-service/src/queue"
 	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/utils"
 	"github.com/rs/zerolog/log"
 )
 
 // OrderService handles business logic for order management
 type OrderService struct {
-	db            *sql.DB
-	orderRepo     *repository.OrderRepository
-	addressRepo   *repository.AddressRepository
-	paymentRepo   *repository.PaymentRepository
-	kafkaProducer *queue.KafkaProducer
+	db             *sql.DB
+	orderRepo      *repository.OrderRepository
+	addressRepo    *repository.AddressRepository
+	paymentRepo    *repository.PaymentRepository
+	voucherRepo    *repository.VoucherRepository
+	loyaltyService *LoyaltyService
+	kafkaProducer  *queue.KafkaProducer
+	broadcaster    *OrderStatusBroadcaster
+	staffHub       *StaffOrderHub
+	webhooks       *MerchantWebhookService
+	archiveRepo    *repository.OrderArchiveRepository
+	auditPublisher *utils.AuditPublisher
 }
 
 // NewOrderService creates a new order service
@@ -27,14 +38,28 @@ func NewOrderService(
 	orderRepo *repository.OrderRepository,
 	addressRepo *repository.AddressRepository,
 	paymentRepo *repository.PaymentRepository,
+	voucherRepo *repository.VoucherRepository,
+	loyaltyService *LoyaltyService,
 	kafkaProducer *queue.KafkaProducer,
+	broadcaster *OrderStatusBroadcaster,
+	staffHub *StaffOrderHub,
+	webhooks *MerchantWebhookService,
+	archiveRepo *repository.OrderArchiveRepository,
+	auditPublisher *utils.AuditPublisher,
 ) *OrderService {
 	return &OrderService{
-		db:            db,
-		orderRepo:     orderRepo,
-		addressRepo:   addressRepo,
-		paymentRepo:   paymentRepo,
-		kafkaProducer: kafkaProducer,
+		db:             db,
+		orderRepo:      orderRepo,
+		addressRepo:    addressRepo,
+		paymentRepo:    paymentRepo,
+		voucherRepo:    voucherRepo,
+		loyaltyService: loyaltyService,
+		kafkaProducer:  kafkaProducer,
+		broadcaster:    broadcaster,
+		staffHub:       staffHub,
+		webhooks:       webhooks,
+		archiveRepo:    archiveRepo,
+		auditPublisher: auditPublisher,
 	}
 }
 
@@ -48,14 +73,95 @@ func (s *OrderService) GetOrderByID(ctx context.Context, orderID string) (*model
 	return s.orderRepo.GetOrderByID(ctx, orderID)
 }
 
-// ListOrdersByTenant retrieves orders for a tenant with optional status filter
+// ListOrdersByTenant retrieves orders for a tenant with optional status, anonymization and contact filters
 func (s *OrderService) ListOrdersByTenant(
 	ctx context.Context,
 	tenantID string,
 	status *models.OrderStatus,
+	anonymized models.AnonymizedFilter,
+	contact *models.ContactSearch,
 	limit, offset int,
 ) ([]*models.GuestOrder, error) {
-	return s.orderRepo.ListOrdersByTenant(ctx, tenantID, status, limit, offset)
+	return s.orderRepo.ListOrdersByTenant(ctx, tenantID, status, anonymized, contact, limit, offset)
+}
+
+// GetArchivedOrder returns the archival view of an anonymized order owned by the tenant.
+// Orders moved to cold storage are read from the archive tables.
+// Returns sql.ErrNoRows when the order does not exist or belongs to another tenant.
+func (s *OrderService) GetArchivedOrder(ctx context.Context, tenantID, orderID string) (*models.ArchivedOrder, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.archiveRepo.GetByID(ctx, tenantID, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if order.TenantID != tenantID {
+		return nil, sql.ErrNoRows
+	}
+	if !order.IsAnonymized {
+		return nil, models.ErrOrderNotArchived
+	}
+
+	return s.buildArchivedOrder(ctx, order)
+}
+
+// ListArchivedOrders returns the archival view of a tenant's anonymized orders, newest first
+func (s *OrderService) ListArchivedOrders(ctx context.Context, tenantID string, limit, offset int) ([]*models.ArchivedOrder, error) {
+	orders, err := s.orderRepo.ListOrdersByTenant(ctx, tenantID, nil, models.AnonymizedFilterOnly, nil, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	archived := make([]*models.ArchivedOrder, 0, len(orders))
+	for _, order := range orders {
+		view, err := s.buildArchivedOrder(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		archived = append(archived, view)
+	}
+
+	return archived, nil
+}
+
+func (s *OrderService) buildArchivedOrder(ctx context.Context, order *models.GuestOrder) (*models.ArchivedOrder, error) {
+	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	payments, err := s.paymentRepo.GetPaymentHistory(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment history: %w", err)
+	}
+
+	return models.NewArchivedOrder(order, items, payments)
+}
+
+// AuditStatusChange records in the audit trail that a staff member moved an order to a new status
+// order still holds the status it had before the change.
+func (s *OrderService) AuditStatusChange(ctx context.Context, order *models.GuestOrder, newStatus models.OrderStatus, userID string) {
+	if s.auditPublisher == nil || userID == "" {
+		return
+	}
+
+	auditEvent := utils.NewSystemEvent(order.TenantID, "UPDATE", "guest_order", order.ID)
+	auditEvent.ActorType = "user"
+	auditEvent.ActorID = &userID
+	auditEvent.BeforeValue = map[string]interface{}{"status": order.Status}
+	auditEvent.AfterValue = map[string]interface{}{"status": newStatus}
+	auditEvent.Metadata = map[string]interface{}{
+		"trigger":         "staff",
+		"order_reference": order.OrderReference,
+	}
+
+	auditCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.auditPublisher.Publish(auditCtx, auditEvent); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to publish order status audit event")
+	}
 }
 
 // UpdateOrderStatus updates order status with validation
@@ -111,6 +217,27 @@ func (s *OrderService) UpdateOrderStatus(
 		return fmt.Errorf("failed to update order status: %w", err)
 	}
 
+	// Give back the voucher usage held by a cancelled order
+	if newStatus == models.OrderStatusCancelled && order.Status != models.OrderStatusCancelled && order.VoucherCode != nil && s.voucherRepo != nil {
+		if err := s.voucherRepo.ReleaseForOrder(ctx, tx, orderID); err != nil {
+			return fmt.Errorf("failed to release voucher: %w", err)
+		}
+	}
+
+	// Take back points earned by a cancelled order and refund the points it used
+	if newStatus == models.OrderStatusCancelled && order.Status != models.OrderStatusCancelled && s.loyaltyService != nil {
+		if err := s.loyaltyService.ReverseForOrder(ctx, tx, orderID); err != nil {
+			return fmt.Errorf("failed to reverse loyalty points: %w", err)
+		}
+	}
+
+	// Call the guest by a short daily number once the order is paid
+	if newStatus == models.OrderStatusPaid && order.Status != models.OrderStatusPaid {
+		if _, err := s.orderRepo.AssignQueueNumber(ctx, tx, order.TenantID, orderID); err != nil {
+			return fmt.Errorf("failed to assign queue number: %w", err)
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -123,8 +250,20 @@ func (s *OrderService) UpdateOrderStatus(
 		Str("new_status", string(newStatus)).
 		Msg("Order status updated successfully")
 
+	// Push the transition to guests following the order
+	s.broadcastStatus(ctx, models.OrderStatusEventStatus, order.OrderReference, newStatus, nil)
+
+	// Tell the merchant's own systems about the cancellation
+	if newStatus == models.OrderStatusCancelled && order.Status != models.OrderStatusCancelled {
+		order.Status = newStatus
+		order.CancelledAt = cancelledAt
+		s.webhooks.Publish(ctx, models.WebhookEventOrderCancelled, order, nil)
+	}
+
 	// Publish order.paid event to Kafka if status changed to PAID
 	if newStatus == models.OrderStatusPaid {
+		newlyPaid := order.Status != models.OrderStatusPaid
+
 		// Reload order to get the updated timestamps and ensure all fields are fresh
 		updatedOrder, err := s.orderRepo.GetOrderByID(ctx, orderID)
 		if err != nil {
@@ -138,6 +277,17 @@ func (s *OrderService) UpdateOrderStatus(
 			updatedOrder.Status = newStatus
 		}
 
+		// Accrue loyalty points; a failure here must not undo the payment
+		if newlyPaid {
+			s.accrueLoyaltyPoints(ctx, updatedOrder)
+		}
+
+		// Let staff dashboards and the merchant's webhooks know without waiting for their next list refresh
+		if newlyPaid {
+			s.staffHub.Publish(ctx, models.NewStaffOrderEvent(models.StaffOrderEventPaid, updatedOrder))
+			s.webhooks.Publish(ctx, models.WebhookEventOrderPaid, updatedOrder, nil)
+		}
+
 		if err := s.publishOrderPaidEvent(ctx, updatedOrder); err != nil {
 			log.Error().
 				Err(err).
@@ -151,6 +301,65 @@ func (s *OrderService) UpdateOrderStatus(
 	return nil
 }
 
+// broadcastStatus pushes an order's new status or payment status to guests following it
+func (s *OrderService) broadcastStatus(ctx context.Context, eventType models.OrderStatusEventType, orderReference string, status models.OrderStatus, paymentStatus *string) {
+	s.broadcaster.Publish(ctx, &models.OrderStatusEvent{
+		Type:           eventType,
+		OrderReference: orderReference,
+		Status:         status,
+		PaymentStatus:  paymentStatus,
+	})
+}
+
+// GetStatusSnapshot returns an order's current status and latest payment status
+// Returns sql.ErrNoRows when the order does not exist.
+func (s *OrderService) GetStatusSnapshot(ctx context.Context, orderReference string) (*models.OrderStatusEvent, error) {
+	order, err := s.orderRepo.GetOrderByReference(ctx, orderReference)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.OrderStatusEvent{
+		Type:           models.OrderStatusEventSnapshot,
+		OrderReference: order.OrderReference,
+		Status:         order.Status,
+		OccurredAt:     time.Now().UTC(),
+	}
+
+	payment, err := s.paymentRepo.GetPaymentByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment != nil {
+		snapshot.PaymentStatus = payment.TransactionStatus
+	}
+
+	return snapshot, nil
+}
+
+// accrueLoyaltyPoints credits the points a paid order earned in a transaction of its own
+// Earning is idempotent per order, so a retried payment notification cannot double the points.
+func (s *OrderService) accrueLoyaltyPoints(ctx context.Context, order *models.GuestOrder) {
+	if s.loyaltyService == nil {
+		return
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Msg("Failed to begin loyalty transaction")
+		return
+	}
+	defer tx.Rollback()
+
+	if err := s.loyaltyService.AccrueForOrder(ctx, tx, order); err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Msg("Failed to accrue loyalty points")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Msg("Failed to commit loyalty points")
+	}
+}
+
 // isValidTransition validates state machine transitions
 // Implements T088: State machine validation from research.md
 //
@@ -229,11 +438,61 @@ func (s *OrderService) GetOrderItems(ctx context.Context, orderID string) ([]mod
 	return s.orderRepo.GetOrderItemsByOrderID(ctx, orderID)
 }
 
+// GetOrderItemCosts returns the current unit cost of each product in an order
+func (s *OrderService) GetOrderItemCosts(ctx context.Context, orderID string) (map[string]int, error) {
+	return s.orderRepo.GetOrderItemCosts(ctx, orderID)
+}
+
 // GetOrderNotes retrieves all notes for a specific order
 func (s *OrderService) GetOrderNotes(ctx context.Context, orderID string) ([]*models.OrderNote, error) {
 	return s.orderRepo.GetOrderNotesByOrderID(ctx, orderID)
 }
 
+// MarkOrderDisputed flags a PAID order as disputed so the auto-complete sweeper leaves it open
+func (s *OrderService) MarkOrderDisputed(ctx context.Context, orderID, tenantID, reason, userName string) error {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return ErrOrderNotFound
+	}
+
+	if order.Status != models.OrderStatusPaid {
+		return models.ErrOrderNotDisputable
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return models.ErrDisputeReasonEmpty
+	}
+
+	if err := s.orderRepo.SetOrderDispute(ctx, orderID, &reason); err != nil {
+		return fmt.Errorf("failed to mark order as disputed: %w", err)
+	}
+
+	if err := s.AddOrderNote(ctx, orderID, "Order marked as disputed: "+reason, userName); err != nil {
+		log.Warn().Err(err).Str("order_id", orderID).Msg("Failed to add dispute note")
+	}
+
+	return nil
+}
+
+// ClearOrderDispute removes the dispute flag so the order can be auto-completed again
+func (s *OrderService) ClearOrderDispute(ctx context.Context, orderID, tenantID, userName string) error {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return ErrOrderNotFound
+	}
+
+	if err := s.orderRepo.SetOrderDispute(ctx, orderID, nil); err != nil {
+		return fmt.Errorf("failed to clear order dispute: %w", err)
+	}
+
+	if err := s.AddOrderNote(ctx, orderID, "Order dispute resolved", userName); err != nil {
+		log.Warn().Err(err).Str("order_id", orderID).Msg("Failed to add dispute note")
+	}
+
+	return nil
+}
+
 // publishOrderPaidEvent publishes an order.paid event to Kafka for notification service
 func (s *OrderService) publishOrderPaidEvent(ctx context.Context, order *models.GuestOrder) error {
 	if s.kafkaProducer == nil {
@@ -285,6 +544,9 @@ func (s *OrderService) publishOrderPaidEvent(ctx context.Context, order *models.
 		if paymentTxn.PaymentType != nil {
 			paymentMethod = *paymentTxn.PaymentType
 		}
+	} else if records, err := s.paymentRepo.GetPaymentHistory(ctx, order.ID); err == nil && len(records) > 0 {
+		// Staff-recorded payments (cash, EDC) have no Midtrans transaction
+		paymentMethod = string(records[0].PaymentMethod)
 	}
 
 	// Convert order items to event format
@@ -316,6 +578,7 @@ func (s *OrderService) publishOrderPaidEvent(ctx context.Context, order *models.
 		"items":           eventItems,
 		"subtotal_amount": order.SubtotalAmount,
 		"delivery_fee":    order.DeliveryFee,
+		"discount_amount": order.DiscountAmount,
 		"total_amount":    order.TotalAmount,
 		"payment_method":  paymentMethod,
 		"paid_at":         paidAtTime.Format(time.RFC3339),
@@ -337,6 +600,37 @@ func (s *OrderService) publishOrderPaidEvent(ctx context.Context, order *models.
 		dataPayload["table_number"] = *order.TableNumber
 	}
 
+	// Add the booked slot for scheduled pickup/delivery orders
+	if order.ScheduledFor != nil {
+		dataPayload["scheduled_for"] = order.ScheduledFor.Format(time.RFC3339)
+	}
+
+	// Add the redeemed voucher code when a discount was applied
+	if order.VoucherCode != nil {
+		dataPayload["voucher_code"] = *order.VoucherCode
+	}
+
+	// Add the automatic promotion discount when promotions applied
+	if order.PromotionDiscountAmount > 0 {
+		dataPayload["promotion_discount_amount"] = order.PromotionDiscountAmount
+	}
+
+	// Add the loyalty points redemption when points were used
+	if order.LoyaltyDiscountAmount > 0 {
+		dataPayload["loyalty_points_redeemed"] = order.LoyaltyPointsRedeemed
+		dataPayload["loyalty_discount_amount"] = order.LoyaltyDiscountAmount
+	}
+
+	// Add the service charge and tax lines when the tenant charges them
+	if order.ServiceChargeAmount > 0 {
+		dataPayload["service_charge_rate"] = order.ServiceChargeRate
+		dataPayload["service_charge_amount"] = order.ServiceChargeAmount
+	}
+	if order.TaxAmount > 0 {
+		dataPayload["tax_rate"] = order.TaxRate
+		dataPayload["tax_amount"] = order.TaxAmount
+	}
+
 	// Prepare event payload
 	event := map[string]interface{}{
 		"event_id":   fmt.Sprintf("order-paid-%s-%d", order.ID, time.Now().Unix()),
@@ -346,8 +640,17 @@ func (s *OrderService) publishOrderPaidEvent(ctx context.Context, order *models.
 		"data":       dataPayload,
 	}
 
-	// Publish to Kafka
-	key := fmt.Sprintf("order-%s", order.ID)
+	// Carry the originating request ID so notification records can be traced back to it
+	if correlationID := observability.CorrelationIDFromContext(ctx); correlationID != "" {
+		event["correlation_id"] = correlationID
+	}
+
+	// Key by transaction so every event for a payment lands on the same partition,
+	// and is therefore handled by a single notification-service replica in order
+	key := transactionID
+	if key == "" {
+		key = fmt.Sprintf("order-%s", order.ID)
+	}
 	if err := s.kafkaProducer.Publish(ctx, key, event); err != nil {
 		return fmt.Errorf("failed to publish to Kafka: %w", err)
 	}
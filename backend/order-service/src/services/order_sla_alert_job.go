@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/queue"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// OrderSLAAlertJob alerts staff when a paid order runs over a stage's SLA target
+// Only tenants with SLA targets are checked. Each late stage of an order is alerted once
+// through an order.sla_breached event, which notification-service emails to staff.
+type OrderSLAAlertJob struct {
+	settingsRepo  *repository.OrderSettingsRepository
+	kitchenRepo   *repository.KitchenRepository
+	slaRepo       *repository.OrderSLARepository
+	kafkaProducer *queue.KafkaProducer
+	interval      time.Duration
+	stopChan      chan struct{}
+}
+
+// NewOrderSLAAlertJob creates the late-order alert sweeper
+func NewOrderSLAAlertJob(
+	settingsRepo *repository.OrderSettingsRepository,
+	kitchenRepo *repository.KitchenRepository,
+	slaRepo *repository.OrderSLARepository,
+	kafkaProducer *queue.KafkaProducer,
+) *OrderSLAAlertJob {
+	return &OrderSLAAlertJob{
+		settingsRepo:  settingsRepo,
+		kitchenRepo:   kitchenRepo,
+		slaRepo:       slaRepo,
+		kafkaProducer: kafkaProducer,
+		interval:      1 * time.Minute, // Targets are in minutes
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start begins the sweeper loop; it blocks until stopped
+func (j *OrderSLAAlertJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting order SLA alert job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.sweep(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping order SLA alert job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping order SLA alert job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the sweeper
+func (j *OrderSLAAlertJob) Stop() {
+	close(j.stopChan)
+}
+
+// sweep checks the kitchen orders of every tenant with SLA targets
+func (j *OrderSLAAlertJob) sweep(ctx context.Context) {
+	tenantIDs, err := j.settingsRepo.ListSLAEnabledTenants(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list tenants with SLA targets")
+		return
+	}
+
+	now := time.Now()
+	for _, tenantID := range tenantIDs {
+		if err := j.sweepTenant(ctx, tenantID, now); err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to check order SLAs")
+		}
+	}
+}
+
+// sweepTenant alerts on the tenant's kitchen orders whose current stage is late
+func (j *OrderSLAAlertJob) sweepTenant(ctx context.Context, tenantID string, now time.Time) error {
+	settings, err := j.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	feed, err := j.kitchenRepo.ListOrders(ctx, tenantID, nil)
+	if err != nil {
+		return err
+	}
+
+	for i := range feed.Orders {
+		order := &feed.Orders[i]
+		// Scheduled orders are timed by their slot rather than by SLA targets
+		if order.ScheduledFor != nil {
+			continue
+		}
+		sla := settings.EvaluateSLA(order.Timing(), now)
+		if sla.Status != models.SLAStatusLate || sla.Stage == models.SLAStageReady {
+			continue
+		}
+
+		recorded, err := j.slaRepo.RecordAlert(ctx, order.ID, sla.Stage)
+		if err != nil {
+			log.Error().Err(err).Str("order_id", order.ID).Msg("Failed to record SLA alert")
+			continue
+		}
+		if !recorded {
+			continue
+		}
+
+		j.publishBreach(ctx, models.OrderSLABreach{
+			TenantID:       tenantID,
+			OrderID:        order.ID,
+			OrderReference: order.OrderReference,
+			DeliveryType:   string(order.DeliveryType),
+			TableNumber:    order.TableNumber,
+			Stage:          sla.Stage,
+			StageStartedAt: sla.StageStartedAt,
+			ElapsedSeconds: int64(now.Sub(sla.StageStartedAt).Seconds()),
+			TargetSeconds:  sla.TargetSeconds,
+		})
+	}
+	return nil
+}
+
+// publishBreach sends the order.sla_breached event to notification-service
+func (j *OrderSLAAlertJob) publishBreach(ctx context.Context, breach models.OrderSLABreach) {
+	log.Warn().
+		Str("tenant_id", breach.TenantID).
+		Str("order_reference", breach.OrderReference).
+		Str("stage", string(breach.Stage)).
+		Int64("elapsed_seconds", breach.ElapsedSeconds).
+		Int64("target_seconds", breach.TargetSeconds).
+		Msg("Order is late for its SLA target")
+
+	if j.kafkaProducer == nil {
+		return
+	}
+	tableNumber := ""
+	if breach.TableNumber != nil {
+		tableNumber = *breach.TableNumber
+	}
+	event := map[string]interface{}{
+		"event_type": "order.sla_breached",
+		"tenant_id":  breach.TenantID,
+		"user_id":    "",
+		"data": map[string]interface{}{
+			"order_id":         breach.OrderID,
+			"order_reference":  breach.OrderReference,
+			"delivery_type":    breach.DeliveryType,
+			"table_number":     tableNumber,
+			"stage":            breach.Stage,
+			"stage_started_at": breach.StageStartedAt.Format(time.RFC3339),
+			"elapsed_minutes":  breach.ElapsedSeconds / 60,
+			"target_minutes":   breach.TargetSeconds / 60,
+		},
+	}
+	if err := j.kafkaProducer.Publish(ctx, breach.TenantID, event); err != nil {
+		log.Warn().Err(err).Str("order_id", breach.OrderID).Msg("Failed to publish order.sla_breached event")
+	}
+}
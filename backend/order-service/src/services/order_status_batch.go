@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// BatchUpdateOrderStatus moves many of a tenant's orders to the same status
+// Each order is updated on its own, with the same checks and side effects as a single
+// status update, so one bad order never holds back the rest. The updated orders are
+// announced in a single order.status_batch_updated event.
+func (s *OrderService) BatchUpdateOrderStatus(ctx context.Context, tenantID string, req *models.BatchOrderStatusRequest, userID, userName string) *models.BatchOrderStatusResponse {
+	response := &models.BatchOrderStatusResponse{
+		Status:  req.Status,
+		Results: make([]models.BatchOrderStatusResult, 0, len(req.OrderIDs)),
+	}
+
+	var updated []models.BatchOrderStatusResult
+	for _, orderID := range req.OrderIDs {
+		result := s.batchUpdateOne(ctx, tenantID, orderID, req.Status, userID)
+		response.Results = append(response.Results, result)
+		switch {
+		case !result.Success:
+			response.Failed++
+		case !result.Unchanged:
+			response.Updated++
+			updated = append(updated, result)
+		}
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("status", string(req.Status)).
+		Int("requested", len(req.OrderIDs)).
+		Int("updated", response.Updated).
+		Int("failed", response.Failed).
+		Msg("Batch order status update completed")
+
+	if len(updated) > 0 {
+		s.publishBatchStatusEvent(ctx, tenantID, req.Status, updated, userID, userName)
+	}
+	return response
+}
+
+// batchUpdateOne updates a single order of a batch and reports the outcome
+func (s *OrderService) batchUpdateOne(ctx context.Context, tenantID, orderID string, status models.OrderStatus, userID string) models.BatchOrderStatusResult {
+	result := models.BatchOrderStatusResult{OrderID: orderID}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		result.Error = "Order not found"
+		return result
+	}
+	result.OrderReference = order.OrderReference
+	result.PreviousStatus = order.Status
+
+	if order.Status == status {
+		result.Success = true
+		result.Unchanged = true
+		return result
+	}
+	if !s.isValidTransition(order.Status, status) {
+		result.Error = "invalid status transition from " + string(order.Status) + " to " + string(status)
+		return result
+	}
+
+	if err := s.UpdateOrderStatus(ctx, orderID, status); err != nil {
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("new_status", string(status)).
+			Msg("Failed to update order status in batch")
+		result.Error = "Failed to update order status"
+		return result
+	}
+	s.AuditStatusChange(ctx, order, status, userID)
+
+	result.Success = true
+	return result
+}
+
+// publishBatchStatusEvent publishes one order.status_batch_updated event for the orders a batch changed
+func (s *OrderService) publishBatchStatusEvent(ctx context.Context, tenantID string, status models.OrderStatus, updated []models.BatchOrderStatusResult, userID, userName string) {
+	if s.kafkaProducer == nil {
+		log.Warn().Msg("Kafka producer not initialized - skipping order.status_batch_updated event")
+		return
+	}
+
+	orders := make([]map[string]interface{}, len(updated))
+	for i, result := range updated {
+		orders[i] = map[string]interface{}{
+			"order_id":        result.OrderID,
+			"order_reference": result.OrderReference,
+			"previous_status": result.PreviousStatus,
+		}
+	}
+
+	event := map[string]interface{}{
+		"event_type": "order.status_batch_updated",
+		"tenant_id":  tenantID,
+		"user_id":    userID,
+		"data": map[string]interface{}{
+			"status":          status,
+			"orders":          orders,
+			"count":           len(orders),
+			"updated_by_name": userName,
+		},
+	}
+	if err := s.kafkaProducer.Publish(ctx, tenantID, event); err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to publish order.status_batch_updated event")
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// OrderStatusChannel is the Redis pub/sub channel carrying order status events between replicas
+const OrderStatusChannel = "order-status-events"
+
+// orderStatusSubscriberBuffer is how many events a slow stream may fall behind before it is dropped
+const orderStatusSubscriberBuffer = 8
+
+// OrderStatusBroadcaster fans order status events out to the guests following an order
+// Events are published through Redis so a stream served by one replica sees transitions made
+// by another. Each replica holds a single subscription and dispatches to its local streams.
+type OrderStatusBroadcaster struct {
+	redisClient *redis.Client
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.OrderStatusEvent]struct{}
+}
+
+// NewOrderStatusBroadcaster creates a new order status broadcaster
+func NewOrderStatusBroadcaster(redisClient *redis.Client) *OrderStatusBroadcaster {
+	return &OrderStatusBroadcaster{
+		redisClient: redisClient,
+		subscribers: make(map[string]map[chan models.OrderStatusEvent]struct{}),
+	}
+}
+
+// Publish sends an event to every replica; failures are logged, never returned
+// Status streams are best effort: a guest that misses an event gets the current state on reconnect.
+func (b *OrderStatusBroadcaster) Publish(ctx context.Context, event *models.OrderStatusEvent) {
+	if b == nil || b.redisClient == nil {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Str("order_reference", event.OrderReference).Msg("Failed to marshal order status event")
+		return
+	}
+	if err := b.redisClient.Publish(ctx, OrderStatusChannel, payload).Err(); err != nil {
+		log.Warn().Err(err).
+			Str("order_reference", event.OrderReference).
+			Str("type", string(event.Type)).
+			Msg("Failed to publish order status event")
+	}
+}
+
+// Subscribe registers a stream for an order's events
+// The channel is closed when the returned cancel func is called, or early when the stream
+// falls too far behind; callers should then end the stream so the guest reconnects.
+func (b *OrderStatusBroadcaster) Subscribe(orderReference string) (<-chan models.OrderStatusEvent, func()) {
+	ch := make(chan models.OrderStatusEvent, orderStatusSubscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[orderReference] == nil {
+		b.subscribers[orderReference] = make(map[chan models.OrderStatusEvent]struct{})
+	}
+	b.subscribers[orderReference][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.removeLocked(orderReference, ch)
+		})
+	}
+}
+
+// removeLocked unregisters and closes a subscriber channel if it is still registered
+func (b *OrderStatusBroadcaster) removeLocked(orderReference string, ch chan models.OrderStatusEvent) {
+	subscribers, ok := b.subscribers[orderReference]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, orderReference)
+	}
+}
+
+// dispatch delivers an event to this replica's streams for the order
+func (b *OrderStatusBroadcaster) dispatch(event models.OrderStatusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.OrderReference] {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Str("order_reference", event.OrderReference).Msg("Order status stream fell behind - closing it")
+			b.removeLocked(event.OrderReference, ch)
+		}
+	}
+}
+
+// Start relays events from Redis to local streams until ctx is cancelled
+func (b *OrderStatusBroadcaster) Start(ctx context.Context) {
+	if b.redisClient == nil {
+		return
+	}
+	log.Info().Msg("Starting order status broadcaster")
+
+	pubsub := b.redisClient.Subscribe(ctx, OrderStatusChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping order status broadcaster")
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event models.OrderStatusEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Warn().Err(err).Msg("Ignoring malformed order status event")
+				continue
+			}
+			b.dispatch(event)
+		}
+	}
+}
@@ -86,6 +86,18 @@ func (pc *PaymentCalculator) ValidatePaymentAmount(paymentAmount int, remainingB
 	return nil
 }
 
+// CalculateChange computes the change owed for a cash payment
+// Returns models.ErrInsufficientTender when the tendered amount does not cover the amount due
+func (pc *PaymentCalculator) CalculateChange(amountDue int, amountTendered int) (int, error) {
+	if amountDue <= 0 {
+		return 0, fmt.Errorf("amount due must be greater than 0")
+	}
+	if amountTendered < amountDue {
+		return 0, models.ErrInsufficientTender
+	}
+	return amountTendered - amountDue, nil
+}
+
 // CalculateNextPaymentNumber determines the next payment number based on existing payments
 // Returns 0 for down payment, 1+ for installments
 func (pc *PaymentCalculator) CalculateNextPaymentNumber(existingPayments []models.PaymentRecord) int {
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// FallBackToManualPayment keeps a checkout going when the tenant's gateway is unreachable
+// The order is flagged UNPAID_OFFLINE so staff collect payment at the counter (or on
+// delivery), its stock is held as long as a cashier-recorded order's, and a QRIS charge
+// is queued for retry once the gateway recovers. Reports whether a retry was queued.
+// Must be called within the checkout transaction that placed the order.
+func (s *PaymentService) FallBackToManualPayment(ctx context.Context, tx *sql.Tx, order *models.GuestOrder, method models.CheckoutPaymentMethod, cause error) (bool, error) {
+	if err := s.orderRepo.SetPaymentFallback(ctx, tx, order.ID, models.PaymentFallbackUnpaidOffline); err != nil {
+		return false, fmt.Errorf("failed to flag order for manual payment: %w", err)
+	}
+	fallback := models.PaymentFallbackUnpaidOffline
+	order.PaymentFallback = &fallback
+
+	if err := s.inventoryService.ExtendReservations(ctx, tx, order.ID, ManualOrderReservationTTL); err != nil {
+		return false, err
+	}
+
+	if method == "" {
+		method = models.CheckoutPaymentQRIS
+	}
+	if !models.QueuesChargeRetry(method) {
+		return false, nil
+	}
+
+	now := time.Now()
+	lastError := cause.Error()
+	retry := &models.PaymentChargeRetry{
+		OrderID:       order.ID,
+		TenantID:      order.TenantID,
+		PaymentMethod: method,
+		NextAttemptAt: now.Add(models.ChargeRetryDelay(1)),
+		ExpiresAt:     now.Add(ManualOrderReservationTTL),
+		LastError:     &lastError,
+	}
+	if err := s.chargeRetryRepo.Queue(ctx, tx, retry); err != nil {
+		return false, fmt.Errorf("failed to queue charge retry: %w", err)
+	}
+	return true, nil
+}
+
+// RetryQueuedCharge tries once more to create the online charge for an order placed during an outage
+// While the gateway is still down the retry is pushed back; once it answers, the retry is
+// removed whether or not the charge could be created.
+func (s *PaymentService) RetryQueuedCharge(ctx context.Context, retry *models.PaymentChargeRetry) error {
+	order, err := s.orderRepo.GetOrderByID(ctx, retry.OrderID)
+	if err != nil || order == nil || order.Status != models.OrderStatusPending {
+		return s.chargeRetryRepo.Delete(ctx, nil, retry.OrderID)
+	}
+
+	gateway, err := s.gatewayForTenant(ctx, order.TenantID)
+	if err != nil {
+		return err
+	}
+
+	payment, err := gateway.CreateCharge(ctx, &GatewayChargeRequest{
+		Order:       order,
+		Method:      retry.PaymentMethod,
+		ReferenceID: models.ChargeRetryOrderID(order.OrderReference, retry.Attempts),
+		Expiry:      s.paymentExpiry(ctx, order.TenantID),
+	})
+	if errors.Is(err, models.ErrPaymentGatewayUnavailable) {
+		nextAttemptAt := time.Now().Add(models.ChargeRetryDelay(retry.Attempts))
+		if err := s.chargeRetryRepo.Reschedule(ctx, order.ID, nextAttemptAt, err.Error()); err != nil {
+			return fmt.Errorf("failed to reschedule charge retry: %w", err)
+		}
+		log.Warn().
+			Err(err).
+			Str("order_id", order.ID).
+			Int("attempts", retry.Attempts).
+			Time("next_attempt_at", nextAttemptAt).
+			Msg("Payment gateway still unavailable - charge retry rescheduled")
+		return nil
+	}
+	if err != nil {
+		if delErr := s.chargeRetryRepo.Delete(ctx, nil, order.ID); delErr != nil {
+			log.Error().Err(delErr).Str("order_id", order.ID).Msg("Failed to remove charge retry")
+		}
+		note := fmt.Sprintf("Online %s payment could not be created after the payment gateway outage (%v). Collect payment manually.", retry.PaymentMethod, err)
+		if noteErr := s.orderService.AddOrderNote(ctx, order.ID, note, "System"); noteErr != nil {
+			log.Warn().Err(noteErr).Str("order_id", order.ID).Msg("Failed to add charge retry note")
+		}
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	saved, err := s.saveRetriedCharge(ctx, order.ID, payment)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		// Paid or cancelled by staff while the charge was being created
+		if err := gateway.Cancel(ctx, order.TenantID, payment); err != nil {
+			log.Warn().Err(err).Str("order_id", order.ID).Str("charge", payment.MidtransOrderID).Msg("Failed to cancel unneeded retried charge")
+		}
+		return nil
+	}
+
+	paymentStatus := "pending"
+	s.orderService.broadcastStatus(ctx, models.OrderStatusEventPayment, order.OrderReference, order.Status, &paymentStatus)
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("charge", payment.MidtransOrderID).
+		Int("attempts", retry.Attempts).
+		Msg("Queued charge created after payment gateway recovered")
+	return nil
+}
+
+// saveRetriedCharge stores a retried charge and removes its retry if the order still awaits payment
+func (s *PaymentService) saveRetriedCharge(ctx context.Context, orderID string, payment *models.PaymentTransaction) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.paymentRepo.LockOrderForPayment(ctx, tx, orderID); err != nil {
+		return false, fmt.Errorf("failed to lock order: %w", err)
+	}
+	var status models.OrderStatus
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM guest_orders WHERE id = $1`, orderID).Scan(&status); err != nil {
+		return false, fmt.Errorf("failed to get order status: %w", err)
+	}
+
+	if err := s.chargeRetryRepo.Delete(ctx, tx, orderID); err != nil {
+		return false, fmt.Errorf("failed to remove charge retry: %w", err)
+	}
+	saved := status == models.OrderStatusPending
+	if saved {
+		if err := s.SaveCheckoutPayment(ctx, tx, payment); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit retried charge: %w", err)
+	}
+	return saved, nil
+}
+
+// ChargeRetryJob creates the online charges queued while the payment gateway was unreachable
+type ChargeRetryJob struct {
+	paymentService *PaymentService
+	interval       time.Duration
+	lease          time.Duration
+	batchSize      int
+	stopChan       chan struct{}
+}
+
+// NewChargeRetryJob creates the charge retry worker
+func NewChargeRetryJob(paymentService *PaymentService) *ChargeRetryJob {
+	return &ChargeRetryJob{
+		paymentService: paymentService,
+		interval:       30 * time.Second,
+		lease:          2 * time.Minute, // Longer than a gateway call can take
+		batchSize:      50,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the retry loop; it blocks until stopped
+func (j *ChargeRetryJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting charge retry job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.retryDue(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping charge retry job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping charge retry job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the retry loop
+func (j *ChargeRetryJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *ChargeRetryJob) retryDue(ctx context.Context) {
+	repo := j.paymentService.chargeRetryRepo
+	now := time.Now()
+
+	if removed, err := repo.DeleteStale(ctx, now); err != nil {
+		log.Error().Err(err).Msg("Failed to remove stale charge retries")
+	} else if removed > 0 {
+		log.Info().Int64("removed", removed).Msg("Removed charge retries that are no longer needed")
+	}
+
+	retries, err := repo.ClaimDue(ctx, now, j.lease, j.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to claim due charge retries")
+		return
+	}
+
+	for _, retry := range retries {
+		if err := j.paymentService.RetryQueuedCharge(ctx, retry); err != nil {
+			log.Error().
+				Err(err).
+				Str("order_id", retry.OrderID).
+				Int("attempts", retry.Attempts).
+				Msg("Charge retry failed")
+		}
+	}
+}
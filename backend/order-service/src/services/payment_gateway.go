@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// PaymentGateway is implemented by each online payment provider a tenant can choose
+// Webhook notifications are normalized to MidtransNotification (Midtrans status
+// vocabulary) before they reach the order state machine.
+type PaymentGateway interface {
+	// Provider identifies the gateway; it is stored on every charge it creates
+	Provider() models.PaymentGatewayProvider
+	// CreateCharge starts a payment; the returned transaction is not yet saved
+	CreateCharge(ctx context.Context, req *GatewayChargeRequest) (*models.PaymentTransaction, error)
+	// VerifyWebhook checks that a notification really came from the gateway
+	VerifyWebhook(ctx context.Context, tenantID string, notification *MidtransNotification, headers http.Header) bool
+	// Refund returns money for a settled charge
+	Refund(ctx context.Context, tenantID string, payment *models.PaymentTransaction, amount int, reason string) (*GatewayRefund, error)
+	// GetStatus asks the gateway for the current state of a charge
+	GetStatus(ctx context.Context, tenantID string, payment *models.PaymentTransaction) (*GatewayPaymentStatus, error)
+	// Cancel voids an unpaid charge so the customer can no longer pay it
+	Cancel(ctx context.Context, tenantID string, payment *models.PaymentTransaction) error
+}
+
+// GatewayChargeRequest describes a charge for all or part of an order
+type GatewayChargeRequest struct {
+	Order       *models.GuestOrder
+	Items       []models.CartItem // Sent as line items only for full-amount charges
+	Method      models.CheckoutPaymentMethod
+	Bank        string        // Virtual account bank, required for bank_transfer
+	ReferenceID string        // Gateway-facing order ID; defaults to the order reference
+	Amount      int           // Defaults to the order total
+	Expiry      time.Duration // Tenant's QRIS and e-wallet payment expiry; defaults to ReservationTTL
+}
+
+// referenceID returns the gateway-facing order ID for the charge
+func (r *GatewayChargeRequest) referenceID() string {
+	if r.ReferenceID != "" {
+		return r.ReferenceID
+	}
+	return r.Order.OrderReference
+}
+
+// amount returns the amount to charge
+func (r *GatewayChargeRequest) amount() int {
+	if r.Amount > 0 {
+		return r.Amount
+	}
+	return r.Order.TotalAmount
+}
+
+// expiry returns how long the charge stays payable, matching the stock reservation
+func (r *GatewayChargeRequest) expiry() time.Duration {
+	return ReservationTTLFor(r.Method, r.Expiry)
+}
+
+// isPartial reports whether the charge covers only part of the order total
+func (r *GatewayChargeRequest) isPartial() bool {
+	return r.amount() != r.Order.TotalAmount
+}
+
+// GatewayRefund is the gateway's answer to a refund request
+type GatewayRefund struct {
+	Gateway  models.PaymentGatewayProvider `json:"gateway"`
+	RefundID string                        `json:"refund_id"`
+	Amount   int                           `json:"amount"`
+	Status   string                        `json:"status"`
+}
+
+// GatewayPaymentStatus is the current state of a charge as reported by the gateway
+type GatewayPaymentStatus struct {
+	Gateway       models.PaymentGatewayProvider `json:"gateway"`
+	TransactionID string                        `json:"transaction_id"`
+	ReferenceID   string                        `json:"reference_id"`
+	Status        string                        `json:"status"` // Raw gateway status
+	Outcome       models.PaymentOutcome         `json:"outcome"`
+	GrossAmount   int                           `json:"gross_amount,omitempty"` // 0 when the gateway does not report it
+}
+
+// RefundRequest is a staff request to refund an order's online payment
+type RefundRequest struct {
+	OrderID  string `json:"-"`
+	TenantID string `json:"-"`
+	NotedBy  string `json:"-"`
+	Amount   int    `json:"amount,omitempty" validate:"omitempty,min=1"` // Defaults to the full payment
+	Reason   string `json:"reason" validate:"required,max=500"`
+}
+
+// gatewayForTenant returns the gateway the tenant has chosen for new payments
+func (s *PaymentService) gatewayForTenant(ctx context.Context, tenantID string) (PaymentGateway, error) {
+	gatewayConfig, err := config.GetPaymentGatewayConfigForTenant(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to fetch tenant payment gateway config")
+		return nil, fmt.Errorf("failed to get payment gateway configuration: %w", err)
+	}
+
+	provider := models.PaymentGatewayProvider(gatewayConfig.Provider)
+	if provider == "" {
+		provider = models.PaymentGatewayMidtrans
+	}
+	return s.gatewayByProvider(provider)
+}
+
+// paymentExpiry returns the tenant's expiry for new QRIS and e-wallet charges
+// Falls back to the default when order settings cannot be read, so a charge is never blocked by it.
+func (s *PaymentService) paymentExpiry(ctx context.Context, tenantID string) time.Duration {
+	settings, err := s.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to fetch order settings for payment expiry, using default")
+		return ReservationTTL
+	}
+	return settings.PaymentExpiry()
+}
+
+// checkoutReservationTTL returns how long a tenant holds checkout stock for a payment method
+// Falls back to the payment expiry default when order settings cannot be read.
+func (s *PaymentService) checkoutReservationTTL(ctx context.Context, tenantID string, method models.CheckoutPaymentMethod) time.Duration {
+	settings, err := s.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", tenantID).Msg("Failed to fetch order settings for reservation TTL, using default")
+		return ReservationTTLFor(method, ReservationTTL)
+	}
+	return CheckoutReservationTTL(method, settings)
+}
+
+// gatewayByProvider returns the adapter for a provider
+// Existing charges always go back to the gateway that created them,
+// even if the tenant has switched gateways since.
+func (s *PaymentService) gatewayByProvider(provider models.PaymentGatewayProvider) (PaymentGateway, error) {
+	if provider == "" {
+		provider = models.PaymentGatewayMidtrans
+	}
+	gateway, ok := s.gateways[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnsupportedPaymentGateway, provider)
+	}
+	return gateway, nil
+}
+
+// RefundOnlinePayment refunds a settled online payment through the gateway that took it
+// The order status is left unchanged; staff cancel the order separately if needed.
+func (s *PaymentService) RefundOnlinePayment(ctx context.Context, req *RefundRequest) (*GatewayRefund, error) {
+	order, payment, err := s.getOnlinePayment(ctx, req.OrderID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.SettledAt == nil {
+		return nil, models.ErrPaymentNotSettled
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = payment.Amount
+	}
+	if amount < 0 || amount > payment.Amount {
+		return nil, models.ErrInvalidPaymentAmount
+	}
+
+	gateway, err := s.gatewayByProvider(payment.Gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	refund, err := gateway.Refund(ctx, order.TenantID, payment, amount, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("Refund of %d requested via %s (refund %s, status: %s). Reason: %s",
+		refund.Amount, refund.Gateway, refund.RefundID, refund.Status, req.Reason)
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, req.NotedBy); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add refund note")
+	}
+
+	s.orderService.webhooks.Publish(ctx, models.WebhookEventOrderRefunded, order, &models.WebhookRefund{
+		RefundID: refund.RefundID,
+		Amount:   refund.Amount,
+		Status:   refund.Status,
+		Reason:   req.Reason,
+	})
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("gateway", string(refund.Gateway)).
+		Str("refund_id", refund.RefundID).
+		Int("amount", refund.Amount).
+		Msg("Online payment refund requested")
+
+	return refund, nil
+}
+
+// GetGatewayPaymentStatus asks the gateway for the live status of an order's online payment
+func (s *PaymentService) GetGatewayPaymentStatus(ctx context.Context, orderID, tenantID string) (*GatewayPaymentStatus, error) {
+	order, payment, err := s.getOnlinePayment(ctx, orderID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	gateway, err := s.gatewayByProvider(payment.Gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	return gateway.GetStatus(ctx, order.TenantID, payment)
+}
+
+// GetSettledOnlinePayment loads a tenant's order and the settled online charge a refund would go against
+func (s *PaymentService) GetSettledOnlinePayment(ctx context.Context, orderID, tenantID string) (*models.GuestOrder, *models.PaymentTransaction, error) {
+	order, payment, err := s.getOnlinePayment(ctx, orderID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payment.SettledAt == nil {
+		return nil, nil, models.ErrPaymentNotSettled
+	}
+	return order, payment, nil
+}
+
+// getOnlinePayment loads a tenant's order and its latest full-amount online charge
+func (s *PaymentService) getOnlinePayment(ctx context.Context, orderID, tenantID string) (*models.GuestOrder, *models.PaymentTransaction, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return nil, nil, ErrOrderNotFound
+	}
+
+	payment, err := s.paymentRepo.GetPaymentByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment == nil {
+		return nil, nil, models.ErrNoOnlinePayment
+	}
+
+	return order, payment, nil
+}
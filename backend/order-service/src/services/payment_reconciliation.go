@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// PaymentReconciliation is the result of re-checking a charge with its gateway
+type PaymentReconciliation struct {
+	OrderID        string                `json:"order_id"`
+	OrderReference string                `json:"order_reference"`
+	Charge         string                `json:"charge"` // Gateway-facing order ID of the charge
+	GatewayStatus  *GatewayPaymentStatus `json:"gateway_status"`
+	OrderStatus    models.OrderStatus    `json:"order_status"`
+	Applied        bool                  `json:"applied"` // Whether the gateway status was applied to the order
+}
+
+// ReconcilePayment asks the gateway for the status of an order's online payment and,
+// if the payment has settled or failed without us hearing about it, applies that status
+// through the same handlers as a webhook
+func (s *PaymentService) ReconcilePayment(ctx context.Context, orderID, tenantID string) (*PaymentReconciliation, error) {
+	order, payment, err := s.getOnlinePayment(ctx, orderID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reconcileCharge(ctx, order, payment)
+}
+
+// reconcileCharge applies a charge's final gateway status to a PENDING order
+// Statuses a webhook has already delivered are left alone.
+func (s *PaymentService) reconcileCharge(ctx context.Context, order *models.GuestOrder, payment *models.PaymentTransaction) (*PaymentReconciliation, error) {
+	gateway, err := s.gatewayByProvider(payment.Gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := gateway.GetStatus(ctx, order.TenantID, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PaymentReconciliation{
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Charge:         payment.MidtransOrderID,
+		GatewayStatus:  status,
+		OrderStatus:    order.Status,
+	}
+
+	if !ReconciliationApplies(order, status) {
+		return result, nil
+	}
+
+	notification := ReconciledNotification(payment, status)
+	idempotencyKey := notification.TransactionID + ":" + notification.TransactionStatus
+	existing, err := s.paymentRepo.GetPaymentByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+	if existing != nil {
+		return result, nil
+	}
+
+	if err := s.applyPaymentStatus(ctx, order, notification, idempotencyKey, payment.IsSplitPart); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+
+	if updated, err := s.orderRepo.GetOrderByID(ctx, order.ID); err == nil && updated != nil {
+		result.OrderStatus = updated.Status
+	}
+
+	note := fmt.Sprintf("Payment status for %s reconciled with %s after no notification arrived (status: %s).",
+		payment.MidtransOrderID, status.Gateway, status.Status)
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, "System"); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add payment reconciliation note")
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("charge", payment.MidtransOrderID).
+		Str("gateway", string(status.Gateway)).
+		Str("gateway_status", status.Status).
+		Str("order_status", string(result.OrderStatus)).
+		Msg("Payment status reconciled with gateway")
+
+	return result, nil
+}
+
+// ReconciliationApplies reports whether a charge's gateway status is applied to its order
+// Only settled or failed charges are, and only while the order is still PENDING.
+func ReconciliationApplies(order *models.GuestOrder, status *GatewayPaymentStatus) bool {
+	if !order.RequiresPayment() {
+		return false
+	}
+	return status.Outcome == models.PaymentOutcomeSuccess || status.Outcome == models.PaymentOutcomeFailed
+}
+
+// ReconciledNotification describes a gateway status in the webhook's Midtrans vocabulary
+// Charges whose transaction ID was never learned are keyed by their gateway-facing order ID.
+func ReconciledNotification(payment *models.PaymentTransaction, status *GatewayPaymentStatus) *MidtransNotification {
+	notification := &MidtransNotification{
+		TransactionID: status.TransactionID,
+		OrderID:       payment.MidtransOrderID,
+		GrossAmount:   strconv.Itoa(payment.Amount),
+	}
+	if notification.TransactionID == "" && payment.MidtransTransactionID != nil {
+		notification.TransactionID = *payment.MidtransTransactionID
+	}
+	if notification.TransactionID == "" {
+		notification.TransactionID = payment.MidtransOrderID
+	}
+	if payment.PaymentType != nil {
+		notification.PaymentType = *payment.PaymentType
+	}
+
+	switch {
+	case status.Outcome == models.PaymentOutcomeSuccess:
+		notification.TransactionStatus = "settlement"
+	case status.Gateway == models.PaymentGatewayMidtrans:
+		notification.TransactionStatus = strings.ToLower(status.Status) // expire, cancel, deny or failure
+	default:
+		notification.TransactionStatus = "expire"
+	}
+	return notification
+}
+
+// PaymentReconciliationJob re-checks charges that expired while their order is still
+// PENDING, covering webhooks the gateway never delivered
+type PaymentReconciliationJob struct {
+	paymentService *PaymentService
+	interval       time.Duration
+	grace          time.Duration
+	lookback       time.Duration
+	batchSize      int
+	stopChan       chan struct{}
+}
+
+// NewPaymentReconciliationJob creates the payment reconciliation worker
+func NewPaymentReconciliationJob(paymentService *PaymentService) *PaymentReconciliationJob {
+	return &PaymentReconciliationJob{
+		paymentService: paymentService,
+		interval:       5 * time.Minute,
+		grace:          2 * time.Minute, // Let the gateway's own expiry webhook arrive first
+		lookback:       24 * time.Hour,  // Older charges are left to staff
+		batchSize:      100,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the reconciliation loop; it blocks until stopped
+func (j *PaymentReconciliationJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting payment reconciliation job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.reconcileExpired(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping payment reconciliation job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping payment reconciliation job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the reconciliation loop
+func (j *PaymentReconciliationJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *PaymentReconciliationJob) reconcileExpired(ctx context.Context) {
+	now := time.Now().UTC() // expiry_time is stored in UTC without a time zone
+	charges, err := j.paymentService.paymentRepo.ListUnresolvedExpiredCharges(ctx, now.Add(-j.lookback), now.Add(-j.grace), j.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list expired charges for reconciliation")
+		return
+	}
+
+	applied, failed := 0, 0
+	for _, charge := range charges {
+		order, err := j.paymentService.orderRepo.GetOrderByID(ctx, charge.OrderID)
+		if err != nil || order == nil {
+			log.Error().Err(err).Str("order_id", charge.OrderID).Msg("Failed to get order for payment reconciliation")
+			failed++
+			continue
+		}
+
+		result, err := j.paymentService.reconcileCharge(ctx, order, charge)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("order_id", charge.OrderID).
+				Str("charge", charge.MidtransOrderID).
+				Msg("Payment reconciliation failed")
+			failed++
+			continue
+		}
+		if result.Applied {
+			applied++
+		}
+	}
+
+	if len(charges) > 0 {
+		log.Info().
+			Int("checked", len(charges)).
+			Int("applied", applied).
+			Int("failed", failed).
+			Msg("Completed payment reconciliation sweep")
+	}
+}
@@ -2,13 +2,11 @@ package services
 
 import (
 	"context"
-	"crypto/sha512"
 	"database/sql"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -22,7 +20,8 @@ import (
 	"github.com/point-of-sale-system/order-service/src/repository"
 )
 
-// PaymentService handles payment operations with Midtrans integration
+// PaymentService handles online payments through the tenant's payment gateway
+// and staff-recorded in-store payments
 type PaymentService struct {
 	db               *sql.DB
 	snapClient       *snap.Client
@@ -32,6 +31,10 @@ type PaymentService struct {
 	orderRepo        *repository.OrderRepository
 	inventoryService *InventoryService
 	orderService     *OrderService
+	calculator       *PaymentCalculator
+	chargeRetryRepo  *repository.ChargeRetryRepository
+	settingsRepo     *repository.OrderSettingsRepository
+	gateways         map[models.PaymentGatewayProvider]PaymentGateway
 }
 
 // NewPaymentService creates a new payment service
@@ -51,6 +54,13 @@ func NewPaymentService(
 		orderRepo:        orderRepo,
 		inventoryService: inventoryService,
 		orderService:     orderService,
+		calculator:       NewPaymentCalculator(),
+		chargeRetryRepo:  repository.NewChargeRetryRepository(db),
+		settingsRepo:     repository.NewOrderSettingsRepository(db),
+		gateways: map[models.PaymentGatewayProvider]PaymentGateway{
+			models.PaymentGatewayMidtrans: NewMidtransGateway(),
+			models.PaymentGatewayXendit:   NewXenditGateway(config.GetXenditAPIURL()),
+		},
 	}
 }
 
@@ -75,165 +85,6 @@ type Action struct {
 	URL    string `json:"url"`
 }
 
-// CreateQRISCharge creates a QRIS payment charge using Midtrans Core API
-// Implements integration with /v2/charge endpoint for QRIS generation
-func (s *PaymentService) CreateQRISCharge(ctx context.Context, order *models.GuestOrder, items []models.CartItem) (*coreapi.ChargeResponse, error) {
-	// Fetch tenant-specific Midtrans configuration
-	midtransConfig, err := config.GetMidtransConfigForTenant(ctx, order.TenantID)
-	if err != nil {
-		log.Error().Err(err).Str("tenant_id", order.TenantID).Msg("Failed to fetch tenant Midtrans config")
-		return nil, fmt.Errorf("failed to get Midtrans configuration: %w", err)
-	}
-
-	if !midtransConfig.IsConfigured {
-		log.Error().Str("tenant_id", order.TenantID).Msg("Midtrans not configured for tenant")
-		return nil, fmt.Errorf("Midtrans is not configured for this tenant")
-	}
-
-	customerEmail := ""
-	if customerEmailPtr := order.CustomerEmail; customerEmailPtr != nil {
-		customerEmail = *customerEmailPtr
-	}
-
-	// Build charge request payload
-	chargeReq := coreapi.ChargeReq{
-		PaymentType: coreapi.PaymentTypeQris,
-		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  order.OrderReference,
-			GrossAmt: int64(order.TotalAmount),
-		},
-		CustomerDetails: &midtrans.CustomerDetails{
-			FName: order.CustomerName,
-			Phone: order.CustomerPhone,
-			Email: customerEmail,
-		},
-		Items: convertCartItemsToMidtransItems(items),
-	}
-
-	midtransCoreAPI, coreAPIClientErr := config.GetCoreAPIClientForTenant(ctx, order.TenantID)
-	if coreAPIClientErr != nil {
-		log.Error().Err(coreAPIClientErr).Str("tenant_id", order.TenantID).Msg("Failed to get Core API client for tenant")
-		return nil, fmt.Errorf("failed to get Core API client: %w", coreAPIClientErr)
-	}
-
-	// Execute request
-	resp, chargeErr := midtransCoreAPI.ChargeTransaction(&chargeReq)
-	if chargeErr != nil {
-		log.Error().Err(chargeErr).Msg("Failed to execute QRIS charge request")
-		return nil, fmt.Errorf("failed to execute request: %w", chargeErr)
-	}
-
-	// Check HTTP status
-	if resp.StatusCode != strconv.Itoa(http.StatusCreated) && resp.StatusCode != strconv.Itoa(http.StatusOK) {
-		log.Error().
-			Str("status_code", resp.StatusCode).
-			Str("status_message", resp.StatusMessage).
-			Str("transaction_id", resp.TransactionID).
-			Str("order_id", resp.OrderID).
-			Msg("QRIS charge request failed")
-		return nil, fmt.Errorf("charge request failed with status %s: %s", resp.StatusCode, resp.StatusMessage)
-	}
-
-	log.Info().
-		Str("tenant_id", order.TenantID).
-		Str("order_id", order.ID).
-		Str("order_reference", order.OrderReference).
-		Str("transaction_id", resp.TransactionID).
-		Str("qr_code_url", resp.Actions[0].URL).
-		Str("expiry_time", resp.ExpiryTime).
-		Msg("QRIS charge created successfully with tenant-specific credentials")
-
-	return resp, nil
-}
-
-// SaveQRISPaymentInfo saves QRIS payment information to database
-func (s *PaymentService) SaveQRISPaymentInfo(ctx context.Context, tx *sql.Tx, orderID string, amount int, chargeResp *coreapi.ChargeResponse) error {
-	// Parse expiry time - Midtrans returns time in Asia/Jakarta timezone (WIB)
-	// Since our database column is TIMESTAMP WITHOUT TIME ZONE, we need to convert to UTC
-	// loc, err := time.LoadLocation("Asia/Jakarta")
-	// if err != nil {
-	// 	log.Error().Err(err).Msg("Failed to load Asia/Jakarta location, using UTC fallback")
-	// 	loc = time.UTC
-	// }
-	// expiryTimeWIB, err := time.ParseInLocation("2006-01-02 15:04:05", chargeResp.ExpiryTime, loc)
-	// if err != nil {
-	// 	log.Error().Err(err).Str("expiry_time", chargeResp.ExpiryTime).Msg("Failed to parse expiry time")
-	// 	// Continue with nil expiry time rather than failing
-	// }
-
-	// Convert to UTC for storage (since DB column is WITHOUT TIME ZONE, it stores as-is)
-	// expiryTime := expiryTimeWIB.UTC()
-
-	// Get QR code URL from actions array (first action)
-	var qrCodeURL string
-	if len(chargeResp.Actions) > 0 {
-		qrCodeURL = chargeResp.Actions[0].URL
-	}
-
-	// Create payment transaction record
-	transactionID := chargeResp.TransactionID
-	paymentType := chargeResp.PaymentType
-	transactionStatus := chargeResp.TransactionStatus
-	fraudStatus := chargeResp.FraudStatus
-
-	// Marshal charge response to JSON for notification_payload
-	chargeJSON, err := json.Marshal(chargeResp)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to marshal charge response")
-		chargeJSON = json.RawMessage(`{}`) // Use empty JSON object as fallback
-	}
-
-	// Parse expiry time string to *time.Time default is 15 minutes from now with RFC3339 format
-	expiryTimePtr := time.Now().Add(15 * time.Minute)
-	if chargeResp.ExpiryTime != "" {
-		// Try parsing with common Midtrans format: "2006-01-02 15:04:05"
-		expiryTime, err := time.Parse("2006-01-02 15:04:05", chargeResp.ExpiryTime)
-		if err != nil {
-			log.Error().Err(err).Str("expiry_time", chargeResp.ExpiryTime).Msg("Failed to parse expiry time, storing as nil")
-		} else {
-			expiryTimePtr = expiryTime
-		}
-	}
-
-	// Generate idempotency key for initial pending status
-	idempotencyKey := transactionID + ":" + strings.ToLower(transactionStatus)
-
-	// Use expiryTimePtr as *time.Time for ExpiryTime
-	payment := &models.PaymentTransaction{
-		OrderID:               orderID,
-		MidtransTransactionID: &transactionID,
-		MidtransOrderID:       chargeResp.OrderID,
-		Amount:                amount,
-		PaymentType:           &paymentType,
-		TransactionStatus:     &transactionStatus,
-		FraudStatus:           &fraudStatus,
-		NotificationPayload:   chargeJSON,
-		QRCodeURL:             &qrCodeURL,
-		QRString:              &chargeResp.QRString,
-		ExpiryTime:            &expiryTimePtr,
-		SignatureVerified:     false, // Will be verified on webhook
-		IdempotencyKey:        &idempotencyKey,
-	}
-
-	err = s.paymentRepo.CreatePaymentTransaction(ctx, tx, payment)
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("order_id", orderID).
-			Str("transaction_id", chargeResp.TransactionID).
-			Msg("Failed to save QRIS payment info")
-		return fmt.Errorf("failed to save payment info: %w", err)
-	}
-
-	log.Info().
-		Str("order_id", orderID).
-		Str("transaction_id", chargeResp.TransactionID).
-		Str("qr_code_url", qrCodeURL).
-		Msg("QRIS payment info saved successfully")
-
-	return nil
-}
-
 // CreateSnapTransaction creates a Midtrans Snap transaction for QRIS payment
 // Implements T057-T058: Snap transaction creation with QRIS method
 func (s *PaymentService) CreateSnapTransaction(ctx context.Context, order *models.GuestOrder) (*snap.Response, error) {
@@ -279,41 +130,55 @@ func (s *PaymentService) CreateSnapTransaction(ctx context.Context, order *model
 	return snapResp, nil
 }
 
-// VerifySignature verifies Midtrans webhook signature using tenant-specific server key
-// Implements T059: SHA512 signature verification
-func (s *PaymentService) VerifySignature(ctx context.Context, tenantID, orderID, statusCode, grossAmount, signatureKey string) bool {
-	// Fetch tenant-specific Midtrans server key
-	serverKey, err := config.GetMidtransServerKeyForTenant(ctx, tenantID)
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("tenant_id", tenantID).
-			Str("order_id", orderID).
-			Msg("Failed to fetch tenant Midtrans server key for signature verification")
-		return false
-	}
+// CheckoutPaymentRequest describes the payment method chosen by the customer at checkout
+type CheckoutPaymentRequest struct {
+	Method models.CheckoutPaymentMethod
+	Bank   string        // Virtual account bank, required for bank_transfer
+	Expiry time.Duration // Tenant's payment expiry from order settings
+}
 
-	// Build signature string: order_id + status_code + gross_amount + server_key
-	signatureString := orderID + statusCode + grossAmount + serverKey
+// CreateCheckoutPayment starts an online payment with the tenant's chosen gateway
+// The returned transaction is not yet saved.
+func (s *PaymentService) CreateCheckoutPayment(ctx context.Context, order *models.GuestOrder, items []models.CartItem, req CheckoutPaymentRequest) (*models.PaymentTransaction, error) {
+	method := req.Method
+	if method == "" {
+		method = models.CheckoutPaymentQRIS
+	}
+	if err := models.ValidateCheckoutPayment(method, req.Bank); err != nil {
+		return nil, err
+	}
 
-	// Calculate SHA512 hash
-	hash := sha512.New()
-	hash.Write([]byte(signatureString))
-	calculatedSignature := hex.EncodeToString(hash.Sum(nil))
+	gateway, err := s.gatewayForTenant(ctx, order.TenantID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Compare signatures
-	isValid := calculatedSignature == signatureKey
+	return gateway.CreateCharge(ctx, &GatewayChargeRequest{
+		Order:  order,
+		Items:  items,
+		Method: method,
+		Bank:   req.Bank,
+		Expiry: req.Expiry,
+	})
+}
 
-	if !isValid {
-		log.Warn().
-			Str("tenant_id", tenantID).
-			Str("order_id", orderID).
-			Str("expected_signature", calculatedSignature).
-			Str("received_signature", signatureKey).
-			Msg("Signature verification failed")
+// SaveCheckoutPayment persists a payment transaction created at checkout
+func (s *PaymentService) SaveCheckoutPayment(ctx context.Context, tx *sql.Tx, payment *models.PaymentTransaction) error {
+	if err := s.paymentRepo.CreatePaymentTransaction(ctx, tx, payment); err != nil {
+		log.Error().
+			Err(err).
+			Str("order_id", payment.OrderID).
+			Str("payment_method", string(payment.PaymentMethod)).
+			Msg("Failed to save payment info")
+		return fmt.Errorf("failed to save payment info: %w", err)
 	}
 
-	return isValid
+	log.Info().
+		Str("order_id", payment.OrderID).
+		Str("payment_method", string(payment.PaymentMethod)).
+		Msg("Payment info saved successfully")
+
+	return nil
 }
 
 // MidtransNotification represents the webhook notification from Midtrans
@@ -332,9 +197,15 @@ type MidtransNotification struct {
 	Currency          string `json:"currency"`
 }
 
-// ProcessNotification processes Midtrans webhook notification
+// ProcessNotification processes a payment gateway webhook notification
+// Notifications from gateways other than Midtrans are normalized to MidtransNotification first.
 // Implements T060: Notification processing with idempotency, signature validation, status mapping
-func (s *PaymentService) ProcessNotification(ctx context.Context, notification *MidtransNotification) error {
+func (s *PaymentService) ProcessNotification(ctx context.Context, provider models.PaymentGatewayProvider, notification *MidtransNotification, headers http.Header) error {
+	gateway, err := s.gatewayByProvider(provider)
+	if err != nil {
+		return err
+	}
+
 	// Step 1: Check idempotency - have we processed this exact notification before?
 	idempotencyKey := notification.TransactionID + ":" + strings.ToLower(notification.TransactionStatus)
 	existing, err := s.paymentRepo.GetPaymentByIdempotencyKey(ctx, idempotencyKey)
@@ -355,7 +226,16 @@ func (s *PaymentService) ProcessNotification(ctx context.Context, notification *
 	}
 
 	// Step 2: Get order by order reference (need tenant ID for signature verification)
-	order, err := s.orderRepo.GetOrderByReference(ctx, notification.OrderID)
+	// Split payment parts and charges replaced after an order edit carry a numbered suffix
+	// after the order reference
+	orderReference, isSplitPart := models.ParseSplitPaymentOrderID(notification.OrderID)
+	if !isSplitPart {
+		orderReference = notification.OrderID
+		if reference, isRevised := models.ParseRevisedChargeOrderID(notification.OrderID); isRevised {
+			orderReference = reference
+		}
+	}
+	order, err := s.orderRepo.GetOrderByReference(ctx, orderReference)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -371,15 +251,8 @@ func (s *PaymentService) ProcessNotification(ctx context.Context, notification *
 		return fmt.Errorf("order not found")
 	}
 
-	// Step 3: Verify signature using tenant-specific server key
-	isValid := s.VerifySignature(
-		ctx,
-		order.TenantID,
-		notification.OrderID,
-		notification.StatusCode,
-		notification.GrossAmount,
-		notification.SignatureKey,
-	)
+	// Step 3: Verify the notification with the tenant's credentials for this gateway
+	isValid := gateway.VerifyWebhook(ctx, order.TenantID, notification, headers)
 
 	if !isValid {
 		log.Error().
@@ -390,6 +263,12 @@ func (s *PaymentService) ProcessNotification(ctx context.Context, notification *
 		return fmt.Errorf("invalid signature")
 	}
 
+	return s.applyPaymentStatus(ctx, order, notification, idempotencyKey, isSplitPart)
+}
+
+// applyPaymentStatus records a verified payment status and moves the order accordingly
+// Used for webhook notifications and for statuses fetched from the gateway by reconciliation.
+func (s *PaymentService) applyPaymentStatus(ctx context.Context, order *models.GuestOrder, notification *MidtransNotification, idempotencyKey string, isSplitPart bool) error {
 	// Step 4: Map Midtrans transaction status to order status and process
 	log.Info().
 		Str("order_reference", notification.OrderID).
@@ -403,38 +282,77 @@ func (s *PaymentService) ProcessNotification(ctx context.Context, notification *
 	notificationJSON, _ := json.Marshal(notification)
 
 	// Update payment transaction record
-	err = s.updatePaymentTransaction(ctx, notification, notificationJSON, idempotencyKey)
-	if err != nil {
+	if err := s.updatePaymentTransaction(ctx, notification, notificationJSON, idempotencyKey); err != nil {
 		return fmt.Errorf("failed to update payment transaction: %w", err)
 	}
 
-	// Process based on transaction status
-	switch strings.ToLower(notification.TransactionStatus) {
-	case "settlement", "capture":
+	// Push the gateway's transaction status to guests following the order
+	paymentStatus := strings.ToLower(notification.TransactionStatus)
+	s.orderService.broadcastStatus(ctx, models.OrderStatusEventPayment, order.OrderReference, order.Status, &paymentStatus)
+
+	// Process based on the per-method status mapping
+	switch models.MapMidtransStatus(notification.PaymentType, notification.TransactionStatus, notification.FraudStatus) {
+	case models.PaymentOutcomeSuccess:
+		if isSplitPart {
+			return s.handleSplitPaymentSettled(ctx, order, notification)
+		}
 		// Payment successful - update order to PAID and convert inventory reservations
 		return s.handlePaymentSuccess(ctx, order.ID, order.TenantID, notification)
 
-	case "pending":
-		// Payment still pending - keep reservation active
+	case models.PaymentOutcomePending:
+		// Payment still pending (or card challenged by fraud screening) - keep reservation active
 		log.Info().
 			Str("order_id", order.ID).
 			Str("order_reference", notification.OrderID).
+			Str("payment_type", notification.PaymentType).
+			Str("fraud_status", notification.FraudStatus).
 			Msg("Payment pending - reservation remains active")
 		return nil
 
-	case "cancel", "deny", "expire":
+	case models.PaymentOutcomeFailed:
+		if isSplitPart {
+			return s.handleSplitPaymentFailed(ctx, order, notification)
+		}
+		// A charge cancelled by an order edit has been replaced; the order stays open
+		if s.isSupersededCharge(ctx, order.ID, notification.OrderID) {
+			log.Info().
+				Str("order_id", order.ID).
+				Str("order_reference", notification.OrderID).
+				Str("transaction_status", notification.TransactionStatus).
+				Msg("Superseded charge closed - order awaits its replacement charge")
+			return nil
+		}
+		// Orders placed during a gateway outage are collected by staff; a lapsed QR does not cancel them
+		if order.PaymentFallback != nil {
+			log.Info().
+				Str("order_id", order.ID).
+				Str("order_reference", notification.OrderID).
+				Str("transaction_status", notification.TransactionStatus).
+				Msg("Charge for pay-at-counter order closed - order awaits payment by staff")
+			return nil
+		}
 		// Payment failed or expired - release inventory reservations
 		return s.handlePaymentFailure(ctx, order.ID, order.TenantID, notification)
 
 	default:
 		log.Warn().
 			Str("order_id", order.ID).
+			Str("payment_type", notification.PaymentType).
 			Str("transaction_status", notification.TransactionStatus).
 			Msg("Unknown transaction status - no action taken")
 		return nil
 	}
 }
 
+// isSupersededCharge reports whether a full-amount charge has been replaced by a newer one
+func (s *PaymentService) isSupersededCharge(ctx context.Context, orderID, gatewayOrderID string) bool {
+	current, err := s.paymentRepo.GetPaymentByOrderID(ctx, orderID)
+	if err != nil || current == nil {
+		return false
+	}
+	return current.MidtransOrderID != gatewayOrderID
+}
+
 // handlePaymentSuccess handles successful payment
 // Implements T061: Order status update for settlement
 // Implements T062: Inventory reservation conversion
@@ -452,7 +370,7 @@ func (s *PaymentService) handlePaymentSuccess(ctx context.Context, orderID, tena
 
 	// Step 2: Convert inventory reservations to permanent allocations
 	// This decrements product quantity and marks reservations as 'converted'
-	err = s.inventoryService.ConvertReservationsToPermanent(ctx, orderID)
+	err = s.inventoryService.ConvertReservationsToPermanent(ctx, tenantID, orderID)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -544,7 +462,7 @@ func (s *PaymentService) updatePaymentTransaction(
 	// Update existing payment transaction with new status and idempotency key
 	now := time.Now()
 	var settledAt *time.Time
-	if strings.ToLower(notification.TransactionStatus) == "settlement" || strings.ToLower(notification.TransactionStatus) == "capture" {
+	if models.MapMidtransStatus(notification.PaymentType, notification.TransactionStatus, notification.FraudStatus) == models.PaymentOutcomeSuccess {
 		settledAt = &now
 	}
 
@@ -555,18 +473,24 @@ func (s *PaymentService) updatePaymentTransaction(
 	defer tx.Rollback()
 
 	// Update payment status and idempotency key
+	// Snap (credit card) transactions only learn their transaction ID from the first notification
 	updateQuery := `
 			UPDATE payment_transactions
-			SET transaction_status = $2,
+			SET midtrans_transaction_id = $1,
+			    transaction_status = $2,
 			    settled_at = $3,
 			    notification_payload = $4,
 			    notification_received_at = NOW(),
 			    idempotency_key = $5,
 			    signature_key = $6,
-			    signature_verified = true
+			    signature_verified = true,
+			    payment_type = COALESCE(NULLIF($8, ''), payment_type),
+			    fraud_status = COALESCE(NULLIF($9, ''), fraud_status)
 			WHERE midtrans_transaction_id = $1
+			   OR (midtrans_transaction_id IS NULL AND midtrans_order_id = $7)
 		`
-	_, err = tx.ExecContext(ctx, updateQuery, transactionID, transactionStatus, settledAt, notificationJSON, idempotencyKey, signatureKey)
+	_, err = tx.ExecContext(ctx, updateQuery, transactionID, transactionStatus, settledAt, notificationJSON, idempotencyKey, signatureKey,
+		notification.OrderID, notification.PaymentType, notification.FraudStatus)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -581,3 +505,300 @@ func (s *PaymentService) updatePaymentTransaction(
 
 	return nil
 }
+
+// CashPaymentRequest represents a staff-recorded cash payment for an order
+type CashPaymentRequest struct {
+	OrderID          string  `json:"-"`
+	TenantID         string  `json:"-"`
+	RecordedByUserID string  `json:"-"`
+	AmountTendered   int     `json:"amount_tendered" validate:"required,min=1"`
+	ReceiptNumber    *string `json:"receipt_number,omitempty" validate:"omitempty,max=100"`
+	Notes            *string `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// CashPaymentResult summarizes a completed cash payment
+type CashPaymentResult struct {
+	OrderID        string                `json:"order_id"`
+	OrderReference string                `json:"order_reference"`
+	Status         models.OrderStatus    `json:"status"`
+	AmountDue      int                   `json:"amount_due"`
+	AmountTendered int                   `json:"amount_tendered"`
+	ChangeAmount   int                   `json:"change_amount"`
+	Payment        *models.PaymentRecord `json:"payment"`
+}
+
+// In-store payment errors
+var (
+	ErrOrderNotPayable = errors.New("order is not awaiting payment")
+	ErrOrderNotFound   = errors.New("order not found")
+)
+
+// ProcessCashPayment settles a pending order with cash collected in store
+// Only the balance left after earlier split payments is collected; the order is then
+// moved to PAID and its inventory reservations are converted. Midtrans is never contacted.
+func (s *PaymentService) ProcessCashPayment(ctx context.Context, req *CashPaymentRequest) (*CashPaymentResult, error) {
+	order, err := s.getPayableOrder(ctx, req.OrderID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	amountDue, err := s.getAmountDue(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	changeAmount, err := s.calculator.CalculateChange(amountDue, req.AmountTendered)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentRecordReq := &models.CreatePaymentRecordRequest{
+		OrderID:          order.ID,
+		AmountPaid:       amountDue,
+		PaymentMethod:    models.PaymentMethodCash,
+		RecordedByUserID: req.RecordedByUserID,
+		Notes:            req.Notes,
+		ReceiptNumber:    req.ReceiptNumber,
+		AmountTendered:   &req.AmountTendered,
+		ChangeAmount:     &changeAmount,
+	}
+
+	note := fmt.Sprintf("Paid in cash. Tendered: %d, change: %d.", req.AmountTendered, changeAmount)
+	payment, err := s.settleInStorePayment(ctx, order, paymentRecordReq, note)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("payment_record_id", payment.ID).
+		Int("amount_due", amountDue).
+		Int("amount_tendered", req.AmountTendered).
+		Int("change_amount", changeAmount).
+		Msg("Cash payment recorded - order PAID without Midtrans")
+
+	return &CashPaymentResult{
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Status:         orderStatusAfterPayment(payment),
+		AmountDue:      amountDue,
+		AmountTendered: req.AmountTendered,
+		ChangeAmount:   changeAmount,
+		Payment:        payment,
+	}, nil
+}
+
+// CardPaymentRequest represents a card payment taken on an external EDC terminal
+type CardPaymentRequest struct {
+	OrderID          string          `json:"-"`
+	TenantID         string          `json:"-"`
+	RecordedByUserID string          `json:"-"`
+	CardType         models.CardType `json:"card_type" validate:"required,oneof=visa mastercard jcb amex gpn other"`
+	ApprovalCode     string          `json:"approval_code" validate:"required,max=20"`
+	CardLast4        string          `json:"card_last4" validate:"required,len=4,numeric"`
+	ReceiptNumber    *string         `json:"receipt_number,omitempty" validate:"omitempty,max=100"`
+	Notes            *string         `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// CardPaymentResult summarizes a recorded EDC card payment
+type CardPaymentResult struct {
+	OrderID        string                `json:"order_id"`
+	OrderReference string                `json:"order_reference"`
+	Status         models.OrderStatus    `json:"status"`
+	AmountPaid     int                   `json:"amount_paid"`
+	Payment        *models.PaymentRecord `json:"payment"`
+}
+
+// ProcessCardPayment settles a pending order paid by card on a standalone EDC terminal
+// The terminal has already charged the card; we only record the slip details
+// (card type, approval code, last four digits) and close the order as PAID.
+func (s *PaymentService) ProcessCardPayment(ctx context.Context, req *CardPaymentRequest) (*CardPaymentResult, error) {
+	if err := models.ValidateCardDetails(req.CardType, req.ApprovalCode, req.CardLast4); err != nil {
+		return nil, err
+	}
+
+	order, err := s.getPayableOrder(ctx, req.OrderID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	amountDue, err := s.getAmountDue(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentRecordReq := &models.CreatePaymentRecordRequest{
+		OrderID:          order.ID,
+		AmountPaid:       amountDue,
+		PaymentMethod:    models.PaymentMethodCard,
+		RecordedByUserID: req.RecordedByUserID,
+		Notes:            req.Notes,
+		ReceiptNumber:    req.ReceiptNumber,
+		CardType:         &req.CardType,
+		ApprovalCode:     &req.ApprovalCode,
+		CardLast4:        &req.CardLast4,
+	}
+
+	note := fmt.Sprintf("Paid by card on EDC (%s ending %s). Approval code: %s.", req.CardType, req.CardLast4, req.ApprovalCode)
+	payment, err := s.settleInStorePayment(ctx, order, paymentRecordReq, note)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("payment_record_id", payment.ID).
+		Str("card_type", string(req.CardType)).
+		Int("amount_paid", amountDue).
+		Msg("EDC card payment recorded - order PAID without Midtrans")
+
+	return &CardPaymentResult{
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Status:         orderStatusAfterPayment(payment),
+		AmountPaid:     amountDue,
+		Payment:        payment,
+	}, nil
+}
+
+// orderStatusAfterPayment reports the order status implied by a recorded in-store payment
+func orderStatusAfterPayment(payment *models.PaymentRecord) models.OrderStatus {
+	if payment.RemainingBalanceAfter == 0 {
+		return models.OrderStatusPaid
+	}
+	return models.OrderStatusPending
+}
+
+// getAmountDue returns what is left to collect on an order after earlier split payments
+func (s *PaymentService) getAmountDue(ctx context.Context, order *models.GuestOrder) (int, error) {
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, nil, order.ID, order.TotalAmount)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get payment balance: %w", err)
+	}
+	if balance.Outstanding() == 0 {
+		// Pending QRIS parts already cover the rest of the total
+		return 0, models.ErrSplitAmountExceedsBalance
+	}
+	return balance.Outstanding(), nil
+}
+
+// getPayableOrder loads an order for in-store settlement, enforcing tenant isolation
+func (s *PaymentService) getPayableOrder(ctx context.Context, orderID, tenantID string) (*models.GuestOrder, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err == sql.ErrNoRows || (err == nil && order == nil) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// Tenant isolation
+	if order.TenantID != tenantID {
+		return nil, ErrOrderNotFound
+	}
+
+	if !order.RequiresPayment() {
+		return nil, ErrOrderNotPayable
+	}
+
+	return order, nil
+}
+
+// settleInStorePayment records a payment collected by staff and marks the order PAID
+// once recorded payments cover the total. The payment number and remaining balance are
+// assigned here under a row lock; the note is attached to the order for staff.
+func (s *PaymentService) settleInStorePayment(ctx context.Context, order *models.GuestOrder, paymentRecordReq *models.CreatePaymentRecordRequest, note string) (*models.PaymentRecord, error) {
+	// Step 1: Record the payment
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.paymentRepo.LockOrderForPayment(ctx, tx, order.ID); err != nil {
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, tx, order.ID, order.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment balance: %w", err)
+	}
+	if err := balance.ValidateSplitAmount(paymentRecordReq.AmountPaid); err != nil {
+		return nil, err
+	}
+	paymentRecordReq.RemainingBalanceAfter = balance.TotalAmount - balance.AmountPaid - paymentRecordReq.AmountPaid
+
+	paymentHistory, err := s.paymentRepo.GetPaymentHistory(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment history: %w", err)
+	}
+	paymentRecordReq.PaymentNumber = s.calculator.CalculateNextPaymentNumber(paymentHistory)
+
+	paymentRecordID, err := s.paymentRepo.RecordPayment(ctx, tx, paymentRecordReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record %s payment: %w", paymentRecordReq.PaymentMethod, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit %s payment: %w", paymentRecordReq.PaymentMethod, err)
+	}
+
+	if paymentRecordReq.RemainingBalanceAfter == 0 {
+		// Step 2: Update order status to PAID and convert reservations
+		if err := s.completeOrderPayment(ctx, order.ID, order.TenantID); err != nil {
+			log.Error().
+				Err(err).
+				Str("order_id", order.ID).
+				Str("payment_method", string(paymentRecordReq.PaymentMethod)).
+				Msg("Failed to update order status to PAID after in-store payment")
+			return nil, err
+		}
+	} else {
+		note += fmt.Sprintf(" Remaining balance: %d.", paymentRecordReq.RemainingBalanceAfter)
+	}
+
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, "System"); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add payment note")
+	}
+
+	now := time.Now()
+	return &models.PaymentRecord{
+		ID:                    paymentRecordID,
+		OrderID:               order.ID,
+		PaymentNumber:         paymentRecordReq.PaymentNumber,
+		AmountPaid:            paymentRecordReq.AmountPaid,
+		PaymentDate:           now,
+		PaymentMethod:         paymentRecordReq.PaymentMethod,
+		RemainingBalanceAfter: paymentRecordReq.RemainingBalanceAfter,
+		RecordedByUserID:      paymentRecordReq.RecordedByUserID,
+		Notes:                 paymentRecordReq.Notes,
+		ReceiptNumber:         paymentRecordReq.ReceiptNumber,
+		AmountTendered:        paymentRecordReq.AmountTendered,
+		ChangeAmount:          paymentRecordReq.ChangeAmount,
+		CardType:              paymentRecordReq.CardType,
+		ApprovalCode:          paymentRecordReq.ApprovalCode,
+		CardLast4:             paymentRecordReq.CardLast4,
+		CreatedAt:             now,
+	}, nil
+}
+
+// completeOrderPayment marks a fully paid order PAID (publishing order.paid) and
+// converts its inventory reservations to permanent allocations
+func (s *PaymentService) completeOrderPayment(ctx context.Context, orderID, tenantID string) error {
+	if err := s.orderService.UpdateOrderStatus(ctx, orderID, models.OrderStatusPaid); err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	if err := s.inventoryService.ConvertReservationsToPermanent(ctx, tenantID, orderID); err != nil {
+		log.Error().
+			Err(err).
+			Str("order_id", orderID).
+			Str("tenant_id", tenantID).
+			Msg("Failed to convert inventory reservations - order is PAID but inventory not updated")
+		// Order is already PAID, so we log error but don't fail the payment
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/receipt"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// printJobListLimit bounds the print job history returned to staff
+const printJobListLimit = 100
+
+// PrintService renders receipts and kitchen tickets and queues them for a tenant's print agent
+type PrintService struct {
+	printRepo    *repository.PrintJobRepository
+	orderRepo    *repository.OrderRepository
+	settingsRepo *repository.OrderSettingsRepository
+	brandingRepo *repository.InvoiceBrandingRepository
+}
+
+// NewPrintService creates a new print service
+func NewPrintService(printRepo *repository.PrintJobRepository, orderRepo *repository.OrderRepository, settingsRepo *repository.OrderSettingsRepository, brandingRepo *repository.InvoiceBrandingRepository) *PrintService {
+	return &PrintService{
+		printRepo:    printRepo,
+		orderRepo:    orderRepo,
+		settingsRepo: settingsRepo,
+		brandingRepo: brandingRepo,
+	}
+}
+
+// QueueOrder renders the requested documents for a paid order and queues them in order
+func (s *PrintService) QueueOrder(ctx context.Context, tenantID, orderID string, req *models.CreatePrintJobsRequest) ([]*models.PrintJob, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && order.TenantID != tenantID) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != models.OrderStatusPaid && order.Status != models.OrderStatusComplete {
+		return nil, models.ErrOrderNotPrintable
+	}
+
+	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	store, err := s.store(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*models.PrintJob, 0, len(req.Kinds))
+	for _, kind := range req.Kinds {
+		job := &models.PrintJob{
+			TenantID:       tenantID,
+			OrderID:        orderID,
+			OrderReference: order.OrderReference,
+			Kind:           kind,
+			PaperWidth:     req.PaperWidth,
+		}
+		switch kind {
+		case models.PrintJobKitchenTicket:
+			job.Payload = receipt.KitchenTicket(store, order, items, req.PaperWidth)
+		default:
+			job.Payload = receipt.CustomerReceipt(store, order, items, req.PaperWidth)
+		}
+
+		if err := s.printRepo.Create(ctx, job); err != nil {
+			return nil, fmt.Errorf("failed to queue %s: %w", kind, err)
+		}
+		// The payload goes to the print agent only
+		job.Payload = nil
+		jobs = append(jobs, job)
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("order_reference", order.OrderReference).
+		Int("jobs", len(jobs)).
+		Msg("Print jobs queued")
+
+	return jobs, nil
+}
+
+// store returns the receipt header details of a tenant
+func (s *PrintService) store(ctx context.Context, tenantID string) (receipt.Store, error) {
+	branding, err := s.brandingRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return receipt.Store{}, fmt.Errorf("failed to get store branding: %w", err)
+	}
+
+	settings, err := s.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return receipt.Store{}, fmt.Errorf("failed to get order settings: %w", err)
+	}
+
+	return receipt.Store{
+		Name:        branding.Name(),
+		Location:    settings.Location(),
+		Header:      branding.ReceiptHeader,
+		Footer:      branding.ReceiptFooter,
+		SocialLinks: branding.SocialLinks,
+	}, nil
+}
+
+// ListJobs returns a tenant's most recent print jobs
+func (s *PrintService) ListJobs(ctx context.Context, tenantID string, status *models.PrintJobStatus) ([]*models.PrintJob, error) {
+	if status != nil && !status.IsValid() {
+		return nil, fmt.Errorf("%w: status must be pending, printing, printed or failed", models.ErrInvalidPrintJob)
+	}
+	return s.printRepo.List(ctx, tenantID, status, printJobListLimit)
+}
+
+// ClaimNext hands the oldest waiting job to a print agent; nil means the queue is empty
+func (s *PrintService) ClaimNext(ctx context.Context, tenantID string) (*models.PrintJob, error) {
+	return s.printRepo.ClaimNext(ctx, tenantID, models.PrintClaimTimeout, models.MaxPrintAttempts)
+}
+
+// Acknowledge records whether a print agent printed a claimed job
+func (s *PrintService) Acknowledge(ctx context.Context, tenantID, jobID string, req *models.AckPrintJobRequest) (*models.PrintJob, error) {
+	job, err := s.printRepo.Acknowledge(ctx, tenantID, jobID, req, models.MaxPrintAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status == models.PrintJobFailed {
+		log.Warn().
+			Str("tenant_id", tenantID).
+			Str("print_job_id", jobID).
+			Str("order_reference", job.OrderReference).
+			Msg("Print job failed after all attempts")
+	}
+	return job, nil
+}
+
+// Retry queues a printed or failed job again
+func (s *PrintService) Retry(ctx context.Context, tenantID, jobID string) (*models.PrintJob, error) {
+	return s.printRepo.Retry(ctx, tenantID, jobID)
+}
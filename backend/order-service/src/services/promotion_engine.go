@@ -0,0 +1,205 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// PromotionLine is a cart line as seen by the promotion engine
+type PromotionLine struct {
+	ProductID  string
+	CategoryID string // Empty when the product has no category
+	UnitPrice  int
+	Quantity   int
+}
+
+// ApplyPromotions works out which promotions apply to the lines and the discount each gives
+//
+// Stacking rules:
+//   - Promotions are applied in priority order (highest first); each one only
+//     discounts what is left of a line after the promotions before it, so a line
+//     is never discounted below zero.
+//   - Stackable promotions all combine.
+//   - A non-stackable promotion never combines with anything. The best one is
+//     used instead of the stackable set only when it gives a larger discount.
+func ApplyPromotions(promotions []*models.Promotion, lines []PromotionLine, now time.Time) []models.AppliedPromotion {
+	subtotal := 0
+	for _, line := range lines {
+		subtotal += line.UnitPrice * line.Quantity
+	}
+	if subtotal <= 0 {
+		return nil
+	}
+
+	candidates := make([]*models.Promotion, 0, len(promotions))
+	for _, p := range promotions {
+		if p.ActiveAt(now) && subtotal >= p.MinSubtotal {
+			candidates = append(candidates, p)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority > candidates[j].Priority
+	})
+
+	stacked := []models.AppliedPromotion{}
+	stackedTotal := 0
+	remaining := lineAmounts(lines)
+	for _, p := range candidates {
+		if !p.Stackable {
+			continue
+		}
+		if discount := evaluatePromotion(p, lines, remaining); discount > 0 {
+			stacked = append(stacked, appliedPromotion(p, discount))
+			stackedTotal += discount
+		}
+	}
+
+	var best *models.AppliedPromotion
+	for _, p := range candidates {
+		if p.Stackable {
+			continue
+		}
+		discount := evaluatePromotion(p, lines, lineAmounts(lines))
+		if discount > stackedTotal && (best == nil || discount > best.DiscountAmount) {
+			applied := appliedPromotion(p, discount)
+			best = &applied
+		}
+	}
+
+	if best != nil {
+		return []models.AppliedPromotion{*best}
+	}
+	return stacked
+}
+
+// TotalPromotionDiscount sums the discounts of applied promotions
+func TotalPromotionDiscount(applied []models.AppliedPromotion) int {
+	total := 0
+	for _, a := range applied {
+		total += a.DiscountAmount
+	}
+	return total
+}
+
+func appliedPromotion(p *models.Promotion, discount int) models.AppliedPromotion {
+	return models.AppliedPromotion{
+		PromotionID:    p.ID,
+		Name:           p.Name,
+		PromotionType:  p.PromotionType,
+		DiscountAmount: discount,
+	}
+}
+
+func lineAmounts(lines []PromotionLine) []int {
+	amounts := make([]int, len(lines))
+	for i, line := range lines {
+		amounts[i] = line.UnitPrice * line.Quantity
+	}
+	return amounts
+}
+
+// promotionTargets reports whether a promotion's scope covers a line
+func promotionTargets(p *models.Promotion, line PromotionLine) bool {
+	switch p.Scope {
+	case models.PromotionScopeCategory:
+		return p.CategoryID != nil && line.CategoryID != "" && *p.CategoryID == line.CategoryID
+	case models.PromotionScopeProduct:
+		return p.ProductID != nil && *p.ProductID == line.ProductID
+	default:
+		return true
+	}
+}
+
+// evaluatePromotion returns the promotion's discount and takes it off remaining
+func evaluatePromotion(p *models.Promotion, lines []PromotionLine, remaining []int) int {
+	eligible := []int{}
+	for i, line := range lines {
+		if remaining[i] > 0 && line.Quantity > 0 && promotionTargets(p, line) {
+			eligible = append(eligible, i)
+		}
+	}
+	if len(eligible) == 0 {
+		return 0
+	}
+
+	switch p.PromotionType {
+	case models.PromotionPercentOff:
+		capLeft := -1
+		if p.MaxDiscountAmount != nil {
+			capLeft = *p.MaxDiscountAmount
+		}
+		total := 0
+		for _, i := range eligible {
+			discount := remaining[i] * p.DiscountValue / 100
+			if capLeft >= 0 && discount > capLeft {
+				discount = capLeft
+			}
+			remaining[i] -= discount
+			total += discount
+			if capLeft >= 0 {
+				capLeft -= discount
+			}
+		}
+		return total
+
+	case models.PromotionFixedOff:
+		left := p.DiscountValue
+		total := 0
+		for _, i := range eligible {
+			discount := left
+			if discount > remaining[i] {
+				discount = remaining[i]
+			}
+			remaining[i] -= discount
+			total += discount
+			left -= discount
+			if left == 0 {
+				break
+			}
+		}
+		return total
+
+	case models.PromotionBOGO:
+		return evaluateBOGO(p, lines, remaining, eligible)
+	}
+
+	return 0
+}
+
+// evaluateBOGO discounts the cheapest units of every buy+get group of eligible units
+func evaluateBOGO(p *models.Promotion, lines []PromotionLine, remaining []int, eligible []int) int {
+	if p.BuyQuantity == nil || p.GetQuantity == nil {
+		return 0
+	}
+	groupSize := *p.BuyQuantity + *p.GetQuantity
+
+	type unit struct {
+		line  int
+		price int
+	}
+	units := []unit{}
+	for _, i := range eligible {
+		price := remaining[i] / lines[i].Quantity // Unit price after earlier promotions
+		for q := 0; q < lines[i].Quantity; q++ {
+			units = append(units, unit{line: i, price: price})
+		}
+	}
+	sort.SliceStable(units, func(a, b int) bool {
+		return units[a].price > units[b].price
+	})
+
+	total := 0
+	for start := 0; start+groupSize <= len(units); start += groupSize {
+		for _, u := range units[start+*p.BuyQuantity : start+groupSize] {
+			discount := u.price * p.DiscountValue / 100
+			if discount > remaining[u.line] {
+				discount = remaining[u.line]
+			}
+			remaining[u.line] -= discount
+			total += discount
+		}
+	}
+	return total
+}
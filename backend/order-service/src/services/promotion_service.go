@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// PromotionService manages tenant promotions and prices carts with them
+type PromotionService struct {
+	promotionRepo *repository.PromotionRepository
+}
+
+// NewPromotionService creates a new promotion service
+func NewPromotionService(promotionRepo *repository.PromotionRepository) *PromotionService {
+	return &PromotionService{
+		promotionRepo: promotionRepo,
+	}
+}
+
+// CreatePromotion validates and creates a promotion
+func (s *PromotionService) CreatePromotion(ctx context.Context, tenantID string, req *models.PromotionRequest) (*models.Promotion, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.promotionRepo.Create(ctx, tenantID, req)
+}
+
+// UpdatePromotion validates and replaces a promotion's settings
+func (s *PromotionService) UpdatePromotion(ctx context.Context, tenantID, promotionID string, req *models.PromotionRequest) (*models.Promotion, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.promotionRepo.Update(ctx, tenantID, promotionID, req)
+}
+
+// GetPromotion retrieves a tenant's promotion
+func (s *PromotionService) GetPromotion(ctx context.Context, tenantID, promotionID string) (*models.Promotion, error) {
+	return s.promotionRepo.GetByID(ctx, tenantID, promotionID)
+}
+
+// ListPromotions lists a tenant's promotions
+func (s *PromotionService) ListPromotions(ctx context.Context, tenantID string, activeOnly bool) ([]*models.Promotion, error) {
+	return s.promotionRepo.List(ctx, tenantID, activeOnly)
+}
+
+// DeletePromotion deletes a promotion
+func (s *PromotionService) DeletePromotion(ctx context.Context, tenantID, promotionID string) error {
+	return s.promotionRepo.Delete(ctx, tenantID, promotionID)
+}
+
+// ListOrderPromotions returns the promotions applied to an order
+func (s *PromotionService) ListOrderPromotions(ctx context.Context, tenantID, orderID string) ([]*models.OrderPromotion, error) {
+	return s.promotionRepo.ListForOrder(ctx, tenantID, orderID)
+}
+
+// PriceCart applies the tenant's promotions to the cart as of now
+// Sets cart.Promotions and cart.PromotionDiscount; the cart is not saved.
+func (s *PromotionService) PriceCart(ctx context.Context, cart *models.Cart) error {
+	cart.Promotions = nil
+	cart.PromotionDiscount = 0
+	if len(cart.Items) == 0 {
+		return nil
+	}
+
+	promotions, err := s.promotionRepo.ListApplicable(ctx, cart.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to load promotions: %w", err)
+	}
+	if len(promotions) == 0 {
+		return nil
+	}
+
+	categories := map[string]string{}
+	for _, p := range promotions {
+		if p.Scope == models.PromotionScopeCategory {
+			productIDs := make([]string, 0, len(cart.Items))
+			for _, item := range cart.Items {
+				productIDs = append(productIDs, item.ProductID)
+			}
+			if categories, err = s.promotionRepo.GetProductCategories(ctx, cart.TenantID, productIDs); err != nil {
+				return fmt.Errorf("failed to load product categories: %w", err)
+			}
+			break
+		}
+	}
+
+	lines := make([]PromotionLine, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		lines = append(lines, PromotionLine{
+			ProductID:  item.ProductID,
+			CategoryID: categories[item.ProductID],
+			UnitPrice:  item.UnitPrice,
+			Quantity:   item.Quantity,
+		})
+	}
+
+	cart.Promotions = ApplyPromotions(promotions, lines, time.Now())
+	cart.PromotionDiscount = TotalPromotionDiscount(cart.Promotions)
+	return nil
+}
+
+// RecordForOrder stores the cart's applied promotions against an order inside the checkout transaction
+func (s *PromotionService) RecordForOrder(ctx context.Context, tx *sql.Tx, tenantID, orderID string, applied []models.AppliedPromotion) error {
+	if len(applied) == 0 {
+		return nil
+	}
+	return s.promotionRepo.RecordForOrder(ctx, tx, tenantID, orderID, applied)
+}
+
+// ReplaceForOrder swaps the promotions recorded against an order after it was repriced
+func (s *PromotionService) ReplaceForOrder(ctx context.Context, tx *sql.Tx, tenantID, orderID string, applied []models.AppliedPromotion) error {
+	if err := s.promotionRepo.DeleteForOrder(ctx, tx, tenantID, orderID); err != nil {
+		return err
+	}
+	return s.RecordForOrder(ctx, tx, tenantID, orderID, applied)
+}
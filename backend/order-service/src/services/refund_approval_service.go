@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/queue"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// RefundApprovalService puts gateway refunds behind a request/approve step
+// Staff whose role may approve a refund of the requested size execute it straight away;
+// everyone else files a request that an approver executes or rejects. Refunds above the
+// tenant's refund_approval_threshold can only be approved by an owner.
+type RefundApprovalService struct {
+	db             *sql.DB
+	approvalRepo   *repository.RefundApprovalRepository
+	settingsRepo   *repository.OrderSettingsRepository
+	paymentService *PaymentService
+	orderService   *OrderService
+	kafkaProducer  *queue.KafkaProducer
+}
+
+// NewRefundApprovalService creates a new refund approval service
+func NewRefundApprovalService(
+	db *sql.DB,
+	approvalRepo *repository.RefundApprovalRepository,
+	settingsRepo *repository.OrderSettingsRepository,
+	paymentService *PaymentService,
+	orderService *OrderService,
+	kafkaProducer *queue.KafkaProducer,
+) *RefundApprovalService {
+	return &RefundApprovalService{
+		db:             db,
+		approvalRepo:   approvalRepo,
+		settingsRepo:   settingsRepo,
+		paymentService: paymentService,
+		orderService:   orderService,
+		kafkaProducer:  kafkaProducer,
+	}
+}
+
+// RefundActor is the staff member requesting or deciding a refund
+type RefundActor struct {
+	UserID string
+	Name   string
+	Role   string // owner, manager or cashier as set by the API gateway
+}
+
+// refundPlan is a validated refund: its amount and who may approve it
+type refundPlan struct {
+	order        *models.GuestOrder
+	amount       int
+	approverRole string
+}
+
+// plan checks a refund against the order's payment and the tenant's approval threshold
+func (s *RefundApprovalService) plan(ctx context.Context, req *RefundRequest) (*refundPlan, error) {
+	order, payment, err := s.paymentService.GetSettledOnlinePayment(ctx, req.OrderID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = payment.Amount
+	}
+	if amount < 0 || amount > payment.Amount {
+		return nil, models.ErrInvalidPaymentAmount
+	}
+
+	committed, err := s.approvalRepo.GetCommittedAmount(ctx, order.ID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get committed refunds: %w", err)
+	}
+	if committed+amount > payment.Amount {
+		return nil, models.ErrRefundExceedsPayment
+	}
+
+	settings, err := s.settingsRepo.GetOrCreate(ctx, req.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order settings: %w", err)
+	}
+
+	return &refundPlan{
+		order:        order,
+		amount:       amount,
+		approverRole: models.RefundApproverRole(amount, settings.RefundApprovalThreshold),
+	}, nil
+}
+
+// RequestRefund refunds an order's online payment, or files a request when the actor may not
+// Returns the gateway refund when it was executed, nil when the request awaits approval.
+func (s *RefundApprovalService) RequestRefund(ctx context.Context, req *RefundRequest, actor RefundActor) (*models.RefundApproval, *GatewayRefund, error) {
+	plan, err := s.plan(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if models.CanApproveRefund(actor.Role, plan.approverRole) {
+		return s.execute(ctx, req, plan, actor)
+	}
+
+	approval := &models.RefundApproval{
+		TenantID:        req.TenantID,
+		OrderID:         plan.order.ID,
+		OrderReference:  plan.order.OrderReference,
+		Amount:          plan.amount,
+		Reason:          req.Reason,
+		Status:          models.RefundApprovalPending,
+		ApproverRole:    plan.approverRole,
+		RequestedBy:     optionalString(actor.UserID),
+		RequestedByName: optionalString(actor.Name),
+		RequestedByRole: actor.Role,
+	}
+	if err := s.approvalRepo.Create(ctx, approval); err != nil {
+		return nil, nil, err
+	}
+
+	note := fmt.Sprintf("Refund of %d requested, waiting for %s approval. Reason: %s", approval.Amount, approval.ApproverRole, approval.Reason)
+	if err := s.orderService.AddOrderNote(ctx, approval.OrderID, note, actor.Name); err != nil {
+		log.Warn().Err(err).Str("order_id", approval.OrderID).Msg("Failed to add refund request note")
+	}
+
+	s.publishRequested(ctx, approval)
+
+	log.Info().
+		Str("approval_id", approval.ID).
+		Str("order_id", approval.OrderID).
+		Int("amount", approval.Amount).
+		Str("approver_role", approval.ApproverRole).
+		Str("requested_by", actor.UserID).
+		Msg("Refund request waiting for approval")
+
+	return approval, nil, nil
+}
+
+// RefundNow refunds an order's online payment when the actor may approve it themselves
+// Used by flows that cannot wait for an approval, such as completing a return.
+func (s *RefundApprovalService) RefundNow(ctx context.Context, req *RefundRequest, actor RefundActor) (*GatewayRefund, error) {
+	plan, err := s.plan(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanApproveRefund(actor.Role, plan.approverRole) {
+		return nil, models.ErrRefundApprovalRequired
+	}
+
+	_, refund, err := s.execute(ctx, req, plan, actor)
+	return refund, err
+}
+
+// execute sends the refund to the gateway and records it as approved by the actor
+func (s *RefundApprovalService) execute(ctx context.Context, req *RefundRequest, plan *refundPlan, actor RefundActor) (*models.RefundApproval, *GatewayRefund, error) {
+	refund, err := s.paymentService.RefundOnlinePayment(ctx, &RefundRequest{
+		OrderID:  req.OrderID,
+		TenantID: req.TenantID,
+		NotedBy:  actor.Name,
+		Amount:   plan.amount,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	gateway := string(refund.Gateway)
+	approval := &models.RefundApproval{
+		TenantID:        req.TenantID,
+		OrderID:         plan.order.ID,
+		OrderReference:  plan.order.OrderReference,
+		Amount:          refund.Amount,
+		Reason:          req.Reason,
+		Status:          models.RefundApprovalApproved,
+		ApproverRole:    plan.approverRole,
+		RequestedBy:     optionalString(actor.UserID),
+		RequestedByName: optionalString(actor.Name),
+		RequestedByRole: actor.Role,
+		DecidedBy:       optionalString(actor.UserID),
+		DecidedByName:   optionalString(actor.Name),
+		RefundID:        &refund.RefundID,
+		Gateway:         &gateway,
+		DecidedAt:       &now,
+	}
+	if err := s.approvalRepo.Create(ctx, approval); err != nil {
+		// The money has moved; the audit row is not worth failing the refund over
+		log.Error().Err(err).
+			Str("order_id", plan.order.ID).
+			Str("refund_id", refund.RefundID).
+			Msg("Refund was issued but could not be recorded")
+	}
+
+	return approval, refund, nil
+}
+
+// ApproveRefund executes a pending refund request
+// The request stays pending when the gateway refuses the refund so it can be retried.
+func (s *RefundApprovalService) ApproveRefund(ctx context.Context, tenantID, approvalID string, req *models.RefundDecisionRequest, actor RefundActor) (*models.RefundApproval, *GatewayRefund, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	approval, err := s.lockPending(ctx, tx, tenantID, approvalID, actor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	committed, err := s.approvalRepo.GetCommittedAmount(ctx, approval.OrderID, approval.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get committed refunds: %w", err)
+	}
+	_, payment, err := s.paymentService.GetSettledOnlinePayment(ctx, approval.OrderID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if committed+approval.Amount > payment.Amount {
+		return nil, nil, models.ErrRefundExceedsPayment
+	}
+
+	refund, err := s.paymentService.RefundOnlinePayment(ctx, &RefundRequest{
+		OrderID:  approval.OrderID,
+		TenantID: tenantID,
+		NotedBy:  actor.Name,
+		Amount:   approval.Amount,
+		Reason:   approval.Reason,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gateway := string(refund.Gateway)
+	approval.Status = models.RefundApprovalApproved
+	approval.RefundID = &refund.RefundID
+	approval.Gateway = &gateway
+	s.decide(approval, req, actor)
+
+	if err := s.approvalRepo.Decide(ctx, tx, approval); err != nil {
+		return nil, nil, fmt.Errorf("failed to update refund request: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).
+			Str("approval_id", approval.ID).
+			Str("refund_id", refund.RefundID).
+			Msg("Refund was issued but the request could not be marked approved")
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Info().
+		Str("approval_id", approval.ID).
+		Str("order_id", approval.OrderID).
+		Str("refund_id", refund.RefundID).
+		Str("approved_by", actor.UserID).
+		Msg("Refund request approved")
+
+	return approval, refund, nil
+}
+
+// RejectRefund closes a pending refund request without refunding
+func (s *RefundApprovalService) RejectRefund(ctx context.Context, tenantID, approvalID string, req *models.RefundDecisionRequest, actor RefundActor) (*models.RefundApproval, error) {
+	if strings.TrimSpace(req.Note) == "" {
+		return nil, models.ErrRefundRejectionNote
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	approval, err := s.lockPending(ctx, tx, tenantID, approvalID, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	approval.Status = models.RefundApprovalRejected
+	s.decide(approval, req, actor)
+
+	if err := s.approvalRepo.Decide(ctx, tx, approval); err != nil {
+		return nil, fmt.Errorf("failed to update refund request: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	note := fmt.Sprintf("Refund request of %d rejected. Reason: %s", approval.Amount, req.Note)
+	if err := s.orderService.AddOrderNote(ctx, approval.OrderID, note, actor.Name); err != nil {
+		log.Warn().Err(err).Str("order_id", approval.OrderID).Msg("Failed to add refund rejection note")
+	}
+
+	log.Info().
+		Str("approval_id", approval.ID).
+		Str("order_id", approval.OrderID).
+		Str("rejected_by", actor.UserID).
+		Msg("Refund request rejected")
+
+	return approval, nil
+}
+
+// GetRefundApproval returns one of a tenant's refund requests
+func (s *RefundApprovalService) GetRefundApproval(ctx context.Context, tenantID, approvalID string) (*models.RefundApproval, error) {
+	return s.approvalRepo.Get(ctx, nil, tenantID, approvalID)
+}
+
+// ListRefundApprovals returns a tenant's refund requests, optionally by status
+func (s *RefundApprovalService) ListRefundApprovals(ctx context.Context, tenantID string, status *models.RefundApprovalStatus, limit, offset int) ([]*models.RefundApproval, error) {
+	return s.approvalRepo.List(ctx, tenantID, status, limit, offset)
+}
+
+// ListOrderRefundApprovals returns the refund requests filed against an order
+func (s *RefundApprovalService) ListOrderRefundApprovals(ctx context.Context, tenantID, orderID string) ([]*models.RefundApproval, error) {
+	return s.approvalRepo.ListByOrder(ctx, tenantID, orderID)
+}
+
+// lockPending locks a refund request the actor is about to decide
+func (s *RefundApprovalService) lockPending(ctx context.Context, tx *sql.Tx, tenantID, approvalID string, actor RefundActor) (*models.RefundApproval, error) {
+	approval, err := s.approvalRepo.Get(ctx, tx, tenantID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Status != models.RefundApprovalPending {
+		return nil, models.ErrRefundApprovalNotPending
+	}
+	if !models.CanApproveRefund(actor.Role, approval.ApproverRole) {
+		return nil, models.ErrRefundApprovalForbidden
+	}
+	return approval, nil
+}
+
+// decide stamps who decided a refund request and when
+func (s *RefundApprovalService) decide(approval *models.RefundApproval, req *models.RefundDecisionRequest, actor RefundActor) {
+	now := time.Now()
+	approval.DecidedBy = optionalString(actor.UserID)
+	approval.DecidedByName = optionalString(actor.Name)
+	approval.DecisionNote = optionalString(strings.TrimSpace(req.Note))
+	approval.DecidedAt = &now
+}
+
+// publishRequested asks the notification service to email the staff who can approve a request
+func (s *RefundApprovalService) publishRequested(ctx context.Context, approval *models.RefundApproval) {
+	if s.kafkaProducer == nil {
+		return
+	}
+
+	requestedBy := ""
+	if approval.RequestedByName != nil {
+		requestedBy = *approval.RequestedByName
+	}
+	event := map[string]interface{}{
+		"event_type": "refund.approval_requested",
+		"tenant_id":  approval.TenantID,
+		"user_id":    "",
+		"data": map[string]interface{}{
+			"approval_id":       approval.ID,
+			"order_id":          approval.OrderID,
+			"order_reference":   approval.OrderReference,
+			"amount":            approval.Amount,
+			"reason":            approval.Reason,
+			"approver_role":     approval.ApproverRole,
+			"requested_by_name": requestedBy,
+			"requested_by_role": approval.RequestedByRole,
+		},
+	}
+	if approval.RequestedBy != nil {
+		event["user_id"] = *approval.RequestedBy
+	}
+	if err := s.kafkaProducer.Publish(ctx, approval.TenantID, event); err != nil {
+		log.Warn().Err(err).Str("approval_id", approval.ID).Msg("Failed to publish refund.approval_requested event")
+	}
+}
+
+// optionalString returns nil for an empty string so the column stays NULL
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// ReorderService rebuilds a guest's cart from one of their past orders
+// Items are added at today's price and capped at today's stock, and everything that
+// differs from the past order is reported back so the guest can review it before checkout.
+type ReorderService struct {
+	orderRepo       *repository.OrderRepository
+	reservationRepo *repository.ReservationRepository
+	cartRepo        *repository.CartRepository
+	cartService     *CartService
+}
+
+// NewReorderService creates a new re-order service
+func NewReorderService(
+	orderRepo *repository.OrderRepository,
+	reservationRepo *repository.ReservationRepository,
+	cartRepo *repository.CartRepository,
+	cartService *CartService,
+) *ReorderService {
+	return &ReorderService{
+		orderRepo:       orderRepo,
+		reservationRepo: reservationRepo,
+		cartRepo:        cartRepo,
+		cartService:     cartService,
+	}
+}
+
+// Reorder adds the items of a past order to the guest's cart
+// The order reference is the guest's proof of ownership, as on the public order page.
+// Items already in the cart keep the larger of the two quantities, as when carts merge.
+func (s *ReorderService) Reorder(ctx context.Context, tenantID, cartID, orderReference string) (*models.ReorderResult, error) {
+	order, err := s.orderRepo.GetOrderByReference(ctx, orderReference)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (order == nil || order.TenantID != tenantID)) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	productIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	products, err := s.reservationRepo.GetSellableProducts(ctx, tenantID, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cartItems, substitutions := models.PlanReorder(items, products)
+
+	cart, err := s.cartRepo.Get(ctx, tenantID, cartID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	cart.Merge(&models.Cart{Items: cartItems})
+
+	if err := s.cartService.validateItemLimit(ctx, tenantID, cart.GetItemCount()); err != nil {
+		return nil, err
+	}
+	// Items already in the cart may need capping too now that they hold more units
+	if err := s.cartService.ValidateAndAdjustCart(ctx, cart); err != nil {
+		return nil, err
+	}
+	if err := s.cartRepo.Save(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to save cart: %w", err)
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("order_reference", orderReference).
+		Int("item_count", len(cartItems)).
+		Int("substitution_count", len(substitutions)).
+		Msg("Rebuilt cart from past order")
+
+	s.cartService.priceCart(ctx, cart)
+	return &models.ReorderResult{
+		Cart:          cart,
+		Substitutions: substitutions,
+	}, nil
+}
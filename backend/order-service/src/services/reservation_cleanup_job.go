@@ -7,6 +7,11 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// ReservationCleanupJob releases checkout stock holds once their payment can no longer be made
+// Each reservation carries its own expiry (the tenant's reservation TTL, the virtual account
+// or manual-order TTL, plus any extensions granted to the order), so the job follows
+// per-tenant settings and per-order extensions without reading them. It runs every minute,
+// well inside the shortest payment expiry allowed.
 type ReservationCleanupJob struct {
 	inventoryService *InventoryService
 	interval         time.Duration
@@ -69,8 +74,8 @@ func (j *ReservationCleanupJob) cleanupExpiredReservations(ctx context.Context)
 	failedCount := 0
 
 	for _, reservation := range reservations {
-		// Release the reservation
-		err := j.inventoryService.reservationRepo.ReleaseReservation(ctx, nil, reservation.ID)
+		// Release the reservation unless the order's hold was extended or settled since it was read
+		released, err := j.inventoryService.reservationRepo.ReleaseExpiredReservation(ctx, reservation.ID)
 		if err != nil {
 			log.Error().Err(err).
 				Str("reservation_id", reservation.ID).
@@ -80,6 +85,13 @@ func (j *ReservationCleanupJob) cleanupExpiredReservations(ctx context.Context)
 			failedCount++
 			continue
 		}
+		if !released {
+			log.Debug().
+				Str("reservation_id", reservation.ID).
+				Str("order_id", reservation.OrderID).
+				Msg("Reservation was extended or settled before release")
+			continue
+		}
 
 		log.Info().
 			Str("reservation_id", reservation.ID).
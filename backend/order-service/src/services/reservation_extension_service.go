@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// ReservationExtensionService lets guests keep their stock held while they finish paying
+// Each extension adds the tenant's reservation_extension_minutes to the order's unexpired
+// holds, up to models.MaxReservationExtensions times per order.
+type ReservationExtensionService struct {
+	orderRepo       *repository.OrderRepository
+	reservationRepo *repository.ReservationRepository
+	settingsRepo    *repository.OrderSettingsRepository
+}
+
+// NewReservationExtensionService creates a new reservation extension service
+func NewReservationExtensionService(
+	orderRepo *repository.OrderRepository,
+	reservationRepo *repository.ReservationRepository,
+	settingsRepo *repository.OrderSettingsRepository,
+) *ReservationExtensionService {
+	return &ReservationExtensionService{
+		orderRepo:       orderRepo,
+		reservationRepo: reservationRepo,
+		settingsRepo:    settingsRepo,
+	}
+}
+
+// ExtendReservation extends the stock hold of a PENDING order
+func (s *ReservationExtensionService) ExtendReservation(ctx context.Context, orderReference string) (*models.ReservationExtension, error) {
+	order, err := s.orderRepo.GetOrderByReference(ctx, orderReference)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && order == nil) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, models.ErrOrderNotAwaitingPayment
+	}
+
+	settings, err := s.settingsRepo.GetOrCreate(ctx, order.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order settings: %w", err)
+	}
+	extension := settings.ReservationExtension()
+	if extension <= 0 {
+		return nil, models.ErrReservationExtensionDisabled
+	}
+
+	expiresAt, extensions, err := s.reservationRepo.ExtendOrderHold(ctx, order.ID, extension, models.MaxReservationExtensions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extend reservations: %w", err)
+	}
+	if expiresAt == nil {
+		held, err := s.reservationRepo.CountHeldReservations(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count reservations: %w", err)
+		}
+		if held == 0 {
+			return nil, models.ErrReservationNotHeld
+		}
+		return nil, models.ErrReservationExtensionLimit
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Time("expires_at", *expiresAt).
+		Int("extensions", extensions).
+		Msg("Reservation extended by guest")
+
+	return &models.ReservationExtension{
+		OrderReference:      order.OrderReference,
+		ExpiresAt:           *expiresAt,
+		ExtensionsRemaining: models.MaxReservationExtensions - extensions,
+	}, nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// SettlementReportService builds daily settlement reports that total a tenant's settled
+// online payments and check each charge against its gateway
+type SettlementReportService struct {
+	reportRepo     *repository.SettlementReportRepository
+	settingsRepo   *repository.OrderSettingsRepository
+	paymentService *PaymentService
+}
+
+// NewSettlementReportService creates a new settlement report service
+func NewSettlementReportService(db *sql.DB, paymentService *PaymentService) *SettlementReportService {
+	return &SettlementReportService{
+		reportRepo:     repository.NewSettlementReportRepository(db),
+		settingsRepo:   repository.NewOrderSettingsRepository(db),
+		paymentService: paymentService,
+	}
+}
+
+// GetReport returns a tenant's report for a day, generating it on first request
+func (s *SettlementReportService) GetReport(ctx context.Context, tenantID, reportDate string) (*models.SettlementReport, error) {
+	report, err := s.reportRepo.Get(ctx, tenantID, reportDate)
+	if errors.Is(err, models.ErrSettlementReportNotFound) {
+		return s.GenerateReport(ctx, tenantID, reportDate)
+	}
+	return report, err
+}
+
+// GenerateReport (re)builds a tenant's report for a finished local day
+// Settled charges are totalled per gateway and payment type; every charge in the day
+// is then looked up at its gateway and disagreements are listed as mismatches.
+func (s *SettlementReportService) GenerateReport(ctx context.Context, tenantID, reportDate string) (*models.SettlementReport, error) {
+	settings, err := s.settingsRepo.GetOrCreate(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order settings: %w", err)
+	}
+	loc := settings.Location()
+
+	dayStart, err := time.ParseInLocation(models.SettlementReportDateLayout, reportDate, loc)
+	if err != nil {
+		return nil, models.ErrInvalidSettlementDate
+	}
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	if dayEnd.After(time.Now()) {
+		return nil, models.ErrInvalidSettlementDate
+	}
+
+	// Payment timestamps are stored in UTC without a time zone
+	charges, err := s.reportRepo.ListChargesForDay(ctx, tenantID, dayStart.UTC(), dayEnd.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list charges: %w", err)
+	}
+
+	report := models.NewSettlementReport(tenantID, reportDate, loc.String())
+	for _, charge := range charges {
+		recordedSettled := charge.SettledAt != nil
+		if recordedSettled {
+			report.AddSettled(charge.Gateway, settlementPaymentType(charge), charge.Amount)
+		}
+		if mismatch := s.checkCharge(ctx, tenantID, charge, recordedSettled); mismatch != nil {
+			report.AddMismatch(*mismatch)
+		}
+	}
+
+	if err := s.reportRepo.Upsert(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to save settlement report: %w", err)
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("report_date", reportDate).
+		Int("transactions", report.TransactionCount).
+		Int64("total_amount", report.TotalAmount).
+		Int("mismatches", report.MismatchCount).
+		Msg("Generated settlement report")
+
+	return report, nil
+}
+
+// checkCharge compares a charge with its gateway, returning nil when they agree
+// Unsettled charges that cannot be looked up are skipped; most are simply abandoned.
+func (s *SettlementReportService) checkCharge(ctx context.Context, tenantID string, charge *models.PaymentTransaction, recordedSettled bool) *models.SettlementMismatch {
+	mismatch := &models.SettlementMismatch{
+		PaymentTransactionID: charge.ID,
+		OrderID:              charge.OrderID,
+		Charge:               charge.MidtransOrderID,
+		Gateway:              charge.Gateway,
+		PaymentType:          settlementPaymentType(charge),
+		RecordedAmount:       charge.Amount,
+	}
+	if charge.TransactionStatus != nil {
+		mismatch.RecordedStatus = *charge.TransactionStatus
+	}
+
+	gateway, err := s.paymentService.gatewayByProvider(charge.Gateway)
+	if err == nil {
+		var status *GatewayPaymentStatus
+		status, err = gateway.GetStatus(ctx, tenantID, charge)
+		if err == nil {
+			outcome := status.Outcome
+			switch strings.ToLower(status.Status) {
+			case "refund", "partial_refund":
+				outcome = models.PaymentOutcomeSuccess // Refunds are settled payouts reversed separately
+			}
+			mismatch.Kind = models.ClassifySettlement(recordedSettled, charge.Amount, outcome, status.GrossAmount)
+			if mismatch.Kind == "" {
+				return nil
+			}
+			mismatch.GatewayStatus = status.Status
+			mismatch.GatewayAmount = status.GrossAmount
+			return mismatch
+		}
+	}
+
+	if !recordedSettled {
+		log.Debug().Err(err).Str("charge", charge.MidtransOrderID).Msg("Skipping unsettled charge that could not be checked")
+		return nil
+	}
+	log.Warn().Err(err).Str("charge", charge.MidtransOrderID).Msg("Failed to check settled charge with gateway")
+	mismatch.Kind = models.SettlementCheckFailed
+	mismatch.Detail = err.Error()
+	return mismatch
+}
+
+// settlementPaymentType is the gateway payment type of a charge, or its checkout method
+// when no notification has told us the type
+func settlementPaymentType(charge *models.PaymentTransaction) string {
+	if charge.PaymentType != nil && *charge.PaymentType != "" {
+		return *charge.PaymentType
+	}
+	return string(charge.PaymentMethod)
+}
+
+// SettlementReportJob generates the previous day's settlement report for each tenant
+// with online payments, once that day has finished in the tenant's timezone
+type SettlementReportJob struct {
+	service  *SettlementReportService
+	interval time.Duration
+	stopChan chan struct{}
+}
+
+// NewSettlementReportJob creates the settlement report worker
+func NewSettlementReportJob(service *SettlementReportService) *SettlementReportJob {
+	return &SettlementReportJob{
+		service:  service,
+		interval: time.Hour,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins the report loop; it blocks until stopped
+func (j *SettlementReportJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting settlement report job")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.generateMissing(ctx)
+		case <-j.stopChan:
+			log.Info().Msg("Stopping settlement report job")
+			return
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping settlement report job")
+			return
+		}
+	}
+}
+
+// Stop gracefully stops the report loop
+func (j *SettlementReportJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *SettlementReportJob) generateMissing(ctx context.Context) {
+	now := time.Now()
+	tenantIDs, err := j.service.reportRepo.ListTenantsWithCharges(ctx, now.UTC().Add(-72*time.Hour))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list tenants for settlement reports")
+		return
+	}
+
+	for _, tenantID := range tenantIDs {
+		settings, err := j.service.settingsRepo.GetOrCreate(ctx, tenantID)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get order settings for settlement report")
+			continue
+		}
+		reportDate := now.In(settings.Location()).AddDate(0, 0, -1).Format(models.SettlementReportDateLayout)
+
+		_, err = j.service.reportRepo.Get(ctx, tenantID, reportDate)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, models.ErrSettlementReportNotFound) {
+			log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get settlement report")
+			continue
+		}
+
+		if _, err := j.service.GenerateReport(ctx, tenantID, reportDate); err != nil {
+			log.Error().
+				Err(err).
+				Str("tenant_id", tenantID).
+				Str("report_date", reportDate).
+				Msg("Failed to generate settlement report")
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// SplitPaymentRequest records one part of an order paid across several methods
+// Cash and card parts are recorded immediately; a QRIS part creates a gateway charge
+// for just that amount and counts toward the total once the gateway settles it.
+type SplitPaymentRequest struct {
+	OrderID          string                    `json:"-"`
+	TenantID         string                    `json:"-"`
+	RecordedByUserID string                    `json:"-"`
+	Method           models.SplitPaymentMethod `json:"method" validate:"required,oneof=cash card qris"`
+	Amount           int                       `json:"amount" validate:"required,min=1"`
+	AmountTendered   *int                      `json:"amount_tendered,omitempty" validate:"omitempty,min=1"` // Cash only; defaults to the exact amount
+	CardType         *models.CardType          `json:"card_type,omitempty"`                                  // Card only
+	ApprovalCode     *string                   `json:"approval_code,omitempty"`                              // Card only
+	CardLast4        *string                   `json:"card_last4,omitempty"`                                 // Card only
+	ReceiptNumber    *string                   `json:"receipt_number,omitempty" validate:"omitempty,max=100"`
+	Notes            *string                   `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// SplitPaymentResult summarizes one recorded part and the order balance afterwards
+type SplitPaymentResult struct {
+	OrderID        string                     `json:"order_id"`
+	OrderReference string                     `json:"order_reference"`
+	Status         models.OrderStatus         `json:"status"`
+	Method         models.SplitPaymentMethod  `json:"method"`
+	Amount         int                        `json:"amount"`
+	ChangeAmount   *int                       `json:"change_amount,omitempty"`
+	Payment        *models.PaymentRecord      `json:"payment,omitempty"` // Cash and card parts
+	Charge         *models.PaymentTransaction `json:"charge,omitempty"`  // QRIS parts awaiting payment
+	Balance        models.PaymentBalance      `json:"balance"`
+	Outstanding    int                        `json:"outstanding"`
+}
+
+// PaymentSummary lists every payment part applied to an order
+type PaymentSummary struct {
+	OrderID        string                      `json:"order_id"`
+	OrderReference string                      `json:"order_reference"`
+	Status         models.OrderStatus          `json:"status"`
+	Balance        models.PaymentBalance       `json:"balance"`
+	Outstanding    int                         `json:"outstanding"`
+	Payments       []models.PaymentRecord      `json:"payments"`
+	QRISParts      []models.PaymentTransaction `json:"qris_parts"`
+}
+
+// ProcessSplitPayment records one part of a split payment
+// The order only moves to PAID when settled parts add up to the order total.
+func (s *PaymentService) ProcessSplitPayment(ctx context.Context, req *SplitPaymentRequest) (*SplitPaymentResult, error) {
+	if !req.Method.IsValid() {
+		return nil, models.ErrInvalidSplitMethod
+	}
+	if req.Amount <= 0 {
+		return nil, models.ErrInvalidPaymentAmount
+	}
+
+	order, err := s.getPayableOrder(ctx, req.OrderID, req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SplitPaymentResult{
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Method:         req.Method,
+		Amount:         req.Amount,
+	}
+
+	switch req.Method {
+	case models.SplitPaymentQRIS:
+		charge, err := s.createSplitQRISCharge(ctx, order, req.Amount)
+		if err != nil {
+			return nil, err
+		}
+		result.Charge = charge
+		result.Status = order.Status
+
+	default:
+		paymentRecordReq, note, err := s.buildSplitPaymentRecord(order, req)
+		if err != nil {
+			return nil, err
+		}
+		payment, err := s.settleInStorePayment(ctx, order, paymentRecordReq, note)
+		if err != nil {
+			return nil, err
+		}
+		result.Payment = payment
+		result.ChangeAmount = paymentRecordReq.ChangeAmount
+		result.Status = orderStatusAfterPayment(payment)
+	}
+
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, nil, order.ID, order.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment balance: %w", err)
+	}
+	result.Balance = balance
+	result.Outstanding = balance.Outstanding()
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("order_reference", order.OrderReference).
+		Str("method", string(req.Method)).
+		Int("amount", req.Amount).
+		Int("amount_paid", balance.AmountPaid).
+		Int("amount_pending", balance.AmountPending).
+		Int("total_amount", order.TotalAmount).
+		Msg("Split payment part recorded")
+
+	return result, nil
+}
+
+// buildSplitPaymentRecord validates a cash or card part and prepares its payment record
+func (s *PaymentService) buildSplitPaymentRecord(order *models.GuestOrder, req *SplitPaymentRequest) (*models.CreatePaymentRecordRequest, string, error) {
+	paymentRecordReq := &models.CreatePaymentRecordRequest{
+		OrderID:          order.ID,
+		AmountPaid:       req.Amount,
+		RecordedByUserID: req.RecordedByUserID,
+		Notes:            req.Notes,
+		ReceiptNumber:    req.ReceiptNumber,
+	}
+
+	if req.Method == models.SplitPaymentCard {
+		var cardType models.CardType
+		var approvalCode, last4 string
+		if req.CardType != nil {
+			cardType = *req.CardType
+		}
+		if req.ApprovalCode != nil {
+			approvalCode = *req.ApprovalCode
+		}
+		if req.CardLast4 != nil {
+			last4 = *req.CardLast4
+		}
+		if err := models.ValidateCardDetails(cardType, approvalCode, last4); err != nil {
+			return nil, "", err
+		}
+
+		paymentRecordReq.PaymentMethod = models.PaymentMethodCard
+		paymentRecordReq.CardType = &cardType
+		paymentRecordReq.ApprovalCode = &approvalCode
+		paymentRecordReq.CardLast4 = &last4
+		note := fmt.Sprintf("Split payment: %d by card on EDC (%s ending %s).", req.Amount, cardType, last4)
+		return paymentRecordReq, note, nil
+	}
+
+	tendered := req.Amount
+	if req.AmountTendered != nil {
+		tendered = *req.AmountTendered
+	}
+	changeAmount, err := s.calculator.CalculateChange(req.Amount, tendered)
+	if err != nil {
+		return nil, "", err
+	}
+
+	paymentRecordReq.PaymentMethod = models.PaymentMethodCash
+	paymentRecordReq.AmountTendered = &tendered
+	paymentRecordReq.ChangeAmount = &changeAmount
+	note := fmt.Sprintf("Split payment: %d in cash. Tendered: %d, change: %d.", req.Amount, tendered, changeAmount)
+	return paymentRecordReq, note, nil
+}
+
+// createSplitQRISCharge creates a QRIS charge for part of an order total
+// The order row stays locked while the charge is created so the part number and
+// outstanding balance cannot race with another split payment.
+func (s *PaymentService) createSplitQRISCharge(ctx context.Context, order *models.GuestOrder, amount int) (*models.PaymentTransaction, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.paymentRepo.LockOrderForPayment(ctx, tx, order.ID); err != nil {
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, tx, order.ID, order.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment balance: %w", err)
+	}
+	if err := balance.ValidateSplitAmount(amount); err != nil {
+		return nil, err
+	}
+
+	parts, err := s.paymentRepo.CountSplitPaymentParts(ctx, tx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count split payment parts: %w", err)
+	}
+
+	gateway, err := s.gatewayForTenant(ctx, order.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := gateway.CreateCharge(ctx, &GatewayChargeRequest{
+		Order:       order,
+		Method:      models.CheckoutPaymentQRIS,
+		ReferenceID: models.SplitPaymentMidtransOrderID(order.OrderReference, parts+1),
+		Amount:      amount,
+		Expiry:      s.paymentExpiry(ctx, order.TenantID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	payment.IsSplitPart = true
+
+	if err := s.SaveCheckoutPayment(ctx, tx, payment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit split payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+// GetPaymentSummary lists the payment parts recorded against an order
+func (s *PaymentService) GetPaymentSummary(ctx context.Context, orderID, tenantID string) (*PaymentSummary, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil || order == nil || order.TenantID != tenantID {
+		return nil, ErrOrderNotFound
+	}
+
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, nil, order.ID, order.TotalAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment balance: %w", err)
+	}
+
+	payments, err := s.paymentRepo.GetPaymentHistory(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment history: %w", err)
+	}
+	if payments == nil {
+		payments = []models.PaymentRecord{}
+	}
+
+	qrisParts, err := s.paymentRepo.ListSplitPaymentParts(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list split payment parts: %w", err)
+	}
+
+	return &PaymentSummary{
+		OrderID:        order.ID,
+		OrderReference: order.OrderReference,
+		Status:         order.Status,
+		Balance:        balance,
+		Outstanding:    balance.Outstanding(),
+		Payments:       payments,
+		QRISParts:      qrisParts,
+	}, nil
+}
+
+// handleSplitPaymentSettled applies a settled QRIS part and marks the order PAID
+// once every part adds up to the total
+func (s *PaymentService) handleSplitPaymentSettled(ctx context.Context, order *models.GuestOrder, notification *MidtransNotification) error {
+	balance, err := s.paymentRepo.GetPaymentBalance(ctx, nil, order.ID, order.TotalAmount)
+	if err != nil {
+		return fmt.Errorf("failed to get payment balance: %w", err)
+	}
+
+	if !balance.IsCovered() {
+		note := fmt.Sprintf("Split payment: QRIS part %s settled. Remaining balance: %d.", notification.OrderID, balance.TotalAmount-balance.AmountPaid)
+		if err := s.orderService.AddOrderNote(ctx, order.ID, note, "System"); err != nil {
+			log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add split payment note")
+		}
+		log.Info().
+			Str("order_id", order.ID).
+			Str("midtrans_order_id", notification.OrderID).
+			Int("amount_paid", balance.AmountPaid).
+			Int("total_amount", balance.TotalAmount).
+			Msg("Split QRIS part settled - order awaiting remaining balance")
+		return nil
+	}
+
+	if !order.RequiresPayment() {
+		return nil
+	}
+
+	return s.handlePaymentSuccess(ctx, order.ID, order.TenantID, notification)
+}
+
+// handleSplitPaymentFailed records an expired or failed QRIS part
+// The order stays open: other parts may already be paid and staff can collect the rest another way.
+func (s *PaymentService) handleSplitPaymentFailed(ctx context.Context, order *models.GuestOrder, notification *MidtransNotification) error {
+	note := fmt.Sprintf("Split payment: QRIS part %s was not completed (status: %s).", notification.OrderID, notification.TransactionStatus)
+	if err := s.orderService.AddOrderNote(ctx, order.ID, note, "System"); err != nil {
+		log.Warn().Err(err).Str("order_id", order.ID).Msg("Failed to add split payment note")
+	}
+
+	log.Info().
+		Str("order_id", order.ID).
+		Str("midtrans_order_id", notification.OrderID).
+		Str("transaction_status", notification.TransactionStatus).
+		Msg("Split QRIS part failed - order left open")
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// StaffOrderChannel is the Redis pub/sub channel carrying staff order events between replicas
+const StaffOrderChannel = "staff-order-events"
+
+// staffOrderSubscriberBuffer is how many events a slow dashboard may fall behind before it is dropped
+// Larger than a guest stream's: a busy tenant can take several orders at once.
+const staffOrderSubscriberBuffer = 32
+
+// StaffOrderHub pushes new and newly paid orders to the staff dashboards of their tenant
+// Like OrderStatusBroadcaster, events go through Redis so a dashboard connected to one
+// replica sees orders handled by another.
+type StaffOrderHub struct {
+	redisClient *redis.Client
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.StaffOrderEvent]struct{}
+}
+
+// NewStaffOrderHub creates a new staff order hub
+func NewStaffOrderHub(redisClient *redis.Client) *StaffOrderHub {
+	return &StaffOrderHub{
+		redisClient: redisClient,
+		subscribers: make(map[string]map[chan models.StaffOrderEvent]struct{}),
+	}
+}
+
+// Publish sends an event to every replica; failures are logged, never returned
+// Dashboards reload the order list when they reconnect, so a missed event is not lost for good.
+func (h *StaffOrderHub) Publish(ctx context.Context, event *models.StaffOrderEvent) {
+	if h == nil || h.redisClient == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", event.OrderID).Msg("Failed to marshal staff order event")
+		return
+	}
+	if err := h.redisClient.Publish(ctx, StaffOrderChannel, payload).Err(); err != nil {
+		log.Warn().Err(err).
+			Str("order_id", event.OrderID).
+			Str("type", string(event.Type)).
+			Msg("Failed to publish staff order event")
+	}
+}
+
+// Subscribe registers a dashboard connection for a tenant's events
+// The channel is closed when the returned cancel func is called, or early when the
+// connection falls too far behind; callers should then close the connection.
+func (h *StaffOrderHub) Subscribe(tenantID string) (<-chan models.StaffOrderEvent, func()) {
+	ch := make(chan models.StaffOrderEvent, staffOrderSubscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[tenantID] == nil {
+		h.subscribers[tenantID] = make(map[chan models.StaffOrderEvent]struct{})
+	}
+	h.subscribers[tenantID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.removeLocked(tenantID, ch)
+		})
+	}
+}
+
+// removeLocked unregisters and closes a subscriber channel if it is still registered
+func (h *StaffOrderHub) removeLocked(tenantID string, ch chan models.StaffOrderEvent) {
+	subscribers, ok := h.subscribers[tenantID]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(h.subscribers, tenantID)
+	}
+}
+
+// dispatch delivers an event to this replica's dashboards for the tenant
+func (h *StaffOrderHub) dispatch(event models.StaffOrderEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[event.TenantID] {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Str("tenant_id", event.TenantID).Msg("Staff order connection fell behind - closing it")
+			h.removeLocked(event.TenantID, ch)
+		}
+	}
+}
+
+// Start relays events from Redis to local dashboards until ctx is cancelled
+func (h *StaffOrderHub) Start(ctx context.Context) {
+	if h.redisClient == nil {
+		return
+	}
+	log.Info().Msg("Starting staff order hub")
+
+	pubsub := h.redisClient.Subscribe(ctx, StaffOrderChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Context cancelled, stopping staff order hub")
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event models.StaffOrderEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Warn().Err(err).Msg("Ignoring malformed staff order event")
+				continue
+			}
+			h.dispatch(event)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/skip2/go-qrcode"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+	"github.com/point-of-sale-system/order-service/src/utils"
+)
+
+const (
+	// DefaultTableQRSize is the QR code image width in pixels when none is requested
+	DefaultTableQRSize = 512
+	// MinTableQRSize and MaxTableQRSize bound requested QR code sizes
+	MinTableQRSize = 128
+	MaxTableQRSize = 2048
+)
+
+// TableService manages dine-in tables, their QR codes and the orders seated at them
+type TableService struct {
+	db           *sql.DB
+	tableRepo    *repository.TableRepository
+	orderService *OrderService
+	menuBaseURL  string
+}
+
+// NewTableService creates a new table service
+// menuBaseURL is the guest frontend origin the table QR codes link to.
+func NewTableService(db *sql.DB, tableRepo *repository.TableRepository, orderService *OrderService, menuBaseURL string) *TableService {
+	return &TableService{
+		db:           db,
+		tableRepo:    tableRepo,
+		orderService: orderService,
+		menuBaseURL:  menuBaseURL,
+	}
+}
+
+// ListTables lists a tenant's tables with their occupancy
+func (s *TableService) ListTables(ctx context.Context, tenantID string, activeOnly bool) ([]*models.TableOccupancy, error) {
+	return s.tableRepo.List(ctx, tenantID, activeOnly)
+}
+
+// GetTable returns a table with its open orders
+func (s *TableService) GetTable(ctx context.Context, tenantID, tableID string) (*models.TableDetail, error) {
+	table, err := s.tableRepo.GetByID(ctx, tenantID, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.tableRepo.ListOpenOrders(ctx, &table.DiningTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table orders: %w", err)
+	}
+
+	return &models.TableDetail{TableOccupancy: *table, Orders: orders}, nil
+}
+
+// CreateTable validates and adds a table with a fresh QR code token
+func (s *TableService) CreateTable(ctx context.Context, tenantID string, req *models.CreateTableRequest) (*models.DiningTable, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateTableQRToken()
+	if err != nil {
+		return nil, err
+	}
+	return s.tableRepo.Create(ctx, tenantID, req, token)
+}
+
+// UpdateTable renames, resizes or (de)activates a table
+// A rename moves the table's open orders along with it.
+func (s *TableService) UpdateTable(ctx context.Context, tenantID, tableID string, req *models.UpdateTableRequest) (*models.DiningTable, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := s.tableRepo.GetForUpdate(ctx, tx, tenantID, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := s.tableRepo.Update(ctx, tx, tenantID, tableID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Name != current.Name {
+		moved, err := s.tableRepo.MoveOpenOrders(ctx, tx, tenantID, current.Name, table.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move orders to renamed table: %w", err)
+		}
+		if moved > 0 {
+			log.Info().
+				Str("table_id", tableID).
+				Str("from", current.Name).
+				Str("to", table.Name).
+				Int64("orders", moved).
+				Msg("Open orders moved to renamed table")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit table update: %w", err)
+	}
+	return table, nil
+}
+
+// DeleteTable removes a table that has no open orders
+func (s *TableService) DeleteTable(ctx context.Context, tenantID, tableID string) error {
+	table, err := s.tableRepo.GetByID(ctx, tenantID, tableID)
+	if err != nil {
+		return err
+	}
+	if table.Occupied {
+		return models.ErrTableOccupied
+	}
+	return s.tableRepo.Delete(ctx, tenantID, tableID)
+}
+
+// RotateQRToken issues a new QR code token, so previously printed codes stop working
+func (s *TableService) RotateQRToken(ctx context.Context, tenantID, tableID string) (*models.DiningTable, error) {
+	token, err := utils.GenerateTableQRToken()
+	if err != nil {
+		return nil, err
+	}
+	return s.tableRepo.SetQRToken(ctx, tenantID, tableID, token)
+}
+
+// QRCodeURL returns the public menu link encoded in a table's QR code
+func (s *TableService) QRCodeURL(ctx context.Context, table *models.DiningTable) (string, error) {
+	slug, err := s.tableRepo.GetTenantSlug(ctx, table.TenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tenant slug: %w", err)
+	}
+	return models.TableQRURL(s.menuBaseURL, slug, table.QRToken), nil
+}
+
+// QRCodePNG renders a table's QR code as a square PNG of size pixels
+func (s *TableService) QRCodePNG(ctx context.Context, tenantID, tableID string, size int) ([]byte, error) {
+	table, err := s.tableRepo.GetByID(ctx, tenantID, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.QRCodeURL(ctx, &table.DiningTable)
+	if err != nil {
+		return nil, err
+	}
+
+	// Medium error correction survives smudged or partly covered table stickers
+	png, err := qrcode.Encode(link, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return png, nil
+}
+
+// ResolvePublicTable returns the active table a QR code token belongs to
+func (s *TableService) ResolvePublicTable(ctx context.Context, tenantID, qrToken string) (*models.PublicTable, error) {
+	table, err := s.tableRepo.GetByToken(ctx, tenantID, qrToken)
+	if err != nil {
+		return nil, err
+	}
+	if !table.IsActive {
+		return nil, models.ErrTableNotFound
+	}
+	return &models.PublicTable{Name: table.Name}, nil
+}
+
+// ResolveCheckoutTable returns the table name to seat a dine-in order at
+// A QR code token takes precedence over a typed table number. Typed numbers are matched
+// to the tenant's tables; tenants that do not manage tables accept any number.
+// Returns nil when neither is given.
+func (s *TableService) ResolveCheckoutTable(ctx context.Context, tenantID, qrToken string, tableNumber *string) (*string, error) {
+	if qrToken != "" {
+		table, err := s.ResolvePublicTable(ctx, tenantID, qrToken)
+		if errors.Is(err, models.ErrTableNotFound) {
+			return nil, fmt.Errorf("%w: the table QR code is no longer valid", models.ErrUnknownTable)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &table.Name, nil
+	}
+
+	if tableNumber == nil || models.NormalizeTableName(*tableNumber) == "" {
+		return nil, nil
+	}
+	name := models.NormalizeTableName(*tableNumber)
+
+	table, err := s.tableRepo.GetByName(ctx, tenantID, name)
+	if err == nil {
+		if !table.IsActive {
+			return nil, models.ErrTableInactive
+		}
+		return &table.Name, nil
+	}
+	if !errors.Is(err, models.ErrTableNotFound) {
+		return nil, err
+	}
+
+	managed, err := s.tableRepo.HasActiveTables(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tenant tables: %w", err)
+	}
+	if managed {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownTable, name)
+	}
+	return &name, nil
+}
+
+// MergeTables seats the open orders of the source table at the target table
+// The source table is left free; both stay defined.
+func (s *TableService) MergeTables(ctx context.Context, tenantID, targetID, sourceID string) (*models.TableDetail, error) {
+	if sourceID == "" {
+		return nil, fmt.Errorf("%w: source_table_id is required", models.ErrInvalidTable)
+	}
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge a table into itself", models.ErrInvalidTable)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock both tables in a fixed order so opposite merges cannot deadlock
+	first, second := targetID, sourceID
+	if second < first {
+		first, second = second, first
+	}
+	locked := make(map[string]*models.DiningTable, 2)
+	for _, id := range []string{first, second} {
+		table, err := s.tableRepo.GetForUpdate(ctx, tx, tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+		locked[id] = table
+	}
+	target, source := locked[targetID], locked[sourceID]
+	if !target.IsActive {
+		return nil, models.ErrTableInactive
+	}
+
+	moved, err := s.tableRepo.MoveOpenOrders(ctx, tx, tenantID, source.Name, target.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move table orders: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit table merge: %w", err)
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("source_table", source.Name).
+		Str("target_table", target.Name).
+		Int64("orders", moved).
+		Msg("Tables merged")
+
+	return s.GetTable(ctx, tenantID, targetID)
+}
+
+// CloseTable completes the paid orders seated at a table, freeing it
+// Tables with unpaid orders are not closed; staff collect payment or cancel those first.
+func (s *TableService) CloseTable(ctx context.Context, tenantID, tableID string) (*models.CloseTableResult, error) {
+	table, err := s.GetTable(ctx, tenantID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if table.UnpaidOrders > 0 {
+		return nil, models.ErrTableHasUnpaidOrders
+	}
+
+	result := &models.CloseTableResult{TableID: tableID, CompletedOrders: []string{}}
+	for _, order := range table.Orders {
+		if err := s.orderService.UpdateOrderStatus(ctx, order.ID, models.OrderStatusComplete); err != nil {
+			return nil, fmt.Errorf("failed to complete order %s: %w", order.OrderReference, err)
+		}
+		result.CompletedOrders = append(result.CompletedOrders, order.OrderReference)
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID).
+		Str("table", table.Name).
+		Int("orders", len(result.CompletedOrders)).
+		Msg("Table closed")
+
+	return result, nil
+}
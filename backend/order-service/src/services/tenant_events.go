@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/queue"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// TenantEventHandler takes part in the tenant deletion saga run by tenant-service
+// It deletes the tenant's order note attachments and delivery proof photos from object storage, then its
+// live and archived orders and cashier shifts, and acknowledges with the counts.
+type TenantEventHandler struct {
+	deletionRepo *repository.TenantDeletionRepository
+	storage      *AttachmentStorage
+	tenantEvents *queue.KafkaProducer
+}
+
+// NewTenantEventHandler creates a new tenant event handler
+// storage may be nil when no object storage is configured.
+func NewTenantEventHandler(deletionRepo *repository.TenantDeletionRepository, storage *AttachmentStorage, tenantEvents *queue.KafkaProducer) *TenantEventHandler {
+	return &TenantEventHandler{
+		deletionRepo: deletionRepo,
+		storage:      storage,
+		tenantEvents: tenantEvents,
+	}
+}
+
+// Handle processes one message from the tenant-events topic; other event types are ignored
+func (h *TenantEventHandler) Handle(ctx context.Context, payload []byte) error {
+	var event models.TenantEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Error().Err(err).Msg("Discarding malformed tenant event")
+		return nil
+	}
+	if event.EventType != models.TenantDeletionRequestedEvent {
+		return nil
+	}
+	var req models.TenantDeletionRequest
+	if err := json.Unmarshal(event.Data, &req); err != nil || !req.Includes(models.TenantDeletionParticipant) {
+		return nil
+	}
+	if _, err := uuid.Parse(event.TenantID); err != nil {
+		log.Error().Str("tenant_id", event.TenantID).Msg("Discarding tenant deletion request with an invalid tenant ID")
+		return nil
+	}
+
+	deleted, err := h.deleteTenantData(ctx, event.TenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", event.TenantID).Msg("Failed to delete tenant orders")
+	}
+
+	ack := &models.TenantEvent{
+		EventID:   uuid.New().String(),
+		EventType: models.TenantDeletionAcknowledgedEvent,
+		TenantID:  event.TenantID,
+		Timestamp: time.Now(),
+	}
+	ack.Data, _ = json.Marshal(models.NewTenantDeletionAck(req.DeletionID, deleted, err))
+	if err := h.tenantEvents.Publish(ctx, event.TenantID, ack); err != nil {
+		return fmt.Errorf("failed to acknowledge tenant deletion %s: %w", req.DeletionID, err)
+	}
+	return nil
+}
+
+// deleteTenantData is safe to repeat; a retried request finds less, or nothing, to delete
+// Stored files go first, so a failure leaves the orders in place for the retry.
+func (h *TenantEventHandler) deleteTenantData(ctx context.Context, tenantID string) (map[string]int64, error) {
+	deleted := map[string]int64{}
+
+	if h.storage != nil {
+		for kind, prefix := range map[string]string{
+			"order_note_attachments": "order-notes/" + tenantID + "/",
+			"delivery_proof_photos":  "delivery-proofs/" + tenantID + "/",
+		} {
+			removed, err := h.storage.DeletePrefix(ctx, prefix)
+			if err != nil {
+				return deleted, err
+			}
+			deleted[kind] = removed
+		}
+	}
+
+	orders, err := h.deletionRepo.DeleteTenantOrders(ctx, tenantID)
+	if err != nil {
+		return deleted, err
+	}
+	for kind, count := range orders {
+		deleted[kind] = count
+	}
+	return deleted, nil
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/repository"
+)
+
+// ErrCartEmpty is returned when a voucher is applied to an empty cart
+var ErrCartEmpty = errors.New("cart is empty")
+
+// VoucherService manages tenant vouchers and applies them to carts and orders
+type VoucherService struct {
+	voucherRepo      *repository.VoucherRepository
+	cartRepo         *repository.CartRepository
+	promotionService *PromotionService
+}
+
+// NewVoucherService creates a new voucher service
+func NewVoucherService(voucherRepo *repository.VoucherRepository, cartRepo *repository.CartRepository, promotionService *PromotionService) *VoucherService {
+	return &VoucherService{
+		voucherRepo:      voucherRepo,
+		cartRepo:         cartRepo,
+		promotionService: promotionService,
+	}
+}
+
+// CreateVoucher validates and creates a voucher
+func (s *VoucherService) CreateVoucher(ctx context.Context, tenantID string, req *models.VoucherRequest) (*models.Voucher, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.voucherRepo.Create(ctx, tenantID, req)
+}
+
+// UpdateVoucher validates and replaces a voucher's settings
+func (s *VoucherService) UpdateVoucher(ctx context.Context, tenantID, voucherID string, req *models.VoucherRequest) (*models.Voucher, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.voucherRepo.Update(ctx, tenantID, voucherID, req)
+}
+
+// GetVoucher retrieves a tenant's voucher
+func (s *VoucherService) GetVoucher(ctx context.Context, tenantID, voucherID string) (*models.Voucher, error) {
+	return s.voucherRepo.GetByID(ctx, tenantID, voucherID)
+}
+
+// ListVouchers lists a tenant's vouchers
+func (s *VoucherService) ListVouchers(ctx context.Context, tenantID string, activeOnly bool) ([]*models.Voucher, error) {
+	return s.voucherRepo.List(ctx, tenantID, activeOnly)
+}
+
+// DeleteVoucher deletes a voucher that has never been redeemed
+func (s *VoucherService) DeleteVoucher(ctx context.Context, tenantID, voucherID string) error {
+	return s.voucherRepo.Delete(ctx, tenantID, voucherID)
+}
+
+// ApplyToCart checks a voucher against the cart and remembers it for checkout
+func (s *VoucherService) ApplyToCart(ctx context.Context, tenantID, sessionID, code string) (*models.CartVoucherSummary, error) {
+	cart, err := s.cartRepo.Get(ctx, tenantID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	if len(cart.Items) == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	voucher, err := s.voucherRepo.GetByCode(ctx, tenantID, models.NormalizeVoucherCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	// Vouchers apply to the subtotal left after automatic promotions
+	if err := s.promotionService.PriceCart(ctx, cart); err != nil {
+		return nil, err
+	}
+	subtotal := cart.GetTotalAfterPromotions()
+	if err := voucher.CheckAvailable(subtotal, time.Now()); err != nil {
+		return nil, err
+	}
+
+	cart.VoucherCode = voucher.Code
+	if err := s.cartRepo.Save(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to save cart: %w", err)
+	}
+
+	discount := voucher.CalculateDiscount(subtotal)
+	return &models.CartVoucherSummary{
+		VoucherCode:       voucher.Code,
+		Description:       voucher.Description,
+		DiscountType:      voucher.DiscountType,
+		SubtotalAmount:    cart.GetTotal(),
+		PromotionDiscount: cart.PromotionDiscount,
+		DiscountAmount:    discount,
+		TotalAmount:       subtotal - discount,
+	}, nil
+}
+
+// RemoveFromCart clears the voucher applied to the cart
+func (s *VoucherService) RemoveFromCart(ctx context.Context, tenantID, sessionID string) (*models.Cart, error) {
+	cart, err := s.cartRepo.Get(ctx, tenantID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	if cart.VoucherCode == "" {
+		return cart, nil
+	}
+
+	cart.VoucherCode = ""
+	if err := s.cartRepo.Save(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to save cart: %w", err)
+	}
+	return cart, nil
+}
+
+// PriceCheckout resolves the voucher for a checkout and returns its discount
+// subtotal is the amount after automatic promotions. Returns a nil voucher when
+// no code is given. All limits, including the per-customer limit, are checked here so
+// the guest hears about them before the order is created; Redeem checks the usage and
+// per-customer limits again under a lock on the voucher.
+func (s *VoucherService) PriceCheckout(ctx context.Context, tenantID, code string, subtotal int, customerPhone string) (*models.Voucher, int, error) {
+	code = models.NormalizeVoucherCode(code)
+	if code == "" {
+		return nil, 0, nil
+	}
+
+	voucher, err := s.voucherRepo.GetByCode(ctx, tenantID, code)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := voucher.CheckAvailable(subtotal, time.Now()); err != nil {
+		return nil, 0, err
+	}
+
+	if voucher.PerCustomerLimit != nil {
+		if customerPhone == "" {
+			return nil, 0, models.ErrVoucherCustomerPhoneRequired
+		}
+		used, err := s.voucherRepo.CountCustomerRedemptions(ctx, voucher.ID, models.VoucherCustomerKey(tenantID, customerPhone))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count customer redemptions: %w", err)
+		}
+		if used >= *voucher.PerCustomerLimit {
+			return nil, 0, models.ErrVoucherCustomerLimitReached
+		}
+	}
+
+	return voucher, voucher.CalculateDiscount(subtotal), nil
+}
+
+// Redeem records the voucher against an order inside the checkout transaction
+func (s *VoucherService) Redeem(ctx context.Context, tx *sql.Tx, voucher *models.Voucher, orderID, customerPhone string, discountAmount int) error {
+	customerKey := models.VoucherCustomerKey(voucher.TenantID, customerPhone)
+	if err := s.voucherRepo.Redeem(ctx, tx, voucher, orderID, customerKey, discountAmount); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("tenant_id", voucher.TenantID).
+		Str("order_id", orderID).
+		Str("voucher_code", voucher.Code).
+		Int("discount_amount", discountAmount).
+		Msg("Voucher redeemed")
+	return nil
+}
@@ -0,0 +1,527 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+)
+
+// Xendit QR Codes API version used for charges and callbacks
+const xenditQRAPIVersion = "2022-07-31"
+
+// XenditCallbackTokenHeader carries the tenant's webhook verification token
+const XenditCallbackTokenHeader = "x-callback-token"
+
+// ErrUnrecognizedXenditCallback is returned for callbacks that are not payment updates
+var ErrUnrecognizedXenditCallback = errors.New("unrecognized Xendit callback")
+
+// XenditGateway charges through the Xendit REST API with the tenant's secret key
+// QRIS uses dynamic QR codes, bank transfers use closed single-use virtual accounts
+// and credit cards go through a hosted invoice page. Xendit does not offer GoPay.
+type XenditGateway struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewXenditGateway creates the Xendit payment gateway adapter
+func NewXenditGateway(baseURL string) *XenditGateway {
+	return &XenditGateway{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Provider identifies the gateway
+func (g *XenditGateway) Provider() models.PaymentGatewayProvider {
+	return models.PaymentGatewayXendit
+}
+
+type xenditQRCode struct {
+	ID          string `json:"id"`
+	ReferenceID string `json:"reference_id"`
+	QRString    string `json:"qr_string"`
+	Status      string `json:"status"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+type xenditVirtualAccount struct {
+	ID             string `json:"id"`
+	ExternalID     string `json:"external_id"`
+	AccountNumber  string `json:"account_number"`
+	BankCode       string `json:"bank_code"`
+	Status         string `json:"status"`
+	ExpirationDate string `json:"expiration_date"`
+}
+
+type xenditInvoice struct {
+	ID         string `json:"id"`
+	ExternalID string `json:"external_id"`
+	Status     string `json:"status"`
+	InvoiceURL string `json:"invoice_url"`
+	ExpiryDate string `json:"expiry_date"`
+}
+
+type xenditRefund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount int    `json:"amount"`
+}
+
+type xenditError struct {
+	ErrorCode string `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// CreateCharge starts a Xendit payment using the requested method
+func (g *XenditGateway) CreateCharge(ctx context.Context, req *GatewayChargeRequest) (*models.PaymentTransaction, error) {
+	order := req.Order
+
+	switch req.Method {
+	case models.CheckoutPaymentQRIS:
+		expiresAt := time.Now().Add(req.expiry())
+		body := map[string]interface{}{
+			"reference_id": req.referenceID(),
+			"type":         "DYNAMIC",
+			"currency":     "IDR",
+			"amount":       req.amount(),
+			"expires_at":   expiresAt.UTC().Format(time.RFC3339),
+		}
+
+		var qr xenditQRCode
+		if err := g.do(ctx, order.TenantID, http.MethodPost, "/qr_codes", body, &qr); err != nil {
+			return nil, err
+		}
+
+		payment := g.buildPaymentTransaction(req, qr.ID, "qris", qr.Status, qr, parseXenditTime(qr.ExpiresAt, expiresAt))
+		payment.QRString = &qr.QRString
+		return payment, nil
+
+	case models.CheckoutPaymentBankTransfer:
+		expiresAt := time.Now().Add(req.expiry()) // Lapses with the stock reservation
+		name := order.CustomerName
+		if name == "" {
+			name = "Customer"
+		}
+		body := map[string]interface{}{
+			"external_id":     req.referenceID(),
+			"bank_code":       strings.ToUpper(req.Bank),
+			"name":            name,
+			"expected_amount": req.amount(),
+			"is_closed":       true,
+			"is_single_use":   true,
+			"expiration_date": expiresAt.UTC().Format(time.RFC3339),
+		}
+
+		var va xenditVirtualAccount
+		if err := g.do(ctx, order.TenantID, http.MethodPost, "/callback_virtual_accounts", body, &va); err != nil {
+			return nil, err
+		}
+
+		payment := g.buildPaymentTransaction(req, va.ID, "bank_transfer", va.Status, va, parseXenditTime(va.ExpirationDate, expiresAt))
+		bank := strings.ToLower(va.BankCode)
+		payment.Bank = &bank
+		payment.VANumber = &va.AccountNumber
+		return payment, nil
+
+	case models.CheckoutPaymentCreditCard:
+		expiresAt := time.Now().Add(24 * time.Hour)
+		customer := map[string]interface{}{
+			"given_names":   order.CustomerName,
+			"mobile_number": order.CustomerPhone,
+		}
+		body := map[string]interface{}{
+			"external_id":      req.referenceID(),
+			"amount":           req.amount(),
+			"currency":         "IDR",
+			"description":      fmt.Sprintf("Order %s", order.OrderReference),
+			"invoice_duration": int(24 * time.Hour / time.Second),
+			"payment_methods":  []string{"CREDIT_CARD"},
+			"customer":         customer,
+		}
+		if order.CustomerEmail != nil && *order.CustomerEmail != "" {
+			body["payer_email"] = *order.CustomerEmail
+			customer["email"] = *order.CustomerEmail
+		}
+
+		var invoice xenditInvoice
+		if err := g.do(ctx, order.TenantID, http.MethodPost, "/v2/invoices", body, &invoice); err != nil {
+			return nil, err
+		}
+
+		payment := g.buildPaymentTransaction(req, invoice.ID, "credit_card", invoice.Status, invoice, parseXenditTime(invoice.ExpiryDate, expiresAt))
+		payment.RedirectURL = &invoice.InvoiceURL
+		return payment, nil
+
+	default:
+		return nil, models.ErrPaymentMethodNotOfferedByGateway
+	}
+}
+
+// buildPaymentTransaction maps a created Xendit object to a payment transaction
+func (g *XenditGateway) buildPaymentTransaction(req *GatewayChargeRequest, xenditID, paymentType, status string, response interface{}, expiryTime time.Time) *models.PaymentTransaction {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal Xendit response")
+		responseJSON = json.RawMessage(`{}`)
+	}
+
+	transactionStatus := models.XenditTransactionStatus(status)
+	if transactionStatus == "" {
+		transactionStatus = "pending"
+	}
+	idempotencyKey := xenditID + ":" + transactionStatus
+
+	log.Info().
+		Str("tenant_id", req.Order.TenantID).
+		Str("order_id", req.Order.ID).
+		Str("order_reference", req.Order.OrderReference).
+		Str("xendit_id", xenditID).
+		Str("payment_type", paymentType).
+		Msg("Xendit charge created successfully with tenant-specific credentials")
+
+	return &models.PaymentTransaction{
+		OrderID:               req.Order.ID,
+		MidtransTransactionID: &xenditID,
+		MidtransOrderID:       req.referenceID(),
+		Amount:                req.amount(),
+		PaymentMethod:         req.Method,
+		PaymentType:           &paymentType,
+		TransactionStatus:     &transactionStatus,
+		NotificationPayload:   responseJSON,
+		ExpiryTime:            &expiryTime,
+		SignatureVerified:     false, // Will be verified on webhook
+		IdempotencyKey:        &idempotencyKey,
+		Gateway:               models.PaymentGatewayXendit,
+	}
+}
+
+// VerifyWebhook compares the x-callback-token header with the tenant's verification token
+func (g *XenditGateway) VerifyWebhook(ctx context.Context, tenantID string, notification *MidtransNotification, headers http.Header) bool {
+	gatewayConfig, err := config.GetPaymentGatewayConfigForTenant(ctx, tenantID)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("order_id", notification.OrderID).
+			Msg("Failed to fetch tenant Xendit config for callback verification")
+		return false
+	}
+
+	expected := gatewayConfig.XenditCallbackToken
+	received := headers.Get(XenditCallbackTokenHeader)
+	isValid := expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(received)) == 1
+
+	if !isValid {
+		log.Warn().
+			Str("tenant_id", tenantID).
+			Str("order_id", notification.OrderID).
+			Msg("Xendit callback token verification failed")
+	}
+
+	return isValid
+}
+
+// Refund refunds a settled Xendit charge
+// Only card invoices can be refunded through the API; QRIS and virtual account
+// payments must be returned manually.
+func (g *XenditGateway) Refund(ctx context.Context, tenantID string, payment *models.PaymentTransaction, amount int, reason string) (*GatewayRefund, error) {
+	if payment.PaymentMethod != models.CheckoutPaymentCreditCard || payment.MidtransTransactionID == nil {
+		return nil, models.ErrRefundNotSupported
+	}
+
+	body := map[string]interface{}{
+		"reference_id": fmt.Sprintf("%s-refund-%d", payment.MidtransOrderID, time.Now().Unix()),
+		"invoice_id":   *payment.MidtransTransactionID,
+		"amount":       amount,
+		"currency":     "IDR",
+		"reason":       "OTHERS",
+		"metadata":     map[string]string{"note": reason},
+	}
+
+	var refund xenditRefund
+	if err := g.do(ctx, tenantID, http.MethodPost, "/refunds", body, &refund); err != nil {
+		return nil, err
+	}
+
+	return &GatewayRefund{
+		Gateway:  models.PaymentGatewayXendit,
+		RefundID: refund.ID,
+		Amount:   amount,
+		Status:   refund.Status,
+	}, nil
+}
+
+// GetStatus fetches the current state of a Xendit charge
+// Virtual accounts only report ACTIVE/INACTIVE, so a paid VA shows as an ignored outcome;
+// the payment callback remains the source of truth for settlement.
+func (g *XenditGateway) GetStatus(ctx context.Context, tenantID string, payment *models.PaymentTransaction) (*GatewayPaymentStatus, error) {
+	if payment.MidtransTransactionID == nil {
+		return nil, fmt.Errorf("payment has no Xendit ID")
+	}
+	xenditID := *payment.MidtransTransactionID
+
+	var status string
+	switch payment.PaymentMethod {
+	case models.CheckoutPaymentQRIS:
+		var payments struct {
+			Data []struct {
+				Status string `json:"status"`
+			} `json:"data"`
+		}
+		if err := g.do(ctx, tenantID, http.MethodGet, "/qr_codes/"+xenditID+"/payments", nil, &payments); err != nil {
+			return nil, err
+		}
+		status = "PENDING"
+		if payment.ExpiryTime != nil && time.Now().After(*payment.ExpiryTime) {
+			status = "EXPIRED"
+		}
+		for _, p := range payments.Data {
+			if strings.EqualFold(p.Status, "SUCCEEDED") {
+				status = "SUCCEEDED"
+				break
+			}
+		}
+
+	case models.CheckoutPaymentBankTransfer:
+		var va xenditVirtualAccount
+		if err := g.do(ctx, tenantID, http.MethodGet, "/callback_virtual_accounts/"+xenditID, nil, &va); err != nil {
+			return nil, err
+		}
+		status = va.Status
+
+	case models.CheckoutPaymentCreditCard:
+		var invoice xenditInvoice
+		if err := g.do(ctx, tenantID, http.MethodGet, "/v2/invoices/"+xenditID, nil, &invoice); err != nil {
+			return nil, err
+		}
+		status = invoice.Status
+
+	default:
+		return nil, models.ErrPaymentMethodNotOfferedByGateway
+	}
+
+	paymentType := string(payment.PaymentMethod)
+	return &GatewayPaymentStatus{
+		Gateway:       models.PaymentGatewayXendit,
+		TransactionID: xenditID,
+		ReferenceID:   payment.MidtransOrderID,
+		Status:        status,
+		Outcome:       models.MapMidtransStatus(paymentType, models.XenditTransactionStatus(status), ""),
+	}, nil
+}
+
+// Cancel voids an unpaid Xendit charge
+// Virtual accounts and invoices are expired through the API. Dynamic QR codes cannot be
+// deactivated and lapse at their expiry, so only one that has already been paid is refused.
+func (g *XenditGateway) Cancel(ctx context.Context, tenantID string, payment *models.PaymentTransaction) error {
+	if payment.MidtransTransactionID == nil {
+		return fmt.Errorf("payment has no Xendit ID")
+	}
+	xenditID := *payment.MidtransTransactionID
+
+	switch payment.PaymentMethod {
+	case models.CheckoutPaymentQRIS:
+		status, err := g.GetStatus(ctx, tenantID, payment)
+		if err != nil {
+			return err
+		}
+		if status.Outcome == models.PaymentOutcomeSuccess {
+			return models.ErrPaymentNotCancellable
+		}
+		return nil
+
+	case models.CheckoutPaymentBankTransfer:
+		body := map[string]interface{}{
+			"expiration_date": time.Now().UTC().Format(time.RFC3339),
+		}
+		return g.do(ctx, tenantID, http.MethodPatch, "/callback_virtual_accounts/"+xenditID, body, nil)
+
+	case models.CheckoutPaymentCreditCard:
+		var invoice xenditInvoice
+		if err := g.do(ctx, tenantID, http.MethodPost, "/invoices/"+xenditID+"/expire!", nil, &invoice); err != nil {
+			return err
+		}
+		if models.XenditTransactionStatus(invoice.Status) == "settlement" {
+			return models.ErrPaymentNotCancellable
+		}
+		return nil
+
+	default:
+		return models.ErrPaymentMethodNotOfferedByGateway
+	}
+}
+
+// do sends an authenticated request to the Xendit API and decodes the JSON response
+func (g *XenditGateway) do(ctx context.Context, tenantID, method, path string, body interface{}, out interface{}) error {
+	gatewayConfig, err := config.GetPaymentGatewayConfigForTenant(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to fetch tenant Xendit config")
+		return fmt.Errorf("failed to get Xendit configuration: %w", err)
+	}
+	if gatewayConfig.XenditSecretKey == "" {
+		log.Error().Str("tenant_id", tenantID).Msg("Xendit not configured for tenant")
+		return fmt.Errorf("Xendit is not configured for this tenant")
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal Xendit request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create Xendit request: %w", err)
+	}
+	req.SetBasicAuth(gatewayConfig.XenditSecretKey, "")
+	req.Header.Set("Content-Type", "application/json")
+	if strings.HasPrefix(path, "/qr_codes") {
+		req.Header.Set("api-version", xenditQRAPIVersion)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		log.Error().Err(err).Str("path", path).Msg("Failed to call Xendit API")
+		return fmt.Errorf("%w: failed to execute Xendit request: %w", models.ErrPaymentGatewayUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read Xendit response: %w", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		var xErr xenditError
+		_ = json.Unmarshal(respBody, &xErr)
+		log.Error().
+			Int("status_code", resp.StatusCode).
+			Str("error_code", xErr.ErrorCode).
+			Str("message", xErr.Message).
+			Str("path", path).
+			Msg("Xendit request failed")
+		if models.IsGatewayOutageStatus(resp.StatusCode) {
+			return fmt.Errorf("%w: Xendit request failed with status %d: %s %s", models.ErrPaymentGatewayUnavailable, resp.StatusCode, xErr.ErrorCode, xErr.Message)
+		}
+		return fmt.Errorf("Xendit request failed with status %d: %s %s", resp.StatusCode, xErr.ErrorCode, xErr.Message)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode Xendit response: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseXenditTime parses an RFC 3339 timestamp as UTC, falling back to a default
+func parseXenditTime(value string, fallback time.Time) time.Time {
+	if value == "" {
+		return fallback.UTC()
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Error().Err(err).Str("expiry_time", value).Msg("Failed to parse Xendit time, using default")
+		return fallback.UTC()
+	}
+	return parsed.UTC()
+}
+
+// xenditCallback covers the callback shapes for QR code payments, virtual account
+// payments and invoices; only the fields needed to locate the charge are decoded
+type xenditCallback struct {
+	Event string `json:"event"`
+	Data  *struct {
+		ID          string  `json:"id"`
+		QRID        string  `json:"qr_id"`
+		ReferenceID string  `json:"reference_id"`
+		Amount      float64 `json:"amount"`
+		Status      string  `json:"status"`
+		Created     string  `json:"created"`
+	} `json:"data"`
+
+	ID                       string  `json:"id"`
+	ExternalID               string  `json:"external_id"`
+	Status                   string  `json:"status"`
+	Amount                   float64 `json:"amount"`
+	PaidAmount               float64 `json:"paid_amount"`
+	CallbackVirtualAccountID string  `json:"callback_virtual_account_id"`
+	TransactionTimestamp     string  `json:"transaction_timestamp"`
+	Updated                  string  `json:"updated"`
+}
+
+// ParseXenditCallback normalizes a Xendit callback body to a MidtransNotification
+// TransactionID is the Xendit object created at checkout (QR code, virtual account
+// or invoice) and OrderID the reference we sent, matching the stored charge.
+func ParseXenditCallback(body []byte) (*MidtransNotification, error) {
+	var callback xenditCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, fmt.Errorf("failed to parse Xendit callback: %w", err)
+	}
+
+	switch {
+	case callback.Data != nil && callback.Data.QRID != "":
+		return &MidtransNotification{
+			TransactionTime:   callback.Data.Created,
+			TransactionStatus: models.XenditTransactionStatus(callback.Data.Status),
+			TransactionID:     callback.Data.QRID,
+			StatusMessage:     callback.Data.Status,
+			PaymentType:       "qris",
+			OrderID:           callback.Data.ReferenceID,
+			GrossAmount:       formatXenditAmount(callback.Data.Amount),
+			Currency:          "IDR",
+		}, nil
+
+	case callback.CallbackVirtualAccountID != "":
+		// Xendit only sends virtual account callbacks for completed payments
+		return &MidtransNotification{
+			TransactionTime:   callback.TransactionTimestamp,
+			TransactionStatus: "settlement",
+			TransactionID:     callback.CallbackVirtualAccountID,
+			StatusMessage:     "PAID",
+			PaymentType:       "bank_transfer",
+			OrderID:           callback.ExternalID,
+			GrossAmount:       formatXenditAmount(callback.Amount),
+			Currency:          "IDR",
+		}, nil
+
+	case callback.ID != "" && callback.ExternalID != "" && callback.Status != "":
+		amount := callback.PaidAmount
+		if amount == 0 {
+			amount = callback.Amount
+		}
+		return &MidtransNotification{
+			TransactionTime:   callback.Updated,
+			TransactionStatus: models.XenditTransactionStatus(callback.Status),
+			TransactionID:     callback.ID,
+			StatusMessage:     callback.Status,
+			PaymentType:       "credit_card",
+			OrderID:           callback.ExternalID,
+			GrossAmount:       formatXenditAmount(amount),
+			Currency:          "IDR",
+		}, nil
+	}
+
+	return nil, ErrUnrecognizedXenditCallback
+}
+
+// formatXenditAmount formats an amount the way Midtrans reports gross_amount
+func formatXenditAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base32"
 	"fmt"
+	"math/big"
 	"strings"
 )
 
@@ -29,6 +30,31 @@ func GenerateOrderReference() (string, error) {
 	return "GO-" + encoded, nil
 }
 
+// GenerateTableQRToken generates the unguessable token in a table's QR code URL
+// Format: 26 lowercase base32 characters (128 random bits)
+func GenerateTableQRToken() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(bytes)
+	return strings.ToLower(encoded), nil
+}
+
+// GenerateOTPCode generates a numeric one-time code of the given length
+func GenerateOTPCode(length int) (string, error) {
+	digits := make([]byte, length)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random digit: %w", err)
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+	return string(digits), nil
+}
+
 // ValidateOrderReference checks if an order reference is valid format
 func ValidateOrderReference(ref string) bool {
 	if len(ref) != 9 { // GO-XXXXXX = 9 characters
@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func anonymizedOrder() *models.GuestOrder {
+	email := "deleted@example.com"
+	ip := "10.0.0.1"
+	anonymizedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	paidAt := time.Date(2026, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	return &models.GuestOrder{
+		ID:             "order-1",
+		OrderReference: "GO-ABC123",
+		TenantID:       "tenant-1",
+		Status:         models.OrderStatusComplete,
+		OrderType:      models.OrderTypeOnline,
+		DeliveryType:   models.DeliveryTypePickup,
+		SubtotalAmount: 90000,
+		DeliveryFee:    10000,
+		TotalAmount:    100000,
+		CustomerName:   "Deleted User",
+		CustomerPhone:  "08XXXXXXXXXX",
+		CustomerEmail:  &email,
+		IPAddress:      &ip,
+		SessionID:      "sess-1",
+		PaidAt:         &paidAt,
+		IsAnonymized:   true,
+		AnonymizedAt:   &anonymizedAt,
+	}
+}
+
+func TestAnonymizedFilter_IsValid(t *testing.T) {
+	assert.True(t, models.AnonymizedFilterInclude.IsValid())
+	assert.True(t, models.AnonymizedFilterExclude.IsValid())
+	assert.True(t, models.AnonymizedFilterOnly.IsValid())
+	assert.False(t, models.AnonymizedFilter("all").IsValid())
+	assert.False(t, models.AnonymizedFilter("").IsValid())
+}
+
+func TestGuestOrder_RedactPII(t *testing.T) {
+	t.Run("Anonymized order loses customer linkage", func(t *testing.T) {
+		order := anonymizedOrder()
+		order.RedactPII()
+
+		assert.Empty(t, order.CustomerName)
+		assert.Empty(t, order.CustomerPhone)
+		assert.Nil(t, order.CustomerEmail)
+		assert.Nil(t, order.IPAddress)
+		assert.Empty(t, order.SessionID)
+		assert.True(t, order.IsAnonymized)
+		assert.Equal(t, 100000, order.TotalAmount)
+	})
+
+	t.Run("Active order is untouched", func(t *testing.T) {
+		order := anonymizedOrder()
+		order.IsAnonymized = false
+		order.CustomerName = "Budi"
+		order.RedactPII()
+
+		assert.Equal(t, "Budi", order.CustomerName)
+		assert.NotNil(t, order.CustomerEmail)
+	})
+}
+
+func TestNewArchivedOrder(t *testing.T) {
+	t.Run("Keeps finance and operational fields", func(t *testing.T) {
+		order := anonymizedOrder()
+		items := []models.OrderItem{{ProductName: "Kopi Susu", Quantity: 2, UnitPrice: 45000, TotalPrice: 90000}}
+		payments := []models.PaymentRecord{{AmountPaid: 100000, PaymentMethod: models.PaymentMethodCash}}
+
+		archived, err := models.NewArchivedOrder(order, items, payments)
+		require.NoError(t, err)
+
+		assert.Equal(t, "GO-ABC123", archived.OrderReference)
+		assert.Equal(t, models.OrderStatusComplete, archived.Status)
+		assert.Equal(t, 100000, archived.TotalAmount)
+		assert.Equal(t, order.PaidAt, archived.PaidAt)
+		assert.Equal(t, order.AnonymizedAt, archived.AnonymizedAt)
+		assert.True(t, archived.IsAnonymized)
+		assert.Len(t, archived.Items, 1)
+		assert.Len(t, archived.Payments, 1)
+	})
+
+	t.Run("Nil slices become empty lists", func(t *testing.T) {
+		archived, err := models.NewArchivedOrder(anonymizedOrder(), nil, nil)
+		require.NoError(t, err)
+
+		assert.NotNil(t, archived.Items)
+		assert.NotNil(t, archived.Payments)
+	})
+
+	t.Run("Rejects orders that still hold customer data", func(t *testing.T) {
+		order := anonymizedOrder()
+		order.IsAnonymized = false
+
+		_, err := models.NewArchivedOrder(order, nil, nil)
+		assert.ErrorIs(t, err, models.ErrOrderNotArchived)
+	})
+}
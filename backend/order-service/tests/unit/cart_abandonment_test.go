@@ -0,0 +1,40 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCartContactValidate(t *testing.T) {
+	t.Run("normalizes email and phone", func(t *testing.T) {
+		contact := &models.CartContact{Name: "  Budi ", Email: " Budi@Example.COM ", Phone: "+62 812-3456-7890"}
+		require.NoError(t, contact.Validate())
+		assert.Equal(t, "Budi", contact.Name)
+		assert.Equal(t, "budi@example.com", contact.Email)
+		assert.Equal(t, "081234567890", contact.Phone)
+	})
+
+	t.Run("accepts a phone number alone", func(t *testing.T) {
+		contact := &models.CartContact{Phone: "081234567890"}
+		assert.NoError(t, contact.Validate())
+	})
+
+	t.Run("requires an email or phone", func(t *testing.T) {
+		contact := &models.CartContact{Name: "Budi"}
+		assert.ErrorIs(t, contact.Validate(), models.ErrCartContactRequired)
+	})
+
+	t.Run("rejects malformed contacts", func(t *testing.T) {
+		assert.ErrorIs(t, (&models.CartContact{Email: "budi"}).Validate(), models.ErrInvalidCartContactEmail)
+		assert.ErrorIs(t, (&models.CartContact{Email: "Budi <budi@example.com>"}).Validate(), models.ErrInvalidCartContactEmail)
+		assert.ErrorIs(t, (&models.CartContact{Phone: "0812"}).Validate(), models.ErrInvalidCartContactPhone)
+	})
+}
+
+func TestCartResumeURL(t *testing.T) {
+	url := models.CartResumeURL("https://shop.example.com/", "kopi kita", "abc+123")
+	assert.Equal(t, "https://shop.example.com/menu/kopi%20kita?resume_cart=abc%2B123", url)
+}
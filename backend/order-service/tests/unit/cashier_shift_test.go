@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCashierShiftReconcile(t *testing.T) {
+	t.Run("Expects float plus sales minus refunds", func(t *testing.T) {
+		shift := &models.CashierShift{OpeningFloat: 200000}
+		shift.SetCashTotals(750000, 50000)
+		assert.Equal(t, 900000, shift.ExpectedCash)
+	})
+
+	t.Run("Reports a short drawer as negative variance", func(t *testing.T) {
+		shift := &models.CashierShift{OpeningFloat: 200000}
+		shift.SetCashTotals(750000, 50000)
+		shift.Reconcile(880000)
+		assert.Equal(t, 880000, *shift.CountedCash)
+		assert.Equal(t, -20000, *shift.Variance)
+	})
+
+	t.Run("Reports a balanced drawer as zero variance", func(t *testing.T) {
+		shift := &models.CashierShift{OpeningFloat: 100000}
+		shift.SetCashTotals(0, 0)
+		shift.Reconcile(100000)
+		assert.Zero(t, *shift.Variance)
+	})
+}
+
+func TestShiftRequestValidation(t *testing.T) {
+	t.Run("Rejects a negative opening float", func(t *testing.T) {
+		assert.ErrorIs(t, (&models.OpenShiftRequest{OpeningFloat: -1}).Validate(), models.ErrInvalidOpeningFloat)
+		assert.NoError(t, (&models.OpenShiftRequest{OpeningFloat: 0}).Validate())
+	})
+
+	t.Run("Requires the counted cash", func(t *testing.T) {
+		assert.ErrorIs(t, (&models.CloseShiftRequest{}).Validate(), models.ErrInvalidCountedCash)
+
+		negative := -5
+		assert.ErrorIs(t, (&models.CloseShiftRequest{CountedCash: &negative}).Validate(), models.ErrInvalidCountedCash)
+
+		counted := 0
+		req := &models.CloseShiftRequest{CountedCash: &counted, Note: "  drawer empty "}
+		assert.NoError(t, req.Validate())
+		assert.Equal(t, "drawer empty", req.Note)
+	})
+
+	t.Run("Checks cash refunds", func(t *testing.T) {
+		assert.ErrorIs(t, (&models.CashRefundRequest{Amount: 0, Reason: "Wrong item"}).Validate(), models.ErrInvalidCashRefund)
+		assert.ErrorIs(t, (&models.CashRefundRequest{Amount: 1000, Reason: "   "}).Validate(), models.ErrCashRefundReason)
+		assert.ErrorIs(t, (&models.CashRefundRequest{Amount: 1000, Reason: strings.Repeat("a", models.MaxCashRefundReasonLength+1)}).Validate(), models.ErrCashRefundReason)
+		assert.NoError(t, (&models.CashRefundRequest{Amount: 1000, Reason: "Wrong item"}).Validate())
+	})
+}
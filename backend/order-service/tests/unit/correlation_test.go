@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/observability"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationIDContext(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		ctx := observability.WithCorrelationID(context.Background(), "req-123")
+		assert.Equal(t, "req-123", observability.CorrelationIDFromContext(ctx))
+	})
+
+	t.Run("Empty ID leaves context unchanged", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Equal(t, ctx, observability.WithCorrelationID(ctx, ""))
+		assert.Equal(t, "", observability.CorrelationIDFromContext(ctx))
+	})
+}
+
+func TestCorrelationIDMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(echoMiddleware.RequestID())
+	e.Use(middleware.CorrelationID)
+
+	var seen string
+	e.GET("/", func(c echo.Context) error {
+		seen = observability.CorrelationIDFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("Uses forwarded request ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(observability.CorrelationIDHeader, "gateway-id")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, "gateway-id", seen)
+	})
+
+	t.Run("Falls back to generated request ID", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.NotEmpty(t, seen)
+		assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), seen)
+	})
+}
@@ -0,0 +1,215 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func courierTestSettings() *models.CourierSettings {
+	provider := models.CourierGoSend
+	lat, lng := -6.2088, 106.8456
+	return &models.CourierSettings{
+		TenantID:        "tenant-1",
+		Provider:        &provider,
+		ServiceType:     models.CourierServiceInstant,
+		PickupName:      "Kopi Kita",
+		PickupPhone:     "+6281200000000",
+		PickupAddress:   "Jl. Sudirman No. 1, Jakarta",
+		PickupLatitude:  &lat,
+		PickupLongitude: &lng,
+	}
+}
+
+func courierTestRequest() *services.CourierBookingRequest {
+	return &services.CourierBookingRequest{
+		Order: &models.GuestOrder{
+			OrderReference: "GO-ABC123",
+			CustomerName:   "Budi",
+			CustomerPhone:  "+6281311111111",
+		},
+		Items: []models.OrderItem{
+			{ProductName: "Es Kopi Susu", Quantity: 2, UnitPrice: 25000},
+		},
+		Settings: courierTestSettings(),
+		Destination: &models.DeliveryAddress{
+			FullAddress: "Jl. Thamrin No. 10, Jakarta",
+			Latitude:    -6.1944,
+			Longitude:   106.8229,
+		},
+	}
+}
+
+func TestCourierSettingsApply(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	t.Run("provider requires a pickup point", func(t *testing.T) {
+		settings := &models.CourierSettings{ServiceType: models.CourierServiceInstant}
+		err := settings.Apply(&models.UpdateCourierSettingsRequest{Provider: strPtr("gosend")})
+		assert.ErrorIs(t, err, models.ErrInvalidCourierSettings)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		settings := courierTestSettings()
+		err := settings.Apply(&models.UpdateCourierSettingsRequest{Provider: strPtr("lalamove")})
+		assert.ErrorIs(t, err, models.ErrInvalidCourierSettings)
+	})
+
+	t.Run("coordinates out of range", func(t *testing.T) {
+		settings := courierTestSettings()
+		lat := 120.0
+		err := settings.Apply(&models.UpdateCourierSettingsRequest{PickupLatitude: &lat})
+		assert.ErrorIs(t, err, models.ErrInvalidCourierSettings)
+	})
+
+	t.Run("empty provider disables booking", func(t *testing.T) {
+		settings := courierTestSettings()
+		require.NoError(t, settings.Apply(&models.UpdateCourierSettingsRequest{Provider: strPtr("")}))
+		assert.Nil(t, settings.Provider)
+	})
+
+	t.Run("switch provider and service type", func(t *testing.T) {
+		settings := courierTestSettings()
+		sameDay := models.CourierServiceSameDay
+		require.NoError(t, settings.Apply(&models.UpdateCourierSettingsRequest{
+			Provider:    strPtr("grabexpress"),
+			ServiceType: &sameDay,
+			PickupName:  strPtr("  Kopi Kita Thamrin "),
+		}))
+		assert.Equal(t, models.CourierGrabExpress, *settings.Provider)
+		assert.Equal(t, models.CourierServiceSameDay, settings.ServiceType)
+		assert.Equal(t, "Kopi Kita Thamrin", settings.PickupName)
+	})
+}
+
+func TestDeliveryStatusCanMoveTo(t *testing.T) {
+	assert.True(t, models.DeliveryRequesting.CanMoveTo(models.DeliveryFindingDriver))
+	assert.True(t, models.DeliveryFindingDriver.CanMoveTo(models.DeliveryPickedUp))
+	assert.True(t, models.DeliveryDriverAssigned.CanMoveTo(models.DeliveryCancelled))
+	assert.True(t, models.DeliveryPickedUp.CanMoveTo(models.DeliveryDelivered))
+
+	// Late webhooks must not move a delivery backwards
+	assert.False(t, models.DeliveryPickedUp.CanMoveTo(models.DeliveryDriverAssigned))
+	assert.False(t, models.DeliveryDriverAssigned.CanMoveTo(models.DeliveryDriverAssigned))
+	// Finished deliveries never change
+	assert.False(t, models.DeliveryDelivered.CanMoveTo(models.DeliveryCancelled))
+	assert.False(t, models.DeliveryFailed.CanMoveTo(models.DeliveryFindingDriver))
+}
+
+func TestGoSendParseWebhook(t *testing.T) {
+	courier := services.NewGoSendCourier(config.CourierCredentials{WebhookToken: "secret"})
+	headers := http.Header{"Authorization": []string{"secret"}}
+
+	tests := []struct {
+		status   string
+		expected models.DeliveryStatus
+	}{
+		{"confirmed", models.DeliveryFindingDriver},
+		{"allocated", models.DeliveryDriverAssigned},
+		{"out_for_pickup", models.DeliveryDriverAssigned},
+		{"picked", models.DeliveryPickedUp},
+		{"out_for_delivery", models.DeliveryPickedUp},
+		{"delivered", models.DeliveryDelivered},
+		{"cancelled", models.DeliveryCancelled},
+		{"no_driver", models.DeliveryFailed},
+		{"on_hold", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			body := []byte(`{"entity_id":"GK-1","status":"` + tt.status + `","driver_name":"Andi","vehicle_number":"B 1234 XY","price":18000}`)
+			update, err := courier.ParseWebhook(headers, body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, update.Status)
+			assert.Equal(t, "GK-1", update.ExternalID)
+			assert.Equal(t, "Andi", update.DriverName)
+			assert.Equal(t, "B 1234 XY", update.VehiclePlate)
+			assert.Equal(t, 18000, update.Fee)
+		})
+	}
+
+	t.Run("wrong token", func(t *testing.T) {
+		_, err := courier.ParseWebhook(http.Header{"Authorization": []string{"guess"}}, []byte(`{"entity_id":"GK-1"}`))
+		assert.ErrorIs(t, err, models.ErrInvalidCourierWebhook)
+	})
+}
+
+func TestGrabExpressParseWebhook(t *testing.T) {
+	courier := services.NewGrabExpressCourier(config.CourierCredentials{WebhookToken: "secret"})
+	headers := http.Header{"Authorization": []string{"secret"}}
+
+	body := []byte(`{"deliveryID":"IN-2-ABC","status":"PICKING_UP","trackURL":"https://express.grab.com/track/abc",
+		"driver":{"name":"Sari","phone":"+6281322222222","licensePlate":"B 9876 ZZ"}}`)
+	update, err := courier.ParseWebhook(headers, body)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryDriverAssigned, update.Status)
+	assert.Equal(t, "IN-2-ABC", update.ExternalID)
+	assert.Equal(t, "https://express.grab.com/track/abc", update.TrackingURL)
+	assert.Equal(t, "B 9876 ZZ", update.VehiclePlate)
+
+	update, err = courier.ParseWebhook(headers, []byte(`{"deliveryID":"IN-2-ABC","status":"FAILED","failedReason":"recipient unreachable"}`))
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryFailed, update.Status)
+	assert.Equal(t, "recipient unreachable", update.Reason)
+
+	_, err = courier.ParseWebhook(http.Header{}, body)
+	assert.ErrorIs(t, err, models.ErrInvalidCourierWebhook)
+}
+
+func TestGoSendBook(t *testing.T) {
+	var booking map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "client", r.Header.Get("Client-ID"))
+		assert.Equal(t, "pass", r.Header.Get("Pass-Key"))
+		switch r.URL.Path {
+		case "/gokilat/v10/booking":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&booking))
+			w.Write([]byte(`{"id":1,"orderNo":"GK-1"}`))
+		case "/gokilat/v10/calculate/price":
+			w.Write([]byte(`{"Instant":{"serviceable":true,"price":{"total_price":18000}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	courier := services.NewGoSendCourier(config.CourierCredentials{APIURL: server.URL, ClientID: "client", ClientSecret: "pass"})
+	result, err := courier.Book(context.Background(), courierTestRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "GK-1", result.ExternalID)
+	assert.Equal(t, models.DeliveryFindingDriver, result.Status)
+	assert.Equal(t, 18000, result.Fee)
+
+	assert.Equal(t, "Instant", booking["shipment_method"])
+	route := booking["routes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "-6.208800,106.845600", route["originLatLong"])
+	assert.Equal(t, "-6.194400,106.822900", route["destinationLatLong"])
+	assert.Equal(t, "2x Es Kopi Susu", route["item"])
+	assert.Equal(t, "GO-ABC123", route["storeOrderId"])
+}
+
+func TestCourierBookOutage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/grabid/v1/oauth2/token" {
+			w.Write([]byte(`{"access_token":"token","expires_in":3600}`))
+			return
+		}
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	courier := services.NewGrabExpressCourier(config.CourierCredentials{APIURL: server.URL, ClientID: "client", ClientSecret: "secret"})
+	_, err := courier.Book(context.Background(), courierTestRequest())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, models.ErrCourierUnavailable))
+}
@@ -0,0 +1,35 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveCustomerAddressRequestValidate(t *testing.T) {
+	t.Run("trims the label and address", func(t *testing.T) {
+		req := &models.SaveCustomerAddressRequest{Label: " Home ", Address: "  Jl. Sudirman No. 1, Jakarta  "}
+		assert.NoError(t, req.Validate())
+		assert.Equal(t, "Home", req.Label)
+		assert.Equal(t, "Jl. Sudirman No. 1, Jakarta", req.Address)
+	})
+
+	t.Run("rejects short addresses", func(t *testing.T) {
+		req := &models.SaveCustomerAddressRequest{Address: " Jl. A "}
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidCustomerAddress)
+	})
+}
+
+func TestNormalizeAddressText(t *testing.T) {
+	assert.Equal(t,
+		models.NormalizeAddressText("Jl. Sudirman No. 1, Jakarta"),
+		models.NormalizeAddressText("  jl.  SUDIRMAN no. 1,\tJakarta "),
+	)
+}
+
+func TestCustomerAddressHasCoordinates(t *testing.T) {
+	lat, lng := -6.2, 106.8
+	assert.True(t, (&models.CustomerAddress{Latitude: &lat, Longitude: &lng}).HasCoordinates())
+	assert.False(t, (&models.CustomerAddress{Latitude: &lat}).HasCoordinates())
+}
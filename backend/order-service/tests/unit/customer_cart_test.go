@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerCartID(t *testing.T) {
+	cartID := models.CustomerCartID("abc123")
+	assert.Equal(t, "customer:abc123", cartID)
+	assert.True(t, models.IsCustomerCartID(cartID))
+	assert.False(t, models.IsCustomerCartID("550e8400-e29b-41d4-a716-446655440000"))
+}
+
+func TestCartMerge(t *testing.T) {
+	newCustomerCart := func() *models.Cart {
+		return &models.Cart{
+			TenantID:    "tenant-1",
+			SessionID:   models.CustomerCartID("abc123"),
+			VoucherCode: "OLD10",
+			Items: []models.CartItem{
+				{ProductID: "p1", ProductName: "Latte", Quantity: 2, UnitPrice: 30000, TotalPrice: 60000},
+				{ProductID: "p2", ProductName: "Croissant", Quantity: 1, UnitPrice: 25000, TotalPrice: 25000},
+			},
+		}
+	}
+	anonymous := &models.Cart{
+		TenantID:    "tenant-1",
+		SessionID:   "sess-1",
+		VoucherCode: "NEW20",
+		Items: []models.CartItem{
+			{ProductID: "p1", ProductName: "Latte", Quantity: 3, UnitPrice: 32000, TotalPrice: 96000},
+			{ProductID: "p2", ProductName: "Croissant", Quantity: 1, UnitPrice: 25000, TotalPrice: 25000},
+			{ProductID: "p3", ProductName: "Bagel", Quantity: 1, UnitPrice: 20000, TotalPrice: 20000},
+		},
+	}
+
+	t.Run("keeps the larger quantity and adds new products", func(t *testing.T) {
+		cart := newCustomerCart()
+		cart.Merge(anonymous)
+
+		require.Len(t, cart.Items, 3)
+		assert.Equal(t, 3, cart.Items[0].Quantity)
+		assert.Equal(t, 96000, cart.Items[0].TotalPrice)
+		assert.Equal(t, 1, cart.Items[1].Quantity)
+		assert.Equal(t, "p3", cart.Items[2].ProductID)
+		assert.Equal(t, 5, cart.GetItemCount())
+		assert.Equal(t, models.CustomerCartID("abc123"), cart.SessionID)
+	})
+
+	t.Run("merging twice changes nothing", func(t *testing.T) {
+		cart := newCustomerCart()
+		cart.Merge(anonymous)
+		cart.Merge(anonymous)
+		assert.Equal(t, 5, cart.GetItemCount())
+	})
+
+	t.Run("the anonymous cart's voucher wins", func(t *testing.T) {
+		cart := newCustomerCart()
+		cart.Merge(anonymous)
+		assert.Equal(t, "NEW20", cart.VoucherCode)
+
+		cart = newCustomerCart()
+		cart.Merge(&models.Cart{})
+		assert.Equal(t, "OLD10", cart.VoucherCode)
+	})
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/config"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceDeliveryFee(t *testing.T) {
+	feeConfig := &config.TenantDeliveryFeeConfig{
+		Enabled:    true,
+		Type:       "distance",
+		BaseFee:    8000,
+		PerKmFee:   2500,
+		IncludedKm: 2,
+		MaxFee:     30000,
+	}
+
+	tests := []struct {
+		name       string
+		distanceKm float64
+		expected   int
+	}{
+		{"within included distance", 1.4, 8000},
+		{"exactly included distance", 2, 8000},
+		{"started km is charged in full", 2.1, 10500},
+		{"several km", 5, 15500},
+		{"capped", 20, 30000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, services.DistanceDeliveryFee(feeConfig, tt.distanceKm))
+		})
+	}
+
+	t.Run("uncapped", func(t *testing.T) {
+		uncapped := *feeConfig
+		uncapped.MaxFee = 0
+		assert.Equal(t, 53000, services.DistanceDeliveryFee(&uncapped, 20))
+	})
+}
+
+func TestTenantDeliveryFeeConfigDistancePricing(t *testing.T) {
+	lat, lng := -6.2088, 106.8456
+
+	assert.True(t, (&config.TenantDeliveryFeeConfig{Enabled: true, Type: "distance", OriginLatitude: &lat, OriginLongitude: &lng}).DistancePricing())
+	// Disabled, flat, or missing the origin falls back to the flat default fee
+	assert.False(t, (&config.TenantDeliveryFeeConfig{Type: "distance", OriginLatitude: &lat, OriginLongitude: &lng}).DistancePricing())
+	assert.False(t, (&config.TenantDeliveryFeeConfig{Enabled: true, Type: "flat", OriginLatitude: &lat, OriginLongitude: &lng}).DistancePricing())
+	assert.False(t, (&config.TenantDeliveryFeeConfig{Enabled: true, Type: "distance"}).DistancePricing())
+}
@@ -0,0 +1,75 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRecipientName(t *testing.T) {
+	t.Run("Trims the name", func(t *testing.T) {
+		name, err := models.NormalizeRecipientName("  Budi Santoso ")
+		assert.NoError(t, err)
+		assert.Equal(t, "Budi Santoso", name)
+	})
+
+	t.Run("Rejects a blank name", func(t *testing.T) {
+		_, err := models.NormalizeRecipientName("   ")
+		assert.ErrorIs(t, err, models.ErrDeliveryProofRecipient)
+	})
+
+	t.Run("Rejects a name over the limit", func(t *testing.T) {
+		_, err := models.NormalizeRecipientName(strings.Repeat("a", models.MaxRecipientNameLength+1))
+		assert.ErrorIs(t, err, models.ErrDeliveryProofRecipient)
+	})
+}
+
+func TestCanTakeDeliveryProof(t *testing.T) {
+	tests := []struct {
+		name         string
+		deliveryType models.DeliveryType
+		status       models.OrderStatus
+		want         bool
+	}{
+		{"Paid delivery order", models.DeliveryTypeDelivery, models.OrderStatusPaid, true},
+		{"Completed delivery order", models.DeliveryTypeDelivery, models.OrderStatusComplete, true},
+		{"Unpaid delivery order", models.DeliveryTypeDelivery, models.OrderStatusPending, false},
+		{"Cancelled delivery order", models.DeliveryTypeDelivery, models.OrderStatusCancelled, false},
+		{"Paid pickup order", models.DeliveryTypePickup, models.OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &models.GuestOrder{DeliveryType: tt.deliveryType, Status: tt.status}
+			assert.Equal(t, tt.want, order.CanTakeDeliveryProof())
+		})
+	}
+}
+
+func TestDeliveryProofStorageKey(t *testing.T) {
+	key := models.DeliveryProofStorageKey("tenant-1", "order-1", "proof-1", "image/jpeg")
+	assert.Equal(t, "delivery-proofs/tenant-1/order-1/proof-1.jpg", key)
+}
+
+func TestDeliveryProofForGuest(t *testing.T) {
+	userID := "user-1"
+	key := "delivery-proofs/tenant-1/order-1/proof-1.jpg"
+	proof := &models.DeliveryProof{
+		ID:               "proof-1",
+		OrderID:          "order-1",
+		RecipientName:    "Budi",
+		PhotoStorageKey:  &key,
+		PhotoURL:         "https://storage.example.com/signed",
+		CapturedByUserID: &userID,
+		DeliveredAt:      time.Now(),
+	}
+
+	guest := proof.ForGuest()
+	assert.Equal(t, "Budi", guest.RecipientName)
+	assert.Equal(t, proof.PhotoURL, guest.PhotoURL)
+	assert.Nil(t, guest.CapturedByUserID)
+	assert.Nil(t, guest.PhotoStorageKey)
+}
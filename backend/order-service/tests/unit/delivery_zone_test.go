@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func radiusZone(id string, minKm, maxKm float64, fee int) *models.DeliveryZone {
+	return &models.DeliveryZone{
+		ID:              id,
+		Name:            id,
+		Type:            models.DeliveryZoneRadius,
+		CenterLatitude:  floatPtr(-6.2088),
+		CenterLongitude: floatPtr(106.8456),
+		MinRadiusKm:     minKm,
+		MaxRadiusKm:     floatPtr(maxKm),
+		Fee:             fee,
+	}
+}
+
+func TestDeliveryZoneRequestValidate(t *testing.T) {
+	t.Run("radius band", func(t *testing.T) {
+		req := &models.DeliveryZoneRequest{
+			Name:            "  Inner city ",
+			Type:            models.DeliveryZoneRadius,
+			CenterLatitude:  floatPtr(-6.2088),
+			CenterLongitude: floatPtr(106.8456),
+			MinRadiusKm:     3,
+			MaxRadiusKm:     floatPtr(5),
+			Polygon:         []models.LatLng{{Latitude: 1, Longitude: 1}},
+			Fee:             15000,
+		}
+		require.NoError(t, req.Validate())
+		assert.Equal(t, "Inner city", req.Name)
+		assert.Nil(t, req.Polygon)
+	})
+
+	t.Run("polygon drops radius fields", func(t *testing.T) {
+		req := &models.DeliveryZoneRequest{
+			Name:           "Kemang",
+			Type:           models.DeliveryZonePolygon,
+			CenterLatitude: floatPtr(-6.2),
+			MinRadiusKm:    2,
+			Polygon: []models.LatLng{
+				{Latitude: -6.25, Longitude: 106.80},
+				{Latitude: -6.25, Longitude: 106.82},
+				{Latitude: -6.27, Longitude: 106.82},
+			},
+		}
+		require.NoError(t, req.Validate())
+		assert.Nil(t, req.CenterLatitude)
+		assert.Zero(t, req.MinRadiusKm)
+	})
+
+	invalid := map[string]*models.DeliveryZoneRequest{
+		"missing name":      {Type: models.DeliveryZoneRadius, CenterLatitude: floatPtr(0), CenterLongitude: floatPtr(0), MaxRadiusKm: floatPtr(5)},
+		"negative fee":      {Name: "A", Type: models.DeliveryZoneRadius, CenterLatitude: floatPtr(0), CenterLongitude: floatPtr(0), MaxRadiusKm: floatPtr(5), Fee: -1},
+		"no center":         {Name: "A", Type: models.DeliveryZoneRadius, MaxRadiusKm: floatPtr(5)},
+		"empty band":        {Name: "A", Type: models.DeliveryZoneRadius, CenterLatitude: floatPtr(0), CenterLongitude: floatPtr(0), MinRadiusKm: 5, MaxRadiusKm: floatPtr(5)},
+		"too few points":    {Name: "A", Type: models.DeliveryZonePolygon, Polygon: []models.LatLng{{}, {}}},
+		"point off map":     {Name: "A", Type: models.DeliveryZonePolygon, Polygon: []models.LatLng{{Latitude: 91}, {}, {}}},
+		"unknown zone type": {Name: "A", Type: "circle"},
+	}
+	for name, req := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, req.Validate(), models.ErrInvalidDeliveryZone)
+		})
+	}
+}
+
+func TestMatchDeliveryZone(t *testing.T) {
+	inner := radiusZone("inner", 0, 3, 10000)
+	outer := radiusZone("outer", 3, 8, 20000)
+	kemang := &models.DeliveryZone{
+		ID:   "kemang",
+		Type: models.DeliveryZonePolygon,
+		Polygon: []models.LatLng{
+			{Latitude: -6.25, Longitude: 106.80},
+			{Latitude: -6.25, Longitude: 106.83},
+			{Latitude: -6.28, Longitude: 106.83},
+			{Latitude: -6.28, Longitude: 106.80},
+		},
+		Fee: 12000,
+	}
+
+	t.Run("inner band", func(t *testing.T) {
+		zone, distance := services.MatchDeliveryZone([]*models.DeliveryZone{inner, outer}, -6.2000, 106.8456)
+		require.NotNil(t, zone)
+		assert.Equal(t, "inner", zone.ID)
+		require.NotNil(t, distance)
+		assert.InDelta(t, 0.98, *distance, 0.05)
+	})
+
+	t.Run("outer band", func(t *testing.T) {
+		zone, _ := services.MatchDeliveryZone([]*models.DeliveryZone{inner, outer}, -6.2088, 106.8900)
+		require.NotNil(t, zone)
+		assert.Equal(t, "outer", zone.ID)
+	})
+
+	t.Run("polygon", func(t *testing.T) {
+		zone, distance := services.MatchDeliveryZone([]*models.DeliveryZone{kemang}, -6.2650, 106.8150)
+		require.NotNil(t, zone)
+		assert.Equal(t, "kemang", zone.ID)
+		assert.Nil(t, distance)
+	})
+
+	t.Run("first zone in priority order wins", func(t *testing.T) {
+		// The Kemang polygon lies within the outer band; listing it first makes it win
+		zone, _ := services.MatchDeliveryZone([]*models.DeliveryZone{kemang, outer}, -6.2650, 106.8150)
+		require.NotNil(t, zone)
+		assert.Equal(t, "kemang", zone.ID)
+
+		zone, _ = services.MatchDeliveryZone([]*models.DeliveryZone{outer, kemang}, -6.2650, 106.8150)
+		require.NotNil(t, zone)
+		assert.Equal(t, "outer", zone.ID)
+	})
+
+	t.Run("outside every zone", func(t *testing.T) {
+		zone, distance := services.MatchDeliveryZone([]*models.DeliveryZone{inner, outer, kemang}, -6.9175, 107.6191)
+		assert.Nil(t, zone)
+		assert.Nil(t, distance)
+	})
+}
@@ -0,0 +1,72 @@
+package unit
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTableRequestValidate(t *testing.T) {
+	t.Run("Trims the name", func(t *testing.T) {
+		req := &models.CreateTableRequest{Name: "  T12 "}
+		require.NoError(t, req.Validate())
+		assert.Equal(t, "T12", req.Name)
+	})
+
+	t.Run("Rejects a blank name", func(t *testing.T) {
+		req := &models.CreateTableRequest{Name: "   "}
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidTable)
+	})
+
+	t.Run("Rejects a name longer than table_number", func(t *testing.T) {
+		req := &models.CreateTableRequest{Name: strings.Repeat("a", models.MaxTableNameLength+1)}
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidTable)
+	})
+
+	t.Run("Rejects a non-positive capacity", func(t *testing.T) {
+		capacity := 0
+		req := &models.CreateTableRequest{Name: "T1", Capacity: &capacity}
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidTable)
+	})
+}
+
+func TestUpdateTableRequestValidate(t *testing.T) {
+	t.Run("Omitted fields are valid", func(t *testing.T) {
+		assert.NoError(t, (&models.UpdateTableRequest{}).Validate())
+	})
+
+	t.Run("Trims a new name", func(t *testing.T) {
+		name := " Patio 2 "
+		req := &models.UpdateTableRequest{Name: &name}
+		require.NoError(t, req.Validate())
+		assert.Equal(t, "Patio 2", *req.Name)
+	})
+
+	t.Run("Rejects a blank new name", func(t *testing.T) {
+		name := ""
+		req := &models.UpdateTableRequest{Name: &name}
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidTable)
+	})
+}
+
+func TestTableQRURL(t *testing.T) {
+	assert.Equal(t,
+		"https://shop.example.com/menu/kopi-kita?table=abc123",
+		models.TableQRURL("https://shop.example.com/", "kopi-kita", "abc123"),
+	)
+}
+
+func TestGenerateTableQRToken(t *testing.T) {
+	first, err := utils.GenerateTableQRToken()
+	require.NoError(t, err)
+	second, err := utils.GenerateTableQRToken()
+	require.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{26}$`), first)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,57 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckGuestCancel(t *testing.T) {
+	createdAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	settings := &models.OrderSettings{GuestCancelWindowMinutes: 15}
+	pending := &models.GuestOrder{Status: models.OrderStatusPending, OrderType: models.OrderTypeOnline, CreatedAt: createdAt}
+
+	t.Run("within window", func(t *testing.T) {
+		assert.NoError(t, settings.CheckGuestCancel(pending, createdAt.Add(14*time.Minute)))
+
+		deadline, ok := settings.GuestCancelDeadline(pending)
+		require.True(t, ok)
+		assert.Equal(t, createdAt.Add(15*time.Minute), deadline)
+	})
+
+	t.Run("window passed", func(t *testing.T) {
+		assert.ErrorIs(t, settings.CheckGuestCancel(pending, createdAt.Add(16*time.Minute)), models.ErrGuestCancelWindowClosed)
+	})
+
+	t.Run("disabled by tenant", func(t *testing.T) {
+		disabled := &models.OrderSettings{}
+		assert.ErrorIs(t, disabled.CheckGuestCancel(pending, createdAt), models.ErrGuestCancelWindowClosed)
+	})
+
+	t.Run("paid order", func(t *testing.T) {
+		paid := *pending
+		paid.Status = models.OrderStatusPaid
+		assert.ErrorIs(t, settings.CheckGuestCancel(&paid, createdAt), models.ErrGuestCancelNotAllowed)
+	})
+
+	t.Run("staff-recorded order", func(t *testing.T) {
+		offline := *pending
+		offline.OrderType = models.OrderTypeOffline
+		assert.ErrorIs(t, settings.CheckGuestCancel(&offline, createdAt), models.ErrGuestCancelNotAllowed)
+	})
+}
+
+func TestValidateGuestCancellation(t *testing.T) {
+	window := func(minutes int) *models.UpdateOrderSettingsRequest {
+		return &models.UpdateOrderSettingsRequest{GuestCancelWindowMinutes: &minutes}
+	}
+
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{}).ValidateGuestCancellation())
+	assert.NoError(t, window(0).ValidateGuestCancellation())
+	assert.NoError(t, window(30).ValidateGuestCancellation())
+	assert.ErrorIs(t, window(-1).ValidateGuestCancellation(), models.ErrInvalidGuestCancelWindow)
+	assert.ErrorIs(t, window(1441).ValidateGuestCancellation(), models.ErrInvalidGuestCancelWindow)
+}
@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeReservationLines(t *testing.T) {
+	merged := models.MergeReservationLines([]models.ReservationLine{
+		{ProductID: "prod-b", ProductName: "Kopi Susu", Quantity: 2},
+		{ProductID: "prod-a", ProductName: "Roti Bakar", Quantity: 1},
+		{ProductID: "prod-b", ProductName: "Kopi Susu", Quantity: 3},
+		{ProductID: "prod-c", ProductName: "Es Teh", Quantity: 0},
+	})
+
+	assert.Equal(t, []models.ReservationLine{
+		{ProductID: "prod-a", ProductName: "Roti Bakar", Quantity: 1},
+		{ProductID: "prod-b", ProductName: "Kopi Susu", Quantity: 5},
+	}, merged, "duplicates are summed, empty lines dropped and products ordered by ID")
+
+	assert.Empty(t, models.MergeReservationLines(nil))
+}
+
+func TestUncoveredReservationLines(t *testing.T) {
+	lines := []models.ReservationLine{
+		{ProductID: "prod-a", ProductName: "Roti Bakar", Quantity: 4},
+		{ProductID: "prod-b", ProductName: "Kopi Susu", Quantity: 2},
+		{ProductID: "prod-c", ProductName: "Es Teh", Quantity: 1},
+	}
+
+	t.Run("should allocate everything when nothing is held", func(t *testing.T) {
+		assert.Equal(t, models.MergeReservationLines(lines), models.UncoveredReservationLines(lines, nil))
+	})
+
+	t.Run("should skip quantities held or already allocated", func(t *testing.T) {
+		reservations := []*models.InventoryReservation{
+			{ProductID: "prod-a", Quantity: 3, Status: models.ReservationStatusActive},
+			{ProductID: "prod-b", Quantity: 2, Status: models.ReservationStatusConverted},
+			{ProductID: "prod-c", Quantity: 1, Status: models.ReservationStatusReleased},
+		}
+
+		assert.Equal(t, []models.ReservationLine{
+			{ProductID: "prod-a", ProductName: "Roti Bakar", Quantity: 1},
+			{ProductID: "prod-c", ProductName: "Es Teh", Quantity: 1},
+		}, models.UncoveredReservationLines(lines, reservations), "released holds no longer cover the line")
+	})
+
+	t.Run("should return nothing once the order is fully allocated", func(t *testing.T) {
+		reservations := []*models.InventoryReservation{
+			{ProductID: "prod-a", Quantity: 4, Status: models.ReservationStatusConverted},
+			{ProductID: "prod-b", Quantity: 2, Status: models.ReservationStatusConverted},
+			{ProductID: "prod-c", Quantity: 1, Status: models.ReservationStatusConverted},
+		}
+
+		assert.Empty(t, models.UncoveredReservationLines(lines, reservations))
+	})
+}
@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/invoice"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invoiceTestBranding() *models.InvoiceBranding {
+	return &models.InvoiceBranding{
+		BusinessName:  "PT Kopi Kita",
+		DisplayName:   "Kopi Kita",
+		PrimaryColor:  "#0F766E",
+		Address:       "Jl. Sudirman No. 1, Jakarta",
+		Phone:         "+6221555000",
+		TaxID:         "01.234.567.8-901.000",
+		InvoiceFooter: "Terima kasih!",
+	}
+}
+
+func testLogo(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 20; y++ {
+			img.Set(x, y, color.RGBA{15, 118, 110, 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestInvoiceBrandingName(t *testing.T) {
+	branding := invoiceTestBranding()
+	assert.Equal(t, "Kopi Kita", branding.Name())
+
+	branding.DisplayName = ""
+	assert.Equal(t, "PT Kopi Kita", branding.Name())
+}
+
+func TestRenderInvoice(t *testing.T) {
+	order, items := receiptTestOrder()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	t.Run("Renders a PDF with the logo", func(t *testing.T) {
+		out, err := invoice.Render(invoiceTestBranding(), testLogo(t), order, items, jakarta)
+		require.NoError(t, err)
+
+		assert.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+		assert.Contains(t, string(out), "/Subtype /Image")
+		assert.Contains(t, string(out), "/Count 1")
+	})
+
+	t.Run("Renders without a logo that is not an image", func(t *testing.T) {
+		out, err := invoice.Render(invoiceTestBranding(), []byte("<html>not found</html>"), order, items, nil)
+		require.NoError(t, err)
+
+		assert.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+		assert.NotContains(t, string(out), "/Subtype /Image")
+	})
+
+	t.Run("Falls back to the default color and breaks long orders across pages", func(t *testing.T) {
+		branding := invoiceTestBranding()
+		branding.PrimaryColor = "teal"
+
+		many := make([]models.OrderItem, 0, 80)
+		for i := 0; i < 80; i++ {
+			many = append(many, models.OrderItem{
+				ProductName: "Nasi Goreng Spesial " + strings.Repeat("Pedas ", i%4),
+				Quantity:    1,
+				UnitPrice:   30000,
+				TotalPrice:  30000,
+			})
+		}
+
+		out, err := invoice.Render(branding, nil, order, many, jakarta)
+		require.NoError(t, err)
+		assert.Regexp(t, `/Count [2-9]`, string(out), "long orders span several pages")
+	})
+}
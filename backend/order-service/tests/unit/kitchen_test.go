@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func kitchenOrder(statuses ...models.PrepStatus) *models.KitchenOrder {
+	order := &models.KitchenOrder{}
+	for _, status := range statuses {
+		order.Items = append(order.Items, models.KitchenItem{PrepStatus: status})
+	}
+	return order
+}
+
+func TestKitchenOrderRollUpPrepStatus(t *testing.T) {
+	t.Run("Queued until any item is started", func(t *testing.T) {
+		assert.Equal(t, models.PrepStatusQueued, kitchenOrder(models.PrepStatusQueued, models.PrepStatusQueued).RollUpPrepStatus())
+		assert.Equal(t, models.PrepStatusQueued, kitchenOrder().RollUpPrepStatus())
+	})
+
+	t.Run("Preparing while some items are not ready", func(t *testing.T) {
+		assert.Equal(t, models.PrepStatusPreparing, kitchenOrder(models.PrepStatusQueued, models.PrepStatusPreparing).RollUpPrepStatus())
+		assert.Equal(t, models.PrepStatusPreparing, kitchenOrder(models.PrepStatusReady, models.PrepStatusQueued).RollUpPrepStatus())
+	})
+
+	t.Run("Ready once every item is ready", func(t *testing.T) {
+		assert.Equal(t, models.PrepStatusReady, kitchenOrder(models.PrepStatusReady, models.PrepStatusReady).RollUpPrepStatus())
+	})
+
+	t.Run("Out-of-stock items do not hold the order back", func(t *testing.T) {
+		assert.Equal(t, models.PrepStatusReady, kitchenOrder(models.PrepStatusReady, models.PrepStatusOutOfStock).RollUpPrepStatus())
+		assert.Equal(t, models.PrepStatusQueued, kitchenOrder(models.PrepStatusQueued, models.PrepStatusOutOfStock).RollUpPrepStatus())
+		assert.Equal(t, models.PrepStatusOutOfStock, kitchenOrder(models.PrepStatusOutOfStock, models.PrepStatusOutOfStock).RollUpPrepStatus())
+	})
+}
+
+func TestSummarizeFulfillment(t *testing.T) {
+	t.Run("Partially ready while some fulfillable items are ready", func(t *testing.T) {
+		summary := models.SummarizeFulfillment([]models.PrepStatus{
+			models.PrepStatusReady, models.PrepStatusPreparing, models.PrepStatusOutOfStock,
+		})
+		assert.Equal(t, models.PrepStatusPreparing, summary.Status)
+		assert.True(t, summary.PartiallyReady)
+		assert.Equal(t, 3, summary.ItemsTotal)
+		assert.Equal(t, 1, summary.ItemsReady)
+		assert.Equal(t, 1, summary.ItemsOutOfStock)
+	})
+
+	t.Run("Not partially ready once the rest is ready", func(t *testing.T) {
+		summary := models.SummarizeFulfillment([]models.PrepStatus{models.PrepStatusReady, models.PrepStatusOutOfStock})
+		assert.Equal(t, models.PrepStatusReady, summary.Status)
+		assert.False(t, summary.PartiallyReady)
+	})
+
+	t.Run("Order items without a status count as queued", func(t *testing.T) {
+		summary := models.SummarizeOrderItems([]models.OrderItem{{}, {PrepStatus: models.PrepStatusReady}})
+		assert.Equal(t, models.PrepStatusPreparing, summary.Status)
+		assert.True(t, summary.PartiallyReady)
+	})
+}
+
+func TestKitchenOrderSetTiming(t *testing.T) {
+	paidAt := time.Date(2026, time.October, 12, 10, 0, 0, 0, time.UTC)
+
+	t.Run("ASAP orders are due the prep time after payment", func(t *testing.T) {
+		order := kitchenOrder(models.PrepStatusPreparing)
+		order.PaidAt = paidAt
+		order.SetTiming(paidAt.Add(25*time.Minute), 20)
+
+		assert.True(t, order.DueAt.Equal(paidAt.Add(20*time.Minute)))
+		assert.True(t, order.IsLate)
+		assert.Equal(t, models.PrepStatusPreparing, order.PrepStatus)
+	})
+
+	t.Run("Scheduled orders are due at their slot", func(t *testing.T) {
+		slot := paidAt.Add(3 * time.Hour)
+		order := kitchenOrder(models.PrepStatusQueued)
+		order.PaidAt = paidAt
+		order.ScheduledFor = &slot
+		order.SetTiming(paidAt.Add(time.Hour), 20)
+
+		assert.True(t, order.DueAt.Equal(slot))
+		assert.False(t, order.IsLate)
+	})
+
+	t.Run("Bumped orders are never late", func(t *testing.T) {
+		bumpedAt := paidAt.Add(time.Hour)
+		order := kitchenOrder(models.PrepStatusReady)
+		order.PaidAt = paidAt
+		order.BumpedAt = &bumpedAt
+		order.SetTiming(paidAt.Add(2*time.Hour), 20)
+
+		assert.False(t, order.IsLate)
+	})
+}
+
+func TestPrepStatusIsValid(t *testing.T) {
+	assert.True(t, models.PrepStatusQueued.IsValid())
+	assert.True(t, models.PrepStatusReady.IsValid())
+	assert.True(t, models.PrepStatusOutOfStock.IsValid())
+	assert.False(t, models.PrepStatus("cooking").IsValid())
+	assert.False(t, models.PrepStatus("").IsValid())
+}
@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func loyaltyIntPtr(i int) *int { return &i }
+
+func enabledLoyaltyProgram() *models.LoyaltyProgram {
+	program := models.DefaultLoyaltyProgram("tenant-1")
+	program.IsEnabled = true
+	return program
+}
+
+func TestLoyaltyPointsEarned(t *testing.T) {
+	program := enabledLoyaltyProgram() // 1 point per Rp 10.000
+
+	assert.Equal(t, 8, program.PointsEarned(85000))
+	assert.Equal(t, 0, program.PointsEarned(9999))
+	assert.Equal(t, 0, program.PointsEarned(-5000))
+
+	program.IsEnabled = false
+	assert.Equal(t, 0, program.PointsEarned(85000), "disabled programs earn nothing")
+}
+
+func TestLoyaltyRedemption(t *testing.T) {
+	program := enabledLoyaltyProgram() // Rp 100 per point, min 10 points, max 50% of the order
+
+	t.Run("Redeems the requested points", func(t *testing.T) {
+		points, discount, err := program.Redemption(50, 120, 80000)
+		assert.NoError(t, err)
+		assert.Equal(t, 50, points)
+		assert.Equal(t, 5000, discount)
+	})
+
+	t.Run("Caps the redemption at the maximum share of the order", func(t *testing.T) {
+		points, discount, err := program.Redemption(500, 600, 30000)
+		assert.NoError(t, err)
+		assert.Equal(t, 150, points) // 50% of Rp 30.000
+		assert.Equal(t, 15000, discount)
+	})
+
+	t.Run("Rejects redemptions the rules do not allow", func(t *testing.T) {
+		_, _, err := program.Redemption(5, 120, 80000)
+		assert.ErrorIs(t, err, models.ErrLoyaltyBelowMinimum)
+
+		_, _, err = program.Redemption(50, 20, 80000)
+		assert.ErrorIs(t, err, models.ErrLoyaltyInsufficientPoints)
+
+		_, _, err = program.Redemption(50, 120, 1500)
+		assert.ErrorIs(t, err, models.ErrLoyaltyOrderTooSmall)
+
+		disabled := models.DefaultLoyaltyProgram("tenant-1")
+		_, _, err = disabled.Redemption(50, 120, 80000)
+		assert.ErrorIs(t, err, models.ErrLoyaltyDisabled)
+	})
+}
+
+func TestUpdateLoyaltyProgramRequestValidate(t *testing.T) {
+	assert.NoError(t, (&models.UpdateLoyaltyProgramRequest{}).Validate())
+	assert.NoError(t, (&models.UpdateLoyaltyProgramRequest{MaxRedeemPercent: loyaltyIntPtr(100)}).Validate())
+
+	invalid := []*models.UpdateLoyaltyProgramRequest{
+		{SpendPerPoint: loyaltyIntPtr(0)},
+		{PointValue: loyaltyIntPtr(-1)},
+		{MinRedeemPoints: loyaltyIntPtr(0)},
+		{MaxRedeemPercent: loyaltyIntPtr(101)},
+	}
+	for _, req := range invalid {
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidLoyaltyProgram)
+	}
+}
+
+func TestCustomerPhoneHash(t *testing.T) {
+	local := models.CustomerPhoneHash("tenant-1", "0812-3456-7890")
+
+	assert.Equal(t, local, models.CustomerPhoneHash("tenant-1", "+62 812 3456 7890"))
+	assert.Equal(t, local, models.VoucherCustomerKey("tenant-1", "6281234567890"), "vouchers and loyalty share the customer key")
+	assert.NotEqual(t, local, models.CustomerPhoneHash("tenant-2", "081234567890"), "keys are per tenant")
+	assert.Len(t, local, 64)
+}
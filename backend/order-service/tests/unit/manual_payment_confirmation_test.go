@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckManualPaymentConfirmation(t *testing.T) {
+	t.Run("Pending order with nothing paid", func(t *testing.T) {
+		order := &models.GuestOrder{Status: models.OrderStatusPending, TotalAmount: 100000}
+		assert.NoError(t, services.CheckManualPaymentConfirmation(order, 0))
+	})
+
+	t.Run("Order no longer awaiting payment", func(t *testing.T) {
+		for _, status := range []models.OrderStatus{models.OrderStatusPaid, models.OrderStatusComplete, models.OrderStatusCancelled} {
+			order := &models.GuestOrder{Status: status, TotalAmount: 100000}
+			assert.ErrorIs(t, services.CheckManualPaymentConfirmation(order, 0), services.ErrOrderNotPayable, "status %s", status)
+		}
+	})
+
+	t.Run("Partly paid split payment", func(t *testing.T) {
+		order := &models.GuestOrder{Status: models.OrderStatusPending, TotalAmount: 100000}
+		assert.ErrorIs(t, services.CheckManualPaymentConfirmation(order, 40000), services.ErrOrderNotPayable)
+	})
+}
+
+func TestManualSettlementNotification(t *testing.T) {
+	t.Run("Carries the settlement webhook's idempotency key", func(t *testing.T) {
+		transactionID := "9f1c2d3e-txn"
+		paymentType := "qris"
+		payment := &models.PaymentTransaction{
+			MidtransOrderID:       "GO-ABC123",
+			MidtransTransactionID: &transactionID,
+			PaymentType:           &paymentType,
+			Amount:                85000,
+		}
+
+		notification, idempotencyKey := services.ManualSettlementNotification(payment)
+		require.NotNil(t, idempotencyKey)
+		assert.Equal(t, "9f1c2d3e-txn:settlement", *idempotencyKey)
+		assert.Equal(t, "GO-ABC123", notification.OrderID)
+		assert.Equal(t, "9f1c2d3e-txn", notification.TransactionID)
+		assert.Equal(t, "85000", notification.GrossAmount)
+		assert.Equal(t, "qris", notification.PaymentType)
+		assert.Equal(t, models.PaymentOutcomeSuccess,
+			models.MapMidtransStatus(notification.PaymentType, notification.TransactionStatus, notification.FraudStatus))
+	})
+
+	t.Run("No idempotency key without a transaction ID", func(t *testing.T) {
+		payment := &models.PaymentTransaction{MidtransOrderID: "GO-ABC123", Amount: 85000}
+
+		notification, idempotencyKey := services.ManualSettlementNotification(payment)
+		assert.Nil(t, idempotencyKey)
+		assert.Empty(t, notification.TransactionID)
+		assert.Equal(t, "settlement", notification.TransactionStatus)
+	})
+}
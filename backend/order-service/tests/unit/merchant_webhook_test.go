@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerchantWebhookRequestValidate(t *testing.T) {
+	req := &models.MerchantWebhookRequest{
+		URL:    "  https://merchant.example.com/hooks/pos ",
+		Events: []models.MerchantWebhookEvent{models.WebhookEventOrderPaid, models.WebhookEventOrderCreated, models.WebhookEventOrderPaid},
+	}
+	require.NoError(t, req.Validate())
+	assert.Equal(t, "https://merchant.example.com/hooks/pos", req.URL)
+	assert.Equal(t, []models.MerchantWebhookEvent{models.WebhookEventOrderPaid, models.WebhookEventOrderCreated}, req.Events)
+
+	invalid := []*models.MerchantWebhookRequest{
+		{URL: "merchant.example.com/hooks", Events: []models.MerchantWebhookEvent{models.WebhookEventOrderPaid}},
+		{URL: "ftp://merchant.example.com/hooks", Events: []models.MerchantWebhookEvent{models.WebhookEventOrderPaid}},
+		{URL: "http://merchant.example.com/hooks", Events: []models.MerchantWebhookEvent{models.WebhookEventOrderPaid}},
+		{URL: "https://merchant.example.com/hooks"},
+		{URL: "https://merchant.example.com/hooks", Events: []models.MerchantWebhookEvent{"order.shipped"}},
+	}
+	for _, r := range invalid {
+		assert.ErrorIs(t, r.Validate(), models.ErrInvalidMerchantWebhook, r.URL)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, models.ValidateWebhookURL("https://merchant.example.com/hooks/pos"))
+	assert.NoError(t, models.ValidateWebhookURL("https://203.0.113.10:8443/hooks"))
+
+	rejected := []string{
+		"http://merchant.example.com/hooks",
+		"https://localhost/hooks",
+		"https://api.localhost/hooks",
+		"https://order-service:8080/internal",
+		"https://127.0.0.1/hooks",
+		"https://10.0.0.5/hooks",
+		"https://172.16.3.4/hooks",
+		"https://192.168.1.1/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://100.64.0.1/hooks",
+		"https://0.0.0.0/hooks",
+		"https://[::1]/hooks",
+		"https://[fd00::1]/hooks",
+		"https://[fe80::1]/hooks",
+		"https://[::ffff:127.0.0.1]/hooks",
+	}
+	for _, rawURL := range rejected {
+		assert.ErrorIs(t, models.ValidateWebhookURL(rawURL), models.ErrInvalidMerchantWebhook, rawURL)
+	}
+}
+
+func TestCheckWebhookAddress(t *testing.T) {
+	assert.NoError(t, models.CheckWebhookAddress("203.0.113.10:443"))
+	assert.NoError(t, models.CheckWebhookAddress("[2001:db8::1]:443"))
+
+	for _, address := range []string{"127.0.0.1:443", "10.1.2.3:443", "169.254.169.254:80", "[::1]:443", "[::ffff:10.0.0.1]:443", "[64:ff9b::a00:1]:443", "not-an-address"} {
+		assert.ErrorIs(t, models.CheckWebhookAddress(address), models.ErrWebhookAddressNotAllowed, address)
+	}
+	assert.False(t, models.IsPublicWebhookIP(net.ParseIP("224.0.0.1")))
+}
+
+func TestWebhookHTTPClientRefusesLocalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = services.NewWebhookHTTPClient(2 * time.Second).Do(req)
+	assert.ErrorIs(t, err, models.ErrWebhookAddressNotAllowed)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, models.WebhookRetryDelay(1))
+	assert.Equal(t, time.Minute, models.WebhookRetryDelay(2))
+	assert.Equal(t, 16*time.Minute, models.WebhookRetryDelay(6))
+	assert.Equal(t, 32*time.Minute, models.WebhookRetryDelay(7))
+	assert.Equal(t, time.Hour, models.WebhookRetryDelay(8))
+	assert.Equal(t, time.Hour, models.WebhookRetryDelay(50))
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"id":"evt-1","type":"order.paid"}`)
+
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte("1700000000." + string(body)))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, expected, models.SignWebhookPayload("whsec_test", 1700000000, body))
+	assert.NotEqual(t, expected, models.SignWebhookPayload("whsec_test", 1700000001, body))
+	assert.NotEqual(t, expected, models.SignWebhookPayload("whsec_other", 1700000000, body))
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func validBatchOrder(createdAt time.Time) services.OfflineBatchOrder {
+	consent := models.ConsentMethodVerbal
+	return services.OfflineBatchOrder{
+		ClientOrderID:    "3f2b8c1e-7a4d-4e7b-9c1a-2d5e6f7a8b9c",
+		CreatedAt:        createdAt,
+		CustomerName:     "Budi Santoso",
+		CustomerPhone:    "081234567890",
+		DeliveryType:     models.DeliveryTypeDineIn,
+		DataConsentGiven: true,
+		ConsentMethod:    &consent,
+		Items: []models.CreateOrderItemReq{
+			{ProductID: "550e8400-e29b-41d4-a716-446655440000", ProductName: "Nasi Goreng", Quantity: 2, UnitPrice: 25000},
+		},
+	}
+}
+
+func TestValidateOfflineBatchOrder(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Valid order", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-2 * time.Hour))
+		assert.NoError(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Invalid client order id", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-time.Hour))
+		order.ClientOrderID = "not-a-uuid"
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Missing timestamp", func(t *testing.T) {
+		order := validBatchOrder(time.Time{})
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Timestamp in the future", func(t *testing.T) {
+		order := validBatchOrder(now.Add(time.Hour))
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Small clock drift is tolerated", func(t *testing.T) {
+		order := validBatchOrder(now.Add(time.Minute))
+		assert.NoError(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Zero quantity item", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-time.Hour))
+		order.Items[0].Quantity = 0
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Missing consent", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-time.Hour))
+		order.DataConsentGiven = false
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Full payment requires amount and method", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-time.Hour))
+		order.PaymentInfo = &services.PaymentInfo{Type: "full"}
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Full payment covering the total", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-time.Hour))
+		amount, method := 50000, models.PaymentMethodCash
+		order.PaymentInfo = &services.PaymentInfo{Type: "full", Amount: &amount, Method: &method}
+		assert.NoError(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Full payment below the total", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-time.Hour))
+		amount, method := 45000, models.PaymentMethodCash
+		order.PaymentInfo = &services.PaymentInfo{Type: "full", Amount: &amount, Method: &method}
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+
+	t.Run("Installment payments are not synced", func(t *testing.T) {
+		order := validBatchOrder(now.Add(-time.Hour))
+		order.PaymentInfo = &services.PaymentInfo{Type: "installment", InstallmentCount: 3}
+		assert.Error(t, services.ValidateOfflineBatchOrder(&order, now))
+	})
+}
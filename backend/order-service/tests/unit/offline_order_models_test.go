@@ -1,5 +1,4 @@
 package unit
-package unit
 
 import (
 	"testing"
@@ -97,6 +96,37 @@ func TestPaymentRecord_PaymentTypeChecks(t *testing.T) {
 	})
 }
 
+func TestValidateCardDetails(t *testing.T) {
+	t.Run("Valid EDC slip", func(t *testing.T) {
+		assert.NoError(t, models.ValidateCardDetails(models.CardTypeVisa, "A1B2C3", "4242"))
+	})
+
+	t.Run("Unknown card type", func(t *testing.T) {
+		err := models.ValidateCardDetails(models.CardType("diners"), "123456", "4242")
+		assert.ErrorIs(t, err, models.ErrInvalidCardType)
+	})
+
+	t.Run("Missing approval code", func(t *testing.T) {
+		err := models.ValidateCardDetails(models.CardTypeMastercard, "", "4242")
+		assert.ErrorIs(t, err, models.ErrInvalidApprovalCode)
+	})
+
+	t.Run("Approval code with symbols", func(t *testing.T) {
+		err := models.ValidateCardDetails(models.CardTypeMastercard, "12-34", "4242")
+		assert.ErrorIs(t, err, models.ErrInvalidApprovalCode)
+	})
+
+	t.Run("Last4 too long", func(t *testing.T) {
+		err := models.ValidateCardDetails(models.CardTypeGPN, "123456", "42424")
+		assert.ErrorIs(t, err, models.ErrInvalidCardLast4)
+	})
+
+	t.Run("Last4 not numeric", func(t *testing.T) {
+		err := models.ValidateCardDetails(models.CardTypeGPN, "123456", "42a2")
+		assert.ErrorIs(t, err, models.ErrInvalidCardLast4)
+	})
+}
+
 func TestPaymentTerms_Methods(t *testing.T) {
 	t.Run("HasRemainingBalance true when balance > 0", func(t *testing.T) {
 		pt := &models.PaymentTerms{RemainingBalance: 100000}
@@ -0,0 +1,46 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchivePartition(t *testing.T) {
+	t.Run("Month of the order", func(t *testing.T) {
+		name, from, to := services.ArchivePartition("archived_guest_orders", time.Date(2025, 3, 17, 14, 5, 0, 0, time.UTC))
+		assert.Equal(t, "archived_guest_orders_2025_03", name)
+		assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), to)
+	})
+
+	t.Run("December ends at the next year", func(t *testing.T) {
+		name, from, to := services.ArchivePartition("archived_order_items", time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC))
+		assert.Equal(t, "archived_order_items_2025_12", name)
+		assert.Equal(t, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), to)
+	})
+
+	t.Run("Month is taken in UTC", func(t *testing.T) {
+		jakarta := time.FixedZone("WIB", 7*60*60)
+		name, from, _ := services.ArchivePartition("archived_payment_transactions", time.Date(2025, 4, 1, 3, 0, 0, 0, jakarta))
+		assert.Equal(t, "archived_payment_transactions_2025_03", name)
+		assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), from)
+	})
+
+	t.Run("Order lies within its partition", func(t *testing.T) {
+		createdAt := time.Date(2025, 2, 28, 23, 59, 59, 999999000, time.UTC)
+		_, from, to := services.ArchivePartition("archived_guest_orders", createdAt)
+		assert.False(t, createdAt.Before(from))
+		assert.True(t, createdAt.Before(to))
+	})
+}
+
+func TestArchiveCutoff(t *testing.T) {
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 4, 17, 8, 0, 0, 0, time.UTC), services.ArchiveCutoff(now, 6))
+	assert.Equal(t, time.Date(2024, 10, 17, 8, 0, 0, 0, time.UTC), services.ArchiveCutoff(now, 24))
+}
@@ -0,0 +1,62 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoCompleteCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC) // 21:30 in Jakarta
+
+	t.Run("Disabled has no cutoff", func(t *testing.T) {
+		settings := &models.OrderSettings{AutoCompleteMode: models.AutoCompleteDisabled}
+		_, ok := settings.AutoCompleteCutoff(now)
+		assert.False(t, ok)
+	})
+
+	t.Run("After paid subtracts the configured hours", func(t *testing.T) {
+		settings := &models.OrderSettings{AutoCompleteMode: models.AutoCompleteAfterPaid, AutoCompleteAfterHours: 4}
+		cutoff, ok := settings.AutoCompleteCutoff(now)
+		assert.True(t, ok)
+		assert.True(t, cutoff.Equal(now.Add(-4*time.Hour)))
+	})
+
+	t.Run("End of day uses local midnight", func(t *testing.T) {
+		settings := &models.OrderSettings{AutoCompleteMode: models.AutoCompleteEndOfDay, AutoCompleteTimezone: "Asia/Jakarta"}
+		cutoff, ok := settings.AutoCompleteCutoff(now)
+		assert.True(t, ok)
+		// Midnight 10 March in Jakarta (UTC+7) is 17:00 UTC on 9 March
+		assert.True(t, cutoff.Equal(time.Date(2026, 3, 9, 17, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("End of day rolls over with the local date", func(t *testing.T) {
+		settings := &models.OrderSettings{AutoCompleteMode: models.AutoCompleteEndOfDay, AutoCompleteTimezone: "Asia/Jakarta"}
+		lateUTC := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC) // 01:00 on 11 March in Jakarta
+		cutoff, ok := settings.AutoCompleteCutoff(lateUTC)
+		assert.True(t, ok)
+		assert.True(t, cutoff.Equal(time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)))
+	})
+}
+
+func TestValidateAutoCompleteSettings(t *testing.T) {
+	mode := func(m models.AutoCompleteMode) *models.AutoCompleteMode { return &m }
+	intPtr := func(i int) *int { return &i }
+	strPtr := func(s string) *string { return &s }
+
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{}).ValidateAutoComplete())
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{
+		AutoCompleteMode:       mode(models.AutoCompleteAfterPaid),
+		AutoCompleteAfterHours: intPtr(6),
+		AutoCompleteTimezone:   strPtr("Asia/Makassar"),
+	}).ValidateAutoComplete())
+
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{AutoCompleteMode: mode("weekly")}).ValidateAutoComplete(),
+		models.ErrInvalidAutoCompleteMode)
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{AutoCompleteAfterHours: intPtr(0)}).ValidateAutoComplete(),
+		models.ErrInvalidAutoCompleteHours)
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{AutoCompleteTimezone: strPtr("Mars/Olympus")}).ValidateAutoComplete(),
+		models.ErrInvalidAutoCompleteTimezone)
+}
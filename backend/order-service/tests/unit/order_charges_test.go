@@ -0,0 +1,47 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderChargeLines(t *testing.T) {
+	t.Run("Tax is charged on the subtotal plus service charge", func(t *testing.T) {
+		settings := &models.OrderSettings{ServiceChargePercent: 5, TaxPercent: 11}
+		serviceCharge, tax := settings.ChargeLines(100000)
+		assert.Equal(t, 5000, serviceCharge)
+		assert.Equal(t, 11550, tax)
+	})
+
+	t.Run("Amounts are rounded to whole rupiah", func(t *testing.T) {
+		settings := &models.OrderSettings{ServiceChargePercent: 2.5, TaxPercent: 11}
+		serviceCharge, tax := settings.ChargeLines(33333)
+		assert.Equal(t, 833, serviceCharge) // 833.325
+		assert.Equal(t, 3758, tax)          // 11% of 34166 = 3758.26
+	})
+
+	t.Run("Zero percentages charge nothing", func(t *testing.T) {
+		settings := &models.OrderSettings{}
+		serviceCharge, tax := settings.ChargeLines(100000)
+		assert.Zero(t, serviceCharge)
+		assert.Zero(t, tax)
+	})
+
+	t.Run("Fully discounted orders charge nothing", func(t *testing.T) {
+		settings := &models.OrderSettings{ServiceChargePercent: 5, TaxPercent: 11}
+		serviceCharge, tax := settings.ChargeLines(0)
+		assert.Zero(t, serviceCharge)
+		assert.Zero(t, tax)
+	})
+}
+
+func TestValidateCharges(t *testing.T) {
+	percent := func(f float64) *float64 { return &f }
+
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{}).ValidateCharges())
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{ServiceChargePercent: percent(5), TaxPercent: percent(11)}).ValidateCharges())
+	assert.Equal(t, models.ErrInvalidServiceChargePercent, (&models.UpdateOrderSettingsRequest{ServiceChargePercent: percent(-1)}).ValidateCharges())
+	assert.Equal(t, models.ErrInvalidTaxPercent, (&models.UpdateOrderSettingsRequest{TaxPercent: percent(101)}).ValidateCharges())
+}
@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditOrderItemsRequest_Validate(t *testing.T) {
+	item := models.OrderItemInput{ProductID: "p1", ProductName: "Latte", Quantity: 2, UnitPrice: 25000}
+
+	t.Run("Valid edit", func(t *testing.T) {
+		req := &models.EditOrderItemsRequest{Items: []models.OrderItemInput{item}}
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("Order must keep at least one item", func(t *testing.T) {
+		req := &models.EditOrderItemsRequest{}
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidOrderEdit)
+	})
+
+	t.Run("Quantity must be positive", func(t *testing.T) {
+		bad := item
+		bad.Quantity = 0
+		req := &models.EditOrderItemsRequest{Items: []models.OrderItemInput{bad}}
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidOrderEdit)
+	})
+}
+
+func TestGuestOrder_Reprice(t *testing.T) {
+	t.Run("Charges use the rates recorded on the order", func(t *testing.T) {
+		order := &models.GuestOrder{ServiceChargeRate: 5, TaxRate: 11, DeliveryFee: 10000}
+		assert.NoError(t, order.Reprice(120000, 10000, 10000))
+
+		assert.Equal(t, 120000, order.SubtotalAmount)
+		assert.Equal(t, 5000, order.ServiceChargeAmount)
+		assert.Equal(t, 11550, order.TaxAmount)
+		assert.Equal(t, 100000+5000+11550+10000, order.TotalAmount)
+	})
+
+	t.Run("Loyalty discount is kept", func(t *testing.T) {
+		order := &models.GuestOrder{LoyaltyDiscountAmount: 5000}
+		assert.NoError(t, order.Reprice(50000, 0, 0))
+		assert.Equal(t, 45000, order.TotalAmount)
+	})
+
+	t.Run("Edit below the redeemed points is rejected", func(t *testing.T) {
+		order := &models.GuestOrder{LoyaltyDiscountAmount: 30000, TotalAmount: 20000}
+		assert.ErrorIs(t, order.Reprice(25000, 0, 0), models.ErrOrderEditBelowLoyaltyDiscount)
+		assert.Equal(t, 20000, order.TotalAmount)
+	})
+}
+
+func TestRevisedChargeOrderID(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		id := models.RevisedChargeOrderID("GO-ABC123", 1)
+		assert.Equal(t, "GO-ABC123_R1", id)
+
+		reference, ok := models.ParseRevisedChargeOrderID(id)
+		assert.True(t, ok)
+		assert.Equal(t, "GO-ABC123", reference)
+	})
+
+	t.Run("Original charge is not a revision", func(t *testing.T) {
+		_, ok := models.ParseRevisedChargeOrderID("GO-ABC123")
+		assert.False(t, ok)
+	})
+
+	t.Run("Split part is not a revision", func(t *testing.T) {
+		_, ok := models.ParseRevisedChargeOrderID(models.SplitPaymentMidtransOrderID("GO-ABC123", 2))
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderHistoryOTPRequestValidate(t *testing.T) {
+	t.Run("defaults to sms and normalizes the phone", func(t *testing.T) {
+		req := &models.OrderHistoryOTPRequest{Phone: "+62 812-3456-7890"}
+		phone, err := req.Validate()
+		require.NoError(t, err)
+		assert.Equal(t, "081234567890", phone)
+		assert.Equal(t, models.OTPChannelSMS, req.Channel)
+	})
+
+	t.Run("accepts whatsapp", func(t *testing.T) {
+		req := &models.OrderHistoryOTPRequest{Phone: "081234567890", Channel: models.OTPChannelWhatsApp}
+		_, err := req.Validate()
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects unknown channels", func(t *testing.T) {
+		req := &models.OrderHistoryOTPRequest{Phone: "081234567890", Channel: "email"}
+		_, err := req.Validate()
+		assert.ErrorIs(t, err, models.ErrInvalidOTPChannel)
+	})
+
+	t.Run("rejects emails and partial numbers", func(t *testing.T) {
+		for _, phone := range []string{"budi@example.com", "0812", ""} {
+			req := &models.OrderHistoryOTPRequest{Phone: phone}
+			_, err := req.Validate()
+			assert.ErrorIs(t, err, models.ErrInvalidOrderHistoryPhone, phone)
+		}
+	})
+}
+
+func TestOrderHistoryVerifyRequestValidate(t *testing.T) {
+	req := &models.OrderHistoryVerifyRequest{Phone: "081234567890", Code: " 123456 "}
+	phone, err := req.Validate()
+	require.NoError(t, err)
+	assert.Equal(t, "081234567890", phone)
+	assert.Equal(t, "123456", req.Code)
+
+	req = &models.OrderHistoryVerifyRequest{Phone: "081234567890", Code: "1234"}
+	_, err = req.Validate()
+	assert.ErrorIs(t, err, models.ErrOrderHistoryOTPInvalid)
+}
+
+func TestGenerateOTPCode(t *testing.T) {
+	code, err := utils.GenerateOTPCode(models.OrderHistoryOTPLength)
+	require.NoError(t, err)
+	assert.Len(t, code, models.OrderHistoryOTPLength)
+	for _, c := range code {
+		assert.True(t, c >= '0' && c <= '9', code)
+	}
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderLimits(t *testing.T) {
+	settings := &models.OrderSettings{
+		MinOrderAmount: 20000,
+		MinOrderAmountByDeliveryType: models.MinOrderAmounts{
+			models.DeliveryTypeDelivery: 50000,
+			models.DeliveryTypeDineIn:   0,
+		},
+		MaxItemsPerOrder: 10,
+	}
+
+	t.Run("Delivery types without an override use min_order_amount", func(t *testing.T) {
+		assert.Equal(t, 20000, settings.MinOrderAmountFor(models.DeliveryTypePickup))
+		assert.Equal(t, 50000, settings.MinOrderAmountFor(models.DeliveryTypeDelivery))
+		assert.Equal(t, 0, settings.MinOrderAmountFor(models.DeliveryTypeDineIn))
+	})
+
+	t.Run("Subtotal below the minimum is rejected", func(t *testing.T) {
+		assert.ErrorIs(t, settings.CheckOrderLimits(models.DeliveryTypeDelivery, 49999, 2), models.ErrBelowMinOrderAmount)
+		assert.NoError(t, settings.CheckOrderLimits(models.DeliveryTypeDelivery, 50000, 2))
+		assert.NoError(t, settings.CheckOrderLimits(models.DeliveryTypeDineIn, 5000, 1))
+	})
+
+	t.Run("Item units above the limit are rejected", func(t *testing.T) {
+		assert.ErrorIs(t, settings.CheckOrderLimits(models.DeliveryTypePickup, 100000, 11), models.ErrMaxItemsExceeded)
+		assert.NoError(t, settings.CheckItemLimit(10))
+	})
+
+	t.Run("Zero max items means unlimited", func(t *testing.T) {
+		unlimited := &models.OrderSettings{}
+		assert.NoError(t, unlimited.CheckItemLimit(1000))
+	})
+
+	t.Run("Overrides must name a delivery type and be non-negative", func(t *testing.T) {
+		valid := models.MinOrderAmounts{models.DeliveryTypePickup: 10000}
+		unknown := models.MinOrderAmounts{"drive_thru": 10000}
+		negative := models.MinOrderAmounts{models.DeliveryTypePickup: -1}
+		maxItems := -1
+
+		assert.NoError(t, (&models.UpdateOrderSettingsRequest{MinOrderAmountByDeliveryType: &valid}).ValidateLimits())
+		assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{MinOrderAmountByDeliveryType: &unknown}).ValidateLimits(), models.ErrInvalidMinOrderAmounts)
+		assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{MinOrderAmountByDeliveryType: &negative}).ValidateLimits(), models.ErrInvalidMinOrderAmounts)
+		assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{MaxItemsPerOrder: &maxItems}).ValidateLimits(), models.ErrInvalidMaxItemsPerOrder)
+	})
+}
@@ -0,0 +1,40 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNoteAttachments(t *testing.T) {
+	photo := models.NoteAttachmentUpload{Filename: "damaged.jpg", ContentType: "image/jpeg", Size: 2 * 1024 * 1024}
+
+	assert.NoError(t, models.ValidateNoteAttachments(nil))
+	assert.NoError(t, models.ValidateNoteAttachments([]models.NoteAttachmentUpload{photo}))
+
+	tooMany := make([]models.NoteAttachmentUpload, models.MaxNoteAttachments+1)
+	for i := range tooMany {
+		tooMany[i] = photo
+	}
+	assert.ErrorIs(t, models.ValidateNoteAttachments(tooMany), models.ErrTooManyNoteAttachments)
+
+	large := photo
+	large.Size = models.MaxNoteAttachmentSizeByte + 1
+	assert.ErrorIs(t, models.ValidateNoteAttachments([]models.NoteAttachmentUpload{large}), models.ErrNoteAttachmentTooLarge)
+
+	pdf := photo
+	pdf.ContentType = "application/pdf"
+	assert.ErrorIs(t, models.ValidateNoteAttachments([]models.NoteAttachmentUpload{pdf}), models.ErrNoteAttachmentType)
+}
+
+func TestNoteAttachmentStorageKey(t *testing.T) {
+	assert.Equal(t,
+		"order-notes/tenant-1/order-1/att-1.png",
+		models.NoteAttachmentStorageKey("tenant-1", "order-1", "att-1", "image/png"),
+	)
+	assert.Equal(t,
+		"order-notes/tenant-1/order-1/att-2.jpg",
+		models.NoteAttachmentStorageKey("tenant-1", "order-1", "att-2", "image/jpeg"),
+	)
+}
@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/middleware"
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/projection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func projectionFixture() (*models.GuestOrder, []models.OrderItem, map[string]int) {
+	email := "budi@example.com"
+	ip := "10.0.0.7"
+	ua := "Mozilla/5.0"
+	order := &models.GuestOrder{
+		ID:             "order-1",
+		OrderReference: "GO-ABC123",
+		Status:         models.OrderStatusPaid,
+		SubtotalAmount: 80000,
+		TotalAmount:    80000,
+		CustomerName:   "Budi Santoso",
+		CustomerPhone:  "+628123456789",
+		CustomerEmail:  &email,
+		DeliveryType:   models.DeliveryTypePickup,
+		SessionID:      "sess-1",
+		IPAddress:      &ip,
+		UserAgent:      &ua,
+	}
+	items := []models.OrderItem{
+		{ID: "item-1", OrderID: "order-1", ProductID: "coffee", ProductName: "Coffee", Quantity: 2, UnitPrice: 30000, TotalPrice: 60000},
+		{ID: "item-2", OrderID: "order-1", ProductID: "gone", ProductName: "Seasonal cake", Quantity: 1, UnitPrice: 20000, TotalPrice: 20000},
+	}
+	costs := map[string]int{"coffee": 12000}
+	return order, items, costs
+}
+
+// projectionJSON returns the projected order and items as the JSON the client receives
+func projectionJSON(t *testing.T, role middleware.Role) (map[string]interface{}, []map[string]interface{}) {
+	order, items, costs := projectionFixture()
+	level := projection.ForRole(role)
+
+	var orderJSON map[string]interface{}
+	raw, err := json.Marshal(projection.Order(order, level))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &orderJSON))
+
+	var itemsJSON []map[string]interface{}
+	raw, err = json.Marshal(projection.OrderItems(items, costs, level))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &itemsJSON))
+
+	return orderJSON, itemsJSON
+}
+
+func TestOrderProjectionCashier(t *testing.T) {
+	order, items := projectionJSON(t, middleware.RoleCashier)
+
+	assert.Equal(t, "GO-ABC123", order["order_reference"])
+	assert.Equal(t, float64(80000), order["total_amount"])
+	assert.Equal(t, "B*** S***", order["customer_name"])
+	assert.Equal(t, "******6789", order["customer_phone"])
+	for _, field := range []string{"customer_email", "session_id", "ip_address", "user_agent"} {
+		assert.NotContains(t, order, field)
+	}
+
+	require.Len(t, items, 2)
+	assert.Equal(t, float64(60000), items[0]["total_price"])
+	for _, field := range []string{"unit_cost", "cost_total", "margin"} {
+		assert.NotContains(t, items[0], field)
+	}
+}
+
+func TestOrderProjectionManager(t *testing.T) {
+	order, items := projectionJSON(t, middleware.RoleManager)
+
+	assert.Equal(t, "Budi Santoso", order["customer_name"])
+	assert.Equal(t, "+628123456789", order["customer_phone"])
+	assert.Equal(t, "budi@example.com", order["customer_email"])
+	for _, field := range []string{"session_id", "ip_address", "user_agent"} {
+		assert.NotContains(t, order, field)
+	}
+
+	require.Len(t, items, 2)
+	for _, field := range []string{"unit_cost", "cost_total", "margin"} {
+		assert.NotContains(t, items[0], field)
+	}
+}
+
+func TestOrderProjectionOwner(t *testing.T) {
+	order, items := projectionJSON(t, middleware.RoleOwner)
+
+	assert.Equal(t, "Budi Santoso", order["customer_name"])
+	assert.Equal(t, "budi@example.com", order["customer_email"])
+	assert.Equal(t, "sess-1", order["session_id"])
+	assert.Equal(t, "10.0.0.7", order["ip_address"])
+	assert.Equal(t, "Mozilla/5.0", order["user_agent"])
+
+	require.Len(t, items, 2)
+	assert.Equal(t, float64(12000), items[0]["unit_cost"])
+	assert.Equal(t, float64(24000), items[0]["cost_total"])
+	assert.Equal(t, float64(36000), items[0]["margin"])
+	assert.NotContains(t, items[1], "margin", "deleted products have no cost")
+}
+
+func TestOrderProjectionUnknownRoleIsRestricted(t *testing.T) {
+	assert.Equal(t, projection.LevelOperational, projection.ForRole(""))
+	assert.Equal(t, projection.LevelOperational, projection.ForRole("auditor"))
+
+	order, _ := projectionJSON(t, "")
+	assert.Equal(t, "B*** S***", order["customer_name"])
+}
+
+func TestOrderProjectionDoesNotModifyOrder(t *testing.T) {
+	order, _, _ := projectionFixture()
+	projection.Order(order, projection.LevelOperational)
+
+	assert.Equal(t, "Budi Santoso", order.CustomerName)
+	assert.NotNil(t, order.IPAddress)
+}
+
+func TestProjectionMasking(t *testing.T) {
+	assert.Equal(t, "******7890", projection.MaskPhone("0812-3456-7890"))
+	assert.Equal(t, "******", projection.MaskPhone("123"))
+	assert.Equal(t, "", projection.MaskPhone(""))
+	assert.Equal(t, "S***", projection.MaskName("  Siti "))
+	assert.Equal(t, "", projection.MaskName(""))
+}
@@ -0,0 +1,50 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewQueueDisplay(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	readyAt := func(minutesAgo int) *time.Time {
+		at := now.Add(-time.Duration(minutesAgo) * time.Minute)
+		return &at
+	}
+
+	t.Run("Splits ready and preparing orders", func(t *testing.T) {
+		display := models.NewQueueDisplay("2026-03-02", []models.QueueEntry{
+			{QueueNumber: 1, ReadyAt: readyAt(5)},
+			{QueueNumber: 2},
+			{QueueNumber: 3, ReadyAt: readyAt(1)},
+			{QueueNumber: 4},
+		}, now)
+
+		assert.Equal(t, "2026-03-02", display.QueueDate)
+		assert.Equal(t, now, display.UpdatedAt)
+
+		// Most recently called first; preparing stays in queue order
+		assert.Equal(t, []int{3, 1}, queueNumbers(display.NowServing))
+		assert.Equal(t, []int{2, 4}, queueNumbers(display.Preparing))
+	})
+
+	t.Run("Empty queue serializes as empty lists", func(t *testing.T) {
+		display := models.NewQueueDisplay("2026-03-02", nil, now)
+
+		assert.NotNil(t, display.NowServing)
+		assert.NotNil(t, display.Preparing)
+		assert.Empty(t, display.NowServing)
+		assert.Empty(t, display.Preparing)
+	})
+}
+
+func queueNumbers(entries []models.QueueEntry) []int {
+	numbers := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		numbers = append(numbers, entry.QueueNumber)
+	}
+	return numbers
+}
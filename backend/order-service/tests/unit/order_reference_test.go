@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeOrderReferencePattern(t *testing.T) {
+	valid := []struct {
+		pattern string
+		want    string
+	}{
+		{"WARUNG-YYYYMMDD-####", "WARUNG-YYYYMMDD-####"},
+		{" kopi-yymmdd-### ", "KOPI-YYMMDD-###"},
+		{"TOKO1-DD-MM-YYYY-######", "TOKO1-DD-MM-YYYY-######"},
+	}
+	for _, tt := range valid {
+		t.Run("Accepts "+tt.pattern, func(t *testing.T) {
+			got, err := models.NormalizeOrderReferencePattern(tt.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	invalid := map[string]string{
+		"No prefix":              "YYYYMMDD-####",
+		"Prefix too short":       "W-YYYYMMDD-####",
+		"Prefix too long":        "WARUNGMAKAN1-YYYYMMDD-####",
+		"No sequence":            "WARUNG-YYYYMMDD",
+		"Sequence too short":     "WARUNG-YYYYMMDD-##",
+		"Two sequences":          "WARUNG-YYYYMMDD-###-###",
+		"No day":                 "WARUNG-YYYYMM-####",
+		"No year":                "WARUNG-MMDD-####",
+		"Invalid character":      "WARUNG_YYYYMMDD_####",
+		"Longer than the limit":  "WARUNG-YYYYMMDD-YYYYMMDD-ABCDEF-####",
+		"Sequence in the prefix": "W##-YYYYMMDD-####",
+	}
+	for name, pattern := range invalid {
+		t.Run("Rejects "+name, func(t *testing.T) {
+			_, err := models.NormalizeOrderReferencePattern(pattern)
+			assert.ErrorIs(t, err, models.ErrInvalidOrderReferencePattern)
+		})
+	}
+
+	t.Run("Rejects the global prefix", func(t *testing.T) {
+		_, err := models.NormalizeOrderReferencePattern("GO-YYYYMMDD-####")
+		assert.ErrorIs(t, err, models.ErrOrderReferencePrefixReserved)
+	})
+}
+
+func TestFormatOrderReference(t *testing.T) {
+	day := time.Date(2026, time.October, 6, 9, 30, 0, 0, time.UTC)
+
+	t.Run("Fills the date and pads the sequence", func(t *testing.T) {
+		assert.Equal(t, "WARUNG-20261006-0001", models.FormatOrderReference("WARUNG-YYYYMMDD-####", day, 1))
+		assert.Equal(t, "KOPI-261006-042", models.FormatOrderReference("KOPI-YYMMDD-###", day, 42))
+	})
+
+	t.Run("Keeps a Y, M or D that is not a placeholder", func(t *testing.T) {
+		assert.Equal(t, "DAPUR-06D-10M-2026-007", models.FormatOrderReference("DAPUR-DDD-MMM-YYYY-###", day, 7))
+	})
+
+	t.Run("Writes an overflowing sequence in full", func(t *testing.T) {
+		assert.Equal(t, "KOPI-261006-1000", models.FormatOrderReference("KOPI-YYMMDD-###", day, 1000))
+	})
+
+	t.Run("Does not treat a prefix as placeholders", func(t *testing.T) {
+		assert.Equal(t, "MMDD-20261006-001", models.FormatOrderReference("MMDD-YYYYMMDD-###", day, 1))
+		assert.Equal(t, "MMDD", models.OrderReferencePrefix("MMDD-YYYYMMDD-###"))
+	})
+}
+
+func TestValidateOrderReferenceSetting(t *testing.T) {
+	t.Run("Normalizes the pattern", func(t *testing.T) {
+		pattern := "warung-yyyymmdd-####"
+		req := &models.UpdateOrderSettingsRequest{OrderReferencePattern: &pattern}
+		assert.NoError(t, req.ValidateOrderReference())
+		assert.Equal(t, "WARUNG-YYYYMMDD-####", *req.OrderReferencePattern)
+	})
+
+	t.Run("Allows clearing the pattern", func(t *testing.T) {
+		pattern := ""
+		req := &models.UpdateOrderSettingsRequest{OrderReferencePattern: &pattern}
+		assert.NoError(t, req.ValidateOrderReference())
+	})
+}
@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateReturnRequestValidate(t *testing.T) {
+	valid := &models.CreateReturnRequest{
+		Resolution: models.ReturnResolutionStoreCredit,
+		Items:      []models.ReturnItemRequest{{OrderItemID: "i1", Quantity: 1}},
+	}
+	require.NoError(t, valid.Validate())
+
+	invalid := []*models.CreateReturnRequest{
+		{Resolution: "cash", Items: []models.ReturnItemRequest{{OrderItemID: "i1", Quantity: 1}}},
+		{Resolution: models.ReturnResolutionRefund},
+		{Resolution: models.ReturnResolutionRefund, Items: []models.ReturnItemRequest{{Quantity: 1}}},
+		{Resolution: models.ReturnResolutionRefund, Items: []models.ReturnItemRequest{{OrderItemID: "i1"}}},
+	}
+	for _, req := range invalid {
+		assert.ErrorIs(t, req.Validate(), models.ErrInvalidReturn)
+	}
+}
+
+func TestBuildReturnItems(t *testing.T) {
+	orderItems := []models.OrderItem{
+		{ID: "i1", ProductID: "p1", ProductName: "T-Shirt", Quantity: 3, UnitPrice: 100000},
+		{ID: "i2", ProductID: "p2", ProductName: "Cap", Quantity: 1, UnitPrice: 50000},
+	}
+	damaged := "Torn seam"
+
+	items, value, err := models.BuildReturnItems(orderItems, map[string]int{"i1": 1}, []models.ReturnItemRequest{
+		{OrderItemID: "i1", Quantity: 1},
+		{OrderItemID: "i2", Quantity: 1, Reason: &damaged},
+		{OrderItemID: "i1", Quantity: 1, Reason: &damaged},
+	})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "p1", items[0].ProductID)
+	assert.Equal(t, 2, items[0].Quantity)
+	assert.Equal(t, 100000, items[0].UnitPrice)
+	assert.Equal(t, &damaged, items[0].Reason)
+	assert.Equal(t, "Cap", items[1].ProductName)
+	assert.Equal(t, 250000, value)
+
+	_, _, err = models.BuildReturnItems(orderItems, map[string]int{"i1": 2}, []models.ReturnItemRequest{{OrderItemID: "i1", Quantity: 2}})
+	assert.ErrorIs(t, err, models.ErrReturnQuantityExceeded)
+
+	_, _, err = models.BuildReturnItems(orderItems, nil, []models.ReturnItemRequest{{OrderItemID: "other", Quantity: 1}})
+	assert.ErrorIs(t, err, models.ErrInvalidReturn)
+}
+
+func TestReturnCredit(t *testing.T) {
+	assert.Equal(t, 100000, models.ReturnCredit(100000, 300000, 0))
+	assert.Equal(t, 50000, models.ReturnCredit(100000, 300000, 250000))
+	assert.Equal(t, 0, models.ReturnCredit(100000, 300000, 300000))
+	assert.Equal(t, 0, models.ReturnCredit(100000, 300000, 400000))
+}
+
+func TestSplitExchangeCredit(t *testing.T) {
+	items := []models.CreateOrderItemReq{
+		{ProductID: "p3", ProductName: "T-Shirt (L)", Quantity: 1, UnitPrice: 80000},
+	}
+
+	total, remainder, err := models.SplitExchangeCredit(100000, items)
+	require.NoError(t, err)
+	assert.Equal(t, 80000, total)
+	assert.Equal(t, 20000, remainder)
+
+	_, _, err = models.SplitExchangeCredit(50000, items)
+	assert.ErrorIs(t, err, models.ErrExchangeExceedsCredit)
+
+	_, _, err = models.SplitExchangeCredit(100000, nil)
+	assert.ErrorIs(t, err, models.ErrInvalidReturn)
+}
+
+func TestOrderReturnCanTransition(t *testing.T) {
+	ret := &models.OrderReturn{Status: models.ReturnStatusRequested}
+	assert.True(t, ret.CanTransition(models.ReturnStatusApproved))
+	assert.True(t, ret.CanTransition(models.ReturnStatusRejected))
+	assert.False(t, ret.CanTransition(models.ReturnStatusReceived))
+
+	ret.Status = models.ReturnStatusReceived
+	assert.True(t, ret.CanTransition(models.ReturnStatusCompleted))
+	assert.False(t, ret.CanTransition(models.ReturnStatusRejected))
+
+	ret.Status = models.ReturnStatusCompleted
+	assert.False(t, ret.CanTransition(models.ReturnStatusCompleted))
+}
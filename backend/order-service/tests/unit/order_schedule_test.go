@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scheduleSettings opens Mondays 10:00-14:00 Jakarta time with 30 minute slots
+func scheduleSettings() *models.OrderSettings {
+	return &models.OrderSettings{
+		EstimatedPrepTime:      20,
+		AutoCompleteTimezone:   "Asia/Jakarta",
+		SchedulingEnabled:      true,
+		BusinessHours:          models.BusinessHours{"monday": {Open: "10:00", Close: "14:00"}},
+		SlotDurationMinutes:    30,
+		SlotCapacity:           2,
+		SchedulingMaxDaysAhead: 0,
+	}
+}
+
+func jakartaTime(t *testing.T, day, hour, minute int) time.Time {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	return time.Date(2026, time.October, day, hour, minute, 0, 0, loc) // 12 October 2026 is a Monday
+}
+
+func TestValidateScheduledSlot(t *testing.T) {
+	now := jakartaTime(t, 12, 9, 0)
+
+	t.Run("Slot start within business hours is accepted", func(t *testing.T) {
+		settings := scheduleSettings()
+		assert.NoError(t, settings.ValidateScheduledSlot(jakartaTime(t, 12, 10, 0), now))
+		assert.NoError(t, settings.ValidateScheduledSlot(jakartaTime(t, 12, 13, 30), now))
+		assert.NoError(t, settings.ValidateScheduledSlot(jakartaTime(t, 12, 11, 0).UTC(), now))
+	})
+
+	t.Run("Scheduling must be enabled", func(t *testing.T) {
+		settings := scheduleSettings()
+		settings.SchedulingEnabled = false
+		assert.ErrorIs(t, settings.ValidateScheduledSlot(jakartaTime(t, 12, 10, 0), now), models.ErrSchedulingDisabled)
+	})
+
+	t.Run("Slot must start on a slot boundary", func(t *testing.T) {
+		assert.ErrorIs(t, scheduleSettings().ValidateScheduledSlot(jakartaTime(t, 12, 10, 15), now), models.ErrScheduledSlotUnavailable)
+	})
+
+	t.Run("Slot must end by closing time", func(t *testing.T) {
+		assert.ErrorIs(t, scheduleSettings().ValidateScheduledSlot(jakartaTime(t, 12, 14, 0), now), models.ErrScheduledSlotUnavailable)
+	})
+
+	t.Run("Slot must leave the estimated prep time", func(t *testing.T) {
+		assert.ErrorIs(t, scheduleSettings().ValidateScheduledSlot(jakartaTime(t, 12, 9, 10), now), models.ErrScheduledSlotTooSoon)
+	})
+
+	t.Run("Slot must fall within the booking horizon", func(t *testing.T) {
+		assert.ErrorIs(t, scheduleSettings().ValidateScheduledSlot(jakartaTime(t, 19, 10, 0), now), models.ErrScheduledSlotTooFar)
+	})
+
+	t.Run("Closed days have no slots", func(t *testing.T) {
+		settings := scheduleSettings()
+		settings.SchedulingMaxDaysAhead = 7
+		assert.ErrorIs(t, settings.ValidateScheduledSlot(jakartaTime(t, 13, 10, 0), now), models.ErrScheduledSlotUnavailable)
+		assert.NoError(t, settings.ValidateScheduledSlot(jakartaTime(t, 19, 10, 0), now))
+	})
+}
+
+func TestOrderSettingsSlots(t *testing.T) {
+	t.Run("Lists every slot of the day before opening", func(t *testing.T) {
+		slots := scheduleSettings().Slots(jakartaTime(t, 12, 9, 0))
+		require.Len(t, slots, 8)
+		assert.True(t, slots[0].Start.Equal(jakartaTime(t, 12, 10, 0)))
+		assert.True(t, slots[7].End.Equal(jakartaTime(t, 12, 14, 0)))
+	})
+
+	t.Run("Skips slots that start before the order can be ready", func(t *testing.T) {
+		slots := scheduleSettings().Slots(jakartaTime(t, 12, 10, 45))
+		require.Len(t, slots, 5)
+		assert.True(t, slots[0].Start.Equal(jakartaTime(t, 12, 11, 30)))
+	})
+
+	t.Run("Disabled scheduling lists no slots", func(t *testing.T) {
+		settings := scheduleSettings()
+		settings.SchedulingEnabled = false
+		assert.Empty(t, settings.Slots(jakartaTime(t, 12, 9, 0)))
+	})
+
+	t.Run("Remaining capacity counts booked orders", func(t *testing.T) {
+		settings := scheduleSettings()
+		slots := settings.Slots(jakartaTime(t, 12, 9, 0))
+		settings.FillRemaining(slots, map[time.Time]int{
+			jakartaTime(t, 12, 10, 0).UTC():  2,
+			jakartaTime(t, 12, 10, 30).UTC(): 1,
+		})
+		require.NotNil(t, slots[0].Remaining)
+		assert.Equal(t, 0, *slots[0].Remaining)
+		assert.Equal(t, 1, *slots[1].Remaining)
+		assert.Equal(t, 2, *slots[2].Remaining)
+		assert.True(t, settings.SlotFull(2))
+		assert.False(t, settings.SlotFull(1))
+	})
+
+	t.Run("Unlimited capacity leaves remaining unset", func(t *testing.T) {
+		settings := scheduleSettings()
+		settings.SlotCapacity = 0
+		slots := settings.Slots(jakartaTime(t, 12, 9, 0))
+		settings.FillRemaining(slots, map[time.Time]int{jakartaTime(t, 12, 10, 0).UTC(): 50})
+		assert.Nil(t, slots[0].Remaining)
+		assert.False(t, settings.SlotFull(50))
+	})
+}
+
+func TestBusinessHoursValidate(t *testing.T) {
+	assert.NoError(t, models.BusinessHours{"monday": {Open: "08:00", Close: "24:00"}}.Validate())
+	assert.ErrorIs(t, models.BusinessHours{"mon": {Open: "08:00", Close: "17:00"}}.Validate(), models.ErrInvalidBusinessHours)
+	assert.ErrorIs(t, models.BusinessHours{"monday": {Open: "17:00", Close: "08:00"}}.Validate(), models.ErrInvalidBusinessHours)
+	assert.ErrorIs(t, models.BusinessHours{"monday": {Open: "8am", Close: "17:00"}}.Validate(), models.ErrInvalidBusinessHours)
+}
+
+func TestGuestOrderDueAt(t *testing.T) {
+	paidAt := jakartaTime(t, 12, 10, 0)
+
+	t.Run("ASAP orders are due the prep time after payment", func(t *testing.T) {
+		order := &models.GuestOrder{Status: models.OrderStatusPaid, PaidAt: &paidAt}
+		assert.True(t, order.DueAt(20).Equal(jakartaTime(t, 12, 10, 20)))
+		assert.False(t, order.IsLate(jakartaTime(t, 12, 10, 15), 20))
+		assert.True(t, order.IsLate(jakartaTime(t, 12, 10, 25), 20))
+	})
+
+	t.Run("Scheduled orders are due at their slot", func(t *testing.T) {
+		slot := jakartaTime(t, 12, 13, 0)
+		order := &models.GuestOrder{Status: models.OrderStatusPaid, PaidAt: &paidAt, ScheduledFor: &slot}
+		assert.True(t, order.DueAt(20).Equal(slot))
+		assert.False(t, order.IsLate(jakartaTime(t, 12, 12, 0), 20))
+		assert.True(t, order.IsLate(jakartaTime(t, 12, 13, 5), 20))
+	})
+
+	t.Run("Completed orders are never late", func(t *testing.T) {
+		order := &models.GuestOrder{Status: models.OrderStatusComplete, PaidAt: &paidAt}
+		assert.False(t, order.IsLate(jakartaTime(t, 12, 18, 0), 20))
+	})
+}
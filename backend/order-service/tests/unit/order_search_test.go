@@ -0,0 +1,34 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContactSearch(t *testing.T) {
+	t.Run("email is lowercased", func(t *testing.T) {
+		search, err := models.ParseContactSearch("  Budi.Santoso@Example.COM ")
+		require.NoError(t, err)
+		assert.True(t, search.Email)
+		assert.Equal(t, "budi.santoso@example.com", search.Value)
+	})
+
+	t.Run("phone formats normalize alike", func(t *testing.T) {
+		for _, query := range []string{"081234567890", "+62 812-3456-7890", "62812 3456 7890"} {
+			search, err := models.ParseContactSearch(query)
+			require.NoError(t, err, query)
+			assert.False(t, search.Email)
+			assert.Equal(t, "081234567890", search.Value)
+		}
+	})
+
+	t.Run("rejects partial or non-contact queries", func(t *testing.T) {
+		for _, query := range []string{"0812", "ORD-1234", "budi", ""} {
+			_, err := models.ParseContactSearch(query)
+			assert.ErrorIs(t, err, models.ErrInvalidContactSearch, query)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderItemsTiming(t *testing.T) {
+	paidAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		ts := paidAt.Add(time.Duration(minutes) * time.Minute)
+		return &ts
+	}
+
+	t.Run("Waiting until an item is started", func(t *testing.T) {
+		timing := models.OrderItemsTiming(paidAt, []models.OrderItem{
+			{PrepStatus: models.PrepStatusQueued},
+		})
+		assert.Nil(t, timing.PrepStartedAt)
+		assert.Nil(t, timing.ReadyAt)
+	})
+
+	t.Run("Started at the first item, ready at the last", func(t *testing.T) {
+		timing := models.OrderItemsTiming(paidAt, []models.OrderItem{
+			{PrepStatus: models.PrepStatusReady, PrepStartedAt: at(6), PrepReadyAt: at(15)},
+			{PrepStatus: models.PrepStatusReady, PrepStartedAt: at(4), PrepReadyAt: at(12)},
+		})
+		assert.Equal(t, *at(4), *timing.PrepStartedAt)
+		assert.Equal(t, *at(15), *timing.ReadyAt)
+	})
+
+	t.Run("Not ready while an item is preparing", func(t *testing.T) {
+		timing := models.OrderItemsTiming(paidAt, []models.OrderItem{
+			{PrepStatus: models.PrepStatusReady, PrepStartedAt: at(4), PrepReadyAt: at(12)},
+			{PrepStatus: models.PrepStatusPreparing, PrepStartedAt: at(5)},
+		})
+		assert.Equal(t, *at(4), *timing.PrepStartedAt)
+		assert.Nil(t, timing.ReadyAt)
+	})
+
+	t.Run("Skips out-of-stock items", func(t *testing.T) {
+		timing := models.OrderItemsTiming(paidAt, []models.OrderItem{
+			{PrepStatus: models.PrepStatusReady, PrepReadyAt: at(9)},
+			{PrepStatus: models.PrepStatusOutOfStock},
+		})
+		assert.Equal(t, *at(9), *timing.PrepStartedAt)
+		assert.Equal(t, *at(9), *timing.ReadyAt)
+	})
+}
+
+func TestEvaluateSLA(t *testing.T) {
+	paidAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return paidAt.Add(time.Duration(minutes) * time.Minute)
+	}
+	ptr := func(ts time.Time) *time.Time { return &ts }
+	settings := &models.OrderSettings{SLAPreparingMinutes: 10, SLAReadyMinutes: 20, SLAAtRiskPercent: 80}
+
+	tests := []struct {
+		name   string
+		timing models.OrderTiming
+		now    time.Time
+		stage  models.SLAStage
+		status models.SLAStatus
+	}{
+		{"Waiting within target", models.OrderTiming{PaidAt: paidAt}, at(5), models.SLAStageWaiting, models.SLAStatusOnTrack},
+		{"Waiting at risk", models.OrderTiming{PaidAt: paidAt}, at(8), models.SLAStageWaiting, models.SLAStatusAtRisk},
+		{"Waiting too long", models.OrderTiming{PaidAt: paidAt}, at(11), models.SLAStageWaiting, models.SLAStatusLate},
+		{"Preparing within target", models.OrderTiming{PaidAt: paidAt, PrepStartedAt: ptr(at(9))}, at(20), models.SLAStagePreparing, models.SLAStatusOnTrack},
+		{"Preparing too long", models.OrderTiming{PaidAt: paidAt, PrepStartedAt: ptr(at(2))}, at(23), models.SLAStagePreparing, models.SLAStatusLate},
+		{"Ready after a late start", models.OrderTiming{PaidAt: paidAt, PrepStartedAt: ptr(at(12)), ReadyAt: ptr(at(20))}, at(30), models.SLAStageReady, models.SLAStatusLate},
+		{"Ready on time", models.OrderTiming{PaidAt: paidAt, PrepStartedAt: ptr(at(3)), ReadyAt: ptr(at(15))}, at(30), models.SLAStageReady, models.SLAStatusOnTrack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sla := settings.EvaluateSLA(tt.timing, tt.now)
+			assert.Equal(t, tt.stage, sla.Stage)
+			assert.Equal(t, tt.status, sla.Status)
+		})
+	}
+
+	t.Run("Untimed stage stays on track", func(t *testing.T) {
+		onlyReady := &models.OrderSettings{SLAReadyMinutes: 20}
+		sla := onlyReady.EvaluateSLA(models.OrderTiming{PaidAt: paidAt}, at(60))
+		assert.Equal(t, models.SLAStatusOnTrack, sla.Status)
+		assert.Zero(t, sla.TargetSeconds)
+	})
+}
+
+func TestOrderSLAFor(t *testing.T) {
+	paidAt := time.Now().Add(-15 * time.Minute)
+	settings := &models.OrderSettings{SLAPreparingMinutes: 10, SLAAtRiskPercent: 80}
+
+	t.Run("Times paid ASAP orders", func(t *testing.T) {
+		order := &models.GuestOrder{Status: models.OrderStatusPaid, PaidAt: &paidAt}
+		sla, ok := settings.OrderSLAFor(order, nil, time.Now())
+		assert.True(t, ok)
+		assert.Equal(t, models.SLAStatusLate, sla.Status)
+	})
+
+	t.Run("Skips scheduled orders", func(t *testing.T) {
+		slot := time.Now().Add(time.Hour)
+		order := &models.GuestOrder{Status: models.OrderStatusPaid, PaidAt: &paidAt, ScheduledFor: &slot}
+		_, ok := settings.OrderSLAFor(order, nil, time.Now())
+		assert.False(t, ok)
+	})
+
+	t.Run("Skips tenants without targets", func(t *testing.T) {
+		order := &models.GuestOrder{Status: models.OrderStatusPaid, PaidAt: &paidAt}
+		_, ok := (&models.OrderSettings{}).OrderSLAFor(order, nil, time.Now())
+		assert.False(t, ok)
+	})
+}
+
+func TestValidateSLA(t *testing.T) {
+	tooLong := models.MaxSLATargetMinutes + 1
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{SLAReadyMinutes: &tooLong}).ValidateSLA(), models.ErrInvalidSLATarget)
+
+	full := 100
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{SLAAtRiskPercent: &full}).ValidateSLA(), models.ErrInvalidSLAAtRiskPercent)
+
+	off := 0
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{SLAPreparingMinutes: &off}).ValidateSLA())
+}
@@ -0,0 +1,38 @@
+package unit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchOrderStatusRequestValidate(t *testing.T) {
+	t.Run("Drops duplicate and empty order IDs", func(t *testing.T) {
+		req := &models.BatchOrderStatusRequest{
+			OrderIDs: []string{"order-1", "", "order-2", "order-1"},
+			Status:   models.OrderStatusComplete,
+		}
+		assert.NoError(t, req.Validate())
+		assert.Equal(t, []string{"order-1", "order-2"}, req.OrderIDs)
+	})
+
+	t.Run("Rejects an unknown status", func(t *testing.T) {
+		req := &models.BatchOrderStatusRequest{OrderIDs: []string{"order-1"}, Status: "READY"}
+		assert.ErrorIs(t, req.Validate(), models.ErrBatchStatusInvalid)
+	})
+
+	t.Run("Rejects an empty batch", func(t *testing.T) {
+		req := &models.BatchOrderStatusRequest{OrderIDs: []string{""}, Status: models.OrderStatusPaid}
+		assert.ErrorIs(t, req.Validate(), models.ErrBatchStatusEmpty)
+	})
+
+	t.Run("Rejects more than the batch limit", func(t *testing.T) {
+		req := &models.BatchOrderStatusRequest{Status: models.OrderStatusComplete}
+		for i := 0; i <= models.MaxBatchStatusOrders; i++ {
+			req.OrderIDs = append(req.OrderIDs, fmt.Sprintf("order-%d", i))
+		}
+		assert.ErrorIs(t, req.Validate(), models.ErrBatchStatusTooLarge)
+	})
+}
@@ -0,0 +1,38 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatusEventIsFinal(t *testing.T) {
+	assert.False(t, (&models.OrderStatusEvent{Status: models.OrderStatusPending}).IsFinal())
+	assert.False(t, (&models.OrderStatusEvent{Status: models.OrderStatusPaid}).IsFinal())
+	assert.True(t, (&models.OrderStatusEvent{Status: models.OrderStatusComplete}).IsFinal())
+	assert.True(t, (&models.OrderStatusEvent{Status: models.OrderStatusCancelled}).IsFinal())
+}
+
+func TestOrderStatusBroadcasterSubscribe(t *testing.T) {
+	t.Run("Unsubscribing closes the stream once", func(t *testing.T) {
+		broadcaster := services.NewOrderStatusBroadcaster(nil)
+		events, unsubscribe := broadcaster.Subscribe("ORD-123")
+
+		unsubscribe()
+		unsubscribe()
+
+		_, open := <-events
+		assert.False(t, open)
+	})
+
+	t.Run("Publishing without Redis is a no-op", func(t *testing.T) {
+		var nilBroadcaster *services.OrderStatusBroadcaster
+		assert.NotPanics(t, func() {
+			nilBroadcaster.Publish(context.Background(), &models.OrderStatusEvent{OrderReference: "ORD-123"})
+			services.NewOrderStatusBroadcaster(nil).Publish(context.Background(), &models.OrderStatusEvent{OrderReference: "ORD-123"})
+		})
+	})
+}
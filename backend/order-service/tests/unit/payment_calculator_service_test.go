@@ -119,6 +119,32 @@ func TestPaymentCalculatorService_ValidatePaymentAmount(t *testing.T) {
 	})
 }
 
+func TestPaymentCalculatorService_CalculateChange(t *testing.T) {
+	calc := services.NewPaymentCalculator()
+
+	t.Run("Tendered more than due", func(t *testing.T) {
+		change, err := calc.CalculateChange(85000, 100000)
+		require.NoError(t, err)
+		assert.Equal(t, 15000, change)
+	})
+
+	t.Run("Exact amount gives no change", func(t *testing.T) {
+		change, err := calc.CalculateChange(85000, 85000)
+		require.NoError(t, err)
+		assert.Equal(t, 0, change)
+	})
+
+	t.Run("Insufficient tender", func(t *testing.T) {
+		_, err := calc.CalculateChange(85000, 50000)
+		assert.ErrorIs(t, err, models.ErrInsufficientTender)
+	})
+
+	t.Run("Zero amount due is invalid", func(t *testing.T) {
+		_, err := calc.CalculateChange(0, 50000)
+		require.Error(t, err)
+	})
+}
+
 func TestPaymentCalculatorService_CalculateNextPaymentNumber(t *testing.T) {
 	calc := services.NewPaymentCalculator()
 
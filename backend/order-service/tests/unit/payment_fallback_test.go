@@ -0,0 +1,66 @@
+package unit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentOutagePolicy(t *testing.T) {
+	t.Run("Only reject and pay_at_counter are valid", func(t *testing.T) {
+		assert.True(t, models.PaymentOutageReject.IsValid())
+		assert.True(t, models.PaymentOutagePayAtCounter.IsValid())
+		assert.False(t, models.PaymentOutagePolicy("queue").IsValid())
+	})
+
+	t.Run("Settings update rejects an unknown policy", func(t *testing.T) {
+		policy := models.PaymentOutagePolicy("cash_only")
+		req := &models.UpdateOrderSettingsRequest{PaymentOutagePolicy: &policy}
+		assert.ErrorIs(t, req.ValidatePaymentOutage(), models.ErrInvalidPaymentOutagePolicy)
+
+		assert.NoError(t, (&models.UpdateOrderSettingsRequest{}).ValidatePaymentOutage())
+	})
+}
+
+func TestGatewayOutageStatus(t *testing.T) {
+	for _, code := range []int{0, 408, 429, 500, 502, 503, 504} {
+		assert.True(t, models.IsGatewayOutageStatus(code), "status %d", code)
+	}
+	for _, code := range []int{200, 201, 400, 401, 404, 406} {
+		assert.False(t, models.IsGatewayOutageStatus(code), "status %d", code)
+	}
+
+	wrapped := fmt.Errorf("%w: charge request failed with status 503", models.ErrPaymentGatewayUnavailable)
+	assert.ErrorIs(t, wrapped, models.ErrPaymentGatewayUnavailable)
+}
+
+func TestChargeRetry(t *testing.T) {
+	t.Run("Only QRIS charges are retried", func(t *testing.T) {
+		assert.True(t, models.QueuesChargeRetry(models.CheckoutPaymentQRIS))
+		assert.False(t, models.QueuesChargeRetry(models.CheckoutPaymentGoPay))
+		assert.False(t, models.QueuesChargeRetry(models.CheckoutPaymentBankTransfer))
+		assert.False(t, models.QueuesChargeRetry(models.CheckoutPaymentCreditCard))
+	})
+
+	t.Run("Backoff doubles up to the maximum", func(t *testing.T) {
+		assert.Equal(t, time.Minute, models.ChargeRetryDelay(0))
+		assert.Equal(t, time.Minute, models.ChargeRetryDelay(1))
+		assert.Equal(t, 2*time.Minute, models.ChargeRetryDelay(2))
+		assert.Equal(t, 8*time.Minute, models.ChargeRetryDelay(4))
+		assert.Equal(t, models.ChargeRetryMaxDelay, models.ChargeRetryDelay(5))
+		assert.Equal(t, models.ChargeRetryMaxDelay, models.ChargeRetryDelay(50))
+	})
+
+	t.Run("Retried charges map back to the order and never reuse an edit revision", func(t *testing.T) {
+		gatewayOrderID := models.ChargeRetryOrderID("ORD-ABC123", 1)
+		reference, ok := models.ParseRevisedChargeOrderID(gatewayOrderID)
+		assert.True(t, ok)
+		assert.Equal(t, "ORD-ABC123", reference)
+
+		assert.NotEqual(t, models.RevisedChargeOrderID("ORD-ABC123", 1), gatewayOrderID)
+		assert.NotEqual(t, models.ChargeRetryOrderID("ORD-ABC123", 2), gatewayOrderID)
+	})
+}
@@ -0,0 +1,181 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXenditTransactionStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected string
+	}{
+		{"SUCCEEDED", "settlement"},
+		{"COMPLETED", "settlement"},
+		{"PAID", "settlement"},
+		{"SETTLED", "settlement"},
+		{"paid", "settlement"},
+		{"PENDING", "pending"},
+		{"ACTIVE", "pending"},
+		{"EXPIRED", "expire"},
+		{"FAILED", "failure"},
+		{"INACTIVE", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.expected, models.XenditTransactionStatus(tt.status))
+		})
+	}
+}
+
+func TestPaymentGatewayProviderIsValid(t *testing.T) {
+	assert.True(t, models.PaymentGatewayMidtrans.IsValid())
+	assert.True(t, models.PaymentGatewayXendit.IsValid())
+	assert.False(t, models.PaymentGatewayProvider("stripe").IsValid())
+}
+
+func TestParseXenditCallback(t *testing.T) {
+	t.Run("QR code payment", func(t *testing.T) {
+		body := []byte(`{
+			"event": "qr.payment",
+			"data": {
+				"id": "qrpy_123",
+				"qr_id": "qr_456",
+				"reference_id": "GO-ABC123",
+				"amount": 150000,
+				"status": "SUCCEEDED",
+				"created": "2026-01-10T08:00:00Z"
+			}
+		}`)
+
+		n, err := services.ParseXenditCallback(body)
+		require.NoError(t, err)
+		assert.Equal(t, "qr_456", n.TransactionID)
+		assert.Equal(t, "GO-ABC123", n.OrderID)
+		assert.Equal(t, "settlement", n.TransactionStatus)
+		assert.Equal(t, "qris", n.PaymentType)
+		assert.Equal(t, "150000.00", n.GrossAmount)
+		assert.Equal(t, models.PaymentOutcomeSuccess, models.MapMidtransStatus(n.PaymentType, n.TransactionStatus, n.FraudStatus))
+	})
+
+	t.Run("Virtual account payment", func(t *testing.T) {
+		body := []byte(`{
+			"id": "pay_789",
+			"callback_virtual_account_id": "va_321",
+			"external_id": "GO-ABC123_S2",
+			"bank_code": "BNI",
+			"amount": 50000,
+			"transaction_timestamp": "2026-01-10T08:00:00Z"
+		}`)
+
+		n, err := services.ParseXenditCallback(body)
+		require.NoError(t, err)
+		assert.Equal(t, "va_321", n.TransactionID)
+		assert.Equal(t, "GO-ABC123_S2", n.OrderID)
+		assert.Equal(t, "settlement", n.TransactionStatus)
+		assert.Equal(t, "bank_transfer", n.PaymentType)
+		assert.Equal(t, "50000.00", n.GrossAmount)
+	})
+
+	t.Run("Invoice paid", func(t *testing.T) {
+		body := []byte(`{
+			"id": "inv_555",
+			"external_id": "GO-ABC123",
+			"status": "PAID",
+			"amount": 200000,
+			"paid_amount": 200000,
+			"payment_method": "CREDIT_CARD"
+		}`)
+
+		n, err := services.ParseXenditCallback(body)
+		require.NoError(t, err)
+		assert.Equal(t, "inv_555", n.TransactionID)
+		assert.Equal(t, "settlement", n.TransactionStatus)
+		assert.Equal(t, "credit_card", n.PaymentType)
+		assert.Equal(t, "200000.00", n.GrossAmount)
+	})
+
+	t.Run("Invoice expired", func(t *testing.T) {
+		body := []byte(`{"id": "inv_555", "external_id": "GO-ABC123", "status": "EXPIRED", "amount": 200000}`)
+
+		n, err := services.ParseXenditCallback(body)
+		require.NoError(t, err)
+		assert.Equal(t, "expire", n.TransactionStatus)
+		assert.Equal(t, models.PaymentOutcomeFailed, models.MapMidtransStatus(n.PaymentType, n.TransactionStatus, n.FraudStatus))
+	})
+
+	t.Run("Unrecognized callback", func(t *testing.T) {
+		_, err := services.ParseXenditCallback([]byte(`{"event": "disbursement.completed"}`))
+		assert.ErrorIs(t, err, services.ErrUnrecognizedXenditCallback)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := services.ParseXenditCallback([]byte(`not json`))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseMidtransTime(t *testing.T) {
+	parsed, err := services.ParseMidtransTime("2026-10-16 21:30:00")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, parsed.Location())
+	assert.True(t, parsed.Equal(time.Date(2026, time.October, 16, 14, 30, 0, 0, time.UTC)), "Midtrans times are WIB (UTC+7)")
+
+	_, err = services.ParseMidtransTime("2026-10-16T21:30:00Z")
+	assert.Error(t, err)
+}
+
+func TestReservationTTLFor(t *testing.T) {
+	assert.Equal(t, services.BankTransferReservationTTL, services.ReservationTTLFor(models.CheckoutPaymentBankTransfer, 0))
+	assert.Equal(t, services.ReservationTTL, services.ReservationTTLFor(models.CheckoutPaymentQRIS, 0))
+	assert.Equal(t, services.ReservationTTL, services.ReservationTTLFor(models.CheckoutPaymentGoPay, 0))
+
+	// The tenant's payment expiry applies to QRIS and e-wallets; virtual accounts keep their own
+	assert.Equal(t, 30*time.Minute, services.ReservationTTLFor(models.CheckoutPaymentQRIS, 30*time.Minute))
+	assert.Equal(t, 30*time.Minute, services.ReservationTTLFor(models.CheckoutPaymentGoPay, 30*time.Minute))
+	assert.Equal(t, services.BankTransferReservationTTL, services.ReservationTTLFor(models.CheckoutPaymentBankTransfer, 30*time.Minute))
+}
+
+func TestPaymentExpirySettings(t *testing.T) {
+	minutes := func(m int) *int { return &m }
+
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{}).ValidatePaymentExpiry())
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{PaymentExpiryMinutes: minutes(10)}).ValidatePaymentExpiry())
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{PaymentExpiryMinutes: minutes(60)}).ValidatePaymentExpiry())
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{PaymentExpiryMinutes: minutes(9)}).ValidatePaymentExpiry(), models.ErrInvalidPaymentExpiry)
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{PaymentExpiryMinutes: minutes(61)}).ValidatePaymentExpiry(), models.ErrInvalidPaymentExpiry)
+
+	assert.Equal(t, 25*time.Minute, (&models.OrderSettings{PaymentExpiryMinutes: 25}).PaymentExpiry())
+	assert.Equal(t, services.ReservationTTL, (&models.OrderSettings{}).PaymentExpiry())
+}
+
+func TestReservationTTLSettings(t *testing.T) {
+	minutes := func(m int) *int { return &m }
+
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{ReservationTTLMinutes: minutes(120), ReservationExtensionMinutes: minutes(0)}).ValidateReservation())
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{ReservationTTLMinutes: minutes(9)}).ValidateReservation(), models.ErrInvalidReservationTTL)
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{ReservationTTLMinutes: minutes(121)}).ValidateReservation(), models.ErrInvalidReservationTTL)
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{ReservationExtensionMinutes: minutes(-1)}).ValidateReservation(), models.ErrInvalidReservationExtension)
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{ReservationExtensionMinutes: minutes(61)}).ValidateReservation(), models.ErrInvalidReservationExtension)
+
+	assert.Equal(t, services.ReservationTTL, (&models.OrderSettings{}).ReservationTTL())
+	assert.Equal(t, 45*time.Minute, (&models.OrderSettings{ReservationTTLMinutes: 45, PaymentExpiryMinutes: 15}).ReservationTTL())
+	// Stock is never released while the charge can still be paid
+	assert.Equal(t, 30*time.Minute, (&models.OrderSettings{ReservationTTLMinutes: 15, PaymentExpiryMinutes: 30}).ReservationTTL())
+
+	settings := &models.OrderSettings{ReservationTTLMinutes: 90, PaymentExpiryMinutes: 15}
+	assert.Equal(t, 90*time.Minute, services.CheckoutReservationTTL(models.CheckoutPaymentQRIS, settings))
+	assert.Equal(t, 90*time.Minute, services.CheckoutReservationTTL(models.CheckoutPaymentBankTransfer, settings))
+	settings.ReservationTTLMinutes = 20
+	assert.Equal(t, 20*time.Minute, services.CheckoutReservationTTL(models.CheckoutPaymentQRIS, settings))
+	assert.Equal(t, services.BankTransferReservationTTL, services.CheckoutReservationTTL(models.CheckoutPaymentBankTransfer, settings))
+
+	assert.Zero(t, (&models.OrderSettings{}).ReservationExtension())
+	assert.Equal(t, 10*time.Minute, (&models.OrderSettings{ReservationExtensionMinutes: 10}).ReservationExtension())
+}
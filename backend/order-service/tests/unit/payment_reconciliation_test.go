@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconciliationApplies(t *testing.T) {
+	pending := &models.GuestOrder{Status: models.OrderStatusPending}
+
+	t.Run("Settled charge of a pending order", func(t *testing.T) {
+		status := &services.GatewayPaymentStatus{Status: "settlement", Outcome: models.PaymentOutcomeSuccess}
+		assert.True(t, services.ReconciliationApplies(pending, status))
+	})
+
+	t.Run("Failed charge of a pending order", func(t *testing.T) {
+		status := &services.GatewayPaymentStatus{Status: "expire", Outcome: models.PaymentOutcomeFailed}
+		assert.True(t, services.ReconciliationApplies(pending, status))
+	})
+
+	t.Run("Charge still pending at the gateway", func(t *testing.T) {
+		status := &services.GatewayPaymentStatus{Status: "pending", Outcome: models.PaymentOutcomePending}
+		assert.False(t, services.ReconciliationApplies(pending, status))
+	})
+
+	t.Run("Order no longer awaiting payment", func(t *testing.T) {
+		paid := &models.GuestOrder{Status: models.OrderStatusPaid}
+		status := &services.GatewayPaymentStatus{Status: "settlement", Outcome: models.PaymentOutcomeSuccess}
+		assert.False(t, services.ReconciliationApplies(paid, status))
+	})
+}
+
+func TestReconciledNotification(t *testing.T) {
+	t.Run("Settled charge maps to a successful settlement", func(t *testing.T) {
+		payment := &models.PaymentTransaction{MidtransOrderID: "GO-ABC123", Amount: 85000}
+		status := &services.GatewayPaymentStatus{
+			Gateway:       models.PaymentGatewayMidtrans,
+			TransactionID: "txn-1",
+			Status:        "settlement",
+			Outcome:       models.PaymentOutcomeSuccess,
+		}
+
+		notification := services.ReconciledNotification(payment, status)
+		assert.Equal(t, "settlement", notification.TransactionStatus)
+		assert.Equal(t, "txn-1", notification.TransactionID)
+		assert.Equal(t, "GO-ABC123", notification.OrderID)
+		assert.Equal(t, "85000", notification.GrossAmount)
+		assert.Equal(t, models.PaymentOutcomeSuccess,
+			models.MapMidtransStatus(notification.PaymentType, notification.TransactionStatus, notification.FraudStatus))
+	})
+
+	t.Run("Midtrans failure keeps its status", func(t *testing.T) {
+		payment := &models.PaymentTransaction{MidtransOrderID: "GO-ABC123", Amount: 85000}
+		status := &services.GatewayPaymentStatus{
+			Gateway:       models.PaymentGatewayMidtrans,
+			TransactionID: "txn-1",
+			Status:        "DENY",
+			Outcome:       models.PaymentOutcomeFailed,
+		}
+
+		notification := services.ReconciledNotification(payment, status)
+		assert.Equal(t, "deny", notification.TransactionStatus)
+		assert.Equal(t, models.PaymentOutcomeFailed,
+			models.MapMidtransStatus(notification.PaymentType, notification.TransactionStatus, notification.FraudStatus))
+	})
+
+	t.Run("Other gateways' failures expire the charge", func(t *testing.T) {
+		payment := &models.PaymentTransaction{MidtransOrderID: "GO-ABC123", Amount: 85000}
+		status := &services.GatewayPaymentStatus{
+			Gateway:       models.PaymentGatewayXendit,
+			TransactionID: "qr_123",
+			Status:        "INACTIVE",
+			Outcome:       models.PaymentOutcomeFailed,
+		}
+
+		notification := services.ReconciledNotification(payment, status)
+		assert.Equal(t, "expire", notification.TransactionStatus)
+	})
+
+	t.Run("Falls back to the stored transaction ID, then the charge's order ID", func(t *testing.T) {
+		stored := "txn-stored"
+		status := &services.GatewayPaymentStatus{Gateway: models.PaymentGatewayMidtrans, Status: "expire", Outcome: models.PaymentOutcomeFailed}
+
+		withStored := &models.PaymentTransaction{MidtransOrderID: "GO-ABC123", MidtransTransactionID: &stored}
+		assert.Equal(t, "txn-stored", services.ReconciledNotification(withStored, status).TransactionID)
+
+		withoutStored := &models.PaymentTransaction{MidtransOrderID: "GO-ABC123"}
+		assert.Equal(t, "GO-ABC123", services.ReconciledNotification(withoutStored, status).TransactionID)
+	})
+}
@@ -0,0 +1,57 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapMidtransStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		paymentType string
+		status      string
+		fraud       string
+		expected    models.PaymentOutcome
+	}{
+		{"QRIS settlement", "qris", "settlement", "", models.PaymentOutcomeSuccess},
+		{"GoPay settlement", "gopay", "settlement", "accept", models.PaymentOutcomeSuccess},
+		{"VA pending", "bank_transfer", "pending", "", models.PaymentOutcomePending},
+		{"VA expired", "bank_transfer", "expire", "", models.PaymentOutcomeFailed},
+		{"Card capture accepted", "credit_card", "capture", "accept", models.PaymentOutcomeSuccess},
+		{"Card capture challenged", "credit_card", "capture", "challenge", models.PaymentOutcomePending},
+		{"Card capture denied by fraud", "credit_card", "capture", "deny", models.PaymentOutcomeFailed},
+		{"Card denied", "credit_card", "deny", "", models.PaymentOutcomeFailed},
+		{"Card authorize waits for capture", "credit_card", "authorize", "accept", models.PaymentOutcomePending},
+		{"Cancel", "gopay", "cancel", "", models.PaymentOutcomeFailed},
+		{"Refund is ignored", "qris", "refund", "", models.PaymentOutcomeIgnored},
+		{"Status is case-insensitive", "QRIS", "SETTLEMENT", "", models.PaymentOutcomeSuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, models.MapMidtransStatus(tt.paymentType, tt.status, tt.fraud))
+		})
+	}
+}
+
+func TestValidateCheckoutPayment(t *testing.T) {
+	t.Run("QRIS needs no bank", func(t *testing.T) {
+		assert.NoError(t, models.ValidateCheckoutPayment(models.CheckoutPaymentQRIS, ""))
+	})
+
+	t.Run("Bank transfer with supported bank", func(t *testing.T) {
+		assert.NoError(t, models.ValidateCheckoutPayment(models.CheckoutPaymentBankTransfer, "bca"))
+	})
+
+	t.Run("Bank transfer without bank", func(t *testing.T) {
+		err := models.ValidateCheckoutPayment(models.CheckoutPaymentBankTransfer, "")
+		assert.ErrorIs(t, err, models.ErrUnsupportedBank)
+	})
+
+	t.Run("Unknown method", func(t *testing.T) {
+		err := models.ValidateCheckoutPayment(models.CheckoutPaymentMethod("paypal"), "")
+		assert.ErrorIs(t, err, models.ErrUnsupportedPaymentMethod)
+	})
+}
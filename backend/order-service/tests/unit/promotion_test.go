@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func promotionIntPtr(i int) *int       { return &i }
+func promotionStrPtr(s string) *string { return &s }
+
+func TestPromotionRequestValidate(t *testing.T) {
+	t.Run("Defaults scope, timezone and BOGO discount", func(t *testing.T) {
+		req := &models.PromotionRequest{
+			Name:          "Buy 1 get 1 coffee",
+			PromotionType: models.PromotionBOGO,
+			BuyQuantity:   promotionIntPtr(1),
+			GetQuantity:   promotionIntPtr(1),
+		}
+		assert.NoError(t, req.Validate())
+		assert.Equal(t, models.PromotionScopeOrder, req.Scope)
+		assert.Equal(t, "Asia/Jakarta", req.Timezone)
+		assert.Equal(t, 100, req.DiscountValue)
+	})
+
+	t.Run("Rejects invalid rules", func(t *testing.T) {
+		invalid := []*models.PromotionRequest{
+			{Name: "", PromotionType: models.PromotionPercentOff, DiscountValue: 10},
+			{Name: "Too much", PromotionType: models.PromotionPercentOff, DiscountValue: 150},
+			{Name: "No quantities", PromotionType: models.PromotionBOGO},
+			{Name: "Cap on fixed", PromotionType: models.PromotionFixedOff, DiscountValue: 5000, MaxDiscountAmount: promotionIntPtr(1000)},
+			{Name: "Missing category", PromotionType: models.PromotionPercentOff, DiscountValue: 20, Scope: models.PromotionScopeCategory},
+			{Name: "Order with product", PromotionType: models.PromotionPercentOff, DiscountValue: 20, ProductID: promotionStrPtr("p1")},
+			{Name: "Bad day", PromotionType: models.PromotionPercentOff, DiscountValue: 20, DaysOfWeek: []int{7}},
+			{Name: "Half window", PromotionType: models.PromotionPercentOff, DiscountValue: 20, StartTime: promotionStrPtr("10:00")},
+			{Name: "Bad time", PromotionType: models.PromotionPercentOff, DiscountValue: 20, StartTime: promotionStrPtr("25:00"), EndTime: promotionStrPtr("26:00")},
+			{Name: "Bad zone", PromotionType: models.PromotionPercentOff, DiscountValue: 20, Timezone: "Mars/Olympus"},
+		}
+		for _, req := range invalid {
+			assert.ErrorIs(t, req.Validate(), models.ErrInvalidPromotion, req.Name)
+		}
+	})
+}
+
+func TestPromotionActiveAt(t *testing.T) {
+	// Tuesday 10 March 2026, 14:30 in Jakarta
+	now := time.Date(2026, 3, 10, 7, 30, 0, 0, time.UTC)
+
+	weekdays := &models.Promotion{IsActive: true, Timezone: "Asia/Jakarta", DaysOfWeek: []int{1, 2, 3, 4, 5}}
+	assert.True(t, weekdays.ActiveAt(now))
+	assert.False(t, weekdays.ActiveAt(now.Add(4*24*time.Hour)), "Saturday")
+
+	happyHour := &models.Promotion{IsActive: true, Timezone: "Asia/Jakarta", StartTime: promotionStrPtr("14:00"), EndTime: promotionStrPtr("16:00")}
+	assert.True(t, happyHour.ActiveAt(now))
+	assert.False(t, happyHour.ActiveAt(now.Add(2*time.Hour)), "16:30 local")
+
+	lateNight := &models.Promotion{IsActive: true, Timezone: "Asia/Jakarta", StartTime: promotionStrPtr("22:00"), EndTime: promotionStrPtr("02:00")}
+	assert.False(t, lateNight.ActiveAt(now))
+	assert.True(t, lateNight.ActiveAt(now.Add(9*time.Hour)), "23:30 local")
+	assert.True(t, lateNight.ActiveAt(now.Add(11*time.Hour)), "01:30 local")
+
+	inactive := &models.Promotion{IsActive: false, Timezone: "Asia/Jakarta"}
+	assert.False(t, inactive.ActiveAt(now))
+}
+
+func TestApplyPromotions(t *testing.T) {
+	now := time.Date(2026, 3, 10, 7, 30, 0, 0, time.UTC)
+	drinks := "cat-drinks"
+
+	lines := []services.PromotionLine{
+		{ProductID: "coffee", CategoryID: drinks, UnitPrice: 30000, Quantity: 2},
+		{ProductID: "tea", CategoryID: drinks, UnitPrice: 20000, Quantity: 1},
+		{ProductID: "cake", UnitPrice: 50000, Quantity: 1},
+	}
+
+	categoryOff := &models.Promotion{
+		ID: "category", Name: "20% off drinks", IsActive: true, Timezone: "Asia/Jakarta", Stackable: true,
+		PromotionType: models.PromotionPercentOff, Scope: models.PromotionScopeCategory, CategoryID: &drinks, DiscountValue: 20,
+	}
+
+	t.Run("Category percent discount only touches the category", func(t *testing.T) {
+		applied := services.ApplyPromotions([]*models.Promotion{categoryOff}, lines, now)
+		assert.Len(t, applied, 1)
+		assert.Equal(t, 16000, applied[0].DiscountAmount) // 20% of 80.000
+	})
+
+	t.Run("BOGO discounts the cheapest unit of each group", func(t *testing.T) {
+		bogo := &models.Promotion{
+			ID: "bogo", Name: "Buy 1 get 1 drinks", IsActive: true, Timezone: "Asia/Jakarta", Stackable: true,
+			PromotionType: models.PromotionBOGO, Scope: models.PromotionScopeCategory, CategoryID: &drinks,
+			DiscountValue: 100, BuyQuantity: promotionIntPtr(1), GetQuantity: promotionIntPtr(1),
+		}
+		applied := services.ApplyPromotions([]*models.Promotion{bogo}, lines, now)
+		assert.Len(t, applied, 1)
+		// Drink units 30.000, 30.000, 20.000: one full group, the second coffee is free
+		assert.Equal(t, 30000, applied[0].DiscountAmount)
+	})
+
+	t.Run("Stackable promotions apply on what is left, by priority", func(t *testing.T) {
+		orderOff := &models.Promotion{
+			ID: "order", Name: "10k off", IsActive: true, Timezone: "Asia/Jakarta", Stackable: true, Priority: -1,
+			PromotionType: models.PromotionFixedOff, Scope: models.PromotionScopeOrder, DiscountValue: 10000,
+		}
+		applied := services.ApplyPromotions([]*models.Promotion{orderOff, categoryOff}, lines, now)
+		assert.Len(t, applied, 2)
+		assert.Equal(t, "category", applied[0].PromotionID)
+		assert.Equal(t, 26000, services.TotalPromotionDiscount(applied))
+	})
+
+	t.Run("Exclusive promotion wins only when it beats the stack", func(t *testing.T) {
+		exclusive := &models.Promotion{
+			ID: "exclusive", Name: "25% off everything", IsActive: true, Timezone: "Asia/Jakarta", Stackable: false,
+			PromotionType: models.PromotionPercentOff, Scope: models.PromotionScopeOrder, DiscountValue: 25,
+		}
+		applied := services.ApplyPromotions([]*models.Promotion{categoryOff, exclusive}, lines, now)
+		assert.Len(t, applied, 1)
+		assert.Equal(t, "exclusive", applied[0].PromotionID)
+		assert.Equal(t, 32500, applied[0].DiscountAmount)
+
+		small := *exclusive
+		small.ID = "small"
+		small.DiscountValue = 5
+		applied = services.ApplyPromotions([]*models.Promotion{categoryOff, &small}, lines, now)
+		assert.Len(t, applied, 1)
+		assert.Equal(t, "category", applied[0].PromotionID)
+	})
+
+	t.Run("Minimum subtotal and schedule are respected", func(t *testing.T) {
+		weekend := *categoryOff
+		weekend.DaysOfWeek = []int{0, 6}
+		assert.Empty(t, services.ApplyPromotions([]*models.Promotion{&weekend}, lines, now))
+
+		bigSpender := *categoryOff
+		bigSpender.MinSubtotal = 200000
+		assert.Empty(t, services.ApplyPromotions([]*models.Promotion{&bigSpender}, lines, now))
+	})
+
+	t.Run("Fixed discount never exceeds the eligible amount", func(t *testing.T) {
+		cakeOff := &models.Promotion{
+			ID: "cake", Name: "Cake deal", IsActive: true, Timezone: "Asia/Jakarta", Stackable: true,
+			PromotionType: models.PromotionFixedOff, Scope: models.PromotionScopeProduct, ProductID: promotionStrPtr("cake"), DiscountValue: 80000,
+		}
+		applied := services.ApplyPromotions([]*models.Promotion{cakeOff}, lines, now)
+		assert.Len(t, applied, 1)
+		assert.Equal(t, 50000, applied[0].DiscountAmount)
+	})
+}
@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/receipt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receiptTestOrder() (*models.GuestOrder, []models.OrderItem) {
+	table := "7"
+	notes := "No ice\x1b@ please"
+	queueNumber := 12
+	paidAt := time.Date(2026, 3, 2, 5, 30, 0, 0, time.UTC)
+	order := &models.GuestOrder{
+		OrderReference: "ORD-ABC123",
+		Status:         models.OrderStatusPaid,
+		DeliveryType:   models.DeliveryTypeDineIn,
+		TableNumber:    &table,
+		Notes:          &notes,
+		QueueNumber:    &queueNumber,
+		SubtotalAmount: 50000,
+		TaxRate:        11,
+		TaxAmount:      5500,
+		TotalAmount:    55500,
+		CustomerName:   "Budi",
+		PaidAt:         &paidAt,
+	}
+	items := []models.OrderItem{
+		{ProductName: "Es Kopi Susu", Quantity: 2, UnitPrice: 25000, TotalPrice: 50000},
+	}
+	return order, items
+}
+
+func TestFormatRupiah(t *testing.T) {
+	assert.Equal(t, "Rp 0", receipt.FormatRupiah(0))
+	assert.Equal(t, "Rp 500", receipt.FormatRupiah(500))
+	assert.Equal(t, "Rp 25.000", receipt.FormatRupiah(25000))
+	assert.Equal(t, "Rp 1.250.000", receipt.FormatRupiah(1250000))
+	assert.Equal(t, "-Rp 5.000", receipt.FormatRupiah(-5000))
+}
+
+func TestCustomerReceipt(t *testing.T) {
+	order, items := receiptTestOrder()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	out := receipt.CustomerReceipt(receipt.Store{Name: "Kopi Kita", Location: jakarta}, order, items, models.PaperWidth58mm)
+	text := string(out)
+
+	assert.True(t, bytes.HasPrefix(out, []byte{0x1B, 0x40}), "starts by resetting the printer")
+	assert.True(t, bytes.HasSuffix(out, []byte{0x1D, 0x56, 0x42, 0x03}), "ends with a cut")
+	assert.Contains(t, text, "Kopi Kita")
+	assert.Contains(t, text, "02 Mar 2026 12:30", "printed in the store timezone")
+	assert.Contains(t, text, "Table 7")
+	assert.Contains(t, text, "Tax 11%")
+	assert.Contains(t, text, "Rp 55.500")
+
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.ContainsRune(line, 0x1B) && !strings.ContainsRune(line, 0x1D) {
+			assert.LessOrEqual(t, len(line), models.PaperWidth58mm, "line %q overflows the paper", line)
+		}
+	}
+}
+
+func TestCustomerReceiptBranding(t *testing.T) {
+	order, items := receiptTestOrder()
+	store := receipt.Store{
+		Name:   "Kopi Kita",
+		Header: "Jl. Sudirman 1, Jakarta\nWiFi: kopikita",
+		Footer: "Terima kasih!\nSee you again",
+		SocialLinks: map[string]string{
+			"website":   "https://www.kopikita.id/",
+			"instagram": "https://instagram.com/kopikita",
+		},
+	}
+
+	text := string(receipt.CustomerReceipt(store, order, items, models.PaperWidth58mm))
+
+	assert.Contains(t, text, "Jl. Sudirman 1, Jakarta\nWiFi: kopikita\n\nQueue number", "header under the name, line breaks kept")
+	assert.Contains(t, text, "Terima kasih!\nSee you again\n")
+	assert.NotContains(t, text, "Thank you!", "the footer replaces the default closing line")
+	assert.Contains(t, text, "instagram.com/kopikita\nkopikita.id\n", "links sorted by platform without their scheme")
+
+	plain := string(receipt.CustomerReceipt(receipt.Store{Name: "Kopi Kita"}, order, items, models.PaperWidth58mm))
+	assert.Contains(t, plain, "Thank you!")
+}
+
+func TestKitchenTicket(t *testing.T) {
+	order, items := receiptTestOrder()
+
+	out := receipt.KitchenTicket(receipt.Store{Name: "Kopi Kita"}, order, items, models.PaperWidth80mm)
+	text := string(out)
+
+	assert.Contains(t, text, "#12")
+	assert.Contains(t, text, "2x Es Kopi Susu")
+	assert.NotContains(t, text, "Rp ", "kitchen tickets carry no prices")
+
+	// Control bytes in guest notes are printed as text, never as commands
+	assert.Contains(t, text, "No ice?@ please")
+	assert.Equal(t, 1, bytes.Count(out, []byte{0x1B, 0x40}), "only the printer reset at the start")
+}
+
+func TestCreatePrintJobsRequestValidate(t *testing.T) {
+	t.Run("Defaults to both documents on 80mm paper", func(t *testing.T) {
+		req := &models.CreatePrintJobsRequest{}
+		require.NoError(t, req.Validate())
+		assert.Equal(t, []models.PrintJobKind{models.PrintJobKitchenTicket, models.PrintJobReceipt}, req.Kinds)
+		assert.Equal(t, models.PaperWidth80mm, req.PaperWidth)
+	})
+
+	t.Run("Rejects unknown kinds and widths", func(t *testing.T) {
+		assert.ErrorIs(t, (&models.CreatePrintJobsRequest{Kinds: []models.PrintJobKind{"label"}}).Validate(), models.ErrInvalidPrintJob)
+		assert.ErrorIs(t, (&models.CreatePrintJobsRequest{PaperWidth: 40}).Validate(), models.ErrInvalidPrintJob)
+	})
+}
@@ -0,0 +1,37 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefundApproverRole(t *testing.T) {
+	// A threshold of 0 leaves every refund to managers
+	assert.Equal(t, models.StaffRoleManager, models.RefundApproverRole(5000000, 0))
+
+	assert.Equal(t, models.StaffRoleManager, models.RefundApproverRole(100000, 250000))
+	assert.Equal(t, models.StaffRoleManager, models.RefundApproverRole(250000, 250000))
+	assert.Equal(t, models.StaffRoleOwner, models.RefundApproverRole(250001, 250000))
+}
+
+func TestCanApproveRefund(t *testing.T) {
+	assert.True(t, models.CanApproveRefund(models.StaffRoleOwner, models.StaffRoleOwner))
+	assert.True(t, models.CanApproveRefund(models.StaffRoleOwner, models.StaffRoleManager))
+	assert.True(t, models.CanApproveRefund(models.StaffRoleManager, models.StaffRoleManager))
+	assert.False(t, models.CanApproveRefund(models.StaffRoleManager, models.StaffRoleOwner))
+	assert.False(t, models.CanApproveRefund(models.StaffRoleCashier, models.StaffRoleManager))
+	assert.False(t, models.CanApproveRefund("", models.StaffRoleManager))
+}
+
+func TestValidateRefundApproval(t *testing.T) {
+	threshold := 0
+	req := &models.UpdateOrderSettingsRequest{RefundApprovalThreshold: &threshold}
+	assert.NoError(t, req.ValidateRefundApproval())
+
+	threshold = -1
+	assert.ErrorIs(t, req.ValidateRefundApproval(), models.ErrInvalidRefundThreshold)
+
+	assert.NoError(t, (&models.UpdateOrderSettingsRequest{}).ValidateRefundApproval())
+}
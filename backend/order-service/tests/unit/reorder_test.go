@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanReorder(t *testing.T) {
+	items := []models.OrderItem{
+		{ProductID: "p1", ProductName: "Latte", Quantity: 2, UnitPrice: 30000, TotalPrice: 60000},
+		{ProductID: "p2", ProductName: "Croissant", Quantity: 3, UnitPrice: 25000, TotalPrice: 75000},
+		{ProductID: "p3", ProductName: "Bagel", Quantity: 1, UnitPrice: 20000, TotalPrice: 20000},
+		{ProductID: "p4", ProductName: "Muffin", Quantity: 1, UnitPrice: 18000, TotalPrice: 18000},
+		{ProductID: "p5", ProductName: "Tea", Quantity: 1, UnitPrice: 15000, TotalPrice: 15000},
+		{ProductID: "p1", ProductName: "Latte", Quantity: 1, UnitPrice: 30000, TotalPrice: 30000},
+	}
+	products := map[string]models.SellableProduct{
+		"p1": {ProductID: "p1", Name: "Latte", UnitPrice: 30000, Available: 10},
+		"p2": {ProductID: "p2", Name: "Croissant", UnitPrice: 25000, Available: 2},
+		"p4": {ProductID: "p4", Name: "Muffin", UnitPrice: 18000, Available: 0},
+		"p5": {ProductID: "p5", Name: "Iced Tea", UnitPrice: 17000, Available: 5},
+	}
+
+	cartItems, substitutions := models.PlanReorder(items, products)
+
+	require.Len(t, cartItems, 3)
+	assert.Equal(t, models.CartItem{ProductID: "p1", ProductName: "Latte", Quantity: 3, UnitPrice: 30000, TotalPrice: 90000}, cartItems[0])
+	assert.Equal(t, 2, cartItems[1].Quantity)
+	assert.Equal(t, 50000, cartItems[1].TotalPrice)
+	assert.Equal(t, "Iced Tea", cartItems[2].ProductName)
+	assert.Equal(t, 17000, cartItems[2].UnitPrice)
+
+	require.Len(t, substitutions, 4)
+	assert.Equal(t, models.ReorderReasonQuantityReduced, substitutions[0].Reason)
+	assert.Equal(t, 3, substitutions[0].OrderedQuantity)
+	assert.Equal(t, 2, substitutions[0].Quantity)
+	assert.Equal(t, models.ReorderReasonUnavailable, substitutions[1].Reason)
+	assert.Equal(t, "Bagel", substitutions[1].ProductName)
+	assert.Equal(t, models.ReorderReasonOutOfStock, substitutions[2].Reason)
+	assert.Equal(t, 0, substitutions[2].Quantity)
+	assert.Equal(t, models.ReorderReasonPriceChanged, substitutions[3].Reason)
+	assert.Equal(t, 15000, substitutions[3].OrderedUnitPrice)
+	assert.Equal(t, 17000, substitutions[3].UnitPrice)
+}
+
+func TestPlanReorderEmptyOrder(t *testing.T) {
+	cartItems, substitutions := models.PlanReorder(nil, map[string]models.SellableProduct{})
+	assert.Empty(t, cartItems)
+	assert.Empty(t, substitutions)
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifySettlement(t *testing.T) {
+	tests := []struct {
+		name            string
+		recordedSettled bool
+		recordedAmount  int
+		outcome         models.PaymentOutcome
+		gatewayAmount   int
+		expected        models.SettlementMismatchKind
+	}{
+		{"both settled", true, 50000, models.PaymentOutcomeSuccess, 50000, ""},
+		{"gateway amount unknown", true, 50000, models.PaymentOutcomeSuccess, 0, ""},
+		{"both unsettled", false, 50000, models.PaymentOutcomeFailed, 50000, ""},
+		{"still pending", false, 50000, models.PaymentOutcomePending, 0, ""},
+		{"settled only here", true, 50000, models.PaymentOutcomeFailed, 50000, models.SettlementNotSettledAtGateway},
+		{"settled only at gateway", false, 50000, models.PaymentOutcomeSuccess, 50000, models.SettlementUnrecorded},
+		{"different amounts", true, 50000, models.PaymentOutcomeSuccess, 45000, models.SettlementAmountMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, models.ClassifySettlement(tt.recordedSettled, tt.recordedAmount, tt.outcome, tt.gatewayAmount))
+		})
+	}
+}
+
+func TestSettlementReportTotals(t *testing.T) {
+	report := models.NewSettlementReport("tenant-1", "2026-01-15", "Asia/Jakarta")
+	report.AddSettled(models.PaymentGatewayMidtrans, "qris", 30000)
+	report.AddSettled(models.PaymentGatewayMidtrans, "bank_transfer", 120000)
+	report.AddSettled(models.PaymentGatewayMidtrans, "qris", 20000)
+	report.AddMismatch(models.SettlementMismatch{Charge: "ORD-1", Kind: models.SettlementUnrecorded})
+
+	assert.Equal(t, 3, report.TransactionCount)
+	assert.Equal(t, int64(170000), report.TotalAmount)
+	assert.Equal(t, 1, report.MismatchCount)
+
+	require.Len(t, report.Lines, 2)
+	assert.Equal(t, "bank_transfer", report.Lines[0].PaymentType)
+	assert.Equal(t, 1, report.Lines[0].Count)
+	assert.Equal(t, "qris", report.Lines[1].PaymentType)
+	assert.Equal(t, 2, report.Lines[1].Count)
+	assert.Equal(t, int64(50000), report.Lines[1].Amount)
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitPaymentMethod_IsValid(t *testing.T) {
+	assert.True(t, models.SplitPaymentCash.IsValid())
+	assert.True(t, models.SplitPaymentCard.IsValid())
+	assert.True(t, models.SplitPaymentQRIS.IsValid())
+	assert.False(t, models.SplitPaymentMethod("gopay").IsValid())
+}
+
+func TestSplitPaymentMidtransOrderID(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		id := models.SplitPaymentMidtransOrderID("GO-ABC123", 2)
+		assert.Equal(t, "GO-ABC123_S2", id)
+
+		reference, ok := models.ParseSplitPaymentOrderID(id)
+		assert.True(t, ok)
+		assert.Equal(t, "GO-ABC123", reference)
+	})
+
+	t.Run("Full-amount charge is not a split part", func(t *testing.T) {
+		_, ok := models.ParseSplitPaymentOrderID("GO-ABC123")
+		assert.False(t, ok)
+	})
+
+	t.Run("Reference containing S is not mistaken for a part", func(t *testing.T) {
+		_, ok := models.ParseSplitPaymentOrderID("GO-S12345")
+		assert.False(t, ok)
+	})
+
+	t.Run("Non-numeric part is rejected", func(t *testing.T) {
+		_, ok := models.ParseSplitPaymentOrderID("GO-ABC123_Sx")
+		assert.False(t, ok)
+	})
+}
+
+func TestPaymentBalance(t *testing.T) {
+	t.Run("Part cash part QRIS", func(t *testing.T) {
+		balance := models.PaymentBalance{TotalAmount: 100000, AmountPaid: 40000, AmountPending: 0}
+		assert.Equal(t, 60000, balance.Outstanding())
+		assert.False(t, balance.IsCovered())
+		assert.NoError(t, balance.ValidateSplitAmount(60000))
+
+		balance.AmountPending = 60000
+		assert.Equal(t, 0, balance.Outstanding())
+		assert.False(t, balance.IsCovered(), "pending QRIS does not mark the order paid")
+
+		balance.AmountPaid, balance.AmountPending = 100000, 0
+		assert.True(t, balance.IsCovered())
+	})
+
+	t.Run("Overpaying a part is rejected", func(t *testing.T) {
+		balance := models.PaymentBalance{TotalAmount: 100000, AmountPaid: 40000, AmountPending: 20000}
+		assert.ErrorIs(t, balance.ValidateSplitAmount(40001), models.ErrSplitAmountExceedsBalance)
+		assert.NoError(t, balance.ValidateSplitAmount(40000))
+	})
+
+	t.Run("Zero amount is rejected", func(t *testing.T) {
+		balance := models.PaymentBalance{TotalAmount: 100000}
+		assert.ErrorIs(t, balance.ValidateSplitAmount(0), models.ErrInvalidPaymentAmount)
+	})
+}
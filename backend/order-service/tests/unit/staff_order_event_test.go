@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/point-of-sale-system/order-service/src/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStaffOrderEvent(t *testing.T) {
+	table := "12"
+	order := &models.GuestOrder{
+		ID:             "order-1",
+		TenantID:       "tenant-1",
+		OrderReference: "ORD-123",
+		Status:         models.OrderStatusPaid,
+		DeliveryType:   models.DeliveryTypeDineIn,
+		TableNumber:    &table,
+		TotalAmount:    55000,
+	}
+
+	event := models.NewStaffOrderEvent(models.StaffOrderEventPaid, order)
+
+	assert.Equal(t, models.StaffOrderEventPaid, event.Type)
+	assert.Equal(t, "tenant-1", event.TenantID)
+	assert.Equal(t, "ORD-123", event.OrderReference)
+	assert.Equal(t, models.OrderStatusPaid, event.Status)
+	assert.Equal(t, &table, event.TableNumber)
+	assert.Equal(t, 55000, event.TotalAmount)
+	assert.False(t, event.OccurredAt.IsZero())
+}
+
+func TestStaffOrderHubSubscribe(t *testing.T) {
+	t.Run("Unsubscribing closes the connection's channel once", func(t *testing.T) {
+		hub := services.NewStaffOrderHub(nil)
+		events, unsubscribe := hub.Subscribe("tenant-1")
+
+		unsubscribe()
+		unsubscribe()
+
+		_, open := <-events
+		assert.False(t, open)
+	})
+
+	t.Run("Publishing without Redis is a no-op", func(t *testing.T) {
+		var nilHub *services.StaffOrderHub
+		assert.NotPanics(t, func() {
+			nilHub.Publish(context.Background(), &models.StaffOrderEvent{TenantID: "tenant-1"})
+			services.NewStaffOrderHub(nil).Publish(context.Background(), &models.StaffOrderEvent{TenantID: "tenant-1"})
+		})
+	})
+}
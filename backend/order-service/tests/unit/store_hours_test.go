@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeHoursSettings opens Mondays and Tuesdays 10:00-14:00 Jakarta time, taking last orders at 13:30
+func storeHoursSettings() *models.OrderSettings {
+	settings := scheduleSettings()
+	settings.BusinessHours["tuesday"] = models.DayHours{Open: "10:00", Close: "14:00"}
+	settings.OrderingCutoffMinutes = 30
+	settings.SchedulingMaxDaysAhead = 7
+	return settings
+}
+
+func TestOpenForOrders(t *testing.T) {
+	t.Run("Open between opening time and the cutoff", func(t *testing.T) {
+		settings := storeHoursSettings()
+		assert.False(t, settings.OpenForOrders(jakartaTime(t, 12, 9, 59)))
+		assert.True(t, settings.OpenForOrders(jakartaTime(t, 12, 10, 0)))
+		assert.True(t, settings.OpenForOrders(jakartaTime(t, 12, 13, 29).UTC()))
+		assert.False(t, settings.OpenForOrders(jakartaTime(t, 12, 13, 30)))
+		assert.False(t, settings.OpenForOrders(jakartaTime(t, 14, 11, 0)))
+	})
+
+	t.Run("Stores without business hours never close", func(t *testing.T) {
+		settings := storeHoursSettings()
+		settings.BusinessHours = models.BusinessHours{}
+		assert.True(t, settings.OpenForOrders(jakartaTime(t, 12, 3, 0)))
+	})
+
+	t.Run("Holidays close the store or set special hours", func(t *testing.T) {
+		settings := storeHoursSettings()
+		settings.Holidays = models.Holidays{
+			{Date: "2026-10-12", Name: "Store anniversary"},
+			{Date: "2026-10-13", Open: "12:00", Close: "16:00"},
+		}
+		assert.False(t, settings.OpenForOrders(jakartaTime(t, 12, 11, 0)))
+		assert.False(t, settings.OpenForOrders(jakartaTime(t, 13, 11, 0)))
+		assert.True(t, settings.OpenForOrders(jakartaTime(t, 13, 15, 0)))
+	})
+}
+
+func TestNextOpening(t *testing.T) {
+	t.Run("Later the same day before opening", func(t *testing.T) {
+		next := storeHoursSettings().NextOpening(jakartaTime(t, 12, 8, 0))
+		require.NotNil(t, next)
+		assert.True(t, next.Equal(jakartaTime(t, 12, 10, 0)))
+	})
+
+	t.Run("Next open day after the cutoff", func(t *testing.T) {
+		next := storeHoursSettings().NextOpening(jakartaTime(t, 12, 13, 45))
+		require.NotNil(t, next)
+		assert.True(t, next.Equal(jakartaTime(t, 13, 10, 0)))
+	})
+
+	t.Run("Skips closed holidays", func(t *testing.T) {
+		settings := storeHoursSettings()
+		settings.Holidays = models.Holidays{{Date: "2026-10-13", Name: "Closed for renovation"}}
+		next := settings.NextOpening(jakartaTime(t, 12, 15, 0))
+		require.NotNil(t, next)
+		assert.True(t, next.Equal(jakartaTime(t, 19, 10, 0)))
+	})
+
+	t.Run("Nil when the store never opens", func(t *testing.T) {
+		settings := storeHoursSettings()
+		settings.BusinessHours = models.BusinessHours{"monday": {Open: "10:00", Close: "10:20"}}
+		assert.Nil(t, settings.NextOpening(jakartaTime(t, 12, 8, 0)))
+	})
+}
+
+func TestHolidaySlots(t *testing.T) {
+	settings := storeHoursSettings()
+	settings.Holidays = models.Holidays{{Date: "2026-10-12", Open: "12:00", Close: "13:00"}}
+
+	slots := settings.Slots(jakartaTime(t, 12, 9, 0))
+	require.NotEmpty(t, slots)
+	assert.True(t, slots[0].Start.Equal(jakartaTime(t, 12, 12, 0)))
+	assert.True(t, slots[2].Start.Equal(jakartaTime(t, 13, 10, 0)))
+	assert.NoError(t, settings.ValidateScheduledSlot(jakartaTime(t, 12, 12, 30), jakartaTime(t, 12, 9, 0)))
+	assert.ErrorIs(t, settings.ValidateScheduledSlot(jakartaTime(t, 12, 10, 0), jakartaTime(t, 12, 9, 0)), models.ErrScheduledSlotUnavailable)
+}
+
+func TestFirstOpenSlot(t *testing.T) {
+	settings := storeHoursSettings()
+	slots := settings.Slots(jakartaTime(t, 12, 9, 0))
+	settings.FillRemaining(slots, map[time.Time]int{
+		jakartaTime(t, 12, 10, 0).UTC():  2,
+		jakartaTime(t, 12, 10, 30).UTC(): 2,
+	})
+
+	slot := models.FirstOpenSlot(slots)
+	require.NotNil(t, slot)
+	assert.True(t, slot.Start.Equal(jakartaTime(t, 12, 11, 0)))
+	assert.Nil(t, models.FirstOpenSlot(slots[:2]))
+}
+
+func TestValidateStoreHours(t *testing.T) {
+	policy := models.ClosedOrderPolicy("queue")
+	cutoff := 300
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{ClosedOrderPolicy: &policy}).ValidateStoreHours(), models.ErrInvalidClosedOrderPolicy)
+	assert.ErrorIs(t, (&models.UpdateOrderSettingsRequest{OrderingCutoffMinutes: &cutoff}).ValidateStoreHours(), models.ErrInvalidOrderingCutoff)
+
+	valid := models.Holidays{{Date: "2026-12-25", Name: "Christmas"}, {Date: "2026-12-31", Open: "10:00", Close: "15:00"}}
+	assert.NoError(t, valid.Validate())
+	assert.ErrorIs(t, models.Holidays{{Date: "25-12-2026"}}.Validate(), models.ErrInvalidHolidays)
+	assert.ErrorIs(t, models.Holidays{{Date: "2026-12-25"}, {Date: "2026-12-25"}}.Validate(), models.ErrInvalidHolidays)
+	assert.ErrorIs(t, models.Holidays{{Date: "2026-12-31", Open: "10:00"}}.Validate(), models.ErrInvalidHolidays)
+}
@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddBillingMonth(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name string
+		from time.Time
+		want time.Time
+	}{
+		{"Same day next month", time.Date(2026, 10, 17, 9, 30, 0, 0, jakarta), time.Date(2026, 11, 17, 9, 30, 0, 0, jakarta)},
+		{"Clamped to the end of February", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"Leap year February", time.Date(2028, 1, 30, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"Clamped to a 30-day month", time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC), time.Date(2026, 4, 30, 12, 0, 0, 0, time.UTC)},
+		{"Into the next year", time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(models.AddBillingMonth(tt.from)), "got %s", models.AddBillingMonth(tt.from))
+		})
+	}
+}
+
+func TestBillingChargeOrderID(t *testing.T) {
+	invoiceID := "6f9619ff-8b86-d011-b42d-00cf4fc964ff"
+
+	orderID := models.BillingChargeOrderID(invoiceID, 3)
+	assert.Equal(t, "SUB-6f9619ff-8b86-d011-b42d-00cf4fc964ff-3", orderID)
+	assert.LessOrEqual(t, len(orderID), 50, "Midtrans order IDs are at most 50 characters")
+
+	parsed, ok := models.ParseBillingChargeOrderID(orderID)
+	assert.True(t, ok)
+	assert.Equal(t, invoiceID, parsed)
+
+	for _, other := range []string{"ORD-20261017-0001", "SUB-", "SUB-abc", "SUB-abc-0", "SUB-abc-x"} {
+		_, ok := models.ParseBillingChargeOrderID(other)
+		assert.False(t, ok, other)
+	}
+}
+
+func TestPlanLimitErrorResponse(t *testing.T) {
+	err := &models.PlanLimitError{
+		Limit:       models.UsageMonthlyOrders,
+		Max:         500,
+		PlanName:    "Free",
+		UpgradePlan: models.PlanPro,
+	}
+
+	assert.Equal(t, "the Free plan allows 500 orders per month", err.Error())
+
+	body := err.Response()
+	assert.Equal(t, "plan_limit_exceeded", body["code"])
+	assert.Equal(t, models.UsageMonthlyOrders, body["limit"])
+	assert.Equal(t, int64(500), body["max"])
+	assert.Equal(t, "pro", body["upgrade_plan"])
+	assert.Equal(t, "Upgrade to the pro plan to raise this limit", body["upgrade_hint"])
+
+	err.UpgradePlan = ""
+	assert.Equal(t, "Contact support to raise this limit", err.UpgradeHint())
+}
@@ -0,0 +1,38 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantDeletionRequestIncludes(t *testing.T) {
+	req := &models.TenantDeletionRequest{
+		DeletionID: "deletion-1",
+		Services:   []string{"product-service", "order-service"},
+	}
+	assert.True(t, req.Includes(models.TenantDeletionParticipant))
+	assert.False(t, req.Includes("user-service"))
+
+	assert.False(t, (&models.TenantDeletionRequest{}).Includes(models.TenantDeletionParticipant))
+}
+
+func TestNewTenantDeletionAck(t *testing.T) {
+	t.Run("Reports what was deleted", func(t *testing.T) {
+		ack := models.NewTenantDeletionAck("deletion-1", map[string]int64{"orders": 12}, nil)
+		assert.Equal(t, "deletion-1", ack.DeletionID)
+		assert.Equal(t, models.TenantDeletionParticipant, ack.Service)
+		assert.Equal(t, "completed", ack.Status)
+		assert.Equal(t, int64(12), ack.Deleted["orders"])
+		assert.Empty(t, ack.Error)
+	})
+
+	t.Run("Reports the failure", func(t *testing.T) {
+		ack := models.NewTenantDeletionAck("deletion-1", nil, errors.New("connection refused"))
+		assert.Equal(t, "failed", ack.Status)
+		assert.Equal(t, "connection refused", ack.Error)
+		assert.NotNil(t, ack.Deleted)
+	})
+}
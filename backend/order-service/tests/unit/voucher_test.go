@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/point-of-sale-system/order-service/src/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoucherRequestValidate(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	t.Run("Normalizes the code", func(t *testing.T) {
+		req := &models.VoucherRequest{Code: " hemat10 ", DiscountType: models.DiscountTypePercent, DiscountValue: 10}
+		assert.NoError(t, req.Validate())
+		assert.Equal(t, "HEMAT10", req.Code)
+	})
+
+	t.Run("Rejects invalid rules", func(t *testing.T) {
+		from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+		until := from.Add(-time.Hour)
+
+		invalid := []*models.VoucherRequest{
+			{Code: "X", DiscountType: models.DiscountTypeFixed, DiscountValue: 5000},
+			{Code: "HEMAT 10", DiscountType: models.DiscountTypeFixed, DiscountValue: 5000},
+			{Code: "HEMAT10", DiscountType: "bogo", DiscountValue: 1},
+			{Code: "HEMAT10", DiscountType: models.DiscountTypePercent, DiscountValue: 101},
+			{Code: "HEMAT10", DiscountType: models.DiscountTypeFixed, DiscountValue: 0},
+			{Code: "HEMAT10", DiscountType: models.DiscountTypeFixed, DiscountValue: 5000, MaxDiscountAmount: intPtr(1000)},
+			{Code: "HEMAT10", DiscountType: models.DiscountTypeFixed, DiscountValue: 5000, UsageLimit: intPtr(0)},
+			{Code: "HEMAT10", DiscountType: models.DiscountTypeFixed, DiscountValue: 5000, PerCustomerLimit: intPtr(0)},
+			{Code: "HEMAT10", DiscountType: models.DiscountTypeFixed, DiscountValue: 5000, ValidFrom: &from, ValidUntil: &until},
+		}
+		for _, req := range invalid {
+			assert.ErrorIs(t, req.Validate(), models.ErrInvalidVoucher, "code=%s type=%s", req.Code, req.DiscountType)
+		}
+	})
+}
+
+func TestVoucherCheckAvailable(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	from := now.Add(-24 * time.Hour)
+	until := now.Add(24 * time.Hour)
+	limit := 5
+
+	base := func() *models.Voucher {
+		return &models.Voucher{
+			Code:          "HEMAT10",
+			DiscountType:  models.DiscountTypePercent,
+			DiscountValue: 10,
+			MinSubtotal:   50000,
+			UsageLimit:    &limit,
+			UsageCount:    2,
+			ValidFrom:     &from,
+			ValidUntil:    &until,
+			IsActive:      true,
+		}
+	}
+
+	assert.NoError(t, base().CheckAvailable(50000, now))
+
+	inactive := base()
+	inactive.IsActive = false
+	assert.ErrorIs(t, inactive.CheckAvailable(50000, now), models.ErrVoucherInactive)
+
+	assert.ErrorIs(t, base().CheckAvailable(50000, from.Add(-time.Minute)), models.ErrVoucherNotStarted)
+	assert.ErrorIs(t, base().CheckAvailable(50000, until), models.ErrVoucherExpired)
+
+	usedUp := base()
+	usedUp.UsageCount = limit
+	assert.ErrorIs(t, usedUp.CheckAvailable(50000, now), models.ErrVoucherUsageLimitReached)
+
+	assert.ErrorIs(t, base().CheckAvailable(49999, now), models.ErrVoucherMinSubtotal)
+}
+
+func TestVoucherCalculateDiscount(t *testing.T) {
+	maxDiscount := 15000
+
+	t.Run("Percent discount", func(t *testing.T) {
+		voucher := &models.Voucher{DiscountType: models.DiscountTypePercent, DiscountValue: 10}
+		assert.Equal(t, 12345, voucher.CalculateDiscount(123450))
+	})
+
+	t.Run("Percent discount is capped", func(t *testing.T) {
+		voucher := &models.Voucher{DiscountType: models.DiscountTypePercent, DiscountValue: 25, MaxDiscountAmount: &maxDiscount}
+		assert.Equal(t, 15000, voucher.CalculateDiscount(100000))
+		assert.Equal(t, 10000, voucher.CalculateDiscount(40000))
+	})
+
+	t.Run("Fixed discount never exceeds the subtotal", func(t *testing.T) {
+		voucher := &models.Voucher{DiscountType: models.DiscountTypeFixed, DiscountValue: 20000}
+		assert.Equal(t, 20000, voucher.CalculateDiscount(75000))
+		assert.Equal(t, 12000, voucher.CalculateDiscount(12000))
+	})
+}
+
+func TestVoucherCustomerKey(t *testing.T) {
+	tenantID := "tenant-1"
+	key := models.VoucherCustomerKey(tenantID, "081234567890")
+
+	assert.Equal(t, key, models.VoucherCustomerKey(tenantID, "+62 812-3456-7890"))
+	assert.Equal(t, key, models.VoucherCustomerKey(tenantID, "6281234567890"))
+	assert.NotEqual(t, key, models.VoucherCustomerKey("tenant-2", "081234567890"))
+	assert.NotContains(t, key, "81234567890")
+}
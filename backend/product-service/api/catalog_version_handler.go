@@ -0,0 +1,259 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pos/backend/product-service/src/models"
+	"github.com/pos/backend/product-service/src/services"
+	"github.com/pos/backend/product-service/src/utils"
+)
+
+// CatalogVersionHandler handles draft catalog versions and their publishing
+type CatalogVersionHandler struct {
+	versionService *services.CatalogVersionService
+}
+
+// NewCatalogVersionHandler creates a new CatalogVersionHandler
+func NewCatalogVersionHandler(versionService *services.CatalogVersionService) *CatalogVersionHandler {
+	return &CatalogVersionHandler{
+		versionService: versionService,
+	}
+}
+
+// RegisterRoutes registers catalog version routes
+func (h *CatalogVersionHandler) RegisterRoutes(e *echo.Group) {
+	e.GET("/catalog-versions", h.ListVersions)
+	e.POST("/catalog-versions", h.CreateVersion)
+	e.GET("/catalog-versions/:id", h.GetVersion)
+	e.DELETE("/catalog-versions/:id", h.CancelVersion)
+	e.PUT("/catalog-versions/:id/changes", h.StageChange)
+	e.DELETE("/catalog-versions/:id/changes/:product_id", h.RemoveChange)
+	e.POST("/catalog-versions/:id/schedule", h.ScheduleVersion)
+	e.DELETE("/catalog-versions/:id/schedule", h.UnscheduleVersion)
+	e.POST("/catalog-versions/:id/publish", h.PublishVersion)
+	e.POST("/catalog-versions/:id/rollback", h.RollbackVersion)
+}
+
+// ListVersions handles GET /api/v1/catalog-versions
+func (h *CatalogVersionHandler) ListVersions(c echo.Context) error {
+	tenantID, err := utils.GetTenantIDFromContext(c)
+	if err != nil {
+		return utils.RespondError(c, http.StatusUnauthorized, "Invalid tenant ID")
+	}
+
+	versions, err := h.versionService.ListVersions(c.Request().Context(), tenantID)
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"versions": versions,
+	})
+}
+
+// CreateVersion handles POST /api/v1/catalog-versions
+func (h *CatalogVersionHandler) CreateVersion(c echo.Context) error {
+	tenantID, err := utils.GetTenantIDFromContext(c)
+	if err != nil {
+		return utils.RespondError(c, http.StatusUnauthorized, "Invalid tenant ID")
+	}
+
+	var req models.CreateCatalogVersionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.RespondBadRequest(c, "Invalid request body")
+	}
+
+	version, err := h.versionService.CreateVersion(c.Request().Context(), tenantID, catalogUserID(c), &req)
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, version)
+}
+
+// GetVersion handles GET /api/v1/catalog-versions/:id
+func (h *CatalogVersionHandler) GetVersion(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	version, err := h.versionService.GetVersion(c.Request().Context(), tenantID, id)
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, version)
+}
+
+// CancelVersion handles DELETE /api/v1/catalog-versions/:id
+func (h *CatalogVersionHandler) CancelVersion(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.versionService.Cancel(c.Request().Context(), tenantID, id); err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// StageChange handles PUT /api/v1/catalog-versions/:id/changes
+func (h *CatalogVersionHandler) StageChange(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.CatalogChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.RespondBadRequest(c, "Invalid request body")
+	}
+
+	change, err := h.versionService.StageChange(c.Request().Context(), tenantID, id, &req)
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, change)
+}
+
+// RemoveChange handles DELETE /api/v1/catalog-versions/:id/changes/:product_id
+func (h *CatalogVersionHandler) RemoveChange(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	productID, err := uuid.Parse(c.Param("product_id"))
+	if err != nil {
+		return utils.RespondBadRequest(c, "Invalid product ID")
+	}
+
+	if err := h.versionService.RemoveChange(c.Request().Context(), tenantID, id, productID); err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ScheduleVersion handles POST /api/v1/catalog-versions/:id/schedule
+func (h *CatalogVersionHandler) ScheduleVersion(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.ScheduleCatalogVersionRequest
+	if err := c.Bind(&req); err != nil {
+		return utils.RespondBadRequest(c, "Invalid request body", "publish_at must be an RFC 3339 timestamp")
+	}
+
+	version, err := h.versionService.Schedule(c.Request().Context(), tenantID, id, &req)
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, version)
+}
+
+// UnscheduleVersion handles DELETE /api/v1/catalog-versions/:id/schedule
+func (h *CatalogVersionHandler) UnscheduleVersion(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	version, err := h.versionService.Unschedule(c.Request().Context(), tenantID, id)
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, version)
+}
+
+// PublishVersion handles POST /api/v1/catalog-versions/:id/publish
+func (h *CatalogVersionHandler) PublishVersion(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	version, err := h.versionService.Publish(c.Request().Context(), tenantID, id, catalogUserID(c))
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, version)
+}
+
+// RollbackVersion handles POST /api/v1/catalog-versions/:id/rollback
+func (h *CatalogVersionHandler) RollbackVersion(c echo.Context) error {
+	tenantID, id, err := catalogVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	version, err := h.versionService.Rollback(c.Request().Context(), tenantID, id, catalogUserID(c))
+	if err != nil {
+		return handleCatalogVersionError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, version)
+}
+
+// catalogVersionParams reads the tenant and version ID; the returned error is the HTTP error to send
+func catalogVersionParams(c echo.Context) (uuid.UUID, uuid.UUID, error) {
+	tenantID, err := utils.GetTenantIDFromContext(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, echo.NewHTTPError(http.StatusUnauthorized, utils.NewErrorResponse(http.StatusUnauthorized, "Invalid tenant ID"))
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, utils.NewErrorResponse(http.StatusBadRequest, "Invalid catalog version ID"))
+	}
+
+	return tenantID, id, nil
+}
+
+// catalogUserID returns the acting user set by the tenant middleware, if any
+func catalogUserID(c echo.Context) *uuid.UUID {
+	userID, ok := c.Get("user_id").(string)
+	if !ok {
+		return nil
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// handleCatalogVersionError converts service errors to appropriate HTTP responses
+func handleCatalogVersionError(c echo.Context, err error) error {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return utils.RespondBadRequest(c, validationErr.Error(), "Field: "+validationErr.Field)
+	case errors.Is(err, models.ErrCatalogVersionNotFound):
+		return utils.RespondNotFound(c, err.Error())
+	case errors.Is(err, models.ErrCatalogVersionNotEditable),
+		errors.Is(err, models.ErrCatalogVersionNotLatest),
+		errors.Is(err, models.ErrCatalogVersionNotPublished):
+		return utils.RespondConflict(c, err.Error())
+	case errors.Is(err, models.ErrCatalogVersionEmpty),
+		errors.Is(err, models.ErrCatalogProductNotFound),
+		errors.Is(err, models.ErrCatalogCategoryNotFound),
+		errors.Is(err, models.ErrCatalogPhotoNotFound):
+		return utils.RespondError(c, http.StatusUnprocessableEntity, err.Error())
+	default:
+		utils.Log.Error("Catalog version request failed: %v", err)
+		return utils.RespondInternalError(c, "An internal error occurred")
+	}
+}
@@ -419,6 +419,10 @@ func handlePhotoError(c echo.Context, err error) error {
 	case models.ErrUnauthorizedAccess:
 		return utils.RespondError(c, http.StatusForbidden, err.Error())
 	default:
+		if limitErr, ok := err.(*models.PlanLimitError); ok {
+			return respondPlanLimit(c, limitErr)
+		}
+
 		// Check for validation errors
 		if validationErr, ok := err.(*models.ValidationError); ok {
 			return utils.RespondBadRequest(c, validationErr.Error(), "Field: "+validationErr.Field)
@@ -76,6 +76,9 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 	}
 
 	if err := h.service.CreateProduct(c.Request().Context(), product); err != nil {
+		if limitErr, ok := err.(*models.PlanLimitError); ok {
+			return respondPlanLimit(c, limitErr)
+		}
 		if err.Error() == "SKU already exists" {
 			return utils.RespondConflict(c, "SKU already exists", "A product with this SKU already exists in your catalog")
 		}
@@ -367,6 +370,9 @@ func (h *ProductHandler) RestoreProduct(c echo.Context) error {
 	}
 
 	if err := h.service.RestoreProduct(c.Request().Context(), tenantUUID, id); err != nil {
+		if limitErr, ok := err.(*models.PlanLimitError); ok {
+			return respondPlanLimit(c, limitErr)
+		}
 		utils.Log.Error("Failed to restore product: %v", err)
 		return utils.RespondInternalError(c, "Failed to restore product")
 	}
@@ -468,3 +474,15 @@ func (h *ProductHandler) DeletePhoto(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// respondPlanLimit answers an action refused by the tenant's plan, pointing the owner at the plan to upgrade to
+func respondPlanLimit(c echo.Context, err *models.PlanLimitError) error {
+	return c.JSON(http.StatusForbidden, map[string]interface{}{
+		"error":        err.Error(),
+		"code":         "plan_limit_exceeded",
+		"limit":        err.Limit,
+		"max":          err.Max,
+		"upgrade_plan": err.UpgradePlan,
+		"upgrade_hint": err.UpgradeHint(),
+	})
+}
@@ -54,12 +54,14 @@ func (h *PublicCatalogHandler) GetPublicMenu(c echo.Context) error {
 				for _, photo := range photos {
 					if photo.IsPrimary {
 						products[i].ImageURL = &photo.PhotoURL
+						products[i].ImageFormats = photo.Formats
 						break
 					}
 				}
 				// If no primary, use first photo
 				if products[i].ImageURL == nil && len(photos) > 0 {
 					products[i].ImageURL = &photos[0].PhotoURL
+					products[i].ImageFormats = photos[0].Formats
 				}
 			}
 		}
@@ -84,6 +86,16 @@ func (h *PublicCatalogHandler) GetPublicPhoto(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tenant ID")
 	}
 
+	// Photos in object storage are served in the best format the client accepts
+	if h.photoService != nil {
+		photoURL, err := h.photoService.ResolvePublicPhotoURL(c.Request().Context(), id, tenantID, c.Request().Header.Get(echo.HeaderAccept))
+		if err == nil && photoURL != "" {
+			c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+			return c.Redirect(http.StatusFound, photoURL)
+		}
+	}
+
+	// Fall back to the legacy single photo stored on disk
 	photoPath, err := h.productService.GetPhotoPath(c.Request().Context(), id, tenantID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "Photo not found")
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -13,6 +14,7 @@ import (
 	"github.com/pos/backend/product-service/src/config"
 	customMiddleware "github.com/pos/backend/product-service/src/middleware"
 	"github.com/pos/backend/product-service/src/observability"
+	"github.com/pos/backend/product-service/src/queue"
 	"github.com/pos/backend/product-service/src/repository"
 	"github.com/pos/backend/product-service/src/services"
 	"github.com/pos/backend/product-service/src/utils"
@@ -52,6 +54,14 @@ func main() {
 	}
 	utils.Log.Info("Storage bucket '%s' initialized successfully", storageConfig.BucketName)
 
+	// Initialize AuditPublisher for audit trail
+	kafkaBrokers := strings.Split(utils.GetEnv("KAFKA_BROKERS"), ",")
+	auditPublisher, err := utils.NewAuditPublisher(utils.GetEnv("SERVICE_NAME"), kafkaBrokers, utils.GetEnv("KAFKA_AUDIT_TOPIC"))
+	if err != nil {
+		log.Fatal("Failed to initialize AuditPublisher:", err)
+	}
+	defer auditPublisher.Close()
+
 	e := echo.New()
 
 	e.Use(emw.Logger())
@@ -83,6 +93,7 @@ func main() {
 	categoryRepo := repository.NewCategoryRepository(config.DB)
 	stockRepo := repository.NewStockRepository(config.DB)
 	photoRepo := repository.NewPhotoRepository(config.DB)
+	catalogVersionRepo := repository.NewCatalogVersionRepository(config.DB)
 
 	// Initialize photo service and dependencies (needed for product handler)
 	imageProcessor := services.NewImageProcessor(
@@ -91,6 +102,22 @@ func main() {
 		4096, // max height
 	)
 
+	// Photo renditions for format negotiation: AVIF and WebP need the external
+	// encoders (libavif-apps, libwebp-tools); missing ones are skipped
+	renditionEncoders := []services.ImageEncoder{}
+	if encoder, err := services.NewAVIFEncoder(utils.GetEnv("IMAGE_AVIF_ENCODER")); err != nil {
+		utils.Log.Warn("AVIF photo renditions disabled: %v", err)
+	} else {
+		renditionEncoders = append(renditionEncoders, encoder)
+	}
+	if encoder, err := services.NewWebPEncoder(utils.GetEnv("IMAGE_WEBP_ENCODER")); err != nil {
+		utils.Log.Warn("WebP photo renditions disabled: %v", err)
+	} else {
+		renditionEncoders = append(renditionEncoders, encoder)
+	}
+	renditionEncoders = append(renditionEncoders, services.NewJPEGEncoder())
+	imageProcessor.WithEncoders(renditionEncoders...)
+
 	// Initialize retry queue for background S3 deletion retries (Feature 005 - T074)
 	retryQueue := services.NewRetryQueue(storageService, 30*time.Second) // Check every 30 seconds
 	retryQueue.Start(ctx)
@@ -113,10 +140,21 @@ func main() {
 	categoryHandler := api.NewCategoryHandler(categoryService)
 	categoryHandler.RegisterRoutes(apiGroup)
 
-	inventoryService := services.NewInventoryService(productRepo, stockRepo, config.DB)
+	inventoryService := services.NewInventoryService(productRepo, stockRepo, config.DB, auditPublisher)
 	stockHandler := api.NewStockHandler(productService, inventoryService)
 	stockHandler.RegisterRoutes(apiGroup)
 
+	// Catalog versions: staged product changes published together, now or on a schedule
+	catalogVersionService := services.NewCatalogVersionService(catalogVersionRepo, config.DB, auditPublisher)
+	catalogVersionHandler := api.NewCatalogVersionHandler(catalogVersionService)
+	catalogVersionHandler.RegisterRoutes(apiGroup)
+
+	catalogPublishJob := services.NewCatalogPublishJob(
+		catalogVersionService,
+		time.Duration(utils.GetEnvInt("CATALOG_PUBLISH_INTERVAL_SECONDS"))*time.Second,
+	)
+	catalogPublishJob.Start(ctx)
+
 	// Photo management endpoints (Feature 005)
 	photoHandler := api.NewPhotoHandler(photoService)
 
@@ -136,6 +174,14 @@ func main() {
 	e.GET("/public/menu/:tenant_id/products", publicCatalogHandler.GetPublicMenu)
 	e.GET("/public/products/:tenant_id/:id/photo", publicCatalogHandler.GetPublicPhoto)
 
+	// Tenant deletion saga: delete the tenant's photos and stock history when tenant-service asks
+	tenantEvents := queue.NewKafkaProducer(kafkaBrokers, utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC"))
+	defer tenantEvents.Close()
+	tenantEventHandler := services.NewTenantEventHandler(photoService, stockRepo, tenantEvents)
+	tenantEventsConsumer := queue.NewKafkaConsumer(kafkaBrokers, utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC"), utils.GetEnv("SERVICE_NAME"), tenantEventHandler.Handle)
+	consumerCtx, cancelConsumers := context.WithCancel(ctx)
+	go tenantEventsConsumer.Start(consumerCtx)
+
 	port := utils.GetEnv("PORT")
 	utils.Log.Info("Product service starting on port %s", port)
 
@@ -157,6 +203,9 @@ func main() {
 	retryQueue.Stop()
 	utils.Log.Info("Retry queue stopped")
 
+	catalogPublishJob.Stop()
+	cancelConsumers()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
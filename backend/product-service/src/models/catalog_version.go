@@ -0,0 +1,206 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogVersionStatus is the lifecycle state of a catalog version
+type CatalogVersionStatus string
+
+const (
+	CatalogVersionDraft      CatalogVersionStatus = "draft"
+	CatalogVersionScheduled  CatalogVersionStatus = "scheduled"
+	CatalogVersionPublished  CatalogVersionStatus = "published"
+	CatalogVersionRolledBack CatalogVersionStatus = "rolled_back"
+	CatalogVersionCancelled  CatalogVersionStatus = "cancelled"
+	CatalogVersionFailed     CatalogVersionStatus = "failed"
+)
+
+// Custom errors for catalog versions
+var (
+	ErrCatalogVersionNotFound     = errors.New("catalog version not found")
+	ErrCatalogVersionNotEditable  = errors.New("catalog version has already been published or cancelled")
+	ErrCatalogVersionEmpty        = errors.New("catalog version has no changes")
+	ErrCatalogVersionNotLatest    = errors.New("only the latest published catalog version can be rolled back")
+	ErrCatalogVersionNotPublished = errors.New("catalog version is not published")
+	ErrCatalogProductNotFound     = errors.New("product in catalog version not found")
+	ErrCatalogCategoryNotFound    = errors.New("category in catalog version not found")
+	ErrCatalogPhotoNotFound       = errors.New("photo in catalog version not found")
+
+	ErrInvalidCatalogVersionName = &ValidationError{Field: "name", Message: "name is required and must be at most 255 characters"}
+	ErrInvalidCatalogPublishAt   = &ValidationError{Field: "publish_at", Message: "publish_at must be in the future"}
+	ErrEmptyCatalogChange        = &ValidationError{Field: "changes", Message: "change must set at least one field"}
+	ErrInvalidCatalogChangeName  = &ValidationError{Field: "name", Message: "name must be between 1 and 255 characters"}
+	ErrInvalidCatalogChangePrice = &ValidationError{Field: "selling_price", Message: "selling price must be non-negative"}
+)
+
+// CatalogVersion is a named set of product changes that go live together
+type CatalogVersion struct {
+	ID            uuid.UUID            `json:"id" db:"id"`
+	TenantID      uuid.UUID            `json:"tenant_id" db:"tenant_id"`
+	Name          string               `json:"name" db:"name"`
+	Notes         *string              `json:"notes,omitempty" db:"notes"`
+	Status        CatalogVersionStatus `json:"status" db:"status"`
+	PublishAt     *time.Time           `json:"publish_at,omitempty" db:"publish_at"`
+	PublishedAt   *time.Time           `json:"published_at,omitempty" db:"published_at"`
+	RolledBackAt  *time.Time           `json:"rolled_back_at,omitempty" db:"rolled_back_at"`
+	FailureReason *string              `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedBy     *uuid.UUID           `json:"created_by,omitempty" db:"created_by"`
+	PublishedBy   *uuid.UUID           `json:"published_by,omitempty" db:"published_by"`
+	ChangeCount   int                  `json:"change_count" db:"change_count"`
+	Changes       []CatalogChange      `json:"changes,omitempty" db:"-"`
+	CreatedAt     time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// IsEditable reports whether the version's changes and schedule can still be changed
+func (v *CatalogVersion) IsEditable() bool {
+	switch v.Status {
+	case CatalogVersionDraft, CatalogVersionScheduled, CatalogVersionFailed:
+		return true
+	}
+	return false
+}
+
+// CatalogChange is the staged change to one product; nil fields leave the product value unchanged
+type CatalogChange struct {
+	ID             uuid.UUID            `json:"id" db:"id"`
+	VersionID      uuid.UUID            `json:"version_id" db:"version_id"`
+	ProductID      uuid.UUID            `json:"product_id" db:"product_id"`
+	Name           *string              `json:"name,omitempty" db:"name"`
+	Description    *string              `json:"description,omitempty" db:"description"`
+	CategoryID     *uuid.UUID           `json:"category_id,omitempty" db:"category_id"`
+	SellingPrice   *float64             `json:"selling_price,omitempty" db:"selling_price"`
+	PrimaryPhotoID *uuid.UUID           `json:"primary_photo_id,omitempty" db:"primary_photo_id"`
+	Archived       *bool                `json:"archived,omitempty" db:"archived"`
+	PreviousState  *CatalogProductState `json:"previous_state,omitempty" db:"previous_state"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// Apply returns the product state after this change, keeping the values the change leaves alone
+func (c *CatalogChange) Apply(current CatalogProductState, now time.Time) CatalogProductState {
+	next := current
+	if c.Name != nil {
+		next.Name = *c.Name
+	}
+	if c.Description != nil {
+		next.Description = c.Description
+	}
+	if c.CategoryID != nil {
+		next.CategoryID = c.CategoryID
+	}
+	if c.SellingPrice != nil {
+		next.SellingPrice = *c.SellingPrice
+	}
+	if c.PrimaryPhotoID != nil {
+		next.PrimaryPhotoID = c.PrimaryPhotoID
+	}
+	if c.Archived != nil {
+		switch {
+		case !*c.Archived:
+			next.ArchivedAt = nil
+		case current.ArchivedAt == nil:
+			next.ArchivedAt = &now
+		}
+	}
+	return next
+}
+
+// CatalogProductState holds the product values a catalog version can change
+// It is captured at publish time so a rollback can restore the product exactly.
+type CatalogProductState struct {
+	Name           string     `json:"name"`
+	Description    *string    `json:"description,omitempty"`
+	CategoryID     *uuid.UUID `json:"category_id,omitempty"`
+	SellingPrice   float64    `json:"selling_price"`
+	PrimaryPhotoID *uuid.UUID `json:"primary_photo_id,omitempty"`
+	ArchivedAt     *time.Time `json:"archived_at,omitempty"`
+}
+
+// Value implements driver.Valuer so the state is stored as JSONB
+func (s CatalogProductState) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements sql.Scanner for the JSONB column
+func (s *CatalogProductState) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for catalog product state: %T", value)
+	}
+	return json.Unmarshal(data, s)
+}
+
+// CreateCatalogVersionRequest represents the request to start a draft catalog version
+type CreateCatalogVersionRequest struct {
+	Name  string  `json:"name"`
+	Notes *string `json:"notes,omitempty"`
+}
+
+// Validate checks the draft name
+func (r *CreateCatalogVersionRequest) Validate() error {
+	name := strings.TrimSpace(r.Name)
+	if name == "" || len(name) > 255 {
+		return ErrInvalidCatalogVersionName
+	}
+	return nil
+}
+
+// CatalogChangeRequest represents the request to stage a change to one product
+type CatalogChangeRequest struct {
+	ProductID      uuid.UUID  `json:"product_id"`
+	Name           *string    `json:"name,omitempty"`
+	Description    *string    `json:"description,omitempty"`
+	CategoryID     *uuid.UUID `json:"category_id,omitempty"`
+	SellingPrice   *float64   `json:"selling_price,omitempty"`
+	PrimaryPhotoID *uuid.UUID `json:"primary_photo_id,omitempty"`
+	Archived       *bool      `json:"archived,omitempty"`
+}
+
+// Validate checks the staged values
+func (r *CatalogChangeRequest) Validate() error {
+	if r.ProductID == uuid.Nil {
+		return ErrInvalidProductID
+	}
+	if r.Name == nil && r.Description == nil && r.CategoryID == nil &&
+		r.SellingPrice == nil && r.PrimaryPhotoID == nil && r.Archived == nil {
+		return ErrEmptyCatalogChange
+	}
+	if r.Name != nil {
+		name := strings.TrimSpace(*r.Name)
+		if name == "" || len(name) > 255 {
+			return ErrInvalidCatalogChangeName
+		}
+		r.Name = &name
+	}
+	if r.SellingPrice != nil && *r.SellingPrice < 0 {
+		return ErrInvalidCatalogChangePrice
+	}
+	return nil
+}
+
+// ScheduleCatalogVersionRequest represents the request to publish a version at a later time
+type ScheduleCatalogVersionRequest struct {
+	PublishAt time.Time `json:"publish_at"`
+}
+
+// Validate checks the publish time is in the future
+func (r *ScheduleCatalogVersionRequest) Validate(now time.Time) error {
+	if !r.PublishAt.After(now) {
+		return ErrInvalidCatalogPublishAt
+	}
+	return nil
+}
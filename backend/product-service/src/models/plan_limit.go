@@ -0,0 +1,34 @@
+package models
+
+import "fmt"
+
+// Plan limits enforced by the product service
+const (
+	PlanLimitProducts     = "products"
+	PlanLimitStorageBytes = "storage_bytes"
+)
+
+var planLimitLabels = map[string]string{
+	PlanLimitProducts:     "products",
+	PlanLimitStorageBytes: "bytes of photo storage",
+}
+
+// PlanLimitError is returned when an action would take a tenant over a limit of their subscription plan
+type PlanLimitError struct {
+	Limit       string
+	Max         int64
+	PlanName    string
+	UpgradePlan string // Cheapest plan with room for more; empty when there is none
+}
+
+func (e *PlanLimitError) Error() string {
+	return fmt.Sprintf("the %s plan allows %d %s", e.PlanName, e.Max, planLimitLabels[e.Limit])
+}
+
+// UpgradeHint tells the owner how to lift the limit
+func (e *PlanLimitError) UpgradeHint() string {
+	if e.UpgradePlan == "" {
+		return "Contact support to raise this limit"
+	}
+	return fmt.Sprintf("Upgrade to the %s plan to raise this limit", e.UpgradePlan)
+}
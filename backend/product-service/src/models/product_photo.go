@@ -1,6 +1,11 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,12 +32,16 @@ type ProductPhoto struct {
 	DisplayOrder int  `json:"display_order" db:"display_order"` // Order in carousel (0-based, unique per product)
 	IsPrimary    bool `json:"is_primary" db:"is_primary"`       // Primary photo shown in listings (only one per product)
 
+	// Renditions generated from the original (format -> storage key)
+	Renditions PhotoRenditions `json:"-" db:"renditions"`
+
 	// Audit
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 
 	// Runtime field (not stored in database)
-	PhotoURL string `json:"photo_url,omitempty" db:"-"` // Presigned URL for photo access
+	PhotoURL string            `json:"photo_url,omitempty" db:"-"` // Presigned URL for photo access
+	Formats  map[string]string `json:"formats,omitempty" db:"-"`   // Presigned URL per rendition format
 }
 
 // Validate performs validation on ProductPhoto fields
@@ -81,6 +90,111 @@ func (p *ProductPhoto) Validate() error {
 	return nil
 }
 
+// Rendition formats generated for every uploaded photo
+const (
+	PhotoFormatAVIF = "avif"
+	PhotoFormatWebP = "webp"
+	PhotoFormatJPEG = "jpeg"
+)
+
+// PhotoFormatPreference lists rendition formats from smallest to most compatible.
+// Format negotiation serves the first one the client accepts.
+var PhotoFormatPreference = []string{PhotoFormatAVIF, PhotoFormatWebP, PhotoFormatJPEG}
+
+// PhotoFormatMimeType returns the MIME type of a rendition format
+func PhotoFormatMimeType(format string) string {
+	switch format {
+	case PhotoFormatAVIF:
+		return "image/avif"
+	case PhotoFormatWebP:
+		return "image/webp"
+	case PhotoFormatJPEG:
+		return "image/jpeg"
+	default:
+		return ""
+	}
+}
+
+// PhotoRenditions maps a rendition format to its storage key
+type PhotoRenditions map[string]string
+
+// Keys returns the storage keys of all renditions
+func (r PhotoRenditions) Keys() []string {
+	keys := make([]string, 0, len(r))
+	for _, format := range PhotoFormatPreference {
+		if key, ok := r[format]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Value implements driver.Valuer so renditions are stored as JSONB
+func (r PhotoRenditions) Value() (driver.Value, error) {
+	if r == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(r))
+}
+
+// Scan implements sql.Scanner for the JSONB column
+func (r *PhotoRenditions) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*r = PhotoRenditions{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for photo renditions: %T", value)
+	}
+	return json.Unmarshal(data, (*map[string]string)(r))
+}
+
+// NegotiatePhotoFormat picks the rendition to serve for an HTTP Accept header.
+// AVIF and WebP are only served to clients that list them explicitly, since
+// browsers without support still send wildcards; everyone else gets JPEG.
+// Returns "" when the photo has no renditions, in which case the original
+// upload should be served.
+func NegotiatePhotoFormat(accept string, renditions PhotoRenditions) string {
+	accepted := acceptedMediaTypes(accept)
+	for _, format := range PhotoFormatPreference {
+		if _, ok := renditions[format]; ok && accepted[PhotoFormatMimeType(format)] {
+			return format
+		}
+	}
+	if _, ok := renditions[PhotoFormatJPEG]; ok {
+		return PhotoFormatJPEG
+	}
+	return ""
+}
+
+// acceptedMediaTypes returns the media ranges of an Accept header that are not refused with q=0
+func acceptedMediaTypes(accept string) map[string]bool {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaRange == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.TrimSpace(name) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		accepted[mediaRange] = q > 0
+	}
+	return accepted
+}
+
 // ProductPhotoCreateRequest represents the request to upload a product photo
 type ProductPhotoCreateRequest struct {
 	ProductID    uuid.UUID `json:"product_id" form:"product_id"`
@@ -115,6 +229,8 @@ type StorageQuotaResponse struct {
 	PhotoCount        int       `json:"photo_count"`
 	ApproachingLimit  bool      `json:"approaching_limit"` // true if usage > 80%
 	QuotaExceeded     bool      `json:"quota_exceeded"`    // true if usage >= quota
+	PlanName          string    `json:"plan_name"`
+	PlanLimited       bool      `json:"plan_limited"` // true if the quota is the plan's storage limit
 }
 
 // Custom errors for ProductPhoto
@@ -0,0 +1,445 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/backend/product-service/src/models"
+)
+
+// CatalogVersionRepository handles database operations for catalog versions and their staged changes
+type CatalogVersionRepository struct {
+	db *sql.DB
+}
+
+// NewCatalogVersionRepository creates a new catalog version repository
+func NewCatalogVersionRepository(db *sql.DB) *CatalogVersionRepository {
+	return &CatalogVersionRepository{db: db}
+}
+
+const catalogVersionColumns = `
+	v.id, v.tenant_id, v.name, v.notes, v.status, v.publish_at, v.published_at, v.rolled_back_at,
+	v.failure_reason, v.created_by, v.published_by,
+	(SELECT COUNT(*) FROM catalog_version_changes c WHERE c.version_id = v.id) AS change_count,
+	v.created_at, v.updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCatalogVersion(row rowScanner) (*models.CatalogVersion, error) {
+	var v models.CatalogVersion
+	err := row.Scan(
+		&v.ID, &v.TenantID, &v.Name, &v.Notes, &v.Status, &v.PublishAt, &v.PublishedAt, &v.RolledBackAt,
+		&v.FailureReason, &v.CreatedBy, &v.PublishedBy, &v.ChangeCount, &v.CreatedAt, &v.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Create inserts a new draft version
+func (r *CatalogVersionRepository) Create(ctx context.Context, version *models.CatalogVersion) error {
+	query := `
+		INSERT INTO catalog_versions (tenant_id, name, notes, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		version.TenantID, version.Name, version.Notes, version.CreatedBy,
+	).Scan(&version.ID, &version.Status, &version.CreatedAt, &version.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create catalog version: %w", err)
+	}
+	return nil
+}
+
+// List returns a tenant's catalog versions, newest first
+func (r *CatalogVersionRepository) List(ctx context.Context, tenantID uuid.UUID) ([]models.CatalogVersion, error) {
+	query := `SELECT ` + catalogVersionColumns + `
+		FROM catalog_versions v
+		WHERE v.tenant_id = $1
+		ORDER BY v.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query catalog versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]models.CatalogVersion, 0)
+	for rows.Next() {
+		v, err := scanCatalogVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan catalog version: %w", err)
+		}
+		versions = append(versions, *v)
+	}
+	return versions, rows.Err()
+}
+
+// FindByID retrieves a version; nil when the tenant has no such version
+func (r *CatalogVersionRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*models.CatalogVersion, error) {
+	query := `SELECT ` + catalogVersionColumns + `
+		FROM catalog_versions v
+		WHERE v.id = $1 AND v.tenant_id = $2
+	`
+
+	v, err := scanCatalogVersion(r.db.QueryRowContext(ctx, query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog version: %w", err)
+	}
+	return v, nil
+}
+
+// ListChanges returns the staged changes of a version
+func (r *CatalogVersionRepository) ListChanges(ctx context.Context, versionID uuid.UUID) ([]models.CatalogChange, error) {
+	return r.listChanges(ctx, r.db, versionID)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (r *CatalogVersionRepository) listChanges(ctx context.Context, q queryer, versionID uuid.UUID) ([]models.CatalogChange, error) {
+	query := `
+		SELECT id, version_id, product_id, name, description, category_id, selling_price,
+		       primary_photo_id, archived, previous_state, created_at, updated_at
+		FROM catalog_version_changes
+		WHERE version_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := q.QueryContext(ctx, query, versionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query catalog changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]models.CatalogChange, 0)
+	for rows.Next() {
+		var c models.CatalogChange
+		if err := rows.Scan(
+			&c.ID, &c.VersionID, &c.ProductID, &c.Name, &c.Description, &c.CategoryID, &c.SellingPrice,
+			&c.PrimaryPhotoID, &c.Archived, &c.PreviousState, &c.CreatedAt, &c.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan catalog change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// UpsertChange stages a change to a product, replacing any change already staged for it
+func (r *CatalogVersionRepository) UpsertChange(ctx context.Context, tenantID, versionID uuid.UUID, req *models.CatalogChangeRequest) (*models.CatalogChange, error) {
+	query := `
+		INSERT INTO catalog_version_changes
+		(version_id, tenant_id, product_id, name, description, category_id, selling_price, primary_photo_id, archived)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (version_id, product_id) DO UPDATE SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			category_id = EXCLUDED.category_id,
+			selling_price = EXCLUDED.selling_price,
+			primary_photo_id = EXCLUDED.primary_photo_id,
+			archived = EXCLUDED.archived,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+
+	c := models.CatalogChange{
+		VersionID:      versionID,
+		ProductID:      req.ProductID,
+		Name:           req.Name,
+		Description:    req.Description,
+		CategoryID:     req.CategoryID,
+		SellingPrice:   req.SellingPrice,
+		PrimaryPhotoID: req.PrimaryPhotoID,
+		Archived:       req.Archived,
+	}
+	err := r.db.QueryRowContext(ctx, query,
+		versionID, tenantID, req.ProductID, req.Name, req.Description, req.CategoryID,
+		req.SellingPrice, req.PrimaryPhotoID, req.Archived,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage catalog change: %w", err)
+	}
+
+	r.touch(ctx, versionID)
+	return &c, nil
+}
+
+// DeleteChange removes a product's staged change; false when none was staged
+func (r *CatalogVersionRepository) DeleteChange(ctx context.Context, versionID, productID uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM catalog_version_changes WHERE version_id = $1 AND product_id = $2`,
+		versionID, productID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete catalog change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected > 0 {
+		r.touch(ctx, versionID)
+	}
+	return rowsAffected > 0, nil
+}
+
+// touch bumps the version's updated_at after its changes were edited
+func (r *CatalogVersionRepository) touch(ctx context.Context, versionID uuid.UUID) {
+	_, _ = r.db.ExecContext(ctx, `UPDATE catalog_versions SET updated_at = NOW() WHERE id = $1`, versionID)
+}
+
+// ProductExists checks the product belongs to the tenant
+func (r *CatalogVersionRepository) ProductExists(ctx context.Context, tenantID, productID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND tenant_id = $2)`,
+		productID, tenantID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// UpdateSchedule moves an editable version to draft or scheduled; false when it is no longer editable
+func (r *CatalogVersionRepository) UpdateSchedule(ctx context.Context, tenantID, id uuid.UUID, status models.CatalogVersionStatus, publishAt *time.Time) (bool, error) {
+	query := `
+		UPDATE catalog_versions
+		SET status = $3, publish_at = $4, failure_reason = NULL, updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2 AND status IN ('draft', 'scheduled', 'failed')
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, tenantID, status, publishAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to update catalog version schedule: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// Cancel discards an editable version; false when it is no longer editable
+func (r *CatalogVersionRepository) Cancel(ctx context.Context, tenantID, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE catalog_versions
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2 AND status IN ('draft', 'scheduled', 'failed')
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel catalog version: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// ListDue returns scheduled versions of all tenants whose publish time has passed, oldest first
+func (r *CatalogVersionRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.CatalogVersion, error) {
+	query := `SELECT ` + catalogVersionColumns + `
+		FROM catalog_versions v
+		WHERE v.status = 'scheduled' AND v.publish_at <= $1
+		ORDER BY v.publish_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due catalog versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]models.CatalogVersion, 0)
+	for rows.Next() {
+		v, err := scanCatalogVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan catalog version: %w", err)
+		}
+		versions = append(versions, *v)
+	}
+	return versions, rows.Err()
+}
+
+// MarkFailed records why a scheduled version could not be published
+func (r *CatalogVersionRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE catalog_versions
+		SET status = 'failed', failure_reason = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'scheduled'
+	`, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark catalog version failed: %w", err)
+	}
+	return nil
+}
+
+// LockVersion retrieves a version for update inside a publish or rollback transaction
+func (r *CatalogVersionRepository) LockVersion(ctx context.Context, tx *sql.Tx, tenantID, id uuid.UUID) (*models.CatalogVersion, error) {
+	query := `SELECT ` + catalogVersionColumns + `
+		FROM catalog_versions v
+		WHERE v.id = $1 AND v.tenant_id = $2
+		FOR UPDATE
+	`
+
+	v, err := scanCatalogVersion(tx.QueryRowContext(ctx, query, id, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrCatalogVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock catalog version: %w", err)
+	}
+	return v, nil
+}
+
+// LockChanges returns the version's changes inside a publish or rollback transaction
+func (r *CatalogVersionRepository) LockChanges(ctx context.Context, tx *sql.Tx, versionID uuid.UUID) ([]models.CatalogChange, error) {
+	return r.listChanges(ctx, tx, versionID)
+}
+
+// LatestPublishedID returns the tenant's most recently published version; uuid.Nil when none is live
+func (r *CatalogVersionRepository) LatestPublishedID(ctx context.Context, tx *sql.Tx, tenantID uuid.UUID) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, `
+		SELECT id FROM catalog_versions
+		WHERE tenant_id = $1 AND status = 'published'
+		ORDER BY published_at DESC
+		LIMIT 1
+	`, tenantID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, nil
+	}
+	return id, err
+}
+
+// GetProductState locks a product and reads the values a catalog version can change
+func (r *CatalogVersionRepository) GetProductState(ctx context.Context, tx *sql.Tx, tenantID, productID uuid.UUID) (*models.CatalogProductState, error) {
+	query := `
+		SELECT p.name, p.description, p.category_id, p.selling_price, p.archived_at,
+		       (SELECT ph.id FROM product_photos ph WHERE ph.product_id = p.id AND ph.is_primary = true LIMIT 1)
+		FROM products p
+		WHERE p.id = $1 AND p.tenant_id = $2
+		FOR UPDATE OF p
+	`
+
+	var s models.CatalogProductState
+	err := tx.QueryRowContext(ctx, query, productID, tenantID).Scan(
+		&s.Name, &s.Description, &s.CategoryID, &s.SellingPrice, &s.ArchivedAt, &s.PrimaryPhotoID,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrCatalogProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product state: %w", err)
+	}
+	return &s, nil
+}
+
+// CategoryExists checks the category belongs to the tenant
+func (r *CatalogVersionRepository) CategoryExists(ctx context.Context, tx *sql.Tx, tenantID, categoryID uuid.UUID) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1 AND tenant_id = $2)`,
+		categoryID, tenantID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// PhotoExists checks the photo belongs to the product
+func (r *CatalogVersionRepository) PhotoExists(ctx context.Context, tx *sql.Tx, tenantID, productID, photoID uuid.UUID) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM product_photos WHERE id = $1 AND product_id = $2 AND tenant_id = $3)`,
+		photoID, productID, tenantID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// ApplyProductState writes a product's values, including which photo is primary
+// The photo must exist; a nil PrimaryPhotoID leaves the product without a primary photo.
+func (r *CatalogVersionRepository) ApplyProductState(ctx context.Context, tx *sql.Tx, tenantID, productID uuid.UUID, state *models.CatalogProductState) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE products
+		SET name = $3, description = $4, category_id = $5, selling_price = $6, archived_at = $7, updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2
+	`, productID, tenantID, state.Name, state.Description, state.CategoryID, state.SellingPrice, state.ArchivedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+
+	// Clear first: only one photo per product may be primary at a time
+	_, err = tx.ExecContext(ctx, `
+		UPDATE product_photos
+		SET is_primary = false, updated_at = NOW()
+		WHERE product_id = $1 AND tenant_id = $2 AND is_primary = true AND id IS DISTINCT FROM $3
+	`, productID, tenantID, state.PrimaryPhotoID)
+	if err != nil {
+		return fmt.Errorf("failed to clear primary photo: %w", err)
+	}
+	if state.PrimaryPhotoID == nil {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE product_photos
+		SET is_primary = true, updated_at = NOW()
+		WHERE id = $1 AND product_id = $2 AND tenant_id = $3 AND is_primary = false
+	`, state.PrimaryPhotoID, productID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to set primary photo: %w", err)
+	}
+	return nil
+}
+
+// SavePreviousState stores the product values captured before a change was published
+func (r *CatalogVersionRepository) SavePreviousState(ctx context.Context, tx *sql.Tx, changeID uuid.UUID, state *models.CatalogProductState) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE catalog_version_changes SET previous_state = $2, updated_at = NOW() WHERE id = $1`,
+		changeID, state,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save previous product state: %w", err)
+	}
+	return nil
+}
+
+// MarkPublished marks a version live; publishedBy is nil when the scheduler published it
+func (r *CatalogVersionRepository) MarkPublished(ctx context.Context, tx *sql.Tx, version *models.CatalogVersion, publishedBy *uuid.UUID) error {
+	err := tx.QueryRowContext(ctx, `
+		UPDATE catalog_versions
+		SET status = 'published', published_at = NOW(), published_by = $2, failure_reason = NULL, updated_at = NOW()
+		WHERE id = $1
+		RETURNING status, published_at, published_by, failure_reason, updated_at
+	`, version.ID, publishedBy).Scan(
+		&version.Status, &version.PublishedAt, &version.PublishedBy, &version.FailureReason, &version.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark catalog version published: %w", err)
+	}
+	return nil
+}
+
+// MarkRolledBack marks a published version as undone
+func (r *CatalogVersionRepository) MarkRolledBack(ctx context.Context, tx *sql.Tx, version *models.CatalogVersion) error {
+	err := tx.QueryRowContext(ctx, `
+		UPDATE catalog_versions
+		SET status = 'rolled_back', rolled_back_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+		RETURNING status, rolled_back_at, updated_at
+	`, version.ID).Scan(&version.Status, &version.RolledBackAt, &version.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark catalog version rolled back: %w", err)
+	}
+	return nil
+}
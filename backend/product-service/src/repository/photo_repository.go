@@ -26,8 +26,8 @@ func (r *PhotoRepository) Create(ctx context.Context, photo *models.ProductPhoto
 		INSERT INTO product_photos (
 			id, product_id, tenant_id, storage_key, original_filename,
 			file_size_bytes, mime_type, width_px, height_px,
-			display_order, is_primary, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			display_order, is_primary, renditions, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -36,7 +36,7 @@ func (r *PhotoRepository) Create(ctx context.Context, photo *models.ProductPhoto
 		photo.ID, photo.ProductID, photo.TenantID, photo.StorageKey,
 		photo.OriginalFilename, photo.FileSizeBytes, photo.MimeType,
 		photo.WidthPx, photo.HeightPx, photo.DisplayOrder, photo.IsPrimary,
-		time.Now(), time.Now(),
+		photo.Renditions, time.Now(), time.Now(),
 	).Scan(&photo.ID, &photo.CreatedAt, &photo.UpdatedAt)
 
 	if err != nil {
@@ -51,7 +51,7 @@ func (r *PhotoRepository) GetByProduct(ctx context.Context, productID, tenantID
 	query := `
 		SELECT id, product_id, tenant_id, storage_key, original_filename,
 		       file_size_bytes, mime_type, width_px, height_px,
-		       display_order, is_primary, created_at, updated_at
+		       display_order, is_primary, renditions, created_at, updated_at
 		FROM product_photos
 		WHERE product_id = $1 AND tenant_id = $2
 		ORDER BY display_order ASC, created_at ASC
@@ -70,7 +70,7 @@ func (r *PhotoRepository) GetByProduct(ctx context.Context, productID, tenantID
 			&photo.ID, &photo.ProductID, &photo.TenantID, &photo.StorageKey,
 			&photo.OriginalFilename, &photo.FileSizeBytes, &photo.MimeType,
 			&photo.WidthPx, &photo.HeightPx, &photo.DisplayOrder, &photo.IsPrimary,
-			&photo.CreatedAt, &photo.UpdatedAt,
+			&photo.Renditions, &photo.CreatedAt, &photo.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan product photo: %w", err)
@@ -90,7 +90,7 @@ func (r *PhotoRepository) GetByID(ctx context.Context, photoID, tenantID uuid.UU
 	query := `
 		SELECT id, product_id, tenant_id, storage_key, original_filename,
 		       file_size_bytes, mime_type, width_px, height_px,
-		       display_order, is_primary, created_at, updated_at
+		       display_order, is_primary, renditions, created_at, updated_at
 		FROM product_photos
 		WHERE id = $1 AND tenant_id = $2
 	`
@@ -100,7 +100,7 @@ func (r *PhotoRepository) GetByID(ctx context.Context, photoID, tenantID uuid.UU
 		&photo.ID, &photo.ProductID, &photo.TenantID, &photo.StorageKey,
 		&photo.OriginalFilename, &photo.FileSizeBytes, &photo.MimeType,
 		&photo.WidthPx, &photo.HeightPx, &photo.DisplayOrder, &photo.IsPrimary,
-		&photo.CreatedAt, &photo.UpdatedAt,
+		&photo.Renditions, &photo.CreatedAt, &photo.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -157,14 +157,14 @@ func (r *PhotoRepository) Update(ctx context.Context, photo *models.ProductPhoto
 	query := `
 		UPDATE product_photos 
 		SET storage_key = $1, original_filename = $2, file_size_bytes = $3,
-		    mime_type = $4, width_px = $5, height_px = $6, updated_at = $7
-		WHERE id = $8 AND tenant_id = $9
+		    mime_type = $4, width_px = $5, height_px = $6, renditions = $7, updated_at = $8
+		WHERE id = $9 AND tenant_id = $10
 	`
 
 	result, err := r.db.ExecContext(
 		ctx, query,
 		photo.StorageKey, photo.OriginalFilename, photo.FileSizeBytes,
-		photo.MimeType, photo.WidthPx, photo.HeightPx, time.Now(),
+		photo.MimeType, photo.WidthPx, photo.HeightPx, photo.Renditions, time.Now(),
 		photo.ID, photo.TenantID,
 	)
 	if err != nil {
@@ -248,17 +248,19 @@ func (r *PhotoRepository) UpdateTenantStorageUsage(ctx context.Context, tenantID
 }
 
 // GetTenantStorageQuota retrieves storage quota information for a tenant
+// The quota is the storage limit of the tenant's plan, or the tenant's own quota when the plan has none.
 func (r *PhotoRepository) GetTenantStorageQuota(ctx context.Context, tenantID uuid.UUID) (*models.StorageQuotaResponse, error) {
 	query := `
 		SELECT 
 			t.id,
 			COALESCE(t.storage_used_bytes, 0),
-			COALESCE(t.storage_quota_bytes, 5368709120),
-			COUNT(p.id)
+			COALESCE(sp.max_storage_bytes, t.storage_quota_bytes, 5368709120),
+			sp.max_storage_bytes IS NOT NULL,
+			COALESCE(sp.name, ''),
+			(SELECT COUNT(*) FROM product_photos p WHERE p.tenant_id = t.id)
 		FROM tenants t
-		LEFT JOIN product_photos p ON p.tenant_id = t.id
+		LEFT JOIN subscription_plans sp ON sp.code = ` + tenantPlanCode + `
 		WHERE t.id = $1
-		GROUP BY t.id, t.storage_used_bytes, t.storage_quota_bytes
 	`
 
 	var quota models.StorageQuotaResponse
@@ -266,6 +268,8 @@ func (r *PhotoRepository) GetTenantStorageQuota(ctx context.Context, tenantID uu
 		&quota.TenantID,
 		&quota.StorageUsedBytes,
 		&quota.StorageQuotaBytes,
+		&quota.PlanLimited,
+		&quota.PlanName,
 		&quota.PhotoCount,
 	)
 
@@ -292,6 +296,11 @@ func (r *PhotoRepository) GetTenantStorageQuota(ctx context.Context, tenantID uu
 	return &quota, nil
 }
 
+// FindStorageUpgradePlan returns the cheapest plan allowing at least needed bytes of storage, or "" when none does
+func (r *PhotoRepository) FindStorageUpgradePlan(ctx context.Context, needed int64) (string, error) {
+	return findUpgradePlan(ctx, r.db, "max_storage_bytes", needed)
+}
+
 // ClearPrimaryPhoto removes primary flag from all photos of a product
 func (r *PhotoRepository) ClearPrimaryPhoto(ctx context.Context, productID, tenantID uuid.UUID) error {
 	query := `
@@ -346,7 +355,7 @@ func (r *PhotoRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID)
 	query := `
 		SELECT id, product_id, tenant_id, storage_key, original_filename,
 			   file_size_bytes, mime_type, width_px, height_px,
-			   display_order, is_primary, created_at, updated_at
+			   display_order, is_primary, renditions, created_at, updated_at
 		FROM product_photos
 		WHERE tenant_id = $1
 		ORDER BY created_at DESC
@@ -365,7 +374,7 @@ func (r *PhotoRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID)
 			&photo.ID, &photo.ProductID, &photo.TenantID, &photo.StorageKey,
 			&photo.OriginalFilename, &photo.FileSizeBytes, &photo.MimeType,
 			&photo.WidthPx, &photo.HeightPx, &photo.DisplayOrder, &photo.IsPrimary,
-			&photo.CreatedAt, &photo.UpdatedAt,
+			&photo.Renditions, &photo.CreatedAt, &photo.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan photo: %w", err)
@@ -380,22 +389,19 @@ func (r *PhotoRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID)
 	return photos, nil
 }
 
-// DeleteAllByTenant deletes all photos for a tenant (for cascade deletion)
-func (r *PhotoRepository) DeleteAllByTenant(ctx context.Context, tenantID uuid.UUID) error {
+// DeleteAllByTenant deletes all photos for a tenant (for cascade deletion) and returns how many were deleted
+func (r *PhotoRepository) DeleteAllByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
 	query := "DELETE FROM product_photos WHERE tenant_id = $1"
 
 	result, err := r.db.ExecContext(ctx, query, tenantID)
 	if err != nil {
-		return fmt.Errorf("failed to delete tenant photos: %w", err)
+		return 0, fmt.Errorf("failed to delete tenant photos: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	// Log the number of photos deleted (caller should log this)
-	_ = rowsAffected
-
-	return nil
+	return rowsAffected, nil
 }
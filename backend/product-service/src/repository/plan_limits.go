@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// tenantPlanCode selects the plan of tenant $1; tenants without a subscription are on the free plan
+const tenantPlanCode = `COALESCE((SELECT plan_code FROM tenant_subscriptions WHERE tenant_id = $1), 'free')`
+
+// findUpgradePlan returns the cheapest plan whose limit column allows at least needed, or "" when none does
+func findUpgradePlan(ctx context.Context, db *sql.DB, column string, needed int64) (string, error) {
+	var code string
+	err := db.QueryRowContext(ctx, `
+		SELECT code FROM subscription_plans
+		WHERE `+column+` IS NULL OR `+column+` >= $1
+		ORDER BY monthly_price, code
+		LIMIT 1
+	`, needed).Scan(&code)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find upgrade plan: %w", err)
+	}
+	return code, nil
+}
@@ -23,6 +23,8 @@ type ProductRepository interface {
 	HasSalesHistory(ctx context.Context, id uuid.UUID) (bool, error)
 	Count(ctx context.Context, tenantID uuid.UUID, filters map[string]interface{}) (int, error)
 	CreateStockAdjustment(ctx context.Context, adjustment *models.StockAdjustment) error
+	ProductPlanUsage(ctx context.Context, tenantID uuid.UUID) (string, *int64, int64, error)
+	FindProductUpgradePlan(ctx context.Context, needed int64) (string, error)
 }
 
 type productRepository struct {
@@ -245,3 +247,29 @@ func (r *productRepository) CreateStockAdjustment(ctx context.Context, adjustmen
 		adjustment.PreviousQuantity, adjustment.NewQuantity, adjustment.Reason, adjustment.Notes,
 	).Scan(&adjustment.ID, &adjustment.QuantityDelta, &adjustment.CreatedAt)
 }
+
+// ProductPlanUsage returns the tenant's plan name, its product limit (nil when unlimited) and the
+// products the tenant has that are not archived
+func (r *productRepository) ProductPlanUsage(ctx context.Context, tenantID uuid.UUID) (string, *int64, int64, error) {
+	var planName string
+	var maxProducts sql.NullInt64
+	var used int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT p.name, p.max_products,
+			(SELECT COUNT(*) FROM products WHERE tenant_id = $1 AND archived_at IS NULL)
+		FROM subscription_plans p
+		WHERE p.code = `+tenantPlanCode+`
+	`, tenantID).Scan(&planName, &maxProducts, &used)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to get product plan usage: %w", err)
+	}
+	if !maxProducts.Valid {
+		return planName, nil, used, nil
+	}
+	return planName, &maxProducts.Int64, used, nil
+}
+
+// FindProductUpgradePlan returns the cheapest plan allowing at least needed products, or "" when none does
+func (r *productRepository) FindProductUpgradePlan(ctx context.Context, needed int64) (string, error) {
+	return findUpgradePlan(ctx, r.db, "max_products", needed)
+}
@@ -187,3 +187,19 @@ func (r *StockRepository) GetAdjustmentsByTenant(ctx context.Context, tenantID u
 
 	return adjustments, total, nil
 }
+
+// DeleteAllByTenant deletes a tenant's stock adjustment history and returns how many entries were deleted
+// Adjustments reference the staff who made them, so they must go before the tenant's users.
+func (r *StockRepository) DeleteAllByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM stock_adjustments WHERE tenant_id = $1", tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tenant stock adjustments: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CatalogPublishJob publishes scheduled catalog versions once their publish time has passed
+type CatalogPublishJob struct {
+	versionService *CatalogVersionService
+	ticker         *time.Ticker
+	batchSize      int
+	stopChan       chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewCatalogPublishJob creates a new publish job
+func NewCatalogPublishJob(versionService *CatalogVersionService, checkInterval time.Duration) *CatalogPublishJob {
+	return &CatalogPublishJob{
+		versionService: versionService,
+		ticker:         time.NewTicker(checkInterval),
+		batchSize:      50, // Versions per run
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins checking for due versions in the background
+func (j *CatalogPublishJob) Start(ctx context.Context) {
+	j.wg.Add(1)
+	go j.run(ctx)
+	log.Info().Msg("Catalog publish job started")
+}
+
+// Stop gracefully shuts down the publish job
+func (j *CatalogPublishJob) Stop() {
+	close(j.stopChan)
+	j.ticker.Stop()
+	j.wg.Wait()
+	log.Info().Msg("Catalog publish job stopped")
+}
+
+func (j *CatalogPublishJob) run(ctx context.Context) {
+	defer j.wg.Done()
+
+	// Catch up on versions that came due while the service was down
+	j.publishDue(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Catalog publish job context cancelled")
+			return
+		case <-j.stopChan:
+			return
+		case <-j.ticker.C:
+			j.publishDue(ctx)
+		}
+	}
+}
+
+func (j *CatalogPublishJob) publishDue(ctx context.Context) {
+	published, err := j.versionService.PublishDue(ctx, time.Now(), j.batchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to publish due catalog versions")
+		return
+	}
+	if published > 0 {
+		log.Info().Int("published", published).Msg("Scheduled catalog versions published")
+	}
+}
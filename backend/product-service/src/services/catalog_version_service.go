@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/backend/product-service/src/models"
+	"github.com/pos/backend/product-service/src/repository"
+	"github.com/pos/backend/product-service/src/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// errCatalogVersionNotDue means a due version was rescheduled, published or cancelled
+// after the publish job listed it
+var errCatalogVersionNotDue = errors.New("catalog version is no longer due")
+
+// CatalogVersionService stages product changes in draft versions and publishes them atomically
+type CatalogVersionService struct {
+	repo           *repository.CatalogVersionRepository
+	db             *sql.DB
+	auditPublisher utils.AuditPublisherInterface
+}
+
+// NewCatalogVersionService creates a new CatalogVersionService
+func NewCatalogVersionService(repo *repository.CatalogVersionRepository, db *sql.DB, auditPublisher utils.AuditPublisherInterface) *CatalogVersionService {
+	return &CatalogVersionService{
+		repo:           repo,
+		db:             db,
+		auditPublisher: auditPublisher,
+	}
+}
+
+// CreateVersion starts a new draft version
+func (s *CatalogVersionService) CreateVersion(ctx context.Context, tenantID uuid.UUID, userID *uuid.UUID, req *models.CreateCatalogVersionRequest) (*models.CatalogVersion, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	version := &models.CatalogVersion{
+		TenantID:  tenantID,
+		Name:      req.Name,
+		Notes:     req.Notes,
+		CreatedBy: userID,
+	}
+	if err := s.repo.Create(ctx, version); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("version_id", version.ID.String()).
+		Msg("Catalog version created")
+	return version, nil
+}
+
+// ListVersions returns a tenant's catalog versions, newest first
+func (s *CatalogVersionService) ListVersions(ctx context.Context, tenantID uuid.UUID) ([]models.CatalogVersion, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+// GetVersion returns a version with its staged changes
+func (s *CatalogVersionService) GetVersion(ctx context.Context, tenantID, id uuid.UUID) (*models.CatalogVersion, error) {
+	version, err := s.getVersion(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	version.Changes, err = s.repo.ListChanges(ctx, version.ID)
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (s *CatalogVersionService) getVersion(ctx context.Context, tenantID, id uuid.UUID) (*models.CatalogVersion, error) {
+	version, err := s.repo.FindByID(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, models.ErrCatalogVersionNotFound
+	}
+	return version, nil
+}
+
+func (s *CatalogVersionService) getEditableVersion(ctx context.Context, tenantID, id uuid.UUID) (*models.CatalogVersion, error) {
+	version, err := s.getVersion(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !version.IsEditable() {
+		return nil, models.ErrCatalogVersionNotEditable
+	}
+	return version, nil
+}
+
+// StageChange adds or replaces the change to one product in an editable version
+func (s *CatalogVersionService) StageChange(ctx context.Context, tenantID, versionID uuid.UUID, req *models.CatalogChangeRequest) (*models.CatalogChange, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	version, err := s.getEditableVersion(ctx, tenantID, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.ProductExists(ctx, tenantID, req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check product: %w", err)
+	}
+	if !exists {
+		return nil, models.ErrCatalogProductNotFound
+	}
+
+	return s.repo.UpsertChange(ctx, tenantID, version.ID, req)
+}
+
+// RemoveChange drops a product's staged change from an editable version
+func (s *CatalogVersionService) RemoveChange(ctx context.Context, tenantID, versionID, productID uuid.UUID) error {
+	version, err := s.getEditableVersion(ctx, tenantID, versionID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteChange(ctx, version.ID, productID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return models.ErrCatalogProductNotFound
+	}
+	return nil
+}
+
+// Schedule sets the time the publish job puts the version live
+func (s *CatalogVersionService) Schedule(ctx context.Context, tenantID, id uuid.UUID, req *models.ScheduleCatalogVersionRequest) (*models.CatalogVersion, error) {
+	if err := req.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	version, err := s.getEditableVersion(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if version.ChangeCount == 0 {
+		return nil, models.ErrCatalogVersionEmpty
+	}
+
+	publishAt := req.PublishAt.UTC()
+	if err := s.updateSchedule(ctx, tenantID, id, models.CatalogVersionScheduled, &publishAt); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("version_id", id.String()).
+		Time("publish_at", publishAt).
+		Msg("Catalog version scheduled")
+	return s.getVersion(ctx, tenantID, id)
+}
+
+// Unschedule moves a scheduled or failed version back to draft
+func (s *CatalogVersionService) Unschedule(ctx context.Context, tenantID, id uuid.UUID) (*models.CatalogVersion, error) {
+	if _, err := s.getEditableVersion(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	if err := s.updateSchedule(ctx, tenantID, id, models.CatalogVersionDraft, nil); err != nil {
+		return nil, err
+	}
+	return s.getVersion(ctx, tenantID, id)
+}
+
+func (s *CatalogVersionService) updateSchedule(ctx context.Context, tenantID, id uuid.UUID, status models.CatalogVersionStatus, publishAt *time.Time) error {
+	updated, err := s.repo.UpdateSchedule(ctx, tenantID, id, status, publishAt)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		// Published or cancelled since it was read
+		return models.ErrCatalogVersionNotEditable
+	}
+	return nil
+}
+
+// Cancel discards a version that has not been published
+func (s *CatalogVersionService) Cancel(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := s.getVersion(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	cancelled, err := s.repo.Cancel(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return models.ErrCatalogVersionNotEditable
+	}
+
+	log.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("version_id", id.String()).
+		Msg("Catalog version cancelled")
+	return nil
+}
+
+// Publish puts an editable version live immediately
+func (s *CatalogVersionService) Publish(ctx context.Context, tenantID, id uuid.UUID, userID *uuid.UUID) (*models.CatalogVersion, error) {
+	return s.publish(ctx, tenantID, id, userID, false)
+}
+
+// PublishDue publishes scheduled versions whose time has come and returns how many went live
+// Versions rejected because their changes no longer apply (e.g. a staged photo was deleted)
+// are marked failed so the owner can fix and reschedule them; other errors are retried on
+// the next run.
+func (s *CatalogVersionService) PublishDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	due, err := s.repo.ListDue(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, version := range due {
+		_, err := s.publish(ctx, version.TenantID, version.ID, nil, true)
+		switch {
+		case err == nil:
+			published++
+		case errors.Is(err, errCatalogVersionNotDue):
+		case isCatalogRejection(err):
+			log.Warn().
+				Err(err).
+				Str("tenant_id", version.TenantID.String()).
+				Str("version_id", version.ID.String()).
+				Msg("Scheduled catalog version rejected")
+			if err := s.repo.MarkFailed(ctx, version.ID, err.Error()); err != nil {
+				log.Error().Err(err).Str("version_id", version.ID.String()).Msg("Failed to mark catalog version failed")
+			}
+		default:
+			log.Error().
+				Err(err).
+				Str("tenant_id", version.TenantID.String()).
+				Str("version_id", version.ID.String()).
+				Msg("Failed to publish scheduled catalog version")
+		}
+	}
+	return published, nil
+}
+
+// isCatalogRejection reports whether a publish failed because of the version's content
+func isCatalogRejection(err error) bool {
+	return errors.Is(err, models.ErrCatalogVersionEmpty) ||
+		errors.Is(err, models.ErrCatalogProductNotFound) ||
+		errors.Is(err, models.ErrCatalogCategoryNotFound) ||
+		errors.Is(err, models.ErrCatalogPhotoNotFound)
+}
+
+// publish applies every staged change in one transaction, capturing each product's
+// previous values for rollback
+func (s *CatalogVersionService) publish(ctx context.Context, tenantID, id uuid.UUID, userID *uuid.UUID, scheduled bool) (*models.CatalogVersion, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	version, err := s.repo.LockVersion(ctx, tx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if scheduled && (version.Status != models.CatalogVersionScheduled || version.PublishAt == nil || version.PublishAt.After(now)) {
+		return nil, errCatalogVersionNotDue
+	}
+	if !version.IsEditable() {
+		return nil, models.ErrCatalogVersionNotEditable
+	}
+
+	changes, err := s.repo.LockChanges(ctx, tx, version.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, models.ErrCatalogVersionEmpty
+	}
+
+	for i := range changes {
+		change := &changes[i]
+
+		current, err := s.repo.GetProductState(ctx, tx, tenantID, change.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, change.ProductID)
+		}
+		if change.CategoryID != nil {
+			exists, err := s.repo.CategoryExists(ctx, tx, tenantID, *change.CategoryID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check category: %w", err)
+			}
+			if !exists {
+				return nil, fmt.Errorf("%w: %s", models.ErrCatalogCategoryNotFound, change.CategoryID)
+			}
+		}
+		if change.PrimaryPhotoID != nil {
+			exists, err := s.repo.PhotoExists(ctx, tx, tenantID, change.ProductID, *change.PrimaryPhotoID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check photo: %w", err)
+			}
+			if !exists {
+				return nil, fmt.Errorf("%w: %s", models.ErrCatalogPhotoNotFound, change.PrimaryPhotoID)
+			}
+		}
+
+		next := change.Apply(*current, now)
+		if err := s.repo.ApplyProductState(ctx, tx, tenantID, change.ProductID, &next); err != nil {
+			return nil, err
+		}
+		if err := s.repo.SavePreviousState(ctx, tx, change.ID, current); err != nil {
+			return nil, err
+		}
+		change.PreviousState = current
+	}
+
+	previousStatus := version.Status
+	if err := s.repo.MarkPublished(ctx, tx, version, userID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit catalog publish: %w", err)
+	}
+	version.Changes = changes
+
+	log.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("version_id", version.ID.String()).
+		Int("change_count", len(changes)).
+		Bool("scheduled", scheduled).
+		Msg("Catalog version published")
+
+	s.publishAuditEvent(version, userID, "publish", previousStatus)
+	return version, nil
+}
+
+// Rollback restores the products changed by the tenant's latest published version
+// Products deleted since the publish are skipped, as are primary photos that no longer exist.
+func (s *CatalogVersionService) Rollback(ctx context.Context, tenantID, id uuid.UUID, userID *uuid.UUID) (*models.CatalogVersion, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	version, err := s.repo.LockVersion(ctx, tx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if version.Status != models.CatalogVersionPublished {
+		return nil, models.ErrCatalogVersionNotPublished
+	}
+
+	latestID, err := s.repo.LatestPublishedID(ctx, tx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest published version: %w", err)
+	}
+	if latestID != version.ID {
+		return nil, models.ErrCatalogVersionNotLatest
+	}
+
+	changes, err := s.repo.LockChanges(ctx, tx, version.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if change.PreviousState == nil {
+			continue
+		}
+
+		if _, err := s.repo.GetProductState(ctx, tx, tenantID, change.ProductID); err != nil {
+			if errors.Is(err, models.ErrCatalogProductNotFound) {
+				log.Warn().Str("product_id", change.ProductID.String()).Msg("Skipping rollback of deleted product")
+				continue
+			}
+			return nil, err
+		}
+
+		restore := *change.PreviousState
+		if restore.PrimaryPhotoID != nil {
+			exists, err := s.repo.PhotoExists(ctx, tx, tenantID, change.ProductID, *restore.PrimaryPhotoID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check photo: %w", err)
+			}
+			if !exists {
+				log.Warn().
+					Str("product_id", change.ProductID.String()).
+					Str("photo_id", restore.PrimaryPhotoID.String()).
+					Msg("Previous primary photo was deleted, leaving current photo")
+				current, err := s.repo.GetProductState(ctx, tx, tenantID, change.ProductID)
+				if err != nil {
+					return nil, err
+				}
+				restore.PrimaryPhotoID = current.PrimaryPhotoID
+			}
+		}
+
+		if err := s.repo.ApplyProductState(ctx, tx, tenantID, change.ProductID, &restore); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.MarkRolledBack(ctx, tx, version); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit catalog rollback: %w", err)
+	}
+	version.Changes = changes
+
+	log.Info().
+		Str("tenant_id", tenantID.String()).
+		Str("version_id", version.ID.String()).
+		Int("change_count", len(changes)).
+		Msg("Catalog version rolled back")
+
+	s.publishAuditEvent(version, userID, "rollback", models.CatalogVersionPublished)
+	return version, nil
+}
+
+// publishAuditEvent records a publish or rollback in the audit trail
+// Failures are logged; the catalog change has already been committed.
+func (s *CatalogVersionService) publishAuditEvent(version *models.CatalogVersion, userID *uuid.UUID, operation string, previousStatus models.CatalogVersionStatus) {
+	if s.auditPublisher == nil {
+		return
+	}
+
+	// The publish job acts as the system; owners publishing or rolling back act as themselves
+	auditEvent := utils.NewSystemEvent(version.TenantID.String(), "UPDATE", "catalog_version", version.ID.String())
+	if userID != nil {
+		actorID := userID.String()
+		auditEvent.ActorType = "user"
+		auditEvent.ActorID = &actorID
+	}
+
+	productIDs := make([]string, 0, len(version.Changes))
+	for _, change := range version.Changes {
+		productIDs = append(productIDs, change.ProductID.String())
+	}
+
+	auditEvent.BeforeValue = map[string]interface{}{"status": previousStatus}
+	auditEvent.AfterValue = map[string]interface{}{"status": version.Status}
+	auditEvent.Metadata = map[string]interface{}{
+		"operation":    operation,
+		"name":         version.Name,
+		"change_count": len(version.Changes),
+		"product_ids":  productIDs,
+		"publish_at":   version.PublishAt,
+		"published_at": version.PublishedAt,
+	}
+
+	auditCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.auditPublisher.Publish(auditCtx, auditEvent); err != nil {
+		log.Warn().Err(err).Str("version_id", version.ID.String()).Str("operation", operation).Msg("Failed to publish catalog version audit event")
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/pos/backend/product-service/src/models"
+)
+
+// encodeTimeout bounds a single external encoder run
+const encodeTimeout = 30 * time.Second
+
+// ImageEncoder encodes a decoded image into one rendition format
+type ImageEncoder interface {
+	Format() string
+	Encode(ctx context.Context, img image.Image) ([]byte, error)
+}
+
+// jpegEncoder encodes renditions with the standard library
+type jpegEncoder struct {
+	quality int
+}
+
+// NewJPEGEncoder creates the JPEG rendition encoder (always available)
+func NewJPEGEncoder() ImageEncoder {
+	return &jpegEncoder{quality: 82}
+}
+
+func (e *jpegEncoder) Format() string {
+	return models.PhotoFormatJPEG
+}
+
+func (e *jpegEncoder) Encode(ctx context.Context, img image.Image) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: e.quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// commandEncoder encodes renditions by running an external encoder binary
+// (avifenc, cwebp) on a temporary PNG copy of the image
+type commandEncoder struct {
+	format string
+	path   string
+	args   func(input, output string) []string
+}
+
+// NewAVIFEncoder creates an AVIF encoder backed by libavif's avifenc.
+// Returns an error when the binary cannot be found.
+func NewAVIFEncoder(command string) (ImageEncoder, error) {
+	return newCommandEncoder(models.PhotoFormatAVIF, command, func(input, output string) []string {
+		return []string{"--jobs", "2", "--speed", "6", "-q", "60", input, output}
+	})
+}
+
+// NewWebPEncoder creates a WebP encoder backed by libwebp's cwebp.
+// Returns an error when the binary cannot be found.
+func NewWebPEncoder(command string) (ImageEncoder, error) {
+	return newCommandEncoder(models.PhotoFormatWebP, command, func(input, output string) []string {
+		return []string{"-quiet", "-q", "80", input, "-o", output}
+	})
+}
+
+func newCommandEncoder(format, command string, args func(input, output string) []string) (ImageEncoder, error) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil, fmt.Errorf("%s encoder %q not available: %w", format, command, err)
+	}
+	return &commandEncoder{format: format, path: path, args: args}, nil
+}
+
+func (e *commandEncoder) Format() string {
+	return e.format
+}
+
+func (e *commandEncoder) Encode(ctx context.Context, img image.Image) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "photo-rendition-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "source.png")
+	output := filepath.Join(dir, "rendition."+e.format)
+
+	file, err := os.Create(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encoder input: %w", err)
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write encoder input: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write encoder input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, encodeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, e.path, e.args(input, output)...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s encoder failed: %w: %s", e.format, err, bytes.TrimSpace(out))
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s rendition: %w", e.format, err)
+	}
+	return data, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	_ "image/gif"
@@ -11,14 +12,20 @@ import (
 	"strings"
 
 	"github.com/disintegration/imaging"
+	"github.com/pos/backend/product-service/src/models"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/image/webp"
 )
 
+// maxDisplaySize is the longest edge, in pixels, of stored photos and renditions
+const maxDisplaySize = 2048
+
 // ImageProcessor handles image validation and optimization
 type ImageProcessor struct {
 	maxSizeBytes int64 // Maximum file size in bytes
 	maxWidth     int   // Maximum width in pixels
 	maxHeight    int   // Maximum height in pixels
+	encoders     []ImageEncoder
 }
 
 // NewImageProcessor creates a new ImageProcessor
@@ -30,6 +37,19 @@ func NewImageProcessor(maxSizeBytes int64, maxWidth, maxHeight int) *ImageProces
 	}
 }
 
+// WithEncoders sets the encoders used to generate photo renditions
+func (p *ImageProcessor) WithEncoders(encoders ...ImageEncoder) *ImageProcessor {
+	p.encoders = encoders
+	return p
+}
+
+// ImageRendition is an alternative encoding of an uploaded photo
+type ImageRendition struct {
+	Format   string
+	MimeType string
+	Data     []byte
+}
+
 // ImageMetadata contains image metadata
 type ImageMetadata struct {
 	Width    int
@@ -120,24 +140,8 @@ func (p *ImageProcessor) OptimizeImage(imageData []byte, mimeType string) ([]byt
 		return imageData, nil
 	}
 
-	// Check if resizing is needed (images larger than 2048px on either dimension)
-	const maxDisplaySize = 2048
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-
-	if width > maxDisplaySize || height > maxDisplaySize {
-		// Calculate new dimensions maintaining aspect ratio
-		if width > height {
-			height = height * maxDisplaySize / width
-			width = maxDisplaySize
-		} else {
-			width = width * maxDisplaySize / height
-			height = maxDisplaySize
-		}
-		// Resize using Lanczos filter (high quality)
-		img = imaging.Resize(img, width, height, imaging.Lanczos)
-	}
+	// Resize images larger than 2048px on either dimension
+	img = fitDisplaySize(img)
 
 	// Re-encode based on format
 	buf := new(bytes.Buffer)
@@ -173,6 +177,63 @@ func (p *ImageProcessor) OptimizeImage(imageData []byte, mimeType string) ([]byt
 	return imageData, nil
 }
 
+// GenerateRenditions encodes the photo into every configured rendition format.
+// GIFs are skipped so animations are preserved. A failing encoder only drops
+// its own format; the remaining renditions are still returned.
+func (p *ImageProcessor) GenerateRenditions(ctx context.Context, imageData []byte, mimeType string) []ImageRendition {
+	if len(p.encoders) == 0 || mimeType == "image/gif" {
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		img, err = webp.Decode(bytes.NewReader(imageData))
+		if err != nil {
+			log.Warn().Err(err).Str("mime_type", mimeType).Msg("Failed to decode photo for renditions")
+			return nil
+		}
+	}
+	img = fitDisplaySize(img)
+
+	renditions := make([]ImageRendition, 0, len(p.encoders))
+	for _, encoder := range p.encoders {
+		data, err := encoder.Encode(ctx, img)
+		if err != nil {
+			log.Warn().Err(err).Str("format", encoder.Format()).Msg("Failed to generate photo rendition")
+			continue
+		}
+		renditions = append(renditions, ImageRendition{
+			Format:   encoder.Format(),
+			MimeType: models.PhotoFormatMimeType(encoder.Format()),
+			Data:     data,
+		})
+	}
+
+	return renditions
+}
+
+// fitDisplaySize downscales an image so neither edge exceeds maxDisplaySize,
+// maintaining aspect ratio
+func fitDisplaySize(img image.Image) image.Image {
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	if width <= maxDisplaySize && height <= maxDisplaySize {
+		return img
+	}
+
+	if width > height {
+		height = height * maxDisplaySize / width
+		width = maxDisplaySize
+	} else {
+		width = width * maxDisplaySize / height
+		height = maxDisplaySize
+	}
+	// Resize using Lanczos filter (high quality)
+	return imaging.Resize(img, width, height, imaging.Lanczos)
+}
+
 // formatToMimeType converts image format string to MIME type
 func formatToMimeType(format string) string {
 	switch strings.ToLower(format) {
@@ -11,19 +11,22 @@ import (
 	"github.com/pos/backend/product-service/src/models"
 	"github.com/pos/backend/product-service/src/repository"
 	"github.com/pos/backend/product-service/src/utils"
+	"github.com/rs/zerolog/log"
 )
 
 type InventoryService struct {
-	productRepo repository.ProductRepository
-	stockRepo   *repository.StockRepository
-	db          *sql.DB
+	productRepo    repository.ProductRepository
+	stockRepo      *repository.StockRepository
+	db             *sql.DB
+	auditPublisher *utils.AuditPublisher
 }
 
-func NewInventoryService(productRepo repository.ProductRepository, stockRepo *repository.StockRepository, db *sql.DB) *InventoryService {
+func NewInventoryService(productRepo repository.ProductRepository, stockRepo *repository.StockRepository, db *sql.DB, auditPublisher *utils.AuditPublisher) *InventoryService {
 	return &InventoryService{
-		productRepo: productRepo,
-		stockRepo:   stockRepo,
-		db:          db,
+		productRepo:    productRepo,
+		stockRepo:      stockRepo,
+		db:             db,
+		auditPublisher: auditPublisher,
 	}
 }
 
@@ -108,12 +111,39 @@ func (s *InventoryService) AdjustStock(ctx context.Context, productID, tenantID,
 
 	utils.Log.Info("Stock adjusted successfully: product_id=%s, previous=%d, new=%d, delta=%d",
 		productID, previousQuantity, newQuantity, newQuantity-previousQuantity)
+	s.publishAdjustmentAudit(adjustment, product.Name)
 
 	// Return updated product
 	product.StockQuantity = newQuantity
 	return product, nil
 }
 
+// publishAdjustmentAudit records the adjustment in the audit trail under the staff member who made it
+func (s *InventoryService) publishAdjustmentAudit(adjustment *models.StockAdjustment, productName string) {
+	if s.auditPublisher == nil {
+		return
+	}
+
+	actorID := adjustment.UserID.String()
+	auditEvent := utils.NewSystemEvent(adjustment.TenantID.String(), "UPDATE", "product_stock", adjustment.ProductID.String())
+	auditEvent.ActorType = "user"
+	auditEvent.ActorID = &actorID
+	auditEvent.BeforeValue = map[string]interface{}{"stock_quantity": adjustment.PreviousQuantity}
+	auditEvent.AfterValue = map[string]interface{}{"stock_quantity": adjustment.NewQuantity}
+	auditEvent.Metadata = map[string]interface{}{
+		"reason":       adjustment.Reason,
+		"product_name": productName,
+		"delta":        adjustment.NewQuantity - adjustment.PreviousQuantity,
+	}
+
+	auditCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.auditPublisher.Publish(auditCtx, auditEvent); err != nil {
+		log.Warn().Err(err).Str("product_id", adjustment.ProductID.String()).Msg("Failed to publish stock adjustment audit event")
+	}
+}
+
 // GetAdjustmentHistory retrieves stock adjustment history for a product
 func (s *InventoryService) GetAdjustmentHistory(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*models.StockAdjustment, int, error) {
 	return s.stockRepo.GetAdjustmentHistory(ctx, productID, limit, offset)
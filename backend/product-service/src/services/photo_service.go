@@ -70,7 +70,7 @@ func (s *PhotoService) UploadPhoto(
 	}
 
 	if quota.StorageUsedBytes+metadata.Size > quota.StorageQuotaBytes {
-		return nil, models.ErrQuotaExceeded
+		return nil, s.quotaExceeded(ctx, quota, quota.StorageUsedBytes+metadata.Size)
 	}
 
 	// 4. Optimize image (currently a pass-through)
@@ -96,12 +96,16 @@ func (s *PhotoService) UploadPhoto(
 		return nil, fmt.Errorf("failed to upload photo to storage: %w", err)
 	}
 
+	// 6b. Generate and upload AVIF/WebP/JPEG renditions (best effort)
+	renditions := s.storeRenditions(ctx, storageKey, optimizedData, metadata.MimeType)
+
 	// 7. If this should be primary, clear existing primary photo
 	if isPrimary {
 		err = s.photoRepo.ClearPrimaryPhoto(ctx, productID, tenantID)
 		if err != nil {
 			// Try to cleanup uploaded photo
 			_ = s.storageService.DeletePhoto(ctx, storageKey)
+			s.discardRenditions(ctx, renditions)
 			return nil, fmt.Errorf("failed to clear existing primary photo: %w", err)
 		}
 	}
@@ -119,12 +123,14 @@ func (s *PhotoService) UploadPhoto(
 		HeightPx:         &metadata.Height,
 		DisplayOrder:     displayOrder,
 		IsPrimary:        isPrimary,
+		Renditions:       renditions,
 	}
 
 	err = s.photoRepo.Create(ctx, photo)
 	if err != nil {
 		// Cleanup: Delete uploaded photo from storage
 		_ = s.storageService.DeletePhoto(ctx, storageKey)
+		s.discardRenditions(ctx, renditions)
 		return nil, fmt.Errorf("failed to save photo metadata: %w", err)
 	}
 
@@ -151,6 +157,7 @@ func (s *PhotoService) UploadPhoto(
 	} else {
 		photo.PhotoURL = photoURL
 	}
+	s.attachFormatURLs(ctx, photo)
 
 	// Audit log: successful photo upload
 	log.Info().
@@ -159,6 +166,7 @@ func (s *PhotoService) UploadPhoto(
 		Str("photo_id", photoID.String()).
 		Str("filename", sanitizedFilename).
 		Int("file_size", int(metadata.Size)).
+		Int("renditions", len(renditions)).
 		Bool("is_primary", isPrimary).
 		Msg("Photo uploaded successfully")
 
@@ -186,6 +194,7 @@ func (s *PhotoService) ListPhotos(ctx context.Context, productID, tenantID uuid.
 		} else {
 			photo.PhotoURL = url
 		}
+		s.attachFormatURLs(ctx, photo)
 	}
 
 	return photos, nil
@@ -210,6 +219,7 @@ func (s *PhotoService) GetPhoto(ctx context.Context, photoID, tenantID uuid.UUID
 	} else {
 		photo.PhotoURL = url
 	}
+	s.attachFormatURLs(ctx, photo)
 
 	return photo, nil
 }
@@ -288,6 +298,7 @@ func (s *PhotoService) DeletePhoto(ctx context.Context, photoID, tenantID uuid.U
 			Str("storage_key", photo.StorageKey).
 			Msg("Photo deleted from S3 storage successfully")
 	}
+	s.deleteRenditions(ctx, tenantID, photo.Renditions)
 
 	// Update tenant storage usage
 	err = s.photoRepo.UpdateTenantStorageUsage(ctx, tenantID, -int64(photo.FileSizeBytes))
@@ -366,7 +377,7 @@ func (s *PhotoService) ReplacePhoto(
 	// Calculate net storage change (new size - old size)
 	netSizeChange := metadata.Size - int64(existingPhoto.FileSizeBytes)
 	if netSizeChange > 0 && quota.StorageUsedBytes+netSizeChange > quota.StorageQuotaBytes {
-		return nil, models.ErrQuotaExceeded
+		return nil, s.quotaExceeded(ctx, quota, quota.StorageUsedBytes+netSizeChange)
 	}
 
 	// 4. Optimize image
@@ -391,6 +402,9 @@ func (s *PhotoService) ReplacePhoto(
 		return nil, fmt.Errorf("failed to upload replacement photo to storage: %w", err)
 	}
 
+	// 6b. Generate and upload renditions of the new photo
+	renditions := s.storeRenditions(ctx, storageKey, optimizedData, metadata.MimeType)
+
 	// 7. Delete old photo from storage (best effort)
 	if existingPhoto.StorageKey != storageKey {
 		err = s.storageService.DeletePhoto(ctx, existingPhoto.StorageKey)
@@ -408,6 +422,13 @@ func (s *PhotoService) ReplacePhoto(
 				Msg("Failed to delete old photo from storage after replacement, enqueued for retry")
 		}
 	}
+	staleRenditions := models.PhotoRenditions{}
+	for format, key := range existingPhoto.Renditions {
+		if renditions[format] != key {
+			staleRenditions[format] = key
+		}
+	}
+	s.deleteRenditions(ctx, tenantID, staleRenditions)
 
 	// 8. Update database record with new metadata
 	updatedPhoto := &models.ProductPhoto{
@@ -422,12 +443,14 @@ func (s *PhotoService) ReplacePhoto(
 		HeightPx:         &metadata.Height,
 		DisplayOrder:     existingPhoto.DisplayOrder, // Keep existing order
 		IsPrimary:        existingPhoto.IsPrimary,    // Keep existing primary status
+		Renditions:       renditions,
 	}
 
 	err = s.photoRepo.Update(ctx, updatedPhoto)
 	if err != nil {
 		// Cleanup: Try to delete newly uploaded photo
 		_ = s.storageService.DeletePhoto(ctx, storageKey)
+		s.discardRenditions(ctx, renditions)
 		return nil, fmt.Errorf("failed to update photo metadata: %w", err)
 	}
 
@@ -454,6 +477,7 @@ func (s *PhotoService) ReplacePhoto(
 	} else {
 		updatedPhoto.PhotoURL = photoURL
 	}
+	s.attachFormatURLs(ctx, updatedPhoto)
 
 	// Audit log: successful photo replacement
 	log.Info().
@@ -470,12 +494,12 @@ func (s *PhotoService) ReplacePhoto(
 	return updatedPhoto, nil
 }
 
-// DeleteAllTenantPhotos deletes all photos for a tenant (cascade delete)
-func (s *PhotoService) DeleteAllTenantPhotos(ctx context.Context, tenantID uuid.UUID) error {
+// DeleteAllTenantPhotos deletes all photos for a tenant (cascade delete) and returns how many were deleted
+func (s *PhotoService) DeleteAllTenantPhotos(ctx context.Context, tenantID uuid.UUID) (int64, error) {
 	// 1. List all photos for the tenant
 	photos, err := s.photoRepo.ListByTenant(ctx, tenantID)
 	if err != nil {
-		return fmt.Errorf("failed to list tenant photos: %w", err)
+		return 0, fmt.Errorf("failed to list tenant photos: %w", err)
 	}
 
 	// 2. Delete each photo from S3 (continue on error to cleanup as much as possible)
@@ -499,12 +523,13 @@ func (s *PhotoService) DeleteAllTenantPhotos(ctx context.Context, tenantID uuid.
 		} else {
 			deletedCount++
 		}
+		s.deleteRenditions(ctx, tenantID, photo.Renditions)
 	}
 
 	// 3. Delete all photos from database
-	err = s.photoRepo.DeleteAllByTenant(ctx, tenantID)
+	deletedRows, err := s.photoRepo.DeleteAllByTenant(ctx, tenantID)
 	if err != nil {
-		return fmt.Errorf("failed to delete tenant photos from database: %w", err)
+		return 0, fmt.Errorf("failed to delete tenant photos from database: %w", err)
 	}
 
 	// 4. Audit log for tenant cascade delete
@@ -520,10 +545,125 @@ func (s *PhotoService) DeleteAllTenantPhotos(ctx context.Context, tenantID uuid.
 
 	logEvent.Msg("Tenant photos cascade delete completed")
 
-	return nil
+	return deletedRows, nil
+}
+
+// quotaExceeded returns the error for an upload that would need more storage than the tenant has
+// A quota set by the tenant's plan is reported as a plan limit, pointing at the plan to upgrade to.
+func (s *PhotoService) quotaExceeded(ctx context.Context, quota *models.StorageQuotaResponse, needed int64) error {
+	if !quota.PlanLimited {
+		return models.ErrQuotaExceeded
+	}
+
+	upgrade, err := s.photoRepo.FindStorageUpgradePlan(ctx, needed)
+	if err != nil {
+		log.Warn().Err(err).Str("tenant_id", quota.TenantID.String()).Msg("Failed to find storage upgrade plan")
+	}
+	return &models.PlanLimitError{
+		Limit:       models.PlanLimitStorageBytes,
+		Max:         quota.StorageQuotaBytes,
+		PlanName:    quota.PlanName,
+		UpgradePlan: upgrade,
+	}
 }
 
 // GetStorageQuota retrieves storage quota information for a tenant
 func (s *PhotoService) GetStorageQuota(ctx context.Context, tenantID uuid.UUID) (*models.StorageQuotaResponse, error) {
 	return s.photoRepo.GetTenantStorageQuota(ctx, tenantID)
 }
+
+// ResolvePublicPhotoURL returns a presigned URL for the product's primary photo
+// (or its first photo) in the format negotiated from the Accept header
+func (s *PhotoService) ResolvePublicPhotoURL(ctx context.Context, productID, tenantID uuid.UUID, accept string) (string, error) {
+	photos, err := s.photoRepo.GetByProduct(ctx, productID, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list photos: %w", err)
+	}
+	if len(photos) == 0 {
+		return "", models.ErrPhotoNotFound
+	}
+
+	photo := photos[0]
+	for _, candidate := range photos {
+		if candidate.IsPrimary {
+			photo = candidate
+			break
+		}
+	}
+
+	storageKey := photo.StorageKey
+	if format := models.NegotiatePhotoFormat(accept, photo.Renditions); format != "" {
+		storageKey = photo.Renditions[format]
+	}
+
+	return s.storageService.GetPhotoURL(ctx, storageKey)
+}
+
+// storeRenditions generates and uploads the renditions of a photo.
+// Renditions are best effort: a format that fails to encode or upload is left
+// out, and clients negotiate down to the next format or the original.
+func (s *PhotoService) storeRenditions(ctx context.Context, storageKey string, imageData []byte, mimeType string) models.PhotoRenditions {
+	renditions := models.PhotoRenditions{}
+
+	for _, rendition := range s.imageProcessor.GenerateRenditions(ctx, imageData, mimeType) {
+		key := RenditionStorageKey(storageKey, rendition.Format)
+		err := s.storageService.UploadPhoto(
+			ctx,
+			key,
+			bytes.NewReader(rendition.Data),
+			int64(len(rendition.Data)),
+			rendition.MimeType,
+		)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("storage_key", key).
+				Str("format", rendition.Format).
+				Msg("Failed to upload photo rendition")
+			continue
+		}
+		renditions[rendition.Format] = key
+	}
+
+	return renditions
+}
+
+// discardRenditions removes renditions uploaded for a photo that was never saved
+func (s *PhotoService) discardRenditions(ctx context.Context, renditions models.PhotoRenditions) {
+	for _, key := range renditions.Keys() {
+		_ = s.storageService.DeletePhoto(ctx, key)
+	}
+}
+
+// deleteRenditions removes rendition objects, enqueueing failed deletions for retry
+func (s *PhotoService) deleteRenditions(ctx context.Context, tenantID uuid.UUID, renditions models.PhotoRenditions) {
+	for _, key := range renditions.Keys() {
+		if err := s.storageService.DeletePhoto(ctx, key); err != nil {
+			if s.retryQueue != nil {
+				s.retryQueue.Enqueue(tenantID.String(), key, 5)
+			}
+
+			log.Error().
+				Err(err).
+				Str("tenant_id", tenantID.String()).
+				Str("storage_key", key).
+				Msg("Failed to delete photo rendition from S3 storage, enqueued for retry")
+		}
+	}
+}
+
+// attachFormatURLs sets a presigned URL for each rendition of the photo
+func (s *PhotoService) attachFormatURLs(ctx context.Context, photo *models.ProductPhoto) {
+	if len(photo.Renditions) == 0 {
+		return
+	}
+
+	photo.Formats = make(map[string]string, len(photo.Renditions))
+	for format, key := range photo.Renditions {
+		url, err := s.storageService.GetPhotoURL(ctx, key)
+		if err != nil {
+			continue
+		}
+		photo.Formats[format] = url
+	}
+}
@@ -50,6 +50,10 @@ func (s *ProductService) CreateProduct(ctx context.Context, product *models.Prod
 		}
 	}
 
+	if err := s.checkProductLimit(ctx, product.TenantID); err != nil {
+		return err
+	}
+
 	if err := s.repo.Create(ctx, product); err != nil {
 		utils.Log.Error("Failed to create product: %v", err)
 		return err
@@ -59,6 +63,31 @@ func (s *ProductService) CreateProduct(ctx context.Context, product *models.Prod
 	return nil
 }
 
+// checkProductLimit returns a *models.PlanLimitError when the tenant's plan allows no more products
+// Archived products do not count.
+func (s *ProductService) checkProductLimit(ctx context.Context, tenantID uuid.UUID) error {
+	planName, max, used, err := s.repo.ProductPlanUsage(ctx, tenantID)
+	if err != nil {
+		utils.Log.Error("Failed to check product limit: %v", err)
+		return err
+	}
+	if max == nil || used < *max {
+		return nil
+	}
+
+	upgrade, err := s.repo.FindProductUpgradePlan(ctx, used+1)
+	if err != nil {
+		utils.Log.Warn("Failed to find product upgrade plan: %v", err)
+	}
+	utils.Log.Warn("Product limit reached: tenant=%s, plan=%s, max=%d", tenantID, planName, *max)
+	return &models.PlanLimitError{
+		Limit:       models.PlanLimitProducts,
+		Max:         *max,
+		PlanName:    planName,
+		UpgradePlan: upgrade,
+	}
+}
+
 func (s *ProductService) GetProduct(ctx context.Context, tenantID uuid.UUID, id uuid.UUID) (*models.Product, error) {
 	return s.repo.FindByID(ctx, tenantID, id)
 }
@@ -150,6 +179,11 @@ func (s *ProductService) ArchiveProduct(ctx context.Context, tenantID uuid.UUID,
 func (s *ProductService) RestoreProduct(ctx context.Context, tenantID uuid.UUID, id uuid.UUID) error {
 	utils.Log.Info("Restoring product: id=%s", id)
 
+	// A restored product counts towards the plan's products again
+	if err := s.checkProductLimit(ctx, tenantID); err != nil {
+		return err
+	}
+
 	if err := s.repo.Restore(ctx, tenantID, id); err != nil {
 		utils.Log.Error("Failed to restore product: id=%s, error=%v", id, err)
 		return err
@@ -164,6 +164,16 @@ func GenerateStorageKey(tenantID, productID, photoID uuid.UUID, filename string)
 	return fmt.Sprintf("photos/%s/%s/%s_%d%s", tenantID, productID, photoID, timestamp, ext)
 }
 
+// RenditionStorageKey derives the storage key of a photo rendition from the original's key
+// Format: photos/{tenant_id}/{product_id}/{photo_id}_{timestamp}_{format}.{ext}
+func RenditionStorageKey(storageKey, format string) string {
+	ext := format
+	if format == "jpeg" {
+		ext = "jpg"
+	}
+	return fmt.Sprintf("%s_%s.%s", strings.TrimSuffix(storageKey, filepath.Ext(storageKey)), format, ext)
+}
+
 // SanitizeFilename removes potentially dangerous characters from filenames
 func SanitizeFilename(filename string) string {
 	// Remove path traversal attempts
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/backend/product-service/src/queue"
+	"github.com/pos/backend/product-service/src/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// tenantDeletionParticipant is the name tenant-service asks this service by in deletion requests
+const tenantDeletionParticipant = "product-service"
+
+// TenantEvent is a message on the tenant-events topic
+type TenantEvent struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	TenantID  string          `json:"tenant_id"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// TenantDeletionRequest is the data of a tenant.deletion.requested event
+type TenantDeletionRequest struct {
+	DeletionID string   `json:"deletion_id"`
+	Services   []string `json:"services"`
+}
+
+// TenantEventHandler takes part in the tenant deletion saga run by tenant-service
+// It deletes the tenant's product photos, from object storage as well as the database, and its stock
+// adjustment history, then acknowledges with the counts. Products and categories go with the tenant row.
+type TenantEventHandler struct {
+	photoService *PhotoService
+	stockRepo    *repository.StockRepository
+	tenantEvents *queue.KafkaProducer
+}
+
+func NewTenantEventHandler(photoService *PhotoService, stockRepo *repository.StockRepository, tenantEvents *queue.KafkaProducer) *TenantEventHandler {
+	return &TenantEventHandler{
+		photoService: photoService,
+		stockRepo:    stockRepo,
+		tenantEvents: tenantEvents,
+	}
+}
+
+// Handle processes one message from the tenant-events topic; other event types are ignored
+func (h *TenantEventHandler) Handle(ctx context.Context, payload []byte) error {
+	var event TenantEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Error().Err(err).Msg("Discarding malformed tenant event")
+		return nil
+	}
+	if event.EventType != "tenant.deletion.requested" {
+		return nil
+	}
+	var req TenantDeletionRequest
+	if err := json.Unmarshal(event.Data, &req); err != nil || !req.includes(tenantDeletionParticipant) {
+		return nil
+	}
+	tenantID, err := uuid.Parse(event.TenantID)
+	if err != nil {
+		log.Error().Str("tenant_id", event.TenantID).Msg("Discarding tenant deletion request with an invalid tenant ID")
+		return nil
+	}
+
+	deleted, err := h.deleteTenantData(ctx, tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", event.TenantID).Msg("Failed to delete tenant data")
+	}
+	return h.acknowledge(ctx, event.TenantID, req.DeletionID, deleted, err)
+}
+
+// deleteTenantData is safe to repeat; a retried request finds less, or nothing, to delete
+func (h *TenantEventHandler) deleteTenantData(ctx context.Context, tenantID uuid.UUID) (map[string]int64, error) {
+	deleted := map[string]int64{}
+
+	photos, err := h.photoService.DeleteAllTenantPhotos(ctx, tenantID)
+	if err != nil {
+		return deleted, err
+	}
+	deleted["product_photos"] = photos
+
+	adjustments, err := h.stockRepo.DeleteAllByTenant(ctx, tenantID)
+	if err != nil {
+		return deleted, err
+	}
+	deleted["stock_adjustments"] = adjustments
+
+	return deleted, nil
+}
+
+func (h *TenantEventHandler) acknowledge(ctx context.Context, tenantID, deletionID string, deleted map[string]int64, cause error) error {
+	data := map[string]interface{}{
+		"deletion_id": deletionID,
+		"service":     tenantDeletionParticipant,
+		"status":      "completed",
+		"deleted":     deleted,
+	}
+	if cause != nil {
+		data["status"] = "failed"
+		data["error"] = cause.Error()
+	}
+
+	ack := map[string]interface{}{
+		"event_id":   uuid.New().String(),
+		"event_type": "tenant.deletion.acknowledged",
+		"tenant_id":  tenantID,
+		"data":       data,
+		"timestamp":  time.Now(),
+	}
+	if err := h.tenantEvents.Publish(ctx, tenantID, ack); err != nil {
+		return fmt.Errorf("failed to acknowledge tenant deletion %s: %w", deletionID, err)
+	}
+	return nil
+}
+
+func (r *TenantDeletionRequest) includes(service string) bool {
+	for _, s := range r.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
@@ -149,7 +149,7 @@ func TestStockAdjustmentWorkflow(t *testing.T) {
 			mockRepo := new(MockRepoForStockAdjustment)
 			tt.mockSetup(mockRepo)
 
-			service := services.NewInventoryService(mockRepo, nil, nil)
+			service := services.NewInventoryService(mockRepo, nil, nil, nil)
 			handler := api.NewStockHandler(nil, service)
 
 			jsonBody, _ := json.Marshal(tt.requestBody)
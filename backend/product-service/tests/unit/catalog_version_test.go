@@ -0,0 +1,133 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/backend/product-service/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func catalogStringPtr(s string) *string      { return &s }
+func catalogFloatPtr(f float64) *float64     { return &f }
+func catalogBoolPtr(b bool) *bool            { return &b }
+func catalogUUIDPtr(id uuid.UUID) *uuid.UUID { return &id }
+
+func TestCatalogChangeApply(t *testing.T) {
+	now := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	photoID := uuid.New()
+	current := models.CatalogProductState{
+		Name:           "Iced Latte",
+		Description:    catalogStringPtr("Espresso and milk"),
+		SellingPrice:   28000,
+		PrimaryPhotoID: &photoID,
+	}
+
+	t.Run("should change only the staged fields", func(t *testing.T) {
+		change := models.CatalogChange{
+			Name:         catalogStringPtr("Pumpkin Spice Latte"),
+			SellingPrice: catalogFloatPtr(35000),
+		}
+
+		next := change.Apply(current, now)
+
+		assert.Equal(t, "Pumpkin Spice Latte", next.Name)
+		assert.Equal(t, 35000.0, next.SellingPrice)
+		assert.Equal(t, "Espresso and milk", *next.Description)
+		assert.Equal(t, photoID, *next.PrimaryPhotoID)
+		assert.Nil(t, next.ArchivedAt)
+		assert.Equal(t, "Iced Latte", current.Name, "current state must not be modified")
+	})
+
+	t.Run("should switch the primary photo", func(t *testing.T) {
+		seasonalPhoto := uuid.New()
+		change := models.CatalogChange{PrimaryPhotoID: &seasonalPhoto}
+
+		next := change.Apply(current, now)
+
+		assert.Equal(t, seasonalPhoto, *next.PrimaryPhotoID)
+	})
+
+	t.Run("should archive and restore products", func(t *testing.T) {
+		archive := models.CatalogChange{Archived: catalogBoolPtr(true)}
+		archived := archive.Apply(current, now)
+		require.NotNil(t, archived.ArchivedAt)
+		assert.Equal(t, now, *archived.ArchivedAt)
+
+		earlier := now.Add(-48 * time.Hour)
+		alreadyArchived := current
+		alreadyArchived.ArchivedAt = &earlier
+		assert.Equal(t, earlier, *archive.Apply(alreadyArchived, now).ArchivedAt, "keeps the original archive time")
+
+		restore := models.CatalogChange{Archived: catalogBoolPtr(false)}
+		assert.Nil(t, restore.Apply(alreadyArchived, now).ArchivedAt)
+	})
+}
+
+func TestCatalogChangeRequestValidate(t *testing.T) {
+	productID := uuid.New()
+
+	valid := &models.CatalogChangeRequest{ProductID: productID, Name: catalogStringPtr("  Winter Soup  ")}
+	require.NoError(t, valid.Validate())
+	assert.Equal(t, "Winter Soup", *valid.Name)
+
+	assert.NoError(t, (&models.CatalogChangeRequest{ProductID: productID, Archived: catalogBoolPtr(true)}).Validate())
+	assert.NoError(t, (&models.CatalogChangeRequest{ProductID: productID, PrimaryPhotoID: catalogUUIDPtr(uuid.New())}).Validate())
+
+	assert.Equal(t, models.ErrInvalidProductID, (&models.CatalogChangeRequest{Name: catalogStringPtr("Soup")}).Validate())
+	assert.Equal(t, models.ErrEmptyCatalogChange, (&models.CatalogChangeRequest{ProductID: productID}).Validate())
+	assert.Equal(t, models.ErrInvalidCatalogChangeName, (&models.CatalogChangeRequest{ProductID: productID, Name: catalogStringPtr("  ")}).Validate())
+	assert.Equal(t, models.ErrInvalidCatalogChangePrice, (&models.CatalogChangeRequest{ProductID: productID, SellingPrice: catalogFloatPtr(-1)}).Validate())
+}
+
+func TestCatalogVersionRequests(t *testing.T) {
+	now := time.Now()
+
+	assert.NoError(t, (&models.CreateCatalogVersionRequest{Name: "Ramadan menu"}).Validate())
+	assert.Equal(t, models.ErrInvalidCatalogVersionName, (&models.CreateCatalogVersionRequest{Name: " "}).Validate())
+
+	assert.NoError(t, (&models.ScheduleCatalogVersionRequest{PublishAt: now.Add(time.Hour)}).Validate(now))
+	assert.Equal(t, models.ErrInvalidCatalogPublishAt, (&models.ScheduleCatalogVersionRequest{PublishAt: now}).Validate(now))
+	assert.Equal(t, models.ErrInvalidCatalogPublishAt, (&models.ScheduleCatalogVersionRequest{}).Validate(now))
+}
+
+func TestCatalogVersionIsEditable(t *testing.T) {
+	editable := map[models.CatalogVersionStatus]bool{
+		models.CatalogVersionDraft:      true,
+		models.CatalogVersionScheduled:  true,
+		models.CatalogVersionFailed:     true,
+		models.CatalogVersionPublished:  false,
+		models.CatalogVersionRolledBack: false,
+		models.CatalogVersionCancelled:  false,
+	}
+	for status, want := range editable {
+		version := models.CatalogVersion{Status: status}
+		assert.Equal(t, want, version.IsEditable(), string(status))
+	}
+}
+
+func TestCatalogProductStateJSONB(t *testing.T) {
+	archivedAt := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	categoryID := uuid.New()
+	state := models.CatalogProductState{
+		Name:         "Mango Sticky Rice",
+		CategoryID:   &categoryID,
+		SellingPrice: 25000.5,
+		ArchivedAt:   &archivedAt,
+	}
+
+	value, err := state.Value()
+	require.NoError(t, err)
+
+	var scanned models.CatalogProductState
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, state.Name, scanned.Name)
+	assert.Equal(t, categoryID, *scanned.CategoryID)
+	assert.Equal(t, state.SellingPrice, scanned.SellingPrice)
+	assert.True(t, archivedAt.Equal(*scanned.ArchivedAt))
+	assert.Nil(t, scanned.PrimaryPhotoID)
+
+	assert.Error(t, scanned.Scan(42))
+}
@@ -153,7 +153,7 @@ func TestInventoryServiceAdjustStock(t *testing.T) {
 			mockStockRepo := new(MockStockRepository)
 			tt.mockSetup(mockProductRepo, mockStockRepo)
 
-			service := services.NewInventoryService(mockProductRepo, mockStockRepo, nil)
+			service := services.NewInventoryService(mockProductRepo, mockStockRepo, nil, nil)
 
 			_, err := service.AdjustStock(ctx, productID, tenantID, userID, tt.newQuantity, tt.reason, tt.notes)
 
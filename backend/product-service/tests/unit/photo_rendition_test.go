@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/pos/backend/product-service/src/models"
+	"github.com/pos/backend/product-service/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiatePhotoFormat(t *testing.T) {
+	all := models.PhotoRenditions{
+		models.PhotoFormatAVIF: "photos/a.avif",
+		models.PhotoFormatWebP: "photos/a.webp",
+		models.PhotoFormatJPEG: "photos/a.jpg",
+	}
+	withoutAVIF := models.PhotoRenditions{
+		models.PhotoFormatWebP: "photos/a.webp",
+		models.PhotoFormatJPEG: "photos/a.jpg",
+	}
+
+	tests := []struct {
+		name       string
+		accept     string
+		renditions models.PhotoRenditions
+		want       string
+	}{
+		{"modern browser gets avif", "image/avif,image/webp,image/apng,image/*,*/*;q=0.8", all, models.PhotoFormatAVIF},
+		{"webp-only browser gets webp", "image/webp,*/*", all, models.PhotoFormatWebP},
+		{"avif refused explicitly", "image/avif;q=0,image/webp;q=0.9", all, models.PhotoFormatWebP},
+		{"wildcard alone gets jpeg", "image/*,*/*;q=0.8", all, models.PhotoFormatJPEG},
+		{"missing header gets jpeg", "", all, models.PhotoFormatJPEG},
+		{"avif client falls back to webp when avif was not generated", "image/avif,image/webp", withoutAVIF, models.PhotoFormatWebP},
+		{"legacy client gets jpeg", "image/jpeg", all, models.PhotoFormatJPEG},
+		{"unmatched accept still gets jpeg", "image/png", all, models.PhotoFormatJPEG},
+		{"no renditions serves the original", "image/avif", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.NegotiatePhotoFormat(tt.accept, tt.renditions))
+		})
+	}
+}
+
+func TestRenditionStorageKey(t *testing.T) {
+	key := "photos/tenant/product/photo_1700000000.png"
+
+	assert.Equal(t, "photos/tenant/product/photo_1700000000_avif.avif", services.RenditionStorageKey(key, models.PhotoFormatAVIF))
+	assert.Equal(t, "photos/tenant/product/photo_1700000000_webp.webp", services.RenditionStorageKey(key, models.PhotoFormatWebP))
+	assert.Equal(t, "photos/tenant/product/photo_1700000000_jpeg.jpg", services.RenditionStorageKey(key, models.PhotoFormatJPEG))
+	assert.NotEqual(t, "photos/tenant/product/photo_1700000000.jpg",
+		services.RenditionStorageKey("photos/tenant/product/photo_1700000000.jpg", models.PhotoFormatJPEG),
+		"a JPEG rendition must not overwrite a JPEG original")
+}
+
+func TestPhotoRenditionsJSONB(t *testing.T) {
+	renditions := models.PhotoRenditions{models.PhotoFormatWebP: "photos/a.webp"}
+
+	value, err := renditions.Value()
+	require.NoError(t, err)
+
+	var scanned models.PhotoRenditions
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, renditions, scanned)
+
+	empty, err := models.PhotoRenditions(nil).Value()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("{}"), empty)
+}
+
+func TestGenerateRenditions(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 48; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 5), B: 120, A: 255})
+		}
+	}
+	processor := services.NewImageProcessor(10<<20, 4096, 4096).WithEncoders(services.NewJPEGEncoder())
+
+	t.Run("should encode the configured formats", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+
+		renditions := processor.GenerateRenditions(context.Background(), buf.Bytes(), "image/png")
+		require.Len(t, renditions, 1)
+		assert.Equal(t, models.PhotoFormatJPEG, renditions[0].Format)
+		assert.Equal(t, "image/jpeg", renditions[0].MimeType)
+
+		decoded, err := jpeg.Decode(bytes.NewReader(renditions[0].Data))
+		require.NoError(t, err)
+		assert.Equal(t, img.Bounds(), decoded.Bounds())
+	})
+
+	t.Run("should skip gifs to keep animation", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, gif.Encode(&buf, img, nil))
+
+		assert.Empty(t, processor.GenerateRenditions(context.Background(), buf.Bytes(), "image/gif"))
+	})
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/services"
+)
+
+type OutletHandler struct {
+	outletService *services.OutletService
+}
+
+func NewOutletHandler(outletService *services.OutletService) *OutletHandler {
+	return &OutletHandler{
+		outletService: outletService,
+	}
+}
+
+// ListOutlets handles GET /admin/tenants/:tenant_id/outlets
+func (h *OutletHandler) ListOutlets(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	outlets, err := h.outletService.ListOutlets(c.Request().Context(), tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list outlets")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve outlets",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"outlets": outlets,
+	})
+}
+
+// GetOutlet handles GET /admin/tenants/:tenant_id/outlets/:outlet_id
+func (h *OutletHandler) GetOutlet(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	outlet, err := h.outletService.GetOutlet(c.Request().Context(), tenantID, c.Param("outlet_id"))
+	if err != nil {
+		return outletError(c, tenantID, err, "Failed to retrieve outlet")
+	}
+
+	return c.JSON(http.StatusOK, outlet)
+}
+
+// CreateOutlet handles POST /admin/tenants/:tenant_id/outlets
+func (h *OutletHandler) CreateOutlet(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	var req models.OutletRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	outlet, err := h.outletService.CreateOutlet(c.Request().Context(), tenantID, &req)
+	if err != nil {
+		return outletError(c, tenantID, err, "Failed to create outlet")
+	}
+
+	return c.JSON(http.StatusCreated, outlet)
+}
+
+// UpdateOutlet handles PUT /admin/tenants/:tenant_id/outlets/:outlet_id
+func (h *OutletHandler) UpdateOutlet(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	var req models.OutletRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	outlet, err := h.outletService.UpdateOutlet(c.Request().Context(), tenantID, c.Param("outlet_id"), &req)
+	if err != nil {
+		return outletError(c, tenantID, err, "Failed to update outlet")
+	}
+
+	return c.JSON(http.StatusOK, outlet)
+}
+
+// DeleteOutlet handles DELETE /admin/tenants/:tenant_id/outlets/:outlet_id
+func (h *OutletHandler) DeleteOutlet(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	if err := h.outletService.DeleteOutlet(c.Request().Context(), tenantID, c.Param("outlet_id")); err != nil {
+		return outletError(c, tenantID, err, "Failed to delete outlet")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func outletError(c echo.Context, tenantID string, err error, message string) error {
+	var limitErr *models.PlanLimitError
+	if errors.As(err, &limitErr) {
+		return c.JSON(http.StatusForbidden, map[string]interface{}{
+			"error":        limitErr.Error(),
+			"code":         "plan_limit_exceeded",
+			"limit":        limitErr.Limit,
+			"max":          limitErr.Max,
+			"upgrade_plan": limitErr.UpgradePlan,
+			"upgrade_hint": limitErr.UpgradeHint(),
+		})
+	}
+
+	switch {
+	case errors.Is(err, models.ErrInvalidOutlet):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	case errors.Is(err, models.ErrOutletNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Outlet not found",
+		})
+	case errors.Is(err, models.ErrOutletNameTaken), errors.Is(err, models.ErrOutletInUse):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	log.Error().Err(err).Str("tenant_id", tenantID).Msg(message)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -15,13 +16,17 @@ type RegisterHandler struct {
 	tenantService  *services.TenantService
 	db             *sql.DB
 	eventPublisher *queue.EventPublisher
+	passwordPolicy *PasswordPolicy
+	captcha        *CaptchaVerifier
 }
 
-func NewRegisterHandler(db *sql.DB, eventPublisher *queue.EventPublisher) *RegisterHandler {
+func NewRegisterHandler(db *sql.DB, eventPublisher *queue.EventPublisher, passwordPolicy *PasswordPolicy, captcha *CaptchaVerifier) *RegisterHandler {
 	return &RegisterHandler{
 		tenantService:  services.NewTenantService(db, eventPublisher),
 		db:             db,
 		eventPublisher: eventPublisher,
+		passwordPolicy: passwordPolicy,
+		captcha:        captcha,
 	}
 }
 
@@ -37,6 +42,27 @@ func (h *RegisterHandler) Register(c echo.Context) error {
 		})
 	}
 
+	// Checked first so bots are turned away before any lookups or the breached password check
+	if err := h.captcha.Verify(c.Request().Context(), req.CaptchaToken, c.RealIP()); err != nil {
+		switch {
+		case errors.Is(err, ErrCaptchaRequired):
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":           GetLocalizedMessage(locale, "captcha.required"),
+				"captchaRequired": true,
+			})
+		case errors.Is(err, ErrCaptchaInvalid):
+			c.Logger().Warnf("Registration CAPTCHA rejected from ip=%s: %v", c.RealIP(), err)
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"error":           GetLocalizedMessage(locale, "captcha.invalid"),
+				"captchaRequired": true,
+			})
+		}
+		c.Logger().Errorf("Registration CAPTCHA verification unavailable: %v", err)
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": GetLocalizedMessage(locale, "captcha.unavailable"),
+		})
+	}
+
 	masker := NewLogMasker()
 
 	// Debug: Log what we received
@@ -59,10 +85,13 @@ func (h *RegisterHandler) Register(c echo.Context) error {
 		})
 	}
 
-	if !services.IsValidPassword(req.Password) {
-		c.Logger().Warn("Password validation failed for registration attempt")
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": GetLocalizedMessage(locale, "validation.passwordRequirements"),
+	if err := h.passwordPolicy.Validate(c.Request().Context(), req.Password); err != nil {
+		policyErr, _ := err.(*PasswordPolicyError)
+		c.Logger().Warnf("Password validation failed for registration attempt: %v", policyErr.Violations)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":      GetLocalizedMessage(locale, "validation.passwordRequirements"),
+			"details":    policyErr.Error(),
+			"violations": policyErr.Violations,
 		})
 	}
 
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/services"
+)
+
+type TenantBrandingHandler struct {
+	brandingService *services.TenantBrandingService
+}
+
+func NewTenantBrandingHandler(brandingService *services.TenantBrandingService) *TenantBrandingHandler {
+	return &TenantBrandingHandler{
+		brandingService: brandingService,
+	}
+}
+
+// GetBranding handles GET /admin/tenants/:tenant_id/branding
+func (h *TenantBrandingHandler) GetBranding(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	branding, err := h.brandingService.GetBranding(c.Request().Context(), tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get tenant branding")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve branding",
+		})
+	}
+
+	return c.JSON(http.StatusOK, branding)
+}
+
+// UpdateBranding handles PATCH /admin/tenants/:tenant_id/branding
+func (h *TenantBrandingHandler) UpdateBranding(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	var req models.UpdateTenantBrandingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	branding, err := h.brandingService.UpdateBranding(c.Request().Context(), tenantID, &req)
+	if errors.Is(err, models.ErrInvalidBranding) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to update tenant branding")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update branding",
+		})
+	}
+
+	return c.JSON(http.StatusOK, branding)
+}
+
+// UploadLogo handles POST /admin/tenants/:tenant_id/branding/logo
+// Expects a multipart "file" field holding a PNG or JPEG image.
+func (h *TenantBrandingHandler) UploadLogo(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "An image file is required",
+		})
+	}
+	if fileHeader.Size > services.MaxLogoSizeBytes {
+		return logoError(c, tenantID, services.ErrLogoTooLarge, "")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to open logo file")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to read image file",
+		})
+	}
+	defer file.Close()
+
+	branding, err := h.brandingService.UploadLogo(c.Request().Context(), tenantID, file)
+	if err != nil {
+		return logoError(c, tenantID, err, "Failed to upload logo")
+	}
+
+	return c.JSON(http.StatusOK, branding)
+}
+
+// DeleteLogo handles DELETE /admin/tenants/:tenant_id/branding/logo
+func (h *TenantBrandingHandler) DeleteLogo(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	if err := h.brandingService.DeleteLogo(c.Request().Context(), tenantID); err != nil {
+		return logoError(c, tenantID, err, "Failed to remove logo")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func logoError(c echo.Context, tenantID string, err error, message string) error {
+	switch err {
+	case services.ErrLogoTooLarge:
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": "Logo must be at most 1 MB",
+		})
+	case services.ErrLogoInvalid:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Logo must be a PNG or JPEG image",
+		})
+	case services.ErrLogoDimensions:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Logo must be at most 2048 pixels wide and high",
+		})
+	case services.ErrLogoUnavailable:
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "Logo uploads are not available",
+		})
+	}
+
+	log.Error().Err(err).Str("tenant_id", tenantID).Msg(message)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog/log"
 
+	"github.com/pos/tenant-service/src/models"
 	"github.com/pos/tenant-service/src/services"
 )
 
@@ -37,6 +39,11 @@ func (h *TenantConfigHandler) GetPublicTenantConfig(c echo.Context) error {
 		})
 	}
 
+	var unavailable *models.TenantUnavailableError
+	if errors.As(err, &unavailable) {
+		return c.JSON(http.StatusForbidden, unavailable.Response())
+	}
+
 	if err != nil {
 		log.Error().Err(err).Str("tenant_slug", tenantSlug).Msg("Failed to get tenant config")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -124,3 +131,107 @@ func (h *TenantConfigHandler) UpdateMidtransConfig(c echo.Context) error {
 		"message": "Midtrans configuration updated successfully",
 	})
 }
+
+// GetPaymentGatewayConfig handles GET /admin/tenants/:tenant_id/payment-gateway-config
+func (h *TenantConfigHandler) GetPaymentGatewayConfig(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	config, err := h.configService.GetPaymentGatewayConfig(c.Request().Context(), tenantID)
+	if err != nil {
+		c.Logger().Errorf("Failed to get payment gateway config for tenant %s: %v", tenantID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve payment gateway configuration",
+		})
+	}
+
+	return c.JSON(http.StatusOK, config)
+}
+
+// UpdatePaymentGatewayConfig handles PATCH /admin/tenants/:tenant_id/payment-gateway-config
+func (h *TenantConfigHandler) UpdatePaymentGatewayConfig(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	var req services.PaymentGatewayConfig
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.TenantID = tenantID
+
+	if err := h.configService.UpdatePaymentGatewayConfig(c.Request().Context(), &req); err != nil {
+		if errors.Is(err, services.ErrInvalidPaymentGatewayConfig) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		c.Logger().Errorf("Failed to update payment gateway config for tenant %s: %v", tenantID, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update payment gateway configuration",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Payment gateway configuration updated successfully",
+	})
+}
+
+// GetDeliveryFeeConfig handles GET /admin/tenants/:tenant_id/delivery-fee-config
+// Also read by the order service when pricing deliveries.
+func (h *TenantConfigHandler) GetDeliveryFeeConfig(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	config, err := h.configService.GetDeliveryFeeConfig(c.Request().Context(), tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to get delivery fee config")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve delivery fee configuration",
+		})
+	}
+
+	return c.JSON(http.StatusOK, config)
+}
+
+// UpdateDeliveryFeeConfig handles PATCH /admin/tenants/:tenant_id/delivery-fee-config
+func (h *TenantConfigHandler) UpdateDeliveryFeeConfig(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	var req models.UpdateDeliveryFeeConfigRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	config, err := h.configService.UpdateDeliveryFeeConfig(c.Request().Context(), tenantID, &req)
+	if errors.Is(err, models.ErrInvalidDeliveryFeeConfig) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to update delivery fee config")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update delivery fee configuration",
+		})
+	}
+
+	return c.JSON(http.StatusOK, config)
+}
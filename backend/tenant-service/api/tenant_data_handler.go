@@ -1,38 +1,25 @@
 package api
 
 import (
-	"database/sql"
 	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/pos/tenant-service/src/repository"
+	"github.com/rs/zerolog/log"
+
 	"github.com/pos/tenant-service/src/services"
-	"github.com/pos/tenant-service/src/utils"
 )
 
 type TenantDataHandler struct {
-	tenantDataService *services.TenantDataService
+	tenantDataService   *services.TenantDataService
+	tenantExportService *services.TenantExportService
 }
 
-func NewTenantDataHandler(db *sql.DB, auditPublisher *utils.AuditPublisher) (*TenantDataHandler, error) {
-	tenantRepo := repository.NewTenantRepository(db)
-	tenantConfigRepo, err := repository.NewTenantConfigRepositoryWithVault(db, auditPublisher)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create tenant config repository: %w", err)
-	}
-
-	// Create Vault encryptor for PII decryption
-	encryptor, err := utils.NewVaultClient()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create vault encryptor: %w", err)
-	}
-
-	tenantDataService := services.NewTenantDataService(tenantRepo, tenantConfigRepo, db, encryptor)
-
+func NewTenantDataHandler(tenantDataService *services.TenantDataService, tenantExportService *services.TenantExportService) *TenantDataHandler {
 	return &TenantDataHandler{
-		tenantDataService: tenantDataService,
-	}, nil
+		tenantDataService:   tenantDataService,
+		tenantExportService: tenantExportService,
+	}
 }
 
 // GetTenantData retrieves all tenant data for UU PDP compliance (Article 3 - right to access)
@@ -94,3 +81,95 @@ func (h *TenantDataHandler) ExportTenantData(c echo.Context) error {
 
 	return c.Blob(http.StatusOK, "application/json", jsonData)
 }
+
+// RequestExport starts an archive of all of the tenant's data: products and their photos, orders,
+// configuration, consent records and an audit summary (UU PDP Article 4 - data portability)
+// POST /api/v1/tenant/data/exports
+// The archive is assembled in the background; poll GetExport for its download link.
+func (h *TenantDataHandler) RequestExport(c echo.Context) error {
+	tenantID, ok := ownerTenant(c)
+	if !ok {
+		return nil
+	}
+
+	export, err := h.tenantExportService.RequestExport(c.Request().Context(), tenantID, c.Request().Header.Get("X-User-ID"))
+	if err != nil {
+		return tenantExportError(c, err, tenantID, "Failed to request tenant data export")
+	}
+	return c.JSON(http.StatusAccepted, export)
+}
+
+// ListExports returns the tenant's data exports, newest first
+// GET /api/v1/tenant/data/exports
+func (h *TenantDataHandler) ListExports(c echo.Context) error {
+	tenantID, ok := ownerTenant(c)
+	if !ok {
+		return nil
+	}
+
+	exports, err := h.tenantExportService.ListExports(c.Request().Context(), tenantID)
+	if err != nil {
+		return tenantExportError(c, err, tenantID, "Failed to list tenant data exports")
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"exports": exports,
+	})
+}
+
+// GetExport returns a data export, with a short-lived download link once it is ready
+// GET /api/v1/tenant/data/exports/:export_id
+func (h *TenantDataHandler) GetExport(c echo.Context) error {
+	tenantID, ok := ownerTenant(c)
+	if !ok {
+		return nil
+	}
+
+	export, err := h.tenantExportService.GetExport(c.Request().Context(), tenantID,
+		c.Request().Header.Get("X-User-ID"), c.Param("export_id"), c.RealIP())
+	if err != nil {
+		return tenantExportError(c, err, tenantID, "Failed to get tenant data export")
+	}
+	return c.JSON(http.StatusOK, export)
+}
+
+// ownerTenant returns the requesting tenant, answering the request itself when it is not the owner's
+func ownerTenant(c echo.Context) (string, bool) {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Missing tenant ID",
+		})
+		return "", false
+	}
+
+	// Verify the requesting user is the tenant owner (set by RBAC middleware in API Gateway)
+	if c.Request().Header.Get("X-User-Role") != "owner" {
+		c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Only tenant owners can export tenant data",
+		})
+		return "", false
+	}
+	return tenantID, true
+}
+
+func tenantExportError(c echo.Context, err error, tenantID, message string) error {
+	switch err {
+	case services.ErrTenantExportInProgress:
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "A tenant data export is already being prepared",
+		})
+	case services.ErrTenantExportNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Tenant data export not found",
+		})
+	case services.ErrTenantExportUnavailable:
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "Tenant data exports are not available",
+		})
+	}
+
+	log.Error().Err(err).Str("tenant_id", tenantID).Msg(message)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/services"
+)
+
+// TenantDeletionHandler serves the platform operator endpoints that delete a tenant scheduled for
+// deletion and follow the deletion across services
+type TenantDeletionHandler struct {
+	deletionService *services.TenantDeletionService
+}
+
+func NewTenantDeletionHandler(deletionService *services.TenantDeletionService) *TenantDeletionHandler {
+	return &TenantDeletionHandler{deletionService: deletionService}
+}
+
+// DeleteTenant handles POST /api/v1/operator/tenants/:tenant_id/delete
+// Deletion runs in the background; 202 is returned with its progress. Calling it again retries the
+// services that have not completed.
+func (h *TenantDeletionHandler) DeleteTenant(c echo.Context) error {
+	operator := c.Get("operator").(*models.PlatformOperator)
+
+	var req models.ChangeTenantStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	deletion, err := h.deletionService.Delete(c.Request().Context(), operator, c.Param("tenant_id"), &req)
+	if err != nil {
+		return h.deletionError(c, err, "Failed to delete tenant")
+	}
+
+	log.Warn().Str("operator_id", operator.ID).Str("tenant_id", deletion.TenantID).
+		Str("deletion_id", deletion.ID).Msg("Tenant deletion requested by platform operator")
+	return c.JSON(http.StatusAccepted, deletion)
+}
+
+// GetDeletion handles GET /api/v1/operator/tenants/:tenant_id/deletion
+// Once completed, the deletion carries the ID of its certificate in the audit trail.
+func (h *TenantDeletionHandler) GetDeletion(c echo.Context) error {
+	deletion, err := h.deletionService.GetDeletion(c.Request().Context(), c.Param("tenant_id"))
+	if err != nil {
+		return h.deletionError(c, err, "Failed to retrieve tenant deletion")
+	}
+
+	return c.JSON(http.StatusOK, deletion)
+}
+
+func (h *TenantDeletionHandler) deletionError(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, models.ErrTenantNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Tenant not found",
+		})
+	case errors.Is(err, models.ErrTenantDeletionNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "No deletion has been requested for this tenant",
+		})
+	case errors.Is(err, models.ErrInvalidStatusReason):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	case errors.Is(err, models.ErrTenantNotPendingDeletion):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	log.Error().Err(err).Str("tenant_id", c.Param("tenant_id")).Msg(message)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/services"
+)
+
+type TenantDomainHandler struct {
+	domainService *services.TenantDomainService
+}
+
+func NewTenantDomainHandler(domainService *services.TenantDomainService) *TenantDomainHandler {
+	return &TenantDomainHandler{
+		domainService: domainService,
+	}
+}
+
+// authorizeTenant ensures the path tenant matches the tenant injected by the API Gateway
+func authorizeTenant(c echo.Context) (string, error) {
+	tenantID := c.Param("tenant_id")
+	if tenantID == "" {
+		return "", c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	if headerTenantID := c.Request().Header.Get("X-Tenant-ID"); headerTenantID != tenantID {
+		return "", c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Access denied to this tenant",
+		})
+	}
+
+	return tenantID, nil
+}
+
+// ListDomains handles GET /admin/tenants/:tenant_id/domains
+func (h *TenantDomainHandler) ListDomains(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	domains, err := h.domainService.ListDomains(c.Request().Context(), tenantID)
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to list tenant domains")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to retrieve domains",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"domains": domains,
+	})
+}
+
+// AddDomain handles POST /admin/tenants/:tenant_id/domains
+func (h *TenantDomainHandler) AddDomain(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	var req models.CreateTenantDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	domain, err := h.domainService.AddDomain(c.Request().Context(), tenantID, &req)
+	if errors.Is(err, models.ErrInvalidDomain) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "domain must be a valid hostname, e.g. shop.example.com",
+		})
+	}
+	if errors.Is(err, models.ErrDomainReserved) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, models.ErrDomainAlreadyExists) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to add tenant domain")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to add domain",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, domain)
+}
+
+// VerifyDomain handles POST /admin/tenants/:tenant_id/domains/:domain_id/verify
+// Looks up the domain's verification TXT record; the domain resolves to the tenant once found
+func (h *TenantDomainHandler) VerifyDomain(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	domain, err := h.domainService.VerifyDomain(c.Request().Context(), tenantID, c.Param("domain_id"))
+	if errors.Is(err, models.ErrDomainNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Domain not found",
+		})
+	}
+	if errors.Is(err, models.ErrDomainNotVerified) {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error":   err.Error(),
+			"message": "Add a TXT record with the verification token, then try again once DNS has updated",
+		})
+	}
+	if errors.Is(err, models.ErrDomainAlreadyExists) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to verify tenant domain")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to verify domain",
+		})
+	}
+
+	return c.JSON(http.StatusOK, domain)
+}
+
+// RemoveDomain handles DELETE /admin/tenants/:tenant_id/domains/:domain_id
+func (h *TenantDomainHandler) RemoveDomain(c echo.Context) error {
+	tenantID, err := authorizeTenant(c)
+	if tenantID == "" {
+		return err
+	}
+
+	err = h.domainService.RemoveDomain(c.Request().Context(), tenantID, c.Param("domain_id"))
+	if errors.Is(err, models.ErrDomainNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Domain not found",
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("tenant_id", tenantID).Msg("Failed to remove tenant domain")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to remove domain",
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResolveDomain handles GET /public/domains/resolve?origin=https://shop.example.com
+// Used by the API Gateway to allow CORS origins and to find the tenant of a storefront host
+// (?host=shop.example.com), and by the proxy before issuing a certificate (?domain=shop.example.com)
+func (h *TenantDomainHandler) ResolveDomain(c echo.Context) error {
+	origin := c.QueryParam("origin")
+	if origin == "" {
+		origin = c.QueryParam("host")
+	}
+	if origin == "" {
+		origin = c.QueryParam("domain")
+	}
+	if origin == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "origin, host or domain is required",
+		})
+	}
+
+	domain, err := h.domainService.ResolveOrigin(c.Request().Context(), origin)
+	if errors.Is(err, models.ErrInvalidDomain) || errors.Is(err, models.ErrDomainNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Domain not registered",
+		})
+	}
+	if err != nil {
+		log.Error().Err(err).Str("origin", origin).Msg("Failed to resolve tenant domain")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to resolve domain",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"domain":      domain.Domain,
+		"tenant_id":   domain.TenantID,
+		"tenant_slug": domain.TenantSlug,
+	})
+}
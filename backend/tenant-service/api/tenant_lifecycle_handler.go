@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/services"
+)
+
+// TenantLifecycleHandler serves the platform operator endpoints that suspend, reactivate and
+// schedule the deletion of tenants, and the status lookup the API Gateway uses to refuse them
+type TenantLifecycleHandler struct {
+	lifecycleService *services.TenantLifecycleService
+}
+
+func NewTenantLifecycleHandler(lifecycleService *services.TenantLifecycleService) *TenantLifecycleHandler {
+	return &TenantLifecycleHandler{lifecycleService: lifecycleService}
+}
+
+// RequireOperator authenticates platform operators by the API key sent as a Bearer token
+func (h *TenantLifecycleHandler) RequireOperator(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		apiKey := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+		operator, err := h.lifecycleService.AuthenticateOperator(c.Request().Context(), apiKey)
+		if errors.Is(err, services.ErrOperatorUnauthorized) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "Invalid operator API key",
+			})
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to authenticate platform operator")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to authenticate operator",
+			})
+		}
+
+		c.Set("operator", operator)
+		return next(c)
+	}
+}
+
+// GetPublicStatus handles GET /public/tenant-status/:tenant_id
+// Consulted by the API Gateway; only the status is returned, never the reason.
+func (h *TenantLifecycleHandler) GetPublicStatus(c echo.Context) error {
+	lifecycle, err := h.lifecycleService.GetStatus(c.Request().Context(), c.Param("tenant_id"))
+	if err != nil {
+		return h.statusError(c, err, "Failed to retrieve tenant status")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"tenant_id": lifecycle.TenantID,
+		"status":    lifecycle.Status,
+	})
+}
+
+// GetStatus handles GET /api/v1/operator/tenants/:tenant_id/status
+func (h *TenantLifecycleHandler) GetStatus(c echo.Context) error {
+	lifecycle, err := h.lifecycleService.GetStatus(c.Request().Context(), c.Param("tenant_id"))
+	if err != nil {
+		return h.statusError(c, err, "Failed to retrieve tenant status")
+	}
+
+	return c.JSON(http.StatusOK, lifecycle)
+}
+
+// Suspend handles POST /api/v1/operator/tenants/:tenant_id/suspend
+func (h *TenantLifecycleHandler) Suspend(c echo.Context) error {
+	return h.changeStatus(c, h.lifecycleService.Suspend)
+}
+
+// Reactivate handles POST /api/v1/operator/tenants/:tenant_id/reactivate
+func (h *TenantLifecycleHandler) Reactivate(c echo.Context) error {
+	return h.changeStatus(c, h.lifecycleService.Reactivate)
+}
+
+// ScheduleDeletion handles POST /api/v1/operator/tenants/:tenant_id/schedule-deletion
+func (h *TenantLifecycleHandler) ScheduleDeletion(c echo.Context) error {
+	return h.changeStatus(c, h.lifecycleService.ScheduleDeletion)
+}
+
+func (h *TenantLifecycleHandler) changeStatus(c echo.Context, change func(ctx context.Context, operator *models.PlatformOperator, tenantID string, req *models.ChangeTenantStatusRequest) (*models.TenantLifecycle, error)) error {
+	operator := c.Get("operator").(*models.PlatformOperator)
+
+	var req models.ChangeTenantStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	lifecycle, err := change(c.Request().Context(), operator, c.Param("tenant_id"), &req)
+	if err != nil {
+		return h.statusError(c, err, "Failed to change tenant status")
+	}
+
+	log.Warn().Str("operator_id", operator.ID).Str("tenant_id", lifecycle.TenantID).
+		Str("status", lifecycle.Status).Msg("Tenant status changed by platform operator")
+	return c.JSON(http.StatusOK, lifecycle)
+}
+
+func (h *TenantLifecycleHandler) statusError(c echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, models.ErrTenantNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Tenant not found",
+		})
+	case errors.Is(err, models.ErrInvalidStatusReason):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	case errors.Is(err, models.ErrInvalidStatusTransition):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	log.Error().Err(err).Str("tenant_id", c.Param("tenant_id")).Msg(message)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/pos/tenant-service/src/observability"
 	"github.com/pos/tenant-service/src/queue"
 	"github.com/pos/tenant-service/src/repository"
+	"github.com/pos/tenant-service/src/scheduler"
 	"github.com/pos/tenant-service/src/services"
 	. "github.com/pos/tenant-service/src/utils"
 )
@@ -72,7 +74,7 @@ func main() {
 	e.GET("/health", api.HealthCheck)
 	e.GET("/ready", api.ReadyCheck)
 
-	registerHandler := api.NewRegisterHandler(db, eventPublisher)
+	registerHandler := api.NewRegisterHandler(db, eventPublisher, LoadPasswordPolicy(), LoadCaptchaVerifier())
 	e.POST("/register", registerHandler.Register)
 
 	tenantHandler := api.NewTenantHandler(db)
@@ -83,7 +85,20 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create tenant config repository: %v", err)
 	}
-	configService := services.NewTenantConfigService(configRepo, db)
+	// Branding: storefront look, invoice header and receipt text. Logos share the product photo
+	// bucket; without object storage, uploads are refused but logo_url can still be set.
+	var objectStorage *services.ObjectStorage
+	if storageConfig := LoadStorageConfig(); storageConfig.Configured() {
+		objectStorage, err = services.NewObjectStorage(storageConfig)
+		if err != nil {
+			log.Fatalf("Failed to create object storage: %v", err)
+		}
+	} else {
+		log.Printf("Object storage not configured; logo uploads and data exports are disabled")
+	}
+	brandingService := services.NewTenantBrandingService(repository.NewTenantBrandingRepository(db), objectStorage)
+
+	configService := services.NewTenantConfigService(configRepo, brandingService, db)
 	configHandler := api.NewTenantConfigHandler(configService)
 
 	// Public routes
@@ -94,15 +109,107 @@ func main() {
 	admin.PATCH("/:tenant_id/config", configHandler.UpdateTenantConfig)
 	admin.GET("/:tenant_id/midtrans-config", configHandler.GetMidtransConfig)
 	admin.PATCH("/:tenant_id/midtrans-config", configHandler.UpdateMidtransConfig)
+	admin.GET("/:tenant_id/payment-gateway-config", configHandler.GetPaymentGatewayConfig)
+	admin.PATCH("/:tenant_id/payment-gateway-config", configHandler.UpdatePaymentGatewayConfig)
+	admin.GET("/:tenant_id/delivery-fee-config", configHandler.GetDeliveryFeeConfig)
+	admin.PATCH("/:tenant_id/delivery-fee-config", configHandler.UpdateDeliveryFeeConfig)
+
+	// Custom storefront domains and platform subdomains (consulted by the API Gateway for CORS
+	// and host-based storefront routing)
+	domainService := services.NewTenantDomainService(repository.NewTenantDomainRepository(db), GetEnv("STOREFRONT_BASE_DOMAIN"))
+	domainHandler := api.NewTenantDomainHandler(domainService)
+	e.GET("/public/domains/resolve", domainHandler.ResolveDomain)
+	admin.GET("/:tenant_id/domains", domainHandler.ListDomains)
+	admin.POST("/:tenant_id/domains", domainHandler.AddDomain)
+	admin.POST("/:tenant_id/domains/:domain_id/verify", domainHandler.VerifyDomain)
+	admin.DELETE("/:tenant_id/domains/:domain_id", domainHandler.RemoveDomain)
+
+	// Branding (read by the order service when rendering invoices and receipts)
+	brandingHandler := api.NewTenantBrandingHandler(brandingService)
+	admin.GET("/:tenant_id/branding", brandingHandler.GetBranding)
+	admin.PATCH("/:tenant_id/branding", brandingHandler.UpdateBranding)
+	admin.POST("/:tenant_id/branding/logo", brandingHandler.UploadLogo)
+	admin.DELETE("/:tenant_id/branding/logo", brandingHandler.DeleteLogo)
+
+	// Outlets (branches); the outlet a user works at is carried in their JWT
+	outletService := services.NewOutletService(repository.NewOutletRepository(db))
+	outletHandler := api.NewOutletHandler(outletService)
+	admin.GET("/:tenant_id/outlets", outletHandler.ListOutlets)
+	admin.POST("/:tenant_id/outlets", outletHandler.CreateOutlet)
+	admin.GET("/:tenant_id/outlets/:outlet_id", outletHandler.GetOutlet)
+	admin.PUT("/:tenant_id/outlets/:outlet_id", outletHandler.UpdateOutlet)
+	admin.DELETE("/:tenant_id/outlets/:outlet_id", outletHandler.DeleteOutlet)
+
+	// Tenant lifecycle: platform operators suspend, reactivate and schedule the deletion of tenants,
+	// authenticated by their API key; the API Gateway refuses requests for blocked tenants
+	tenantEvents := queue.NewKafkaProducer(kafkaBrokers, GetEnv("KAFKA_TENANT_EVENTS_TOPIC"))
+	defer tenantEvents.Close()
+	lifecycleService := services.NewTenantLifecycleService(
+		repository.NewTenantRepository(db),
+		repository.NewPlatformOperatorRepository(db),
+		tenantEvents,
+		auditPublisher,
+	)
+	lifecycleHandler := api.NewTenantLifecycleHandler(lifecycleService)
+	e.GET("/public/tenant-status/:tenant_id", lifecycleHandler.GetPublicStatus)
+	operator := e.Group("/api/v1/operator/tenants", lifecycleHandler.RequireOperator)
+	operator.GET("/:tenant_id/status", lifecycleHandler.GetStatus)
+	operator.POST("/:tenant_id/suspend", lifecycleHandler.Suspend)
+	operator.POST("/:tenant_id/reactivate", lifecycleHandler.Reactivate)
+	operator.POST("/:tenant_id/schedule-deletion", lifecycleHandler.ScheduleDeletion)
+
+	// Tenant deletion saga: every service deletes the tenant's data on tenant.deletion.requested and
+	// answers with tenant.deletion.acknowledged, read back here from the same topic
+	deletionService := services.NewTenantDeletionService(
+		repository.NewTenantDeletionRepository(db),
+		repository.NewTenantRepository(db),
+		objectStorage,
+		tenantEvents,
+		auditPublisher,
+	)
+	deletionHandler := api.NewTenantDeletionHandler(deletionService)
+	operator.POST("/:tenant_id/delete", deletionHandler.DeleteTenant)
+	operator.GET("/:tenant_id/deletion", deletionHandler.GetDeletion)
+
+	consumerCtx, cancelConsumers := context.WithCancel(context.Background())
+	defer cancelConsumers()
+	tenantEventsConsumer := queue.NewKafkaConsumer(kafkaBrokers, GetEnv("KAFKA_TENANT_EVENTS_TOPIC"), serviceName, deletionService.HandleEvent)
+	go tenantEventsConsumer.Start(consumerCtx)
 
 	// Tenant data rights routes - UU PDP compliance (owner only via API Gateway RBAC)
-	tenantDataHandler, err := api.NewTenantDataHandler(db, auditPublisher)
+	encryptor, err := NewVaultClient()
 	if err != nil {
-		log.Fatalf("Failed to create tenant data handler: %v", err)
+		log.Fatalf("Failed to create vault encryptor: %v", err)
+	}
+	tenantDataService := services.NewTenantDataService(repository.NewTenantRepository(db), configRepo, db, encryptor)
+	// Full data exports are archived in object storage and downloaded through presigned links,
+	// which object storage lets live for at most a week
+	exportLinkTTLHours := GetEnvInt("TENANT_EXPORT_LINK_TTL_HOURS")
+	if exportLinkTTLHours < 1 || exportLinkTTLHours > 168 {
+		log.Fatalf("TENANT_EXPORT_LINK_TTL_HOURS must be between 1 and 168")
 	}
+	tenantExportService := services.NewTenantExportService(
+		repository.NewTenantExportRepository(db, encryptor),
+		repository.NewTenantBrandingRepository(db),
+		repository.NewOutletRepository(db),
+		tenantDataService,
+		objectStorage,
+		auditPublisher,
+		exportLinkTTLHours,
+	)
+	tenantExportScheduler := scheduler.NewTenantExportCleanupScheduler(tenantExportService)
+	if err := tenantExportScheduler.Start(); err != nil {
+		log.Fatalf("Failed to start tenant export cleanup scheduler: %v", err)
+	}
+	defer tenantExportScheduler.Stop()
+
+	tenantDataHandler := api.NewTenantDataHandler(tenantDataService, tenantExportService)
 	dataRights := e.Group("/api/v1/tenant")
 	dataRights.GET("/data", tenantDataHandler.GetTenantData)
 	dataRights.POST("/data/export", tenantDataHandler.ExportTenantData)
+	dataRights.POST("/data/exports", tenantDataHandler.RequestExport)
+	dataRights.GET("/data/exports", tenantDataHandler.ListExports)
+	dataRights.GET("/data/exports/:export_id", tenantDataHandler.GetExport)
 
 	port := GetEnv("PORT")
 
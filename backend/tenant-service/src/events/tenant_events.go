@@ -0,0 +1,29 @@
+package events
+
+import "time"
+
+// Tenant lifecycle event types published to the tenant-events topic
+const (
+	TenantSuspended       = "tenant.suspended"
+	TenantReactivated     = "tenant.reactivated"
+	TenantDeletionPending = "tenant.deletion_pending"
+)
+
+// Tenant deletion saga event types, also on the tenant-events topic
+// tenant.deletion.requested asks the services in data["services"] to delete the tenant's data; each
+// answers with tenant.deletion.acknowledged carrying deletion_id, service, status ("completed" or
+// "failed"), deleted (counts by kind) and error.
+const (
+	TenantDeletionRequested    = "tenant.deletion.requested"
+	TenantDeletionAcknowledged = "tenant.deletion.acknowledged"
+)
+
+// TenantEvent represents a tenant lifecycle change published to Kafka (tenant-events topic)
+// auth-service consumes tenant.suspended and tenant.deletion_pending to end the tenant's sessions
+type TenantEvent struct {
+	EventID   string                 `json:"event_id"`   // Idempotency key (UUID)
+	EventType string                 `json:"event_type"` // "tenant.suspended", "tenant.reactivated", "tenant.deletion_pending"
+	TenantID  string                 `json:"tenant_id"`
+	Data      map[string]interface{} `json:"data"` // previous_status, status, reason, operator_id
+	Timestamp time.Time              `json:"timestamp"`
+}
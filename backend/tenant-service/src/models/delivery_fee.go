@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Delivery fee types a tenant can charge by; zone pricing lives with the order service's delivery zones
+const (
+	DeliveryFeeFlat     = "flat"     // The order settings' default delivery fee
+	DeliveryFeeDistance = "distance" // Base fee plus a per-km rate from the tenant's location
+)
+
+// DeliveryFeeConfig is how a tenant prices deliveries by distance
+type DeliveryFeeConfig struct {
+	TenantID        string   `json:"tenant_id"`
+	Enabled         bool     `json:"enabled"` // When false the flat default fee applies
+	Type            string   `json:"type"`
+	OriginLatitude  *float64 `json:"origin_latitude,omitempty"` // Where deliveries start from
+	OriginLongitude *float64 `json:"origin_longitude,omitempty"`
+	BaseFee         int      `json:"base_fee"`    // In IDR
+	PerKmFee        int      `json:"per_km_fee"`  // In IDR, charged per started km beyond included_km
+	IncludedKm      float64  `json:"included_km"` // Distance covered by the base fee
+	MaxFee          int      `json:"max_fee"`     // Cap on the fee; 0 means uncapped
+}
+
+// UpdateDeliveryFeeConfigRequest changes the fields that are set
+type UpdateDeliveryFeeConfigRequest struct {
+	Enabled         *bool    `json:"enabled,omitempty"`
+	Type            *string  `json:"type,omitempty"`
+	OriginLatitude  *float64 `json:"origin_latitude,omitempty"`
+	OriginLongitude *float64 `json:"origin_longitude,omitempty"`
+	BaseFee         *int     `json:"base_fee,omitempty"`
+	PerKmFee        *int     `json:"per_km_fee,omitempty"`
+	IncludedKm      *float64 `json:"included_km,omitempty"`
+	MaxFee          *int     `json:"max_fee,omitempty"`
+}
+
+var ErrInvalidDeliveryFeeConfig = errors.New("invalid delivery fee configuration")
+
+// Apply copies the set fields of req onto c
+func (c *DeliveryFeeConfig) Apply(req *UpdateDeliveryFeeConfigRequest) {
+	if req.Enabled != nil {
+		c.Enabled = *req.Enabled
+	}
+	if req.Type != nil {
+		c.Type = *req.Type
+	}
+	if req.OriginLatitude != nil {
+		c.OriginLatitude = req.OriginLatitude
+	}
+	if req.OriginLongitude != nil {
+		c.OriginLongitude = req.OriginLongitude
+	}
+	if req.BaseFee != nil {
+		c.BaseFee = *req.BaseFee
+	}
+	if req.PerKmFee != nil {
+		c.PerKmFee = *req.PerKmFee
+	}
+	if req.IncludedKm != nil {
+		c.IncludedKm = *req.IncludedKm
+	}
+	if req.MaxFee != nil {
+		c.MaxFee = *req.MaxFee
+	}
+	if c.Type == "" {
+		c.Type = DeliveryFeeFlat
+	}
+}
+
+// Validate checks the configuration before it is saved
+func (c *DeliveryFeeConfig) Validate() error {
+	if c.Type != DeliveryFeeFlat && c.Type != DeliveryFeeDistance {
+		return fmt.Errorf("%w: type must be 'flat' or 'distance'", ErrInvalidDeliveryFeeConfig)
+	}
+	if c.BaseFee < 0 || c.PerKmFee < 0 || c.MaxFee < 0 || c.IncludedKm < 0 {
+		return fmt.Errorf("%w: fees and included_km must be non-negative", ErrInvalidDeliveryFeeConfig)
+	}
+	if c.MaxFee > 0 && c.MaxFee < c.BaseFee {
+		return fmt.Errorf("%w: max_fee must not be below base_fee", ErrInvalidDeliveryFeeConfig)
+	}
+	if (c.OriginLatitude == nil) != (c.OriginLongitude == nil) {
+		return fmt.Errorf("%w: origin_latitude and origin_longitude must be set together", ErrInvalidDeliveryFeeConfig)
+	}
+	if c.OriginLatitude != nil && (*c.OriginLatitude < -90 || *c.OriginLatitude > 90 || *c.OriginLongitude < -180 || *c.OriginLongitude > 180) {
+		return fmt.Errorf("%w: origin coordinates are out of range", ErrInvalidDeliveryFeeConfig)
+	}
+	// Distance pricing needs a starting point
+	if c.Enabled && c.Type == DeliveryFeeDistance && c.OriginLatitude == nil {
+		return fmt.Errorf("%w: distance pricing needs origin_latitude and origin_longitude", ErrInvalidDeliveryFeeConfig)
+	}
+	return nil
+}
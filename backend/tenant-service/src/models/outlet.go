@@ -0,0 +1,181 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Outlet is one branch of a tenant
+// Staff, products and orders with an outlet_id belong to that branch; the API Gateway forwards the
+// outlet of the signed-in user as X-Outlet-ID.
+type Outlet struct {
+	ID            string        `json:"id" db:"id"`
+	TenantID      string        `json:"tenant_id" db:"tenant_id"`
+	Name          string        `json:"name" db:"name"`
+	Address       string        `json:"address" db:"address"`
+	Latitude      *float64      `json:"latitude" db:"latitude"`
+	Longitude     *float64      `json:"longitude" db:"longitude"`
+	Phone         string        `json:"phone" db:"phone"`
+	Email         string        `json:"email" db:"email"`
+	BusinessHours BusinessHours `json:"business_hours" db:"business_hours"`
+	IsActive      bool          `json:"is_active" db:"is_active"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// OutletRequest creates or replaces an outlet; is_active defaults to true
+type OutletRequest struct {
+	Name          string        `json:"name"`
+	Address       string        `json:"address"`
+	Latitude      *float64      `json:"latitude"`
+	Longitude     *float64      `json:"longitude"`
+	Phone         string        `json:"phone"`
+	Email         string        `json:"email"`
+	BusinessHours BusinessHours `json:"business_hours"`
+	IsActive      *bool         `json:"is_active,omitempty"`
+}
+
+var (
+	ErrInvalidOutlet   = errors.New("invalid outlet")
+	ErrOutletNotFound  = errors.New("outlet not found")
+	ErrOutletNameTaken = errors.New("an outlet with this name already exists")
+	ErrOutletInUse     = errors.New("outlet still has staff, products or orders; deactivate it instead")
+)
+
+// PlanLimitOutlets is the plan limit checked before an outlet is added
+const PlanLimitOutlets = "outlets"
+
+// PlanLimitError is returned when an action would take a tenant over a limit of their subscription plan
+type PlanLimitError struct {
+	Limit       string
+	Max         int
+	PlanName    string
+	UpgradePlan string // Cheapest plan with room for more; empty when there is none
+}
+
+func (e *PlanLimitError) Error() string {
+	return fmt.Sprintf("the %s plan allows %d outlets", e.PlanName, e.Max)
+}
+
+// UpgradeHint tells the owner how to lift the limit
+func (e *PlanLimitError) UpgradeHint() string {
+	if e.UpgradePlan == "" {
+		return "Contact support to raise this limit"
+	}
+	return fmt.Sprintf("Upgrade to the %s plan to raise this limit", e.UpgradePlan)
+}
+
+// DayHours is an outlet's opening window on one weekday, as local "HH:MM" times
+type DayHours struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// BusinessHours maps lowercase weekday names to opening windows; missing days are closed
+type BusinessHours map[string]DayHours
+
+var weekdayNames = map[string]bool{
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true,
+	"friday": true, "saturday": true, "sunday": true,
+}
+
+// Scan implements sql.Scanner for BusinessHours (JSONB)
+func (h *BusinessHours) Scan(value interface{}) error {
+	*h = BusinessHours{}
+	if value == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into BusinessHours", value)
+	}
+	return json.Unmarshal(data, h)
+}
+
+// Value implements driver.Valuer for BusinessHours (JSONB)
+func (h BusinessHours) Value() (driver.Value, error) {
+	if h == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Validate checks weekday names and that each day opens before it closes
+func (h BusinessHours) Validate() error {
+	for day, hours := range h {
+		open, errOpen := time.Parse("15:04", hours.Open)
+		closing, errClose := time.Parse("15:04", hours.Close)
+		if !weekdayNames[day] || errOpen != nil || errClose != nil || !open.Before(closing) {
+			return fmt.Errorf("%w: business_hours must map weekdays (monday to sunday) to open and close times in HH:MM, with open before close", ErrInvalidOutlet)
+		}
+	}
+	return nil
+}
+
+// Outlet returns the outlet described by the request, trimmed and validated
+func (req *OutletRequest) Outlet() (*Outlet, error) {
+	outlet := &Outlet{
+		Name:          strings.TrimSpace(req.Name),
+		Address:       strings.TrimSpace(req.Address),
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Phone:         strings.TrimSpace(req.Phone),
+		Email:         strings.TrimSpace(req.Email),
+		BusinessHours: req.BusinessHours,
+		IsActive:      req.IsActive == nil || *req.IsActive,
+	}
+	if outlet.BusinessHours == nil {
+		outlet.BusinessHours = BusinessHours{}
+	}
+
+	if outlet.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidOutlet)
+	}
+
+	limits := []struct {
+		field string
+		value string
+		max   int
+	}{
+		{"name", outlet.Name, 100},
+		{"address", outlet.Address, 500},
+		{"phone", outlet.Phone, 30},
+		{"email", outlet.Email, 255},
+	}
+	for _, limit := range limits {
+		if len(limit.value) > limit.max {
+			return nil, fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidOutlet, limit.field, limit.max)
+		}
+	}
+
+	if outlet.Email != "" && !strings.Contains(outlet.Email, "@") {
+		return nil, fmt.Errorf("%w: email must be an email address", ErrInvalidOutlet)
+	}
+
+	if (outlet.Latitude == nil) != (outlet.Longitude == nil) {
+		return nil, fmt.Errorf("%w: latitude and longitude must be set together", ErrInvalidOutlet)
+	}
+	if outlet.Latitude != nil && (*outlet.Latitude < -90 || *outlet.Latitude > 90 || *outlet.Longitude < -180 || *outlet.Longitude > 180) {
+		return nil, fmt.Errorf("%w: latitude must be between -90 and 90 and longitude between -180 and 180", ErrInvalidOutlet)
+	}
+
+	if err := outlet.BusinessHours.Validate(); err != nil {
+		return nil, err
+	}
+
+	return outlet, nil
+}
@@ -23,6 +23,8 @@ const (
 	TenantStatusInactive  TenantStatus = "inactive"
 	TenantStatusSuspended TenantStatus = "suspended"
 	TenantStatusDeleted   TenantStatus = "deleted"
+
+	TenantStatusPendingDeletion TenantStatus = "pending_deletion"
 )
 
 type CreateTenantRequest struct {
@@ -33,6 +35,7 @@ type CreateTenantRequest struct {
 	FirstName    string   `json:"first_name,omitempty" validate:"omitempty,max=50"`
 	LastName     string   `json:"last_name,omitempty" validate:"omitempty,max=50"`
 	Consents     []string `json:"consents" validate:"dive,oneof=analytics advertising"` // Optional consents granted (required consents implicit)
+	CaptchaToken string   `json:"captcha_token,omitempty"`                              // Solved CAPTCHA, required when CAPTCHA_ENABLED
 }
 
 type TenantResponse struct {
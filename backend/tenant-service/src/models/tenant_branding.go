@@ -0,0 +1,212 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultBrandColor is the invoice accent color of tenants that did not pick one
+const DefaultBrandColor = "#1F2937"
+
+// TenantBranding is how a tenant's storefront, invoices and receipts look
+type TenantBranding struct {
+	TenantID         string      `json:"tenant_id" db:"tenant_id"`
+	DisplayName      string      `json:"display_name" db:"display_name"` // Empty prints the business name
+	LogoURL          string      `json:"logo_url" db:"logo_url"`         // Short-lived link when the logo was uploaded
+	LogoStorageKey   string      `json:"-" db:"logo_storage_key"`        // Uploaded logo; takes the place of logo_url
+	PrimaryColor     string      `json:"primary_color" db:"primary_color"`
+	SecondaryColor   string      `json:"secondary_color" db:"secondary_color"`
+	Address          string      `json:"address" db:"address"`
+	Phone            string      `json:"phone" db:"phone"`
+	Email            string      `json:"email" db:"email"`
+	TaxID            string      `json:"tax_id" db:"tax_id"` // NPWP
+	InvoiceFooter    string      `json:"invoice_footer" db:"invoice_footer"`
+	ReceiptHeader    string      `json:"receipt_header" db:"receipt_header"`
+	ReceiptFooter    string      `json:"receipt_footer" db:"receipt_footer"` // Empty prints "Thank you!"
+	SocialLinks      SocialLinks `json:"social_links" db:"social_links"`
+	AttachInvoicePDF bool        `json:"attach_invoice_pdf" db:"attach_invoice_pdf"`
+	UpdatedAt        time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateTenantBrandingRequest changes the fields that are set; an empty string clears a field
+type UpdateTenantBrandingRequest struct {
+	DisplayName      *string      `json:"display_name,omitempty"`
+	LogoURL          *string      `json:"logo_url,omitempty"` // Replaces an uploaded logo
+	PrimaryColor     *string      `json:"primary_color,omitempty"`
+	SecondaryColor   *string      `json:"secondary_color,omitempty"`
+	Address          *string      `json:"address,omitempty"`
+	Phone            *string      `json:"phone,omitempty"`
+	Email            *string      `json:"email,omitempty"`
+	TaxID            *string      `json:"tax_id,omitempty"`
+	InvoiceFooter    *string      `json:"invoice_footer,omitempty"`
+	ReceiptHeader    *string      `json:"receipt_header,omitempty"`
+	ReceiptFooter    *string      `json:"receipt_footer,omitempty"`
+	SocialLinks      *SocialLinks `json:"social_links,omitempty"` // Replaces all links; {} clears them
+	AttachInvoicePDF *bool        `json:"attach_invoice_pdf,omitempty"`
+}
+
+// PublicBranding is the part of a tenant's branding shown on the public storefront
+type PublicBranding struct {
+	DisplayName    string      `json:"display_name,omitempty"`
+	LogoURL        string      `json:"logo_url,omitempty"`
+	PrimaryColor   string      `json:"primary_color"`
+	SecondaryColor string      `json:"secondary_color,omitempty"`
+	SocialLinks    SocialLinks `json:"social_links"`
+}
+
+// SocialLinks maps a social platform to the tenant's profile URL
+type SocialLinks map[string]string
+
+// SocialPlatforms are the platforms a tenant can link
+var SocialPlatforms = map[string]bool{
+	"instagram": true, "facebook": true, "tiktok": true, "x": true, "youtube": true,
+	"whatsapp": true, "gofood": true, "grabfood": true, "shopeefood": true, "website": true,
+}
+
+// Scan implements sql.Scanner for SocialLinks (JSONB)
+func (l *SocialLinks) Scan(value interface{}) error {
+	*l = SocialLinks{}
+	if value == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into SocialLinks", value)
+	}
+	return json.Unmarshal(data, l)
+}
+
+// Value implements driver.Valuer for SocialLinks (JSONB)
+func (l SocialLinks) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+var ErrInvalidBranding = errors.New("invalid branding")
+
+var brandColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// Apply copies the set fields of req onto b, trimmed
+func (b *TenantBranding) Apply(req *UpdateTenantBrandingRequest) {
+	set := func(dst *string, src *string) {
+		if src != nil {
+			*dst = strings.TrimSpace(*src)
+		}
+	}
+	set(&b.DisplayName, req.DisplayName)
+	set(&b.LogoURL, req.LogoURL)
+	set(&b.PrimaryColor, req.PrimaryColor)
+	set(&b.SecondaryColor, req.SecondaryColor)
+	set(&b.Address, req.Address)
+	set(&b.Phone, req.Phone)
+	set(&b.Email, req.Email)
+	set(&b.TaxID, req.TaxID)
+	set(&b.InvoiceFooter, req.InvoiceFooter)
+	set(&b.ReceiptHeader, req.ReceiptHeader)
+	set(&b.ReceiptFooter, req.ReceiptFooter)
+	if req.LogoURL != nil {
+		b.LogoStorageKey = ""
+	}
+	if req.SocialLinks != nil {
+		b.SocialLinks = SocialLinks{}
+		for platform, link := range *req.SocialLinks {
+			b.SocialLinks[strings.ToLower(strings.TrimSpace(platform))] = strings.TrimSpace(link)
+		}
+	}
+	if req.AttachInvoicePDF != nil {
+		b.AttachInvoicePDF = *req.AttachInvoicePDF
+	}
+	if b.PrimaryColor == "" {
+		b.PrimaryColor = DefaultBrandColor
+	}
+	b.PrimaryColor = strings.ToUpper(b.PrimaryColor)
+	b.SecondaryColor = strings.ToUpper(b.SecondaryColor)
+}
+
+// Validate checks the branding before it is saved
+func (b *TenantBranding) Validate() error {
+	if !brandColorPattern.MatchString(b.PrimaryColor) {
+		return fmt.Errorf("%w: primary_color must be a hex color like #1F2937", ErrInvalidBranding)
+	}
+
+	if b.SecondaryColor != "" && !brandColorPattern.MatchString(b.SecondaryColor) {
+		return fmt.Errorf("%w: secondary_color must be a hex color like #F59E0B", ErrInvalidBranding)
+	}
+
+	if b.LogoURL != "" && !isHTTPSURL(b.LogoURL) {
+		return fmt.Errorf("%w: logo_url must be an https URL", ErrInvalidBranding)
+	}
+
+	for platform, link := range b.SocialLinks {
+		if !SocialPlatforms[platform] {
+			return fmt.Errorf("%w: social_links has unknown platform %q", ErrInvalidBranding, platform)
+		}
+		if !isHTTPSURL(link) {
+			return fmt.Errorf("%w: social_links.%s must be an https URL", ErrInvalidBranding, platform)
+		}
+	}
+
+	limits := []struct {
+		field string
+		value string
+		max   int
+	}{
+		{"display_name", b.DisplayName, 255},
+		{"address", b.Address, 500},
+		{"phone", b.Phone, 50},
+		{"email", b.Email, 255},
+		{"tax_id", b.TaxID, 32},
+		{"invoice_footer", b.InvoiceFooter, 500},
+		{"receipt_header", b.ReceiptHeader, 500},
+		{"receipt_footer", b.ReceiptFooter, 500},
+	}
+	for _, limit := range limits {
+		if len(limit.value) > limit.max {
+			return fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidBranding, limit.field, limit.max)
+		}
+	}
+
+	if b.Email != "" && !strings.Contains(b.Email, "@") {
+		return fmt.Errorf("%w: email must be an email address", ErrInvalidBranding)
+	}
+
+	return nil
+}
+
+// Public returns the branding shown on the storefront
+func (b *TenantBranding) Public() *PublicBranding {
+	links := b.SocialLinks
+	if links == nil {
+		links = SocialLinks{}
+	}
+	return &PublicBranding{
+		DisplayName:    b.DisplayName,
+		LogoURL:        b.LogoURL,
+		PrimaryColor:   b.PrimaryColor,
+		SecondaryColor: b.SecondaryColor,
+		SocialLinks:    links,
+	}
+}
+
+func isHTTPSURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && parsed.Scheme == "https" && parsed.Host != "" && len(value) <= 2048
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Tenant deletion errors
+var (
+	ErrTenantNotPendingDeletion = errors.New("tenant must be scheduled for deletion first")
+	ErrTenantDeletionNotFound   = errors.New("tenant deletion not found")
+	ErrTenantDeletionFailed     = errors.New("tenant deletion failed")
+)
+
+// Statuses of a tenant deletion and of its steps
+const (
+	TenantDeletionInProgress = "in_progress"
+	TenantDeletionCompleted  = "completed"
+
+	DeletionStepPending   = "pending"
+	DeletionStepCompleted = "completed"
+	DeletionStepFailed    = "failed"
+)
+
+// TenantDeletionStages are the services that delete a tenant's data, stage by stage
+// A stage is asked once every service of the previous one has acknowledged. Users go last: orders,
+// cashier shifts and stock adjustments reference the staff who recorded them.
+var TenantDeletionStages = [][]string{
+	{"product-service", "order-service", "notification-service", "audit-service"},
+	{"user-service"},
+}
+
+// TenantDeletion is the deletion of all of a tenant's data across services
+// Once completed it stands as the deletion certificate.
+type TenantDeletion struct {
+	ID            string               `json:"id"`
+	TenantID      string               `json:"tenant_id"`
+	TenantSlug    string               `json:"tenant_slug"`
+	RequestedBy   *string              `json:"requested_by,omitempty"` // Platform operator ID
+	Reason        string               `json:"reason,omitempty"`
+	Status        string               `json:"status"`
+	Stage         int                  `json:"stage"`
+	Error         *string              `json:"error,omitempty"`
+	CertificateID *string              `json:"certificate_id,omitempty"`
+	RequestedAt   time.Time            `json:"requested_at"`
+	CompletedAt   *time.Time           `json:"completed_at,omitempty"`
+	Steps         []TenantDeletionStep `json:"steps"`
+}
+
+// TenantDeletionStep is one service's part of a tenant deletion
+type TenantDeletionStep struct {
+	Service        string           `json:"service"`
+	Stage          int              `json:"stage"`
+	Status         string           `json:"status"`
+	Deleted        map[string]int64 `json:"deleted"`
+	Error          *string          `json:"error,omitempty"`
+	AcknowledgedAt *time.Time       `json:"acknowledged_at,omitempty"`
+}
+
+// Outstanding returns the steps of the current stage that have not completed
+func (d *TenantDeletion) Outstanding() []TenantDeletionStep {
+	var outstanding []TenantDeletionStep
+	for _, step := range d.Steps {
+		if step.Stage == d.Stage && step.Status != DeletionStepCompleted {
+			outstanding = append(outstanding, step)
+		}
+	}
+	return outstanding
+}
+
+// TenantDeletionAck is a service's acknowledgement of a tenant.deletion.requested event
+type TenantDeletionAck struct {
+	DeletionID string           `json:"deletion_id"`
+	Service    string           `json:"service"`
+	Status     string           `json:"status"`
+	Deleted    map[string]int64 `json:"deleted"`
+	Error      string           `json:"error"`
+}
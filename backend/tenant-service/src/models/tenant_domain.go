@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TenantDomain maps a custom storefront domain to a tenant
+type TenantDomain struct {
+	ID                string     `json:"id" db:"id"`
+	TenantID          string     `json:"tenant_id" db:"tenant_id"`
+	Domain            string     `json:"domain" db:"domain"`
+	IsActive          bool       `json:"is_active" db:"is_active"`
+	VerificationToken string     `json:"verification_token" db:"verification_token"`
+	VerifiedAt        *time.Time `json:"verified_at" db:"verified_at"` // nil until the TXT record is found
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+
+	TenantSlug         string `json:"-"`                             // Set when resolving a host
+	VerificationRecord string `json:"verification_record,omitempty"` // TXT record name to publish the token under
+}
+
+// DomainVerificationPrefix names the TXT record holding a domain's verification token
+const DomainVerificationPrefix = "_pos-verification."
+
+// VerificationRecordName is the TXT record the tenant must create to prove they own the domain
+func (d *TenantDomain) VerificationRecordName() string {
+	return DomainVerificationPrefix + HostWithoutPort(d.Domain)
+}
+
+type CreateTenantDomainRequest struct {
+	Domain string `json:"domain" validate:"required,max=253"`
+}
+
+var (
+	ErrInvalidDomain       = errors.New("invalid domain")
+	ErrDomainAlreadyExists = errors.New("domain is already registered")
+	ErrDomainNotFound      = errors.New("domain not found")
+	ErrDomainReserved      = errors.New("subdomains of the platform domain are assigned from the tenant slug")
+	ErrDomainNotVerified   = errors.New("verification TXT record not found")
+)
+
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}(:[0-9]{1,5})?$`)
+
+// NormalizeDomain reduces user input or a browser Origin to a lowercase host[:port]
+// Accepts "Shop.Example.com", "https://shop.example.com/" or "shop.example.com:8443"
+func NormalizeDomain(input string) (string, error) {
+	value := strings.TrimSpace(strings.ToLower(input))
+	if value == "" {
+		return "", ErrInvalidDomain
+	}
+
+	if strings.Contains(value, "://") {
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Host == "" {
+			return "", ErrInvalidDomain
+		}
+		value = parsed.Host
+	}
+	value = strings.TrimSuffix(value, "/")
+
+	if len(value) > 253 || !hostnamePattern.MatchString(value) {
+		return "", ErrInvalidDomain
+	}
+
+	return value, nil
+}
+
+// HostWithoutPort strips an optional :port from a normalized domain
+func HostWithoutPort(domain string) string {
+	if i := strings.LastIndex(domain, ":"); i >= 0 {
+		return domain[:i]
+	}
+	return domain
+}
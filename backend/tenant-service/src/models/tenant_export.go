@@ -0,0 +1,123 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Statuses of a tenant data export
+const (
+	TenantExportPending = "pending"
+	TenantExportReady   = "ready"
+	TenantExportFailed  = "failed"
+)
+
+// TenantExport is an owner's request for an archive of all of the tenant's data
+type TenantExport struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id"`
+	RequestedBy *string    `json:"requested_by,omitempty"`
+	Status      string     `json:"status"`
+	StorageKey  string     `json:"-"`
+	SizeBytes   *int64     `json:"size_bytes,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	// Short-lived download link, only filled in while a ready export has not expired
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// TenantExportManifest is manifest.json, describing what an archive holds
+type TenantExportManifest struct {
+	TenantID    string         `json:"tenant_id"`
+	ExportID    string         `json:"export_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Files       []string       `json:"files"`
+	Counts      map[string]int `json:"counts"`
+}
+
+// ExportedProduct is one product of the catalog, archived or not
+type ExportedProduct struct {
+	ID            string          `json:"id"`
+	SKU           string          `json:"sku"`
+	Name          string          `json:"name"`
+	Description   *string         `json:"description,omitempty"`
+	CategoryID    *string         `json:"category_id,omitempty"`
+	CategoryName  *string         `json:"category_name,omitempty"`
+	SellingPrice  float64         `json:"selling_price"`
+	CostPrice     float64         `json:"cost_price"`
+	TaxRate       float64         `json:"tax_rate"`
+	StockQuantity int             `json:"stock_quantity"`
+	ArchivedAt    *time.Time      `json:"archived_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	Photos        []ExportedPhoto `json:"photos"`
+}
+
+// ExportedPhoto is a product photo; the image itself is in the archive at ArchivePath
+type ExportedPhoto struct {
+	ID               string `json:"id"`
+	ProductID        string `json:"-"`
+	StorageKey       string `json:"-"`
+	OriginalFilename string `json:"original_filename"`
+	MimeType         string `json:"mime_type"`
+	SizeBytes        int    `json:"size_bytes"`
+	DisplayOrder     int    `json:"display_order"`
+	IsPrimary        bool   `json:"is_primary"`
+	ArchivePath      string `json:"archive_path,omitempty"` // Empty when the image could not be read
+}
+
+// ExportedOrder is one order with its items
+// Archived orders keep no customer contact, and anonymized orders have had theirs removed.
+type ExportedOrder struct {
+	ID                      string          `json:"id"`
+	OrderReference          string          `json:"order_reference"`
+	Status                  string          `json:"status"`
+	OrderType               string          `json:"order_type"`
+	DeliveryType            string          `json:"delivery_type"`
+	TableNumber             *string         `json:"table_number,omitempty"`
+	SubtotalAmount          int             `json:"subtotal_amount"`
+	DeliveryFee             int             `json:"delivery_fee"`
+	DiscountAmount          int             `json:"discount_amount"`
+	PromotionDiscountAmount int             `json:"promotion_discount_amount"`
+	LoyaltyDiscountAmount   int             `json:"loyalty_discount_amount"`
+	ServiceChargeAmount     int             `json:"service_charge_amount"`
+	TaxAmount               int             `json:"tax_amount"`
+	TotalAmount             int             `json:"total_amount"`
+	CustomerName            *string         `json:"customer_name,omitempty"`
+	CustomerPhone           *string         `json:"customer_phone,omitempty"`
+	CustomerEmail           *string         `json:"customer_email,omitempty"`
+	Notes                   *string         `json:"notes,omitempty"`
+	Archived                bool            `json:"archived"`
+	Items                   json.RawMessage `json:"items"`
+	CreatedAt               time.Time       `json:"created_at"`
+	PaidAt                  *time.Time      `json:"paid_at,omitempty"`
+	CompletedAt             *time.Time      `json:"completed_at,omitempty"`
+	CancelledAt             *time.Time      `json:"cancelled_at,omitempty"`
+}
+
+// ExportedConsent is one consent grant or revocation by a staff member or guest
+// IP addresses and user agents are left out.
+type ExportedConsent struct {
+	ID            string     `json:"id"`
+	SubjectType   string     `json:"subject_type"`
+	SubjectID     *string    `json:"subject_id,omitempty"`
+	GuestOrderID  *string    `json:"guest_order_id,omitempty"`
+	Purpose       string     `json:"purpose"`
+	Granted       bool       `json:"granted"`
+	PolicyVersion string     `json:"policy_version"`
+	ConsentMethod string     `json:"consent_method"`
+	GrantedAt     time.Time  `json:"granted_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+}
+
+// AuditSummaryEntry counts the audit trail entries of one action on one resource type
+// The entries themselves stay in the audit service.
+type AuditSummaryEntry struct {
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	Count        int64     `json:"count"`
+	FirstAt      time.Time `json:"first_at"`
+	LastAt       time.Time `json:"last_at"`
+}
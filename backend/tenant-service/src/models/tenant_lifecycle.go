@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Tenant lifecycle errors
+var (
+	ErrTenantNotFound          = errors.New("tenant not found")
+	ErrInvalidStatusTransition = errors.New("tenant status cannot change this way")
+	ErrInvalidStatusReason     = errors.New("reason is required and must be at most 500 characters")
+)
+
+// PlatformOperator is a member of the platform staff, authenticated by their API key
+type PlatformOperator struct {
+	ID    string
+	Email string
+	Name  string
+}
+
+// TenantLifecycle is a tenant's status as managed by platform operators
+type TenantLifecycle struct {
+	TenantID        string     `json:"tenant_id"`
+	Slug            string     `json:"slug"`
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	StatusChangedBy *string    `json:"status_changed_by,omitempty"` // Platform operator ID
+}
+
+// ChangeTenantStatusRequest is the body of the operator suspend, reactivate and schedule deletion endpoints
+type ChangeTenantStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks the reason; suspensions and deletions must say why, reactivations may
+func (r *ChangeTenantStatusRequest) Validate(required bool) error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if (required && r.Reason == "") || len(r.Reason) > 500 {
+		return ErrInvalidStatusReason
+	}
+	return nil
+}
+
+// statusTransitions lists, for each status an operator can set, the statuses it can be set from
+// Reactivating a tenant that was suspended before verifying its account makes it active.
+var statusTransitions = map[TenantStatus][]TenantStatus{
+	TenantStatusSuspended:       {TenantStatusActive, TenantStatusInactive},
+	TenantStatusActive:          {TenantStatusSuspended, TenantStatusPendingDeletion},
+	TenantStatusPendingDeletion: {TenantStatusActive, TenantStatusInactive, TenantStatusSuspended},
+}
+
+// StatusesLeadingTo returns the statuses a tenant can be moved to target from
+func StatusesLeadingTo(target TenantStatus) []string {
+	from := make([]string, 0, len(statusTransitions[target]))
+	for _, status := range statusTransitions[target] {
+		from = append(from, string(status))
+	}
+	return from
+}
+
+// Blocked reports whether requests for a tenant in this status are refused
+func (s TenantStatus) Blocked() bool {
+	return s == TenantStatusSuspended || s == TenantStatusPendingDeletion
+}
+
+// TenantUnavailableError is returned for requests to a suspended tenant or one pending deletion
+type TenantUnavailableError struct {
+	Status TenantStatus
+}
+
+func (e *TenantUnavailableError) Error() string {
+	return "tenant is " + string(e.Status)
+}
+
+// Response is the body returned with 403; clients branch on tenant_suspended or tenant_pending_deletion
+func (e *TenantUnavailableError) Response() map[string]string {
+	message := "This store has been suspended"
+	if e.Status == TenantStatusPendingDeletion {
+		message = "This store is closing and its account is scheduled for deletion"
+	}
+	return map[string]string{
+		"error":   "tenant_" + string(e.Status),
+		"message": message,
+	}
+}
@@ -3,11 +3,63 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"log"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// KafkaConsumer for consuming events
+type KafkaConsumer struct {
+	reader  *kafka.Reader
+	handler func(context.Context, []byte) error
+}
+
+func NewKafkaConsumer(brokers []string, topic string, groupID string, handler func(context.Context, []byte) error) *KafkaConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        brokers,
+		Topic:          topic,
+		GroupID:        groupID,
+		MinBytes:       10e1, // 100B
+		MaxBytes:       10e6, // 10MB
+		CommitInterval: time.Second,
+		StartOffset:    kafka.LastOffset,
+	})
+
+	return &KafkaConsumer{
+		reader:  reader,
+		handler: handler,
+	}
+}
+
+func (c *KafkaConsumer) Start(ctx context.Context) {
+	log.Printf("Starting Kafka consumer for topic: %s", c.reader.Config().Topic)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Shutting down Kafka consumer...")
+			c.reader.Close()
+			return
+		default:
+			msg, err := c.reader.ReadMessage(ctx)
+			if err != nil {
+				log.Printf("Error reading message: %v", err)
+				continue
+			}
+
+			if err := c.handler(ctx, msg.Value); err != nil {
+				log.Printf("Error handling message: %v", err)
+				continue
+			}
+		}
+	}
+}
+
+func (c *KafkaConsumer) Close() error {
+	return c.reader.Close()
+}
+
 // KafkaProducer for publishing events
 type KafkaProducer struct {
 	writer *kafka.Writer
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/pos/tenant-service/src/models"
+)
+
+type OutletRepository struct {
+	db *sql.DB
+}
+
+func NewOutletRepository(db *sql.DB) *OutletRepository {
+	return &OutletRepository{db: db}
+}
+
+const outletColumns = `
+	id, tenant_id, name, COALESCE(address, ''), latitude, longitude,
+	COALESCE(phone, ''), COALESCE(email, ''), business_hours, is_active, created_at, updated_at
+`
+
+type outletScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOutlet(row outletScanner) (*models.Outlet, error) {
+	var o models.Outlet
+	err := row.Scan(
+		&o.ID, &o.TenantID, &o.Name, &o.Address, &o.Latitude, &o.Longitude,
+		&o.Phone, &o.Email, &o.BusinessHours, &o.IsActive, &o.CreatedAt, &o.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *OutletRepository) ListByTenantID(ctx context.Context, tenantID string) ([]models.Outlet, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+outletColumns+`
+		FROM outlets
+		WHERE tenant_id = $1
+		ORDER BY LOWER(name)
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	outlets := []models.Outlet{}
+	for rows.Next() {
+		outlet, err := scanOutlet(rows)
+		if err != nil {
+			return nil, err
+		}
+		outlets = append(outlets, *outlet)
+	}
+
+	return outlets, rows.Err()
+}
+
+func (r *OutletRepository) FindByID(ctx context.Context, tenantID, outletID string) (*models.Outlet, error) {
+	outlet, err := scanOutlet(r.db.QueryRowContext(ctx, `
+		SELECT `+outletColumns+`
+		FROM outlets
+		WHERE id = $1 AND tenant_id = $2
+	`, outletID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrOutletNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return outlet, nil
+}
+
+func (r *OutletRepository) Create(ctx context.Context, outlet *models.Outlet) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO outlets (tenant_id, name, address, latitude, longitude, phone, email, business_hours, is_active)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9)
+		RETURNING id, created_at, updated_at
+	`, outlet.TenantID, outlet.Name, outlet.Address, outlet.Latitude, outlet.Longitude,
+		outlet.Phone, outlet.Email, outlet.BusinessHours, outlet.IsActive,
+	).Scan(&outlet.ID, &outlet.CreatedAt, &outlet.UpdatedAt)
+
+	return mapOutletError(err)
+}
+
+func (r *OutletRepository) Update(ctx context.Context, outlet *models.Outlet) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE outlets
+		SET name = $1, address = NULLIF($2, ''), latitude = $3, longitude = $4, phone = NULLIF($5, ''),
+			email = NULLIF($6, ''), business_hours = $7, is_active = $8, updated_at = NOW()
+		WHERE id = $9 AND tenant_id = $10
+		RETURNING created_at, updated_at
+	`, outlet.Name, outlet.Address, outlet.Latitude, outlet.Longitude, outlet.Phone,
+		outlet.Email, outlet.BusinessHours, outlet.IsActive, outlet.ID, outlet.TenantID,
+	).Scan(&outlet.CreatedAt, &outlet.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return models.ErrOutletNotFound
+	}
+
+	return mapOutletError(err)
+}
+
+// Delete removes an outlet no staff, product or order refers to
+func (r *OutletRepository) Delete(ctx context.Context, tenantID, outletID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM outlets WHERE id = $1 AND tenant_id = $2`,
+		outletID, tenantID,
+	)
+	if err != nil {
+		return mapOutletError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrOutletNotFound
+	}
+
+	return nil
+}
+
+// OutletPlanUsage returns the tenant's plan name, its outlet limit (nil when unlimited) and the
+// tenant's outlets; deactivated outlets count, as they can be reactivated at any time
+// Tenants without a subscription are on the free plan.
+func (r *OutletRepository) OutletPlanUsage(ctx context.Context, tenantID string) (string, *int, int, error) {
+	var planName string
+	var maxOutlets sql.NullInt64
+	var used int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT p.name, p.max_outlets, (SELECT COUNT(*) FROM outlets WHERE tenant_id = $1)
+		FROM subscription_plans p
+		WHERE p.code = COALESCE((SELECT plan_code FROM tenant_subscriptions WHERE tenant_id = $1), 'free')
+	`, tenantID).Scan(&planName, &maxOutlets, &used)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	if !maxOutlets.Valid {
+		return planName, nil, used, nil
+	}
+	max := int(maxOutlets.Int64)
+	return planName, &max, used, nil
+}
+
+// FindOutletUpgradePlan returns the cheapest plan allowing at least needed outlets, or "" when none does
+func (r *OutletRepository) FindOutletUpgradePlan(ctx context.Context, needed int) (string, error) {
+	var code string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT code FROM subscription_plans
+		WHERE max_outlets IS NULL OR max_outlets >= $1
+		ORDER BY monthly_price, code
+		LIMIT 1
+	`, needed).Scan(&code)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return code, err
+}
+
+func mapOutletError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			return models.ErrOutletNameTaken
+		case "23503":
+			return models.ErrOutletInUse
+		}
+	}
+	return err
+}
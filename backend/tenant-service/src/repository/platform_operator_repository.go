@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pos/tenant-service/src/models"
+)
+
+// PlatformOperatorRepository looks up the platform staff managed by auth-service
+type PlatformOperatorRepository struct {
+	db *sql.DB
+}
+
+func NewPlatformOperatorRepository(db *sql.DB) *PlatformOperatorRepository {
+	return &PlatformOperatorRepository{db: db}
+}
+
+// FindActiveByAPIKeyHash returns the active operator owning the API key, or nil when there is none
+// A successful lookup also records when the key was last used.
+func (r *PlatformOperatorRepository) FindActiveByAPIKeyHash(ctx context.Context, apiKeyHash string) (*models.PlatformOperator, error) {
+	operator := &models.PlatformOperator{}
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE platform_operators SET last_used_at = NOW()
+		WHERE api_key_hash = $1 AND status = 'active'
+		RETURNING id, email, name
+	`, apiKeyHash).Scan(&operator.ID, &operator.Email, &operator.Name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return operator, nil
+}
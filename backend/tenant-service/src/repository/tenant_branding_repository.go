@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pos/tenant-service/src/models"
+)
+
+type TenantBrandingRepository struct {
+	db *sql.DB
+}
+
+func NewTenantBrandingRepository(db *sql.DB) *TenantBrandingRepository {
+	return &TenantBrandingRepository{db: db}
+}
+
+// GetByTenantID returns a tenant's branding, or the defaults when the tenant never saved any
+func (r *TenantBrandingRepository) GetByTenantID(ctx context.Context, tenantID string) (*models.TenantBranding, error) {
+	query := `
+		SELECT tenant_id, COALESCE(display_name, ''), COALESCE(logo_url, ''), COALESCE(logo_storage_key, ''),
+		       primary_color, COALESCE(secondary_color, ''),
+		       COALESCE(address, ''), COALESCE(phone, ''), COALESCE(email, ''), COALESCE(tax_id, ''),
+		       COALESCE(invoice_footer, ''), COALESCE(receipt_header, ''), COALESCE(receipt_footer, ''),
+		       social_links, attach_invoice_pdf, updated_at
+		FROM tenant_branding
+		WHERE tenant_id = $1
+	`
+
+	var b models.TenantBranding
+	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
+		&b.TenantID, &b.DisplayName, &b.LogoURL, &b.LogoStorageKey,
+		&b.PrimaryColor, &b.SecondaryColor,
+		&b.Address, &b.Phone, &b.Email, &b.TaxID,
+		&b.InvoiceFooter, &b.ReceiptHeader, &b.ReceiptFooter,
+		&b.SocialLinks, &b.AttachInvoicePDF, &b.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return &models.TenantBranding{
+			TenantID:     tenantID,
+			PrimaryColor: models.DefaultBrandColor,
+			SocialLinks:  models.SocialLinks{},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// Upsert saves a tenant's branding, storing empty fields as NULL
+func (r *TenantBrandingRepository) Upsert(ctx context.Context, branding *models.TenantBranding) error {
+	query := `
+		INSERT INTO tenant_branding (
+			tenant_id, display_name, logo_url, primary_color, address, phone, email, tax_id,
+			invoice_footer, attach_invoice_pdf, updated_at,
+			logo_storage_key, secondary_color, receipt_header, receipt_footer, social_links
+		)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''),
+		        NULLIF($8, ''), NULLIF($9, ''), $10, $11,
+		        NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''), NULLIF($15, ''), $16)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			display_name = EXCLUDED.display_name,
+			logo_url = EXCLUDED.logo_url,
+			primary_color = EXCLUDED.primary_color,
+			address = EXCLUDED.address,
+			phone = EXCLUDED.phone,
+			email = EXCLUDED.email,
+			tax_id = EXCLUDED.tax_id,
+			invoice_footer = EXCLUDED.invoice_footer,
+			attach_invoice_pdf = EXCLUDED.attach_invoice_pdf,
+			updated_at = EXCLUDED.updated_at,
+			logo_storage_key = EXCLUDED.logo_storage_key,
+			secondary_color = EXCLUDED.secondary_color,
+			receipt_header = EXCLUDED.receipt_header,
+			receipt_footer = EXCLUDED.receipt_footer,
+			social_links = EXCLUDED.social_links
+	`
+
+	branding.UpdatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, query,
+		branding.TenantID,
+		branding.DisplayName,
+		branding.LogoURL,
+		branding.PrimaryColor,
+		branding.Address,
+		branding.Phone,
+		branding.Email,
+		branding.TaxID,
+		branding.InvoiceFooter,
+		branding.AttachInvoicePDF,
+		branding.UpdatedAt,
+		branding.LogoStorageKey,
+		branding.SecondaryColor,
+		branding.ReceiptHeader,
+		branding.ReceiptFooter,
+		branding.SocialLinks,
+	)
+	return err
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/lib/pq"
+	"github.com/pos/tenant-service/src/models"
 	"github.com/pos/tenant-service/src/utils"
 )
 
@@ -48,6 +49,9 @@ type TenantConfig struct {
 	MidtransClientKey    string                 `json:"midtrans_client_key,omitempty"`
 	MidtransMerchantID   string                 `json:"midtrans_merchant_id,omitempty"`
 	MidtransEnvironment  string                 `json:"midtrans_environment"`
+	PaymentGateway       string                 `json:"payment_gateway"`
+	XenditSecretKey      string                 `json:"xendit_secret_key,omitempty"`
+	XenditCallbackToken  string                 `json:"xendit_callback_token,omitempty"`
 	CreatedAt            string                 `json:"created_at"`
 	UpdatedAt            string                 `json:"updated_at"`
 }
@@ -64,6 +68,9 @@ func (r *TenantConfigRepository) GetByTenantID(ctx context.Context, tenantID str
 			COALESCE(midtrans_client_key, '') as midtrans_client_key,
 			COALESCE(midtrans_merchant_id, '') as midtrans_merchant_id,
 			COALESCE(midtrans_environment, 'sandbox') as midtrans_environment,
+			COALESCE(payment_gateway, 'midtrans') as payment_gateway,
+			COALESCE(xendit_secret_key, '') as xendit_secret_key,
+			COALESCE(xendit_callback_token, '') as xendit_callback_token,
 			created_at,
 			updated_at
 		FROM tenant_configs
@@ -73,6 +80,7 @@ func (r *TenantConfigRepository) GetByTenantID(ctx context.Context, tenantID str
 	var config TenantConfig
 	var serviceArea, deliveryFeeConfig []byte
 	var encryptedServerKey, encryptedClientKey string
+	var encryptedXenditKey, encryptedXenditToken string
 
 	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
 		&config.TenantID,
@@ -84,6 +92,9 @@ func (r *TenantConfigRepository) GetByTenantID(ctx context.Context, tenantID str
 		&encryptedClientKey,
 		&config.MidtransMerchantID,
 		&config.MidtransEnvironment,
+		&config.PaymentGateway,
+		&encryptedXenditKey,
+		&encryptedXenditToken,
 		&config.CreatedAt,
 		&config.UpdatedAt,
 	)
@@ -97,6 +108,7 @@ func (r *TenantConfigRepository) GetByTenantID(ctx context.Context, tenantID str
 			DeliveryFeeConfig:    map[string]interface{}{},
 			AutoCalculateFees:    false,
 			MidtransEnvironment:  "sandbox",
+			PaymentGateway:       "midtrans",
 		}, nil
 	}
 
@@ -119,6 +131,21 @@ func (r *TenantConfigRepository) GetByTenantID(ctx context.Context, tenantID str
 		}
 	}
 
+	// Decrypt Xendit credentials with context
+	if encryptedXenditKey != "" {
+		config.XenditSecretKey, err = r.encryptor.DecryptWithContext(ctx, encryptedXenditKey, "tenant_config:xendit_secret_key")
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt xendit_secret_key: %w", err)
+		}
+	}
+
+	if encryptedXenditToken != "" {
+		config.XenditCallbackToken, err = r.encryptor.DecryptWithContext(ctx, encryptedXenditToken, "tenant_config:xendit_callback_token")
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt xendit_callback_token: %w", err)
+		}
+	}
+
 	// Unmarshal JSON fields
 	if err := json.Unmarshal(serviceArea, &config.ServiceArea); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal service_area: %w", err)
@@ -150,6 +177,16 @@ func (r *TenantConfigRepository) Create(ctx context.Context, config *TenantConfi
 		}
 	}
 
+	encryptedXenditKey, encryptedXenditToken, err := r.encryptXenditCredentials(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	paymentGateway := config.PaymentGateway
+	if paymentGateway == "" {
+		paymentGateway = "midtrans"
+	}
+
 	serviceArea, err := json.Marshal(config.ServiceArea)
 	if err != nil {
 		return fmt.Errorf("failed to marshal service_area: %w", err)
@@ -170,8 +207,11 @@ func (r *TenantConfigRepository) Create(ctx context.Context, config *TenantConfi
 			midtrans_server_key,
 			midtrans_client_key,
 			midtrans_merchant_id,
-			midtrans_environment
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			midtrans_environment,
+			payment_gateway,
+			xendit_secret_key,
+			xendit_callback_token
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
 	_, err = r.db.ExecContext(
@@ -186,6 +226,9 @@ func (r *TenantConfigRepository) Create(ctx context.Context, config *TenantConfi
 		encryptedClientKey,
 		config.MidtransMerchantID,
 		config.MidtransEnvironment,
+		paymentGateway,
+		encryptedXenditKey,
+		encryptedXenditToken,
 	)
 
 	if err != nil {
@@ -214,6 +257,16 @@ func (r *TenantConfigRepository) Update(ctx context.Context, config *TenantConfi
 		}
 	}
 
+	encryptedXenditKey, encryptedXenditToken, err := r.encryptXenditCredentials(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	paymentGateway := config.PaymentGateway
+	if paymentGateway == "" {
+		paymentGateway = "midtrans"
+	}
+
 	serviceArea, err := json.Marshal(config.ServiceArea)
 	if err != nil {
 		return fmt.Errorf("failed to marshal service_area: %w", err)
@@ -235,6 +288,9 @@ func (r *TenantConfigRepository) Update(ctx context.Context, config *TenantConfi
 			midtrans_client_key = $7,
 			midtrans_merchant_id = $8,
 			midtrans_environment = $9,
+			payment_gateway = $10,
+			xendit_secret_key = $11,
+			xendit_callback_token = $12,
 			updated_at = NOW()
 		WHERE tenant_id = $1
 	`
@@ -251,6 +307,9 @@ func (r *TenantConfigRepository) Update(ctx context.Context, config *TenantConfi
 		encryptedClientKey,
 		config.MidtransMerchantID,
 		config.MidtransEnvironment,
+		paymentGateway,
+		encryptedXenditKey,
+		encryptedXenditToken,
 	)
 
 	if err != nil {
@@ -267,12 +326,14 @@ func (r *TenantConfigRepository) Update(ctx context.Context, config *TenantConfi
 	}
 
 	// T102: Publish ConfigUpdatedEvent when payment credentials changed
-	if r.auditPublisher != nil && (config.MidtransServerKey != "" || config.MidtransClientKey != "") {
+	if r.auditPublisher != nil && (config.MidtransServerKey != "" || config.MidtransClientKey != "" || config.XenditSecretKey != "") {
 		afterValue := map[string]interface{}{
 			"midtrans_server_key": encryptedServerKey,
 			"midtrans_client_key": encryptedClientKey,
 			"midtrans_merchant_id": config.MidtransMerchantID,
 			"midtrans_environment": config.MidtransEnvironment,
+			"payment_gateway":      paymentGateway,
+			"xendit_secret_key":    encryptedXenditKey,
 		}
 
 		auditEvent := &utils.AuditEvent{
@@ -294,3 +355,133 @@ func (r *TenantConfigRepository) Update(ctx context.Context, config *TenantConfi
 
 	return nil
 }
+
+// encryptXenditCredentials encrypts the Xendit secret key and callback token for storage
+func (r *TenantConfigRepository) encryptXenditCredentials(ctx context.Context, config *TenantConfig) (string, string, error) {
+	var encryptedKey, encryptedToken string
+	var err error
+
+	if config.XenditSecretKey != "" {
+		encryptedKey, err = r.encryptor.EncryptWithContext(ctx, config.XenditSecretKey, "tenant_config:xendit_secret_key")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encrypt xendit_secret_key: %w", err)
+		}
+	}
+
+	if config.XenditCallbackToken != "" {
+		encryptedToken, err = r.encryptor.EncryptWithContext(ctx, config.XenditCallbackToken, "tenant_config:xendit_callback_token")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encrypt xendit_callback_token: %w", err)
+		}
+	}
+
+	return encryptedKey, encryptedToken, nil
+}
+
+// deliveryFeeRates is the distance pricing stored in delivery_fee_config
+type deliveryFeeRates struct {
+	BaseFee    int     `json:"base_fee"`
+	PerKmFee   int     `json:"per_km_fee"`
+	IncludedKm float64 `json:"included_km"`
+	MaxFee     int     `json:"max_fee"`
+}
+
+// GetDeliveryFeeConfig returns a tenant's delivery pricing, or flat pricing when none was saved
+func (r *TenantConfigRepository) GetDeliveryFeeConfig(ctx context.Context, tenantID string) (*models.DeliveryFeeConfig, error) {
+	query := `
+		SELECT
+			COALESCE(enable_delivery_fee_calculation, false),
+			COALESCE(delivery_fee_type, 'flat'),
+			location_lat,
+			location_lng,
+			COALESCE(delivery_fee_config, '{}'::jsonb)
+		FROM tenant_configs
+		WHERE tenant_id = $1
+	`
+
+	config := models.DeliveryFeeConfig{TenantID: tenantID}
+	var latitude, longitude sql.NullFloat64
+	var rawRates []byte
+
+	err := r.db.QueryRowContext(ctx, query, tenantID).Scan(
+		&config.Enabled,
+		&config.Type,
+		&latitude,
+		&longitude,
+		&rawRates,
+	)
+	if err == sql.ErrNoRows {
+		config.Type = models.DeliveryFeeFlat
+		return &config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery fee config: %w", err)
+	}
+
+	// Zone pricing moved to the order service's delivery zones
+	if config.Type != models.DeliveryFeeDistance {
+		config.Type = models.DeliveryFeeFlat
+	}
+	if latitude.Valid && longitude.Valid {
+		config.OriginLatitude = &latitude.Float64
+		config.OriginLongitude = &longitude.Float64
+	}
+
+	var rates deliveryFeeRates
+	if err := json.Unmarshal(rawRates, &rates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal delivery_fee_config: %w", err)
+	}
+	config.BaseFee = rates.BaseFee
+	config.PerKmFee = rates.PerKmFee
+	config.IncludedKm = rates.IncludedKm
+	config.MaxFee = rates.MaxFee
+
+	return &config, nil
+}
+
+// UpsertDeliveryFeeConfig saves a tenant's delivery pricing without touching its other settings
+func (r *TenantConfigRepository) UpsertDeliveryFeeConfig(ctx context.Context, config *models.DeliveryFeeConfig) error {
+	rates, err := json.Marshal(deliveryFeeRates{
+		BaseFee:    config.BaseFee,
+		PerKmFee:   config.PerKmFee,
+		IncludedKm: config.IncludedKm,
+		MaxFee:     config.MaxFee,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery_fee_config: %w", err)
+	}
+
+	query := `
+		INSERT INTO tenant_configs (
+			tenant_id,
+			enable_delivery_fee_calculation,
+			delivery_fee_type,
+			location_lat,
+			location_lng,
+			delivery_fee_config
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			enable_delivery_fee_calculation = EXCLUDED.enable_delivery_fee_calculation,
+			delivery_fee_type = EXCLUDED.delivery_fee_type,
+			location_lat = EXCLUDED.location_lat,
+			location_lng = EXCLUDED.location_lng,
+			delivery_fee_config = EXCLUDED.delivery_fee_config,
+			updated_at = NOW()
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		config.TenantID,
+		config.Enabled,
+		config.Type,
+		config.OriginLatitude,
+		config.OriginLongitude,
+		rates,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save delivery fee config: %w", err)
+	}
+
+	return nil
+}
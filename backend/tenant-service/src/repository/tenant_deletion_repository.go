@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pos/tenant-service/src/models"
+)
+
+// TenantDeletionRepository stores tenant deletions and the acknowledgements of the services taking part
+type TenantDeletionRepository struct {
+	db *sql.DB
+}
+
+func NewTenantDeletionRepository(db *sql.DB) *TenantDeletionRepository {
+	return &TenantDeletionRepository{db: db}
+}
+
+const tenantDeletionColumns = `id, tenant_id, tenant_slug, requested_by, COALESCE(reason, ''), status, stage, error_msg, certificate_id, requested_at, completed_at`
+
+func scanTenantDeletion(row interface{ Scan(...interface{}) error }) (*models.TenantDeletion, error) {
+	deletion := &models.TenantDeletion{}
+	var requestedBy, errorMsg, certificateID sql.NullString
+	var completedAt sql.NullTime
+	err := row.Scan(&deletion.ID, &deletion.TenantID, &deletion.TenantSlug, &requestedBy, &deletion.Reason,
+		&deletion.Status, &deletion.Stage, &errorMsg, &certificateID, &deletion.RequestedAt, &completedAt)
+	if err != nil {
+		return nil, err
+	}
+	if requestedBy.Valid {
+		deletion.RequestedBy = &requestedBy.String
+	}
+	if errorMsg.Valid {
+		deletion.Error = &errorMsg.String
+	}
+	if certificateID.Valid {
+		deletion.CertificateID = &certificateID.String
+	}
+	if completedAt.Valid {
+		deletion.CompletedAt = &completedAt.Time
+	}
+	return deletion, nil
+}
+
+// Start records a new deletion of a tenant pending deletion, with one pending step per service
+// Returns nil when the tenant is not pending deletion or another deletion of it is already in progress.
+func (r *TenantDeletionRepository) Start(ctx context.Context, tenantID, operatorID, reason string, stages [][]string) (*models.TenantDeletion, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO tenant_deletions (tenant_id, tenant_slug, requested_by, reason)
+		SELECT id, slug, $2, NULLIF($3, '')
+		FROM tenants
+		WHERE id = $1 AND status = 'pending_deletion'
+		ON CONFLICT (tenant_id) WHERE status = 'in_progress' DO NOTHING
+		RETURNING `+tenantDeletionColumns,
+		tenantID, operatorID, reason)
+	deletion, err := scanTenantDeletion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tenant deletion: %w", err)
+	}
+
+	for i, services := range stages {
+		for _, service := range services {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO tenant_deletion_steps (deletion_id, service, stage)
+				VALUES ($1, $2, $3)
+			`, deletion.ID, service, i+1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create tenant deletion step: %w", err)
+			}
+			deletion.Steps = append(deletion.Steps, models.TenantDeletionStep{
+				Service: service,
+				Stage:   i + 1,
+				Status:  models.DeletionStepPending,
+				Deleted: map[string]int64{},
+			})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tenant deletion: %w", err)
+	}
+	return deletion, nil
+}
+
+// FindByID returns a deletion with its steps, or nil
+func (r *TenantDeletionRepository) FindByID(ctx context.Context, id string) (*models.TenantDeletion, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+tenantDeletionColumns+`
+		FROM tenant_deletions
+		WHERE id = $1
+	`, id)
+	return r.withSteps(ctx, row)
+}
+
+// FindLatest returns the tenant's most recent deletion with its steps, or nil
+func (r *TenantDeletionRepository) FindLatest(ctx context.Context, tenantID string) (*models.TenantDeletion, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+tenantDeletionColumns+`
+		FROM tenant_deletions
+		WHERE tenant_id = $1
+		ORDER BY requested_at DESC
+		LIMIT 1
+	`, tenantID)
+	return r.withSteps(ctx, row)
+}
+
+func (r *TenantDeletionRepository) withSteps(ctx context.Context, row *sql.Row) (*models.TenantDeletion, error) {
+	deletion, err := scanTenantDeletion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT service, stage, status, deleted, error_msg, acknowledged_at
+		FROM tenant_deletion_steps
+		WHERE deletion_id = $1
+		ORDER BY stage ASC, service ASC
+	`, deletion.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant deletion steps: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		step := models.TenantDeletionStep{}
+		var deleted []byte
+		var errorMsg sql.NullString
+		var acknowledgedAt sql.NullTime
+		if err := rows.Scan(&step.Service, &step.Stage, &step.Status, &deleted, &errorMsg, &acknowledgedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(deleted, &step.Deleted); err != nil {
+			return nil, fmt.Errorf("invalid deletion counts for %s: %w", step.Service, err)
+		}
+		if errorMsg.Valid {
+			step.Error = &errorMsg.String
+		}
+		if acknowledgedAt.Valid {
+			step.AcknowledgedAt = &acknowledgedAt.Time
+		}
+		deletion.Steps = append(deletion.Steps, step)
+	}
+	return deletion, rows.Err()
+}
+
+// RecordAcknowledgement records a service's answer for its step of an in-progress deletion of the tenant
+// Returns false when there is no such step or it has already completed.
+func (r *TenantDeletionRepository) RecordAcknowledgement(ctx context.Context, tenantID string, ack *models.TenantDeletionAck) (bool, error) {
+	deleted, err := json.Marshal(ack.Deleted)
+	if err != nil {
+		return false, err
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_deletion_steps s
+		SET status = $4, deleted = $5, error_msg = NULLIF($6, ''), acknowledged_at = NOW()
+		FROM tenant_deletions d
+		WHERE s.deletion_id = d.id AND d.id = $1 AND d.tenant_id = $2 AND d.status = 'in_progress'
+		  AND s.service = $3 AND s.status <> 'completed'
+	`, ack.DeletionID, tenantID, ack.Service, ack.Status, deleted, ack.Error)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ResetFailed moves failed steps back to pending before their services are asked again
+func (r *TenantDeletionRepository) ResetFailed(ctx context.Context, deletionID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_deletion_steps
+		SET status = 'pending', error_msg = NULL
+		WHERE deletion_id = $1 AND status = 'failed'
+	`, deletionID)
+	return err
+}
+
+// AdvanceStage moves an in-progress deletion from stage to the next one
+// Returns false when it has already been moved, so each stage is requested once.
+func (r *TenantDeletionRepository) AdvanceStage(ctx context.Context, deletionID string, stage int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_deletions
+		SET stage = stage + 1
+		WHERE id = $1 AND stage = $2 AND status = 'in_progress'
+	`, deletionID, stage)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// RecordError keeps the reason the deletion could not be finished
+func (r *TenantDeletionRepository) RecordError(ctx context.Context, deletionID, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_deletions SET error_msg = $1 WHERE id = $2
+	`, reason, deletionID)
+	return err
+}
+
+// Complete deletes the tenant row, cascading to what remains of its data, and completes the deletion
+// in one transaction. Returns false when the deletion is no longer in progress or a step has not completed.
+func (r *TenantDeletionRepository) Complete(ctx context.Context, deletion *models.TenantDeletion, certificateID string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		SELECT status FROM tenant_deletions WHERE id = $1 FOR UPDATE
+	`, deletion.ID).Scan(&status)
+	if err != nil {
+		return false, err
+	}
+	var incomplete int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM tenant_deletion_steps WHERE deletion_id = $1 AND status <> 'completed'
+	`, deletion.ID).Scan(&incomplete)
+	if err != nil {
+		return false, err
+	}
+	if status != models.TenantDeletionInProgress || incomplete > 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tenants WHERE id = $1`, deletion.TenantID); err != nil {
+		return false, fmt.Errorf("failed to delete tenant: %w", err)
+	}
+
+	var completedAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		UPDATE tenant_deletions
+		SET status = 'completed', certificate_id = $1, error_msg = NULL, completed_at = NOW()
+		WHERE id = $2
+		RETURNING completed_at
+	`, certificateID, deletion.ID).Scan(&completedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete tenant deletion: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit tenant deletion: %w", err)
+	}
+	deletion.Status = models.TenantDeletionCompleted
+	deletion.CertificateID = &certificateID
+	deletion.CompletedAt = &completedAt
+	deletion.Error = nil
+	return true, nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/pos/tenant-service/src/models"
+)
+
+type TenantDomainRepository struct {
+	db *sql.DB
+}
+
+func NewTenantDomainRepository(db *sql.DB) *TenantDomainRepository {
+	return &TenantDomainRepository{db: db}
+}
+
+func (r *TenantDomainRepository) ListByTenantID(ctx context.Context, tenantID string) ([]models.TenantDomain, error) {
+	query := `
+		SELECT id, tenant_id, domain, is_active, verification_token, verified_at, created_at, updated_at
+		FROM tenant_domains
+		WHERE tenant_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := []models.TenantDomain{}
+	for rows.Next() {
+		var d models.TenantDomain
+		if err := rows.Scan(&d.ID, &d.TenantID, &d.Domain, &d.IsActive, &d.VerificationToken, &d.VerifiedAt, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		domains = append(domains, d)
+	}
+
+	return domains, rows.Err()
+}
+
+// Create registers an unverified domain for the tenant
+// Returns ErrDomainAlreadyExists when the tenant already registered it or another tenant has verified it.
+// Unverified claims by other tenants do not block it; whoever verifies the domain first keeps it.
+func (r *TenantDomainRepository) Create(ctx context.Context, domain *models.TenantDomain) error {
+	query := `
+		INSERT INTO tenant_domains (id, tenant_id, domain, is_active, verification_token, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7
+		WHERE NOT EXISTS (
+			SELECT 1 FROM tenant_domains WHERE domain = $3 AND verified_at IS NOT NULL
+		)
+	`
+
+	if domain.ID == "" {
+		domain.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	domain.CreatedAt = now
+	domain.UpdatedAt = now
+	domain.IsActive = true
+
+	result, err := r.db.ExecContext(ctx, query,
+		domain.ID,
+		domain.TenantID,
+		domain.Domain,
+		domain.IsActive,
+		domain.VerificationToken,
+		domain.CreatedAt,
+		domain.UpdatedAt,
+	)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return models.ErrDomainAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrDomainAlreadyExists
+	}
+
+	return nil
+}
+
+func (r *TenantDomainRepository) Delete(ctx context.Context, tenantID, domainID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM tenant_domains WHERE id = $1 AND tenant_id = $2`,
+		domainID, tenantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrDomainNotFound
+	}
+
+	return nil
+}
+
+// FindByID returns one of a tenant's domains
+func (r *TenantDomainRepository) FindByID(ctx context.Context, tenantID, domainID string) (*models.TenantDomain, error) {
+	query := `
+		SELECT id, tenant_id, domain, is_active, verification_token, verified_at, created_at, updated_at
+		FROM tenant_domains
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	var d models.TenantDomain
+	err := r.db.QueryRowContext(ctx, query, domainID, tenantID).Scan(
+		&d.ID, &d.TenantID, &d.Domain, &d.IsActive, &d.VerificationToken, &d.VerifiedAt, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// MarkVerified records that the domain's verification TXT record was found
+// The tenant takes the domain from other tenants' unverified claims. Returns ErrDomainAlreadyExists
+// when another tenant verified it first.
+func (r *TenantDomainRepository) MarkVerified(ctx context.Context, domain *models.TenantDomain) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx,
+		`UPDATE tenant_domains SET verified_at = $1, updated_at = $1 WHERE id = $2 AND tenant_id = $3`,
+		now, domain.ID, domain.TenantID,
+	)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return models.ErrDomainAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM tenant_domains WHERE domain = $1 AND tenant_id <> $2 AND verified_at IS NULL`,
+		domain.Domain, domain.TenantID,
+	); err != nil {
+		return fmt.Errorf("failed to remove unverified claims: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	domain.VerifiedAt = &now
+	domain.UpdatedAt = now
+	return nil
+}
+
+// FindActiveByDomain resolves a storefront domain to its tenant
+// Only active, verified domains belonging to active tenants are returned
+func (r *TenantDomainRepository) FindActiveByDomain(ctx context.Context, domain string) (*models.TenantDomain, error) {
+	query := `
+		SELECT d.id, d.tenant_id, d.domain, d.is_active, d.verification_token, d.verified_at, d.created_at, d.updated_at, t.slug
+		FROM tenant_domains d
+		JOIN tenants t ON t.id = d.tenant_id
+		WHERE d.domain = $1 AND d.is_active = TRUE AND d.verified_at IS NOT NULL AND t.status = 'active'
+	`
+
+	var d models.TenantDomain
+	err := r.db.QueryRowContext(ctx, query, domain).Scan(
+		&d.ID, &d.TenantID, &d.Domain, &d.IsActive, &d.VerificationToken, &d.VerifiedAt, &d.CreatedAt, &d.UpdatedAt, &d.TenantSlug,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// FindActiveBySlug resolves a platform subdomain (<slug>.<base domain>) to its tenant
+// These hosts are not stored; the result carries only the tenant and the host asked for
+func (r *TenantDomainRepository) FindActiveBySlug(ctx context.Context, slug, domain string) (*models.TenantDomain, error) {
+	d := models.TenantDomain{Domain: domain, IsActive: true, TenantSlug: slug}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id FROM tenants WHERE slug = $1 AND status = 'active'`,
+		slug,
+	).Scan(&d.TenantID)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrDomainNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
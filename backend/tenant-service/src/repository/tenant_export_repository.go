@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/utils"
+)
+
+// TenantExportRepository stores tenant data exports and reads the records they are assembled from
+// Products, orders, consents and the audit trail belong to other services; they are read from the shared database.
+type TenantExportRepository struct {
+	db        *sql.DB
+	encryptor utils.Encryptor
+}
+
+func NewTenantExportRepository(db *sql.DB, encryptor utils.Encryptor) *TenantExportRepository {
+	return &TenantExportRepository{db: db, encryptor: encryptor}
+}
+
+const tenantExportColumns = `id, tenant_id, requested_by, status, storage_key, size_bytes, error_msg, expires_at, completed_at, created_at`
+
+func scanTenantExport(row interface{ Scan(...interface{}) error }) (*models.TenantExport, error) {
+	export := &models.TenantExport{}
+	var requestedBy, storageKey, errorMsg sql.NullString
+	var sizeBytes sql.NullInt64
+	var expiresAt, completedAt sql.NullTime
+	err := row.Scan(&export.ID, &export.TenantID, &requestedBy, &export.Status, &storageKey,
+		&sizeBytes, &errorMsg, &expiresAt, &completedAt, &export.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if requestedBy.Valid {
+		export.RequestedBy = &requestedBy.String
+	}
+	export.StorageKey = storageKey.String
+	if sizeBytes.Valid {
+		export.SizeBytes = &sizeBytes.Int64
+	}
+	if errorMsg.Valid {
+		export.Error = &errorMsg.String
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	return export, nil
+}
+
+// Create saves a new pending export
+func (r *TenantExportRepository) Create(ctx context.Context, tenantID, requestedBy string) (*models.TenantExport, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO tenant_data_exports (tenant_id, requested_by, status)
+		VALUES ($1, NULLIF($2, '')::uuid, 'pending')
+		RETURNING `+tenantExportColumns,
+		tenantID, requestedBy)
+	return scanTenantExport(row)
+}
+
+// FindPending returns the tenant's export still being assembled, or nil
+func (r *TenantExportRepository) FindPending(ctx context.Context, tenantID string) (*models.TenantExport, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+tenantExportColumns+`
+		FROM tenant_data_exports
+		WHERE tenant_id = $1 AND status = 'pending'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, tenantID)
+	export, err := scanTenantExport(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return export, err
+}
+
+// FindByID returns one of the tenant's exports, or nil
+func (r *TenantExportRepository) FindByID(ctx context.Context, tenantID, id string) (*models.TenantExport, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+tenantExportColumns+`
+		FROM tenant_data_exports
+		WHERE id = $1 AND tenant_id = $2
+	`, id, tenantID)
+	export, err := scanTenantExport(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return export, err
+}
+
+// ListByTenantID returns the tenant's exports, newest first
+func (r *TenantExportRepository) ListByTenantID(ctx context.Context, tenantID string) ([]*models.TenantExport, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+tenantExportColumns+`
+		FROM tenant_data_exports
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exports := make([]*models.TenantExport, 0)
+	for rows.Next() {
+		export, err := scanTenantExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	return exports, rows.Err()
+}
+
+// MarkReady records the stored archive and starts the download link lifetime
+func (r *TenantExportRepository) MarkReady(ctx context.Context, export *models.TenantExport, storageKey string, size int64, expiresAt time.Time) error {
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_data_exports
+		SET status = 'ready', storage_key = $1, size_bytes = $2, expires_at = $3, completed_at = $4
+		WHERE id = $5
+	`, storageKey, size, expiresAt, now, export.ID)
+	if err != nil {
+		return err
+	}
+	export.Status = models.TenantExportReady
+	export.StorageKey = storageKey
+	export.SizeBytes = &size
+	export.ExpiresAt = &expiresAt
+	export.CompletedAt = &now
+	return nil
+}
+
+// MarkFailed records why an export could not be assembled
+func (r *TenantExportRepository) MarkFailed(ctx context.Context, id, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_data_exports
+		SET status = 'failed', error_msg = $1, completed_at = NOW()
+		WHERE id = $2
+	`, reason, id)
+	return err
+}
+
+// ListExpired returns exports whose download link has expired, and failed or stuck ones older than a day
+func (r *TenantExportRepository) ListExpired(ctx context.Context) ([]*models.TenantExport, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+tenantExportColumns+`
+		FROM tenant_data_exports
+		WHERE expires_at < NOW()
+		   OR (status <> 'ready' AND created_at < NOW() - INTERVAL '1 day')
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exports := make([]*models.TenantExport, 0)
+	for rows.Next() {
+		export, err := scanTenantExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	return exports, rows.Err()
+}
+
+// Delete removes an export record
+func (r *TenantExportRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM tenant_data_exports WHERE id = $1`, id)
+	return err
+}
+
+// ListProducts returns the tenant's whole catalog, archived products included, with their photos
+func (r *TenantExportRepository) ListProducts(ctx context.Context, tenantID string) ([]*models.ExportedProduct, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT p.id, p.sku, p.name, p.description, p.category_id, c.name, p.selling_price, p.cost_price,
+		       p.tax_rate, p.stock_quantity, p.archived_at, p.created_at, p.updated_at
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE p.tenant_id = $1
+		ORDER BY p.created_at ASC
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]*models.ExportedProduct, 0)
+	byID := make(map[string]*models.ExportedProduct)
+	for rows.Next() {
+		product := &models.ExportedProduct{Photos: []models.ExportedPhoto{}}
+		var description, categoryID, categoryName sql.NullString
+		var archivedAt sql.NullTime
+		err := rows.Scan(&product.ID, &product.SKU, &product.Name, &description, &categoryID, &categoryName,
+			&product.SellingPrice, &product.CostPrice, &product.TaxRate, &product.StockQuantity,
+			&archivedAt, &product.CreatedAt, &product.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		if description.Valid {
+			product.Description = &description.String
+		}
+		if categoryID.Valid {
+			product.CategoryID = &categoryID.String
+			product.CategoryName = &categoryName.String
+		}
+		if archivedAt.Valid {
+			product.ArchivedAt = &archivedAt.Time
+		}
+		products = append(products, product)
+		byID[product.ID] = product
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	photoRows, err := r.db.QueryContext(ctx, `
+		SELECT id, product_id, storage_key, original_filename, mime_type, file_size_bytes, display_order, is_primary
+		FROM product_photos
+		WHERE tenant_id = $1
+		ORDER BY product_id, display_order
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product photos: %w", err)
+	}
+	defer photoRows.Close()
+
+	for photoRows.Next() {
+		var photo models.ExportedPhoto
+		err := photoRows.Scan(&photo.ID, &photo.ProductID, &photo.StorageKey, &photo.OriginalFilename,
+			&photo.MimeType, &photo.SizeBytes, &photo.DisplayOrder, &photo.IsPrimary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product photo: %w", err)
+		}
+		if product, ok := byID[photo.ProductID]; ok {
+			product.Photos = append(product.Photos, photo)
+		}
+	}
+	return products, photoRows.Err()
+}
+
+// EachOrder calls fn with every order of the tenant, live and archived, oldest first
+// Orders are streamed so that large order histories are not held in memory.
+func (r *TenantExportRepository) EachOrder(ctx context.Context, tenantID string, fn func(*models.ExportedOrder) error) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT o.id, o.order_reference, o.status, o.order_type, o.delivery_type, o.table_number,
+		       o.subtotal_amount, o.delivery_fee, o.discount_amount, o.promotion_discount_amount,
+		       o.loyalty_discount_amount, o.service_charge_amount, o.tax_amount, o.total_amount,
+		       CASE WHEN o.is_anonymized THEN NULL ELSE o.customer_name END,
+		       CASE WHEN o.is_anonymized THEN NULL ELSE o.customer_phone END,
+		       CASE WHEN o.is_anonymized THEN NULL ELSE o.customer_email END,
+		       o.notes, false,
+		       COALESCE((
+		           SELECT json_agg(json_build_object(
+		               'product_id', i.product_id, 'product_name', i.product_name, 'product_sku', i.product_sku,
+		               'quantity', i.quantity, 'unit_price', i.unit_price, 'total_price', i.total_price
+		           ) ORDER BY i.created_at)
+		           FROM order_items i WHERE i.order_id = o.id
+		       ), '[]'),
+		       o.created_at, o.paid_at, o.completed_at, o.cancelled_at
+		FROM guest_orders o
+		WHERE o.tenant_id = $1
+		UNION ALL
+		SELECT a.id, a.order_reference, a.status, a.order_type, a.delivery_type, a.table_number,
+		       a.subtotal_amount, a.delivery_fee, a.discount_amount, a.promotion_discount_amount,
+		       a.loyalty_discount_amount, a.service_charge_amount, a.tax_amount, a.total_amount,
+		       NULL, NULL, NULL, NULL, true,
+		       COALESCE((
+		           SELECT json_agg(json_build_object(
+		               'product_id', i.product_id, 'product_name', i.product_name, 'product_sku', i.product_sku,
+		               'quantity', i.quantity, 'unit_price', i.unit_price, 'total_price', i.total_price
+		           ) ORDER BY i.id)
+		           FROM archived_order_items i WHERE i.order_id = a.id AND i.order_created_at = a.created_at
+		       ), '[]'),
+		       a.created_at, a.paid_at, a.completed_at, a.cancelled_at
+		FROM archived_guest_orders a
+		WHERE a.tenant_id = $1
+		ORDER BY created_at ASC
+	`, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to list orders: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order := &models.ExportedOrder{}
+		var tableNumber, name, phone, email, notes sql.NullString
+		var items []byte
+		var paidAt, completedAt, cancelledAt sql.NullTime
+		err := rows.Scan(&order.ID, &order.OrderReference, &order.Status, &order.OrderType, &order.DeliveryType, &tableNumber,
+			&order.SubtotalAmount, &order.DeliveryFee, &order.DiscountAmount, &order.PromotionDiscountAmount,
+			&order.LoyaltyDiscountAmount, &order.ServiceChargeAmount, &order.TaxAmount, &order.TotalAmount,
+			&name, &phone, &email, &notes, &order.Archived, &items,
+			&order.CreatedAt, &paidAt, &completedAt, &cancelledAt)
+		if err != nil {
+			return fmt.Errorf("failed to scan order: %w", err)
+		}
+		order.Items = items
+		if tableNumber.Valid {
+			order.TableNumber = &tableNumber.String
+		}
+		if notes.Valid {
+			order.Notes = &notes.String
+		}
+		if paidAt.Valid {
+			order.PaidAt = &paidAt.Time
+		}
+		if completedAt.Valid {
+			order.CompletedAt = &completedAt.Time
+		}
+		if cancelledAt.Valid {
+			order.CancelledAt = &cancelledAt.Time
+		}
+
+		// Customer contact is encrypted by the order service
+		if order.CustomerName, err = r.decrypt(ctx, name, "guest_order:customer_name"); err != nil {
+			return err
+		}
+		if order.CustomerPhone, err = r.decrypt(ctx, phone, "guest_order:customer_phone"); err != nil {
+			return err
+		}
+		if order.CustomerEmail, err = r.decrypt(ctx, email, "guest_order:customer_email"); err != nil {
+			return err
+		}
+
+		if err := fn(order); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *TenantExportRepository) decrypt(ctx context.Context, value sql.NullString, encContext string) (*string, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	decrypted, err := r.encryptor.DecryptWithContext(ctx, value.String, encContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", encContext, err)
+	}
+	return &decrypted, nil
+}
+
+// ListConsents returns every consent grant and revocation recorded for the tenant, oldest first
+func (r *TenantExportRepository) ListConsents(ctx context.Context, tenantID string) ([]*models.ExportedConsent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cr.id, cr.subject_type, cr.subject_id, cr.guest_order_id, cp.purpose_code, cr.granted,
+		       cr.policy_version, cr.consent_method, cr.granted_at, cr.revoked_at
+		FROM consent_records cr
+		JOIN consent_purposes cp ON cp.id = cr.purpose_id
+		WHERE cr.tenant_id = $1
+		ORDER BY cr.granted_at ASC
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consent records: %w", err)
+	}
+	defer rows.Close()
+
+	consents := make([]*models.ExportedConsent, 0)
+	for rows.Next() {
+		consent := &models.ExportedConsent{}
+		var subjectID, guestOrderID sql.NullString
+		var revokedAt sql.NullTime
+		err := rows.Scan(&consent.ID, &consent.SubjectType, &subjectID, &guestOrderID, &consent.Purpose, &consent.Granted,
+			&consent.PolicyVersion, &consent.ConsentMethod, &consent.GrantedAt, &revokedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan consent record: %w", err)
+		}
+		if subjectID.Valid {
+			consent.SubjectID = &subjectID.String
+		}
+		if guestOrderID.Valid {
+			consent.GuestOrderID = &guestOrderID.String
+		}
+		if revokedAt.Valid {
+			consent.RevokedAt = &revokedAt.Time
+		}
+		consents = append(consents, consent)
+	}
+	return consents, rows.Err()
+}
+
+// AuditSummary counts the tenant's audit trail entries by action and resource type
+func (r *TenantExportRepository) AuditSummary(ctx context.Context, tenantID string) ([]*models.AuditSummaryEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT action, resource_type, COUNT(*), MIN(timestamp), MAX(timestamp)
+		FROM audit_events
+		WHERE tenant_id = $1
+		GROUP BY action, resource_type
+		ORDER BY resource_type, action
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize audit trail: %w", err)
+	}
+	defer rows.Close()
+
+	summary := make([]*models.AuditSummaryEntry, 0)
+	for rows.Next() {
+		entry := &models.AuditSummaryEntry{}
+		if err := rows.Scan(&entry.Action, &entry.ResourceType, &entry.Count, &entry.FirstAt, &entry.LastAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit summary: %w", err)
+		}
+		summary = append(summary, entry)
+	}
+	return summary, rows.Err()
+}
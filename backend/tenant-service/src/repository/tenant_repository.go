@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/pos/tenant-service/src/models"
 )
 
@@ -122,3 +123,65 @@ func (r *TenantRepository) Update(ctx context.Context, tenant *models.Tenant) er
 
 	return err
 }
+
+// GetLifecycle returns a tenant's status and who last changed it, or nil when the tenant does not exist
+func (r *TenantRepository) GetLifecycle(ctx context.Context, id string) (*models.TenantLifecycle, error) {
+	query := `
+		SELECT id, slug, status, COALESCE(status_reason, ''), status_changed_at, status_changed_by
+		FROM tenants
+		WHERE id = $1 AND status != 'deleted'
+	`
+
+	lifecycle := &models.TenantLifecycle{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&lifecycle.TenantID,
+		&lifecycle.Slug,
+		&lifecycle.Status,
+		&lifecycle.StatusReason,
+		&lifecycle.StatusChangedAt,
+		&lifecycle.StatusChangedBy,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lifecycle, nil
+}
+
+// ChangeStatus moves a tenant to status when it is currently in one of from
+// Returns nil without changing anything when the tenant is in another status or its data is being deleted.
+func (r *TenantRepository) ChangeStatus(ctx context.Context, id string, from []string, status models.TenantStatus, reason, operatorID string) (*models.TenantLifecycle, error) {
+	query := `
+		UPDATE tenants
+		SET status = $2, status_reason = NULLIF($3, ''), status_changed_at = NOW(),
+		    status_changed_by = $4, updated_at = NOW()
+		WHERE id = $1 AND status = ANY($5)
+		  AND NOT EXISTS (SELECT 1 FROM tenant_deletions WHERE tenant_id = $1 AND status = 'in_progress')
+		RETURNING id, slug, status, COALESCE(status_reason, ''), status_changed_at, status_changed_by
+	`
+
+	lifecycle := &models.TenantLifecycle{}
+	err := r.db.QueryRowContext(ctx, query, id, status, reason, operatorID, pq.Array(from)).Scan(
+		&lifecycle.TenantID,
+		&lifecycle.Slug,
+		&lifecycle.Status,
+		&lifecycle.StatusReason,
+		&lifecycle.StatusChangedAt,
+		&lifecycle.StatusChangedBy,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lifecycle, nil
+}
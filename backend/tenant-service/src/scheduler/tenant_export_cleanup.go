@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+
+	"github.com/pos/tenant-service/src/services"
+	"github.com/robfig/cron/v3"
+)
+
+// TenantExportCleanupScheduler deletes tenant data export archives once their download link has expired
+type TenantExportCleanupScheduler struct {
+	cron                *cron.Cron
+	tenantExportService *services.TenantExportService
+}
+
+func NewTenantExportCleanupScheduler(tenantExportService *services.TenantExportService) *TenantExportCleanupScheduler {
+	return &TenantExportCleanupScheduler{
+		cron:                cron.New(),
+		tenantExportService: tenantExportService,
+	}
+}
+
+// Start schedules the job at quarter past every hour
+func (s *TenantExportCleanupScheduler) Start() error {
+	_, err := s.cron.AddFunc("15 * * * *", func() {
+		deleted, err := s.tenantExportService.PurgeExpired(context.Background())
+		if err != nil {
+			log.Printf("ERROR: Tenant export cleanup job failed: %v", err)
+			return
+		}
+		if deleted > 0 {
+			log.Printf("Tenant export cleanup job: %d deleted", deleted)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	log.Printf("Tenant export cleanup scheduler started (runs hourly)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler
+func (s *TenantExportCleanupScheduler) Stop() {
+	if s.cron != nil {
+		s.cron.Stop()
+		log.Printf("Tenant export cleanup scheduler stopped")
+	}
+}
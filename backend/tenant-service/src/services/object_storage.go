@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pos/tenant-service/src/utils"
+)
+
+// ObjectStorage is the object storage shared with product-service
+// Tenant logos and data export archives live under their own key prefixes; product photos are read from it.
+type ObjectStorage struct {
+	client *minio.Client
+	bucket string
+	urlTTL time.Duration
+}
+
+// NewObjectStorage creates a storage client for the shared bucket
+func NewObjectStorage(cfg utils.StorageConfig) (*ObjectStorage, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	return &ObjectStorage{
+		client: client,
+		bucket: cfg.BucketName,
+		urlTTL: cfg.URLTTL,
+	}, nil
+}
+
+// Upload stores an object under its storage key
+func (s *ObjectStorage) Upload(ctx context.Context, storageKey string, reader io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, storageKey, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", storageKey, err)
+	}
+	return nil
+}
+
+// Open streams an object; the caller closes it
+func (s *ObjectStorage) Open(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, storageKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", storageKey, err)
+	}
+	return object, nil
+}
+
+// PresignedURL returns a short-lived URL to view a logo
+func (s *ObjectStorage) PresignedURL(ctx context.Context, storageKey string) (string, error) {
+	url, err := s.client.PresignedGetObject(ctx, s.bucket, storageKey, s.urlTTL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign logo URL: %w", err)
+	}
+	return url.String(), nil
+}
+
+// PresignedDownloadURL returns a URL valid for ttl that downloads an object as filename
+func (s *ObjectStorage) PresignedDownloadURL(ctx context.Context, storageKey, filename string, ttl time.Duration) (string, error) {
+	params := url.Values{}
+	params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	presigned, err := s.client.PresignedGetObject(ctx, s.bucket, storageKey, ttl, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign download URL: %w", err)
+	}
+	return presigned.String(), nil
+}
+
+// Delete removes an object
+func (s *ObjectStorage) Delete(ctx context.Context, storageKey string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, storageKey, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", storageKey, err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object whose key starts with prefix and returns how many were removed
+func (s *ObjectStorage) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var removed int64
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return removed, fmt.Errorf("failed to list %s: %w", prefix, object.Err)
+		}
+		if err := s.Delete(ctx, object.Key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/repository"
+)
+
+type OutletService struct {
+	outletRepo *repository.OutletRepository
+}
+
+func NewOutletService(outletRepo *repository.OutletRepository) *OutletService {
+	return &OutletService{outletRepo: outletRepo}
+}
+
+func (s *OutletService) ListOutlets(ctx context.Context, tenantID string) ([]models.Outlet, error) {
+	return s.outletRepo.ListByTenantID(ctx, tenantID)
+}
+
+func (s *OutletService) GetOutlet(ctx context.Context, tenantID, outletID string) (*models.Outlet, error) {
+	if _, err := uuid.Parse(outletID); err != nil {
+		return nil, models.ErrOutletNotFound
+	}
+	return s.outletRepo.FindByID(ctx, tenantID, outletID)
+}
+
+func (s *OutletService) CreateOutlet(ctx context.Context, tenantID string, req *models.OutletRequest) (*models.Outlet, error) {
+	outlet, err := req.Outlet()
+	if err != nil {
+		return nil, err
+	}
+	outlet.TenantID = tenantID
+
+	if err := s.checkOutletLimit(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	if err := s.outletRepo.Create(ctx, outlet); err != nil {
+		return nil, err
+	}
+	return outlet, nil
+}
+
+// UpdateOutlet replaces every field of an outlet
+func (s *OutletService) UpdateOutlet(ctx context.Context, tenantID, outletID string, req *models.OutletRequest) (*models.Outlet, error) {
+	if _, err := uuid.Parse(outletID); err != nil {
+		return nil, models.ErrOutletNotFound
+	}
+	outlet, err := req.Outlet()
+	if err != nil {
+		return nil, err
+	}
+	outlet.ID = outletID
+	outlet.TenantID = tenantID
+
+	if err := s.outletRepo.Update(ctx, outlet); err != nil {
+		return nil, err
+	}
+	return outlet, nil
+}
+
+func (s *OutletService) DeleteOutlet(ctx context.Context, tenantID, outletID string) error {
+	if _, err := uuid.Parse(outletID); err != nil {
+		return models.ErrOutletNotFound
+	}
+	return s.outletRepo.Delete(ctx, tenantID, outletID)
+}
+
+// checkOutletLimit returns a *models.PlanLimitError when the tenant's plan allows no more outlets
+func (s *OutletService) checkOutletLimit(ctx context.Context, tenantID string) error {
+	planName, max, used, err := s.outletRepo.OutletPlanUsage(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to count outlets: %w", err)
+	}
+	if max == nil || used < *max {
+		return nil
+	}
+
+	upgrade, err := s.outletRepo.FindOutletUpgradePlan(ctx, used+1)
+	if err != nil {
+		fmt.Printf("Warning: failed to find outlet upgrade plan for tenant %s: %v\n", tenantID, err)
+	}
+	return &models.PlanLimitError{Limit: models.PlanLimitOutlets, Max: *max, PlanName: planName, UpgradePlan: upgrade}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register the decoders used to check uploaded logos
+	_ "image/png"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/repository"
+)
+
+const (
+	// MaxLogoSizeBytes bounds an uploaded logo; invoices skip larger logos
+	MaxLogoSizeBytes = 1024 * 1024
+	// maxLogoPixels bounds the longest edge of an uploaded logo
+	maxLogoPixels = 2048
+)
+
+var (
+	ErrLogoTooLarge    = errors.New("logo file is too large")
+	ErrLogoInvalid     = errors.New("logo must be a PNG or JPEG image")
+	ErrLogoDimensions  = errors.New("logo dimensions are not supported")
+	ErrLogoUnavailable = errors.New("logo storage is not configured")
+)
+
+// TenantBrandingService manages how a tenant's storefront, invoices and receipts look
+// storage is nil when no object storage is configured; logos then cannot be uploaded, but a
+// logo_url can still be set.
+type TenantBrandingService struct {
+	brandingRepo *repository.TenantBrandingRepository
+	storage      *ObjectStorage
+}
+
+func NewTenantBrandingService(brandingRepo *repository.TenantBrandingRepository, storage *ObjectStorage) *TenantBrandingService {
+	return &TenantBrandingService{brandingRepo: brandingRepo, storage: storage}
+}
+
+// LogoStorageKey returns where a tenant logo is stored
+// Format: logos/{tenant_id}/{logo_id}.{png|jpg}
+func LogoStorageKey(tenantID, logoID, extension string) string {
+	return fmt.Sprintf("logos/%s/%s.%s", tenantID, logoID, extension)
+}
+
+// GetBranding returns the tenant's branding with a short-lived link to an uploaded logo
+func (s *TenantBrandingService) GetBranding(ctx context.Context, tenantID string) (*models.TenantBranding, error) {
+	branding, err := s.brandingRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	s.resolveLogoURL(ctx, branding)
+	return branding, nil
+}
+
+// UpdateBranding applies the set fields of req to the tenant's branding and saves it
+// Setting logo_url replaces an uploaded logo, which is then deleted.
+func (s *TenantBrandingService) UpdateBranding(ctx context.Context, tenantID string, req *models.UpdateTenantBrandingRequest) (*models.TenantBranding, error) {
+	branding, err := s.brandingRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	previousLogo := branding.LogoStorageKey
+
+	branding.Apply(req)
+	if err := branding.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.brandingRepo.Upsert(ctx, branding); err != nil {
+		return nil, err
+	}
+
+	if previousLogo != "" && branding.LogoStorageKey == "" {
+		s.removeObject(ctx, previousLogo)
+	}
+	s.resolveLogoURL(ctx, branding)
+	return branding, nil
+}
+
+// UploadLogo replaces the tenant's logo with an uploaded PNG or JPEG
+// The new image is stored before the branding is saved; the replaced one is deleted afterwards.
+func (s *TenantBrandingService) UploadLogo(ctx context.Context, tenantID string, reader io.Reader) (*models.TenantBranding, error) {
+	if s.storage == nil {
+		return nil, ErrLogoUnavailable
+	}
+
+	logo, format, err := readLogo(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	branding, err := s.brandingRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	previousLogo := branding.LogoStorageKey
+
+	extension, contentType := "png", "image/png"
+	if format == "jpeg" {
+		extension, contentType = "jpg", "image/jpeg"
+	}
+	storageKey := LogoStorageKey(tenantID, uuid.NewString(), extension)
+	if err := s.storage.Upload(ctx, storageKey, bytes.NewReader(logo), int64(len(logo)), contentType); err != nil {
+		return nil, err
+	}
+
+	branding.LogoStorageKey = storageKey
+	branding.LogoURL = ""
+	if err := s.brandingRepo.Upsert(ctx, branding); err != nil {
+		s.removeObject(ctx, storageKey)
+		return nil, fmt.Errorf("failed to save logo: %w", err)
+	}
+	if previousLogo != "" {
+		s.removeObject(ctx, previousLogo)
+	}
+
+	s.resolveLogoURL(ctx, branding)
+	return branding, nil
+}
+
+// DeleteLogo removes the tenant's logo, uploaded or linked; removing a missing logo is not an error
+func (s *TenantBrandingService) DeleteLogo(ctx context.Context, tenantID string) error {
+	branding, err := s.brandingRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if branding.LogoStorageKey == "" && branding.LogoURL == "" {
+		return nil
+	}
+	previousLogo := branding.LogoStorageKey
+
+	branding.LogoStorageKey = ""
+	branding.LogoURL = ""
+	if err := s.brandingRepo.Upsert(ctx, branding); err != nil {
+		return fmt.Errorf("failed to remove logo: %w", err)
+	}
+	if previousLogo != "" {
+		s.removeObject(ctx, previousLogo)
+	}
+	return nil
+}
+
+// resolveLogoURL points LogoURL at an uploaded logo; it is left empty when the link cannot be signed
+func (s *TenantBrandingService) resolveLogoURL(ctx context.Context, branding *models.TenantBranding) {
+	if branding.LogoStorageKey == "" {
+		return
+	}
+	branding.LogoURL = ""
+	if s.storage == nil {
+		return
+	}
+	url, err := s.storage.PresignedURL(ctx, branding.LogoStorageKey)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return
+	}
+	branding.LogoURL = url
+}
+
+func (s *TenantBrandingService) removeObject(ctx context.Context, storageKey string) {
+	if s.storage == nil {
+		return
+	}
+	if err := s.storage.Delete(ctx, storageKey); err != nil {
+		fmt.Printf("Warning: failed to delete logo %s: %v\n", storageKey, err)
+	}
+}
+
+// readLogo reads an uploaded logo and checks it is a PNG or JPEG invoices can draw
+// The image is stored as uploaded so transparent PNG logos keep their background.
+func readLogo(reader io.Reader) ([]byte, string, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, MaxLogoSizeBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read logo: %w", err)
+	}
+	if len(data) > MaxLogoSizeBytes {
+		return nil, "", ErrLogoTooLarge
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return nil, "", ErrLogoInvalid
+	}
+	if config.Width == 0 || config.Height == 0 || config.Width > maxLogoPixels || config.Height > maxLogoPixels {
+		return nil, "", ErrLogoDimensions
+	}
+	return data, format, nil
+}
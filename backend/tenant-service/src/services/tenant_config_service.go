@@ -3,20 +3,25 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/pos/tenant-service/src/models"
 	"github.com/pos/tenant-service/src/repository"
 )
 
 type TenantConfigService struct {
-	configRepo *repository.TenantConfigRepository
-	db         *sql.DB
+	configRepo      *repository.TenantConfigRepository
+	brandingService *TenantBrandingService
+	db              *sql.DB
 }
 
-func NewTenantConfigService(configRepo *repository.TenantConfigRepository, db *sql.DB) *TenantConfigService {
+func NewTenantConfigService(configRepo *repository.TenantConfigRepository, brandingService *TenantBrandingService, db *sql.DB) *TenantConfigService {
 	return &TenantConfigService{
-		configRepo: configRepo,
-		db:         db,
+		configRepo:      configRepo,
+		brandingService: brandingService,
+		db:              db,
 	}
 }
 
@@ -33,13 +38,26 @@ type DeliveryConfig struct {
 	MinOrderAmount       int                    `json:"min_order_amount,omitempty"`
 	EstimatedPrepTime    int                    `json:"estimated_prep_time,omitempty"`
 	ChargeDeliveryFee    bool                   `json:"charge_delivery_fee"`
+
+	// Opening hours, so the storefront can show when orders are taken
+	Timezone              string               `json:"timezone"`
+	BusinessHours         models.BusinessHours `json:"business_hours"`
+	Holidays              json.RawMessage      `json:"holidays"`
+	OrderingCutoffMinutes int                  `json:"ordering_cutoff_minutes"`
+	ClosedOrderPolicy     string               `json:"closed_order_policy"`
+
+	// Storefront look; logo_url above is the same logo
+	Branding *models.PublicBranding `json:"branding"`
 }
 
 func (s *TenantConfigService) GetDeliveryConfig(ctx context.Context, tenantSlug string) (*DeliveryConfig, error) {
 	// Fetch tenant information
-	var tenantID, tenantName sql.NullString
-	query := `SELECT id, business_name FROM tenants WHERE slug = $1`
-	err := s.db.QueryRowContext(ctx, query, tenantSlug).Scan(&tenantID, &tenantName)
+	var tenantID, tenantName, tenantStatus sql.NullString
+	query := `
+		SELECT t.id, t.business_name, t.status
+		FROM tenants t
+		WHERE t.slug = $1`
+	err := s.db.QueryRowContext(ctx, query, tenantSlug).Scan(&tenantID, &tenantName, &tenantStatus)
 	if err != nil && err != sql.ErrNoRows {
 		// Log error but continue with config data
 		fmt.Printf("Warning: failed to fetch tenant info: %v\n", err)
@@ -48,22 +66,31 @@ func (s *TenantConfigService) GetDeliveryConfig(ctx context.Context, tenantSlug
 	if !tenantID.Valid {
 		return nil, fmt.Errorf("tenant not found")
 	}
+	if status := models.TenantStatus(tenantStatus.String); status.Blocked() {
+		return nil, &models.TenantUnavailableError{Status: status}
+	}
 
 	// Fetch order settings from order_settings table
 	var deliveryEnabled, pickupEnabled, dineInEnabled, chargeDeliveryFee bool
 	var defaultDeliveryFee, minOrderAmount, estimatedPrepTime sql.NullInt64
+	var timezone, closedOrderPolicy string
+	var businessHours models.BusinessHours
+	var holidays []byte
+	var orderingCutoffMinutes int
 
 	orderSettingsQuery := `
 		SELECT delivery_enabled, pickup_enabled, dine_in_enabled, 
 		       default_delivery_fee, min_order_amount, estimated_prep_time,
-		       charge_delivery_fee
+		       charge_delivery_fee, auto_complete_timezone, business_hours,
+		       holidays, ordering_cutoff_minutes, closed_order_policy
 		FROM order_settings 
 		WHERE tenant_id = $1`
 
 	err = s.db.QueryRowContext(ctx, orderSettingsQuery, tenantID.String).Scan(
 		&deliveryEnabled, &pickupEnabled, &dineInEnabled,
 		&defaultDeliveryFee, &minOrderAmount, &estimatedPrepTime,
-		&chargeDeliveryFee,
+		&chargeDeliveryFee, &timezone, &businessHours,
+		&holidays, &orderingCutoffMinutes, &closedOrderPolicy,
 	)
 
 	// Build enabled delivery types array
@@ -72,6 +99,10 @@ func (s *TenantConfigService) GetDeliveryConfig(ctx context.Context, tenantSlug
 		// No settings found, return defaults
 		enabledTypes = []string{"pickup", "delivery", "dine_in"}
 		chargeDeliveryFee = true
+		timezone = "Asia/Jakarta"
+		businessHours = models.BusinessHours{}
+		holidays = []byte("[]")
+		closedOrderPolicy = "accept"
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to get order settings: %w", err)
 	} else {
@@ -87,9 +118,15 @@ func (s *TenantConfigService) GetDeliveryConfig(ctx context.Context, tenantSlug
 		}
 	}
 
+	branding, err := s.brandingService.GetBranding(ctx, tenantID.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branding: %w", err)
+	}
+
 	return &DeliveryConfig{
 		TenantID:             tenantID.String,
 		TenantName:           tenantName.String,
+		LogoURL:              branding.LogoURL,
 		EnabledDeliveryTypes: enabledTypes,
 		ServiceArea:          map[string]interface{}{},
 		DeliveryFeeConfig:    map[string]interface{}{},
@@ -98,6 +135,14 @@ func (s *TenantConfigService) GetDeliveryConfig(ctx context.Context, tenantSlug
 		MinOrderAmount:       int(minOrderAmount.Int64),
 		EstimatedPrepTime:    int(estimatedPrepTime.Int64),
 		ChargeDeliveryFee:    chargeDeliveryFee,
+
+		Timezone:              timezone,
+		BusinessHours:         businessHours,
+		Holidays:              holidays,
+		OrderingCutoffMinutes: orderingCutoffMinutes,
+		ClosedOrderPolicy:     closedOrderPolicy,
+
+		Branding: branding.Public(),
 	}, nil
 }
 
@@ -207,3 +252,109 @@ func (s *TenantConfigService) UpdateMidtransConfig(ctx context.Context, midtrans
 
 	return s.configRepo.Update(ctx, config)
 }
+
+// Supported online payment gateways
+const (
+	PaymentGatewayMidtrans = "midtrans"
+	PaymentGatewayXendit   = "xendit"
+)
+
+// ErrInvalidPaymentGatewayConfig is returned when a gateway cannot be selected
+var ErrInvalidPaymentGatewayConfig = errors.New("invalid payment gateway configuration")
+
+// PaymentGatewayConfig represents the tenant's gateway choice and Xendit credentials
+// Midtrans credentials keep their own endpoint (midtrans-config).
+type PaymentGatewayConfig struct {
+	TenantID            string `json:"tenant_id"`
+	Provider            string `json:"provider"` // midtrans or xendit
+	XenditSecretKey     string `json:"xendit_secret_key"`
+	XenditCallbackToken string `json:"xendit_callback_token"`
+	XenditConfigured    bool   `json:"xendit_configured"`
+	MidtransConfigured  bool   `json:"midtrans_configured"`
+}
+
+// GetPaymentGatewayConfig retrieves the payment gateway selection for a tenant
+func (s *TenantConfigService) GetPaymentGatewayConfig(ctx context.Context, tenantID string) (*PaymentGatewayConfig, error) {
+	config, err := s.configRepo.GetByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant config: %w", err)
+	}
+
+	provider := config.PaymentGateway
+	if provider == "" {
+		provider = PaymentGatewayMidtrans
+	}
+
+	return &PaymentGatewayConfig{
+		TenantID:            tenantID,
+		Provider:            provider,
+		XenditSecretKey:     config.XenditSecretKey,
+		XenditCallbackToken: config.XenditCallbackToken,
+		XenditConfigured:    config.XenditSecretKey != "" && config.XenditCallbackToken != "",
+		MidtransConfigured:  config.MidtransServerKey != "" && config.MidtransClientKey != "",
+	}, nil
+}
+
+// UpdatePaymentGatewayConfig switches the tenant's gateway and stores Xendit credentials
+// Empty Xendit fields keep the stored values so the provider can be switched on its own.
+func (s *TenantConfigService) UpdatePaymentGatewayConfig(ctx context.Context, gatewayConfig *PaymentGatewayConfig) error {
+	if gatewayConfig.Provider != PaymentGatewayMidtrans && gatewayConfig.Provider != PaymentGatewayXendit {
+		return fmt.Errorf("%w: provider must be 'midtrans' or 'xendit'", ErrInvalidPaymentGatewayConfig)
+	}
+
+	config, err := s.configRepo.GetByTenantID(ctx, gatewayConfig.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant config: %w", err)
+	}
+
+	if gatewayConfig.XenditSecretKey != "" {
+		config.XenditSecretKey = gatewayConfig.XenditSecretKey
+	}
+	if gatewayConfig.XenditCallbackToken != "" {
+		config.XenditCallbackToken = gatewayConfig.XenditCallbackToken
+	}
+
+	// Refuse to route payments to a gateway that cannot take them
+	switch gatewayConfig.Provider {
+	case PaymentGatewayXendit:
+		if config.XenditSecretKey == "" || config.XenditCallbackToken == "" {
+			return fmt.Errorf("%w: xendit_secret_key and xendit_callback_token are required to use Xendit", ErrInvalidPaymentGatewayConfig)
+		}
+	case PaymentGatewayMidtrans:
+		if config.MidtransServerKey == "" || config.MidtransClientKey == "" {
+			return fmt.Errorf("%w: Midtrans credentials must be configured before switching to Midtrans", ErrInvalidPaymentGatewayConfig)
+		}
+	}
+	config.PaymentGateway = gatewayConfig.Provider
+
+	// If no created_at, it's a default config, so create it
+	if config.CreatedAt == "" {
+		return s.configRepo.Create(ctx, config)
+	}
+
+	return s.configRepo.Update(ctx, config)
+}
+
+// GetDeliveryFeeConfig retrieves how a tenant prices deliveries
+func (s *TenantConfigService) GetDeliveryFeeConfig(ctx context.Context, tenantID string) (*models.DeliveryFeeConfig, error) {
+	return s.configRepo.GetDeliveryFeeConfig(ctx, tenantID)
+}
+
+// UpdateDeliveryFeeConfig applies the set fields of req to the tenant's delivery pricing and saves it
+func (s *TenantConfigService) UpdateDeliveryFeeConfig(ctx context.Context, tenantID string, req *models.UpdateDeliveryFeeConfigRequest) (*models.DeliveryFeeConfig, error) {
+	config, err := s.configRepo.GetDeliveryFeeConfig(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Apply(req)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.configRepo.UpsertDeliveryFeeConfig(ctx, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/tenant-service/src/events"
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/queue"
+	"github.com/pos/tenant-service/src/repository"
+	"github.com/pos/tenant-service/src/utils"
+)
+
+// TenantDeletionService deletes all of a tenant's data across services as a saga
+// Each stage's services are asked on the tenant-events topic to delete what they own and answer with an
+// acknowledgement; once every service has completed, the tenant's stored files and the tenant row itself
+// are deleted and a deletion certificate is recorded in the audit trail.
+type TenantDeletionService struct {
+	deletionRepo   *repository.TenantDeletionRepository
+	tenantRepo     *repository.TenantRepository
+	storage        *ObjectStorage
+	tenantEvents   *queue.KafkaProducer
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewTenantDeletionService(
+	deletionRepo *repository.TenantDeletionRepository,
+	tenantRepo *repository.TenantRepository,
+	storage *ObjectStorage,
+	tenantEvents *queue.KafkaProducer,
+	auditPublisher utils.AuditPublisherInterface,
+) *TenantDeletionService {
+	return &TenantDeletionService{
+		deletionRepo:   deletionRepo,
+		tenantRepo:     tenantRepo,
+		storage:        storage,
+		tenantEvents:   tenantEvents,
+		auditPublisher: auditPublisher,
+	}
+}
+
+// GetDeletion returns the tenant's most recent deletion
+func (s *TenantDeletionService) GetDeletion(ctx context.Context, tenantID string) (*models.TenantDeletion, error) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return nil, models.ErrTenantDeletionNotFound
+	}
+	deletion, err := s.deletionRepo.FindLatest(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tenant deletion: %w", err)
+	}
+	if deletion == nil {
+		return nil, models.ErrTenantDeletionNotFound
+	}
+	return deletion, nil
+}
+
+// Delete starts deleting a tenant scheduled for deletion
+// Calling it again while the deletion is in progress asks the services that have not completed once more,
+// or retries the final step when they all have.
+func (s *TenantDeletionService) Delete(ctx context.Context, operator *models.PlatformOperator, tenantID string, req *models.ChangeTenantStatusRequest) (*models.TenantDeletion, error) {
+	if err := req.Validate(false); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return nil, models.ErrTenantNotFound
+	}
+
+	deletion, err := s.deletionRepo.Start(ctx, tenantID, operator.ID, req.Reason, models.TenantDeletionStages)
+	if err != nil {
+		return nil, err
+	}
+	if deletion != nil {
+		s.publishAudit(ctx, operator, deletion)
+		s.requestDeletion(ctx, deletion, deletion.Outstanding())
+		return deletion, nil
+	}
+
+	// Either the tenant is not pending deletion or its deletion is already in progress
+	deletion, err = s.deletionRepo.FindLatest(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tenant deletion: %w", err)
+	}
+	if deletion == nil || deletion.Status != models.TenantDeletionInProgress {
+		lifecycle, err := s.tenantRepo.GetLifecycle(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if lifecycle == nil {
+			return nil, models.ErrTenantNotFound
+		}
+		return nil, models.ErrTenantNotPendingDeletion
+	}
+
+	outstanding := deletion.Outstanding()
+	if len(outstanding) == 0 {
+		if err := s.proceed(ctx, deletion); err != nil {
+			return nil, err
+		}
+		return s.deletionRepo.FindByID(ctx, deletion.ID)
+	}
+	if err := s.deletionRepo.ResetFailed(ctx, deletion.ID); err != nil {
+		return nil, fmt.Errorf("failed to reset failed deletion steps: %w", err)
+	}
+	s.requestDeletion(ctx, deletion, outstanding)
+	return s.deletionRepo.FindByID(ctx, deletion.ID)
+}
+
+// HandleEvent processes one message from the tenant-events topic, recording deletion acknowledgements
+// Other event types, including the service's own, are ignored.
+func (s *TenantDeletionService) HandleEvent(ctx context.Context, payload []byte) error {
+	var event struct {
+		EventType string                   `json:"event_type"`
+		TenantID  string                   `json:"tenant_id"`
+		Data      models.TenantDeletionAck `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		fmt.Printf("Warning: discarding malformed tenant event: %v\n", err)
+		return nil
+	}
+	if event.EventType != events.TenantDeletionAcknowledged {
+		return nil
+	}
+	ack := &event.Data
+	if _, err := uuid.Parse(ack.DeletionID); err != nil {
+		fmt.Printf("Warning: discarding tenant deletion acknowledgement from %s without a deletion ID\n", ack.Service)
+		return nil
+	}
+	if ack.Status != models.DeletionStepCompleted && ack.Status != models.DeletionStepFailed {
+		fmt.Printf("Warning: discarding tenant deletion acknowledgement with status %q from %s\n", ack.Status, ack.Service)
+		return nil
+	}
+	if ack.Deleted == nil {
+		ack.Deleted = map[string]int64{}
+	}
+
+	recorded, err := s.deletionRepo.RecordAcknowledgement(ctx, event.TenantID, ack)
+	if err != nil {
+		return fmt.Errorf("failed to record tenant deletion acknowledgement: %w", err)
+	}
+	if !recorded {
+		return nil
+	}
+	if ack.Status == models.DeletionStepFailed {
+		// The operator retries once the cause is fixed; the other services carry on meanwhile
+		fmt.Printf("Warning: %s failed to delete tenant %s data: %s\n", ack.Service, event.TenantID, ack.Error)
+		return nil
+	}
+
+	deletion, err := s.deletionRepo.FindByID(ctx, ack.DeletionID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tenant deletion: %w", err)
+	}
+	return s.proceed(ctx, deletion)
+}
+
+// proceed asks the next stage once every service of the current one has completed, and finishes
+// the deletion after the last stage
+func (s *TenantDeletionService) proceed(ctx context.Context, deletion *models.TenantDeletion) error {
+	if deletion == nil || deletion.Status != models.TenantDeletionInProgress || len(deletion.Outstanding()) > 0 {
+		return nil
+	}
+
+	if deletion.Stage < len(models.TenantDeletionStages) {
+		advanced, err := s.deletionRepo.AdvanceStage(ctx, deletion.ID, deletion.Stage)
+		if err != nil {
+			return fmt.Errorf("failed to advance tenant deletion: %w", err)
+		}
+		if !advanced {
+			return nil
+		}
+		deletion.Stage++
+		s.requestDeletion(ctx, deletion, deletion.Outstanding())
+		return nil
+	}
+
+	return s.finish(ctx, deletion)
+}
+
+// finish deletes the tenant's logos and export archives, then the tenant row, and records the certificate
+func (s *TenantDeletionService) finish(ctx context.Context, deletion *models.TenantDeletion) error {
+	if s.storage != nil {
+		for _, prefix := range []string{"logos/" + deletion.TenantID + "/", "exports/" + deletion.TenantID + "/"} {
+			if _, err := s.storage.DeletePrefix(ctx, prefix); err != nil {
+				s.recordError(ctx, deletion, err)
+				return fmt.Errorf("%w: %v", models.ErrTenantDeletionFailed, err)
+			}
+		}
+	}
+
+	completed, err := s.deletionRepo.Complete(ctx, deletion, uuid.New().String())
+	if err != nil {
+		s.recordError(ctx, deletion, err)
+		return fmt.Errorf("%w: %v", models.ErrTenantDeletionFailed, err)
+	}
+	if !completed {
+		return nil
+	}
+
+	s.publishCertificate(ctx, deletion)
+	return nil
+}
+
+func (s *TenantDeletionService) recordError(ctx context.Context, deletion *models.TenantDeletion, cause error) {
+	if err := s.deletionRepo.RecordError(ctx, deletion.ID, cause.Error()); err != nil {
+		fmt.Printf("Warning: failed to record tenant deletion error: %v\n", err)
+	}
+}
+
+// requestDeletion asks services to delete the tenant's data
+func (s *TenantDeletionService) requestDeletion(ctx context.Context, deletion *models.TenantDeletion, steps []models.TenantDeletionStep) {
+	if s.tenantEvents == nil || len(steps) == 0 {
+		return
+	}
+	services := make([]string, 0, len(steps))
+	for _, step := range steps {
+		services = append(services, step.Service)
+	}
+	event := &events.TenantEvent{
+		EventID:   uuid.New().String(),
+		EventType: events.TenantDeletionRequested,
+		TenantID:  deletion.TenantID,
+		Data: map[string]interface{}{
+			"deletion_id": deletion.ID,
+			"services":    services,
+		},
+		Timestamp: time.Now(),
+	}
+	if err := s.tenantEvents.Publish(ctx, deletion.TenantID, event); err != nil {
+		fmt.Printf("Warning: failed to publish %s event: %v\n", events.TenantDeletionRequested, err)
+	}
+}
+
+func (s *TenantDeletionService) publishAudit(ctx context.Context, operator *models.PlatformOperator, deletion *models.TenantDeletion) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     deletion.TenantID,
+		ActorType:    "admin",
+		ActorID:      &operator.ID,
+		Action:       "DELETE",
+		ResourceType: "tenant",
+		ResourceID:   deletion.TenantID,
+		Metadata: map[string]interface{}{
+			"event":       "tenant_deletion_requested",
+			"deletion_id": deletion.ID,
+			"tenant_slug": deletion.TenantSlug,
+			"reason":      deletion.Reason,
+		},
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish tenant deletion audit event: %v\n", err)
+	}
+}
+
+// publishCertificate records the deletion certificate: who asked, when, and what each service deleted
+func (s *TenantDeletionService) publishCertificate(ctx context.Context, deletion *models.TenantDeletion) {
+	if s.auditPublisher == nil {
+		return
+	}
+	services := make(map[string]interface{}, len(deletion.Steps))
+	for _, step := range deletion.Steps {
+		services[step.Service] = map[string]interface{}{
+			"acknowledged_at": step.AcknowledgedAt,
+			"deleted":         step.Deleted,
+		}
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     deletion.TenantID,
+		ActorType:    "system",
+		Action:       "DELETE",
+		ResourceType: "tenant",
+		ResourceID:   deletion.TenantID,
+		Metadata: map[string]interface{}{
+			"event":          "tenant_deletion_certificate",
+			"certificate_id": *deletion.CertificateID,
+			"deletion_id":    deletion.ID,
+			"tenant_slug":    deletion.TenantSlug,
+			"requested_by":   deletion.RequestedBy,
+			"requested_at":   deletion.RequestedAt,
+			"completed_at":   deletion.CompletedAt,
+			"services":       services,
+		},
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Warning: failed to publish tenant deletion certificate %s: %v\n", *deletion.CertificateID, err)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/repository"
+)
+
+type TenantDomainService struct {
+	domainRepo *repository.TenantDomainRepository
+	baseDomain string // Platform storefront domain; <slug>.<baseDomain> resolves without registration
+
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+}
+
+func NewTenantDomainService(domainRepo *repository.TenantDomainRepository, baseDomain string) *TenantDomainService {
+	return &TenantDomainService{
+		domainRepo: domainRepo,
+		baseDomain: strings.ToLower(strings.Trim(baseDomain, ". ")),
+		lookupTXT:  net.DefaultResolver.LookupTXT,
+	}
+}
+
+func (s *TenantDomainService) ListDomains(ctx context.Context, tenantID string) ([]models.TenantDomain, error) {
+	domains, err := s.domainRepo.ListByTenantID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range domains {
+		domains[i].VerificationRecord = domains[i].VerificationRecordName()
+	}
+	return domains, nil
+}
+
+// AddDomain registers a custom storefront domain for a tenant
+// The domain does not resolve until VerifyDomain finds its TXT record.
+func (s *TenantDomainService) AddDomain(ctx context.Context, tenantID string, req *models.CreateTenantDomainRequest) (*models.TenantDomain, error) {
+	domain, err := models.NormalizeDomain(req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if s.isPlatformDomain(models.HostWithoutPort(domain)) {
+		return nil, models.ErrDomainReserved
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	tenantDomain := &models.TenantDomain{
+		TenantID:          tenantID,
+		Domain:            domain,
+		VerificationToken: hex.EncodeToString(token),
+	}
+	if err := s.domainRepo.Create(ctx, tenantDomain); err != nil {
+		return nil, err
+	}
+
+	tenantDomain.VerificationRecord = tenantDomain.VerificationRecordName()
+	return tenantDomain, nil
+}
+
+// VerifyDomain checks the domain's TXT record for its verification token and marks it verified
+func (s *TenantDomainService) VerifyDomain(ctx context.Context, tenantID, domainID string) (*models.TenantDomain, error) {
+	domain, err := s.domainRepo.FindByID(ctx, tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	domain.VerificationRecord = domain.VerificationRecordName()
+	if domain.VerifiedAt != nil {
+		return domain, nil
+	}
+
+	records, err := s.lookupTXT(ctx, domain.VerificationRecord)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrDomainNotVerified, err)
+	}
+	found := false
+	for _, record := range records {
+		if strings.TrimSpace(record) == domain.VerificationToken {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, models.ErrDomainNotVerified
+	}
+
+	if err := s.domainRepo.MarkVerified(ctx, domain); err != nil {
+		return nil, err
+	}
+	return domain, nil
+}
+
+func (s *TenantDomainService) RemoveDomain(ctx context.Context, tenantID, domainID string) error {
+	return s.domainRepo.Delete(ctx, tenantID, domainID)
+}
+
+// ResolveOrigin maps a browser Origin (or bare host) to the tenant that registered it
+// Platform subdomains resolve from the tenant slug; custom domains must be verified.
+func (s *TenantDomainService) ResolveOrigin(ctx context.Context, origin string) (*models.TenantDomain, error) {
+	domain, err := models.NormalizeDomain(origin)
+	if err != nil {
+		return nil, err
+	}
+
+	host := models.HostWithoutPort(domain)
+	if s.isPlatformDomain(host) {
+		slug := strings.TrimSuffix(host, "."+s.baseDomain)
+		if slug == host || strings.Contains(slug, ".") {
+			return nil, models.ErrDomainNotFound
+		}
+		return s.domainRepo.FindActiveBySlug(ctx, slug, domain)
+	}
+
+	return s.domainRepo.FindActiveByDomain(ctx, domain)
+}
+
+// isPlatformDomain reports whether host is the platform domain or one of its subdomains
+func (s *TenantDomainService) isPlatformDomain(host string) bool {
+	return s.baseDomain != "" && (host == s.baseDomain || strings.HasSuffix(host, "."+s.baseDomain))
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/repository"
+	"github.com/pos/tenant-service/src/utils"
+)
+
+// tenantExportTimeout bounds assembling one archive; photo-heavy catalogs take a while to copy
+const tenantExportTimeout = 30 * time.Minute
+
+var (
+	ErrTenantExportInProgress  = errors.New("a tenant data export is already being prepared")
+	ErrTenantExportNotFound    = errors.New("tenant data export not found")
+	ErrTenantExportUnavailable = errors.New("object storage is not configured")
+)
+
+// TenantExportService assembles all of a tenant's data into a ZIP archive, for backups and for
+// tenants leaving the platform (UU PDP data portability)
+// The archive is built in the background and kept in object storage; owners fetch it through a
+// short-lived link until the link TTL passes and the hourly cleanup deletes it.
+type TenantExportService struct {
+	exportRepo     *repository.TenantExportRepository
+	brandingRepo   *repository.TenantBrandingRepository
+	outletRepo     *repository.OutletRepository
+	dataService    *TenantDataService
+	storage        *ObjectStorage
+	auditPublisher utils.AuditPublisherInterface
+	linkTTL        time.Duration
+}
+
+func NewTenantExportService(
+	exportRepo *repository.TenantExportRepository,
+	brandingRepo *repository.TenantBrandingRepository,
+	outletRepo *repository.OutletRepository,
+	dataService *TenantDataService,
+	storage *ObjectStorage,
+	auditPublisher utils.AuditPublisherInterface,
+	linkTTLHours int,
+) *TenantExportService {
+	return &TenantExportService{
+		exportRepo:     exportRepo,
+		brandingRepo:   brandingRepo,
+		outletRepo:     outletRepo,
+		dataService:    dataService,
+		storage:        storage,
+		auditPublisher: auditPublisher,
+		linkTTL:        time.Duration(linkTTLHours) * time.Hour,
+	}
+}
+
+// TenantExportStorageKey returns where an export archive is stored
+// Format: exports/{tenant_id}/{export_id}.zip
+func TenantExportStorageKey(tenantID, exportID string) string {
+	return fmt.Sprintf("exports/%s/%s.zip", tenantID, exportID)
+}
+
+// RequestExport starts assembling the tenant's data; only one export is prepared at a time
+func (s *TenantExportService) RequestExport(ctx context.Context, tenantID, userID string) (*models.TenantExport, error) {
+	if s.storage == nil {
+		return nil, ErrTenantExportUnavailable
+	}
+
+	pending, err := s.exportRepo.FindPending(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending exports: %w", err)
+	}
+	if pending != nil && time.Since(pending.CreatedAt) < tenantExportTimeout {
+		return nil, ErrTenantExportInProgress
+	}
+
+	export, err := s.exportRepo.Create(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tenant data export: %w", err)
+	}
+	s.publishAudit(ctx, export, userID, "EXPORT", nil)
+
+	go s.assemble(export)
+	return export, nil
+}
+
+// ListExports returns the tenant's exports, newest first
+func (s *TenantExportService) ListExports(ctx context.Context, tenantID string) ([]*models.TenantExport, error) {
+	return s.exportRepo.ListByTenantID(ctx, tenantID)
+}
+
+// GetExport returns one of the tenant's exports, with a download link while it can be downloaded
+func (s *TenantExportService) GetExport(ctx context.Context, tenantID, userID, exportID, ipAddress string) (*models.TenantExport, error) {
+	if _, err := uuid.Parse(exportID); err != nil {
+		return nil, ErrTenantExportNotFound
+	}
+	export, err := s.exportRepo.FindByID(ctx, tenantID, exportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenant data export: %w", err)
+	}
+	if export == nil {
+		return nil, ErrTenantExportNotFound
+	}
+
+	if export.Status != models.TenantExportReady || export.ExpiresAt == nil || s.storage == nil {
+		return export, nil
+	}
+	ttl := time.Until(*export.ExpiresAt)
+	if ttl <= 0 {
+		return export, nil
+	}
+	filename := fmt.Sprintf("tenant-data-%s.zip", export.CreatedAt.Format("2006-01-02"))
+	if export.DownloadURL, err = s.storage.PresignedDownloadURL(ctx, export.StorageKey, filename, ttl); err != nil {
+		return nil, err
+	}
+	s.publishAudit(ctx, export, userID, "ACCESS", &ipAddress)
+	return export, nil
+}
+
+// PurgeExpired deletes archives whose download link has expired, with their export records
+func (s *TenantExportService) PurgeExpired(ctx context.Context) (int, error) {
+	exports, err := s.exportRepo.ListExpired(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, export := range exports {
+		if export.StorageKey != "" && s.storage != nil {
+			if err := s.storage.Delete(ctx, export.StorageKey); err != nil {
+				fmt.Printf("Warning: failed to delete tenant data export archive %s: %v\n", export.ID, err)
+				continue
+			}
+		}
+		if err := s.exportRepo.Delete(ctx, export.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// assemble builds the archive in a temporary file, uploads it and marks the export ready
+func (s *TenantExportService) assemble(export *models.TenantExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), tenantExportTimeout)
+	defer cancel()
+
+	err := s.buildAndStore(ctx, export)
+	if err != nil {
+		fmt.Printf("Warning: failed to assemble tenant data export %s: %v\n", export.ID, err)
+		// The assembly context may be what ran out
+		if markErr := s.exportRepo.MarkFailed(context.Background(), export.ID, err.Error()); markErr != nil {
+			fmt.Printf("Warning: failed to mark tenant data export %s as failed: %v\n", export.ID, markErr)
+		}
+	}
+}
+
+func (s *TenantExportService) buildAndStore(ctx context.Context, export *models.TenantExport) error {
+	file, err := os.CreateTemp("", "tenant-export-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := s.writeArchive(ctx, export, file); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	storageKey := TenantExportStorageKey(export.TenantID, export.ID)
+	if err := s.storage.Upload(ctx, storageKey, file, size, "application/zip"); err != nil {
+		return err
+	}
+	if err := s.exportRepo.MarkReady(ctx, export, storageKey, size, time.Now().Add(s.linkTTL)); err != nil {
+		s.removeArchive(storageKey)
+		return err
+	}
+	return nil
+}
+
+// writeArchive writes every part of the tenant's data to a ZIP archive, ending with manifest.json
+func (s *TenantExportService) writeArchive(ctx context.Context, export *models.TenantExport, w io.Writer) error {
+	archive := zip.NewWriter(w)
+	manifest := &models.TenantExportManifest{
+		TenantID:    export.TenantID,
+		ExportID:    export.ID,
+		GeneratedAt: time.Now(),
+		Files:       []string{},
+		Counts:      map[string]int{},
+	}
+	writeJSON := func(name string, value interface{}) error {
+		entry, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		manifest.Files = append(manifest.Files, name)
+		return nil
+	}
+
+	// Business profile, team and configuration
+	tenantData, err := s.dataService.GetAllTenantData(ctx, export.TenantID)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON("tenant.json", tenantData); err != nil {
+		return err
+	}
+	manifest.Counts["team_members"] = len(tenantData.TeamMembers)
+
+	branding, err := s.brandingRepo.GetByTenantID(ctx, export.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to read branding: %w", err)
+	}
+	if branding.LogoStorageKey != "" {
+		logoPath := "branding/logo" + path.Ext(branding.LogoStorageKey)
+		if s.copyObject(ctx, archive, branding.LogoStorageKey, logoPath) {
+			branding.LogoURL = logoPath
+			manifest.Files = append(manifest.Files, logoPath)
+		}
+	}
+	if err := writeJSON("branding.json", branding); err != nil {
+		return err
+	}
+
+	outlets, err := s.outletRepo.ListByTenantID(ctx, export.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to read outlets: %w", err)
+	}
+	if err := writeJSON("outlets.json", outlets); err != nil {
+		return err
+	}
+	manifest.Counts["outlets"] = len(outlets)
+
+	// Catalog, with the photos copied next to it
+	products, err := s.exportRepo.ListProducts(ctx, export.TenantID)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		for i := range product.Photos {
+			photo := &product.Photos[i]
+			photoPath := fmt.Sprintf("photos/%s/%s%s", product.ID, photo.ID, path.Ext(photo.StorageKey))
+			if s.copyObject(ctx, archive, photo.StorageKey, photoPath) {
+				photo.ArchivePath = photoPath
+				manifest.Counts["photos"]++
+			}
+		}
+	}
+	if err := writeJSON("products.json", products); err != nil {
+		return err
+	}
+	manifest.Counts["products"] = len(products)
+
+	// Orders, one JSON object per line
+	ordersFile, err := archive.Create("orders.jsonl")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(ordersFile)
+	err = s.exportRepo.EachOrder(ctx, export.TenantID, func(order *models.ExportedOrder) error {
+		manifest.Counts["orders"]++
+		return encoder.Encode(order)
+	})
+	if err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, "orders.jsonl")
+
+	consents, err := s.exportRepo.ListConsents(ctx, export.TenantID)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON("consents.json", consents); err != nil {
+		return err
+	}
+	manifest.Counts["consent_records"] = len(consents)
+
+	auditSummary, err := s.exportRepo.AuditSummary(ctx, export.TenantID)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON("audit-summary.json", auditSummary); err != nil {
+		return err
+	}
+
+	if err := writeJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// copyObject copies a stored object into the archive; objects that cannot be read are left out
+func (s *TenantExportService) copyObject(ctx context.Context, archive *zip.Writer, storageKey, archivePath string) bool {
+	object, err := s.storage.Open(ctx, storageKey)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return false
+	}
+	defer object.Close()
+
+	// Images are already compressed
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: archivePath, Method: zip.Store})
+	if err != nil {
+		fmt.Printf("Warning: failed to add %s to tenant data export: %v\n", archivePath, err)
+		return false
+	}
+	if _, err := io.Copy(entry, object); err != nil {
+		// A partly written entry cannot be taken back; it stays, and is not referenced
+		fmt.Printf("Warning: failed to copy %s into tenant data export: %v\n", storageKey, err)
+		return false
+	}
+	return true
+}
+
+func (s *TenantExportService) removeArchive(storageKey string) {
+	if err := s.storage.Delete(context.Background(), storageKey); err != nil {
+		fmt.Printf("Warning: failed to delete tenant data export archive %s: %v\n", storageKey, err)
+	}
+}
+
+func (s *TenantExportService) publishAudit(ctx context.Context, export *models.TenantExport, userID, action string, ipAddress *string) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     export.TenantID,
+		ActorType:    "user",
+		Action:       action,
+		ResourceType: "tenant_data_export",
+		ResourceID:   export.ID,
+		IPAddress:    ipAddress,
+	}
+	if userID != "" {
+		auditEvent.ActorID = &userID
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish tenant data export audit event: %v\n", err)
+	}
+}
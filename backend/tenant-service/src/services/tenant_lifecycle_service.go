@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/tenant-service/src/events"
+	"github.com/pos/tenant-service/src/models"
+	"github.com/pos/tenant-service/src/queue"
+	"github.com/pos/tenant-service/src/repository"
+	"github.com/pos/tenant-service/src/utils"
+)
+
+var ErrOperatorUnauthorized = errors.New("invalid platform operator key")
+
+// TenantLifecycleService lets platform operators suspend, reactivate and schedule the deletion of tenants
+// Every change is published on the tenant-events topic and recorded in the audit trail.
+type TenantLifecycleService struct {
+	tenantRepo     *repository.TenantRepository
+	operatorRepo   *repository.PlatformOperatorRepository
+	tenantEvents   *queue.KafkaProducer
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewTenantLifecycleService(
+	tenantRepo *repository.TenantRepository,
+	operatorRepo *repository.PlatformOperatorRepository,
+	tenantEvents *queue.KafkaProducer,
+	auditPublisher utils.AuditPublisherInterface,
+) *TenantLifecycleService {
+	return &TenantLifecycleService{
+		tenantRepo:     tenantRepo,
+		operatorRepo:   operatorRepo,
+		tenantEvents:   tenantEvents,
+		auditPublisher: auditPublisher,
+	}
+}
+
+// AuthenticateOperator resolves the operator owning an API key
+func (s *TenantLifecycleService) AuthenticateOperator(ctx context.Context, apiKey string) (*models.PlatformOperator, error) {
+	if apiKey == "" {
+		return nil, ErrOperatorUnauthorized
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	operator, err := s.operatorRepo.FindActiveByAPIKeyHash(ctx, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up platform operator: %w", err)
+	}
+	if operator == nil {
+		return nil, ErrOperatorUnauthorized
+	}
+	return operator, nil
+}
+
+// GetStatus returns a tenant's lifecycle status
+func (s *TenantLifecycleService) GetStatus(ctx context.Context, tenantID string) (*models.TenantLifecycle, error) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return nil, models.ErrTenantNotFound
+	}
+	lifecycle, err := s.tenantRepo.GetLifecycle(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if lifecycle == nil {
+		return nil, models.ErrTenantNotFound
+	}
+	return lifecycle, nil
+}
+
+// Suspend blocks the tenant's staff, storefront and public ordering until it is reactivated
+func (s *TenantLifecycleService) Suspend(ctx context.Context, operator *models.PlatformOperator, tenantID string, req *models.ChangeTenantStatusRequest) (*models.TenantLifecycle, error) {
+	if err := req.Validate(true); err != nil {
+		return nil, err
+	}
+	return s.changeStatus(ctx, operator, tenantID, models.TenantStatusSuspended, req.Reason, events.TenantSuspended)
+}
+
+// Reactivate lifts a suspension or cancels a scheduled deletion
+func (s *TenantLifecycleService) Reactivate(ctx context.Context, operator *models.PlatformOperator, tenantID string, req *models.ChangeTenantStatusRequest) (*models.TenantLifecycle, error) {
+	if err := req.Validate(false); err != nil {
+		return nil, err
+	}
+	return s.changeStatus(ctx, operator, tenantID, models.TenantStatusActive, req.Reason, events.TenantReactivated)
+}
+
+// ScheduleDeletion blocks the tenant like a suspension while its data waits to be deleted
+func (s *TenantLifecycleService) ScheduleDeletion(ctx context.Context, operator *models.PlatformOperator, tenantID string, req *models.ChangeTenantStatusRequest) (*models.TenantLifecycle, error) {
+	if err := req.Validate(true); err != nil {
+		return nil, err
+	}
+	return s.changeStatus(ctx, operator, tenantID, models.TenantStatusPendingDeletion, req.Reason, events.TenantDeletionPending)
+}
+
+func (s *TenantLifecycleService) changeStatus(ctx context.Context, operator *models.PlatformOperator, tenantID string, status models.TenantStatus, reason, eventType string) (*models.TenantLifecycle, error) {
+	current, err := s.GetStatus(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	lifecycle, err := s.tenantRepo.ChangeStatus(ctx, tenantID, models.StatusesLeadingTo(status), status, reason, operator.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to change tenant status: %w", err)
+	}
+	if lifecycle == nil {
+		// The tenant was in a status this change cannot be made from, or changed concurrently
+		return nil, models.ErrInvalidStatusTransition
+	}
+
+	data := map[string]interface{}{
+		"previous_status": current.Status,
+		"status":          lifecycle.Status,
+		"reason":          reason,
+		"operator_id":     operator.ID,
+	}
+	s.publishEvent(ctx, tenantID, eventType, data)
+	s.publishAudit(ctx, operator, tenantID, eventType, data)
+	return lifecycle, nil
+}
+
+func (s *TenantLifecycleService) publishEvent(ctx context.Context, tenantID, eventType string, data map[string]interface{}) {
+	if s.tenantEvents == nil {
+		return
+	}
+	event := &events.TenantEvent{
+		EventID:   uuid.New().String(),
+		EventType: eventType,
+		TenantID:  tenantID,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+	if err := s.tenantEvents.Publish(ctx, tenantID, event); err != nil {
+		fmt.Printf("Warning: failed to publish %s event: %v\n", eventType, err)
+	}
+}
+
+func (s *TenantLifecycleService) publishAudit(ctx context.Context, operator *models.PlatformOperator, tenantID, eventType string, data map[string]interface{}) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "admin",
+		ActorID:      &operator.ID,
+		Action:       "UPDATE",
+		ResourceType: "tenant_status",
+		ResourceID:   tenantID,
+		Metadata: map[string]interface{}{
+			"event":           eventType,
+			"previous_status": data["previous_status"],
+			"status":          data["status"],
+			"reason":          data["reason"],
+		},
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish tenant status audit event: %v\n", err)
+	}
+}
@@ -98,6 +98,10 @@ func (s *TenantService) RegisterTenant(ctx context.Context, req *models.CreateTe
 		return nil, fmt.Errorf("failed to create owner user: %w", err)
 	}
 
+	if err := createDefaultTeams(ctx, tx, tenant.ID); err != nil {
+		return nil, fmt.Errorf("failed to create default teams: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -204,6 +208,25 @@ func (s *TenantService) createOwnerUser(ctx context.Context, tx *sql.Tx, tenantI
 	return userID, verificationToken, nil
 }
 
+// defaultTeams are the teams every new tenant starts with, as migration 000127 created for existing ones
+var defaultTeams = []struct{ Name, Description string }{
+	{"Kitchen", "Prepares orders"},
+	{"Front-of-house", "Serves customers and takes payments"},
+	{"Back office", "Stock, reporting and administration"},
+}
+
+func createDefaultTeams(ctx context.Context, tx *sql.Tx, tenantID string) error {
+	for _, team := range defaultTeams {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO teams (tenant_id, name, description) VALUES ($1, $2, $3)
+		`, tenantID, team.Name, team.Description)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func generateVerificationToken() string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	b := make([]byte, 32)
@@ -30,27 +30,6 @@ func IsValidBusinessName(name string) bool {
 	return businessNameRegex.MatchString(name)
 }
 
-func IsValidPassword(password string) bool {
-	if len(password) < 8 {
-		return false
-	}
-	
-	// Check for at least one letter and one digit
-	hasLetter := false
-	hasDigit := false
-	
-	for _, char := range password {
-		if unicode.IsLetter(char) {
-			hasLetter = true
-		}
-		if unicode.IsDigit(char) {
-			hasDigit = true
-		}
-	}
-	
-	return hasLetter && hasDigit
-}
-
 func GenerateSlug(businessName string) string {
 	slug := strings.ToLower(businessName)
 
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported CAPTCHA providers
+const (
+	CaptchaProviderHCaptcha  = "hcaptcha"
+	CaptchaProviderTurnstile = "turnstile"
+)
+
+var captchaVerifyURLs = map[string]string{
+	CaptchaProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	CaptchaProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+var (
+	ErrCaptchaRequired    = errors.New("captcha token is required")
+	ErrCaptchaInvalid     = errors.New("captcha verification failed")
+	ErrCaptchaUnavailable = errors.New("captcha provider unavailable")
+)
+
+// CaptchaVerifier checks CAPTCHA tokens solved in the browser with the configured provider
+// hCaptcha and Cloudflare Turnstile share the same siteverify contract, so only the URL differs.
+type CaptchaVerifier struct {
+	Enabled  bool   `json:"enabled"`
+	Provider string `json:"provider,omitempty"`
+	SiteKey  string `json:"siteKey,omitempty"` // Public key the frontend renders the widget with
+
+	secret     string
+	verifyURL  string
+	httpClient *http.Client
+}
+
+// LoadCaptchaVerifier reads the CAPTCHA configuration from CAPTCHA_* environment variables
+// Registration has no tenant yet, so only the environment-wide CAPTCHA_ENABLED applies; the
+// provider, site key and secret are then required.
+func LoadCaptchaVerifier() *CaptchaVerifier {
+	verifier := &CaptchaVerifier{
+		Enabled:    GetEnvBool("CAPTCHA_ENABLED"),
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+	if !verifier.Enabled {
+		return verifier
+	}
+
+	verifier.Provider = strings.ToLower(GetEnv("CAPTCHA_PROVIDER"))
+	verifyURL, ok := captchaVerifyURLs[verifier.Provider]
+	if !ok {
+		panic("Invalid CAPTCHA_PROVIDER " + verifier.Provider + ", expected hcaptcha or turnstile")
+	}
+	verifier.verifyURL = verifyURL
+	verifier.SiteKey = GetEnv("CAPTCHA_SITE_KEY")
+	verifier.secret = GetEnv("CAPTCHA_SECRET_KEY")
+	return verifier
+}
+
+type captchaVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// Verify checks a CAPTCHA token with the provider; it always passes when CAPTCHA is disabled
+// Verification fails closed: if the provider cannot be reached ErrCaptchaUnavailable is
+// returned, since letting requests through would reopen the door to credential stuffing.
+func (v *CaptchaVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if !v.Enabled {
+		return nil
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrCaptchaRequired
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCaptchaUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: siteverify returned %d", ErrCaptchaUnavailable, resp.StatusCode)
+	}
+
+	var result captchaVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%w: %v", ErrCaptchaUnavailable, err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrCaptchaInvalid, strings.Join(result.ErrorCodes, ","))
+	}
+	return nil
+}
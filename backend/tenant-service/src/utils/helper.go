@@ -82,19 +82,25 @@ func GetLocalizedMessage(locale, key string) string {
 			"validation.invalidRequest":        "Invalid request format",
 			"validation.businessNameRequired":  "Business name is required and must be 1-100 characters",
 			"validation.emailInvalid":          "Invalid email format",
-			"validation.passwordRequirements":  "Password must be at least 8 characters and contain letters and numbers",
+			"validation.passwordRequirements":  "Password does not meet the password requirements",
 			"auth.register.businessNameExists": "Business name already taken",
 			"auth.register.success":            "Tenant registered successfully. We've sent you a verification email.",
 			"errors.internalServer":            "Failed to register tenant. Please try again later.",
+			"captcha.required":                 "Please complete the CAPTCHA challenge",
+			"captcha.invalid":                  "CAPTCHA verification failed. Please try again.",
+			"captcha.unavailable":              "CAPTCHA verification is temporarily unavailable. Please try again later.",
 		},
 		"id": {
 			"validation.invalidRequest":        "Format permintaan tidak valid",
 			"validation.businessNameRequired":  "Nama bisnis wajib diisi dan harus 1-100 karakter",
 			"validation.emailInvalid":          "Format email tidak valid",
-			"validation.passwordRequirements":  "Kata sandi harus minimal 8 karakter dan mengandung huruf dan angka",
+			"validation.passwordRequirements":  "Kata sandi tidak memenuhi persyaratan kata sandi",
 			"auth.register.businessNameExists": "Nama bisnis sudah digunakan",
 			"auth.register.success":            "Tenant berhasil didaftarkan. Kami telah mengirimkan email verifikasi kepada Anda.",
 			"errors.internalServer":            "Gagal mendaftarkan tenant. Silakan coba lagi nanti.",
+			"captcha.required":                 "Silakan selesaikan tantangan CAPTCHA",
+			"captcha.invalid":                  "Verifikasi CAPTCHA gagal. Silakan coba lagi.",
+			"captcha.unavailable":              "Verifikasi CAPTCHA sedang tidak tersedia. Silakan coba lagi nanti.",
 		},
 	}
 
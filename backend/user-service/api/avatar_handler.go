@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/services"
+)
+
+// AvatarHandler lets staff upload and remove their own profile picture
+type AvatarHandler struct {
+	avatarService *services.AvatarService
+}
+
+func NewAvatarHandler(avatarService *services.AvatarService) *AvatarHandler {
+	return &AvatarHandler{avatarService: avatarService}
+}
+
+// UploadOwnAvatar handles PUT /api/v1/users/me/avatar
+// Expects a multipart "file" field holding a JPEG, PNG, GIF or WebP image.
+func (h *AvatarHandler) UploadOwnAvatar(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "An image file is required",
+		})
+	}
+	if fileHeader.Size > services.MaxAvatarSizeBytes {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": "Avatar must be at most 5 MB",
+		})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Logger().Errorf("Failed to open avatar file: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to read image file",
+		})
+	}
+	defer file.Close()
+
+	url, err := h.avatarService.Upload(c.Request().Context(), tenantID, userID, file)
+	if err != nil {
+		return avatarError(c, err, "Failed to upload avatar")
+	}
+	return c.JSON(http.StatusOK, map[string]string{
+		"avatar_url": url,
+	})
+}
+
+// DeleteOwnAvatar handles DELETE /api/v1/users/me/avatar
+func (h *AvatarHandler) DeleteOwnAvatar(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.avatarService.Delete(c.Request().Context(), tenantID, userID); err != nil {
+		return avatarError(c, err, "Failed to remove avatar")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func avatarError(c echo.Context, err error, message string) error {
+	switch err {
+	case services.ErrAvatarTooLarge:
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": "Avatar must be at most 5 MB",
+		})
+	case services.ErrAvatarInvalid:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Avatar must be a JPEG, PNG, GIF or WebP image",
+		})
+	case services.ErrAvatarDimensions:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Avatar must be at most 4096 pixels wide and high",
+		})
+	case services.ErrAvatarUnavailable:
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "Avatar uploads are not available",
+		})
+	case services.ErrUserNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "User not found",
+		})
+	}
+
+	c.Logger().Errorf("%s: %v", message, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
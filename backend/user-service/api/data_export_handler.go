@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// DataExportHandler lets users download a copy of the personal data held about them (UU PDP right of access)
+type DataExportHandler struct {
+	dataExportService *services.DataExportService
+}
+
+func NewDataExportHandler(dataExportService *services.DataExportService) *DataExportHandler {
+	return &DataExportHandler{dataExportService: dataExportService}
+}
+
+// RequestExport handles POST /api/v1/users/me/data-exports
+// The bundle is assembled in the background; the user is emailed a download link once it is ready.
+func (h *DataExportHandler) RequestExport(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.CreateDataExportRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	export, err := h.dataExportService.RequestExport(c.Request().Context(), tenantID, userID, req.Format)
+	if err != nil {
+		return dataExportError(c, err, "Failed to request data export")
+	}
+	return c.JSON(http.StatusAccepted, export)
+}
+
+// GetExport handles GET /api/v1/users/me/data-exports/:export_id
+func (h *DataExportHandler) GetExport(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	export, err := h.dataExportService.GetExport(c.Request().Context(), tenantID, userID, c.Param("export_id"))
+	if err != nil {
+		return dataExportError(c, err, "Failed to get data export")
+	}
+	return c.JSON(http.StatusOK, export)
+}
+
+// Download handles GET /api/v1/data-exports/:export_id/download
+// Public: the expires and signature query parameters of the emailed link authorize the download.
+func (h *DataExportHandler) Download(c echo.Context) error {
+	export, bundle, err := h.dataExportService.Download(c.Request().Context(), c.Param("export_id"),
+		c.QueryParam("expires"), c.QueryParam("signature"), c.RealIP())
+	if err != nil {
+		return dataExportError(c, err, "Failed to download data export")
+	}
+
+	contentType := "application/json"
+	if export.Format == models.DataExportFormatZIP {
+		contentType = "application/zip"
+	}
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=personal-data-%s.%s", export.ID, export.Format))
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.Blob(http.StatusOK, contentType, bundle)
+}
+
+func dataExportError(c echo.Context, err error, message string) error {
+	switch err {
+	case services.ErrDataExportFormat:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Format must be json or zip",
+		})
+	case services.ErrDataExportInProgress:
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "A data export is already being prepared",
+		})
+	case services.ErrDataExportNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Data export not found",
+		})
+	case services.ErrDataExportLink:
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Download link is invalid or has expired",
+		})
+	}
+
+	c.Logger().Errorf("%s: %v", message, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
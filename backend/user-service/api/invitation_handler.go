@@ -1,27 +1,23 @@
 package api
 
 import (
-	"database/sql"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pos/user-service/src/models"
-	"github.com/pos/user-service/src/queue"
 	"github.com/pos/user-service/src/services"
 	"github.com/pos/user-service/src/utils"
 )
 
 type InvitationHandler struct {
 	invitationService *services.InvitationService
+	passwordPolicy    *utils.PasswordPolicy
 }
 
-func NewInvitationHandler(db *sql.DB, eventProducer *queue.KafkaProducer, auditPublisher utils.AuditPublisherInterface) *InvitationHandler {
-	invitationService, err := services.NewInvitationService(db, eventProducer, auditPublisher)
-	if err != nil {
-		panic("Failed to create invitation service: " + err.Error())
-	}
+func NewInvitationHandler(invitationService *services.InvitationService, passwordPolicy *utils.PasswordPolicy) *InvitationHandler {
 	return &InvitationHandler{
 		invitationService: invitationService,
+		passwordPolicy:    passwordPolicy,
 	}
 }
 
@@ -62,12 +58,7 @@ func (h *InvitationHandler) CreateInvitation(c echo.Context) error {
 	}
 
 	// Validate role
-	validRoles := map[string]bool{
-		"admin":   true,
-		"manager": true,
-		"cashier": true,
-	}
-	if !validRoles[req.Role] {
+	if !services.ValidInvitationRole(req.Role) {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Invalid role. Must be one of: admin, manager, cashier",
 		})
@@ -75,11 +66,19 @@ func (h *InvitationHandler) CreateInvitation(c echo.Context) error {
 
 	invitation, err := h.invitationService.Create(c.Request().Context(), tenantID, req.Email, req.Role, userID)
 	if err != nil {
+		if limitErr, ok := err.(*services.PlanLimitError); ok {
+			return planLimitError(c, limitErr)
+		}
 		if err == services.ErrEmailAlreadyExists {
 			return c.JSON(http.StatusConflict, map[string]string{
 				"error": "Email is already registered",
 			})
 		}
+		if err == services.ErrEmailDeactivated {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "This email belongs to a deactivated user; reactivate them instead",
+			})
+		}
 		if err == services.ErrEmailAlreadyInvited {
 			return c.JSON(http.StatusConflict, map[string]string{
 				"error": "Email already has a pending invitation",
@@ -105,6 +104,56 @@ func (h *InvitationHandler) CreateInvitation(c echo.Context) error {
 	return c.JSON(http.StatusCreated, response)
 }
 
+// maxBulkInvitationFileSize bounds the uploaded CSV; 500 rows fit with plenty to spare
+const maxBulkInvitationFileSize = 1 << 20
+
+// BulkCreateInvitations handles POST /invitations/bulk
+// Takes a multipart "file" field holding a CSV of email,role rows and reports the outcome of every row.
+func (h *InvitationHandler) BulkCreateInvitations(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	userID := c.Request().Header.Get("X-User-ID")
+	if tenantID == "" || userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "A CSV file is required",
+		})
+	}
+	if fileHeader.Size > maxBulkInvitationFileSize {
+		return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": "CSV file must be at most 1 MB",
+		})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Logger().Errorf("Failed to open bulk invitation file: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to read CSV file",
+		})
+	}
+	defer file.Close()
+
+	rows, err := services.ParseInvitationCSV(file)
+	if err != nil {
+		if fileErr, ok := err.(*services.BulkInvitationFileError); ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fileErr.Message,
+			})
+		}
+		c.Logger().Errorf("Failed to parse bulk invitation file: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to read CSV file",
+		})
+	}
+
+	return c.JSON(http.StatusOK, h.invitationService.CreateBulk(c.Request().Context(), tenantID, userID, rows))
+}
+
 // ListInvitations handles GET /invitations
 func (h *InvitationHandler) ListInvitations(c echo.Context) error {
 	tenantID := c.Request().Header.Get("X-Tenant-ID")
@@ -161,9 +210,12 @@ func (h *InvitationHandler) AcceptInvitation(c echo.Context) error {
 		})
 	}
 
-	if req.Password == "" || len(req.Password) < 8 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Password must be at least 8 characters",
+	if err := h.passwordPolicy.Validate(c.Request().Context(), req.Password); err != nil {
+		policyErr, _ := err.(*utils.PasswordPolicyError)
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error":      "Password does not meet the password requirements",
+			"details":    policyErr.Error(),
+			"violations": policyErr.Violations,
 		})
 	}
 
@@ -180,7 +232,7 @@ func (h *InvitationHandler) AcceptInvitation(c echo.Context) error {
 		}
 		if err == services.ErrInvitationExpired {
 			return c.JSON(http.StatusGone, map[string]string{
-				"error": "Invitation has expired",
+				"error": "Invitation has expired. A new invitation link has been sent to your email.",
 			})
 		}
 		if err == services.ErrInvitationInvalid {
@@ -193,6 +245,11 @@ func (h *InvitationHandler) AcceptInvitation(c echo.Context) error {
 				"error": "Email is already registered",
 			})
 		}
+		if err == services.ErrEmailDeactivated {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "This email belongs to a deactivated user; reactivate them instead",
+			})
+		}
 
 		c.Logger().Errorf("Failed to accept invitation: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -235,6 +292,11 @@ func (h *InvitationHandler) ResendInvitation(c echo.Context) error {
 
 	invitation, err := h.invitationService.Resend(c.Request().Context(), tenantID, invitationID, userID)
 	if err != nil {
+		if err == services.ErrEmailAlreadyInvited {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "Email already has a pending invitation",
+			})
+		}
 		if err == services.ErrInvitationNotFound {
 			return c.JSON(http.StatusNotFound, map[string]string{
 				"error": "Invitation not found",
@@ -4,23 +4,25 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
 )
 
 // NotificationPreferencesHandler handles notification preference endpoints
 type NotificationPreferencesHandler struct {
 	userService interface {
 		GetUsersWithNotificationPreferences(tenantID string) ([]map[string]interface{}, error)
-		UpdateUserNotificationPreference(tenantID, userID string, receive bool) error
 	}
+	preferenceService *services.NotificationPreferenceService
 }
 
 // NewNotificationPreferencesHandler creates a new notification preferences handler
 func NewNotificationPreferencesHandler(userService interface {
 	GetUsersWithNotificationPreferences(tenantID string) ([]map[string]interface{}, error)
-	UpdateUserNotificationPreference(tenantID, userID string, receive bool) error
-}) *NotificationPreferencesHandler {
+}, preferenceService *services.NotificationPreferenceService) *NotificationPreferencesHandler {
 	return &NotificationPreferencesHandler{
-		userService: userService,
+		userService:       userService,
+		preferenceService: preferenceService,
 	}
 }
 
@@ -60,7 +62,25 @@ func (h *NotificationPreferencesHandler) GetNotificationPreferences(c echo.Conte
 	})
 }
 
+// GetUserNotificationPreferences handles GET /api/v1/users/:user_id/notification-preferences
+func (h *NotificationPreferencesHandler) GetUserNotificationPreferences(c echo.Context) error {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	if tenantID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized - tenant ID not found",
+		})
+	}
+
+	preferences, err := h.preferenceService.Get(c.Request().Context(), tenantID, c.Param("user_id"))
+	if err != nil {
+		return notificationPreferenceError(c, err, "Failed to fetch notification preferences")
+	}
+	return c.JSON(http.StatusOK, preferences)
+}
+
 // PatchNotificationPreferences handles PATCH /api/v1/users/:user_id/notification-preferences
+// Only the event and channel cells in the request change; receive_order_notifications is still
+// accepted and switches order emails on or off.
 func (h *NotificationPreferencesHandler) PatchNotificationPreferences(c echo.Context) error {
 	// Get tenant ID from header (set by API gateway)
 	tenantID := c.Request().Header.Get("X-Tenant-ID")
@@ -84,42 +104,78 @@ func (h *NotificationPreferencesHandler) PatchNotificationPreferences(c echo.Con
 		})
 	}
 
-	// Parse request body
-	var req struct {
-		ReceiveOrderNotifications *bool `json:"receive_order_notifications"`
-	}
-
+	var req models.PatchNotificationPreferencesRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "Invalid request body",
 		})
 	}
 
-	if req.ReceiveOrderNotifications == nil {
+	actorID := c.Request().Header.Get("X-User-ID")
+	preferences, err := h.preferenceService.Patch(c.Request().Context(), tenantID, actorID, userID, &req)
+	if err != nil {
+		return notificationPreferenceError(c, err, "Failed to update notification preference")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"user": map[string]interface{}{
+			"user_id":                     userID,
+			"receive_order_notifications": preferences.Preferences["order.paid"][models.NotificationChannelEmail],
+			"preferences":                 preferences.Preferences,
+		},
+	})
+}
+
+// GetOwnNotificationPreferences handles GET /api/v1/users/me/notification-preferences
+func (h *NotificationPreferencesHandler) GetOwnNotificationPreferences(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	preferences, err := h.preferenceService.Get(c.Request().Context(), tenantID, userID)
+	if err != nil {
+		return notificationPreferenceError(c, err, "Failed to fetch notification preferences")
+	}
+	return c.JSON(http.StatusOK, preferences)
+}
+
+// PatchOwnNotificationPreferences handles PATCH /api/v1/users/me/notification-preferences
+func (h *NotificationPreferencesHandler) PatchOwnNotificationPreferences(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.PatchNotificationPreferencesRequest
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "receive_order_notifications field is required",
+			"error": "Invalid request body",
 		})
 	}
 
-	// Update user preference
-	if err := h.userService.UpdateUserNotificationPreference(tenantID, userID, *req.ReceiveOrderNotifications); err != nil {
-		// Check if user not found
-		if err.Error() == "user not found" {
-			return c.JSON(http.StatusNotFound, map[string]string{
-				"error": "User not found",
-			})
-		}
+	preferences, err := h.preferenceService.Patch(c.Request().Context(), tenantID, userID, userID, &req)
+	if err != nil {
+		return notificationPreferenceError(c, err, "Failed to update notification preferences")
+	}
+	return c.JSON(http.StatusOK, preferences)
+}
 
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to update notification preference",
+func notificationPreferenceError(c echo.Context, err error, message string) error {
+	if validationErr, ok := err.(*services.NotificationPreferenceError); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": validationErr.Message,
+		})
+	}
+	if err == services.ErrUserNotFound {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "User not found",
 		})
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success": true,
-		"user": map[string]interface{}{
-			"user_id":                     userID,
-			"receive_order_notifications": *req.ReceiveOrderNotifications,
-		},
+	c.Logger().Errorf("%s: %v", message, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
 	})
 }
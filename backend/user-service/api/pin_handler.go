@@ -0,0 +1,139 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/services"
+)
+
+// PinHandler manages the quick-switch PINs staff use on shared POS terminals
+type PinHandler struct {
+	pinService *services.PinService
+}
+
+func NewPinHandler(pinService *services.PinService) *PinHandler {
+	return &PinHandler{pinService: pinService}
+}
+
+// userContext returns the tenant and user of an authenticated request, or writes the error response
+func userContext(c echo.Context) (string, string, bool) {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	userID := c.Request().Header.Get("X-User-ID")
+	if tenantID == "" || userID == "" {
+		c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+		return "", "", false
+	}
+	return tenantID, userID, true
+}
+
+// GetOwnPin handles GET /api/v1/users/me/pin
+func (h *PinHandler) GetOwnPin(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	status, err := h.pinService.GetStatus(c.Request().Context(), tenantID, userID)
+	if err != nil {
+		return pinError(c, err, "Failed to get PIN status")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// SetOwnPin handles PUT /api/v1/users/me/pin
+func (h *PinHandler) SetOwnPin(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.SetPinRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.pinService.SetOwn(c.Request().Context(), tenantID, userID, &req); err != nil {
+		return pinError(c, err, "Failed to set PIN")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetPin handles GET /api/v1/users/:user_id/pin
+func (h *PinHandler) GetPin(c echo.Context) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	role := c.Request().Header.Get("X-User-Role")
+	status, err := h.pinService.GetStatusForUser(c.Request().Context(), tenantID, actorID, role, c.Param("user_id"))
+	if err != nil {
+		return pinError(c, err, "Failed to get PIN status")
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// SetPin handles PUT /api/v1/users/:user_id/pin
+func (h *PinHandler) SetPin(c echo.Context) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.SetPinRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	role := c.Request().Header.Get("X-User-Role")
+	if err := h.pinService.SetForUser(c.Request().Context(), tenantID, actorID, role, c.Param("user_id"), &req); err != nil {
+		return pinError(c, err, "Failed to set PIN")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResetPin handles DELETE /api/v1/users/:user_id/pin
+func (h *PinHandler) ResetPin(c echo.Context) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	role := c.Request().Header.Get("X-User-Role")
+	if err := h.pinService.Reset(c.Request().Context(), tenantID, actorID, role, c.Param("user_id")); err != nil {
+		return pinError(c, err, "Failed to reset PIN")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func pinError(c echo.Context, err error, message string) error {
+	if validationErr, ok := err.(*services.PinValidationError); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": validationErr.Message,
+		})
+	}
+	switch err {
+	case repository.ErrPinUserNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "User not found",
+		})
+	case services.ErrPinManageForbidden:
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "You are not allowed to manage this user's PIN",
+		})
+	}
+
+	c.Logger().Errorf("%s: %v", message, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/services"
+)
+
+// RoleHandler serves the custom role endpoints (owner only via API Gateway RBAC)
+type RoleHandler struct {
+	roleService *services.RoleService
+}
+
+func NewRoleHandler(roleService *services.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// ownerContext returns the tenant and user of an owner request, or writes the error response
+func ownerContext(c echo.Context) (string, string, bool) {
+	tenantID := c.Request().Header.Get("X-Tenant-ID")
+	userID := c.Request().Header.Get("X-User-ID")
+	if tenantID == "" || userID == "" {
+		c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Unauthorized",
+		})
+		return "", "", false
+	}
+	if c.Request().Header.Get("X-User-Role") != string(models.RoleOwner) {
+		c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Only tenant owners can manage roles",
+		})
+		return "", "", false
+	}
+	return tenantID, userID, true
+}
+
+// ListRoles handles GET /api/v1/roles
+func (h *RoleHandler) ListRoles(c echo.Context) error {
+	tenantID, _, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	roles, err := h.roleService.List(c.Request().Context(), tenantID)
+	if err != nil {
+		c.Logger().Errorf("Failed to list custom roles: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list roles",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"roles":       roles,
+		"permissions": models.AssignablePermissions,
+	})
+}
+
+// GetRole handles GET /api/v1/roles/:id
+func (h *RoleHandler) GetRole(c echo.Context) error {
+	tenantID, _, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	role, err := h.roleService.Get(c.Request().Context(), tenantID, c.Param("id"))
+	if err != nil {
+		return roleError(c, err, "Failed to get role")
+	}
+	return c.JSON(http.StatusOK, role)
+}
+
+// CreateRole handles POST /api/v1/roles
+func (h *RoleHandler) CreateRole(c echo.Context) error {
+	tenantID, userID, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.CustomRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	role, err := h.roleService.Create(c.Request().Context(), tenantID, userID, &req)
+	if err != nil {
+		return roleError(c, err, "Failed to create role")
+	}
+	return c.JSON(http.StatusCreated, role)
+}
+
+// UpdateRole handles PUT /api/v1/roles/:id
+func (h *RoleHandler) UpdateRole(c echo.Context) error {
+	tenantID, userID, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.CustomRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	role, err := h.roleService.Update(c.Request().Context(), tenantID, userID, c.Param("id"), &req)
+	if err != nil {
+		return roleError(c, err, "Failed to update role")
+	}
+	return c.JSON(http.StatusOK, role)
+}
+
+// DeleteRole handles DELETE /api/v1/roles/:id
+func (h *RoleHandler) DeleteRole(c echo.Context) error {
+	tenantID, userID, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.roleService.Delete(c.Request().Context(), tenantID, userID, c.Param("id")); err != nil {
+		return roleError(c, err, "Failed to delete role")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AssignRole handles PUT /api/v1/users/:user_id/custom-role
+func (h *RoleHandler) AssignRole(c echo.Context) error {
+	tenantID, userID, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.AssignCustomRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	targetUserID := c.Param("user_id")
+	if err := h.roleService.AssignToUser(c.Request().Context(), tenantID, userID, targetUserID, req.CustomRoleID); err != nil {
+		return roleError(c, err, "Failed to assign role")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"user_id":        targetUserID,
+		"custom_role_id": req.CustomRoleID,
+	})
+}
+
+func roleError(c echo.Context, err error, message string) error {
+	if validationErr, ok := err.(*services.RoleValidationError); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": validationErr.Message,
+		})
+	}
+	switch err {
+	case repository.ErrRoleNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Role not found",
+		})
+	case repository.ErrRoleNameTaken:
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "A role with this name already exists",
+		})
+	case services.ErrRoleUserNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "User not found",
+		})
+	case services.ErrOwnerRoleFixed:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Owners cannot be assigned a custom role",
+		})
+	}
+
+	c.Logger().Errorf("%s: %v", message, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,293 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/services"
+)
+
+const scimContentType = "application/scim+json"
+
+// ScimHandler serves the SCIM 2.0 Users endpoint identity providers provision staff through,
+// and the owner endpoints that manage SCIM tokens and group-to-role mappings
+type ScimHandler struct {
+	scimService *services.ScimService
+}
+
+func NewScimHandler(scimService *services.ScimService) *ScimHandler {
+	return &ScimHandler{scimService: scimService}
+}
+
+// Authenticate is middleware for /scim/v2 that resolves the tenant from the bearer token
+// The tenant never comes from request headers, as SCIM requests reach the service without a session.
+func (h *ScimHandler) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Request().Header.Get("Authorization")
+		token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+		if token == "" || token == header {
+			return scimError(c, http.StatusUnauthorized, "", "Bearer token required")
+		}
+
+		tenantID, err := h.scimService.Authenticate(c.Request().Context(), token)
+		if err == services.ErrScimUnauthorized {
+			return scimError(c, http.StatusUnauthorized, "", "Invalid or revoked SCIM token")
+		}
+		if err != nil {
+			c.Logger().Errorf("Failed to authenticate SCIM token: %v", err)
+			return scimError(c, http.StatusInternalServerError, "", "Failed to authenticate")
+		}
+
+		c.Set("scim_tenant_id", tenantID)
+		return next(c)
+	}
+}
+
+func scimTenantID(c echo.Context) string {
+	tenantID, _ := c.Get("scim_tenant_id").(string)
+	return tenantID
+}
+
+// ServiceProviderConfig handles GET /scim/v2/ServiceProviderConfig
+func (h *ScimHandler) ServiceProviderConfig(c echo.Context) error {
+	return scimJSON(c, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": 100},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "SCIM token created by the tenant owner",
+		}},
+	})
+}
+
+// ListUsers handles GET /scim/v2/Users
+func (h *ScimHandler) ListUsers(c echo.Context) error {
+	startIndex, _ := strconv.Atoi(c.QueryParam("startIndex"))
+	count := -1
+	if value := c.QueryParam("count"); value != "" {
+		count, _ = strconv.Atoi(value)
+	}
+
+	list, err := h.scimService.ListUsers(c.Request().Context(), scimTenantID(c), c.QueryParam("filter"), startIndex, count)
+	if err != nil {
+		return scimServiceError(c, err, "Failed to list users")
+	}
+	return scimJSON(c, http.StatusOK, list)
+}
+
+// GetUser handles GET /scim/v2/Users/:id
+func (h *ScimHandler) GetUser(c echo.Context) error {
+	user, err := h.scimService.GetUser(c.Request().Context(), scimTenantID(c), c.Param("id"))
+	if err != nil {
+		return scimServiceError(c, err, "Failed to get user")
+	}
+	return scimJSON(c, http.StatusOK, user)
+}
+
+// CreateUser handles POST /scim/v2/Users
+func (h *ScimHandler) CreateUser(c echo.Context) error {
+	var req models.ScimUser
+	if err := decodeScimBody(c, &req); err != nil {
+		return err
+	}
+
+	user, err := h.scimService.CreateUser(c.Request().Context(), scimTenantID(c), &req)
+	if err != nil {
+		return scimServiceError(c, err, "Failed to create user")
+	}
+	c.Response().Header().Set("Location", user.Meta.Location)
+	return scimJSON(c, http.StatusCreated, user)
+}
+
+// ReplaceUser handles PUT /scim/v2/Users/:id
+func (h *ScimHandler) ReplaceUser(c echo.Context) error {
+	var req models.ScimUser
+	if err := decodeScimBody(c, &req); err != nil {
+		return err
+	}
+
+	user, err := h.scimService.ReplaceUser(c.Request().Context(), scimTenantID(c), c.Param("id"), &req)
+	if err != nil {
+		return scimServiceError(c, err, "Failed to update user")
+	}
+	return scimJSON(c, http.StatusOK, user)
+}
+
+// PatchUser handles PATCH /scim/v2/Users/:id
+func (h *ScimHandler) PatchUser(c echo.Context) error {
+	var req models.ScimPatchRequest
+	if err := decodeScimBody(c, &req); err != nil {
+		return err
+	}
+
+	user, err := h.scimService.PatchUser(c.Request().Context(), scimTenantID(c), c.Param("id"), &req)
+	if err != nil {
+		return scimServiceError(c, err, "Failed to update user")
+	}
+	return scimJSON(c, http.StatusOK, user)
+}
+
+// DeleteUser handles DELETE /scim/v2/Users/:id
+func (h *ScimHandler) DeleteUser(c echo.Context) error {
+	if err := h.scimService.DeleteUser(c.Request().Context(), scimTenantID(c), c.Param("id")); err != nil {
+		return scimServiceError(c, err, "Failed to delete user")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListTokens handles GET /api/v1/scim/tokens
+func (h *ScimHandler) ListTokens(c echo.Context) error {
+	tenantID, _, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	tokens, err := h.scimService.ListTokens(c.Request().Context(), tenantID)
+	if err != nil {
+		c.Logger().Errorf("Failed to list SCIM tokens: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list SCIM tokens",
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tokens": tokens,
+	})
+}
+
+// CreateToken handles POST /api/v1/scim/tokens
+func (h *ScimHandler) CreateToken(c echo.Context) error {
+	tenantID, userID, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.CreateScimTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	token, err := h.scimService.CreateToken(c.Request().Context(), tenantID, userID, &req)
+	if err != nil {
+		return scimAdminError(c, err, "Failed to create SCIM token")
+	}
+	return c.JSON(http.StatusCreated, token)
+}
+
+// RevokeToken handles DELETE /api/v1/scim/tokens/:id
+func (h *ScimHandler) RevokeToken(c echo.Context) error {
+	tenantID, userID, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.scimService.RevokeToken(c.Request().Context(), tenantID, userID, c.Param("id")); err != nil {
+		return scimAdminError(c, err, "Failed to revoke SCIM token")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListGroupMappings handles GET /api/v1/scim/group-mappings
+func (h *ScimHandler) ListGroupMappings(c echo.Context) error {
+	tenantID, _, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	mappings, err := h.scimService.ListGroupMappings(c.Request().Context(), tenantID)
+	if err != nil {
+		c.Logger().Errorf("Failed to list SCIM group mappings: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list group mappings",
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"mappings": mappings,
+	})
+}
+
+// ReplaceGroupMappings handles PUT /api/v1/scim/group-mappings
+func (h *ScimHandler) ReplaceGroupMappings(c echo.Context) error {
+	tenantID, userID, ok := ownerContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.ReplaceScimGroupMappingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	mappings, err := h.scimService.ReplaceGroupMappings(c.Request().Context(), tenantID, userID, &req)
+	if err != nil {
+		return scimAdminError(c, err, "Failed to save group mappings")
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"mappings": mappings,
+	})
+}
+
+// decodeScimBody reads a JSON body; SCIM clients send application/scim+json, which echo's binder rejects
+func decodeScimBody(c echo.Context, v interface{}) error {
+	if err := json.NewDecoder(c.Request().Body).Decode(v); err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidSyntax", "Invalid request body")
+	}
+	return nil
+}
+
+func scimJSON(c echo.Context, status int, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, scimContentType, body)
+}
+
+func scimError(c echo.Context, status int, scimType, detail string) error {
+	return scimJSON(c, status, &models.ScimError{
+		Schemas:  []string{models.ScimErrorSchema},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func scimServiceError(c echo.Context, err error, message string) error {
+	if requestErr, ok := err.(*services.ScimRequestError); ok {
+		return scimError(c, requestErr.Status, requestErr.ScimType, requestErr.Detail)
+	}
+	if err == services.ErrScimUserNotFound {
+		return scimError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	c.Logger().Errorf("%s: %v", message, err)
+	return scimError(c, http.StatusInternalServerError, "", message)
+}
+
+func scimAdminError(c echo.Context, err error, message string) error {
+	switch err {
+	case repository.ErrScimTokenNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "SCIM token not found",
+		})
+	case repository.ErrScimGroupMappingTaken:
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "Each group can only be mapped once",
+		})
+	}
+	return roleError(c, err, message)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/services"
+)
+
+// TeamHandler serves the team endpoints
+// The API Gateway limits them to users.invite, like the other staff management routes.
+type TeamHandler struct {
+	teamService *services.TeamService
+}
+
+func NewTeamHandler(teamService *services.TeamService) *TeamHandler {
+	return &TeamHandler{teamService: teamService}
+}
+
+// ListTeams handles GET /api/v1/teams
+func (h *TeamHandler) ListTeams(c echo.Context) error {
+	tenantID, _, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	teams, err := h.teamService.List(c.Request().Context(), tenantID)
+	if err != nil {
+		c.Logger().Errorf("Failed to list teams: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list teams",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"teams":               teams,
+		"notification_events": models.StaffNotificationEvents,
+		"delivery_types":      models.OrderDeliveryTypes,
+	})
+}
+
+// GetTeam handles GET /api/v1/teams/:team_id
+func (h *TeamHandler) GetTeam(c echo.Context) error {
+	tenantID, _, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	team, err := h.teamService.Get(c.Request().Context(), tenantID, c.Param("team_id"))
+	if err != nil {
+		return teamError(c, err, "Failed to get team")
+	}
+	return c.JSON(http.StatusOK, team)
+}
+
+// CreateTeam handles POST /api/v1/teams
+func (h *TeamHandler) CreateTeam(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.TeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	team, err := h.teamService.Create(c.Request().Context(), tenantID, userID, &req)
+	if err != nil {
+		return teamError(c, err, "Failed to create team")
+	}
+	return c.JSON(http.StatusCreated, team)
+}
+
+// UpdateTeam handles PUT /api/v1/teams/:team_id
+func (h *TeamHandler) UpdateTeam(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.TeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	team, err := h.teamService.Update(c.Request().Context(), tenantID, userID, c.Param("team_id"), &req)
+	if err != nil {
+		return teamError(c, err, "Failed to update team")
+	}
+	return c.JSON(http.StatusOK, team)
+}
+
+// DeleteTeam handles DELETE /api/v1/teams/:team_id
+func (h *TeamHandler) DeleteTeam(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.teamService.Delete(c.Request().Context(), tenantID, userID, c.Param("team_id")); err != nil {
+		return teamError(c, err, "Failed to delete team")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetTeamMembers handles PUT /api/v1/teams/:team_id/members
+func (h *TeamHandler) SetTeamMembers(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.SetTeamMembersRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	team, err := h.teamService.SetMembers(c.Request().Context(), tenantID, userID, c.Param("team_id"), req.UserIDs)
+	if err != nil {
+		return teamError(c, err, "Failed to update team members")
+	}
+	return c.JSON(http.StatusOK, team)
+}
+
+func teamError(c echo.Context, err error, message string) error {
+	if validationErr, ok := err.(*services.TeamValidationError); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": validationErr.Message,
+		})
+	}
+	switch err {
+	case repository.ErrTeamNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Team not found",
+		})
+	case repository.ErrTeamNameTaken:
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "A team with this name already exists",
+		})
+	case repository.ErrTeamMemberNotFound:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Every member must be a user of this tenant",
+		})
+	}
+
+	c.Logger().Errorf("%s: %v", message, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// UserHandler serves staff management: listing users and changing their profile, role and status
+// The API Gateway limits the staff routes to users.invite; who may change whom is checked here.
+type UserHandler struct {
+	userService     *services.UserService
+	activityService *services.ActivityService
+	avatarService   *services.AvatarService
+}
+
+func NewUserHandler(userService *services.UserService, activityService *services.ActivityService, avatarService *services.AvatarService) *UserHandler {
+	return &UserHandler{userService: userService, activityService: activityService, avatarService: avatarService}
+}
+
+// ListUsers handles GET /api/v1/users
+// Optional query parameters: status, role, team_id, offset and limit.
+func (h *UserHandler) ListUsers(c echo.Context) error {
+	tenantID, _, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	users, total, err := h.userService.ListStaff(c.Request().Context(), tenantID, c.QueryParam("status"), c.QueryParam("role"), c.QueryParam("team_id"), c.QueryParam("outlet_id"), offset, limit)
+	if err != nil {
+		return userError(c, err, "Failed to list users")
+	}
+
+	response := &models.UserListResponse{
+		Users: make([]*models.UserResponse, len(users)),
+		Total: total,
+	}
+	for i, user := range users {
+		response.Users[i] = h.avatarService.Response(c.Request().Context(), user)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetUser handles GET /api/v1/users/:user_id
+func (h *UserHandler) GetUser(c echo.Context) error {
+	tenantID, _, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	user, err := h.userService.GetStaff(c.Request().Context(), tenantID, c.Param("user_id"))
+	if err != nil {
+		return userError(c, err, "Failed to get user")
+	}
+	return c.JSON(http.StatusOK, h.avatarService.Response(c.Request().Context(), user))
+}
+
+// GetOwnProfile handles GET /api/v1/users/me
+func (h *UserHandler) GetOwnProfile(c echo.Context) error {
+	tenantID, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	user, err := h.userService.GetStaff(c.Request().Context(), tenantID, userID)
+	if err != nil {
+		return userError(c, err, "Failed to get profile")
+	}
+	return c.JSON(http.StatusOK, h.avatarService.Response(c.Request().Context(), user))
+}
+
+// UpdateOwnProfile handles PATCH /api/v1/users/me
+func (h *UserHandler) UpdateOwnProfile(c echo.Context) error {
+	_, userID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+	return h.updateProfile(c, userID)
+}
+
+// UpdateUser handles PATCH /api/v1/users/:user_id
+func (h *UserHandler) UpdateUser(c echo.Context) error {
+	return h.updateProfile(c, c.Param("user_id"))
+}
+
+func (h *UserHandler) updateProfile(c echo.Context, userID string) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.UpdateUserProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	role := c.Request().Header.Get("X-User-Role")
+	user, err := h.userService.UpdateProfile(c.Request().Context(), tenantID, actorID, role, userID, &req)
+	if err != nil {
+		return userError(c, err, "Failed to update user")
+	}
+	return c.JSON(http.StatusOK, h.avatarService.Response(c.Request().Context(), user))
+}
+
+// ChangeRole handles PUT /api/v1/users/:user_id/role
+func (h *UserHandler) ChangeRole(c echo.Context) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.ChangeUserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	role := c.Request().Header.Get("X-User-Role")
+	user, err := h.userService.ChangeRole(c.Request().Context(), tenantID, actorID, role, c.Param("user_id"), &req)
+	if err != nil {
+		return userError(c, err, "Failed to change user role")
+	}
+	return c.JSON(http.StatusOK, h.avatarService.Response(c.Request().Context(), user))
+}
+
+// SetUserOutlet handles PUT /api/v1/users/:user_id/outlet
+func (h *UserHandler) SetUserOutlet(c echo.Context) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	var req models.SetUserOutletRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request body",
+		})
+	}
+
+	role := c.Request().Header.Get("X-User-Role")
+	user, err := h.userService.SetOutlet(c.Request().Context(), tenantID, actorID, role, c.Param("user_id"), &req)
+	if err != nil {
+		return userError(c, err, "Failed to change user outlet")
+	}
+	return c.JSON(http.StatusOK, h.avatarService.Response(c.Request().Context(), user))
+}
+
+// DeactivateUser handles POST /api/v1/users/:user_id/deactivate
+func (h *UserHandler) DeactivateUser(c echo.Context) error {
+	return h.setActive(c, false)
+}
+
+// ReactivateUser handles POST /api/v1/users/:user_id/reactivate
+func (h *UserHandler) ReactivateUser(c echo.Context) error {
+	return h.setActive(c, true)
+}
+
+func (h *UserHandler) setActive(c echo.Context, active bool) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	role := c.Request().Header.Get("X-User-Role")
+	user, err := h.userService.SetActive(c.Request().Context(), tenantID, actorID, role, c.Param("user_id"), active)
+	if err != nil {
+		return userError(c, err, "Failed to change user status")
+	}
+	return c.JSON(http.StatusOK, h.avatarService.Response(c.Request().Context(), user))
+}
+
+// GetUserActivity handles GET /api/v1/users/:user_id/activity
+// Returns the user's recent logins, stock adjustments and order status changes; limit is optional.
+func (h *UserHandler) GetUserActivity(c echo.Context) error {
+	tenantID, actorID, ok := userContext(c)
+	if !ok {
+		return nil
+	}
+
+	userID := c.Param("user_id")
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	role := c.Request().Header.Get("X-User-Role")
+	activities, err := h.activityService.Activity(c.Request().Context(), tenantID, actorID, role, userID, limit)
+	if err != nil {
+		if _, ok := err.(*services.AuditServiceError); ok {
+			c.Logger().Errorf("Failed to read user activity: %v", err)
+			return c.JSON(http.StatusBadGateway, map[string]string{
+				"error": "User activity is temporarily unavailable",
+			})
+		}
+		return userError(c, err, "Failed to get user activity")
+	}
+	return c.JSON(http.StatusOK, &models.UserActivityResponse{
+		UserID:     userID,
+		Activities: activities,
+	})
+}
+
+func userError(c echo.Context, err error, message string) error {
+	if validationErr, ok := err.(*services.UserValidationError); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": validationErr.Message,
+		})
+	}
+	if limitErr, ok := err.(*services.PlanLimitError); ok {
+		return planLimitError(c, limitErr)
+	}
+	switch err {
+	case services.ErrUserNotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "User not found",
+		})
+	case services.ErrUserManageForbidden:
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "You are not allowed to manage this user",
+		})
+	case services.ErrUserManageSelf:
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "You cannot change your own role or status",
+		})
+	case services.ErrUserStatusChange:
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "Only active users can be deactivated and only deactivated users reactivated",
+		})
+	}
+
+	c.Logger().Errorf("%s: %v", message, err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": message,
+	})
+}
+
+// planLimitError answers an action refused by the tenant's plan, pointing the owner at the plan to upgrade to
+func planLimitError(c echo.Context, err *services.PlanLimitError) error {
+	return c.JSON(http.StatusForbidden, map[string]interface{}{
+		"error":        err.Error(),
+		"code":         "plan_limit_exceeded",
+		"limit":        err.Limit,
+		"max":          err.Max,
+		"upgrade_plan": err.UpgradePlan,
+		"upgrade_hint": err.UpgradeHint(),
+	})
+}
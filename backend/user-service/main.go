@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"strings"
@@ -70,26 +71,76 @@ func main() {
 	}
 	defer auditPublisher.Close()
 
+	// Account lifecycle events; auth-service ends a deactivated user's sessions
+	userEventsProducer := queue.NewKafkaProducer(kafkaBrokers, utils.GetEnv("KAFKA_USER_EVENTS_TOPIC"))
+	defer userEventsProducer.Close()
+	offboarding, err := services.NewOffboarding(db, userEventsProducer, auditPublisher)
+	if err != nil {
+		log.Fatalf("Failed to create offboarding: %v", err)
+	}
+
 	// Health checks
 	e.GET("/health", api.HealthCheck)
 	e.GET("/ready", api.ReadyCheck)
 
 	// Invitation endpoints
-	invitationHandler := api.NewInvitationHandler(db, eventProducer, auditPublisher)
+	invitationService, err := services.NewInvitationService(db, eventProducer, auditPublisher, utils.GetEnvInt("INVITATION_TTL_HOURS"), utils.GetEnvInt("INVITATION_RETENTION_DAYS"))
+	if err != nil {
+		log.Fatalf("Failed to create invitation service: %v", err)
+	}
+	invitationHandler := api.NewInvitationHandler(invitationService, utils.LoadPasswordPolicy())
 	e.POST("/invitations", invitationHandler.CreateInvitation)
+	e.POST("/invitations/bulk", invitationHandler.BulkCreateInvitations)
 	e.GET("/invitations", invitationHandler.ListInvitations)
 	e.POST("/invitations/:token/accept", invitationHandler.AcceptInvitation)
 	e.POST("/invitations/:id/resend", invitationHandler.ResendInvitation)
 
 	// Notification preferences endpoints
-	userService, err := services.NewUserService(db, auditPublisher)
+	userService, err := services.NewUserService(db, auditPublisher, offboarding)
 	if err != nil {
 		log.Fatalf("Failed to create user service: %v", err)
 	}
-	notificationPrefsHandler := api.NewNotificationPreferencesHandler(userService)
+	notificationPrefsHandler := api.NewNotificationPreferencesHandler(userService, services.NewNotificationPreferenceService(db, auditPublisher))
 	e.GET("/api/v1/users/notification-preferences", notificationPrefsHandler.GetNotificationPreferences)
+	e.GET("/api/v1/users/me/notification-preferences", notificationPrefsHandler.GetOwnNotificationPreferences)
+	e.PATCH("/api/v1/users/me/notification-preferences", notificationPrefsHandler.PatchOwnNotificationPreferences)
+	e.GET("/api/v1/users/:user_id/notification-preferences", notificationPrefsHandler.GetUserNotificationPreferences)
 	e.PATCH("/api/v1/users/:user_id/notification-preferences", notificationPrefsHandler.PatchNotificationPreferences)
 
+	userRepo, err := repository.NewUserRepositoryWithVault(db, auditPublisher)
+	if err != nil {
+		log.Fatalf("Failed to create user repository: %v", err)
+	}
+
+	// Avatars share the product photo bucket; without object storage, uploads are refused
+	var avatarStorage *services.AvatarStorage
+	if storageConfig := utils.LoadStorageConfig(); storageConfig.Configured() {
+		avatarStorage, err = services.NewAvatarStorage(storageConfig)
+		if err != nil {
+			log.Fatalf("Failed to create avatar storage: %v", err)
+		}
+	} else {
+		log.Printf("Object storage not configured; avatar uploads are disabled")
+	}
+	avatarService := services.NewAvatarService(userRepo, avatarStorage, auditPublisher)
+
+	// Staff management endpoints; everyone manages their own profile
+	activityService := services.NewActivityService(userService, utils.GetEnv("AUDIT_SERVICE_URL"))
+	userHandler := api.NewUserHandler(userService, activityService, avatarService)
+	e.GET("/api/v1/users", userHandler.ListUsers)
+	e.GET("/api/v1/users/me", userHandler.GetOwnProfile)
+	e.PATCH("/api/v1/users/me", userHandler.UpdateOwnProfile)
+	avatarHandler := api.NewAvatarHandler(avatarService)
+	e.PUT("/api/v1/users/me/avatar", avatarHandler.UploadOwnAvatar)
+	e.DELETE("/api/v1/users/me/avatar", avatarHandler.DeleteOwnAvatar)
+	e.GET("/api/v1/users/:user_id", userHandler.GetUser)
+	e.GET("/api/v1/users/:user_id/activity", userHandler.GetUserActivity)
+	e.PATCH("/api/v1/users/:user_id", userHandler.UpdateUser)
+	e.PUT("/api/v1/users/:user_id/role", userHandler.ChangeRole)
+	e.PUT("/api/v1/users/:user_id/outlet", userHandler.SetUserOutlet)
+	e.POST("/api/v1/users/:user_id/deactivate", userHandler.DeactivateUser)
+	e.POST("/api/v1/users/:user_id/reactivate", userHandler.ReactivateUser)
+
 	// User deletion endpoints - UU PDP compliance (owner only via API Gateway RBAC)
 	userDeletionHandler, err := api.NewUserDeletionHandler(db, auditPublisher)
 	if err != nil {
@@ -97,11 +148,62 @@ func main() {
 	}
 	e.DELETE("/api/v1/users/:user_id", userDeletionHandler.DeleteUser)
 
-	// Initialize cleanup job scheduler (T135-T138)
-	userRepo, err := repository.NewUserRepositoryWithVault(db, auditPublisher)
+	// Custom role endpoints (owner only via API Gateway RBAC)
+	roleHandler := api.NewRoleHandler(services.NewRoleService(db, auditPublisher))
+	e.GET("/api/v1/roles", roleHandler.ListRoles)
+	e.POST("/api/v1/roles", roleHandler.CreateRole)
+	e.GET("/api/v1/roles/:id", roleHandler.GetRole)
+	e.PUT("/api/v1/roles/:id", roleHandler.UpdateRole)
+	e.DELETE("/api/v1/roles/:id", roleHandler.DeleteRole)
+	e.PUT("/api/v1/users/:user_id/custom-role", roleHandler.AssignRole)
+
+	// Teams group staff for notification routing and staff list filters (users.invite via API Gateway RBAC)
+	teamHandler := api.NewTeamHandler(services.NewTeamService(db, auditPublisher))
+	e.GET("/api/v1/teams", teamHandler.ListTeams)
+	e.POST("/api/v1/teams", teamHandler.CreateTeam)
+	e.GET("/api/v1/teams/:team_id", teamHandler.GetTeam)
+	e.PUT("/api/v1/teams/:team_id", teamHandler.UpdateTeam)
+	e.DELETE("/api/v1/teams/:team_id", teamHandler.DeleteTeam)
+	e.PUT("/api/v1/teams/:team_id/members", teamHandler.SetTeamMembers)
+
+	// Quick-switch PINs for shared POS terminals; the auth service verifies them
+	pinHandler := api.NewPinHandler(services.NewPinService(db, auditPublisher))
+	e.GET("/api/v1/users/me/pin", pinHandler.GetOwnPin)
+	e.PUT("/api/v1/users/me/pin", pinHandler.SetOwnPin)
+	e.GET("/api/v1/users/:user_id/pin", pinHandler.GetPin)
+	e.PUT("/api/v1/users/:user_id/pin", pinHandler.SetPin)
+	e.DELETE("/api/v1/users/:user_id/pin", pinHandler.ResetPin)
+
+	// SCIM 2.0 provisioning: identity providers authenticate with a tenant SCIM token
+	scimHandler := api.NewScimHandler(services.NewScimService(db, userRepo, offboarding, auditPublisher))
+	scim := e.Group("/scim/v2", scimHandler.Authenticate)
+	scim.GET("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+	scim.GET("/Users", scimHandler.ListUsers)
+	scim.POST("/Users", scimHandler.CreateUser)
+	scim.GET("/Users/:id", scimHandler.GetUser)
+	scim.PUT("/Users/:id", scimHandler.ReplaceUser)
+	scim.PATCH("/Users/:id", scimHandler.PatchUser)
+	scim.DELETE("/Users/:id", scimHandler.DeleteUser)
+
+	// SCIM token and group mapping endpoints (owner only via API Gateway RBAC)
+	e.GET("/api/v1/scim/tokens", scimHandler.ListTokens)
+	e.POST("/api/v1/scim/tokens", scimHandler.CreateToken)
+	e.DELETE("/api/v1/scim/tokens/:id", scimHandler.RevokeToken)
+	e.GET("/api/v1/scim/group-mappings", scimHandler.ListGroupMappings)
+	e.PUT("/api/v1/scim/group-mappings", scimHandler.ReplaceGroupMappings)
+
+	// Personal data exports (UU PDP right of access); the download link is signed, so it is public
+	dataExportService, err := services.NewDataExportService(db, userRepo, eventProducer, auditPublisher,
+		utils.GetEnv("AUDIT_SERVICE_URL"), utils.GetEnv("DATA_EXPORT_SIGNING_KEY"), utils.GetEnvInt("DATA_EXPORT_LINK_TTL_HOURS"))
 	if err != nil {
-		log.Fatalf("Failed to create user repository: %v", err)
+		log.Fatalf("Failed to create data export service: %v", err)
 	}
+	dataExportHandler := api.NewDataExportHandler(dataExportService)
+	e.POST("/api/v1/users/me/data-exports", dataExportHandler.RequestExport)
+	e.GET("/api/v1/users/me/data-exports/:export_id", dataExportHandler.GetExport)
+	e.GET("/api/v1/data-exports/:export_id/download", dataExportHandler.Download)
+
+	// Initialize cleanup job scheduler (T135-T138)
 	deletionService := services.NewUserDeletionService(userRepo, auditPublisher, db)
 	cleanupJob := services.NewCleanupJob(deletionService, eventProducer)
 	cleanupScheduler := scheduler.NewUserDeletionScheduler(cleanupJob)
@@ -109,6 +211,27 @@ func main() {
 		log.Fatalf("Failed to start cleanup scheduler: %v", err)
 	}
 
+	// Tenant deletion saga: delete the tenant's users once tenant-service asks, after everything that references them
+	tenantEvents := queue.NewKafkaProducer(kafkaBrokers, utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC"))
+	defer tenantEvents.Close()
+	tenantEventHandler := services.NewTenantEventHandler(deletionService, avatarStorage, tenantEvents)
+	consumerCtx, cancelConsumers := context.WithCancel(context.Background())
+	defer cancelConsumers()
+	tenantEventsConsumer := queue.NewKafkaConsumer(kafkaBrokers, utils.GetEnv("KAFKA_TENANT_EVENTS_TOPIC"), serviceName, tenantEventHandler.Handle)
+	go tenantEventsConsumer.Start(consumerCtx)
+
+	// Expire lapsed invitations hourly and delete closed ones past retention
+	invitationScheduler := scheduler.NewInvitationExpiryScheduler(invitationService)
+	if err := invitationScheduler.Start(); err != nil {
+		log.Fatalf("Failed to start invitation expiry scheduler: %v", err)
+	}
+
+	// Delete personal data bundles once their download link has expired
+	dataExportScheduler := scheduler.NewDataExportCleanupScheduler(dataExportService)
+	if err := dataExportScheduler.Start(); err != nil {
+		log.Fatalf("Failed to start data export cleanup scheduler: %v", err)
+	}
+
 	// Start server
 	port := utils.GetEnv("PORT")
 	log.Printf("User service starting on port %s", port)
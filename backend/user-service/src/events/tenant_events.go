@@ -0,0 +1,45 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TenantDeletionParticipant is the name tenant-service asks this service by in deletion requests
+const TenantDeletionParticipant = "user-service"
+
+// TenantEvent is a message on the tenant-events topic
+// tenant-service publishes tenant.deletion.requested; the services it names answer with
+// tenant.deletion.acknowledged once they have deleted their part of the tenant's data.
+type TenantEvent struct {
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	TenantID  string          `json:"tenant_id"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// TenantDeletionRequest is the data of a tenant.deletion.requested event
+type TenantDeletionRequest struct {
+	DeletionID string   `json:"deletion_id"`
+	Services   []string `json:"services"`
+}
+
+// Includes reports whether service is asked to delete its part of the tenant's data
+func (r *TenantDeletionRequest) Includes(service string) bool {
+	for _, s := range r.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantDeletionAck is the data of a tenant.deletion.acknowledged event
+type TenantDeletionAck struct {
+	DeletionID string           `json:"deletion_id"`
+	Service    string           `json:"service"`
+	Status     string           `json:"status"` // "completed" or "failed"
+	Deleted    map[string]int64 `json:"deleted"`
+	Error      string           `json:"error,omitempty"`
+}
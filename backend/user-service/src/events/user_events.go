@@ -0,0 +1,14 @@
+package events
+
+import "time"
+
+// UserEvent represents an account lifecycle change published to Kafka (user-events topic)
+// auth-service consumes user.deactivated to end the user's sessions
+type UserEvent struct {
+	EventID   string                 `json:"event_id"`   // Idempotency key (UUID)
+	EventType string                 `json:"event_type"` // "user.deactivated"
+	TenantID  string                 `json:"tenant_id"`
+	UserID    string                 `json:"user_id"`
+	Data      map[string]interface{} `json:"data"`
+	Timestamp time.Time              `json:"timestamp"`
+}
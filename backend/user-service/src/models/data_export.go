@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Formats of a personal data export
+const (
+	DataExportFormatJSON = "json"
+	DataExportFormatZIP  = "zip"
+)
+
+// Statuses of a personal data export
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+)
+
+// DataExport is a user's request for a copy of their personal data
+// The bundle itself is only read when it is downloaded.
+type DataExport struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id"`
+	UserID      string     `json:"user_id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	SizeBytes   *int       `json:"size_bytes,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	// Signed download link, only filled in while a ready export has not expired
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// CreateDataExportRequest is the body of POST /api/v1/users/me/data-exports
+type CreateDataExportRequest struct {
+	Format string `json:"format"`
+}
+
+// PersonalDataBundle is everything held about a user, as written to an export
+type PersonalDataBundle struct {
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Profile       *UserResponse          `json:"profile"`
+	Sessions      []*SessionRecord       `json:"sessions"`
+	Notifications []*NotificationRecord  `json:"notifications"`
+	AuditTrail    []*AuditEventReference `json:"audit_trail"`
+}
+
+// SessionRecord is one login session of the user
+type SessionRecord struct {
+	ID           string     `json:"id"`
+	IPAddress    *string    `json:"ip_address,omitempty"`
+	UserAgent    *string    `json:"user_agent,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	TerminatedAt *time.Time `json:"terminated_at,omitempty"`
+}
+
+// NotificationRecord is one notification sent to the user
+// Bodies are left out: they carry one-time links and codes.
+type NotificationRecord struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	EventType string     `json:"event_type"`
+	Status    string     `json:"status"`
+	Subject   *string    `json:"subject,omitempty"`
+	Recipient string     `json:"recipient"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// AuditEventReference points at an audit trail entry recording the user's actions
+// Values before and after the change stay in the audit service.
+type AuditEventReference struct {
+	EventID      string    `json:"event_id"`
+	Timestamp    time.Time `json:"timestamp"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+}
@@ -48,3 +48,36 @@ type InvitationResponse struct {
 	InvitedBy string           `json:"invitedBy"`
 	CreatedAt time.Time        `json:"createdAt"`
 }
+
+// Bulk invitation row outcomes
+const (
+	BulkInvitationInvited = "invited"
+	BulkInvitationSkipped = "skipped"
+	BulkInvitationInvalid = "invalid"
+	BulkInvitationFailed  = "failed"
+)
+
+// BulkInvitationRow is one email,role line of a bulk invitation CSV
+type BulkInvitationRow struct {
+	Line  int
+	Email string
+	Role  string
+}
+
+// BulkInvitationResult reports what happened to one CSV row
+type BulkInvitationResult struct {
+	Line         int    `json:"line"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"`
+	InvitationID string `json:"invitationId,omitempty"`
+}
+
+type BulkInvitationResponse struct {
+	Results []*BulkInvitationResult `json:"results"`
+	Invited int                     `json:"invited"`
+	Skipped int                     `json:"skipped"`
+	Invalid int                     `json:"invalid"`
+	Failed  int                     `json:"failed"`
+}
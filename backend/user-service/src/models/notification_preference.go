@@ -0,0 +1,68 @@
+package models
+
+// Channels a staff notification can be delivered on
+const (
+	NotificationChannelEmail    = "email"
+	NotificationChannelWhatsApp = "whatsapp"
+	NotificationChannelPush     = "push"
+	NotificationChannelInApp    = "in_app"
+)
+
+// NotificationChannels are the columns of the preference matrix
+var NotificationChannels = []string{
+	NotificationChannelEmail,
+	NotificationChannelWhatsApp,
+	NotificationChannelPush,
+	NotificationChannelInApp,
+}
+
+// StaffNotificationEvents are the rows of the preference matrix: the events staff can opt in to or out of
+// Security and account emails (password resets, new device logins, ...) are always sent and not listed.
+var StaffNotificationEvents = []string{
+	"order.paid",
+	"order.manual_payment_required",
+	"order.sla_breached",
+	"refund.approval_requested",
+}
+
+// orderNotificationEvents are the events the legacy receive_order_notifications switch covers
+var orderNotificationEvents = []string{
+	"order.paid",
+	"order.manual_payment_required",
+	"order.sla_breached",
+}
+
+// NotificationPreferenceMatrix maps event type to channel to whether the user receives it
+type NotificationPreferenceMatrix map[string]map[string]bool
+
+// DefaultNotificationPreference is whether a user receives an event on a channel they never set
+// Refund approvals are emailed to approvers unless they opt out; everything else is opt-in.
+// notification-service applies the same defaults, and also sends in-app by default to members of a
+// team the event is routed to.
+func DefaultNotificationPreference(eventType, channel string) bool {
+	return eventType == "refund.approval_requested" && channel == NotificationChannelEmail
+}
+
+// OrderEmailPreferences is the matrix patch the legacy receive_order_notifications switch stands for
+func OrderEmailPreferences(receive bool) NotificationPreferenceMatrix {
+	matrix := make(NotificationPreferenceMatrix, len(orderNotificationEvents))
+	for _, eventType := range orderNotificationEvents {
+		matrix[eventType] = map[string]bool{NotificationChannelEmail: receive}
+	}
+	return matrix
+}
+
+// NotificationPreferencesResponse is a user's full preference matrix
+type NotificationPreferencesResponse struct {
+	UserID      string                       `json:"user_id"`
+	Events      []string                     `json:"events"`
+	Channels    []string                     `json:"channels"`
+	Preferences NotificationPreferenceMatrix `json:"preferences"`
+}
+
+// PatchNotificationPreferencesRequest changes only the cells it names
+// receive_order_notifications is the legacy switch for order emails and is still accepted.
+type PatchNotificationPreferencesRequest struct {
+	Preferences               NotificationPreferenceMatrix `json:"preferences"`
+	ReceiveOrderNotifications *bool                        `json:"receive_order_notifications"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SetPinRequest sets the quick-switch PIN a user enters on shared POS terminals
+type SetPinRequest struct {
+	Pin string `json:"pin"`
+}
+
+// PinStatus tells whether a user has a quick-switch PIN, never the PIN itself
+// A PIN is locked after too many wrong entries in a row until it is set or reset again.
+type PinStatus struct {
+	HasPin         bool       `json:"hasPin"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+	FailedAttempts int        `json:"failedAttempts"`
+	LockedAt       *time.Time `json:"lockedAt,omitempty"`
+}
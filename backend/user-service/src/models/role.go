@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// Permissions a custom role may grant; the names match the permissions auth-service puts in the JWT
+// roles.manage is deliberately absent so a custom role can never hand out role management.
+var AssignablePermissions = []string{
+	"products.read",
+	"products.write",
+	"catalog.publish",
+	"orders.read",
+	"orders.write",
+	"orders.refund",
+	"refunds.approve",
+	"analytics.read",
+	"settings.write",
+	"tenant.write",
+	"users.invite",
+	"audit.read",
+	"data_rights.write",
+}
+
+// CustomRole is an owner-defined set of permissions assigned to staff in place of their base role's
+type CustomRole struct {
+	ID          string    `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	Permissions []string  `json:"permissions" db:"permissions"`
+	UserCount   int       `json:"user_count"`
+	CreatedBy   *string   `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CustomRoleRequest struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// AssignCustomRoleRequest assigns a custom role to a user; a null role returns them to their base role
+type AssignCustomRoleRequest struct {
+	CustomRoleID *string `json:"custom_role_id"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ScimUserSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ScimListResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	ScimPatchOpSchema      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ScimErrorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// ScimToken is an API token an identity provider uses to provision a tenant's users
+type ScimToken struct {
+	ID          string     `json:"id" db:"id"`
+	TenantID    string     `json:"tenant_id" db:"tenant_id"`
+	Name        string     `json:"name" db:"name"`
+	TokenPrefix string     `json:"token_prefix" db:"token_prefix"`
+	CreatedBy   *string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+type CreateScimTokenRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateScimTokenResponse carries the plaintext token; it is shown once and never stored
+type CreateScimTokenResponse struct {
+	*ScimToken
+	Token string `json:"token"`
+}
+
+// ScimGroupMapping provisions members of an identity provider group with a base role and optional custom role
+type ScimGroupMapping struct {
+	ID           string    `json:"id" db:"id"`
+	TenantID     string    `json:"tenant_id" db:"tenant_id"`
+	GroupName    string    `json:"group_name" db:"group_name"`
+	Role         string    `json:"role" db:"role"`
+	CustomRoleID *string   `json:"custom_role_id,omitempty" db:"custom_role_id"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ReplaceScimGroupMappingsRequest replaces all of a tenant's group mappings
+type ReplaceScimGroupMappingsRequest struct {
+	Mappings []ScimGroupMappingRequest `json:"mappings"`
+}
+
+type ScimGroupMappingRequest struct {
+	GroupName    string  `json:"group_name"`
+	Role         string  `json:"role"`
+	CustomRoleID *string `json:"custom_role_id,omitempty"`
+}
+
+// ScimUser is the SCIM 2.0 User resource (RFC 7643), limited to the attributes the POS keeps
+// groups is accepted on writes so identity providers that push group names as a user attribute
+// can drive role mapping without a Groups endpoint.
+type ScimUser struct {
+	Schemas    []string       `json:"schemas"`
+	ID         string         `json:"id,omitempty"`
+	ExternalID string         `json:"externalId,omitempty"`
+	UserName   string         `json:"userName"`
+	Name       *ScimName      `json:"name,omitempty"`
+	Emails     []ScimEmail    `json:"emails,omitempty"`
+	Active     *bool          `json:"active,omitempty"`
+	Locale     string         `json:"locale,omitempty"`
+	Groups     []ScimGroupRef `json:"groups,omitempty"`
+	Meta       *ScimMeta      `json:"meta,omitempty"`
+}
+
+type ScimName struct {
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type ScimEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type ScimGroupRef struct {
+	Value   string `json:"value,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type ScimMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+type ScimListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    []*ScimUser `json:"Resources"`
+}
+
+type ScimPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []ScimPatchOperation `json:"Operations"`
+}
+
+type ScimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type ScimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
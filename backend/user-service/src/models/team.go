@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// Delivery types a team notification route can be limited to, as on guest orders
+var OrderDeliveryTypes = []string{"pickup", "delivery", "dine_in"}
+
+// Team groups staff, e.g. Kitchen or Front-of-house; a user may belong to several teams
+type Team struct {
+	ID                 string                  `json:"id" db:"id"`
+	TenantID           string                  `json:"tenant_id" db:"tenant_id"`
+	Name               string                  `json:"name" db:"name"`
+	Description        *string                 `json:"description,omitempty" db:"description"`
+	MemberIDs          []string                `json:"member_ids"`
+	NotificationRoutes []TeamNotificationRoute `json:"notification_routes"`
+	CreatedBy          *string                 `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt          time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at" db:"updated_at"`
+}
+
+// TeamNotificationRoute sends a staff notification event to every member of a team
+// Members still opt out per channel in their notification preferences.
+type TeamNotificationRoute struct {
+	EventType     string   `json:"event_type"`
+	DeliveryTypes []string `json:"delivery_types"` // Empty matches every order
+}
+
+type TeamRequest struct {
+	Name               string                  `json:"name"`
+	Description        *string                 `json:"description,omitempty"`
+	NotificationRoutes []TeamNotificationRoute `json:"notification_routes"`
+}
+
+// SetTeamMembersRequest replaces the members of a team
+type SetTeamMembersRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
@@ -14,9 +14,16 @@ type User struct {
 	FirstName    *string    `json:"first_name,omitempty" db:"first_name"`
 	LastName     *string    `json:"last_name,omitempty" db:"last_name"`
 	Locale       string     `json:"locale" db:"locale"`
+	Timezone     *string    `json:"timezone,omitempty" db:"timezone"`
+	OutletID     *string    `json:"outlet_id,omitempty" db:"outlet_id"`
 	LastLoginAt  *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	// Only filled in when listing staff
+	CustomRoleID   *string `json:"custom_role_id,omitempty" db:"custom_role_id"`
+	CustomRoleName *string `json:"custom_role_name,omitempty"`
+	// Object storage key of the avatar; responses carry a presigned URL instead
+	AvatarStorageKey *string `json:"-" db:"avatar_storage_key"`
 }
 
 type UserRole string
@@ -55,21 +62,81 @@ type UserResponse struct {
 	FirstName   *string    `json:"first_name,omitempty"`
 	LastName    *string    `json:"last_name,omitempty"`
 	Locale      string     `json:"locale"`
+	Timezone    *string    `json:"timezone"`
+	OutletID    *string    `json:"outlet_id"` // Null for users working at every outlet
 	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
+	// Custom role the user acts with in place of their base role, if any
+	CustomRoleID   *string `json:"custom_role_id,omitempty"`
+	CustomRoleName *string `json:"custom_role_name,omitempty"`
+	// Presigned avatar URL, valid for AVATAR_URL_TTL_SECONDS
+	AvatarURL string `json:"avatar_url,omitempty"`
 }
 
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
-		ID:          u.ID,
-		TenantID:    u.TenantID,
-		Email:       u.Email,
-		Role:        u.Role,
-		Status:      u.Status,
-		FirstName:   u.FirstName,
-		LastName:    u.LastName,
-		Locale:      u.Locale,
-		LastLoginAt: u.LastLoginAt,
-		CreatedAt:   u.CreatedAt,
+		ID:             u.ID,
+		TenantID:       u.TenantID,
+		Email:          u.Email,
+		Role:           u.Role,
+		Status:         u.Status,
+		FirstName:      u.FirstName,
+		LastName:       u.LastName,
+		Locale:         u.Locale,
+		Timezone:       u.Timezone,
+		OutletID:       u.OutletID,
+		LastLoginAt:    u.LastLoginAt,
+		CreatedAt:      u.CreatedAt,
+		CustomRoleID:   u.CustomRoleID,
+		CustomRoleName: u.CustomRoleName,
 	}
 }
+
+// UpdateUserProfileRequest changes a staff member's profile; omitted fields are left as they are
+// An empty timezone clears it, so dates follow the server's timezone again.
+type UpdateUserProfileRequest struct {
+	FirstName *string `json:"first_name,omitempty"`
+	LastName  *string `json:"last_name,omitempty"`
+	Locale    *string `json:"locale,omitempty"`
+	Timezone  *string `json:"timezone,omitempty"`
+}
+
+// ChangeUserRoleRequest changes a staff member's base role
+type ChangeUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// SetUserOutletRequest assigns a staff member to the outlet they work at; null for every outlet
+type SetUserOutletRequest struct {
+	OutletID *string `json:"outlet_id"`
+}
+
+// UserListResponse is a page of a tenant's staff
+type UserListResponse struct {
+	Users []*UserResponse `json:"users"`
+	Total int             `json:"total"`
+}
+
+// Activity types of a staff member's activity feed
+const (
+	ActivityLogin           = "login"
+	ActivityStockAdjustment = "stock_adjustment"
+	ActivityOrderStatus     = "order_status"
+)
+
+// UserActivity is one entry of a staff member's activity feed, read from the audit trail
+type UserActivity struct {
+	Type         string                 `json:"type"`
+	Timestamp    time.Time              `json:"timestamp"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	Before       map[string]interface{} `json:"before,omitempty"`
+	After        map[string]interface{} `json:"after,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+}
+
+// UserActivityResponse is a staff member's most recent activity, newest first
+type UserActivityResponse struct {
+	UserID     string          `json:"user_id"`
+	Activities []*UserActivity `json:"activities"`
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/utils"
+)
+
+// DataExportRepository stores personal data exports and reads the records they are assembled from
+// Sessions belong to auth-service and notifications to notification-service; both are read from the shared
+// database and decrypted with the contexts those services write them with.
+type DataExportRepository struct {
+	db        *sql.DB
+	encryptor utils.Encryptor
+}
+
+func NewDataExportRepository(db *sql.DB, encryptor utils.Encryptor) *DataExportRepository {
+	return &DataExportRepository{db: db, encryptor: encryptor}
+}
+
+// NewDataExportRepositoryWithVault creates a DataExportRepository with a real VaultClient
+func NewDataExportRepositoryWithVault(db *sql.DB) (*DataExportRepository, error) {
+	vaultClient, err := utils.NewVaultClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize VaultClient: %w", err)
+	}
+	return NewDataExportRepository(db, vaultClient), nil
+}
+
+const dataExportColumns = `id, tenant_id, user_id, format, status, size_bytes, expires_at, completed_at, created_at`
+
+func scanDataExport(row interface{ Scan(...interface{}) error }) (*models.DataExport, error) {
+	export := &models.DataExport{}
+	var sizeBytes sql.NullInt64
+	var expiresAt, completedAt sql.NullTime
+	err := row.Scan(&export.ID, &export.TenantID, &export.UserID, &export.Format, &export.Status,
+		&sizeBytes, &expiresAt, &completedAt, &export.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if sizeBytes.Valid {
+		size := int(sizeBytes.Int64)
+		export.SizeBytes = &size
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	return export, nil
+}
+
+// Create saves a new pending export
+func (r *DataExportRepository) Create(ctx context.Context, tenantID, userID, format string) (*models.DataExport, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_data_exports (tenant_id, user_id, format, status)
+		VALUES ($1, $2, $3, 'pending')
+		RETURNING `+dataExportColumns,
+		tenantID, userID, format)
+	return scanDataExport(row)
+}
+
+// FindPending returns the user's export still being assembled, or nil
+func (r *DataExportRepository) FindPending(ctx context.Context, tenantID, userID string) (*models.DataExport, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+dataExportColumns+`
+		FROM user_data_exports
+		WHERE tenant_id = $1 AND user_id = $2 AND status = 'pending'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, tenantID, userID)
+	export, err := scanDataExport(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return export, err
+}
+
+// FindByID returns one of the user's exports, or nil
+func (r *DataExportRepository) FindByID(ctx context.Context, tenantID, userID, id string) (*models.DataExport, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+dataExportColumns+`
+		FROM user_data_exports
+		WHERE id = $1 AND tenant_id = $2 AND user_id = $3
+	`, id, tenantID, userID)
+	export, err := scanDataExport(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return export, err
+}
+
+// FindBundle returns a ready, unexpired export with its decrypted bundle, or nil
+func (r *DataExportRepository) FindBundle(ctx context.Context, id string) (*models.DataExport, []byte, error) {
+	export := &models.DataExport{}
+	var encryptedBundle string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, user_id, format, status, bundle
+		FROM user_data_exports
+		WHERE id = $1 AND status = 'ready' AND expires_at > NOW()
+	`, id).Scan(&export.ID, &export.TenantID, &export.UserID, &export.Format, &export.Status, &encryptedBundle)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := r.encryptor.DecryptWithContext(ctx, encryptedBundle, "user_data_export:bundle")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt bundle: %w", err)
+	}
+	bundle, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	return export, bundle, nil
+}
+
+// MarkReady stores the assembled bundle and starts the download link lifetime
+func (r *DataExportRepository) MarkReady(ctx context.Context, export *models.DataExport, bundle []byte, expiresAt time.Time) error {
+	encryptedBundle, err := r.encryptor.EncryptWithContext(ctx, base64.StdEncoding.EncodeToString(bundle), "user_data_export:bundle")
+	if err != nil {
+		return fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+
+	now := time.Now()
+	size := len(bundle)
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE user_data_exports
+		SET status = 'ready', bundle = $1, size_bytes = $2, expires_at = $3, completed_at = $4
+		WHERE id = $5
+	`, encryptedBundle, size, expiresAt, now, export.ID)
+	if err != nil {
+		return err
+	}
+	export.Status = models.DataExportReady
+	export.SizeBytes = &size
+	export.ExpiresAt = &expiresAt
+	export.CompletedAt = &now
+	return nil
+}
+
+// MarkFailed records why an export could not be assembled
+func (r *DataExportRepository) MarkFailed(ctx context.Context, id, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_data_exports
+		SET status = 'failed', error_msg = $1, completed_at = NOW()
+		WHERE id = $2
+	`, reason, id)
+	return err
+}
+
+// DeleteExpired removes exports whose download link has expired, and failed or stuck ones older than a day
+func (r *DataExportRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM user_data_exports
+		WHERE expires_at < NOW()
+		   OR (status <> 'ready' AND created_at < NOW() - INTERVAL '1 day')
+	`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListSessions returns every login session of the user, newest first
+func (r *DataExportRepository) ListSessions(ctx context.Context, tenantID, userID string) ([]*models.SessionRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, ip_address, user_agent, created_at, expires_at, terminated_at
+		FROM sessions
+		WHERE tenant_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+	`, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.SessionRecord, 0)
+	for rows.Next() {
+		session := &models.SessionRecord{}
+		var ipAddress, userAgent sql.NullString
+		var terminatedAt sql.NullTime
+		if err := rows.Scan(&session.ID, &ipAddress, &userAgent, &session.CreatedAt, &session.ExpiresAt, &terminatedAt); err != nil {
+			return nil, err
+		}
+		if ipAddress.Valid && ipAddress.String != "" {
+			decrypted, err := r.encryptor.DecryptWithContext(ctx, ipAddress.String, "session:ip_address")
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt session ip_address: %w", err)
+			}
+			session.IPAddress = &decrypted
+		}
+		if userAgent.Valid && userAgent.String != "" {
+			session.UserAgent = &userAgent.String
+		}
+		if terminatedAt.Valid {
+			session.TerminatedAt = &terminatedAt.Time
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// ListNotifications returns every notification sent to the user, newest first
+func (r *DataExportRepository) ListNotifications(ctx context.Context, tenantID, userID string) ([]*models.NotificationRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, type, event_type, status, subject, recipient, sent_at, created_at
+		FROM notifications
+		WHERE tenant_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+	`, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]*models.NotificationRecord, 0)
+	for rows.Next() {
+		notification := &models.NotificationRecord{}
+		var subject sql.NullString
+		var encryptedRecipient string
+		var sentAt sql.NullTime
+		if err := rows.Scan(&notification.ID, &notification.Type, &notification.EventType, &notification.Status,
+			&subject, &encryptedRecipient, &sentAt, &notification.CreatedAt); err != nil {
+			return nil, err
+		}
+		recipient, err := r.encryptor.DecryptWithContext(ctx, encryptedRecipient, "notification:recipient")
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt notification recipient: %w", err)
+		}
+		notification.Recipient = recipient
+		if subject.Valid {
+			notification.Subject = &subject.String
+		}
+		if sentAt.Valid {
+			notification.SentAt = &sentAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, rows.Err()
+}
@@ -68,6 +68,7 @@ func (r *InvitationRepository) Create(ctx context.Context, invitation *models.In
 	return err
 }
 
+// FindByToken returns the pending or expired invitation a link points to
 func (r *InvitationRepository) FindByToken(ctx context.Context, token string) (*models.Invitation, error) {
 	// Encrypt token for direct lookup with deterministic encryption (Phase 2)
 	encryptedTokenForQuery, err := r.encryptor.EncryptWithContext(ctx, token, "invitation:token")
@@ -78,7 +79,7 @@ func (r *InvitationRepository) FindByToken(ctx context.Context, token string) (*
 	query := `
 		SELECT id, tenant_id, email, role, token, status, invited_by, expires_at, accepted_at, created_at, updated_at
 		FROM invitations
-		WHERE token = $1 AND status = $2
+		WHERE token = $1 AND status IN ($2, $3)
 		LIMIT 1
 	`
 
@@ -86,7 +87,7 @@ func (r *InvitationRepository) FindByToken(ctx context.Context, token string) (*
 	var acceptedAt sql.NullTime
 	var encryptedEmail, encryptedToken string
 
-	err = r.db.QueryRowContext(ctx, query, encryptedTokenForQuery, models.InvitationPending).Scan(
+	err = r.db.QueryRowContext(ctx, query, encryptedTokenForQuery, models.InvitationPending, models.InvitationExpired).Scan(
 		&invitation.ID,
 		&invitation.TenantID,
 		&encryptedEmail,
@@ -318,6 +319,7 @@ func (r *InvitationRepository) FindByID(ctx context.Context, id string) (*models
 	return invitation, nil
 }
 
+// UpdateToken replaces the link of an invitation and extends it; an expired invitation becomes pending again
 func (r *InvitationRepository) UpdateToken(ctx context.Context, id, token string, expiresAt time.Time) error {
 	// Encrypt the new token with context (Phase 2)
 	encryptedToken, err := r.encryptor.EncryptWithContext(ctx, token, "invitation:token")
@@ -327,10 +329,35 @@ func (r *InvitationRepository) UpdateToken(ctx context.Context, id, token string
 
 	query := `
 		UPDATE invitations
-		SET token = $1, expires_at = $2, updated_at = $3
-		WHERE id = $4
+		SET token = $1, expires_at = $2, status = $3, updated_at = $4
+		WHERE id = $5
 	`
 
-	_, err = r.db.ExecContext(ctx, query, encryptedToken, expiresAt, time.Now(), id)
+	_, err = r.db.ExecContext(ctx, query, encryptedToken, expiresAt, models.InvitationPending, time.Now(), id)
 	return err
 }
+
+// ExpirePending marks pending invitations past their expiry as expired
+func (r *InvitationRepository) ExpirePending(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE invitations SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND expires_at < NOW()
+	`, models.InvitationExpired, models.InvitationPending)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteClosedBefore deletes expired, revoked and cancelled invitations last changed before the cutoff
+// Accepted invitations are kept as the record of how a user joined.
+func (r *InvitationRepository) DeleteClosedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM invitations
+		WHERE status IN ($1, $2, 'cancelled') AND updated_at < $3
+	`, models.InvitationExpired, models.InvitationRevoked, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pos/user-service/src/models"
+)
+
+// NotificationPreferenceRepository stores the cells of users' notification preference matrices
+// Cells a user never set are not stored; notification-service falls back to the event's default for them.
+type NotificationPreferenceRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationPreferenceRepository(db *sql.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// List returns the cells the user has set; sql.ErrNoRows when the user does not exist
+func (r *NotificationPreferenceRepository) List(ctx context.Context, tenantID, userID string) (models.NotificationPreferenceMatrix, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM users WHERE id = $1 AND tenant_id = $2 AND status <> 'deleted')
+	`, userID, tenantID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT event_type, channel, enabled
+		FROM user_notification_preferences
+		WHERE tenant_id = $1 AND user_id = $2
+	`, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matrix := make(models.NotificationPreferenceMatrix)
+	for rows.Next() {
+		var eventType, channel string
+		var enabled bool
+		if err := rows.Scan(&eventType, &channel, &enabled); err != nil {
+			return nil, err
+		}
+		if matrix[eventType] == nil {
+			matrix[eventType] = make(map[string]bool)
+		}
+		matrix[eventType][channel] = enabled
+	}
+	return matrix, rows.Err()
+}
+
+// Set stores the given cells and leaves the others untouched; sql.ErrNoRows when the user does not exist
+// The legacy receive_order_notifications column follows the order.paid email cell.
+func (r *NotificationPreferenceRepository) Set(ctx context.Context, tenantID, userID string, cells models.NotificationPreferenceMatrix) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM users WHERE id = $1 AND tenant_id = $2 AND status <> 'deleted' FOR UPDATE
+	`, userID, tenantID).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	for eventType, channels := range cells {
+		for channel, enabled := range channels {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO user_notification_preferences (tenant_id, user_id, event_type, channel, enabled)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (user_id, event_type, channel)
+				DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = NOW()
+			`, tenantID, userID, eventType, channel, enabled)
+			if err != nil {
+				return fmt.Errorf("failed to save %s %s preference: %w", eventType, channel, err)
+			}
+		}
+	}
+
+	if enabled, ok := cells["order.paid"][models.NotificationChannelEmail]; ok {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE users SET receive_order_notifications = $1, updated_at = NOW()
+			WHERE id = $2 AND tenant_id = $3
+		`, enabled, userID, tenantID)
+		if err != nil {
+			return fmt.Errorf("failed to update order notification preference: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pos/user-service/src/models"
+)
+
+var ErrPinUserNotFound = errors.New("user not found")
+
+// PinRepository stores the quick-switch PINs of users
+type PinRepository struct {
+	db *sql.DB
+}
+
+func NewPinRepository(db *sql.DB) *PinRepository {
+	return &PinRepository{db: db}
+}
+
+// GetStatus returns whether the user has a PIN and their base role
+func (r *PinRepository) GetStatus(ctx context.Context, tenantID, userID string) (*models.PinStatus, string, error) {
+	status := &models.PinStatus{}
+	var pinHash sql.NullString
+	var updatedAt, lockedAt sql.NullTime
+	var role string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT role, pin_hash, pin_updated_at, pin_failed_attempts, pin_locked_at
+		FROM users
+		WHERE id = $1 AND tenant_id = $2 AND status <> 'deleted'
+	`, userID, tenantID).Scan(&role, &pinHash, &updatedAt, &status.FailedAttempts, &lockedAt)
+	if err == sql.ErrNoRows {
+		return nil, "", ErrPinUserNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	status.HasPin = pinHash.Valid
+	if updatedAt.Valid {
+		status.UpdatedAt = &updatedAt.Time
+	}
+	if lockedAt.Valid {
+		status.LockedAt = &lockedAt.Time
+	}
+	return status, role, nil
+}
+
+// SetHash stores a user's PIN hash and unlocks it; a nil hash removes the PIN
+func (r *PinRepository) SetHash(ctx context.Context, tenantID, userID string, pinHash *string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET pin_hash = $1, pin_updated_at = CASE WHEN $1::VARCHAR IS NULL THEN NULL ELSE NOW() END,
+		    pin_failed_attempts = 0, pin_locked_at = NULL, updated_at = NOW()
+		WHERE id = $2 AND tenant_id = $3 AND status <> 'deleted'
+	`, pinHash, userID, tenantID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPinUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/pos/user-service/src/models"
+)
+
+var (
+	ErrRoleNotFound  = errors.New("custom role not found")
+	ErrRoleNameTaken = errors.New("custom role name already exists")
+)
+
+// RoleRepository stores tenants' custom roles and their assignment to users
+type RoleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+const customRoleColumns = `
+	tr.id, tr.tenant_id, tr.name, tr.description, tr.permissions, tr.created_by, tr.created_at, tr.updated_at,
+	(SELECT COUNT(*) FROM users u WHERE u.custom_role_id = tr.id AND u.status <> 'deleted')
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCustomRole(row rowScanner) (*models.CustomRole, error) {
+	role := &models.CustomRole{}
+	var permissions pq.StringArray
+	err := row.Scan(
+		&role.ID,
+		&role.TenantID,
+		&role.Name,
+		&role.Description,
+		&permissions,
+		&role.CreatedBy,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+		&role.UserCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	role.Permissions = permissions
+	return role, nil
+}
+
+// List returns a tenant's custom roles ordered by name
+func (r *RoleRepository) List(ctx context.Context, tenantID string) ([]*models.CustomRole, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+customRoleColumns+`
+		FROM tenant_roles tr
+		WHERE tr.tenant_id = $1
+		ORDER BY LOWER(tr.name)
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*models.CustomRole{}
+	for rows.Next() {
+		role, err := scanCustomRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *RoleRepository) FindByID(ctx context.Context, tenantID, roleID string) (*models.CustomRole, error) {
+	role, err := scanCustomRole(r.db.QueryRowContext(ctx, `
+		SELECT `+customRoleColumns+`
+		FROM tenant_roles tr
+		WHERE tr.id = $1 AND tr.tenant_id = $2
+	`, roleID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrRoleNotFound
+	}
+	return role, err
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *models.CustomRole) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO tenant_roles (tenant_id, name, description, permissions, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, role.TenantID, role.Name, role.Description, pq.Array(role.Permissions), role.CreatedBy).
+		Scan(&role.ID, &role.CreatedAt, &role.UpdatedAt)
+	return mapRoleError(err)
+}
+
+func (r *RoleRepository) Update(ctx context.Context, role *models.CustomRole) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE tenant_roles
+		SET name = $1, description = $2, permissions = $3, updated_at = NOW()
+		WHERE id = $4 AND tenant_id = $5
+		RETURNING updated_at
+	`, role.Name, role.Description, pq.Array(role.Permissions), role.ID, role.TenantID).Scan(&role.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrRoleNotFound
+	}
+	return mapRoleError(err)
+}
+
+// Delete removes a custom role; its users fall back to their base role's permissions
+func (r *RoleRepository) Delete(ctx context.Context, tenantID, roleID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM tenant_roles WHERE id = $1 AND tenant_id = $2
+	`, roleID, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+// GetUserAssignment returns a user's base role and current custom role
+func (r *RoleRepository) GetUserAssignment(ctx context.Context, tenantID, userID string) (string, *string, error) {
+	var baseRole string
+	var customRoleID *string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT role, custom_role_id FROM users
+		WHERE id = $1 AND tenant_id = $2 AND status <> 'deleted'
+	`, userID, tenantID).Scan(&baseRole, &customRoleID)
+	if err != nil {
+		return "", nil, err
+	}
+	return baseRole, customRoleID, nil
+}
+
+// AssignToUser sets or clears (nil roleID) the custom role of a user
+func (r *RoleRepository) AssignToUser(ctx context.Context, tenantID, userID string, roleID *string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET custom_role_id = $1, updated_at = NOW()
+		WHERE id = $2 AND tenant_id = $3
+	`, roleID, userID, tenantID)
+	return err
+}
+
+func mapRoleError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrRoleNameTaken
+	}
+	return err
+}
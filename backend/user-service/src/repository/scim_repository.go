@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/pos/user-service/src/models"
+)
+
+var (
+	ErrScimTokenNotFound     = errors.New("SCIM token not found")
+	ErrScimExternalIDTaken   = errors.New("SCIM external ID already in use")
+	ErrScimGroupMappingTaken = errors.New("SCIM group mapped more than once")
+)
+
+// ScimRepository stores SCIM API tokens, group-to-role mappings and the external IDs of provisioned users
+type ScimRepository struct {
+	db *sql.DB
+}
+
+func NewScimRepository(db *sql.DB) *ScimRepository {
+	return &ScimRepository{db: db}
+}
+
+func (r *ScimRepository) CreateToken(ctx context.Context, token *models.ScimToken, tokenHash string) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO tenant_scim_tokens (tenant_id, name, token_hash, token_prefix, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, token.TenantID, token.Name, tokenHash, token.TokenPrefix, token.CreatedBy).Scan(&token.ID, &token.CreatedAt)
+}
+
+// ListTokens returns a tenant's SCIM tokens, newest first, revoked ones included
+func (r *ScimRepository) ListTokens(ctx context.Context, tenantID string) ([]*models.ScimToken, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, name, token_prefix, created_by, created_at, last_used_at, revoked_at
+		FROM tenant_scim_tokens
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []*models.ScimToken{}
+	for rows.Next() {
+		token := &models.ScimToken{}
+		if err := rows.Scan(&token.ID, &token.TenantID, &token.Name, &token.TokenPrefix, &token.CreatedBy,
+			&token.CreatedAt, &token.LastUsedAt, &token.RevokedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *ScimRepository) RevokeToken(ctx context.Context, tenantID, tokenID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tenant_scim_tokens SET revoked_at = NOW()
+		WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL
+	`, tokenID, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrScimTokenNotFound
+	}
+	return nil
+}
+
+// RevokeTokensCreatedBy revokes the unrevoked tokens a user issued and returns their IDs
+func (r *ScimRepository) RevokeTokensCreatedBy(ctx context.Context, tenantID, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE tenant_scim_tokens SET revoked_at = NOW()
+		WHERE tenant_id = $1 AND created_by = $2 AND revoked_at IS NULL
+		RETURNING id
+	`, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// UseToken returns the tenant of an unrevoked token and records that it was used
+func (r *ScimRepository) UseToken(ctx context.Context, tokenHash string) (string, error) {
+	var tenantID string
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE tenant_scim_tokens SET last_used_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+		RETURNING tenant_id
+	`, tokenHash).Scan(&tenantID)
+	if err == sql.ErrNoRows {
+		return "", ErrScimTokenNotFound
+	}
+	return tenantID, err
+}
+
+// ListGroupMappings returns a tenant's group mappings ordered by group name
+func (r *ScimRepository) ListGroupMappings(ctx context.Context, tenantID string) ([]*models.ScimGroupMapping, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, group_name, role, custom_role_id, created_at
+		FROM tenant_scim_group_mappings
+		WHERE tenant_id = $1
+		ORDER BY LOWER(group_name)
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mappings := []*models.ScimGroupMapping{}
+	for rows.Next() {
+		mapping := &models.ScimGroupMapping{}
+		if err := rows.Scan(&mapping.ID, &mapping.TenantID, &mapping.GroupName, &mapping.Role,
+			&mapping.CustomRoleID, &mapping.CreatedAt); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, rows.Err()
+}
+
+// ReplaceGroupMappings swaps a tenant's group mappings for the given ones in one transaction
+func (r *ScimRepository) ReplaceGroupMappings(ctx context.Context, tenantID string, mappings []*models.ScimGroupMapping) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tenant_scim_group_mappings WHERE tenant_id = $1`, tenantID); err != nil {
+		return err
+	}
+	for _, mapping := range mappings {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO tenant_scim_group_mappings (tenant_id, group_name, role, custom_role_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		`, tenantID, mapping.GroupName, mapping.Role, mapping.CustomRoleID).Scan(&mapping.ID, &mapping.CreatedAt)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return ErrScimGroupMappingTaken
+			}
+			return err
+		}
+		mapping.TenantID = tenantID
+	}
+	return tx.Commit()
+}
+
+// ListUserIDs returns one page of a tenant's non-deleted users in creation order, and their total
+func (r *ScimRepository) ListUserIDs(ctx context.Context, tenantID string, offset, limit int) ([]string, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM users WHERE tenant_id = $1 AND status != 'deleted'
+	`, tenantID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM users
+		WHERE tenant_id = $1 AND status != 'deleted'
+		ORDER BY created_at, id
+		OFFSET $2 LIMIT $3
+	`, tenantID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, 0, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, total, rows.Err()
+}
+
+// FindUserIDByExternalID returns the non-deleted user the identity provider knows by externalID, or ""
+func (r *ScimRepository) FindUserIDByExternalID(ctx context.Context, tenantID, externalID string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id FROM users
+		WHERE tenant_id = $1 AND scim_external_id = $2 AND status != 'deleted'
+	`, tenantID, externalID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return id, err
+}
+
+// GetScimAttributes returns the external ID and groups the identity provider last set on a user
+// Both are empty for users that were never provisioned through SCIM; groups is then nil.
+func (r *ScimRepository) GetScimAttributes(ctx context.Context, tenantID, userID string) (string, []models.ScimGroupRef, error) {
+	var externalID sql.NullString
+	var groupsJSON []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT scim_external_id, scim_groups FROM users WHERE tenant_id = $1 AND id = $2
+	`, tenantID, userID).Scan(&externalID, &groupsJSON)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var groups []models.ScimGroupRef
+	if groupsJSON != nil {
+		if err := json.Unmarshal(groupsJSON, &groups); err != nil {
+			return "", nil, err
+		}
+	}
+	return externalID.String, groups, nil
+}
+
+// SetScimAttributes stores the external ID ("" clears it) and groups of a user; nil groups are left unchanged
+func (r *ScimRepository) SetScimAttributes(ctx context.Context, tenantID, userID, externalID string, groups []models.ScimGroupRef) error {
+	var groupsJSON interface{}
+	if groups != nil {
+		data, err := json.Marshal(groups)
+		if err != nil {
+			return err
+		}
+		groupsJSON = string(data)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET scim_external_id = NULLIF($1, ''), scim_groups = COALESCE($2, scim_groups)
+		WHERE tenant_id = $3 AND id = $4
+	`, externalID, groupsJSON, tenantID, userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrScimExternalIDTaken
+	}
+	return err
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/pos/user-service/src/models"
+)
+
+var (
+	ErrTeamNotFound       = errors.New("team not found")
+	ErrTeamNameTaken      = errors.New("team name already exists")
+	ErrTeamMemberNotFound = errors.New("team member not found")
+)
+
+// TeamRepository stores tenants' teams, their members and the staff notifications routed to them
+// notification-service reads team_members and team_notification_routes when picking recipients.
+type TeamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+const teamColumns = `
+	t.id, t.tenant_id, t.name, t.description, t.created_by, t.created_at, t.updated_at,
+	COALESCE((
+		SELECT array_agg(tm.user_id::text ORDER BY tm.created_at, tm.user_id)
+		FROM team_members tm
+		JOIN users u ON u.id = tm.user_id AND u.status <> 'deleted'
+		WHERE tm.team_id = t.id
+	), '{}')
+`
+
+func scanTeam(row rowScanner) (*models.Team, error) {
+	team := &models.Team{}
+	var memberIDs pq.StringArray
+	err := row.Scan(
+		&team.ID,
+		&team.TenantID,
+		&team.Name,
+		&team.Description,
+		&team.CreatedBy,
+		&team.CreatedAt,
+		&team.UpdatedAt,
+		&memberIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	team.MemberIDs = memberIDs
+	team.NotificationRoutes = []models.TeamNotificationRoute{}
+	return team, nil
+}
+
+// List returns a tenant's teams ordered by name
+func (r *TeamRepository) List(ctx context.Context, tenantID string) ([]*models.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+teamColumns+`
+		FROM teams t
+		WHERE t.tenant_id = $1
+		ORDER BY LOWER(t.name)
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []*models.Team{}
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadRoutes(ctx, tenantID, teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, tenantID, teamID string) (*models.Team, error) {
+	team, err := scanTeam(r.db.QueryRowContext(ctx, `
+		SELECT `+teamColumns+`
+		FROM teams t
+		WHERE t.id = $1 AND t.tenant_id = $2
+	`, teamID, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadRoutes(ctx, tenantID, []*models.Team{team}); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// loadRoutes fills in the notification routes of the given teams
+func (r *TeamRepository) loadRoutes(ctx context.Context, tenantID string, teams []*models.Team) error {
+	if len(teams) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.Team, len(teams))
+	teamIDs := make([]string, len(teams))
+	for i, team := range teams {
+		byID[team.ID] = team
+		teamIDs[i] = team.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT team_id, event_type, delivery_types
+		FROM team_notification_routes
+		WHERE tenant_id = $1 AND team_id = ANY($2)
+		ORDER BY team_id, event_type
+	`, tenantID, pq.Array(teamIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var teamID string
+		var route models.TeamNotificationRoute
+		var deliveryTypes pq.StringArray
+		if err := rows.Scan(&teamID, &route.EventType, &deliveryTypes); err != nil {
+			return err
+		}
+		route.DeliveryTypes = deliveryTypes
+		if team, ok := byID[teamID]; ok {
+			team.NotificationRoutes = append(team.NotificationRoutes, route)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO teams (tenant_id, name, description, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`, team.TenantID, team.Name, team.Description, team.CreatedBy).
+		Scan(&team.ID, &team.CreatedAt, &team.UpdatedAt)
+	if err != nil {
+		return mapTeamError(err)
+	}
+
+	if err := replaceRoutes(ctx, tx, team); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update saves a team's name, description and notification routes; its members are left untouched
+func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE teams
+		SET name = $1, description = $2, updated_at = NOW()
+		WHERE id = $3 AND tenant_id = $4
+		RETURNING updated_at
+	`, team.Name, team.Description, team.ID, team.TenantID).Scan(&team.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrTeamNotFound
+	}
+	if err != nil {
+		return mapTeamError(err)
+	}
+
+	if err := replaceRoutes(ctx, tx, team); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceRoutes(ctx context.Context, tx *sql.Tx, team *models.Team) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM team_notification_routes WHERE team_id = $1
+	`, team.ID)
+	if err != nil {
+		return fmt.Errorf("failed to clear notification routes: %w", err)
+	}
+
+	for _, route := range team.NotificationRoutes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO team_notification_routes (team_id, tenant_id, event_type, delivery_types)
+			VALUES ($1, $2, $3, $4)
+		`, team.ID, team.TenantID, route.EventType, pq.Array(route.DeliveryTypes))
+		if err != nil {
+			return fmt.Errorf("failed to save %s notification route: %w", route.EventType, err)
+		}
+	}
+	return nil
+}
+
+// Delete removes a team along with its memberships and notification routes
+func (r *TeamRepository) Delete(ctx context.Context, tenantID, teamID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM teams WHERE id = $1 AND tenant_id = $2
+	`, teamID, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTeamNotFound
+	}
+	return nil
+}
+
+// SetMembers replaces the members of a team; every user must be a non-deleted user of the tenant
+func (r *TeamRepository) SetMembers(ctx context.Context, tenantID, teamID string, userIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM teams WHERE id = $1 AND tenant_id = $2 FOR UPDATE
+	`, teamID, tenantID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return ErrTeamNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var found int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM users
+		WHERE id = ANY($1::uuid[]) AND tenant_id = $2 AND status <> 'deleted'
+	`, pq.Array(userIDs), tenantID).Scan(&found)
+	if err != nil {
+		return err
+	}
+	if found != len(userIDs) {
+		return ErrTeamMemberNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM team_members WHERE team_id = $1
+	`, teamID)
+	if err != nil {
+		return fmt.Errorf("failed to clear team members: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO team_members (team_id, user_id, tenant_id)
+		SELECT $1, unnest($2::uuid[]), $3
+	`, teamID, pq.Array(userIDs), tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to save team members: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE teams SET updated_at = NOW() WHERE id = $1
+	`, teamID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func mapTeamError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrTeamNameTaken
+	}
+	return err
+}
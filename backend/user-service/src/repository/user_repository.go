@@ -179,7 +179,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 
 func (r *UserRepository) FindByEmail(ctx context.Context, tenantID, email string) (*models.User, error) {
 	query := `
-		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale, last_login_at, created_at, updated_at
+		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale, timezone, last_login_at, created_at, updated_at
 		FROM users
 		WHERE tenant_id = $1 AND email = $2 AND status != 'deleted'
 	`
@@ -203,6 +203,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, tenantID, email string
 		&encryptedFirstNameDB,
 		&encryptedLastNameDB,
 		&user.Locale,
+		&user.Timezone,
 		&user.LastLoginAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -235,7 +236,8 @@ func (r *UserRepository) FindByEmail(ctx context.Context, tenantID, email string
 
 func (r *UserRepository) FindByID(ctx context.Context, tenantID, id string) (*models.User, error) {
 	query := `
-		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale, last_login_at, created_at, updated_at
+		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale, timezone, last_login_at, created_at, updated_at,
+			avatar_storage_key, outlet_id
 		FROM users
 		WHERE tenant_id = $1 AND id = $2 AND status != 'deleted'
 	`
@@ -253,9 +255,12 @@ func (r *UserRepository) FindByID(ctx context.Context, tenantID, id string) (*mo
 		&encryptedFirstNameDB,
 		&encryptedLastNameDB,
 		&user.Locale,
+		&user.Timezone,
 		&user.LastLoginAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.AvatarStorageKey,
+		&user.OutletID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -284,6 +289,12 @@ func (r *UserRepository) FindByID(ctx context.Context, tenantID, id string) (*mo
 }
 
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	return r.UpdateBy(ctx, user, "")
+}
+
+// UpdateBy updates a user like Update and attributes the audit event to the staff member who
+// made the change; an empty actorID records a system change
+func (r *UserRepository) UpdateBy(ctx context.Context, user *models.User, actorID string) error {
 	// T099: Fetch before value for audit trail
 	var beforeValue map[string]interface{}
 	if r.auditPublisher != nil {
@@ -300,14 +311,16 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 				"last_name":  encLastName,
 				"role":       existingUser.Role,
 				"status":     existingUser.Status,
+				"outlet_id":  existingUser.OutletID,
 			}
 		}
 	}
 
 	query := `
 		UPDATE users
-		SET email = $1, role = $2, status = $3, first_name = $4, last_name = $5, locale = $6, last_login_at = $7, updated_at = $8
-		WHERE tenant_id = $9 AND id = $10
+		SET email = $1, role = $2, status = $3, first_name = $4, last_name = $5, locale = $6, timezone = $7, outlet_id = $8,
+			last_login_at = $9, updated_at = $10
+		WHERE tenant_id = $11 AND id = $12
 	`
 
 	user.UpdatedAt = time.Now()
@@ -333,6 +346,8 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		encryptedFirstName,
 		encryptedLastName,
 		user.Locale,
+		user.Timezone,
+		user.OutletID,
 		user.LastLoginAt,
 		user.UpdatedAt,
 		user.TenantID,
@@ -351,6 +366,7 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 			"last_name":  encryptedLastName,
 			"role":       user.Role,
 			"status":     user.Status,
+			"outlet_id":  user.OutletID,
 		}
 
 		auditEvent := &utils.AuditEvent{
@@ -362,9 +378,14 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 			BeforeValue:  beforeValue,
 			AfterValue:   afterValue,
 			Metadata: map[string]interface{}{
-				"locale": user.Locale,
+				"locale":   user.Locale,
+				"timezone": user.Timezone,
 			},
 		}
+		if actorID != "" {
+			auditEvent.ActorType = "user"
+			auditEvent.ActorID = &actorID
+		}
 
 		if err := r.auditPublisher.Publish(ctx, auditEvent); err != nil {
 			fmt.Printf("Failed to publish user update audit event: %v\n", err)
@@ -374,10 +395,170 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+// List returns a page of a tenant's users that are not deleted, oldest first, with their custom
+// role, and the total number matching; empty status, role, team and outlet match any
+func (r *UserRepository) List(ctx context.Context, tenantID, status, role, teamID, outletID string, offset, limit int) ([]*models.User, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM users u
+		WHERE u.tenant_id = $1 AND u.status != 'deleted'
+			AND ($2 = '' OR u.status = $2) AND ($3 = '' OR u.role = $3)
+			AND ($4 = '' OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = u.id AND tm.team_id::text = $4))
+			AND ($5 = '' OR u.outlet_id::text = $5)
+	`, tenantID, status, role, teamID, outletID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.tenant_id, u.email, u.role, u.status, u.first_name, u.last_name, u.locale, u.timezone,
+			u.last_login_at, u.created_at, u.updated_at, u.custom_role_id, tr.name, u.avatar_storage_key, u.outlet_id
+		FROM users u
+		LEFT JOIN tenant_roles tr ON tr.id = u.custom_role_id
+		WHERE u.tenant_id = $1 AND u.status != 'deleted'
+			AND ($2 = '' OR u.status = $2) AND ($3 = '' OR u.role = $3)
+			AND ($4 = '' OR EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = u.id AND tm.team_id::text = $4))
+			AND ($5 = '' OR u.outlet_id::text = $5)
+		ORDER BY u.created_at, u.id
+		OFFSET $6 LIMIT $7
+	`, tenantID, status, role, teamID, outletID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user := &models.User{}
+		var encryptedEmailDB string
+		var encryptedFirstNameDB, encryptedLastNameDB sql.NullString
+
+		err := rows.Scan(
+			&user.ID,
+			&user.TenantID,
+			&encryptedEmailDB,
+			&user.Role,
+			&user.Status,
+			&encryptedFirstNameDB,
+			&encryptedLastNameDB,
+			&user.Locale,
+			&user.Timezone,
+			&user.LastLoginAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.CustomRoleID,
+			&user.CustomRoleName,
+			&user.AvatarStorageKey,
+			&user.OutletID,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		user.Email, err = r.encryptor.DecryptWithContext(ctx, encryptedEmailDB, "user:email")
+		if err != nil {
+			return nil, 0, err
+		}
+		user.FirstName, err = r.decryptToStringPtrWithContext(ctx, encryptedFirstNameDB.String, "user:first_name")
+		if err != nil {
+			return nil, 0, err
+		}
+		user.LastName, err = r.decryptToStringPtrWithContext(ctx, encryptedLastNameDB.String, "user:last_name")
+		if err != nil {
+			return nil, 0, err
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// OutletExists reports whether an outlet belongs to the tenant
+func (r *UserRepository) OutletExists(ctx context.Context, tenantID, outletID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM outlets WHERE id = $1 AND tenant_id = $2)
+	`, outletID, tenantID).Scan(&exists)
+	return exists, err
+}
+
+// StaffSeatUsage returns the tenant's plan name and staff seat limit, nil when unlimited, and the seats
+// taken by staff who can sign in and by pending invitations
+// Tenants without a subscription are on the free plan.
+func (r *UserRepository) StaffSeatUsage(ctx context.Context, tenantID string) (string, *int, int, error) {
+	var planName string
+	var maxStaff sql.NullInt64
+	var used int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT p.name, p.max_staff,
+			(SELECT COUNT(*) FROM users
+				WHERE tenant_id = $1 AND status NOT IN ('suspended', 'deleted') AND deleted_at IS NULL)
+			+ (SELECT COUNT(*) FROM invitations
+				WHERE tenant_id = $1 AND status = 'pending' AND expires_at > NOW())
+		FROM subscription_plans p
+		WHERE p.code = COALESCE((SELECT plan_code FROM tenant_subscriptions WHERE tenant_id = $1), 'free')
+	`, tenantID).Scan(&planName, &maxStaff, &used)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	if !maxStaff.Valid {
+		return planName, nil, used, nil
+	}
+	max := int(maxStaff.Int64)
+	return planName, &max, used, nil
+}
+
+// FindStaffUpgradePlan returns the cheapest plan with at least needed staff seats, or "" when none has
+func (r *UserRepository) FindStaffUpgradePlan(ctx context.Context, needed int) (string, error) {
+	var code string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT code FROM subscription_plans
+		WHERE max_staff IS NULL OR max_staff >= $1
+		ORDER BY monthly_price, code
+		LIMIT 1
+	`, needed).Scan(&code)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return code, err
+}
+
+// SetAvatar stores the storage key of a user's avatar, or removes the avatar when the key is nil
+// Returns the key of the avatar it replaced so the caller can delete the old object.
+func (r *UserRepository) SetAvatar(ctx context.Context, tenantID, userID string, storageKey *string) (*string, error) {
+	var previous sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		WITH previous AS (
+			SELECT id, avatar_storage_key FROM users
+			WHERE id = $2 AND tenant_id = $3 AND status != 'deleted'
+			FOR UPDATE
+		)
+		UPDATE users u
+		SET avatar_storage_key = $1,
+			avatar_updated_at = CASE WHEN $1::VARCHAR IS NULL THEN NULL ELSE NOW() END,
+			updated_at = NOW()
+		FROM previous
+		WHERE u.id = previous.id
+		RETURNING previous.avatar_storage_key
+	`, storageKey, userID, tenantID).Scan(&previous)
+	if err != nil {
+		return nil, err
+	}
+	if !previous.Valid {
+		return nil, nil
+	}
+	return &previous.String, nil
+}
+
 // FindStaffWithOrderNotifications retrieves all active staff users who have opted in to receive order notifications
 func (r *UserRepository) FindStaffWithOrderNotifications(ctx context.Context, tenantID string) ([]*models.User, error) {
 	query := `
-		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale, last_login_at, created_at, updated_at
+		SELECT id, tenant_id, email, password_hash, role, status, first_name, last_name, locale, timezone, last_login_at, created_at, updated_at
 		FROM users
 		WHERE tenant_id = $1 
 		  AND status = 'active'
@@ -407,6 +588,7 @@ func (r *UserRepository) FindStaffWithOrderNotifications(ctx context.Context, te
 			&encryptedFirstNameDB,
 			&encryptedLastNameDB,
 			&user.Locale,
+			&user.Timezone,
 			&user.LastLoginAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
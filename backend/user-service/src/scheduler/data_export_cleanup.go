@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+
+	"github.com/pos/user-service/src/services"
+	"github.com/robfig/cron/v3"
+)
+
+// DataExportCleanupScheduler deletes personal data bundles once their download link has expired
+type DataExportCleanupScheduler struct {
+	cron              *cron.Cron
+	dataExportService *services.DataExportService
+}
+
+func NewDataExportCleanupScheduler(dataExportService *services.DataExportService) *DataExportCleanupScheduler {
+	return &DataExportCleanupScheduler{
+		cron:              cron.New(),
+		dataExportService: dataExportService,
+	}
+}
+
+// Start schedules the job at half past every hour
+func (s *DataExportCleanupScheduler) Start() error {
+	_, err := s.cron.AddFunc("30 * * * *", func() {
+		deleted, err := s.dataExportService.PurgeExpired(context.Background())
+		if err != nil {
+			log.Printf("ERROR: Data export cleanup job failed: %v", err)
+			return
+		}
+		if deleted > 0 {
+			log.Printf("Data export cleanup job: %d deleted", deleted)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	log.Printf("Data export cleanup scheduler started (runs hourly)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler
+func (s *DataExportCleanupScheduler) Stop() {
+	if s.cron != nil {
+		s.cron.Stop()
+		log.Printf("Data export cleanup scheduler stopped")
+	}
+}
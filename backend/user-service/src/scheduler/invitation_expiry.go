@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+
+	"github.com/pos/user-service/src/services"
+	"github.com/robfig/cron/v3"
+)
+
+// InvitationExpiryScheduler runs the invitation expiry and cleanup job
+type InvitationExpiryScheduler struct {
+	cron              *cron.Cron
+	invitationService *services.InvitationService
+}
+
+func NewInvitationExpiryScheduler(invitationService *services.InvitationService) *InvitationExpiryScheduler {
+	return &InvitationExpiryScheduler{
+		cron:              cron.New(),
+		invitationService: invitationService,
+	}
+}
+
+// Start schedules the job at the top of every hour
+func (s *InvitationExpiryScheduler) Start() error {
+	_, err := s.cron.AddFunc("0 * * * *", func() {
+		expired, deleted, err := s.invitationService.ExpireInvitations(context.Background())
+		if err != nil {
+			log.Printf("ERROR: Invitation expiry job failed: %v", err)
+			return
+		}
+		if expired > 0 || deleted > 0 {
+			log.Printf("Invitation expiry job: %d expired, %d deleted", expired, deleted)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	log.Printf("Invitation expiry scheduler started (runs hourly)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler
+func (s *InvitationExpiryScheduler) Stop() {
+	if s.cron != nil {
+		s.cron.Stop()
+		log.Printf("Invitation expiry scheduler stopped")
+	}
+}
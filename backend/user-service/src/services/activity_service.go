@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pos/user-service/src/models"
+)
+
+const (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 200
+)
+
+// AuditServiceError reports that the audit trail could not be read
+type AuditServiceError struct {
+	Err error
+}
+
+func (e *AuditServiceError) Error() string {
+	return fmt.Sprintf("audit service: %v", e.Err)
+}
+
+func (e *AuditServiceError) Unwrap() error {
+	return e.Err
+}
+
+// activityQuery is one audit-service search contributing to the activity feed
+type activityQuery struct {
+	activityType string
+	action       string
+	resourceType string
+}
+
+// activityQueries are the audit events a staff member's actions are recorded as
+// Logins come from auth-service, stock adjustments from product-service and status changes from order-service.
+var activityQueries = []activityQuery{
+	{activityType: models.ActivityLogin, action: "LOGIN"},
+	{activityType: models.ActivityStockAdjustment, action: "UPDATE", resourceType: "product_stock"},
+	{activityType: models.ActivityOrderStatus, action: "UPDATE", resourceType: "guest_order"},
+}
+
+// auditEvent is the part of an audit-service event user-service reads
+type auditEvent struct {
+	EventID      string                 `json:"event_id"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	BeforeValue  map[string]interface{} `json:"before_value"`
+	AfterValue   map[string]interface{} `json:"after_value"`
+	Metadata     map[string]interface{} `json:"metadata"`
+}
+
+// ActivityService builds a staff member's activity feed from audit-service
+type ActivityService struct {
+	userService *UserService
+	auditURL    string
+	httpClient  *http.Client
+}
+
+func NewActivityService(userService *UserService, auditServiceURL string) *ActivityService {
+	return &ActivityService{
+		userService: userService,
+		auditURL:    auditServiceURL,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Activity returns the user's most recent logins, stock adjustments and order status changes
+// Anyone may read their own activity; other users' follow CanManageUser. limit is capped and
+// defaults when not positive.
+func (s *ActivityService) Activity(ctx context.Context, tenantID, actorID, actorRole, userID string, limit int) ([]*models.UserActivity, error) {
+	user, err := s.userService.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID != userID {
+		if err := CanManageUser(actorRole, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit <= 0 {
+		limit = defaultActivityLimit
+	}
+	if limit > maxActivityLimit {
+		limit = maxActivityLimit
+	}
+
+	feeds := make([][]*models.UserActivity, 0, len(activityQueries))
+	for _, query := range activityQueries {
+		events, err := s.fetchEvents(ctx, tenantID, userID, query, limit)
+		if err != nil {
+			return nil, &AuditServiceError{Err: err}
+		}
+		feed := make([]*models.UserActivity, len(events))
+		for i, event := range events {
+			feed[i] = &models.UserActivity{
+				Type:         query.activityType,
+				Timestamp:    event.Timestamp,
+				ResourceType: event.ResourceType,
+				ResourceID:   event.ResourceID,
+				Before:       event.BeforeValue,
+				After:        event.AfterValue,
+				Details:      event.Metadata,
+			}
+		}
+		feeds = append(feeds, feed)
+	}
+	return MergeActivities(feeds, limit), nil
+}
+
+// MergeActivities combines activity feeds newest first and keeps at most limit entries
+func MergeActivities(feeds [][]*models.UserActivity, limit int) []*models.UserActivity {
+	merged := make([]*models.UserActivity, 0)
+	for _, feed := range feeds {
+		merged = append(merged, feed...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.After(merged[j].Timestamp)
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+// fetchEvents reads the user's most recent audit events matching the query
+func (s *ActivityService) fetchEvents(ctx context.Context, tenantID, userID string, query activityQuery, limit int) ([]auditEvent, error) {
+	params := url.Values{}
+	params.Set("tenant_id", tenantID)
+	params.Set("actor_id", userID)
+	params.Set("action", query.action)
+	if query.resourceType != "" {
+		params.Set("resource_type", query.resourceType)
+	}
+	params.Set("limit", strconv.Itoa(limit))
+
+	events, _, err := listAuditEvents(ctx, s.httpClient, s.auditURL, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s events: %w", query.activityType, err)
+	}
+	return events, nil
+}
+
+// listAuditEvents runs one audit-service search and returns a page of events with the total match count
+func listAuditEvents(ctx context.Context, client *http.Client, auditURL string, params url.Values) ([]auditEvent, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, auditURL+"/api/v1/audit-events?"+params.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Events     []auditEvent `json:"events"`
+		Pagination struct {
+			Total int `json:"total"`
+		} `json:"pagination"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode events: %w", err)
+	}
+	return body.Events, body.Pagination.Total, nil
+}
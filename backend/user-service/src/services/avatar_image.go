@@ -0,0 +1,71 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/webp"
+)
+
+const (
+	// MaxAvatarSizeBytes bounds an uploaded avatar file
+	MaxAvatarSizeBytes = 5 * 1024 * 1024
+	// maxAvatarSourcePixels bounds the longest edge of an uploaded avatar
+	maxAvatarSourcePixels = 4096
+	// AvatarSizePixels is the edge of the square JPEG avatars are stored as
+	AvatarSizePixels = 512
+)
+
+var (
+	ErrAvatarTooLarge    = errors.New("avatar file is too large")
+	ErrAvatarInvalid     = errors.New("avatar must be a JPEG, PNG, GIF or WebP image")
+	ErrAvatarDimensions  = errors.New("avatar dimensions are not supported")
+	ErrAvatarUnavailable = errors.New("avatar storage is not configured")
+)
+
+// ProcessAvatar validates an uploaded image like product photos are validated, then crops it to
+// a centred square of AvatarSizePixels and re-encodes it as JPEG
+// Re-encoding also drops metadata such as the GPS position of phone photos.
+func ProcessAvatar(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, MaxAvatarSizeBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if len(data) > MaxAvatarSizeBytes {
+		return nil, ErrAvatarTooLarge
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		config, err = webp.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrAvatarInvalid
+		}
+	}
+	if config.Width == 0 || config.Height == 0 ||
+		config.Width > maxAvatarSourcePixels || config.Height > maxAvatarSourcePixels {
+		return nil, ErrAvatarDimensions
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		img, err = webp.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrAvatarInvalid
+		}
+	}
+
+	avatar := imaging.Fill(img, AvatarSizePixels, AvatarSizePixels, imaging.Center, imaging.Lanczos)
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, avatar, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %w", err)
+	}
+	return buf.Bytes(), nil
+}
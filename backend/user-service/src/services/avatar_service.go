@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+)
+
+// AvatarService uploads and removes the profile pictures of users
+// storage is nil when no object storage is configured; avatars then cannot be uploaded and
+// responses carry no avatar URL.
+type AvatarService struct {
+	userRepo       *repository.UserRepository
+	storage        *AvatarStorage
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewAvatarService(userRepo *repository.UserRepository, storage *AvatarStorage, auditPublisher utils.AuditPublisherInterface) *AvatarService {
+	return &AvatarService{
+		userRepo:       userRepo,
+		storage:        storage,
+		auditPublisher: auditPublisher,
+	}
+}
+
+// AvatarStorageKey returns where an avatar is stored
+// Format: avatars/{tenant_id}/{user_id}/{avatar_id}.jpg
+func AvatarStorageKey(tenantID, userID, avatarID string) string {
+	return fmt.Sprintf("avatars/%s/%s/%s.jpg", tenantID, userID, avatarID)
+}
+
+// Upload replaces the user's avatar and returns a presigned URL to it
+// The new image is stored before the user is updated; the replaced one is deleted afterwards.
+func (s *AvatarService) Upload(ctx context.Context, tenantID, userID string, reader io.Reader) (string, error) {
+	if s.storage == nil {
+		return "", ErrAvatarUnavailable
+	}
+
+	avatar, err := ProcessAvatar(reader)
+	if err != nil {
+		return "", err
+	}
+
+	storageKey := AvatarStorageKey(tenantID, userID, uuid.NewString())
+	if err := s.storage.Upload(ctx, storageKey, bytes.NewReader(avatar), int64(len(avatar)), "image/jpeg"); err != nil {
+		return "", err
+	}
+
+	previous, err := s.userRepo.SetAvatar(ctx, tenantID, userID, &storageKey)
+	if err != nil {
+		s.removeObject(ctx, storageKey)
+		if err == sql.ErrNoRows {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to save avatar: %w", err)
+	}
+	if previous != nil {
+		s.removeObject(ctx, *previous)
+	}
+
+	s.publishAudit(ctx, tenantID, userID, "UPDATE", storageKey)
+	return s.URL(ctx, &storageKey), nil
+}
+
+// Delete removes the user's avatar; removing a missing avatar is not an error
+func (s *AvatarService) Delete(ctx context.Context, tenantID, userID string) error {
+	previous, err := s.userRepo.SetAvatar(ctx, tenantID, userID, nil)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove avatar: %w", err)
+	}
+	if previous == nil {
+		return nil
+	}
+
+	if s.storage != nil {
+		s.removeObject(ctx, *previous)
+	}
+	s.publishAudit(ctx, tenantID, userID, "DELETE", *previous)
+	return nil
+}
+
+// URL returns a presigned URL to an avatar, or an empty string when there is none or it cannot be signed
+func (s *AvatarService) URL(ctx context.Context, storageKey *string) string {
+	if s == nil || s.storage == nil || storageKey == nil {
+		return ""
+	}
+	url, err := s.storage.PresignedURL(ctx, *storageKey)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return ""
+	}
+	return url
+}
+
+// Response returns the user's API representation with its avatar URL
+func (s *AvatarService) Response(ctx context.Context, user *models.User) *models.UserResponse {
+	response := user.ToResponse()
+	response.AvatarURL = s.URL(ctx, user.AvatarStorageKey)
+	return response
+}
+
+func (s *AvatarService) removeObject(ctx context.Context, storageKey string) {
+	if err := s.storage.Delete(ctx, storageKey); err != nil {
+		fmt.Printf("Warning: failed to delete avatar %s: %v\n", storageKey, err)
+	}
+}
+
+func (s *AvatarService) publishAudit(ctx context.Context, tenantID, userID, action, storageKey string) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: "user_avatar",
+		ResourceID:   userID,
+		Metadata: map[string]interface{}{
+			"storage_key": storageKey,
+		},
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish avatar audit event: %v\n", err)
+	}
+}
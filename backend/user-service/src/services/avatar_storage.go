@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pos/user-service/src/utils"
+)
+
+// AvatarStorage keeps user avatars in the object storage shared with product-service
+type AvatarStorage struct {
+	client *minio.Client
+	bucket string
+	urlTTL time.Duration
+}
+
+// NewAvatarStorage creates a storage client for avatars
+func NewAvatarStorage(cfg utils.StorageConfig) (*AvatarStorage, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+
+	return &AvatarStorage{
+		client: client,
+		bucket: cfg.BucketName,
+		urlTTL: cfg.URLTTL,
+	}, nil
+}
+
+// Upload stores an avatar under its storage key
+func (s *AvatarStorage) Upload(ctx context.Context, storageKey string, reader io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, storageKey, reader, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload avatar: %w", err)
+	}
+	return nil
+}
+
+// PresignedURL returns a short-lived URL to view an avatar
+func (s *AvatarStorage) PresignedURL(ctx context.Context, storageKey string) (string, error) {
+	url, err := s.client.PresignedGetObject(ctx, s.bucket, storageKey, s.urlTTL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign avatar URL: %w", err)
+	}
+	return url.String(), nil
+}
+
+// Delete removes an avatar
+func (s *AvatarStorage) Delete(ctx context.Context, storageKey string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, storageKey, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete avatar: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object whose key starts with prefix and returns how many were removed
+func (s *AvatarStorage) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var removed int64
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return removed, fmt.Errorf("failed to list %s: %w", prefix, object.Err)
+		}
+		if err := s.Delete(ctx, object.Key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
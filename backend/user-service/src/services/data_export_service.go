@@ -0,0 +1,341 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/events"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/queue"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+)
+
+const (
+	// dataExportTimeout bounds assembling one bundle
+	dataExportTimeout = 5 * time.Minute
+	// auditPageSize is the largest page audit-service returns
+	auditPageSize = 1000
+	// maxAuditReferences bounds the audit trail references of one bundle
+	maxAuditReferences = 50000
+)
+
+var (
+	ErrDataExportInProgress = errors.New("a data export is already being prepared")
+	ErrDataExportNotFound   = errors.New("data export not found")
+	ErrDataExportFormat     = errors.New("format must be json or zip")
+	ErrDataExportLink       = errors.New("download link is invalid or has expired")
+)
+
+// DataExportService assembles a user's personal data (UU PDP right of access) into a downloadable bundle
+// The bundle is built in the background; the user is emailed a signed link once it is ready and the link
+// stops working after the link TTL, when the hourly cleanup deletes the bundle.
+type DataExportService struct {
+	exportRepo     *repository.DataExportRepository
+	userRepo       *repository.UserRepository
+	eventProducer  *queue.KafkaProducer
+	auditPublisher utils.AuditPublisherInterface
+	auditURL       string
+	httpClient     *http.Client
+	signingKey     []byte
+	linkTTL        time.Duration
+}
+
+func NewDataExportService(
+	db *sql.DB,
+	userRepo *repository.UserRepository,
+	eventProducer *queue.KafkaProducer,
+	auditPublisher utils.AuditPublisherInterface,
+	auditServiceURL, signingKey string,
+	linkTTLHours int,
+) (*DataExportService, error) {
+	exportRepo, err := repository.NewDataExportRepositoryWithVault(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data export repository: %w", err)
+	}
+	return &DataExportService{
+		exportRepo:     exportRepo,
+		userRepo:       userRepo,
+		eventProducer:  eventProducer,
+		auditPublisher: auditPublisher,
+		auditURL:       auditServiceURL,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		signingKey:     []byte(signingKey),
+		linkTTL:        time.Duration(linkTTLHours) * time.Hour,
+	}, nil
+}
+
+// RequestExport starts assembling the user's personal data; only one export is prepared at a time
+func (s *DataExportService) RequestExport(ctx context.Context, tenantID, userID, format string) (*models.DataExport, error) {
+	if format == "" {
+		format = models.DataExportFormatJSON
+	}
+	if format != models.DataExportFormatJSON && format != models.DataExportFormatZIP {
+		return nil, ErrDataExportFormat
+	}
+
+	pending, err := s.exportRepo.FindPending(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending exports: %w", err)
+	}
+	if pending != nil && time.Since(pending.CreatedAt) < dataExportTimeout {
+		return nil, ErrDataExportInProgress
+	}
+
+	export, err := s.exportRepo.Create(ctx, tenantID, userID, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data export: %w", err)
+	}
+	s.publishAudit(ctx, export, "EXPORT", nil)
+
+	go s.assemble(export)
+	return export, nil
+}
+
+// GetExport returns one of the user's exports, with its download link while it can be downloaded
+func (s *DataExportService) GetExport(ctx context.Context, tenantID, userID, exportID string) (*models.DataExport, error) {
+	if _, err := uuid.Parse(exportID); err != nil {
+		return nil, ErrDataExportNotFound
+	}
+	export, err := s.exportRepo.FindByID(ctx, tenantID, userID, exportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find data export: %w", err)
+	}
+	if export == nil {
+		return nil, ErrDataExportNotFound
+	}
+	if export.Status == models.DataExportReady && export.ExpiresAt != nil && export.ExpiresAt.After(time.Now()) {
+		export.DownloadURL = s.downloadPath(export)
+	}
+	return export, nil
+}
+
+// Download returns the bundle behind a signed link; the signature stands in for a session
+func (s *DataExportService) Download(ctx context.Context, exportID, expires, signature, ipAddress string) (*models.DataExport, []byte, error) {
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !VerifyDataExportSignature(s.signingKey, exportID, expiresUnix, signature, time.Now()) {
+		return nil, nil, ErrDataExportLink
+	}
+
+	export, bundle, err := s.exportRepo.FindBundle(ctx, exportID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read data export: %w", err)
+	}
+	if export == nil {
+		return nil, nil, ErrDataExportLink
+	}
+	s.publishAudit(ctx, export, "ACCESS", &ipAddress)
+	return export, bundle, nil
+}
+
+// PurgeExpired deletes bundles whose download link has expired
+func (s *DataExportService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.exportRepo.DeleteExpired(ctx)
+}
+
+// SignDataExport signs an export ID and link expiry with HMAC-SHA256
+func SignDataExport(key []byte, exportID string, expires int64) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s.%d", exportID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDataExportSignature reports whether a download link is authentic and not yet expired at now
+func VerifyDataExportSignature(key []byte, exportID string, expires int64, signature string, now time.Time) bool {
+	if now.Unix() >= expires {
+		return false
+	}
+	expected := SignDataExport(key, exportID, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// downloadPath is the signed download link of a ready export, relative to the public site
+func (s *DataExportService) downloadPath(export *models.DataExport) string {
+	expires := export.ExpiresAt.Unix()
+	params := url.Values{}
+	params.Set("expires", strconv.FormatInt(expires, 10))
+	params.Set("signature", SignDataExport(s.signingKey, export.ID, expires))
+	return fmt.Sprintf("/api/v1/data-exports/%s/download?%s", export.ID, params.Encode())
+}
+
+// assemble builds and stores the bundle, then emails the user the download link
+func (s *DataExportService) assemble(export *models.DataExport) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataExportTimeout)
+	defer cancel()
+
+	user, bundle, err := s.buildBundle(ctx, export)
+	if err == nil {
+		err = s.exportRepo.MarkReady(ctx, export, bundle, time.Now().Add(s.linkTTL))
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to assemble data export %s: %v\n", export.ID, err)
+		// The assembly context may be what ran out
+		if markErr := s.exportRepo.MarkFailed(context.Background(), export.ID, err.Error()); markErr != nil {
+			fmt.Printf("Warning: failed to mark data export %s as failed: %v\n", export.ID, markErr)
+		}
+		return
+	}
+	s.publishReady(ctx, export, user)
+}
+
+// buildBundle collects the user's personal data and encodes it in the export's format
+func (s *DataExportService) buildBundle(ctx context.Context, export *models.DataExport) (*models.User, []byte, error) {
+	user, err := s.userRepo.FindByID(ctx, export.TenantID, export.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	if user == nil {
+		return nil, nil, ErrUserNotFound
+	}
+
+	sessions, err := s.exportRepo.ListSessions(ctx, export.TenantID, export.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	notifications, err := s.exportRepo.ListNotifications(ctx, export.TenantID, export.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read notifications: %w", err)
+	}
+	auditTrail, err := s.auditReferences(ctx, export.TenantID, export.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read audit trail: %w", err)
+	}
+
+	data, err := json.MarshalIndent(&models.PersonalDataBundle{
+		GeneratedAt:   time.Now(),
+		Profile:       user.ToResponse(),
+		Sessions:      sessions,
+		Notifications: notifications,
+		AuditTrail:    auditTrail,
+	}, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if export.Format != models.DataExportFormatZIP {
+		return user, data, nil
+	}
+	zipped, err := ZipDataExport(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, zipped, nil
+}
+
+// ZipDataExport wraps a JSON bundle in a ZIP archive as personal-data.json
+func ZipDataExport(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	file, err := archive.Create("personal-data.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(data); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// auditReferences pages through the audit events the user is the actor of
+func (s *DataExportService) auditReferences(ctx context.Context, tenantID, userID string) ([]*models.AuditEventReference, error) {
+	references := make([]*models.AuditEventReference, 0)
+	for offset := 0; offset < maxAuditReferences; offset += auditPageSize {
+		params := url.Values{}
+		params.Set("tenant_id", tenantID)
+		params.Set("actor_id", userID)
+		params.Set("limit", strconv.Itoa(auditPageSize))
+		params.Set("offset", strconv.Itoa(offset))
+
+		events, total, err := listAuditEvents(ctx, s.httpClient, s.auditURL, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			references = append(references, &models.AuditEventReference{
+				EventID:      event.EventID,
+				Timestamp:    event.Timestamp,
+				Action:       event.Action,
+				ResourceType: event.ResourceType,
+				ResourceID:   event.ResourceID,
+			})
+		}
+		if len(events) < auditPageSize || offset+len(events) >= total {
+			break
+		}
+	}
+	return references, nil
+}
+
+func (s *DataExportService) publishReady(ctx context.Context, export *models.DataExport, user *models.User) {
+	if s.eventProducer == nil {
+		return
+	}
+
+	name := ""
+	if user.FirstName != nil {
+		name = *user.FirstName
+	}
+	// The email is written in the user's language and shows the expiry on their clock
+	timezone := ""
+	if user.Timezone != nil {
+		timezone = *user.Timezone
+	}
+	event := &events.NotificationEvent{
+		EventID:   uuid.New().String(),
+		EventType: "data_export.ready",
+		TenantID:  export.TenantID,
+		UserID:    export.UserID,
+		Data: map[string]interface{}{
+			"export_id":     export.ID,
+			"email":         user.Email,
+			"name":          name,
+			"format":        export.Format,
+			"download_path": s.downloadPath(export),
+			"expires_at":    export.ExpiresAt.Format(time.RFC3339),
+			"locale":        user.Locale,
+			"timezone":      timezone,
+		},
+		Timestamp: time.Now(),
+	}
+	if err := s.eventProducer.Publish(ctx, export.ID, event); err != nil {
+		fmt.Printf("Warning: failed to publish data export ready event: %v\n", err)
+	}
+}
+
+func (s *DataExportService) publishAudit(ctx context.Context, export *models.DataExport, action string, ipAddress *string) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     export.TenantID,
+		ActorType:    "user",
+		ActorID:      &export.UserID,
+		Action:       action,
+		ResourceType: "user_data_export",
+		ResourceID:   export.ID,
+		IPAddress:    ipAddress,
+		Metadata: map[string]interface{}{
+			"user_id": export.UserID,
+			"format":  export.Format,
+		},
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish data export audit event: %v\n", err)
+	}
+}
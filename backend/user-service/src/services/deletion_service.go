@@ -184,6 +184,22 @@ func (s *UserDeletionService) HardDelete(ctx context.Context, tenantID string, u
 	return nil
 }
 
+// DeleteTenantUsers permanently deletes every user of a tenant being deleted, whatever their status,
+// and returns how many were deleted. Their sessions and team memberships go with them; unlike
+// HardDelete, the audit trail is left as it is.
+func (s *UserDeletionService) DeleteTenantUsers(ctx context.Context, tenantID string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM users WHERE tenant_id = $1`, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tenant users: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted users: %w", err)
+	}
+	return deleted, nil
+}
+
 // GetUserDeletionEligible returns users eligible for hard deletion (deleted > 90 days ago)
 // Used by cleanup job to enforce retention policy
 func (s *UserDeletionService) GetUserDeletionEligible(ctx context.Context) ([]models.User, error) {
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"github.com/pos/user-service/src/models"
+)
+
+// MaxBulkInvitations bounds the rows of one bulk invitation CSV
+const MaxBulkInvitations = 500
+
+// BulkInvitationFileError explains why a bulk invitation CSV could not be read at all
+type BulkInvitationFileError struct {
+	Message string
+}
+
+func (e *BulkInvitationFileError) Error() string {
+	return e.Message
+}
+
+// ValidInvitationRole reports whether staff can be invited with the role
+func ValidInvitationRole(role string) bool {
+	switch role {
+	case "admin", "manager", "cashier":
+		return true
+	}
+	return false
+}
+
+// ParseInvitationCSV reads email,role rows; a first row naming the columns is skipped
+// Blank lines are ignored. Line numbers count from 1 and include the header.
+func ParseInvitationCSV(r io.Reader) ([]models.BulkInvitationRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []models.BulkInvitationRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &BulkInvitationFileError{Message: fmt.Sprintf("CSV could not be read: %v", err)}
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(rows) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "email") {
+			continue
+		}
+		if len(record) < 2 {
+			record = append(record, "")
+		}
+
+		rows = append(rows, models.BulkInvitationRow{
+			Line:  line,
+			Email: strings.ToLower(strings.TrimSpace(record[0])),
+			Role:  strings.ToLower(strings.TrimSpace(record[1])),
+		})
+		if len(rows) > MaxBulkInvitations {
+			return nil, &BulkInvitationFileError{Message: fmt.Sprintf("CSV has more than %d rows", MaxBulkInvitations)}
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, &BulkInvitationFileError{Message: "CSV has no invitation rows"}
+	}
+	return rows, nil
+}
+
+// validateInvitationRow returns why a row cannot be invited, or an empty string
+func validateInvitationRow(row models.BulkInvitationRow) string {
+	if row.Email == "" {
+		return "Email is required"
+	}
+	address, err := mail.ParseAddress(row.Email)
+	if err != nil || address.Address != row.Email {
+		return "Email is not a valid address"
+	}
+	if row.Role == "" {
+		return "Role is required"
+	}
+	if !ValidInvitationRole(row.Role) {
+		return "Invalid role. Must be one of: admin, manager, cashier"
+	}
+	return ""
+}
+
+// CreateBulk invites every valid row of a CSV and reports the outcome of each
+// Rows repeating an earlier address, or naming an existing user or pending invitation, are skipped.
+// Each invitation goes through Create, so invitees get the same invitation email as a single invite.
+func (s *InvitationService) CreateBulk(ctx context.Context, tenantID, invitedByID string, rows []models.BulkInvitationRow) *models.BulkInvitationResponse {
+	response := &models.BulkInvitationResponse{Results: make([]*models.BulkInvitationResult, 0, len(rows))}
+	seen := make(map[string]bool, len(rows))
+
+	for _, row := range rows {
+		result := &models.BulkInvitationResult{Line: row.Line, Email: row.Email, Role: row.Role}
+		response.Results = append(response.Results, result)
+
+		if reason := validateInvitationRow(row); reason != "" {
+			result.Status, result.Reason = models.BulkInvitationInvalid, reason
+			response.Invalid++
+			continue
+		}
+		if seen[row.Email] {
+			result.Status, result.Reason = models.BulkInvitationSkipped, "Email appears earlier in the file"
+			response.Skipped++
+			continue
+		}
+		seen[row.Email] = true
+
+		invitation, err := s.Create(ctx, tenantID, row.Email, row.Role, invitedByID)
+		switch {
+		case err == nil:
+			result.Status, result.InvitationID = models.BulkInvitationInvited, invitation.ID
+			response.Invited++
+		case err == ErrEmailAlreadyExists:
+			result.Status, result.Reason = models.BulkInvitationSkipped, "Email is already registered"
+			response.Skipped++
+		case err == ErrEmailDeactivated:
+			result.Status, result.Reason = models.BulkInvitationSkipped, "Email belongs to a deactivated user"
+			response.Skipped++
+		case err == ErrEmailAlreadyInvited:
+			result.Status, result.Reason = models.BulkInvitationSkipped, "Email already has a pending invitation"
+			response.Skipped++
+		default:
+			if limitErr, ok := err.(*PlanLimitError); ok {
+				result.Status, result.Reason = models.BulkInvitationSkipped, "Staff seat limit reached: "+limitErr.Error()
+				response.Skipped++
+				continue
+			}
+			fmt.Printf("Warning: failed to create bulk invitation on line %d: %v\n", row.Line, err)
+			result.Status, result.Reason = models.BulkInvitationFailed, "Failed to create invitation"
+			response.Failed++
+		}
+	}
+	return response
+}
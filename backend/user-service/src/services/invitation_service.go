@@ -24,16 +24,22 @@ var (
 	ErrInvitationInvalid   = errors.New("invitation invalid")
 	ErrEmailAlreadyInvited = errors.New("email already invited")
 	ErrEmailAlreadyExists  = errors.New("email already registered")
+	ErrEmailDeactivated    = errors.New("email belongs to a deactivated user")
 )
 
+// InvitationService invites staff to a tenant
+// Invitations expire after ttl. Opening an expired link sends the invitee a fresh one, and the expiry job
+// deletes expired, revoked and cancelled invitations once retention has passed.
 type InvitationService struct {
 	invitationRepo *repository.InvitationRepository
 	userRepo       *repository.UserRepository
 	db             *sql.DB
 	eventProducer  *queue.KafkaProducer
+	ttl            time.Duration
+	retention      time.Duration
 }
 
-func NewInvitationService(db *sql.DB, eventProducer *queue.KafkaProducer, auditPublisher utils.AuditPublisherInterface) (*InvitationService, error) {
+func NewInvitationService(db *sql.DB, eventProducer *queue.KafkaProducer, auditPublisher utils.AuditPublisherInterface, ttlHours, retentionDays int) (*InvitationService, error) {
 	userRepo, err := repository.NewUserRepositoryWithVault(db, auditPublisher)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user repository: %w", err)
@@ -49,6 +55,8 @@ func NewInvitationService(db *sql.DB, eventProducer *queue.KafkaProducer, auditP
 		userRepo:       userRepo,
 		db:             db,
 		eventProducer:  eventProducer,
+		ttl:            time.Duration(ttlHours) * time.Hour,
+		retention:      time.Duration(retentionDays) * 24 * time.Hour,
 	}, nil
 }
 
@@ -59,7 +67,7 @@ func (s *InvitationService) Create(ctx context.Context, tenantID, email, role, i
 		return nil, fmt.Errorf("failed to check existing user: %w", err)
 	}
 	if existingUser != nil {
-		return nil, ErrEmailAlreadyExists
+		return nil, existingUserError(existingUser)
 	}
 
 	// Check if there's already a pending invitation
@@ -68,7 +76,18 @@ func (s *InvitationService) Create(ctx context.Context, tenantID, email, role, i
 		return nil, fmt.Errorf("failed to check existing invitation: %w", err)
 	}
 	if existingInvitation != nil {
-		return nil, ErrEmailAlreadyInvited
+		if existingInvitation.ExpiresAt.After(time.Now()) {
+			return nil, ErrEmailAlreadyInvited
+		}
+		// An expired invitation the expiry job has not reached yet gives way to the new one
+		if err := s.invitationRepo.UpdateStatus(ctx, existingInvitation.ID, models.InvitationExpired); err != nil {
+			return nil, fmt.Errorf("failed to expire previous invitation: %w", err)
+		}
+	}
+
+	// A pending invitation holds a seat of the tenant's plan
+	if err := checkStaffSeat(ctx, s.userRepo, tenantID); err != nil {
+		return nil, err
 	}
 
 	// Generate secure token
@@ -86,7 +105,7 @@ func (s *InvitationService) Create(ctx context.Context, tenantID, email, role, i
 		Token:     token,
 		Status:    models.InvitationPending,
 		InvitedBy: invitedByID,
-		ExpiresAt: now.Add(7 * 24 * time.Hour), // 7 days expiration
+		ExpiresAt: now.Add(s.ttl),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -96,48 +115,7 @@ func (s *InvitationService) Create(ctx context.Context, tenantID, email, role, i
 	}
 
 	// Publish invitation event to Kafka for notification service
-	if s.eventProducer != nil {
-		// Fetch inviter info
-		inviter, err := s.userRepo.FindByID(ctx, tenantID, invitedByID)
-		inviterName := "Team Member"
-		if err == nil && inviter != nil {
-			if inviter.FirstName != nil && inviter.LastName != nil {
-				inviterName = fmt.Sprintf("%s %s", *inviter.FirstName, *inviter.LastName)
-			}
-		}
-
-		// Fetch tenant info
-		var tenantName string
-		err = s.db.QueryRowContext(ctx, "SELECT business_name FROM tenants WHERE id = $1", tenantID).Scan(&tenantName)
-		if err != nil {
-			tenantName = "the team"
-		}
-
-		event := &events.NotificationEvent{
-			EventID:   uuid.New().String(),
-			EventType: "invitation.created",
-			TenantID:  tenantID,
-			UserID:    invitedByID,
-			Data: map[string]interface{}{
-				"invitation_id":    invitation.ID,
-				"email":            email,
-				"role":             role,
-				"token":            token,
-				"invitation_token": token,
-				"expires_at":       invitation.ExpiresAt.Format(time.RFC3339),
-				"invited_by":       invitedByID,
-				"inviter_name":     inviterName,
-				"tenant_name":      tenantName,
-			},
-			Timestamp: now,
-		}
-
-		// Send event to Kafka (non-blocking, log error if failed)
-		if err := s.eventProducer.Publish(ctx, invitation.ID, event); err != nil {
-			// Log the error but don't fail the invitation creation
-			fmt.Printf("Warning: failed to publish invitation event: %v\n", err)
-		}
-	}
+	s.publishInvitation(ctx, invitation, invitedByID)
 
 	return invitation, nil
 }
@@ -170,22 +148,22 @@ func (s *InvitationService) Accept(ctx context.Context, token, firstName, lastNa
 		return nil, ErrInvitationNotFound
 	}
 
-	// Validate invitation
-	if invitation.Status != models.InvitationPending {
-		return nil, ErrInvitationInvalid
-	}
-	if invitation.ExpiresAt.Before(time.Now()) {
-		s.invitationRepo.UpdateStatus(ctx, invitation.ID, models.InvitationExpired)
-		return nil, ErrInvitationExpired
-	}
-
 	// Check if email is already registered in this tenant
 	existingUser, err := s.userRepo.FindByEmail(ctx, invitation.TenantID, invitation.Email)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to check existing user: %w", err)
 	}
 	if existingUser != nil {
-		return nil, ErrEmailAlreadyExists
+		return nil, existingUserError(existingUser)
+	}
+
+	// An expired link is answered by sending the invitee a new one
+	if invitation.Status == models.InvitationExpired || invitation.ExpiresAt.Before(time.Now()) {
+		// A newer pending invitation means the invitee already has a working link
+		if err := s.reissue(ctx, invitation, invitation.InvitedBy); err != nil && err != ErrEmailAlreadyInvited {
+			return nil, err
+		}
+		return nil, ErrInvitationExpired
 	}
 
 	// Hash password
@@ -269,69 +247,116 @@ func (s *InvitationService) Resend(ctx context.Context, tenantID, invitationID,
 		return nil, ErrInvitationNotFound
 	}
 
-	// Can only resend pending invitations
-	if invitation.Status != models.InvitationPending {
+	// Can only resend invitations that were not accepted, revoked or cancelled
+	if invitation.Status != models.InvitationPending && invitation.Status != models.InvitationExpired {
 		return nil, errors.New("can only resend pending invitations")
 	}
 
-	// Generate new token and extend expiration
+	if err := s.reissue(ctx, invitation, resendByID); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// reissue gives an invitation a new token and expiry and sends it again
+// An expired invitation is not revived while a newer one for the address is pending.
+func (s *InvitationService) reissue(ctx context.Context, invitation *models.Invitation, sentByID string) error {
+	if invitation.Status == models.InvitationExpired {
+		pending, err := s.invitationRepo.FindByEmail(ctx, invitation.TenantID, invitation.Email)
+		if err != nil {
+			return fmt.Errorf("failed to check pending invitation: %w", err)
+		}
+		if pending != nil {
+			return ErrEmailAlreadyInvited
+		}
+	}
+
 	token, err := generateSecureToken(32)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	now := time.Now()
 	invitation.Token = token
-	invitation.ExpiresAt = now.Add(7 * 24 * time.Hour) // 7 days expiration
+	invitation.Status = models.InvitationPending
+	invitation.ExpiresAt = now.Add(s.ttl)
 	invitation.UpdatedAt = now
 
 	if err := s.invitationRepo.UpdateToken(ctx, invitation.ID, token, invitation.ExpiresAt); err != nil {
-		return nil, fmt.Errorf("failed to update invitation token: %w", err)
+		return fmt.Errorf("failed to update invitation token: %w", err)
 	}
 
-	// Publish resend event to Kafka
-	if s.eventProducer != nil {
-		// Fetch inviter info
-		inviter, err := s.userRepo.FindByID(ctx, tenantID, resendByID)
-		inviterName := "Team Member"
-		if err == nil && inviter != nil {
-			if inviter.FirstName != nil && inviter.LastName != nil {
-				inviterName = fmt.Sprintf("%s %s", *inviter.FirstName, *inviter.LastName)
-			}
-		}
+	s.publishInvitation(ctx, invitation, sentByID)
+	return nil
+}
 
-		// Fetch tenant info
-		var tenantName string
-		err = s.db.QueryRowContext(ctx, "SELECT business_name FROM tenants WHERE id = $1", tenantID).Scan(&tenantName)
-		if err != nil {
-			tenantName = "the team"
-		}
+// publishInvitation asks the notification service to email the invitation link
+// Publishing is best effort: the invitation is saved and can be resent.
+func (s *InvitationService) publishInvitation(ctx context.Context, invitation *models.Invitation, sentByID string) {
+	if s.eventProducer == nil {
+		return
+	}
 
-		event := &events.NotificationEvent{
-			EventID:   uuid.New().String(),
-			EventType: "invitation.created", // Reuse same template
-			TenantID:  tenantID,
-			UserID:    resendByID,
-			Data: map[string]interface{}{
-				"invitation_id":    invitation.ID,
-				"email":            invitation.Email,
-				"role":             invitation.Role,
-				"token":            token,
-				"invitation_token": token,
-				"expires_at":       invitation.ExpiresAt.Format(time.RFC3339),
-				"invited_by":       resendByID,
-				"inviter_name":     inviterName,
-				"tenant_name":      tenantName,
-			},
-			Timestamp: now,
+	// Fetch inviter info
+	inviter, err := s.userRepo.FindByID(ctx, invitation.TenantID, sentByID)
+	inviterName := "Team Member"
+	if err == nil && inviter != nil {
+		if inviter.FirstName != nil && inviter.LastName != nil {
+			inviterName = fmt.Sprintf("%s %s", *inviter.FirstName, *inviter.LastName)
 		}
+	}
 
-		if err := s.eventProducer.Publish(ctx, invitation.ID, event); err != nil {
-			fmt.Printf("Warning: failed to publish resend invitation event: %v\n", err)
-		}
+	// Fetch tenant info
+	var tenantName string
+	err = s.db.QueryRowContext(ctx, "SELECT business_name FROM tenants WHERE id = $1", invitation.TenantID).Scan(&tenantName)
+	if err != nil {
+		tenantName = "the team"
 	}
 
-	return invitation, nil
+	event := &events.NotificationEvent{
+		EventID:   uuid.New().String(),
+		EventType: "invitation.created", // Resends reuse the same template
+		TenantID:  invitation.TenantID,
+		UserID:    sentByID,
+		Data: map[string]interface{}{
+			"invitation_id":    invitation.ID,
+			"email":            invitation.Email,
+			"role":             invitation.Role,
+			"token":            invitation.Token,
+			"invitation_token": invitation.Token,
+			"expires_at":       invitation.ExpiresAt.Format(time.RFC3339),
+			"invited_by":       sentByID,
+			"inviter_name":     inviterName,
+			"tenant_name":      tenantName,
+		},
+		Timestamp: time.Now(),
+	}
+
+	if err := s.eventProducer.Publish(ctx, invitation.ID, event); err != nil {
+		fmt.Printf("Warning: failed to publish invitation event: %v\n", err)
+	}
+}
+
+// ExpireInvitations closes pending invitations past their expiry and deletes closed invitations past retention
+func (s *InvitationService) ExpireInvitations(ctx context.Context) (expired, deleted int64, err error) {
+	expired, err = s.invitationRepo.ExpirePending(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to expire invitations: %w", err)
+	}
+	deleted, err = s.invitationRepo.DeleteClosedBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return expired, 0, fmt.Errorf("failed to delete closed invitations: %w", err)
+	}
+	return expired, deleted, nil
+}
+
+// existingUserError refuses an invitation for an address already in the tenant
+// A deactivated user is reactivated by an owner or manager; an invitation must not hand them a new account.
+func existingUserError(user *models.User) error {
+	if user.Status == string(models.UserStatusSuspended) {
+		return ErrEmailDeactivated
+	}
+	return ErrEmailAlreadyExists
 }
 
 func generateSecureToken(length int) (string, error) {
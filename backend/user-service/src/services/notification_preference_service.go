@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+)
+
+// NotificationPreferenceError reports a preference patch that cannot be applied
+type NotificationPreferenceError struct {
+	Message string
+}
+
+func (e *NotificationPreferenceError) Error() string {
+	return e.Message
+}
+
+// NotificationPreferenceService manages which staff events a user receives on which channel
+// notification-service reads the same matrix before sending a staff notification.
+type NotificationPreferenceService struct {
+	preferenceRepo *repository.NotificationPreferenceRepository
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewNotificationPreferenceService(db *sql.DB, auditPublisher utils.AuditPublisherInterface) *NotificationPreferenceService {
+	return &NotificationPreferenceService{
+		preferenceRepo: repository.NewNotificationPreferenceRepository(db),
+		auditPublisher: auditPublisher,
+	}
+}
+
+// Get returns the user's full preference matrix, with defaults for the cells they never set
+func (s *NotificationPreferenceService) Get(ctx context.Context, tenantID, userID string) (*models.NotificationPreferencesResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	stored, err := s.preferenceRepo.List(ctx, tenantID, userID)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification preferences: %w", err)
+	}
+	return &models.NotificationPreferencesResponse{
+		UserID:      userID,
+		Events:      models.StaffNotificationEvents,
+		Channels:    models.NotificationChannels,
+		Preferences: ResolveNotificationPreferences(stored),
+	}, nil
+}
+
+// Patch changes the cells named in the request and returns the resulting matrix
+func (s *NotificationPreferenceService) Patch(ctx context.Context, tenantID, actorID, userID string, req *models.PatchNotificationPreferencesRequest) (*models.NotificationPreferencesResponse, error) {
+	cells := make(models.NotificationPreferenceMatrix)
+	if req.ReceiveOrderNotifications != nil {
+		for eventType, channels := range models.OrderEmailPreferences(*req.ReceiveOrderNotifications) {
+			cells[eventType] = channels
+		}
+	}
+	for eventType, channels := range req.Preferences {
+		if cells[eventType] == nil {
+			cells[eventType] = make(map[string]bool)
+		}
+		for channel, enabled := range channels {
+			cells[eventType][channel] = enabled
+		}
+	}
+	if err := ValidateNotificationPreferences(cells); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	err := s.preferenceRepo.Set(ctx, tenantID, userID, cells)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, userID, cells)
+	return s.Get(ctx, tenantID, userID)
+}
+
+// ValidateNotificationPreferences checks a patch names at least one cell and only known events and channels
+func ValidateNotificationPreferences(cells models.NotificationPreferenceMatrix) error {
+	count := 0
+	for eventType, channels := range cells {
+		if !slices.Contains(models.StaffNotificationEvents, eventType) {
+			return &NotificationPreferenceError{Message: fmt.Sprintf("unknown notification event: %s", eventType)}
+		}
+		for channel := range channels {
+			if !slices.Contains(models.NotificationChannels, channel) {
+				return &NotificationPreferenceError{Message: fmt.Sprintf("unknown notification channel: %s", channel)}
+			}
+			count++
+		}
+	}
+	if count == 0 {
+		return &NotificationPreferenceError{Message: "preferences must set at least one event and channel"}
+	}
+	return nil
+}
+
+// ResolveNotificationPreferences fills in the default of every cell the user has not set
+func ResolveNotificationPreferences(stored models.NotificationPreferenceMatrix) models.NotificationPreferenceMatrix {
+	matrix := make(models.NotificationPreferenceMatrix, len(models.StaffNotificationEvents))
+	for _, eventType := range models.StaffNotificationEvents {
+		matrix[eventType] = make(map[string]bool, len(models.NotificationChannels))
+		for _, channel := range models.NotificationChannels {
+			enabled, ok := stored[eventType][channel]
+			if !ok {
+				enabled = models.DefaultNotificationPreference(eventType, channel)
+			}
+			matrix[eventType][channel] = enabled
+		}
+	}
+	return matrix
+}
+
+func (s *NotificationPreferenceService) publishAudit(ctx context.Context, tenantID, actorID, userID string, cells models.NotificationPreferenceMatrix) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &actorID,
+		Action:       "UPDATE",
+		ResourceType: "user_notification_preferences",
+		ResourceID:   userID,
+		AfterValue: map[string]interface{}{
+			"preferences": cells,
+		},
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish notification preference audit event: %v\n", err)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/events"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/queue"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+)
+
+// Offboarding revokes what a deactivated user could still act with
+// Sessions live in auth-service, which ends them on the user.deactivated event published here. The SCIM
+// tokens the user issued are revoked and invitations still pending for their address are withdrawn.
+// Every step is best effort: the deactivation itself has already been saved.
+type Offboarding struct {
+	scimRepo       *repository.ScimRepository
+	invitationRepo *repository.InvitationRepository
+	userEvents     *queue.KafkaProducer
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewOffboarding(db *sql.DB, userEvents *queue.KafkaProducer, auditPublisher utils.AuditPublisherInterface) (*Offboarding, error) {
+	invitationRepo, err := repository.NewInvitationRepositoryWithVault(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invitation repository: %w", err)
+	}
+	return &Offboarding{
+		scimRepo:       repository.NewScimRepository(db),
+		invitationRepo: invitationRepo,
+		userEvents:     userEvents,
+		auditPublisher: auditPublisher,
+	}, nil
+}
+
+// Deactivated runs after a user is suspended or deleted
+// actorID is empty when the identity provider deactivated the user over SCIM.
+func (o *Offboarding) Deactivated(ctx context.Context, user *models.User, actorID, reason string) {
+	if o == nil {
+		return
+	}
+
+	if o.userEvents != nil {
+		event := &events.UserEvent{
+			EventID:   uuid.New().String(),
+			EventType: "user.deactivated",
+			TenantID:  user.TenantID,
+			UserID:    user.ID,
+			Data: map[string]interface{}{
+				"reason":   reason,
+				"actor_id": actorID,
+			},
+			Timestamp: time.Now(),
+		}
+		if err := o.userEvents.Publish(ctx, user.ID, event); err != nil {
+			fmt.Printf("Warning: failed to publish user deactivated event: %v\n", err)
+		}
+	}
+
+	tokenIDs, err := o.scimRepo.RevokeTokensCreatedBy(ctx, user.TenantID, user.ID)
+	if err != nil {
+		fmt.Printf("Warning: failed to revoke SCIM tokens of deactivated user: %v\n", err)
+	}
+	for _, tokenID := range tokenIDs {
+		o.publishAudit(ctx, user, actorID, "scim_token", tokenID, reason)
+	}
+
+	invitation, err := o.invitationRepo.FindByEmail(ctx, user.TenantID, user.Email)
+	if err != nil {
+		fmt.Printf("Warning: failed to find pending invitation of deactivated user: %v\n", err)
+	}
+	if invitation != nil {
+		if err := o.invitationRepo.UpdateStatus(ctx, invitation.ID, models.InvitationRevoked); err != nil {
+			fmt.Printf("Warning: failed to revoke invitation of deactivated user: %v\n", err)
+		} else {
+			o.publishAudit(ctx, user, actorID, "invitation", invitation.ID, reason)
+		}
+	}
+}
+
+func (o *Offboarding) publishAudit(ctx context.Context, user *models.User, actorID, resourceType, resourceID, reason string) {
+	if o.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     user.TenantID,
+		ActorType:    "system",
+		Action:       "DELETE",
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Metadata: map[string]interface{}{
+			"reason":  reason,
+			"user_id": user.ID,
+		},
+	}
+	if actorID != "" {
+		auditEvent.ActorType = "user"
+		auditEvent.ActorID = &actorID
+	}
+	if err := o.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish offboarding audit event: %v\n", err)
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPinLength = 4
+	maxPinLength = 6
+)
+
+var ErrPinManageForbidden = errors.New("not allowed to manage this user's PIN")
+
+// PinValidationError reports a PIN that cannot be used
+type PinValidationError struct {
+	Message string
+}
+
+func (e *PinValidationError) Error() string {
+	return e.Message
+}
+
+// PinService manages the PINs staff enter to switch users on a shared POS terminal
+// The auth service checks PINs; this service only ever stores their bcrypt hash.
+type PinService struct {
+	pinRepo        *repository.PinRepository
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewPinService(db *sql.DB, auditPublisher utils.AuditPublisherInterface) *PinService {
+	return &PinService{
+		pinRepo:        repository.NewPinRepository(db),
+		auditPublisher: auditPublisher,
+	}
+}
+
+func (s *PinService) GetStatus(ctx context.Context, tenantID, userID string) (*models.PinStatus, error) {
+	status, _, err := s.pinRepo.GetStatus(ctx, tenantID, userID)
+	return status, err
+}
+
+// GetStatusForUser returns whether a staff member has a PIN and whether it is locked
+func (s *PinService) GetStatusForUser(ctx context.Context, tenantID, actorID, actorRole, userID string) (*models.PinStatus, error) {
+	status, role, err := s.pinRepo.GetStatus(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := CanManagePin(actorRole, actorID, userID, role); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// SetOwn sets or replaces the PIN of the requesting user
+func (s *PinService) SetOwn(ctx context.Context, tenantID, userID string, req *models.SetPinRequest) error {
+	if err := ValidatePin(req.Pin); err != nil {
+		return err
+	}
+	if err := s.setPin(ctx, tenantID, userID, req.Pin); err != nil {
+		return err
+	}
+
+	s.publishAudit(ctx, tenantID, userID, userID, "pin_set")
+	return nil
+}
+
+// SetForUser sets or replaces a staff member's PIN, e.g. when setting up a new cashier
+// Setting a PIN also unlocks it.
+func (s *PinService) SetForUser(ctx context.Context, tenantID, actorID, actorRole, userID string, req *models.SetPinRequest) error {
+	if err := ValidatePin(req.Pin); err != nil {
+		return err
+	}
+	_, role, err := s.pinRepo.GetStatus(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if err := CanManagePin(actorRole, actorID, userID, role); err != nil {
+		return err
+	}
+	if err := s.setPin(ctx, tenantID, userID, req.Pin); err != nil {
+		return err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, userID, "pin_set")
+	return nil
+}
+
+// Reset removes a user's PIN so they have to choose a new one, e.g. after it was shared or got locked
+func (s *PinService) Reset(ctx context.Context, tenantID, actorID, actorRole, userID string) error {
+	_, role, err := s.pinRepo.GetStatus(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if err := CanManagePin(actorRole, actorID, userID, role); err != nil {
+		return err
+	}
+
+	if err := s.pinRepo.SetHash(ctx, tenantID, userID, nil); err != nil {
+		return err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, userID, "pin_reset")
+	return nil
+}
+
+// CanManagePin reports whether an actor may see, set or reset a user's PIN
+// Owners manage anyone's PIN and managers those of cashiers; everyone manages their own.
+func CanManagePin(actorRole, actorID, userID, userRole string) error {
+	if userID == actorID {
+		return nil
+	}
+	switch models.UserRole(actorRole) {
+	case models.RoleOwner:
+		return nil
+	case models.RoleManager:
+		if models.UserRole(userRole) == models.RoleCashier {
+			return nil
+		}
+	}
+	return ErrPinManageForbidden
+}
+
+func (s *PinService) setPin(ctx context.Context, tenantID, userID, pin string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash PIN: %w", err)
+	}
+	pinHash := string(hash)
+	return s.pinRepo.SetHash(ctx, tenantID, userID, &pinHash)
+}
+
+// ValidatePin checks a PIN is 4 to 6 digits and not trivially guessable
+func ValidatePin(pin string) error {
+	if len(pin) < minPinLength || len(pin) > maxPinLength {
+		return &PinValidationError{Message: fmt.Sprintf("PIN must be %d to %d digits", minPinLength, maxPinLength)}
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return &PinValidationError{Message: "PIN must contain only digits"}
+		}
+	}
+
+	// Reject repeated digits (1111) and straight runs (1234, 9876)
+	repeated, ascending, descending := true, true, true
+	for i := 1; i < len(pin); i++ {
+		step := int(pin[i]) - int(pin[i-1])
+		repeated = repeated && step == 0
+		ascending = ascending && step == 1
+		descending = descending && step == -1
+	}
+	if repeated || ascending || descending {
+		return &PinValidationError{Message: "PIN is too easy to guess"}
+	}
+	return nil
+}
+
+func (s *PinService) publishAudit(ctx context.Context, tenantID, actorID, userID, event string) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &actorID,
+		Action:       "UPDATE",
+		ResourceType: "user",
+		ResourceID:   userID,
+		Metadata: map[string]interface{}{
+			"event": event,
+		},
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish PIN audit event: %v\n", err)
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pos/user-service/src/repository"
+)
+
+// StaffSeatLimit is the plan limit checked before a staff seat is taken
+const StaffSeatLimit = "staff_seats"
+
+// PlanLimitError is returned when an action would take a tenant over a limit of their subscription plan
+type PlanLimitError struct {
+	Limit       string
+	Max         int
+	PlanName    string
+	UpgradePlan string // Cheapest plan with room for more; empty when there is none
+}
+
+func (e *PlanLimitError) Error() string {
+	return fmt.Sprintf("the %s plan allows %d staff seats", e.PlanName, e.Max)
+}
+
+// UpgradeHint tells the owner how to lift the limit
+func (e *PlanLimitError) UpgradeHint() string {
+	if e.UpgradePlan == "" {
+		return "Contact support to raise this limit"
+	}
+	return fmt.Sprintf("Upgrade to the %s plan to raise this limit", e.UpgradePlan)
+}
+
+// checkStaffSeat returns a *PlanLimitError when the tenant's plan has no free staff seat
+// Active and invited staff take a seat, as does every pending invitation; deactivated staff do not.
+func checkStaffSeat(ctx context.Context, userRepo *repository.UserRepository, tenantID string) error {
+	planName, max, used, err := userRepo.StaffSeatUsage(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to count staff seats: %w", err)
+	}
+	if max == nil || used < *max {
+		return nil
+	}
+
+	upgrade, err := userRepo.FindStaffUpgradePlan(ctx, used+1)
+	if err != nil {
+		fmt.Printf("Warning: failed to find upgrade plan for tenant %s: %v\n", tenantID, err)
+	}
+	return &PlanLimitError{Limit: StaffSeatLimit, Max: *max, PlanName: planName, UpgradePlan: upgrade}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+)
+
+const (
+	maxRoleNameLength        = 50
+	maxRoleDescriptionLength = 255
+)
+
+var (
+	ErrRoleUserNotFound = errors.New("user not found")
+	ErrOwnerRoleFixed   = errors.New("owners cannot be assigned a custom role")
+)
+
+// RoleValidationError reports a custom role request that cannot be saved
+type RoleValidationError struct {
+	Message string
+}
+
+func (e *RoleValidationError) Error() string {
+	return e.Message
+}
+
+// RoleService manages the custom roles owners define for their staff
+// Permissions take effect the next time the user's session token is refreshed.
+type RoleService struct {
+	roleRepo       *repository.RoleRepository
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewRoleService(db *sql.DB, auditPublisher utils.AuditPublisherInterface) *RoleService {
+	return &RoleService{
+		roleRepo:       repository.NewRoleRepository(db),
+		auditPublisher: auditPublisher,
+	}
+}
+
+func (s *RoleService) List(ctx context.Context, tenantID string) ([]*models.CustomRole, error) {
+	return s.roleRepo.List(ctx, tenantID)
+}
+
+func (s *RoleService) Get(ctx context.Context, tenantID, roleID string) (*models.CustomRole, error) {
+	return s.roleRepo.FindByID(ctx, tenantID, roleID)
+}
+
+func (s *RoleService) Create(ctx context.Context, tenantID, actorID string, req *models.CustomRoleRequest) (*models.CustomRole, error) {
+	role, err := NormalizeCustomRole(req)
+	if err != nil {
+		return nil, err
+	}
+	role.TenantID = tenantID
+	role.CreatedBy = &actorID
+
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "CREATE", "custom_role", role.ID, nil, roleAuditValue(role))
+	return role, nil
+}
+
+func (s *RoleService) Update(ctx context.Context, tenantID, actorID, roleID string, req *models.CustomRoleRequest) (*models.CustomRole, error) {
+	existing, err := s.roleRepo.FindByID(ctx, tenantID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := NormalizeCustomRole(req)
+	if err != nil {
+		return nil, err
+	}
+	role.ID = existing.ID
+	role.TenantID = tenantID
+	role.CreatedBy = existing.CreatedBy
+	role.CreatedAt = existing.CreatedAt
+	role.UserCount = existing.UserCount
+
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "UPDATE", "custom_role", role.ID, roleAuditValue(existing), roleAuditValue(role))
+	return role, nil
+}
+
+func (s *RoleService) Delete(ctx context.Context, tenantID, actorID, roleID string) error {
+	existing, err := s.roleRepo.FindByID(ctx, tenantID, roleID)
+	if err != nil {
+		return err
+	}
+	if err := s.roleRepo.Delete(ctx, tenantID, roleID); err != nil {
+		return err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "DELETE", "custom_role", roleID, roleAuditValue(existing), nil)
+	return nil
+}
+
+// AssignToUser gives a user a custom role, or returns them to their base role when roleID is nil
+func (s *RoleService) AssignToUser(ctx context.Context, tenantID, actorID, userID string, roleID *string) error {
+	baseRole, currentRoleID, err := s.roleRepo.GetUserAssignment(ctx, tenantID, userID)
+	if err == sql.ErrNoRows {
+		return ErrRoleUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+	if baseRole == string(models.RoleOwner) && roleID != nil {
+		return ErrOwnerRoleFixed
+	}
+	if roleID != nil {
+		// Confirms the role belongs to this tenant
+		if _, err := s.roleRepo.FindByID(ctx, tenantID, *roleID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.roleRepo.AssignToUser(ctx, tenantID, userID, roleID); err != nil {
+		return fmt.Errorf("failed to assign custom role: %w", err)
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "UPDATE", "user", userID,
+		map[string]interface{}{"custom_role_id": currentRoleID},
+		map[string]interface{}{"custom_role_id": roleID})
+	return nil
+}
+
+// NormalizeCustomRole validates a custom role request and returns the role it describes
+// Names are trimmed, blank descriptions dropped and permissions de-duplicated in the order given.
+func NormalizeCustomRole(req *models.CustomRoleRequest) (*models.CustomRole, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &RoleValidationError{Message: "Role name is required"}
+	}
+	if utf8.RuneCountInString(name) > maxRoleNameLength {
+		return nil, &RoleValidationError{Message: fmt.Sprintf("Role name must be at most %d characters", maxRoleNameLength)}
+	}
+	switch models.UserRole(strings.ToLower(name)) {
+	case models.RoleOwner, models.RoleManager, models.RoleCashier:
+		return nil, &RoleValidationError{Message: "Role name must differ from the built-in roles"}
+	}
+
+	var description *string
+	if req.Description != nil {
+		if trimmed := strings.TrimSpace(*req.Description); trimmed != "" {
+			if utf8.RuneCountInString(trimmed) > maxRoleDescriptionLength {
+				return nil, &RoleValidationError{Message: fmt.Sprintf("Role description must be at most %d characters", maxRoleDescriptionLength)}
+			}
+			description = &trimmed
+		}
+	}
+
+	if len(req.Permissions) == 0 {
+		return nil, &RoleValidationError{Message: "At least one permission is required"}
+	}
+	assignable := make(map[string]bool, len(models.AssignablePermissions))
+	for _, permission := range models.AssignablePermissions {
+		assignable[permission] = true
+	}
+	seen := make(map[string]bool, len(req.Permissions))
+	permissions := make([]string, 0, len(req.Permissions))
+	for _, permission := range req.Permissions {
+		if !assignable[permission] {
+			return nil, &RoleValidationError{Message: fmt.Sprintf("Unknown permission: %s", permission)}
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return &models.CustomRole{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	}, nil
+}
+
+func roleAuditValue(role *models.CustomRole) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        role.Name,
+		"permissions": role.Permissions,
+	}
+}
+
+func (s *RoleService) publishAudit(ctx context.Context, tenantID, actorID, action, resourceType, resourceID string, before, after map[string]interface{}) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &actorID,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		BeforeValue:  before,
+		AfterValue:   after,
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish custom role audit event: %v\n", err)
+	}
+}
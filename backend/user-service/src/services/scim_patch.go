@@ -0,0 +1,252 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pos/user-service/src/models"
+)
+
+// ScimRequestError is a SCIM request the service refuses; it maps onto a SCIM error response
+type ScimRequestError struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func (e *ScimRequestError) Error() string {
+	return e.Detail
+}
+
+func scimBadRequest(scimType, format string, args ...interface{}) *ScimRequestError {
+	return &ScimRequestError{Status: http.StatusBadRequest, ScimType: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+var scimFilterPattern = regexp.MustCompile(`(?i)^\s*(userName|externalId)\s+eq\s+"([^"]*)"\s*$`)
+
+// ParseScimFilter reads the filters identity providers use to look a user up before provisioning
+// Only userName eq "..." and externalId eq "..." are supported; the attribute comes back as written in the schema.
+func ParseScimFilter(filter string) (string, string, error) {
+	match := scimFilterPattern.FindStringSubmatch(filter)
+	if match == nil {
+		return "", "", scimBadRequest("invalidFilter", "Unsupported filter: %s", filter)
+	}
+	attribute := "userName"
+	if strings.EqualFold(match[1], "externalId") {
+		attribute = "externalId"
+	}
+	return attribute, match[2], nil
+}
+
+// ResolveScimRole picks the base role and custom role for a user in the given identity provider groups
+// Groups match mappings by display name or value, case-insensitively. A manager mapping wins over a
+// cashier one; between equal roles the first mapping (by group name) wins. Users in no mapped group
+// are provisioned as cashiers.
+func ResolveScimRole(mappings []*models.ScimGroupMapping, groups []models.ScimGroupRef) (string, *string) {
+	names := make(map[string]bool, len(groups)*2)
+	for _, group := range groups {
+		if display := strings.ToLower(strings.TrimSpace(group.Display)); display != "" {
+			names[display] = true
+		}
+		if value := strings.ToLower(strings.TrimSpace(group.Value)); value != "" {
+			names[value] = true
+		}
+	}
+
+	var chosen *models.ScimGroupMapping
+	for _, mapping := range mappings {
+		if !names[strings.ToLower(mapping.GroupName)] {
+			continue
+		}
+		if chosen == nil || (chosen.Role != string(models.RoleManager) && mapping.Role == string(models.RoleManager)) {
+			chosen = mapping
+		}
+	}
+	if chosen == nil {
+		return string(models.RoleCashier), nil
+	}
+	return chosen.Role, chosen.CustomRoleID
+}
+
+// ApplyScimPatch applies PATCH operations (RFC 7644 section 3.5.2) to a user resource
+// Operations without a path carry an object of attribute paths to values, as Azure AD sends them.
+func ApplyScimPatch(user *models.ScimUser, operations []models.ScimPatchOperation) error {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		switch op {
+		case "add", "replace", "remove":
+		default:
+			return scimBadRequest("invalidSyntax", "Unsupported patch operation: %s", operation.Op)
+		}
+
+		if operation.Path == "" {
+			if op == "remove" {
+				return scimBadRequest("noTarget", "Remove operations require a path")
+			}
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(operation.Value, &values); err != nil {
+				return scimBadRequest("invalidValue", "Patch value must be an object when no path is given")
+			}
+			for path, value := range values {
+				if err := applyScimPatchPath(user, op, path, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := applyScimPatchPath(user, op, operation.Path, operation.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyScimPatchPath(user *models.ScimUser, op, path string, value json.RawMessage) error {
+	lowerPath := strings.ToLower(path)
+	switch {
+	case lowerPath == "active":
+		if op == "remove" {
+			return scimBadRequest("mutability", "active cannot be removed")
+		}
+		active, err := scimBool(value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+
+	case lowerPath == "username", lowerPath == "externalid", lowerPath == "locale",
+		lowerPath == "name.givenname", lowerPath == "name.familyname":
+		text := ""
+		if op != "remove" {
+			if err := json.Unmarshal(value, &text); err != nil {
+				return scimBadRequest("invalidValue", "%s must be a string", path)
+			}
+		}
+		setScimString(user, lowerPath, text)
+
+	case lowerPath == "name":
+		name := models.ScimName{}
+		if op != "remove" {
+			if err := json.Unmarshal(value, &name); err != nil {
+				return scimBadRequest("invalidValue", "name must be an object")
+			}
+		}
+		user.Name = &name
+
+	case lowerPath == "emails":
+		var emails []models.ScimEmail
+		if op != "remove" {
+			if err := json.Unmarshal(value, &emails); err != nil {
+				return scimBadRequest("invalidValue", "emails must be a list")
+			}
+		}
+		user.Emails = emails
+
+	case strings.HasPrefix(lowerPath, "emails[") && strings.HasSuffix(lowerPath, "].value"):
+		// A single email address, e.g. emails[type eq "work"].value; the POS keeps only one
+		email := ""
+		if op != "remove" {
+			if err := json.Unmarshal(value, &email); err != nil {
+				return scimBadRequest("invalidValue", "%s must be a string", path)
+			}
+		}
+		user.Emails = nil
+		if email != "" {
+			user.Emails = []models.ScimEmail{{Value: email, Primary: true}}
+		}
+
+	case lowerPath == "groups":
+		var groups []models.ScimGroupRef
+		if len(value) > 0 {
+			if err := json.Unmarshal(value, &groups); err != nil {
+				return scimBadRequest("invalidValue", "groups must be a list")
+			}
+		}
+		user.Groups = patchScimGroups(user.Groups, op, groups)
+
+	default:
+		return scimBadRequest("invalidPath", "Unsupported attribute path: %s", path)
+	}
+	return nil
+}
+
+func setScimString(user *models.ScimUser, lowerPath, text string) {
+	switch lowerPath {
+	case "username":
+		user.UserName = text
+	case "externalid":
+		user.ExternalID = text
+	case "locale":
+		user.Locale = text
+	case "name.givenname", "name.familyname":
+		if user.Name == nil {
+			user.Name = &models.ScimName{}
+		}
+		if lowerPath == "name.givenname" {
+			user.Name.GivenName = text
+		} else {
+			user.Name.FamilyName = text
+		}
+	}
+}
+
+// patchScimGroups returns the user's groups after an operation; the result is never nil so the
+// caller can tell that the groups were touched and the role must be resolved again
+func patchScimGroups(current []models.ScimGroupRef, op string, groups []models.ScimGroupRef) []models.ScimGroupRef {
+	result := []models.ScimGroupRef{}
+	switch op {
+	case "replace":
+		result = append(result, groups...)
+	case "add":
+		seen := make(map[string]bool, len(current)+len(groups))
+		for _, group := range append(append([]models.ScimGroupRef{}, current...), groups...) {
+			if !seen[scimGroupKey(group)] {
+				seen[scimGroupKey(group)] = true
+				result = append(result, group)
+			}
+		}
+	case "remove":
+		if len(groups) == 0 {
+			return result
+		}
+		removed := make(map[string]bool, len(groups))
+		for _, group := range groups {
+			removed[scimGroupKey(group)] = true
+		}
+		for _, group := range current {
+			if !removed[scimGroupKey(group)] {
+				result = append(result, group)
+			}
+		}
+	}
+	return result
+}
+
+// scimGroupKey identifies a group by its value, or by its display name when it has no value
+func scimGroupKey(group models.ScimGroupRef) string {
+	if group.Value != "" {
+		return "value:" + strings.ToLower(group.Value)
+	}
+	return "display:" + strings.ToLower(group.Display)
+}
+
+// scimBool reads a boolean; some identity providers send "True" and "False" as strings
+func scimBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var text string
+	if err := json.Unmarshal(value, &text); err == nil {
+		switch strings.ToLower(text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, scimBadRequest("invalidValue", "active must be a boolean")
+}
@@ -0,0 +1,574 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	scimTokenPrefix        = "scim_"
+	maxScimTokenNameLength = 100
+	maxScimGroupNameLength = 255
+	maxScimPageSize        = 100
+)
+
+var (
+	ErrScimUnauthorized = errors.New("invalid SCIM token")
+	ErrScimUserNotFound = errors.New("user not found")
+)
+
+// ScimService provisions a tenant's staff from an identity provider over SCIM 2.0
+// The identity provider authenticates with a tenant API token. Users are matched to roles through the
+// tenant's group mappings; owners are never created, changed or removed through SCIM. Provisioned
+// users get no usable password and sign in through SSO, a magic link or a password reset.
+type ScimService struct {
+	userRepo       *repository.UserRepository
+	scimRepo       *repository.ScimRepository
+	roleRepo       *repository.RoleRepository
+	offboarding    *Offboarding
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewScimService(db *sql.DB, userRepo *repository.UserRepository, offboarding *Offboarding, auditPublisher utils.AuditPublisherInterface) *ScimService {
+	return &ScimService{
+		userRepo:       userRepo,
+		scimRepo:       repository.NewScimRepository(db),
+		roleRepo:       repository.NewRoleRepository(db),
+		offboarding:    offboarding,
+		auditPublisher: auditPublisher,
+	}
+}
+
+func hashScimToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate returns the tenant a SCIM bearer token belongs to
+func (s *ScimService) Authenticate(ctx context.Context, token string) (string, error) {
+	if !strings.HasPrefix(token, scimTokenPrefix) {
+		return "", ErrScimUnauthorized
+	}
+	tenantID, err := s.scimRepo.UseToken(ctx, hashScimToken(token))
+	if err == repository.ErrScimTokenNotFound {
+		return "", ErrScimUnauthorized
+	}
+	return tenantID, err
+}
+
+// CreateToken issues a SCIM token for the tenant; the plaintext is returned once and only its hash is kept
+func (s *ScimService) CreateToken(ctx context.Context, tenantID, actorID string, req *models.CreateScimTokenRequest) (*models.CreateScimTokenResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &RoleValidationError{Message: "Token name is required"}
+	}
+	if utf8.RuneCountInString(name) > maxScimTokenNameLength {
+		return nil, &RoleValidationError{Message: fmt.Sprintf("Token name must be at most %d characters", maxScimTokenNameLength)}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate SCIM token: %w", err)
+	}
+	plaintext := scimTokenPrefix + hex.EncodeToString(secret)
+
+	token := &models.ScimToken{
+		TenantID:    tenantID,
+		Name:        name,
+		TokenPrefix: plaintext[:len(scimTokenPrefix)+6],
+		CreatedBy:   &actorID,
+	}
+	if err := s.scimRepo.CreateToken(ctx, token, hashScimToken(plaintext)); err != nil {
+		return nil, fmt.Errorf("failed to store SCIM token: %w", err)
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "CREATE", "scim_token", token.ID, nil, map[string]interface{}{
+		"name":         token.Name,
+		"token_prefix": token.TokenPrefix,
+	})
+	return &models.CreateScimTokenResponse{ScimToken: token, Token: plaintext}, nil
+}
+
+func (s *ScimService) ListTokens(ctx context.Context, tenantID string) ([]*models.ScimToken, error) {
+	return s.scimRepo.ListTokens(ctx, tenantID)
+}
+
+// RevokeToken stops a SCIM token from authenticating; it stays listed for the audit trail
+func (s *ScimService) RevokeToken(ctx context.Context, tenantID, actorID, tokenID string) error {
+	if _, err := uuid.Parse(tokenID); err != nil {
+		return repository.ErrScimTokenNotFound
+	}
+	if err := s.scimRepo.RevokeToken(ctx, tenantID, tokenID); err != nil {
+		return err
+	}
+	s.publishAudit(ctx, tenantID, actorID, "DELETE", "scim_token", tokenID, nil, nil)
+	return nil
+}
+
+func (s *ScimService) ListGroupMappings(ctx context.Context, tenantID string) ([]*models.ScimGroupMapping, error) {
+	return s.scimRepo.ListGroupMappings(ctx, tenantID)
+}
+
+// ReplaceGroupMappings sets the tenant's group-to-role mappings
+// New mappings apply to users the next time the identity provider pushes them.
+func (s *ScimService) ReplaceGroupMappings(ctx context.Context, tenantID, actorID string, req *models.ReplaceScimGroupMappingsRequest) ([]*models.ScimGroupMapping, error) {
+	previous, err := s.scimRepo.ListGroupMappings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SCIM group mappings: %w", err)
+	}
+
+	mappings := make([]*models.ScimGroupMapping, 0, len(req.Mappings))
+	for _, item := range req.Mappings {
+		groupName := strings.TrimSpace(item.GroupName)
+		if groupName == "" {
+			return nil, &RoleValidationError{Message: "Group name is required"}
+		}
+		if utf8.RuneCountInString(groupName) > maxScimGroupNameLength {
+			return nil, &RoleValidationError{Message: fmt.Sprintf("Group name must be at most %d characters", maxScimGroupNameLength)}
+		}
+		if item.Role != string(models.RoleManager) && item.Role != string(models.RoleCashier) {
+			return nil, &RoleValidationError{Message: "Groups can only be mapped to the manager or cashier role"}
+		}
+		if item.CustomRoleID != nil {
+			// Confirms the role belongs to this tenant
+			if _, err := s.roleRepo.FindByID(ctx, tenantID, *item.CustomRoleID); err != nil {
+				return nil, err
+			}
+		}
+		mappings = append(mappings, &models.ScimGroupMapping{
+			GroupName:    groupName,
+			Role:         item.Role,
+			CustomRoleID: item.CustomRoleID,
+		})
+	}
+
+	if err := s.scimRepo.ReplaceGroupMappings(ctx, tenantID, mappings); err != nil {
+		return nil, err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "UPDATE", "scim_group_mappings", tenantID,
+		map[string]interface{}{"mappings": groupMappingAuditValue(previous)},
+		map[string]interface{}{"mappings": groupMappingAuditValue(mappings)})
+	return s.scimRepo.ListGroupMappings(ctx, tenantID)
+}
+
+// ListUsers returns one page of the tenant's users, or the users matching a userName or externalId filter
+// startIndex is 1-based as in SCIM.
+func (s *ScimService) ListUsers(ctx context.Context, tenantID, filter string, startIndex, count int) (*models.ScimListResponse, error) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 || count > maxScimPageSize {
+		count = maxScimPageSize
+	}
+
+	var ids []string
+	total := 0
+	if filter != "" {
+		attribute, value, err := ParseScimFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		id, err := s.findUserID(ctx, tenantID, attribute, value)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			total = 1
+			if startIndex == 1 && count > 0 {
+				ids = []string{id}
+			}
+		}
+	} else {
+		var err error
+		ids, total, err = s.scimRepo.ListUserIDs(ctx, tenantID, startIndex-1, count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+	}
+
+	resources := make([]*models.ScimUser, 0, len(ids))
+	for _, id := range ids {
+		user, err := s.GetUser(ctx, tenantID, id)
+		if err == ErrScimUserNotFound {
+			// Deleted between the listing and the lookup
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, user)
+	}
+
+	return &models.ScimListResponse{
+		Schemas:      []string{models.ScimListResponseSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+func (s *ScimService) findUserID(ctx context.Context, tenantID, attribute, value string) (string, error) {
+	if attribute == "externalId" {
+		id, err := s.scimRepo.FindUserIDByExternalID(ctx, tenantID, value)
+		if err != nil {
+			return "", fmt.Errorf("failed to find user by external ID: %w", err)
+		}
+		return id, nil
+	}
+	user, err := s.userRepo.FindByEmail(ctx, tenantID, strings.ToLower(value))
+	if err != nil {
+		return "", fmt.Errorf("failed to find user by email: %w", err)
+	}
+	if user == nil {
+		return "", nil
+	}
+	return user.ID, nil
+}
+
+func (s *ScimService) GetUser(ctx context.Context, tenantID, userID string) (*models.ScimUser, error) {
+	user, err := s.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	externalID, groups, err := s.scimRepo.GetScimAttributes(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SCIM attributes: %w", err)
+	}
+	return toScimUser(user, externalID, groups), nil
+}
+
+func (s *ScimService) findUser(ctx context.Context, tenantID, userID string) (*models.User, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrScimUserNotFound
+	}
+	user, err := s.userRepo.FindByID(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrScimUserNotFound
+	}
+	return user, nil
+}
+
+// CreateUser provisions a new staff member
+func (s *ScimService) CreateUser(ctx context.Context, tenantID string, req *models.ScimUser) (*models.ScimUser, error) {
+	email, err := scimUserEmail(req)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.userRepo.FindByEmail(ctx, tenantID, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
+	}
+	if existing != nil {
+		return nil, &ScimRequestError{Status: http.StatusConflict, ScimType: "uniqueness", Detail: "A user with this userName already exists"}
+	}
+
+	mappings, err := s.scimRepo.ListGroupMappings(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SCIM group mappings: %w", err)
+	}
+	role, customRoleID := ResolveScimRole(mappings, req.Groups)
+
+	passwordHash, err := unusablePasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		TenantID:     tenantID,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+		Status:       scimStatus(req.Active, string(models.UserStatusActive)),
+		Locale:       scimLocale(req.Locale, "en"),
+	}
+	applyScimName(user, req.Name)
+	if user.Status == string(models.UserStatusActive) {
+		if err := s.checkStaffSeat(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	if err := s.scimRepo.SetScimAttributes(ctx, tenantID, user.ID, req.ExternalID, scimGroupsOrEmpty(req.Groups)); err != nil {
+		if err == repository.ErrScimExternalIDTaken {
+			return nil, &ScimRequestError{Status: http.StatusConflict, ScimType: "uniqueness", Detail: "externalId is already in use"}
+		}
+		return nil, fmt.Errorf("failed to store SCIM attributes: %w", err)
+	}
+	if customRoleID != nil {
+		if err := s.roleRepo.AssignToUser(ctx, tenantID, user.ID, customRoleID); err != nil {
+			return nil, fmt.Errorf("failed to assign custom role: %w", err)
+		}
+	}
+
+	return s.GetUser(ctx, tenantID, user.ID)
+}
+
+// ReplaceUser updates a user from a full resource (PUT)
+// Attributes the identity provider leaves out keep their value; groups, when present, set the role again.
+func (s *ScimService) ReplaceUser(ctx context.Context, tenantID, userID string, req *models.ScimUser) (*models.ScimUser, error) {
+	user, err := s.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == string(models.RoleOwner) {
+		return nil, &ScimRequestError{Status: http.StatusForbidden, ScimType: "mutability", Detail: "Tenant owners cannot be managed through SCIM"}
+	}
+
+	email, err := scimUserEmail(req)
+	if err != nil {
+		return nil, err
+	}
+	if email != user.Email {
+		existing, err := s.userRepo.FindByEmail(ctx, tenantID, email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing user: %w", err)
+		}
+		if existing != nil && existing.ID != user.ID {
+			return nil, &ScimRequestError{Status: http.StatusConflict, ScimType: "uniqueness", Detail: "A user with this userName already exists"}
+		}
+		user.Email = email
+	}
+
+	var customRoleID *string
+	if req.Groups != nil {
+		mappings, err := s.scimRepo.ListGroupMappings(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SCIM group mappings: %w", err)
+		}
+		user.Role, customRoleID = ResolveScimRole(mappings, req.Groups)
+	}
+	wasActive := user.Status == string(models.UserStatusActive)
+	user.Status = scimStatus(req.Active, user.Status)
+	if user.Status == string(models.UserStatusActive) && !wasActive {
+		if err := s.checkStaffSeat(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	user.Locale = scimLocale(req.Locale, user.Locale)
+	applyScimName(user, req.Name)
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	if wasActive && user.Status == string(models.UserStatusSuspended) {
+		s.offboarding.Deactivated(ctx, user, "", "scim_deactivated")
+	}
+
+	externalID := req.ExternalID
+	if externalID == "" {
+		if externalID, _, err = s.scimRepo.GetScimAttributes(ctx, tenantID, userID); err != nil {
+			return nil, fmt.Errorf("failed to load SCIM attributes: %w", err)
+		}
+	}
+	if err := s.scimRepo.SetScimAttributes(ctx, tenantID, userID, externalID, req.Groups); err != nil {
+		if err == repository.ErrScimExternalIDTaken {
+			return nil, &ScimRequestError{Status: http.StatusConflict, ScimType: "uniqueness", Detail: "externalId is already in use"}
+		}
+		return nil, fmt.Errorf("failed to store SCIM attributes: %w", err)
+	}
+	if req.Groups != nil {
+		if err := s.roleRepo.AssignToUser(ctx, tenantID, userID, customRoleID); err != nil {
+			return nil, fmt.Errorf("failed to assign custom role: %w", err)
+		}
+	}
+
+	return s.GetUser(ctx, tenantID, userID)
+}
+
+// PatchUser applies PATCH operations to a user; active=false is how identity providers deactivate
+func (s *ScimService) PatchUser(ctx context.Context, tenantID, userID string, req *models.ScimPatchRequest) (*models.ScimUser, error) {
+	current, err := s.GetUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ApplyScimPatch(current, req.Operations); err != nil {
+		return nil, err
+	}
+	return s.ReplaceUser(ctx, tenantID, userID, current)
+}
+
+// DeleteUser soft deletes a user; the retention jobs purge them like any other deleted user
+func (s *ScimService) DeleteUser(ctx context.Context, tenantID, userID string) error {
+	user, err := s.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == string(models.RoleOwner) {
+		return &ScimRequestError{Status: http.StatusForbidden, ScimType: "mutability", Detail: "Tenant owners cannot be managed through SCIM"}
+	}
+	if err := s.userRepo.Delete(ctx, tenantID, userID, "soft"); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	s.offboarding.Deactivated(ctx, user, "", "scim_deleted")
+	return nil
+}
+
+// checkStaffSeat refuses users the tenant's plan has no seat for, in SCIM error form
+func (s *ScimService) checkStaffSeat(ctx context.Context, tenantID string) error {
+	err := checkStaffSeat(ctx, s.userRepo, tenantID)
+	if limitErr, ok := err.(*PlanLimitError); ok {
+		return &ScimRequestError{Status: http.StatusForbidden, Detail: limitErr.Error() + ". " + limitErr.UpgradeHint()}
+	}
+	return err
+}
+
+func toScimUser(user *models.User, externalID string, groups []models.ScimGroupRef) *models.ScimUser {
+	active := user.Status == string(models.UserStatusActive)
+	scimUser := &models.ScimUser{
+		Schemas:    []string{models.ScimUserSchema},
+		ID:         user.ID,
+		ExternalID: externalID,
+		UserName:   user.Email,
+		Emails:     []models.ScimEmail{{Value: user.Email, Type: "work", Primary: true}},
+		Active:     &active,
+		Locale:     user.Locale,
+		Groups:     groups,
+		Meta: &models.ScimMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     "/scim/v2/Users/" + user.ID,
+		},
+	}
+	if user.FirstName != nil || user.LastName != nil {
+		scimUser.Name = &models.ScimName{}
+		if user.FirstName != nil {
+			scimUser.Name.GivenName = *user.FirstName
+		}
+		if user.LastName != nil {
+			scimUser.Name.FamilyName = *user.LastName
+		}
+	}
+	return scimUser
+}
+
+// scimUserEmail returns the address a resource provisions: the primary email, else the first, else userName
+func scimUserEmail(req *models.ScimUser) (string, error) {
+	email := ""
+	for _, candidate := range req.Emails {
+		if candidate.Primary || email == "" {
+			email = candidate.Value
+		}
+		if candidate.Primary {
+			break
+		}
+	}
+	if email == "" {
+		email = req.UserName
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || !strings.Contains(email, "@") {
+		return "", scimBadRequest("invalidValue", "userName or emails must hold an email address")
+	}
+	return email, nil
+}
+
+func scimStatus(active *bool, current string) string {
+	if active == nil {
+		return current
+	}
+	if *active {
+		return string(models.UserStatusActive)
+	}
+	return string(models.UserStatusSuspended)
+}
+
+// scimLocale maps SCIM locales such as id-ID and en-US onto the POS languages
+func scimLocale(locale, current string) string {
+	switch strings.ToLower(strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)[0]) {
+	case "id":
+		return "id"
+	case "en":
+		return "en"
+	}
+	return current
+}
+
+func applyScimName(user *models.User, name *models.ScimName) {
+	if name == nil {
+		return
+	}
+	user.FirstName = optionalString(name.GivenName)
+	user.LastName = optionalString(name.FamilyName)
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func scimGroupsOrEmpty(groups []models.ScimGroupRef) []models.ScimGroupRef {
+	if groups == nil {
+		return []models.ScimGroupRef{}
+	}
+	return groups
+}
+
+// unusablePasswordHash returns the hash of a random password nobody knows
+func unusablePasswordHash() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func groupMappingAuditValue(mappings []*models.ScimGroupMapping) []map[string]interface{} {
+	values := make([]map[string]interface{}, 0, len(mappings))
+	for _, mapping := range mappings {
+		values = append(values, map[string]interface{}{
+			"group_name":     mapping.GroupName,
+			"role":           mapping.Role,
+			"custom_role_id": mapping.CustomRoleID,
+		})
+	}
+	return values
+}
+
+func (s *ScimService) publishAudit(ctx context.Context, tenantID, actorID, action, resourceType, resourceID string, before, after map[string]interface{}) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &actorID,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		BeforeValue:  before,
+		AfterValue:   after,
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish SCIM audit event: %v\n", err)
+	}
+}
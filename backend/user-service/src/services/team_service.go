@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/repository"
+	"github.com/pos/user-service/src/utils"
+)
+
+const (
+	maxTeamNameLength        = 50
+	maxTeamDescriptionLength = 255
+)
+
+// TeamValidationError reports a team request that cannot be saved
+type TeamValidationError struct {
+	Message string
+}
+
+func (e *TeamValidationError) Error() string {
+	return e.Message
+}
+
+// TeamService manages the teams staff are grouped into
+// Routes take effect on the next staff notification; notification-service reads them directly.
+type TeamService struct {
+	teamRepo       *repository.TeamRepository
+	auditPublisher utils.AuditPublisherInterface
+}
+
+func NewTeamService(db *sql.DB, auditPublisher utils.AuditPublisherInterface) *TeamService {
+	return &TeamService{
+		teamRepo:       repository.NewTeamRepository(db),
+		auditPublisher: auditPublisher,
+	}
+}
+
+func (s *TeamService) List(ctx context.Context, tenantID string) ([]*models.Team, error) {
+	return s.teamRepo.List(ctx, tenantID)
+}
+
+func (s *TeamService) Get(ctx context.Context, tenantID, teamID string) (*models.Team, error) {
+	if _, err := uuid.Parse(teamID); err != nil {
+		return nil, repository.ErrTeamNotFound
+	}
+	return s.teamRepo.FindByID(ctx, tenantID, teamID)
+}
+
+func (s *TeamService) Create(ctx context.Context, tenantID, actorID string, req *models.TeamRequest) (*models.Team, error) {
+	team, err := NormalizeTeam(req)
+	if err != nil {
+		return nil, err
+	}
+	team.TenantID = tenantID
+	team.CreatedBy = &actorID
+
+	if err := s.teamRepo.Create(ctx, team); err != nil {
+		return nil, err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "CREATE", team.ID, nil, teamAuditValue(team))
+	return team, nil
+}
+
+func (s *TeamService) Update(ctx context.Context, tenantID, actorID, teamID string, req *models.TeamRequest) (*models.Team, error) {
+	existing, err := s.Get(ctx, tenantID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := NormalizeTeam(req)
+	if err != nil {
+		return nil, err
+	}
+	team.ID = existing.ID
+	team.TenantID = tenantID
+	team.MemberIDs = existing.MemberIDs
+	team.CreatedBy = existing.CreatedBy
+	team.CreatedAt = existing.CreatedAt
+
+	if err := s.teamRepo.Update(ctx, team); err != nil {
+		return nil, err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "UPDATE", team.ID, teamAuditValue(existing), teamAuditValue(team))
+	return team, nil
+}
+
+func (s *TeamService) Delete(ctx context.Context, tenantID, actorID, teamID string) error {
+	existing, err := s.Get(ctx, tenantID, teamID)
+	if err != nil {
+		return err
+	}
+	if err := s.teamRepo.Delete(ctx, tenantID, teamID); err != nil {
+		return err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "DELETE", teamID, teamAuditValue(existing), nil)
+	return nil
+}
+
+// SetMembers replaces the members of a team and returns the updated team
+func (s *TeamService) SetMembers(ctx context.Context, tenantID, actorID, teamID string, userIDs []string) (*models.Team, error) {
+	memberIDs, err := NormalizeTeamMembers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.Get(ctx, tenantID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.teamRepo.SetMembers(ctx, tenantID, teamID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	s.publishAudit(ctx, tenantID, actorID, "UPDATE", teamID,
+		map[string]interface{}{"member_ids": existing.MemberIDs},
+		map[string]interface{}{"member_ids": memberIDs})
+	return s.teamRepo.FindByID(ctx, tenantID, teamID)
+}
+
+// NormalizeTeam validates a team request and returns the team it describes
+// Names are trimmed, blank descriptions dropped and delivery types de-duplicated in the order given.
+func NormalizeTeam(req *models.TeamRequest) (*models.Team, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &TeamValidationError{Message: "Team name is required"}
+	}
+	if utf8.RuneCountInString(name) > maxTeamNameLength {
+		return nil, &TeamValidationError{Message: fmt.Sprintf("Team name must be at most %d characters", maxTeamNameLength)}
+	}
+
+	var description *string
+	if req.Description != nil {
+		if trimmed := strings.TrimSpace(*req.Description); trimmed != "" {
+			if utf8.RuneCountInString(trimmed) > maxTeamDescriptionLength {
+				return nil, &TeamValidationError{Message: fmt.Sprintf("Team description must be at most %d characters", maxTeamDescriptionLength)}
+			}
+			description = &trimmed
+		}
+	}
+
+	routes := make([]models.TeamNotificationRoute, 0, len(req.NotificationRoutes))
+	seenEvents := make(map[string]bool, len(req.NotificationRoutes))
+	for _, route := range req.NotificationRoutes {
+		if !slices.Contains(models.StaffNotificationEvents, route.EventType) {
+			return nil, &TeamValidationError{Message: fmt.Sprintf("Unknown notification event: %s", route.EventType)}
+		}
+		if seenEvents[route.EventType] {
+			return nil, &TeamValidationError{Message: fmt.Sprintf("Notification event listed twice: %s", route.EventType)}
+		}
+		seenEvents[route.EventType] = true
+
+		deliveryTypes := make([]string, 0, len(route.DeliveryTypes))
+		for _, deliveryType := range route.DeliveryTypes {
+			if !slices.Contains(models.OrderDeliveryTypes, deliveryType) {
+				return nil, &TeamValidationError{Message: fmt.Sprintf("Unknown delivery type: %s", deliveryType)}
+			}
+			if !slices.Contains(deliveryTypes, deliveryType) {
+				deliveryTypes = append(deliveryTypes, deliveryType)
+			}
+		}
+		routes = append(routes, models.TeamNotificationRoute{EventType: route.EventType, DeliveryTypes: deliveryTypes})
+	}
+
+	return &models.Team{
+		Name:               name,
+		Description:        description,
+		MemberIDs:          []string{},
+		NotificationRoutes: routes,
+	}, nil
+}
+
+// NormalizeTeamMembers checks every member is a user ID, lower-cases them and drops duplicates in the order given
+func NormalizeTeamMembers(userIDs []string) ([]string, error) {
+	members := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		parsed, err := uuid.Parse(userID)
+		if err != nil {
+			return nil, &TeamValidationError{Message: fmt.Sprintf("Invalid user ID: %s", userID)}
+		}
+		if id := parsed.String(); !slices.Contains(members, id) {
+			members = append(members, id)
+		}
+	}
+	return members, nil
+}
+
+func teamAuditValue(team *models.Team) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                team.Name,
+		"notification_routes": team.NotificationRoutes,
+	}
+}
+
+func (s *TeamService) publishAudit(ctx context.Context, tenantID, actorID, action, teamID string, before, after map[string]interface{}) {
+	if s.auditPublisher == nil {
+		return
+	}
+	auditEvent := &utils.AuditEvent{
+		TenantID:     tenantID,
+		ActorType:    "user",
+		ActorID:      &actorID,
+		Action:       action,
+		ResourceType: "team",
+		ResourceID:   teamID,
+		BeforeValue:  before,
+		AfterValue:   after,
+	}
+	if err := s.auditPublisher.Publish(ctx, auditEvent); err != nil {
+		fmt.Printf("Failed to publish team audit event: %v\n", err)
+	}
+}
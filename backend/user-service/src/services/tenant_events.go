@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/events"
+	"github.com/pos/user-service/src/queue"
+)
+
+// TenantEventHandler takes part in the tenant deletion saga run by tenant-service
+// It deletes the tenant's avatars from object storage and then its users, and acknowledges with the counts.
+// tenant-service asks it last, once the orders, shifts and stock history that reference staff are gone.
+type TenantEventHandler struct {
+	deletionService *UserDeletionService
+	avatarStorage   *AvatarStorage
+	tenantEvents    *queue.KafkaProducer
+}
+
+// NewTenantEventHandler creates a tenant event handler; avatarStorage may be nil when no object storage is configured
+func NewTenantEventHandler(deletionService *UserDeletionService, avatarStorage *AvatarStorage, tenantEvents *queue.KafkaProducer) *TenantEventHandler {
+	return &TenantEventHandler{
+		deletionService: deletionService,
+		avatarStorage:   avatarStorage,
+		tenantEvents:    tenantEvents,
+	}
+}
+
+// Handle processes one message from the tenant-events topic; other event types are ignored
+func (h *TenantEventHandler) Handle(ctx context.Context, payload []byte) error {
+	var event events.TenantEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		log.Printf("Discarding malformed tenant event: %v", err)
+		return nil
+	}
+	if event.EventType != "tenant.deletion.requested" {
+		return nil
+	}
+	var req events.TenantDeletionRequest
+	if err := json.Unmarshal(event.Data, &req); err != nil || !req.Includes(events.TenantDeletionParticipant) {
+		return nil
+	}
+	if _, err := uuid.Parse(event.TenantID); err != nil {
+		log.Printf("Discarding tenant deletion request with invalid tenant ID %q", event.TenantID)
+		return nil
+	}
+
+	ack := events.TenantDeletionAck{
+		DeletionID: req.DeletionID,
+		Service:    events.TenantDeletionParticipant,
+		Status:     "completed",
+		Deleted:    map[string]int64{},
+	}
+	if err := h.deleteTenantData(ctx, event.TenantID, ack.Deleted); err != nil {
+		log.Printf("Failed to delete users of tenant %s: %v", event.TenantID, err)
+		ack.Status = "failed"
+		ack.Error = err.Error()
+	}
+
+	data, err := json.Marshal(ack)
+	if err != nil {
+		return err
+	}
+	reply := events.TenantEvent{
+		EventID:   uuid.New().String(),
+		EventType: "tenant.deletion.acknowledged",
+		TenantID:  event.TenantID,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+	if err := h.tenantEvents.Publish(ctx, event.TenantID, reply); err != nil {
+		return fmt.Errorf("failed to acknowledge tenant deletion %s: %w", req.DeletionID, err)
+	}
+	return nil
+}
+
+// deleteTenantData is safe to repeat; a retried request finds less, or nothing, to delete
+func (h *TenantEventHandler) deleteTenantData(ctx context.Context, tenantID string, deleted map[string]int64) error {
+	if h.avatarStorage != nil {
+		avatars, err := h.avatarStorage.DeletePrefix(ctx, "avatars/"+tenantID+"/")
+		if err != nil {
+			return err
+		}
+		deleted["avatars"] = avatars
+	}
+
+	users, err := h.deletionService.DeleteTenantUsers(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	deleted["users"] = users
+	return nil
+}
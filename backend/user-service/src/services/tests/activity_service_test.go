@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// TestMergeActivities verifies feeds are combined newest first and cut at the limit
+func TestMergeActivities(t *testing.T) {
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	logins := []*models.UserActivity{
+		{Type: models.ActivityLogin, Timestamp: base.Add(3 * time.Hour)},
+		{Type: models.ActivityLogin, Timestamp: base},
+	}
+	adjustments := []*models.UserActivity{
+		{Type: models.ActivityStockAdjustment, Timestamp: base.Add(2 * time.Hour)},
+	}
+	orders := []*models.UserActivity{
+		{Type: models.ActivityOrderStatus, Timestamp: base.Add(4 * time.Hour)},
+		{Type: models.ActivityOrderStatus, Timestamp: base.Add(time.Hour)},
+	}
+
+	merged := services.MergeActivities([][]*models.UserActivity{logins, adjustments, orders}, 4)
+	want := []string{
+		models.ActivityOrderStatus,
+		models.ActivityLogin,
+		models.ActivityStockAdjustment,
+		models.ActivityOrderStatus,
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("MergeActivities() returned %d entries, want %d", len(merged), len(want))
+	}
+	for i, activity := range merged {
+		if activity.Type != want[i] {
+			t.Errorf("MergeActivities()[%d].Type = %s, want %s", i, activity.Type, want[i])
+		}
+		if i > 0 && activity.Timestamp.After(merged[i-1].Timestamp) {
+			t.Errorf("MergeActivities()[%d] is newer than the entry before it", i)
+		}
+	}
+}
+
+// TestMergeActivitiesEmpty verifies an empty feed is returned as an empty list
+func TestMergeActivitiesEmpty(t *testing.T) {
+	merged := services.MergeActivities(nil, 10)
+	if merged == nil || len(merged) != 0 {
+		t.Errorf("MergeActivities(nil) = %v, want empty list", merged)
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/pos/user-service/src/services"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 255})
+	}
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestProcessAvatar verifies avatars are cropped to a square JPEG
+func TestProcessAvatar(t *testing.T) {
+	avatar, err := services.ProcessAvatar(bytes.NewReader(pngImage(t, 800, 600)))
+	if err != nil {
+		t.Fatalf("ProcessAvatar() unexpected error: %v", err)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(avatar))
+	if err != nil {
+		t.Fatalf("ProcessAvatar() did not return a JPEG: %v", err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() != services.AvatarSizePixels || bounds.Dy() != services.AvatarSizePixels {
+		t.Errorf("ProcessAvatar() size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), services.AvatarSizePixels, services.AvatarSizePixels)
+	}
+}
+
+// TestProcessAvatarRejects verifies files that are not images, or too large, are refused
+func TestProcessAvatarRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{name: "Not an image", data: []byte("name,email\n"), want: services.ErrAvatarInvalid},
+		{name: "Too many pixels", data: pngImage(t, 5000, 10), want: services.ErrAvatarDimensions},
+		{name: "Too large", data: []byte(strings.Repeat("a", services.MaxAvatarSizeBytes+1)), want: services.ErrAvatarTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := services.ProcessAvatar(bytes.NewReader(tt.data)); err != tt.want {
+				t.Errorf("ProcessAvatar() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestAvatarStorageKey verifies avatars are stored under the user's prefix
+func TestAvatarStorageKey(t *testing.T) {
+	got := services.AvatarStorageKey("tenant-1", "user-1", "avatar-1")
+	if want := "avatars/tenant-1/user-1/avatar-1.jpg"; got != want {
+		t.Errorf("AvatarStorageKey() = %s, want %s", got, want)
+	}
+}
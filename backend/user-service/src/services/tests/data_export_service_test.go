@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pos/user-service/src/services"
+)
+
+// TestVerifyDataExportSignature verifies download links are refused when altered or expired
+func TestVerifyDataExportSignature(t *testing.T) {
+	key := []byte("test-signing-key")
+	exportID := "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f"
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	expires := now.Add(24 * time.Hour).Unix()
+	signature := services.SignDataExport(key, exportID, expires)
+
+	tests := []struct {
+		name      string
+		key       []byte
+		exportID  string
+		expires   int64
+		signature string
+		now       time.Time
+		want      bool
+	}{
+		{name: "Valid link", key: key, exportID: exportID, expires: expires, signature: signature, now: now, want: true},
+		{name: "Expired link", key: key, exportID: exportID, expires: expires, signature: signature, now: now.Add(25 * time.Hour), want: false},
+		{name: "Extended expiry", key: key, exportID: exportID, expires: expires + 3600, signature: signature, now: now, want: false},
+		{name: "Other export", key: key, exportID: "7a1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f", expires: expires, signature: signature, now: now, want: false},
+		{name: "Other key", key: []byte("other-key"), exportID: exportID, expires: expires, signature: signature, now: now, want: false},
+		{name: "Missing signature", key: key, exportID: exportID, expires: expires, signature: "", now: now, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := services.VerifyDataExportSignature(tt.key, tt.exportID, tt.expires, tt.signature, tt.now)
+			if got != tt.want {
+				t.Errorf("VerifyDataExportSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestZipDataExport verifies the ZIP bundle holds the JSON as personal-data.json
+func TestZipDataExport(t *testing.T) {
+	data := []byte(`{"profile":{"id":"user-1"}}`)
+	zipped, err := services.ZipDataExport(data)
+	if err != nil {
+		t.Fatalf("ZipDataExport() unexpected error: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(zipped), int64(len(zipped)))
+	if err != nil {
+		t.Fatalf("ZipDataExport() did not return a ZIP archive: %v", err)
+	}
+	if len(archive.File) != 1 || archive.File[0].Name != "personal-data.json" {
+		t.Fatalf("ZipDataExport() archive files = %v, want personal-data.json only", archive.File)
+	}
+	file, err := archive.File[0].Open()
+	if err != nil {
+		t.Fatalf("failed to open personal-data.json: %v", err)
+	}
+	defer file.Close()
+	content, _ := io.ReadAll(file)
+	if !bytes.Equal(content, data) {
+		t.Errorf("personal-data.json = %s, want %s", content, data)
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// TestParseInvitationCSV verifies rows are read with their line numbers and a header is skipped
+func TestParseInvitationCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []models.BulkInvitationRow
+		wantErr bool
+	}{
+		{
+			name: "Header row",
+			csv:  "email,role\nana@example.com,cashier\nBudi@Example.com, Manager\n",
+			want: []models.BulkInvitationRow{
+				{Line: 2, Email: "ana@example.com", Role: "cashier"},
+				{Line: 3, Email: "budi@example.com", Role: "manager"},
+			},
+		},
+		{
+			name: "No header, blank lines and a missing role",
+			csv:  "ana@example.com,cashier\n\nbudi@example.com\n",
+			want: []models.BulkInvitationRow{
+				{Line: 1, Email: "ana@example.com", Role: "cashier"},
+				{Line: 3, Email: "budi@example.com", Role: ""},
+			},
+		},
+		{
+			name:    "Header only",
+			csv:     "email,role\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated quote",
+			csv:     "\"ana@example.com,cashier\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := services.ParseInvitationCSV(strings.NewReader(tt.csv))
+			if tt.wantErr {
+				if _, ok := err.(*services.BulkInvitationFileError); !ok {
+					t.Fatalf("ParseInvitationCSV() error = %v, want BulkInvitationFileError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseInvitationCSV() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("ParseInvitationCSV() = %+v, want %+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseInvitationCSVRowLimit verifies files over the row limit are refused
+func TestParseInvitationCSVRowLimit(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i <= services.MaxBulkInvitations; i++ {
+		fmt.Fprintf(&b, "staff%d@example.com,cashier\n", i)
+	}
+	if _, err := services.ParseInvitationCSV(strings.NewReader(b.String())); err == nil {
+		t.Error("ParseInvitationCSV() accepted more than MaxBulkInvitations rows")
+	}
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// TestResolveNotificationPreferences verifies stored cells win and every other cell gets its default
+func TestResolveNotificationPreferences(t *testing.T) {
+	stored := models.NotificationPreferenceMatrix{
+		"order.paid":                {"email": true, "in_app": true},
+		"refund.approval_requested": {"email": false},
+	}
+
+	matrix := services.ResolveNotificationPreferences(stored)
+
+	if len(matrix) != len(models.StaffNotificationEvents) {
+		t.Fatalf("got %d events, want %d", len(matrix), len(models.StaffNotificationEvents))
+	}
+	for _, eventType := range models.StaffNotificationEvents {
+		if len(matrix[eventType]) != len(models.NotificationChannels) {
+			t.Errorf("%s has %d channels, want %d", eventType, len(matrix[eventType]), len(models.NotificationChannels))
+		}
+	}
+
+	tests := []struct {
+		eventType string
+		channel   string
+		want      bool
+	}{
+		{eventType: "order.paid", channel: "email", want: true},
+		{eventType: "order.paid", channel: "in_app", want: true},
+		{eventType: "order.paid", channel: "whatsapp", want: false},
+		{eventType: "order.sla_breached", channel: "email", want: false},
+		{eventType: "refund.approval_requested", channel: "email", want: false},
+		{eventType: "refund.approval_requested", channel: "push", want: false},
+	}
+	for _, tt := range tests {
+		if got := matrix[tt.eventType][tt.channel]; got != tt.want {
+			t.Errorf("%s/%s = %v, want %v", tt.eventType, tt.channel, got, tt.want)
+		}
+	}
+
+	if !services.ResolveNotificationPreferences(nil)["refund.approval_requested"]["email"] {
+		t.Error("refund approval emails should be on by default")
+	}
+}
+
+// TestValidateNotificationPreferences verifies only known events and channels can be patched
+func TestValidateNotificationPreferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		cells   models.NotificationPreferenceMatrix
+		wantErr bool
+	}{
+		{name: "single cell", cells: models.NotificationPreferenceMatrix{"order.paid": {"whatsapp": true}}, wantErr: false},
+		{name: "legacy order emails", cells: models.OrderEmailPreferences(true), wantErr: false},
+		{name: "unknown event", cells: models.NotificationPreferenceMatrix{"password.changed": {"email": false}}, wantErr: true},
+		{name: "unknown channel", cells: models.NotificationPreferenceMatrix{"order.paid": {"sms": true}}, wantErr: true},
+		{name: "empty", cells: models.NotificationPreferenceMatrix{}, wantErr: true},
+		{name: "event without channels", cells: models.NotificationPreferenceMatrix{"order.paid": {}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.ValidateNotificationPreferences(tt.cells)
+			if tt.wantErr {
+				if _, ok := err.(*services.NotificationPreferenceError); !ok {
+					t.Errorf("ValidateNotificationPreferences() error = %v, want *NotificationPreferenceError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateNotificationPreferences() unexpected error: %v", err)
+			}
+		})
+	}
+}
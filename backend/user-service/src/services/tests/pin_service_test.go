@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pos/user-service/src/services"
+)
+
+// TestValidatePin verifies PIN length, digits-only and guessability rules
+func TestValidatePin(t *testing.T) {
+	tests := []struct {
+		pin     string
+		wantErr bool
+	}{
+		{pin: "2580", wantErr: false},
+		{pin: "739164", wantErr: false},
+		{pin: "1123", wantErr: false},
+		{pin: "123", wantErr: true},
+		{pin: "1234567", wantErr: true},
+		{pin: "12a4", wantErr: true},
+		{pin: "0000", wantErr: true},
+		{pin: "1234", wantErr: true},
+		{pin: "987654", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pin, func(t *testing.T) {
+			err := services.ValidatePin(tt.pin)
+			if tt.wantErr {
+				if _, ok := err.(*services.PinValidationError); !ok {
+					t.Errorf("ValidatePin(%q) error = %v, want *PinValidationError", tt.pin, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidatePin(%q) unexpected error: %v", tt.pin, err)
+			}
+		})
+	}
+}
+
+// TestCanManagePin verifies who may see, set or reset another user's PIN
+func TestCanManagePin(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorRole string
+		actorID   string
+		userRole  string
+		wantErr   bool
+	}{
+		{name: "owner manages manager", actorRole: "owner", actorID: "actor", userRole: "manager", wantErr: false},
+		{name: "owner manages owner", actorRole: "owner", actorID: "actor", userRole: "owner", wantErr: false},
+		{name: "manager manages cashier", actorRole: "manager", actorID: "actor", userRole: "cashier", wantErr: false},
+		{name: "manager cannot manage manager", actorRole: "manager", actorID: "actor", userRole: "manager", wantErr: true},
+		{name: "cashier cannot manage cashier", actorRole: "cashier", actorID: "actor", userRole: "cashier", wantErr: true},
+		{name: "cashier manages own", actorRole: "cashier", actorID: "user", userRole: "cashier", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.CanManagePin(tt.actorRole, tt.actorID, "user", tt.userRole)
+			if tt.wantErr && err != services.ErrPinManageForbidden {
+				t.Errorf("CanManagePin() error = %v, want ErrPinManageForbidden", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CanManagePin() unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// TestNormalizeCustomRole verifies custom role requests are cleaned up or rejected
+func TestNormalizeCustomRole(t *testing.T) {
+	blank := "   "
+	description := "  Reads reports  "
+
+	tests := []struct {
+		name        string
+		req         models.CustomRoleRequest
+		wantErr     bool
+		wantName    string
+		wantDesc    *string
+		permissions []string
+	}{
+		{
+			name:        "Trims and de-duplicates",
+			req:         models.CustomRoleRequest{Name: "  Accountant ", Description: &description, Permissions: []string{"analytics.read", "audit.read", "analytics.read"}},
+			wantName:    "Accountant",
+			wantDesc:    func() *string { s := "Reads reports"; return &s }(),
+			permissions: []string{"analytics.read", "audit.read"},
+		},
+		{
+			name:        "Blank description dropped",
+			req:         models.CustomRoleRequest{Name: "Kitchen staff", Description: &blank, Permissions: []string{"orders.read"}},
+			wantName:    "Kitchen staff",
+			permissions: []string{"orders.read"},
+		},
+		{
+			name:    "Missing name",
+			req:     models.CustomRoleRequest{Name: " ", Permissions: []string{"orders.read"}},
+			wantErr: true,
+		},
+		{
+			name:    "Name too long",
+			req:     models.CustomRoleRequest{Name: strings.Repeat("a", 51), Permissions: []string{"orders.read"}},
+			wantErr: true,
+		},
+		{
+			name:    "Built-in role name",
+			req:     models.CustomRoleRequest{Name: "Manager", Permissions: []string{"orders.read"}},
+			wantErr: true,
+		},
+		{
+			name:    "No permissions",
+			req:     models.CustomRoleRequest{Name: "Empty"},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown permission",
+			req:     models.CustomRoleRequest{Name: "Typo", Permissions: []string{"orders.reed"}},
+			wantErr: true,
+		},
+		{
+			name:    "Role management cannot be delegated",
+			req:     models.CustomRoleRequest{Name: "Deputy", Permissions: []string{"roles.manage"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := services.NormalizeCustomRole(&tt.req)
+			if tt.wantErr {
+				if _, ok := err.(*services.RoleValidationError); !ok {
+					t.Fatalf("NormalizeCustomRole() error = %v, want *RoleValidationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeCustomRole() unexpected error: %v", err)
+			}
+			if role.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", role.Name, tt.wantName)
+			}
+			if !reflect.DeepEqual(role.Description, tt.wantDesc) {
+				t.Errorf("Description = %v, want %v", role.Description, tt.wantDesc)
+			}
+			if !reflect.DeepEqual(role.Permissions, tt.permissions) {
+				t.Errorf("Permissions = %v, want %v", role.Permissions, tt.permissions)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// TestResolveScimRole verifies identity provider groups map to the right base and custom role
+func TestResolveScimRole(t *testing.T) {
+	supervisors := "role-supervisor"
+	mappings := []*models.ScimGroupMapping{
+		{GroupName: "Cashiers", Role: "cashier"},
+		{GroupName: "Store Managers", Role: "manager"},
+		{GroupName: "Supervisors", Role: "cashier", CustomRoleID: &supervisors},
+	}
+
+	tests := []struct {
+		name       string
+		groups     []models.ScimGroupRef
+		wantRole   string
+		wantCustom *string
+	}{
+		{
+			name:     "No groups",
+			wantRole: "cashier",
+		},
+		{
+			name:     "Unmapped group",
+			groups:   []models.ScimGroupRef{{Display: "Everyone"}},
+			wantRole: "cashier",
+		},
+		{
+			name:     "Matched by display name, case-insensitive",
+			groups:   []models.ScimGroupRef{{Value: "00g1", Display: "store managers"}},
+			wantRole: "manager",
+		},
+		{
+			name:       "Matched by value",
+			groups:     []models.ScimGroupRef{{Value: "Supervisors"}},
+			wantRole:   "cashier",
+			wantCustom: &supervisors,
+		},
+		{
+			name:     "Manager wins over cashier",
+			groups:   []models.ScimGroupRef{{Display: "Supervisors"}, {Display: "Store Managers"}},
+			wantRole: "manager",
+		},
+		{
+			name:     "First mapping wins between equal roles",
+			groups:   []models.ScimGroupRef{{Display: "Supervisors"}, {Display: "Cashiers"}},
+			wantRole: "cashier",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, customRoleID := services.ResolveScimRole(mappings, tt.groups)
+			if role != tt.wantRole {
+				t.Errorf("role = %q, want %q", role, tt.wantRole)
+			}
+			if !reflect.DeepEqual(customRoleID, tt.wantCustom) {
+				t.Errorf("customRoleID = %v, want %v", customRoleID, tt.wantCustom)
+			}
+		})
+	}
+}
+
+// TestApplyScimPatch verifies the PATCH shapes identity providers send
+func TestApplyScimPatch(t *testing.T) {
+	active := true
+	newUser := func() *models.ScimUser {
+		return &models.ScimUser{
+			UserName: "siti@example.com",
+			Active:   &active,
+			Name:     &models.ScimName{GivenName: "Siti", FamilyName: "Rahma"},
+			Groups:   []models.ScimGroupRef{{Value: "g1", Display: "Cashiers"}},
+		}
+	}
+	op := func(op, path, value string) models.ScimPatchOperation {
+		return models.ScimPatchOperation{Op: op, Path: path, Value: json.RawMessage(value)}
+	}
+
+	t.Run("Deactivate with path", func(t *testing.T) {
+		user := newUser()
+		if err := services.ApplyScimPatch(user, []models.ScimPatchOperation{op("replace", "active", "false")}); err != nil {
+			t.Fatalf("ApplyScimPatch() unexpected error: %v", err)
+		}
+		if *user.Active {
+			t.Error("Active = true, want false")
+		}
+	})
+
+	t.Run("Deactivate without path and string boolean", func(t *testing.T) {
+		user := newUser()
+		if err := services.ApplyScimPatch(user, []models.ScimPatchOperation{op("Replace", "", `{"active":"False"}`)}); err != nil {
+			t.Fatalf("ApplyScimPatch() unexpected error: %v", err)
+		}
+		if *user.Active {
+			t.Error("Active = true, want false")
+		}
+	})
+
+	t.Run("Name and email", func(t *testing.T) {
+		user := newUser()
+		err := services.ApplyScimPatch(user, []models.ScimPatchOperation{
+			op("replace", "name.familyName", `"Putri"`),
+			op("replace", `emails[type eq "work"].value`, `"siti.putri@example.com"`),
+		})
+		if err != nil {
+			t.Fatalf("ApplyScimPatch() unexpected error: %v", err)
+		}
+		if user.Name.GivenName != "Siti" || user.Name.FamilyName != "Putri" {
+			t.Errorf("Name = %+v, want Siti Putri", user.Name)
+		}
+		if len(user.Emails) != 1 || user.Emails[0].Value != "siti.putri@example.com" {
+			t.Errorf("Emails = %+v, want siti.putri@example.com", user.Emails)
+		}
+	})
+
+	t.Run("Add and remove groups", func(t *testing.T) {
+		user := newUser()
+		err := services.ApplyScimPatch(user, []models.ScimPatchOperation{
+			op("add", "groups", `[{"value":"g2","display":"Store Managers"},{"value":"g1"}]`),
+			op("remove", "groups", `[{"value":"g1"}]`),
+		})
+		if err != nil {
+			t.Fatalf("ApplyScimPatch() unexpected error: %v", err)
+		}
+		want := []models.ScimGroupRef{{Value: "g2", Display: "Store Managers"}}
+		if !reflect.DeepEqual(user.Groups, want) {
+			t.Errorf("Groups = %+v, want %+v", user.Groups, want)
+		}
+	})
+
+	t.Run("Remove all groups", func(t *testing.T) {
+		user := newUser()
+		if err := services.ApplyScimPatch(user, []models.ScimPatchOperation{op("remove", "groups", "")}); err != nil {
+			t.Fatalf("ApplyScimPatch() unexpected error: %v", err)
+		}
+		if user.Groups == nil || len(user.Groups) != 0 {
+			t.Errorf("Groups = %#v, want empty non-nil slice", user.Groups)
+		}
+	})
+
+	t.Run("Unsupported path", func(t *testing.T) {
+		err := services.ApplyScimPatch(newUser(), []models.ScimPatchOperation{op("replace", "title", `"Chef"`)})
+		if _, ok := err.(*services.ScimRequestError); !ok {
+			t.Fatalf("ApplyScimPatch() error = %v, want *ScimRequestError", err)
+		}
+	})
+}
+
+// TestParseScimFilter verifies the lookup filters identity providers send before creating a user
+func TestParseScimFilter(t *testing.T) {
+	tests := []struct {
+		filter        string
+		wantAttribute string
+		wantValue     string
+		wantErr       bool
+	}{
+		{filter: `userName eq "siti@example.com"`, wantAttribute: "userName", wantValue: "siti@example.com"},
+		{filter: `externalid EQ "00u1"`, wantAttribute: "externalId", wantValue: "00u1"},
+		{filter: `userName sw "siti"`, wantErr: true},
+		{filter: `title eq "Chef"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			attribute, value, err := services.ParseScimFilter(tt.filter)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParseScimFilter() expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseScimFilter() unexpected error: %v", err)
+			}
+			if attribute != tt.wantAttribute || value != tt.wantValue {
+				t.Errorf("ParseScimFilter() = %q, %q, want %q, %q", attribute, value, tt.wantAttribute, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// TestNormalizeTeam verifies team requests are cleaned up or rejected
+func TestNormalizeTeam(t *testing.T) {
+	blank := "   "
+
+	tests := []struct {
+		name     string
+		req      models.TeamRequest
+		wantErr  bool
+		wantName string
+		routes   []models.TeamNotificationRoute
+	}{
+		{
+			name:     "Trims name and de-duplicates delivery types",
+			req:      models.TeamRequest{Name: "  Kitchen ", Description: &blank, NotificationRoutes: []models.TeamNotificationRoute{{EventType: "order.paid", DeliveryTypes: []string{"dine_in", "pickup", "dine_in"}}}},
+			wantName: "Kitchen",
+			routes:   []models.TeamNotificationRoute{{EventType: "order.paid", DeliveryTypes: []string{"dine_in", "pickup"}}},
+		},
+		{
+			name:     "No routes",
+			req:      models.TeamRequest{Name: "Back office"},
+			wantName: "Back office",
+			routes:   []models.TeamNotificationRoute{},
+		},
+		{
+			name:    "Missing name",
+			req:     models.TeamRequest{Name: " "},
+			wantErr: true,
+		},
+		{
+			name:    "Name too long",
+			req:     models.TeamRequest{Name: strings.Repeat("a", 51)},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown event",
+			req:     models.TeamRequest{Name: "Kitchen", NotificationRoutes: []models.TeamNotificationRoute{{EventType: "user.login"}}},
+			wantErr: true,
+		},
+		{
+			name:    "Event listed twice",
+			req:     models.TeamRequest{Name: "Kitchen", NotificationRoutes: []models.TeamNotificationRoute{{EventType: "order.paid"}, {EventType: "order.paid", DeliveryTypes: []string{"pickup"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown delivery type",
+			req:     models.TeamRequest{Name: "Kitchen", NotificationRoutes: []models.TeamNotificationRoute{{EventType: "order.paid", DeliveryTypes: []string{"takeaway"}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			team, err := services.NormalizeTeam(&tt.req)
+			if tt.wantErr {
+				if _, ok := err.(*services.TeamValidationError); !ok {
+					t.Fatalf("NormalizeTeam() error = %v, want *TeamValidationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeTeam() unexpected error: %v", err)
+			}
+			if team.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", team.Name, tt.wantName)
+			}
+			if team.Description != nil {
+				t.Errorf("Description = %v, want nil", *team.Description)
+			}
+			if !reflect.DeepEqual(team.NotificationRoutes, tt.routes) {
+				t.Errorf("NotificationRoutes = %v, want %v", team.NotificationRoutes, tt.routes)
+			}
+		})
+	}
+}
+
+// TestNormalizeTeamMembers verifies member lists are de-duplicated and invalid IDs rejected
+func TestNormalizeTeamMembers(t *testing.T) {
+	members, err := services.NormalizeTeamMembers([]string{
+		"6F9619FF-8B86-D011-B42D-00CF4FC964FF",
+		"6f9619ff-8b86-d011-b42d-00cf4fc964ff",
+		"a1b2c3d4-0000-4000-8000-000000000001",
+	})
+	if err != nil {
+		t.Fatalf("NormalizeTeamMembers() unexpected error: %v", err)
+	}
+	want := []string{"6f9619ff-8b86-d011-b42d-00cf4fc964ff", "a1b2c3d4-0000-4000-8000-000000000001"}
+	if !reflect.DeepEqual(members, want) {
+		t.Errorf("members = %v, want %v", members, want)
+	}
+
+	if _, err := services.NormalizeTeamMembers([]string{"not-a-uuid"}); err == nil {
+		t.Error("NormalizeTeamMembers() accepted an invalid user ID")
+	}
+
+	members, err = services.NormalizeTeamMembers(nil)
+	if err != nil || len(members) != 0 {
+		t.Errorf("NormalizeTeamMembers(nil) = %v, %v; want empty list", members, err)
+	}
+}
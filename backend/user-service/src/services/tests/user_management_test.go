@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/pos/user-service/src/models"
+	"github.com/pos/user-service/src/services"
+)
+
+// TestCanManageUser verifies owners manage everyone and managers only cashiers
+func TestCanManageUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		actorRole  string
+		targetRole string
+		allowed    bool
+	}{
+		{name: "owner manages owner", actorRole: "owner", targetRole: "owner", allowed: true},
+		{name: "owner manages manager", actorRole: "owner", targetRole: "manager", allowed: true},
+		{name: "owner manages cashier", actorRole: "owner", targetRole: "cashier", allowed: true},
+		{name: "manager manages cashier", actorRole: "manager", targetRole: "cashier", allowed: true},
+		{name: "manager cannot manage manager", actorRole: "manager", targetRole: "manager", allowed: false},
+		{name: "manager cannot manage owner", actorRole: "manager", targetRole: "owner", allowed: false},
+		{name: "cashier cannot manage cashier", actorRole: "cashier", targetRole: "cashier", allowed: false},
+		{name: "missing role", actorRole: "", targetRole: "cashier", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.CanManageUser(tt.actorRole, &models.User{Role: tt.targetRole})
+			if tt.allowed && err != nil {
+				t.Errorf("CanManageUser(%q, %q) unexpected error: %v", tt.actorRole, tt.targetRole, err)
+			}
+			if !tt.allowed && err != services.ErrUserManageForbidden {
+				t.Errorf("CanManageUser(%q, %q) error = %v, want ErrUserManageForbidden", tt.actorRole, tt.targetRole, err)
+			}
+		})
+	}
+}
+
+// TestNormalizeTimezone verifies IANA timezones are kept, blanks cleared and anything else rejected
+func TestNormalizeTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		want     *string
+		wantErr  bool
+	}{
+		{name: "IANA name", timezone: " Asia/Makassar ", want: func() *string { s := "Asia/Makassar"; return &s }()},
+		{name: "Blank clears", timezone: "  "},
+		{name: "Unknown name", timezone: "Asia/Bandung", wantErr: true},
+		{name: "UTC offset", timezone: "+07:00", wantErr: true},
+		{name: "Server local", timezone: "Local", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := services.NormalizeTimezone(tt.timezone)
+			if tt.wantErr {
+				if _, ok := err.(*services.UserValidationError); !ok {
+					t.Fatalf("NormalizeTimezone() error = %v, want *UserValidationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeTimezone() unexpected error: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("NormalizeTimezone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
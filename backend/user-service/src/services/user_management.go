@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pos/user-service/src/models"
+)
+
+const (
+	maxUserNameLength   = 50
+	defaultUserPageSize = 50
+	maxUserPageSize     = 200
+)
+
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserManageForbidden = errors.New("not allowed to manage this user")
+	ErrUserManageSelf      = errors.New("cannot change your own role or status")
+	ErrUserStatusChange    = errors.New("user status cannot be changed this way")
+)
+
+// UserValidationError reports a user change that cannot be saved
+type UserValidationError struct {
+	Message string
+}
+
+func (e *UserValidationError) Error() string {
+	return e.Message
+}
+
+// CanManageUser reports whether a staff member may change another user of their tenant
+// Owners manage everyone; managers manage cashiers only.
+func CanManageUser(actorRole string, target *models.User) error {
+	if actorRole == string(models.RoleOwner) {
+		return nil
+	}
+	if actorRole == string(models.RoleManager) && target.Role == string(models.RoleCashier) {
+		return nil
+	}
+	return ErrUserManageForbidden
+}
+
+// ListStaff returns a page of the tenant's users; limit is capped and defaults when not positive
+func (s *UserService) ListStaff(ctx context.Context, tenantID, status, role, teamID, outletID string, offset, limit int) ([]*models.User, int, error) {
+	if teamID != "" {
+		if _, err := uuid.Parse(teamID); err != nil {
+			return nil, 0, &UserValidationError{Message: "team_id must be a team ID"}
+		}
+	}
+	if outletID != "" {
+		if _, err := uuid.Parse(outletID); err != nil {
+			return nil, 0, &UserValidationError{Message: "outlet_id must be an outlet ID"}
+		}
+	}
+	if limit <= 0 {
+		limit = defaultUserPageSize
+	}
+	if limit > maxUserPageSize {
+		limit = maxUserPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.userRepo.List(ctx, tenantID, status, role, teamID, outletID, offset, limit)
+}
+
+// GetStaff returns a user of the tenant
+func (s *UserService) GetStaff(ctx context.Context, tenantID, userID string) (*models.User, error) {
+	return s.findUser(ctx, tenantID, userID)
+}
+
+// UpdateProfile changes a user's names and locale
+// Anyone may change their own profile; other users' follow CanManageUser.
+func (s *UserService) UpdateProfile(ctx context.Context, tenantID, actorID, actorRole, userID string, req *models.UpdateUserProfileRequest) (*models.User, error) {
+	user, err := s.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID != userID {
+		if err := CanManageUser(actorRole, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.FirstName != nil {
+		user.FirstName, err = normalizeUserName(*req.FirstName, "First name")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.LastName != nil {
+		user.LastName, err = normalizeUserName(*req.LastName, "Last name")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Locale != nil {
+		if *req.Locale != "en" && *req.Locale != "id" {
+			return nil, &UserValidationError{Message: "Locale must be en or id"}
+		}
+		user.Locale = *req.Locale
+	}
+	if req.Timezone != nil {
+		user.Timezone, err = NormalizeTimezone(*req.Timezone)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepo.UpdateBy(ctx, user, actorID); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	return user, nil
+}
+
+// ChangeRole changes a user's base role; only owners may, and never their own
+// A user made owner loses their custom role, since owners always hold every permission.
+func (s *UserService) ChangeRole(ctx context.Context, tenantID, actorID, actorRole, userID string, req *models.ChangeUserRoleRequest) (*models.User, error) {
+	if actorRole != string(models.RoleOwner) {
+		return nil, ErrUserManageForbidden
+	}
+	if actorID == userID {
+		return nil, ErrUserManageSelf
+	}
+	switch models.UserRole(req.Role) {
+	case models.RoleOwner, models.RoleManager, models.RoleCashier:
+	default:
+		return nil, &UserValidationError{Message: "Role must be owner, manager or cashier"}
+	}
+
+	user, err := s.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == req.Role {
+		return user, nil
+	}
+
+	user.Role = req.Role
+	if err := s.userRepo.UpdateBy(ctx, user, actorID); err != nil {
+		return nil, fmt.Errorf("failed to change user role: %w", err)
+	}
+	if req.Role == string(models.RoleOwner) {
+		if err := s.roleRepo.AssignToUser(ctx, tenantID, userID, nil); err != nil {
+			return nil, fmt.Errorf("failed to clear custom role: %w", err)
+		}
+	}
+	return user, nil
+}
+
+// SetOutlet assigns a user to the outlet they work at, or to every outlet when the outlet is null
+// The outlet reaches the user's token, and so the X-Outlet-ID of their requests, at its next refresh.
+// Owners always work at every outlet.
+func (s *UserService) SetOutlet(ctx context.Context, tenantID, actorID, actorRole, userID string, req *models.SetUserOutletRequest) (*models.User, error) {
+	user, err := s.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := CanManageUser(actorRole, user); err != nil {
+		return nil, err
+	}
+
+	var outletID *string
+	if req.OutletID != nil {
+		parsed, err := uuid.Parse(*req.OutletID)
+		if err != nil {
+			return nil, &UserValidationError{Message: "outlet_id must be an outlet ID"}
+		}
+		if user.Role == string(models.RoleOwner) {
+			return nil, &UserValidationError{Message: "Owners work at every outlet"}
+		}
+		exists, err := s.userRepo.OutletExists(ctx, tenantID, parsed.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check outlet: %w", err)
+		}
+		if !exists {
+			return nil, &UserValidationError{Message: "Outlet not found"}
+		}
+		id := parsed.String()
+		outletID = &id
+	}
+
+	user.OutletID = outletID
+	if err := s.userRepo.UpdateBy(ctx, user, actorID); err != nil {
+		return nil, fmt.Errorf("failed to change user outlet: %w", err)
+	}
+	return user, nil
+}
+
+// SetActive deactivates (suspends) or reactivates a user
+// Deactivated users cannot log in, and offboarding ends the sessions they already hold.
+func (s *UserService) SetActive(ctx context.Context, tenantID, actorID, actorRole, userID string, active bool) (*models.User, error) {
+	if actorID == userID {
+		return nil, ErrUserManageSelf
+	}
+	user, err := s.findUser(ctx, tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := CanManageUser(actorRole, user); err != nil {
+		return nil, err
+	}
+
+	from, to := string(models.UserStatusSuspended), string(models.UserStatusActive)
+	if !active {
+		from, to = to, from
+	}
+	if user.Status == to {
+		return user, nil
+	}
+	// Invited users have no account to deactivate yet; their invitation is managed instead
+	if user.Status != from {
+		return nil, ErrUserStatusChange
+	}
+
+	// A reactivated user takes a staff seat again
+	if active {
+		if err := checkStaffSeat(ctx, s.userRepo, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Status = to
+	if err := s.userRepo.UpdateBy(ctx, user, actorID); err != nil {
+		return nil, fmt.Errorf("failed to change user status: %w", err)
+	}
+	if !active {
+		s.offboarding.Deactivated(ctx, user, actorID, "deactivated")
+	}
+	return user, nil
+}
+
+func (s *UserService) findUser(ctx context.Context, tenantID, userID string) (*models.User, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	user, err := s.userRepo.FindByID(ctx, tenantID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// normalizeUserName trims a name and checks its length; an empty name clears it
+func normalizeUserName(name, field string) (*string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(name) > maxUserNameLength {
+		return nil, &UserValidationError{Message: fmt.Sprintf("%s must be at most %d characters", field, maxUserNameLength)}
+	}
+	return &name, nil
+}
+
+// NormalizeTimezone checks a timezone is a valid IANA name; an empty timezone clears it
+// UTC offsets such as +07:00 are rejected: they do not follow daylight saving changes.
+func NormalizeTimezone(timezone string) (*string, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		return nil, nil
+	}
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+		return nil, &UserValidationError{Message: "Timezone must be an IANA timezone such as Asia/Jakarta"}
+	}
+	return &timezone, nil
+}
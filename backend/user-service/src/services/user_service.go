@@ -11,19 +11,23 @@ import (
 
 // UserService handles user-related business logic
 type UserService struct {
-	userRepo *repository.UserRepository
-	db       *sql.DB
+	userRepo    *repository.UserRepository
+	roleRepo    *repository.RoleRepository
+	offboarding *Offboarding
+	db          *sql.DB
 }
 
 // NewUserService creates a new user service with a real VaultClient (production use)
-func NewUserService(db *sql.DB, auditPublisher utils.AuditPublisherInterface) (*UserService, error) {
+func NewUserService(db *sql.DB, auditPublisher utils.AuditPublisherInterface, offboarding *Offboarding) (*UserService, error) {
 	userRepo, err := repository.NewUserRepositoryWithVault(db, auditPublisher)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user repository: %w", err)
 	}
 	return &UserService{
-		userRepo: userRepo,
-		db:       db,
+		userRepo:    userRepo,
+		roleRepo:    repository.NewRoleRepository(db),
+		offboarding: offboarding,
+		db:          db,
 	}, nil
 }
 
@@ -32,6 +36,7 @@ func NewUserService(db *sql.DB, auditPublisher utils.AuditPublisherInterface) (*
 func NewUserServiceWithRepository(db *sql.DB, userRepo *repository.UserRepository) *UserService {
 	return &UserService{
 		userRepo: userRepo,
+		roleRepo: repository.NewRoleRepository(db),
 		db:       db,
 	}
 }
@@ -120,50 +125,3 @@ func (s *UserService) GetUsersWithNotificationPreferences(tenantID string) ([]ma
 
 	return users, nil
 }
-
-// UpdateUserNotificationPreference updates a user's notification preference
-func (s *UserService) UpdateUserNotificationPreference(tenantID, userID string, receive bool) error {
-	ctx := context.Background()
-
-	// First check if user exists and belongs to this tenant
-	checkQuery := `
-		SELECT id
-		FROM users
-		WHERE id = $1
-		  AND tenant_id = $2
-	`
-
-	var existingUserID string
-	err := s.db.QueryRowContext(ctx, checkQuery, userID, tenantID).Scan(&existingUserID)
-	if err == sql.ErrNoRows {
-		return fmt.Errorf("user not found")
-	}
-	if err != nil {
-		return fmt.Errorf("failed to check user: %w", err)
-	}
-
-	// Update the preference
-	updateQuery := `
-		UPDATE users
-		SET receive_order_notifications = $1,
-		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2
-		  AND tenant_id = $3
-	`
-
-	result, err := s.db.ExecContext(ctx, updateQuery, receive, userID, tenantID)
-	if err != nil {
-		return fmt.Errorf("failed to update notification preference: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("no rows updated")
-	}
-
-	return nil
-}
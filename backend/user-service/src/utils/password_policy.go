@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Password policy violation codes, returned to clients so they can highlight the failed rule
+const (
+	PasswordTooShort         = "min_length"
+	PasswordTooLong          = "max_length"
+	PasswordMissingLetter    = "letter"
+	PasswordMissingUppercase = "uppercase"
+	PasswordMissingLowercase = "lowercase"
+	PasswordMissingDigit     = "digit"
+	PasswordMissingSymbol    = "symbol"
+	PasswordBreached         = "breached"
+)
+
+// maxPasswordBytes is bcrypt's input limit; longer passwords cannot be hashed
+const maxPasswordBytes = 72
+
+const pwnedPasswordsRangeURL = "https://api.pwnedpasswords.com/range/"
+
+// PasswordPolicy holds the rules a new password must satisfy
+type PasswordPolicy struct {
+	MinLength        int  `json:"minLength"`
+	RequireLetter    bool `json:"requireLetter"`
+	RequireUppercase bool `json:"requireUppercase"`
+	RequireLowercase bool `json:"requireLowercase"`
+	RequireDigit     bool `json:"requireDigit"`
+	RequireSymbol    bool `json:"requireSymbol"`
+	CheckBreached    bool `json:"checkBreached"` // Reject passwords found in HaveIBeenPwned
+
+	httpClient *http.Client
+}
+
+// LoadPasswordPolicy reads the password policy from PASSWORD_* environment variables
+func LoadPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:        GetEnvInt("PASSWORD_MIN_LENGTH"),
+		RequireLetter:    GetEnvBool("PASSWORD_REQUIRE_LETTER"),
+		RequireUppercase: GetEnvBool("PASSWORD_REQUIRE_UPPERCASE"),
+		RequireLowercase: GetEnvBool("PASSWORD_REQUIRE_LOWERCASE"),
+		RequireDigit:     GetEnvBool("PASSWORD_REQUIRE_DIGIT"),
+		RequireSymbol:    GetEnvBool("PASSWORD_REQUIRE_SYMBOL"),
+		CheckBreached:    GetEnvBool("PASSWORD_BREACH_CHECK_ENABLED"),
+		httpClient:       &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// PasswordPolicyError lists the rules a password failed
+type PasswordPolicyError struct {
+	Violations []string
+	messages   []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password " + strings.Join(e.messages, ", ")
+}
+
+func (e *PasswordPolicyError) add(code, message string) {
+	e.Violations = append(e.Violations, code)
+	e.messages = append(e.messages, message)
+}
+
+// Validate returns a *PasswordPolicyError when the password breaks the policy
+// The breach check fails open: when the range API cannot be reached the password is
+// judged on the other rules only, so an outage does not block registration or resets.
+func (p *PasswordPolicy) Validate(ctx context.Context, password string) error {
+	policyErr := &PasswordPolicyError{}
+
+	if len([]rune(password)) < p.MinLength {
+		policyErr.add(PasswordTooShort, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if len(password) > maxPasswordBytes {
+		policyErr.add(PasswordTooLong, fmt.Sprintf("must be at most %d bytes", maxPasswordBytes))
+	}
+
+	var hasUpper, hasLower, hasOtherLetter, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsLetter(r):
+			hasOtherLetter = true // Scripts without case
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireLetter && !hasUpper && !hasLower && !hasOtherLetter {
+		policyErr.add(PasswordMissingLetter, "must contain a letter")
+	}
+	if p.RequireUppercase && !hasUpper {
+		policyErr.add(PasswordMissingUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !hasLower {
+		policyErr.add(PasswordMissingLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		policyErr.add(PasswordMissingDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		policyErr.add(PasswordMissingSymbol, "must contain a symbol")
+	}
+
+	// Only spend a network call on passwords that pass the local rules
+	if len(policyErr.Violations) == 0 && p.CheckBreached {
+		breached, err := p.isBreached(ctx, password)
+		if err != nil {
+			log.Printf("Warning: breached password check unavailable: %v", err)
+		} else if breached {
+			policyErr.add(PasswordBreached, "has appeared in a data breach, choose another")
+		}
+	}
+
+	if len(policyErr.Violations) > 0 {
+		return policyErr
+	}
+	return nil
+}
+
+// isBreached looks the password up in HaveIBeenPwned with k-anonymity: only the first five
+// hex characters of its SHA-1 leave this service, and the match is done locally
+func (p *PasswordPolicy) isBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pwnedPasswordsRangeURL+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding hides the real number of matches from observers of the response size
+	req.Header.Set("Add-Padding", "true")
+
+	client := p.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("range API returned %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		// Padding entries have a count of 0
+		if found && candidate == suffix && count != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"os"
+	"time"
+)
+
+// StorageConfig is the object storage (S3/MinIO) shared with product-service
+// Avatars live under their own key prefix in the same bucket.
+type StorageConfig struct {
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	BucketName      string
+	Region          string
+	UseSSL          bool
+	URLTTL          time.Duration
+}
+
+// Configured reports whether avatars can be stored; without storage uploads are refused
+func (c StorageConfig) Configured() bool {
+	return c.Endpoint != "" && c.BucketName != "" && c.AccessKeyID != "" && c.SecretAccessKey != ""
+}
+
+// LoadStorageConfig reads the object storage configuration
+// The S3_* variables are optional; AVATAR_URL_TTL_SECONDS is required.
+func LoadStorageConfig() StorageConfig {
+	return StorageConfig{
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+		AccessKeyID:     os.Getenv("S3_ACCESS_KEY"),
+		SecretAccessKey: os.Getenv("S3_SECRET_KEY"),
+		BucketName:      os.Getenv("S3_BUCKET_NAME"),
+		Region:          os.Getenv("S3_REGION"),
+		UseSSL:          os.Getenv("S3_USE_SSL") == "true",
+		URLTTL:          time.Duration(GetEnvInt("AVATAR_URL_TTL_SECONDS")) * time.Second,
+	}
+}
@@ -0,0 +1,85 @@
+package utils_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pos/user-service/src/utils"
+)
+
+// TestPasswordPolicyValidate verifies each rule reports its own violation code
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := &utils.PasswordPolicy{
+		MinLength:        10,
+		RequireLetter:    true,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		violations []string
+	}{
+		{
+			name:     "Satisfies every rule",
+			password: "Kasir-Toko-2024",
+		},
+		{
+			name:       "Too short",
+			password:   "Ab1!",
+			violations: []string{utils.PasswordTooShort},
+		},
+		{
+			name:       "Missing uppercase and symbol",
+			password:   "kasirtoko2024",
+			violations: []string{utils.PasswordMissingUppercase, utils.PasswordMissingSymbol},
+		},
+		{
+			name:       "Digits only",
+			password:   "12345678901",
+			violations: []string{utils.PasswordMissingLetter, utils.PasswordMissingUppercase, utils.PasswordMissingLowercase, utils.PasswordMissingSymbol},
+		},
+		{
+			name:       "Longer than bcrypt accepts",
+			password:   "Aa1!" + strings.Repeat("x", 72),
+			violations: []string{utils.PasswordTooLong},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(context.Background(), tt.password)
+			if tt.violations == nil {
+				if err != nil {
+					t.Fatalf("Validate(%q) = %v, want nil", tt.password, err)
+				}
+				return
+			}
+
+			policyErr, ok := err.(*utils.PasswordPolicyError)
+			if !ok {
+				t.Fatalf("Validate(%q) = %v, want *PasswordPolicyError", tt.password, err)
+			}
+			if !reflect.DeepEqual(policyErr.Violations, tt.violations) {
+				t.Errorf("Violations = %v, want %v", policyErr.Violations, tt.violations)
+			}
+		})
+	}
+}
+
+// TestPasswordPolicyLength verifies the minimum length counts characters, not bytes
+func TestPasswordPolicyLength(t *testing.T) {
+	policy := &utils.PasswordPolicy{MinLength: 8}
+
+	if err := policy.Validate(context.Background(), "kata sandi"); err != nil {
+		t.Errorf("10-character password rejected: %v", err)
+	}
+	if err := policy.Validate(context.Background(), "ééééééé"); err == nil {
+		t.Error("7-character password with multi-byte runes accepted")
+	}
+}
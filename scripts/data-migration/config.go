@@ -123,6 +123,11 @@ func (vc *VaultClient) Encrypt(ctx context.Context, plaintext string) (string, e
 // Decrypt decrypts ciphertext using Vault Transit Engine
 // Verifies HMAC integrity if present (format: vault:v1:ciphertext:hmac)
 func (vc *VaultClient) Decrypt(ctx context.Context, ciphertext string) (string, error) {
+	return vc.DecryptWithContext(ctx, ciphertext, "")
+}
+
+// DecryptWithContext decrypts ciphertext encrypted with a derived-key context (e.g. "guest_order:customer_phone")
+func (vc *VaultClient) DecryptWithContext(ctx context.Context, ciphertext string, encryptionContext string) (string, error) {
 	if ciphertext == "" {
 		return "", nil
 	}
@@ -181,6 +186,9 @@ func (vc *VaultClient) Decrypt(ctx context.Context, ciphertext string) (string,
 	data := map[string]interface{}{
 		"ciphertext": vaultCiphertext,
 	}
+	if encryptionContext != "" {
+		data["context"] = base64.StdEncoding.EncodeToString([]byte(encryptionContext))
+	}
 
 	secret, err := vc.client.Logical().WriteWithContext(ctx, path, data)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -44,6 +45,12 @@ func populateSearchHashes(db *sql.DB, encryptor *VaultClient) error {
 		return fmt.Errorf("failed to populate invitations hashes: %w", err)
 	}
 
+	// 3. Populate guest_orders.customer_phone_hash and customer_email_hash
+	log.Println("Populating guest_orders.customer_phone_hash and customer_email_hash...")
+	if err := populateGuestOrderContactHashes(ctx, db, encryptor); err != nil {
+		return fmt.Errorf("failed to populate guest_orders contact hashes: %w", err)
+	}
+
 	log.Println("✓ Search hash population completed")
 	return nil
 }
@@ -167,3 +174,86 @@ func populateInvitationsHashes(ctx context.Context, db *sql.DB, encryptor *Vault
 	log.Printf("✓ Invitations hashes: %d processed, %d updated, %d skipped", processed, updated, skipped)
 	return rows.Err()
 }
+
+// normalizeCustomerPhone must match order-service models.NormalizeCustomerPhone
+func normalizeCustomerPhone(phone string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	if strings.HasPrefix(normalized, "62") {
+		normalized = "0" + normalized[2:]
+	}
+	return normalized
+}
+
+func populateGuestOrderContactHashes(ctx context.Context, db *sql.DB, encryptor *VaultClient) error {
+	query := `SELECT id, customer_phone, COALESCE(customer_email, '') FROM guest_orders WHERE customer_phone_hash IS NULL AND is_anonymized = FALSE`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	updateStmt, err := db.PrepareContext(ctx, `UPDATE guest_orders SET customer_phone_hash = $1, customer_email_hash = $2 WHERE id = $3`)
+	if err != nil {
+		return err
+	}
+	defer updateStmt.Close()
+
+	var processed, updated, skipped int
+	for rows.Next() {
+		var id, encryptedPhone, encryptedEmail string
+		if err := rows.Scan(&id, &encryptedPhone, &encryptedEmail); err != nil {
+			log.Printf("ERROR: Failed to scan guest order %s: %v", id, err)
+			continue
+		}
+
+		processed++
+
+		// Decrypt phone and email
+		phone, err := encryptor.DecryptWithContext(ctx, encryptedPhone, "guest_order:customer_phone")
+		if err != nil {
+			log.Printf("ERROR: Failed to decrypt phone for guest order %s: %v", id, err)
+			skipped++
+			continue
+		}
+
+		email, err := encryptor.DecryptWithContext(ctx, encryptedEmail, "guest_order:customer_email")
+		if err != nil {
+			log.Printf("ERROR: Failed to decrypt email for guest order %s: %v", id, err)
+			skipped++
+			continue
+		}
+
+		// Generate hashes of the normalized values
+		var phoneHash, emailHash sql.NullString
+		if normalized := normalizeCustomerPhone(phone); normalized != "" {
+			phoneHash = sql.NullString{String: hashForSearch(normalized), Valid: true}
+		}
+		if normalized := strings.ToLower(strings.TrimSpace(email)); normalized != "" {
+			emailHash = sql.NullString{String: hashForSearch(normalized), Valid: true}
+		}
+
+		// Update hashes
+		if _, err := updateStmt.ExecContext(ctx, phoneHash, emailHash, id); err != nil {
+			log.Printf("ERROR: Failed to update guest order %s: %v", id, err)
+			skipped++
+			continue
+		}
+
+		updated++
+		if processed%100 == 0 {
+			log.Printf("Progress: %d processed, %d updated, %d skipped", processed, updated, skipped)
+		}
+
+		if updated%50 == 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	log.Printf("✓ Guest orders contact hashes: %d processed, %d updated, %d skipped", processed, updated, skipped)
+	return rows.Err()
+}
@@ -13,7 +13,7 @@ import (
 func PopulateSearchHashes() error {
 	log.Println("=== Search Hash Population Migration ===")
 	log.Println("Purpose: Generate searchable hashes for encrypted fields")
-	log.Println("Target: users.email_hash, invitations.email_hash, invitations.token_hash")
+	log.Println("Target: users.email_hash, invitations.email_hash, invitations.token_hash, guest_orders.customer_phone_hash, guest_orders.customer_email_hash")
 	log.Println()
 
 	// Load configuration